@@ -20,7 +20,9 @@ const docTemplate = `{
         {"name": "Dashboard", "description": "Dashboard summaries for admin and teacher personas"},
         {"name": "Reports", "description": "Asynchronous report generation & exports"},
         {"name": "Mutations", "description": "Data change approvals"},
-        {"name": "Archives", "description": "Secure archive storage"}
+        {"name": "Grade Appeals", "description": "Disputes of finalized grades"},
+        {"name": "Archives", "description": "Secure archive storage"},
+        {"name": "Search", "description": "Global search across core entities"}
     ],
     "paths": {
         "/health": {
@@ -34,8 +36,52 @@ const docTemplate = `{
         "/ready": {
             "get": {
                 "summary": "Readiness check",
+                "description": "Lists the report and archive storage directories with their free space. Answers 503 while any of them is not writable or below its free-space threshold.",
                 "responses": {
-                    "200": {"description": "Ready"}
+                    "200": {"description": "Ready"},
+                    "503": {"description": "A storage directory is unusable"}
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "summary": "Build information",
+                "description": "Version, git commit, build date, Go version and enabled features of the serving build. Every response also carries the version in the X-App-Version header.",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/auth/me/permissions": {
+            "get": {
+                "tags": ["Authentication"],
+                "summary": "Routes the caller's token may use",
+                "description": "Read off the RBAC guards of the mounted routes. SELF-scoped routes only admit the caller's own user ID as :id; handlers may narrow access further, e.g. to a teacher's own classes.",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "401": {"description": "Missing or invalid token"}
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "tags": ["Authentication"],
+                "summary": "List the caller's live sessions",
+                "description": "One entry per login, most recently active first, with the last refresh or heartbeat and, when SESSION_IDLE_TIMEOUT is set, when the session ends without further activity. current marks the session of the calling token.",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "401": {"description": "Missing or invalid token"}
+                }
+            }
+        },
+        "/auth/sessions/heartbeat": {
+            "post": {
+                "tags": ["Authentication"],
+                "summary": "Record activity on the caller's session",
+                "description": "Keeps the session from running into the idle timeout between refreshes. Answers 401 once the session has ended, e.g. after a newer login pushed it over SESSION_MAX_PER_ROLE.",
+                "responses": {
+                    "204": {"description": "Recorded"},
+                    "401": {"description": "Session has ended"}
                 }
             }
         },
@@ -43,79 +89,2412 @@ const docTemplate = `{
             "get": {
                 "tags": ["Dashboard"],
                 "summary": "Admin dashboard summary",
+                "description": "The ops section includes teacherPresence when teacher clock-in is enabled and pendingMutations (pending, overdue and escalated counts, oldest request age) when the mutation workflow is enabled, and unacknowledgedAnnouncements, the newest active announcements requiring acknowledgement that some recipients have not acknowledged.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Term ID, defaults to the active term reported as meta.resolved_term_id"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/dashboard/academics": {
+            "get": {
+                "tags": ["Dashboard"],
+                "summary": "Teacher academics dashboard",
+                "description": "Includes today's schedule, class indicators and syllabus coverage of the teacher's assignments.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Term ID, defaults to the active term reported as meta.resolved_term_id"},
+                    {"name": "date", "in": "query", "type": "string", "description": "Date (YYYY-MM-DD)"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teachers": {
+            "get": {
+                "tags": ["Teachers"],
+                "summary": "List teachers",
+                "parameters": [
+                    {"name": "search", "in": "query", "type": "string", "description": "Matches name, email or NIP as a substring, or the name with typos (trigram word similarity of at least 0.3). Without sort, the closest names come first."},
+                    {"name": "active", "in": "query", "type": "boolean"},
+                    {"name": "termId", "in": "query", "type": "string", "description": "Include assignment_count, is_homeroom and weekly_load for this term"},
+                    {"name": "page", "in": "query", "type": "integer"},
+                    {"name": "limit", "in": "query", "type": "integer"},
+                    {"name": "sort", "in": "query", "type": "string"},
+                    {"name": "order", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Teachers"],
+                "summary": "Create teacher",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateTeacherRequest"}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "tags": ["Search"],
+                "summary": "Search teachers, students, classes, subjects and archives",
+                "parameters": [
+                    {"name": "q", "in": "query", "required": true, "type": "string", "description": "At least 2 characters"},
+                    {"name": "types", "in": "query", "type": "string", "description": "Comma-separated groups (teacher,student,class,subject,archive)"},
+                    {"name": "limit", "in": "query", "type": "integer", "description": "Hits per group (default 5, max 20)"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teachers/{id}": {
+            "get": {
+                "tags": ["Teachers"],
+                "summary": "Get teacher",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["Teachers"],
+                "summary": "Update teacher",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/UpdateTeacherRequest"}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "patch": {
+                "tags": ["Teachers"],
+                "summary": "Partially update teacher",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/PatchTeacherRequest"}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "delete": {
+                "tags": ["Teachers"],
+                "summary": "Deactivate teacher",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/teachers/{id}/assignments": {
+            "get": {
+                "tags": ["Teacher Assignments"],
+                "summary": "List assignments",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "term_id", "in": "query", "type": "string"},
+                    {"name": "class_id", "in": "query", "type": "string"},
+                    {"name": "subject_id", "in": "query", "type": "string"},
+                    {"name": "expand", "in": "query", "type": "string", "description": "Comma-separated relations (class,subject,term,teacher); all when omitted"},
+                    {"name": "page", "in": "query", "type": "integer"},
+                    {"name": "limit", "in": "query", "type": "integer"},
+                    {"name": "sort", "in": "query", "type": "string", "description": "term, class, subject or created_at"},
+                    {"name": "order", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Teacher Assignments"],
+                "summary": "Create assignment",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateTeacherAssignmentRequest"}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teachers/{id}/assignments/{aid}": {
+            "get": {
+                "tags": ["Teacher Assignments"],
+                "summary": "Get assignment",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "aid", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "delete": {
+                "tags": ["Teacher Assignments"],
+                "summary": "Delete assignment",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "aid", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/teachers/{id}/preferences": {
+            "get": {
+                "tags": ["Teacher Preferences"],
+                "summary": "Get preferences",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["Teacher Preferences"],
+                "summary": "Upsert preferences",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/UpsertTeacherPreferenceRequest"}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/schedules/preferences/export": {
+            "get": {
+                "tags": ["Teacher Preferences"],
+                "summary": "Download the preference sheet",
+                "description": "xlsx template listing every active teacher with their stored loads and unavailable slots. Day columns take comma separated slot numbers or ranges such as 1-3, 7.",
+                "produces": ["application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"],
+                "responses": {
+                    "200": {"description": "Preference sheet", "schema": {"type": "file"}}
+                }
+            }
+        },
+        "/schedules/preferences/import": {
+            "post": {
+                "tags": ["Teacher Preferences"],
+                "summary": "Import a filled preference sheet",
+                "description": "Unavailable slots are checked against the term's slot definitions. Valid rows are stored in one batch; the result lists the outcome of every row.",
+                "consumes": ["multipart/form-data"],
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "file", "in": "formData", "required": true, "type": "file"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Not an xlsx workbook or no Teacher ID column"},
+                    "404": {"description": "Term not found"}
+                }
+            }
+        },
+        "/schedules/clashes": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "List double-booked daily schedules of a term",
+                "description": "Groups the term's daily schedules that book the same teacher, class or room more than once in a day and slot, such as rows imported from the legacy system. Rooms are compared case-insensitively. The per-class schedules of one lesson group session never clash with each other, nor do lesson groups sharing a class. counts gives the number of clashes per dimension.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Term not found"}
+                }
+            }
+        },
+        "/schedules/clashes/export": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "Export the daily schedule clash report",
+                "description": "Renders one row per clashing schedule and returns a signed download URL. Only mounted when reports are enabled.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "format", "in": "query", "type": "string", "enum": ["pdf", "xlsx"], "default": "pdf"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Term not found"}
+                }
+            }
+        },
+        "/intake": {
+            "get": {
+                "tags": ["Intake"],
+                "summary": "List a term's intake applications",
+                "description": "Each application carries its uploaded documents and the checklist documents still missing. Waitlisted applications are numbered in the order they were waitlisted and, when filtering on WAITLISTED, listed in that order.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "status", "in": "query", "type": "string", "enum": ["PENDING", "WAITLISTED", "ACCEPTED", "REJECTED"]}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Intake"],
+                "summary": "Record a transfer-in application",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "fullName", "gender", "birthDate", "previousSchool"], "properties": {"termId": {"type": "string"}, "fullName": {"type": "string", "maxLength": 255}, "nisn": {"type": "string", "minLength": 10, "maxLength": 10}, "gender": {"type": "string", "maxLength": 10}, "birthDate": {"type": "string", "format": "date-time"}, "address": {"type": "string"}, "phone": {"type": "string", "maxLength": 32}, "previousSchool": {"type": "string", "maxLength": 255}, "requestedClassId": {"type": "string"}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Term or requested class not found"}
+                }
+            }
+        },
+        "/intake/{id}": {
+            "get": {
+                "tags": ["Intake"],
+                "summary": "Get an intake application with its document checklist",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Application not found"}
+                }
+            },
+            "put": {
+                "tags": ["Intake"],
+                "summary": "Correct an undecided intake application",
+                "description": "termId cannot change.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "fullName", "gender", "birthDate", "previousSchool"], "properties": {"termId": {"type": "string"}, "fullName": {"type": "string", "maxLength": 255}, "nisn": {"type": "string", "minLength": 10, "maxLength": 10}, "gender": {"type": "string", "maxLength": 10}, "birthDate": {"type": "string", "format": "date-time"}, "address": {"type": "string"}, "phone": {"type": "string", "maxLength": 32}, "previousSchool": {"type": "string", "maxLength": 255}, "requestedClassId": {"type": "string"}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Application not found"},
+                    "409": {"description": "Application already accepted or rejected"}
+                }
+            }
+        },
+        "/intake/{id}/documents": {
+            "post": {
+                "tags": ["Intake"],
+                "summary": "Upload a checklist document for an intake application",
+                "description": "Stores the file in the archive under the admin-only INTAKE scope. Uploading a document type again replaces the earlier file. Requires the archive module.",
+                "consumes": ["multipart/form-data"],
                 "parameters": [
-                    {"name": "termId", "in": "query", "required": true, "type": "string", "description": "Term ID"}
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "documentType", "in": "formData", "required": true, "type": "string", "enum": ["BIRTH_CERTIFICATE", "FAMILY_CARD", "REPORT_CARD", "TRANSFER_LETTER"]},
+                    {"name": "file", "in": "formData", "required": true, "type": "file"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Application not found"},
+                    "409": {"description": "Application already accepted or rejected"},
+                    "412": {"description": "Archives are disabled"}
+                }
+            }
+        },
+        "/intake/{id}/decision": {
+            "post": {
+                "tags": ["Intake"],
+                "summary": "Accept, waitlist or reject an intake application",
+                "description": "Accepting creates the student and their enrollment in the class in one transaction, then notifies the class's homeroom teacher. Waitlisting keeps the application open and gives it a place in the term's waitlist.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["decision"], "properties": {"decision": {"type": "string", "enum": ["ACCEPT", "WAITLIST", "REJECT"]}, "classId": {"type": "string", "description": "Required when accepting"}, "nis": {"type": "string", "description": "Required when accepting"}, "note": {"type": "string", "maxLength": 1000}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Application or class not found"},
+                    "409": {"description": "Application already decided or NIS already registered"},
+                    "412": {"description": "Checklist documents missing"}
+                }
+            }
+        },
+        "/electives": {
+            "get": {
+                "tags": ["Electives"],
+                "summary": "List a term's elective offerings",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "category", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Electives"],
+                "summary": "Create an elective offering with its sections",
+                "description": "Students rank the offerings of a category and are allocated to at most one of them. The offering's capacity is the sum of its sections' capacities.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "subjectId", "category", "name", "sections"], "properties": {"termId": {"type": "string"}, "subjectId": {"type": "string"}, "category": {"type": "string", "maxLength": 40}, "name": {"type": "string", "maxLength": 120}, "description": {"type": "string", "maxLength": 1000}, "sections": {"type": "array", "minItems": 1, "items": {"type": "object", "required": ["name", "teacherId", "capacity"], "properties": {"id": {"type": "string", "description": "Existing section to keep; new sections omit it"}, "name": {"type": "string", "maxLength": 60}, "teacherId": {"type": "string"}, "capacity": {"type": "integer", "minimum": 1}, "room": {"type": "string", "maxLength": 64}}}}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "An offering with this name already exists in the term"}
+                }
+            }
+        },
+        "/electives/selections": {
+            "get": {
+                "tags": ["Electives"],
+                "summary": "List ranked elective choices",
+                "description": "Students only see their own choices.",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "category", "in": "query", "type": "string"},
+                    {"name": "enrollmentId", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["Electives"],
+                "summary": "Rank elective offerings of a category",
+                "description": "Replaces the enrollment's choices in the category, first choice first. Students submit for their own enrollment in the term; administrators name the enrollment.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "category", "offeringIds"], "properties": {"termId": {"type": "string"}, "category": {"type": "string"}, "enrollmentId": {"type": "string"}, "offeringIds": {"type": "array", "minItems": 1, "items": {"type": "string"}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "The student is not enrolled in the term"}
+                }
+            }
+        },
+        "/electives/allocate": {
+            "post": {
+                "tags": ["Electives"],
+                "summary": "Allocate students to elective sections",
+                "description": "Honours choices rank by rank, earlier submissions first, and spreads each offering's students over its sections in proportion to capacity. Each section's students are stored in a lesson group used for scheduling and attendance. dryRun reports the allocation without saving it.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "category"], "properties": {"termId": {"type": "string"}, "category": {"type": "string"}, "dryRun": {"type": "boolean"}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "A section's lesson group already has sessions"}
+                }
+            }
+        },
+        "/electives/allocation": {
+            "get": {
+                "tags": ["Electives"],
+                "summary": "Report the stored elective allocation of a category",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "category", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/electives/{id}": {
+            "get": {
+                "tags": ["Electives"],
+                "summary": "Get an elective offering",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Offering not found"}
+                }
+            },
+            "put": {
+                "tags": ["Electives"],
+                "summary": "Update an elective offering and its sections",
+                "description": "termId, subjectId and category cannot change. Allocated sections keep their teacher and cannot be removed.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "subjectId", "category", "name", "sections"], "properties": {"termId": {"type": "string"}, "subjectId": {"type": "string"}, "category": {"type": "string", "maxLength": 40}, "name": {"type": "string", "maxLength": 120}, "description": {"type": "string", "maxLength": 1000}, "sections": {"type": "array", "minItems": 1, "items": {"type": "object", "required": ["name", "teacherId", "capacity"], "properties": {"id": {"type": "string", "description": "Existing section to keep; new sections omit it"}, "name": {"type": "string", "maxLength": 60}, "teacherId": {"type": "string"}, "capacity": {"type": "integer", "minimum": 1}, "room": {"type": "string", "maxLength": 64}}}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "An allocated section was removed or changed teacher"}
+                }
+            },
+            "delete": {
+                "tags": ["Electives"],
+                "summary": "Delete an elective offering without allocated students",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "Deleted"},
+                    "409": {"description": "Students are allocated to the offering"}
+                }
+            }
+        },
+        "/lesson-groups": {
+            "get": {
+                "tags": ["Lesson Groups"],
+                "summary": "List a term's lesson groups",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Lesson Groups"],
+                "summary": "Create a lesson group",
+                "description": "Merges several classes, or subsets of their students, into one lesson taught by one teacher, such as a religion or language elective. Selected students must be active enrollments of their class in the term.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "subjectId", "teacherId", "name", "members"], "properties": {"termId": {"type": "string"}, "subjectId": {"type": "string"}, "teacherId": {"type": "string"}, "name": {"type": "string", "maxLength": 120}, "room": {"type": "string", "maxLength": 64}, "members": {"type": "array", "minItems": 2, "items": {"type": "object", "required": ["classId"], "properties": {"classId": {"type": "string"}, "enrollmentIds": {"type": "array", "items": {"type": "string"}, "description": "Students of the class taking part; the whole class when empty"}}}}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "A group with this name already exists in the term"}
+                }
+            }
+        },
+        "/lesson-groups/{id}": {
+            "get": {
+                "tags": ["Lesson Groups"],
+                "summary": "Get a lesson group",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Lesson group not found"}
+                }
+            },
+            "put": {
+                "tags": ["Lesson Groups"],
+                "summary": "Update a lesson group's name, room and members",
+                "description": "termId, subjectId and teacherId cannot change. While the group has sessions its member classes cannot change, and new student selections are checked against other groups meeting at the same time.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "subjectId", "teacherId", "name", "members"], "properties": {"termId": {"type": "string"}, "subjectId": {"type": "string"}, "teacherId": {"type": "string"}, "name": {"type": "string", "maxLength": 120}, "room": {"type": "string", "maxLength": 64}, "members": {"type": "array", "minItems": 2, "items": {"type": "object", "required": ["classId"], "properties": {"classId": {"type": "string"}, "enrollmentIds": {"type": "array", "items": {"type": "string"}, "description": "Students of the class taking part; the whole class when empty"}}}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Member classes changed while the group has sessions, or a session now clashes"}
+                }
+            },
+            "delete": {
+                "tags": ["Lesson Groups"],
+                "summary": "Delete a lesson group without sessions",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "Deleted"},
+                    "409": {"description": "The group still has sessions"}
+                }
+            }
+        },
+        "/lesson-groups/{id}/sessions": {
+            "get": {
+                "tags": ["Lesson Groups"],
+                "summary": "List a lesson group's weekly sessions",
+                "description": "scheduleIds maps each member class to the daily schedule booked for it.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Lesson Groups"],
+                "summary": "Schedule a weekly session for every member class",
+                "description": "Books one daily schedule per member class. Fails when a member class, the teacher or the room is busy in the slot; a class may attend two groups at once only when they take disjoint subsets of its students. The schedules can only be changed or removed through the group.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["dayOfWeek", "timeSlot"], "properties": {"dayOfWeek": {"type": "string", "example": "MONDAY"}, "timeSlot": {"type": "string"}, "room": {"type": "string", "description": "Defaults to the group's room"}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Schedule conflict"}
+                }
+            },
+            "delete": {
+                "tags": ["Lesson Groups"],
+                "summary": "Remove a weekly session from every member class",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "dayOfWeek", "in": "query", "required": true, "type": "string"},
+                    {"name": "timeSlot", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "Deleted"},
+                    "404": {"description": "The group has no session in the slot"}
+                }
+            }
+        },
+        "/lesson-groups/{id}/roster": {
+            "get": {
+                "tags": ["Lesson Groups"],
+                "summary": "List the students taking part in a lesson group",
+                "description": "Whole member classes contribute their active enrollments. Teachers only see their own groups.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Not the group's teacher"}
+                }
+            }
+        },
+        "/lesson-groups/{id}/attendance": {
+            "post": {
+                "tags": ["Lesson Groups"],
+                "summary": "Mark attendance for a lesson group session",
+                "description": "Records each student against their own class's schedule for the session held on date in timeSlot, so marks appear in class subject attendance. Every enrollment must be on the group's roster. Teachers may only mark their own groups.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["date", "timeSlot", "items"], "properties": {"date": {"type": "string", "format": "date"}, "timeSlot": {"type": "string"}, "mode": {"type": "string", "enum": ["atomic", "partialOnError"], "default": "atomic"}, "makeup": {"type": "boolean"}, "items": {"type": "array", "items": {"type": "object", "required": ["enrollmentId", "status"], "properties": {"enrollmentId": {"type": "string"}, "status": {"type": "string", "enum": ["H", "S", "I", "A"]}, "notes": {"type": "string"}}}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "The group has no session on that day and slot"},
+                    "412": {"description": "Attendance is disabled"}
+                }
+            },
+            "get": {
+                "tags": ["Lesson Groups"],
+                "summary": "Report attendance of a lesson group session",
+                "description": "Lists every student on the roster with their mark; status is empty while unmarked.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "date", "in": "query", "required": true, "type": "string", "format": "date"},
+                    {"name": "timeSlot", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "The group has no session on that day and slot"},
+                    "412": {"description": "Attendance is disabled"}
+                }
+            }
+        },
+        "/classes/{id}/timetable": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "Get a class's effective timetable for one week",
+                "description": "Starts from the latest published semester schedule. Daily schedules replace its lessons slot by slot and exam sittings replace both on their date (source SEMESTER, DAILY or EXAM). Holidays and dates outside the term have no cells. Lessons whose teacher is on approved leave have teacherOnLeave and leaveId; GET /teacher-leaves/{id}/substitutions suggests cover.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Term ID, defaults to the active term reported as meta.resolved_term_id"},
+                    {"name": "week", "in": "query", "required": false, "type": "string", "format": "date", "description": "Any date in the week, defaults to the current week"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "week is not a YYYY-MM-DD date"},
+                    "404": {"description": "Term or class not found"}
+                }
+            }
+        },
+        "/schedule/score": {
+            "post": {
+                "tags": ["Scheduler"],
+                "summary": "Score a hand-built timetable",
+                "description": "Applies the generator's gap, load, constraint and conflict penalties to the slots and returns the score without creating a proposal. Without subjectLoads the weekly counts are taken from the slots.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["termId", "classId", "timeSlotsPerDay", "days", "slots"], "properties": {"termId": {"type": "string"}, "classId": {"type": "string"}, "timeSlotsPerDay": {"type": "integer", "minimum": 1, "maximum": 16}, "days": {"type": "array", "items": {"type": "integer", "minimum": 1, "maximum": 7}}, "slots": {"type": "array", "items": {"type": "object", "required": ["dayOfWeek", "timeSlot", "subjectId", "teacherId"], "properties": {"dayOfWeek": {"type": "integer", "minimum": 1, "maximum": 7}, "timeSlot": {"type": "integer", "minimum": 1}, "subjectId": {"type": "string"}, "teacherId": {"type": "string"}, "room": {"type": "string"}}}}, "subjectLoads": {"type": "array", "items": {"type": "object", "required": ["subjectId", "teacherId", "weeklyCount"], "properties": {"subjectId": {"type": "string"}, "teacherId": {"type": "string"}, "weeklyCount": {"type": "integer", "minimum": 1, "description": "Periods per week; a multiple of blockSize"}, "blockSize": {"type": "integer", "minimum": 1, "maximum": 4, "description": "Consecutive periods per lesson, e.g. 2 for a double lesson"}, "difficulty": {"type": "integer", "minimum": 1, "maximum": 10}, "tags": {"type": "array", "items": {"type": "string"}}}}}, "hardConstraints": {"type": "array", "items": {"type": "string"}}, "softConstraints": {"type": "array", "items": {"type": "string"}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "Score, conflictPenalty, stats (gapPenalty, loadPenalty, constraintPenalty) and conflicts", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Slot outside the days or slots per day, two lessons in one slot, or a teacher not assigned to the subject"},
+                    "404": {"description": "Term, class or subject not found"}
+                }
+            }
+        },
+        "/schedule/presets": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "List subject load presets",
+                "parameters": [
+                    {"name": "grade", "in": "query", "type": "string"},
+                    {"name": "track", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Scheduler"],
+                "summary": "Create a subject load preset",
+                "description": "Generation payloads pass presetId instead of subjectLoads; each subject takes the teacher assigned to it in the class and term.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["name", "grade", "loads"], "properties": {"name": {"type": "string"}, "grade": {"type": "string", "example": "10"}, "track": {"type": "string", "example": "IPA"}, "loads": {"type": "array", "items": {"type": "object", "required": ["subjectId", "weeklyCount"], "properties": {"subjectId": {"type": "string"}, "weeklyCount": {"type": "integer", "minimum": 1, "description": "Periods per week; a multiple of blockSize"}, "blockSize": {"type": "integer", "minimum": 1, "maximum": 4, "description": "Consecutive periods per lesson, e.g. 2 for a double lesson"}, "difficulty": {"type": "integer", "minimum": 1, "maximum": 10}}}}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Name already used"}
+                }
+            }
+        },
+        "/schedule/presets/{id}": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "Get a subject load preset",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Not Found"}
+                }
+            },
+            "put": {
+                "tags": ["Scheduler"],
+                "summary": "Replace a subject load preset",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["name", "grade", "loads"], "properties": {"name": {"type": "string"}, "grade": {"type": "string", "example": "10"}, "track": {"type": "string", "example": "IPA"}, "loads": {"type": "array", "items": {"type": "object", "required": ["subjectId", "weeklyCount"], "properties": {"subjectId": {"type": "string"}, "weeklyCount": {"type": "integer", "minimum": 1, "description": "Periods per week; a multiple of blockSize"}, "blockSize": {"type": "integer", "minimum": 1, "maximum": 4, "description": "Consecutive periods per lesson, e.g. 2 for a double lesson"}, "difficulty": {"type": "integer", "minimum": 1, "maximum": 10}}}}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Not Found"}
+                }
+            },
+            "delete": {
+                "tags": ["Scheduler"],
+                "summary": "Delete a subject load preset",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"},
+                    "404": {"description": "Not Found"}
+                }
+            }
+        },
+        "/semester-schedule/{id}/warnings": {
+            "get": {
+                "tags": ["Scheduler"],
+                "summary": "List conflicts found in a published semester schedule",
+                "description": "Recorded when teacher assignments or preferences change after the schedule was published.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/semester-schedule/{id}/revalidate": {
+            "post": {
+                "tags": ["Scheduler"],
+                "summary": "Re-check a published semester schedule now",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Schedule is not published"}
+                }
+            }
+        },
+        "/reports/generate": {
+            "post": {
+                "tags": ["Reports"],
+                "summary": "Queue a new report job",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["type", "termId", "format"],
+                            "properties": {
+                                "type": {"type": "string", "enum": ["attendance", "grades", "behavior", "summary", "research", "ministry_attendance", "ministry_grades"], "description": "research is a pseudonymized zip of attendance, grades and behavior CSVs, for super admins only; ministry_* are the ministry import layouts, for administrators only"},
+                                "termId": {"type": "string"},
+                                "classId": {"type": "string"},
+                                "format": {"type": "string", "enum": ["csv", "pdf", "xlsx", "zip"], "description": "zip is required for, and only allowed with, research; ministry_* take csv or xlsx, and only they take xlsx"},
+                                "priority": {"type": "string", "enum": ["high", "normal", "low"], "default": "normal", "description": "high is reserved for administrators"},
+                                "force": {"type": "boolean", "description": "Queue a new job even if an identical one is in flight or recent"}
+                            }
+                        }
+                    },
+                    {"name": "force", "in": "query", "type": "boolean", "description": "Same as force in the body"}
+                ],
+                "responses": {
+                    "200": {"description": "An identical in-flight or recently finished job was returned (deduplicated=true)", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "202": {"description": "Accepted", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Research export requested by anyone but a super admin"},
+                    "412": {"description": "Research exports are not configured (REPORTS_RESEARCH_KEY)"},
+                    "507": {"description": "Report storage is full or not writable (INSUFFICIENT_STORAGE)", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/reports/status/{id}": {
+            "get": {
+                "tags": ["Reports"],
+                "summary": "Get report job status",
+                "description": "Queued jobs include queuePosition, the 1-based position among queued jobs ordered by priority then creation time.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/reports/templates": {
+            "get": {
+                "tags": ["Reports"],
+                "summary": "List export templates",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Reports"],
+                "summary": "Create the export template of a report type",
+                "description": "Each report type has at most one template. Generated exports use its columns in order, with label overriding the default header; groupBy keeps rows with the same value together.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/ExportTemplateRequest"}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "The report type already has a template"}
+                }
+            }
+        },
+        "/reports/templates/columns": {
+            "get": {
+                "tags": ["Reports"],
+                "summary": "List the columns each report type offers to export templates",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/reports/templates/{id}": {
+            "get": {
+                "tags": ["Reports"],
+                "summary": "Get an export template",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["Reports"],
+                "summary": "Replace an export template",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/ExportTemplateRequest"}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "delete": {
+                "tags": ["Reports"],
+                "summary": "Delete an export template so the default layout applies",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/internal/backups": {
+            "get": {
+                "tags": ["Internal"],
+                "summary": "List the most recent backups with their status",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Internal"],
+                "summary": "Queue an encrypted database backup",
+                "description": "Dumps the configured schemas and lists the storage files in a manifest. Restore with cmd/restore.",
+                "responses": {
+                    "202": {"description": "Accepted", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "A backup is already queued or running"},
+                    "507": {"description": "Backup directory is full or not writable"}
+                }
+            }
+        },
+        "/internal/backups/{id}": {
+            "get": {
+                "tags": ["Internal"],
+                "summary": "Get a backup",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Not Found"}
+                }
+            }
+        },
+        "/internal/sync/attendance": {
+            "post": {
+                "tags": ["Internal"],
+                "summary": "Upsert daily attendance written by the legacy app",
+                "description": "Records are applied independently; rejected ones are listed in failures. Retrying with the same Idempotency-Key returns the stored outcome.",
+                "parameters": [
+                    {"name": "X-API-Key", "in": "header", "required": true, "type": "string"},
+                    {"name": "Idempotency-Key", "in": "header", "required": true, "type": "string", "maxLength": 128},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["records"],
+                            "properties": {
+                                "records": {
+                                    "type": "array",
+                                    "minItems": 1,
+                                    "maxItems": 1000,
+                                    "items": {
+                                        "type": "object",
+                                        "required": ["studentId", "termId", "date", "status"],
+                                        "properties": {
+                                            "studentId": {"type": "string"},
+                                            "termId": {"type": "string"},
+                                            "date": {"type": "string", "format": "date"},
+                                            "status": {"type": "string", "description": "H, S, I, A or hadir/sakit/izin/alpa or present/sick/permission/absent"},
+                                            "notes": {"type": "string"}
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "401": {"description": "Missing or unknown API key"},
+                    "409": {"description": "Idempotency-Key reused for a different payload"}
+                }
+            }
+        },
+        "/internal/sync/grades": {
+            "post": {
+                "tags": ["Internal"],
+                "summary": "Upsert grades written by the legacy app",
+                "description": "Records are applied independently; rejected ones are listed in failures. Retrying with the same Idempotency-Key returns the stored outcome.",
+                "parameters": [
+                    {"name": "X-API-Key", "in": "header", "required": true, "type": "string"},
+                    {"name": "Idempotency-Key", "in": "header", "required": true, "type": "string", "maxLength": 128},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["records"],
+                            "properties": {
+                                "records": {
+                                    "type": "array",
+                                    "minItems": 1,
+                                    "maxItems": 1000,
+                                    "items": {
+                                        "type": "object",
+                                        "required": ["studentId", "termId", "subjectId", "component", "score"],
+                                        "properties": {
+                                            "studentId": {"type": "string"},
+                                            "termId": {"type": "string"},
+                                            "subjectId": {"type": "string"},
+                                            "component": {"type": "string", "description": "Grade component code"},
+                                            "score": {"type": "number", "minimum": 0, "maximum": 100}
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "401": {"description": "Missing or unknown API key"},
+                    "409": {"description": "Idempotency-Key reused for a different payload"}
+                }
+            }
+        },
+        "/internal/sync/reconciliation": {
+            "get": {
+                "tags": ["Internal"],
+                "summary": "Summarise legacy sync batches and the records they rejected",
+                "parameters": [
+                    {"name": "X-API-Key", "in": "header", "required": true, "type": "string"},
+                    {"name": "from", "in": "query", "type": "string", "format": "date", "description": "Defaults to six days before to"},
+                    {"name": "to", "in": "query", "type": "string", "format": "date", "description": "Defaults to today; the range spans at most 31 days"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/checkin": {
+            "post": {
+                "tags": ["Attendance"],
+                "summary": "Record a gate check-in from a device",
+                "parameters": [
+                    {"name": "X-Device-Key", "in": "header", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "nis": {"type": "string"},
+                                "qrToken": {"type": "string"},
+                                "timestamp": {"type": "string", "format": "date-time"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "200": {"description": "Already recorded for the day", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/checkin/qr/{studentId}": {
+            "get": {
+                "tags": ["Attendance"],
+                "summary": "Issue a signed check-in QR token for a student card",
+                "parameters": [
+                    {"name": "studentId", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/imports": {
+            "post": {
+                "tags": ["Attendance"],
+                "summary": "Queue a bulk daily attendance import",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["date", "items", "mode"],
+                            "properties": {
+                                "date": {"type": "string", "format": "date"},
+                                "mode": {"type": "string", "enum": ["atomic", "partialOnError"]},
+                                "makeup": {"type": "boolean"},
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "required": ["enrollment_id", "status"],
+                                        "properties": {
+                                            "enrollment_id": {"type": "string"},
+                                            "status": {"type": "string"},
+                                            "notes": {"type": "string"}
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {"description": "Accepted", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/imports/{id}": {
+            "get": {
+                "tags": ["Attendance"],
+                "summary": "Get attendance import status and per-row conflicts",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/excuses": {
+            "put": {
+                "tags": ["Attendance"],
+                "summary": "Record the reason and evidence for an absence",
+                "description": "Sets the day's status from the reason (S for SICK, I for PERMISSION and SCHOOL_DUTY) and replaces the linked evidence. Evidence must be STUDENT-scope archives of the enrollment's student. Teachers may only excuse students of classes they teach.",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["enrollmentId", "date", "reason"],
+                            "properties": {
+                                "enrollmentId": {"type": "string"},
+                                "date": {"type": "string", "format": "date"},
+                                "reason": {"type": "string", "enum": ["SICK", "PERMISSION", "SCHOOL_DUTY"]},
+                                "notes": {"type": "string"},
+                                "evidenceIds": {"type": "array", "maxItems": 10, "items": {"type": "string"}},
+                                "makeup": {"type": "boolean"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/thresholds": {
+            "get": {
+                "tags": ["Attendance"],
+                "summary": "List attendance alert thresholds of a term",
+                "parameters": [
+                    {"name": "termId", "in": "query", "type": "string", "description": "Defaults to the active term"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["Attendance"],
+                "summary": "Set the attendance alert threshold of a class",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["classId", "termId", "minPercentage"],
+                            "properties": {
+                                "classId": {"type": "string"},
+                                "termId": {"type": "string"},
+                                "minPercentage": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 100}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/thresholds/{classId}": {
+            "delete": {
+                "tags": ["Attendance"],
+                "summary": "Remove a class threshold so the default applies",
+                "parameters": [
+                    {"name": "classId", "in": "path", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/attendance/alerts": {
+            "get": {
+                "tags": ["Attendance"],
+                "summary": "List students below their class attendance threshold at the last evaluation",
+                "parameters": [
+                    {"name": "termId", "in": "query", "type": "string", "description": "Defaults to the active term"},
+                    {"name": "classId", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/attendance/alerts/evaluate": {
+            "post": {
+                "tags": ["Attendance"],
+                "summary": "Evaluate attendance thresholds now instead of waiting for the nightly run",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/alert-rules": {
+            "get": {
+                "tags": ["Alert Rules"],
+                "summary": "List alert rules",
+                "parameters": [
+                    {"name": "includeDisabled", "in": "query", "type": "boolean"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Alert Rules"],
+                "summary": "Create an alert rule, evaluated from the next run on",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["name", "metric", "comparator", "audience", "channel"],
+                            "properties": {
+                                "name": {"type": "string", "maxLength": 120},
+                                "metric": {"type": "string", "enum": ["CLASS_ATTENDANCE_RATE", "CLASS_AVERAGE_GRADE"]},
+                                "comparator": {"type": "string", "enum": ["LT", "LTE", "GT", "GTE"]},
+                                "threshold": {"type": "number", "minimum": 0, "maximum": 100},
+                                "audience": {"type": "string", "enum": ["HOMEROOM", "TEACHERS", "ADMINS"]},
+                                "channel": {"type": "string", "enum": ["DASHBOARD", "NOTIFICATION"]},
+                                "enabled": {"type": "boolean", "description": "Defaults to true"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/alert-rules/{id}": {
+            "put": {
+                "tags": ["Alert Rules"],
+                "summary": "Replace an alert rule",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["name", "metric", "comparator", "audience", "channel"],
+                            "properties": {
+                                "name": {"type": "string", "maxLength": 120},
+                                "metric": {"type": "string", "enum": ["CLASS_ATTENDANCE_RATE", "CLASS_AVERAGE_GRADE"]},
+                                "comparator": {"type": "string", "enum": ["LT", "LTE", "GT", "GTE"]},
+                                "threshold": {"type": "number", "minimum": 0, "maximum": 100},
+                                "audience": {"type": "string", "enum": ["HOMEROOM", "TEACHERS", "ADMINS"]},
+                                "channel": {"type": "string", "enum": ["DASHBOARD", "NOTIFICATION"]},
+                                "enabled": {"type": "boolean", "description": "Defaults to true"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "delete": {
+                "tags": ["Alert Rules"],
+                "summary": "Delete an alert rule and its open breaches",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/alert-rules/breaches": {
+            "get": {
+                "tags": ["Alert Rules"],
+                "summary": "List open alert rule breaches; teachers only see their classes and rules addressed to teachers",
+                "parameters": [
+                    {"name": "termId", "in": "query", "type": "string", "description": "Defaults to the active term"},
+                    {"name": "classId", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/alert-rules/evaluate": {
+            "post": {
+                "tags": ["Alert Rules"],
+                "summary": "Evaluate the alert rules now instead of waiting for the scheduled run",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-attendance/checkin": {
+            "post": {
+                "tags": ["Teacher Attendance"],
+                "summary": "Clock in the authenticated teacher",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": false,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "latitude": {"type": "number"},
+                                "longitude": {"type": "number"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "200": {"description": "Already clocked in today", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-attendance/checkout": {
+            "post": {
+                "tags": ["Teacher Attendance"],
+                "summary": "Clock out the authenticated teacher",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": false,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "latitude": {"type": "number"},
+                                "longitude": {"type": "number"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-attendance/recap": {
+            "get": {
+                "tags": ["Teacher Attendance"],
+                "summary": "Monthly presence recap for all active teachers",
+                "parameters": [
+                    {"name": "month", "in": "query", "required": false, "type": "string", "description": "YYYY-MM, defaults to the current month"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-attendance/teachers/{id}": {
+            "get": {
+                "tags": ["Teacher Attendance"],
+                "summary": "Monthly presence log for one teacher",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "month", "in": "query", "required": false, "type": "string", "description": "YYYY-MM, defaults to the current month"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-leaves": {
+            "post": {
+                "tags": ["Teacher Leave"],
+                "summary": "Request leave for a teacher",
+                "description": "Teachers request leave for themselves; administrators set teacherId to file on a teacher's behalf. days counts the school days in the range, skipping ATTENDANCE_NON_SCHOOL_WEEKDAYS and school-wide holidays.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["type", "startDate", "endDate"], "properties": {"teacherId": {"type": "string"}, "type": {"type": "string", "enum": ["SICK", "PERSONAL", "TRAINING"]}, "startDate": {"type": "string", "format": "date"}, "endDate": {"type": "string", "format": "date"}, "reason": {"type": "string", "maxLength": 1000}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Leave overlaps another pending or approved leave"}
+                }
+            },
+            "get": {
+                "tags": ["Teacher Leave"],
+                "summary": "List teacher leaves",
+                "description": "Teachers only see their own leaves.",
+                "parameters": [
+                    {"name": "teacherId", "in": "query", "required": false, "type": "string"},
+                    {"name": "status", "in": "query", "required": false, "type": "string", "enum": ["PENDING", "APPROVED", "REJECTED", "CANCELLED"]},
+                    {"name": "from", "in": "query", "required": false, "type": "string", "format": "date"},
+                    {"name": "to", "in": "query", "required": false, "type": "string", "format": "date"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-leaves/balance": {
+            "get": {
+                "tags": ["Teacher Leave"],
+                "summary": "Leave balance per teacher for a year",
+                "description": "Approved and pending days per type for leaves starting in the year, against the TEACHER_LEAVE_*_DAYS allowances. Teachers only get their own balance.",
+                "parameters": [
+                    {"name": "year", "in": "query", "required": false, "type": "integer", "description": "Defaults to the current year"},
+                    {"name": "teacherId", "in": "query", "required": false, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/teacher-leaves/{id}": {
+            "get": {
+                "tags": ["Teacher Leave"],
+                "summary": "Get a teacher leave",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Leave not found"}
+                }
+            }
+        },
+        "/teacher-leaves/{id}/approve": {
+            "post": {
+                "tags": ["Teacher Leave"],
+                "summary": "Approve a pending teacher leave",
+                "description": "Approved leave blocks the teacher as an exam invigilator on its dates.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": false, "schema": {"type": "object", "properties": {"note": {"type": "string", "maxLength": 1000}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Leave is not pending"}
+                }
+            }
+        },
+        "/teacher-leaves/{id}/reject": {
+            "post": {
+                "tags": ["Teacher Leave"],
+                "summary": "Reject a pending teacher leave",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": false, "schema": {"type": "object", "properties": {"note": {"type": "string", "maxLength": 1000}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Leave is not pending"}
+                }
+            }
+        },
+        "/teacher-leaves/{id}/cancel": {
+            "post": {
+                "tags": ["Teacher Leave"],
+                "summary": "Cancel a teacher leave",
+                "description": "Pending leaves can be cancelled any time, approved ones only before they start. Teachers may only cancel their own.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": false, "schema": {"type": "object", "properties": {"note": {"type": "string", "maxLength": 1000}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Leave has started or is closed"}
+                }
+            }
+        },
+        "/teacher-leaves/{id}/substitutions": {
+            "get": {
+                "tags": ["Teacher Leave"],
+                "summary": "List the lessons a leave affects with substitution suggestions",
+                "description": "For each school day of a pending or approved leave (at most 31), lists the teacher's lessons and up to five free teachers of the same subject or class in the term who are not on leave.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Leave is rejected or cancelled"}
+                }
+            }
+        },
+        "/guardians/{id}/contact": {
+            "get": {
+                "tags": ["Guardians"],
+                "summary": "Phone a guardian is messaged on",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Guardian has no contact"}
+                }
+            },
+            "put": {
+                "tags": ["Guardians"],
+                "summary": "Set the phone a guardian is messaged on",
+                "description": "Numbers without a country code are read as Indonesian. Administrators and the guardian themselves may call it; absenceAlerts=false opts out of absence messages.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["phone"], "properties": {"phone": {"type": "string", "example": "081234567890"}, "channel": {"type": "string", "enum": ["SMS", "WHATSAPP"]}, "absenceAlerts": {"type": "boolean"}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid phone or channel, or the user is not a guardian"}
+                }
+            },
+            "delete": {
+                "tags": ["Guardians"],
+                "summary": "Forget a guardian's phone",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"},
+                    "404": {"description": "Guardian has no contact"}
+                }
+            }
+        },
+        "/attendance/absence-messages": {
+            "get": {
+                "tags": ["Attendance"],
+                "summary": "Absence messages sent to guardians",
+                "parameters": [
+                    {"name": "date", "in": "query", "type": "string", "format": "date"},
+                    {"name": "studentId", "in": "query", "type": "string"},
+                    {"name": "guardianId", "in": "query", "type": "string"},
+                    {"name": "status", "in": "query", "type": "string", "enum": ["PENDING", "SENT", "DELIVERED", "FAILED", "SKIPPED"]},
+                    {"name": "page", "in": "query", "type": "integer"},
+                    {"name": "pageSize", "in": "query", "type": "integer"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/messaging/callbacks/{provider}": {
+            "get": {
+                "tags": ["Messaging"],
+                "summary": "Callback URL handshake of a messaging provider",
+                "produces": ["text/plain"],
+                "parameters": [
+                    {"name": "provider", "in": "path", "required": true, "type": "string", "enum": ["meta"]},
+                    {"name": "hub.mode", "in": "query", "type": "string"},
+                    {"name": "hub.verify_token", "in": "query", "type": "string"},
+                    {"name": "hub.challenge", "in": "query", "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "The hub.challenge value"},
+                    "401": {"description": "Verify token mismatch"}
+                }
+            },
+            "post": {
+                "tags": ["Messaging"],
+                "summary": "Delivery status callback of a messaging provider",
+                "description": "Unauthenticated; requests must carry the provider's signature (X-Twilio-Signature or X-Hub-Signature-256).",
+                "parameters": [
+                    {"name": "provider", "in": "path", "required": true, "type": "string", "enum": ["twilio", "meta"]}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"},
+                    "401": {"description": "Invalid signature"},
+                    "404": {"description": "Provider not configured"}
+                }
+            }
+        },
+        "/guardians/{id}/students": {
+            "get": {
+                "tags": ["Guardians"],
+                "summary": "List students linked to a guardian",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string", "description": "Guardian user ID"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Guardians"],
+                "summary": "Link a student to a guardian",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string", "description": "Guardian user ID"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["studentId"],
+                            "properties": {
+                                "studentId": {"type": "string"},
+                                "relationship": {"type": "string", "maxLength": 30}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/guardians/{id}/students/{studentId}": {
+            "delete": {
+                "tags": ["Guardians"],
+                "summary": "Remove a guardian's access to a student",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string", "description": "Guardian user ID"},
+                    {"name": "studentId", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/guardian/students": {
+            "get": {
+                "tags": ["Guardian Portal"],
+                "summary": "List the authenticated guardian's children",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/guardian/students/{studentId}/attendance": {
+            "get": {
+                "tags": ["Guardian Portal"],
+                "summary": "Attendance history for one of the guardian's children",
+                "parameters": [
+                    {"name": "studentId", "in": "path", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Defaults to the active term"},
+                    {"name": "startDate", "in": "query", "required": false, "type": "string", "format": "date"},
+                    {"name": "endDate", "in": "query", "required": false, "type": "string", "format": "date"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Student is not linked to this guardian"}
+                }
+            }
+        },
+        "/guardian/students/{studentId}/report-card": {
+            "get": {
+                "tags": ["Guardian Portal"],
+                "summary": "Report card for one of the guardian's children",
+                "parameters": [
+                    {"name": "studentId", "in": "path", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Defaults to the active term"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Student is not linked to this guardian"}
+                }
+            }
+        },
+        "/guardian/students/{studentId}/grade-appeals": {
+            "post": {
+                "tags": ["Grade Appeals"],
+                "summary": "Appeal a final grade of a linked student",
+                "parameters": [
+                    {"name": "studentId", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["subjectId", "termId", "requestedGrade", "reason"], "properties": {"subjectId": {"type": "string"}, "termId": {"type": "string"}, "requestedGrade": {"type": "number", "minimum": 0, "maximum": 100}, "reason": {"type": "string", "maxLength": 2000}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Student is not linked to this guardian"},
+                    "409": {"description": "An appeal against the grade is already open"},
+                    "412": {"description": "Grade is not finalized"}
+                }
+            }
+        },
+        "/guardian/announcements": {
+            "get": {
+                "tags": ["Guardian Portal"],
+                "summary": "Announcements for students and the children's classes",
+                "parameters": [
+                    {"name": "page", "in": "query", "required": false, "type": "integer"},
+                    {"name": "pageSize", "in": "query", "required": false, "type": "integer"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/guardian/calendar": {
+            "get": {
+                "tags": ["Guardian Portal"],
+                "summary": "School calendar for the guardian's children",
+                "parameters": [
+                    {"name": "startDate", "in": "query", "required": false, "type": "string", "format": "date", "description": "Defaults to today"},
+                    {"name": "endDate", "in": "query", "required": false, "type": "string", "format": "date", "description": "Defaults to 30 days after startDate"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/students/{id}/account": {
+            "post": {
+                "tags": ["Students"],
+                "summary": "Create the STUDENT login for a student",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["email", "password"],
+                            "properties": {
+                                "email": {"type": "string", "format": "email"},
+                                "password": {"type": "string", "minLength": 6}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Student already has an account"}
+                }
+            }
+        },
+        "/student/schedule": {
+            "get": {
+                "tags": ["Student Portal"],
+                "summary": "Timetable of the authenticated student's active class",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/student/attendance": {
+            "get": {
+                "tags": ["Student Portal"],
+                "summary": "Attendance history of the authenticated student",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Defaults to the active enrollment's term"},
+                    {"name": "startDate", "in": "query", "required": false, "type": "string", "format": "date"},
+                    {"name": "endDate", "in": "query", "required": false, "type": "string", "format": "date"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/student/report-card": {
+            "get": {
+                "tags": ["Student Portal"],
+                "summary": "Report card of the authenticated student",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": false, "type": "string", "description": "Defaults to the active enrollment's term"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/student/grade-appeals": {
+            "post": {
+                "tags": ["Grade Appeals"],
+                "summary": "Appeal one of the authenticated student's final grades",
+                "description": "Only finalized grades can be appealed, one open appeal per grade. The appeal goes to the class's subject teacher, or straight to super admins when the subject has none.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["subjectId", "termId", "requestedGrade", "reason"], "properties": {"subjectId": {"type": "string"}, "termId": {"type": "string"}, "requestedGrade": {"type": "number", "minimum": 0, "maximum": 100}, "reason": {"type": "string", "maxLength": 2000}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "No final grade in that subject and term"},
+                    "409": {"description": "An appeal against the grade is already open"},
+                    "412": {"description": "Grade is not finalized"}
+                }
+            }
+        },
+        "/exam-periods": {
+            "get": {
+                "tags": ["Exams"],
+                "summary": "List exam periods of a term",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Exams"],
+                "summary": "Create an exam period within a term",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["termId", "name", "startDate", "endDate"],
+                            "properties": {
+                                "termId": {"type": "string"},
+                                "name": {"type": "string"},
+                                "startDate": {"type": "string", "format": "date"},
+                                "endDate": {"type": "string", "format": "date"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Overlaps another exam period"}
+                }
+            }
+        },
+        "/exam-periods/{id}": {
+            "delete": {
+                "tags": ["Exams"],
+                "summary": "Delete an exam period and its sittings",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/exam-periods/{id}/schedules": {
+            "get": {
+                "tags": ["Exams"],
+                "summary": "List the sittings of an exam period",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "classId", "in": "query", "required": false, "type": "string"},
+                    {"name": "room", "in": "query", "required": false, "type": "string"},
+                    {"name": "invigilatorId", "in": "query", "required": false, "type": "string"},
+                    {"name": "date", "in": "query", "required": false, "type": "string", "format": "date"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Exams"],
+                "summary": "Place a sitting, rejecting clashes with exams, regular lessons and invigilator availability",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["classId", "subjectId", "date", "startSlot", "endSlot", "room", "invigilatorId"],
+                            "properties": {
+                                "classId": {"type": "string"},
+                                "subjectId": {"type": "string"},
+                                "date": {"type": "string", "format": "date"},
+                                "startSlot": {"type": "integer"},
+                                "endSlot": {"type": "integer"},
+                                "room": {"type": "string"},
+                                "invigilatorId": {"type": "string"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Exam conflict"}
+                }
+            }
+        },
+        "/exam-periods/{id}/schedules/{examId}": {
+            "delete": {
+                "tags": ["Exams"],
+                "summary": "Remove a sitting from an exam period",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "examId", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/exam-periods/{id}/generate": {
+            "post": {
+                "tags": ["Exams"],
+                "summary": "Generate the exam timetable, reporting exams that could not be placed",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["classIds", "sessions", "rooms", "invigilatorIds"],
+                            "properties": {
+                                "classIds": {"type": "array", "items": {"type": "string"}},
+                                "subjectIds": {"type": "array", "items": {"type": "string"}},
+                                "sessions": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "startSlot": {"type": "integer"},
+                                            "endSlot": {"type": "integer"}
+                                        }
+                                    }
+                                },
+                                "rooms": {"type": "array", "items": {"type": "string"}},
+                                "invigilatorIds": {"type": "array", "items": {"type": "string"}},
+                                "days": {"type": "array", "items": {"type": "integer"}}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/exam-periods/{id}/export": {
+            "get": {
+                "tags": ["Exams"],
+                "summary": "Export the exam timetable of a class or room",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "format", "in": "query", "required": false, "type": "string", "enum": ["pdf", "xlsx"]},
+                    {"name": "view", "in": "query", "required": false, "type": "string", "enum": ["class", "room"]},
+                    {"name": "classId", "in": "query", "required": false, "type": "string"},
+                    {"name": "room", "in": "query", "required": false, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/calendar/events/{id}/rsvp-options": {
+            "put": {
+                "tags": ["Calendar"],
+                "summary": "Open or close RSVPs for a calendar event",
+                "description": "Admins only. capacity limits GOING answers; omit it for unlimited seats. Lowering it keeps the RSVPs already accepted.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "properties": {"enabled": {"type": "boolean"}, "capacity": {"type": "integer", "minimum": 1}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Event not found"}
+                }
+            }
+        },
+        "/calendar/events/{id}/rsvp": {
+            "put": {
+                "tags": ["Calendar"],
+                "summary": "RSVP to a calendar event",
+                "description": "Teachers and guardians invited by the event audience answer until the event is over; answering again replaces the RSVP. Guardians are invited to CLASS events of their children's classes.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["response"], "properties": {"response": {"type": "string", "enum": ["GOING", "NOT_GOING"]}, "note": {"type": "string", "maxLength": 500}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Caller is not invited"},
+                    "409": {"description": "Event is full"},
+                    "412": {"description": "Event does not accept RSVPs or is over"}
+                }
+            }
+        },
+        "/calendar/events/{id}/rsvps": {
+            "get": {
+                "tags": ["Calendar"],
+                "summary": "List the RSVPs and check-ins of a calendar event",
+                "description": "Returns going, not going, walk-in and checked-in counts, the seats left when the event has a capacity, and every RSVP.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/calendar/events/{id}/attendance": {
+            "post": {
+                "tags": ["Calendar"],
+                "summary": "Check an attendee in on the event day",
+                "description": "Staff capture attendance between the event's start and end dates in the school time zone. Attendees without an RSVP are added as WALK_IN and do not count against the capacity; checking in twice keeps the first check-in.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["userId"], "properties": {"userId": {"type": "string"}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Event or user not found"},
+                    "412": {"description": "Not the event day"}
+                }
+            }
+        },
+        "/calendar/events/{id}/attendance/export": {
+            "get": {
+                "tags": ["Calendar"],
+                "summary": "Export the attendance list of a calendar event",
+                "description": "Available when reports are enabled. Renders every RSVP, walk-in and check-in and returns a signed download URL.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "format", "in": "query", "required": false, "type": "string", "enum": ["pdf", "xlsx"]}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/curriculum/topics": {
+            "get": {
+                "tags": ["Curriculum"],
+                "summary": "List the syllabus topics of a subject in a term",
+                "parameters": [
+                    {"name": "subjectId", "in": "query", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Curriculum"],
+                "summary": "Add a syllabus topic planned for a week of the term",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["subjectId", "termId", "title", "plannedWeek"],
+                            "properties": {
+                                "subjectId": {"type": "string"},
+                                "termId": {"type": "string"},
+                                "title": {"type": "string"},
+                                "description": {"type": "string"},
+                                "plannedWeek": {"type": "integer", "minimum": 1}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/curriculum/topics/{id}": {
+            "put": {
+                "tags": ["Curriculum"],
+                "summary": "Update a syllabus topic",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["title", "plannedWeek"],
+                            "properties": {
+                                "title": {"type": "string"},
+                                "description": {"type": "string"},
+                                "plannedWeek": {"type": "integer", "minimum": 1}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "delete": {
+                "tags": ["Curriculum"],
+                "summary": "Delete a syllabus topic and its progress marks",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/curriculum/topics/{id}/progress": {
+            "post": {
+                "tags": ["Curriculum"],
+                "summary": "Mark a topic as taught to a class",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["classId"],
+                            "properties": {
+                                "classId": {"type": "string"},
+                                "taughtOn": {"type": "string", "format": "date"},
+                                "notes": {"type": "string"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Teacher is not assigned to the class and subject"}
+                }
+            }
+        },
+        "/curriculum/topics/{id}/progress/{classId}": {
+            "delete": {
+                "tags": ["Curriculum"],
+                "summary": "Clear a topic's taught mark for a class",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "classId", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "204": {"description": "No Content"}
+                }
+            }
+        },
+        "/curriculum/progress": {
+            "get": {
+                "tags": ["Curriculum"],
+                "summary": "List a subject's topics with a class's taught marks",
+                "parameters": [
+                    {"name": "classId", "in": "query", "required": true, "type": "string"},
+                    {"name": "subjectId", "in": "query", "required": true, "type": "string"},
+                    {"name": "termId", "in": "query", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/curriculum/coverage": {
+            "get": {
+                "tags": ["Curriculum"],
+                "summary": "Syllabus coverage (planned vs. taught) per class and subject",
+                "parameters": [
+                    {"name": "termId", "in": "query", "required": true, "type": "string"},
+                    {"name": "classId", "in": "query", "required": false, "type": "string"},
+                    {"name": "subjectId", "in": "query", "required": false, "type": "string"},
+                    {"name": "teacherId", "in": "query", "required": false, "type": "string"},
+                    {"name": "date", "in": "query", "required": false, "type": "string", "format": "date"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/grade-components": {
+            "post": {
+                "tags": ["Grade Components"],
+                "summary": "Create a grade component",
+                "description": "Grades are recorded per component in numbered entries (entry 1, 2, ...), such as the quizzes of a term.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["code", "name"], "properties": {"code": {"type": "string"}, "name": {"type": "string"}, "description": {"type": "string"}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Component code already exists"}
+                }
+            }
+        },
+        "/grade-configs": {
+            "post": {
+                "tags": ["Grades"],
+                "summary": "Create a grade calculation config for a class, subject and term",
+                "description": "DROP_LOWEST scores each component by the average of its entries after discarding the drop_lowest lowest ones, always keeping one; BEST_N by the average of its best_n highest entries. Component scores are combined by weight, or equally when no graded component has a weight. AVERAGE averages the component averages, so a component with more entries does not outweigh the others. A config component's min_grades holds back the final grade until the student has that many entries in it.",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["class_id", "subject_id", "term_id", "calculation_scheme", "components"],
+                            "properties": {
+                                "class_id": {"type": "string"},
+                                "subject_id": {"type": "string"},
+                                "term_id": {"type": "string"},
+                                "calculation_scheme": {"type": "string", "enum": ["WEIGHTED", "AVERAGE", "DROP_LOWEST", "BEST_N"]},
+                                "drop_lowest": {"type": "integer", "description": "Required by DROP_LOWEST"},
+                                "best_n": {"type": "integer", "description": "Required by BEST_N"},
+                                "rounding_mode": {"type": "string", "enum": ["HALF_EVEN", "HALF_UP"], "default": "HALF_EVEN"},
+                                "rounding_places": {"type": "integer", "minimum": 0, "maximum": 4, "default": 2},
+                                "round_to_integer": {"type": "boolean", "description": "Round the final grade to a whole number instead of rounding_places decimals"},
+                                "kkm": {"type": "number", "minimum": 0, "maximum": 100, "description": "Minimum mastery score; finals below it are flagged below_kkm"},
+                                "components": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "object",
+                                        "properties": {
+                                            "component_id": {"type": "string"},
+                                            "weight": {"type": "number", "description": "Weights must sum to 100 for WEIGHTED; optional for DROP_LOWEST and BEST_N"},
+                                            "min_grades": {"type": "integer", "minimum": 0, "description": "Entries a student needs in the component before their final grade is calculated"}
+                                        }
+                                    }
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid scheme options"},
+                    "409": {"description": "A config already exists for the scope"}
+                }
+            }
+        },
+        "/grades/finalize-class": {
+            "post": {
+                "tags": ["Grades"],
+                "summary": "Finalize final grades for every subject of a class",
+                "description": "Recalculates and locks final grades for all subjects of a class in one transaction. Subjects without a grade config are skipped and listed with status skipped.",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["class_id", "term_id"],
+                            "properties": {
+                                "class_id": {"type": "string"},
+                                "term_id": {"type": "string"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "Per-subject results", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Class has no subjects"}
+                }
+            }
+        },
+        "/grades/remedial": {
+            "post": {
+                "tags": ["Grades"],
+                "summary": "Record a remedial score",
+                "description": "Stores a remedial score for a finalized final grade below KKM. The final grade is kept; report cards annotate the subject with remedial PENDING, PASSED or FAILED. Teachers may only record scores for subjects they teach in the enrollment's class.",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["enrollment_id", "subject_id", "grade_value"],
+                            "properties": {
+                                "enrollment_id": {"type": "string"},
+                                "subject_id": {"type": "string"},
+                                "grade_value": {"type": "number", "minimum": 0, "maximum": 100}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {"description": "Updated final grade", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "The teacher does not teach the subject in the enrollment's class"},
+                    "404": {"description": "Enrollment or final grade not found"},
+                    "412": {"description": "Final grade not finalized or already meets KKM"}
+                }
+            }
+        },
+        "/grades/unfinalize": {
+            "post": {
+                "tags": ["Grades"],
+                "summary": "Request reopening of finalized grades",
+                "description": "Files a GRADE_CORRECTION mutation (entity grade_finals) for the finalized grades in scope. Grades become editable once a super admin approves it via /mutations/{id}/review. Omit enrollment_ids to reopen every finalized student in the scope.",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["class_id", "subject_id", "term_id", "reason"],
+                            "properties": {
+                                "class_id": {"type": "string"},
+                                "subject_id": {"type": "string"},
+                                "term_id": {"type": "string"},
+                                "enrollment_ids": {"type": "array", "items": {"type": "string"}},
+                                "reason": {"type": "string"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {"description": "Pending mutation", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "No finalized grades in scope or mutation workflow disabled"}
+                }
+            }
+        },
+        "/lesson-plans": {
+            "get": {
+                "tags": ["LessonPlans"],
+                "summary": "List lesson plans (teachers only see their own)",
+                "parameters": [
+                    {"name": "teacherId", "in": "query", "type": "string"},
+                    {"name": "classId", "in": "query", "type": "string"},
+                    {"name": "subjectId", "in": "query", "type": "string"},
+                    {"name": "termId", "in": "query", "type": "string"},
+                    {"name": "status", "in": "query", "type": "string", "enum": ["DRAFT", "SUBMITTED", "APPROVED", "REJECTED"]},
+                    {"name": "week", "in": "query", "type": "string", "format": "date", "description": "Any date of the week"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["LessonPlans"],
+                "summary": "Draft a weekly lesson plan",
+                "parameters": [
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["classId", "subjectId", "termId", "weekStart", "title"],
+                            "properties": {
+                                "classId": {"type": "string"},
+                                "subjectId": {"type": "string"},
+                                "termId": {"type": "string"},
+                                "weekStart": {"type": "string", "format": "date", "description": "Normalised to the Monday of the week"},
+                                "title": {"type": "string"},
+                                "objectives": {"type": "string"},
+                                "activities": {"type": "string"},
+                                "assessment": {"type": "string"}
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "Teacher is not assigned to the class and subject"},
+                    "409": {"description": "A plan for this class, subject and week already exists"}
+                }
+            }
+        },
+        "/lesson-plans/{id}": {
+            "get": {
+                "tags": ["LessonPlans"],
+                "summary": "Get a lesson plan",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "put": {
+                "tags": ["LessonPlans"],
+                "summary": "Edit a draft or rejected lesson plan",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["title"],
+                            "properties": {
+                                "title": {"type": "string"},
+                                "objectives": {"type": "string"},
+                                "activities": {"type": "string"},
+                                "assessment": {"type": "string"}
+                            }
+                        }
+                    }
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Plan is submitted or approved"}
                 }
-            }
-        },
-        "/dashboard/academics": {
-            "get": {
-                "tags": ["Dashboard"],
-                "summary": "Teacher academics dashboard",
+            },
+            "delete": {
+                "tags": ["LessonPlans"],
+                "summary": "Delete a draft or rejected lesson plan",
                 "parameters": [
-                    {"name": "termId", "in": "query", "required": true, "type": "string", "description": "Term ID"},
-                    {"name": "date", "in": "query", "type": "string", "description": "Date (YYYY-MM-DD)"}
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "204": {"description": "No Content"}
                 }
             }
         },
-        "/teachers": {
-            "get": {
-                "tags": ["Teachers"],
-                "summary": "List teachers",
+        "/lesson-plans/{id}/attachment": {
+            "post": {
+                "tags": ["LessonPlans"],
+                "summary": "Attach a document to a draft or rejected lesson plan",
+                "description": "The file is stored as a CLASS-scoped archive item in the lesson_plan category and downloaded through /archives/{id}/download.",
+                "consumes": ["multipart/form-data"],
                 "parameters": [
-                    {"name": "search", "in": "query", "type": "string"},
-                    {"name": "active", "in": "query", "type": "boolean"},
-                    {"name": "page", "in": "query", "type": "integer"},
-                    {"name": "limit", "in": "query", "type": "integer"},
-                    {"name": "sort", "in": "query", "type": "string"},
-                    {"name": "order", "in": "query", "type": "string"}
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "file", "in": "formData", "required": true, "type": "file"}
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Archives are disabled"}
                 }
-            },
+            }
+        },
+        "/lesson-plans/{id}/submit": {
             "post": {
-                "tags": ["Teachers"],
-                "summary": "Create teacher",
+                "tags": ["LessonPlans"],
+                "summary": "Submit a lesson plan for review",
                 "parameters": [
-                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateTeacherRequest"}}
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Plan is already submitted or approved"}
                 }
             }
         },
-        "/teachers/{id}": {
-            "get": {
-                "tags": ["Teachers"],
-                "summary": "Get teacher",
+        "/lesson-plans/{id}/review": {
+            "post": {
+                "tags": ["LessonPlans"],
+                "summary": "Approve or reject a submitted lesson plan",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "required": ["status"],
+                            "properties": {
+                                "status": {"type": "string", "enum": ["APPROVED", "REJECTED"]},
+                                "note": {"type": "string", "description": "Required when rejecting"}
+                            }
+                        }
+                    }
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Plan is not awaiting review"}
                 }
-            },
-            "put": {
-                "tags": ["Teachers"],
-                "summary": "Update teacher",
+            }
+        },
+        "/notifications": {
+            "get": {
+                "tags": ["Notifications"],
+                "summary": "List the caller's notifications",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"},
-                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/UpdateTeacherRequest"}}
+                    {"name": "unread", "in": "query", "type": "boolean"},
+                    {"name": "limit", "in": "query", "type": "integer", "description": "Default 50, max 200"}
                 ],
                 "responses": {
                     "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
                 }
-            },
-            "delete": {
-                "tags": ["Teachers"],
-                "summary": "Deactivate teacher",
+            }
+        },
+        "/notifications/{id}/read": {
+            "post": {
+                "tags": ["Notifications"],
+                "summary": "Mark a notification as read",
                 "parameters": [
                     {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
@@ -124,112 +2503,129 @@ const docTemplate = `{
                 }
             }
         },
-        "/teachers/{id}/assignments": {
-            "get": {
-                "tags": ["Teacher Assignments"],
-                "summary": "List assignments",
+        "/notifications/devices": {
+            "post": {
+                "tags": ["Notifications"],
+                "summary": "Register a device for push notifications",
+                "description": "Registering a token again refreshes it; a token registered by another user is moved to the caller.",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["token", "platform"], "properties": {"token": {"type": "string", "description": "FCM registration token"}, "platform": {"type": "string", "enum": ["ANDROID", "IOS", "WEB"]}}}}
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid token or platform"}
                 }
-            },
-            "post": {
-                "tags": ["Teacher Assignments"],
-                "summary": "Create assignment",
+            }
+        },
+        "/notifications/devices/{token}": {
+            "delete": {
+                "tags": ["Notifications"],
+                "summary": "Stop push notifications to a device",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"},
-                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateTeacherAssignmentRequest"}}
+                    {"name": "token", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "204": {"description": "No Content"},
+                    "404": {"description": "Device not registered"}
                 }
             }
         },
-        "/teachers/{id}/assignments/{aid}": {
-            "delete": {
-                "tags": ["Teacher Assignments"],
-                "summary": "Delete assignment",
+        "/announcements/broadcast": {
+            "post": {
+                "tags": ["Announcements"],
+                "summary": "Broadcast an announcement to users by role",
+                "description": "Publishes the announcement to the portals and notifies every active user holding one of the roles, in-app and by push.",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"},
-                    {"name": "aid", "in": "path", "required": true, "type": "string"}
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["title", "content", "roles"], "properties": {"title": {"type": "string", "maxLength": 200}, "content": {"type": "string"}, "roles": {"type": "array", "items": {"type": "string", "enum": ["SUPERADMIN", "ADMIN", "TEACHER", "STUDENT", "GUARDIAN"]}}, "priority": {"type": "string", "enum": ["LOW", "NORMAL", "HIGH"]}, "isPinned": {"type": "boolean"}, "requiresAck": {"type": "boolean", "description": "Ask recipients to acknowledge the announcement"}, "expiresAt": {"type": "string", "format": "date-time"}}}}
                 ],
                 "responses": {
-                    "204": {"description": "No Content"}
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid payload or roles"}
                 }
             }
         },
-        "/teachers/{id}/preferences": {
-            "get": {
-                "tags": ["Teacher Preferences"],
-                "summary": "Get preferences",
+        "/announcements/{id}/ack": {
+            "post": {
+                "tags": ["Announcements"],
+                "summary": "Acknowledge an announcement sent to the caller",
+                "description": "Records a read receipt and marks the announcement notification read. Acknowledging again returns the first acknowledgement.",
                 "parameters": [
                     {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "403": {"description": "The announcement was not sent to the caller"},
+                    "404": {"description": "Announcement not found"}
                 }
-            },
-            "put": {
-                "tags": ["Teacher Preferences"],
-                "summary": "Upsert preferences",
+            }
+        },
+        "/announcements/{id}/acks": {
+            "get": {
+                "tags": ["Announcements"],
+                "summary": "List who acknowledged an announcement and who has not yet",
+                "description": "Returns recipient, acknowledged and pending counts with the acknowledging and missing users. Each list holds at most 500 users; truncated is set when some were left out.",
                 "parameters": [
                     {"name": "id", "in": "path", "required": true, "type": "string"},
-                    {"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/UpsertTeacherPreferenceRequest"}}
+                    {"name": "role", "in": "query", "required": false, "type": "string", "enum": ["SUPERADMIN", "ADMIN", "TEACHER", "STUDENT", "GUARDIAN"], "description": "Only recipients with this role"}
                 ],
                 "responses": {
-                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Announcement not found"}
                 }
             }
         },
-        "/reports/generate": {
-            "post": {
+        "/export/{token}": {
+            "get": {
                 "tags": ["Reports"],
-                "summary": "Queue a new report job",
+                "summary": "Download report using signed token",
                 "parameters": [
-                    {
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "required": ["type", "termId", "format"],
-                            "properties": {
-                                "type": {"type": "string", "enum": ["attendance", "grades", "behavior", "summary"]},
-                                "termId": {"type": "string"},
-                                "classId": {"type": "string"},
-                                "format": {"type": "string", "enum": ["csv", "pdf"]}
-                            }
-                        }
-                    }
+                    {"name": "token", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "202": {"description": "Accepted", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "200": {"description": "OK"}
                 }
             }
         },
-        "/reports/status/{id}": {
+        "/grade-appeals": {
             "get": {
-                "tags": ["Reports"],
-                "summary": "Get report job status",
+                "tags": ["Grade Appeals"],
+                "summary": "List grade appeals visible to the caller",
+                "description": "Students see their own appeals, guardians those of the linked studentId, teachers those routed to them and administrators all. APPROVED and REJECTED follow the super admin's review of the appeal's mutation.",
                 "parameters": [
-                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                    {"name": "studentId", "in": "query", "required": false, "type": "string"},
+                    {"name": "teacherId", "in": "query", "required": false, "type": "string", "description": "Administrators only"},
+                    {"name": "status", "in": "query", "required": false, "type": "string", "enum": ["TEACHER_REVIEW", "ADMIN_REVIEW", "APPROVED", "REJECTED"]}
                 ],
                 "responses": {
                     "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
                 }
             }
         },
-        "/export/{token}": {
+        "/grade-appeals/{id}": {
             "get": {
-                "tags": ["Reports"],
-                "summary": "Download report using signed token",
+                "tags": ["Grade Appeals"],
+                "summary": "Get a grade appeal",
                 "parameters": [
-                    {"name": "token", "in": "path", "required": true, "type": "string"}
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
                 "responses": {
-                    "200": {"description": "OK"}
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Appeal not found"}
+                }
+            }
+        },
+        "/grade-appeals/{id}/respond": {
+            "post": {
+                "tags": ["Grade Appeals"],
+                "summary": "Record the subject teacher's recommendation",
+                "description": "Forwards the appeal to super admins as a GRADE_CORRECTION mutation on entity grade_appeals. SUPPORT proposes proposedGrade, defaulting to the requested grade; OPPOSE needs a note. Approving the mutation corrects the final grade.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["recommendation"], "properties": {"recommendation": {"type": "string", "enum": ["SUPPORT", "OPPOSE"]}, "proposedGrade": {"type": "number", "minimum": 0, "maximum": 100}, "note": {"type": "string", "maxLength": 2000}}}}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Appeal is no longer awaiting the teacher"}
                 }
             }
         },
@@ -261,6 +2657,7 @@ const docTemplate = `{
             "get": {
                 "tags": ["Mutations"],
                 "summary": "Get mutation detail",
+                "description": "Until a mutation is approved the detail includes diff: one entry per requested field with field, label, old (the value in the snapshot taken at request time, null when absent), new and changed. Fields described by the entity's applier come first in display order.",
                 "parameters": [
                     {"name": "id", "in": "path", "required": true, "type": "string"}
                 ],
@@ -269,6 +2666,24 @@ const docTemplate = `{
                 }
             }
         },
+        "/mutations/{id}/attachments": {
+            "post": {
+                "tags": ["Mutations"],
+                "summary": "Attach supporting evidence to a pending mutation",
+                "description": "Stores the file in the archive with MUTATION scope; it is listed under attachments in the mutation detail and referenced in the review audit record. Teachers may only attach to their own requests. Requires archives to be enabled; at most 10 files per mutation.",
+                "consumes": ["multipart/form-data"],
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"},
+                    {"name": "file", "in": "formData", "required": true, "type": "file"}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Mutation already reviewed", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "412": {"description": "Archives are disabled", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "413": {"description": "Archive quota exceeded", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
         "/mutations/{id}/review": {
             "post": {
                 "tags": ["Mutations"],
@@ -310,7 +2725,61 @@ const docTemplate = `{
                     {"name": "file", "in": "formData", "required": true, "type": "file"}
                 ],
                 "responses": {
-                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "413": {"description": "QUOTA_EXCEEDED: the category or uploader quota would be exceeded", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/archives/usage": {
+            "get": {
+                "tags": ["Archives"],
+                "summary": "Archive storage usage and quotas",
+                "description": "Admins only. Bytes and files of live archives per category and for the 50 largest uploaders, with each quota (0 means unlimited) and the percentage used.",
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/archives/trash": {
+            "get": {
+                "tags": ["Archives"],
+                "summary": "List soft-deleted archives",
+                "description": "Super admins only. Newest deletion first; each item carries purgeAt, after which the file is removed for good.",
+                "parameters": [
+                    {"name": "category", "in": "query", "type": "string"},
+                    {"name": "limit", "in": "query", "type": "integer", "default": 50, "maximum": 200},
+                    {"name": "offset", "in": "query", "type": "integer", "default": 0}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/archives/{id}/restore": {
+            "post": {
+                "tags": ["Archives"],
+                "summary": "Restore a soft-deleted archive",
+                "description": "Super admins only. The archive starts a new retention period.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Archive is not in the trash", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/archives/retention": {
+            "get": {
+                "tags": ["Archives"],
+                "summary": "List archives due for retention deletion",
+                "description": "Admin report of the archives whose category retention period ends within the window, with the configured policies and trash grace period. Only routed when ARCHIVES_RETENTION is set.",
+                "parameters": [
+                    {"name": "days", "in": "query", "type": "integer", "default": 30, "maximum": 365, "description": "Expiry window in days"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid window", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
                 }
             }
         },
@@ -348,9 +2817,98 @@ const docTemplate = `{
                     "200": {"description": "OK"}
                 }
             }
+        },
+        "/archives/{id}/preview": {
+            "get": {
+                "tags": ["Archives"],
+                "summary": "Archive first-page thumbnail",
+                "description": "PNG of the first page of a PDF archive, rendered in the background after upload. Same access rules as the archive itself.",
+                "produces": ["image/png"],
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "PNG thumbnail", "schema": {"type": "file"}},
+                    "404": {"description": "Archive not found, or no preview rendered yet", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/retention/purge": {
+            "post": {
+                "tags": ["Retention"],
+                "summary": "Purge refresh tokens and audit logs past their retention period",
+                "description": "Super admins only; routed when ENABLE_RETENTION is set. Periods come from the retention_refresh_tokens_days and retention_audit_logs_days settings (0 keeps the data). Refresh tokens age from revocation or expiry. Rows under an active legal hold are kept and counted as held. The same purge runs every RETENTION_INTERVAL.",
+                "parameters": [
+                    {"name": "dryRun", "in": "query", "type": "boolean", "description": "Only count the rows a purge would delete"}
+                ],
+                "responses": {
+                    "200": {"description": "Per data class retentionDays, cutoff, eligible, held and purged counts", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/retention/legal-holds": {
+            "get": {
+                "tags": ["Retention"],
+                "summary": "List legal holds",
+                "parameters": [
+                    {"name": "includeReleased", "in": "query", "type": "boolean", "default": false}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            },
+            "post": {
+                "tags": ["Retention"],
+                "summary": "Place a legal hold on refresh tokens or audit logs",
+                "description": "Without userId the hold covers the whole data class; otherwise only the user's rows are kept.",
+                "parameters": [
+                    {"name": "payload", "in": "body", "required": true, "schema": {"type": "object", "required": ["dataClass", "reason"], "properties": {"dataClass": {"type": "string", "enum": ["REFRESH_TOKENS", "AUDIT_LOGS"]}, "userId": {"type": "string"}, "reason": {"type": "string", "maxLength": 500}}}}
+                ],
+                "responses": {
+                    "201": {"description": "Created", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "400": {"description": "Invalid payload", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "User not found", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
+        },
+        "/retention/legal-holds/{id}/release": {
+            "post": {
+                "tags": ["Retention"],
+                "summary": "Release a legal hold",
+                "description": "The held rows are purged by the next run once past their retention period.",
+                "parameters": [
+                    {"name": "id", "in": "path", "required": true, "type": "string"}
+                ],
+                "responses": {
+                    "200": {"description": "OK", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "404": {"description": "Legal hold not found", "schema": {"$ref": "#/definitions/ResponseEnvelope"}},
+                    "409": {"description": "Legal hold already released", "schema": {"$ref": "#/definitions/ResponseEnvelope"}}
+                }
+            }
         }
     },
     "definitions": {
+        "ExportTemplateRequest": {
+            "type": "object",
+            "required": ["type", "name", "columns"],
+            "properties": {
+                "type": {"type": "string", "enum": ["attendance", "grades", "behavior", "summary"]},
+                "name": {"type": "string", "maxLength": 100},
+                "columns": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "object",
+                        "required": ["key"],
+                        "properties": {
+                            "key": {"type": "string"},
+                            "label": {"type": "string", "maxLength": 100}
+                        }
+                    }
+                },
+                "groupBy": {"type": "string"}
+            }
+        },
         "Teacher": {
             "type": "object",
             "properties": {
@@ -424,6 +2982,18 @@ const docTemplate = `{
             },
             "required": ["email", "full_name"]
         },
+        "PatchTeacherRequest": {
+            "type": "object",
+            "description": "Only fields present are changed; an empty nip, phone or expertise clears it",
+            "properties": {
+                "email": {"type": "string"},
+                "full_name": {"type": "string"},
+                "nip": {"type": "string"},
+                "phone": {"type": "string"},
+                "expertise": {"type": "string"},
+                "active": {"type": "boolean"}
+            }
+        },
         "CreateTeacherAssignmentRequest": {
             "type": "object",
             "properties": {
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+)
+
+func newAdminCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{Use: "admin", Short: "Manage administrator accounts"}
+
+	var name, password string
+	create := &cobra.Command{
+		Use:   "create-superadmin <email>",
+		Short: "Create a super admin directly in the database",
+		Long: "Create a super admin directly in the database, for a fresh install or when every admin is locked out.\n" +
+			"Without --initial-password a random one is generated and printed once.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := e.database()
+			if err != nil {
+				return err
+			}
+			generated := password == ""
+			if generated {
+				if password, err = randomSecret(18); err != nil {
+					return err
+				}
+			}
+			users := service.NewUserService(repository.NewUserRepository(db), nil, nil)
+			user, err := users.Create(cmd.Context(), service.CreateUserRequest{
+				Email:    args[0],
+				FullName: name,
+				Role:     models.RoleSuperAdmin,
+				Active:   true,
+				Password: password,
+			}, "", models.LoginRequest{IP: "cli", UserAgent: "adpctl"})
+			if err != nil {
+				return err
+			}
+			e.printf("created super admin %s (%s)\n", user.Email, user.ID)
+			if generated {
+				e.printf("password: %s\n", password)
+			}
+			return nil
+		},
+	}
+	create.Flags().StringVar(&name, "name", "", "full name of the new account")
+	create.Flags().StringVar(&password, "initial-password", "", "password of the new account; generated when empty")
+	_ = create.MarkFlagRequired("name")
+
+	cmd.AddCommand(create)
+	return cmd
+}
+
+// randomSecret returns n random bytes encoded as unpadded base64url.
+func randomSecret(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
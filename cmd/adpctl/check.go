@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// consistencyCheck inspects the database and returns one finding per problem.
+type consistencyCheck struct {
+	name string
+	run  func(ctx context.Context, db *sqlx.DB, cfg *config.Config, now time.Time) ([]string, error)
+}
+
+var consistencyChecks = []consistencyCheck{
+	{name: "active term", run: checkActiveTerm},
+	{name: "super admin", run: checkSuperAdmin},
+	{name: "enrollments", run: checkDuplicateEnrollments},
+	{name: "report jobs", run: checkReportJobs},
+	{name: "outbox", run: checkOutbox},
+	{name: "backups", run: checkBackups},
+	{name: "attendance partitions", run: checkAttendancePartitions},
+}
+
+func newCheckCommand(e *env) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Run consistency checks against the database",
+		Long:  "Run read-only consistency checks against the database. Exits with status 1 when anything is found.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := e.config()
+			if err != nil {
+				return err
+			}
+			db, err := e.database()
+			if err != nil {
+				return err
+			}
+			return runChecks(cmd.Context(), e, db, cfg, time.Now().UTC())
+		},
+	}
+}
+
+func runChecks(ctx context.Context, e *env, db *sqlx.DB, cfg *config.Config, now time.Time) error {
+	problems := 0
+	for _, check := range consistencyChecks {
+		findings, err := check.run(ctx, db, cfg, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", check.name, err)
+		}
+		if len(findings) == 0 {
+			e.printf("ok    %s\n", check.name)
+			continue
+		}
+		problems += len(findings)
+		for _, finding := range findings {
+			e.printf("FAIL  %s: %s\n", check.name, finding)
+		}
+	}
+	if problems > 0 {
+		e.printf("%d problem(s) found\n", problems)
+		return errFindings
+	}
+	return nil
+}
+
+func checkActiveTerm(ctx context.Context, db *sqlx.DB, _ *config.Config, _ time.Time) ([]string, error) {
+	var findings []string
+	var active []string
+	if err := db.SelectContext(ctx, &active, `SELECT id FROM terms WHERE is_active = TRUE ORDER BY id`); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(active) == 0:
+		findings = append(findings, "no term is active")
+	case len(active) > 1:
+		findings = append(findings, fmt.Sprintf("%d terms are active: %v", len(active), active))
+	}
+
+	var configured string
+	err := db.GetContext(ctx, &configured, `SELECT value FROM configurations WHERE key = 'active_term_id'`)
+	if errors.Is(err, sql.ErrNoRows) || configured == "" {
+		return findings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var isActive bool
+	err = db.GetContext(ctx, &isActive, `SELECT is_active FROM terms WHERE id = $1`, configured)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		findings = append(findings, fmt.Sprintf("active_term_id points at missing term %s", configured))
+	case err != nil:
+		return nil, err
+	case !isActive:
+		findings = append(findings, fmt.Sprintf("active_term_id points at inactive term %s", configured))
+	}
+	return findings, nil
+}
+
+func checkSuperAdmin(ctx context.Context, db *sqlx.DB, _ *config.Config, _ time.Time) ([]string, error) {
+	var count int
+	if err := db.GetContext(ctx, &count, `SELECT COUNT(*) FROM users WHERE role = 'SUPERADMIN' AND active = TRUE`); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return []string{"no active super admin; create one with `adpctl admin create-superadmin`"}, nil
+	}
+	return nil, nil
+}
+
+func checkDuplicateEnrollments(ctx context.Context, db *sqlx.DB, _ *config.Config, _ time.Time) ([]string, error) {
+	var rows []struct {
+		StudentID string `db:"student_id"`
+		TermID    string `db:"term_id"`
+		Count     int    `db:"count"`
+	}
+	const query = `SELECT student_id, term_id, COUNT(*) AS count FROM enrollments WHERE status = 'ACTIVE'
+GROUP BY student_id, term_id HAVING COUNT(*) > 1 ORDER BY student_id LIMIT 20`
+	if err := db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	findings := make([]string, 0, len(rows))
+	for _, row := range rows {
+		findings = append(findings, fmt.Sprintf("student %s has %d active enrollments in term %s", row.StudentID, row.Count, row.TermID))
+	}
+	return findings, nil
+}
+
+// checkReportJobs flags jobs that are still queued or processing well after recovery should have
+// picked them up.
+func checkReportJobs(ctx context.Context, db *sqlx.DB, cfg *config.Config, now time.Time) ([]string, error) {
+	stuckBefore := now.Add(-time.Hour)
+	if grace := 10 * cfg.Reports.ClaimTimeout; grace > time.Hour {
+		stuckBefore = now.Add(-grace)
+	}
+	var count int
+	if err := db.GetContext(ctx, &count, `SELECT COUNT(*) FROM report_jobs WHERE status IN ('QUEUED', 'PROCESSING') AND created_at < $1`, stuckBefore); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return []string{fmt.Sprintf("%d job(s) queued or processing since before %s", count, stuckBefore.Format(time.RFC3339))}, nil
+	}
+	return nil, nil
+}
+
+func checkOutbox(ctx context.Context, db *sqlx.DB, cfg *config.Config, now time.Time) ([]string, error) {
+	if !cfg.Events.Enabled {
+		return nil, nil
+	}
+	var count int
+	if err := db.GetContext(ctx, &count, `SELECT COUNT(*) FROM outbox_events WHERE published_at IS NULL AND occurred_at < $1`, now.Add(-15*time.Minute)); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return []string{fmt.Sprintf("%d event(s) unpublished for more than 15 minutes", count)}, nil
+	}
+	return nil, nil
+}
+
+func checkBackups(ctx context.Context, db *sqlx.DB, cfg *config.Config, now time.Time) ([]string, error) {
+	if !cfg.Backups.Enabled {
+		return nil, nil
+	}
+	var last sql.NullTime
+	if err := db.GetContext(ctx, &last, `SELECT MAX(finished_at) FROM backups WHERE status = 'FINISHED'`); err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return []string{"no backup has finished yet"}, nil
+	}
+	if last.Time.Before(now.Add(-48 * time.Hour)) {
+		return []string{fmt.Sprintf("last finished backup is from %s", last.Time.Format(time.RFC3339))}, nil
+	}
+	return nil, nil
+}
+
+// checkAttendancePartitions verifies next month's partitions exist, so writes do not pile up in the
+// default partitions.
+func checkAttendancePartitions(ctx context.Context, db *sqlx.DB, cfg *config.Config, now time.Time) ([]string, error) {
+	if !cfg.AttendancePartitions.Enabled {
+		return nil, nil
+	}
+	next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	var findings []string
+	for _, parent := range []string{"daily_attendance", "subject_attendance"} {
+		name := parent + "_" + next.Format("2006_01")
+		var exists bool
+		if err := db.GetContext(ctx, &exists, `SELECT to_regclass($1) IS NOT NULL`, name); err != nil {
+			return nil, err
+		}
+		if !exists {
+			findings = append(findings, fmt.Sprintf("partition %s is missing", name))
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func TestRunChecksReportsFindings(t *testing.T) {
+	raw, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer raw.Close()
+	db := sqlx.NewDb(raw, "sqlmock")
+	now := time.Date(2026, 10, 16, 7, 0, 0, 0, time.UTC)
+	cfg := &config.Config{}
+	cfg.Reports.ClaimTimeout = 2 * time.Minute
+	cfg.AttendancePartitions.Enabled = true
+
+	mock.ExpectQuery(`SELECT id FROM terms WHERE is_active = TRUE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("term-1"))
+	mock.ExpectQuery(`SELECT value FROM configurations WHERE key = 'active_term_id'`).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("term-0"))
+	mock.ExpectQuery(`SELECT is_active FROM terms WHERE id = \$1`).WithArgs("term-0").
+		WillReturnRows(sqlmock.NewRows([]string{"is_active"}).AddRow(false))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM users WHERE role = 'SUPERADMIN'`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT student_id, term_id, COUNT\(\*\) AS count FROM enrollments`).
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "term_id", "count"}))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM report_jobs`).WithArgs(now.Add(-time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT to_regclass\(\$1\) IS NOT NULL`).WithArgs("daily_attendance_2026_11").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT to_regclass\(\$1\) IS NOT NULL`).WithArgs("subject_attendance_2026_11").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	var out bytes.Buffer
+	err = runChecks(context.Background(), &env{out: &out}, db, cfg, now)
+	require.ErrorIs(t, err, errFindings)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, "FAIL  active term: active_term_id points at inactive term term-0\n"+
+		"ok    super admin\n"+
+		"ok    enrollments\n"+
+		"ok    report jobs\n"+
+		"ok    outbox\n"+
+		"ok    backups\n"+
+		"FAIL  attendance partitions: partition subject_attendance_2026_11 is missing\n"+
+		"2 problem(s) found\n", out.String())
+}
+
+func TestSecretsRotateMovesCurrentToSecondary(t *testing.T) {
+	var out bytes.Buffer
+	e := &env{out: &out, cfg: &config.Config{}}
+	e.cfg.Reports.SignedURLSecret = "old-reports-secret"
+	cmd := newRootCommand(e)
+	cmd.SetArgs([]string{"secrets", "rotate", "reports"})
+	require.NoError(t, cmd.Execute())
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^REPORTS_SIGNED_URL_SECRET=[A-Za-z0-9_-]{64}$`, string(lines[0]))
+	assert.Equal(t, "REPORTS_SIGNED_URL_SECRET_SECONDARY=old-reports-secret", string(lines[1]))
+
+	cmd.SetArgs([]string{"secrets", "rotate", "smtp"})
+	assert.ErrorContains(t, cmd.Execute(), `unknown secret "smtp"`)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	"github.com/noah-isme/sma-adp-api/pkg/client"
+)
+
+// flagStore reads and writes the runtime configuration entries, through the API by default or
+// straight in the database with --direct.
+type flagStore interface {
+	List(ctx context.Context) ([]dto.ConfigurationItem, error)
+	Set(ctx context.Context, key, value string) (*dto.ConfigurationItem, error)
+}
+
+type apiFlags struct {
+	client *client.Client
+	prefix string
+}
+
+func (f apiFlags) List(ctx context.Context) ([]dto.ConfigurationItem, error) {
+	var items []dto.ConfigurationItem
+	resp, err := f.client.Do(ctx, http.MethodGet, f.prefix+"/configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Decode(&items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (f apiFlags) Set(ctx context.Context, key, value string) (*dto.ConfigurationItem, error) {
+	var item dto.ConfigurationItem
+	resp, err := f.client.Do(ctx, http.MethodPut, f.prefix+"/configuration/"+url.PathEscape(key), dto.UpdateConfigurationRequest{Key: key, Value: value})
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Decode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// directFlags writes without an acting user, so the audit log records the change as unattributed.
+type directFlags struct {
+	service *service.ConfigurationService
+}
+
+func (f directFlags) List(ctx context.Context) ([]dto.ConfigurationItem, error) {
+	return f.service.List(ctx)
+}
+
+func (f directFlags) Set(ctx context.Context, key, value string) (*dto.ConfigurationItem, error) {
+	return f.service.Update(ctx, key, value, nil)
+}
+
+func newFlagsCommand(e *env) *cobra.Command {
+	var direct bool
+	cmd := &cobra.Command{
+		Use:   "flags",
+		Short: "Show and toggle runtime configuration such as enable_reports_ui",
+		Long: "Show and change the runtime configuration served by /configuration. Changes go through the API\n" +
+			"as the --email account unless --direct writes them to the database, for when the API is down.",
+	}
+	cmd.PersistentFlags().BoolVar(&direct, "direct", false, "use the database instead of the API")
+
+	store := func(ctx context.Context) (flagStore, error) {
+		if !direct {
+			c, err := e.client(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return apiFlags{client: c, prefix: e.apiPrefix}, nil
+		}
+		cfg, err := e.config()
+		if err != nil {
+			return nil, err
+		}
+		db, err := e.database()
+		if err != nil {
+			return nil, err
+		}
+		defaults := map[string]string{service.AttendanceLateAfterKey: cfg.Attendance.LateAfter}
+		if cfg.Configuration.ActiveTermID != "" {
+			defaults["active_term_id"] = cfg.Configuration.ActiveTermID
+		}
+		if cfg.Configuration.DefaultDashboardTermID != "" {
+			defaults["default_dashboard_term_id"] = cfg.Configuration.DefaultDashboardTermID
+		}
+		if cfg.Configuration.DefaultCalendarTermID != "" {
+			defaults["default_calendar_term_id"] = cfg.Configuration.DefaultCalendarTermID
+		}
+		svc := service.NewConfigurationService(
+			repository.NewConfigurationRepository(db),
+			repository.NewTermRepository(db),
+			repository.NewUserRepository(db),
+			nil,
+			nil,
+			service.ConfigurationServiceConfig{Defaults: defaults},
+		)
+		return directFlags{service: svc}, nil
+	}
+
+	set := func(cmd *cobra.Command, key, value string) error {
+		s, err := store(cmd.Context())
+		if err != nil {
+			return err
+		}
+		item, err := s.Set(cmd.Context(), key, value)
+		if err != nil {
+			return err
+		}
+		e.printf("%s = %s\n", item.Key, item.Value)
+		return nil
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List configuration entries",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				s, err := store(cmd.Context())
+				if err != nil {
+					return err
+				}
+				items, err := s.List(cmd.Context())
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "KEY\tTYPE\tVALUE")
+				for _, item := range items {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", item.Key, item.Type, item.Value)
+				}
+				return w.Flush()
+			},
+		},
+		&cobra.Command{
+			Use:   "set <key> <value>",
+			Short: "Change a configuration entry",
+			Args:  cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return set(cmd, args[0], args[1])
+			},
+		},
+		&cobra.Command{
+			Use:   "enable <key>",
+			Short: "Set a boolean entry to true",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return set(cmd, args[0], "true")
+			},
+		},
+		&cobra.Command{
+			Use:   "disable <key>",
+			Short: "Set a boolean entry to false",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return set(cmd, args[0], "false")
+			},
+		},
+	)
+	return cmd
+}
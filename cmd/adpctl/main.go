@@ -0,0 +1,151 @@
+// Command adpctl runs the operational chores that used to need direct SQL.
+//
+// Commands that change runtime configuration go through the API and need an admin account
+// (--api-url, --email and --password, or ADPCTL_API_URL, ADPCTL_EMAIL and ADPCTL_PASSWORD). The
+// break-glass commands connect to the database configured in the same environment as the API.
+//
+//	go run ./cmd/adpctl admin create-superadmin ops@sma.sch.id --name "Operator"
+//	go run ./cmd/adpctl secrets rotate jwt
+//	go run ./cmd/adpctl flags list
+//	go run ./cmd/adpctl flags set enable_reports_ui false
+//	go run ./cmd/adpctl reports requeue --since 24h --dry-run
+//	go run ./cmd/adpctl terms close <term-id>
+//	go run ./cmd/adpctl check
+//
+// Commands that write take effect immediately; reports requeue and terms close accept --dry-run to
+// print what they would do first.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/pkg/client"
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+)
+
+// errFindings makes the process exit with status 1 without printing another error; the command has
+// already reported what it found.
+var errFindings = errors.New("findings reported")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := newRootCommand(&env{out: os.Stdout}).ExecuteContext(ctx)
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, errFindings) {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+	os.Exit(1)
+}
+
+// env holds the global flags and the resources commands open lazily, so offline commands work
+// without a database or a running API.
+type env struct {
+	out       io.Writer
+	apiURL    string
+	apiPrefix string
+	email     string
+	password  string
+
+	cfg *config.Config
+	db  *sqlx.DB
+}
+
+func newRootCommand(e *env) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "adpctl",
+		Short:         "Operate an SMA ADP API deployment",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPostRun: func(*cobra.Command, []string) {
+			if e.db != nil {
+				e.db.Close() //nolint:errcheck
+			}
+		},
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&e.apiURL, "api-url", envOr("ADPCTL_API_URL", "http://localhost:8080"), "base URL of the API")
+	flags.StringVar(&e.apiPrefix, "api-prefix", envOr("ADPCTL_API_PREFIX", client.DefaultAPIPrefix), "API_PREFIX the API is served under")
+	flags.StringVar(&e.email, "email", os.Getenv("ADPCTL_EMAIL"), "admin account for commands that go through the API")
+	// Read ADPCTL_PASSWORD only when logging in, so --help never prints it as the default.
+	flags.StringVar(&e.password, "password", "", "password for --email (default $ADPCTL_PASSWORD)")
+
+	root.AddCommand(
+		newAdminCommand(e),
+		newSecretsCommand(e),
+		newFlagsCommand(e),
+		newReportsCommand(e),
+		newTermsCommand(e),
+		newCheckCommand(e),
+	)
+	return root
+}
+
+func (e *env) config() (*config.Config, error) {
+	if e.cfg != nil {
+		return e.cfg, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	e.cfg = cfg
+	return cfg, nil
+}
+
+func (e *env) database() (*sqlx.DB, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.NewPostgres(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	e.db = db
+	return db, nil
+}
+
+// client logs in to the API with the admin account.
+func (e *env) client(ctx context.Context) (*client.Client, error) {
+	password := envOr("ADPCTL_PASSWORD", "")
+	if e.password != "" {
+		password = e.password
+	}
+	if e.email == "" || password == "" {
+		return nil, errors.New("--email and --password (or ADPCTL_EMAIL and ADPCTL_PASSWORD) are required")
+	}
+	c, err := client.New(e.apiURL, client.Options{APIPrefix: e.apiPrefix, UserAgent: "adpctl"})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Login(ctx, e.email, password); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (e *env) printf(format string, args ...interface{}) {
+	fmt.Fprintf(e.out, format, args...)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+)
+
+func newReportsCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{Use: "reports", Short: "Manage report jobs"}
+
+	var (
+		since  time.Duration
+		ids    []string
+		dryRun bool
+	)
+	requeue := &cobra.Command{
+		Use:   "requeue",
+		Short: "Put failed report jobs back in the queue",
+		Long: "Reset failed report jobs to QUEUED without a claim. A running API picks them up on its next\n" +
+			"recovery sweep (REPORTS_CLAIM_TIMEOUT), so no restart is needed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			db, err := e.database()
+			if err != nil {
+				return err
+			}
+			repo := repository.NewReportRepository(db)
+			cutoff := time.Now().UTC().Add(-since)
+			if dryRun {
+				jobs, err := repo.ListFailed(cmd.Context(), cutoff, ids)
+				if err != nil {
+					return err
+				}
+				for _, job := range jobs {
+					reason := ""
+					if job.ErrorMessage != nil {
+						reason = *job.ErrorMessage
+					}
+					e.printf("%s\t%s\t%s\t%s\n", job.ID, job.Type, job.CreatedAt.Format(time.RFC3339), reason)
+				}
+				e.printf("%d failed job(s) would be requeued\n", len(jobs))
+				return nil
+			}
+			requeued, err := repo.RequeueFailed(cmd.Context(), cutoff, ids)
+			if err != nil {
+				return err
+			}
+			for _, id := range requeued {
+				e.printf("%s\n", id)
+			}
+			e.printf("%d job(s) requeued\n", len(requeued))
+			return nil
+		},
+	}
+	requeue.Flags().DurationVar(&since, "since", 7*24*time.Hour, "only jobs created within this window")
+	requeue.Flags().StringSliceVar(&ids, "id", nil, "only these job IDs (repeatable)")
+	requeue.Flags().BoolVar(&dryRun, "dry-run", false, "list the jobs without changing them")
+
+	cmd.AddCommand(requeue)
+	return cmd
+}
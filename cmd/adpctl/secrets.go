@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// rotatableSecret is a signing secret with a secondary slot that keeps verifying what the previous
+// secret signed while clients pick up the new one.
+type rotatableSecret struct {
+	env     string
+	current func(*config.Config) string
+}
+
+var rotatableSecrets = map[string]rotatableSecret{
+	"jwt":      {env: "JWT_SECRET", current: func(c *config.Config) string { return c.JWT.Secret }},
+	"reports":  {env: "REPORTS_SIGNED_URL_SECRET", current: func(c *config.Config) string { return c.Reports.SignedURLSecret }},
+	"archives": {env: "ARCHIVES_SIGNED_URL_SECRET", current: func(c *config.Config) string { return c.Archives.SignedURLSecret }},
+}
+
+func newSecretsCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{Use: "secrets", Short: "Rotate signing secrets"}
+
+	names := make([]string, 0, len(rotatableSecrets))
+	for name := range rotatableSecrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rotate := &cobra.Command{
+		Use:   "rotate <" + strings.Join(names, "|") + ">",
+		Short: "Generate a new secret and print the environment to deploy",
+		Long: "Generate a new primary secret and move the current one to the _SECONDARY variable, so tokens and\n" +
+			"signed URLs issued before the deploy stay valid. Nothing is written; put the printed lines in the\n" +
+			"deployment environment, restart every replica, and clear the _SECONDARY variable once the old\n" +
+			"tokens have expired.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: names,
+		RunE: func(_ *cobra.Command, args []string) error {
+			secret, ok := rotatableSecrets[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown secret %q, expected one of %s", args[0], strings.Join(names, ", "))
+			}
+			cfg, err := e.config()
+			if err != nil {
+				return err
+			}
+			next, err := randomSecret(48)
+			if err != nil {
+				return err
+			}
+			e.printf("%s", rotationEnv(secret.env, secret.current(cfg), next))
+			return nil
+		},
+	}
+
+	cmd.AddCommand(rotate)
+	return cmd
+}
+
+// rotationEnv renders the variables for rotating the secret in env from current to next.
+func rotationEnv(env, current, next string) string {
+	return fmt.Sprintf("%s=%s\n%s_SECONDARY=%s\n", env, next, env, current)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+func newTermsCommand(e *env) *cobra.Command {
+	cmd := &cobra.Command{Use: "terms", Short: "Manage academic terms"}
+
+	var force, dryRun bool
+	closeTerm := &cobra.Command{
+		Use:   "close <term-id>",
+		Short: "Finalize every class of a term and deactivate it",
+		Long: "Finalize the grades of every class with active enrollments in the term, then mark the term\n" +
+			"inactive. Subjects without a grade config are skipped and listed. Terms that have not ended yet\n" +
+			"are refused unless --force is given.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg, err := e.config()
+			if err != nil {
+				return err
+			}
+			db, err := e.database()
+			if err != nil {
+				return err
+			}
+			terms := repository.NewTermRepository(db)
+			term, err := terms.FindByID(ctx, args[0])
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("term %s not found", args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if term.EndDate.After(time.Now()) && !force {
+				return fmt.Errorf("term %s ends on %s; pass --force to close it early", term.Name, term.EndDate.Format("2006-01-02"))
+			}
+			enrollments := repository.NewEnrollmentRepository(db)
+			classIDs, err := enrollments.ListClassIDsByTerm(ctx, term.ID)
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				e.printf("would finalize %d class(es) of %s %s and deactivate it\n", len(classIDs), term.Name, term.AcademicYear)
+				for _, classID := range classIDs {
+					e.printf("  %s\n", classID)
+				}
+				return nil
+			}
+
+			var events *service.DomainEvents
+			if cfg.Events.Enabled {
+				// The relay of a running API publishes what is written to the outbox here.
+				events = service.NewDomainEvents(db, repository.NewOutboxRepository(db))
+			}
+			grades := service.NewGradeService(
+				repository.NewGradeRepository(db),
+				repository.NewGradeFinalRepository(db),
+				enrollments,
+				repository.NewGradeConfigRepository(db),
+				repository.NewGradeComponentRepository(db),
+				nil,
+				nil,
+				service.WithClassSubjects(repository.NewClassSubjectRepository(db)),
+				service.WithGradeEvents(events),
+			)
+			for _, classID := range classIDs {
+				result, err := grades.FinalizeClass(ctx, service.FinalizeClassRequest{ClassID: classID, TermID: term.ID})
+				if err != nil && appErrors.FromError(err).Code == appErrors.ErrPreconditionFailed.Code {
+					// A class without subjects has nothing to finalize.
+					e.printf("%s: skipped, %s\n", classID, appErrors.FromError(err).Message)
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("finalize class %s: %w", classID, err)
+				}
+				e.printf("%s: %d subject(s) finalized, %d skipped\n", classID, result.Finalized, result.Skipped)
+				for _, subject := range result.Subjects {
+					if subject.Status == service.FinalizeSubjectSkipped {
+						e.printf("  %s: %s\n", subject.SubjectName, subject.Reason)
+					}
+				}
+			}
+
+			if term.IsActive {
+				term.IsActive = false
+				if err := terms.Update(ctx, term); err != nil {
+					return err
+				}
+			}
+			e.printf("term %s %s closed\n", term.Name, term.AcademicYear)
+			active, err := repository.NewConfigurationRepository(db).Get(ctx, "active_term_id")
+			if err == nil && active.Value == term.ID {
+				e.printf("warning: active_term_id still points at this term; set it with `adpctl flags set active_term_id <id>`\n")
+			}
+			return nil
+		},
+	}
+	closeTerm.Flags().BoolVar(&force, "force", false, "close a term before its end date")
+	closeTerm.Flags().BoolVar(&dryRun, "dry-run", false, "list the classes without changing anything")
+
+	cmd.AddCommand(closeTerm)
+	return cmd
+}
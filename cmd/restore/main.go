@@ -0,0 +1,126 @@
+// Command restore restores a backup written by POST /internal/backups.
+//
+// It reads the same environment as the API: the database to restore into, BACKUPS_ENCRYPTION_KEY
+// and the storage directories to check against the archive's manifest.
+//
+//	go run ./cmd/restore -file backups/backup-20260302-010000-<id>.sbk          # inspect only
+//	go run ./cmd/restore -file backups/backup-20260302-010000-<id>.sbk -confirm # restore
+//
+// Without -confirm the archive is decrypted and checked, its manifest summarised and the storage
+// directories compared with it, but the database is left untouched. With -confirm the dump replaces
+// the objects it contains in one transaction. Storage files are not part of the archive; restore
+// them from their own backups first so the comparison comes out clean.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/noah-isme/sma-adp-api/pkg/backup"
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the backup archive")
+	confirm := flag.Bool("confirm", false, "restore the dump into the configured database")
+	dumpOut := flag.String("dump", "", "also write the decrypted pg_dump file to this path")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	key, err := backup.ParseKey(cfg.Backups.EncryptionKey)
+	if err != nil {
+		log.Fatalf("invalid BACKUPS_ENCRYPTION_KEY: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, cfg, key, *file, *dumpOut, *confirm); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cfg *config.Config, key []byte, file, dumpOut string, confirm bool) error {
+	archive, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer archive.Close() //nolint:errcheck
+	plain, err := backup.NewDecrypter(archive, key)
+	if err != nil {
+		return err
+	}
+
+	dumpPath := dumpOut
+	if dumpPath == "" {
+		tmp, err := os.CreateTemp("", "restore-*.dump")
+		if err != nil {
+			return fmt.Errorf("create dump file: %w", err)
+		}
+		tmp.Close() //nolint:errcheck
+		dumpPath = tmp.Name()
+		defer os.Remove(dumpPath) //nolint:errcheck
+	}
+	dump, err := os.OpenFile(dumpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create dump file: %w", err)
+	}
+	// Reading the whole archive authenticates every chunk before anything is restored.
+	manifest, err := backup.ReadArchive(plain, dump)
+	if closeErr := dump.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(io.Discard, plain); err != nil {
+		return err
+	}
+
+	fmt.Printf("backup of %q taken %s, schemas %v, %d storage files listed\n",
+		manifest.Database, manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"), manifest.Schemas, len(manifest.Storage))
+	mismatches, err := backup.VerifyStorage(manifest.Storage, storageDirs(cfg))
+	if err != nil {
+		return err
+	}
+	for _, mismatch := range mismatches {
+		fmt.Printf("storage %s/%s: %s\n", mismatch.File.Dir, mismatch.File.Path, mismatch.Reason)
+	}
+	if len(mismatches) == 0 {
+		fmt.Println("storage directories match the manifest")
+	}
+
+	if !confirm {
+		fmt.Printf("dry run: re-run with -confirm to restore into %q on %s:%d\n", cfg.Database.Name, cfg.Database.Host, cfg.Database.Port)
+		return nil
+	}
+	pg := backup.Postgres{Database: cfg.Database, RestoreCommand: cfg.Backups.RestoreCommand}
+	if err := pg.Restore(ctx, dumpPath); err != nil {
+		return err
+	}
+	fmt.Printf("restored into %q\n", cfg.Database.Name)
+	return nil
+}
+
+// storageDirs mirrors the directories the API lists in backup manifests.
+func storageDirs(cfg *config.Config) map[string]string {
+	dirs := map[string]string{}
+	if cfg.Reports.Enabled {
+		dirs["reports"] = cfg.Reports.StorageDir
+	}
+	if cfg.Archives.Enabled {
+		dirs["archives"] = cfg.Archives.StorageDir
+	}
+	return dirs
+}
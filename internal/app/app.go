@@ -0,0 +1,251 @@
+// Package app assembles the HTTP application from configuration: it builds repositories, services
+// and handlers, registers every route and starts the background workers, so cmd/api-gateway only
+// loads configuration and runs the result. Tests can boot the whole application in memory by
+// passing a mocked database.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
+
+	// Register the generated API documentation served under /docs.
+	_ "github.com/noah-isme/sma-adp-api/api/swagger"
+	internalhandler "github.com/noah-isme/sma-adp-api/internal/handler"
+	internalmiddleware "github.com/noah-isme/sma-adp-api/internal/middleware"
+	"github.com/noah-isme/sma-adp-api/internal/routes"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	"github.com/noah-isme/sma-adp-api/pkg/buildinfo"
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+	"github.com/noah-isme/sma-adp-api/pkg/logger"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
+	compressmiddleware "github.com/noah-isme/sma-adp-api/pkg/middleware/compress"
+	corsmiddleware "github.com/noah-isme/sma-adp-api/pkg/middleware/cors"
+	reqidmiddleware "github.com/noah-isme/sma-adp-api/pkg/middleware/requestid"
+	headersmiddleware "github.com/noah-isme/sma-adp-api/pkg/middleware/secureheaders"
+)
+
+// Closer releases a resource acquired while building the application.
+type Closer func() error
+
+// App is the assembled application.
+type App struct {
+	// Router serves the public API.
+	Router *gin.Engine
+	// OpsRouter serves /metrics and the profiling endpoints on their own listener. It is nil when
+	// METRICS_PORT is unset and those endpoints live on Router.
+	OpsRouter *gin.Engine
+
+	cfg     *config.Config
+	db      *sqlx.DB
+	logger  *zap.Logger
+	metrics *service.MetricsService
+	ctx     context.Context
+	cancel  context.CancelFunc
+	closers []Closer
+	levels  *logger.Levels
+	storage *service.StorageHealthService
+	queues  *service.QueueMonitorService
+}
+
+// Option customises the application built by New.
+type Option func(*App)
+
+// WithLogLevels exposes levels under /internal/log-levels, next to /metrics and guarded the same
+// way, so operators can change log levels without a restart.
+func WithLogLevels(levels *logger.Levels) Option {
+	return func(a *App) {
+		a.levels = levels
+	}
+}
+
+// New builds the dependency graph for cfg on top of db. Background workers run until Close is
+// called; on error everything started so far has already been released.
+func New(cfg *config.Config, db *sqlx.DB, logr *zap.Logger, opts ...Option) (*App, error) {
+	if logr == nil {
+		logr = zap.NewNop()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &App{cfg: cfg, db: db, logger: logr, metrics: service.NewMetricsService(), ctx: ctx, cancel: cancel}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if err := a.build(); err != nil {
+		_ = a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// Close cancels background work, then releases resources in reverse order of acquisition.
+func (a *App) Close() error {
+	a.cancel()
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
+
+func (a *App) onClose(closer Closer) {
+	a.closers = append(a.closers, closer)
+}
+
+func (a *App) build() error {
+	cfg := a.cfg
+	proxyOpts := clientip.Options{TrustedProxies: cfg.Proxy.TrustedProxies, Platform: cfg.Proxy.Platform}
+
+	var alerter internalmiddleware.PanicAlerter
+	if cfg.Alerts.PanicWebhookURL != "" {
+		alerter = service.NewPanicWebhook(cfg.Alerts.PanicWebhookURL, cfg.Alerts.PanicTimeout, a.logger)
+	}
+	recovery := internalmiddleware.Recovery(a.logger, a.metrics, alerter)
+
+	r := gin.New()
+	if err := clientip.Configure(r, proxyOpts); err != nil {
+		return fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+	r.Use(reqidmiddleware.Middleware())
+	r.Use(internalmiddleware.Version(buildinfo.Get().String()))
+	r.Use(logger.GinMiddleware(a.logger))
+	r.Use(recovery)
+	corsHandler, err := corsmiddleware.NewWithOptions(corsmiddleware.Options{
+		AllowedOrigins:   cfg.CORS.AllowedOrigins,
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		MaxAge:           cfg.CORS.MaxAge,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		RequireOrigins:   cfg.Env == config.EnvProduction,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid CORS configuration: %w", err)
+	}
+	r.Use(corsHandler)
+	if headers := cfg.Security.Headers; headers.Enabled {
+		r.Use(headersmiddleware.Middleware(headersmiddleware.Options{
+			HSTSMaxAge:            headers.HSTSMaxAge,
+			HSTSIncludeSubdomains: headers.HSTSIncludeSubdomains,
+			ContentTypeNosniff:    headers.ContentTypeNosniff,
+			FrameOptions:          headers.FrameOptions,
+			ReferrerPolicy:        headers.ReferrerPolicy,
+			DocsPolicy:            headers.DocsCSP,
+			DocsPrefix:            "/docs",
+		}))
+	}
+	cutoverSvc := service.NewCutoverService(cfg.Cutover, a.metrics)
+
+	r.Use(internalmiddleware.CutoverStage(cutoverSvc))
+	r.Use(internalmiddleware.Metrics(a.metrics))
+	if compression := cfg.Compression; compression.Enabled {
+		r.Use(compressmiddleware.Middleware(compressmiddleware.Options{
+			MinSize:      compression.MinSize,
+			ContentTypes: compression.ContentTypes,
+			Level:        compression.Level,
+			Observer:     a.metrics.RecordCompression,
+		}))
+	}
+	r.Use(internalmiddleware.Timeout(cfg.Server.RequestTimeout, cfg.Server.RouteTimeouts))
+
+	a.storage = service.NewStorageHealthService(a.storageDirs(), service.StorageHealthConfig{
+		MinFreeBytes:   cfg.Storage.MinFreeBytes,
+		MinFreePercent: cfg.Storage.MinFreePercent,
+		Interval:       cfg.Storage.CheckInterval,
+	}, a.metrics, a.logger.Named("storage"))
+
+	a.queues = service.NewQueueMonitorService(service.QueueMonitorConfig{
+		Autoscale:     cfg.Queues.Autoscale,
+		Interval:      cfg.Queues.AutoscaleInterval,
+		JobsPerWorker: cfg.Queues.JobsPerWorker,
+	}, a.logger.Named("queues"))
+
+	metricsHandler := internalhandler.NewMetricsHandler(a.metrics)
+	r.GET("/health", metricsHandler.Health)
+	r.GET("/ready", internalhandler.NewReadinessHandler(a.storage).Ready)
+
+	if cfg.Env != config.EnvProduction {
+		r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	scrapeGuard, err := internalmiddleware.ScrapeGuard(internalmiddleware.ScrapeGuardConfig{
+		Username:   cfg.Metrics.BasicAuthUser,
+		Password:   cfg.Metrics.BasicAuthPassword,
+		AllowedIPs: cfg.Metrics.AllowedIPs,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid metrics configuration: %w", err)
+	}
+	// Operational endpoints live on the main router unless METRICS_PORT gives them their own listener.
+	var ops *gin.RouterGroup
+	if cfg.Metrics.Port != 0 {
+		a.OpsRouter = gin.New()
+		if err := clientip.Configure(a.OpsRouter, proxyOpts); err != nil {
+			return fmt.Errorf("invalid proxy configuration: %w", err)
+		}
+		a.OpsRouter.Use(recovery)
+		ops = a.OpsRouter.Group("", scrapeGuard)
+	} else {
+		ops = r.Group("", scrapeGuard)
+	}
+	ops.GET("/metrics", metricsHandler.Prometheus)
+	if a.levels != nil {
+		routes.RegisterLogLevels(ops, internalhandler.NewLogLevelHandler(a.levels))
+	}
+	routes.RegisterQueues(ops, internalhandler.NewQueueHandler(a.queues))
+	ops.GET("/internal/error-catalog", internalhandler.NewErrorCatalogHandler().List)
+
+	cutoverHandler := internalhandler.NewCutoverHandler(cutoverSvc)
+	internalGroup := r.Group("/internal")
+	internalGroup.GET("/ping-legacy", cutoverHandler.PingLegacy)
+	internalGroup.GET("/ping-go", cutoverHandler.PingGo)
+
+	h, err := a.buildHandlers()
+	if err != nil {
+		return err
+	}
+	// The storage directories exist once their features are built.
+	a.storage.Start(a.ctx)
+	// Every queue is registered once the handlers are built.
+	a.queues.Start(a.ctx)
+	// The permission matrix is read off a probe copy of the route table, so it matches the guards.
+	policies := routes.Policies(func(probe *gin.Engine, authenticate gin.HandlerFunc) {
+		a.registerRoutes(probe, probe.Group(""), h, authenticate)
+	})
+	h.permission = internalhandler.NewPermissionHandler(service.NewPermissionService(policies, cfg.APIPrefix))
+	features := a.registerRoutes(r, ops, h, internalmiddleware.JWT(h.auth))
+	// The inventory maps the whole API surface, so it is guarded like /metrics.
+	ops.GET("/internal/routes", routes.NewCatalog(r.Routes, features).List)
+	enabled := make(map[string]bool, len(features))
+	for _, feature := range features {
+		enabled[feature.Name] = feature.Enabled
+	}
+	// The enabled features reveal the deployment's surface, so build info is guarded like /metrics.
+	ops.GET("/version", internalhandler.NewVersionHandler(enabled).Get)
+	a.Router = r
+	return nil
+}
+
+// storageDirs lists the directories of the enabled features that write files.
+func (a *App) storageDirs() []service.StorageDir {
+	var dirs []service.StorageDir
+	if a.cfg.Reports.Enabled {
+		dirs = append(dirs, service.StorageDir{Name: service.StorageReports, Path: a.cfg.Reports.StorageDir})
+	}
+	if a.cfg.Archives.Enabled {
+		dirs = append(dirs, service.StorageDir{Name: service.StorageArchives, Path: a.cfg.Archives.StorageDir})
+	}
+	if a.cfg.Backups.Enabled {
+		dirs = append(dirs, service.StorageDir{Name: service.StorageBackups, Path: a.cfg.Backups.StorageDir})
+	}
+	return dirs
+}
@@ -0,0 +1,238 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/routes"
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+	"github.com/noah-isme/sma-adp-api/pkg/logger"
+)
+
+func newTestApp(t *testing.T, cfg *config.Config, opts ...Option) (*App, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	application, err := New(cfg, sqlx.NewDb(db, "sqlmock"), nil, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, application.Close()) })
+	return application, mock
+}
+
+func serve(handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestNewBootsInMemory(t *testing.T) {
+	application, mock := newTestApp(t, &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+	})
+
+	assert.Equal(t, http.StatusOK, serve(application.Router, http.MethodGet, "/health").Code)
+	assert.Equal(t, http.StatusOK, serve(application.Router, http.MethodGet, "/metrics").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(application.Router, http.MethodGet, "/api/v1/lesson-plans").Code)
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/api/v1/dashboard").Code, "disabled features are not routed")
+	assert.Nil(t, application.OpsRouter)
+	require.NoError(t, mock.ExpectationsWereMet(), "booting must not touch the database")
+
+	rec := serve(application.Router, http.MethodGet, "/internal/routes")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data routes.Inventory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Contains(t, body.Data.Features, routes.FeatureStatus{Name: "lesson-plans", Enabled: true})
+	assert.Contains(t, body.Data.Features, routes.FeatureStatus{Name: "dashboard", Enabled: false})
+	assert.Contains(t, body.Data.Routes, routes.Route{Method: http.MethodPost, Path: "/api/v1/lesson-plans/:id/review"})
+
+	rec = serve(application.Router, http.MethodGet, "/version")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("X-App-Version"))
+	var version struct {
+		Data struct {
+			Version  string          `json:"version"`
+			Features map[string]bool `json:"features"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &version))
+	assert.Equal(t, "dev", version.Data.Version)
+	assert.True(t, version.Data.Features["lesson-plans"])
+	assert.False(t, version.Data.Features["dashboard"])
+}
+
+func TestNewServesPermissionsFromRouteGuards(t *testing.T) {
+	application, mock := newTestApp(t, &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+	})
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &models.JWTClaims{
+		UserID: "teacher-1",
+		Role:   models.RoleTeacher,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me/permissions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	application.Router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, mock.ExpectationsWereMet(), "probing the guards must not run handlers")
+
+	var body struct {
+		Data dto.PermissionsResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, models.RoleTeacher, body.Data.Role)
+	assert.Contains(t, body.Data.Permissions, dto.Permission{Method: http.MethodGet, Path: "/teachers/:id", Scope: models.PermissionScopeSelf})
+	assert.Contains(t, body.Data.Permissions, dto.Permission{Method: http.MethodGet, Path: "/auth/me/permissions", Scope: models.PermissionScopeAll})
+	assert.NotContains(t, body.Data.Permissions, dto.Permission{Method: http.MethodGet, Path: "/teachers", Scope: models.PermissionScopeAll})
+	for _, permission := range body.Data.Permissions {
+		assert.NotEqual(t, "/auth/login", permission.Path, "routes without a token are not listed")
+	}
+}
+
+func TestNewSeparatesOpsRouter(t *testing.T) {
+	application, _ := newTestApp(t, &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+		Metrics:   config.MetricsConfig{Port: 9100},
+	})
+
+	require.NotNil(t, application.OpsRouter)
+	assert.Equal(t, http.StatusOK, serve(application.OpsRouter, http.MethodGet, "/metrics").Code)
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/metrics").Code)
+
+	rec := serve(application.OpsRouter, http.MethodGet, "/internal/queues")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, extractData(t, rec))
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/internal/queues").Code)
+	assert.Equal(t, http.StatusOK, serve(application.OpsRouter, http.MethodGet, "/internal/routes").Code)
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/internal/routes").Code)
+	assert.Equal(t, http.StatusOK, serve(application.OpsRouter, http.MethodGet, "/internal/error-catalog").Code)
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/internal/error-catalog").Code)
+	assert.Equal(t, http.StatusOK, serve(application.OpsRouter, http.MethodGet, "/version").Code)
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/version").Code)
+}
+
+func TestNewGuardsInternalEndpoints(t *testing.T) {
+	application, _ := newTestApp(t, &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+		Metrics:   config.MetricsConfig{BasicAuthUser: "scraper", BasicAuthPassword: "secret"},
+	})
+
+	for _, path := range []string{"/internal/routes", "/internal/error-catalog", "/version"} {
+		assert.Equal(t, http.StatusUnauthorized, serve(application.Router, http.MethodGet, path).Code, path)
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.SetBasicAuth("scraper", "secret")
+		rec := httptest.NewRecorder()
+		application.Router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestNewServesLogLevels(t *testing.T) {
+	levels := logger.NewLevels(zapcore.InfoLevel, nil)
+	application, _ := newTestApp(t, &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+		Metrics:   config.MetricsConfig{Port: 9100},
+	}, WithLogLevels(levels))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/internal/log-levels", strings.NewReader(`{"module":"scheduler","level":"debug"}`))
+	req.Header.Set("Content-Type", "application/json")
+	application.OpsRouter.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, levels.Enabled("scheduler", zapcore.DebugLevel))
+	assert.JSONEq(t, `{"default":"info","modules":[{"module":"scheduler","level":"debug"}]}`, extractData(t, rec))
+
+	rec = httptest.NewRecorder()
+	application.OpsRouter.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/internal/log-levels", strings.NewReader(`{"module":"scheduler"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	assert.Equal(t, http.StatusNoContent, serve(application.OpsRouter, http.MethodDelete, "/internal/log-levels/scheduler").Code)
+	assert.False(t, levels.Enabled("scheduler", zapcore.DebugLevel))
+	assert.Equal(t, http.StatusNotFound, serve(application.Router, http.MethodGet, "/internal/log-levels").Code)
+}
+
+func TestNewReportsStorageInReadiness(t *testing.T) {
+	cfg := &config.Config{
+		Env:       config.EnvDevelopment,
+		APIPrefix: "/api/v1",
+		JWT:       config.JWTConfig{Secret: "test-secret"},
+		Archives:  config.ArchivesConfig{Enabled: true, StorageDir: t.TempDir(), SignedURLSecret: "archive-secret"},
+	}
+	application, _ := newTestApp(t, cfg)
+	rec := serve(application.Router, http.MethodGet, "/ready")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Status  string `json:"status"`
+		Storage []struct {
+			Name    string `json:"name"`
+			Healthy bool   `json:"healthy"`
+		} `json:"storage"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "ok", body.Status)
+	require.Len(t, body.Storage, 1)
+	assert.Equal(t, "archives", body.Storage[0].Name)
+
+	// No filesystem is ever more than 100% free.
+	cfg.Storage.MinFreePercent = 101
+	application, _ = newTestApp(t, cfg)
+	rec = serve(application.Router, http.MethodGet, "/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "free space below 101%")
+}
+
+func extractData(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return string(body.Data)
+}
+
+func TestNewRejectsInvalidConfiguration(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = New(&config.Config{
+		APIPrefix: "/api/v1",
+		Archives:  config.ArchivesConfig{Enabled: true},
+	}, sqlx.NewDb(db, "sqlmock"), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "archives signed url secret")
+}
@@ -0,0 +1,1023 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	internalhandler "github.com/noah-isme/sma-adp-api/internal/handler"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	"github.com/noah-isme/sma-adp-api/internal/routes"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	"github.com/noah-isme/sma-adp-api/pkg/backup"
+	"github.com/noah-isme/sma-adp-api/pkg/broker"
+	"github.com/noah-isme/sma-adp-api/pkg/cache"
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+	"github.com/noah-isme/sma-adp-api/pkg/messaging"
+	"github.com/noah-isme/sma-adp-api/pkg/push"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+	"github.com/noah-isme/sma-adp-api/pkg/thumbnail"
+)
+
+// handlers holds everything the route table needs. Handlers for disabled features stay nil and
+// their routes are not registered.
+type handlers struct {
+	auth     *service.AuthService
+	security *service.SecurityService
+
+	authHandler        *internalhandler.AuthHandler
+	permission         *internalhandler.PermissionHandler
+	search             *internalhandler.SearchHandler
+	teacher            *internalhandler.TeacherHandler
+	schedulePreference *internalhandler.SchedulePreferenceAliasHandler
+	slotDefinition     *internalhandler.SlotDefinitionHandler
+	subjectLoadPreset  *internalhandler.SubjectLoadPresetHandler
+	exam               *internalhandler.ExamHandler
+	examExport         *internalhandler.ExamExportHandler
+	curriculum         *internalhandler.CurriculumHandler
+	lessonPlan         *internalhandler.LessonPlanHandler
+	grade              *internalhandler.GradeHandler
+	gradeConfig        *internalhandler.GradeConfigHandler
+	gradeComponent     *internalhandler.GradeComponentHandler
+	notification       *internalhandler.NotificationHandler
+	announcement       *internalhandler.AnnouncementHandler
+	guardian           *internalhandler.GuardianHandler
+	studentPortal      *internalhandler.StudentPortalHandler
+	securityHandler    *internalhandler.SecurityHandler
+	homeroom           *internalhandler.HomeroomHandler
+	calendarAlias      *internalhandler.CalendarAliasHandler
+	eventRSVP          *internalhandler.EventRSVPHandler
+	eventExport        *internalhandler.EventAttendanceExportHandler
+	attendanceAlias    *internalhandler.AttendanceAliasHandler
+	attendanceImport   *internalhandler.AttendanceImportHandler
+	attendanceCheckin  *internalhandler.AttendanceCheckinHandler
+	teacherAttendance  *internalhandler.TeacherAttendanceHandler
+	teacherLeave       *internalhandler.TeacherLeaveHandler
+	attendanceAlert    *internalhandler.AttendanceAlertHandler
+	alertRule          *internalhandler.AlertRuleHandler
+	absenceMessage     *internalhandler.AbsenceMessageHandler
+	configuration      *internalhandler.ConfigurationHandler
+	scheduler          *internalhandler.ScheduleGeneratorHandler
+	scheduleExport     *internalhandler.ScheduleExportHandler
+	scheduleWarning    *internalhandler.ScheduleWarningHandler
+	scheduleClash      *internalhandler.ScheduleClashHandler
+	clashExport        *internalhandler.ScheduleClashExportHandler
+	classTimetable     *internalhandler.ClassTimetableHandler
+	lessonGroup        *internalhandler.LessonGroupHandler
+	elective           *internalhandler.ElectiveHandler
+	intake             *internalhandler.IntakeHandler
+	analytics          *internalhandler.AnalyticsHandler
+	report             *internalhandler.ReportHandler
+	exportTemplate     *internalhandler.ExportTemplateHandler
+	mutation           *internalhandler.MutationHandler
+	gradeAppeal        *internalhandler.GradeAppealHandler
+	activeTerm         *service.ConfigurationService
+	archive            *internalhandler.ArchiveHandler
+	archiveRetention   *internalhandler.ArchiveRetentionHandler
+	retention          *internalhandler.RetentionHandler
+	backup             *internalhandler.BackupHandler
+	dashboard          *internalhandler.DashboardHandler
+	legacySync         *internalhandler.LegacySyncHandler
+
+	// owners resolves nested records for the SELF guards of their routes.
+	owners routes.Owners
+}
+
+// buildHandlers wires repositories and services for every enabled feature and starts their
+// background workers.
+func (a *App) buildHandlers() (*handlers, error) {
+	cfg, db, logr := a.cfg, a.db, a.logger
+	h := &handlers{}
+	// Module loggers can be tuned one at a time through LOG_MODULE_LEVELS and /internal/log-levels.
+	schedulerLog := logr.Named("scheduler")
+	attendanceLog := logr.Named("attendance")
+	eventsLog := logr.Named("events")
+	messagingLog := logr.Named("messaging")
+	reportsLog := logr.Named("reports")
+	dashboardLog := logr.Named("dashboard")
+
+	authRepo := repository.NewUserRepository(db)
+	maxSessions := make(map[models.UserRole]int, len(cfg.Sessions.MaxPerRole))
+	for role, limit := range cfg.Sessions.MaxPerRole {
+		maxSessions[models.UserRole(role)] = limit
+	}
+	h.auth = service.NewAuthService(authRepo, nil, logr, service.AuthConfig{
+		AccessTokenSecret:          cfg.JWT.Secret,
+		SecondaryAccessTokenSecret: cfg.JWT.SecondarySecret,
+		AccessTokenExpiry:          cfg.JWT.Expiration,
+		RefreshTokenExpiry:         cfg.JWT.RefreshExpiration,
+		Issuer:                     "sma-adp-api",
+		Audience:                   []string{"sma-adp-clients"},
+		MaxSessions:                maxSessions,
+		IdleTimeout:                cfg.Sessions.IdleTimeout,
+	})
+	if cfg.Sessions.IdleTimeout > 0 {
+		service.NewSessionSweeper(authRepo, service.SessionSweeperConfig{
+			Interval:    cfg.Sessions.SweepInterval,
+			IdleTimeout: cfg.Sessions.IdleTimeout,
+		}, logr).Start(a.ctx)
+	}
+	h.authHandler = internalhandler.NewAuthHandler(h.auth)
+
+	teacherRepo := repository.NewTeacherRepository(db)
+	classRepo := repository.NewClassRepository(db)
+	subjectRepo := repository.NewSubjectRepository(db)
+	termRepo := repository.NewTermRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	assignmentRepo := repository.NewTeacherAssignmentRepository(db)
+	homeroomRepo := repository.NewHomeroomRepository(db)
+	preferenceRepo := repository.NewTeacherPreferenceRepository(db)
+	calendarRepo := repository.NewCalendarRepository(db)
+	enrollmentRepo := repository.NewEnrollmentRepository(db)
+	semesterScheduleRepo := repository.NewSemesterScheduleRepository(db)
+	semesterSlotRepo := repository.NewSemesterScheduleSlotRepository(db)
+	configurationRepo := repository.NewConfigurationRepository(db)
+	slotDefinitionRepo := repository.NewSlotDefinitionRepository(db)
+	attendanceRates := service.NewAttendanceRates(configurationRepo, logr)
+
+	teacherSvc := service.NewTeacherService(teacherRepo, nil, logr)
+	calendarSvc := service.NewCalendarService(calendarRepo, nil, logr)
+	var assignmentOpts []service.TeacherAssignmentServiceOption
+	var preferenceOpts []service.TeacherPreferenceServiceOption
+	if cfg.Scheduler.Enabled {
+		revalidationSvc := service.NewScheduleRevalidationService(semesterScheduleRepo, semesterSlotRepo, assignmentRepo, preferenceRepo, schedulerLog)
+		assignmentOpts = append(assignmentOpts, service.WithAssignmentRevalidation(revalidationSvc))
+		preferenceOpts = append(preferenceOpts, service.WithPreferenceRevalidation(revalidationSvc))
+		h.scheduleWarning = internalhandler.NewScheduleWarningHandler(revalidationSvc)
+	}
+	assignmentSvc := service.NewTeacherAssignmentService(
+		teacherRepo,
+		classRepo,
+		subjectRepo,
+		termRepo,
+		assignmentRepo,
+		scheduleRepo,
+		preferenceRepo,
+		nil,
+		logr,
+		assignmentOpts...,
+	)
+	slotDefinitionSvc := service.NewSlotDefinitionService(slotDefinitionRepo, termRepo, cfg.Scheduler.SlotTimes, nil, schedulerLog)
+	preferenceOpts = append(preferenceOpts, service.WithPreferenceSheets(termRepo, slotDefinitionSvc))
+	preferenceSvc := service.NewTeacherPreferenceService(teacherRepo, preferenceRepo, nil, schedulerLog, preferenceOpts...)
+	h.slotDefinition = internalhandler.NewSlotDefinitionHandler(slotDefinitionSvc)
+	examRepo := repository.NewExamRepository(db)
+	teacherLeaveRepo := repository.NewTeacherLeaveRepository(db)
+	examSvc := service.NewExamService(service.ExamServiceParams{
+		Store:       examRepo,
+		Terms:       termRepo,
+		Classes:     classRepo,
+		Subjects:    subjectRepo,
+		Teachers:    teacherRepo,
+		Regular:     scheduleRepo,
+		Preferences: preferenceRepo,
+		Leaves:      teacherLeaveRepo,
+		Logger:      logr,
+	})
+	h.exam = internalhandler.NewExamHandler(examSvc)
+	curriculumSvc := service.NewCurriculumService(service.CurriculumServiceParams{
+		Store:       repository.NewCurriculumRepository(db),
+		Terms:       termRepo,
+		Subjects:    subjectRepo,
+		Classes:     classRepo,
+		Assignments: assignmentRepo,
+		Logger:      logr,
+	})
+	h.curriculum = internalhandler.NewCurriculumHandler(curriculumSvc)
+	h.teacher = internalhandler.NewTeacherHandler(teacherSvc, assignmentSvc, preferenceSvc)
+	h.owners.TeacherAssignment = assignmentRepo.TeacherOf
+	if preferenceSvc != nil {
+		h.schedulePreference = internalhandler.NewSchedulePreferenceHandler(preferenceSvc)
+	}
+
+	if cfg.Homerooms.Enabled {
+		homeroomSvc := service.NewHomeroomService(
+			homeroomRepo,
+			classRepo,
+			termRepo,
+			teacherRepo,
+			subjectRepo,
+			assignmentRepo,
+			authRepo,
+			nil,
+			logr,
+		)
+		h.homeroom = internalhandler.NewHomeroomHandler(homeroomSvc)
+	}
+
+	if cfg.Aliases.CalendarEnabled {
+		calendarAliasSvc := service.NewCalendarAliasService(calendarSvc, termRepo, assignmentSvc, classRepo, logr)
+		h.calendarAlias = internalhandler.NewCalendarAliasHandler(calendarAliasSvc, logr)
+	}
+
+	// domainEvents stays nil unless the event stream is enabled, in which case services write
+	// without the outbox exactly as before.
+	var domainEvents *service.DomainEvents
+	if cfg.Events.Enabled {
+		outboxRepo := repository.NewOutboxRepository(db)
+		publisher, err := broker.New(cfg.Events, eventsLog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init events broker: %w", err)
+		}
+		a.onClose(publisher.Close)
+		domainEvents = service.NewDomainEvents(db, outboxRepo)
+		service.NewDomainEventRelay(outboxRepo, publisher, service.DomainEventRelayConfig{
+			RelayID:        cfg.Events.RelayID,
+			SubjectPrefix:  cfg.Events.SubjectPrefix,
+			PollInterval:   cfg.Events.PollInterval,
+			BatchSize:      cfg.Events.BatchSize,
+			PublishTimeout: cfg.Events.PublishTimeout,
+			ClaimTimeout:   cfg.Events.ClaimTimeout,
+			Retention:      cfg.Events.Retention,
+		}, eventsLog).Start(a.ctx)
+	}
+
+	// dashboardWarmer is built with the dashboard below; bulk attendance writes trigger it.
+	var dashboardWarmer *service.DashboardWarmer
+	var attendanceSvc *service.AttendanceService
+	var attendanceSummaryRepo *repository.AttendanceAliasRepository
+	if cfg.Aliases.AttendanceEnabled {
+		dailyAttendanceRepo := repository.NewDailyAttendanceRepository(db)
+		subjectAttendanceRepo := repository.NewSubjectAttendanceRepository(db)
+		attendanceOpts := []service.AttendanceServiceOption{
+			service.WithSchoolDayValidation(calendarSvc, enrollmentRepo, termRepo, service.AttendanceCalendarConfig{
+				Policy:            cfg.Attendance.NonSchoolDayPolicy,
+				NonSchoolWeekdays: cfg.Attendance.NonSchoolWeekdays,
+			}),
+			service.WithBulkWriteHook(func() {
+				if dashboardWarmer != nil {
+					dashboardWarmer.Trigger()
+				}
+			}),
+			service.WithAttendanceEvents(domainEvents),
+			service.WithAttendanceRates(attendanceRates),
+		}
+		if cfg.Archives.Enabled {
+			attendanceOpts = append(attendanceOpts, service.WithAbsenceEvidence(repository.NewArchiveRepository(db)))
+		}
+		if cfg.Messaging.Enabled {
+			location, err := time.LoadLocation(cfg.Attendance.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+			}
+			gateway, err := messaging.New(cfg.Messaging, messagingLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to init messaging gateway: %w", err)
+			}
+			absenceMessages, err := service.NewAbsenceMessageService(service.AbsenceMessageServiceParams{
+				Store:  repository.NewAbsenceMessageRepository(db),
+				Sender: gateway,
+				Logger: messagingLog,
+				Config: service.AbsenceMessageConfig{
+					QuietHoursStart: cfg.Messaging.QuietHoursStart,
+					QuietHoursEnd:   cfg.Messaging.QuietHoursEnd,
+					Location:        location,
+					PollInterval:    cfg.Messaging.PollInterval,
+					BatchSize:       cfg.Messaging.BatchSize,
+					SendTimeout:     cfg.Messaging.Timeout,
+					MaxAttempts:     cfg.Messaging.MaxAttempts,
+					RetryBackoff:    cfg.Messaging.RetryBackoff,
+				},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("invalid absence message configuration: %w", err)
+			}
+			absenceMessages.Start(a.ctx)
+			attendanceOpts = append(attendanceOpts, service.WithAbsenceMessages(absenceMessages))
+			h.absenceMessage = internalhandler.NewAbsenceMessageHandler(absenceMessages, gateway)
+		}
+		attendanceSvc = service.NewAttendanceService(dailyAttendanceRepo, subjectAttendanceRepo, nil, attendanceLog, attendanceOpts...)
+		attendanceSummaryRepo = repository.NewAttendanceAliasRepository(db)
+	}
+
+	if attendanceSvc != nil {
+		attendanceImportRepo := repository.NewAttendanceImportRepository(db)
+		importWorker := service.NewAttendanceImportWorker(attendanceImportRepo, attendanceSvc, service.AttendanceImportConfig{
+			ChunkSize:  cfg.Attendance.ImportChunkSize,
+			MaxRetries: cfg.Attendance.ImportRetries,
+		}, attendanceLog)
+		importQueue := a.startQueue("attendance-imports", importWorker.Handle, cfg.Attendance.ImportWorkers, cfg.Attendance.ImportRetries)
+		attendanceImportSvc := service.NewAttendanceImportService(attendanceImportRepo, attendanceSvc, importQueue, attendanceLog)
+		attendanceImportSvc.RecoverPendingJobs(a.ctx)
+		h.attendanceImport = internalhandler.NewAttendanceImportHandler(attendanceImportSvc)
+	}
+
+	if attendanceSvc != nil && len(cfg.Attendance.DeviceKeys) > 0 {
+		location, err := time.LoadLocation(cfg.Attendance.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+		}
+		checkinCfg := service.AttendanceCheckinConfig{
+			DeviceKeys: cfg.Attendance.DeviceKeys,
+			LateAfter:  cfg.Attendance.LateAfter,
+			Location:   location,
+		}
+		studentRepo := repository.NewStudentRepository(db)
+		checkinSvc := service.NewAttendanceCheckinService(studentRepo, enrollmentRepo, termRepo, attendanceSvc, configurationRepo, nil, checkinCfg, attendanceLog)
+		if cfg.Attendance.QRSecret != "" {
+			qrSigner := storage.NewSignedURLSigner(cfg.Attendance.QRSecret, cfg.Attendance.QRTTL)
+			checkinSvc = service.NewAttendanceCheckinService(studentRepo, enrollmentRepo, termRepo, attendanceSvc, configurationRepo, qrSigner, checkinCfg, attendanceLog)
+		}
+		h.attendanceCheckin = internalhandler.NewAttendanceCheckinHandler(checkinSvc)
+	}
+
+	var teacherAttendanceSvc *service.TeacherAttendanceService
+	if cfg.TeacherAttendance.Enabled {
+		location, err := time.LoadLocation(cfg.Attendance.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+		}
+		teacherAttendanceSvc, err = service.NewTeacherAttendanceService(repository.NewTeacherAttendanceRepository(db), teacherRepo, service.TeacherAttendanceConfig{
+			LateAfter:            cfg.TeacherAttendance.LateAfter,
+			Location:             location,
+			AllowedNetworks:      cfg.TeacherAttendance.AllowedNetworks,
+			GeofenceLatitude:     cfg.TeacherAttendance.GeofenceLatitude,
+			GeofenceLongitude:    cfg.TeacherAttendance.GeofenceLongitude,
+			GeofenceRadiusMeters: cfg.TeacherAttendance.GeofenceRadiusMeters,
+		}, logr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid teacher attendance configuration: %w", err)
+		}
+		h.teacherAttendance = internalhandler.NewTeacherAttendanceHandler(teacherAttendanceSvc)
+	}
+
+	defaults := map[string]string{}
+	if cfg.Configuration.ActiveTermID != "" {
+		defaults["active_term_id"] = cfg.Configuration.ActiveTermID
+	}
+	if cfg.Configuration.DefaultDashboardTermID != "" {
+		defaults["default_dashboard_term_id"] = cfg.Configuration.DefaultDashboardTermID
+	}
+	if cfg.Configuration.DefaultCalendarTermID != "" {
+		defaults["default_calendar_term_id"] = cfg.Configuration.DefaultCalendarTermID
+	}
+	defaults[service.AttendanceLateAfterKey] = cfg.Attendance.LateAfter
+	defaults[service.RetentionRefreshTokensKey] = strconv.Itoa(int(cfg.Retention.RefreshTokens / (24 * time.Hour)))
+	defaults[service.RetentionAuditLogsKey] = strconv.Itoa(int(cfg.Retention.AuditLogs / (24 * time.Hour)))
+	configurationSvc := service.NewConfigurationService(
+		configurationRepo,
+		termRepo,
+		authRepo,
+		nil,
+		logr,
+		service.ConfigurationServiceConfig{Defaults: defaults},
+	)
+	// The active term fills in termId on term-scoped routes even when the configuration API is off.
+	h.activeTerm = configurationSvc
+	if cfg.Configuration.Enabled {
+		h.configuration = internalhandler.NewConfigurationHandler(configurationSvc)
+	}
+
+	if cfg.Scheduler.Enabled {
+		presetRepo := repository.NewSubjectLoadPresetRepository(db)
+		h.subjectLoadPreset = internalhandler.NewSubjectLoadPresetHandler(service.NewSubjectLoadPresetService(presetRepo, subjectRepo, nil))
+		schedulerSvc := service.NewScheduleGeneratorService(
+			termRepo,
+			classRepo,
+			subjectRepo,
+			assignmentRepo,
+			preferenceRepo,
+			scheduleRepo,
+			semesterScheduleRepo,
+			semesterSlotRepo,
+			nil,
+			db,
+			nil,
+			schedulerLog,
+			service.ScheduleGeneratorConfig{
+				ProposalTTL:           cfg.Scheduler.ProposalTTL,
+				MaxOptimizationBudget: cfg.Scheduler.MaxOptimizationBudget,
+				Events:                domainEvents,
+				Presets:               presetRepo,
+			},
+		)
+		h.scheduler = internalhandler.NewScheduleGeneratorHandler(schedulerSvc)
+	}
+
+	var analyticsRepo ports.AnalyticsRepository
+	if cfg.Analytics.Enabled || cfg.Dashboard.Enabled || cfg.Reports.Enabled || cfg.Aliases.AttendanceEnabled || cfg.AlertRules.Enabled {
+		analyticsRepo = attendanceRates.Analytics(repository.NewAnalyticsRepository(db))
+	}
+
+	var cacheRepo service.CacheRepository
+	if cfg.Analytics.Enabled || cfg.Dashboard.Enabled {
+		if client, err := cache.NewRedis(cfg.Redis, logr); err != nil {
+			logr.Sugar().Warnw("cache disabled", "error", err)
+		} else {
+			if err := cache.Ping(client); err != nil {
+				logr.Sugar().Warnw("redis unreachable; serving cache misses until it recovers", "error", err)
+			}
+			a.onClose(client.Close)
+			cacheRepo = repository.NewCacheRepository(client, logr)
+		}
+	}
+
+	var analyticsSvc *service.AnalyticsService
+	if cfg.Analytics.Enabled {
+		cacheSvc := service.NewCacheService(cacheRepo, a.metrics, cfg.Analytics.CacheTTL, logr, cacheRepo != nil)
+		analyticsSvc = service.NewAnalyticsService(analyticsRepo, cacheSvc, a.metrics, logr)
+		h.analytics = internalhandler.NewAnalyticsHandler(analyticsSvc)
+	}
+
+	if cfg.Aliases.AttendanceEnabled && attendanceSvc != nil && attendanceSummaryRepo != nil {
+		attendanceAliasSvc := service.NewAttendanceAliasService(attendanceSvc, analyticsSvc, attendanceSummaryRepo, assignmentRepo, enrollmentRepo, termRepo, logr)
+		h.attendanceAlias = internalhandler.NewAttendanceAliasHandler(attendanceAliasSvc)
+	}
+
+	scheduleClashParams := service.ScheduleClashServiceParams{
+		Schedules: scheduleRepo,
+		Terms:     termRepo,
+		Subjects:  subjectRepo,
+		Teachers:  teacherRepo,
+		Classes:   classRepo,
+		Logger:    schedulerLog,
+	}
+	if cfg.Reports.Enabled {
+		reportRepo := repository.NewReportRepository(db)
+		fileStore, err := storage.NewLocalStorage(cfg.Reports.StorageDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init report storage: %w", err)
+		}
+		signer := storage.NewRotatingSignedURLSigner(cfg.Reports.SignedURLSecret, cfg.Reports.SignedURLSecondarySecret, cfg.Reports.SignedURLTTL)
+		exportCfg := service.ExportConfig{APIPrefix: cfg.APIPrefix, ResultTTL: cfg.Reports.SignedURLTTL}
+		exportTemplateRepo := repository.NewExportTemplateRepository(db)
+		exportSvc := service.NewExportService(analyticsRepo, fileStore, signer, exportCfg, reportsLog, nil, nil, service.WithExportTemplates(exportTemplateRepo),
+			service.WithResearchExports(repository.NewResearchExportRepository(db), []byte(cfg.Reports.ResearchKey)),
+			service.WithMinistryExports(repository.NewMinistryExportRepository(db)))
+		h.exportTemplate = internalhandler.NewExportTemplateHandler(service.NewExportTemplateService(exportTemplateRepo, nil))
+		reportClaims := service.ReportClaimConfig{
+			WorkerID:          cfg.Reports.WorkerID,
+			HeartbeatInterval: cfg.Reports.HeartbeatInterval,
+			Timeout:           cfg.Reports.ClaimTimeout,
+		}
+		reportWorker := service.NewReportWorker(reportRepo, exportSvc, cfg.Reports.WorkerRetries, reportsLog, service.WithReportClaims(reportClaims))
+		reportQueue := a.startPriorityQueue("reports", reportWorker.Handle, cfg.Reports.WorkerConcurrency, cfg.Reports.WorkerRetries,
+			service.QueueScaling{MinWorkers: cfg.Reports.WorkerMin, MaxWorkers: cfg.Reports.WorkerMax})
+		reportSvc := service.NewReportService(reportRepo, assignmentRepo, reportQueue, exportSvc, reportsLog, service.ReportServiceConfig{
+			ResultTTL:       cfg.Reports.SignedURLTTL,
+			CleanupInterval: cfg.Reports.CleanupInterval,
+			MaxRetries:      cfg.Reports.WorkerRetries,
+			Claims:          reportClaims,
+			DedupWindow:     cfg.Reports.DedupWindow,
+			Storage:         a.storage,
+		})
+		reportSvc.StartRecovery(a.ctx)
+		reportSvc.StartCleanup(a.ctx)
+		h.report = internalhandler.NewReportHandler(reportSvc, nil)
+		examExportSvc := service.NewExamExportService(examRepo, subjectRepo, teacherRepo, classRepo, slotDefinitionSvc, exportSvc, reportRepo, nil, reportsLog)
+		h.examExport = internalhandler.NewExamExportHandler(examExportSvc)
+		eventExportSvc := service.NewEventAttendanceExportService(calendarRepo, repository.NewEventRSVPRepository(db), exportSvc, reportRepo, nil, reportsLog)
+		h.eventExport = internalhandler.NewEventAttendanceExportHandler(eventExportSvc)
+		scheduleClashParams.Store, scheduleClashParams.Jobs = exportSvc, reportRepo
+		if cfg.Scheduler.Enabled {
+			scheduleExportSvc := service.NewScheduleExportService(
+				semesterScheduleRepo,
+				semesterSlotRepo,
+				subjectRepo,
+				teacherRepo,
+				classRepo,
+				slotDefinitionSvc,
+				exportSvc,
+				reportRepo,
+				nil,
+				reportsLog,
+			)
+			h.scheduleExport = internalhandler.NewScheduleExportHandler(scheduleExportSvc)
+		}
+	}
+
+	scheduleClashSvc := service.NewScheduleClashService(scheduleClashParams)
+	h.scheduleClash = internalhandler.NewScheduleClashHandler(scheduleClashSvc)
+	if scheduleClashParams.Store != nil {
+		h.clashExport = internalhandler.NewScheduleClashExportHandler(scheduleClashSvc)
+	}
+
+	lessonGroupRepo := repository.NewLessonGroupRepository(db)
+	lessonGroupParams := service.LessonGroupServiceParams{
+		Store:       lessonGroupRepo,
+		Schedules:   scheduleRepo,
+		Enrollments: enrollmentRepo,
+		Terms:       termRepo,
+		Classes:     classRepo,
+		Subjects:    subjectRepo,
+		Teachers:    teacherRepo,
+		Logger:      schedulerLog,
+	}
+	if attendanceSvc != nil {
+		lessonGroupParams.Attendance = attendanceSvc
+	}
+	h.lessonGroup = internalhandler.NewLessonGroupHandler(service.NewLessonGroupService(lessonGroupParams))
+	h.elective = internalhandler.NewElectiveHandler(service.NewElectiveService(service.ElectiveServiceParams{
+		Store:        repository.NewElectiveRepository(db),
+		LessonGroups: lessonGroupRepo,
+		Students:     repository.NewStudentRepository(db),
+		Enrollments:  enrollmentRepo,
+		Terms:        termRepo,
+		Subjects:     subjectRepo,
+		Teachers:     teacherRepo,
+		Logger:       schedulerLog,
+	}))
+
+	var archiveSvc *service.ArchiveService
+	if cfg.Archives.Enabled {
+		if cfg.Archives.SignedURLSecret == "" {
+			return nil, fmt.Errorf("archives signed url secret not configured")
+		}
+		archiveRepo := repository.NewArchiveRepository(db)
+		archiveStore, err := storage.NewLocalStorage(cfg.Archives.StorageDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init archive storage: %w", err)
+		}
+		archiveSigner := storage.NewRotatingSignedURLSigner(cfg.Archives.SignedURLSecret, cfg.Archives.SignedURLSecondarySecret, cfg.Archives.SignedURLTTL)
+		archiveSvc = service.NewArchiveService(
+			archiveRepo,
+			assignmentRepo,
+			enrollmentRepo,
+			archiveStore,
+			archiveSigner,
+			authRepo,
+			logr,
+			service.ArchiveServiceConfig{
+				MaxFileSize:          cfg.Archives.MaxFileSizeBytes,
+				AllowedMIMEs:         cfg.Archives.AllowedMIMEs,
+				APIPrefix:            cfg.APIPrefix,
+				TrashGrace:           cfg.Archives.TrashGrace,
+				CategoryQuotas:       cfg.Archives.CategoryQuotas,
+				DefaultCategoryQuota: cfg.Archives.DefaultCategoryQuota,
+				UploaderQuota:        cfg.Archives.UploaderQuota,
+			},
+		)
+		h.archive = internalhandler.NewArchiveHandler(archiveSvc)
+		// The job also empties the trash, so it runs without retention policies too.
+		retentionSvc := service.NewArchiveRetentionService(archiveRepo, archiveStore, authRepo, logr.Named("archives"), service.ArchiveRetentionConfig{
+			Policies: cfg.Archives.Retention,
+			Grace:    cfg.Archives.TrashGrace,
+			Interval: cfg.Archives.RetentionInterval,
+		})
+		retentionSvc.Start(a.ctx)
+		if len(cfg.Archives.Retention) > 0 {
+			h.archiveRetention = internalhandler.NewArchiveRetentionHandler(retentionSvc)
+		}
+		if cfg.Archives.Thumbnails {
+			renderer := thumbnail.NewPDFRenderer(cfg.Archives.ThumbnailCommand, cfg.Archives.ThumbnailWidth)
+			// Without the rasteriser archives simply have no preview; uploads and downloads are unaffected.
+			if err := renderer.Available(); err != nil {
+				logr.Sugar().Warnw("archive thumbnails disabled", "error", err)
+			} else {
+				service.NewArchiveThumbnailService(archiveRepo, archiveStore, renderer, logr.Named("archives"), service.ArchiveThumbnailConfig{
+					Interval: cfg.Archives.ThumbnailInterval,
+				}).Start(a.ctx)
+			}
+		}
+	}
+
+	if cfg.Backups.Enabled {
+		if err := a.buildBackups(h); err != nil {
+			return nil, err
+		}
+	}
+
+	notificationRepo := repository.NewNotificationRepository(db)
+	var mutationSvc *service.MutationService
+	var mutationExpiry *service.MutationExpiry
+	if cfg.Mutations.Enabled {
+		mutationRepo := repository.NewMutationRepository(db)
+		studentRepo := repository.NewStudentRepository(db)
+		gradeFinalRepo := repository.NewGradeFinalRepository(db)
+		gradeAppealRepo := repository.NewGradeAppealRepository(db)
+		mutationOpts := []service.MutationServiceOption{
+			service.WithMutationAppliers(map[string]service.MutationApplier{
+				"student":                     service.NewStudentMutationApplier(studentRepo, logr),
+				service.GradeUnfinalizeEntity: service.NewGradeUnfinalizeApplier(gradeFinalRepo, logr),
+				service.GradeAppealEntity: service.NewGradeAppealApplier(gradeAppealRepo, gradeFinalRepo,
+					repository.NewGradeConfigRepository(db), domainEvents, logr),
+			}),
+			service.WithMutationEvents(domainEvents),
+		}
+		if archiveSvc != nil {
+			mutationOpts = append(mutationOpts, service.WithMutationAttachments(archiveSvc))
+		}
+		mutationSvc = service.NewMutationService(mutationRepo, authRepo, logr, mutationOpts...)
+		h.mutation = internalhandler.NewMutationHandler(mutationSvc)
+		mutationExpiry = service.NewMutationExpiry(mutationRepo, notificationRepo, authRepo, a.metrics, logr, service.MutationExpiryConfig{
+			Interval:      cfg.Mutations.SweepInterval,
+			ReminderAfter: cfg.Mutations.ReminderAfter,
+			ExpireAfter:   cfg.Mutations.ExpireAfter,
+			Action:        cfg.Mutations.ExpiryAction,
+		})
+		mutationExpiry.Start(a.ctx)
+		gradeAppealSvc := service.NewGradeAppealService(service.GradeAppealServiceParams{
+			Store:         gradeAppealRepo,
+			Mutations:     mutationSvc,
+			Guardians:     repository.NewGuardianRepository(db),
+			Students:      studentRepo,
+			Notifications: notificationRepo,
+			Logger:        logr.Named("grade-appeals"),
+			Config: service.GradeAppealConfig{
+				TeacherSLA: cfg.GradeAppeals.TeacherSLA,
+				AdminSLA:   cfg.GradeAppeals.AdminSLA,
+				Interval:   cfg.Mutations.SweepInterval,
+			},
+		})
+		gradeAppealSvc.Start(a.ctx)
+		h.gradeAppeal = internalhandler.NewGradeAppealHandler(gradeAppealSvc)
+	}
+
+	deviceTokenRepo := repository.NewDeviceTokenRepository(db)
+	h.notification = internalhandler.NewNotificationHandler(service.NewNotificationService(notificationRepo, deviceTokenRepo))
+	h.announcement = internalhandler.NewAnnouncementHandler(service.NewAnnouncementService(repository.NewAnnouncementRepository(db), nil, logr,
+		service.WithAnnouncementNotifications(notificationRepo)))
+	if cfg.Push.Enabled {
+		sender, err := push.New(cfg.Push, logr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init push sender: %w", err)
+		}
+		service.NewPushChannel(notificationRepo, deviceTokenRepo, sender, service.PushChannelConfig{
+			PollInterval: cfg.Push.PollInterval,
+			BatchSize:    cfg.Push.BatchSize,
+			Concurrency:  cfg.Push.Concurrency,
+			SendTimeout:  cfg.Push.Timeout,
+			MaxAttempts:  cfg.Push.MaxAttempts,
+			RetryBackoff: cfg.Push.RetryBackoff,
+			MaxAge:       cfg.Push.MaxAge,
+		}, logr).Start(a.ctx)
+	}
+	lessonPlanRepo := repository.NewLessonPlanRepository(db)
+	lessonPlanParams := service.LessonPlanServiceParams{
+		Store:         lessonPlanRepo,
+		Terms:         termRepo,
+		Subjects:      subjectRepo,
+		Classes:       classRepo,
+		Assignments:   assignmentRepo,
+		Notifications: notificationRepo,
+		Audit:         authRepo,
+		Logger:        logr,
+	}
+	if archiveSvc != nil {
+		lessonPlanParams.Attachments = archiveSvc
+	}
+	h.lessonPlan = internalhandler.NewLessonPlanHandler(service.NewLessonPlanService(lessonPlanParams))
+
+	if cfg.LessonPlans.RemindersEnabled {
+		service.NewLessonPlanReminder(lessonPlanRepo, termRepo, notificationRepo, service.LessonPlanReminderConfig{
+			Interval:     cfg.LessonPlans.ReminderInterval,
+			DeadlineDays: cfg.LessonPlans.DeadlineDays,
+			Lead:         cfg.LessonPlans.ReminderLead,
+		}, logr).Start(a.ctx)
+	}
+
+	intakeParams := service.IntakeServiceParams{
+		Store:         repository.NewIntakeRepository(db),
+		Students:      repository.NewStudentRepository(db),
+		Terms:         termRepo,
+		Classes:       classRepo,
+		Homerooms:     homeroomRepo,
+		Notifications: notificationRepo,
+		Audit:         authRepo,
+		Logger:        logr,
+	}
+	if archiveSvc != nil {
+		intakeParams.Documents = archiveSvc
+	}
+	h.intake = internalhandler.NewIntakeHandler(service.NewIntakeService(intakeParams))
+
+	var attendanceAlertRepo *repository.AttendanceAlertRepository
+	if cfg.AttendanceAlerts.Enabled {
+		location, err := time.LoadLocation(cfg.Attendance.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+		}
+		attendanceAlertRepo = repository.NewAttendanceAlertRepository(db)
+		attendanceAlertSvc, err := service.NewAttendanceAlertService(service.AttendanceAlertServiceParams{
+			Store:         attendanceAlertRepo,
+			Terms:         termRepo,
+			Classes:       classRepo,
+			Homerooms:     homeroomRepo,
+			Notifications: notificationRepo,
+			Rates:         attendanceRates,
+			Logger:        logr,
+			Config: service.AttendanceAlertConfig{
+				DefaultThreshold: cfg.AttendanceAlerts.DefaultThreshold,
+				MinDays:          cfg.AttendanceAlerts.MinDays,
+				RunAt:            cfg.AttendanceAlerts.RunAt,
+				Location:         location,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendance alert configuration: %w", err)
+		}
+		attendanceAlertSvc.Start(a.ctx)
+		h.attendanceAlert = internalhandler.NewAttendanceAlertHandler(attendanceAlertSvc)
+	}
+
+	var alertRuleRepo *repository.AlertRuleRepository
+	if cfg.AlertRules.Enabled {
+		alertRuleRepo = repository.NewAlertRuleRepository(db)
+		alertRuleSvc := service.NewAlertRuleService(service.AlertRuleServiceParams{
+			Store:         alertRuleRepo,
+			Terms:         termRepo,
+			Analytics:     analyticsRepo,
+			Classes:       classRepo,
+			Notifications: notificationRepo,
+			Homerooms:     homeroomRepo,
+			Assignments:   assignmentRepo,
+			Logger:        logr,
+			Interval:      cfg.AlertRules.Interval,
+		})
+		alertRuleSvc.Start(a.ctx)
+		h.alertRule = internalhandler.NewAlertRuleHandler(alertRuleSvc)
+	}
+
+	if cfg.AttendancePartitions.Enabled {
+		location, err := time.LoadLocation(cfg.Attendance.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+		}
+		service.NewAttendancePartitionService(repository.NewAttendancePartitionRepository(db), logr.Named("attendance"), service.AttendancePartitionConfig{
+			AheadMonths:  cfg.AttendancePartitions.AheadMonths,
+			RetainMonths: cfg.AttendancePartitions.RetainMonths,
+			Interval:     cfg.AttendancePartitions.Interval,
+			Location:     location,
+		}).Start(a.ctx)
+	}
+
+	searchRepo := repository.NewSearchRepository(db)
+	searchSvc := service.NewSearchService(searchRepo, nil, assignmentRepo, logr)
+	if archiveSvc != nil {
+		searchSvc = service.NewSearchService(searchRepo, archiveSvc, assignmentRepo, logr)
+	}
+	h.search = internalhandler.NewSearchHandler(searchSvc)
+
+	gradeOpts := []service.GradeServiceOption{
+		service.WithClassSubjects(repository.NewClassSubjectRepository(db)),
+		service.WithGradeEvents(domainEvents),
+		service.WithTeacherAssignments(assignmentRepo),
+	}
+	if mutationSvc != nil {
+		gradeOpts = append(gradeOpts, service.WithUnfinalizeApproval(mutationSvc))
+	}
+	gradeConfigRepo := repository.NewGradeConfigRepository(db)
+	gradeComponentRepo := repository.NewGradeComponentRepository(db)
+	gradeSvc := service.NewGradeService(
+		repository.NewGradeRepository(db),
+		repository.NewGradeFinalRepository(db),
+		enrollmentRepo,
+		gradeConfigRepo,
+		gradeComponentRepo,
+		nil,
+		logr,
+		gradeOpts...,
+	)
+	h.gradeConfig = internalhandler.NewGradeConfigHandler(service.NewGradeConfigService(gradeConfigRepo, gradeComponentRepo, nil, logr))
+	h.gradeComponent = internalhandler.NewGradeComponentHandler(service.NewGradeComponentService(gradeComponentRepo, nil, logr))
+	h.grade = internalhandler.NewGradeHandler(gradeSvc)
+	if attendanceSvc != nil && len(cfg.Cutover.SyncAPIKeys) > 0 {
+		h.legacySync = internalhandler.NewLegacySyncHandler(service.NewLegacySyncService(service.LegacySyncServiceParams{
+			Store:       repository.NewLegacySyncRepository(db),
+			Enrollments: enrollmentRepo,
+			Attendance:  attendanceSvc,
+			Grades:      gradeSvc,
+			Logger:      logr,
+		}))
+	}
+	guardianRepo := repository.NewGuardianRepository(db)
+	guardianSvc := service.NewGuardianService(service.GuardianServiceParams{
+		Links:         guardianRepo,
+		Contacts:      guardianRepo,
+		Users:         authRepo,
+		Students:      repository.NewStudentRepository(db),
+		Enrollments:   enrollmentRepo,
+		Terms:         termRepo,
+		Attendance:    repository.NewAttendanceAliasRepository(db),
+		Rates:         attendanceRates,
+		Grades:        gradeSvc,
+		Announcements: service.NewAnnouncementService(repository.NewAnnouncementRepository(db), nil, logr),
+		Calendar:      calendarSvc,
+		Logger:        logr,
+	})
+	h.guardian = internalhandler.NewGuardianHandler(guardianSvc)
+	schoolLocation, err := time.LoadLocation(cfg.Attendance.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attendance timezone: %w", err)
+	}
+	h.eventRSVP = internalhandler.NewEventRSVPHandler(service.NewEventRSVPService(service.EventRSVPServiceParams{
+		Events:    calendarRepo,
+		Store:     repository.NewEventRSVPRepository(db),
+		Users:     authRepo,
+		Guardians: guardianSvc,
+		Logger:    logr,
+		Config:    service.EventRSVPConfig{Location: schoolLocation},
+	}))
+	h.teacherLeave = internalhandler.NewTeacherLeaveHandler(service.NewTeacherLeaveService(service.TeacherLeaveServiceParams{
+		Store:       teacherLeaveRepo,
+		Teachers:    teacherRepo,
+		Terms:       termRepo,
+		Schedules:   scheduleRepo,
+		Assignments: assignmentRepo,
+		Calendar:    calendarSvc,
+		Logger:      logr,
+		Config: service.TeacherLeaveConfig{
+			Location: schoolLocation,
+			Allowances: map[models.TeacherLeaveType]int{
+				models.TeacherLeaveSick:     cfg.TeacherLeave.SickDays,
+				models.TeacherLeavePersonal: cfg.TeacherLeave.PersonalDays,
+				models.TeacherLeaveTraining: cfg.TeacherLeave.TrainingDays,
+			},
+			NonSchoolWeekdays: cfg.Attendance.NonSchoolWeekdays,
+		},
+	}))
+	h.classTimetable = internalhandler.NewClassTimetableHandler(service.NewClassTimetableService(service.ClassTimetableServiceParams{
+		Terms:     termRepo,
+		Classes:   classRepo,
+		Subjects:  subjectRepo,
+		Teachers:  teacherRepo,
+		Semesters: semesterScheduleRepo,
+		Slots:     semesterSlotRepo,
+		Schedules: scheduleRepo,
+		Exams:     examRepo,
+		Leaves:    teacherLeaveRepo,
+		Calendar:  calendarSvc,
+		Labels:    slotDefinitionSvc,
+		Logger:    schedulerLog,
+		Location:  schoolLocation,
+	}))
+
+	h.studentPortal = internalhandler.NewStudentPortalHandler(service.NewStudentPortalService(service.StudentPortalServiceParams{
+		Students:   repository.NewStudentRepository(db),
+		Users:      service.NewUserService(authRepo, nil, logr),
+		Schedules:  service.NewScheduleService(scheduleRepo, nil, logr),
+		Attendance: repository.NewAttendanceAliasRepository(db),
+		Rates:      attendanceRates,
+		Grades:     gradeSvc,
+		Logger:     logr,
+	}))
+
+	if cfg.Security.AuditDenials {
+		h.security = service.NewSecurityService(authRepo, repository.NewSecurityRepository(db), logr, service.SecurityServiceConfig{
+			AlertThreshold: cfg.Security.DenialAlertThreshold,
+			AlertWindow:    cfg.Security.DenialAlertWindow,
+		})
+		h.securityHandler = internalhandler.NewSecurityHandler(h.security)
+	}
+
+	if cfg.Retention.Enabled {
+		retention := service.NewRetentionService(service.RetentionServiceParams{
+			Store:    repository.NewRetentionRepository(db),
+			Settings: configurationRepo,
+			Users:    authRepo,
+			Audit:    authRepo,
+			Metrics:  a.metrics,
+			Logger:   logr.Named("retention"),
+			Config: service.RetentionConfig{
+				Periods: map[models.RetentionDataClass]time.Duration{
+					models.RetentionRefreshTokens: cfg.Retention.RefreshTokens,
+					models.RetentionAuditLogs:     cfg.Retention.AuditLogs,
+				},
+				Interval: cfg.Retention.Interval,
+			},
+		})
+		retention.Start(a.ctx)
+		h.retention = internalhandler.NewRetentionHandler(retention)
+	}
+
+	if cfg.Dashboard.Enabled {
+		dashboardCache := service.NewCacheService(cacheRepo, a.metrics, cfg.Dashboard.CacheTTL, logr, cacheRepo != nil)
+		announcementSvc := service.NewAnnouncementService(repository.NewAnnouncementRepository(db), nil, logr)
+		scheduleSvc := service.NewScheduleService(scheduleRepo, nil, logr)
+		dashboardParams := service.DashboardServiceParams{
+			Analytics:        analyticsSvc,
+			AnalyticsRepo:    analyticsRepo,
+			Calendar:         calendarSvc,
+			Announcements:    announcementSvc,
+			AnnouncementAcks: announcementSvc,
+			Schedules:        scheduleSvc,
+			Assignments:      assignmentSvc,
+			SlotLabels:       slotDefinitionSvc,
+			Curriculum:       curriculumSvc,
+			Cache:            dashboardCache,
+			Logger:           dashboardLog,
+			Config:           service.DashboardServiceConfig{CacheTTL: cfg.Dashboard.CacheTTL},
+		}
+		if teacherAttendanceSvc != nil {
+			dashboardParams.TeacherPresence = teacherAttendanceSvc
+		}
+		if attendanceAlertRepo != nil {
+			dashboardParams.AttendanceAlerts = attendanceAlertRepo
+		}
+		if alertRuleRepo != nil {
+			dashboardParams.AlertRules = alertRuleRepo
+		}
+		if mutationExpiry != nil {
+			dashboardParams.PendingMutations = mutationExpiry
+		}
+		dashboardSvc := service.NewDashboardService(dashboardParams)
+		h.dashboard = internalhandler.NewDashboardHandler(dashboardSvc)
+		if dashboardCache.Enabled() {
+			dashboardWarmer = service.NewDashboardWarmer(dashboardSvc, termRepo, authRepo, service.DashboardWarmerConfig{
+				Interval:     cfg.Dashboard.WarmInterval,
+				ActiveWindow: cfg.Dashboard.WarmActiveWindow,
+				TeacherLimit: cfg.Dashboard.WarmTeacherLimit,
+			}, dashboardLog)
+			dashboardWarmer.Start(a.ctx)
+		}
+	}
+
+	return h, nil
+}
+
+// buildBackups wires the backup endpoints. The manifest lists the files of the other storage
+// directories, which are backed up separately.
+func (a *App) buildBackups(h *handlers) error {
+	cfg := a.cfg.Backups
+	key, err := backup.ParseKey(cfg.EncryptionKey)
+	if err != nil {
+		return fmt.Errorf("invalid BACKUPS_ENCRYPTION_KEY: %w", err)
+	}
+	files, err := storage.NewLocalStorage(cfg.StorageDir)
+	if err != nil {
+		return fmt.Errorf("failed to init backup storage: %w", err)
+	}
+	dumper := backup.Postgres{
+		Database:       a.cfg.Database,
+		Schemas:        cfg.Schemas,
+		DumpCommand:    cfg.DumpCommand,
+		RestoreCommand: cfg.RestoreCommand,
+	}
+	// The endpoints stay up without pg_dump so the failure is visible on every backup, not only in
+	// the startup log.
+	if err := dumper.Available(); err != nil {
+		a.logger.Sugar().Warnw("backups will fail until pg_dump is installed", "error", err)
+	}
+	storageDirs := make(map[string]string)
+	for _, dir := range a.storageDirs() {
+		if dir.Name != service.StorageBackups {
+			storageDirs[dir.Name] = dir.Path
+		}
+	}
+	backupSvc, err := service.NewBackupService(service.BackupServiceParams{
+		Store:   repository.NewBackupRepository(a.db),
+		Dumper:  dumper,
+		Files:   files,
+		Storage: a.storage,
+		Logger:  a.logger.Named("backups"),
+		Config: service.BackupConfig{
+			Key:         key,
+			Database:    a.cfg.Database.Name,
+			Schemas:     cfg.Schemas,
+			StorageDirs: storageDirs,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid backup configuration: %w", err)
+	}
+	backupSvc.FailInterrupted(a.ctx)
+	backupSvc.SetQueue(a.startQueue("backups", backupSvc.Handle, 1, 1))
+	h.backup = internalhandler.NewBackupHandler(backupSvc)
+	return nil
+}
+
+// startQueue starts a job queue that drains until the application is closed.
+func (a *App) startQueue(name string, handler jobs.Handler, workers, retries int) *jobs.Queue {
+	if workers <= 0 {
+		workers = 1
+	}
+	queue := jobs.NewQueue(name, handler, jobs.QueueConfig{
+		Workers:    workers,
+		BufferSize: workers * 4,
+		MaxRetries: retries,
+		RetryDelay: 5 * time.Second,
+		Logger:     a.logger,
+	})
+	queue.Start(a.ctx)
+	a.onClose(func() error {
+		queue.Stop()
+		return nil
+	})
+	a.queues.Register(queue, service.QueueScaling{})
+	return queue
+}
+
+// startPriorityQueue starts a queue that runs higher priority jobs first. Its buffer is deeper than
+// startQueue's so urgent jobs can overtake a backlog rather than wait to be accepted. With queue
+// autoscaling, its worker pool is resized within scaling.
+func (a *App) startPriorityQueue(name string, handler jobs.Handler, workers, retries int, scaling service.QueueScaling) *jobs.PriorityQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+	queue := jobs.NewPriorityQueue(name, handler, jobs.QueueConfig{
+		Workers:    workers,
+		BufferSize: workers * 100,
+		MaxRetries: retries,
+		RetryDelay: 5 * time.Second,
+		Logger:     a.logger,
+	})
+	queue.Start(a.ctx)
+	a.onClose(func() error {
+		queue.Stop()
+		return nil
+	})
+	a.queues.Register(queue, scaling)
+	return queue
+}
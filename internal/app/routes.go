@@ -0,0 +1,95 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+
+	internalmiddleware "github.com/noah-isme/sma-adp-api/internal/middleware"
+	"github.com/noah-isme/sma-adp-api/internal/routes"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// registerRoutes mounts every feature under the configured prefix and reports which were enabled.
+// Features whose handler was not built are skipped. authenticate checks the user token.
+func (a *App) registerRoutes(r *gin.Engine, ops *gin.RouterGroup, h *handlers, authenticate gin.HandlerFunc) []routes.FeatureStatus {
+	api := r.Group(a.cfg.APIPrefix, response.WithCasing(response.Casing(a.cfg.Cutover.ResponseCasing)))
+
+	secured := api.Group("", authenticate)
+	if h.security != nil {
+		secured.Use(internalmiddleware.DenialAudit(h.security))
+	}
+
+	// Term-scoped routes default a missing termId to the active term.
+	resolveTerm := internalmiddleware.ResolveTerm(h.activeTerm)
+	termScoped := secured.Group("", resolveTerm)
+
+	return routes.Mount(
+		routes.Feature{Name: "auth", Enabled: true, Register: func() { routes.RegisterAuth(api, h.authHandler, h.permission, authenticate) }},
+		routes.Feature{Name: "analytics", Enabled: h.analytics != nil, Register: func() {
+			routes.RegisterAnalytics(api.Group("", resolveTerm), h.analytics)
+			routes.RegisterPprof(ops)
+		}},
+		routes.Feature{Name: "search", Enabled: true, Register: func() { routes.RegisterSearch(secured, h.search) }},
+		routes.Feature{Name: "teachers", Enabled: true, Register: func() { routes.RegisterTeachers(secured, h.teacher, h.owners) }},
+		routes.Feature{Name: "guardians", Enabled: true, Register: func() { routes.RegisterGuardians(secured, h.guardian) }},
+		routes.Feature{Name: "student-portal", Enabled: true, Register: func() { routes.RegisterStudentPortal(secured, h.studentPortal) }},
+		routes.Feature{Name: "security", Enabled: h.securityHandler != nil, Register: func() { routes.RegisterSecurity(secured, h.securityHandler) }},
+		routes.Feature{Name: "term-slots", Enabled: true, Register: func() { routes.RegisterTermSlots(secured, h.slotDefinition) }},
+		routes.Feature{Name: "exams", Enabled: true, Register: func() { routes.RegisterExams(secured, h.exam, h.examExport) }},
+		routes.Feature{Name: "curriculum", Enabled: true, Register: func() { routes.RegisterCurriculum(secured, h.curriculum) }},
+		routes.Feature{Name: "lesson-plans", Enabled: true, Register: func() { routes.RegisterLessonPlans(secured, h.lessonPlan) }},
+		routes.Feature{Name: "grades", Enabled: true, Register: func() {
+			routes.RegisterGrades(secured, h.grade)
+			routes.RegisterGradeConfigs(secured, h.gradeConfig, h.gradeComponent)
+		}},
+		routes.Feature{Name: "notifications", Enabled: true, Register: func() { routes.RegisterNotifications(secured, h.notification) }},
+		routes.Feature{Name: "announcements", Enabled: h.announcement != nil, Register: func() { routes.RegisterAnnouncements(secured, h.announcement) }},
+		routes.Feature{Name: "calendar", Enabled: h.calendarAlias != nil, Register: func() { routes.RegisterCalendar(termScoped, h.calendarAlias) }},
+		routes.Feature{Name: "calendar-events", Enabled: true, Register: func() { routes.RegisterCalendarEvents(secured, h.eventRSVP, h.eventExport) }},
+		routes.Feature{Name: "attendance", Enabled: h.attendanceAlias != nil, Register: func() { routes.RegisterAttendance(termScoped, h.attendanceAlias) }},
+		routes.Feature{Name: "attendance-checkin", Enabled: h.attendanceCheckin != nil, Register: func() {
+			routes.RegisterAttendanceCheckin(api, secured, h.attendanceCheckin)
+		}},
+		routes.Feature{Name: "teacher-attendance", Enabled: h.teacherAttendance != nil, Register: func() {
+			routes.RegisterTeacherAttendance(secured, h.teacherAttendance)
+		}},
+		routes.Feature{Name: "teacher-leaves", Enabled: true, Register: func() { routes.RegisterTeacherLeaves(secured, h.teacherLeave) }},
+		routes.Feature{Name: "attendance-imports", Enabled: h.attendanceImport != nil, Register: func() {
+			routes.RegisterAttendanceImports(secured, h.attendanceImport)
+		}},
+		routes.Feature{Name: "attendance-alerts", Enabled: h.attendanceAlert != nil, Register: func() {
+			routes.RegisterAttendanceAlerts(secured, h.attendanceAlert)
+		}},
+		routes.Feature{Name: "alert-rules", Enabled: h.alertRule != nil, Register: func() { routes.RegisterAlertRules(secured, h.alertRule) }},
+		routes.Feature{Name: "absence-messages", Enabled: h.absenceMessage != nil, Register: func() {
+			routes.RegisterAbsenceMessages(api, secured, h.absenceMessage)
+		}},
+		routes.Feature{Name: "configuration", Enabled: h.configuration != nil, Register: func() { routes.RegisterConfiguration(secured, h.configuration) }},
+		routes.Feature{Name: "homerooms", Enabled: h.homeroom != nil, Register: func() { routes.RegisterHomerooms(secured, h.homeroom) }},
+		routes.Feature{Name: "scheduler", Enabled: h.scheduler != nil, Register: func() { routes.RegisterScheduler(secured, h.scheduler, h.scheduleExport, h.scheduleWarning) }},
+		routes.Feature{Name: "schedule-clashes", Enabled: h.scheduleClash != nil, Register: func() {
+			routes.RegisterScheduleClashes(secured, h.scheduleClash, h.clashExport)
+		}},
+		routes.Feature{Name: "class-timetable", Enabled: h.classTimetable != nil, Register: func() {
+			routes.RegisterClassTimetable(termScoped, h.classTimetable)
+		}},
+		routes.Feature{Name: "lesson-groups", Enabled: true, Register: func() { routes.RegisterLessonGroups(secured, h.lessonGroup) }},
+		routes.Feature{Name: "electives", Enabled: true, Register: func() { routes.RegisterElectives(secured, h.elective) }},
+		routes.Feature{Name: "intake", Enabled: true, Register: func() { routes.RegisterIntake(secured, h.intake) }},
+		routes.Feature{Name: "schedule-presets", Enabled: h.subjectLoadPreset != nil, Register: func() { routes.RegisterSubjectLoadPresets(secured, h.subjectLoadPreset) }},
+		routes.Feature{Name: "schedule-preferences", Enabled: h.schedulePreference != nil, Register: func() {
+			routes.RegisterSchedulePreferences(secured, h.schedulePreference)
+		}},
+		routes.Feature{Name: "reports", Enabled: h.report != nil, Register: func() { routes.RegisterReports(secured, h.report, h.exportTemplate) }},
+		routes.Feature{Name: "mutations", Enabled: h.mutation != nil, Register: func() { routes.RegisterMutations(secured, h.mutation) }},
+		routes.Feature{Name: "grade-appeals", Enabled: h.gradeAppeal != nil, Register: func() { routes.RegisterGradeAppeals(secured, h.gradeAppeal) }},
+		routes.Feature{Name: "archives", Enabled: h.archive != nil, Register: func() { routes.RegisterArchives(secured, h.archive, h.archiveRetention) }},
+		routes.Feature{Name: "retention", Enabled: h.retention != nil, Register: func() { routes.RegisterRetention(secured, h.retention) }},
+		routes.Feature{Name: "dashboard", Enabled: h.dashboard != nil, Register: func() { routes.RegisterDashboard(termScoped, h.dashboard) }},
+		routes.Feature{Name: "backups", Enabled: h.backup != nil, Register: func() {
+			routes.RegisterBackups(r.Group("/internal/backups", authenticate), h.backup)
+		}},
+		routes.Feature{Name: "legacy-sync", Enabled: h.legacySync != nil, Register: func() {
+			routes.RegisterLegacySync(r.Group("/internal/sync", internalmiddleware.APIKey(a.cfg.Cutover.SyncAPIKeys)), h.legacySync)
+		}},
+	)
+}
@@ -0,0 +1,33 @@
+package dto
+
+// AlertRuleRequest creates or replaces an alert rule. Rules are enabled unless Enabled is false.
+type AlertRuleRequest struct {
+	Name       string  `json:"name" validate:"required,max=120"`
+	Metric     string  `json:"metric" validate:"required,oneof=CLASS_ATTENDANCE_RATE CLASS_AVERAGE_GRADE"`
+	Comparator string  `json:"comparator" validate:"required,oneof=LT LTE GT GTE"`
+	Threshold  float64 `json:"threshold" validate:"gte=0,lte=100"`
+	Audience   string  `json:"audience" validate:"required,oneof=HOMEROOM TEACHERS ADMINS"`
+	Channel    string  `json:"channel" validate:"required,oneof=DASHBOARD NOTIFICATION"`
+	Enabled    *bool   `json:"enabled"`
+}
+
+// AlertRuleQuery filters the alert rule list.
+type AlertRuleQuery struct {
+	IncludeDisabled bool `form:"includeDisabled"`
+}
+
+// AlertRuleBreachQuery filters open breaches. TermID defaults to the active term.
+type AlertRuleBreachQuery struct {
+	TermID  string `form:"termId"`
+	ClassID string `form:"classId"`
+}
+
+// AlertRuleRun summarises one evaluation of the alert rules.
+type AlertRuleRun struct {
+	TermID   string `json:"termId"`
+	Rules    int    `json:"rules"`
+	Classes  int    `json:"classes"`
+	Open     int    `json:"open"`
+	New      int    `json:"new"`
+	Notified int    `json:"notified"`
+}
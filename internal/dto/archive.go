@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/noah-isme/sma-adp-api/internal/models"
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
 
 // CreateArchiveRequest contains metadata submitted alongside a file upload.
 type CreateArchiveRequest struct {
@@ -18,6 +22,9 @@ type ArchiveFilter struct {
 	Category string
 	TermID   string
 	ClassID  string
+	Search   string
+	Limit    int
+	Offset   int
 }
 
 // ArchiveDownloadResponse enriches metadata with a signed download URL.
@@ -25,3 +32,68 @@ type ArchiveDownloadResponse struct {
 	models.ArchiveItem
 	DownloadURL string `json:"downloadUrl"`
 }
+
+// ArchiveTrashItem is a soft-deleted archive with the time it will be purged for good.
+type ArchiveTrashItem struct {
+	models.ArchiveItem
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// ArchiveRetentionPolicy is the retention period of one archive category.
+type ArchiveRetentionPolicy struct {
+	Category      string `json:"category"`
+	RetentionDays int    `json:"retentionDays"`
+}
+
+// ArchiveExpiringItem is an archive the retention job will soft-delete at ExpiresAt.
+type ArchiveExpiringItem struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Category   string    `json:"category"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	UploadedBy string    `json:"uploadedBy"`
+	UploadedAt time.Time `json:"uploadedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// ArchiveRetentionReport lists the archives expiring within WithinDays so admins can save or
+// re-categorise them before they are deleted.
+type ArchiveRetentionReport struct {
+	GeneratedAt time.Time                `json:"generatedAt"`
+	WithinDays  int                      `json:"withinDays"`
+	GraceDays   int                      `json:"graceDays"`
+	Policies    []ArchiveRetentionPolicy `json:"policies"`
+	Items       []ArchiveExpiringItem    `json:"items"`
+	TotalBytes  int64                    `json:"totalBytes"`
+	// Truncated is set when a category had more expiring archives than the report lists.
+	Truncated bool `json:"truncated"`
+}
+
+// ArchiveRetentionRun summarises one pass of the retention job.
+type ArchiveRetentionRun struct {
+	Expired int `json:"expired"`
+	Purged  int `json:"purged"`
+}
+
+// ArchiveQuotaUsage is the storage taken by one category or uploader. QuotaBytes is zero when the
+// key has no quota.
+type ArchiveQuotaUsage struct {
+	Key         string  `json:"key"`
+	Files       int     `json:"files"`
+	Bytes       int64   `json:"bytes"`
+	QuotaBytes  int64   `json:"quotaBytes"`
+	UsedPercent float64 `json:"usedPercent,omitempty"`
+}
+
+// ArchiveUsageReport summarises archive storage consumption against the configured quotas.
+// Uploaders lists the largest uploaders only.
+type ArchiveUsageReport struct {
+	GeneratedAt               time.Time           `json:"generatedAt"`
+	TotalFiles                int                 `json:"totalFiles"`
+	TotalBytes                int64               `json:"totalBytes"`
+	MaxFileBytes              int64               `json:"maxFileBytes"`
+	DefaultCategoryQuotaBytes int64               `json:"defaultCategoryQuotaBytes"`
+	UploaderQuotaBytes        int64               `json:"uploaderQuotaBytes"`
+	Categories                []ArchiveQuotaUsage `json:"categories"`
+	Uploaders                 []ArchiveQuotaUsage `json:"uploaders"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// SetAttendanceThresholdRequest overrides the minimum attendance percentage of a class for a term.
+type SetAttendanceThresholdRequest struct {
+	ClassID       string  `json:"classId" validate:"required"`
+	TermID        string  `json:"termId" validate:"required"`
+	MinPercentage float64 `json:"minPercentage" validate:"gt=0,lte=100"`
+}
+
+// AttendanceThresholdsResponse lists a term's class overrides next to the default that applies to
+// every other class.
+type AttendanceThresholdsResponse struct {
+	TermID            string                            `json:"termId"`
+	DefaultPercentage float64                           `json:"defaultPercentage"`
+	Classes           []models.AttendanceAlertThreshold `json:"classes"`
+}
+
+// AttendanceAlertQuery filters stored attendance alerts. TermID defaults to the active term.
+type AttendanceAlertQuery struct {
+	TermID  string `form:"termId"`
+	ClassID string `form:"classId"`
+}
+
+// AttendanceAlertRun summarises one evaluation of attendance thresholds.
+type AttendanceAlertRun struct {
+	TermID    string `json:"termId"`
+	Evaluated int    `json:"evaluated"`
+	Open      int    `json:"open"`
+	New       int    `json:"new"`
+	Notified  int    `json:"notified"`
+}
@@ -43,22 +43,112 @@ type AttendanceSummaryScope struct {
 
 // AttendanceSummaryStats aggregates status counts.
 type AttendanceSummaryStats struct {
-	TotalDays      int     `json:"totalDays"`
-	Present        int     `json:"present"`
-	Sick           int     `json:"sick"`
-	Excused        int     `json:"excused"`
-	Absent         int     `json:"absent"`
-	AttendanceRate float64 `json:"attendanceRate"`
+	TotalDays int `json:"totalDays"`
+	Present   int `json:"present"`
+	Sick      int `json:"sick"`
+	Excused   int `json:"excused"`
+	Absent    int `json:"absent"`
+	// SchoolDuty counts the excused days spent on school duty, a subset of Excused.
+	SchoolDuty int `json:"schoolDuty"`
+	// ExcusedAbsences is Sick plus Excused and UnexcusedAbsences equals Absent, matching the
+	// ministry report's split of absences.
+	ExcusedAbsences   int `json:"excusedAbsences"`
+	UnexcusedAbsences int `json:"unexcusedAbsences"`
+	// DocumentedAbsences counts excused absences with at least one linked evidence document.
+	DocumentedAbsences int     `json:"documentedAbsences"`
+	AttendanceRate     float64 `json:"attendanceRate"`
 }
 
-// AttendanceSummaryStudent represents per-student breakdown.
+// AttendanceSummaryStudent represents per-student breakdown, with the same counts as
+// AttendanceSummaryStats.
 type AttendanceSummaryStudent struct {
-	StudentID      string  `json:"studentId"`
-	StudentName    string  `json:"studentName"`
-	ClassID        string  `json:"classId"`
-	Present        int     `json:"present"`
-	Sick           int     `json:"sick"`
-	Excused        int     `json:"excused"`
-	Absent         int     `json:"absent"`
-	AttendanceRate float64 `json:"attendanceRate"`
+	StudentID          string  `json:"studentId"`
+	StudentName        string  `json:"studentName"`
+	ClassID            string  `json:"classId"`
+	Present            int     `json:"present"`
+	Sick               int     `json:"sick"`
+	Excused            int     `json:"excused"`
+	Absent             int     `json:"absent"`
+	SchoolDuty         int     `json:"schoolDuty"`
+	ExcusedAbsences    int     `json:"excusedAbsences"`
+	UnexcusedAbsences  int     `json:"unexcusedAbsences"`
+	DocumentedAbsences int     `json:"documentedAbsences"`
+	AttendanceRate     float64 `json:"attendanceRate"`
+}
+
+// AttendanceMonthlyRequest captures query parameters for /attendance/monthly.
+type AttendanceMonthlyRequest struct {
+	ClassID string
+	TermID  string
+	// Month is formatted YYYY-MM.
+	Month string
+}
+
+// AttendanceMonthlyResponse pivots a class month into one row per student and one cell per day,
+// mirroring the legacy monthly recap page.
+type AttendanceMonthlyResponse struct {
+	ClassID  string                     `json:"classId"`
+	TermID   *string                    `json:"termId,omitempty"`
+	Month    string                     `json:"month"`
+	Dates    []string                   `json:"dates"`
+	Students []AttendanceMonthlyStudent `json:"students"`
+}
+
+// AttendanceMonthlyStudent holds a student's statuses keyed by date (YYYY-MM-DD). Days without a
+// record are omitted.
+type AttendanceMonthlyStudent struct {
+	StudentID   string                  `json:"studentId"`
+	StudentName string                  `json:"studentName"`
+	NIS         string                  `json:"nis"`
+	Days        map[string]string       `json:"days"`
+	Summary     AttendanceLegacySummary `json:"summary"`
+}
+
+// AttendanceLegacySummary uses the legacy API's field names for status counts.
+type AttendanceLegacySummary struct {
+	TotalDays  int     `json:"totalDays"`
+	Present    int     `json:"present"`
+	Sick       int     `json:"sick"`
+	Permission int     `json:"permission"`
+	Absent     int     `json:"absent"`
+	Percentage float64 `json:"percentage"`
+}
+
+// AttendanceStudentRequest captures parameters for /attendance/student/:id.
+type AttendanceStudentRequest struct {
+	StudentID string
+	TermID    string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// AttendanceStudentResponse mirrors the legacy per-student attendance history payload.
+type AttendanceStudentResponse struct {
+	StudentID   string                    `json:"studentId"`
+	StudentName string                    `json:"studentName"`
+	NIS         string                    `json:"nis"`
+	TermID      string                    `json:"termId"`
+	Summary     AttendanceLegacySummary   `json:"summary"`
+	Records     []AttendanceStudentRecord `json:"records"`
+}
+
+// AttendanceStudentRecord is one day of a student's attendance history.
+type AttendanceStudentRecord struct {
+	Date   string `json:"date"`
+	Status string `json:"status"`
+	// Reason is SICK, PERMISSION or SCHOOL_DUTY on S and I days.
+	Reason *string `json:"reason,omitempty"`
+	Notes  *string `json:"notes,omitempty"`
+}
+
+// AttendanceExcuseRequest records the reason for an absence and the archived documents backing it,
+// such as a doctor's note. The status becomes S for SICK and I for PERMISSION and SCHOOL_DUTY.
+type AttendanceExcuseRequest struct {
+	EnrollmentID string  `json:"enrollmentId" validate:"required"`
+	Date         string  `json:"date" validate:"required"`
+	Reason       string  `json:"reason" validate:"required,absence_reason"`
+	Notes        *string `json:"notes"`
+	// EvidenceIDs are STUDENT-scope archive IDs of the same student; they replace earlier links.
+	EvidenceIDs []string `json:"evidenceIds" validate:"max=10,dive,required"`
+	Makeup      bool     `json:"makeup"`
 }
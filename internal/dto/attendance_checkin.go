@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// AttendanceCheckinRequest is posted by gate devices. Exactly one of NIS or QRToken identifies the
+// student; Timestamp defaults to the server time when omitted.
+type AttendanceCheckinRequest struct {
+	NIS       string     `json:"nis,omitempty"`
+	QRToken   string     `json:"qrToken,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// AttendanceCheckinResponse describes the daily attendance row behind a check-in.
+type AttendanceCheckinResponse struct {
+	AttendanceID string                  `json:"attendanceId"`
+	StudentID    string                  `json:"studentId"`
+	EnrollmentID string                  `json:"enrollmentId"`
+	Date         string                  `json:"date"`
+	Status       models.AttendanceStatus `json:"status"`
+	Source       models.AttendanceSource `json:"source"`
+	CheckedInAt  *time.Time              `json:"checkedInAt,omitempty"`
+	Late         bool                    `json:"late"`
+	LateMinutes  int                     `json:"lateMinutes,omitempty"`
+	// Duplicate is true when the student was already marked for the day; the stored row is returned.
+	Duplicate bool     `json:"duplicate"`
+	Device    string   `json:"device"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// AttendanceQRTokenResponse carries a signed token to print on a student card.
+type AttendanceQRTokenResponse struct {
+	StudentID string    `json:"studentId"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
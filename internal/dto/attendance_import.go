@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// AttendanceImportJobResponse is returned after an import is accepted.
+type AttendanceImportJobResponse struct {
+	ID        string              `json:"id"`
+	Status    models.ReportStatus `json:"status"`
+	Progress  int                 `json:"progress"`
+	TotalRows int                 `json:"totalRows"`
+}
+
+// AttendanceImportStatusResponse exposes import progress and per-row conflicts.
+type AttendanceImportStatusResponse struct {
+	ID         string                            `json:"id"`
+	Status     models.ReportStatus               `json:"status"`
+	Progress   int                               `json:"progress"`
+	TotalRows  int                               `json:"totalRows"`
+	Processed  int                               `json:"processed"`
+	Success    int                               `json:"success"`
+	Conflicts  []models.AttendanceImportConflict `json:"conflicts"`
+	Warnings   []string                          `json:"warnings,omitempty"`
+	Error      *string                           `json:"error,omitempty"`
+	CreatedAt  time.Time                         `json:"createdAt"`
+	FinishedAt *time.Time                        `json:"finishedAt,omitempty"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// AttendancePartitionRun summarises one pass of the attendance partition maintenance job.
+type AttendancePartitionRun struct {
+	Created  []string `json:"created"`
+	Archived []string `json:"archived"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+// CurriculumTopicRequest creates a syllabus topic for a subject in a term.
+type CurriculumTopicRequest struct {
+	SubjectID   string  `json:"subjectId" validate:"required"`
+	TermID      string  `json:"termId" validate:"required"`
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description"`
+	PlannedWeek int     `json:"plannedWeek" validate:"required,min=1,max=53"`
+}
+
+// CurriculumTopicUpdateRequest edits a topic; its subject and term are fixed.
+type CurriculumTopicUpdateRequest struct {
+	Title       string  `json:"title" validate:"required,max=200"`
+	Description *string `json:"description"`
+	PlannedWeek int     `json:"plannedWeek" validate:"required,min=1,max=53"`
+}
+
+// CurriculumProgressRequest marks a topic as taught to a class. TaughtOn defaults to today.
+type CurriculumProgressRequest struct {
+	ClassID  string  `json:"classId" validate:"required"`
+	TaughtOn string  `json:"taughtOn"`
+	Notes    *string `json:"notes"`
+}
+
+// CurriculumCoverageQuery filters the coverage report. Date defaults to today.
+type CurriculumCoverageQuery struct {
+	TermID    string `form:"termId" validate:"required"`
+	ClassID   string `form:"classId"`
+	SubjectID string `form:"subjectId"`
+	TeacherID string `form:"teacherId"`
+	Date      string `form:"date"`
+}
+
+// CurriculumCoverage compares planned and taught topics of one class/subject assignment.
+type CurriculumCoverage struct {
+	ClassID           string  `json:"classId"`
+	SubjectID         string  `json:"subjectId"`
+	TeacherID         string  `json:"teacherId"`
+	TotalTopics       int     `json:"totalTopics"`
+	PlannedToDate     int     `json:"plannedToDate"`
+	Taught            int     `json:"taught"`
+	Behind            int     `json:"behind"`
+	CoveragePercent   float64 `json:"coveragePercent"`
+	CompletionPercent float64 `json:"completionPercent"`
+	Status            string  `json:"status"`
+}
+
+// CurriculumCoverageResponse is the coverage report of a term as of a given week.
+type CurriculumCoverageResponse struct {
+	TermID string               `json:"termId"`
+	Date   string               `json:"date"`
+	Week   int                  `json:"week"`
+	Items  []CurriculumCoverage `json:"items"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // AdminDashboardResponse captures the aggregated admin dashboard payload.
 type AdminDashboardResponse struct {
 	TermID     string                   `json:"termId"`
@@ -7,6 +9,8 @@ type AdminDashboardResponse struct {
 	Grades     AdminGradesSection       `json:"grades"`
 	Behavior   AdminBehaviorSection     `json:"behavior"`
 	Ops        AdminOperationsHighlight `json:"ops"`
+	// Alerts lists the classes breaching an alert rule.
+	Alerts []DashboardRuleAlert `json:"alerts"`
 }
 
 // AdminAttendanceSection summarises attendance for admin dashboard.
@@ -55,6 +59,43 @@ type BehaviorLeaderboardEntry struct {
 type AdminOperationsHighlight struct {
 	UpcomingEvents    []OpsEvent `json:"upcomingEvents"`
 	OpenAnnouncements int        `json:"openAnnouncements"`
+	// TeacherPresence is omitted when teacher clock-in is disabled.
+	TeacherPresence *TeacherPresenceStats `json:"teacherPresence,omitempty"`
+	// PendingMutations is omitted when the mutation workflow is disabled.
+	PendingMutations *PendingMutationStats `json:"pendingMutations,omitempty"`
+	// UnacknowledgedAnnouncements lists active announcements still awaiting acknowledgements.
+	UnacknowledgedAnnouncements []UnacknowledgedAnnouncement `json:"unacknowledgedAnnouncements,omitempty"`
+}
+
+// UnacknowledgedAnnouncement counts the pending acknowledgements of an announcement.
+type UnacknowledgedAnnouncement struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Priority     string    `json:"priority"`
+	PublishedAt  time.Time `json:"publishedAt"`
+	Recipients   int       `json:"recipients"`
+	Acknowledged int       `json:"acknowledged"`
+	Pending      int       `json:"pending"`
+}
+
+// PendingMutationStats describes the backlog of change requests awaiting review. Overdue counts the
+// requests pending longer than the reminder threshold.
+type PendingMutationStats struct {
+	Pending           int        `json:"pending"`
+	Overdue           int        `json:"overdue"`
+	Escalated         int        `json:"escalated"`
+	OldestRequestedAt *time.Time `json:"oldestRequestedAt,omitempty"`
+	OldestAgeDays     int        `json:"oldestAgeDays"`
+}
+
+// TeacherPresenceStats counts today's teacher clock-ins.
+type TeacherPresenceStats struct {
+	Date           string `json:"date"`
+	ActiveTeachers int    `json:"activeTeachers"`
+	CheckedIn      int    `json:"checkedIn"`
+	NotCheckedIn   int    `json:"notCheckedIn"`
+	Late           int    `json:"late"`
+	CheckedOut     int    `json:"checkedOut"`
 }
 
 // OpsEvent is a simplified calendar event for the dashboard.
@@ -70,6 +111,8 @@ type TeacherDashboardResponse struct {
 	Today     TeacherScheduleSummary `json:"today"`
 	Classes   []TeacherClassSummary  `json:"classes"`
 	Alerts    TeacherAlerts          `json:"alerts"`
+	// Curriculum holds syllabus coverage per assigned class and subject, when available.
+	Curriculum []CurriculumCoverage `json:"curriculum,omitempty"`
 }
 
 // TeacherScheduleSummary outlines today's schedule.
@@ -83,6 +126,7 @@ type TeacherScheduleSlot struct {
 	ClassID   string  `json:"classId"`
 	SubjectID string  `json:"subjectId"`
 	TimeSlot  int     `json:"timeSlot"`
+	TimeLabel string  `json:"timeLabel,omitempty"`
 	Room      *string `json:"room"`
 }
 
@@ -97,4 +141,31 @@ type TeacherClassSummary struct {
 type TeacherAlerts struct {
 	LowAttendanceClasses []string `json:"lowAttendanceClasses"`
 	GradeOutliers        []string `json:"gradeOutliers"`
+	// LowAttendanceStudents lists students below their class threshold at the last nightly
+	// evaluation, when attendance alerts are enabled.
+	LowAttendanceStudents []TeacherAttendanceAlert `json:"lowAttendanceStudents,omitempty"`
+	// Rules lists the teacher's classes breaching an alert rule addressed to teachers.
+	Rules []DashboardRuleAlert `json:"rules"`
+}
+
+// DashboardRuleAlert is a class whose metric crosses an alert rule's threshold.
+type DashboardRuleAlert struct {
+	RuleID     string  `json:"ruleId"`
+	RuleName   string  `json:"ruleName"`
+	Metric     string  `json:"metric"`
+	Comparator string  `json:"comparator"`
+	ClassID    string  `json:"classId"`
+	Value      float64 `json:"value"`
+	Threshold  float64 `json:"threshold"`
+	Since      string  `json:"since,omitempty"`
+}
+
+// TeacherAttendanceAlert is a student whose attendance is below the class threshold.
+type TeacherAttendanceAlert struct {
+	StudentID   string  `json:"studentId"`
+	StudentName string  `json:"studentName"`
+	ClassID     string  `json:"classId"`
+	Percentage  float64 `json:"percentage"`
+	Threshold   float64 `json:"threshold"`
+	Since       string  `json:"since"`
 }
@@ -0,0 +1,90 @@
+package dto
+
+// ElectiveSectionRequest describes a section of an offering. Sections keep their ID on update;
+// sections without one are added.
+type ElectiveSectionRequest struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name" validate:"required,max=60"`
+	TeacherID string  `json:"teacherId" validate:"required"`
+	Capacity  int     `json:"capacity" validate:"required,min=1"`
+	Room      *string `json:"room" validate:"omitempty,max=64"`
+}
+
+// ElectiveOfferingRequest creates or updates an elective offering. The term, subject and category
+// are fixed once the offering exists and are ignored on update.
+type ElectiveOfferingRequest struct {
+	TermID      string                   `json:"termId" validate:"required"`
+	SubjectID   string                   `json:"subjectId" validate:"required"`
+	Category    string                   `json:"category" validate:"required,max=40"`
+	Name        string                   `json:"name" validate:"required,max=120"`
+	Description *string                  `json:"description" validate:"omitempty,max=1000"`
+	Sections    []ElectiveSectionRequest `json:"sections" validate:"required,min=1,dive"`
+}
+
+// ElectiveOfferingQuery filters offering listings.
+type ElectiveOfferingQuery struct {
+	TermID   string `form:"termId" validate:"required"`
+	Category string `form:"category"`
+}
+
+// ElectiveSelectionRequest ranks offerings of one category, first choice first. Students submit their
+// own choices; administrators name the enrollment.
+type ElectiveSelectionRequest struct {
+	TermID       string   `json:"termId" validate:"required"`
+	Category     string   `json:"category" validate:"required"`
+	EnrollmentID string   `json:"enrollmentId"`
+	OfferingIDs  []string `json:"offeringIds" validate:"required,min=1,dive,required"`
+}
+
+// ElectiveSelectionQuery filters selection listings. EnrollmentID is ignored for students.
+type ElectiveSelectionQuery struct {
+	TermID       string `form:"termId" validate:"required"`
+	Category     string `form:"category"`
+	EnrollmentID string `form:"enrollmentId"`
+}
+
+// ElectiveAllocationRequest allocates the students who ranked offerings of a category. DryRun
+// returns the allocation without storing it.
+type ElectiveAllocationRequest struct {
+	TermID   string `json:"termId" validate:"required"`
+	Category string `json:"category" validate:"required"`
+	DryRun   bool   `json:"dryRun"`
+}
+
+// ElectiveAllocationQuery selects the stored allocation to report.
+type ElectiveAllocationQuery struct {
+	TermID   string `form:"termId" validate:"required"`
+	Category string `form:"category" validate:"required"`
+}
+
+// ElectiveAllocationStudent is a student with the rank of the offering they were placed in.
+type ElectiveAllocationStudent struct {
+	EnrollmentID string `json:"enrollmentId"`
+	StudentID    string `json:"studentId"`
+	ClassID      string `json:"classId"`
+	Rank         int    `json:"rank,omitempty"`
+}
+
+// ElectiveSectionAllocation lists the students placed in one section.
+type ElectiveSectionAllocation struct {
+	OfferingID    string                      `json:"offeringId"`
+	OfferingName  string                      `json:"offeringName"`
+	SectionID     string                      `json:"sectionId"`
+	SectionName   string                      `json:"sectionName"`
+	LessonGroupID string                      `json:"lessonGroupId,omitempty"`
+	Capacity      int                         `json:"capacity"`
+	Students      []ElectiveAllocationStudent `json:"students"`
+}
+
+// ElectiveAllocationReport summarises an allocation. ChoiceCounts counts the students placed by the
+// rank of their choice; Unallocated lists those none of whose choices had room.
+type ElectiveAllocationReport struct {
+	TermID       string                      `json:"termId"`
+	Category     string                      `json:"category"`
+	Students     int                         `json:"students"`
+	Allocated    int                         `json:"allocated"`
+	ChoiceCounts map[int]int                 `json:"choiceCounts"`
+	Sections     []ElectiveSectionAllocation `json:"sections"`
+	Unallocated  []ElectiveAllocationStudent `json:"unallocated"`
+	DryRun       bool                        `json:"dryRun,omitempty"`
+}
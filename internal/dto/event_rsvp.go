@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// EventRSVPOptionsRequest opens or closes RSVPs for a calendar event.
+type EventRSVPOptionsRequest struct {
+	Enabled bool `json:"enabled"`
+	// Capacity limits GOING answers; omit it for unlimited seats.
+	Capacity *int `json:"capacity" validate:"omitempty,min=1"`
+}
+
+// EventRSVPRequest answers a calendar event invitation.
+type EventRSVPRequest struct {
+	Response string  `json:"response" validate:"required,oneof=GOING NOT_GOING"`
+	Note     *string `json:"note" validate:"omitempty,max=500"`
+}
+
+// EventCheckInRequest records an attendee at the door.
+type EventCheckInRequest struct {
+	UserID string `json:"userId" validate:"required"`
+}
+
+// EventRSVPReport lists the RSVPs and check-ins of an event with their counts.
+type EventRSVPReport struct {
+	EventID     string `json:"eventId"`
+	Title       string `json:"title"`
+	RSVPEnabled bool   `json:"rsvpEnabled"`
+	Capacity    *int   `json:"capacity,omitempty"`
+	// SeatsLeft is omitted for events without a capacity.
+	SeatsLeft *int               `json:"seatsLeft,omitempty"`
+	Going     int                `json:"going"`
+	NotGoing  int                `json:"notGoing"`
+	WalkIns   int                `json:"walkIns"`
+	CheckedIn int                `json:"checkedIn"`
+	RSVPs     []models.EventRSVP `json:"rsvps"`
+}
+
+// EventAttendanceExportRequest selects the format of an event attendance export.
+type EventAttendanceExportRequest struct {
+	Format string `form:"format" json:"format" validate:"omitempty,oneof=pdf xlsx"`
+}
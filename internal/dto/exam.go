@@ -0,0 +1,69 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// ExamPeriodRequest creates an exam period within a term.
+type ExamPeriodRequest struct {
+	TermID    string `json:"termId" validate:"required"`
+	Name      string `json:"name" validate:"required,max=100"`
+	StartDate string `json:"startDate" validate:"required"`
+	EndDate   string `json:"endDate" validate:"required"`
+}
+
+// ExamScheduleRequest places a single sitting in an exam period.
+type ExamScheduleRequest struct {
+	ClassID       string `json:"classId" validate:"required"`
+	SubjectID     string `json:"subjectId" validate:"required"`
+	Date          string `json:"date" validate:"required"`
+	StartSlot     int    `json:"startSlot" validate:"required,min=1,max=20"`
+	EndSlot       int    `json:"endSlot" validate:"required,min=1,max=20,gtefield=StartSlot"`
+	Room          string `json:"room" validate:"required,max=50"`
+	InvigilatorID string `json:"invigilatorId" validate:"required"`
+}
+
+// ExamScheduleQuery filters the sittings of an exam period.
+type ExamScheduleQuery struct {
+	ClassID       string `form:"classId"`
+	Room          string `form:"room"`
+	InvigilatorID string `form:"invigilatorId"`
+	Date          string `form:"date"`
+}
+
+// ExamSessionRequest is one exam session of the day, e.g. slots 1-2.
+type ExamSessionRequest struct {
+	StartSlot int `json:"startSlot" validate:"required,min=1,max=20"`
+	EndSlot   int `json:"endSlot" validate:"required,min=1,max=20,gtefield=StartSlot"`
+}
+
+// ExamGenerateRequest asks the generator to lay out every pending subject exam of the classes over
+// the period. Without SubjectIDs each class sits the subjects of its regular timetable for the term.
+type ExamGenerateRequest struct {
+	ClassIDs       []string             `json:"classIds" validate:"required,min=1,dive,required"`
+	SubjectIDs     []string             `json:"subjectIds" validate:"omitempty,dive,required"`
+	Sessions       []ExamSessionRequest `json:"sessions" validate:"required,min=1,dive"`
+	Rooms          []string             `json:"rooms" validate:"required,min=1,dive,required,max=50"`
+	InvigilatorIDs []string             `json:"invigilatorIds" validate:"required,min=1,dive,required"`
+	Days           []int                `json:"days" validate:"omitempty,dive,min=1,max=7"`
+}
+
+// ExamUnplaced reports a class/subject exam the generator could not fit.
+type ExamUnplaced struct {
+	ClassID   string `json:"classId"`
+	SubjectID string `json:"subjectId"`
+	Reason    string `json:"reason"`
+}
+
+// ExamGenerateResponse lists the sittings created by a generation run.
+type ExamGenerateResponse struct {
+	PeriodID string                `json:"periodId"`
+	Created  []models.ExamSchedule `json:"created"`
+	Unplaced []ExamUnplaced        `json:"unplaced"`
+}
+
+// ExamExportRequest selects the exam timetable to render: one class, or one room.
+type ExamExportRequest struct {
+	Format  string `form:"format" json:"format" validate:"omitempty,oneof=pdf xlsx"`
+	View    string `form:"view" json:"view" validate:"omitempty,oneof=class room"`
+	ClassID string `form:"classId" json:"classId" validate:"required_if=View class"`
+	Room    string `form:"room" json:"room" validate:"required_if=View room"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// ExportTemplateColumn selects a report column; Label overrides its default header.
+type ExportTemplateColumn struct {
+	Key   string `json:"key" validate:"required"`
+	Label string `json:"label,omitempty" validate:"max=100"`
+}
+
+// ExportTemplateRequest creates or replaces the export layout of a report type. Columns are
+// exported in the given order and GroupBy, when set, keeps rows with the same value together.
+type ExportTemplateRequest struct {
+	Type    models.ReportType      `json:"type" validate:"required"`
+	Name    string                 `json:"name" validate:"required,max=100"`
+	Columns []ExportTemplateColumn `json:"columns" validate:"required,min=1,dive"`
+	GroupBy *string                `json:"groupBy,omitempty"`
+}
+
+// ExportReportColumns lists the columns a report type offers to export templates, with their
+// default headers.
+type ExportReportColumns struct {
+	Type    models.ReportType      `json:"type"`
+	Columns []ExportTemplateColumn `json:"columns"`
+}
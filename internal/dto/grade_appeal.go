@@ -0,0 +1,33 @@
+package dto
+
+// GradeAppealRequest disputes the student's final grade in one subject and term.
+type GradeAppealRequest struct {
+	SubjectID      string  `json:"subjectId" validate:"required"`
+	TermID         string  `json:"termId" validate:"required"`
+	RequestedGrade float64 `json:"requestedGrade" validate:"gte=0,lte=100"`
+	Reason         string  `json:"reason" validate:"required,max=2000"`
+}
+
+// GradeAppealResponseRequest is the subject teacher's recommendation on an appeal. ProposedGrade is
+// the grade the teacher supports, defaulting to the requested one; Note is required when opposing.
+type GradeAppealResponseRequest struct {
+	Recommendation string   `json:"recommendation" validate:"required,oneof=SUPPORT OPPOSE"`
+	ProposedGrade  *float64 `json:"proposedGrade" validate:"omitempty,gte=0,lte=100"`
+	Note           *string  `json:"note" validate:"omitempty,max=2000"`
+}
+
+// GradeAppealQuery filters appeal listings. StudentID is required for guardians and ignored for
+// students and teachers.
+type GradeAppealQuery struct {
+	StudentID string `form:"studentId"`
+	TeacherID string `form:"teacherId"`
+	Status    string `form:"status" validate:"omitempty,oneof=TEACHER_REVIEW ADMIN_REVIEW APPROVED REJECTED"`
+}
+
+// GradeAppealSweep reports what one pass over the appeal deadlines did.
+type GradeAppealSweep struct {
+	Forwarded int `json:"forwarded"`
+	Filed     int `json:"filed"`
+	Escalated int `json:"escalated"`
+	Announced int `json:"announced"`
+}
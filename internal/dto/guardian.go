@@ -0,0 +1,15 @@
+package dto
+
+// LinkGuardianStudentRequest attaches a student to a guardian account.
+type LinkGuardianStudentRequest struct {
+	StudentID    string `json:"studentId"`
+	Relationship string `json:"relationship,omitempty"`
+}
+
+// GuardianContactRequest sets the phone a guardian is messaged on. Channel defaults to SMS and
+// absence messages default to on.
+type GuardianContactRequest struct {
+	Phone         string `json:"phone"`
+	Channel       string `json:"channel,omitempty"`
+	AbsenceAlerts *bool  `json:"absenceAlerts,omitempty"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// Intake placement decisions.
+const (
+	IntakeDecisionAccept   = "ACCEPT"
+	IntakeDecisionWaitlist = "WAITLIST"
+	IntakeDecisionReject   = "REJECT"
+)
+
+// IntakeApplicationRequest creates or updates a transfer-in application. The term is fixed once the
+// application exists and is ignored on update.
+type IntakeApplicationRequest struct {
+	TermID           string    `json:"termId" validate:"required"`
+	FullName         string    `json:"fullName" validate:"required,max=255"`
+	NISN             *string   `json:"nisn" validate:"omitempty,len=10,numeric"`
+	Gender           string    `json:"gender" validate:"required,max=10"`
+	BirthDate        time.Time `json:"birthDate" validate:"required"`
+	Address          string    `json:"address"`
+	Phone            string    `json:"phone" validate:"max=32"`
+	PreviousSchool   string    `json:"previousSchool" validate:"required,max=255"`
+	RequestedClassID *string   `json:"requestedClassId"`
+}
+
+// IntakeQuery filters application listings.
+type IntakeQuery struct {
+	TermID string `form:"termId" validate:"required"`
+	Status string `form:"status"`
+}
+
+// IntakeDecisionRequest places, waitlists or rejects an application. Accepting needs the class the
+// student joins and the NIS they are registered under.
+type IntakeDecisionRequest struct {
+	Decision string  `json:"decision" validate:"required,oneof=ACCEPT WAITLIST REJECT"`
+	ClassID  string  `json:"classId" validate:"required_if=Decision ACCEPT"`
+	NIS      string  `json:"nis" validate:"required_if=Decision ACCEPT"`
+	Note     *string `json:"note" validate:"omitempty,max=1000"`
+}
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// LegacyAttendanceSyncRequest is the daily attendance payload the legacy app pushes to
+// POST /internal/sync/attendance.
+type LegacyAttendanceSyncRequest struct {
+	Records []LegacyAttendanceRecord `json:"records" validate:"required,min=1,max=1000,dive"`
+}
+
+// LegacyAttendanceRecord is one daily mark in the legacy format. Status accepts the legacy codes
+// (H, S, I, A) as well as their names (hadir/present, sakit/sick, izin/permission, alpa/absent).
+type LegacyAttendanceRecord struct {
+	StudentID string  `json:"studentId" validate:"required"`
+	TermID    string  `json:"termId" validate:"required"`
+	Date      string  `json:"date" validate:"required"`
+	Status    string  `json:"status" validate:"required"`
+	Notes     *string `json:"notes,omitempty"`
+}
+
+// LegacyGradeSyncRequest is the grade payload the legacy app pushes to POST /internal/sync/grades.
+type LegacyGradeSyncRequest struct {
+	Records []LegacyGradeRecord `json:"records" validate:"required,min=1,max=1000,dive"`
+}
+
+// LegacyGradeRecord is one component score in the legacy format. Component is the grade
+// component code.
+type LegacyGradeRecord struct {
+	StudentID string  `json:"studentId" validate:"required"`
+	TermID    string  `json:"termId" validate:"required"`
+	SubjectID string  `json:"subjectId" validate:"required"`
+	Component string  `json:"component" validate:"required"`
+	Score     float64 `json:"score" validate:"gte=0,lte=100"`
+}
+
+// LegacySyncResult reports how a batch was applied. Replayed is set when the idempotency key was
+// seen before and the stored outcome is returned without applying the records again.
+type LegacySyncResult struct {
+	Kind           models.LegacySyncKind      `json:"kind"`
+	IdempotencyKey string                     `json:"idempotencyKey"`
+	Received       int                        `json:"received"`
+	Applied        int                        `json:"applied"`
+	Failed         int                        `json:"failed"`
+	Failures       []models.LegacySyncFailure `json:"failures"`
+	Replayed       bool                       `json:"replayed,omitempty"`
+}
+
+// LegacyReconciliationQuery bounds the reconciliation report by receive date (YYYY-MM-DD,
+// inclusive). It defaults to the last seven days.
+type LegacyReconciliationQuery struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// LegacyReconciliationReport totals the batches received from the legacy app and lists every
+// record that was rejected, so they can be corrected and resent.
+type LegacyReconciliationReport struct {
+	From     string                        `json:"from"`
+	To       string                        `json:"to"`
+	Totals   []LegacySyncTotals            `json:"totals"`
+	Failures []LegacyReconciliationFailure `json:"failures"`
+}
+
+// LegacySyncTotals aggregates the batches of one kind.
+type LegacySyncTotals struct {
+	Kind     models.LegacySyncKind `json:"kind"`
+	Batches  int                   `json:"batches"`
+	Received int                   `json:"received"`
+	Applied  int                   `json:"applied"`
+	Failed   int                   `json:"failed"`
+}
+
+// LegacyReconciliationFailure is a rejected record with the batch it arrived in.
+type LegacyReconciliationFailure struct {
+	Kind           models.LegacySyncKind `json:"kind"`
+	IdempotencyKey string                `json:"idempotencyKey"`
+	ReceivedAt     time.Time             `json:"receivedAt"`
+	Index          int                   `json:"index"`
+	StudentID      string                `json:"studentId"`
+	Reason         string                `json:"reason"`
+}
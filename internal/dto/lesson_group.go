@@ -0,0 +1,83 @@
+package dto
+
+// LessonGroupMemberRequest names a member class. EnrollmentIDs limits the group to those students of
+// the class; when empty the whole class takes part.
+type LessonGroupMemberRequest struct {
+	ClassID       string   `json:"classId" validate:"required"`
+	EnrollmentIDs []string `json:"enrollmentIds" validate:"omitempty,dive,required"`
+}
+
+// LessonGroupRequest creates or updates a lesson group. The term, subject and teacher are fixed once
+// the group exists and are ignored on update.
+type LessonGroupRequest struct {
+	TermID    string                     `json:"termId" validate:"required"`
+	SubjectID string                     `json:"subjectId" validate:"required"`
+	TeacherID string                     `json:"teacherId" validate:"required"`
+	Name      string                     `json:"name" validate:"required,max=120"`
+	Room      *string                    `json:"room" validate:"omitempty,max=64"`
+	Members   []LessonGroupMemberRequest `json:"members" validate:"required,min=2,dive"`
+}
+
+// LessonGroupSessionRequest places a weekly session of a lesson group, or names the one to remove
+// in the query. Room defaults to the group's room.
+type LessonGroupSessionRequest struct {
+	DayOfWeek string `json:"dayOfWeek" form:"dayOfWeek" validate:"required"`
+	TimeSlot  string `json:"timeSlot" form:"timeSlot" validate:"required"`
+	Room      string `json:"room" form:"-"`
+}
+
+// LessonGroupSession is one weekly session with the schedule created for each member class.
+type LessonGroupSession struct {
+	DayOfWeek   string            `json:"dayOfWeek"`
+	TimeSlot    string            `json:"timeSlot"`
+	Room        string            `json:"room"`
+	ScheduleIDs map[string]string `json:"scheduleIds"`
+}
+
+// LessonGroupStudent is a student taking part in a lesson group.
+type LessonGroupStudent struct {
+	EnrollmentID string `json:"enrollmentId"`
+	StudentID    string `json:"studentId"`
+	ClassID      string `json:"classId"`
+}
+
+// LessonGroupAttendanceItem is one student's mark for a lesson group session.
+type LessonGroupAttendanceItem struct {
+	EnrollmentID string  `json:"enrollmentId" validate:"required"`
+	Status       string  `json:"status" validate:"required"`
+	Notes        *string `json:"notes"`
+}
+
+// LessonGroupAttendanceRequest marks attendance for the session held on Date in TimeSlot.
+type LessonGroupAttendanceRequest struct {
+	Date     string                      `json:"date" validate:"required"`
+	TimeSlot string                      `json:"timeSlot" validate:"required"`
+	Mode     string                      `json:"mode" validate:"omitempty,oneof=atomic partialOnError"`
+	Makeup   bool                        `json:"makeup"`
+	Items    []LessonGroupAttendanceItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// LessonGroupAttendanceQuery selects the session an attendance report covers.
+type LessonGroupAttendanceQuery struct {
+	Date     string `form:"date" validate:"required"`
+	TimeSlot string `form:"timeSlot" validate:"required"`
+}
+
+// LessonGroupAttendanceRow is a student's mark in a session; Status is empty while unmarked.
+type LessonGroupAttendanceRow struct {
+	EnrollmentID string  `json:"enrollmentId"`
+	StudentID    string  `json:"studentId"`
+	StudentName  string  `json:"studentName,omitempty"`
+	ClassID      string  `json:"classId"`
+	ScheduleID   string  `json:"scheduleId"`
+	Status       string  `json:"status,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+// LessonGroupAttendanceReport lists every student of a session with their mark.
+type LessonGroupAttendanceReport struct {
+	GroupID  string                     `json:"groupId"`
+	Date     string                     `json:"date"`
+	TimeSlot string                     `json:"timeSlot"`
+	Rows     []LessonGroupAttendanceRow `json:"rows"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// LessonPlanRequest creates a weekly lesson plan. WeekStart may be any date of the week; it is
+// normalised to that week's Monday.
+type LessonPlanRequest struct {
+	ClassID    string  `json:"classId" validate:"required"`
+	SubjectID  string  `json:"subjectId" validate:"required"`
+	TermID     string  `json:"termId" validate:"required"`
+	WeekStart  string  `json:"weekStart" validate:"required"`
+	Title      string  `json:"title" validate:"required,max=200"`
+	Objectives *string `json:"objectives"`
+	Activities *string `json:"activities"`
+	Assessment *string `json:"assessment"`
+}
+
+// LessonPlanUpdateRequest edits the content of a draft or rejected plan.
+type LessonPlanUpdateRequest struct {
+	Title      string  `json:"title" validate:"required,max=200"`
+	Objectives *string `json:"objectives"`
+	Activities *string `json:"activities"`
+	Assessment *string `json:"assessment"`
+}
+
+// LessonPlanQuery filters lesson plan listings. Teachers only ever see their own plans.
+type LessonPlanQuery struct {
+	TeacherID string `form:"teacherId"`
+	ClassID   string `form:"classId"`
+	SubjectID string `form:"subjectId"`
+	TermID    string `form:"termId"`
+	Status    string `form:"status"`
+	Week      string `form:"week"`
+}
+
+// ReviewLessonPlanRequest captures the reviewer decision and an optional note.
+type ReviewLessonPlanRequest struct {
+	Status models.LessonPlanStatus `json:"status"`
+	Note   string                  `json:"note"`
+}
+
+// NotificationQuery filters the caller's notifications.
+type NotificationQuery struct {
+	UnreadOnly bool `form:"unread"`
+	Limit      int  `form:"limit"`
+}
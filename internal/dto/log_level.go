@@ -0,0 +1,19 @@
+package dto
+
+// LogLevelRequest sets the level of a module, or the default level when Module is empty.
+type LogLevelRequest struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+
+// LogLevelsResponse lists the default log level and the per-module overrides.
+type LogLevelsResponse struct {
+	Default string           `json:"default"`
+	Modules []ModuleLogLevel `json:"modules"`
+}
+
+// ModuleLogLevel is the level one module logs at.
+type ModuleLogLevel struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
@@ -27,3 +27,10 @@ type MutationQuery struct {
 	Entity string
 	Type   models.MutationType
 }
+
+// MutationExpiryRun summarises one pass of the mutation expiry job.
+type MutationExpiryRun struct {
+	Reminded  int `json:"reminded"`
+	Expired   int `json:"expired"`
+	Escalated int `json:"escalated"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// RegisterDeviceRequest registers the caller's device for push notifications.
+type RegisterDeviceRequest struct {
+	Token    string `json:"token"`
+	Platform string `json:"platform"`
+}
+
+// BroadcastAnnouncementRequest publishes an announcement and notifies every active user holding
+// one of Roles.
+type BroadcastAnnouncementRequest struct {
+	Title     string            `json:"title"`
+	Content   string            `json:"content"`
+	Roles     []models.UserRole `json:"roles"`
+	Priority  string            `json:"priority"`
+	IsPinned  bool              `json:"isPinned"`
+	ExpiresAt *time.Time        `json:"expiresAt"`
+	// RequiresAck asks recipients to acknowledge the announcement; pending acknowledgements show on
+	// the admin dashboard.
+	RequiresAck bool `json:"requiresAck"`
+}
+
+// BroadcastAnnouncementResult reports the stored announcement and how many users were notified.
+type BroadcastAnnouncementResult struct {
+	Announcement *models.Announcement `json:"announcement"`
+	Recipients   int                  `json:"recipients"`
+}
+
+// AnnouncementAck confirms that the caller acknowledged an announcement.
+type AnnouncementAck struct {
+	AnnouncementID string    `json:"announcementId"`
+	AcknowledgedAt time.Time `json:"acknowledgedAt"`
+}
+
+// AnnouncementRecipientStatus is one recipient of an announcement in an acknowledgement report.
+type AnnouncementRecipientStatus struct {
+	UserID         string          `json:"userId"`
+	FullName       string          `json:"fullName"`
+	Role           models.UserRole `json:"role"`
+	AcknowledgedAt *time.Time      `json:"acknowledgedAt,omitempty"`
+}
+
+// AnnouncementAckReport lists who acknowledged an announcement and who has not yet. The lists are
+// capped and Truncated set when recipients were left out; the counts always cover everyone.
+type AnnouncementAckReport struct {
+	AnnouncementID string                        `json:"announcementId"`
+	Title          string                        `json:"title"`
+	RequiresAck    bool                          `json:"requiresAck"`
+	Recipients     int                           `json:"recipients"`
+	Acknowledged   int                           `json:"acknowledged"`
+	Pending        int                           `json:"pending"`
+	Acknowledgers  []AnnouncementRecipientStatus `json:"acknowledgers"`
+	Missing        []AnnouncementRecipientStatus `json:"missing"`
+	Truncated      bool                          `json:"truncated"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// Permission is one route the caller may use.
+type Permission struct {
+	Method string                 `json:"method"`
+	Path   string                 `json:"path"`
+	Scope  models.PermissionScope `json:"scope"`
+}
+
+// PermissionsResponse lists the routes the current token may use, relative to the API prefix.
+type PermissionsResponse struct {
+	UserID      string          `json:"user_id"`
+	Role        models.UserRole `json:"role"`
+	Permissions []Permission    `json:"permissions"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// QueueStatus reports the backlog and throughput of one background job queue together with a worker
+// count suited to its current load.
+type QueueStatus struct {
+	Name    string `json:"name"`
+	Workers int    `json:"workers"`
+	// MinWorkers and MaxWorkers bound automatic resizing; they equal Workers for fixed-size queues.
+	MinWorkers int  `json:"minWorkers"`
+	MaxWorkers int  `json:"maxWorkers"`
+	Autoscaled bool `json:"autoscaled"`
+	// RecommendedWorkers is the pool size the current backlog calls for, regardless of the bounds.
+	RecommendedWorkers  int     `json:"recommendedWorkers"`
+	Depth               int     `json:"depth"`
+	Active              int     `json:"active"`
+	Processed           uint64  `json:"processed"`
+	Failed              uint64  `json:"failed"`
+	RatePerMinute       float64 `json:"ratePerMinute"`
+	AvgDurationMs       int64   `json:"avgDurationMs"`
+	OldestQueuedSeconds float64 `json:"oldestQueuedSeconds"`
+}
@@ -2,26 +2,37 @@ package dto
 
 import "github.com/noah-isme/sma-adp-api/internal/models"
 
-// ReportRequest captures POST /reports/generate payload.
+// ReportRequest captures POST /reports/generate payload. Priority is high, normal or low and
+// defaults to normal; only administrators may request high. Force skips deduplication and always
+// queues a new job.
 type ReportRequest struct {
-	Type    models.ReportType   `json:"type"`
-	TermID  string              `json:"termId"`
-	ClassID *string             `json:"classId,omitempty"`
-	Format  models.ReportFormat `json:"format"`
+	Type     models.ReportType     `json:"type"`
+	TermID   string                `json:"termId"`
+	ClassID  *string               `json:"classId,omitempty"`
+	Format   models.ReportFormat   `json:"format"`
+	Priority models.ReportPriority `json:"priority,omitempty"`
+	Force    bool                  `json:"force,omitempty"`
 }
 
-// ReportJobResponse is returned after enqueueing a report.
+// ReportJobResponse is returned after enqueueing a report. Deduplicated is set when an identical
+// in-flight or recent job was returned instead of queueing a new one.
 type ReportJobResponse struct {
-	ID       string              `json:"id"`
-	Status   models.ReportStatus `json:"status"`
-	Progress int                 `json:"progress"`
+	ID           string                `json:"id"`
+	Status       models.ReportStatus   `json:"status"`
+	Progress     int                   `json:"progress"`
+	Priority     models.ReportPriority `json:"priority"`
+	ResultURL    *string               `json:"resultUrl,omitempty"`
+	Deduplicated bool                  `json:"deduplicated,omitempty"`
 }
 
-// ReportStatusResponse exposes job progress metadata.
+// ReportStatusResponse exposes job progress metadata. QueuePosition is the 1-based position among
+// queued jobs and is omitted once the job has started.
 type ReportStatusResponse struct {
-	ID        string              `json:"id"`
-	Status    models.ReportStatus `json:"status"`
-	Progress  int                 `json:"progress"`
-	ResultURL *string             `json:"resultUrl,omitempty"`
-	Error     *string             `json:"error,omitempty"`
+	ID            string                `json:"id"`
+	Status        models.ReportStatus   `json:"status"`
+	Progress      int                   `json:"progress"`
+	ResultURL     *string               `json:"resultUrl,omitempty"`
+	Error         *string               `json:"error,omitempty"`
+	Priority      models.ReportPriority `json:"priority"`
+	QueuePosition *int                  `json:"queuePosition,omitempty"`
 }
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// RetentionPurgeReport summarises one retention purge. In a dry run nothing is deleted and Purged
+// stays zero; Eligible minus Held is what a real run would delete.
+type RetentionPurgeReport struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	DryRun      bool                   `json:"dryRun"`
+	Classes     []RetentionClassReport `json:"classes"`
+	Purged      int64                  `json:"purged"`
+}
+
+// RetentionClassReport covers one data class. Cutoff is nil when the class is kept indefinitely.
+type RetentionClassReport struct {
+	DataClass     string     `json:"dataClass"`
+	RetentionDays int        `json:"retentionDays"`
+	Cutoff        *time.Time `json:"cutoff,omitempty"`
+	Eligible      int64      `json:"eligible"`
+	Held          int64      `json:"held"`
+	Purged        int64      `json:"purged"`
+}
+
+// CreateLegalHoldRequest places a legal hold. Without a user ID the hold covers the whole data class.
+type CreateLegalHoldRequest struct {
+	DataClass string  `json:"dataClass" validate:"required,oneof=REFRESH_TOKENS AUDIT_LOGS"`
+	UserID    *string `json:"userId" validate:"omitempty,min=1"`
+	Reason    string  `json:"reason" validate:"required,max=500"`
+}
+
+// LegalHoldQuery filters the legal hold list.
+type LegalHoldQuery struct {
+	IncludeReleased bool `form:"includeReleased"`
+}
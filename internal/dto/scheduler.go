@@ -1,49 +1,97 @@
 package dto
 
-// SubjectLoadRequest captures weekly demand for a subject-teacher pair.
+import "time"
+
+// SubjectLoadRequest captures weekly demand for a subject-teacher pair. WeeklyCount counts periods;
+// with a BlockSize above 1 they are taught as lessons of BlockSize consecutive periods, so
+// WeeklyCount must be a multiple of it.
 type SubjectLoadRequest struct {
 	SubjectID   string   `json:"subjectId" validate:"required"`
 	TeacherID   string   `json:"teacherId" validate:"required"`
 	WeeklyCount int      `json:"weeklyCount" validate:"required,min=1"`
+	BlockSize   int      `json:"blockSize,omitempty" validate:"omitempty,min=1,max=4"`
 	Difficulty  int      `json:"difficulty" validate:"omitempty,min=1,max=10"`
 	Preferred   []int    `json:"preferredSlots" validate:"omitempty,dive,min=0"`
 	Tags        []string `json:"tags"`
 }
 
-// GenerateScheduleRequest instructs the generator to build a proposal for the class/term.
+// GenerateScheduleRequest instructs the generator to build a proposal for the class/term. PresetID
+// replaces SubjectLoads with a stored preset whose teachers come from the class's assignments.
 type GenerateScheduleRequest struct {
-	TermID          string               `json:"termId" validate:"required"`
-	ClassID         string               `json:"classId" validate:"required"`
-	TimeSlotsPerDay int                  `json:"timeSlotsPerDay" validate:"required,min=1,max=16"`
-	Days            []int                `json:"days" validate:"required,min=1,dive,min=1,max=7"`
-	SubjectLoads    []SubjectLoadRequest `json:"subjectLoads" validate:"required,min=1,dive"`
-	HardConstraints []string             `json:"hardConstraints"`
-	SoftConstraints []string             `json:"softConstraints"`
-	Meta            map[string]any       `json:"meta"`
+	TermID          string                `json:"termId" validate:"required"`
+	ClassID         string                `json:"classId" validate:"required"`
+	TimeSlotsPerDay int                   `json:"timeSlotsPerDay" validate:"required,min=1,max=16"`
+	Days            []int                 `json:"days" validate:"required,min=1,dive,min=1,max=7"`
+	PresetID        string                `json:"presetId,omitempty" validate:"excluded_with=SubjectLoads"`
+	SubjectLoads    []SubjectLoadRequest  `json:"subjectLoads" validate:"required_without=PresetID,dive"`
+	HardConstraints []string              `json:"hardConstraints"`
+	SoftConstraints []string              `json:"softConstraints"`
+	Optimization    *ScheduleOptimization `json:"optimization"`
+	Meta            map[string]any        `json:"meta"`
 }
 
-// ScheduleSlotProposal represents a generated slot.
+// ScheduleOptimization enables the optional search phase run after heuristic seeding.
+type ScheduleOptimization struct {
+	Mode         string `json:"mode" validate:"omitempty,oneof=heuristic annealing"`
+	TimeBudgetMs int    `json:"timeBudgetMs" validate:"omitempty,min=1,max=60000"`
+	Candidates   int    `json:"candidates" validate:"omitempty,min=1,max=10"`
+	Seed         int64  `json:"seed"`
+}
+
+// ScheduleSlotProposal represents a generated slot. BlockSize is set on every period of a block
+// lesson.
 type ScheduleSlotProposal struct {
 	DayOfWeek int     `json:"dayOfWeek"`
 	TimeSlot  int     `json:"timeSlot"`
 	SubjectID string  `json:"subjectId"`
 	TeacherID string  `json:"teacherId"`
 	Room      *string `json:"room,omitempty"`
+	BlockSize int     `json:"blockSize,omitempty"`
 }
 
 // ProposalConflict captures unmet demand or hard constraint violations.
 type ProposalConflict struct {
-	Type    string                `json:"type"`
-	Message string                `json:"message"`
-	Slot    *ScheduleSlotProposal `json:"slot,omitempty"`
-	Meta    map[string]any        `json:"meta,omitempty"`
+	Type        string                `json:"type"`
+	Message     string                `json:"message"`
+	Slot        *ScheduleSlotProposal `json:"slot,omitempty"`
+	Meta        map[string]any        `json:"meta,omitempty"`
+	Explanation *ConflictExplanation  `json:"explanation,omitempty"`
+}
+
+// ConflictExplanation enumerates why every slot was rejected for an unplaced subject load.
+type ConflictExplanation struct {
+	SubjectID      string              `json:"subjectId"`
+	TeacherID      string              `json:"teacherId"`
+	EvaluatedSlots int                 `json:"evaluatedSlots"`
+	AvailableSlots int                 `json:"availableSlots"`
+	Blockers       map[string]int      `json:"blockers"`
+	Slots          []SlotBlockerDetail `json:"slots"`
+}
+
+// SlotBlockerDetail lists the blocking checks hit at one day/slot.
+type SlotBlockerDetail struct {
+	DayOfWeek int      `json:"dayOfWeek"`
+	TimeSlot  int      `json:"timeSlot"`
+	Reasons   []string `json:"reasons"`
+	Detail    string   `json:"detail,omitempty"`
+}
+
+// ProposalExplanationResponse is returned by the proposal explain endpoint.
+type ProposalExplanationResponse struct {
+	ProposalID string             `json:"proposalId"`
+	Score      float64            `json:"score"`
+	Conflicts  []ProposalConflict `json:"conflicts"`
 }
 
 // ScheduleImprovementStats summarises repair iterations.
 type ScheduleImprovementStats struct {
-	Iterations  int     `json:"iterations"`
-	GapPenalty  float64 `json:"gapPenalty"`
-	LoadPenalty float64 `json:"loadPenalty"`
+	Iterations        int     `json:"iterations"`
+	GapPenalty        float64 `json:"gapPenalty"`
+	LoadPenalty       float64 `json:"loadPenalty"`
+	ConstraintPenalty float64 `json:"constraintPenalty"`
+	// OptimizationSteps counts moves evaluated by the optimization phase.
+	OptimizationSteps int    `json:"optimizationSteps,omitempty"`
+	Algorithm         string `json:"algorithm,omitempty"`
 }
 
 // GenerateScheduleResponse returns the built timetable proposal.
@@ -53,6 +101,16 @@ type GenerateScheduleResponse struct {
 	Slots      []ScheduleSlotProposal   `json:"slots"`
 	Conflicts  []ProposalConflict       `json:"conflicts"`
 	Stats      ScheduleImprovementStats `json:"stats"`
+	Candidates []ScheduleCandidate      `json:"candidates,omitempty"`
+}
+
+// ScheduleCandidate summarises an alternative proposal ranked by score.
+type ScheduleCandidate struct {
+	Rank       int                      `json:"rank"`
+	ProposalID string                   `json:"proposalId"`
+	Score      float64                  `json:"score"`
+	Conflicts  int                      `json:"conflicts"`
+	Stats      ScheduleImprovementStats `json:"stats"`
 }
 
 // SaveScheduleRequest persists a proposal into semester schedules.
@@ -61,8 +119,236 @@ type SaveScheduleRequest struct {
 	CommitToDaily bool   `json:"commitToDaily"`
 }
 
+// SaveScheduleResult describes the semester schedule written by a save. For dry runs it describes
+// what would have been written; ScheduleID and Version are then not persisted.
+type SaveScheduleResult struct {
+	ScheduleID     string `json:"scheduleId"`
+	Version        int    `json:"version"`
+	Status         string `json:"status"`
+	Slots          int    `json:"slots"`
+	DailySchedules int    `json:"dailySchedules"`
+	DryRun         bool   `json:"dryRun,omitempty"`
+}
+
+// ScheduleSlotRef addresses one cell of a proposal timetable.
+type ScheduleSlotRef struct {
+	DayOfWeek int `json:"dayOfWeek" validate:"required,min=1,max=7"`
+	TimeSlot  int `json:"timeSlot" validate:"required,min=1"`
+}
+
+// ScheduleSlotOperation describes a manual edit. Move and swap require To; replace requires SubjectID and TeacherID.
+// Moving any period of a block lesson moves the whole block so it starts at To; block lessons cannot be swapped.
+type ScheduleSlotOperation struct {
+	Type      string           `json:"type" validate:"required,oneof=move swap replace"`
+	From      ScheduleSlotRef  `json:"from" validate:"required"`
+	To        *ScheduleSlotRef `json:"to" validate:"required_if=Type move,required_if=Type swap"`
+	SubjectID string           `json:"subjectId" validate:"required_if=Type replace"`
+	TeacherID string           `json:"teacherId" validate:"required_if=Type replace"`
+}
+
+// EditProposalSlotsRequest applies manual edits to a cached proposal before it is saved.
+type EditProposalSlotsRequest struct {
+	Operations []ScheduleSlotOperation `json:"operations" validate:"required,min=1,max=50,dive"`
+}
+
+// ScheduleSlotInput places one lesson of a hand-built timetable.
+type ScheduleSlotInput struct {
+	DayOfWeek int     `json:"dayOfWeek" validate:"required,min=1,max=7"`
+	TimeSlot  int     `json:"timeSlot" validate:"required,min=1"`
+	SubjectID string  `json:"subjectId" validate:"required"`
+	TeacherID string  `json:"teacherId" validate:"required"`
+	Room      *string `json:"room,omitempty"`
+}
+
+// ScoreScheduleRequest scores a hand-built timetable for the class/term without creating a
+// proposal. SubjectLoads is optional; when omitted every subject-teacher pair's weekly count is
+// taken from the slots, so no load is reported as unfulfilled.
+type ScoreScheduleRequest struct {
+	TermID          string               `json:"termId" validate:"required"`
+	ClassID         string               `json:"classId" validate:"required"`
+	TimeSlotsPerDay int                  `json:"timeSlotsPerDay" validate:"required,min=1,max=16"`
+	Days            []int                `json:"days" validate:"required,min=1,dive,min=1,max=7"`
+	Slots           []ScheduleSlotInput  `json:"slots" validate:"required,min=1,dive"`
+	SubjectLoads    []SubjectLoadRequest `json:"subjectLoads" validate:"omitempty,dive"`
+	HardConstraints []string             `json:"hardConstraints"`
+	SoftConstraints []string             `json:"softConstraints"`
+}
+
+// ScoreScheduleResponse reports the generator's scoring of a hand-built timetable. ConflictPenalty
+// counts the conflicts, each of which costs 100 points of the score.
+type ScoreScheduleResponse struct {
+	Score           float64                  `json:"score"`
+	ConflictPenalty float64                  `json:"conflictPenalty"`
+	Slots           []ScheduleSlotProposal   `json:"slots"`
+	Conflicts       []ProposalConflict       `json:"conflicts"`
+	Stats           ScheduleImprovementStats `json:"stats"`
+}
+
 // SemesterScheduleQuery filters schedule summaries by class and term.
 type SemesterScheduleQuery struct {
 	TermID  string `form:"termId" json:"termId"`
 	ClassID string `form:"classId" json:"classId"`
 }
+
+// ScheduleClashQuery selects the term scanned for double-booked daily schedules.
+type ScheduleClashQuery struct {
+	TermID string `form:"termId" json:"termId" validate:"required"`
+}
+
+// ScheduleClashExportRequest selects the term and format of a clash report export.
+type ScheduleClashExportRequest struct {
+	TermID string `form:"termId" json:"termId" validate:"required"`
+	Format string `form:"format" json:"format" validate:"omitempty,oneof=pdf xlsx"`
+}
+
+// ScheduleClashEntry is one daily schedule taking part in a clash.
+type ScheduleClashEntry struct {
+	ScheduleID  string `json:"scheduleId"`
+	ClassID     string `json:"classId"`
+	ClassName   string `json:"className"`
+	SubjectID   string `json:"subjectId"`
+	SubjectName string `json:"subjectName"`
+	TeacherID   string `json:"teacherId"`
+	TeacherName string `json:"teacherName"`
+	Room        string `json:"room,omitempty"`
+}
+
+// ScheduleClash groups the daily schedules that book one teacher, class or room (the dimension)
+// more than once in the same day and slot.
+type ScheduleClash struct {
+	Dimension    string               `json:"dimension"`
+	DayOfWeek    string               `json:"dayOfWeek"`
+	TimeSlot     string               `json:"timeSlot"`
+	ResourceID   string               `json:"resourceId"`
+	ResourceName string               `json:"resourceName"`
+	Schedules    []ScheduleClashEntry `json:"schedules"`
+}
+
+// ScheduleClashReport lists a term's clashes with their count per dimension.
+type ScheduleClashReport struct {
+	TermID  string          `json:"termId"`
+	Total   int             `json:"total"`
+	Counts  map[string]int  `json:"counts"`
+	Clashes []ScheduleClash `json:"clashes"`
+}
+
+// ScheduleConstraintInfo describes a registered scheduler constraint.
+type ScheduleConstraintInfo struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Weight      float64 `json:"weight"`
+}
+
+// ScheduleExportRequest selects the rendering of a semester schedule export.
+type ScheduleExportRequest struct {
+	Format    string `form:"format" json:"format" validate:"omitempty,oneof=pdf xlsx"`
+	View      string `form:"view" json:"view" validate:"omitempty,oneof=class teacher"`
+	TeacherID string `form:"teacherId" json:"teacherId" validate:"required_if=View teacher"`
+}
+
+// ScheduleExportResponse points to the rendered timetable download.
+type ScheduleExportResponse struct {
+	URL       string    `json:"url"`
+	Format    string    `json:"format"`
+	View      string    `json:"view"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SlotDefinitionRequest creates or updates the wall-clock times of a term slot.
+type SlotDefinitionRequest struct {
+	SlotIndex int     `json:"slotIndex" validate:"required,min=1,max=20"`
+	StartTime string  `json:"startTime" validate:"required"`
+	EndTime   string  `json:"endTime" validate:"required"`
+	Label     *string `json:"label" validate:"omitempty,max=50"`
+	IsBreak   bool    `json:"isBreak"`
+}
+
+// TeacherPreferenceImportResult reports how a filled preference sheet was applied. Rows lists every
+// teacher row of the sheet; valid rows are stored even when others fail.
+type TeacherPreferenceImportResult struct {
+	TermID   string                       `json:"termId"`
+	Received int                          `json:"received"`
+	Applied  int                          `json:"applied"`
+	Failed   int                          `json:"failed"`
+	Rows     []TeacherPreferenceImportRow `json:"rows"`
+}
+
+// TeacherPreferenceImportRow is the outcome of one sheet row. Row is the spreadsheet row number.
+type TeacherPreferenceImportRow struct {
+	Row       int      `json:"row"`
+	TeacherID string   `json:"teacherId,omitempty"`
+	Applied   bool     `json:"applied"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// SubjectLoadPresetItemRequest is the weekly demand of one subject within a preset.
+type SubjectLoadPresetItemRequest struct {
+	SubjectID   string `json:"subjectId" validate:"required"`
+	WeeklyCount int    `json:"weeklyCount" validate:"required,min=1,max=16"`
+	BlockSize   int    `json:"blockSize,omitempty" validate:"omitempty,min=1,max=4"`
+	Difficulty  int    `json:"difficulty" validate:"omitempty,min=1,max=10"`
+}
+
+// SubjectLoadPresetRequest creates or replaces a curriculum load preset. Track is omitted for
+// presets shared by every track of the grade.
+type SubjectLoadPresetRequest struct {
+	Name  string                         `json:"name" validate:"required,max=100"`
+	Grade string                         `json:"grade" validate:"required,max=10"`
+	Track *string                        `json:"track,omitempty" validate:"omitempty,max=50"`
+	Loads []SubjectLoadPresetItemRequest `json:"loads" validate:"required,min=1,dive"`
+}
+
+// ClassTimetableQuery selects the term and the week, given as any YYYY-MM-DD date in it, of a class
+// timetable. The week defaults to the current one.
+type ClassTimetableQuery struct {
+	TermID string `form:"termId" json:"termId" validate:"required"`
+	Week   string `form:"week" json:"week"`
+}
+
+// ClassTimetableSlot labels one row of the timetable grid.
+type ClassTimetableSlot struct {
+	TimeSlot int    `json:"timeSlot"`
+	Label    string `json:"label,omitempty"`
+}
+
+// ClassTimetableCell is one taught or examined slot of a day. Source is SEMESTER for the published
+// semester schedule, DAILY for a daily schedule taking its place and EXAM for an exam sitting, whose
+// teacher is the invigilator. LeaveID names the approved leave of a lesson's teacher, whose
+// substitutes are suggested by /teacher-leaves/{id}/substitutions.
+type ClassTimetableCell struct {
+	TimeSlot       int    `json:"timeSlot"`
+	Source         string `json:"source"`
+	SubjectID      string `json:"subjectId"`
+	SubjectName    string `json:"subjectName"`
+	TeacherID      string `json:"teacherId"`
+	TeacherName    string `json:"teacherName"`
+	Room           string `json:"room,omitempty"`
+	ScheduleID     string `json:"scheduleId,omitempty"`
+	ExamID         string `json:"examId,omitempty"`
+	TeacherOnLeave bool   `json:"teacherOnLeave"`
+	LeaveID        string `json:"leaveId,omitempty"`
+}
+
+// ClassTimetableDay holds a date's cells. Holidays and dates outside the term have no cells.
+type ClassTimetableDay struct {
+	Date        string               `json:"date"`
+	DayOfWeek   int                  `json:"dayOfWeek"`
+	DayName     string               `json:"dayName"`
+	Holiday     string               `json:"holiday,omitempty"`
+	OutsideTerm bool                 `json:"outsideTerm,omitempty"`
+	Cells       []ClassTimetableCell `json:"cells"`
+}
+
+// ClassTimetable is a class's effective timetable for one week. ScheduleID and ScheduleVersion name
+// the published semester schedule it is based on, if any.
+type ClassTimetable struct {
+	ClassID         string               `json:"classId"`
+	ClassName       string               `json:"className"`
+	TermID          string               `json:"termId"`
+	WeekStart       string               `json:"weekStart"`
+	WeekEnd         string               `json:"weekEnd"`
+	ScheduleID      string               `json:"scheduleId,omitempty"`
+	ScheduleVersion int                  `json:"scheduleVersion,omitempty"`
+	Slots           []ClassTimetableSlot `json:"slots"`
+	Days            []ClassTimetableDay  `json:"days"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// SearchRequest captures the global search query.
+type SearchRequest struct {
+	Query string
+	// Types restricts the groups searched; empty searches every type the caller may see.
+	Types []models.SearchType
+	Limit int
+}
+
+// SearchGroup holds the hits for one entity type.
+type SearchGroup struct {
+	Type  models.SearchType  `json:"type"`
+	Items []models.SearchHit `json:"items"`
+}
+
+// SearchResponse lists result groups in a stable type order.
+type SearchResponse struct {
+	Query  string        `json:"query"`
+	Groups []SearchGroup `json:"groups"`
+}
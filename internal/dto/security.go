@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// SecurityDashboardResponse summarises access denials for administrators.
+type SecurityDashboardResponse struct {
+	Window       string                      `json:"window"`
+	Since        time.Time                   `json:"since"`
+	TotalDenials int                         `json:"totalDenials"`
+	ByReason     map[string]int              `json:"byReason"`
+	TopRoutes    []SecurityCount             `json:"topRoutes"`
+	TopActors    []SecurityCount             `json:"topActors"`
+	Recent       []models.AccessDenialRecord `json:"recent"`
+	Alert        SecurityAlertStatus         `json:"alert"`
+}
+
+// SecurityCount is a denial tally for a route or actor.
+type SecurityCount struct {
+	Key   string `json:"key"`
+	Total int    `json:"total"`
+}
+
+// SecurityAlertStatus reports whether denials in the window crossed the configured threshold.
+type SecurityAlertStatus struct {
+	Threshold int      `json:"threshold"`
+	Triggered bool     `json:"triggered"`
+	Actors    []string `json:"actors,omitempty"`
+}
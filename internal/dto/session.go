@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// Session is one live login of the caller.
+type Session struct {
+	ID      string `json:"id"`
+	Current bool   `json:"current"`
+	// StartedAt is the login; LastActiveAt the latest refresh or heartbeat.
+	StartedAt    time.Time `json:"started_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+	// ExpiresAt is when the current refresh token lapses. IdleExpiresAt, set when an idle timeout
+	// is configured, is when the session ends without further activity.
+	ExpiresAt     time.Time  `json:"expires_at"`
+	IdleExpiresAt *time.Time `json:"idle_expires_at,omitempty"`
+	IPAddress     string     `json:"ip_address"`
+	UserAgent     string     `json:"user_agent"`
+}
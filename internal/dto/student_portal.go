@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/internal/models"
+
+// ProvisionStudentAccountRequest creates the STUDENT login for an existing student record.
+type ProvisionStudentAccountRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// StudentScheduleResponse is the weekly timetable of the student's active class.
+type StudentScheduleResponse struct {
+	StudentID string            `json:"studentId"`
+	ClassID   string            `json:"classId"`
+	ClassName string            `json:"className,omitempty"`
+	TermID    string            `json:"termId"`
+	Schedules []models.Schedule `json:"schedules"`
+}
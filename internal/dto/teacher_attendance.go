@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// TeacherPresenceRequest is posted by teachers clocking in or out. Coordinates are required only
+// when a geofence is configured.
+type TeacherPresenceRequest struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// TeacherAttendanceResponse describes a teacher's presence for one day.
+type TeacherAttendanceResponse struct {
+	ID            string     `json:"id"`
+	TeacherID     string     `json:"teacherId"`
+	Date          string     `json:"date"`
+	CheckInAt     time.Time  `json:"checkInAt"`
+	CheckOutAt    *time.Time `json:"checkOutAt,omitempty"`
+	Late          bool       `json:"late"`
+	LateMinutes   int        `json:"lateMinutes,omitempty"`
+	WorkedMinutes int        `json:"workedMinutes,omitempty"`
+	// Duplicate is true when the teacher had already clocked in that day; the stored row is returned.
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// TeacherAttendanceSummary aggregates one teacher's presence over a month.
+type TeacherAttendanceSummary struct {
+	TeacherID        string `json:"teacherId"`
+	FullName         string `json:"fullName,omitempty"`
+	DaysPresent      int    `json:"daysPresent"`
+	LateDays         int    `json:"lateDays"`
+	LateMinutes      int    `json:"lateMinutes"`
+	MissingCheckouts int    `json:"missingCheckouts"`
+	WorkedMinutes    int    `json:"workedMinutes"`
+}
+
+// TeacherAttendanceRecapResponse lists monthly presence for every active teacher.
+type TeacherAttendanceRecapResponse struct {
+	Month    string                     `json:"month"`
+	Teachers []TeacherAttendanceSummary `json:"teachers"`
+}
+
+// TeacherAttendanceMonthResponse is a single teacher's monthly log with its summary.
+type TeacherAttendanceMonthResponse struct {
+	Month   string                      `json:"month"`
+	Summary TeacherAttendanceSummary    `json:"summary"`
+	Days    []TeacherAttendanceResponse `json:"days"`
+}
@@ -0,0 +1,83 @@
+package dto
+
+// TeacherLeaveRequest files a leave. Teachers file for themselves; administrators may file on a
+// teacher's behalf by setting TeacherID.
+type TeacherLeaveRequest struct {
+	TeacherID string  `json:"teacherId"`
+	Type      string  `json:"type" validate:"required,oneof=SICK PERSONAL TRAINING"`
+	StartDate string  `json:"startDate" validate:"required"`
+	EndDate   string  `json:"endDate" validate:"required"`
+	Reason    *string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// TeacherLeaveReviewRequest carries an administrator's note on an approval, rejection or
+// cancellation.
+type TeacherLeaveReviewRequest struct {
+	Note *string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// TeacherLeaveQuery filters leave listings. From and To (YYYY-MM-DD) select leaves overlapping the
+// range.
+type TeacherLeaveQuery struct {
+	TeacherID string `form:"teacherId"`
+	Status    string `form:"status" validate:"omitempty,oneof=PENDING APPROVED REJECTED CANCELLED"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+}
+
+// SubstituteCandidate is a teacher free to cover a lesson. Candidates teaching the subject come
+// first, then those teaching the class, then the least busy that day.
+type SubstituteCandidate struct {
+	TeacherID      string `json:"teacherId"`
+	FullName       string `json:"fullName,omitempty"`
+	TeachesSubject bool   `json:"teachesSubject"`
+	TeachesClass   bool   `json:"teachesClass"`
+	LessonsThatDay int    `json:"lessonsThatDay"`
+}
+
+// LeaveAffectedSlot is a lesson the teacher on leave would have taught on one date.
+type LeaveAffectedSlot struct {
+	Date       string                `json:"date"`
+	DayOfWeek  string                `json:"dayOfWeek"`
+	TimeSlot   string                `json:"timeSlot"`
+	ScheduleID string                `json:"scheduleId"`
+	TermID     string                `json:"termId"`
+	ClassID    string                `json:"classId"`
+	SubjectID  string                `json:"subjectId"`
+	Room       string                `json:"room,omitempty"`
+	Candidates []SubstituteCandidate `json:"candidates"`
+}
+
+// TeacherLeaveSubstitutions lists the lessons a leave affects with substitution suggestions.
+type TeacherLeaveSubstitutions struct {
+	LeaveID   string              `json:"leaveId"`
+	TeacherID string              `json:"teacherId"`
+	Slots     []LeaveAffectedSlot `json:"slots"`
+	// Truncated is true when the leave is longer than the dates suggestions are computed for.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// TeacherLeaveTypeBalance is one leave type's allowance and usage in a year. Allowance and Remaining
+// are omitted for types without an annual allowance.
+type TeacherLeaveTypeBalance struct {
+	Type      string `json:"type"`
+	Allowance *int   `json:"allowance,omitempty"`
+	Used      int    `json:"used"`
+	Pending   int    `json:"pending"`
+	Remaining *int   `json:"remaining,omitempty"`
+	// Overdrawn is true when approved days exceed the allowance.
+	Overdrawn bool `json:"overdrawn,omitempty"`
+}
+
+// TeacherLeaveBalance is one teacher's leave balance per type.
+type TeacherLeaveBalance struct {
+	TeacherID string                    `json:"teacherId"`
+	FullName  string                    `json:"fullName"`
+	Types     []TeacherLeaveTypeBalance `json:"types"`
+}
+
+// TeacherLeaveBalanceReport lists the leave balance of each teacher in a year.
+type TeacherLeaveBalanceReport struct {
+	Year     int                   `json:"year"`
+	Teachers []TeacherLeaveBalance `json:"teachers"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/noah-isme/sma-adp-api/pkg/buildinfo"
+
+// VersionResponse identifies the serving build and the features it has enabled.
+type VersionResponse struct {
+	buildinfo.Info
+	Features map[string]bool `json:"features"`
+}
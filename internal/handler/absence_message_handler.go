@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/messaging"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// maxCallbackBody bounds provider status callbacks, which are a few kilobytes at most.
+const maxCallbackBody = 1 << 20
+
+type absenceMessageService interface {
+	List(ctx context.Context, filter models.AbsenceMessageFilter) ([]models.AbsenceMessage, *models.Pagination, error)
+	ApplyStatuses(ctx context.Context, updates []messaging.StatusUpdate) error
+}
+
+type messagingCallbacks interface {
+	ParseCallback(provider string, r *http.Request, body []byte) ([]messaging.StatusUpdate, error)
+	VerifySubscription(provider string, query url.Values) (string, error)
+}
+
+// AbsenceMessageHandler exposes the absence message delivery log and the providers' status callbacks.
+type AbsenceMessageHandler struct {
+	service   absenceMessageService
+	callbacks messagingCallbacks
+}
+
+// NewAbsenceMessageHandler constructs the handler.
+func NewAbsenceMessageHandler(service absenceMessageService, callbacks messagingCallbacks) *AbsenceMessageHandler {
+	return &AbsenceMessageHandler{service: service, callbacks: callbacks}
+}
+
+// List godoc
+// @Summary Absence messages sent to guardians
+// @Tags Attendance
+// @Produce json
+// @Param date query string false "Absence date (YYYY-MM-DD)"
+// @Param studentId query string false "Student ID"
+// @Param guardianId query string false "Guardian user ID"
+// @Param status query string false "PENDING, SENT, DELIVERED, FAILED or SKIPPED"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/absence-messages [get]
+func (h *AbsenceMessageHandler) List(c *gin.Context) {
+	date, err := parseDateParam(c.Query("date"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	messages, pagination, err := h.service.List(c.Request.Context(), models.AbsenceMessageFilter{
+		Date:       date,
+		StudentID:  c.Query("studentId"),
+		GuardianID: c.Query("guardianId"),
+		Status:     models.AbsenceMessageStatus(c.Query("status")),
+		Page:       parseQueryInt(c, "page", 1),
+		PageSize:   parseQueryInt(c, "pageSize", 20),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, messages, pagination)
+}
+
+// Callback godoc
+// @Summary Delivery status callback of a messaging provider
+// @Description Called by Twilio and the WhatsApp Business Cloud API; requests must carry the provider's signature.
+// @Tags Messaging
+// @Param provider path string true "twilio or meta"
+// @Success 204
+// @Router /messaging/callbacks/{provider} [post]
+func (h *AbsenceMessageHandler) Callback(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCallbackBody))
+	if err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid payload"))
+		return
+	}
+	updates, err := h.callbacks.ParseCallback(c.Param("provider"), c.Request, body)
+	if err != nil {
+		response.Error(c, callbackError(err))
+		return
+	}
+	if err := h.service.ApplyStatuses(c.Request.Context(), updates); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// VerifyCallback godoc
+// @Summary Callback URL handshake of a messaging provider
+// @Tags Messaging
+// @Produce plain
+// @Param provider path string true "meta"
+// @Success 200 {string} string "The hub.challenge value"
+// @Router /messaging/callbacks/{provider} [get]
+func (h *AbsenceMessageHandler) VerifyCallback(c *gin.Context) {
+	challenge, err := h.callbacks.VerifySubscription(c.Param("provider"), c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, callbackError(err))
+		return
+	}
+	c.String(http.StatusOK, challenge)
+}
+
+func callbackError(err error) error {
+	switch {
+	case errors.Is(err, messaging.ErrUnknownProvider):
+		return appErrors.Clone(appErrors.ErrNotFound, "messaging provider not found")
+	case errors.Is(err, messaging.ErrInvalidSignature):
+		return appErrors.Clone(appErrors.ErrUnauthorized, "invalid callback signature")
+	default:
+		return appErrors.Clone(appErrors.ErrValidation, "invalid callback payload")
+	}
+}
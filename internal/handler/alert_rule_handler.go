@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type alertRuleService interface {
+	ListRules(ctx context.Context, query dto.AlertRuleQuery) ([]models.AlertRule, error)
+	CreateRule(ctx context.Context, req dto.AlertRuleRequest, actor *models.JWTClaims) (*models.AlertRule, error)
+	UpdateRule(ctx context.Context, id string, req dto.AlertRuleRequest, actor *models.JWTClaims) (*models.AlertRule, error)
+	DeleteRule(ctx context.Context, id string) error
+	Breaches(ctx context.Context, query dto.AlertRuleBreachQuery, claims *models.JWTClaims) ([]models.AlertRuleBreach, error)
+	Evaluate(ctx context.Context) (*dto.AlertRuleRun, error)
+}
+
+// AlertRuleHandler exposes alert rule management and the breaches found by their evaluation.
+type AlertRuleHandler struct {
+	service alertRuleService
+}
+
+// NewAlertRuleHandler constructs the handler.
+func NewAlertRuleHandler(service alertRuleService) *AlertRuleHandler {
+	return &AlertRuleHandler{service: service}
+}
+
+// ListRules godoc
+// @Summary List alert rules
+// @Tags Alert Rules
+// @Produce json
+// @Param includeDisabled query bool false "Include disabled rules"
+// @Success 200 {object} response.Envelope
+// @Router /alert-rules [get]
+func (h *AlertRuleHandler) ListRules(c *gin.Context) {
+	var query dto.AlertRuleQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid alert rule query"))
+		return
+	}
+	rules, err := h.service.ListRules(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, rules, nil)
+}
+
+// CreateRule godoc
+// @Summary Create an alert rule
+// @Tags Alert Rules
+// @Accept json
+// @Produce json
+// @Param payload body dto.AlertRuleRequest true "Alert rule"
+// @Success 201 {object} response.Envelope
+// @Router /alert-rules [post]
+func (h *AlertRuleHandler) CreateRule(c *gin.Context) {
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid alert rule payload"))
+		return
+	}
+	rule, err := h.service.CreateRule(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, rule)
+}
+
+// UpdateRule godoc
+// @Summary Replace an alert rule
+// @Tags Alert Rules
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert rule ID"
+// @Param payload body dto.AlertRuleRequest true "Alert rule"
+// @Success 200 {object} response.Envelope
+// @Router /alert-rules/{id} [put]
+func (h *AlertRuleHandler) UpdateRule(c *gin.Context) {
+	var req dto.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid alert rule payload"))
+		return
+	}
+	rule, err := h.service.UpdateRule(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, rule, nil)
+}
+
+// DeleteRule godoc
+// @Summary Delete an alert rule and its open breaches
+// @Tags Alert Rules
+// @Param id path string true "Alert rule ID"
+// @Success 204
+// @Router /alert-rules/{id} [delete]
+func (h *AlertRuleHandler) DeleteRule(c *gin.Context) {
+	if err := h.service.DeleteRule(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Breaches godoc
+// @Summary List open alert rule breaches
+// @Description Teachers only see breaches of classes they teach for rules addressed to homeroom or subject teachers.
+// @Tags Alert Rules
+// @Produce json
+// @Param termId query string false "Term ID (defaults to active)"
+// @Param classId query string false "Class ID filter"
+// @Success 200 {object} response.Envelope
+// @Router /alert-rules/breaches [get]
+func (h *AlertRuleHandler) Breaches(c *gin.Context) {
+	var query dto.AlertRuleBreachQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid alert rule breach query"))
+		return
+	}
+	breaches, err := h.service.Breaches(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, breaches, nil)
+}
+
+// Evaluate godoc
+// @Summary Evaluate the alert rules now instead of waiting for the scheduled run
+// @Tags Alert Rules
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /alert-rules/evaluate [post]
+func (h *AlertRuleHandler) Evaluate(c *gin.Context) {
+	run, err := h.service.Evaluate(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, run, nil)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -126,8 +127,20 @@ func (scheduleGeneratorIntegrationMock) Generate(ctx context.Context, req dto.Ge
 	return &dto.GenerateScheduleResponse{ProposalID: "proposal-1"}, nil
 }
 
-func (scheduleGeneratorIntegrationMock) Save(ctx context.Context, req dto.SaveScheduleRequest) (string, error) {
-	return "", nil
+func (scheduleGeneratorIntegrationMock) Save(ctx context.Context, req dto.SaveScheduleRequest) (*dto.SaveScheduleResult, error) {
+	return &dto.SaveScheduleResult{}, nil
+}
+
+func (scheduleGeneratorIntegrationMock) EditProposalSlots(ctx context.Context, proposalID string, req dto.EditProposalSlotsRequest) (*dto.GenerateScheduleResponse, error) {
+	return &dto.GenerateScheduleResponse{ProposalID: proposalID}, nil
+}
+
+func (scheduleGeneratorIntegrationMock) Score(ctx context.Context, req dto.ScoreScheduleRequest) (*dto.ScoreScheduleResponse, error) {
+	return &dto.ScoreScheduleResponse{}, nil
+}
+
+func (scheduleGeneratorIntegrationMock) ExplainProposal(ctx context.Context, proposalID string) (*dto.ProposalExplanationResponse, error) {
+	return &dto.ProposalExplanationResponse{ProposalID: proposalID}, nil
 }
 
 func (scheduleGeneratorIntegrationMock) List(ctx context.Context, query dto.SemesterScheduleQuery) ([]models.SemesterSchedule, error) {
@@ -142,6 +155,10 @@ func (scheduleGeneratorIntegrationMock) Delete(ctx context.Context, id string) e
 	return nil
 }
 
+func (scheduleGeneratorIntegrationMock) Constraints() []dto.ScheduleConstraintInfo {
+	return nil
+}
+
 type schedulePreferenceIntegrationMock struct{}
 
 func (schedulePreferenceIntegrationMock) Get(ctx context.Context, teacherID string) (*models.TeacherPreference, error) {
@@ -155,4 +172,12 @@ func (schedulePreferenceIntegrationMock) Upsert(ctx context.Context, teacherID s
 	return &models.TeacherPreference{TeacherID: teacherID, MaxLoadPerDay: req.MaxLoadPerDay}, nil
 }
 
+func (schedulePreferenceIntegrationMock) ExportSheet(ctx context.Context) ([]byte, error) {
+	return nil, nil
+}
+
+func (schedulePreferenceIntegrationMock) ImportSheet(ctx context.Context, termID string, data []byte) (*dto.TeacherPreferenceImportResult, error) {
+	return &dto.TeacherPreferenceImportResult{TermID: termID}, nil
+}
+
 const defaultGeneratorPayload = `{"termId":"2024","classId":"10A","timeSlotsPerDay":4,"days":[1,2],"subjectLoads":[{"subjectId":"math","teacherId":"t1","weeklyCount":4}]}`
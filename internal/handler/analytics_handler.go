@@ -56,7 +56,7 @@ func (h *AnalyticsHandler) Grades(c *gin.Context) {
 		return
 	}
 	filter := models.AnalyticsGradeFilter{
-		TermID:    c.Query("term_id"),
+		TermID:    middleware.TermID(c),
 		ClassID:   c.Query("class_id"),
 		SubjectID: c.Query("subject_id"),
 	}
@@ -120,7 +120,7 @@ func (h *AnalyticsHandler) System(c *gin.Context) {
 
 func parseAttendanceFilter(c *gin.Context) (models.AnalyticsAttendanceFilter, error) {
 	filter := models.AnalyticsAttendanceFilter{
-		TermID:  c.Query("term_id"),
+		TermID:  middleware.TermID(c),
 		ClassID: c.Query("class_id"),
 	}
 	if raw := c.Query("date_from"); raw != "" {
@@ -142,7 +142,7 @@ func parseAttendanceFilter(c *gin.Context) (models.AnalyticsAttendanceFilter, er
 
 func parseBehaviorFilter(c *gin.Context) (models.AnalyticsBehaviorFilter, error) {
 	filter := models.AnalyticsBehaviorFilter{
-		TermID:    c.Query("term_id"),
+		TermID:    middleware.TermID(c),
 		StudentID: c.Query("student_id"),
 		ClassID:   c.Query("class_id"),
 	}
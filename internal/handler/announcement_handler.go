@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type announcementBroadcaster interface {
+	Broadcast(ctx context.Context, req dto.BroadcastAnnouncementRequest, claims *models.JWTClaims) (*dto.BroadcastAnnouncementResult, error)
+	Acknowledge(ctx context.Context, id string, claims *models.JWTClaims) (*dto.AnnouncementAck, error)
+	Acknowledgements(ctx context.Context, id, role string) (*dto.AnnouncementAckReport, error)
+}
+
+// AnnouncementHandler lets admins broadcast announcements and track who acknowledged them.
+type AnnouncementHandler struct {
+	service announcementBroadcaster
+}
+
+// NewAnnouncementHandler builds a new handler.
+func NewAnnouncementHandler(service announcementBroadcaster) *AnnouncementHandler {
+	return &AnnouncementHandler{service: service}
+}
+
+// Broadcast godoc
+// @Summary Broadcast an announcement to users by role
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param payload body dto.BroadcastAnnouncementRequest true "Announcement and target roles"
+// @Success 201 {object} response.Envelope
+// @Router /announcements/broadcast [post]
+func (h *AnnouncementHandler) Broadcast(c *gin.Context) {
+	var req dto.BroadcastAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid announcement payload"))
+		return
+	}
+	result, err := h.service.Broadcast(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, result, nil)
+}
+
+// Acknowledge godoc
+// @Summary Acknowledge an announcement sent to the caller
+// @Tags Announcements
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Envelope
+// @Router /announcements/{id}/ack [post]
+func (h *AnnouncementHandler) Acknowledge(c *gin.Context) {
+	ack, err := h.service.Acknowledge(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, ack, nil)
+}
+
+// Acks godoc
+// @Summary List who acknowledged an announcement and who has not yet
+// @Tags Announcements
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Param role query string false "Only recipients with this role"
+// @Success 200 {object} response.Envelope
+// @Router /announcements/{id}/acks [get]
+func (h *AnnouncementHandler) Acks(c *gin.Context) {
+	report, err := h.service.Acknowledgements(c.Request.Context(), c.Param("id"), c.Query("role"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +24,11 @@ type archiveService interface {
 	Get(ctx context.Context, id string, actor *models.JWTClaims) (*models.ArchiveItem, error)
 	GetDownloadURL(ctx context.Context, id string, actor *models.JWTClaims) (string, error)
 	Download(ctx context.Context, id, token string, actor *models.JWTClaims) (*service.ArchiveDownload, error)
+	Preview(ctx context.Context, id string, actor *models.JWTClaims) (*service.ArchiveDownload, error)
 	Delete(ctx context.Context, id string, actor *models.JWTClaims) error
+	Trash(ctx context.Context, filter dto.ArchiveFilter, actor *models.JWTClaims) ([]dto.ArchiveTrashItem, error)
+	Restore(ctx context.Context, id string, actor *models.JWTClaims) (*models.ArchiveItem, error)
+	Usage(ctx context.Context, actor *models.JWTClaims) (*dto.ArchiveUsageReport, error)
 }
 
 // ArchiveHandler manages archive HTTP endpoints.
@@ -60,38 +65,17 @@ func (h *ArchiveHandler) Upload(c *gin.Context) {
 		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid archive payload"))
 		return
 	}
-	fileHeader, err := c.FormFile("file")
-	if err != nil {
-		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "file is required"))
-		return
-	}
-	src, err := fileHeader.Open()
+	upload, closeFile, err := formFileUpload(c)
 	if err != nil {
-		response.Error(c, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to open file"))
+		response.Error(c, err)
 		return
 	}
-	defer src.Close()
-
-	reader, ok := src.(io.ReadSeeker)
-	if !ok {
-		buf, readErr := io.ReadAll(src)
-		if readErr != nil {
-			response.Error(c, appErrors.Wrap(readErr, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to buffer file"))
-			return
-		}
-		reader = bytes.NewReader(buf)
-	}
+	defer closeFile()
 	claims := claimsFromContext(c)
 	if claims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	upload := service.ArchiveUpload{
-		Filename: fileHeader.Filename,
-		Size:     fileHeader.Size,
-		MimeType: fileHeader.Header.Get("Content-Type"),
-		Content:  reader,
-	}
 	item, err := h.service.Upload(c.Request.Context(), req, upload, claims)
 	if err != nil {
 		response.Error(c, err)
@@ -203,6 +187,34 @@ func (h *ArchiveHandler) Download(c *gin.Context) {
 	c.DataFromReader(http.StatusOK, result.SizeBytes, result.MimeType, result.File, nil)
 }
 
+// Preview godoc
+// @Summary Archive first-page thumbnail
+// @Tags Archives
+// @Produce png
+// @Param id path string true "Archive ID"
+// @Success 200 {file} binary
+// @Router /archives/{id}/preview [get]
+func (h *ArchiveHandler) Preview(c *gin.Context) {
+	if h.service == nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "archive service not configured"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	result, err := h.service.Preview(c.Request.Context(), c.Param("id"), claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer result.File.Close() //nolint:errcheck
+	// Thumbnails never change once rendered, but access is per user, so only private caches may keep them.
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.DataFromReader(http.StatusOK, result.SizeBytes, result.MimeType, result.File, nil)
+}
+
 // Delete godoc
 // @Summary Soft delete an archive entry
 // @Tags Archives
@@ -226,3 +238,116 @@ func (h *ArchiveHandler) Delete(c *gin.Context) {
 	}
 	response.NoContent(c)
 }
+
+// Trash godoc
+// @Summary List soft-deleted archives
+// @Tags Archives
+// @Produce json
+// @Param category query string false "Category filter"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} response.Envelope
+// @Router /archives/trash [get]
+func (h *ArchiveHandler) Trash(c *gin.Context) {
+	if h.service == nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "archive service not configured"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	filter := dto.ArchiveFilter{Category: strings.TrimSpace(c.Query("category"))}
+	if limit, err := strconv.Atoi(c.DefaultQuery("limit", "50")); err == nil {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.DefaultQuery("offset", "0")); err == nil {
+		filter.Offset = offset
+	}
+	items, err := h.service.Trash(c.Request.Context(), filter, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// Restore godoc
+// @Summary Restore a soft-deleted archive
+// @Tags Archives
+// @Produce json
+// @Param id path string true "Archive ID"
+// @Success 200 {object} response.Envelope
+// @Router /archives/{id}/restore [post]
+func (h *ArchiveHandler) Restore(c *gin.Context) {
+	if h.service == nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "archive service not configured"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	item, err := h.service.Restore(c.Request.Context(), c.Param("id"), claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Usage godoc
+// @Summary Archive storage usage and quotas
+// @Tags Archives
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /archives/usage [get]
+func (h *ArchiveHandler) Usage(c *gin.Context) {
+	if h.service == nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "archive service not configured"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	report, err := h.service.Usage(c.Request.Context(), claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// formFileUpload opens the multipart "file" field as a seekable archive upload. The returned func
+// closes the underlying file.
+func formFileUpload(c *gin.Context) (service.ArchiveUpload, func(), error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return service.ArchiveUpload{}, nil, appErrors.Clone(appErrors.ErrValidation, "file is required")
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		return service.ArchiveUpload{}, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to open file")
+	}
+	closeFile := func() { _ = src.Close() }
+
+	reader, ok := src.(io.ReadSeeker)
+	if !ok {
+		buf, readErr := io.ReadAll(src)
+		if readErr != nil {
+			closeFile()
+			return service.ArchiveUpload{}, nil, appErrors.Wrap(readErr, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to buffer file")
+		}
+		reader = bytes.NewReader(buf)
+	}
+	return service.ArchiveUpload{
+		Filename: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		MimeType: fileHeader.Header.Get("Content-Type"),
+		Content:  reader,
+	}, closeFile, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type archiveRetentionService interface {
+	Report(ctx context.Context, withinDays int) (*dto.ArchiveRetentionReport, error)
+}
+
+// ArchiveRetentionHandler exposes the archive retention report.
+type ArchiveRetentionHandler struct {
+	service archiveRetentionService
+}
+
+// NewArchiveRetentionHandler constructs the handler.
+func NewArchiveRetentionHandler(service archiveRetentionService) *ArchiveRetentionHandler {
+	return &ArchiveRetentionHandler{service: service}
+}
+
+// Report godoc
+// @Summary List archives due for retention deletion
+// @Tags Archives
+// @Produce json
+// @Param days query int false "Expiry window in days (default 30, max 365)"
+// @Success 200 {object} response.Envelope
+// @Router /archives/retention [get]
+func (h *ArchiveRetentionHandler) Report(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "days must be a whole number"))
+		return
+	}
+	report, err := h.service.Report(c.Request.Context(), days)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type attendanceAlertService interface {
+	Thresholds(ctx context.Context, termID string) (*dto.AttendanceThresholdsResponse, error)
+	SetThreshold(ctx context.Context, req dto.SetAttendanceThresholdRequest, actor *models.JWTClaims) (*models.AttendanceAlertThreshold, error)
+	DeleteThreshold(ctx context.Context, classID, termID string) error
+	Alerts(ctx context.Context, query dto.AttendanceAlertQuery, claims *models.JWTClaims) ([]models.AttendanceAlert, error)
+	Evaluate(ctx context.Context) (*dto.AttendanceAlertRun, error)
+}
+
+// AttendanceAlertHandler exposes attendance thresholds and the alerts raised against them.
+type AttendanceAlertHandler struct {
+	service attendanceAlertService
+}
+
+// NewAttendanceAlertHandler builds a new handler.
+func NewAttendanceAlertHandler(service attendanceAlertService) *AttendanceAlertHandler {
+	return &AttendanceAlertHandler{service: service}
+}
+
+// Thresholds godoc
+// @Summary List attendance alert thresholds
+// @Tags Attendance
+// @Produce json
+// @Param termId query string false "Term ID (defaults to active)"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/thresholds [get]
+func (h *AttendanceAlertHandler) Thresholds(c *gin.Context) {
+	result, err := h.service.Thresholds(c.Request.Context(), c.Query("termId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// SetThreshold godoc
+// @Summary Set the attendance alert threshold of a class
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param payload body dto.SetAttendanceThresholdRequest true "Threshold payload"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/thresholds [put]
+func (h *AttendanceAlertHandler) SetThreshold(c *gin.Context) {
+	var req dto.SetAttendanceThresholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid attendance threshold payload"))
+		return
+	}
+	threshold, err := h.service.SetThreshold(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, threshold, nil)
+}
+
+// DeleteThreshold godoc
+// @Summary Remove a class attendance threshold so the default applies
+// @Tags Attendance
+// @Param classId path string true "Class ID"
+// @Param termId query string true "Term ID"
+// @Success 204
+// @Router /attendance/thresholds/{classId} [delete]
+func (h *AttendanceAlertHandler) DeleteThreshold(c *gin.Context) {
+	if err := h.service.DeleteThreshold(c.Request.Context(), c.Param("classId"), c.Query("termId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Alerts godoc
+// @Summary List students below their class attendance threshold
+// @Tags Attendance
+// @Produce json
+// @Param termId query string false "Term ID (defaults to active)"
+// @Param classId query string false "Class ID filter"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/alerts [get]
+func (h *AttendanceAlertHandler) Alerts(c *gin.Context) {
+	var query dto.AttendanceAlertQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid attendance alert query"))
+		return
+	}
+	alerts, err := h.service.Alerts(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, alerts, nil)
+}
+
+// Evaluate godoc
+// @Summary Evaluate attendance thresholds now instead of waiting for the nightly run
+// @Tags Attendance
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /attendance/alerts/evaluate [post]
+func (h *AttendanceAlertHandler) Evaluate(c *gin.Context) {
+	run, err := h.service.Evaluate(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, run, nil)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
@@ -17,9 +18,13 @@ import (
 type attendanceAliasService interface {
 	ListDaily(ctx context.Context, req dto.AttendanceDailyRequest, claims *models.JWTClaims) ([]models.DailyAttendanceRecord, *models.Pagination, error)
 	Summary(ctx context.Context, req dto.AttendanceSummaryRequest, claims *models.JWTClaims) (*dto.AttendanceSummaryResponse, bool, error)
+	Monthly(ctx context.Context, req dto.AttendanceMonthlyRequest, claims *models.JWTClaims) (*dto.AttendanceMonthlyResponse, error)
+	StudentHistory(ctx context.Context, req dto.AttendanceStudentRequest, claims *models.JWTClaims) (*dto.AttendanceStudentResponse, error)
+	Excuse(ctx context.Context, req dto.AttendanceExcuseRequest, claims *models.JWTClaims) (*models.DailyAttendance, error)
 }
 
-// AttendanceAliasHandler exposes /attendance and /attendance/daily adapters.
+// AttendanceAliasHandler exposes /attendance, /attendance/daily, /attendance/monthly and
+// /attendance/student/:id adapters.
 type AttendanceAliasHandler struct {
 	service attendanceAliasService
 }
@@ -33,7 +38,7 @@ func NewAttendanceAliasHandler(service attendanceAliasService) *AttendanceAliasH
 // @Summary Daily attendance alias endpoint
 // @Tags Attendance
 // @Produce json
-// @Param termId query string true "Term ID"
+// @Param termId query string false "Term ID, defaults to the active term"
 // @Param classId query string false "Class ID"
 // @Param studentId query string false "Student ID"
 // @Param status query string false "Attendance status (H/S/I/A)"
@@ -53,7 +58,7 @@ func (h *AttendanceAliasHandler) Daily(c *gin.Context) {
 	}
 
 	req := dto.AttendanceDailyRequest{
-		TermID:    c.Query("termId"),
+		TermID:    middleware.TermID(c),
 		ClassID:   c.Query("classId"),
 		StudentID: c.Query("studentId"),
 		SortBy:    c.Query("sortBy"),
@@ -82,14 +87,14 @@ func (h *AttendanceAliasHandler) Daily(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
-	response.JSON(c, http.StatusOK, rows, pagination)
+	response.JSON(c, http.StatusOK, rows, pagination, middleware.ExtractMeta(c))
 }
 
 // Summary godoc
 // @Summary Attendance summary alias endpoint
 // @Tags Attendance
 // @Produce json
-// @Param termId query string true "Term ID"
+// @Param termId query string false "Term ID, defaults to the active term"
 // @Param classId query string false "Class ID"
 // @Param studentId query string false "Student ID"
 // @Param from query string false "From date (YYYY-MM-DD)"
@@ -104,7 +109,7 @@ func (h *AttendanceAliasHandler) Summary(c *gin.Context) {
 	}
 
 	req := dto.AttendanceSummaryRequest{
-		TermID:    c.Query("termId"),
+		TermID:    middleware.TermID(c),
 		ClassID:   c.Query("classId"),
 		StudentID: c.Query("studentId"),
 	}
@@ -127,13 +132,105 @@ func (h *AttendanceAliasHandler) Summary(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
-	meta := map[string]interface{}{
-		"cache_hit":          cacheHit,
-		"processing_time_ms": time.Since(start).Milliseconds(),
-	}
+	middleware.SetCacheHit(c, cacheHit)
+	meta := middleware.ExtractMeta(c)
+	meta["processing_time_ms"] = time.Since(start).Milliseconds()
 	response.JSON(c, http.StatusOK, summary, nil, meta)
 }
 
+// Excuse godoc
+// @Summary Record the reason and evidence for an absence
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param payload body dto.AttendanceExcuseRequest true "Excuse payload"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/excuses [put]
+func (h *AttendanceAliasHandler) Excuse(c *gin.Context) {
+	var req dto.AttendanceExcuseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid excuse payload"))
+		return
+	}
+	record, err := h.service.Excuse(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, record, nil)
+}
+
+// Monthly godoc
+// @Summary Monthly attendance pivot alias endpoint
+// @Tags Attendance
+// @Produce json
+// @Param classId query string true "Class ID"
+// @Param month query string true "Month (YYYY-MM)"
+// @Param termId query string false "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/monthly [get]
+func (h *AttendanceAliasHandler) Monthly(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+
+	req := dto.AttendanceMonthlyRequest{
+		ClassID: c.Query("classId"),
+		TermID:  middleware.TermID(c),
+		Month:   c.Query("month"),
+	}
+	monthly, err := h.service.Monthly(c.Request.Context(), req, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, monthly, nil, middleware.ExtractMeta(c))
+}
+
+// Student godoc
+// @Summary Per-student attendance history alias endpoint
+// @Tags Attendance
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param termId query string false "Term ID, defaults to the active term"
+// @Param startDate query string false "From date (YYYY-MM-DD)"
+// @Param endDate query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Router /attendance/student/{id} [get]
+func (h *AttendanceAliasHandler) Student(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+
+	req := dto.AttendanceStudentRequest{
+		StudentID: c.Param("id"),
+		TermID:    middleware.TermID(c),
+	}
+	from, err := parseDateParam(c.Query("startDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseDateParam(c.Query("endDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.StartDate = from
+	req.EndDate = to
+
+	history, err := h.service.StudentHistory(c.Request.Context(), req, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, history, nil, middleware.ExtractMeta(c))
+}
+
 func parseDateParam(raw string) (*time.Time, error) {
 	if raw == "" {
 		return nil, nil
@@ -30,6 +30,18 @@ func (m *attendanceAliasServiceMock) Summary(ctx context.Context, req dto.Attend
 	return m.summaryResp, false, nil
 }
 
+func (m *attendanceAliasServiceMock) Monthly(ctx context.Context, req dto.AttendanceMonthlyRequest, claims *models.JWTClaims) (*dto.AttendanceMonthlyResponse, error) {
+	return &dto.AttendanceMonthlyResponse{ClassID: req.ClassID, Month: req.Month}, nil
+}
+
+func (m *attendanceAliasServiceMock) StudentHistory(ctx context.Context, req dto.AttendanceStudentRequest, claims *models.JWTClaims) (*dto.AttendanceStudentResponse, error) {
+	return &dto.AttendanceStudentResponse{StudentID: req.StudentID, TermID: req.TermID}, nil
+}
+
+func (m *attendanceAliasServiceMock) Excuse(ctx context.Context, req dto.AttendanceExcuseRequest, claims *models.JWTClaims) (*models.DailyAttendance, error) {
+	return &models.DailyAttendance{EnrollmentID: req.EnrollmentID}, nil
+}
+
 func TestAttendanceAliasHandlerSummaryValidation(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	handler := NewAttendanceAliasHandler(&attendanceAliasServiceMock{})
@@ -55,3 +67,17 @@ func TestAttendanceAliasHandlerDailyInvalidDate(t *testing.T) {
 	handler.Daily(c)
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestAttendanceAliasHandlerStudentInvalidDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAttendanceAliasHandler(&attendanceAliasServiceMock{})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodGet, "/attendance/student/stu-1?termId=term-1&endDate=2024-13-01", nil)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: "stu-1"}}
+	c.Set(middleware.ContextUserKey, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+
+	handler.Student(c)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// DeviceKeyHeader carries the API key of a check-in device.
+const DeviceKeyHeader = "X-Device-Key"
+
+type attendanceCheckinService interface {
+	CheckIn(ctx context.Context, deviceKey string, req dto.AttendanceCheckinRequest) (*dto.AttendanceCheckinResponse, error)
+	IssueQRToken(ctx context.Context, studentID string) (*dto.AttendanceQRTokenResponse, error)
+}
+
+// AttendanceCheckinHandler exposes gate device check-ins.
+type AttendanceCheckinHandler struct {
+	service attendanceCheckinService
+}
+
+// NewAttendanceCheckinHandler constructs the handler.
+func NewAttendanceCheckinHandler(service attendanceCheckinService) *AttendanceCheckinHandler {
+	return &AttendanceCheckinHandler{service: service}
+}
+
+// CheckIn godoc
+// @Summary Record a gate check-in from a device
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param X-Device-Key header string true "Device API key"
+// @Param payload body dto.AttendanceCheckinRequest true "Student NIS or QR token with scan time"
+// @Success 201 {object} response.Envelope{data=dto.AttendanceCheckinResponse}
+// @Success 200 {object} response.Envelope{data=dto.AttendanceCheckinResponse} "Already recorded for the day"
+// @Router /attendance/checkin [post]
+func (h *AttendanceCheckinHandler) CheckIn(c *gin.Context) {
+	var req dto.AttendanceCheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid check-in payload"))
+		return
+	}
+	result, err := h.service.CheckIn(c.Request.Context(), c.GetHeader(DeviceKeyHeader), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	response.JSON(c, status, result, nil)
+}
+
+// QRToken godoc
+// @Summary Issue a signed check-in QR token for a student card
+// @Tags Attendance
+// @Produce json
+// @Param studentId path string true "Student ID"
+// @Success 200 {object} response.Envelope{data=dto.AttendanceQRTokenResponse}
+// @Router /attendance/checkin/qr/{studentId} [get]
+func (h *AttendanceCheckinHandler) QRToken(c *gin.Context) {
+	result, err := h.service.IssueQRToken(c.Request.Context(), c.Param("studentId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type attendanceImportService interface {
+	CreateJob(ctx context.Context, req service.BulkMarkDailyAttendanceRequest, actorID string) (*dto.AttendanceImportJobResponse, error)
+	GetStatus(ctx context.Context, id string) (*dto.AttendanceImportStatusResponse, error)
+}
+
+// AttendanceImportHandler exposes asynchronous bulk attendance imports.
+type AttendanceImportHandler struct {
+	service attendanceImportService
+}
+
+// NewAttendanceImportHandler constructs the handler.
+func NewAttendanceImportHandler(service attendanceImportService) *AttendanceImportHandler {
+	return &AttendanceImportHandler{service: service}
+}
+
+// Create godoc
+// @Summary Queue a bulk daily attendance import
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param payload body service.BulkMarkDailyAttendanceRequest true "Bulk daily attendance"
+// @Success 202 {object} response.Envelope{data=dto.AttendanceImportJobResponse}
+// @Router /attendance/imports [post]
+func (h *AttendanceImportHandler) Create(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	var req service.BulkMarkDailyAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid attendance import payload"))
+		return
+	}
+	job, err := h.service.CreateJob(c.Request.Context(), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusAccepted, job, nil)
+}
+
+// Status godoc
+// @Summary Get attendance import status and per-row conflicts
+// @Tags Attendance
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 200 {object} response.Envelope{data=dto.AttendanceImportStatusResponse}
+// @Router /attendance/imports/{id} [get]
+func (h *AttendanceImportHandler) Status(c *gin.Context) {
+	status, err := h.service.GetStatus(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, status, nil)
+}
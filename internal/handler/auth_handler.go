@@ -5,10 +5,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
 )
 
@@ -39,7 +39,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid login payload"))
 		return
 	}
-	req.IP = c.ClientIP()
+	req.IP = clientip.Resolve(c)
 	req.UserAgent = c.GetHeader("User-Agent")
 
 	res, err := h.service.Login(c.Request.Context(), req)
@@ -68,7 +68,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid refresh payload"))
 		return
 	}
-	req.IP = c.ClientIP()
+	req.IP = clientip.Resolve(c)
 	req.UserAgent = c.GetHeader("User-Agent")
 
 	res, err := h.service.RefreshToken(c.Request.Context(), req)
@@ -91,12 +91,11 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 // @Failure 401 {object} response.Envelope
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	jwtClaims := claims.(*models.JWTClaims)
 
 	var payload struct {
 		RefreshToken string `json:"refresh_token" binding:"required"`
@@ -106,7 +105,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	meta := models.LoginRequest{IP: c.ClientIP(), UserAgent: c.GetHeader("User-Agent")}
+	meta := models.LoginRequest{IP: clientip.Resolve(c), UserAgent: c.GetHeader("User-Agent")}
 	if err := h.service.Logout(c.Request.Context(), payload.RefreshToken, jwtClaims.UserID, meta); err != nil {
 		response.Error(c, err)
 		return
@@ -115,6 +114,38 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	response.NoContent(c)
 }
 
+// Sessions godoc
+// @Summary List the caller's sessions
+// @Description Live logins of the current user with their last activity, most recently active first
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Failure 401 {object} response.Envelope
+// @Router /auth/sessions [get]
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	sessions, err := h.service.Sessions(c.Request.Context(), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, sessions, nil)
+}
+
+// Heartbeat godoc
+// @Summary Record session activity
+// @Description Keeps the current session from running into the idle timeout; 401 once the session has ended
+// @Tags Authentication
+// @Success 204 {object} response.Envelope
+// @Failure 401 {object} response.Envelope
+// @Router /auth/sessions/heartbeat [post]
+func (h *AuthHandler) Heartbeat(c *gin.Context) {
+	if err := h.service.Heartbeat(c.Request.Context(), claimsFromContext(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
 // ChangePassword godoc
 // @Summary Change password
 // @Description Change password for current user
@@ -127,12 +158,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 // @Failure 401 {object} response.Envelope
 // @Router /auth/change-password [post]
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	jwtClaims := claims.(*models.JWTClaims)
 
 	var req models.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -207,13 +237,11 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 // @Failure 401 {object} response.Envelope
 // @Router /auth/me [get]
 func (h *AuthHandler) Me(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-
-	jwtClaims := claims.(*models.JWTClaims)
 	info := models.UserInfo{
 		ID:       jwtClaims.UserID,
 		Email:    jwtClaims.Email,
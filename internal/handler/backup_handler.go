@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type backupService interface {
+	Trigger(ctx context.Context, actorID string) (*models.Backup, error)
+	List(ctx context.Context) ([]models.Backup, error)
+	Get(ctx context.Context, id string) (*models.Backup, error)
+}
+
+// BackupHandler triggers database backups and reports their status.
+type BackupHandler struct {
+	service backupService
+}
+
+// NewBackupHandler constructs the handler.
+func NewBackupHandler(service backupService) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// Trigger godoc
+// @Summary Queue an encrypted backup of the database and a storage manifest
+// @Tags Backups
+// @Produce json
+// @Success 202 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Router /internal/backups [post]
+func (h *BackupHandler) Trigger(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	backup, err := h.service.Trigger(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusAccepted, backup, nil)
+}
+
+// List godoc
+// @Summary List recent backups with their status
+// @Tags Backups
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /internal/backups [get]
+func (h *BackupHandler) List(c *gin.Context) {
+	backups, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, backups, nil)
+}
+
+// Get godoc
+// @Summary Get a backup
+// @Tags Backups
+// @Produce json
+// @Param id path string true "Backup ID"
+// @Success 200 {object} response.Envelope
+// @Router /internal/backups/{id} [get]
+func (h *BackupHandler) Get(c *gin.Context) {
+	backup, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, backup, nil)
+}
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
@@ -51,7 +52,7 @@ func (h *CalendarAliasHandler) List(c *gin.Context) {
 	}
 
 	req := dto.CalendarAliasRequest{
-		TermID:  pickQuery(c, "term_id", "termId"),
+		TermID:  middleware.TermID(c),
 		ClassID: pickQuery(c, "class_id", "classId"),
 	}
 
@@ -86,7 +87,7 @@ func (h *CalendarAliasHandler) List(c *gin.Context) {
 		zap.String("user_id", claims.UserID),
 		zap.String("role", string(claims.Role)),
 	)
-	response.JSON(c, http.StatusOK, result, nil)
+	response.JSON(c, http.StatusOK, result, nil, middleware.ExtractMeta(c))
 }
 
 func parseCalendarDate(raw string) (*time.Time, error) {
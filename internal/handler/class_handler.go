@@ -117,6 +117,29 @@ func (h *ClassHandler) Update(c *gin.Context) {
 	response.JSON(c, http.StatusOK, class, nil)
 }
 
+// Patch godoc
+// @Summary Partially update class
+// @Tags Classes
+// @Accept json
+// @Produce json
+// @Param id path string true "Class ID"
+// @Param payload body service.PatchClassRequest true "Fields to change"
+// @Success 200 {object} response.Envelope
+// @Router /classes/{id} [patch]
+func (h *ClassHandler) Patch(c *gin.Context) {
+	var req service.PatchClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid payload"))
+		return
+	}
+	class, err := h.service.Patch(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, class, nil)
+}
+
 // Delete godoc
 // @Summary Delete class
 // @Tags Classes
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type classTimetableService interface {
+	Week(ctx context.Context, classID string, query dto.ClassTimetableQuery) (*dto.ClassTimetable, error)
+}
+
+// ClassTimetableHandler serves a class's effective weekly timetable.
+type ClassTimetableHandler struct {
+	service classTimetableService
+}
+
+// NewClassTimetableHandler constructs the handler.
+func NewClassTimetableHandler(service classTimetableService) *ClassTimetableHandler {
+	return &ClassTimetableHandler{service: service}
+}
+
+// Week godoc
+// @Summary Get a class's effective timetable for one week
+// @Description Daily schedules replace the published semester schedule slot by slot and exam sittings replace both. Holidays and dates outside the term have no cells; lessons whose teacher is on approved leave are flagged with the leave ID.
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Class ID"
+// @Param termId query string false "Term ID, defaults to the active term"
+// @Param week query string false "Any date (YYYY-MM-DD) in the week, defaults to the current week"
+// @Success 200 {object} response.Envelope{data=dto.ClassTimetable}
+// @Router /classes/{id}/timetable [get]
+func (h *ClassTimetableHandler) Week(c *gin.Context) {
+	query := dto.ClassTimetableQuery{TermID: middleware.TermID(c), Week: c.Query("week")}
+	timetable, err := h.service.Week(c.Request.Context(), c.Param("id"), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, timetable, nil)
+}
@@ -1,20 +1,33 @@
 package handler
 
 import (
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 
-	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 func claimsFromContext(c *gin.Context) *models.JWTClaims {
-	value, exists := c.Get(middleware.ContextUserKey)
-	if !exists {
-		return nil
+	return auth.ClaimsFromContext(c)
+}
+
+// applyDryRun honours the ?dryRun=true convention of bulk endpoints by marking the request context so
+// the service runs every validation and write, then rolls the transaction back.
+func applyDryRun(c *gin.Context) (bool, error) {
+	raw, ok := c.GetQuery("dryRun")
+	if !ok || raw == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, appErrors.Clone(appErrors.ErrValidation, "dryRun must be true or false")
 	}
-	claims, ok := value.(*models.JWTClaims)
-	if !ok {
-		return nil
+	if dryRun {
+		c.Request = c.Request.WithContext(database.WithDryRun(c.Request.Context()))
 	}
-	return claims
+	return dryRun, nil
 }
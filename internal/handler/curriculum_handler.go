@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type curriculumService interface {
+	ListTopics(ctx context.Context, subjectID, termID string) ([]models.CurriculumTopic, error)
+	CreateTopic(ctx context.Context, req dto.CurriculumTopicRequest) (*models.CurriculumTopic, error)
+	UpdateTopic(ctx context.Context, id string, req dto.CurriculumTopicUpdateRequest) (*models.CurriculumTopic, error)
+	DeleteTopic(ctx context.Context, id string) error
+	ClassProgress(ctx context.Context, classID, subjectID, termID string, claims *models.JWTClaims) ([]models.CurriculumTopicProgress, error)
+	MarkTaught(ctx context.Context, topicID string, req dto.CurriculumProgressRequest, claims *models.JWTClaims) (*models.CurriculumProgress, error)
+	UnmarkTaught(ctx context.Context, topicID, classID string, claims *models.JWTClaims) error
+	Coverage(ctx context.Context, query dto.CurriculumCoverageQuery) (*dto.CurriculumCoverageResponse, error)
+}
+
+// CurriculumHandler exposes syllabus topics, progress marking and coverage endpoints.
+type CurriculumHandler struct {
+	service curriculumService
+}
+
+// NewCurriculumHandler builds a new handler.
+func NewCurriculumHandler(service curriculumService) *CurriculumHandler {
+	return &CurriculumHandler{service: service}
+}
+
+// ListTopics godoc
+// @Summary List the syllabus topics of a subject in a term
+// @Tags Curriculum
+// @Produce json
+// @Param subjectId query string true "Subject ID"
+// @Param termId query string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /curriculum/topics [get]
+func (h *CurriculumHandler) ListTopics(c *gin.Context) {
+	items, err := h.service.ListTopics(c.Request.Context(), c.Query("subjectId"), c.Query("termId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// CreateTopic godoc
+// @Summary Add a syllabus topic
+// @Tags Curriculum
+// @Accept json
+// @Produce json
+// @Param payload body dto.CurriculumTopicRequest true "Topic payload"
+// @Success 201 {object} response.Envelope
+// @Router /curriculum/topics [post]
+func (h *CurriculumHandler) CreateTopic(c *gin.Context) {
+	var req dto.CurriculumTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid curriculum topic payload"))
+		return
+	}
+	item, err := h.service.CreateTopic(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, item)
+}
+
+// UpdateTopic godoc
+// @Summary Update a syllabus topic
+// @Tags Curriculum
+// @Accept json
+// @Produce json
+// @Param id path string true "Topic ID"
+// @Param payload body dto.CurriculumTopicUpdateRequest true "Topic payload"
+// @Success 200 {object} response.Envelope
+// @Router /curriculum/topics/{id} [put]
+func (h *CurriculumHandler) UpdateTopic(c *gin.Context) {
+	var req dto.CurriculumTopicUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid curriculum topic payload"))
+		return
+	}
+	item, err := h.service.UpdateTopic(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// DeleteTopic godoc
+// @Summary Delete a syllabus topic
+// @Tags Curriculum
+// @Param id path string true "Topic ID"
+// @Success 204
+// @Router /curriculum/topics/{id} [delete]
+func (h *CurriculumHandler) DeleteTopic(c *gin.Context) {
+	if err := h.service.DeleteTopic(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// ClassProgress godoc
+// @Summary List a subject's topics with a class's taught marks
+// @Tags Curriculum
+// @Produce json
+// @Param classId query string true "Class ID"
+// @Param subjectId query string true "Subject ID"
+// @Param termId query string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /curriculum/progress [get]
+func (h *CurriculumHandler) ClassProgress(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	items, err := h.service.ClassProgress(c.Request.Context(), c.Query("classId"), c.Query("subjectId"), c.Query("termId"), claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// MarkTaught godoc
+// @Summary Mark a topic as taught to a class
+// @Tags Curriculum
+// @Accept json
+// @Produce json
+// @Param id path string true "Topic ID"
+// @Param payload body dto.CurriculumProgressRequest true "Progress payload"
+// @Success 200 {object} response.Envelope
+// @Router /curriculum/topics/{id}/progress [post]
+func (h *CurriculumHandler) MarkTaught(c *gin.Context) {
+	var req dto.CurriculumProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid curriculum progress payload"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	item, err := h.service.MarkTaught(c.Request.Context(), c.Param("id"), req, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// UnmarkTaught godoc
+// @Summary Clear a topic's taught mark for a class
+// @Tags Curriculum
+// @Param id path string true "Topic ID"
+// @Param classId path string true "Class ID"
+// @Success 204
+// @Router /curriculum/topics/{id}/progress/{classId} [delete]
+func (h *CurriculumHandler) UnmarkTaught(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	if err := h.service.UnmarkTaught(c.Request.Context(), c.Param("id"), c.Param("classId"), claims); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Coverage godoc
+// @Summary Syllabus coverage report (planned vs. taught)
+// @Tags Curriculum
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param classId query string false "Class ID"
+// @Param subjectId query string false "Subject ID"
+// @Param teacherId query string false "Teacher ID"
+// @Param date query string false "Report date (YYYY-MM-DD). Defaults to today"
+// @Success 200 {object} response.Envelope
+// @Router /curriculum/coverage [get]
+func (h *CurriculumHandler) Coverage(c *gin.Context) {
+	var query dto.CurriculumCoverageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid curriculum coverage query"))
+		return
+	}
+	result, err := h.service.Coverage(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
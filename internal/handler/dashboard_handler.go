@@ -10,7 +10,6 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/middleware"
-	"github.com/noah-isme/sma-adp-api/internal/models"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
 )
@@ -34,7 +33,7 @@ func NewDashboardHandler(service dashboardService) *DashboardHandler {
 // @Summary Admin dashboard summary
 // @Tags Dashboard
 // @Produce json
-// @Param termId query string true "Term ID"
+// @Param termId query string false "Term ID, defaults to the active term"
 // @Success 200 {object} response.Envelope
 // @Router /dashboard [get]
 func (h *DashboardHandler) Admin(c *gin.Context) {
@@ -42,7 +41,7 @@ func (h *DashboardHandler) Admin(c *gin.Context) {
 		response.Error(c, appErrors.ErrInternal)
 		return
 	}
-	termID := strings.TrimSpace(c.Query("termId"))
+	termID := middleware.TermID(c)
 	if termID == "" {
 		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "termId is required"))
 		return
@@ -66,7 +65,7 @@ func (h *DashboardHandler) Admin(c *gin.Context) {
 // @Summary Teacher academics dashboard
 // @Tags Dashboard
 // @Produce json
-// @Param termId query string true "Term ID"
+// @Param termId query string false "Term ID, defaults to the active term"
 // @Param date query string false "Date (YYYY-MM-DD). Defaults to today"
 // @Success 200 {object} response.Envelope
 // @Router /dashboard/academics [get]
@@ -75,17 +74,12 @@ func (h *DashboardHandler) Teacher(c *gin.Context) {
 		response.Error(c, appErrors.ErrInternal)
 		return
 	}
-	claimsValue, exists := c.Get(middleware.ContextUserKey)
-	if !exists {
+	claims := claimsFromContext(c)
+	if claims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	claims, ok := claimsValue.(*models.JWTClaims)
-	if !ok {
-		response.Error(c, appErrors.ErrUnauthorized)
-		return
-	}
-	termID := strings.TrimSpace(c.Query("termId"))
+	termID := middleware.TermID(c)
 	if termID == "" {
 		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "termId is required"))
 		return
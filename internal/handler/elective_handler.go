@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type electiveService interface {
+	ListOfferings(ctx context.Context, query dto.ElectiveOfferingQuery) ([]models.ElectiveOffering, error)
+	GetOffering(ctx context.Context, id string) (*models.ElectiveOffering, error)
+	CreateOffering(ctx context.Context, req dto.ElectiveOfferingRequest) (*models.ElectiveOffering, error)
+	UpdateOffering(ctx context.Context, id string, req dto.ElectiveOfferingRequest) (*models.ElectiveOffering, error)
+	DeleteOffering(ctx context.Context, id string) error
+	SubmitSelections(ctx context.Context, req dto.ElectiveSelectionRequest, claims *models.JWTClaims) ([]models.ElectiveSelection, error)
+	ListSelections(ctx context.Context, query dto.ElectiveSelectionQuery, claims *models.JWTClaims) ([]models.ElectiveSelection, error)
+	Allocate(ctx context.Context, req dto.ElectiveAllocationRequest) (*dto.ElectiveAllocationReport, error)
+	Allocation(ctx context.Context, query dto.ElectiveAllocationQuery) (*dto.ElectiveAllocationReport, error)
+}
+
+// ElectiveHandler exposes elective offerings, students' ranked choices and their allocation.
+type ElectiveHandler struct {
+	service electiveService
+}
+
+// NewElectiveHandler constructs the handler.
+func NewElectiveHandler(service electiveService) *ElectiveHandler {
+	return &ElectiveHandler{service: service}
+}
+
+// List godoc
+// @Summary List a term's elective offerings
+// @Tags Electives
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param category query string false "Category"
+// @Success 200 {object} response.Envelope
+// @Router /electives [get]
+func (h *ElectiveHandler) List(c *gin.Context) {
+	var query dto.ElectiveOfferingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective offering query"))
+		return
+	}
+	offerings, err := h.service.ListOfferings(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, offerings, nil)
+}
+
+// Create godoc
+// @Summary Create an elective offering with its sections
+// @Tags Electives
+// @Accept json
+// @Produce json
+// @Param payload body dto.ElectiveOfferingRequest true "Elective offering"
+// @Success 201 {object} response.Envelope
+// @Router /electives [post]
+func (h *ElectiveHandler) Create(c *gin.Context) {
+	var req dto.ElectiveOfferingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective offering payload"))
+		return
+	}
+	offering, err := h.service.CreateOffering(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, offering, nil)
+}
+
+// Get godoc
+// @Summary Get an elective offering
+// @Tags Electives
+// @Produce json
+// @Param id path string true "Offering ID"
+// @Success 200 {object} response.Envelope
+// @Router /electives/{id} [get]
+func (h *ElectiveHandler) Get(c *gin.Context) {
+	offering, err := h.service.GetOffering(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, offering, nil)
+}
+
+// Update godoc
+// @Summary Update an elective offering and its sections
+// @Tags Electives
+// @Accept json
+// @Produce json
+// @Param id path string true "Offering ID"
+// @Param payload body dto.ElectiveOfferingRequest true "Elective offering"
+// @Success 200 {object} response.Envelope
+// @Router /electives/{id} [put]
+func (h *ElectiveHandler) Update(c *gin.Context) {
+	var req dto.ElectiveOfferingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective offering payload"))
+		return
+	}
+	offering, err := h.service.UpdateOffering(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, offering, nil)
+}
+
+// Delete godoc
+// @Summary Delete an elective offering without allocated students
+// @Tags Electives
+// @Param id path string true "Offering ID"
+// @Success 204
+// @Router /electives/{id} [delete]
+func (h *ElectiveHandler) Delete(c *gin.Context) {
+	if err := h.service.DeleteOffering(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// SubmitSelections godoc
+// @Summary Rank elective offerings of a category
+// @Tags Electives
+// @Accept json
+// @Produce json
+// @Param payload body dto.ElectiveSelectionRequest true "Ranked offerings"
+// @Success 200 {object} response.Envelope
+// @Router /electives/selections [put]
+func (h *ElectiveHandler) SubmitSelections(c *gin.Context) {
+	var req dto.ElectiveSelectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective selection payload"))
+		return
+	}
+	selections, err := h.service.SubmitSelections(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, selections, nil)
+}
+
+// ListSelections godoc
+// @Summary List ranked elective choices
+// @Tags Electives
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param category query string false "Category"
+// @Param enrollmentId query string false "Enrollment ID"
+// @Success 200 {object} response.Envelope
+// @Router /electives/selections [get]
+func (h *ElectiveHandler) ListSelections(c *gin.Context) {
+	var query dto.ElectiveSelectionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective selection query"))
+		return
+	}
+	selections, err := h.service.ListSelections(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, selections, nil)
+}
+
+// Allocate godoc
+// @Summary Allocate students to elective sections
+// @Tags Electives
+// @Accept json
+// @Produce json
+// @Param payload body dto.ElectiveAllocationRequest true "Allocation"
+// @Success 200 {object} response.Envelope
+// @Router /electives/allocate [post]
+func (h *ElectiveHandler) Allocate(c *gin.Context) {
+	var req dto.ElectiveAllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective allocation payload"))
+		return
+	}
+	report, err := h.service.Allocate(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// Allocation godoc
+// @Summary Report the stored elective allocation of a category
+// @Tags Electives
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param category query string true "Category"
+// @Success 200 {object} response.Envelope
+// @Router /electives/allocation [get]
+func (h *ElectiveHandler) Allocation(c *gin.Context) {
+	var query dto.ElectiveAllocationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid elective allocation query"))
+		return
+	}
+	report, err := h.service.Allocation(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// ErrorCatalogHandler exposes the registered error codes so clients can map them reliably.
+type ErrorCatalogHandler struct{}
+
+// NewErrorCatalogHandler constructs an ErrorCatalogHandler.
+func NewErrorCatalogHandler() *ErrorCatalogHandler {
+	return &ErrorCatalogHandler{}
+}
+
+// List godoc
+// @Summary List error codes
+// @Description Enumerate every error code with its HTTP status, description and the modules returning it
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /internal/error-catalog [get]
+func (h *ErrorCatalogHandler) List(c *gin.Context) {
+	response.JSON(c, http.StatusOK, appErrors.Catalog(), nil)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type eventRSVPService interface {
+	SetOptions(ctx context.Context, eventID string, req dto.EventRSVPOptionsRequest) (*models.CalendarEvent, error)
+	Respond(ctx context.Context, eventID string, req dto.EventRSVPRequest, claims *models.JWTClaims) (*models.EventRSVP, error)
+	CheckIn(ctx context.Context, eventID string, req dto.EventCheckInRequest, claims *models.JWTClaims) (*models.EventRSVP, error)
+	Report(ctx context.Context, eventID string) (*dto.EventRSVPReport, error)
+}
+
+type eventAttendanceExporter interface {
+	Export(ctx context.Context, eventID string, req dto.EventAttendanceExportRequest, actorID string) (*dto.ScheduleExportResponse, error)
+}
+
+// EventRSVPHandler exposes calendar event RSVPs and event-day check-ins.
+type EventRSVPHandler struct {
+	service eventRSVPService
+}
+
+// NewEventRSVPHandler constructs the handler.
+func NewEventRSVPHandler(service eventRSVPService) *EventRSVPHandler {
+	return &EventRSVPHandler{service: service}
+}
+
+// SetOptions godoc
+// @Summary Open or close RSVPs for a calendar event
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param payload body dto.EventRSVPOptionsRequest true "RSVP options"
+// @Success 200 {object} response.Envelope
+// @Router /calendar/events/{id}/rsvp-options [put]
+func (h *EventRSVPHandler) SetOptions(c *gin.Context) {
+	var req dto.EventRSVPOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid rsvp options payload"))
+		return
+	}
+	event, err := h.service.SetOptions(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, event, nil)
+}
+
+// Respond godoc
+// @Summary RSVP to a calendar event
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param payload body dto.EventRSVPRequest true "RSVP"
+// @Success 200 {object} response.Envelope
+// @Router /calendar/events/{id}/rsvp [put]
+func (h *EventRSVPHandler) Respond(c *gin.Context) {
+	var req dto.EventRSVPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid rsvp payload"))
+		return
+	}
+	rsvp, err := h.service.Respond(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, rsvp, nil)
+}
+
+// CheckIn godoc
+// @Summary Check an attendee in on the event day
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param payload body dto.EventCheckInRequest true "Attendee"
+// @Success 200 {object} response.Envelope
+// @Router /calendar/events/{id}/attendance [post]
+func (h *EventRSVPHandler) CheckIn(c *gin.Context) {
+	var req dto.EventCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid check-in payload"))
+		return
+	}
+	rsvp, err := h.service.CheckIn(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, rsvp, nil)
+}
+
+// Report godoc
+// @Summary List the RSVPs and check-ins of a calendar event
+// @Tags Calendar
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} response.Envelope
+// @Router /calendar/events/{id}/rsvps [get]
+func (h *EventRSVPHandler) Report(c *gin.Context) {
+	report, err := h.service.Report(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// EventAttendanceExportHandler serves calendar event attendance exports.
+type EventAttendanceExportHandler struct {
+	service eventAttendanceExporter
+}
+
+// NewEventAttendanceExportHandler constructs the handler.
+func NewEventAttendanceExportHandler(service eventAttendanceExporter) *EventAttendanceExportHandler {
+	return &EventAttendanceExportHandler{service: service}
+}
+
+// Export godoc
+// @Summary Export the attendance list of a calendar event
+// @Description Renders the RSVPs, walk-ins and check-ins of the event and returns a signed download URL.
+// @Tags Calendar
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param format query string false "pdf or xlsx" default(pdf)
+// @Success 200 {object} response.Envelope
+// @Router /calendar/events/{id}/attendance/export [get]
+func (h *EventAttendanceExportHandler) Export(c *gin.Context) {
+	var req dto.EventAttendanceExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export query"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	result, err := h.service.Export(c.Request.Context(), c.Param("id"), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type examExporter interface {
+	Export(ctx context.Context, periodID string, req dto.ExamExportRequest, actorID string) (*dto.ScheduleExportResponse, error)
+}
+
+// ExamExportHandler serves exam timetable exports per class or room.
+type ExamExportHandler struct {
+	service examExporter
+}
+
+// NewExamExportHandler constructs the handler.
+func NewExamExportHandler(svc *service.ExamExportService) *ExamExportHandler {
+	return &ExamExportHandler{service: svc}
+}
+
+// Export godoc
+// @Summary Export an exam timetable
+// @Description Renders the sittings of one class, or of one room when view=room, and returns a signed download URL.
+// @Tags Exams
+// @Produce json
+// @Param id path string true "Exam period ID"
+// @Param format query string false "pdf or xlsx" default(pdf)
+// @Param view query string false "class or room" default(class)
+// @Param classId query string false "Class ID (required when view=class)"
+// @Param room query string false "Room (required when view=room)"
+// @Success 200 {object} response.Envelope
+// @Router /exam-periods/{id}/export [get]
+func (h *ExamExportHandler) Export(c *gin.Context) {
+	var req dto.ExamExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export query"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	result, err := h.service.Export(c.Request.Context(), c.Param("id"), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type examService interface {
+	ListPeriods(ctx context.Context, termID string) ([]models.ExamPeriod, error)
+	CreatePeriod(ctx context.Context, req dto.ExamPeriodRequest) (*models.ExamPeriod, error)
+	DeletePeriod(ctx context.Context, id string) error
+	List(ctx context.Context, periodID string, query dto.ExamScheduleQuery) ([]models.ExamSchedule, error)
+	Create(ctx context.Context, periodID string, req dto.ExamScheduleRequest) (*models.ExamSchedule, error)
+	Delete(ctx context.Context, periodID, id string) error
+	Generate(ctx context.Context, periodID string, req dto.ExamGenerateRequest) (*dto.ExamGenerateResponse, error)
+}
+
+// ExamHandler exposes exam period and exam timetable endpoints.
+type ExamHandler struct {
+	service examService
+}
+
+// NewExamHandler builds a new handler.
+func NewExamHandler(service examService) *ExamHandler {
+	return &ExamHandler{service: service}
+}
+
+// ListPeriods godoc
+// @Summary List exam periods of a term
+// @Tags Exams
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /exam-periods [get]
+func (h *ExamHandler) ListPeriods(c *gin.Context) {
+	termID := c.Query("termId")
+	if termID == "" {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "termId is required"))
+		return
+	}
+	items, err := h.service.ListPeriods(c.Request.Context(), termID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// CreatePeriod godoc
+// @Summary Create an exam period
+// @Tags Exams
+// @Accept json
+// @Produce json
+// @Param payload body dto.ExamPeriodRequest true "Exam period payload"
+// @Success 201 {object} response.Envelope
+// @Router /exam-periods [post]
+func (h *ExamHandler) CreatePeriod(c *gin.Context) {
+	var req dto.ExamPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid exam period payload"))
+		return
+	}
+	item, err := h.service.CreatePeriod(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, item)
+}
+
+// DeletePeriod godoc
+// @Summary Delete an exam period and its sittings
+// @Tags Exams
+// @Param id path string true "Exam period ID"
+// @Success 204
+// @Router /exam-periods/{id} [delete]
+func (h *ExamHandler) DeletePeriod(c *gin.Context) {
+	if err := h.service.DeletePeriod(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// List godoc
+// @Summary List the sittings of an exam period
+// @Tags Exams
+// @Produce json
+// @Param id path string true "Exam period ID"
+// @Param classId query string false "Class ID"
+// @Param room query string false "Room"
+// @Param invigilatorId query string false "Invigilator teacher ID"
+// @Param date query string false "Exam date (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Router /exam-periods/{id}/schedules [get]
+func (h *ExamHandler) List(c *gin.Context) {
+	var query dto.ExamScheduleQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid exam schedule query"))
+		return
+	}
+	items, err := h.service.List(c.Request.Context(), c.Param("id"), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// Create godoc
+// @Summary Place a sitting in an exam period
+// @Description Rejects sittings that clash with other exams, with rooms or invigilators used by the regular timetable, or with the invigilator's unavailable hours.
+// @Tags Exams
+// @Accept json
+// @Produce json
+// @Param id path string true "Exam period ID"
+// @Param payload body dto.ExamScheduleRequest true "Exam schedule payload"
+// @Success 201 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Router /exam-periods/{id}/schedules [post]
+func (h *ExamHandler) Create(c *gin.Context) {
+	var req dto.ExamScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid exam schedule payload"))
+		return
+	}
+	item, err := h.service.Create(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, item)
+}
+
+// Delete godoc
+// @Summary Remove a sitting from an exam period
+// @Tags Exams
+// @Param id path string true "Exam period ID"
+// @Param examId path string true "Exam schedule ID"
+// @Success 204
+// @Router /exam-periods/{id}/schedules/{examId} [delete]
+func (h *ExamHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), c.Param("examId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Generate godoc
+// @Summary Generate the exam timetable of an exam period
+// @Description Places every pending class/subject exam into free sessions, rooms and invigilators and reports what could not be placed.
+// @Tags Exams
+// @Accept json
+// @Produce json
+// @Param id path string true "Exam period ID"
+// @Param payload body dto.ExamGenerateRequest true "Generation payload"
+// @Success 201 {object} response.Envelope
+// @Router /exam-periods/{id}/generate [post]
+func (h *ExamHandler) Generate(c *gin.Context) {
+	var req dto.ExamGenerateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid exam generation payload"))
+		return
+	}
+	result, err := h.service.Generate(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, result)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type exportTemplateService interface {
+	Columns() []dto.ExportReportColumns
+	List(ctx context.Context) ([]models.ExportTemplate, error)
+	Get(ctx context.Context, id string) (*models.ExportTemplate, error)
+	Create(ctx context.Context, req dto.ExportTemplateRequest, actor *models.JWTClaims) (*models.ExportTemplate, error)
+	Update(ctx context.Context, id string, req dto.ExportTemplateRequest, actor *models.JWTClaims) (*models.ExportTemplate, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ExportTemplateHandler manages the export layouts applied to generated reports.
+type ExportTemplateHandler struct {
+	service exportTemplateService
+}
+
+// NewExportTemplateHandler builds a new handler.
+func NewExportTemplateHandler(service exportTemplateService) *ExportTemplateHandler {
+	return &ExportTemplateHandler{service: service}
+}
+
+// Columns godoc
+// @Summary List the columns each report type offers to export templates
+// @Tags Reports
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /reports/templates/columns [get]
+func (h *ExportTemplateHandler) Columns(c *gin.Context) {
+	response.JSON(c, http.StatusOK, h.service.Columns(), nil)
+}
+
+// List godoc
+// @Summary List export templates
+// @Tags Reports
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /reports/templates [get]
+func (h *ExportTemplateHandler) List(c *gin.Context) {
+	templates, err := h.service.List(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, templates, nil)
+}
+
+// Get godoc
+// @Summary Get an export template
+// @Tags Reports
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} response.Envelope
+// @Router /reports/templates/{id} [get]
+func (h *ExportTemplateHandler) Get(c *gin.Context) {
+	template, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, template, nil)
+}
+
+// Create godoc
+// @Summary Create the export template of a report type
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param payload body dto.ExportTemplateRequest true "Template payload"
+// @Success 201 {object} response.Envelope
+// @Router /reports/templates [post]
+func (h *ExportTemplateHandler) Create(c *gin.Context) {
+	var req dto.ExportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export template payload"))
+		return
+	}
+	template, err := h.service.Create(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, template, nil)
+}
+
+// Update godoc
+// @Summary Replace an export template
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param payload body dto.ExportTemplateRequest true "Template payload"
+// @Success 200 {object} response.Envelope
+// @Router /reports/templates/{id} [put]
+func (h *ExportTemplateHandler) Update(c *gin.Context) {
+	var req dto.ExportTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export template payload"))
+		return
+	}
+	template, err := h.service.Update(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, template, nil)
+}
+
+// Delete godoc
+// @Summary Delete an export template so the default layout applies
+// @Tags Reports
+// @Param id path string true "Template ID"
+// @Success 204
+// @Router /reports/templates/{id} [delete]
+func (h *ExportTemplateHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
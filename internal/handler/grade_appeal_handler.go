@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type gradeAppealService interface {
+	Submit(ctx context.Context, studentID string, req dto.GradeAppealRequest, claims *models.JWTClaims) (*models.GradeAppeal, error)
+	List(ctx context.Context, query dto.GradeAppealQuery, claims *models.JWTClaims) ([]models.GradeAppeal, error)
+	Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.GradeAppeal, error)
+	Respond(ctx context.Context, id string, req dto.GradeAppealResponseRequest, claims *models.JWTClaims) (*models.GradeAppeal, error)
+}
+
+// GradeAppealHandler exposes grade appeals to appellants, subject teachers and administrators.
+type GradeAppealHandler struct {
+	service gradeAppealService
+}
+
+// NewGradeAppealHandler constructs the handler.
+func NewGradeAppealHandler(service gradeAppealService) *GradeAppealHandler {
+	return &GradeAppealHandler{service: service}
+}
+
+// SubmitOwn godoc
+// @Summary Appeal one of the student's own final grades
+// @Tags Grade Appeals
+// @Accept json
+// @Produce json
+// @Param payload body dto.GradeAppealRequest true "Appeal"
+// @Success 201 {object} response.Envelope
+// @Router /student/grade-appeals [post]
+func (h *GradeAppealHandler) SubmitOwn(c *gin.Context) {
+	h.submit(c, "")
+}
+
+// SubmitForStudent godoc
+// @Summary Appeal a final grade of a linked student
+// @Tags Grade Appeals
+// @Accept json
+// @Produce json
+// @Param studentId path string true "Student ID"
+// @Param payload body dto.GradeAppealRequest true "Appeal"
+// @Success 201 {object} response.Envelope
+// @Router /guardian/students/{studentId}/grade-appeals [post]
+func (h *GradeAppealHandler) SubmitForStudent(c *gin.Context) {
+	h.submit(c, c.Param("studentId"))
+}
+
+func (h *GradeAppealHandler) submit(c *gin.Context, studentID string) {
+	var req dto.GradeAppealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid grade appeal payload"))
+		return
+	}
+	appeal, err := h.service.Submit(c.Request.Context(), studentID, req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, appeal, nil)
+}
+
+// List godoc
+// @Summary List grade appeals visible to the caller
+// @Tags Grade Appeals
+// @Produce json
+// @Param studentId query string false "Student ID, required for guardians"
+// @Param teacherId query string false "Teacher ID, administrators only"
+// @Param status query string false "TEACHER_REVIEW, ADMIN_REVIEW, APPROVED or REJECTED"
+// @Success 200 {object} response.Envelope
+// @Router /grade-appeals [get]
+func (h *GradeAppealHandler) List(c *gin.Context) {
+	var query dto.GradeAppealQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid grade appeal query"))
+		return
+	}
+	appeals, err := h.service.List(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, appeals, nil)
+}
+
+// Get godoc
+// @Summary Get a grade appeal
+// @Tags Grade Appeals
+// @Produce json
+// @Param id path string true "Appeal ID"
+// @Success 200 {object} response.Envelope
+// @Router /grade-appeals/{id} [get]
+func (h *GradeAppealHandler) Get(c *gin.Context) {
+	appeal, err := h.service.Get(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, appeal, nil)
+}
+
+// Respond godoc
+// @Summary Record the subject teacher's recommendation and forward the appeal to super admins
+// @Tags Grade Appeals
+// @Accept json
+// @Produce json
+// @Param id path string true "Appeal ID"
+// @Param payload body dto.GradeAppealResponseRequest true "Recommendation"
+// @Success 200 {object} response.Envelope
+// @Router /grade-appeals/{id}/respond [post]
+func (h *GradeAppealHandler) Respond(c *gin.Context) {
+	var req dto.GradeAppealResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid grade appeal response"))
+		return
+	}
+	appeal, err := h.service.Respond(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, appeal, nil)
+}
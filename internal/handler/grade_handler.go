@@ -125,3 +125,86 @@ func (h *GradeHandler) Finalize(c *gin.Context) {
 	}
 	response.JSON(c, http.StatusOK, gin.H{"status": "finalized"}, nil)
 }
+
+// FinalizeClass godoc
+// @Summary Finalize final grades for every subject of a class
+// @Description Recalculates and locks final grades for all subjects of a class in one transaction. Subjects without a grade config are skipped and listed in the result.
+// @Tags Grades
+// @Accept json
+// @Produce json
+// @Param payload body service.FinalizeClassRequest true "Class finalize payload"
+// @Success 200 {object} response.Envelope
+// @Failure 412 {object} response.Envelope
+// @Router /grades/finalize-class [post]
+func (h *GradeHandler) FinalizeClass(c *gin.Context) {
+	var req service.FinalizeClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid payload"))
+		return
+	}
+	result, err := h.grades.FinalizeClass(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// Unfinalize godoc
+// @Summary Request reopening of finalized grades
+// @Description Files a GRADE_CORRECTION mutation for the finalized grades in scope. The grades become editable again once a super admin approves it through POST /mutations/{id}/review.
+// @Tags Grades
+// @Accept json
+// @Produce json
+// @Param payload body service.UnfinalizeGradesRequest true "Unfinalize payload"
+// @Success 202 {object} response.Envelope
+// @Failure 412 {object} response.Envelope
+// @Router /grades/unfinalize [post]
+func (h *GradeHandler) Unfinalize(c *gin.Context) {
+	var req service.UnfinalizeGradesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid payload"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	mutation, err := h.grades.RequestUnfinalize(c.Request.Context(), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusAccepted, mutation, nil)
+}
+
+// Remedial godoc
+// @Summary Record a remedial score
+// @Description Stores a remedial score for a finalized final grade below KKM. The final grade is kept and the report card shows the remedial outcome. Teachers may only record scores for subjects they teach in the enrollment's class.
+// @Tags Grades
+// @Accept json
+// @Produce json
+// @Param payload body service.RemedialGradeRequest true "Remedial payload"
+// @Success 200 {object} response.Envelope
+// @Failure 403 {object} response.Envelope
+// @Failure 412 {object} response.Envelope
+// @Router /grades/remedial [post]
+func (h *GradeHandler) Remedial(c *gin.Context) {
+	var req service.RemedialGradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid payload"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	final, err := h.grades.RecordRemedial(c.Request.Context(), req, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, final, nil)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type guardianService interface {
+	LinkStudent(ctx context.Context, guardianID string, req dto.LinkGuardianStudentRequest) ([]models.GuardianStudent, error)
+	UnlinkStudent(ctx context.Context, guardianID, studentID string) error
+	ListStudents(ctx context.Context, guardianID string) ([]models.GuardianStudent, error)
+	Contact(ctx context.Context, guardianID string) (*models.GuardianContact, error)
+	SetContact(ctx context.Context, guardianID string, req dto.GuardianContactRequest) (*models.GuardianContact, error)
+	DeleteContact(ctx context.Context, guardianID string) error
+	StudentAttendance(ctx context.Context, guardianID string, req dto.AttendanceStudentRequest) (*dto.AttendanceStudentResponse, error)
+	ReportCard(ctx context.Context, guardianID, studentID, termID string) (*models.StudentReportCard, error)
+	Announcements(ctx context.Context, guardianID string, page, pageSize int) ([]models.Announcement, *models.Pagination, error)
+	Calendar(ctx context.Context, guardianID string, start, end *time.Time) ([]models.CalendarEvent, *models.Pagination, error)
+}
+
+// GuardianHandler exposes guardian link management and the read-only guardian portal.
+type GuardianHandler struct {
+	service guardianService
+}
+
+// NewGuardianHandler constructs the handler.
+func NewGuardianHandler(service guardianService) *GuardianHandler {
+	return &GuardianHandler{service: service}
+}
+
+// ListLinks godoc
+// @Summary List students linked to a guardian
+// @Tags Guardians
+// @Produce json
+// @Param id path string true "Guardian user ID"
+// @Success 200 {object} response.Envelope
+// @Router /guardians/{id}/students [get]
+func (h *GuardianHandler) ListLinks(c *gin.Context) {
+	students, err := h.service.ListStudents(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, students, nil)
+}
+
+// Link godoc
+// @Summary Link a student to a guardian
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Param id path string true "Guardian user ID"
+// @Param payload body dto.LinkGuardianStudentRequest true "Student to link"
+// @Success 200 {object} response.Envelope
+// @Router /guardians/{id}/students [post]
+func (h *GuardianHandler) Link(c *gin.Context) {
+	var req dto.LinkGuardianStudentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid payload"))
+		return
+	}
+	students, err := h.service.LinkStudent(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, students, nil)
+}
+
+// Unlink godoc
+// @Summary Remove a guardian's access to a student
+// @Tags Guardians
+// @Param id path string true "Guardian user ID"
+// @Param studentId path string true "Student ID"
+// @Success 204
+// @Router /guardians/{id}/students/{studentId} [delete]
+func (h *GuardianHandler) Unlink(c *gin.Context) {
+	if err := h.service.UnlinkStudent(c.Request.Context(), c.Param("id"), c.Param("studentId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Contact godoc
+// @Summary Phone a guardian is messaged on
+// @Tags Guardians
+// @Produce json
+// @Param id path string true "Guardian user ID"
+// @Success 200 {object} response.Envelope
+// @Router /guardians/{id}/contact [get]
+func (h *GuardianHandler) Contact(c *gin.Context) {
+	contact, err := h.service.Contact(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, contact, nil)
+}
+
+// SetContact godoc
+// @Summary Set the phone a guardian is messaged on
+// @Tags Guardians
+// @Accept json
+// @Produce json
+// @Param id path string true "Guardian user ID"
+// @Param payload body dto.GuardianContactRequest true "Contact"
+// @Success 200 {object} response.Envelope
+// @Router /guardians/{id}/contact [put]
+func (h *GuardianHandler) SetContact(c *gin.Context) {
+	var req dto.GuardianContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid payload"))
+		return
+	}
+	contact, err := h.service.SetContact(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, contact, nil)
+}
+
+// DeleteContact godoc
+// @Summary Forget a guardian's phone
+// @Tags Guardians
+// @Param id path string true "Guardian user ID"
+// @Success 204
+// @Router /guardians/{id}/contact [delete]
+func (h *GuardianHandler) DeleteContact(c *gin.Context) {
+	if err := h.service.DeleteContact(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// MyStudents godoc
+// @Summary List the authenticated guardian's children
+// @Tags Guardian Portal
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /guardian/students [get]
+func (h *GuardianHandler) MyStudents(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	students, err := h.service.ListStudents(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, students, nil)
+}
+
+// StudentAttendance godoc
+// @Summary Attendance history for one of the guardian's children
+// @Tags Guardian Portal
+// @Produce json
+// @Param studentId path string true "Student ID"
+// @Param termId query string false "Term ID, defaults to the active term"
+// @Param startDate query string false "From date (YYYY-MM-DD)"
+// @Param endDate query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope{data=dto.AttendanceStudentResponse}
+// @Router /guardian/students/{studentId}/attendance [get]
+func (h *GuardianHandler) StudentAttendance(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	req := dto.AttendanceStudentRequest{StudentID: c.Param("studentId"), TermID: c.Query("termId")}
+	from, err := parseDateParam(c.Query("startDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseDateParam(c.Query("endDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.StartDate = from
+	req.EndDate = to
+
+	history, err := h.service.StudentAttendance(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, history, nil)
+}
+
+// ReportCard godoc
+// @Summary Report card for one of the guardian's children
+// @Tags Guardian Portal
+// @Produce json
+// @Param studentId path string true "Student ID"
+// @Param termId query string false "Term ID, defaults to the active term"
+// @Success 200 {object} response.Envelope
+// @Router /guardian/students/{studentId}/report-card [get]
+func (h *GuardianHandler) ReportCard(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	report, err := h.service.ReportCard(c.Request.Context(), claims.UserID, c.Param("studentId"), c.Query("termId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// Announcements godoc
+// @Summary Announcements visible to the guardian
+// @Tags Guardian Portal
+// @Produce json
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /guardian/announcements [get]
+func (h *GuardianHandler) Announcements(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	items, pagination, err := h.service.Announcements(c.Request.Context(), claims.UserID, parseQueryInt(c, "page", 1), parseQueryInt(c, "pageSize", 20))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, pagination)
+}
+
+// Calendar godoc
+// @Summary School calendar for the guardian's children
+// @Tags Guardian Portal
+// @Produce json
+// @Param startDate query string false "From date (YYYY-MM-DD), defaults to today"
+// @Param endDate query string false "To date (YYYY-MM-DD), defaults to 30 days after startDate"
+// @Success 200 {object} response.Envelope
+// @Router /guardian/calendar [get]
+func (h *GuardianHandler) Calendar(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	start, err := parseDateParam(c.Query("startDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	end, err := parseDateParam(c.Query("endDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	events, pagination, err := h.service.Calendar(c.Request.Context(), claims.UserID, start, end)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, events, pagination)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type intakeService interface {
+	List(ctx context.Context, query dto.IntakeQuery) ([]models.IntakeApplication, error)
+	Get(ctx context.Context, id string) (*models.IntakeApplication, error)
+	Create(ctx context.Context, req dto.IntakeApplicationRequest, claims *models.JWTClaims) (*models.IntakeApplication, error)
+	Update(ctx context.Context, id string, req dto.IntakeApplicationRequest) (*models.IntakeApplication, error)
+	AttachDocument(ctx context.Context, id, documentType string, upload service.ArchiveUpload, claims *models.JWTClaims) (*models.IntakeApplication, error)
+	Decide(ctx context.Context, id string, req dto.IntakeDecisionRequest, claims *models.JWTClaims) (*models.IntakeApplication, error)
+}
+
+// IntakeHandler exposes transfer-in applications, their documents and placement decisions.
+type IntakeHandler struct {
+	service intakeService
+}
+
+// NewIntakeHandler constructs the handler.
+func NewIntakeHandler(service intakeService) *IntakeHandler {
+	return &IntakeHandler{service: service}
+}
+
+// List godoc
+// @Summary List a term's intake applications
+// @Tags Intake
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param status query string false "PENDING, WAITLISTED, ACCEPTED or REJECTED"
+// @Success 200 {object} response.Envelope
+// @Router /intake [get]
+func (h *IntakeHandler) List(c *gin.Context) {
+	var query dto.IntakeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid intake query"))
+		return
+	}
+	applications, err := h.service.List(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, applications, nil)
+}
+
+// Create godoc
+// @Summary Record a transfer-in application
+// @Tags Intake
+// @Accept json
+// @Produce json
+// @Param payload body dto.IntakeApplicationRequest true "Application"
+// @Success 201 {object} response.Envelope
+// @Router /intake [post]
+func (h *IntakeHandler) Create(c *gin.Context) {
+	var req dto.IntakeApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid intake application payload"))
+		return
+	}
+	application, err := h.service.Create(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, application, nil)
+}
+
+// Get godoc
+// @Summary Get an intake application with its document checklist
+// @Tags Intake
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} response.Envelope
+// @Router /intake/{id} [get]
+func (h *IntakeHandler) Get(c *gin.Context) {
+	application, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, application, nil)
+}
+
+// Update godoc
+// @Summary Correct an undecided intake application
+// @Tags Intake
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param payload body dto.IntakeApplicationRequest true "Application"
+// @Success 200 {object} response.Envelope
+// @Router /intake/{id} [put]
+func (h *IntakeHandler) Update(c *gin.Context) {
+	var req dto.IntakeApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid intake application payload"))
+		return
+	}
+	application, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, application, nil)
+}
+
+// AttachDocument godoc
+// @Summary Upload a checklist document for an intake application
+// @Tags Intake
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param documentType formData string true "BIRTH_CERTIFICATE, FAMILY_CARD, REPORT_CARD or TRANSFER_LETTER"
+// @Param file formData file true "Document"
+// @Success 200 {object} response.Envelope
+// @Router /intake/{id}/documents [post]
+func (h *IntakeHandler) AttachDocument(c *gin.Context) {
+	upload, closeFile, err := formFileUpload(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer closeFile()
+	application, err := h.service.AttachDocument(c.Request.Context(), c.Param("id"), c.PostForm("documentType"), upload, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, application, nil)
+}
+
+// Decide godoc
+// @Summary Accept, waitlist or reject an intake application
+// @Tags Intake
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param payload body dto.IntakeDecisionRequest true "Decision"
+// @Success 200 {object} response.Envelope
+// @Router /intake/{id}/decision [post]
+func (h *IntakeHandler) Decide(c *gin.Context) {
+	var req dto.IntakeDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid intake decision payload"))
+		return
+	}
+	application, err := h.service.Decide(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, application, nil)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// IdempotencyKeyHeader identifies a legacy sync delivery so retries are not applied twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type legacySyncService interface {
+	SyncAttendance(ctx context.Context, idempotencyKey, source string, req dto.LegacyAttendanceSyncRequest) (*dto.LegacySyncResult, error)
+	SyncGrades(ctx context.Context, idempotencyKey, source string, req dto.LegacyGradeSyncRequest) (*dto.LegacySyncResult, error)
+	Reconciliation(ctx context.Context, query dto.LegacyReconciliationQuery) (*dto.LegacyReconciliationReport, error)
+}
+
+// LegacySyncHandler accepts the writes the legacy app still makes during the migration window.
+type LegacySyncHandler struct {
+	service legacySyncService
+}
+
+// NewLegacySyncHandler builds a new handler.
+func NewLegacySyncHandler(service legacySyncService) *LegacySyncHandler {
+	return &LegacySyncHandler{service: service}
+}
+
+// SyncAttendance godoc
+// @Summary Upsert daily attendance written by the legacy app
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Legacy sync API key"
+// @Param Idempotency-Key header string true "Unique key of this delivery"
+// @Param payload body dto.LegacyAttendanceSyncRequest true "Legacy attendance records"
+// @Success 200 {object} response.Envelope
+// @Router /internal/sync/attendance [post]
+func (h *LegacySyncHandler) SyncAttendance(c *gin.Context) {
+	var req dto.LegacyAttendanceSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid legacy attendance payload"))
+		return
+	}
+	result, err := h.service.SyncAttendance(c.Request.Context(), c.GetHeader(IdempotencyKeyHeader), c.GetString(middleware.ContextAPIClientKey), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// SyncGrades godoc
+// @Summary Upsert grades written by the legacy app
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Legacy sync API key"
+// @Param Idempotency-Key header string true "Unique key of this delivery"
+// @Param payload body dto.LegacyGradeSyncRequest true "Legacy grade records"
+// @Success 200 {object} response.Envelope
+// @Router /internal/sync/grades [post]
+func (h *LegacySyncHandler) SyncGrades(c *gin.Context) {
+	var req dto.LegacyGradeSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid legacy grade payload"))
+		return
+	}
+	result, err := h.service.SyncGrades(c.Request.Context(), c.GetHeader(IdempotencyKeyHeader), c.GetString(middleware.ContextAPIClientKey), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// Reconciliation godoc
+// @Summary Summarise legacy sync batches and the records they rejected
+// @Tags Internal
+// @Produce json
+// @Param X-API-Key header string true "Legacy sync API key"
+// @Param from query string false "First receive date (YYYY-MM-DD)"
+// @Param to query string false "Last receive date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} response.Envelope
+// @Router /internal/sync/reconciliation [get]
+func (h *LegacySyncHandler) Reconciliation(c *gin.Context) {
+	var query dto.LegacyReconciliationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid reconciliation query"))
+		return
+	}
+	report, err := h.service.Reconciliation(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type lessonGroupService interface {
+	List(ctx context.Context, termID string) ([]models.LessonGroup, error)
+	Get(ctx context.Context, id string) (*models.LessonGroup, error)
+	Create(ctx context.Context, req dto.LessonGroupRequest) (*models.LessonGroup, error)
+	Update(ctx context.Context, id string, req dto.LessonGroupRequest) (*models.LessonGroup, error)
+	Delete(ctx context.Context, id string) error
+	Sessions(ctx context.Context, id string) ([]dto.LessonGroupSession, error)
+	AddSession(ctx context.Context, id string, req dto.LessonGroupSessionRequest) (*dto.LessonGroupSession, error)
+	RemoveSession(ctx context.Context, id string, req dto.LessonGroupSessionRequest) error
+	Roster(ctx context.Context, id string, claims *models.JWTClaims) ([]dto.LessonGroupStudent, error)
+	MarkAttendance(ctx context.Context, id string, req dto.LessonGroupAttendanceRequest, claims *models.JWTClaims) (*service.BulkAttendanceResult, error)
+	AttendanceReport(ctx context.Context, id string, query dto.LessonGroupAttendanceQuery, claims *models.JWTClaims) (*dto.LessonGroupAttendanceReport, error)
+}
+
+// LessonGroupHandler exposes lessons shared by several classes, their sessions and attendance.
+type LessonGroupHandler struct {
+	service lessonGroupService
+}
+
+// NewLessonGroupHandler constructs the handler.
+func NewLessonGroupHandler(service lessonGroupService) *LessonGroupHandler {
+	return &LessonGroupHandler{service: service}
+}
+
+// List godoc
+// @Summary List a term's lesson groups
+// @Tags Lesson Groups
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups [get]
+func (h *LessonGroupHandler) List(c *gin.Context) {
+	groups, err := h.service.List(c.Request.Context(), c.Query("termId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, groups, nil)
+}
+
+// Create godoc
+// @Summary Create a lesson group
+// @Tags Lesson Groups
+// @Accept json
+// @Produce json
+// @Param payload body dto.LessonGroupRequest true "Lesson group"
+// @Success 201 {object} response.Envelope
+// @Router /lesson-groups [post]
+func (h *LessonGroupHandler) Create(c *gin.Context) {
+	var req dto.LessonGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group payload"))
+		return
+	}
+	group, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, group, nil)
+}
+
+// Get godoc
+// @Summary Get a lesson group
+// @Tags Lesson Groups
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id} [get]
+func (h *LessonGroupHandler) Get(c *gin.Context) {
+	group, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, group, nil)
+}
+
+// Update godoc
+// @Summary Update a lesson group's name, room and members
+// @Tags Lesson Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Param payload body dto.LessonGroupRequest true "Lesson group"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id} [put]
+func (h *LessonGroupHandler) Update(c *gin.Context) {
+	var req dto.LessonGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group payload"))
+		return
+	}
+	group, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, group, nil)
+}
+
+// Delete godoc
+// @Summary Delete a lesson group without sessions
+// @Tags Lesson Groups
+// @Param id path string true "Lesson group ID"
+// @Success 204
+// @Router /lesson-groups/{id} [delete]
+func (h *LessonGroupHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Sessions godoc
+// @Summary List a lesson group's weekly sessions
+// @Tags Lesson Groups
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id}/sessions [get]
+func (h *LessonGroupHandler) Sessions(c *gin.Context) {
+	sessions, err := h.service.Sessions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, sessions, nil)
+}
+
+// AddSession godoc
+// @Summary Schedule a weekly session for every member class
+// @Tags Lesson Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Param payload body dto.LessonGroupSessionRequest true "Session slot"
+// @Success 201 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Router /lesson-groups/{id}/sessions [post]
+func (h *LessonGroupHandler) AddSession(c *gin.Context) {
+	var req dto.LessonGroupSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group session payload"))
+		return
+	}
+	session, err := h.service.AddSession(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, session, nil)
+}
+
+// RemoveSession godoc
+// @Summary Remove a weekly session from every member class
+// @Tags Lesson Groups
+// @Param id path string true "Lesson group ID"
+// @Param dayOfWeek query string true "Day of week"
+// @Param timeSlot query string true "Time slot"
+// @Success 204
+// @Router /lesson-groups/{id}/sessions [delete]
+func (h *LessonGroupHandler) RemoveSession(c *gin.Context) {
+	var req dto.LessonGroupSessionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group session query"))
+		return
+	}
+	if err := h.service.RemoveSession(c.Request.Context(), c.Param("id"), req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Roster godoc
+// @Summary List the students taking part in a lesson group
+// @Tags Lesson Groups
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id}/roster [get]
+func (h *LessonGroupHandler) Roster(c *gin.Context) {
+	students, err := h.service.Roster(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, students, nil)
+}
+
+// MarkAttendance godoc
+// @Summary Mark attendance for a lesson group session
+// @Tags Lesson Groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Param payload body dto.LessonGroupAttendanceRequest true "Session marks"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id}/attendance [post]
+func (h *LessonGroupHandler) MarkAttendance(c *gin.Context) {
+	var req dto.LessonGroupAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group attendance payload"))
+		return
+	}
+	result, err := h.service.MarkAttendance(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// AttendanceReport godoc
+// @Summary Report attendance of a lesson group session
+// @Tags Lesson Groups
+// @Produce json
+// @Param id path string true "Lesson group ID"
+// @Param date query string true "Session date (YYYY-MM-DD)"
+// @Param timeSlot query string true "Time slot"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-groups/{id}/attendance [get]
+func (h *LessonGroupHandler) AttendanceReport(c *gin.Context) {
+	var query dto.LessonGroupAttendanceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid lesson group attendance query"))
+		return
+	}
+	report, err := h.service.AttendanceReport(c.Request.Context(), c.Param("id"), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
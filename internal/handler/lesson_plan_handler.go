@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type lessonPlanService interface {
+	List(ctx context.Context, query dto.LessonPlanQuery, claims *models.JWTClaims) ([]models.LessonPlan, error)
+	Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonPlan, error)
+	Create(ctx context.Context, req dto.LessonPlanRequest, claims *models.JWTClaims) (*models.LessonPlan, error)
+	Update(ctx context.Context, id string, req dto.LessonPlanUpdateRequest, claims *models.JWTClaims) (*models.LessonPlan, error)
+	Attach(ctx context.Context, id string, upload service.ArchiveUpload, claims *models.JWTClaims) (*models.LessonPlan, error)
+	Delete(ctx context.Context, id string, claims *models.JWTClaims) error
+	Submit(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonPlan, error)
+	Review(ctx context.Context, id string, req dto.ReviewLessonPlanRequest, reviewerID string) (*models.LessonPlan, error)
+}
+
+// LessonPlanHandler exposes weekly lesson plan and review endpoints.
+type LessonPlanHandler struct {
+	service lessonPlanService
+}
+
+// NewLessonPlanHandler builds a new handler.
+func NewLessonPlanHandler(service lessonPlanService) *LessonPlanHandler {
+	return &LessonPlanHandler{service: service}
+}
+
+// List godoc
+// @Summary List lesson plans
+// @Tags LessonPlans
+// @Produce json
+// @Param teacherId query string false "Teacher ID (ignored for teachers)"
+// @Param classId query string false "Class ID"
+// @Param subjectId query string false "Subject ID"
+// @Param termId query string false "Term ID"
+// @Param status query string false "DRAFT, SUBMITTED, APPROVED or REJECTED"
+// @Param week query string false "Any date of the week (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans [get]
+func (h *LessonPlanHandler) List(c *gin.Context) {
+	var query dto.LessonPlanQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid lesson plan query"))
+		return
+	}
+	items, err := h.service.List(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// Get godoc
+// @Summary Get a lesson plan
+// @Tags LessonPlans
+// @Produce json
+// @Param id path string true "Lesson plan ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans/{id} [get]
+func (h *LessonPlanHandler) Get(c *gin.Context) {
+	item, err := h.service.Get(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Create godoc
+// @Summary Draft a weekly lesson plan
+// @Tags LessonPlans
+// @Accept json
+// @Produce json
+// @Param payload body dto.LessonPlanRequest true "Lesson plan payload"
+// @Success 201 {object} response.Envelope
+// @Router /lesson-plans [post]
+func (h *LessonPlanHandler) Create(c *gin.Context) {
+	var req dto.LessonPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid lesson plan payload"))
+		return
+	}
+	item, err := h.service.Create(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, item)
+}
+
+// Update godoc
+// @Summary Edit a draft or rejected lesson plan
+// @Tags LessonPlans
+// @Accept json
+// @Produce json
+// @Param id path string true "Lesson plan ID"
+// @Param payload body dto.LessonPlanUpdateRequest true "Lesson plan payload"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans/{id} [put]
+func (h *LessonPlanHandler) Update(c *gin.Context) {
+	var req dto.LessonPlanUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid lesson plan payload"))
+		return
+	}
+	item, err := h.service.Update(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Attach godoc
+// @Summary Attach a document to a draft or rejected lesson plan
+// @Tags LessonPlans
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Lesson plan ID"
+// @Param file formData file true "Document"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans/{id}/attachment [post]
+func (h *LessonPlanHandler) Attach(c *gin.Context) {
+	upload, closeFile, err := formFileUpload(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer closeFile()
+	item, err := h.service.Attach(c.Request.Context(), c.Param("id"), upload, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Delete godoc
+// @Summary Delete a draft or rejected lesson plan
+// @Tags LessonPlans
+// @Param id path string true "Lesson plan ID"
+// @Success 204
+// @Router /lesson-plans/{id} [delete]
+func (h *LessonPlanHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), claimsFromContext(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// Submit godoc
+// @Summary Submit a lesson plan for review
+// @Tags LessonPlans
+// @Produce json
+// @Param id path string true "Lesson plan ID"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans/{id}/submit [post]
+func (h *LessonPlanHandler) Submit(c *gin.Context) {
+	item, err := h.service.Submit(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Review godoc
+// @Summary Approve or reject a submitted lesson plan
+// @Tags LessonPlans
+// @Accept json
+// @Produce json
+// @Param id path string true "Lesson plan ID"
+// @Param payload body dto.ReviewLessonPlanRequest true "Review decision"
+// @Success 200 {object} response.Envelope
+// @Router /lesson-plans/{id}/review [post]
+func (h *LessonPlanHandler) Review(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	var req dto.ReviewLessonPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid review payload"))
+		return
+	}
+	item, err := h.service.Review(c.Request.Context(), c.Param("id"), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
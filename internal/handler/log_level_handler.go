@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/logger"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type logLevelStore interface {
+	Snapshot() (string, []logger.ModuleLevel)
+	SetDefault(level zapcore.Level)
+	SetModule(module string, level zapcore.Level)
+	ResetModule(module string)
+}
+
+// LogLevelHandler reads and changes log levels while the application runs.
+type LogLevelHandler struct {
+	levels logLevelStore
+}
+
+// NewLogLevelHandler constructs a LogLevelHandler.
+func NewLogLevelHandler(levels logLevelStore) *LogLevelHandler {
+	return &LogLevelHandler{levels: levels}
+}
+
+// List godoc
+// @Summary List log levels
+// @Description Return the default log level and the per-module overrides
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /internal/log-levels [get]
+func (h *LogLevelHandler) List(c *gin.Context) {
+	response.JSON(c, http.StatusOK, h.snapshot(), nil)
+}
+
+// Update godoc
+// @Summary Change a log level
+// @Description Set the level of one module, or the default level when module is empty
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param payload body dto.LogLevelRequest true "Level payload"
+// @Success 200 {object} response.Envelope
+// @Router /internal/log-levels [put]
+func (h *LogLevelHandler) Update(c *gin.Context) {
+	var req dto.LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid log level payload"))
+		return
+	}
+	// ParseLevel reads an empty string as info, so an omitted level is rejected first.
+	raw := strings.TrimSpace(req.Level)
+	level, err := zapcore.ParseLevel(raw)
+	if raw == "" || err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "level must be one of debug, info, warn, error, dpanic, panic, fatal"))
+		return
+	}
+	if module := strings.TrimSpace(req.Module); module != "" {
+		h.levels.SetModule(module, level)
+	} else {
+		h.levels.SetDefault(level)
+	}
+	response.JSON(c, http.StatusOK, h.snapshot(), nil)
+}
+
+// Reset godoc
+// @Summary Remove a module log level override
+// @Tags Internal
+// @Param module path string true "Module"
+// @Success 204
+// @Router /internal/log-levels/{module} [delete]
+func (h *LogLevelHandler) Reset(c *gin.Context) {
+	h.levels.ResetModule(c.Param("module"))
+	response.NoContent(c)
+}
+
+func (h *LogLevelHandler) snapshot() dto.LogLevelsResponse {
+	base, modules := h.levels.Snapshot()
+	resp := dto.LogLevelsResponse{Default: base, Modules: make([]dto.ModuleLogLevel, 0, len(modules))}
+	for _, module := range modules {
+		resp.Modules = append(resp.Modules, dto.ModuleLogLevel{Module: module.Module, Level: module.Level})
+	}
+	return resp
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
 )
@@ -18,6 +19,7 @@ type mutationService interface {
 	List(ctx context.Context, query dto.MutationQuery, actor *models.JWTClaims) ([]models.Mutation, error)
 	Get(ctx context.Context, id string, actor *models.JWTClaims) (*models.Mutation, error)
 	Review(ctx context.Context, id string, req dto.ReviewMutationRequest, reviewerID string) (*models.Mutation, error)
+	Attach(ctx context.Context, id string, upload service.ArchiveUpload, actor *models.JWTClaims) (*models.MutationAttachment, error)
 }
 
 // MutationHandler exposes REST endpoints for mutation workflows.
@@ -162,3 +164,36 @@ func (h *MutationHandler) Review(c *gin.Context) {
 	}
 	response.JSON(c, http.StatusOK, mutation, nil)
 }
+
+// Attach godoc
+// @Summary Attach supporting evidence to a pending mutation
+// @Tags Mutations
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Mutation ID"
+// @Param file formData file true "Document"
+// @Success 201 {object} response.Envelope
+// @Router /mutations/{id}/attachments [post]
+func (h *MutationHandler) Attach(c *gin.Context) {
+	if h.service == nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "mutation service not configured"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	upload, closeFile, err := formFileUpload(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer closeFile()
+	attachment, err := h.service.Attach(c.Request.Context(), c.Param("id"), upload, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, attachment, nil)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type notificationService interface {
+	List(ctx context.Context, query dto.NotificationQuery, claims *models.JWTClaims) ([]models.Notification, error)
+	MarkRead(ctx context.Context, id string, claims *models.JWTClaims) error
+	RegisterDevice(ctx context.Context, req dto.RegisterDeviceRequest, claims *models.JWTClaims) (*models.DeviceToken, error)
+	UnregisterDevice(ctx context.Context, token string, claims *models.JWTClaims) error
+}
+
+// NotificationHandler exposes the caller's in-app notifications and push devices.
+type NotificationHandler struct {
+	service notificationService
+}
+
+// NewNotificationHandler builds a new handler.
+func NewNotificationHandler(service notificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// List godoc
+// @Summary List the caller's notifications
+// @Tags Notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Maximum number of notifications (default 50, max 200)"
+// @Success 200 {object} response.Envelope
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	var query dto.NotificationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid notification query"))
+		return
+	}
+	items, err := h.service.List(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Tags Notifications
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	if err := h.service.MarkRead(c.Request.Context(), c.Param("id"), claimsFromContext(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param payload body dto.RegisterDeviceRequest true "FCM token and platform (ANDROID, IOS or WEB)"
+// @Success 201 {object} response.Envelope
+// @Router /notifications/devices [post]
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	var req dto.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid device payload"))
+		return
+	}
+	device, err := h.service.RegisterDevice(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, device, nil)
+}
+
+// UnregisterDevice godoc
+// @Summary Stop push notifications to a device
+// @Tags Notifications
+// @Param token path string true "FCM token"
+// @Success 204
+// @Router /notifications/devices/{token} [delete]
+func (h *NotificationHandler) UnregisterDevice(c *gin.Context) {
+	if err := h.service.UnregisterDevice(c.Request.Context(), c.Param("token"), claimsFromContext(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// PermissionHandler exposes the caller's effective permissions.
+type PermissionHandler struct {
+	service *service.PermissionService
+}
+
+// NewPermissionHandler creates a new handler.
+func NewPermissionHandler(svc *service.PermissionService) *PermissionHandler {
+	return &PermissionHandler{service: svc}
+}
+
+// Get godoc
+// @Summary List the caller's permissions
+// @Description Routes the current token may use, with scope ALL or SELF (only the caller's own :id), derived from the RBAC guards
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Failure 401 {object} response.Envelope
+// @Router /auth/me/permissions [get]
+func (h *PermissionHandler) Get(c *gin.Context) {
+	permissions, err := h.service.For(claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, permissions, nil)
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type queueMonitor interface {
+	List() []dto.QueueStatus
+}
+
+// QueueHandler reports the background job queues so operators can size their worker pools.
+type QueueHandler struct {
+	queues queueMonitor
+}
+
+// NewQueueHandler constructs a QueueHandler.
+func NewQueueHandler(queues queueMonitor) *QueueHandler {
+	return &QueueHandler{queues: queues}
+}
+
+// List godoc
+// @Summary List background job queues
+// @Description Return each queue's depth, processing rate, average job duration, oldest queued age and recommended worker count
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /internal/queues [get]
+func (h *QueueHandler) List(c *gin.Context) {
+	response.JSON(c, http.StatusOK, h.queues.List(), nil)
+}
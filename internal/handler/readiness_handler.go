@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type storageStatusReader interface {
+	Statuses() []models.StorageStatus
+}
+
+// ReadinessHandler answers /ready from the health of the resources requests depend on.
+type ReadinessHandler struct {
+	storage storageStatusReader
+}
+
+// NewReadinessHandler constructs a readiness handler.
+func NewReadinessHandler(storage storageStatusReader) *ReadinessHandler {
+	return &ReadinessHandler{storage: storage}
+}
+
+// Ready godoc
+// @Summary Readiness check
+// @Description Lists the storage directories with their free space; 503 while any is unusable.
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /ready [get]
+func (h *ReadinessHandler) Ready(c *gin.Context) {
+	statuses := []models.StorageStatus{}
+	if h.storage != nil {
+		statuses = append(statuses, h.storage.Statuses()...)
+	}
+	code, status := http.StatusOK, "ok"
+	for _, storage := range statuses {
+		if !storage.Healthy {
+			code, status = http.StatusServiceUnavailable, "unavailable"
+			break
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "storage": statuses})
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
-	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
@@ -93,6 +93,8 @@ func (h *ReportHandler) ClassReport(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param payload body dto.ReportRequest true "Report request"
+// @Param force query bool false "Queue a new job even if an identical one is in flight or recent"
+// @Success 200 {object} response.Envelope "Identical job reused"
 // @Success 202 {object} response.Envelope
 // @Router /reports/generate [post]
 func (h *ReportHandler) GenerateReport(c *gin.Context) {
@@ -105,13 +107,11 @@ func (h *ReportHandler) GenerateReport(c *gin.Context) {
 		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid report payload"))
 		return
 	}
-	claimsValue, exists := c.Get(middleware.ContextUserKey)
-	if !exists {
-		response.Error(c, appErrors.ErrUnauthorized)
-		return
+	if force, err := strconv.ParseBool(c.DefaultQuery("force", "false")); err == nil && force {
+		req.Force = true
 	}
-	claims, ok := claimsValue.(*models.JWTClaims)
-	if !ok {
+	claims := claimsFromContext(c)
+	if claims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
@@ -120,6 +120,10 @@ func (h *ReportHandler) GenerateReport(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	if job.Deduplicated {
+		response.JSON(c, http.StatusOK, job, nil)
+		return
+	}
 	response.JSON(c, http.StatusAccepted, job, nil)
 }
 
@@ -135,13 +139,8 @@ func (h *ReportHandler) ReportStatus(c *gin.Context) {
 		response.Error(c, appErrors.Clone(appErrors.ErrInternal, "report service not configured"))
 		return
 	}
-	claimsValue, exists := c.Get(middleware.ContextUserKey)
-	if !exists {
-		response.Error(c, appErrors.ErrUnauthorized)
-		return
-	}
-	claims, ok := claimsValue.(*models.JWTClaims)
-	if !ok {
+	claims := claimsFromContext(c)
+	if claims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
@@ -191,6 +190,10 @@ func mimeForFormat(format models.ReportFormat) string {
 	switch format {
 	case models.ReportFormatPDF:
 		return "application/pdf"
+	case models.ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case models.ReportFormatZIP:
+		return "application/zip"
 	default:
 		return "text/csv"
 	}
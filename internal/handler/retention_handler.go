@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type retentionService interface {
+	Purge(ctx context.Context, actorID string) (*dto.RetentionPurgeReport, error)
+	ListHolds(ctx context.Context, query dto.LegalHoldQuery) ([]models.LegalHold, error)
+	CreateHold(ctx context.Context, req dto.CreateLegalHoldRequest, actorID string) (*models.LegalHold, error)
+	ReleaseHold(ctx context.Context, id, actorID string) (*models.LegalHold, error)
+}
+
+// RetentionHandler exposes the retention purge and legal holds to super admins.
+type RetentionHandler struct {
+	service retentionService
+}
+
+// NewRetentionHandler constructs the handler.
+func NewRetentionHandler(service retentionService) *RetentionHandler {
+	return &RetentionHandler{service: service}
+}
+
+// Purge godoc
+// @Summary Purge refresh tokens and audit logs past their retention period
+// @Description Rows under an active legal hold are kept. With dryRun=true nothing is deleted and the report shows what would be.
+// @Tags Retention
+// @Produce json
+// @Param dryRun query bool false "Only count the rows a purge would delete"
+// @Success 200 {object} response.Envelope
+// @Router /retention/purge [post]
+func (h *RetentionHandler) Purge(c *gin.Context) {
+	if _, err := applyDryRun(c); err != nil {
+		response.Error(c, err)
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	report, err := h.service.Purge(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// ListHolds godoc
+// @Summary List legal holds
+// @Tags Retention
+// @Produce json
+// @Param includeReleased query bool false "Include released holds"
+// @Success 200 {object} response.Envelope
+// @Router /retention/legal-holds [get]
+func (h *RetentionHandler) ListHolds(c *gin.Context) {
+	var query dto.LegalHoldQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid legal hold query"))
+		return
+	}
+	holds, err := h.service.ListHolds(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, holds, nil)
+}
+
+// CreateHold godoc
+// @Summary Place a legal hold on refresh tokens or audit logs
+// @Description Without userId the hold covers the whole data class.
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Param payload body dto.CreateLegalHoldRequest true "Legal hold"
+// @Success 201 {object} response.Envelope
+// @Router /retention/legal-holds [post]
+func (h *RetentionHandler) CreateHold(c *gin.Context) {
+	var req dto.CreateLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid legal hold payload"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	hold, err := h.service.CreateHold(c.Request.Context(), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, hold)
+}
+
+// ReleaseHold godoc
+// @Summary Release a legal hold
+// @Tags Retention
+// @Produce json
+// @Param id path string true "Legal hold ID"
+// @Success 200 {object} response.Envelope
+// @Router /retention/legal-holds/{id}/release [post]
+func (h *RetentionHandler) ReleaseHold(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	hold, err := h.service.ReleaseHold(c.Request.Context(), c.Param("id"), claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, hold, nil)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type scheduleClashReporter interface {
+	Report(ctx context.Context, query dto.ScheduleClashQuery) (*dto.ScheduleClashReport, error)
+}
+
+type scheduleClashExporter interface {
+	Export(ctx context.Context, req dto.ScheduleClashExportRequest, actorID string) (*dto.ScheduleExportResponse, error)
+}
+
+// ScheduleClashHandler serves the school-wide daily schedule clash report.
+type ScheduleClashHandler struct {
+	service scheduleClashReporter
+}
+
+// NewScheduleClashHandler constructs the handler.
+func NewScheduleClashHandler(svc *service.ScheduleClashService) *ScheduleClashHandler {
+	return &ScheduleClashHandler{service: svc}
+}
+
+// Clashes godoc
+// @Summary List double-booked daily schedules of a term
+// @Description Groups the term's daily schedules that book the same teacher, class or room twice in one day and slot.
+// @Tags Scheduler
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /schedules/clashes [get]
+func (h *ScheduleClashHandler) Clashes(c *gin.Context) {
+	var query dto.ScheduleClashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid schedule clash query"))
+		return
+	}
+	report, err := h.service.Report(c.Request.Context(), query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// ScheduleClashExportHandler serves exports of the clash report.
+type ScheduleClashExportHandler struct {
+	service scheduleClashExporter
+}
+
+// NewScheduleClashExportHandler constructs the handler.
+func NewScheduleClashExportHandler(svc *service.ScheduleClashService) *ScheduleClashExportHandler {
+	return &ScheduleClashExportHandler{service: svc}
+}
+
+// Export godoc
+// @Summary Export the daily schedule clash report
+// @Description Renders one row per clashing schedule and returns a signed download URL.
+// @Tags Scheduler
+// @Produce json
+// @Param termId query string true "Term ID"
+// @Param format query string false "pdf or xlsx" default(pdf)
+// @Success 200 {object} response.Envelope
+// @Router /schedules/clashes/export [get]
+func (h *ScheduleClashExportHandler) Export(c *gin.Context) {
+	var req dto.ScheduleClashExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export query"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	result, err := h.service.Export(c.Request.Context(), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type scheduleExporter interface {
+	Export(ctx context.Context, scheduleID string, req dto.ScheduleExportRequest, actorID string) (*dto.ScheduleExportResponse, error)
+}
+
+// ScheduleExportHandler serves timetable exports for semester schedules.
+type ScheduleExportHandler struct {
+	service scheduleExporter
+}
+
+// NewScheduleExportHandler constructs the handler.
+func NewScheduleExportHandler(svc *service.ScheduleExportService) *ScheduleExportHandler {
+	return &ScheduleExportHandler{service: svc}
+}
+
+// Export godoc
+// @Summary Export a semester schedule timetable
+// @Description Renders the class grid, or a teacher's term timetable when view=teacher, and returns a signed download URL.
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Semester schedule ID"
+// @Param format query string false "pdf or xlsx" default(pdf)
+// @Param view query string false "class or teacher" default(class)
+// @Param teacherId query string false "Teacher ID (required when view=teacher)"
+// @Success 200 {object} response.Envelope
+// @Router /semester-schedule/{id}/export [get]
+func (h *ScheduleExportHandler) Export(c *gin.Context) {
+	var req dto.ScheduleExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid export query"))
+		return
+	}
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	result, err := h.service.Export(c.Request.Context(), c.Param("id"), req, claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
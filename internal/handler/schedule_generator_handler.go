@@ -24,10 +24,14 @@ type schedulePreviewResponse struct {
 
 type scheduleGenerator interface {
 	Generate(ctx context.Context, req dto.GenerateScheduleRequest) (*dto.GenerateScheduleResponse, error)
-	Save(ctx context.Context, req dto.SaveScheduleRequest) (string, error)
+	Save(ctx context.Context, req dto.SaveScheduleRequest) (*dto.SaveScheduleResult, error)
+	EditProposalSlots(ctx context.Context, proposalID string, req dto.EditProposalSlotsRequest) (*dto.GenerateScheduleResponse, error)
+	Score(ctx context.Context, req dto.ScoreScheduleRequest) (*dto.ScoreScheduleResponse, error)
+	ExplainProposal(ctx context.Context, proposalID string) (*dto.ProposalExplanationResponse, error)
 	List(ctx context.Context, query dto.SemesterScheduleQuery) ([]models.SemesterSchedule, error)
 	GetSlots(ctx context.Context, id string) ([]models.SemesterScheduleSlot, error)
 	Delete(ctx context.Context, id string) error
+	Constraints() []dto.ScheduleConstraintInfo
 }
 
 // ScheduleGeneratorHandler exposes scheduler endpoints.
@@ -72,7 +76,9 @@ func (h *ScheduleGeneratorHandler) GenerateAlias(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param payload body dto.SaveScheduleRequest true "Save schedule payload"
+// @Param dryRun query bool false "Validate and report what would be written without persisting"
 // @Success 201 {object} response.Envelope
+// @Success 200 {object} response.Envelope "Dry run result"
 // @Router /schedule/save [post]
 func (h *ScheduleGeneratorHandler) Save(c *gin.Context) {
 	var req dto.SaveScheduleRequest
@@ -80,12 +86,85 @@ func (h *ScheduleGeneratorHandler) Save(c *gin.Context) {
 		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid save payload"))
 		return
 	}
-	id, err := h.service.Save(c.Request.Context(), req)
+	dryRun, err := applyDryRun(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	response.Created(c, gin.H{"scheduleId": id})
+	result, err := h.service.Save(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if dryRun {
+		response.JSON(c, http.StatusOK, result, nil)
+		return
+	}
+	response.Created(c, result)
+}
+
+// EditSlots godoc
+// @Summary Manually move, swap, or replace slots on a cached proposal
+// @Description Conflicts and score are recomputed after the edits; proposals with conflicts cannot be saved.
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param id path string true "Proposal ID"
+// @Param payload body dto.EditProposalSlotsRequest true "Slot edit operations"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/proposals/{id}/slots [patch]
+func (h *ScheduleGeneratorHandler) EditSlots(c *gin.Context) {
+	var req dto.EditProposalSlotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid slot edit payload"))
+		return
+	}
+	result, err := h.service.EditProposalSlots(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, schedulePreviewResponse{Mode: "preview", Proposal: result}, nil)
+}
+
+// Score godoc
+// @Summary Score a hand-built timetable
+// @Description Applies the generator's gap, load, constraint and conflict penalties to the given slots without creating a proposal.
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param payload body dto.ScoreScheduleRequest true "Timetable to score"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/score [post]
+func (h *ScheduleGeneratorHandler) Score(c *gin.Context) {
+	var req dto.ScoreScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid schedule score payload"))
+		return
+	}
+	result, err := h.service.Score(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// Explain godoc
+// @Summary Explain why a proposal has unfulfilled loads
+// @Description Each UNFULFILLED_LOAD conflict lists, per day/slot, the blocking checks evaluated (teacher windows, existing schedules, daily/weekly caps, class slot saturation, hard constraints).
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Proposal ID"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/proposals/{id}/explain [get]
+func (h *ScheduleGeneratorHandler) Explain(c *gin.Context) {
+	result, err := h.service.ExplainProposal(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
 }
 
 // List godoc
@@ -139,6 +218,17 @@ func (h *ScheduleGeneratorHandler) Delete(c *gin.Context) {
 	response.NoContent(c)
 }
 
+// Constraints godoc
+// @Summary List selectable scheduler constraints
+// @Description Names returned here may be passed in hardConstraints or softConstraints when generating.
+// @Tags Scheduler
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /schedule/constraints [get]
+func (h *ScheduleGeneratorHandler) Constraints(c *gin.Context) {
+	response.JSON(c, http.StatusOK, h.service.Constraints(), nil)
+}
+
 func (h *ScheduleGeneratorHandler) handleGenerate(c *gin.Context) {
 	var req dto.GenerateScheduleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	internalmiddleware "github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 )
 
 type scheduleGeneratorMock struct {
@@ -24,8 +25,20 @@ func (m *scheduleGeneratorMock) Generate(ctx context.Context, req dto.GenerateSc
 	return &dto.GenerateScheduleResponse{ProposalID: "proposal-1"}, nil
 }
 
-func (m *scheduleGeneratorMock) Save(ctx context.Context, req dto.SaveScheduleRequest) (string, error) {
-	return "", nil
+func (m *scheduleGeneratorMock) Save(ctx context.Context, req dto.SaveScheduleRequest) (*dto.SaveScheduleResult, error) {
+	return &dto.SaveScheduleResult{ScheduleID: "schedule-1", DryRun: database.IsDryRun(ctx)}, nil
+}
+
+func (m *scheduleGeneratorMock) EditProposalSlots(ctx context.Context, proposalID string, req dto.EditProposalSlotsRequest) (*dto.GenerateScheduleResponse, error) {
+	return &dto.GenerateScheduleResponse{ProposalID: proposalID}, nil
+}
+
+func (m *scheduleGeneratorMock) Score(ctx context.Context, req dto.ScoreScheduleRequest) (*dto.ScoreScheduleResponse, error) {
+	return &dto.ScoreScheduleResponse{}, nil
+}
+
+func (m *scheduleGeneratorMock) ExplainProposal(ctx context.Context, proposalID string) (*dto.ProposalExplanationResponse, error) {
+	return &dto.ProposalExplanationResponse{ProposalID: proposalID}, nil
 }
 
 func (m *scheduleGeneratorMock) List(ctx context.Context, query dto.SemesterScheduleQuery) ([]models.SemesterSchedule, error) {
@@ -40,6 +53,10 @@ func (m *scheduleGeneratorMock) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *scheduleGeneratorMock) Constraints() []dto.ScheduleConstraintInfo {
+	return nil
+}
+
 func TestScheduleGeneratorAliasSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockSvc := &scheduleGeneratorMock{}
@@ -104,6 +121,25 @@ func TestScheduleGeneratorAliasForbidden(t *testing.T) {
 	require.Equal(t, http.StatusForbidden, w.Code)
 }
 
+func TestScheduleGeneratorSaveDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := &ScheduleGeneratorHandler{service: &scheduleGeneratorMock{}}
+	router := gin.New()
+	router.POST("/schedule/save", handler.Save)
+
+	for query, want := range map[string]int{"": http.StatusCreated, "?dryRun=false": http.StatusCreated, "?dryRun=true": http.StatusOK, "?dryRun=maybe": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/schedule/save"+query, bytes.NewReader([]byte(`{"proposalId":"proposal-1"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, want, w.Code, query)
+		if want == http.StatusOK {
+			require.Contains(t, w.Body.String(), `"dryRun":true`)
+		}
+	}
+}
+
 func validGeneratorPayload() []byte {
 	return []byte(`{"termId":"2025","classId":"10A","timeSlotsPerDay":4,"days":[1,2],"subjectLoads":[{"subjectId":"math","teacherId":"t1","weeklyCount":4}]}`)
 }
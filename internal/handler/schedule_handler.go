@@ -161,6 +161,29 @@ func (h *ScheduleHandler) Update(c *gin.Context) {
 	response.JSON(c, http.StatusOK, schedule, nil)
 }
 
+// Patch godoc
+// @Summary Partially update schedule
+// @Tags Schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param payload body service.PatchScheduleRequest true "Fields to change"
+// @Success 200 {object} response.Envelope
+// @Router /schedules/{id} [patch]
+func (h *ScheduleHandler) Patch(c *gin.Context) {
+	var req service.PatchScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid payload"))
+		return
+	}
+	schedule, err := h.service.Patch(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, schedule, nil)
+}
+
 // Delete godoc
 // @Summary Delete schedule
 // @Tags Schedules
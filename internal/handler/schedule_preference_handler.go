@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
@@ -18,8 +20,13 @@ import (
 type schedulePreferenceService interface {
 	Get(ctx context.Context, teacherID string) (*models.TeacherPreference, error)
 	Upsert(ctx context.Context, teacherID string, req service.UpsertTeacherPreferenceRequest) (*models.TeacherPreference, error)
+	ExportSheet(ctx context.Context) ([]byte, error)
+	ImportSheet(ctx context.Context, termID string, data []byte) (*dto.TeacherPreferenceImportResult, error)
 }
 
+// maxPreferenceSheetSize bounds uploaded preference sheets.
+const maxPreferenceSheetSize = 5 << 20
+
 // SchedulePreferenceAliasHandler exposes /schedules/preferences alias endpoints.
 type SchedulePreferenceAliasHandler struct {
 	service schedulePreferenceService
@@ -79,6 +86,63 @@ func (h *SchedulePreferenceAliasHandler) Upsert(c *gin.Context) {
 	response.JSON(c, http.StatusOK, pref, nil)
 }
 
+// Export godoc
+// @Summary Download the teacher preference sheet
+// @Description Lists every active teacher with their stored preferences as an xlsx template to fill in and import.
+// @Tags Academics
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success 200 {file} file
+// @Router /schedules/preferences/export [get]
+func (h *SchedulePreferenceAliasHandler) Export(c *gin.Context) {
+	payload, err := h.service.ExportSheet(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="teacher-preferences.xlsx"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, mimeForFormat(models.ReportFormatXLSX), payload)
+}
+
+// Import godoc
+// @Summary Import a filled teacher preference sheet
+// @Description Validates unavailable windows against the term's slots and stores every valid row; the result reports each row.
+// @Tags Academics
+// @Accept multipart/form-data
+// @Produce json
+// @Param termId query string true "Term the windows refer to"
+// @Param file formData file true "Filled preference sheet (xlsx)"
+// @Success 200 {object} response.Envelope
+// @Router /schedules/preferences/import [post]
+func (h *SchedulePreferenceAliasHandler) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "file is required"))
+		return
+	}
+	if fileHeader.Size > maxPreferenceSheetSize {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "preference sheet is larger than 5 MiB"))
+		return
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to open file"))
+		return
+	}
+	defer src.Close() //nolint:errcheck
+	data, err := io.ReadAll(io.LimitReader(src, maxPreferenceSheetSize))
+	if err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to read file"))
+		return
+	}
+	result, err := h.service.ImportSheet(c.Request.Context(), c.Query("termId"), data)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
 func requireTeacherID(c *gin.Context) string {
 	teacherID := strings.TrimSpace(c.Query("teacher_id"))
 	if teacherID == "" {
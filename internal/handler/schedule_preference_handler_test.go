@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
@@ -30,6 +31,14 @@ func (m *schedulePreferenceServiceMock) Upsert(ctx context.Context, teacherID st
 	return m.resp, m.err
 }
 
+func (m *schedulePreferenceServiceMock) ExportSheet(ctx context.Context) ([]byte, error) {
+	return nil, m.err
+}
+
+func (m *schedulePreferenceServiceMock) ImportSheet(ctx context.Context, termID string, data []byte) (*dto.TeacherPreferenceImportResult, error) {
+	return nil, m.err
+}
+
 func TestSchedulePreferenceAliasHandlerRequiresTeacherID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	handler := NewSchedulePreferenceHandler(&schedulePreferenceServiceMock{})
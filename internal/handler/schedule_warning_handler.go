@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type scheduleWarningService interface {
+	Warnings(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error)
+	Revalidate(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error)
+}
+
+// ScheduleWarningHandler exposes the conflicts recorded against published semester schedules.
+type ScheduleWarningHandler struct {
+	service scheduleWarningService
+}
+
+// NewScheduleWarningHandler constructs the handler.
+func NewScheduleWarningHandler(svc scheduleWarningService) *ScheduleWarningHandler {
+	return &ScheduleWarningHandler{service: svc}
+}
+
+// Warnings godoc
+// @Summary List conflicts found in a published semester schedule
+// @Description Warnings are recorded when teacher assignments or preferences change after the schedule was published.
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Semester schedule ID"
+// @Success 200 {object} response.Envelope
+// @Router /semester-schedule/{id}/warnings [get]
+func (h *ScheduleWarningHandler) Warnings(c *gin.Context) {
+	warnings, err := h.service.Warnings(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, warnings, nil)
+}
+
+// Revalidate godoc
+// @Summary Re-check a published semester schedule now
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Semester schedule ID"
+// @Success 200 {object} response.Envelope
+// @Router /semester-schedule/{id}/revalidate [post]
+func (h *ScheduleWarningHandler) Revalidate(c *gin.Context) {
+	warnings, err := h.service.Revalidate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, warnings, nil)
+}
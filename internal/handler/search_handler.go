@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type searchService interface {
+	Search(ctx context.Context, req dto.SearchRequest, actor *models.JWTClaims) (*dto.SearchResponse, error)
+}
+
+// SearchHandler exposes the global search endpoint.
+type SearchHandler struct {
+	service searchService
+}
+
+// NewSearchHandler constructs the handler.
+func NewSearchHandler(service searchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// Search godoc
+// @Summary Search teachers, students, classes, subjects and archives
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search text (at least 2 characters)"
+// @Param types query string false "Comma-separated groups (teacher,student,class,subject,archive)"
+// @Param limit query int false "Hits per group (default 5, max 20)"
+// @Success 200 {object} response.Envelope{data=dto.SearchResponse}
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+
+	req := dto.SearchRequest{
+		Query: c.Query("q"),
+		Limit: parseQueryInt(c, "limit", 0),
+	}
+	for _, raw := range strings.Split(c.Query("types"), ",") {
+		if raw = strings.ToLower(strings.TrimSpace(raw)); raw != "" {
+			req.Types = append(req.Types, models.SearchType(raw))
+		}
+	}
+
+	result, err := h.service.Search(c.Request.Context(), req, claims)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
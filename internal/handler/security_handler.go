@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type securityDashboardService interface {
+	Dashboard(ctx context.Context) (*dto.SecurityDashboardResponse, error)
+}
+
+// SecurityHandler exposes the security section of the analytics dashboard.
+type SecurityHandler struct {
+	service securityDashboardService
+}
+
+// NewSecurityHandler constructs the handler.
+func NewSecurityHandler(service securityDashboardService) *SecurityHandler {
+	return &SecurityHandler{service: service}
+}
+
+// Dashboard godoc
+// @Summary Access denial summary
+// @Description Counts RBAC and ownership denials inside the alert window and lists the most recent ones.
+// @Tags Analytics
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /analytics/security [get]
+func (h *SecurityHandler) Dashboard(c *gin.Context) {
+	result, err := h.service.Dashboard(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
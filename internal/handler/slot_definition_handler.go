@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type slotDefinitionService interface {
+	List(ctx context.Context, termID string) ([]models.SlotDefinition, error)
+	Create(ctx context.Context, termID string, req dto.SlotDefinitionRequest) (*models.SlotDefinition, error)
+	Update(ctx context.Context, termID, id string, req dto.SlotDefinitionRequest) (*models.SlotDefinition, error)
+	Delete(ctx context.Context, termID, id string) error
+}
+
+// SlotDefinitionHandler exposes term slot time mapping endpoints.
+type SlotDefinitionHandler struct {
+	service slotDefinitionService
+}
+
+// NewSlotDefinitionHandler builds a new handler.
+func NewSlotDefinitionHandler(service slotDefinitionService) *SlotDefinitionHandler {
+	return &SlotDefinitionHandler{service: service}
+}
+
+// List godoc
+// @Summary List slot definitions of a term
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Term ID"
+// @Success 200 {object} response.Envelope
+// @Router /terms/{id}/slots [get]
+func (h *SlotDefinitionHandler) List(c *gin.Context) {
+	items, err := h.service.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, items, nil)
+}
+
+// Create godoc
+// @Summary Define a slot time for a term
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param id path string true "Term ID"
+// @Param payload body dto.SlotDefinitionRequest true "Slot definition payload"
+// @Success 201 {object} response.Envelope
+// @Router /terms/{id}/slots [post]
+func (h *SlotDefinitionHandler) Create(c *gin.Context) {
+	var req dto.SlotDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid slot definition payload"))
+		return
+	}
+	item, err := h.service.Create(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, item)
+}
+
+// Update godoc
+// @Summary Update a slot definition
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param id path string true "Term ID"
+// @Param slotId path string true "Slot definition ID"
+// @Param payload body dto.SlotDefinitionRequest true "Slot definition payload"
+// @Success 200 {object} response.Envelope
+// @Router /terms/{id}/slots/{slotId} [put]
+func (h *SlotDefinitionHandler) Update(c *gin.Context) {
+	var req dto.SlotDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid slot definition payload"))
+		return
+	}
+	item, err := h.service.Update(c.Request.Context(), c.Param("id"), c.Param("slotId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, item, nil)
+}
+
+// Delete godoc
+// @Summary Delete a slot definition
+// @Tags Scheduler
+// @Param id path string true "Term ID"
+// @Param slotId path string true "Slot definition ID"
+// @Success 204
+// @Router /terms/{id}/slots/{slotId} [delete]
+func (h *SlotDefinitionHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), c.Param("slotId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
@@ -27,7 +27,7 @@ func NewStudentHandler(students *service.StudentService) *StudentHandler {
 // @Summary List students
 // @Tags Students
 // @Produce json
-// @Param search query string false "Search by name or NIS"
+// @Param search query string false "Search by name or NIS; names also match with typos, closest first unless sorted"
 // @Param classId query string false "Filter by class"
 // @Param active query bool false "Filter by active state"
 // @Param page query int false "Page"
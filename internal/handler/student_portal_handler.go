@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type studentPortalService interface {
+	ProvisionAccount(ctx context.Context, studentID string, req dto.ProvisionStudentAccountRequest, actorID string, meta models.LoginRequest) (*models.User, error)
+	Schedule(ctx context.Context, userID string) (*dto.StudentScheduleResponse, error)
+	Attendance(ctx context.Context, userID string, req dto.AttendanceStudentRequest) (*dto.AttendanceStudentResponse, error)
+	ReportCard(ctx context.Context, userID, termID string) (*models.StudentReportCard, error)
+}
+
+// StudentPortalHandler exposes student account provisioning and the student self-service endpoints.
+type StudentPortalHandler struct {
+	service studentPortalService
+}
+
+// NewStudentPortalHandler constructs the handler.
+func NewStudentPortalHandler(service studentPortalService) *StudentPortalHandler {
+	return &StudentPortalHandler{service: service}
+}
+
+// ProvisionAccount godoc
+// @Summary Create the login account for a student
+// @Tags Students
+// @Accept json
+// @Produce json
+// @Param id path string true "Student ID"
+// @Param payload body dto.ProvisionStudentAccountRequest true "Login credentials"
+// @Success 201 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Router /students/{id}/account [post]
+func (h *StudentPortalHandler) ProvisionAccount(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	var req dto.ProvisionStudentAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid payload"))
+		return
+	}
+	meta := models.LoginRequest{IP: clientip.Resolve(c), UserAgent: c.GetHeader("User-Agent")}
+	user, err := h.service.ProvisionAccount(c.Request.Context(), c.Param("id"), req, claims.UserID, meta)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Created(c, user)
+}
+
+// Schedule godoc
+// @Summary Timetable of the authenticated student's class
+// @Tags Student Portal
+// @Produce json
+// @Success 200 {object} response.Envelope{data=dto.StudentScheduleResponse}
+// @Router /student/schedule [get]
+func (h *StudentPortalHandler) Schedule(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	schedule, err := h.service.Schedule(c.Request.Context(), claims.UserID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, schedule, nil)
+}
+
+// Attendance godoc
+// @Summary Attendance history of the authenticated student
+// @Tags Student Portal
+// @Produce json
+// @Param termId query string false "Term ID, defaults to the active enrollment's term"
+// @Param startDate query string false "From date (YYYY-MM-DD)"
+// @Param endDate query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope{data=dto.AttendanceStudentResponse}
+// @Router /student/attendance [get]
+func (h *StudentPortalHandler) Attendance(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	req := dto.AttendanceStudentRequest{TermID: c.Query("termId")}
+	from, err := parseDateParam(c.Query("startDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseDateParam(c.Query("endDate"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.StartDate = from
+	req.EndDate = to
+
+	history, err := h.service.Attendance(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, history, nil)
+}
+
+// ReportCard godoc
+// @Summary Report card of the authenticated student
+// @Tags Student Portal
+// @Produce json
+// @Param termId query string false "Term ID, defaults to the active enrollment's term"
+// @Success 200 {object} response.Envelope
+// @Router /student/report-card [get]
+func (h *StudentPortalHandler) ReportCard(c *gin.Context) {
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return
+	}
+	report, err := h.service.ReportCard(c.Request.Context(), claims.UserID, c.Query("termId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type subjectLoadPresetService interface {
+	List(ctx context.Context, filter models.SubjectLoadPresetFilter) ([]models.SubjectLoadPreset, error)
+	Get(ctx context.Context, id string) (*models.SubjectLoadPreset, error)
+	Create(ctx context.Context, req dto.SubjectLoadPresetRequest) (*models.SubjectLoadPreset, error)
+	Update(ctx context.Context, id string, req dto.SubjectLoadPresetRequest) (*models.SubjectLoadPreset, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// SubjectLoadPresetHandler manages the curriculum load presets schedule generation can reuse.
+type SubjectLoadPresetHandler struct {
+	service subjectLoadPresetService
+}
+
+// NewSubjectLoadPresetHandler builds a new handler.
+func NewSubjectLoadPresetHandler(service subjectLoadPresetService) *SubjectLoadPresetHandler {
+	return &SubjectLoadPresetHandler{service: service}
+}
+
+// List godoc
+// @Summary List subject load presets
+// @Tags Scheduler
+// @Produce json
+// @Param grade query string false "Grade"
+// @Param track query string false "Track"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/presets [get]
+func (h *SubjectLoadPresetHandler) List(c *gin.Context) {
+	presets, err := h.service.List(c.Request.Context(), models.SubjectLoadPresetFilter{Grade: c.Query("grade"), Track: c.Query("track")})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, presets, nil)
+}
+
+// Get godoc
+// @Summary Get a subject load preset
+// @Tags Scheduler
+// @Produce json
+// @Param id path string true "Preset ID"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/presets/{id} [get]
+func (h *SubjectLoadPresetHandler) Get(c *gin.Context) {
+	preset, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, preset, nil)
+}
+
+// Create godoc
+// @Summary Create a subject load preset
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param payload body dto.SubjectLoadPresetRequest true "Preset payload"
+// @Success 201 {object} response.Envelope
+// @Router /schedule/presets [post]
+func (h *SubjectLoadPresetHandler) Create(c *gin.Context) {
+	var req dto.SubjectLoadPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid subject load preset payload"))
+		return
+	}
+	preset, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, preset, nil)
+}
+
+// Update godoc
+// @Summary Replace a subject load preset
+// @Tags Scheduler
+// @Accept json
+// @Produce json
+// @Param id path string true "Preset ID"
+// @Param payload body dto.SubjectLoadPresetRequest true "Preset payload"
+// @Success 200 {object} response.Envelope
+// @Router /schedule/presets/{id} [put]
+func (h *SubjectLoadPresetHandler) Update(c *gin.Context) {
+	var req dto.SubjectLoadPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid subject load preset payload"))
+		return
+	}
+	preset, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, preset, nil)
+}
+
+// Delete godoc
+// @Summary Delete a subject load preset
+// @Tags Scheduler
+// @Param id path string true "Preset ID"
+// @Success 204
+// @Router /schedule/presets/{id} [delete]
+func (h *SubjectLoadPresetHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.NoContent(c)
+}
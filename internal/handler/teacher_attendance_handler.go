@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type teacherAttendanceService interface {
+	CheckIn(ctx context.Context, teacherID string, req dto.TeacherPresenceRequest, clientIP string) (*dto.TeacherAttendanceResponse, error)
+	CheckOut(ctx context.Context, teacherID string, req dto.TeacherPresenceRequest, clientIP string) (*dto.TeacherAttendanceResponse, error)
+	Recap(ctx context.Context, month string) (*dto.TeacherAttendanceRecapResponse, error)
+	TeacherMonth(ctx context.Context, teacherID, month string) (*dto.TeacherAttendanceMonthResponse, error)
+}
+
+// TeacherAttendanceHandler exposes teacher clock-in/out and monthly recaps.
+type TeacherAttendanceHandler struct {
+	service teacherAttendanceService
+}
+
+// NewTeacherAttendanceHandler constructs the handler.
+func NewTeacherAttendanceHandler(service teacherAttendanceService) *TeacherAttendanceHandler {
+	return &TeacherAttendanceHandler{service: service}
+}
+
+// CheckIn godoc
+// @Summary Clock in the authenticated teacher
+// @Tags Teacher Attendance
+// @Accept json
+// @Produce json
+// @Param payload body dto.TeacherPresenceRequest false "Device coordinates, required when a geofence is configured"
+// @Success 201 {object} response.Envelope{data=dto.TeacherAttendanceResponse}
+// @Success 200 {object} response.Envelope{data=dto.TeacherAttendanceResponse} "Already clocked in today"
+// @Router /teacher-attendance/checkin [post]
+func (h *TeacherAttendanceHandler) CheckIn(c *gin.Context) {
+	teacherID, req, ok := bindTeacherPresence(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.CheckIn(c.Request.Context(), teacherID, req, clientip.Resolve(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	response.JSON(c, status, result, nil)
+}
+
+// CheckOut godoc
+// @Summary Clock out the authenticated teacher
+// @Tags Teacher Attendance
+// @Accept json
+// @Produce json
+// @Param payload body dto.TeacherPresenceRequest false "Device coordinates, required when a geofence is configured"
+// @Success 200 {object} response.Envelope{data=dto.TeacherAttendanceResponse}
+// @Router /teacher-attendance/checkout [post]
+func (h *TeacherAttendanceHandler) CheckOut(c *gin.Context) {
+	teacherID, req, ok := bindTeacherPresence(c)
+	if !ok {
+		return
+	}
+	result, err := h.service.CheckOut(c.Request.Context(), teacherID, req, clientip.Resolve(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// Recap godoc
+// @Summary Monthly presence recap for all active teachers
+// @Tags Teacher Attendance
+// @Produce json
+// @Param month query string false "Month in YYYY-MM, defaults to the current month"
+// @Success 200 {object} response.Envelope{data=dto.TeacherAttendanceRecapResponse}
+// @Router /teacher-attendance/recap [get]
+func (h *TeacherAttendanceHandler) Recap(c *gin.Context) {
+	result, err := h.service.Recap(c.Request.Context(), c.Query("month"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// TeacherMonth godoc
+// @Summary Monthly presence log for one teacher
+// @Tags Teacher Attendance
+// @Produce json
+// @Param id path string true "Teacher ID"
+// @Param month query string false "Month in YYYY-MM, defaults to the current month"
+// @Success 200 {object} response.Envelope{data=dto.TeacherAttendanceMonthResponse}
+// @Router /teacher-attendance/teachers/{id} [get]
+func (h *TeacherAttendanceHandler) TeacherMonth(c *gin.Context) {
+	result, err := h.service.TeacherMonth(c.Request.Context(), c.Param("id"), c.Query("month"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// bindTeacherPresence resolves the caller and accepts an empty body when no coordinates are sent.
+func bindTeacherPresence(c *gin.Context) (string, dto.TeacherPresenceRequest, bool) {
+	var req dto.TeacherPresenceRequest
+	claims := claimsFromContext(c)
+	if claims == nil {
+		response.Error(c, appErrors.ErrUnauthorized)
+		return "", req, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid presence payload"))
+		return "", req, false
+	}
+	return claims.UserID, req, true
+}
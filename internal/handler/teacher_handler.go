@@ -33,8 +33,9 @@ func NewTeacherHandler(teachers *service.TeacherService, assignments *service.Te
 // @Summary List teachers
 // @Tags Teachers
 // @Produce json
-// @Param search query string false "Search by name/email/NIP"
+// @Param search query string false "Search by name/email/NIP; names also match with typos, closest first unless sorted"
 // @Param active query bool false "Filter by active status"
+// @Param termId query string false "Include assignment count, homeroom flag and weekly load for this term"
 // @Param page query int false "Page number"
 // @Param limit query int false "Page size"
 // @Param sort query string false "Sort field (full_name,email,created_at)"
@@ -64,6 +65,17 @@ func (h *TeacherHandler) List(c *gin.Context) {
 		filter.PageSize = size
 	}
 
+	if termID := strings.TrimSpace(c.Query("termId")); termID != "" {
+		filter.TermID = termID
+		teachers, pagination, err := h.teachers.ListWithTermLoad(c.Request.Context(), filter)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.JSON(c, http.StatusOK, teachers, pagination)
+		return
+	}
+
 	teachers, pagination, err := h.teachers.List(c.Request.Context(), filter)
 	if err != nil {
 		response.Error(c, err)
@@ -133,6 +145,29 @@ func (h *TeacherHandler) Update(c *gin.Context) {
 	response.JSON(c, http.StatusOK, teacher, nil)
 }
 
+// Patch godoc
+// @Summary Partially update teacher
+// @Tags Teachers
+// @Accept json
+// @Produce json
+// @Param id path string true "Teacher ID"
+// @Param payload body service.PatchTeacherRequest true "Fields to change"
+// @Success 200 {object} response.Envelope
+// @Router /teachers/{id} [patch]
+func (h *TeacherHandler) Patch(c *gin.Context) {
+	var req service.PatchTeacherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Wrap(err, appErrors.ErrValidation.Code, http.StatusBadRequest, "invalid teacher payload"))
+		return
+	}
+	teacher, err := h.teachers.Patch(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, teacher, nil)
+}
+
 // Delete godoc
 // @Summary Deactivate teacher
 // @Tags Teachers
@@ -151,16 +186,47 @@ func (h *TeacherHandler) Delete(c *gin.Context) {
 // @Summary List teacher assignments
 // @Tags Teacher Assignments
 // @Param id path string true "Teacher ID"
+// @Param term_id query string false "Filter by term"
+// @Param class_id query string false "Filter by class"
+// @Param subject_id query string false "Filter by subject"
+// @Param expand query string false "Relations to include (class,subject,term,teacher); all when omitted"
+// @Param page query int false "Page number"
+// @Param limit query int false "Page size"
+// @Param sort query string false "Sort field (term,class,subject,created_at)"
+// @Param order query string false "Sort order (asc/desc)"
 // @Produce json
 // @Success 200 {object} response.Envelope
 // @Router /teachers/{id}/assignments [get]
 func (h *TeacherHandler) ListAssignments(c *gin.Context) {
-	assignments, err := h.assignments.ListByTeacher(c.Request.Context(), c.Param("id"))
+	filter := models.TeacherAssignmentFilter{
+		TeacherID: c.Param("id"),
+		TermID:    strings.TrimSpace(c.Query("term_id")),
+		ClassID:   strings.TrimSpace(c.Query("class_id")),
+		SubjectID: strings.TrimSpace(c.Query("subject_id")),
+		SortBy:    c.Query("sort"),
+		SortOrder: c.Query("order"),
+	}
+	if expand, ok := c.GetQuery("expand"); ok {
+		filter.Expand = []string{}
+		for _, relation := range strings.Split(expand, ",") {
+			if relation = strings.ToLower(strings.TrimSpace(relation)); relation != "" {
+				filter.Expand = append(filter.Expand, relation)
+			}
+		}
+	}
+	if page, err := strconv.Atoi(c.DefaultQuery("page", "1")); err == nil {
+		filter.Page = page
+	}
+	if size, err := strconv.Atoi(c.DefaultQuery("limit", "20")); err == nil {
+		filter.PageSize = size
+	}
+
+	assignments, pagination, err := h.assignments.ListAssignments(c.Request.Context(), filter)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	response.JSON(c, http.StatusOK, assignments, nil)
+	response.JSON(c, http.StatusOK, assignments, pagination)
 }
 
 // CreateAssignment godoc
@@ -186,6 +252,23 @@ func (h *TeacherHandler) CreateAssignment(c *gin.Context) {
 	response.Created(c, assignment)
 }
 
+// GetAssignment godoc
+// @Summary Get teacher assignment
+// @Tags Teacher Assignments
+// @Produce json
+// @Param id path string true "Teacher ID"
+// @Param aid path string true "Assignment ID"
+// @Success 200 {object} response.Envelope
+// @Router /teachers/{id}/assignments/{aid} [get]
+func (h *TeacherHandler) GetAssignment(c *gin.Context) {
+	assignment, err := h.assignments.Get(c.Request.Context(), c.Param("id"), c.Param("aid"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, assignment, nil)
+}
+
 // DeleteAssignment godoc
 // @Summary Delete teacher assignment
 // @Tags Teacher Assignments
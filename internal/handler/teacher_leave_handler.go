@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type teacherLeaveService interface {
+	Request(ctx context.Context, req dto.TeacherLeaveRequest, claims *models.JWTClaims) (*models.TeacherLeave, error)
+	List(ctx context.Context, query dto.TeacherLeaveQuery, claims *models.JWTClaims) ([]models.TeacherLeave, error)
+	Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.TeacherLeave, error)
+	Approve(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error)
+	Reject(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error)
+	Cancel(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error)
+	Substitutions(ctx context.Context, id string) (*dto.TeacherLeaveSubstitutions, error)
+	Balance(ctx context.Context, year int, teacherID string, claims *models.JWTClaims) (*dto.TeacherLeaveBalanceReport, error)
+}
+
+// TeacherLeaveHandler exposes teacher leave requests, their review and leave balances.
+type TeacherLeaveHandler struct {
+	service teacherLeaveService
+}
+
+// NewTeacherLeaveHandler constructs the handler.
+func NewTeacherLeaveHandler(service teacherLeaveService) *TeacherLeaveHandler {
+	return &TeacherLeaveHandler{service: service}
+}
+
+// Request godoc
+// @Summary Request leave for a teacher
+// @Tags Teacher Leave
+// @Accept json
+// @Produce json
+// @Param payload body dto.TeacherLeaveRequest true "Leave request"
+// @Success 201 {object} response.Envelope
+// @Router /teacher-leaves [post]
+func (h *TeacherLeaveHandler) Request(c *gin.Context) {
+	var req dto.TeacherLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid leave payload"))
+		return
+	}
+	leave, err := h.service.Request(c.Request.Context(), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusCreated, leave, nil)
+}
+
+// List godoc
+// @Summary List teacher leaves
+// @Tags Teacher Leave
+// @Produce json
+// @Param teacherId query string false "Teacher ID, ignored for teachers"
+// @Param status query string false "PENDING, APPROVED, REJECTED or CANCELLED"
+// @Param from query string false "Start of the date range (YYYY-MM-DD)"
+// @Param to query string false "End of the date range (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves [get]
+func (h *TeacherLeaveHandler) List(c *gin.Context) {
+	var query dto.TeacherLeaveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid leave query"))
+		return
+	}
+	leaves, err := h.service.List(c.Request.Context(), query, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, leaves, nil)
+}
+
+// Get godoc
+// @Summary Get a teacher leave
+// @Tags Teacher Leave
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/{id} [get]
+func (h *TeacherLeaveHandler) Get(c *gin.Context) {
+	leave, err := h.service.Get(c.Request.Context(), c.Param("id"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, leave, nil)
+}
+
+// Approve godoc
+// @Summary Approve a pending teacher leave
+// @Tags Teacher Leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Param payload body dto.TeacherLeaveReviewRequest false "Review note"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/{id}/approve [post]
+func (h *TeacherLeaveHandler) Approve(c *gin.Context) {
+	h.decide(c, h.service.Approve)
+}
+
+// Reject godoc
+// @Summary Reject a pending teacher leave
+// @Tags Teacher Leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Param payload body dto.TeacherLeaveReviewRequest false "Review note"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/{id}/reject [post]
+func (h *TeacherLeaveHandler) Reject(c *gin.Context) {
+	h.decide(c, h.service.Reject)
+}
+
+// Cancel godoc
+// @Summary Cancel a pending leave or an approved leave that has not started
+// @Tags Teacher Leave
+// @Accept json
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Param payload body dto.TeacherLeaveReviewRequest false "Cancellation note"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/{id}/cancel [post]
+func (h *TeacherLeaveHandler) Cancel(c *gin.Context) {
+	h.decide(c, h.service.Cancel)
+}
+
+// Substitutions godoc
+// @Summary List the lessons a leave affects with substitution suggestions
+// @Tags Teacher Leave
+// @Produce json
+// @Param id path string true "Leave ID"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/{id}/substitutions [get]
+func (h *TeacherLeaveHandler) Substitutions(c *gin.Context) {
+	result, err := h.service.Substitutions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, result, nil)
+}
+
+// Balance godoc
+// @Summary Leave balance per teacher for a year
+// @Tags Teacher Leave
+// @Produce json
+// @Param year query int false "Year, defaults to the current year"
+// @Param teacherId query string false "Teacher ID, ignored for teachers"
+// @Success 200 {object} response.Envelope
+// @Router /teacher-leaves/balance [get]
+func (h *TeacherLeaveHandler) Balance(c *gin.Context) {
+	year := 0
+	if raw := c.Query("year"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			response.Error(c, appErrors.Clone(appErrors.ErrValidation, "year must be a number"))
+			return
+		}
+		year = parsed
+	}
+	report, err := h.service.Balance(c.Request.Context(), year, c.Query("teacherId"), claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, report, nil)
+}
+
+// decide binds an optional review note and applies the decision to the leave in the path.
+func (h *TeacherLeaveHandler) decide(c *gin.Context, apply func(context.Context, string, dto.TeacherLeaveReviewRequest, *models.JWTClaims) (*models.TeacherLeave, error)) {
+	var req dto.TeacherLeaveReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, appErrors.Clone(appErrors.ErrValidation, "invalid review payload"))
+		return
+	}
+	leave, err := apply(c.Request.Context(), c.Param("id"), req, claimsFromContext(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.JSON(c, http.StatusOK, leave, nil)
+}
@@ -6,10 +6,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 
-	"github.com/noah-isme/sma-adp-api/internal/middleware"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/service"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
 	"github.com/noah-isme/sma-adp-api/pkg/response"
 )
 
@@ -104,12 +104,11 @@ func (h *UserHandler) Get(c *gin.Context) {
 // @Failure 400 {object} response.Envelope
 // @Router /users [post]
 func (h *UserHandler) Create(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	jwtClaims := claims.(*models.JWTClaims)
 
 	var req service.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -117,7 +116,7 @@ func (h *UserHandler) Create(c *gin.Context) {
 		return
 	}
 
-	meta := models.LoginRequest{IP: c.ClientIP(), UserAgent: c.GetHeader("User-Agent")}
+	meta := models.LoginRequest{IP: clientip.Resolve(c), UserAgent: c.GetHeader("User-Agent")}
 	user, err := h.service.Create(c.Request.Context(), req, jwtClaims.UserID, meta)
 	if err != nil {
 		response.Error(c, err)
@@ -139,12 +138,11 @@ func (h *UserHandler) Create(c *gin.Context) {
 // @Failure 400 {object} response.Envelope
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	jwtClaims := claims.(*models.JWTClaims)
 
 	var req service.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -152,7 +150,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	meta := models.LoginRequest{IP: c.ClientIP(), UserAgent: c.GetHeader("User-Agent")}
+	meta := models.LoginRequest{IP: clientip.Resolve(c), UserAgent: c.GetHeader("User-Agent")}
 	user, err := h.service.Update(c.Request.Context(), c.Param("id"), req, jwtClaims.UserID, meta)
 	if err != nil {
 		response.Error(c, err)
@@ -172,14 +170,13 @@ func (h *UserHandler) Update(c *gin.Context) {
 // @Failure 404 {object} response.Envelope
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
-	claims, ok := c.Get(middleware.ContextUserKey)
-	if !ok {
+	jwtClaims := claimsFromContext(c)
+	if jwtClaims == nil {
 		response.Error(c, appErrors.ErrUnauthorized)
 		return
 	}
-	jwtClaims := claims.(*models.JWTClaims)
 
-	meta := models.LoginRequest{IP: c.ClientIP(), UserAgent: c.GetHeader("User-Agent")}
+	meta := models.LoginRequest{IP: clientip.Resolve(c), UserAgent: c.GetHeader("User-Agent")}
 	if err := h.service.Delete(c.Request.Context(), c.Param("id"), jwtClaims.UserID, meta); err != nil {
 		response.Error(c, err)
 		return
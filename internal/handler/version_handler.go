@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/pkg/buildinfo"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// VersionHandler reports which build is serving, so operators can verify a rollout.
+type VersionHandler struct {
+	features map[string]bool
+}
+
+// NewVersionHandler constructs a VersionHandler reporting the given feature flags.
+func NewVersionHandler(features map[string]bool) *VersionHandler {
+	return &VersionHandler{features: features}
+}
+
+// Get godoc
+// @Summary Build information
+// @Description Version, git commit, build date, Go version and enabled features of the serving build
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /version [get]
+func (h *VersionHandler) Get(c *gin.Context) {
+	response.JSON(c, http.StatusOK, dto.VersionResponse{Info: buildinfo.Get(), Features: h.features}, nil)
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+const (
+	// APIKeyHeader carries the key of a system-to-system client.
+	APIKeyHeader = "X-API-Key"
+	// ContextAPIClientKey is the gin context key storing the name of the authenticated API client.
+	ContextAPIClientKey = "apiClient"
+)
+
+// APIKey authenticates system clients by X-API-Key. keys maps client names to keys; the matching
+// client name is stored under ContextAPIClientKey. With no keys every request is rejected.
+func APIKey(keys map[string]string) gin.HandlerFunc {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader(APIKeyHeader))
+		matched := ""
+		// Compare against every key so the response time does not reveal which one matched.
+		for _, name := range names {
+			if subtle.ConstantTimeCompare([]byte(keys[name]), provided) == 1 && matched == "" {
+				matched = name
+			}
+		}
+		if len(provided) == 0 || matched == "" {
+			response.Error(c, appErrors.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Set(ContextAPIClientKey, matched)
+		c.Next()
+	}
+}
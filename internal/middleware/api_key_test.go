@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/internal/sync/grades", APIKey(map[string]string{"legacy": "key-1", "backfill": "key-2"}), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(ContextAPIClientKey))
+	})
+
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/internal/sync/grades", nil)
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call("").Code)
+	assert.Equal(t, http.StatusUnauthorized, call("key-3").Code)
+	w := call("key-2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "backfill", w.Body.String())
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
 )
 
 // Audit creates a middleware that records audit logs after successful requests.
@@ -21,8 +23,7 @@ func Audit(repo *repository.UserRepository, action, resource string) gin.Handler
 		}
 
 		var userID *string
-		if claims, ok := c.Get(ContextUserKey); ok {
-			user := claims.(*models.JWTClaims)
+		if user := auth.ClaimsFromContext(c); user != nil {
 			userID = &user.UserID
 		}
 
@@ -39,7 +40,7 @@ func Audit(repo *repository.UserRepository, action, resource string) gin.Handler
 			Resource:   resource,
 			ResourceID: nil,
 			NewValues:  body,
-			IPAddress:  c.ClientIP(),
+			IPAddress:  clientip.Resolve(c),
 			UserAgent:  c.GetHeader("User-Agent"),
 		})
 	}
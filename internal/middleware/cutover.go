@@ -10,6 +10,7 @@ import (
 const (
 	cutoverStageContextKey   = "cutover_stage"
 	cutoverSegmentContextKey = "cutover_segment"
+	cutoverFallbackHeader    = "X-Cutover-Fallback"
 )
 
 // CutoverStage annotates responses with rollout metadata headers.
@@ -19,6 +20,9 @@ func CutoverStage(cutoverSvc *service.CutoverService) gin.HandlerFunc {
 			headers := cutoverSvc.HeadersForRequest(c.Request)
 			applyHeader(c, headers.StageHeader, string(headers.Stage))
 			applyHeader(c, headers.SegmentHeader, headers.Segment)
+			if headers.Fallback {
+				applyHeader(c, cutoverFallbackHeader, "go")
+			}
 			c.Set(cutoverStageContextKey, headers.Stage)
 			c.Set(cutoverSegmentContextKey, headers.Segment)
 		}
@@ -8,7 +8,9 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/service"
 )
 
-// Metrics returns middleware that captures request metrics using the provided service.
+// Metrics returns middleware that captures request metrics using the provided service. It must run
+// after CutoverStage so requests are labelled with their stage; requests matching no route share
+// the service.RouteUnmatched label rather than their raw path.
 func Metrics(metricsSvc *service.MetricsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if metricsSvc == nil {
@@ -16,13 +18,11 @@ func Metrics(metricsSvc *service.MetricsService) gin.HandlerFunc {
 			return
 		}
 		start := time.Now()
+		route := c.FullPath()
+		stage, _ := CutoverMetadata(c)
+		done := metricsSvc.TrackInFlight(route, string(stage))
+		defer done()
 		c.Next()
-		duration := time.Since(start)
-		status := c.Writer.Status()
-		path := c.FullPath()
-		if path == "" {
-			path = c.Request.URL.Path
-		}
-		metricsSvc.ObserveHTTPRequest(c.Request.Method, path, status, duration)
+		metricsSvc.ObserveHTTPRequestInStage(c.Request.Method, route, string(stage), c.Writer.Status(), time.Since(start))
 	}
 }
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/requestid"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// PanicAlerter is notified of every recovered panic, e.g. to forward it to a webhook or error tracker.
+// It is called on the request goroutine and must not block.
+type PanicAlerter interface {
+	AlertPanic(ctx context.Context, event models.PanicEvent)
+}
+
+// Recovery turns panics into the standard 500 error envelope carrying the request ID, logs the stack,
+// counts the panic and forwards it to alerter when one is configured. Panics caused by the client
+// going away are logged without a response.
+func Recovery(logger *zap.Logger, metricsSvc *service.MetricsService, alerter PanicAlerter) gin.HandlerFunc {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			path := c.FullPath()
+			if path == "" {
+				path = c.Request.URL.Path
+			}
+			event := models.PanicEvent{
+				RequestID:  requestid.Value(c),
+				Method:     c.Request.Method,
+				Path:       path,
+				Value:      fmt.Sprint(recovered),
+				Stack:      string(debug.Stack()),
+				OccurredAt: time.Now().UTC(),
+			}
+			if brokenConnection(recovered) {
+				logger.Warn("client connection lost", zap.String("request_id", event.RequestID), zap.String("path", path), zap.String("error", event.Value))
+				c.Abort()
+				return
+			}
+
+			logger.Error("panic recovered",
+				zap.String("request_id", event.RequestID),
+				zap.String("method", event.Method),
+				zap.String("path", path),
+				zap.String("panic", event.Value),
+				zap.String("stack", event.Stack),
+			)
+			metricsSvc.RecordPanic(event.Method, path)
+			if alerter != nil {
+				alerter.AlertPanic(c.Request.Context(), event)
+			}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			var meta map[string]interface{}
+			if event.RequestID != "" {
+				meta = map[string]interface{}{"requestId": event.RequestID}
+			}
+			response.Error(c, appErrors.ErrInternal, meta)
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// brokenConnection reports whether the panic came from writing to a client that disconnected.
+func brokenConnection(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		var syscallErr *os.SyscallError
+		if errors.As(opErr, &syscallErr) {
+			msg := strings.ToLower(syscallErr.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+	return false
+}
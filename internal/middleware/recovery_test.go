@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/service"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/requestid"
+)
+
+type panicAlerterStub struct{ events []models.PanicEvent }
+
+func (s *panicAlerterStub) AlertPanic(ctx context.Context, event models.PanicEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestRecoveryRendersErrorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := service.NewMetricsService()
+	alerter := &panicAlerterStub{}
+	router := gin.New()
+	router.Use(requestid.Middleware(), Recovery(nil, metrics, alerter))
+	router.GET("/boom/:id", func(c *gin.Context) { panic("nil map write") })
+
+	req := httptest.NewRequest(http.MethodGet, "/boom/1", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+		Meta map[string]string `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Error.Code)
+	assert.Equal(t, "req-42", body.Meta["requestId"])
+
+	require.Len(t, alerter.events, 1)
+	assert.Equal(t, "req-42", alerter.events[0].RequestID)
+	assert.Equal(t, "/boom/:id", alerter.events[0].Path)
+	assert.Equal(t, "nil map write", alerter.events[0].Value)
+	assert.Contains(t, alerter.events[0].Stack, "recovery_test.go")
+
+	scrape := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, scrape.Body.String(), `http_panics_total{method="GET",path="/boom/:id"} 1`)
+}
+
+func TestRecoveryKeepsPartialResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery(nil, nil, nil))
+	router.GET("/stream", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("lost mid-stream")
+	})
+	router.GET("/gone", func(c *gin.Context) { panic(syscall.EPIPE) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gone", nil))
+	assert.False(t, strings.Contains(w.Body.String(), "INTERNAL_ERROR"))
+}
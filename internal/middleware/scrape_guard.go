@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// ScrapeGuardConfig restricts operational endpoints such as /metrics and /debug/pprof.
+type ScrapeGuardConfig struct {
+	Username string
+	Password string
+	// AllowedIPs accepts single addresses and CIDR ranges. It is matched against the peer address,
+	// not X-Forwarded-For, so the header cannot be spoofed to bypass it.
+	AllowedIPs []string
+}
+
+// ScrapeGuard enforces the configured IP allowlist and basic auth. Both apply when set; with
+// neither configured every request passes.
+func ScrapeGuard(cfg ScrapeGuardConfig) (gin.HandlerFunc, error) {
+	networks := make([]*net.IPNet, 0, len(cfg.AllowedIPs))
+	for _, raw := range cfg.AllowedIPs {
+		entry := strings.TrimSpace(raw)
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics allowlist entry %q: %w", raw, err)
+		}
+		networks = append(networks, network)
+	}
+	requireAuth := cfg.Username != "" || cfg.Password != ""
+
+	return func(c *gin.Context) {
+		if len(networks) > 0 && !ipAllowed(networks, c.RemoteIP()) {
+			response.Error(c, appErrors.ErrForbidden)
+			c.Abort()
+			return
+		}
+		if requireAuth {
+			user, pass, ok := c.Request.BasicAuth()
+			userMatch := subtle.ConstantTimeCompare([]byte(user), []byte(cfg.Username)) == 1
+			passMatch := subtle.ConstantTimeCompare([]byte(pass), []byte(cfg.Password)) == 1
+			if !ok || !userMatch || !passMatch {
+				c.Header("WWW-Authenticate", `Basic realm="metrics"`)
+				response.Error(c, appErrors.ErrUnauthorized)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}, nil
+}
+
+func ipAllowed(networks []*net.IPNet, raw string) bool {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
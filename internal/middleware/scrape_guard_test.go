@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, cfg ScrapeGuardConfig, prepare func(*http.Request)) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	guard, err := ScrapeGuard(cfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/metrics", guard, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.RemoteAddr = "10.1.2.3:5555"
+	if prepare != nil {
+		prepare(req)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestScrapeGuardAllowlist(t *testing.T) {
+	cfg := ScrapeGuardConfig{AllowedIPs: []string{"10.1.0.0/16", "127.0.0.1"}}
+	assert.Equal(t, http.StatusOK, scrape(t, cfg, nil))
+	assert.Equal(t, http.StatusForbidden, scrape(t, cfg, func(r *http.Request) {
+		r.RemoteAddr = "203.0.113.9:5555"
+		r.Header.Set("X-Forwarded-For", "10.1.2.3")
+	}))
+}
+
+func TestScrapeGuardBasicAuth(t *testing.T) {
+	cfg := ScrapeGuardConfig{Username: "prometheus", Password: "s3cret"}
+	assert.Equal(t, http.StatusUnauthorized, scrape(t, cfg, nil))
+	assert.Equal(t, http.StatusUnauthorized, scrape(t, cfg, func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") }))
+	assert.Equal(t, http.StatusOK, scrape(t, cfg, func(r *http.Request) { r.SetBasicAuth("prometheus", "s3cret") }))
+}
+
+func TestScrapeGuardRejectsInvalidAllowlist(t *testing.T) {
+	_, err := ScrapeGuard(ScrapeGuardConfig{AllowedIPs: []string{"not-an-ip"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
+)
+
+// ContextDenialReasonKey is set by access checks that reject a request so the audit knows which layer denied it.
+const ContextDenialReasonKey = auth.ContextDenialReasonKey
+
+// DenialRecorder persists access denials.
+type DenialRecorder interface {
+	RecordDenial(ctx context.Context, denial models.AccessDenial) error
+}
+
+// DenialAudit records every 403 response. Denials raised by RBAC are tagged as such; any other
+// forbidden response comes from a service-level ownership check.
+func DenialAudit(recorder DenialRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if recorder == nil || c.Writer.Status() != http.StatusForbidden {
+			return
+		}
+
+		denial := models.AccessDenial{
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.Path,
+			Reason:    models.AccessDenialOwnership,
+			IPAddress: clientip.Resolve(c),
+			UserAgent: c.GetHeader("User-Agent"),
+		}
+		if denial.Route == "" {
+			denial.Route = denial.Path
+		}
+		if reason, ok := c.Get(ContextDenialReasonKey); ok {
+			denial.Reason = reason.(models.AccessDenialReason)
+		}
+		if user := auth.ClaimsFromContext(c); user != nil {
+			denial.UserID = &user.UserID
+			denial.Role = user.Role
+		}
+		if id := c.Param("id"); id != "" {
+			denial.ResourceID = &id
+		}
+
+		_ = recorder.RecordDenial(c.Request.Context(), denial)
+	}
+}
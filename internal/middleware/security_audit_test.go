@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type denialRecorderStub struct {
+	denials []models.AccessDenial
+}
+
+func (s *denialRecorderStub) RecordDenial(ctx context.Context, denial models.AccessDenial) error {
+	s.denials = append(s.denials, denial)
+	return nil
+}
+
+func TestDenialAuditRecordsForbiddenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &denialRecorderStub{}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(ContextUserKey, &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+		c.Next()
+	})
+	router.Use(DenialAudit(recorder))
+	router.DELETE("/teachers/:id", RBAC(string(models.RoleSuperAdmin)), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/reports/status/:id", func(c *gin.Context) {
+		response.Error(c, appErrors.ErrForbidden)
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, target := range []struct{ method, path string }{
+		{http.MethodDelete, "/teachers/t-9"},
+		{http.MethodGet, "/reports/status/job-1"},
+		{http.MethodGet, "/ok"},
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(target.method, target.path, nil))
+	}
+
+	if len(recorder.denials) != 2 {
+		t.Fatalf("expected 2 denials, got %d", len(recorder.denials))
+	}
+	rbac := recorder.denials[0]
+	if rbac.Reason != models.AccessDenialRBAC || rbac.Route != "/teachers/:id" || rbac.ResourceID == nil || *rbac.ResourceID != "t-9" {
+		t.Fatalf("unexpected rbac denial: %+v", rbac)
+	}
+	if rbac.UserID == nil || *rbac.UserID != "teacher-1" || rbac.Role != models.RoleTeacher {
+		t.Fatalf("unexpected actor on rbac denial: %+v", rbac)
+	}
+	if owner := recorder.denials[1]; owner.Reason != models.AccessDenialOwnership || owner.Route != "/reports/status/:id" {
+		t.Fatalf("unexpected ownership denial: %+v", owner)
+	}
+}
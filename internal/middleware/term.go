@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+const (
+	resolvedTermKey = "resolved_term_id"
+	// resolvedTermMetaKey names the response meta field announcing a term the client did not send.
+	resolvedTermMetaKey = "resolved_term_id"
+)
+
+type activeTermResolver interface {
+	GetActiveTermID(ctx context.Context) (string, error)
+}
+
+// ResolveTerm fills in the active term for requests that send neither termId nor term_id. Handlers
+// read it through TermID and the response meta reports it as resolved_term_id. Without a configured
+// active term the request goes on unchanged, so handlers that need a term still ask for one.
+func ResolveTerm(terms activeTermResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if terms == nil || queryTermID(c) != "" {
+			c.Next()
+			return
+		}
+		termID, err := terms.GetActiveTermID(c.Request.Context())
+		// An unset or stale active term is left to the handler; only lookup failures end the request.
+		if err != nil && appErrors.FromError(err).Status >= http.StatusInternalServerError {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if err == nil && termID != "" {
+			c.Set(resolvedTermKey, termID)
+			ensureMeta(c)[resolvedTermMetaKey] = termID
+		}
+		c.Next()
+	}
+}
+
+// TermID returns the term the request names in termId or term_id, or else the one ResolveTerm
+// resolved for it.
+func TermID(c *gin.Context) string {
+	if termID := queryTermID(c); termID != "" {
+		return termID
+	}
+	return c.GetString(resolvedTermKey)
+}
+
+func queryTermID(c *gin.Context) string {
+	if termID := strings.TrimSpace(c.Query("termId")); termID != "" {
+		return termID
+	}
+	return strings.TrimSpace(c.Query("term_id"))
+}
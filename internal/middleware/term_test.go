@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+type activeTermStub struct {
+	termID string
+	err    error
+}
+
+func (s activeTermStub) GetActiveTermID(ctx context.Context) (string, error) {
+	return s.termID, s.err
+}
+
+func serveResolvedTerm(t *testing.T, terms activeTermResolver, target string) (int, response.Envelope) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/dashboard", ResolveTerm(terms), func(c *gin.Context) {
+		response.JSON(c, http.StatusOK, TermID(c), nil, ExtractMeta(c))
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	var envelope response.Envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	return w.Code, envelope
+}
+
+func TestResolveTermFillsInActiveTerm(t *testing.T) {
+	terms := activeTermStub{termID: "term-2026-1"}
+
+	code, envelope := serveResolvedTerm(t, terms, "/dashboard")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "term-2026-1", envelope.Data)
+	assert.Equal(t, "term-2026-1", envelope.Meta["resolved_term_id"])
+
+	// An explicit term, in either spelling, wins and is not reported as resolved.
+	for _, target := range []string{"/dashboard?termId=term-2025-2", "/dashboard?term_id=term-2025-2"} {
+		_, envelope = serveResolvedTerm(t, terms, target)
+		assert.Equal(t, "term-2025-2", envelope.Data)
+		assert.NotContains(t, envelope.Meta, "resolved_term_id")
+	}
+}
+
+func TestResolveTermWithoutActiveTerm(t *testing.T) {
+	code, envelope := serveResolvedTerm(t, activeTermStub{err: appErrors.Clone(appErrors.ErrNotFound, "active_term_id not configured")}, "/dashboard")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "", envelope.Data)
+	assert.Nil(t, envelope.Meta)
+
+	code, _ = serveResolvedTerm(t, activeTermStub{err: errors.New("connection refused")}, "/dashboard")
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// routeBudget is the time budget of the routes under prefix, optionally for one method only.
+type routeBudget struct {
+	method string
+	prefix string
+	budget time.Duration
+}
+
+// Timeout bounds every request by a deadline on its context, so repositories cancel their queries
+// once the client would have given up. routes maps "[METHOD ]/path/prefix" to a budget that
+// replaces fallback for matching route patterns; the longest prefix wins and a zero budget means no
+// deadline. Handlers are not interrupted: a request whose budget runs out before anything was
+// written gets a 504 envelope once its handler returns.
+func Timeout(fallback time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	budgets := make([]routeBudget, 0, len(routes))
+	for key, budget := range routes {
+		entry := routeBudget{prefix: strings.TrimSpace(key), budget: budget}
+		if method, prefix, found := strings.Cut(entry.prefix, " "); found {
+			entry.method = strings.ToUpper(method)
+			entry.prefix = strings.TrimSpace(prefix)
+		}
+		budgets = append(budgets, entry)
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		if len(budgets[i].prefix) != len(budgets[j].prefix) {
+			return len(budgets[i].prefix) > len(budgets[j].prefix)
+		}
+		return budgets[i].method > budgets[j].method
+	})
+
+	return func(c *gin.Context) {
+		budget := budgetFor(budgets, c.Request.Method, c.FullPath(), fallback)
+		if budget <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), budget)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			response.Error(c, appErrors.ErrTimeout)
+			c.Abort()
+		}
+	}
+}
+
+// budgetFor picks the budget of the longest matching prefix; budgets is sorted longest first with
+// method-specific entries ahead of the others.
+func budgetFor(budgets []routeBudget, method, route string, fallback time.Duration) time.Duration {
+	if route == "" {
+		return fallback
+	}
+	for _, entry := range budgets {
+		if entry.method != "" && entry.method != method {
+			continue
+		}
+		if matchesPrefix(route, entry.prefix) {
+			return entry.budget
+		}
+	}
+	return fallback
+}
+
+// matchesPrefix matches whole path segments, so "/reports" covers "/reports/generate" but not
+// "/reportsx".
+func matchesPrefix(route, prefix string) bool {
+	if !strings.HasPrefix(route, prefix) {
+		return false
+	}
+	return len(route) == len(prefix) || strings.HasSuffix(prefix, "/") || route[len(prefix)] == '/'
+}
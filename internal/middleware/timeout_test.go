@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+func TestTimeoutPicksRouteBudgets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(5*time.Second, map[string]time.Duration{
+		"/api/v1/reports":               10 * time.Second,
+		"POST /api/v1/reports/generate": 30 * time.Second,
+		"/api/v1/export":                0,
+	}))
+	budget := func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if !ok {
+			c.String(http.StatusOK, "none")
+			return
+		}
+		c.String(http.StatusOK, time.Until(deadline).Round(time.Second).String())
+	}
+	router.POST("/api/v1/reports/generate", budget)
+	router.GET("/api/v1/reports/generate", budget)
+	router.GET("/api/v1/reportsx", budget)
+	router.GET("/api/v1/export/:token", budget)
+
+	call := func(method, path string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "30s", call(http.MethodPost, "/api/v1/reports/generate"))
+	assert.Equal(t, "10s", call(http.MethodGet, "/api/v1/reports/generate"))
+	assert.Equal(t, "5s", call(http.MethodGet, "/api/v1/reportsx"))
+	assert.Equal(t, "none", call(http.MethodGet, "/api/v1/export/abc"))
+}
+
+func TestTimeoutRendersGatewayTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(20*time.Millisecond, nil))
+	router.GET("/silent", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/query", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		// What lib/pq returns for a query cancelled by the deadline.
+		response.Error(c, errors.New("pq: canceling statement due to user request"))
+	})
+
+	for _, path := range []string{"/silent", "/query"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code, path)
+		assert.Contains(t, w.Body.String(), `"code":"REQUEST_TIMEOUT"`, path)
+	}
+}
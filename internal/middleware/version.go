@@ -0,0 +1,14 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// VersionHeader carries the serving build on every response so canary traffic can be traced to a build.
+const VersionHeader = "X-App-Version"
+
+// Version stamps responses with the build version.
+func Version(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(VersionHeader, version)
+		c.Next()
+	}
+}
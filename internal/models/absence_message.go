@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// AbsenceMessageStatus tracks an absence message through delivery.
+type AbsenceMessageStatus string
+
+const (
+	AbsenceMessagePending AbsenceMessageStatus = "PENDING"
+	// AbsenceMessageSent means the provider accepted the message; callbacks move it on.
+	AbsenceMessageSent      AbsenceMessageStatus = "SENT"
+	AbsenceMessageDelivered AbsenceMessageStatus = "DELIVERED"
+	AbsenceMessageFailed    AbsenceMessageStatus = "FAILED"
+	// AbsenceMessageSkipped means the absence was corrected before the message went out.
+	AbsenceMessageSkipped AbsenceMessageStatus = "SKIPPED"
+)
+
+// AbsenceMessage tells a guardian their child was marked absent. Rows double as the delivery log.
+type AbsenceMessage struct {
+	ID                string               `db:"id" json:"id"`
+	AttendanceID      string               `db:"attendance_id" json:"attendance_id"`
+	StudentID         string               `db:"student_id" json:"student_id"`
+	StudentName       string               `db:"student_name" json:"student_name"`
+	GuardianID        string               `db:"guardian_id" json:"guardian_id"`
+	Date              time.Time            `db:"date" json:"date"`
+	Channel           MessageChannel       `db:"channel" json:"channel"`
+	Phone             string               `db:"phone" json:"phone"`
+	Body              string               `db:"body" json:"body"`
+	Status            AbsenceMessageStatus `db:"status" json:"status"`
+	ProviderMessageID *string              `db:"provider_message_id" json:"provider_message_id,omitempty"`
+	Attempts          int                  `db:"attempts" json:"attempts"`
+	Error             *string              `db:"error" json:"error,omitempty"`
+	SendAfter         time.Time            `db:"send_after" json:"send_after"`
+	SentAt            *time.Time           `db:"sent_at" json:"sent_at,omitempty"`
+	DeliveredAt       *time.Time           `db:"delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt         time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time            `db:"updated_at" json:"updated_at"`
+	// AttendanceStatus is the current status of the absence, loaded when a message is claimed.
+	AttendanceStatus *AttendanceStatus `db:"attendance_status" json:"-"`
+}
+
+// AbsenceRecipient is a guardian to message about an absent student.
+type AbsenceRecipient struct {
+	EnrollmentID string         `db:"enrollment_id"`
+	StudentID    string         `db:"student_id"`
+	StudentName  string         `db:"student_name"`
+	GuardianID   string         `db:"guardian_id"`
+	Phone        string         `db:"phone"`
+	Channel      MessageChannel `db:"channel"`
+}
+
+// AbsenceMessageFilter narrows the delivery log.
+type AbsenceMessageFilter struct {
+	Date       *time.Time
+	StudentID  string
+	GuardianID string
+	Status     AbsenceMessageStatus
+	Page       int
+	PageSize   int
+}
@@ -0,0 +1,103 @@
+package models
+
+import "time"
+
+// AlertMetric names a per class value alert rules are evaluated against. Both metrics are
+// percentages of the term so far.
+type AlertMetric string
+
+const (
+	AlertMetricClassAttendanceRate AlertMetric = "CLASS_ATTENDANCE_RATE"
+	AlertMetricClassAverageGrade   AlertMetric = "CLASS_AVERAGE_GRADE"
+)
+
+// AlertComparator decides how a metric value is compared with a rule's threshold.
+type AlertComparator string
+
+const (
+	AlertComparatorLT  AlertComparator = "LT"
+	AlertComparatorLTE AlertComparator = "LTE"
+	AlertComparatorGT  AlertComparator = "GT"
+	AlertComparatorGTE AlertComparator = "GTE"
+)
+
+// Breached reports whether value crosses threshold.
+func (c AlertComparator) Breached(value, threshold float64) bool {
+	switch c {
+	case AlertComparatorLT:
+		return value < threshold
+	case AlertComparatorLTE:
+		return value <= threshold
+	case AlertComparatorGT:
+		return value > threshold
+	case AlertComparatorGTE:
+		return value >= threshold
+	}
+	return false
+}
+
+// AlertAudience selects who hears about a breach: the homeroom teacher of the class, every teacher
+// assigned to it, or the admins.
+type AlertAudience string
+
+const (
+	AlertAudienceHomeroom AlertAudience = "HOMEROOM"
+	AlertAudienceTeachers AlertAudience = "TEACHERS"
+	AlertAudienceAdmins   AlertAudience = "ADMINS"
+)
+
+// AlertChannel selects where a breach is surfaced. Every breach shows on dashboards; NOTIFICATION
+// also notifies the audience when the breach is first detected.
+type AlertChannel string
+
+const (
+	AlertChannelDashboard    AlertChannel = "DASHBOARD"
+	AlertChannelNotification AlertChannel = "NOTIFICATION"
+)
+
+// AlertRule raises an alert for every class whose metric crosses the threshold.
+type AlertRule struct {
+	ID         string          `db:"id" json:"id"`
+	Name       string          `db:"name" json:"name"`
+	Metric     AlertMetric     `db:"metric" json:"metric"`
+	Comparator AlertComparator `db:"comparator" json:"comparator"`
+	Threshold  float64         `db:"threshold" json:"threshold"`
+	Audience   AlertAudience   `db:"audience" json:"audience"`
+	Channel    AlertChannel    `db:"channel" json:"channel"`
+	Enabled    bool            `db:"enabled" json:"enabled"`
+	UpdatedBy  *string         `db:"updated_by" json:"updatedBy,omitempty"`
+	CreatedAt  time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+// Breached reports whether value crosses the rule's threshold.
+func (r AlertRule) Breached(value float64) bool {
+	return r.Comparator.Breached(value, r.Threshold)
+}
+
+// AlertRuleBreach is a class whose metric crossed a rule at the last evaluation. DetectedAt survives
+// re-evaluation until the class recovers and the breach is cleared. The rule's fields are joined in
+// on listing.
+type AlertRuleBreach struct {
+	ID         string          `db:"id" json:"id"`
+	RuleID     string          `db:"rule_id" json:"ruleId"`
+	RuleName   string          `db:"rule_name" json:"ruleName"`
+	Metric     AlertMetric     `db:"metric" json:"metric"`
+	Comparator AlertComparator `db:"comparator" json:"comparator"`
+	Audience   AlertAudience   `db:"audience" json:"audience"`
+	TermID     string          `db:"term_id" json:"termId"`
+	ClassID    string          `db:"class_id" json:"classId"`
+	Value      float64         `db:"value" json:"value"`
+	Threshold  float64         `db:"threshold" json:"threshold"`
+	DetectedAt time.Time       `db:"detected_at" json:"detectedAt"`
+	UpdatedAt  time.Time       `db:"updated_at" json:"updatedAt"`
+}
+
+// AlertRuleBreachFilter narrows breach listings. TeacherID limits results to classes the teacher is
+// assigned to; Audiences to rules addressed to one of them.
+type AlertRuleBreachFilter struct {
+	TermID    string
+	ClassIDs  []string
+	TeacherID string
+	Audiences []AlertAudience
+}
@@ -2,10 +2,12 @@ package models
 
 import "time"
 
-// AnalyticsAttendanceFilter scopes attendance analytics queries.
+// AnalyticsAttendanceFilter scopes attendance analytics queries. ClassIDs limits results to any
+// of the listed classes.
 type AnalyticsAttendanceFilter struct {
 	TermID   string
 	ClassID  string
+	ClassIDs []string
 	DateFrom *time.Time
 	DateTo   *time.Time
 }
@@ -15,15 +17,24 @@ type AnalyticsAttendanceSummary struct {
 	TermID       string     `db:"term_id" json:"term_id"`
 	ClassID      string     `db:"class_id" json:"class_id"`
 	PresentCount int        `db:"present_count" json:"present_count"`
+	SickCount    int        `db:"sick_count" json:"sick_count"`
+	ExcusedCount int        `db:"excused_count" json:"excused_count"`
 	AbsentCount  int        `db:"absent_count" json:"absent_count"`
 	Percentage   float64    `db:"percentage" json:"percentage"`
 	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
 }
 
-// AnalyticsGradeFilter scopes grade analytics queries.
+// Counts returns the summary's rows by status.
+func (s AnalyticsAttendanceSummary) Counts() AttendanceStatusCounts {
+	return AttendanceStatusCounts{Present: s.PresentCount, Sick: s.SickCount, Excused: s.ExcusedCount, Absent: s.AbsentCount}
+}
+
+// AnalyticsGradeFilter scopes grade analytics queries. ClassIDs limits results to any of the
+// listed classes.
 type AnalyticsGradeFilter struct {
 	TermID    string
 	ClassID   string
+	ClassIDs  []string
 	SubjectID string
 }
 
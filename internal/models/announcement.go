@@ -30,6 +30,7 @@ type Announcement struct {
 	TargetClassID *string              `db:"target_class_id" json:"target_class_id,omitempty"`
 	Priority      AnnouncementPriority `db:"priority" json:"priority"`
 	IsPinned      bool                 `db:"is_pinned" json:"is_pinned"`
+	RequiresAck   bool                 `db:"requires_ack" json:"requires_ack"`
 	PublishedAt   time.Time            `db:"published_at" json:"published_at"`
 	ExpiresAt     *time.Time           `db:"expires_at" json:"expires_at,omitempty"`
 	CreatedBy     string               `db:"created_by" json:"created_by"`
@@ -37,6 +38,30 @@ type Announcement struct {
 	UpdatedAt     time.Time            `db:"updated_at" json:"updated_at"`
 }
 
+// AnnouncementDedupeKey is the dedupe key of the notifications a broadcast sends, which also makes
+// their holders the announcement's recipients.
+func AnnouncementDedupeKey(announcementID string) string {
+	return "announcement:" + announcementID
+}
+
+// AnnouncementRecipient is a user an announcement was sent to, with their acknowledgement if any.
+type AnnouncementRecipient struct {
+	UserID         string     `db:"user_id" json:"user_id"`
+	FullName       string     `db:"full_name" json:"full_name"`
+	Role           UserRole   `db:"role" json:"role"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at" json:"acknowledged_at,omitempty"`
+}
+
+// AnnouncementAckSummary counts the acknowledgements of an announcement.
+type AnnouncementAckSummary struct {
+	ID           string               `db:"id" json:"id"`
+	Title        string               `db:"title" json:"title"`
+	Priority     AnnouncementPriority `db:"priority" json:"priority"`
+	PublishedAt  time.Time            `db:"published_at" json:"published_at"`
+	Recipients   int                  `db:"recipients" json:"recipients"`
+	Acknowledged int                  `db:"acknowledged" json:"acknowledged"`
+}
+
 // AnnouncementFilter allows listing announcements.
 type AnnouncementFilter struct {
 	AudienceRoles []UserRole
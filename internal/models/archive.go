@@ -6,36 +6,68 @@ import "time"
 type ArchiveScope string
 
 const (
-	ArchiveScopeGlobal  ArchiveScope = "GLOBAL"
-	ArchiveScopeTerm    ArchiveScope = "TERM"
-	ArchiveScopeClass   ArchiveScope = "CLASS"
-	ArchiveScopeStudent ArchiveScope = "STUDENT"
+	ArchiveScopeGlobal   ArchiveScope = "GLOBAL"
+	ArchiveScopeTerm     ArchiveScope = "TERM"
+	ArchiveScopeClass    ArchiveScope = "CLASS"
+	ArchiveScopeStudent  ArchiveScope = "STUDENT"
+	ArchiveScopeMutation ArchiveScope = "MUTATION"
+	ArchiveScopeIntake   ArchiveScope = "INTAKE"
 )
 
 // ArchiveItem represents one archived document metadata row.
 type ArchiveItem struct {
-	ID           string       `db:"id" json:"id"`
-	Title        string       `db:"title" json:"title"`
-	Category     string       `db:"category" json:"category"`
-	Scope        ArchiveScope `db:"scope" json:"scope"`
-	RefTermID    *string      `db:"ref_term_id" json:"refTermId,omitempty"`
-	RefClassID   *string      `db:"ref_class_id" json:"refClassId,omitempty"`
-	RefStudentID *string      `db:"ref_student_id" json:"refStudentId,omitempty"`
-	FilePath     string       `db:"file_path" json:"filePath"`
-	MimeType     string       `db:"mime_type" json:"mimeType"`
-	SizeBytes    int64        `db:"size_bytes" json:"sizeBytes"`
-	UploadedBy   string       `db:"uploaded_by" json:"uploadedBy"`
-	UploadedAt   time.Time    `db:"uploaded_at" json:"uploadedAt"`
-	DeletedAt    *time.Time   `db:"deleted_at" json:"deletedAt,omitempty"`
+	ID                   string       `db:"id" json:"id"`
+	Title                string       `db:"title" json:"title"`
+	Category             string       `db:"category" json:"category"`
+	Scope                ArchiveScope `db:"scope" json:"scope"`
+	RefTermID            *string      `db:"ref_term_id" json:"refTermId,omitempty"`
+	RefClassID           *string      `db:"ref_class_id" json:"refClassId,omitempty"`
+	RefStudentID         *string      `db:"ref_student_id" json:"refStudentId,omitempty"`
+	FilePath             string       `db:"file_path" json:"filePath"`
+	MimeType             string       `db:"mime_type" json:"mimeType"`
+	SizeBytes            int64        `db:"size_bytes" json:"sizeBytes"`
+	UploadedBy           string       `db:"uploaded_by" json:"uploadedBy"`
+	UploadedAt           time.Time    `db:"uploaded_at" json:"uploadedAt"`
+	DeletedAt            *time.Time   `db:"deleted_at" json:"deletedAt,omitempty"`
+	DeletionReason       *string      `db:"deletion_reason" json:"deletionReason,omitempty"`
+	RestoredAt           *time.Time   `db:"restored_at" json:"restoredAt,omitempty"`
+	ThumbnailPath        *string      `db:"thumbnail_path" json:"thumbnailPath,omitempty"`
+	ThumbnailError       *string      `db:"thumbnail_error" json:"-"`
+	ThumbnailGeneratedAt *time.Time   `db:"thumbnail_generated_at" json:"thumbnailGeneratedAt,omitempty"`
 }
 
+// RetainedSince returns when the archive's retention period started: its last restore, if any,
+// otherwise its upload.
+func (a ArchiveItem) RetainedSince() time.Time {
+	if a.RestoredAt != nil {
+		return *a.RestoredAt
+	}
+	return a.UploadedAt
+}
+
+// Reasons an archive was soft-deleted.
+const (
+	ArchiveDeletionManual    = "MANUAL"
+	ArchiveDeletionRetention = "RETENTION"
+)
+
 // ArchiveFilter narrows listing queries by metadata fields.
 type ArchiveFilter struct {
 	Scope          ArchiveScope
 	Category       string
 	TermID         string
 	ClassID        string
+	Search         string
 	IncludeDeleted bool
-	Limit          int
-	Offset         int
+	// OnlyDeleted lists the trash instead, newest deletion first.
+	OnlyDeleted bool
+	Limit       int
+	Offset      int
+}
+
+// ArchiveUsage is the storage taken by live archives, grouped by category or uploader.
+type ArchiveUsage struct {
+	Key   string `db:"key"`
+	Files int    `db:"files"`
+	Bytes int64  `db:"bytes"`
 }
@@ -22,6 +22,46 @@ func (s AttendanceStatus) Valid() bool {
 	}
 }
 
+// AbsenceReason classifies an excused absence in the ministry's reporting categories.
+type AbsenceReason string
+
+const (
+	AbsenceReasonSick       AbsenceReason = "SICK"
+	AbsenceReasonPermission AbsenceReason = "PERMISSION"
+	AbsenceReasonSchoolDuty AbsenceReason = "SCHOOL_DUTY"
+)
+
+// Valid returns true when the reason is a supported value.
+func (r AbsenceReason) Valid() bool {
+	switch r {
+	case AbsenceReasonSick, AbsenceReasonPermission, AbsenceReasonSchoolDuty:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status returns the attendance status the reason refines: S for sickness, I otherwise.
+func (r AbsenceReason) Status() AttendanceStatus {
+	if r == AbsenceReasonSick {
+		return AttendanceStatusSick
+	}
+	return AttendanceStatusExcused
+}
+
+// DefaultAbsenceReason returns the reason a status implies when none was recorded, and "" for
+// present and unexcused days.
+func DefaultAbsenceReason(status AttendanceStatus) AbsenceReason {
+	switch status {
+	case AttendanceStatusSick:
+		return AbsenceReasonSick
+	case AttendanceStatusExcused:
+		return AbsenceReasonPermission
+	default:
+		return ""
+	}
+}
+
 // BulkOperationMode controls how bulk writes behave on errors.
 type BulkOperationMode string
 
@@ -30,15 +70,28 @@ const (
 	BulkModePartialOnError BulkOperationMode = "partialOnError"
 )
 
+// AttendanceSource records how a daily attendance row was captured.
+type AttendanceSource string
+
+const (
+	AttendanceSourceManual AttendanceSource = "MANUAL"
+	AttendanceSourceDevice AttendanceSource = "DEVICE"
+)
+
 // DailyAttendance represents a single daily attendance row.
 type DailyAttendance struct {
-	ID           string           `db:"id" json:"id"`
-	EnrollmentID string           `db:"enrollment_id" json:"enrollment_id"`
-	Date         time.Time        `db:"date" json:"date"`
-	Status       AttendanceStatus `db:"status" json:"status"`
-	Notes        *string          `db:"notes" json:"notes,omitempty"`
-	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time        `db:"updated_at" json:"updated_at"`
+	ID            string               `db:"id" json:"id"`
+	EnrollmentID  string               `db:"enrollment_id" json:"enrollment_id"`
+	Date          time.Time            `db:"date" json:"date"`
+	Status        AttendanceStatus     `db:"status" json:"status"`
+	AbsenceReason *AbsenceReason       `db:"absence_reason" json:"absence_reason,omitempty"`
+	Notes         *string              `db:"notes" json:"notes,omitempty"`
+	Source        AttendanceSource     `db:"source" json:"source,omitempty"`
+	CheckedInAt   *time.Time           `db:"checked_in_at" json:"checked_in_at,omitempty"`
+	CreatedAt     time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time            `db:"updated_at" json:"updated_at"`
+	Evidence      []AttendanceEvidence `db:"-" json:"evidence,omitempty"`
+	Warnings      []string             `db:"-" json:"warnings,omitempty"`
 }
 
 // DailyAttendanceRecord extends the model with student metadata.
@@ -67,10 +120,11 @@ type DailyAttendanceFilter struct {
 
 // DailyAttendanceReportRow captures report rows for a class/date.
 type DailyAttendanceReportRow struct {
-	StudentID   string           `db:"student_id" json:"student_id"`
-	StudentName string           `db:"student_name" json:"student_name"`
-	Status      AttendanceStatus `db:"status" json:"status"`
-	Notes       *string          `db:"notes" json:"notes,omitempty"`
+	StudentID     string           `db:"student_id" json:"student_id"`
+	StudentName   string           `db:"student_name" json:"student_name"`
+	Status        AttendanceStatus `db:"status" json:"status"`
+	AbsenceReason *AbsenceReason   `db:"absence_reason" json:"absence_reason,omitempty"`
+	Notes         *string          `db:"notes" json:"notes,omitempty"`
 }
 
 // DailyAttendanceSummary summarises counts for a student.
@@ -85,9 +139,10 @@ type DailyAttendanceSummary struct {
 
 // DailyAttendanceHistoryRow captures attendance history entries.
 type DailyAttendanceHistoryRow struct {
-	Date   time.Time        `db:"date" json:"date"`
-	Status AttendanceStatus `db:"status" json:"status"`
-	Notes  *string          `db:"notes" json:"notes,omitempty"`
+	Date          time.Time        `db:"date" json:"date"`
+	Status        AttendanceStatus `db:"status" json:"status"`
+	AbsenceReason *AbsenceReason   `db:"absence_reason" json:"absence_reason,omitempty"`
+	Notes         *string          `db:"notes" json:"notes,omitempty"`
 }
 
 // SubjectAttendance represents attendance per subject session.
@@ -100,6 +155,7 @@ type SubjectAttendance struct {
 	Notes        *string          `db:"notes" json:"notes,omitempty"`
 	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time        `db:"updated_at" json:"updated_at"`
+	Warnings     []string         `db:"-" json:"warnings,omitempty"`
 }
 
 // SubjectAttendanceRecord extends the session attendance row with metadata.
@@ -133,6 +189,15 @@ type SubjectAttendanceReportRow struct {
 	Notes        *string          `db:"notes" json:"notes,omitempty"`
 }
 
+// AttendanceEvidence links an archived document to the absence it excuses.
+type AttendanceEvidence struct {
+	EnrollmentID string    `db:"enrollment_id" json:"enrollment_id"`
+	Date         time.Time `db:"date" json:"date"`
+	ArchiveID    string    `db:"archive_id" json:"archive_id"`
+	LinkedBy     string    `db:"linked_by" json:"linked_by"`
+	LinkedAt     time.Time `db:"linked_at" json:"linked_at"`
+}
+
 // AttendanceBulkConflict captures failed bulk operations.
 type AttendanceBulkConflict struct {
 	EnrollmentID string    `json:"enrollment_id"`
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// AttendanceAlertThreshold overrides the minimum attendance percentage for one class in a term.
+// Classes without an override use the configured default.
+type AttendanceAlertThreshold struct {
+	ID            string    `db:"id" json:"id"`
+	ClassID       string    `db:"class_id" json:"class_id"`
+	TermID        string    `db:"term_id" json:"term_id"`
+	MinPercentage float64   `db:"min_percentage" json:"min_percentage"`
+	UpdatedBy     *string   `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// AttendanceAlert records a student whose attendance fell below their class threshold at the
+// last evaluation. DetectedAt is when the breach was first seen; it survives re-evaluation until
+// the student recovers and the alert is cleared.
+type AttendanceAlert struct {
+	ID           string    `db:"id" json:"id"`
+	EnrollmentID string    `db:"enrollment_id" json:"enrollment_id"`
+	StudentID    string    `db:"student_id" json:"student_id"`
+	StudentName  string    `db:"student_name" json:"student_name,omitempty"`
+	ClassID      string    `db:"class_id" json:"class_id"`
+	TermID       string    `db:"term_id" json:"term_id"`
+	Percentage   float64   `db:"percentage" json:"percentage"`
+	Threshold    float64   `db:"threshold" json:"threshold"`
+	PresentDays  int       `db:"present_days" json:"present_days"`
+	TotalDays    int       `db:"total_days" json:"total_days"`
+	DetectedAt   time.Time `db:"detected_at" json:"detected_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// AttendanceAlertFilter narrows stored alert listings. TeacherID limits results to classes the
+// teacher is assigned to.
+type AttendanceAlertFilter struct {
+	TermID    string
+	ClassIDs  []string
+	TeacherID string
+}
+
+// StudentAttendanceRate is one enrollment's attendance over a term.
+type StudentAttendanceRate struct {
+	EnrollmentID string  `db:"enrollment_id"`
+	StudentID    string  `db:"student_id"`
+	ClassID      string  `db:"class_id"`
+	PresentDays  int     `db:"present_days"`
+	TotalDays    int     `db:"total_days"`
+	Percentage   float64 `db:"percentage"`
+}
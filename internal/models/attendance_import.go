@@ -0,0 +1,106 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AttendanceImportJob tracks an asynchronous bulk daily attendance import. It shares the report job
+// lifecycle (QUEUED, PROCESSING, FINISHED, FAILED).
+type AttendanceImportJob struct {
+	ID           string                  `db:"id" json:"id"`
+	Payload      AttendanceImportPayload `db:"payload" json:"payload"`
+	TotalRows    int                     `db:"total_rows" json:"total_rows"`
+	Status       ReportStatus            `db:"status" json:"status"`
+	Progress     int                     `db:"progress" json:"progress"`
+	Result       AttendanceImportResult  `db:"result" json:"result"`
+	CreatedBy    string                  `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time               `db:"created_at" json:"created_at"`
+	FinishedAt   *time.Time              `db:"finished_at" json:"finished_at,omitempty"`
+	ErrorMessage *string                 `db:"error_message" json:"error_message,omitempty"`
+}
+
+// AttendanceImportItem is one row of an import payload.
+type AttendanceImportItem struct {
+	EnrollmentID string  `json:"enrollmentId"`
+	Status       string  `json:"status"`
+	Notes        *string `json:"notes,omitempty"`
+}
+
+// AttendanceImportPayload stores the accepted request as JSONB until a worker picks it up.
+type AttendanceImportPayload struct {
+	Date   string                 `json:"date"`
+	Mode   string                 `json:"mode"`
+	Makeup bool                   `json:"makeup"`
+	Items  []AttendanceImportItem `json:"items"`
+}
+
+// AttendanceImportConflict reports a row that could not be written. Row is the zero-based index in
+// the submitted items.
+type AttendanceImportConflict struct {
+	Row          int    `json:"row"`
+	EnrollmentID string `json:"enrollmentId"`
+	Reason       string `json:"reason"`
+}
+
+// AttendanceImportResult accumulates per-chunk outcomes. Processed doubles as the resume offset when
+// a failed job is retried.
+type AttendanceImportResult struct {
+	Processed int                        `json:"processed"`
+	Success   int                        `json:"success"`
+	Conflicts []AttendanceImportConflict `json:"conflicts,omitempty"`
+	Warnings  []string                   `json:"warnings,omitempty"`
+}
+
+// Value marshals the payload to JSON for persistence.
+func (p AttendanceImportPayload) Value() (driver.Value, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal attendance import payload: %w", err)
+	}
+	return data, nil
+}
+
+// Scan unmarshals the JSONB payload column.
+func (p *AttendanceImportPayload) Scan(value interface{}) error {
+	*p = AttendanceImportPayload{}
+	return scanJSONColumn(value, p, "AttendanceImportPayload")
+}
+
+// Value marshals the result to JSON for persistence.
+func (r AttendanceImportResult) Value() (driver.Value, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal attendance import result: %w", err)
+	}
+	return data, nil
+}
+
+// Scan unmarshals the JSONB result column.
+func (r *AttendanceImportResult) Scan(value interface{}) error {
+	*r = AttendanceImportResult{}
+	return scanJSONColumn(value, r, "AttendanceImportResult")
+}
+
+func scanJSONColumn(value interface{}, dest interface{}, name string) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for %s", value, name)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("unmarshal %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// AttendancePartitionedTables lists the attendance tables partitioned by month on their date.
+var AttendancePartitionedTables = []string{"daily_attendance", "subject_attendance"}
+
+// AttendancePartition is the partition of an attendance table holding one month.
+type AttendancePartition struct {
+	Table string    `json:"table"`
+	Name  string    `json:"name"`
+	Month time.Time `json:"month"`
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AttendanceStatusCounts tallies attendance rows by status.
+type AttendanceStatusCounts struct {
+	Present int
+	Sick    int
+	Excused int
+	Absent  int
+}
+
+// Of returns the number of rows with status.
+func (c AttendanceStatusCounts) Of(status AttendanceStatus) int {
+	switch status {
+	case AttendanceStatusPresent:
+		return c.Present
+	case AttendanceStatusSick:
+		return c.Sick
+	case AttendanceStatusExcused:
+		return c.Excused
+	case AttendanceStatusAbsent:
+		return c.Absent
+	default:
+		return 0
+	}
+}
+
+// Total returns the number of rows counted.
+func (c AttendanceStatusCounts) Total() int {
+	return c.Present + c.Sick + c.Excused + c.Absent
+}
+
+// AttendanceRateFormula decides how an attendance percentage is computed from status counts: rows
+// with a Present status are attended days, and rows with an Excused status that is not also present
+// are left out of the days counted. Late arrivals are stored as H and therefore count as present
+// whenever H does.
+type AttendanceRateFormula struct {
+	Present []AttendanceStatus
+	Excused []AttendanceStatus
+}
+
+// DefaultAttendanceRateFormula counts only H as present and every recorded day towards the total.
+var DefaultAttendanceRateFormula = AttendanceRateFormula{Present: []AttendanceStatus{AttendanceStatusPresent}}
+
+// PresentStatuses returns the statuses counted as attended; an empty formula falls back to
+// DefaultAttendanceRateFormula.
+func (f AttendanceRateFormula) PresentStatuses() []AttendanceStatus {
+	if len(f.Present) == 0 {
+		return DefaultAttendanceRateFormula.Present
+	}
+	return f.Present
+}
+
+// DiscountedStatuses returns the excused statuses that are not counted as present, i.e. the rows
+// removed from the total.
+func (f AttendanceRateFormula) DiscountedStatuses() []AttendanceStatus {
+	present := f.PresentStatuses()
+	discounted := make([]AttendanceStatus, 0, len(f.Excused))
+	for _, status := range f.Excused {
+		if !containsAttendanceStatus(present, status) && !containsAttendanceStatus(discounted, status) {
+			discounted = append(discounted, status)
+		}
+	}
+	return discounted
+}
+
+// Days returns the attended days and the days counted towards the percentage.
+func (f AttendanceRateFormula) Days(counts AttendanceStatusCounts) (present, total int) {
+	for _, status := range uniqueAttendanceStatuses(f.PresentStatuses()) {
+		present += counts.Of(status)
+	}
+	total = counts.Total()
+	for _, status := range f.DiscountedStatuses() {
+		total -= counts.Of(status)
+	}
+	return present, total
+}
+
+// Rate returns the attendance percentage of counts, or 0 when no day counts towards it.
+func (f AttendanceRateFormula) Rate(counts AttendanceStatusCounts) float64 {
+	present, total := f.Days(counts)
+	if total <= 0 {
+		return 0
+	}
+	return float64(present) / float64(total) * 100
+}
+
+// ParseAttendanceStatuses parses a comma separated list of status codes such as "H, S". Codes are
+// case-insensitive and duplicates are dropped; an empty list parses to nil.
+func ParseAttendanceStatuses(raw string) ([]AttendanceStatus, error) {
+	var statuses []AttendanceStatus
+	for _, part := range strings.Split(raw, ",") {
+		code := strings.ToUpper(strings.TrimSpace(part))
+		if code == "" {
+			continue
+		}
+		status := AttendanceStatus(code)
+		if !status.Valid() {
+			return nil, fmt.Errorf("unknown attendance status %q", code)
+		}
+		if !containsAttendanceStatus(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// FormatAttendanceStatuses is the inverse of ParseAttendanceStatuses.
+func FormatAttendanceStatuses(statuses []AttendanceStatus) string {
+	codes := make([]string, len(statuses))
+	for i, status := range statuses {
+		codes[i] = string(status)
+	}
+	return strings.Join(codes, ",")
+}
+
+func uniqueAttendanceStatuses(statuses []AttendanceStatus) []AttendanceStatus {
+	unique := make([]AttendanceStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if !containsAttendanceStatus(unique, status) {
+			unique = append(unique, status)
+		}
+	}
+	return unique
+}
+
+func containsAttendanceStatus(statuses []AttendanceStatus, status AttendanceStatus) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendanceRateFormula(t *testing.T) {
+	counts := AttendanceStatusCounts{Present: 14, Sick: 2, Excused: 2, Absent: 2}
+
+	assert.InDelta(t, 70, DefaultAttendanceRateFormula.Rate(counts), 0.001)
+	assert.InDelta(t, 70, AttendanceRateFormula{}.Rate(counts), 0.001)
+
+	excused := AttendanceRateFormula{
+		Present: []AttendanceStatus{AttendanceStatusPresent},
+		Excused: []AttendanceStatus{AttendanceStatusSick, AttendanceStatusExcused},
+	}
+	assert.InDelta(t, 87.5, excused.Rate(counts), 0.001)
+
+	// A status listed as both present and excused counts as present.
+	lenient := AttendanceRateFormula{
+		Present: []AttendanceStatus{AttendanceStatusPresent, AttendanceStatusExcused},
+		Excused: []AttendanceStatus{AttendanceStatusExcused, AttendanceStatusSick},
+	}
+	present, total := lenient.Days(counts)
+	assert.Equal(t, 16, present)
+	assert.Equal(t, 18, total)
+
+	assert.Zero(t, excused.Rate(AttendanceStatusCounts{Sick: 3}))
+}
+
+func TestParseAttendanceStatuses(t *testing.T) {
+	statuses, err := ParseAttendanceStatuses(" h, I ,h,")
+	require.NoError(t, err)
+	assert.Equal(t, []AttendanceStatus{AttendanceStatusPresent, AttendanceStatusExcused}, statuses)
+	assert.Equal(t, "H,I", FormatAttendanceStatuses(statuses))
+
+	statuses, err = ParseAttendanceStatuses("")
+	require.NoError(t, err)
+	assert.Nil(t, statuses)
+
+	_, err = ParseAttendanceStatuses("H,LATE")
+	assert.EqualError(t, err, `unknown attendance status "LATE"`)
+}
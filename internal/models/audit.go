@@ -4,18 +4,31 @@ import "time"
 
 // AuditAction constants represent actions to be logged.
 const (
-	AuditActionLogin          = "LOGIN"
-	AuditActionLogout         = "LOGOUT"
-	AuditActionUserCreate     = "USER_CREATE"
-	AuditActionUserUpdate     = "USER_UPDATE"
-	AuditActionUserDelete     = "USER_DELETE"
-	AuditActionPasswordChange = "PASSWORD_CHANGE"
-	AuditActionMutationCreate = "MUTATION_REQUEST"
-	AuditActionMutationReview = "MUTATION_REVIEW"
-	AuditActionArchiveUpload  = "ARCHIVE_UPLOAD"
-	AuditActionArchiveDelete  = "ARCHIVE_DELETE"
-	AuditActionHomeroomUpdate = "HOMEROOM_UPDATE"
-	AuditActionConfigUpdate   = "CONFIGURATION_UPDATE"
+	AuditActionLogin            = "LOGIN"
+	AuditActionLogout           = "LOGOUT"
+	AuditActionUserCreate       = "USER_CREATE"
+	AuditActionUserUpdate       = "USER_UPDATE"
+	AuditActionUserDelete       = "USER_DELETE"
+	AuditActionPasswordChange   = "PASSWORD_CHANGE"
+	AuditActionMutationCreate   = "MUTATION_REQUEST"
+	AuditActionMutationReview   = "MUTATION_REVIEW"
+	AuditActionMutationAttach   = "MUTATION_ATTACH"
+	AuditActionMutationExpire   = "MUTATION_EXPIRE"
+	AuditActionMutationEscalate = "MUTATION_ESCALATE"
+	AuditActionArchiveUpload    = "ARCHIVE_UPLOAD"
+	AuditActionArchiveDelete    = "ARCHIVE_DELETE"
+	AuditActionArchiveExpire    = "ARCHIVE_EXPIRE"
+	AuditActionArchivePurge     = "ARCHIVE_PURGE"
+	AuditActionArchiveRestore   = "ARCHIVE_RESTORE"
+	AuditActionHomeroomUpdate   = "HOMEROOM_UPDATE"
+	AuditActionConfigUpdate     = "CONFIGURATION_UPDATE"
+	AuditActionAccessDenied     = "ACCESS_DENIED"
+	AuditActionLessonPlanSubmit = "LESSON_PLAN_SUBMIT"
+	AuditActionLessonPlanReview = "LESSON_PLAN_REVIEW"
+	AuditActionRetentionPurge   = "RETENTION_PURGE"
+	AuditActionLegalHoldPlace   = "LEGAL_HOLD_PLACE"
+	AuditActionLegalHoldRelease = "LEGAL_HOLD_RELEASE"
+	AuditActionIntakeDecision   = "INTAKE_DECISION"
 )
 
 // AuditLog represents an audit trail record.
@@ -69,5 +69,8 @@ type JWTClaims struct {
 	Role     UserRole `json:"role"`
 	Email    string   `json:"email"`
 	FullName string   `json:"full_name"`
+	// SessionID names the refresh token session the access token was issued for. Tokens issued
+	// before sessions were tracked carry none.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Backup is one run of the backup job. Statuses follow the report jobs: QUEUED, PROCESSING, FINISHED
+// and FAILED.
+type Backup struct {
+	ID     string       `db:"id" json:"id"`
+	Status ReportStatus `db:"status" json:"status"`
+	// FilePath is relative to the backup storage directory.
+	FilePath  *string `db:"file_path" json:"filePath,omitempty"`
+	SizeBytes *int64  `db:"size_bytes" json:"sizeBytes,omitempty"`
+	// Checksum is the hex SHA-256 of the encrypted archive.
+	Checksum     *string    `db:"checksum" json:"checksum,omitempty"`
+	StorageFiles int        `db:"storage_files" json:"storageFiles"`
+	ErrorMessage *string    `db:"error_message" json:"error,omitempty"`
+	RequestedBy  string     `db:"requested_by" json:"requestedBy"`
+	CreatedAt    time.Time  `db:"created_at" json:"createdAt"`
+	StartedAt    *time.Time `db:"started_at" json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `db:"finished_at" json:"finishedAt,omitempty"`
+}
+
+// BackupResult describes the archive a finished backup produced.
+type BackupResult struct {
+	FilePath     string
+	SizeBytes    int64
+	Checksum     string
+	StorageFiles int
+}
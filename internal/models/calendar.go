@@ -2,6 +2,9 @@ package models
 
 import "time"
 
+// CalendarEventTypeHoliday marks events during which no lessons take place.
+const CalendarEventTypeHoliday = "HOLIDAY"
+
 // CalendarEvent represents an academic calendar entry.
 type CalendarEvent struct {
 	ID            string               `db:"id" json:"id"`
@@ -15,6 +18,8 @@ type CalendarEvent struct {
 	Audience      AnnouncementAudience `db:"audience" json:"audience"`
 	TargetClassID *string              `db:"target_class_id" json:"target_class_id,omitempty"`
 	Location      *string              `db:"location" json:"location,omitempty"`
+	RSVPEnabled   bool                 `db:"rsvp_enabled" json:"rsvp_enabled"`
+	RSVPCapacity  *int                 `db:"rsvp_capacity" json:"rsvp_capacity,omitempty"`
 	CreatedBy     string               `db:"created_by" json:"created_by"`
 	CreatedAt     time.Time            `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time            `db:"updated_at" json:"updated_at"`
@@ -29,3 +34,26 @@ type CalendarFilter struct {
 	Page      int
 	PageSize  int
 }
+
+// EventRSVPResponse is a user's answer to a calendar event invitation.
+type EventRSVPResponse string
+
+const (
+	EventRSVPGoing    EventRSVPResponse = "GOING"
+	EventRSVPNotGoing EventRSVPResponse = "NOT_GOING"
+	// EventRSVPWalkIn marks attendees checked in at the event without an RSVP.
+	EventRSVPWalkIn EventRSVPResponse = "WALK_IN"
+)
+
+// EventRSVP is a user's RSVP to a calendar event and their check-in on the event day.
+type EventRSVP struct {
+	EventID     string            `db:"event_id" json:"eventId"`
+	UserID      string            `db:"user_id" json:"userId"`
+	FullName    string            `db:"full_name" json:"fullName"`
+	Role        UserRole          `db:"role" json:"role"`
+	Response    EventRSVPResponse `db:"response" json:"response"`
+	Note        *string           `db:"note" json:"note,omitempty"`
+	RespondedAt time.Time         `db:"responded_at" json:"respondedAt"`
+	CheckedInAt *time.Time        `db:"checked_in_at" json:"checkedInAt,omitempty"`
+	CheckedInBy *string           `db:"checked_in_by" json:"checkedInBy,omitempty"`
+}
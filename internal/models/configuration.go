@@ -8,6 +8,7 @@ type ConfigurationType string
 const (
 	ConfigurationTypeString  ConfigurationType = "STRING"
 	ConfigurationTypeBoolean ConfigurationType = "BOOLEAN"
+	ConfigurationTypeInteger ConfigurationType = "INTEGER"
 )
 
 // Configuration represents a persisted configuration entry.
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// CurriculumTopic is one syllabus topic of a subject in a term, planned for a week of the term.
+type CurriculumTopic struct {
+	ID          string    `db:"id" json:"id"`
+	SubjectID   string    `db:"subject_id" json:"subject_id"`
+	TermID      string    `db:"term_id" json:"term_id"`
+	Title       string    `db:"title" json:"title"`
+	Description *string   `db:"description" json:"description,omitempty"`
+	PlannedWeek int       `db:"planned_week" json:"planned_week"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// CurriculumProgress records that a topic has been taught to a class.
+type CurriculumProgress struct {
+	ID        string    `db:"id" json:"id"`
+	TopicID   string    `db:"topic_id" json:"topic_id"`
+	ClassID   string    `db:"class_id" json:"class_id"`
+	TaughtOn  time.Time `db:"taught_on" json:"taught_on"`
+	Notes     *string   `db:"notes" json:"notes,omitempty"`
+	MarkedBy  *string   `db:"marked_by" json:"marked_by,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// CurriculumTopicProgress is a topic together with its progress for one class.
+type CurriculumTopicProgress struct {
+	CurriculumTopic
+	TaughtOn *time.Time `db:"taught_on" json:"taught_on,omitempty"`
+	Notes    *string    `db:"notes" json:"notes,omitempty"`
+	MarkedBy *string    `db:"marked_by" json:"marked_by,omitempty"`
+}
+
+// CurriculumCoverageRow aggregates topic counts for a class/subject teaching assignment.
+type CurriculumCoverageRow struct {
+	ClassID         string `db:"class_id" json:"class_id"`
+	SubjectID       string `db:"subject_id" json:"subject_id"`
+	TeacherID       string `db:"teacher_id" json:"teacher_id"`
+	TotalTopics     int    `db:"total_topics" json:"total_topics"`
+	PlannedToDate   int    `db:"planned_to_date" json:"planned_to_date"`
+	Taught          int    `db:"taught" json:"taught"`
+	TaughtOnPlanned int    `db:"taught_on_planned" json:"taught_on_planned"`
+}
+
+// CurriculumCoverageFilter narrows the coverage report. PlannedWeek is the current week of the term.
+type CurriculumCoverageFilter struct {
+	TermID      string
+	ClassID     string
+	SubjectID   string
+	TeacherID   string
+	PlannedWeek int
+}
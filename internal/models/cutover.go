@@ -22,6 +22,8 @@ type CutoverHeaders struct {
         Stage         CutoverStage `json:"stage"`
         SegmentHeader string       `json:"segment_header"`
         Segment       string       `json:"segment"`
+        // Fallback is set when the Go API serves traffic because the legacy circuit is open.
+        Fallback      bool         `json:"fallback"`
 }
 
 // CutoverPingResult describes the outcome of pinging an upstream (legacy or Go).
@@ -37,4 +39,5 @@ type CutoverPingResult struct {
         Shadow       bool          `json:"shadow"`
         LegacyLocked bool          `json:"legacy_readonly"`
         CanaryPct    int           `json:"canary_percentage"`
+        Circuit      string        `json:"circuit_state,omitempty"`
 }
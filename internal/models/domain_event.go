@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// DomainEventType names a change published to downstream consumers.
+type DomainEventType string
+
+const (
+	DomainEventAttendanceMarked  DomainEventType = "attendance.marked"
+	DomainEventGradeFinalized    DomainEventType = "grade.finalized"
+	DomainEventSchedulePublished DomainEventType = "schedule.published"
+	DomainEventMutationApproved  DomainEventType = "mutation.approved"
+)
+
+// domainEventSchemaVersions holds the payload version currently written for each event type. Bump
+// a version whenever a payload field is renamed, removed or changes meaning; adding a field does
+// not need a new version.
+var domainEventSchemaVersions = map[DomainEventType]int{
+	DomainEventAttendanceMarked:  1,
+	DomainEventGradeFinalized:    1,
+	DomainEventSchedulePublished: 1,
+	DomainEventMutationApproved:  1,
+}
+
+// SchemaVersion returns the payload version written for t, or 0 for unknown types.
+func (t DomainEventType) SchemaVersion() int {
+	return domainEventSchemaVersions[t]
+}
+
+// OutboxEvent is a domain event stored in the same transaction as the change it describes. A relay
+// publishes pending events to the broker and marks them published, so an event is emitted if and
+// only if its change was committed.
+type OutboxEvent struct {
+	ID            string          `db:"id" json:"id"`
+	EventType     DomainEventType `db:"event_type" json:"event_type"`
+	SchemaVersion int             `db:"schema_version" json:"schema_version"`
+	AggregateID   string          `db:"aggregate_id" json:"aggregate_id"`
+	Payload       types.JSONText  `db:"payload" json:"payload"`
+	OccurredAt    time.Time       `db:"occurred_at" json:"occurred_at"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	LastError     *string         `db:"last_error" json:"last_error,omitempty"`
+	ClaimedBy     *string         `db:"claimed_by" json:"claimed_by,omitempty"`
+	ClaimedAt     *time.Time      `db:"claimed_at" json:"claimed_at,omitempty"`
+	PublishedAt   *time.Time      `db:"published_at" json:"published_at,omitempty"`
+}
+
+// DomainEventEnvelope is the message body sent to the broker. Consumers dispatch on Type and
+// SchemaVersion before decoding Data.
+type DomainEventEnvelope struct {
+	ID            string          `json:"id"`
+	Type          DomainEventType `json:"type"`
+	SchemaVersion int             `json:"schemaVersion"`
+	AggregateID   string          `json:"aggregateId"`
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// AttendanceMarkedEvent is the v1 payload of attendance.marked, emitted for every daily attendance
+// row written by a manual mark, a bulk mark or a device check-in.
+type AttendanceMarkedEvent struct {
+	AttendanceID string           `json:"attendanceId"`
+	EnrollmentID string           `json:"enrollmentId"`
+	Date         string           `json:"date"`
+	Status       AttendanceStatus `json:"status"`
+	Source       AttendanceSource `json:"source"`
+}
+
+// GradeFinalizedEvent is the v1 payload of grade.finalized, emitted once per finalized subject.
+type GradeFinalizedEvent struct {
+	ClassID       string   `json:"classId"`
+	SubjectID     string   `json:"subjectId"`
+	TermID        string   `json:"termId"`
+	EnrollmentIDs []string `json:"enrollmentIds"`
+}
+
+// SchedulePublishedEvent is the v1 payload of schedule.published.
+type SchedulePublishedEvent struct {
+	ScheduleID string `json:"scheduleId"`
+	TermID     string `json:"termId"`
+	ClassID    string `json:"classId"`
+	Version    int    `json:"version"`
+	Slots      int    `json:"slots"`
+}
+
+// MutationApprovedEvent is the v1 payload of mutation.approved.
+type MutationApprovedEvent struct {
+	MutationID string         `json:"mutationId"`
+	Entity     string         `json:"entity"`
+	EntityID   string         `json:"entityId"`
+	ReviewedBy string         `json:"reviewedBy"`
+	Changes    types.JSONText `json:"changes"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// ElectiveOffering is an elective subject students of a term can choose. Students rank the offerings
+// of one category, such as RELIGION or LANGUAGE, and are allocated to at most one of them.
+type ElectiveOffering struct {
+	ID          string            `db:"id" json:"id"`
+	TermID      string            `db:"term_id" json:"termId"`
+	SubjectID   string            `db:"subject_id" json:"subjectId"`
+	Category    string            `db:"category" json:"category"`
+	Name        string            `db:"name" json:"name"`
+	Description *string           `db:"description" json:"description,omitempty"`
+	CreatedAt   time.Time         `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time         `db:"updated_at" json:"updatedAt"`
+	Sections    []ElectiveSection `db:"-" json:"sections"`
+}
+
+// Capacity is the number of students the offering's sections take together.
+func (o ElectiveOffering) Capacity() int {
+	total := 0
+	for _, section := range o.Sections {
+		total += section.Capacity
+	}
+	return total
+}
+
+// ElectiveSection is one teaching group of an offering. Allocation places students in the section's
+// lesson group, which is created on the first allocation.
+type ElectiveSection struct {
+	ID            string  `db:"id" json:"id"`
+	OfferingID    string  `db:"offering_id" json:"offeringId"`
+	Name          string  `db:"name" json:"name"`
+	TeacherID     string  `db:"teacher_id" json:"teacherId"`
+	Capacity      int     `db:"capacity" json:"capacity"`
+	Room          *string `db:"room" json:"room,omitempty"`
+	LessonGroupID *string `db:"lesson_group_id" json:"lessonGroupId,omitempty"`
+}
+
+// ElectiveSelection is a student's ranked choice of an offering; rank 1 is the first choice.
+type ElectiveSelection struct {
+	EnrollmentID string    `db:"enrollment_id" json:"enrollmentId"`
+	OfferingID   string    `db:"offering_id" json:"offeringId"`
+	Rank         int       `db:"rank" json:"rank"`
+	SubmittedAt  time.Time `db:"submitted_at" json:"submittedAt"`
+	StudentID    string    `db:"student_id" json:"studentId"`
+	ClassID      string    `db:"class_id" json:"classId"`
+}
+
+// ElectiveSelectionFilter narrows selection listings to a term's category.
+type ElectiveSelectionFilter struct {
+	TermID       string
+	Category     string
+	EnrollmentID string
+}
+
+// ElectiveSectionGroup is the lesson group an allocation stores for a section.
+type ElectiveSectionGroup struct {
+	SectionID string
+	Group     LessonGroup
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// ExamPeriod is an exam week (or weeks) within a term. Exams are only scheduled inside a period.
+type ExamPeriod struct {
+	ID        string    `db:"id" json:"id"`
+	TermID    string    `db:"term_id" json:"term_id"`
+	Name      string    `db:"name" json:"name"`
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ExamSchedule is one sitting of a subject exam for a class, spanning StartSlot..EndSlot of the day.
+type ExamSchedule struct {
+	ID            string    `db:"id" json:"id"`
+	PeriodID      string    `db:"period_id" json:"period_id"`
+	ClassID       string    `db:"class_id" json:"class_id"`
+	SubjectID     string    `db:"subject_id" json:"subject_id"`
+	ExamDate      time.Time `db:"exam_date" json:"exam_date"`
+	StartSlot     int       `db:"start_slot" json:"start_slot"`
+	EndSlot       int       `db:"end_slot" json:"end_slot"`
+	Room          string    `db:"room" json:"room"`
+	InvigilatorID string    `db:"invigilator_id" json:"invigilator_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Overlaps reports whether the sitting shares at least one slot with the given date and slot range.
+func (e ExamSchedule) Overlaps(date time.Time, startSlot, endSlot int) bool {
+	return e.ExamDate.Format("2006-01-02") == date.Format("2006-01-02") && e.StartSlot <= endSlot && startSlot <= e.EndSlot
+}
+
+// ExamScheduleFilter narrows exam sittings.
+type ExamScheduleFilter struct {
+	PeriodID      string
+	ClassID       string
+	Room          string
+	InvigilatorID string
+	Date          *time.Time
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// ExportTemplateColumn selects one dataset column for an export template. Label replaces the
+// column's default header when set.
+type ExportTemplateColumn struct {
+	Key   string `json:"key"`
+	Label string `json:"label,omitempty"`
+}
+
+// ExportTemplate customises the layout of one report type's exports: which columns appear, in what
+// order, under which headers and which column rows are grouped by. Columns holds a JSON encoded
+// []ExportTemplateColumn.
+type ExportTemplate struct {
+	ID         string         `db:"id" json:"id"`
+	ReportType ReportType     `db:"report_type" json:"report_type"`
+	Name       string         `db:"name" json:"name"`
+	Columns    types.JSONText `db:"columns" json:"columns"`
+	GroupBy    *string        `db:"group_by" json:"group_by,omitempty"`
+	UpdatedBy  *string        `db:"updated_by" json:"updated_by,omitempty"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
@@ -8,8 +8,22 @@ type GradeCalculationScheme string
 const (
 	// GradeSchemeWeighted applies component weights to grade values.
 	GradeSchemeWeighted GradeCalculationScheme = "WEIGHTED"
-	// GradeSchemeAverage computes the average of all grade values.
+	// GradeSchemeAverage averages the configured components equally, each by the mean of its entries.
 	GradeSchemeAverage GradeCalculationScheme = "AVERAGE"
+	// GradeSchemeDropLowest averages grade values after discarding the DropLowest lowest ones.
+	GradeSchemeDropLowest GradeCalculationScheme = "DROP_LOWEST"
+	// GradeSchemeBestN averages the BestN highest grade values, e.g. the best 3 of 5 quizzes.
+	GradeSchemeBestN GradeCalculationScheme = "BEST_N"
+)
+
+// GradeRoundingMode selects how ties are rounded when a final grade is stored.
+type GradeRoundingMode string
+
+const (
+	// GradeRoundingHalfEven rounds ties to the nearest even digit (banker's rounding).
+	GradeRoundingHalfEven GradeRoundingMode = "HALF_EVEN"
+	// GradeRoundingHalfUp rounds ties up, as report card regulations usually require.
+	GradeRoundingHalfUp GradeRoundingMode = "HALF_UP"
 )
 
 // GradeComponent describes a reusable grading component.
@@ -22,20 +36,32 @@ type GradeComponent struct {
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-// GradeConfig defines calculation configuration for a class+subject+term.
+// GradeConfig defines calculation configuration for a class+subject+term. DropLowest and BestN
+// parameterise the DROP_LOWEST and BEST_N schemes, which apply to the entries of each component.
+// Final grades are rounded with RoundingMode to RoundingPlaces decimals or, when RoundToInteger is
+// set, straight to a whole number. KKM is the minimum mastery score (Kriteria Ketuntasan
+// Minimal); finals below it are flagged for remedial work.
 type GradeConfig struct {
 	ID                string                 `db:"id" json:"id"`
-	ClassID           string                 `db:"class_id" json:"class_id"`
-	SubjectID         string                 `db:"subject_id" json:"subject_id"`
-	TermID            string                 `db:"term_id" json:"term_id"`
+	ClassID           ClassID                `db:"class_id" json:"class_id"`
+	SubjectID         SubjectID              `db:"subject_id" json:"subject_id"`
+	TermID            TermID                 `db:"term_id" json:"term_id"`
 	CalculationScheme GradeCalculationScheme `db:"calculation_scheme" json:"calculation_scheme"`
+	DropLowest        int                    `db:"drop_lowest" json:"drop_lowest"`
+	BestN             int                    `db:"best_n" json:"best_n"`
+	RoundingMode      GradeRoundingMode      `db:"rounding_mode" json:"rounding_mode"`
+	RoundingPlaces    int                    `db:"rounding_places" json:"rounding_places"`
+	RoundToInteger    bool                   `db:"round_to_integer" json:"round_to_integer"`
+	KKM               *float64               `db:"kkm" json:"kkm,omitempty"`
 	Finalized         bool                   `db:"finalized" json:"finalized"`
 	CreatedAt         time.Time              `db:"created_at" json:"created_at"`
 	UpdatedAt         time.Time              `db:"updated_at" json:"updated_at"`
 	Components        []GradeConfigComponent `json:"components,omitempty"`
 }
 
-// GradeConfigComponent maps grade components to configurations.
+// GradeConfigComponent maps grade components to configurations. MinGrades is the number of entries a
+// student needs in the component before the config calculates a final grade; zero disables the
+// requirement.
 type GradeConfigComponent struct {
 	ID            string    `db:"id" json:"id"`
 	GradeConfigID string    `db:"grade_config_id" json:"grade_config_id"`
@@ -43,32 +69,51 @@ type GradeConfigComponent struct {
 	Weight        float64   `db:"weight" json:"weight"`
 	ComponentCode string    `db:"component_code" json:"component_code"`
 	ComponentName string    `db:"component_name" json:"component_name"`
+	MinGrades     int       `db:"min_grades" json:"min_grades"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 }
 
-// Grade represents a single grade entry for a component.
+// Grade represents a single grade entry for a component. Entry numbers the grades a student has in
+// one component, starting at 1.
 type Grade struct {
 	ID            string    `db:"id" json:"id"`
 	EnrollmentID  string    `db:"enrollment_id" json:"enrollment_id"`
 	SubjectID     string    `db:"subject_id" json:"subject_id"`
 	ComponentID   string    `db:"component_id" json:"component_id"`
+	Entry         int       `db:"entry" json:"entry"`
 	GradeValue    float64   `db:"grade_value" json:"grade_value"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
 	ComponentCode string    `db:"component_code" json:"component_code"`
 }
 
-// GradeFinal stores the computed final grade for an enrollment + subject.
+// GradeFinal stores the computed final grade for an enrollment + subject. BelowKKM flags finals under
+// the config's KKM; a remedial score is recorded separately and never replaces FinalGrade.
 type GradeFinal struct {
-	ID              string    `db:"id" json:"id"`
-	EnrollmentID    string    `db:"enrollment_id" json:"enrollment_id"`
-	SubjectID       string    `db:"subject_id" json:"subject_id"`
-	FinalGrade      float64   `db:"final_grade" json:"final_grade"`
-	Finalized       bool      `db:"finalized" json:"finalized"`
-	CalculatedAt    time.Time `db:"calculated_at" json:"calculated_at"`
-	CalculationNote string    `db:"calculation_note" json:"calculation_note"`
+	ID              string     `db:"id" json:"id"`
+	EnrollmentID    string     `db:"enrollment_id" json:"enrollment_id"`
+	SubjectID       string     `db:"subject_id" json:"subject_id"`
+	FinalGrade      float64    `db:"final_grade" json:"final_grade"`
+	Finalized       bool       `db:"finalized" json:"finalized"`
+	CalculatedAt    time.Time  `db:"calculated_at" json:"calculated_at"`
+	CalculationNote string     `db:"calculation_note" json:"calculation_note"`
+	BelowKKM        bool       `db:"below_kkm" json:"below_kkm"`
+	RemedialGrade   *float64   `db:"remedial_grade" json:"remedial_grade,omitempty"`
+	RemedialAt      *time.Time `db:"remedial_at" json:"remedial_at,omitempty"`
 }
 
+// RemedialStatus annotates report card subjects whose final grade fell below KKM.
+type RemedialStatus string
+
+const (
+	// RemedialPending means no remedial score has been recorded yet.
+	RemedialPending RemedialStatus = "PENDING"
+	// RemedialPassed means the remedial score reached KKM.
+	RemedialPassed RemedialStatus = "PASSED"
+	// RemedialFailed means the remedial score is still below KKM.
+	RemedialFailed RemedialStatus = "FAILED"
+)
+
 // GradeFilter allows querying of grade entries.
 type GradeFilter struct {
 	EnrollmentID string
@@ -85,9 +130,13 @@ type FinalGradeFilter struct {
 
 // GradeReportSubject summarises student performance per subject.
 type GradeReportSubject struct {
-	SubjectID   string   `db:"subject_id" json:"subject_id"`
-	SubjectName string   `db:"subject_name" json:"subject_name"`
-	FinalGrade  *float64 `db:"final_grade" json:"final_grade,omitempty"`
+	SubjectID     string         `db:"subject_id" json:"subject_id"`
+	SubjectName   string         `db:"subject_name" json:"subject_name"`
+	FinalGrade    *float64       `db:"final_grade" json:"final_grade,omitempty"`
+	KKM           *float64       `db:"kkm" json:"kkm,omitempty"`
+	BelowKKM      bool           `db:"below_kkm" json:"below_kkm"`
+	RemedialGrade *float64       `db:"remedial_grade" json:"remedial_grade,omitempty"`
+	Remedial      RemedialStatus `db:"-" json:"remedial,omitempty"`
 }
 
 // StudentReportCard contains per-subject grades for a student.
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// GradeAppealStatus tracks an appeal from the subject teacher to the super admins' decision.
+type GradeAppealStatus string
+
+const (
+	// GradeAppealTeacherReview waits for the subject teacher's recommendation.
+	GradeAppealTeacherReview GradeAppealStatus = "TEACHER_REVIEW"
+	// GradeAppealAdminReview waits for a super admin to review the appeal's mutation.
+	GradeAppealAdminReview GradeAppealStatus = "ADMIN_REVIEW"
+	GradeAppealApproved    GradeAppealStatus = "APPROVED"
+	GradeAppealRejected    GradeAppealStatus = "REJECTED"
+)
+
+// GradeAppealRecommendation is the subject teacher's advice to the super admins. NONE marks appeals
+// forwarded because the teacher did not answer in time.
+type GradeAppealRecommendation string
+
+const (
+	GradeAppealSupport GradeAppealRecommendation = "SUPPORT"
+	GradeAppealOppose  GradeAppealRecommendation = "OPPOSE"
+	GradeAppealNone    GradeAppealRecommendation = "NONE"
+)
+
+// GradeAppeal disputes one finalized final grade. CurrentGrade is the grade as it is now, which
+// differs from OriginalGrade once an appeal on it was approved. DecidedAt and DecisionNote come from
+// the super admin's review of the appeal's mutation.
+type GradeAppeal struct {
+	ID             string                     `db:"id" json:"id"`
+	GradeFinalID   string                     `db:"grade_final_id" json:"gradeFinalId"`
+	EnrollmentID   string                     `db:"enrollment_id" json:"enrollmentId"`
+	StudentID      string                     `db:"student_id" json:"studentId"`
+	StudentName    string                     `db:"student_name" json:"studentName"`
+	ClassID        string                     `db:"class_id" json:"classId"`
+	SubjectID      string                     `db:"subject_id" json:"subjectId"`
+	SubjectName    string                     `db:"subject_name" json:"subjectName"`
+	TermID         string                     `db:"term_id" json:"termId"`
+	TeacherID      *string                    `db:"teacher_id" json:"teacherId,omitempty"`
+	TeacherName    *string                    `db:"teacher_name" json:"teacherName,omitempty"`
+	SubmittedBy    string                     `db:"submitted_by" json:"submittedBy"`
+	Reason         string                     `db:"reason" json:"reason"`
+	OriginalGrade  float64                    `db:"original_grade" json:"originalGrade"`
+	RequestedGrade float64                    `db:"requested_grade" json:"requestedGrade"`
+	CurrentGrade   float64                    `db:"current_grade" json:"currentGrade"`
+	Status         GradeAppealStatus          `db:"status" json:"status"`
+	TeacherDueAt   time.Time                  `db:"teacher_due_at" json:"teacherDueAt"`
+	Recommendation *GradeAppealRecommendation `db:"recommendation" json:"recommendation,omitempty"`
+	ProposedGrade  *float64                   `db:"proposed_grade" json:"proposedGrade,omitempty"`
+	TeacherNote    *string                    `db:"teacher_note" json:"teacherNote,omitempty"`
+	ForwardedAt    *time.Time                 `db:"forwarded_at" json:"forwardedAt,omitempty"`
+	AdminDueAt     *time.Time                 `db:"admin_due_at" json:"adminDueAt,omitempty"`
+	MutationID     *string                    `db:"mutation_id" json:"mutationId,omitempty"`
+	DecidedAt      *time.Time                 `db:"decided_at" json:"decidedAt,omitempty"`
+	DecisionNote   *string                    `db:"decision_note" json:"decisionNote,omitempty"`
+	CreatedAt      time.Time                  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time                  `db:"updated_at" json:"updatedAt"`
+	Overdue        bool                       `db:"-" json:"overdue"`
+}
+
+// Open reports whether the appeal still awaits a decision.
+func (a GradeAppeal) Open() bool {
+	return a.Status == GradeAppealTeacherReview || a.Status == GradeAppealAdminReview
+}
+
+// IsOverdue reports whether the stage the appeal is in has run past its deadline at now.
+func (a GradeAppeal) IsOverdue(now time.Time) bool {
+	switch a.Status {
+	case GradeAppealTeacherReview:
+		return now.After(a.TeacherDueAt)
+	case GradeAppealAdminReview:
+		return a.AdminDueAt != nil && now.After(*a.AdminDueAt)
+	default:
+		return false
+	}
+}
+
+// GradeAppealFilter narrows appeal listings.
+type GradeAppealFilter struct {
+	StudentID string
+	TeacherID string
+	Status    GradeAppealStatus
+	Limit     int
+}
+
+// GradeAppealTarget is a student's final grade in one subject and term, with the subject teacher an
+// appeal against it is routed to.
+type GradeAppealTarget struct {
+	GradeFinalID string  `db:"grade_final_id"`
+	EnrollmentID string  `db:"enrollment_id"`
+	ClassID      string  `db:"class_id"`
+	FinalGrade   float64 `db:"final_grade"`
+	Finalized    bool    `db:"finalized"`
+	TeacherID    *string `db:"teacher_id"`
+}
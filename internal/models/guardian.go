@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// GuardianStudent links a GUARDIAN user to a student they may view.
+type GuardianStudent struct {
+	GuardianID   string    `db:"guardian_id" json:"guardian_id"`
+	StudentID    string    `db:"student_id" json:"student_id"`
+	Relationship *string   `db:"relationship" json:"relationship,omitempty"`
+	StudentName  string    `db:"student_name" json:"student_name"`
+	NIS          string    `db:"nis" json:"nis"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// MessageChannel is how text messages reach a guardian's phone.
+type MessageChannel string
+
+const (
+	MessageChannelSMS      MessageChannel = "SMS"
+	MessageChannelWhatsApp MessageChannel = "WHATSAPP"
+)
+
+// GuardianContact is the phone a guardian is messaged on.
+type GuardianContact struct {
+	GuardianID string         `db:"guardian_id" json:"guardian_id"`
+	Phone      string         `db:"phone" json:"phone"`
+	Channel    MessageChannel `db:"channel" json:"channel"`
+	// AbsenceAlerts is false when the guardian opted out of absence messages.
+	AbsenceAlerts bool      `db:"absence_alerts" json:"absence_alerts"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
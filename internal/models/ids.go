@@ -0,0 +1,104 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// maxIDLength bounds identifiers accepted from callers; stored IDs are UUIDs or short legacy codes.
+const maxIDLength = 64
+
+// TermID identifies a term. Repository methods take the typed IDs below instead of bare strings so
+// passing a class ID where a term ID belongs no longer compiles.
+type TermID string
+
+// ClassID identifies a class.
+type ClassID string
+
+// SubjectID identifies a subject.
+type SubjectID string
+
+// TeacherID identifies a teacher.
+type TeacherID string
+
+// StudentID identifies a student.
+type StudentID string
+
+func (id TermID) String() string    { return string(id) }
+func (id ClassID) String() string   { return string(id) }
+func (id SubjectID) String() string { return string(id) }
+func (id TeacherID) String() string { return string(id) }
+func (id StudentID) String() string { return string(id) }
+
+// Validate reports whether the ID is usable as a term reference.
+func (id TermID) Validate() error { return validateID("term", string(id)) }
+
+// Validate reports whether the ID is usable as a class reference.
+func (id ClassID) Validate() error { return validateID("class", string(id)) }
+
+// Validate reports whether the ID is usable as a subject reference.
+func (id SubjectID) Validate() error { return validateID("subject", string(id)) }
+
+// Validate reports whether the ID is usable as a teacher reference.
+func (id TeacherID) Validate() error { return validateID("teacher", string(id)) }
+
+// Validate reports whether the ID is usable as a student reference.
+func (id StudentID) Validate() error { return validateID("student", string(id)) }
+
+// Value implements driver.Valuer.
+func (id TermID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer.
+func (id ClassID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer.
+func (id SubjectID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer.
+func (id TeacherID) Value() (driver.Value, error) { return string(id), nil }
+
+// Value implements driver.Valuer.
+func (id StudentID) Value() (driver.Value, error) { return string(id), nil }
+
+// Scan implements sql.Scanner.
+func (id *TermID) Scan(value interface{}) error { return scanID((*string)(id), "TermID", value) }
+
+// Scan implements sql.Scanner.
+func (id *ClassID) Scan(value interface{}) error { return scanID((*string)(id), "ClassID", value) }
+
+// Scan implements sql.Scanner.
+func (id *SubjectID) Scan(value interface{}) error { return scanID((*string)(id), "SubjectID", value) }
+
+// Scan implements sql.Scanner.
+func (id *TeacherID) Scan(value interface{}) error { return scanID((*string)(id), "TeacherID", value) }
+
+// Scan implements sql.Scanner.
+func (id *StudentID) Scan(value interface{}) error { return scanID((*string)(id), "StudentID", value) }
+
+func validateID(kind, id string) error {
+	switch {
+	case id == "":
+		return fmt.Errorf("%s id is required", kind)
+	case strings.TrimSpace(id) != id:
+		return fmt.Errorf("%s id must not have surrounding whitespace", kind)
+	case len(id) > maxIDLength:
+		return fmt.Errorf("%s id must be at most %d characters", kind, maxIDLength)
+	}
+	return nil
+}
+
+// scanID copies a text column into dst; NULL scans as the empty ID.
+func scanID(dst *string, name string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*dst = ""
+	case string:
+		*dst = v
+	case []byte:
+		*dst = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for %s", value, name)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDValidate(t *testing.T) {
+	assert.NoError(t, TermID("term-1").Validate())
+	assert.EqualError(t, ClassID("").Validate(), "class id is required")
+	assert.EqualError(t, TeacherID(" teacher-1").Validate(), "teacher id must not have surrounding whitespace")
+	assert.EqualError(t, SubjectID(strings.Repeat("x", maxIDLength+1)).Validate(), "subject id must be at most 64 characters")
+}
+
+func TestIDValueAndScan(t *testing.T) {
+	value, err := StudentID("student-1").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "student-1", value)
+
+	var term TermID
+	require.NoError(t, term.Scan([]byte("term-1")))
+	assert.Equal(t, TermID("term-1"), term)
+	require.NoError(t, term.Scan(nil))
+	assert.Empty(t, term)
+	assert.EqualError(t, term.Scan(int64(1)), "unsupported type int64 for TermID")
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// IntakeStatus tracks a transfer-in application through placement.
+type IntakeStatus string
+
+// Intake statuses.
+const (
+	IntakeStatusPending    IntakeStatus = "PENDING"
+	IntakeStatusWaitlisted IntakeStatus = "WAITLISTED"
+	IntakeStatusAccepted   IntakeStatus = "ACCEPTED"
+	IntakeStatusRejected   IntakeStatus = "REJECTED"
+)
+
+// Open reports whether the application still awaits a placement decision.
+func (s IntakeStatus) Open() bool {
+	return s == IntakeStatusPending || s == IntakeStatusWaitlisted
+}
+
+// IntakeDocumentType names a document of the intake checklist.
+type IntakeDocumentType string
+
+// Intake checklist documents.
+const (
+	IntakeDocumentBirthCertificate IntakeDocumentType = "BIRTH_CERTIFICATE"
+	IntakeDocumentFamilyCard       IntakeDocumentType = "FAMILY_CARD"
+	IntakeDocumentReportCard       IntakeDocumentType = "REPORT_CARD"
+	IntakeDocumentTransferLetter   IntakeDocumentType = "TRANSFER_LETTER"
+)
+
+// IntakeChecklist lists the documents an application needs before it can be accepted.
+var IntakeChecklist = []IntakeDocumentType{
+	IntakeDocumentBirthCertificate,
+	IntakeDocumentFamilyCard,
+	IntakeDocumentReportCard,
+	IntakeDocumentTransferLetter,
+}
+
+// IntakeApplication is a transfer student's request to join the school mid-year. Accepting it
+// creates the student and enrolls them in the placed class.
+type IntakeApplication struct {
+	ID               string               `db:"id" json:"id"`
+	TermID           string               `db:"term_id" json:"termId"`
+	FullName         string               `db:"full_name" json:"fullName"`
+	NISN             *string              `db:"nisn" json:"nisn,omitempty"`
+	Gender           string               `db:"gender" json:"gender"`
+	BirthDate        time.Time            `db:"birth_date" json:"birthDate"`
+	Address          string               `db:"address" json:"address"`
+	Phone            string               `db:"phone" json:"phone"`
+	PreviousSchool   string               `db:"previous_school" json:"previousSchool"`
+	RequestedClassID *string              `db:"requested_class_id" json:"requestedClassId,omitempty"`
+	Status           IntakeStatus         `db:"status" json:"status"`
+	WaitlistedAt     *time.Time           `db:"waitlisted_at" json:"waitlistedAt,omitempty"`
+	ClassID          *string              `db:"class_id" json:"classId,omitempty"`
+	StudentID        *string              `db:"student_id" json:"studentId,omitempty"`
+	EnrollmentID     *string              `db:"enrollment_id" json:"enrollmentId,omitempty"`
+	DecisionNote     *string              `db:"decision_note" json:"decisionNote,omitempty"`
+	DecidedBy        *string              `db:"decided_by" json:"decidedBy,omitempty"`
+	DecidedAt        *time.Time           `db:"decided_at" json:"decidedAt,omitempty"`
+	CreatedBy        string               `db:"created_by" json:"createdBy"`
+	CreatedAt        time.Time            `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time            `db:"updated_at" json:"updatedAt"`
+	Documents        []IntakeDocument     `db:"-" json:"documents"`
+	MissingDocuments []IntakeDocumentType `db:"-" json:"missingDocuments"`
+	// WaitlistPosition is the 1-based place among the term's waitlisted applications.
+	WaitlistPosition int `db:"-" json:"waitlistPosition,omitempty"`
+}
+
+// IntakeDocument links an application to a checklist document stored in the archive.
+type IntakeDocument struct {
+	ApplicationID string             `db:"application_id" json:"applicationId"`
+	DocumentType  IntakeDocumentType `db:"document_type" json:"documentType"`
+	ArchiveID     string             `db:"archive_id" json:"archiveId"`
+	Title         string             `db:"title" json:"title"`
+	MimeType      string             `db:"mime_type" json:"mimeType"`
+	SizeBytes     int64              `db:"size_bytes" json:"sizeBytes"`
+	UploadedBy    string             `db:"uploaded_by" json:"uploadedBy"`
+	UploadedAt    time.Time          `db:"uploaded_at" json:"uploadedAt"`
+}
+
+// IntakeFilter constrains application listings.
+type IntakeFilter struct {
+	TermID string
+	Status []IntakeStatus
+}
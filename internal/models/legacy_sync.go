@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// LegacySyncKind identifies which data a legacy sync batch carries.
+type LegacySyncKind string
+
+const (
+	LegacySyncAttendance LegacySyncKind = "attendance"
+	LegacySyncGrades     LegacySyncKind = "grades"
+)
+
+// LegacySyncBatch records one payload pushed by the legacy app while it still writes during the
+// migration window. Batches are unique per kind and idempotency key so a retried delivery is
+// answered from the stored outcome instead of being applied again. Failures holds a JSON encoded
+// []LegacySyncFailure.
+type LegacySyncBatch struct {
+	ID             string         `db:"id" json:"id"`
+	Kind           LegacySyncKind `db:"kind" json:"kind"`
+	IdempotencyKey string         `db:"idempotency_key" json:"idempotency_key"`
+	Source         string         `db:"source" json:"source"`
+	PayloadHash    string         `db:"payload_hash" json:"-"`
+	Received       int            `db:"received" json:"received"`
+	Applied        int            `db:"applied" json:"applied"`
+	Failed         int            `db:"failed" json:"failed"`
+	Failures       types.JSONText `db:"failures" json:"failures"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+}
+
+// LegacySyncFailure describes one record of a batch that was rejected. Index is the record's
+// position in the submitted payload.
+type LegacySyncFailure struct {
+	Index     int    `json:"index"`
+	StudentID string `json:"student_id"`
+	Reason    string `json:"reason"`
+}
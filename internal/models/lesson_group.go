@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// LessonGroup merges classes, or subsets of their students, into one lesson taught by one teacher,
+// e.g. a religion or language elective. Each session of the group is stored as one schedule per
+// member class carrying the group's ID, so class timetables and conflict checks keep working.
+type LessonGroup struct {
+	ID        string              `db:"id" json:"id"`
+	TermID    string              `db:"term_id" json:"termId"`
+	SubjectID string              `db:"subject_id" json:"subjectId"`
+	TeacherID string              `db:"teacher_id" json:"teacherId"`
+	Name      string              `db:"name" json:"name"`
+	Room      *string             `db:"room" json:"room,omitempty"`
+	CreatedAt time.Time           `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time           `db:"updated_at" json:"updatedAt"`
+	Members   []LessonGroupMember `db:"-" json:"members"`
+}
+
+// LessonGroupMember is a class taking part in a lesson group. Without EnrollmentIDs the whole class
+// takes part; otherwise only the listed enrollments do.
+type LessonGroupMember struct {
+	ClassID       string   `db:"class_id" json:"classId"`
+	EnrollmentIDs []string `db:"-" json:"enrollmentIds,omitempty"`
+}
+
+// WholeClass reports whether every student of the class takes part.
+func (m LessonGroupMember) WholeClass() bool {
+	return len(m.EnrollmentIDs) == 0
+}
+
+// Member returns the group's member entry for classID.
+func (g LessonGroup) Member(classID string) (LessonGroupMember, bool) {
+	for _, member := range g.Members {
+		if member.ClassID == classID {
+			return member, true
+		}
+	}
+	return LessonGroupMember{}, false
+}
+
+// LessonGroupStudent is a selected enrollment of a member class as stored.
+type LessonGroupStudent struct {
+	GroupID      string `db:"group_id"`
+	ClassID      string `db:"class_id"`
+	EnrollmentID string `db:"enrollment_id"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// LessonPlanStatus captures the review workflow state of a lesson plan.
+type LessonPlanStatus string
+
+const (
+	LessonPlanStatusDraft     LessonPlanStatus = "DRAFT"
+	LessonPlanStatusSubmitted LessonPlanStatus = "SUBMITTED"
+	LessonPlanStatusApproved  LessonPlanStatus = "APPROVED"
+	LessonPlanStatusRejected  LessonPlanStatus = "REJECTED"
+)
+
+// LessonPlan is a teacher's weekly plan for one class and subject. WeekStart is always a Monday.
+type LessonPlan struct {
+	ID           string           `db:"id" json:"id"`
+	TeacherID    string           `db:"teacher_id" json:"teacher_id"`
+	ClassID      string           `db:"class_id" json:"class_id"`
+	SubjectID    string           `db:"subject_id" json:"subject_id"`
+	TermID       string           `db:"term_id" json:"term_id"`
+	WeekStart    time.Time        `db:"week_start" json:"week_start"`
+	Title        string           `db:"title" json:"title"`
+	Objectives   *string          `db:"objectives" json:"objectives,omitempty"`
+	Activities   *string          `db:"activities" json:"activities,omitempty"`
+	Assessment   *string          `db:"assessment" json:"assessment,omitempty"`
+	AttachmentID *string          `db:"attachment_id" json:"attachment_id,omitempty"`
+	Status       LessonPlanStatus `db:"status" json:"status"`
+	SubmittedAt  *time.Time       `db:"submitted_at" json:"submitted_at,omitempty"`
+	ReviewedBy   *string          `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time       `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	ReviewNote   *string          `db:"review_note" json:"review_note,omitempty"`
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// Editable reports whether the teacher may still change the plan.
+func (p *LessonPlan) Editable() bool {
+	return p.Status == LessonPlanStatusDraft || p.Status == LessonPlanStatusRejected
+}
+
+// LessonPlanFilter narrows lesson plan listings.
+type LessonPlanFilter struct {
+	TeacherID string
+	ClassID   string
+	SubjectID string
+	TermID    string
+	Status    []LessonPlanStatus
+	WeekStart *time.Time
+}
+
+// LessonPlanGap is a subject teaching assignment without a submitted plan for a week.
+type LessonPlanGap struct {
+	TeacherID string `db:"teacher_id"`
+	ClassID   string `db:"class_id"`
+	SubjectID string `db:"subject_id"`
+}
@@ -0,0 +1,32 @@
+package models
+
+// MinistryAttendanceRow is one student's attendance recap for a term in a ministry export. NISN and
+// the homeroom teacher's NIP are nil when they were never recorded.
+type MinistryAttendanceRow struct {
+	StudentID    string  `db:"student_id"`
+	NISN         *string `db:"nisn"`
+	NIS          string  `db:"nis"`
+	StudentName  string  `db:"student_name"`
+	Gender       string  `db:"gender"`
+	ClassName    string  `db:"class_name"`
+	HomeroomNIP  *string `db:"homeroom_nip"`
+	HomeroomName *string `db:"homeroom_name"`
+	Sick         int     `db:"sick"`
+	Excused      int     `db:"excused"`
+	Absent       int     `db:"absent"`
+}
+
+// MinistryGradeRow is one finalized subject grade of a student in a ministry export, with the
+// subject teacher who taught the class.
+type MinistryGradeRow struct {
+	StudentID   string  `db:"student_id"`
+	NISN        *string `db:"nisn"`
+	NIS         string  `db:"nis"`
+	StudentName string  `db:"student_name"`
+	ClassName   string  `db:"class_name"`
+	SubjectCode string  `db:"subject_code"`
+	SubjectName string  `db:"subject_name"`
+	TeacherNIP  *string `db:"teacher_nip"`
+	TeacherName *string `db:"teacher_name"`
+	FinalGrade  float64 `db:"final_grade"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // MutationType enumerates supported mutation categories.
 type MutationType string
@@ -24,19 +27,43 @@ const (
 
 // Mutation stores structured data change requests awaiting review.
 type Mutation struct {
-	ID               string         `db:"id" json:"id"`
-	Type             MutationType   `db:"type" json:"type"`
-	Entity           string         `db:"entity" json:"entity"`
-	EntityID         string         `db:"entity_id" json:"entityId"`
-	CurrentSnapshot  []byte         `db:"current_snapshot" json:"currentSnapshot"`
-	RequestedChanges []byte         `db:"requested_changes" json:"requestedChanges"`
-	Status           MutationStatus `db:"status" json:"status"`
-	Reason           string         `db:"reason" json:"reason"`
-	RequestedBy      string         `db:"requested_by" json:"requestedBy"`
-	ReviewedBy       *string        `db:"reviewed_by" json:"reviewedBy,omitempty"`
-	RequestedAt      time.Time      `db:"requested_at" json:"requestedAt"`
-	ReviewedAt       *time.Time     `db:"reviewed_at" json:"reviewedAt,omitempty"`
-	Note             *string        `db:"note" json:"note,omitempty"`
+	ID               string               `db:"id" json:"id"`
+	Type             MutationType         `db:"type" json:"type"`
+	Entity           string               `db:"entity" json:"entity"`
+	EntityID         string               `db:"entity_id" json:"entityId"`
+	CurrentSnapshot  []byte               `db:"current_snapshot" json:"currentSnapshot"`
+	RequestedChanges []byte               `db:"requested_changes" json:"requestedChanges"`
+	Status           MutationStatus       `db:"status" json:"status"`
+	Reason           string               `db:"reason" json:"reason"`
+	RequestedBy      string               `db:"requested_by" json:"requestedBy"`
+	ReviewedBy       *string              `db:"reviewed_by" json:"reviewedBy,omitempty"`
+	RequestedAt      time.Time            `db:"requested_at" json:"requestedAt"`
+	ReviewedAt       *time.Time           `db:"reviewed_at" json:"reviewedAt,omitempty"`
+	Note             *string              `db:"note" json:"note,omitempty"`
+	EscalatedAt      *time.Time           `db:"escalated_at" json:"escalatedAt,omitempty"`
+	Attachments      []MutationAttachment `db:"-" json:"attachments,omitempty"`
+	Diff             []MutationFieldDiff  `db:"-" json:"diff,omitempty"`
+}
+
+// MutationFieldDiff pairs the snapshot value of one field with its requested value. Old and New are
+// JSON values; Old is null when the snapshot lacks the field.
+type MutationFieldDiff struct {
+	Field   string          `json:"field"`
+	Label   string          `json:"label"`
+	Old     json.RawMessage `json:"old"`
+	New     json.RawMessage `json:"new"`
+	Changed bool            `json:"changed"`
+}
+
+// MutationAttachment links a mutation to a supporting document stored in the archive.
+type MutationAttachment struct {
+	MutationID string    `db:"mutation_id" json:"mutationId"`
+	ArchiveID  string    `db:"archive_id" json:"archiveId"`
+	Title      string    `db:"title" json:"title"`
+	MimeType   string    `db:"mime_type" json:"mimeType"`
+	SizeBytes  int64     `db:"size_bytes" json:"sizeBytes"`
+	AttachedBy string    `db:"attached_by" json:"attachedBy"`
+	AttachedAt time.Time `db:"attached_at" json:"attachedAt"`
 }
 
 // MutationFilter constrains listing queries.
@@ -47,6 +74,18 @@ type MutationFilter struct {
 	EntityID    string
 	RequestedBy string
 	ReviewerID  string
+	// RequestedBefore keeps mutations requested before this instant; zero disables it.
+	RequestedBefore time.Time
+	// Unescalated keeps mutations that have not been escalated yet.
+	Unescalated bool
 	Limit       int
 	Offset      int
 }
+
+// MutationPendingSummary describes the backlog of mutations awaiting review.
+type MutationPendingSummary struct {
+	Pending           int        `db:"pending" json:"pending"`
+	Overdue           int        `db:"overdue" json:"overdue"`
+	Escalated         int        `db:"escalated" json:"escalated"`
+	OldestRequestedAt *time.Time `db:"oldest_requested_at" json:"oldestRequestedAt,omitempty"`
+}
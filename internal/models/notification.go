@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Notification types.
+const (
+	NotificationTypeLessonPlanReminder = "LESSON_PLAN_REMINDER"
+	NotificationTypeLessonPlanReviewed = "LESSON_PLAN_REVIEWED"
+	NotificationTypeAttendanceAlert    = "ATTENDANCE_ALERT"
+	NotificationTypeAnnouncement       = "ANNOUNCEMENT"
+	NotificationTypeMutationReminder   = "MUTATION_REMINDER"
+	NotificationTypeMutationEscalated  = "MUTATION_ESCALATED"
+	NotificationTypeMutationExpired    = "MUTATION_EXPIRED"
+	NotificationTypeGradeAppeal        = "GRADE_APPEAL"
+	NotificationTypeGradeAppealOverdue = "GRADE_APPEAL_OVERDUE"
+	NotificationTypeGradeAppealDecided = "GRADE_APPEAL_DECIDED"
+	NotificationTypeAlertRule          = "ALERT_RULE"
+	NotificationTypeIntakePlaced       = "INTAKE_PLACED"
+)
+
+// Notification is an in-app message addressed to one user.
+type Notification struct {
+	ID        string     `db:"id" json:"id"`
+	UserID    string     `db:"user_id" json:"user_id"`
+	Type      string     `db:"type" json:"type"`
+	Title     string     `db:"title" json:"title"`
+	Body      string     `db:"body" json:"body"`
+	RefID     *string    `db:"ref_id" json:"ref_id,omitempty"`
+	DedupeKey *string    `db:"dedupe_key" json:"-"`
+	ReadAt    *time.Time `db:"read_at" json:"read_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	// PushAttempts counts push deliveries tried so far; it is only loaded by the push queue.
+	PushAttempts int `db:"push_attempts" json:"-"`
+}
+
+// DevicePlatform identifies the kind of device a push token belongs to.
+type DevicePlatform string
+
+// Device platforms.
+const (
+	DevicePlatformAndroid DevicePlatform = "ANDROID"
+	DevicePlatformIOS     DevicePlatform = "IOS"
+	DevicePlatformWeb     DevicePlatform = "WEB"
+)
+
+// DeviceToken is a push token registered by one of a user's devices.
+type DeviceToken struct {
+	ID         string         `db:"id" json:"id"`
+	UserID     string         `db:"user_id" json:"user_id"`
+	Token      string         `db:"token" json:"token"`
+	Platform   DevicePlatform `db:"platform" json:"platform"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	LastSeenAt time.Time      `db:"last_seen_at" json:"last_seen_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PanicEvent describes a panic recovered while serving a request.
+type PanicEvent struct {
+	RequestID  string    `json:"request_id,omitempty"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Value      string    `json:"value"`
+	Stack      string    `json:"stack"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// PermissionScope says how far a role may use a route.
+type PermissionScope string
+
+const (
+	// PermissionScopeAll admits the role on every resource of the route.
+	PermissionScopeAll PermissionScope = "ALL"
+	// PermissionScopeSelf admits the role only on resources the caller owns; on most routes that is
+	// when :id is the caller's own user ID.
+	PermissionScopeSelf PermissionScope = "SELF"
+)
+
+// roleSelf mirrors auth.Self, the pseudo-role guards use for SELF access.
+const roleSelf = "SELF"
+
+// RoutePolicy is the RBAC guarding one route, as read off the mounted route table.
+type RoutePolicy struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Authenticated is false for routes that take no user token, such as login and provider
+	// callbacks.
+	Authenticated bool `json:"authenticated"`
+	// Guards holds the roles each RBAC guard on the route admits, outermost first. A route needs
+	// every guard to pass; one without guards admits any authenticated caller.
+	Guards [][]string `json:"guards"`
+}
+
+// Access reports the scope role has on the route, and false when every request would be denied.
+func (p RoutePolicy) Access(role UserRole) (PermissionScope, bool) {
+	scope := PermissionScopeAll
+	for _, allowed := range p.Guards {
+		byRole, bySelf := false, false
+		for _, entry := range allowed {
+			switch entry {
+			case string(role):
+				byRole = true
+			case roleSelf:
+				bySelf = true
+			}
+		}
+		switch {
+		case byRole:
+		case bySelf:
+			scope = PermissionScopeSelf
+		default:
+			return "", false
+		}
+	}
+	return scope, true
+}
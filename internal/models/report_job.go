@@ -1,7 +1,9 @@
 package models
 
 import (
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -15,14 +17,34 @@ const (
 	ReportTypeGrades     ReportType = "grades"
 	ReportTypeBehavior   ReportType = "behavior"
 	ReportTypeSummary    ReportType = "summary"
+	// ReportTypeResearch is a pseudonymized dataset of a term's attendance, grades and behavior for
+	// analysis outside the school; only super admins can queue it and it is always a zip of CSVs.
+	ReportTypeResearch ReportType = "research"
+	// ReportTypeMinistryAttendance and ReportTypeMinistryGrades lay a term's attendance recap and
+	// final grades out in the education authority's submission format, as CSV or XLSX.
+	ReportTypeMinistryAttendance ReportType = "ministry_attendance"
+	ReportTypeMinistryGrades     ReportType = "ministry_grades"
+	// ReportTypeSemesterSchedule marks synchronous timetable exports; it cannot be queued via /reports.
+	ReportTypeSemesterSchedule ReportType = "semester_schedule"
+	// ReportTypeExamSchedule marks synchronous exam timetable exports; it cannot be queued via /reports.
+	ReportTypeExamSchedule ReportType = "exam_schedule"
+	// ReportTypeEventAttendance marks synchronous calendar event attendance exports; it cannot be
+	// queued via /reports.
+	ReportTypeEventAttendance ReportType = "event_attendance"
+	// ReportTypeScheduleClashes marks synchronous exports of a term's double-booked daily schedules;
+	// it cannot be queued via /reports.
+	ReportTypeScheduleClashes ReportType = "schedule_clashes"
 )
 
 // ReportFormat enumerates supported export formats.
 type ReportFormat string
 
 const (
-	ReportFormatCSV ReportFormat = "csv"
-	ReportFormatPDF ReportFormat = "pdf"
+	ReportFormatCSV  ReportFormat = "csv"
+	ReportFormatPDF  ReportFormat = "pdf"
+	ReportFormatXLSX ReportFormat = "xlsx"
+	// ReportFormatZIP bundles several CSV files; only research exports use it.
+	ReportFormatZIP ReportFormat = "zip"
 )
 
 // ReportStatus captures background job lifecycle states.
@@ -35,7 +57,67 @@ const (
 	ReportStatusFailed     ReportStatus = "FAILED"
 )
 
-// ReportJob persisted background job metadata.
+// ReportPriority orders queued report jobs; higher priorities are generated first.
+type ReportPriority string
+
+const (
+	ReportPriorityHigh   ReportPriority = "high"
+	ReportPriorityNormal ReportPriority = "normal"
+	ReportPriorityLow    ReportPriority = "low"
+)
+
+var reportPriorityRanks = map[ReportPriority]int64{
+	ReportPriorityLow:    0,
+	ReportPriorityNormal: 1,
+	ReportPriorityHigh:   2,
+}
+
+// Rank returns the priority's sort weight; unknown values rank as normal.
+func (p ReportPriority) Rank() int {
+	if rank, ok := reportPriorityRanks[p]; ok {
+		return int(rank)
+	}
+	return int(reportPriorityRanks[ReportPriorityNormal])
+}
+
+// Valid reports whether p is a known priority.
+func (p ReportPriority) Valid() bool {
+	_, ok := reportPriorityRanks[p]
+	return ok
+}
+
+// Value stores the priority as its rank so the database can order by it.
+func (p ReportPriority) Value() (driver.Value, error) {
+	return int64(p.Rank()), nil
+}
+
+// Scan maps a stored rank back to its priority.
+func (p *ReportPriority) Scan(value interface{}) error {
+	var rank int64
+	switch v := value.(type) {
+	case nil:
+		*p = ReportPriorityNormal
+		return nil
+	case int64:
+		rank = v
+	case []byte:
+		if _, err := fmt.Sscan(string(v), &rank); err != nil {
+			return fmt.Errorf("scan report priority: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported type %T for ReportPriority", value)
+	}
+	for priority, candidate := range reportPriorityRanks {
+		if candidate == rank {
+			*p = priority
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown report priority rank %d", rank)
+}
+
+// ReportJob persisted background job metadata. ClaimedBy names the worker that owns the job; the
+// claim lapses once HeartbeatAt goes stale so another replica can pick the job up.
 type ReportJob struct {
 	ID           string          `db:"id" json:"id"`
 	Type         ReportType      `db:"type" json:"type"`
@@ -47,6 +129,10 @@ type ReportJob struct {
 	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
 	FinishedAt   *time.Time      `db:"finished_at" json:"finished_at,omitempty"`
 	ErrorMessage *string         `db:"error_message" json:"error_message,omitempty"`
+	Priority     ReportPriority  `db:"priority" json:"priority"`
+	ClaimedBy    *string         `db:"claimed_by" json:"claimed_by,omitempty"`
+	HeartbeatAt  *time.Time      `db:"heartbeat_at" json:"heartbeat_at,omitempty"`
+	Fingerprint  string          `db:"fingerprint" json:"fingerprint,omitempty"`
 }
 
 // ReportJobParams stores request-scoped options persisted as JSONB.
@@ -57,6 +143,21 @@ type ReportJobParams struct {
 	Extras  map[string]string `json:"extras,omitempty"`
 }
 
+// Fingerprint identifies requests for the same report so identical jobs can be shared. ClassID and
+// Extras take part; the job's priority and requester do not.
+func (p ReportJobParams) Fingerprint(reportType ReportType) string {
+	if p.Extras == nil {
+		p.Extras = map[string]string{}
+	}
+	// json.Marshal sorts map keys, so equal params always hash the same.
+	data, _ := json.Marshal(struct {
+		Type   ReportType      `json:"type"`
+		Params ReportJobParams `json:"params"`
+	}{Type: reportType, Params: p})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // Value marshals params to JSON for persistence.
 func (p ReportJobParams) Value() (driver.Value, error) {
 	if p.Extras == nil {
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ResearchAttendanceRow is one daily attendance mark in a research export. The IDs are the real
+// ones; they are pseudonymized before anything is written.
+type ResearchAttendanceRow struct {
+	StudentID string           `db:"student_id"`
+	ClassID   string           `db:"class_id"`
+	Date      time.Time        `db:"date"`
+	Status    AttendanceStatus `db:"status"`
+}
+
+// ResearchGradeRow is one grade component score in a research export.
+type ResearchGradeRow struct {
+	StudentID     string  `db:"student_id"`
+	ClassID       string  `db:"class_id"`
+	SubjectCode   string  `db:"subject_code"`
+	ComponentCode string  `db:"component_code"`
+	Value         float64 `db:"grade_value"`
+}
+
+// ResearchBehaviorRow is one behavior note in a research export, without its free-text description.
+type ResearchBehaviorRow struct {
+	StudentID string           `db:"student_id"`
+	ClassID   string           `db:"class_id"`
+	Date      time.Time        `db:"date"`
+	NoteType  BehaviorNoteType `db:"note_type"`
+	Points    int              `db:"points"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RetentionDataClass names a kind of record the retention job purges.
+type RetentionDataClass string
+
+const (
+	// RetentionRefreshTokens covers refresh tokens that expired or were revoked.
+	RetentionRefreshTokens RetentionDataClass = "REFRESH_TOKENS"
+	RetentionAuditLogs     RetentionDataClass = "AUDIT_LOGS"
+)
+
+// RetentionDataClasses lists every purgeable data class in purge order.
+var RetentionDataClasses = []RetentionDataClass{RetentionRefreshTokens, RetentionAuditLogs}
+
+// Valid reports whether c is a known data class.
+func (c RetentionDataClass) Valid() bool {
+	for _, known := range RetentionDataClasses {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// LegalHold keeps records of a data class from being purged until it is released. A hold without a
+// user covers the whole class; otherwise only the user's records are kept.
+type LegalHold struct {
+	ID         string             `db:"id" json:"id"`
+	DataClass  RetentionDataClass `db:"data_class" json:"dataClass"`
+	UserID     *string            `db:"user_id" json:"userId,omitempty"`
+	Reason     string             `db:"reason" json:"reason"`
+	CreatedBy  string             `db:"created_by" json:"createdBy"`
+	CreatedAt  time.Time          `db:"created_at" json:"createdAt"`
+	ReleasedBy *string            `db:"released_by" json:"releasedBy,omitempty"`
+	ReleasedAt *time.Time         `db:"released_at" json:"releasedAt,omitempty"`
+}
+
+// Active reports whether the hold still protects its records.
+func (h LegalHold) Active() bool {
+	return h.ReleasedAt == nil
+}
+
+// RetentionCandidates counts the records of a data class past their retention period, and how many
+// of them an active legal hold keeps.
+type RetentionCandidates struct {
+	Eligible int64 `db:"eligible"`
+	Held     int64 `db:"held"`
+}
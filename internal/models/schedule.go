@@ -14,6 +14,8 @@ type Schedule struct {
 	Room      string    `db:"room" json:"room"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// LessonGroupID is set on the sessions of a lesson group, one schedule per member class.
+	LessonGroupID *string `db:"lesson_group_id" json:"lesson_group_id,omitempty"`
 }
 
 // ScheduleFilter describes query params for listing schedules.
@@ -41,6 +43,8 @@ type ScheduleConflict struct {
 	TimeSlot   string `json:"time_slot"`
 	Room       string `json:"room"`
 	Dimension  string `json:"dimension"`
+	// BlockSlots lists the periods, e.g. "3-4", of the proposed block lesson the conflict blocks.
+	BlockSlots string `json:"block_slots,omitempty"`
 }
 
 // ScheduleConflictError is returned when a schedule collides with an existing one.
@@ -0,0 +1,22 @@
+package models
+
+// SearchType identifies the entity group a search hit belongs to.
+type SearchType string
+
+const (
+	SearchTypeTeacher SearchType = "teacher"
+	SearchTypeStudent SearchType = "student"
+	SearchTypeClass   SearchType = "class"
+	SearchTypeSubject SearchType = "subject"
+	SearchTypeArchive SearchType = "archive"
+)
+
+// SearchTypes lists every searchable type in the order groups are returned.
+var SearchTypes = []SearchType{SearchTypeTeacher, SearchTypeStudent, SearchTypeClass, SearchTypeSubject, SearchTypeArchive}
+
+// SearchHit is one matching entity rendered for the global search bar.
+type SearchHit struct {
+	ID     string  `db:"id" json:"id"`
+	Label  string  `db:"label" json:"label"`
+	Detail *string `db:"detail" json:"detail,omitempty"`
+}
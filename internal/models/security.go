@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// AccessDenialReason identifies which layer refused a request.
+type AccessDenialReason string
+
+const (
+	// AccessDenialRBAC marks requests rejected by the role middleware.
+	AccessDenialRBAC AccessDenialReason = "RBAC"
+	// AccessDenialOwnership marks requests rejected by a service ownership check.
+	AccessDenialOwnership AccessDenialReason = "OWNERSHIP"
+)
+
+// AccessDenial captures a forbidden request for the security audit trail.
+type AccessDenial struct {
+	UserID     *string            `json:"user_id,omitempty"`
+	Role       UserRole           `json:"role,omitempty"`
+	Method     string             `json:"method"`
+	Route      string             `json:"route"`
+	Path       string             `json:"path"`
+	ResourceID *string            `json:"resource_id,omitempty"`
+	Reason     AccessDenialReason `json:"reason"`
+	IPAddress  string             `json:"ip_address"`
+	UserAgent  string             `json:"user_agent"`
+}
+
+// AccessDenialCount aggregates denials per actor, route and reason.
+type AccessDenialCount struct {
+	UserID *string `db:"user_id" json:"user_id,omitempty"`
+	Route  string  `db:"route" json:"route"`
+	Reason string  `db:"reason" json:"reason"`
+	Total  int     `db:"total" json:"total"`
+}
+
+// AccessDenialRecord is a stored denial read back from the audit log.
+type AccessDenialRecord struct {
+	ID         string    `db:"id" json:"id"`
+	UserID     *string   `db:"user_id" json:"user_id,omitempty"`
+	Route      string    `db:"route" json:"route"`
+	ResourceID *string   `db:"resource_id" json:"resource_id,omitempty"`
+	Method     string    `db:"method" json:"method"`
+	Reason     string    `db:"reason" json:"reason"`
+	IPAddress  string    `db:"ip_address" json:"ip_address"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
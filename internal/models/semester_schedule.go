@@ -18,8 +18,8 @@ const (
 // SemesterSchedule captures a versioned timetable proposal for a class-term pair.
 type SemesterSchedule struct {
 	ID        string                 `db:"id" json:"id"`
-	TermID    string                 `db:"term_id" json:"term_id"`
-	ClassID   string                 `db:"class_id" json:"class_id"`
+	TermID    TermID                 `db:"term_id" json:"term_id"`
+	ClassID   ClassID                `db:"class_id" json:"class_id"`
 	Version   int                    `db:"version" json:"version"`
 	Status    SemesterScheduleStatus `db:"status" json:"status"`
 	Meta      types.JSONText         `db:"meta" json:"meta"`
@@ -33,10 +33,19 @@ type SemesterScheduleSlot struct {
 	SemesterScheduleID string    `db:"semester_schedule_id" json:"semester_schedule_id"`
 	DayOfWeek          int       `db:"day_of_week" json:"day_of_week"`
 	TimeSlot           int       `db:"time_slot" json:"time_slot"`
-	SubjectID          string    `db:"subject_id" json:"subject_id"`
-	TeacherID          string    `db:"teacher_id" json:"teacher_id"`
+	SubjectID          SubjectID `db:"subject_id" json:"subject_id"`
+	TeacherID          TeacherID `db:"teacher_id" json:"teacher_id"`
 	Room               *string   `db:"room" json:"room,omitempty"`
-	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	// BlockSize is the number of consecutive periods of the block lesson the slot belongs to; 1 for
+	// single periods.
+	BlockSize int       `db:"block_size" json:"block_size"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// SemesterScheduleClassSlot is a slot annotated with the class owning its schedule.
+type SemesterScheduleClassSlot struct {
+	SemesterScheduleSlot
+	ClassID ClassID `db:"class_id" json:"class_id"`
 }
 
 // SemesterScheduleSummary aggregates versions available for a term/class pair.
@@ -56,3 +65,34 @@ type SemesterScheduleMeta struct {
 	Score     float64                `json:"score"`
 	CreatedAt time.Time              `json:"created_at"`
 }
+
+// ScheduleWarningType classifies an inconsistency found when revalidating a published schedule.
+type ScheduleWarningType string
+
+const (
+	// ScheduleWarningTeacherUnassigned flags a slot whose teacher no longer teaches its subject in the class.
+	ScheduleWarningTeacherUnassigned ScheduleWarningType = "TEACHER_UNASSIGNED"
+	// ScheduleWarningTeacherUnavailable flags a slot inside one of the teacher's unavailable windows.
+	ScheduleWarningTeacherUnavailable ScheduleWarningType = "TEACHER_UNAVAILABLE"
+	// ScheduleWarningTeacherDoubleBooked flags a slot the teacher also teaches in another published schedule.
+	ScheduleWarningTeacherDoubleBooked ScheduleWarningType = "TEACHER_DOUBLE_BOOKED"
+	// ScheduleWarningDailyLoad flags a day on which the teacher exceeds their maximum daily load.
+	ScheduleWarningDailyLoad ScheduleWarningType = "TEACHER_DAILY_LOAD"
+	// ScheduleWarningWeeklyLoad flags a teacher whose published slots exceed their maximum weekly load.
+	ScheduleWarningWeeklyLoad ScheduleWarningType = "TEACHER_WEEKLY_LOAD"
+)
+
+// ScheduleWarning is an actionable inconsistency in a published schedule, recorded when teacher
+// assignments or preferences change after publishing. DayOfWeek and TimeSlot are nil for warnings
+// that concern the whole week.
+type ScheduleWarning struct {
+	ID                 string              `db:"id" json:"id"`
+	SemesterScheduleID string              `db:"semester_schedule_id" json:"semester_schedule_id"`
+	Type               ScheduleWarningType `db:"type" json:"type"`
+	TeacherID          string              `db:"teacher_id" json:"teacher_id"`
+	SubjectID          *string             `db:"subject_id" json:"subject_id,omitempty"`
+	DayOfWeek          *int                `db:"day_of_week" json:"day_of_week,omitempty"`
+	TimeSlot           *int                `db:"time_slot" json:"time_slot,omitempty"`
+	Message            string              `db:"message" json:"message"`
+	DetectedAt         time.Time           `db:"detected_at" json:"detected_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// SlotDefinition maps a numbered time slot to wall-clock times for a term.
+type SlotDefinition struct {
+	ID        string    `db:"id" json:"id"`
+	TermID    string    `db:"term_id" json:"term_id"`
+	SlotIndex int       `db:"slot_index" json:"slot_index"`
+	StartTime string    `db:"start_time" json:"start_time"`
+	EndTime   string    `db:"end_time" json:"end_time"`
+	Label     *string   `db:"label" json:"label,omitempty"`
+	IsBreak   bool      `db:"is_break" json:"is_break"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DisplayLabel returns the explicit label when set, otherwise the time range (e.g. "08:30–09:15").
+func (d SlotDefinition) DisplayLabel() string {
+	if d.Label != nil && *d.Label != "" {
+		return *d.Label
+	}
+	return d.StartTime + "–" + d.EndTime
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// StorageStatus is the outcome of checking one storage directory. FreeBytes and TotalBytes are
+// zero when the platform cannot report disk usage.
+type StorageStatus struct {
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	Healthy     bool      `json:"healthy"`
+	Writable    bool      `json:"writable"`
+	FreeBytes   uint64    `json:"free_bytes"`
+	TotalBytes  uint64    `json:"total_bytes"`
+	FreePercent float64   `json:"free_percent"`
+	Error       string    `json:"error,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
@@ -6,6 +6,7 @@ import "time"
 type Student struct {
 	ID        string    `db:"id" json:"id"`
 	NIS       string    `db:"nis" json:"nis"`
+	NISN      *string   `db:"nisn" json:"nisn,omitempty"`
 	FullName  string    `db:"full_name" json:"full_name"`
 	Gender    string    `db:"gender" json:"gender"`
 	BirthDate time.Time `db:"birth_date" json:"birth_date"`
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// SubjectLoadPresetItem is the weekly demand of one subject within a preset.
+type SubjectLoadPresetItem struct {
+	SubjectID   string `json:"subject_id"`
+	WeeklyCount int    `json:"weekly_count"`
+	BlockSize   int    `json:"block_size,omitempty"`
+	Difficulty  int    `json:"difficulty,omitempty"`
+}
+
+// SubjectLoadPreset is a reusable curriculum load for classes of a grade and, optionally, a track.
+// The scheduler expands it into subject loads when a generation payload names it. Loads holds a
+// JSON encoded []SubjectLoadPresetItem.
+type SubjectLoadPreset struct {
+	ID        string         `db:"id" json:"id"`
+	Name      string         `db:"name" json:"name"`
+	Grade     string         `db:"grade" json:"grade"`
+	Track     *string        `db:"track" json:"track,omitempty"`
+	Loads     types.JSONText `db:"loads" json:"loads"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+// SubjectLoadPresetFilter narrows preset listings.
+type SubjectLoadPresetFilter struct {
+	Grade string
+	Track string
+}
@@ -17,10 +17,20 @@ type Teacher struct {
 
 // TeacherFilter captures filtering options for listing teachers.
 type TeacherFilter struct {
-	Search    string
-	Active    *bool
+	Search string
+	Active *bool
+	// TermID, when set, adds per-term workload aggregates to each teacher.
+	TermID    string
 	Page      int
 	PageSize  int
 	SortBy    string
 	SortOrder string
 }
+
+// TeacherWithLoad augments a teacher with workload aggregates for a single term.
+type TeacherWithLoad struct {
+	Teacher
+	AssignmentCount int  `db:"assignment_count" json:"assignment_count"`
+	IsHomeroom      bool `db:"is_homeroom" json:"is_homeroom"`
+	WeeklyLoad      int  `db:"weekly_load" json:"weekly_load"`
+}
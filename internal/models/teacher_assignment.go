@@ -24,8 +24,30 @@ type TeacherAssignment struct {
 // TeacherAssignmentDetail enriches assignments with descriptive fields.
 type TeacherAssignmentDetail struct {
 	TeacherAssignment
-	ClassName   string  `db:"class_name" json:"class_name"`
-	SubjectName string  `db:"subject_name" json:"subject_name"`
-	TermName    string  `db:"term_name" json:"term_name"`
+	ClassName   string  `db:"class_name" json:"class_name,omitempty"`
+	SubjectName string  `db:"subject_name" json:"subject_name,omitempty"`
+	TermName    string  `db:"term_name" json:"term_name,omitempty"`
 	TeacherName *string `db:"teacher_name" json:"teacher_name,omitempty"`
 }
+
+// Expandable relations on teacher assignment listings.
+const (
+	TeacherAssignmentExpandClass   = "class"
+	TeacherAssignmentExpandSubject = "subject"
+	TeacherAssignmentExpandTerm    = "term"
+	TeacherAssignmentExpandTeacher = "teacher"
+)
+
+// TeacherAssignmentFilter narrows and pages a teacher's assignments.
+type TeacherAssignmentFilter struct {
+	TeacherID string
+	TermID    string
+	ClassID   string
+	SubjectID string
+	// Expand lists the relations whose names are joined in; nil expands all of them.
+	Expand    []string
+	Page      int
+	PageSize  int
+	SortBy    string
+	SortOrder string
+}
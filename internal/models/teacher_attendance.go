@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// TeacherAttendance is a teacher's clock-in/out for a single school-local date.
+type TeacherAttendance struct {
+	ID                string     `db:"id" json:"id"`
+	TeacherID         string     `db:"teacher_id" json:"teacher_id"`
+	Date              time.Time  `db:"date" json:"date"`
+	CheckInAt         time.Time  `db:"check_in_at" json:"check_in_at"`
+	CheckOutAt        *time.Time `db:"check_out_at" json:"check_out_at,omitempty"`
+	LateMinutes       int        `db:"late_minutes" json:"late_minutes"`
+	CheckInIP         *string    `db:"check_in_ip" json:"check_in_ip,omitempty"`
+	CheckOutIP        *string    `db:"check_out_ip" json:"check_out_ip,omitempty"`
+	CheckInLatitude   *float64   `db:"check_in_latitude" json:"check_in_latitude,omitempty"`
+	CheckInLongitude  *float64   `db:"check_in_longitude" json:"check_in_longitude,omitempty"`
+	CheckOutLatitude  *float64   `db:"check_out_latitude" json:"check_out_latitude,omitempty"`
+	CheckOutLongitude *float64   `db:"check_out_longitude" json:"check_out_longitude,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// TeacherCheckOut carries the fields written when a teacher clocks out.
+type TeacherCheckOut struct {
+	At        time.Time
+	IP        *string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// TeacherAttendanceRecap aggregates one teacher's presence over a date range.
+type TeacherAttendanceRecap struct {
+	TeacherID        string `db:"teacher_id" json:"teacher_id"`
+	FullName         string `db:"full_name" json:"full_name"`
+	DaysPresent      int    `db:"days_present" json:"days_present"`
+	LateDays         int    `db:"late_days" json:"late_days"`
+	LateMinutes      int    `db:"late_minutes" json:"late_minutes"`
+	MissingCheckouts int    `db:"missing_checkouts" json:"missing_checkouts"`
+	WorkedMinutes    int    `db:"worked_minutes" json:"worked_minutes"`
+}
+
+// TeacherPresenceCounts summarises teacher clock-ins for a single date.
+type TeacherPresenceCounts struct {
+	ActiveTeachers int `db:"active_teachers" json:"active_teachers"`
+	CheckedIn      int `db:"checked_in" json:"checked_in"`
+	Late           int `db:"late" json:"late"`
+	CheckedOut     int `db:"checked_out" json:"checked_out"`
+}
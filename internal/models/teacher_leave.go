@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// TeacherLeaveType classifies a teacher's leave.
+type TeacherLeaveType string
+
+const (
+	TeacherLeaveSick     TeacherLeaveType = "SICK"
+	TeacherLeavePersonal TeacherLeaveType = "PERSONAL"
+	TeacherLeaveTraining TeacherLeaveType = "TRAINING"
+)
+
+// TeacherLeaveTypes lists the leave types in report order.
+var TeacherLeaveTypes = []TeacherLeaveType{TeacherLeaveSick, TeacherLeavePersonal, TeacherLeaveTraining}
+
+// TeacherLeaveStatus tracks a leave request through its approval workflow.
+type TeacherLeaveStatus string
+
+const (
+	TeacherLeavePending   TeacherLeaveStatus = "PENDING"
+	TeacherLeaveApproved  TeacherLeaveStatus = "APPROVED"
+	TeacherLeaveRejected  TeacherLeaveStatus = "REJECTED"
+	TeacherLeaveCancelled TeacherLeaveStatus = "CANCELLED"
+)
+
+// TeacherLeave is a teacher's leave over an inclusive date range. Days counts the school days in the
+// range when the leave was requested.
+type TeacherLeave struct {
+	ID          string             `db:"id" json:"id"`
+	TeacherID   string             `db:"teacher_id" json:"teacherId"`
+	TeacherName string             `db:"teacher_name" json:"teacherName,omitempty"`
+	Type        TeacherLeaveType   `db:"type" json:"type"`
+	StartDate   time.Time          `db:"start_date" json:"startDate"`
+	EndDate     time.Time          `db:"end_date" json:"endDate"`
+	Days        int                `db:"days" json:"days"`
+	Reason      *string            `db:"reason" json:"reason,omitempty"`
+	Status      TeacherLeaveStatus `db:"status" json:"status"`
+	RequestedBy string             `db:"requested_by" json:"requestedBy"`
+	ReviewedBy  *string            `db:"reviewed_by" json:"reviewedBy,omitempty"`
+	ReviewedAt  *time.Time         `db:"reviewed_at" json:"reviewedAt,omitempty"`
+	ReviewNote  *string            `db:"review_note" json:"reviewNote,omitempty"`
+	CreatedAt   time.Time          `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time          `db:"updated_at" json:"updatedAt"`
+}
+
+// Covers reports whether the leave includes the date.
+func (l TeacherLeave) Covers(date time.Time) bool {
+	day := date.Format("2006-01-02")
+	return day >= l.StartDate.Format("2006-01-02") && day <= l.EndDate.Format("2006-01-02")
+}
+
+// TeacherLeaveFilter narrows leave listings. From and To select leaves overlapping the range.
+type TeacherLeaveFilter struct {
+	TeacherID string
+	Status    TeacherLeaveStatus
+	From      *time.Time
+	To        *time.Time
+}
+
+// TeacherLeaveUsage sums one teacher's approved and pending leave days of one type in a year. Type is
+// nil for teachers without leave that year.
+type TeacherLeaveUsage struct {
+	TeacherID   string            `db:"teacher_id"`
+	TeacherName string            `db:"teacher_name"`
+	Type        *TeacherLeaveType `db:"type"`
+	Used        int               `db:"used"`
+	Pending     int               `db:"pending"`
+}
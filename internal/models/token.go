@@ -13,4 +13,9 @@ type RefreshToken struct {
 	RevokedAt *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
 	IPAddress string     `db:"ip_address" json:"ip_address"`
 	UserAgent string     `db:"user_agent" json:"user_agent"`
+	// SessionID and SessionStartedAt identify the login the token descends from; rotation keeps them.
+	SessionID        string    `db:"session_id" json:"session_id"`
+	SessionStartedAt time.Time `db:"session_started_at" json:"session_started_at"`
+	// LastActiveAt is the last refresh or heartbeat of the session.
+	LastActiveAt time.Time `db:"last_active_at" json:"last_active_at"`
 }
@@ -10,6 +10,7 @@ const (
 	RoleAdmin      UserRole = "ADMIN"
 	RoleTeacher    UserRole = "TEACHER"
 	RoleStudent    UserRole = "STUDENT"
+	RoleGuardian   UserRole = "GUARDIAN"
 )
 
 // User represents an application user stored in the users table.
@@ -0,0 +1,79 @@
+// Package ports defines the canonical repository interfaces shared by services. Services depend on
+// these instead of redeclaring the same lookups locally, and the repository package asserts at
+// compile time that its implementations satisfy them. Interfaces used by a single service stay
+// next to that service.
+package ports
+
+import (
+	"context"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// TermReader loads a term by ID.
+type TermReader interface {
+	FindByID(ctx context.Context, id string) (*models.Term, error)
+}
+
+// ActiveTermReader resolves the currently active term.
+type ActiveTermReader interface {
+	FindActive(ctx context.Context) (*models.Term, error)
+}
+
+// TermResolver loads terms by ID and resolves the active term.
+type TermResolver interface {
+	TermReader
+	ActiveTermReader
+}
+
+// ClassReader loads a class by ID.
+type ClassReader interface {
+	FindByID(ctx context.Context, id string) (*models.Class, error)
+}
+
+// SubjectReader loads a subject by ID.
+type SubjectReader interface {
+	FindByID(ctx context.Context, id string) (*models.Subject, error)
+}
+
+// TeacherReader loads a teacher by ID.
+type TeacherReader interface {
+	FindByID(ctx context.Context, id string) (*models.Teacher, error)
+}
+
+// StudentReader loads a student with class details by ID.
+type StudentReader interface {
+	FindByID(ctx context.Context, id string) (*models.StudentDetail, error)
+}
+
+// TeacherPreferenceReader loads a teacher's scheduling preferences.
+type TeacherPreferenceReader interface {
+	GetByTeacher(ctx context.Context, teacherID string) (*models.TeacherPreference, error)
+}
+
+// TeacherAssignmentLister lists a teacher's class/subject assignments across terms.
+type TeacherAssignmentLister interface {
+	ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error)
+}
+
+// TeacherAssignmentChecker reports whether a teacher teaches a subject to a class in a term.
+type TeacherAssignmentChecker interface {
+	Exists(ctx context.Context, teacherID, classID, subjectID, termID string) (bool, error)
+}
+
+// ClassAccessChecker reports whether a teacher has any assignment in a class during a term.
+type ClassAccessChecker interface {
+	HasClassAccess(ctx context.Context, teacherID, classID, termID string) (bool, error)
+}
+
+// AnalyticsRepository reads the aggregated attendance, grade and behaviour views.
+type AnalyticsRepository interface {
+	AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error)
+	GradeSummary(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, error)
+	BehaviorSummary(ctx context.Context, filter models.AnalyticsBehaviorFilter) ([]models.AnalyticsBehaviorSummary, error)
+}
+
+// AuditLogger persists audit trail records.
+type AuditLogger interface {
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// absenceMessageInsertChunk bounds the rows per INSERT; each row binds 10 parameters and Postgres
+// allows 65535 per statement.
+const absenceMessageInsertChunk = 1000
+
+const absenceMessageColumns = `m.id, m.attendance_id, m.student_id, m.guardian_id, m.date, m.channel, m.phone, m.body, m.status,
+m.provider_message_id, m.attempts, m.error, m.send_after, m.sent_at, m.delivered_at, m.created_at, m.updated_at`
+
+// AbsenceMessageRepository stores the absence messages queued for guardians and their delivery log.
+type AbsenceMessageRepository struct {
+	db *sqlx.DB
+}
+
+// NewAbsenceMessageRepository constructs the repository.
+func NewAbsenceMessageRepository(db *sqlx.DB) *AbsenceMessageRepository {
+	return &AbsenceMessageRepository{db: db}
+}
+
+// Recipients returns the guardians to message about the students of the given enrollments: active
+// guardian accounts with a phone on file that did not opt out of absence messages.
+func (r *AbsenceMessageRepository) Recipients(ctx context.Context, enrollmentIDs []string) ([]models.AbsenceRecipient, error) {
+	if len(enrollmentIDs) == 0 {
+		return nil, nil
+	}
+	const query = `SELECT e.id AS enrollment_id, e.student_id, s.full_name AS student_name, gc.guardian_id, gc.phone, gc.channel
+FROM enrollments e
+JOIN students s ON s.id = e.student_id
+JOIN guardian_students gs ON gs.student_id = e.student_id
+JOIN guardian_contacts gc ON gc.guardian_id = gs.guardian_id
+JOIN users u ON u.id = gc.guardian_id
+WHERE e.id = ANY($1) AND gc.absence_alerts = TRUE AND u.active = TRUE`
+	var recipients []models.AbsenceRecipient
+	if err := r.db.SelectContext(ctx, &recipients, query, pq.Array(enrollmentIDs)); err != nil {
+		return nil, fmt.Errorf("list absence message recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// Enqueue stores messages as pending and returns how many were written. A guardian already queued
+// for the same student and day is skipped, so only the first absence of a day is messaged.
+func (r *AbsenceMessageRepository) Enqueue(ctx context.Context, messages []models.AbsenceMessage) (int, error) {
+	now := time.Now().UTC()
+	written := 0
+	for start := 0; start < len(messages); start += absenceMessageInsertChunk {
+		end := start + absenceMessageInsertChunk
+		if end > len(messages) {
+			end = len(messages)
+		}
+		var values strings.Builder
+		args := make([]interface{}, 0, (end-start)*10)
+		for i, message := range messages[start:end] {
+			if i > 0 {
+				values.WriteString(", ")
+			}
+			if message.ID == "" {
+				message.ID = uuid.NewString()
+			}
+			n := len(args)
+			fmt.Fprintf(&values, "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, 'PENDING', $%d, $%d, $%d)",
+				n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+10)
+			args = append(args, message.ID, message.AttendanceID, message.StudentID, message.GuardianID, message.Date, message.Channel,
+				message.Phone, message.Body, message.SendAfter, now)
+		}
+		query := `INSERT INTO absence_messages (id, attendance_id, student_id, guardian_id, date, channel, phone, body, status, send_after, created_at, updated_at)
+VALUES ` + values.String() + `
+ON CONFLICT (student_id, guardian_id, date) DO NOTHING`
+		result, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return written, fmt.Errorf("enqueue absence messages: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return written, fmt.Errorf("check absence message rows: %w", err)
+		}
+		written += int(affected)
+	}
+	return written, nil
+}
+
+// ClaimDue leases up to limit pending messages due at now until leaseUntil and counts the attempt.
+// Claimed messages carry the student's name and the current status of the absence.
+func (r *AbsenceMessageRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.AbsenceMessage, error) {
+	const query = `UPDATE absence_messages m SET send_after = $2, attempts = m.attempts + 1, updated_at = $1
+WHERE m.id IN (
+    SELECT id FROM absence_messages WHERE status = 'PENDING' AND send_after <= $1
+    ORDER BY send_after LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + absenceMessageColumns + `,
+    (SELECT full_name FROM students WHERE id = m.student_id) AS student_name,
+    (SELECT status FROM daily_attendance WHERE id = m.attendance_id AND date = m.date) AS attendance_status`
+	var messages []models.AbsenceMessage
+	if err := r.db.SelectContext(ctx, &messages, query, now, leaseUntil, limit); err != nil {
+		return nil, fmt.Errorf("claim absence messages: %w", err)
+	}
+	return messages, nil
+}
+
+// MarkSent records the provider's id for a message it accepted.
+func (r *AbsenceMessageRepository) MarkSent(ctx context.Context, id, providerMessageID string, sentAt time.Time) error {
+	return r.update(ctx, `UPDATE absence_messages SET status = 'SENT', provider_message_id = $2, sent_at = $3, error = NULL, updated_at = $3 WHERE id = $1`,
+		id, providerMessageID, sentAt)
+}
+
+// MarkSkipped closes a message that no longer needs sending.
+func (r *AbsenceMessageRepository) MarkSkipped(ctx context.Context, id, reason string, at time.Time) error {
+	return r.update(ctx, `UPDATE absence_messages SET status = 'SKIPPED', error = $2, updated_at = $3 WHERE id = $1`, id, reason, at)
+}
+
+// MarkFailed records why a send failed. A nil retryAt gives the message up; otherwise it stays
+// pending and becomes due again at retryAt.
+func (r *AbsenceMessageRepository) MarkFailed(ctx context.Context, id, reason string, retryAt *time.Time, at time.Time) error {
+	if retryAt == nil {
+		return r.update(ctx, `UPDATE absence_messages SET status = 'FAILED', error = $2, updated_at = $3 WHERE id = $1`, id, reason, at)
+	}
+	return r.update(ctx, `UPDATE absence_messages SET error = $2, send_after = $3, updated_at = $4 WHERE id = $1`, id, reason, *retryAt, at)
+}
+
+// ApplyStatus moves a sent message to the status reported by the provider. Only SENT messages move,
+// so a late or repeated callback never overrides a final status. It reports whether a message moved.
+func (r *AbsenceMessageRepository) ApplyStatus(ctx context.Context, providerMessageID string, status models.AbsenceMessageStatus, reason *string, at time.Time) (bool, error) {
+	const query = `UPDATE absence_messages
+SET status = $2, error = COALESCE($3, error), delivered_at = CASE WHEN $2 = 'DELIVERED' THEN $4 ELSE delivered_at END, updated_at = $4
+WHERE provider_message_id = $1 AND status = 'SENT'`
+	result, err := r.db.ExecContext(ctx, query, providerMessageID, status, reason, at)
+	if err != nil {
+		return false, fmt.Errorf("apply absence message status: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check absence message rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// List returns the delivery log, newest first.
+func (r *AbsenceMessageRepository) List(ctx context.Context, filter models.AbsenceMessageFilter) ([]models.AbsenceMessage, int, error) {
+	var where []string
+	var args []interface{}
+	if filter.Date != nil {
+		args = append(args, *filter.Date)
+		where = append(where, fmt.Sprintf("m.date = $%d", len(args)))
+	}
+	if filter.StudentID != "" {
+		args = append(args, filter.StudentID)
+		where = append(where, fmt.Sprintf("m.student_id = $%d", len(args)))
+	}
+	if filter.GuardianID != "" {
+		args = append(args, filter.GuardianID)
+		where = append(where, fmt.Sprintf("m.guardian_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("m.status = $%d", len(args)))
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	size := filter.PageSize
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+	offset := (page - 1) * size
+
+	query := fmt.Sprintf(`SELECT %s, s.full_name AS student_name
+FROM absence_messages m
+JOIN students s ON s.id = m.student_id
+%s
+ORDER BY m.created_at DESC, m.id
+LIMIT %d OFFSET %d`, absenceMessageColumns, whereClause, size, offset)
+	var messages []models.AbsenceMessage
+	if err := r.db.SelectContext(ctx, &messages, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("list absence messages: %w", err)
+	}
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM absence_messages m "+whereClause, args...); err != nil {
+		return nil, 0, fmt.Errorf("count absence messages: %w", err)
+	}
+	return messages, total, nil
+}
+
+func (r *AbsenceMessageRepository) update(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update absence message: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check absence message rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestAbsenceMessageRepositoryEnqueue(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAbsenceMessageRepository(sqlx.NewDb(db, "sqlmock"))
+
+	date := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	sendAfter := date.Add(time.Hour)
+	mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'PENDING', $9, $10, $10), ($11, $12, $13, $14, $15, $16, $17, $18, 'PENDING', $19, $20, $20)")+
+		`\s+ON CONFLICT \(student_id, guardian_id, date\) DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), "att-1", "student-1", "guardian-1", date, models.MessageChannelSMS, "+6281200000001", "Ani absen", sendAfter, sqlmock.AnyArg(),
+			sqlmock.AnyArg(), "att-1", "student-1", "guardian-2", date, models.MessageChannelWhatsApp, "+6281200000002", "Ani absen", sendAfter, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	written, err := repo.Enqueue(context.Background(), []models.AbsenceMessage{
+		{AttendanceID: "att-1", StudentID: "student-1", GuardianID: "guardian-1", Date: date, Channel: models.MessageChannelSMS, Phone: "+6281200000001", Body: "Ani absen", SendAfter: sendAfter},
+		{AttendanceID: "att-1", StudentID: "student-1", GuardianID: "guardian-2", Date: date, Channel: models.MessageChannelWhatsApp, Phone: "+6281200000002", Body: "Ani absen", SendAfter: sendAfter},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, written, "a guardian already messaged that day is skipped")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAbsenceMessageRepositoryApplyStatus(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAbsenceMessageRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now().UTC()
+	mock.ExpectExec(`UPDATE absence_messages\s+SET status = \$2.*WHERE provider_message_id = \$1 AND status = 'SENT'`).
+		WithArgs("SM1", models.AbsenceMessageDelivered, nil, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	moved, err := repo.ApplyStatus(context.Background(), "SM1", models.AbsenceMessageDelivered, nil, now)
+	require.NoError(t, err)
+	assert.False(t, moved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
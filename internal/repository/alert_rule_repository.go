@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const alertRuleColumns = `id, name, metric, comparator, threshold, audience, channel, enabled, updated_by, created_at, updated_at`
+
+// AlertRuleRepository persists alert rules and the breaches found by their evaluation.
+type AlertRuleRepository struct {
+	db *sqlx.DB
+}
+
+// NewAlertRuleRepository constructs the repository.
+func NewAlertRuleRepository(db *sqlx.DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+// ListRules returns the alert rules by name; disabled ones only when includeDisabled is set.
+func (r *AlertRuleRepository) ListRules(ctx context.Context, includeDisabled bool) ([]models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules`
+	if !includeDisabled {
+		query += ` WHERE enabled`
+	}
+	query += ` ORDER BY name ASC, id ASC`
+	rules := make([]models.AlertRule, 0)
+	if err := r.db.SelectContext(ctx, &rules, query); err != nil {
+		return nil, fmt.Errorf("list alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+// FindRule loads an alert rule by id.
+func (r *AlertRuleRepository) FindRule(ctx context.Context, id string) (*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1`
+	var rule models.AlertRule
+	if err := r.db.GetContext(ctx, &rule, query, id); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// CreateRule persists a new alert rule.
+func (r *AlertRuleRepository) CreateRule(ctx context.Context, rule *models.AlertRule) error {
+	if rule.ID == "" {
+		rule.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = rule.CreatedAt
+	const query = `INSERT INTO alert_rules (id, name, metric, comparator, threshold, audience, channel, enabled, updated_by, created_at, updated_at)
+VALUES (:id, :name, :metric, :comparator, :threshold, :audience, :channel, :enabled, :updated_by, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, rule); err != nil {
+		return fmt.Errorf("create alert rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule replaces the editable fields of a rule. It returns sql.ErrNoRows when the rule does not
+// exist.
+func (r *AlertRuleRepository) UpdateRule(ctx context.Context, rule *models.AlertRule) error {
+	rule.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE alert_rules SET name = :name, metric = :metric, comparator = :comparator, threshold = :threshold,
+    audience = :audience, channel = :channel, enabled = :enabled, updated_by = :updated_by, updated_at = :updated_at
+WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, query, rule)
+	if err != nil {
+		return fmt.Errorf("update alert rule: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check alert rule update rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteRule removes a rule together with its breaches. It returns sql.ErrNoRows when the rule does
+// not exist.
+func (r *AlertRuleRepository) DeleteRule(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete alert rule: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check alert rule delete rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReplaceBreaches makes breaches the complete set of open breaches for a term: breaches of classes
+// that recovered, or of rules that are no longer evaluated, are cleared and the rest are inserted or
+// refreshed. It returns the breaches that were not open before.
+func (r *AlertRuleRepository) ReplaceBreaches(ctx context.Context, termID string, breaches []models.AlertRuleBreach, now time.Time) (created []models.AlertRuleBreach, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin replace alert rule breaches: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var open []string
+	if err = tx.SelectContext(ctx, &open, `SELECT rule_id || ':' || class_id FROM alert_rule_breaches WHERE term_id = $1`, termID); err != nil {
+		return nil, fmt.Errorf("list open alert rule breaches: %w", err)
+	}
+	existing := make(map[string]struct{}, len(open))
+	for _, key := range open {
+		existing[key] = struct{}{}
+	}
+
+	keep := make([]string, len(breaches))
+	for i, breach := range breaches {
+		keep[i] = breach.RuleID + ":" + breach.ClassID
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM alert_rule_breaches WHERE term_id = $1 AND NOT ((rule_id || ':' || class_id) = ANY($2))`, termID, pq.Array(keep)); err != nil {
+		return nil, fmt.Errorf("clear resolved alert rule breaches: %w", err)
+	}
+
+	const upsert = `INSERT INTO alert_rule_breaches (id, rule_id, term_id, class_id, value, threshold, detected_at, updated_at)
+VALUES (:id, :rule_id, :term_id, :class_id, :value, :threshold, :detected_at, :updated_at)
+ON CONFLICT (rule_id, term_id, class_id) DO UPDATE SET value = EXCLUDED.value, threshold = EXCLUDED.threshold,
+    updated_at = EXCLUDED.updated_at`
+	for _, breach := range breaches {
+		payload := breach
+		payload.TermID = termID
+		if payload.ID == "" {
+			payload.ID = uuid.NewString()
+		}
+		payload.DetectedAt = now
+		payload.UpdatedAt = now
+		if _, err = tx.NamedExecContext(ctx, upsert, &payload); err != nil {
+			return nil, fmt.Errorf("upsert alert rule breach: %w", err)
+		}
+		if _, ok := existing[breach.RuleID+":"+breach.ClassID]; !ok {
+			created = append(created, payload)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit replace alert rule breaches: %w", err)
+	}
+	return created, nil
+}
+
+// ListBreaches returns open breaches with their rule, oldest first.
+func (r *AlertRuleRepository) ListBreaches(ctx context.Context, filter models.AlertRuleBreachFilter) ([]models.AlertRuleBreach, error) {
+	var builder strings.Builder
+	builder.WriteString(`SELECT b.id, b.rule_id, ar.name AS rule_name, ar.metric, ar.comparator, ar.audience, b.term_id, b.class_id,
+    b.value, b.threshold, b.detected_at, b.updated_at
+FROM alert_rule_breaches b
+JOIN alert_rules ar ON ar.id = b.rule_id
+WHERE ar.enabled`)
+	var args []interface{}
+	if filter.TermID != "" {
+		args = append(args, filter.TermID)
+		fmt.Fprintf(&builder, " AND b.term_id = $%d", len(args))
+	}
+	if len(filter.ClassIDs) > 0 {
+		args = append(args, pq.Array(filter.ClassIDs))
+		fmt.Fprintf(&builder, " AND b.class_id = ANY($%d)", len(args))
+	}
+	if len(filter.Audiences) > 0 {
+		audiences := make([]string, len(filter.Audiences))
+		for i, audience := range filter.Audiences {
+			audiences[i] = string(audience)
+		}
+		args = append(args, pq.Array(audiences))
+		fmt.Fprintf(&builder, " AND ar.audience = ANY($%d)", len(args))
+	}
+	if filter.TeacherID != "" {
+		args = append(args, filter.TeacherID)
+		fmt.Fprintf(&builder, `
+  AND EXISTS (
+    SELECT 1 FROM teacher_assignments ta
+    WHERE ta.class_id = b.class_id AND ta.term_id = b.term_id AND ta.teacher_id = $%d
+  )`, len(args))
+	}
+	builder.WriteString("\nORDER BY b.detected_at ASC, b.class_id ASC, ar.name ASC")
+
+	var breaches []models.AlertRuleBreach
+	if err := r.db.SelectContext(ctx, &breaches, builder.String(), args...); err != nil {
+		return nil, fmt.Errorf("list alert rule breaches: %w", err)
+	}
+	return breaches, nil
+}
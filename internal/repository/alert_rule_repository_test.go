@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestAlertRuleRepositoryReplaceBreaches(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewAlertRuleRepository(db)
+
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	breaches := []models.AlertRuleBreach{
+		{RuleID: "rule-1", ClassID: "class-a", Value: 82, Threshold: 90},
+		{RuleID: "rule-1", ClassID: "class-b", Value: 85, Threshold: 90},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT rule_id || ':' || class_id FROM alert_rule_breaches WHERE term_id = $1")).
+		WithArgs("term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("rule-1:class-a").AddRow("rule-2:class-a"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM alert_rule_breaches WHERE term_id = $1 AND NOT ((rule_id || ':' || class_id) = ANY($2))")).
+		WithArgs("term-1", pq.Array([]string{"rule-1:class-a", "rule-1:class-b"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (rule_id, term_id, class_id) DO UPDATE")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (rule_id, term_id, class_id) DO UPDATE")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.ReplaceBreaches(context.Background(), "term-1", breaches, now)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "class-b", created[0].ClassID)
+	assert.Equal(t, "term-1", created[0].TermID)
+	assert.Equal(t, now, created[0].DetectedAt)
+	assert.NotEmpty(t, created[0].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRuleRepositoryListBreachesFilters(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewAlertRuleRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("AND ar.audience = ANY($3)")).
+		WithArgs("term-1", pq.Array([]string{"class-a"}), pq.Array([]string{"TEACHERS"}), "teacher-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rule_id", "rule_name", "metric", "comparator", "audience", "term_id", "class_id",
+			"value", "threshold", "detected_at", "updated_at"}).
+			AddRow("breach-1", "rule-1", "Low attendance", "CLASS_ATTENDANCE_RATE", "LT", "TEACHERS", "term-1", "class-a", 82.0, 90.0, time.Now(), time.Now()))
+
+	breaches, err := repo.ListBreaches(context.Background(), models.AlertRuleBreachFilter{
+		TermID: "term-1", ClassIDs: []string{"class-a"}, TeacherID: "teacher-1", Audiences: []models.AlertAudience{models.AlertAudienceTeachers},
+	})
+	require.NoError(t, err)
+	require.Len(t, breaches, 1)
+	assert.Equal(t, "Low attendance", breaches[0].RuleName)
+	assert.Equal(t, models.AlertMetricClassAttendanceRate, breaches[0].Metric)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAlertRuleRepositoryDeleteRuleMissing(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewAlertRuleRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM alert_rules WHERE id = $1")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteRule(context.Background(), "missing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
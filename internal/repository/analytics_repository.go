@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
 )
@@ -27,7 +28,7 @@ func NewAnalyticsRepository(db *sqlx.DB) *AnalyticsRepository {
 func (r *AnalyticsRepository) AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error) {
 	if filter.DateFrom == nil && filter.DateTo == nil {
 		var builder strings.Builder
-		builder.WriteString("SELECT term_id, class_id, present_count, absent_count, percentage, updated_at FROM attendance_summary_mv WHERE 1=1")
+		builder.WriteString("SELECT term_id, class_id, present_count, sick_count, excused_count, absent_count, percentage, updated_at FROM attendance_summary_mv WHERE 1=1")
 		var args []interface{}
 		if filter.TermID != "" {
 			args = append(args, filter.TermID)
@@ -37,6 +38,10 @@ func (r *AnalyticsRepository) AttendanceSummary(ctx context.Context, filter mode
 			args = append(args, filter.ClassID)
 			builder.WriteString(fmt.Sprintf(" AND class_id = $%d", len(args)))
 		}
+		if len(filter.ClassIDs) > 0 {
+			args = append(args, pq.Array(filter.ClassIDs))
+			builder.WriteString(fmt.Sprintf(" AND class_id = ANY($%d)", len(args)))
+		}
 		builder.WriteString(" ORDER BY percentage DESC")
 
 		var summaries []models.AnalyticsAttendanceSummary
@@ -49,10 +54,12 @@ func (r *AnalyticsRepository) AttendanceSummary(ctx context.Context, filter mode
 	var builder strings.Builder
 	builder.WriteString(`SELECT e.term_id, e.class_id,
         SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END) AS present_count,
+        SUM(CASE WHEN da.status = 'S' THEN 1 ELSE 0 END) AS sick_count,
+        SUM(CASE WHEN da.status = 'I' THEN 1 ELSE 0 END) AS excused_count,
         SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END) AS absent_count,
         CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END)::DECIMAL / COUNT(*)) * 100 END AS percentage,
         MAX(da.updated_at) AS updated_at
-        FROM daily_attendances da
+        FROM daily_attendance da
         JOIN enrollments e ON e.id = da.enrollment_id
         WHERE 1=1`)
 	var args []interface{}
@@ -64,6 +71,10 @@ func (r *AnalyticsRepository) AttendanceSummary(ctx context.Context, filter mode
 		args = append(args, filter.ClassID)
 		builder.WriteString(fmt.Sprintf(" AND e.class_id = $%d", len(args)))
 	}
+	if len(filter.ClassIDs) > 0 {
+		args = append(args, pq.Array(filter.ClassIDs))
+		builder.WriteString(fmt.Sprintf(" AND e.class_id = ANY($%d)", len(args)))
+	}
 	if filter.DateFrom != nil {
 		args = append(args, *filter.DateFrom)
 		builder.WriteString(fmt.Sprintf(" AND da.date >= $%d", len(args)))
@@ -94,6 +105,10 @@ func (r *AnalyticsRepository) GradeSummary(ctx context.Context, filter models.An
 		args = append(args, filter.ClassID)
 		builder.WriteString(fmt.Sprintf(" AND class_id = $%d", len(args)))
 	}
+	if len(filter.ClassIDs) > 0 {
+		args = append(args, pq.Array(filter.ClassIDs))
+		builder.WriteString(fmt.Sprintf(" AND class_id = ANY($%d)", len(args)))
+	}
 	if filter.SubjectID != "" {
 		args = append(args, filter.SubjectID)
 		builder.WriteString(fmt.Sprintf(" AND subject_id = $%d", len(args)))
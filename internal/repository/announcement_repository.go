@@ -34,7 +34,7 @@ func (r *AnnouncementRepository) List(ctx context.Context, filter models.Announc
 		switch role {
 		case models.RoleTeacher:
 			allowedAudiences[string(models.AnnouncementAudienceGuru)] = struct{}{}
-		case models.RoleStudent:
+		case models.RoleStudent, models.RoleGuardian:
 			allowedAudiences[string(models.AnnouncementAudienceSiswa)] = struct{}{}
 		case models.RoleAdmin, models.RoleSuperAdmin:
 			allowedAudiences[string(models.AnnouncementAudienceGuru)] = struct{}{}
@@ -68,7 +68,7 @@ func (r *AnnouncementRepository) List(ctx context.Context, filter models.Announc
 	}
 	offset := (page - 1) * size
 
-	query := fmt.Sprintf(`SELECT id, title, content, audience, target_class_id, priority, is_pinned, published_at, expires_at, created_by, created_at, updated_at
+	query := fmt.Sprintf(`SELECT id, title, content, audience, target_class_id, priority, is_pinned, requires_ack, published_at, expires_at, created_by, created_at, updated_at
 %s WHERE %s
 ORDER BY is_pinned DESC, priority DESC, published_at DESC
 LIMIT %d OFFSET %d`, base, whereClause, size, offset)
@@ -86,7 +86,7 @@ LIMIT %d OFFSET %d`, base, whereClause, size, offset)
 
 // GetByID returns an announcement by identifier.
 func (r *AnnouncementRepository) GetByID(ctx context.Context, id string) (*models.Announcement, error) {
-	const query = `SELECT id, title, content, audience, target_class_id, priority, is_pinned, published_at, expires_at, created_by, created_at, updated_at
+	const query = `SELECT id, title, content, audience, target_class_id, priority, is_pinned, requires_ack, published_at, expires_at, created_by, created_at, updated_at
 FROM announcements WHERE id = $1`
 	var announcement models.Announcement
 	if err := r.db.GetContext(ctx, &announcement, query, id); err != nil {
@@ -105,8 +105,8 @@ func (r *AnnouncementRepository) Create(ctx context.Context, announcement *model
 		announcement.CreatedAt = now
 	}
 	announcement.UpdatedAt = now
-	query := `INSERT INTO announcements (id, title, content, audience, target_class_id, priority, is_pinned, published_at, expires_at, created_by, created_at, updated_at)
-VALUES (:id, :title, :content, :audience, :target_class_id, :priority, :is_pinned, :published_at, :expires_at, :created_by, :created_at, :updated_at)`
+	query := `INSERT INTO announcements (id, title, content, audience, target_class_id, priority, is_pinned, requires_ack, published_at, expires_at, created_by, created_at, updated_at)
+VALUES (:id, :title, :content, :audience, :target_class_id, :priority, :is_pinned, :requires_ack, :published_at, :expires_at, :created_by, :created_at, :updated_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, announcement); err != nil {
 		return fmt.Errorf("create announcement: %w", err)
 	}
@@ -117,7 +117,7 @@ VALUES (:id, :title, :content, :audience, :target_class_id, :priority, :is_pinne
 func (r *AnnouncementRepository) Update(ctx context.Context, announcement *models.Announcement) error {
 	announcement.UpdatedAt = time.Now().UTC()
 	query := `UPDATE announcements SET title = :title, content = :content, audience = :audience, target_class_id = :target_class_id,
-priority = :priority, is_pinned = :is_pinned, published_at = :published_at, expires_at = :expires_at, updated_at = :updated_at
+priority = :priority, is_pinned = :is_pinned, requires_ack = :requires_ack, published_at = :published_at, expires_at = :expires_at, updated_at = :updated_at
 WHERE id = :id`
 	if _, err := r.db.NamedExecContext(ctx, query, announcement); err != nil {
 		return fmt.Errorf("update announcement: %w", err)
@@ -133,6 +133,78 @@ func (r *AnnouncementRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Acknowledge records that a recipient of the announcement read it and marks their announcement
+// notification read. Repeated acknowledgements keep the first timestamp, which is returned. Users the
+// announcement was not sent to get sql.ErrNoRows.
+func (r *AnnouncementRepository) Acknowledge(ctx context.Context, announcementID, userID string, at time.Time) (acknowledgedAt time.Time, err error) {
+	dedupeKey := models.AnnouncementDedupeKey(announcementID)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("begin announcement acknowledgement: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	const insert = `INSERT INTO announcement_acks (announcement_id, user_id, acknowledged_at)
+SELECT $1, $2, $3 WHERE EXISTS (SELECT 1 FROM notifications WHERE user_id = $2 AND dedupe_key = $4)
+ON CONFLICT (announcement_id, user_id) DO UPDATE SET acknowledged_at = announcement_acks.acknowledged_at
+RETURNING acknowledged_at`
+	if err = tx.GetContext(ctx, &acknowledgedAt, insert, announcementID, userID, at, dedupeKey); err != nil {
+		return time.Time{}, err
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE user_id = $1 AND dedupe_key = $2`, userID, dedupeKey, at); err != nil {
+		return time.Time{}, fmt.Errorf("mark announcement notification read: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return time.Time{}, fmt.Errorf("commit announcement acknowledgement: %w", err)
+	}
+	return acknowledgedAt, nil
+}
+
+// ListRecipients returns the users the announcement was sent to with their acknowledgements, ordered
+// by name. An empty role lists every recipient.
+func (r *AnnouncementRepository) ListRecipients(ctx context.Context, announcementID string, role models.UserRole) ([]models.AnnouncementRecipient, error) {
+	query := `SELECT u.id AS user_id, u.full_name, u.role, a.acknowledged_at
+FROM notifications n
+JOIN users u ON u.id = n.user_id
+LEFT JOIN announcement_acks a ON a.announcement_id = $1 AND a.user_id = n.user_id
+WHERE n.dedupe_key = $2`
+	args := []interface{}{announcementID, models.AnnouncementDedupeKey(announcementID)}
+	if role != "" {
+		query += " AND u.role = $3"
+		args = append(args, role)
+	}
+	query += " ORDER BY u.full_name ASC, u.id ASC"
+	var recipients []models.AnnouncementRecipient
+	if err := r.db.SelectContext(ctx, &recipients, query, args...); err != nil {
+		return nil, fmt.Errorf("list announcement recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// ListUnacknowledged returns the active announcements requiring acknowledgement that some recipients
+// have not acknowledged yet, newest first.
+func (r *AnnouncementRepository) ListUnacknowledged(ctx context.Context, limit int) ([]models.AnnouncementAckSummary, error) {
+	const query = `SELECT an.id, an.title, an.priority, an.published_at,
+       COUNT(n.user_id) AS recipients, COUNT(a.user_id) AS acknowledged
+FROM announcements an
+JOIN notifications n ON n.dedupe_key = 'announcement:' || an.id
+LEFT JOIN announcement_acks a ON a.announcement_id = an.id AND a.user_id = n.user_id
+WHERE an.requires_ack AND an.published_at <= NOW() AND (an.expires_at IS NULL OR an.expires_at > NOW())
+GROUP BY an.id, an.title, an.priority, an.published_at
+HAVING COUNT(a.user_id) < COUNT(n.user_id)
+ORDER BY an.published_at DESC
+LIMIT $1`
+	var summaries []models.AnnouncementAckSummary
+	if err := r.db.SelectContext(ctx, &summaries, query, limit); err != nil {
+		return nil, fmt.Errorf("list unacknowledged announcements: %w", err)
+	}
+	return summaries, nil
+}
+
 // pqStringArray helper ensures we pass string arrays consistently.
 func pqStringArray(values []string) interface{} {
 	return pq.Array(values)
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestAnnouncementRepositoryAcknowledge(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAnnouncementRepository(sqlx.NewDb(db, "sqlmock"))
+
+	first := time.Date(2026, 5, 4, 7, 0, 0, 0, time.UTC)
+	now := first.Add(time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO announcement_acks .*WHERE EXISTS \(SELECT 1 FROM notifications WHERE user_id = \$2 AND dedupe_key = \$4\)`).
+		WithArgs("ann-1", "teacher-1", now, "announcement:ann-1").
+		WillReturnRows(sqlmock.NewRows([]string{"acknowledged_at"}).AddRow(first))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE user_id = $1 AND dedupe_key = $2")).
+		WithArgs("teacher-1", "announcement:ann-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	acknowledgedAt, err := repo.Acknowledge(context.Background(), "ann-1", "teacher-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, first, acknowledgedAt)
+
+	// Users without the announcement notification are not recipients.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO announcement_acks`).
+		WithArgs("ann-1", "student-1", now, "announcement:ann-1").
+		WillReturnRows(sqlmock.NewRows([]string{"acknowledged_at"}))
+	mock.ExpectRollback()
+
+	_, err = repo.Acknowledge(context.Background(), "ann-1", "student-1", now)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnnouncementRepositoryAcknowledgementReports(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAnnouncementRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`FROM notifications n\s+JOIN users u .*LEFT JOIN announcement_acks a .*WHERE n.dedupe_key = \$2 AND u.role = \$3 ORDER BY u.full_name`).
+		WithArgs("ann-1", "announcement:ann-1", models.RoleTeacher).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "full_name", "role", "acknowledged_at"}).
+			AddRow("teacher-1", "Ani", "TEACHER", now).
+			AddRow("teacher-2", "Budi", "TEACHER", nil))
+
+	recipients, err := repo.ListRecipients(context.Background(), "ann-1", models.RoleTeacher)
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+	require.NotNil(t, recipients[0].AcknowledgedAt)
+	assert.Nil(t, recipients[1].AcknowledgedAt)
+
+	mock.ExpectQuery(`WHERE an.requires_ack .*HAVING COUNT\(a.user_id\) < COUNT\(n.user_id\)\s+ORDER BY an.published_at DESC\s+LIMIT \$1`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "priority", "published_at", "recipients", "acknowledged"}).
+			AddRow("ann-1", "Rapat", "HIGH", now, 12, 9))
+
+	summaries, err := repo.ListUnacknowledged(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 12, summaries[0].Recipients)
+	assert.Equal(t, 9, summaries[0].Acknowledged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/models"
 )
 
+const archiveColumns = `id, title, category, scope, ref_term_id, ref_class_id, ref_student_id,
+       file_path, mime_type, size_bytes, uploaded_by, uploaded_at, deleted_at, deletion_reason, restored_at,
+       thumbnail_path, thumbnail_error, thumbnail_generated_at`
+
 // ArchiveRepository handles archive metadata persistence.
 type ArchiveRepository struct {
 	db *sqlx.DB
@@ -42,9 +46,7 @@ func (r *ArchiveRepository) Create(ctx context.Context, item *models.ArchiveItem
 
 // GetByID retrieves one archive row.
 func (r *ArchiveRepository) GetByID(ctx context.Context, id string) (*models.ArchiveItem, error) {
-	const query = `SELECT id, title, category, scope, ref_term_id, ref_class_id, ref_student_id,
-       file_path, mime_type, size_bytes, uploaded_by, uploaded_at, deleted_at
-	FROM archives WHERE id = $1`
+	query := `SELECT ` + archiveColumns + ` FROM archives WHERE id = $1`
 	var item models.ArchiveItem
 	if err := r.db.GetContext(ctx, &item, query, id); err != nil {
 		return nil, err
@@ -55,12 +57,14 @@ func (r *ArchiveRepository) GetByID(ctx context.Context, id string) (*models.Arc
 // List returns archives applying filters and excluding deleted rows by default.
 func (r *ArchiveRepository) List(ctx context.Context, filter models.ArchiveFilter) ([]models.ArchiveItem, error) {
 	builder := strings.Builder{}
-	builder.WriteString(`SELECT id, title, category, scope, ref_term_id, ref_class_id, ref_student_id,
-       file_path, mime_type, size_bytes, uploaded_by, uploaded_at, deleted_at FROM archives`)
+	builder.WriteString(`SELECT ` + archiveColumns + ` FROM archives`)
 	args := make([]interface{}, 0, 5)
 	conditions := make([]string, 0, 5)
 
-	if !filter.IncludeDeleted {
+	switch {
+	case filter.OnlyDeleted:
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	case !filter.IncludeDeleted:
 		conditions = append(conditions, "deleted_at IS NULL")
 	}
 	if filter.Scope != "" {
@@ -79,12 +83,22 @@ func (r *ArchiveRepository) List(ctx context.Context, filter models.ArchiveFilte
 		args = append(args, filter.ClassID)
 		conditions = append(conditions, fmt.Sprintf("ref_class_id = $%d", len(args)))
 	}
+	if filter.Search != "" {
+		args = append(args, filter.Search, likePattern(filter.Search))
+		conditions = append(conditions, fmt.Sprintf("(to_tsvector('simple', title) @@ plainto_tsquery('simple', $%d) OR title ILIKE $%d ESCAPE '\\')", len(args)-1, len(args)))
+	}
 
 	if len(conditions) > 0 {
 		builder.WriteString(" WHERE ")
 		builder.WriteString(strings.Join(conditions, " AND "))
 	}
-	builder.WriteString(" ORDER BY uploaded_at DESC")
+	if filter.Search != "" {
+		builder.WriteString(fmt.Sprintf(" ORDER BY ts_rank(to_tsvector('simple', title), plainto_tsquery('simple', $%d)) DESC, uploaded_at DESC", len(args)-1))
+	} else if filter.OnlyDeleted {
+		builder.WriteString(" ORDER BY deleted_at DESC")
+	} else {
+		builder.WriteString(" ORDER BY uploaded_at DESC")
+	}
 
 	limit := filter.Limit
 	if limit <= 0 || limit > 200 {
@@ -103,10 +117,10 @@ func (r *ArchiveRepository) List(ctx context.Context, filter models.ArchiveFilte
 	return records, nil
 }
 
-// SoftDelete marks an archive as deleted.
-func (r *ArchiveRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
-	const query = `UPDATE archives SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.ExecContext(ctx, query, id, deletedAt)
+// SoftDelete marks an archive as deleted for reason (models.ArchiveDeletion*).
+func (r *ArchiveRepository) SoftDelete(ctx context.Context, id, reason string, deletedAt time.Time) error {
+	const query = `UPDATE archives SET deleted_at = $2, deletion_reason = $3 WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, deletedAt, reason)
 	if err != nil {
 		return fmt.Errorf("soft delete archive: %w", err)
 	}
@@ -119,3 +133,133 @@ func (r *ArchiveRepository) SoftDelete(ctx context.Context, id string, deletedAt
 	}
 	return nil
 }
+
+// ListUploadedBefore returns live archives of category, matched case-insensitively, whose retention
+// period started before the cutoff, oldest first. The period restarts when an archive is restored.
+func (r *ArchiveRepository) ListUploadedBefore(ctx context.Context, category string, before time.Time, limit int) ([]models.ArchiveItem, error) {
+	query := `SELECT ` + archiveColumns + ` FROM archives
+	WHERE deleted_at IS NULL AND LOWER(category) = LOWER($1) AND COALESCE(restored_at, uploaded_at) < $2
+	ORDER BY COALESCE(restored_at, uploaded_at) ASC LIMIT $3`
+	var records []models.ArchiveItem
+	if err := r.db.SelectContext(ctx, &records, query, category, before, limit); err != nil {
+		return nil, fmt.Errorf("list archives uploaded before: %w", err)
+	}
+	return records, nil
+}
+
+// ListDeletedBefore returns archives soft-deleted before the cutoff, oldest first.
+func (r *ArchiveRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.ArchiveItem, error) {
+	query := `SELECT ` + archiveColumns + ` FROM archives
+	WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	ORDER BY deleted_at ASC LIMIT $2`
+	var records []models.ArchiveItem
+	if err := r.db.SelectContext(ctx, &records, query, before, limit); err != nil {
+		return nil, fmt.Errorf("list deleted archives: %w", err)
+	}
+	return records, nil
+}
+
+// ListPendingThumbnails returns live archives of mimeType without a thumbnail attempt, oldest first.
+func (r *ArchiveRepository) ListPendingThumbnails(ctx context.Context, mimeType string, limit int) ([]models.ArchiveItem, error) {
+	query := `SELECT ` + archiveColumns + ` FROM archives
+	WHERE thumbnail_generated_at IS NULL AND deleted_at IS NULL AND mime_type = $1
+	ORDER BY uploaded_at ASC LIMIT $2`
+	var records []models.ArchiveItem
+	if err := r.db.SelectContext(ctx, &records, query, mimeType, limit); err != nil {
+		return nil, fmt.Errorf("list archives pending thumbnails: %w", err)
+	}
+	return records, nil
+}
+
+// SetThumbnail records a finished thumbnail attempt: the image path on success, the error otherwise.
+func (r *ArchiveRepository) SetThumbnail(ctx context.Context, id string, path, failure *string, generatedAt time.Time) error {
+	const query = `UPDATE archives SET thumbnail_path = $2, thumbnail_error = $3, thumbnail_generated_at = $4 WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, path, failure, generatedAt)
+	if err != nil {
+		return fmt.Errorf("set archive thumbnail: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check archive thumbnail rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Restore brings a soft-deleted archive back and restarts its retention period.
+func (r *ArchiveRepository) Restore(ctx context.Context, id string, restoredAt time.Time) error {
+	const query = `UPDATE archives SET deleted_at = NULL, deletion_reason = NULL, restored_at = $2
+	WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := r.db.ExecContext(ctx, query, id, restoredAt)
+	if err != nil {
+		return fmt.Errorf("restore archive: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check archive restore rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Purge removes the row of a soft-deleted archive for good.
+func (r *ArchiveRepository) Purge(ctx context.Context, id string) error {
+	const query = `DELETE FROM archives WHERE id = $1 AND deleted_at IS NOT NULL`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("purge archive: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check archive purge rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Usage returns the bytes taken by live archives of category, matched case-insensitively, and by
+// live archives uploaded by uploaderID.
+func (r *ArchiveRepository) Usage(ctx context.Context, category, uploaderID string) (int64, int64, error) {
+	const query = `SELECT
+	COALESCE(SUM(size_bytes) FILTER (WHERE LOWER(category) = LOWER($1)), 0) AS category_bytes,
+	COALESCE(SUM(size_bytes) FILTER (WHERE uploaded_by = $2), 0) AS uploader_bytes
+	FROM archives WHERE deleted_at IS NULL AND (LOWER(category) = LOWER($1) OR uploaded_by = $2)`
+	var usage struct {
+		CategoryBytes int64 `db:"category_bytes"`
+		UploaderBytes int64 `db:"uploader_bytes"`
+	}
+	if err := r.db.GetContext(ctx, &usage, query, category, uploaderID); err != nil {
+		return 0, 0, fmt.Errorf("get archive usage: %w", err)
+	}
+	return usage.CategoryBytes, usage.UploaderBytes, nil
+}
+
+// UsageByCategory returns the storage taken by live archives per lower-cased category, largest first.
+func (r *ArchiveRepository) UsageByCategory(ctx context.Context) ([]models.ArchiveUsage, error) {
+	const query = `SELECT LOWER(category) AS key, COUNT(*) AS files, COALESCE(SUM(size_bytes), 0) AS bytes
+	FROM archives WHERE deleted_at IS NULL
+	GROUP BY LOWER(category) ORDER BY bytes DESC, key ASC`
+	var usage []models.ArchiveUsage
+	if err := r.db.SelectContext(ctx, &usage, query); err != nil {
+		return nil, fmt.Errorf("list archive usage by category: %w", err)
+	}
+	return usage, nil
+}
+
+// UsageByUploader returns the storage taken by live archives per uploader, largest first.
+func (r *ArchiveRepository) UsageByUploader(ctx context.Context, limit int) ([]models.ArchiveUsage, error) {
+	const query = `SELECT uploaded_by AS key, COUNT(*) AS files, COALESCE(SUM(size_bytes), 0) AS bytes
+	FROM archives WHERE deleted_at IS NULL
+	GROUP BY uploaded_by ORDER BY bytes DESC, key ASC LIMIT $1`
+	var usage []models.ArchiveUsage
+	if err := r.db.SelectContext(ctx, &usage, query, limit); err != nil {
+		return nil, fmt.Errorf("list archive usage by uploader: %w", err)
+	}
+	return usage, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -80,13 +81,120 @@ func TestArchiveRepositorySoftDelete(t *testing.T) {
 	repo := NewArchiveRepository(db)
 	now := time.Now()
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET deleted_at = $2")).
-		WithArgs("arch-1", sqlmock.AnyArg()).
+		WithArgs("arch-1", sqlmock.AnyArg(), models.ArchiveDeletionManual).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	require.NoError(t, repo.SoftDelete(context.Background(), "arch-1", now))
+	require.NoError(t, repo.SoftDelete(context.Background(), "arch-1", models.ArchiveDeletionManual, now))
 
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET deleted_at = $2")).
-		WithArgs("arch-2", sqlmock.AnyArg()).
+		WithArgs("arch-2", sqlmock.AnyArg(), models.ArchiveDeletionManual).
 		WillReturnResult(sqlmock.NewResult(0, 0))
-	require.Error(t, repo.SoftDelete(context.Background(), "arch-2", now))
+	require.Error(t, repo.SoftDelete(context.Background(), "arch-2", models.ArchiveDeletionManual, now))
+}
+
+func TestArchiveRepositoryRetentionQueries(t *testing.T) {
+	db, mock, cleanup := newArchiveRepoMock(t)
+	defer cleanup()
+
+	repo := NewArchiveRepository(db)
+	cutoff := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "category", "uploaded_at"}).AddRow("arch-1", "Class Materials", cutoff.AddDate(0, -1, 0))
+	mock.ExpectQuery(`WHERE deleted_at IS NULL AND LOWER\(category\) = LOWER\(\$1\) AND COALESCE\(restored_at, uploaded_at\) < \$2\s+ORDER BY COALESCE\(restored_at, uploaded_at\) ASC LIMIT \$3`).
+		WithArgs("class materials", cutoff, 100).
+		WillReturnRows(rows)
+	items, err := repo.ListUploadedBefore(context.Background(), "class materials", cutoff, 100)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "Class Materials", items[0].Category)
+
+	mock.ExpectQuery(`WHERE deleted_at IS NOT NULL AND deleted_at < \$1\s+ORDER BY deleted_at ASC LIMIT \$2`).
+		WithArgs(cutoff, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	items, err = repo.ListDeletedBefore(context.Background(), cutoff, 50)
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM archives WHERE id = $1 AND deleted_at IS NOT NULL")).
+		WithArgs("arch-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Purge(context.Background(), "arch-1"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveRepositoryTrashAndRestore(t *testing.T) {
+	db, mock, cleanup := newArchiveRepoMock(t)
+	defer cleanup()
+
+	repo := NewArchiveRepository(db)
+	mock.ExpectQuery(`FROM archives WHERE deleted_at IS NOT NULL AND category = \$1 ORDER BY deleted_at DESC LIMIT 50 OFFSET 0`).
+		WithArgs("ADMIN").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("arch-1"))
+	items, err := repo.List(context.Background(), models.ArchiveFilter{OnlyDeleted: true, Category: "ADMIN"})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET deleted_at = NULL, deletion_reason = NULL, restored_at = $2")).
+		WithArgs("arch-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Restore(context.Background(), "arch-1", now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET deleted_at = NULL")).
+		WithArgs("arch-2", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.ErrorIs(t, repo.Restore(context.Background(), "arch-2", now), sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveRepositoryUsage(t *testing.T) {
+	db, mock, cleanup := newArchiveRepoMock(t)
+	defer cleanup()
+
+	repo := NewArchiveRepository(db)
+	mock.ExpectQuery(`FROM archives WHERE deleted_at IS NULL AND \(LOWER\(category\) = LOWER\(\$1\) OR uploaded_by = \$2\)`).
+		WithArgs("ADMIN", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"category_bytes", "uploader_bytes"}).AddRow(4096, 1024))
+	categoryBytes, uploaderBytes, err := repo.Usage(context.Background(), "ADMIN", "user-1")
+	require.NoError(t, err)
+	require.EqualValues(t, 4096, categoryBytes)
+	require.EqualValues(t, 1024, uploaderBytes)
+
+	mock.ExpectQuery(`GROUP BY LOWER\(category\) ORDER BY bytes DESC`).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "files", "bytes"}).AddRow("admin", 2, 4096))
+	byCategory, err := repo.UsageByCategory(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []models.ArchiveUsage{{Key: "admin", Files: 2, Bytes: 4096}}, byCategory)
+
+	mock.ExpectQuery(`GROUP BY uploaded_by ORDER BY bytes DESC, key ASC LIMIT \$1`).
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "files", "bytes"}).AddRow("user-1", 1, 1024))
+	byUploader, err := repo.UsageByUploader(context.Background(), 20)
+	require.NoError(t, err)
+	require.Len(t, byUploader, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveRepositoryThumbnails(t *testing.T) {
+	db, mock, cleanup := newArchiveRepoMock(t)
+	defer cleanup()
+
+	repo := NewArchiveRepository(db)
+	mock.ExpectQuery(`WHERE thumbnail_generated_at IS NULL AND deleted_at IS NULL AND mime_type = \$1\s+ORDER BY uploaded_at ASC LIMIT \$2`).
+		WithArgs("application/pdf", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_path"}).AddRow("arch-1", "archive_ops.pdf"))
+	items, err := repo.ListPendingThumbnails(context.Background(), "application/pdf", 20)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+
+	now := time.Now()
+	path := "archive_ops.thumb.png"
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET thumbnail_path = $2, thumbnail_error = $3, thumbnail_generated_at = $4 WHERE id = $1")).
+		WithArgs("arch-1", &path, nil, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SetThumbnail(context.Background(), "arch-1", &path, nil, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE archives SET thumbnail_path")).
+		WithArgs("arch-2", nil, sqlmock.AnyArg(), now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	failure := "broken"
+	require.ErrorIs(t, repo.SetThumbnail(context.Background(), "arch-2", nil, &failure, now), sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// AttendanceAlertRepository persists attendance alert thresholds and the alerts raised by the
+// nightly evaluation.
+type AttendanceAlertRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendanceAlertRepository constructs the repository.
+func NewAttendanceAlertRepository(db *sqlx.DB) *AttendanceAlertRepository {
+	return &AttendanceAlertRepository{db: db}
+}
+
+// ListThresholds returns the class overrides configured for a term.
+func (r *AttendanceAlertRepository) ListThresholds(ctx context.Context, termID string) ([]models.AttendanceAlertThreshold, error) {
+	const query = `SELECT id, class_id, term_id, min_percentage, updated_by, created_at, updated_at
+FROM attendance_alert_thresholds WHERE term_id = $1 ORDER BY class_id ASC`
+	var thresholds []models.AttendanceAlertThreshold
+	if err := r.db.SelectContext(ctx, &thresholds, query, termID); err != nil {
+		return nil, fmt.Errorf("list attendance alert thresholds: %w", err)
+	}
+	return thresholds, nil
+}
+
+// UpsertThreshold creates or replaces the override for the threshold's class and term.
+func (r *AttendanceAlertRepository) UpsertThreshold(ctx context.Context, threshold *models.AttendanceAlertThreshold) error {
+	if threshold.ID == "" {
+		threshold.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if threshold.CreatedAt.IsZero() {
+		threshold.CreatedAt = now
+	}
+	threshold.UpdatedAt = now
+	const query = `INSERT INTO attendance_alert_thresholds (id, class_id, term_id, min_percentage, updated_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (class_id, term_id) DO UPDATE SET min_percentage = EXCLUDED.min_percentage,
+    updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+RETURNING id, created_at`
+	row := r.db.QueryRowxContext(ctx, query, threshold.ID, threshold.ClassID, threshold.TermID, threshold.MinPercentage,
+		threshold.UpdatedBy, threshold.CreatedAt, threshold.UpdatedAt)
+	if err := row.Scan(&threshold.ID, &threshold.CreatedAt); err != nil {
+		return fmt.Errorf("upsert attendance alert threshold: %w", err)
+	}
+	return nil
+}
+
+// DeleteThreshold removes a class override so the default applies again.
+func (r *AttendanceAlertRepository) DeleteThreshold(ctx context.Context, classID, termID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM attendance_alert_thresholds WHERE class_id = $1 AND term_id = $2`, classID, termID)
+	if err != nil {
+		return fmt.Errorf("delete attendance alert threshold: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check attendance alert threshold rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// StudentRates returns the attendance of every active enrollment of a term that has at least one
+// daily mark, computed with formula: total_days leaves out the excused marks it discounts.
+func (r *AttendanceAlertRepository) StudentRates(ctx context.Context, termID string, formula models.AttendanceRateFormula) ([]models.StudentAttendanceRate, error) {
+	query := `SELECT e.id AS enrollment_id, e.student_id, e.class_id,
+    SUM(CASE WHEN da.status = ANY($2) THEN 1 ELSE 0 END) AS present_days,
+    COUNT(*) - SUM(CASE WHEN da.status = ANY($4) THEN 1 ELSE 0 END) AS total_days,
+    COALESCE(SUM(CASE WHEN da.status = ANY($2) THEN 1 ELSE 0 END)::DECIMAL
+        / NULLIF(COUNT(*) - SUM(CASE WHEN da.status = ANY($4) THEN 1 ELSE 0 END), 0) * 100, 0) AS percentage
+FROM daily_attendance da
+JOIN enrollments e ON e.id = da.enrollment_id
+WHERE e.term_id = $1 AND e.status = $3 AND ` + attendanceTermWindow("da.date", "$1") + `
+GROUP BY e.id, e.student_id, e.class_id
+ORDER BY e.class_id ASC, e.student_id ASC`
+	present := pq.Array(attendanceStatusCodes(formula.PresentStatuses()))
+	discounted := pq.Array(attendanceStatusCodes(formula.DiscountedStatuses()))
+	var rates []models.StudentAttendanceRate
+	if err := r.db.SelectContext(ctx, &rates, query, termID, present, models.EnrollmentStatusActive, discounted); err != nil {
+		return nil, fmt.Errorf("list student attendance rates: %w", err)
+	}
+	return rates, nil
+}
+
+// ReplaceAlerts makes alerts the complete set of open alerts for a term: alerts of students who
+// recovered are cleared and the rest are inserted or refreshed. It returns the alerts that were not
+// open before.
+func (r *AttendanceAlertRepository) ReplaceAlerts(ctx context.Context, termID string, alerts []models.AttendanceAlert, now time.Time) (created []models.AttendanceAlert, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin replace attendance alerts: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var open []string
+	if err = tx.SelectContext(ctx, &open, `SELECT enrollment_id FROM attendance_alerts WHERE term_id = $1`, termID); err != nil {
+		return nil, fmt.Errorf("list open attendance alerts: %w", err)
+	}
+	existing := make(map[string]struct{}, len(open))
+	for _, enrollmentID := range open {
+		existing[enrollmentID] = struct{}{}
+	}
+
+	keep := make([]string, len(alerts))
+	for i, alert := range alerts {
+		keep[i] = alert.EnrollmentID
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM attendance_alerts WHERE term_id = $1 AND NOT (enrollment_id = ANY($2))`, termID, pq.Array(keep)); err != nil {
+		return nil, fmt.Errorf("clear resolved attendance alerts: %w", err)
+	}
+
+	const upsert = `INSERT INTO attendance_alerts (id, enrollment_id, student_id, class_id, term_id, percentage, threshold,
+    present_days, total_days, detected_at, updated_at)
+VALUES (:id, :enrollment_id, :student_id, :class_id, :term_id, :percentage, :threshold,
+    :present_days, :total_days, :detected_at, :updated_at)
+ON CONFLICT (enrollment_id) DO UPDATE SET percentage = EXCLUDED.percentage, threshold = EXCLUDED.threshold,
+    present_days = EXCLUDED.present_days, total_days = EXCLUDED.total_days, updated_at = EXCLUDED.updated_at`
+	for _, alert := range alerts {
+		payload := alert
+		payload.TermID = termID
+		if payload.ID == "" {
+			payload.ID = uuid.NewString()
+		}
+		payload.DetectedAt = now
+		payload.UpdatedAt = now
+		if _, err = tx.NamedExecContext(ctx, upsert, &payload); err != nil {
+			return nil, fmt.Errorf("upsert attendance alert: %w", err)
+		}
+		if _, ok := existing[alert.EnrollmentID]; !ok {
+			created = append(created, payload)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit replace attendance alerts: %w", err)
+	}
+	return created, nil
+}
+
+// ListAlerts returns open alerts with the student's name, lowest attendance first.
+func (r *AttendanceAlertRepository) ListAlerts(ctx context.Context, filter models.AttendanceAlertFilter) ([]models.AttendanceAlert, error) {
+	var builder strings.Builder
+	builder.WriteString(`SELECT a.id, a.enrollment_id, a.student_id, s.full_name AS student_name, a.class_id, a.term_id,
+    a.percentage, a.threshold, a.present_days, a.total_days, a.detected_at, a.updated_at
+FROM attendance_alerts a
+JOIN students s ON s.id = a.student_id
+WHERE 1=1`)
+	var args []interface{}
+	if filter.TermID != "" {
+		args = append(args, filter.TermID)
+		fmt.Fprintf(&builder, " AND a.term_id = $%d", len(args))
+	}
+	if len(filter.ClassIDs) > 0 {
+		args = append(args, pq.Array(filter.ClassIDs))
+		fmt.Fprintf(&builder, " AND a.class_id = ANY($%d)", len(args))
+	}
+	if filter.TeacherID != "" {
+		args = append(args, filter.TeacherID)
+		fmt.Fprintf(&builder, `
+  AND EXISTS (
+    SELECT 1 FROM teacher_assignments ta
+    WHERE ta.class_id = a.class_id AND ta.term_id = a.term_id AND ta.teacher_id = $%d
+  )`, len(args))
+	}
+	builder.WriteString("\nORDER BY a.percentage ASC, s.full_name ASC")
+
+	var alerts []models.AttendanceAlert
+	if err := r.db.SelectContext(ctx, &alerts, builder.String(), args...); err != nil {
+		return nil, fmt.Errorf("list attendance alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// attendanceStatusCodes converts statuses for pq.Array, which only handles plain string slices.
+func attendanceStatusCodes(statuses []models.AttendanceStatus) []string {
+	codes := make([]string, len(statuses))
+	for i, status := range statuses {
+		codes[i] = string(status)
+	}
+	return codes
+}
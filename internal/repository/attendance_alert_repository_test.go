@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestAttendanceAlertRepositoryReplaceAlerts(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewAttendanceAlertRepository(db)
+
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	alerts := []models.AttendanceAlert{
+		{EnrollmentID: "enr-1", StudentID: "student-1", ClassID: "class-a", Percentage: 80, Threshold: 85, PresentDays: 16, TotalDays: 20},
+		{EnrollmentID: "enr-2", StudentID: "student-2", ClassID: "class-a", Percentage: 70, Threshold: 85, PresentDays: 14, TotalDays: 20},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT enrollment_id FROM attendance_alerts WHERE term_id = $1")).
+		WithArgs("term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"enrollment_id"}).AddRow("enr-1").AddRow("enr-9"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM attendance_alerts WHERE term_id = $1 AND NOT (enrollment_id = ANY($2))")).
+		WithArgs("term-1", pq.Array([]string{"enr-1", "enr-2"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (enrollment_id) DO UPDATE")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (enrollment_id) DO UPDATE")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	created, err := repo.ReplaceAlerts(context.Background(), "term-1", alerts, now)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, "enr-2", created[0].EnrollmentID)
+	assert.Equal(t, "term-1", created[0].TermID)
+	assert.Equal(t, now, created[0].DetectedAt)
+	assert.NotEmpty(t, created[0].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendanceAlertRepositoryListAlertsScopesTeacher(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewAttendanceAlertRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("AND a.class_id = ANY($2)")).
+		WithArgs("term-1", pq.Array([]string{"class-a"}), "teacher-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "enrollment_id", "student_id", "student_name", "class_id", "term_id",
+			"percentage", "threshold", "present_days", "total_days", "detected_at", "updated_at"}).
+			AddRow("alert-1", "enr-1", "student-1", "Budi", "class-a", "term-1", 80.0, 85.0, 16, 20, time.Now(), time.Now()))
+
+	alerts, err := repo.ListAlerts(context.Background(), models.AttendanceAlertFilter{TermID: "term-1", ClassIDs: []string{"class-a"}, TeacherID: "teacher-1"})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Budi", alerts[0].StudentName)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
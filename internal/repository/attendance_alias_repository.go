@@ -28,6 +28,8 @@ type AttendanceAliasStudentRow struct {
 	Sick        int     `db:"sick"`
 	Excused     int     `db:"excused"`
 	Absent      int     `db:"absent"`
+	SchoolDuty  int     `db:"school_duty"`
+	Documented  int     `db:"documented"`
 	Total       int     `db:"total"`
 	Rate        float64 `db:"rate"`
 }
@@ -39,9 +41,18 @@ type AttendanceAliasAggregate struct {
 	Sick      int
 	Excused   int
 	Absent    int
-	Students  []AttendanceAliasStudentRow
+	// SchoolDuty counts I days excused for school duty.
+	SchoolDuty int
+	// Documented counts S and I days with live evidence linked.
+	Documented int
+	Students   []AttendanceAliasStudentRow
 }
 
+// attendanceDocumented matches excused daily attendance rows with evidence that is not in the trash.
+const attendanceDocumented = `da.status IN ('S', 'I') AND EXISTS (SELECT 1 FROM attendance_evidence ae
+        JOIN archives a ON a.id = ae.archive_id
+        WHERE ae.enrollment_id = da.enrollment_id AND ae.date = da.date AND a.deleted_at IS NULL)`
+
 // AttendanceAliasRepository exposes read-only aggregate helpers for attendance aliases.
 type AttendanceAliasRepository struct {
 	db *sqlx.DB
@@ -65,16 +76,20 @@ func (r *AttendanceAliasRepository) Aggregate(ctx context.Context, filter Attend
     COALESCE(SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END), 0) AS present,
     COALESCE(SUM(CASE WHEN da.status = 'S' THEN 1 ELSE 0 END), 0) AS sick,
     COALESCE(SUM(CASE WHEN da.status = 'I' THEN 1 ELSE 0 END), 0) AS excused,
-    COALESCE(SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END), 0) AS absent
+    COALESCE(SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END), 0) AS absent,
+    COALESCE(SUM(CASE WHEN da.absence_reason = 'SCHOOL_DUTY' THEN 1 ELSE 0 END), 0) AS school_duty,
+    COALESCE(SUM(CASE WHEN `+attendanceDocumented+` THEN 1 ELSE 0 END), 0) AS documented
 FROM daily_attendance da
 JOIN enrollments e ON e.id = da.enrollment_id
 WHERE %s`, whereClause)
 	totalRow := struct {
-		TotalDays int `db:"total_days"`
-		Present   int `db:"present"`
-		Sick      int `db:"sick"`
-		Excused   int `db:"excused"`
-		Absent    int `db:"absent"`
+		TotalDays  int `db:"total_days"`
+		Present    int `db:"present"`
+		Sick       int `db:"sick"`
+		Excused    int `db:"excused"`
+		Absent     int `db:"absent"`
+		SchoolDuty int `db:"school_duty"`
+		Documented int `db:"documented"`
 	}{}
 	if err := r.db.GetContext(ctx, &totalRow, totalSQL, append([]interface{}{}, args...)...); err != nil {
 		return nil, fmt.Errorf("attendance alias totals: %w", err)
@@ -88,6 +103,8 @@ WHERE %s`, whereClause)
     SUM(CASE WHEN da.status = 'S' THEN 1 ELSE 0 END) AS sick,
     SUM(CASE WHEN da.status = 'I' THEN 1 ELSE 0 END) AS excused,
     SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END) AS absent,
+    SUM(CASE WHEN da.absence_reason = 'SCHOOL_DUTY' THEN 1 ELSE 0 END) AS school_duty,
+    SUM(CASE WHEN `+attendanceDocumented+` THEN 1 ELSE 0 END) AS documented,
     COUNT(*) AS total,
     CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END)::DECIMAL / COUNT(*)) * 100 END AS rate
 FROM daily_attendance da
@@ -102,12 +119,14 @@ ORDER BY s.full_name ASC`, whereClause)
 	}
 
 	return &AttendanceAliasAggregate{
-		TotalDays: totalRow.TotalDays,
-		Present:   totalRow.Present,
-		Sick:      totalRow.Sick,
-		Excused:   totalRow.Excused,
-		Absent:    totalRow.Absent,
-		Students:  rows,
+		TotalDays:  totalRow.TotalDays,
+		Present:    totalRow.Present,
+		Sick:       totalRow.Sick,
+		Excused:    totalRow.Excused,
+		Absent:     totalRow.Absent,
+		SchoolDuty: totalRow.SchoolDuty,
+		Documented: totalRow.Documented,
+		Students:   rows,
 	}, nil
 }
 
@@ -117,6 +136,7 @@ func buildAttendanceAliasConditions(filter AttendanceAliasFilter) ([]string, []i
 
 	args = append(args, filter.TermID)
 	conditions = append(conditions, fmt.Sprintf("e.term_id = $%d", len(args)))
+	conditions = append(conditions, attendanceTermWindow("da.date", fmt.Sprintf("$%d", len(args))))
 
 	if filter.ClassID != "" {
 		args = append(args, filter.ClassID)
@@ -147,3 +167,79 @@ func buildAttendanceAliasConditions(filter AttendanceAliasFilter) ([]string, []i
 
 	return conditions, args
 }
+
+// AttendanceAliasRosterFilter scopes roster queries. Without TermID, enrollments are limited to terms
+// overlapping DateFrom..DateTo, which must then both be set.
+type AttendanceAliasRosterFilter struct {
+	TermID    string
+	ClassID   string
+	StudentID string
+	DateFrom  *time.Time
+	DateTo    *time.Time
+}
+
+// AttendanceAliasDayRow is one student's attendance on one day. Date and Status are nil for students
+// without any record in range, so rosters still list them.
+type AttendanceAliasDayRow struct {
+	StudentID     string     `db:"student_id"`
+	StudentName   string     `db:"student_name"`
+	NIS           string     `db:"nis"`
+	ClassID       string     `db:"class_id"`
+	Date          *time.Time `db:"date"`
+	Status        *string    `db:"status"`
+	AbsenceReason *string    `db:"absence_reason"`
+	Notes         *string    `db:"notes"`
+}
+
+// Roster returns daily attendance for every active enrollment in scope, ordered by student and date.
+func (r *AttendanceAliasRepository) Roster(ctx context.Context, filter AttendanceAliasRosterFilter) ([]AttendanceAliasDayRow, error) {
+	if filter.TermID == "" && (filter.DateFrom == nil || filter.DateTo == nil) {
+		return nil, fmt.Errorf("termId or a date range is required")
+	}
+	var joinConditions []string
+	conditions := []string{"e.status = 'ACTIVE'"}
+	args := []interface{}{}
+
+	if filter.DateFrom != nil {
+		args = append(args, *filter.DateFrom)
+		joinConditions = append(joinConditions, fmt.Sprintf("da.date >= $%d", len(args)))
+	}
+	if filter.DateTo != nil {
+		args = append(args, *filter.DateTo)
+		joinConditions = append(joinConditions, fmt.Sprintf("da.date <= $%d", len(args)))
+	}
+	if filter.TermID != "" {
+		args = append(args, filter.TermID)
+		conditions = append(conditions, fmt.Sprintf("e.term_id = $%d", len(args)))
+		joinConditions = append(joinConditions, attendanceTermWindow("da.date", fmt.Sprintf("$%d", len(args))))
+	} else {
+		// Both bounds are set here, so they are $1 and $2.
+		conditions = append(conditions, "t.start_date <= $2", "t.end_date >= $1")
+	}
+	if filter.ClassID != "" {
+		args = append(args, filter.ClassID)
+		conditions = append(conditions, fmt.Sprintf("e.class_id = $%d", len(args)))
+	}
+	if filter.StudentID != "" {
+		args = append(args, filter.StudentID)
+		conditions = append(conditions, fmt.Sprintf("e.student_id = $%d", len(args)))
+	}
+
+	join := "da.enrollment_id = e.id"
+	if len(joinConditions) > 0 {
+		join += " AND " + strings.Join(joinConditions, " AND ")
+	}
+	query := fmt.Sprintf(`SELECT e.student_id, s.full_name AS student_name, s.nis, e.class_id, da.date, da.status, da.absence_reason, da.notes
+FROM enrollments e
+JOIN students s ON s.id = e.student_id
+JOIN terms t ON t.id = e.term_id
+LEFT JOIN daily_attendance da ON %s
+WHERE %s
+ORDER BY s.full_name ASC, e.student_id ASC, da.date ASC`, join, strings.Join(conditions, " AND "))
+
+	var rows []AttendanceAliasDayRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("attendance alias roster: %w", err)
+	}
+	return rows, nil
+}
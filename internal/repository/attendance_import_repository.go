@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// AttendanceImportRepository persists asynchronous attendance import jobs.
+type AttendanceImportRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendanceImportRepository constructs the repository.
+func NewAttendanceImportRepository(db *sqlx.DB) *AttendanceImportRepository {
+	return &AttendanceImportRepository{db: db}
+}
+
+const attendanceImportColumns = `id, payload, total_rows, status, progress, result, created_by, created_at, finished_at, error_message`
+
+// Create inserts a new import job row with generated defaults.
+func (r *AttendanceImportRepository) Create(ctx context.Context, job *models.AttendanceImportJob) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.Status == "" {
+		job.Status = models.ReportStatusQueued
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO attendance_import_jobs (id, payload, total_rows, status, progress, result, created_by, created_at, finished_at, error_message)
+VALUES (:id, :payload, :total_rows, :status, :progress, :result, :created_by, :created_at, :finished_at, :error_message)`
+	if _, err := r.db.NamedExecContext(ctx, query, job); err != nil {
+		return fmt.Errorf("create attendance import job: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns an import job by its identifier.
+func (r *AttendanceImportRepository) GetByID(ctx context.Context, id string) (*models.AttendanceImportJob, error) {
+	query := `SELECT ` + attendanceImportColumns + ` FROM attendance_import_jobs WHERE id = $1`
+	var job models.AttendanceImportJob
+	if err := r.db.GetContext(ctx, &job, query, id); err != nil {
+		return nil, fmt.Errorf("get attendance import job: %w", err)
+	}
+	return &job, nil
+}
+
+// UpdateAttendanceImportParams defines the mutable fields.
+type UpdateAttendanceImportParams struct {
+	Status       *models.ReportStatus
+	Progress     *int
+	Result       *models.AttendanceImportResult
+	ErrorMessage *string
+	FinishedAt   *time.Time
+}
+
+// Update persists the provided changes for an import job row.
+func (r *AttendanceImportRepository) Update(ctx context.Context, id string, params UpdateAttendanceImportParams) error {
+	set := make([]string, 0, 5)
+	args := make([]interface{}, 0, 6)
+	add := func(column string, value interface{}) {
+		args = append(args, value)
+		set = append(set, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if params.Status != nil {
+		add("status", *params.Status)
+	}
+	if params.Progress != nil {
+		add("progress", *params.Progress)
+	}
+	if params.Result != nil {
+		add("result", *params.Result)
+	}
+	if params.ErrorMessage != nil {
+		add("error_message", *params.ErrorMessage)
+	}
+	if params.FinishedAt != nil {
+		add("finished_at", *params.FinishedAt)
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE attendance_import_jobs SET %s WHERE id = $%d", strings.Join(set, ", "), len(args))
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("update attendance import job: %w", err)
+	}
+	return nil
+}
+
+// ListQueued fetches queued jobs (used for cold start recovery).
+func (r *AttendanceImportRepository) ListQueued(ctx context.Context, limit int) ([]models.AttendanceImportJob, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := `SELECT ` + attendanceImportColumns + ` FROM attendance_import_jobs WHERE status = 'QUEUED' ORDER BY created_at ASC LIMIT $1`
+	var jobs []models.AttendanceImportJob
+	if err := r.db.SelectContext(ctx, &jobs, query, limit); err != nil {
+		return nil, fmt.Errorf("list queued attendance import jobs: %w", err)
+	}
+	return jobs, nil
+}
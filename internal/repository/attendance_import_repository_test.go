@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestAttendanceImportRepositoryCreateAndGet(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewAttendanceImportRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_import_jobs")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, "QUEUED", 0, sqlmock.AnyArg(), "admin-1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	job := &models.AttendanceImportJob{
+		Payload:   models.AttendanceImportPayload{Date: "2024-08-05", Mode: "atomic", Items: []models.AttendanceImportItem{{EnrollmentID: "enr-1", Status: "H"}}},
+		TotalRows: 1,
+		CreatedBy: "admin-1",
+	}
+	require.NoError(t, repo.Create(context.Background(), job))
+	require.NotEmpty(t, job.ID)
+
+	rows := sqlmock.NewRows([]string{"id", "payload", "total_rows", "status", "progress", "result", "created_by", "created_at", "finished_at", "error_message"}).
+		AddRow(job.ID, `{"date":"2024-08-05","mode":"atomic","items":[{"enrollmentId":"enr-1","status":"H"}]}`, 1, "FINISHED", 100,
+			`{"processed":1,"success":0,"conflicts":[{"row":0,"enrollmentId":"enr-1","reason":"duplicate record"}]}`, "admin-1", time.Now(), time.Now(), nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM attendance_import_jobs WHERE id = $1")).
+		WithArgs(job.ID).
+		WillReturnRows(rows)
+
+	fetched, err := repo.GetByID(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "enr-1", fetched.Payload.Items[0].EnrollmentID)
+	assert.Equal(t, []models.AttendanceImportConflict{{Row: 0, EnrollmentID: "enr-1", Reason: "duplicate record"}}, fetched.Result.Conflicts)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendanceImportRepositoryUpdate(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewAttendanceImportRepository(db)
+
+	progress := 40
+	result := models.AttendanceImportResult{Processed: 2, Success: 2}
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE attendance_import_jobs SET progress = $1, result = $2 WHERE id = $3")).
+		WithArgs(progress, sqlmock.AnyArg(), "import-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.Update(context.Background(), "import-1", UpdateAttendanceImportParams{Progress: &progress, Result: &result}))
+	require.NoError(t, repo.Update(context.Background(), "import-1", UpdateAttendanceImportParams{}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
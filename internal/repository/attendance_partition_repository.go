@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// attendanceArchiveSchema receives attendance partitions once they are detached.
+const attendanceArchiveSchema = "attendance_archive"
+
+// attendanceTermWindow bounds column to the months the term in placeholder spans. Attendance tables
+// are partitioned by month, so term-scoped queries only read those partitions; the bounds come from
+// subqueries and are resolved when the query starts, which still lets the planner prune.
+func attendanceTermWindow(column, placeholder string) string {
+	return fmt.Sprintf("%[1]s >= (SELECT date_trunc('month', start_date)::DATE FROM terms WHERE id = %[2]s) AND %[1]s < (SELECT (date_trunc('month', end_date) + INTERVAL '1 month')::DATE FROM terms WHERE id = %[2]s)", column, placeholder)
+}
+
+// AttendancePartitionRepository manages the monthly partitions of the attendance tables.
+type AttendancePartitionRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttendancePartitionRepository constructs the repository.
+func NewAttendancePartitionRepository(db *sqlx.DB) *AttendancePartitionRepository {
+	return &AttendancePartitionRepository{db: db}
+}
+
+// List returns the monthly partitions attached to table, oldest first. The default partition is
+// not included.
+func (r *AttendancePartitionRepository) List(ctx context.Context, table string) ([]models.AttendancePartition, error) {
+	if err := checkAttendancePartitionedTable(table); err != nil {
+		return nil, err
+	}
+	const query = `SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = $1::regclass
+ORDER BY c.relname ASC`
+	var names []string
+	if err := r.db.SelectContext(ctx, &names, query, table); err != nil {
+		return nil, fmt.Errorf("list attendance partitions: %w", err)
+	}
+	partitions := make([]models.AttendancePartition, 0, len(names))
+	for _, name := range names {
+		month, err := time.Parse("2006_01", strings.TrimPrefix(name, table+"_"))
+		if err != nil {
+			continue
+		}
+		partitions = append(partitions, models.AttendancePartition{Table: table, Name: name, Month: month})
+	}
+	return partitions, nil
+}
+
+// Create adds the partition of table holding month unless it already exists.
+func (r *AttendancePartitionRepository) Create(ctx context.Context, table string, month time.Time) error {
+	if err := checkAttendancePartitionedTable(table); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `SELECT ensure_attendance_partition($1, $2)`, table, month); err != nil {
+		return fmt.Errorf("create attendance partition: %w", err)
+	}
+	return nil
+}
+
+// Archive detaches partition from table and moves it into the archive schema, where it stays
+// queryable as a plain table until it is dumped and dropped.
+func (r *AttendancePartitionRepository) Archive(ctx context.Context, table, partition string) (err error) {
+	if err := checkAttendancePartitionedTable(table); err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin archive attendance partition: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", pq.QuoteIdentifier(table), pq.QuoteIdentifier(partition))); err != nil {
+		return fmt.Errorf("detach attendance partition: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s", pq.QuoteIdentifier(partition), pq.QuoteIdentifier(attendanceArchiveSchema))); err != nil {
+		return fmt.Errorf("move attendance partition to archive: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit archive attendance partition: %w", err)
+	}
+	return nil
+}
+
+func checkAttendancePartitionedTable(table string) error {
+	for _, known := range models.AttendancePartitionedTables {
+		if table == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a partitioned attendance table", table)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendancePartitionRepositoryList(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAttendancePartitionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE i.inhparent = $1::regclass")).
+		WithArgs("daily_attendance").
+		WillReturnRows(sqlmock.NewRows([]string{"relname"}).
+			AddRow("daily_attendance_2026_02").
+			AddRow("daily_attendance_2026_03").
+			AddRow("daily_attendance_default"))
+
+	partitions, err := repo.List(context.Background(), "daily_attendance")
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+	assert.Equal(t, "daily_attendance_2026_02", partitions[0].Name)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), partitions[0].Month)
+
+	_, err = repo.List(context.Background(), "students")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendancePartitionRepositoryArchive(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewAttendancePartitionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "daily_attendance" DETACH PARTITION "daily_attendance_2025_08"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "daily_attendance_2025_08" SET SCHEMA "attendance_archive"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	require.NoError(t, repo.Archive(context.Background(), "daily_attendance", "daily_attendance_2025_08"))
+
+	// The partition stays attached when it cannot be moved.
+	mock.ExpectBegin()
+	mock.ExpectExec("DETACH PARTITION").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SET SCHEMA").WillReturnError(errors.New("relation already exists"))
+	mock.ExpectRollback()
+	assert.Error(t, repo.Archive(context.Background(), "daily_attendance", "daily_attendance_2025_09"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDailyAttendanceRepositoryStudentSummaryPrunesByTerm(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewDailyAttendanceRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("e.term_id = $2 AND da.date >= (SELECT date_trunc('month', start_date)::DATE FROM terms WHERE id = $2)")).
+		WithArgs("student-1", "term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "cnt"}).AddRow("H", 3).AddRow("A", 1))
+	summary, err := repo.StudentSummary(context.Background(), "student-1", "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.Total)
+
+	mock.ExpectQuery(`WHERE e.student_id = \$1\s+GROUP BY`).
+		WithArgs("student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "cnt"}))
+	_, err = repo.StudentSummary(context.Background(), "student-1", "")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const backupColumns = `id, status, file_path, size_bytes, checksum, storage_files, error_message, requested_by, created_at, started_at, finished_at`
+
+// BackupRepository persists backup runs.
+type BackupRepository struct {
+	db *sqlx.DB
+}
+
+// NewBackupRepository constructs the repository.
+func NewBackupRepository(db *sqlx.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Create inserts a queued backup.
+func (r *BackupRepository) Create(ctx context.Context, backup *models.Backup) error {
+	if backup.ID == "" {
+		backup.ID = uuid.NewString()
+	}
+	if backup.Status == "" {
+		backup.Status = models.ReportStatusQueued
+	}
+	if backup.CreatedAt.IsZero() {
+		backup.CreatedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO backups (id, status, requested_by, created_at) VALUES ($1, $2, $3, $4)`
+	if _, err := r.db.ExecContext(ctx, query, backup.ID, backup.Status, backup.RequestedBy, backup.CreatedAt); err != nil {
+		return fmt.Errorf("create backup: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns a backup.
+func (r *BackupRepository) FindByID(ctx context.Context, id string) (*models.Backup, error) {
+	var backup models.Backup
+	if err := r.db.GetContext(ctx, &backup, `SELECT `+backupColumns+` FROM backups WHERE id = $1`, id); err != nil {
+		return nil, fmt.Errorf("find backup: %w", err)
+	}
+	return &backup, nil
+}
+
+// List returns the most recent backups, newest first.
+func (r *BackupRepository) List(ctx context.Context, limit int) ([]models.Backup, error) {
+	backups := []models.Backup{}
+	if err := r.db.SelectContext(ctx, &backups, `SELECT `+backupColumns+` FROM backups ORDER BY created_at DESC LIMIT $1`, limit); err != nil {
+		return nil, fmt.Errorf("list backups: %w", err)
+	}
+	return backups, nil
+}
+
+// Active returns the queued or running backup, or nil when there is none.
+func (r *BackupRepository) Active(ctx context.Context) (*models.Backup, error) {
+	var backup models.Backup
+	err := r.db.GetContext(ctx, &backup, `SELECT `+backupColumns+` FROM backups
+WHERE status IN ('QUEUED', 'PROCESSING') ORDER BY created_at ASC LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find active backup: %w", err)
+	}
+	return &backup, nil
+}
+
+// MarkProcessing moves a queued backup to PROCESSING. It returns sql.ErrNoRows when the backup is no
+// longer queued.
+func (r *BackupRepository) MarkProcessing(ctx context.Context, id string, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE backups SET status = 'PROCESSING', started_at = $2 WHERE id = $1 AND status = 'QUEUED'`, id, at)
+	if err != nil {
+		return fmt.Errorf("start backup: %w", err)
+	}
+	return expectAffected(res, "start backup")
+}
+
+// Complete records the archive of a finished backup.
+func (r *BackupRepository) Complete(ctx context.Context, id string, result models.BackupResult, at time.Time) error {
+	const query = `UPDATE backups SET status = 'FINISHED', file_path = $2, size_bytes = $3, checksum = $4, storage_files = $5,
+    error_message = NULL, finished_at = $6
+WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, result.FilePath, result.SizeBytes, result.Checksum, result.StorageFiles, at)
+	if err != nil {
+		return fmt.Errorf("complete backup: %w", err)
+	}
+	return expectAffected(res, "complete backup")
+}
+
+// Fail marks a backup failed with message.
+func (r *BackupRepository) Fail(ctx context.Context, id, message string, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE backups SET status = 'FAILED', error_message = $2, finished_at = $3 WHERE id = $1`, id, message, at)
+	if err != nil {
+		return fmt.Errorf("fail backup: %w", err)
+	}
+	return expectAffected(res, "fail backup")
+}
+
+// FailUnfinished fails every queued or running backup and returns how many there were.
+func (r *BackupRepository) FailUnfinished(ctx context.Context, message string, at time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE backups SET status = 'FAILED', error_message = $1, finished_at = $2
+WHERE status IN ('QUEUED', 'PROCESSING')`, message, at)
+	if err != nil {
+		return 0, fmt.Errorf("fail unfinished backups: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("fail unfinished backups rows: %w", err)
+	}
+	return int(affected), nil
+}
+
+func expectAffected(res sql.Result, action string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows: %w", action, err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -3,23 +3,25 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	"github.com/noah-isme/sma-adp-api/pkg/cache"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 // CacheRepository provides helpers around Redis interactions for caching analytics payloads.
 type CacheRepository struct {
-	client *redis.Client
+	client redis.UniversalClient
 	logger *zap.Logger
 }
 
 // NewCacheRepository constructs a cache repository.
-func NewCacheRepository(client *redis.Client, logger *zap.Logger) *CacheRepository {
+func NewCacheRepository(client redis.UniversalClient, logger *zap.Logger) *CacheRepository {
 	return &CacheRepository{client: client, logger: logger}
 }
 
@@ -31,7 +33,7 @@ func (r *CacheRepository) Get(ctx context.Context, key string, dest interface{})
 
 	raw, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
-		if err == redis.Nil {
+		if err == redis.Nil || errors.Is(err, cache.ErrCircuitOpen) {
 			return appErrors.ErrCacheMiss
 		}
 		return fmt.Errorf("redis get %s: %w", key, err)
@@ -56,6 +58,9 @@ func (r *CacheRepository) Set(ctx context.Context, key string, value interface{}
 	}
 
 	if err := r.client.Set(ctx, key, payload, ttl).Err(); err != nil {
+		if errors.Is(err, cache.ErrCircuitOpen) {
+			return nil
+		}
 		return fmt.Errorf("redis set %s: %w", key, err)
 	}
 
@@ -68,10 +73,20 @@ func (r *CacheRepository) DeleteByPattern(ctx context.Context, pattern string) e
 		return nil
 	}
 
-	iter := r.client.Scan(ctx, 0, pattern, 0).Iterator()
+	// SCAN only covers a single node, so cluster deployments walk every master.
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return deleteByPattern(ctx, node, pattern)
+		})
+	}
+	return deleteByPattern(ctx, r.client, pattern)
+}
+
+func deleteByPattern(ctx context.Context, client redis.Cmdable, pattern string) error {
+	iter := client.Scan(ctx, 0, pattern, 0).Iterator()
 	for iter.Next(ctx) {
 		key := iter.Val()
-		if err := r.client.Del(ctx, key).Err(); err != nil {
+		if err := client.Del(ctx, key).Err(); err != nil {
 			return fmt.Errorf("redis delete %s: %w", key, err)
 		}
 	}
@@ -60,7 +60,7 @@ func (r *CalendarRepository) List(ctx context.Context, filter models.CalendarFil
 	}
 	offset := (page - 1) * size
 
-	query := fmt.Sprintf(`SELECT id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, created_by, created_at, updated_at
+	query := fmt.Sprintf(`SELECT id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, rsvp_enabled, rsvp_capacity, created_by, created_at, updated_at
 %s WHERE %s ORDER BY start_date ASC, start_time ASC NULLS FIRST LIMIT %d OFFSET %d`, base, whereClause, size, offset)
 	var events []models.CalendarEvent
 	if err := r.db.SelectContext(ctx, &events, query, args...); err != nil {
@@ -76,7 +76,7 @@ func (r *CalendarRepository) List(ctx context.Context, filter models.CalendarFil
 
 // GetByID fetches a calendar event.
 func (r *CalendarRepository) GetByID(ctx context.Context, id string) (*models.CalendarEvent, error) {
-	const query = `SELECT id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, created_by, created_at, updated_at
+	const query = `SELECT id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, rsvp_enabled, rsvp_capacity, created_by, created_at, updated_at
 FROM calendar_events WHERE id = $1`
 	var event models.CalendarEvent
 	if err := r.db.GetContext(ctx, &event, query, id); err != nil {
@@ -95,8 +95,8 @@ func (r *CalendarRepository) Create(ctx context.Context, event *models.CalendarE
 		event.CreatedAt = now
 	}
 	event.UpdatedAt = now
-	query := `INSERT INTO calendar_events (id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, created_by, created_at, updated_at)
-VALUES (:id, :title, :description, :event_type, :start_date, :end_date, :start_time, :end_time, :audience, :target_class_id, :location, :created_by, :created_at, :updated_at)`
+	query := `INSERT INTO calendar_events (id, title, description, event_type, start_date, end_date, start_time, end_time, audience, target_class_id, location, rsvp_enabled, rsvp_capacity, created_by, created_at, updated_at)
+VALUES (:id, :title, :description, :event_type, :start_date, :end_date, :start_time, :end_time, :audience, :target_class_id, :location, :rsvp_enabled, :rsvp_capacity, :created_by, :created_at, :updated_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, event); err != nil {
 		return fmt.Errorf("create calendar event: %w", err)
 	}
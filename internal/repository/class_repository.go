@@ -156,6 +156,23 @@ func (r *ClassRepository) Update(ctx context.Context, class *models.Class) error
 	return nil
 }
 
+var classPatchColumns = map[string]struct{}{
+	"name": {}, "grade": {}, "track": {}, "homeroom_teacher_id": {},
+}
+
+// UpdateFields writes only the given columns of class so concurrent edits to other fields survive.
+func (r *ClassRepository) UpdateFields(ctx context.Context, class *models.Class, columns []string) error {
+	query, err := partialUpdateQuery("classes", columns, classPatchColumns)
+	if err != nil {
+		return fmt.Errorf("patch class: %w", err)
+	}
+	class.UpdatedAt = time.Now().UTC()
+	if _, err := r.db.NamedExecContext(ctx, query, class); err != nil {
+		return fmt.Errorf("patch class: %w", err)
+	}
+	return nil
+}
+
 // Delete removes a class record.
 func (r *ClassRepository) Delete(ctx context.Context, id string) error {
 	if _, err := r.db.ExecContext(ctx, `DELETE FROM classes WHERE id = $1`, id); err != nil {
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const curriculumTopicColumns = `id, subject_id, term_id, title, description, planned_week, created_at, updated_at`
+
+// CurriculumRepository persists syllabus topics and per-class teaching progress.
+type CurriculumRepository struct {
+	db *sqlx.DB
+}
+
+// NewCurriculumRepository constructs the repository.
+func NewCurriculumRepository(db *sqlx.DB) *CurriculumRepository {
+	return &CurriculumRepository{db: db}
+}
+
+// ListTopics returns the topics of a subject in a term ordered by planned week.
+func (r *CurriculumRepository) ListTopics(ctx context.Context, subjectID, termID string) ([]models.CurriculumTopic, error) {
+	query := `SELECT ` + curriculumTopicColumns + ` FROM curriculum_topics
+WHERE subject_id = $1 AND term_id = $2 ORDER BY planned_week ASC, created_at ASC`
+	var topics []models.CurriculumTopic
+	if err := r.db.SelectContext(ctx, &topics, query, subjectID, termID); err != nil {
+		return nil, fmt.Errorf("list curriculum topics: %w", err)
+	}
+	return topics, nil
+}
+
+// FindTopic fetches a topic.
+func (r *CurriculumRepository) FindTopic(ctx context.Context, id string) (*models.CurriculumTopic, error) {
+	var topic models.CurriculumTopic
+	if err := r.db.GetContext(ctx, &topic, `SELECT `+curriculumTopicColumns+` FROM curriculum_topics WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &topic, nil
+}
+
+// CreateTopic inserts a topic.
+func (r *CurriculumRepository) CreateTopic(ctx context.Context, topic *models.CurriculumTopic) error {
+	if topic.ID == "" {
+		topic.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if topic.CreatedAt.IsZero() {
+		topic.CreatedAt = now
+	}
+	topic.UpdatedAt = now
+	const query = `INSERT INTO curriculum_topics (id, subject_id, term_id, title, description, planned_week, created_at, updated_at)
+VALUES (:id, :subject_id, :term_id, :title, :description, :planned_week, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, topic); err != nil {
+		return fmt.Errorf("create curriculum topic: %w", err)
+	}
+	return nil
+}
+
+// UpdateTopic updates the title, description and planned week of a topic.
+func (r *CurriculumRepository) UpdateTopic(ctx context.Context, topic *models.CurriculumTopic) error {
+	topic.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE curriculum_topics SET title = :title, description = :description, planned_week = :planned_week, updated_at = :updated_at
+WHERE id = :id`
+	result, err := r.db.NamedExecContext(ctx, query, topic)
+	if err != nil {
+		return fmt.Errorf("update curriculum topic: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check curriculum topic rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteTopic removes a topic and, through the cascade, its progress records.
+func (r *CurriculumRepository) DeleteTopic(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM curriculum_topics WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete curriculum topic: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check curriculum topic rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListClassProgress returns every topic of the subject in the term with the class's progress, if any.
+func (r *CurriculumRepository) ListClassProgress(ctx context.Context, classID, subjectID, termID string) ([]models.CurriculumTopicProgress, error) {
+	const query = `SELECT ct.id, ct.subject_id, ct.term_id, ct.title, ct.description, ct.planned_week, ct.created_at, ct.updated_at,
+       cp.taught_on, cp.notes, cp.marked_by
+FROM curriculum_topics ct
+LEFT JOIN curriculum_progress cp ON cp.topic_id = ct.id AND cp.class_id = $1
+WHERE ct.subject_id = $2 AND ct.term_id = $3
+ORDER BY ct.planned_week ASC, ct.created_at ASC`
+	var items []models.CurriculumTopicProgress
+	if err := r.db.SelectContext(ctx, &items, query, classID, subjectID, termID); err != nil {
+		return nil, fmt.Errorf("list curriculum progress: %w", err)
+	}
+	return items, nil
+}
+
+// UpsertProgress marks a topic as taught to a class, replacing an earlier mark.
+func (r *CurriculumRepository) UpsertProgress(ctx context.Context, progress *models.CurriculumProgress) error {
+	if progress.ID == "" {
+		progress.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if progress.CreatedAt.IsZero() {
+		progress.CreatedAt = now
+	}
+	progress.UpdatedAt = now
+	const query = `INSERT INTO curriculum_progress (id, topic_id, class_id, taught_on, notes, marked_by, created_at, updated_at)
+VALUES (:id, :topic_id, :class_id, :taught_on, :notes, :marked_by, :created_at, :updated_at)
+ON CONFLICT (topic_id, class_id)
+DO UPDATE SET taught_on = EXCLUDED.taught_on, notes = EXCLUDED.notes, marked_by = EXCLUDED.marked_by, updated_at = EXCLUDED.updated_at`
+	if _, err := r.db.NamedExecContext(ctx, query, progress); err != nil {
+		return fmt.Errorf("upsert curriculum progress: %w", err)
+	}
+	return nil
+}
+
+// DeleteProgress clears the taught mark of a topic for a class.
+func (r *CurriculumRepository) DeleteProgress(ctx context.Context, topicID, classID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM curriculum_progress WHERE topic_id = $1 AND class_id = $2`, topicID, classID)
+	if err != nil {
+		return fmt.Errorf("delete curriculum progress: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check curriculum progress rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Coverage counts planned and taught topics for every subject teaching assignment of the term.
+func (r *CurriculumRepository) Coverage(ctx context.Context, filter models.CurriculumCoverageFilter) ([]models.CurriculumCoverageRow, error) {
+	args := []interface{}{filter.TermID, filter.PlannedWeek, models.TeacherAssignmentRoleSubject}
+	conditions := []string{"ta.term_id = $1", "ta.role = $3"}
+	add := func(column, value string) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.ClassID != "" {
+		add("ta.class_id", filter.ClassID)
+	}
+	if filter.SubjectID != "" {
+		add("ta.subject_id", filter.SubjectID)
+	}
+	if filter.TeacherID != "" {
+		add("ta.teacher_id", filter.TeacherID)
+	}
+	query := fmt.Sprintf(`SELECT ta.class_id, ta.subject_id, ta.teacher_id,
+       COUNT(ct.id) AS total_topics,
+       COUNT(ct.id) FILTER (WHERE ct.planned_week <= $2) AS planned_to_date,
+       COUNT(cp.id) AS taught,
+       COUNT(cp.id) FILTER (WHERE ct.planned_week <= $2) AS taught_on_planned
+FROM teacher_assignments ta
+LEFT JOIN curriculum_topics ct ON ct.subject_id = ta.subject_id AND ct.term_id = ta.term_id
+LEFT JOIN curriculum_progress cp ON cp.topic_id = ct.id AND cp.class_id = ta.class_id
+WHERE %s
+GROUP BY ta.class_id, ta.subject_id, ta.teacher_id
+ORDER BY ta.class_id ASC, ta.subject_id ASC`, strings.Join(conditions, " AND "))
+	var rows []models.CurriculumCoverageRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("curriculum coverage: %w", err)
+	}
+	return rows, nil
+}
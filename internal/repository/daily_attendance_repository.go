@@ -11,6 +11,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 )
 
 // DailyAttendanceRepository handles persistence for daily attendance records.
@@ -23,6 +24,13 @@ func NewDailyAttendanceRepository(db *sqlx.DB) *DailyAttendanceRepository {
 	return &DailyAttendanceRepository{db: db}
 }
 
+func (r *DailyAttendanceRepository) exec(exec sqlx.ExtContext) sqlx.ExtContext {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
 // List returns daily attendance rows matching the provided filter.
 func (r *DailyAttendanceRepository) List(ctx context.Context, filter models.DailyAttendanceFilter) ([]models.DailyAttendanceRecord, int, error) {
 	base := `FROM daily_attendance da
@@ -37,6 +45,7 @@ LEFT JOIN classes c ON c.id = e.class_id`
 	}
 	if filter.TermID != "" {
 		where = append(where, fmt.Sprintf("e.term_id = $%d", len(args)+1))
+		where = append(where, attendanceTermWindow("da.date", fmt.Sprintf("$%d", len(args)+1)))
 		args = append(args, filter.TermID)
 	}
 	if filter.StudentID != "" {
@@ -83,7 +92,7 @@ LEFT JOIN classes c ON c.id = e.class_id`
 	}
 	offset := (page - 1) * size
 
-	query := fmt.Sprintf(`SELECT da.id, da.enrollment_id, da.date, da.status, da.notes, da.created_at, da.updated_at,
+	query := fmt.Sprintf(`SELECT da.id, da.enrollment_id, da.date, da.status, da.absence_reason, da.notes, da.created_at, da.updated_at,
         e.student_id, s.full_name AS student_name, e.class_id, c.name AS class_name, e.term_id
         %s WHERE %s
         ORDER BY %s %s
@@ -103,7 +112,7 @@ LEFT JOIN classes c ON c.id = e.class_id`
 }
 
 // Upsert inserts or updates a daily attendance record.
-func (r *DailyAttendanceRepository) Upsert(ctx context.Context, record *models.DailyAttendance) (*models.DailyAttendance, error) {
+func (r *DailyAttendanceRepository) Upsert(ctx context.Context, exec sqlx.ExtContext, record *models.DailyAttendance) (*models.DailyAttendance, error) {
 	now := time.Now().UTC()
 	if record.ID == "" {
 		record.ID = uuid.NewString()
@@ -112,36 +121,84 @@ func (r *DailyAttendanceRepository) Upsert(ctx context.Context, record *models.D
 		record.CreatedAt = now
 	}
 	record.UpdatedAt = now
-	query := `INSERT INTO daily_attendance (id, enrollment_id, date, status, notes, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+	query := `INSERT INTO daily_attendance (id, enrollment_id, date, status, absence_reason, notes, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (enrollment_id, date)
-DO UPDATE SET status = EXCLUDED.status, notes = EXCLUDED.notes, updated_at = EXCLUDED.updated_at
-RETURNING id, enrollment_id, date, status, notes, created_at, updated_at`
+DO UPDATE SET status = EXCLUDED.status, absence_reason = EXCLUDED.absence_reason, notes = EXCLUDED.notes, updated_at = EXCLUDED.updated_at
+RETURNING id, enrollment_id, date, status, absence_reason, notes, created_at, updated_at`
 	var stored models.DailyAttendance
-	if err := r.db.GetContext(ctx, &stored, query, record.ID, record.EnrollmentID, record.Date, record.Status, record.Notes, record.CreatedAt, record.UpdatedAt); err != nil {
+	if err := sqlx.GetContext(ctx, r.exec(exec), &stored, query, record.ID, record.EnrollmentID, record.Date, record.Status, record.AbsenceReason, record.Notes, record.CreatedAt, record.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("upsert daily attendance: %w", err)
 	}
 	return &stored, nil
 }
 
-// BulkInsert inserts many records best-effort; returns conflicting entries when partial.
-func (r *DailyAttendanceRepository) BulkInsert(ctx context.Context, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error) {
+// InsertCheckIn stores a device check-in unless the enrollment already has a row for that date, in
+// which case the existing row is returned with created=false so repeated scans never overwrite it.
+func (r *DailyAttendanceRepository) InsertCheckIn(ctx context.Context, exec sqlx.ExtContext, record *models.DailyAttendance) (*models.DailyAttendance, bool, error) {
+	now := time.Now().UTC()
+	if record.ID == "" {
+		record.ID = uuid.NewString()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+	const columns = `id, enrollment_id, date, status, notes, source, checked_in_at, created_at, updated_at`
+	insert := `INSERT INTO daily_attendance (` + columns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (enrollment_id, date) DO NOTHING
+RETURNING ` + columns
+	var stored models.DailyAttendance
+	err := sqlx.GetContext(ctx, r.exec(exec), &stored, insert, record.ID, record.EnrollmentID, record.Date, record.Status, record.Notes, record.Source, record.CheckedInAt, record.CreatedAt, record.UpdatedAt)
+	if err == nil {
+		return &stored, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("insert daily check-in: %w", err)
+	}
+	existing := `SELECT ` + columns + ` FROM daily_attendance WHERE enrollment_id = $1 AND date = $2`
+	if err := sqlx.GetContext(ctx, r.exec(exec), &stored, existing, record.EnrollmentID, record.Date); err != nil {
+		return nil, false, fmt.Errorf("load existing daily attendance: %w", err)
+	}
+	return &stored, false, nil
+}
+
+// BulkInsert inserts many records best-effort; returns conflicting entries when partial. Without
+// exec the records are written in their own transaction, which is rolled back instead of committed
+// for dry-run contexts; with exec the caller owns the transaction.
+func (r *DailyAttendanceRepository) BulkInsert(ctx context.Context, exec sqlx.ExtContext, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error) {
 	if len(records) == 0 {
 		return nil, nil
 	}
+	if exec != nil {
+		return r.bulkInsert(ctx, exec, records, atomic)
+	}
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("begin bulk daily attendance: %w", err)
 	}
-	conflicts := make([]models.DailyAttendance, 0)
 	commit := false
 	defer func() {
 		if !commit {
 			tx.Rollback()
 		}
 	}()
-	query := `INSERT INTO daily_attendance (id, enrollment_id, date, status, notes, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+	conflicts, err := r.bulkInsert(ctx, tx, records, atomic)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.CommitOrRollback(ctx, tx); err != nil {
+		return nil, fmt.Errorf("commit bulk daily attendance: %w", err)
+	}
+	commit = true
+	return conflicts, nil
+}
+
+func (r *DailyAttendanceRepository) bulkInsert(ctx context.Context, exec sqlx.ExtContext, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error) {
+	conflicts := make([]models.DailyAttendance, 0)
+	query := `INSERT INTO daily_attendance (id, enrollment_id, date, status, absence_reason, notes, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (enrollment_id, date) DO NOTHING RETURNING id`
 	now := time.Now().UTC()
 	for i := range records {
@@ -154,7 +211,7 @@ ON CONFLICT (enrollment_id, date) DO NOTHING RETURNING id`
 		}
 		rec.UpdatedAt = now
 		var insertedID string
-		if err := tx.QueryRowxContext(ctx, query, rec.ID, rec.EnrollmentID, rec.Date, rec.Status, rec.Notes, rec.CreatedAt, rec.UpdatedAt).Scan(&insertedID); err != nil {
+		if err := exec.QueryRowxContext(ctx, query, rec.ID, rec.EnrollmentID, rec.Date, rec.Status, rec.AbsenceReason, rec.Notes, rec.CreatedAt, rec.UpdatedAt).Scan(&insertedID); err != nil {
 			if err == sql.ErrNoRows {
 				conflicts = append(conflicts, *rec)
 				if atomic {
@@ -165,16 +222,28 @@ ON CONFLICT (enrollment_id, date) DO NOTHING RETURNING id`
 			return nil, fmt.Errorf("bulk insert daily attendance: %w", err)
 		}
 	}
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("commit bulk daily attendance: %w", err)
-	}
-	commit = true
 	return conflicts, nil
 }
 
+// ReplaceEvidence sets the documents excusing an enrollment's absence on date, dropping links not
+// in evidence.
+func (r *DailyAttendanceRepository) ReplaceEvidence(ctx context.Context, exec sqlx.ExtContext, enrollmentID string, date time.Time, evidence []models.AttendanceEvidence) error {
+	if _, err := r.exec(exec).ExecContext(ctx, `DELETE FROM attendance_evidence WHERE enrollment_id = $1 AND date = $2`, enrollmentID, date); err != nil {
+		return fmt.Errorf("clear attendance evidence: %w", err)
+	}
+	const insert = `INSERT INTO attendance_evidence (enrollment_id, date, archive_id, linked_by, linked_at)
+VALUES ($1, $2, $3, $4, $5)`
+	for _, item := range evidence {
+		if _, err := r.exec(exec).ExecContext(ctx, insert, enrollmentID, date, item.ArchiveID, item.LinkedBy, item.LinkedAt); err != nil {
+			return fmt.Errorf("link attendance evidence: %w", err)
+		}
+	}
+	return nil
+}
+
 // ClassReport summarises attendance for a class on a given date.
 func (r *DailyAttendanceRepository) ClassReport(ctx context.Context, classID string, date time.Time) ([]models.DailyAttendanceReportRow, error) {
-	query := `SELECT s.id AS student_id, s.full_name AS student_name, da.status, da.notes
+	query := `SELECT s.id AS student_id, s.full_name AS student_name, da.status, da.absence_reason, da.notes
 FROM daily_attendance da
 JOIN enrollments e ON e.id = da.enrollment_id
 JOIN students s ON s.id = e.student_id
@@ -198,7 +267,7 @@ func (r *DailyAttendanceRepository) StudentHistory(ctx context.Context, studentI
 		where = append(where, fmt.Sprintf("da.date <= $%d", len(args)+1))
 		args = append(args, *to)
 	}
-	query := fmt.Sprintf(`SELECT da.date, da.status, da.notes
+	query := fmt.Sprintf(`SELECT da.date, da.status, da.absence_reason, da.notes
 FROM daily_attendance da
 JOIN enrollments e ON e.id = da.enrollment_id
 WHERE %s
@@ -210,18 +279,24 @@ ORDER BY da.date DESC`, strings.Join(where, " AND "))
 	return rows, nil
 }
 
-// StudentSummary aggregates counts for a student within a term.
+// StudentSummary aggregates counts for a student within a term, or across terms when termID is empty.
 func (r *DailyAttendanceRepository) StudentSummary(ctx context.Context, studentID string, termID string) (*models.DailyAttendanceSummary, error) {
-	query := `SELECT da.status, COUNT(*) AS cnt
+	where := "e.student_id = $1"
+	args := []interface{}{studentID}
+	if termID != "" {
+		where += " AND e.term_id = $2 AND " + attendanceTermWindow("da.date", "$2")
+		args = append(args, termID)
+	}
+	query := fmt.Sprintf(`SELECT da.status, COUNT(*) AS cnt
 FROM daily_attendance da
 JOIN enrollments e ON e.id = da.enrollment_id
-WHERE e.student_id = $1 AND ($2 = '' OR e.term_id = $2)
-GROUP BY da.status`
+WHERE %s
+GROUP BY da.status`, where)
 	rows := []struct {
 		Status string `db:"status"`
 		Count  int    `db:"cnt"`
 	}{}
-	if err := r.db.SelectContext(ctx, &rows, query, studentID, termID); err != nil {
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
 		return nil, fmt.Errorf("student attendance summary: %w", err)
 	}
 	summary := &models.DailyAttendanceSummary{}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+var dailyCheckInColumns = []string{"id", "enrollment_id", "date", "status", "notes", "source", "checked_in_at", "created_at", "updated_at"}
+
+func TestDailyAttendanceRepositoryInsertCheckIn(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewDailyAttendanceRepository(db)
+
+	date := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	scanned := date.Add(7 * time.Hour)
+	record := &models.DailyAttendance{EnrollmentID: "enr-1", Date: date, Status: models.AttendanceStatusPresent, Source: models.AttendanceSourceDevice, CheckedInAt: &scanned}
+
+	mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (enrollment_id, date) DO NOTHING")).
+		WithArgs(sqlmock.AnyArg(), "enr-1", date, models.AttendanceStatusPresent, nil, models.AttendanceSourceDevice, &scanned, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(dailyCheckInColumns).AddRow("att-1", "enr-1", date, "H", nil, "DEVICE", scanned, time.Now(), time.Now()))
+
+	stored, created, err := repo.InsertCheckIn(context.Background(), nil, record)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, models.AttendanceSourceDevice, stored.Source)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDailyAttendanceRepositoryInsertCheckInKeepsExistingRow(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewDailyAttendanceRepository(db)
+
+	date := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO daily_attendance")).
+		WillReturnRows(sqlmock.NewRows(dailyCheckInColumns))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM daily_attendance WHERE enrollment_id = $1 AND date = $2")).
+		WithArgs("enr-1", date).
+		WillReturnRows(sqlmock.NewRows(dailyCheckInColumns).AddRow("att-0", "enr-1", date, "S", "sick note", "MANUAL", nil, time.Now(), time.Now()))
+
+	stored, created, err := repo.InsertCheckIn(context.Background(), nil, &models.DailyAttendance{EnrollmentID: "enr-1", Date: date, Status: models.AttendanceStatusPresent})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "att-0", stored.ID)
+	assert.Equal(t, models.AttendanceStatusSick, stored.Status)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDailyAttendanceRepositoryUpsertAndReplaceEvidence(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewDailyAttendanceRepository(db)
+
+	date := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	reason := models.AbsenceReasonSchoolDuty
+	mock.ExpectQuery(regexp.QuoteMeta("absence_reason = EXCLUDED.absence_reason")).
+		WithArgs(sqlmock.AnyArg(), "enr-1", date, models.AttendanceStatusExcused, &reason, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "enrollment_id", "date", "status", "absence_reason", "notes", "created_at", "updated_at"}).
+			AddRow("att-1", "enr-1", date, "I", "SCHOOL_DUTY", nil, time.Now(), time.Now()))
+	stored, err := repo.Upsert(context.Background(), nil, &models.DailyAttendance{EnrollmentID: "enr-1", Date: date, Status: models.AttendanceStatusExcused, AbsenceReason: &reason})
+	require.NoError(t, err)
+	require.NotNil(t, stored.AbsenceReason)
+	assert.Equal(t, models.AbsenceReasonSchoolDuty, *stored.AbsenceReason)
+
+	linkedAt := date.Add(9 * time.Hour)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM attendance_evidence WHERE enrollment_id = $1 AND date = $2")).
+		WithArgs("enr-1", date).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO attendance_evidence")).
+		WithArgs("enr-1", date, "arc-1", "teacher-1", linkedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	err = repo.ReplaceEvidence(context.Background(), nil, "enr-1", date, []models.AttendanceEvidence{{ArchiveID: "arc-1", LinkedBy: "teacher-1", LinkedAt: linkedAt}})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// DeviceTokenRepository persists the push tokens registered by users' devices.
+type DeviceTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeviceTokenRepository constructs the repository.
+func NewDeviceTokenRepository(db *sqlx.DB) *DeviceTokenRepository {
+	return &DeviceTokenRepository{db: db}
+}
+
+// Register stores token for its user. A token already registered, possibly by another user signed
+// in on the same device, is moved to the new user and its last seen time refreshed.
+func (r *DeviceTokenRepository) Register(ctx context.Context, token *models.DeviceToken) error {
+	if token.ID == "" {
+		token.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = now
+	}
+	token.LastSeenAt = now
+	const query = `INSERT INTO device_tokens (id, user_id, token, platform, created_at, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, last_seen_at = EXCLUDED.last_seen_at
+RETURNING id, created_at`
+	if err := r.db.QueryRowxContext(ctx, query, token.ID, token.UserID, token.Token, token.Platform, token.CreatedAt, token.LastSeenAt).Scan(&token.ID, &token.CreatedAt); err != nil {
+		return fmt.Errorf("register device token: %w", err)
+	}
+	return nil
+}
+
+// Delete removes one of a user's tokens.
+func (r *DeviceTokenRepository) Delete(ctx context.Context, userID, token string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE user_id = $1 AND token = $2`, userID, token)
+	if err != nil {
+		return fmt.Errorf("delete device token: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check device token rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListByUsers returns the tokens of every given user.
+func (r *DeviceTokenRepository) ListByUsers(ctx context.Context, userIDs []string) ([]models.DeviceToken, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var tokens []models.DeviceToken
+	const query = `SELECT id, user_id, token, platform, created_at, last_seen_at FROM device_tokens WHERE user_id = ANY($1)`
+	if err := r.db.SelectContext(ctx, &tokens, query, pq.Array(userIDs)); err != nil {
+		return nil, fmt.Errorf("list device tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// DeleteTokens forgets tokens the push provider no longer accepts.
+func (r *DeviceTokenRepository) DeleteTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE token = ANY($1)`, pq.Array(tokens)); err != nil {
+		return fmt.Errorf("delete device tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+)
+
+const electiveOfferingColumns = `id, term_id, subject_id, category, name, description, created_at, updated_at`
+
+// ElectiveRepository persists elective offerings, their sections and students' ranked selections.
+type ElectiveRepository struct {
+	db *sqlx.DB
+}
+
+// NewElectiveRepository constructs the repository.
+func NewElectiveRepository(db *sqlx.DB) *ElectiveRepository {
+	return &ElectiveRepository{db: db}
+}
+
+// CreateOffering stores an offering with its sections.
+func (r *ElectiveRepository) CreateOffering(ctx context.Context, offering *models.ElectiveOffering) error {
+	if offering.ID == "" {
+		offering.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	offering.CreatedAt, offering.UpdatedAt = now, now
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		const query = `INSERT INTO elective_offerings (id, term_id, subject_id, category, name, description, created_at, updated_at)
+VALUES (:id, :term_id, :subject_id, :category, :name, :description, :created_at, :updated_at)`
+		if _, err := tx.NamedExecContext(ctx, query, offering); err != nil {
+			return fmt.Errorf("create elective offering: %w", err)
+		}
+		return upsertElectiveSections(ctx, tx, offering)
+	})
+}
+
+// UpdateOffering rewrites the offering's name and description and its sections: sections with an ID
+// are updated, new ones inserted and those no longer listed removed. It returns sql.ErrNoRows when the
+// offering does not exist.
+func (r *ElectiveRepository) UpdateOffering(ctx context.Context, offering *models.ElectiveOffering) error {
+	offering.UpdatedAt = time.Now().UTC()
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, `UPDATE elective_offerings SET name = :name, description = :description, updated_at = :updated_at WHERE id = :id`, offering)
+		if err != nil {
+			return fmt.Errorf("update elective offering: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("check elective offering update rows: %w", err)
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		keep := make([]string, 0, len(offering.Sections))
+		for i := range offering.Sections {
+			if offering.Sections[i].ID == "" {
+				offering.Sections[i].ID = uuid.NewString()
+			}
+			keep = append(keep, offering.Sections[i].ID)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM elective_sections WHERE offering_id = $1 AND NOT (id = ANY($2))`, offering.ID, pq.Array(keep)); err != nil {
+			return fmt.Errorf("remove elective sections: %w", err)
+		}
+		return upsertElectiveSections(ctx, tx, offering)
+	})
+}
+
+func upsertElectiveSections(ctx context.Context, tx *sqlx.Tx, offering *models.ElectiveOffering) error {
+	const query = `INSERT INTO elective_sections (id, offering_id, name, teacher_id, capacity, room)
+VALUES (:id, :offering_id, :name, :teacher_id, :capacity, :room)
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, teacher_id = EXCLUDED.teacher_id, capacity = EXCLUDED.capacity, room = EXCLUDED.room`
+	for i := range offering.Sections {
+		section := &offering.Sections[i]
+		if section.ID == "" {
+			section.ID = uuid.NewString()
+		}
+		section.OfferingID = offering.ID
+		if _, err := tx.NamedExecContext(ctx, query, section); err != nil {
+			return fmt.Errorf("save elective section: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindOffering loads an offering with its sections.
+func (r *ElectiveRepository) FindOffering(ctx context.Context, id string) (*models.ElectiveOffering, error) {
+	var offering models.ElectiveOffering
+	if err := r.db.GetContext(ctx, &offering, `SELECT `+electiveOfferingColumns+` FROM elective_offerings WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	offerings := []models.ElectiveOffering{offering}
+	if err := r.loadSections(ctx, offerings); err != nil {
+		return nil, err
+	}
+	return &offerings[0], nil
+}
+
+// ListOfferings returns the term's offerings ordered by category and name; an empty category lists
+// every category.
+func (r *ElectiveRepository) ListOfferings(ctx context.Context, termID, category string) ([]models.ElectiveOffering, error) {
+	query := `SELECT ` + electiveOfferingColumns + ` FROM elective_offerings WHERE term_id = $1`
+	args := []interface{}{termID}
+	if category != "" {
+		query += ` AND category = $2`
+		args = append(args, category)
+	}
+	query += ` ORDER BY category ASC, name ASC`
+	var offerings []models.ElectiveOffering
+	if err := r.db.SelectContext(ctx, &offerings, query, args...); err != nil {
+		return nil, fmt.Errorf("list elective offerings: %w", err)
+	}
+	if err := r.loadSections(ctx, offerings); err != nil {
+		return nil, err
+	}
+	return offerings, nil
+}
+
+func (r *ElectiveRepository) loadSections(ctx context.Context, offerings []models.ElectiveOffering) error {
+	if len(offerings) == 0 {
+		return nil
+	}
+	ids := make([]string, len(offerings))
+	index := make(map[string]int, len(offerings))
+	for i, offering := range offerings {
+		ids[i] = offering.ID
+		index[offering.ID] = i
+		offerings[i].Sections = []models.ElectiveSection{}
+	}
+	var sections []models.ElectiveSection
+	const query = `SELECT id, offering_id, name, teacher_id, capacity, room, lesson_group_id FROM elective_sections WHERE offering_id = ANY($1) ORDER BY name ASC`
+	if err := r.db.SelectContext(ctx, &sections, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("list elective sections: %w", err)
+	}
+	for _, section := range sections {
+		i := index[section.OfferingID]
+		offerings[i].Sections = append(offerings[i].Sections, section)
+	}
+	return nil
+}
+
+// DeleteOffering removes an offering with its sections and selections. It returns sql.ErrNoRows when
+// the offering does not exist.
+func (r *ElectiveRepository) DeleteOffering(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM elective_offerings WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete elective offering: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check elective offering delete rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReplaceSelections replaces an enrollment's choices among offeringIDs, the offerings of one category,
+// with selections.
+func (r *ElectiveRepository) ReplaceSelections(ctx context.Context, enrollmentID string, offeringIDs []string, selections []models.ElectiveSelection) error {
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM elective_selections WHERE enrollment_id = $1 AND offering_id = ANY($2)`, enrollmentID, pq.Array(offeringIDs)); err != nil {
+			return fmt.Errorf("clear elective selections: %w", err)
+		}
+		for _, selection := range selections {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO elective_selections (enrollment_id, offering_id, rank, submitted_at) VALUES ($1, $2, $3, $4)`,
+				enrollmentID, selection.OfferingID, selection.Rank, selection.SubmittedAt); err != nil {
+				return fmt.Errorf("insert elective selection: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListSelections returns the selections of active enrollments for the term's category, ordered by
+// submission time, enrollment and rank.
+func (r *ElectiveRepository) ListSelections(ctx context.Context, filter models.ElectiveSelectionFilter) ([]models.ElectiveSelection, error) {
+	conditions := []string{"o.term_id = $1", "e.status = $2"}
+	args := []interface{}{filter.TermID, models.EnrollmentStatusActive}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conditions = append(conditions, fmt.Sprintf("o.category = $%d", len(args)))
+	}
+	if filter.EnrollmentID != "" {
+		args = append(args, filter.EnrollmentID)
+		conditions = append(conditions, fmt.Sprintf("s.enrollment_id = $%d", len(args)))
+	}
+	query := `SELECT s.enrollment_id, s.offering_id, s.rank, s.submitted_at, e.student_id, e.class_id
+FROM elective_selections s
+JOIN elective_offerings o ON o.id = s.offering_id
+JOIN enrollments e ON e.id = s.enrollment_id
+WHERE ` + strings.Join(conditions, " AND ") + `
+ORDER BY s.submitted_at ASC, s.enrollment_id ASC, s.rank ASC`
+	var selections []models.ElectiveSelection
+	if err := r.db.SelectContext(ctx, &selections, query, args...); err != nil {
+		return nil, fmt.Errorf("list elective selections: %w", err)
+	}
+	return selections, nil
+}
+
+// SaveAllocation stores each section's lesson group, creating the groups of sections allocated for the
+// first time, and replaces the groups' members with the allocated students.
+func (r *ElectiveRepository) SaveAllocation(ctx context.Context, groups []models.ElectiveSectionGroup) error {
+	now := time.Now().UTC()
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		const upsert = `INSERT INTO lesson_groups (id, term_id, subject_id, teacher_id, name, room, created_at, updated_at)
+VALUES (:id, :term_id, :subject_id, :teacher_id, :name, :room, :created_at, :updated_at)
+ON CONFLICT (id) DO UPDATE SET teacher_id = EXCLUDED.teacher_id, name = EXCLUDED.name, room = EXCLUDED.room, updated_at = EXCLUDED.updated_at`
+		for i := range groups {
+			group := &groups[i].Group
+			if group.ID == "" {
+				group.ID = uuid.NewString()
+			}
+			if group.CreatedAt.IsZero() {
+				group.CreatedAt = now
+			}
+			group.UpdatedAt = now
+			if _, err := tx.NamedExecContext(ctx, upsert, group); err != nil {
+				return fmt.Errorf("save elective lesson group: %w", err)
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM lesson_group_classes WHERE group_id = $1`, group.ID); err != nil {
+				return fmt.Errorf("clear elective lesson group members: %w", err)
+			}
+			if err := insertLessonGroupMembers(ctx, tx, group); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `UPDATE elective_sections SET lesson_group_id = $2 WHERE id = $1`, groups[i].SectionID, group.ID); err != nil {
+				return fmt.Errorf("link elective section lesson group: %w", err)
+			}
+		}
+		return nil
+	})
+}
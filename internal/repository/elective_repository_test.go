@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestElectiveRepositoryListSelectionsFilters(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewElectiveRepository(sqlx.NewDb(db, "sqlmock"))
+
+	submitted := time.Date(2026, 7, 1, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM elective_selections s .*WHERE o.term_id = \$1 AND e.status = \$2 AND o.category = \$3 AND s.enrollment_id = \$4`).
+		WithArgs("term-1", models.EnrollmentStatusActive, "IPS", "enr-1").
+		WillReturnRows(sqlmock.NewRows([]string{"enrollment_id", "offering_id", "rank", "submitted_at", "student_id", "class_id"}).
+			AddRow("enr-1", "off-econ", 1, submitted, "student-1", "class-a").
+			AddRow("enr-1", "off-geo", 2, submitted, "student-1", "class-a"))
+
+	selections, err := repo.ListSelections(context.Background(), models.ElectiveSelectionFilter{TermID: "term-1", Category: "IPS", EnrollmentID: "enr-1"})
+	require.NoError(t, err)
+	require.Len(t, selections, 2)
+	assert.Equal(t, 2, selections[1].Rank)
+	assert.Equal(t, "class-a", selections[1].ClassID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestElectiveRepositorySaveAllocation(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewElectiveRepository(sqlx.NewDb(db, "sqlmock"))
+
+	groups := []models.ElectiveSectionGroup{{
+		SectionID: "sec-1",
+		Group: models.LessonGroup{
+			ID: "group-1", TermID: "term-1", SubjectID: "economics", TeacherID: "teacher-1", Name: "Economics A",
+			Members: []models.LessonGroupMember{{ClassID: "class-a", EnrollmentIDs: []string{"enr-1"}}},
+		},
+	}}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO lesson_groups .*ON CONFLICT \(id\) DO UPDATE`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM lesson_group_classes WHERE group_id = \$1`).WithArgs("group-1").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO lesson_group_classes").WithArgs("group-1", "class-a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lesson_group_students").WithArgs("group-1", "class-a", "enr-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE elective_sections SET lesson_group_id = \$2 WHERE id = \$1`).WithArgs("sec-1", "group-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.SaveAllocation(context.Background(), groups))
+	assert.False(t, groups[0].Group.UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
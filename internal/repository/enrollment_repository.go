@@ -190,6 +190,16 @@ func (r *EnrollmentRepository) ListByClassAndTerm(ctx context.Context, classID,
 	return enrollments, nil
 }
 
+// ListClassIDsByTerm returns the classes with active enrollments in a term.
+func (r *EnrollmentRepository) ListClassIDsByTerm(ctx context.Context, termID string) ([]string, error) {
+	const query = `SELECT DISTINCT class_id FROM enrollments WHERE term_id = $1 AND status = $2 ORDER BY class_id`
+	var classIDs []string
+	if err := r.db.SelectContext(ctx, &classIDs, query, termID, models.EnrollmentStatusActive); err != nil {
+		return nil, fmt.Errorf("list term classes: %w", err)
+	}
+	return classIDs, nil
+}
+
 // FindActiveByStudentAndSubject returns the active enrollment for subject operations.
 func (r *EnrollmentRepository) FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error) {
 	const query = `SELECT id, student_id, class_id, term_id, joined_at, left_at, status FROM enrollments WHERE student_id = $1 AND term_id = $2 AND status = $3`
@@ -35,3 +35,18 @@ func TestEnrollmentRepositoryListActiveByStudent(t *testing.T) {
 	require.Len(t, enrollments, 1)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestEnrollmentRepositoryListClassIDsByTerm(t *testing.T) {
+	db, mock, cleanup := newEnrollmentRepoMock(t)
+	defer cleanup()
+	repo := NewEnrollmentRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT class_id FROM enrollments WHERE term_id = $1 AND status = $2 ORDER BY class_id")).
+		WithArgs("term-1", models.EnrollmentStatusActive).
+		WillReturnRows(sqlmock.NewRows([]string{"class_id"}).AddRow("class-1").AddRow("class-2"))
+
+	classIDs, err := repo.ListClassIDsByTerm(context.Background(), "term-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"class-1", "class-2"}, classIDs)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
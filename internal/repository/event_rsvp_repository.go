@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// EventRSVPRepository persists calendar event RSVPs and check-ins.
+type EventRSVPRepository struct {
+	db *sqlx.DB
+}
+
+// NewEventRSVPRepository constructs the repository.
+func NewEventRSVPRepository(db *sqlx.DB) *EventRSVPRepository {
+	return &EventRSVPRepository{db: db}
+}
+
+// SetOptions enables or disables RSVPs for an event and sets its capacity; nil means unlimited.
+func (r *EventRSVPRepository) SetOptions(ctx context.Context, eventID string, enabled bool, capacity *int) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE calendar_events SET rsvp_enabled = $2, rsvp_capacity = $3, updated_at = $4 WHERE id = $1`,
+		eventID, enabled, capacity, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("set event rsvp options: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check event rsvp option rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Respond stores the user's RSVP, replacing an earlier one. The event row is locked while GOING
+// answers are counted, so concurrent RSVPs cannot exceed its capacity; Respond reports false without
+// writing when the event is full. Check-ins already recorded are kept.
+func (r *EventRSVPRepository) Respond(ctx context.Context, rsvp *models.EventRSVP) (accepted bool, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin event rsvp: %w", err)
+	}
+	defer func() {
+		if err != nil || !accepted {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var capacity sql.NullInt64
+	if err = tx.GetContext(ctx, &capacity, `SELECT rsvp_capacity FROM calendar_events WHERE id = $1 FOR UPDATE`, rsvp.EventID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, err
+		}
+		return false, fmt.Errorf("lock calendar event: %w", err)
+	}
+	if rsvp.Response == models.EventRSVPGoing && capacity.Valid {
+		var going int64
+		if err = tx.GetContext(ctx, &going, `SELECT COUNT(*) FROM calendar_event_rsvps WHERE event_id = $1 AND response = $2 AND user_id <> $3`,
+			rsvp.EventID, models.EventRSVPGoing, rsvp.UserID); err != nil {
+			return false, fmt.Errorf("count event rsvps: %w", err)
+		}
+		if going >= capacity.Int64 {
+			return false, nil
+		}
+	}
+	const upsert = `INSERT INTO calendar_event_rsvps (event_id, user_id, response, note, responded_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (event_id, user_id) DO UPDATE SET response = EXCLUDED.response, note = EXCLUDED.note, responded_at = EXCLUDED.responded_at
+RETURNING checked_in_at, checked_in_by`
+	if err = tx.QueryRowxContext(ctx, upsert, rsvp.EventID, rsvp.UserID, rsvp.Response, rsvp.Note, rsvp.RespondedAt).
+		Scan(&rsvp.CheckedInAt, &rsvp.CheckedInBy); err != nil {
+		return false, fmt.Errorf("save event rsvp: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit event rsvp: %w", err)
+	}
+	return true, nil
+}
+
+// CheckIn records that the user attended the event. Users without an RSVP are added as walk-ins;
+// checking in twice keeps the first check-in. The stored RSVP is returned without the user details.
+func (r *EventRSVPRepository) CheckIn(ctx context.Context, eventID, userID, checkedInBy string, at time.Time) (*models.EventRSVP, error) {
+	const query = `INSERT INTO calendar_event_rsvps (event_id, user_id, response, responded_at, checked_in_at, checked_in_by)
+VALUES ($1, $2, $3, $4, $4, $5)
+ON CONFLICT (event_id, user_id) DO UPDATE SET
+    checked_in_at = COALESCE(calendar_event_rsvps.checked_in_at, EXCLUDED.checked_in_at),
+    checked_in_by = COALESCE(calendar_event_rsvps.checked_in_by, EXCLUDED.checked_in_by)
+RETURNING event_id, user_id, response, note, responded_at, checked_in_at, checked_in_by`
+	var rsvp models.EventRSVP
+	if err := r.db.GetContext(ctx, &rsvp, query, eventID, userID, models.EventRSVPWalkIn, at, checkedInBy); err != nil {
+		return nil, fmt.Errorf("check in event attendee: %w", err)
+	}
+	return &rsvp, nil
+}
+
+// List returns the RSVPs and walk-ins of an event ordered by attendee name.
+func (r *EventRSVPRepository) List(ctx context.Context, eventID string) ([]models.EventRSVP, error) {
+	const query = `SELECT r.event_id, r.user_id, u.full_name, u.role, r.response, r.note, r.responded_at, r.checked_in_at, r.checked_in_by
+FROM calendar_event_rsvps r
+JOIN users u ON u.id = r.user_id
+WHERE r.event_id = $1
+ORDER BY u.full_name ASC, r.user_id ASC`
+	var rsvps []models.EventRSVP
+	if err := r.db.SelectContext(ctx, &rsvps, query, eventID); err != nil {
+		return nil, fmt.Errorf("list event rsvps: %w", err)
+	}
+	return rsvps, nil
+}
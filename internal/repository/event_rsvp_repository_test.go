@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestEventRSVPRepositoryRespond(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewEventRSVPRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Date(2026, 5, 10, 8, 0, 0, 0, time.UTC)
+	rsvp := &models.EventRSVP{EventID: "event-1", UserID: "guardian-1", Response: models.EventRSVPGoing, RespondedAt: now}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT rsvp_capacity FROM calendar_events WHERE id = $1 FOR UPDATE")).
+		WithArgs("event-1").
+		WillReturnRows(sqlmock.NewRows([]string{"rsvp_capacity"}).AddRow(30))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM calendar_event_rsvps WHERE event_id = $1 AND response = $2 AND user_id <> $3")).
+		WithArgs("event-1", models.EventRSVPGoing, "guardian-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery(`INSERT INTO calendar_event_rsvps .*ON CONFLICT \(event_id, user_id\) DO UPDATE`).
+		WithArgs("event-1", "guardian-1", models.EventRSVPGoing, nil, now).
+		WillReturnRows(sqlmock.NewRows([]string{"checked_in_at", "checked_in_by"}).AddRow(nil, nil))
+	mock.ExpectCommit()
+
+	accepted, err := repo.Respond(context.Background(), rsvp)
+	require.NoError(t, err)
+	assert.True(t, accepted)
+
+	// A full event is rolled back without writing.
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT rsvp_capacity FROM calendar_events").
+		WillReturnRows(sqlmock.NewRows([]string{"rsvp_capacity"}).AddRow(12))
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectRollback()
+
+	accepted, err = repo.Respond(context.Background(), rsvp)
+	require.NoError(t, err)
+	assert.False(t, accepted)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT rsvp_capacity FROM calendar_events").
+		WillReturnRows(sqlmock.NewRows([]string{"rsvp_capacity"}))
+	mock.ExpectRollback()
+
+	_, err = repo.Respond(context.Background(), rsvp)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEventRSVPRepositoryCheckIn(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewEventRSVPRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Date(2026, 5, 20, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO calendar_event_rsvps .*checked_in_at = COALESCE\(calendar_event_rsvps.checked_in_at, EXCLUDED.checked_in_at\)`).
+		WithArgs("event-1", "guardian-9", models.EventRSVPWalkIn, now, "teacher-1").
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "user_id", "response", "note", "responded_at", "checked_in_at", "checked_in_by"}).
+			AddRow("event-1", "guardian-9", "WALK_IN", nil, now, now, "teacher-1"))
+
+	rsvp, err := repo.CheckIn(context.Background(), "event-1", "guardian-9", "teacher-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventRSVPWalkIn, rsvp.Response)
+	require.NotNil(t, rsvp.CheckedInAt)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE calendar_events SET rsvp_enabled = $2, rsvp_capacity = $3")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.SetOptions(context.Background(), "missing", true, nil)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
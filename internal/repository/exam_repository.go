@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const examScheduleColumns = `id, period_id, class_id, subject_id, exam_date, start_slot, end_slot, room, invigilator_id, created_at, updated_at`
+
+// ExamRepository persists exam periods and their sittings.
+type ExamRepository struct {
+	db *sqlx.DB
+}
+
+// NewExamRepository constructs the repository.
+func NewExamRepository(db *sqlx.DB) *ExamRepository {
+	return &ExamRepository{db: db}
+}
+
+// ListPeriods returns the exam periods of a term ordered by start date.
+func (r *ExamRepository) ListPeriods(ctx context.Context, termID string) ([]models.ExamPeriod, error) {
+	const query = `SELECT id, term_id, name, start_date, end_date, created_at, updated_at
+FROM exam_periods WHERE term_id = $1 ORDER BY start_date ASC`
+	var periods []models.ExamPeriod
+	if err := r.db.SelectContext(ctx, &periods, query, termID); err != nil {
+		return nil, fmt.Errorf("list exam periods: %w", err)
+	}
+	return periods, nil
+}
+
+// FindPeriodByID fetches an exam period.
+func (r *ExamRepository) FindPeriodByID(ctx context.Context, id string) (*models.ExamPeriod, error) {
+	const query = `SELECT id, term_id, name, start_date, end_date, created_at, updated_at FROM exam_periods WHERE id = $1`
+	var period models.ExamPeriod
+	if err := r.db.GetContext(ctx, &period, query, id); err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// CreatePeriod inserts an exam period.
+func (r *ExamRepository) CreatePeriod(ctx context.Context, period *models.ExamPeriod) error {
+	if period.ID == "" {
+		period.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if period.CreatedAt.IsZero() {
+		period.CreatedAt = now
+	}
+	period.UpdatedAt = now
+	const query = `INSERT INTO exam_periods (id, term_id, name, start_date, end_date, created_at, updated_at)
+VALUES (:id, :term_id, :name, :start_date, :end_date, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, period); err != nil {
+		return fmt.Errorf("create exam period: %w", err)
+	}
+	return nil
+}
+
+// DeletePeriod removes an exam period and, through the cascade, its sittings.
+func (r *ExamRepository) DeletePeriod(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM exam_periods WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete exam period: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check exam period rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// List returns sittings matching the filter ordered by date, slot and class.
+func (r *ExamRepository) List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+	add := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.PeriodID != "" {
+		add("period_id", filter.PeriodID)
+	}
+	if filter.ClassID != "" {
+		add("class_id", filter.ClassID)
+	}
+	if filter.Room != "" {
+		add("LOWER(room)", strings.ToLower(filter.Room))
+	}
+	if filter.InvigilatorID != "" {
+		add("invigilator_id", filter.InvigilatorID)
+	}
+	if filter.Date != nil {
+		add("exam_date", *filter.Date)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM exam_schedules WHERE %s ORDER BY exam_date ASC, start_slot ASC, class_id ASC`,
+		examScheduleColumns, strings.Join(conditions, " AND "))
+	var exams []models.ExamSchedule
+	if err := r.db.SelectContext(ctx, &exams, query, args...); err != nil {
+		return nil, fmt.Errorf("list exam schedules: %w", err)
+	}
+	return exams, nil
+}
+
+// FindByID fetches a sitting.
+func (r *ExamRepository) FindByID(ctx context.Context, id string) (*models.ExamSchedule, error) {
+	var exam models.ExamSchedule
+	if err := r.db.GetContext(ctx, &exam, `SELECT `+examScheduleColumns+` FROM exam_schedules WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &exam, nil
+}
+
+// CreateBatch inserts sittings within a single transaction.
+func (r *ExamRepository) CreateBatch(ctx context.Context, exams []models.ExamSchedule) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin create exam schedules: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now().UTC()
+	for i := range exams {
+		if exams[i].ID == "" {
+			exams[i].ID = uuid.NewString()
+		}
+		if exams[i].CreatedAt.IsZero() {
+			exams[i].CreatedAt = now
+		}
+		exams[i].UpdatedAt = now
+		if _, err = tx.NamedExecContext(ctx, `INSERT INTO exam_schedules (`+examScheduleColumns+`)
+VALUES (:id, :period_id, :class_id, :subject_id, :exam_date, :start_slot, :end_slot, :room, :invigilator_id, :created_at, :updated_at)`, &exams[i]); err != nil {
+			return fmt.Errorf("create exam schedule: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit exam schedules: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a sitting, returning sql.ErrNoRows when it does not exist.
+func (r *ExamRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM exam_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete exam schedule: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check exam schedule rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
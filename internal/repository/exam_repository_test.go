@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestExamRepositoryListFilters(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewExamRepository(db)
+
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "period_id", "class_id", "subject_id", "exam_date", "start_slot", "end_slot", "room", "invigilator_id", "created_at", "updated_at"}).
+		AddRow("exam-1", "period-1", "class-1", "math", date, 1, 2, "R1", "t1", date, date)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM exam_schedules WHERE 1=1 AND period_id = $1 AND LOWER(room) = $2 AND exam_date = $3 ORDER BY exam_date ASC")).
+		WithArgs("period-1", "r1", date).
+		WillReturnRows(rows)
+
+	exams, err := repo.List(context.Background(), models.ExamScheduleFilter{PeriodID: "period-1", Room: "R1", Date: &date})
+	require.NoError(t, err)
+	require.Len(t, exams, 1)
+	assert.Equal(t, 2, exams[0].EndSlot)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExamRepositoryDeleteMissing(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewExamRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM exam_schedules WHERE id = $1")).
+		WithArgs("exam-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), "exam-9")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
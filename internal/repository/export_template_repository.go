@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const exportTemplateColumns = `id, report_type, name, columns, group_by, updated_by, created_at, updated_at`
+
+// ExportTemplateRepository persists the export layouts configured per report type.
+type ExportTemplateRepository struct {
+	db *sqlx.DB
+}
+
+// NewExportTemplateRepository constructs the repository.
+func NewExportTemplateRepository(db *sqlx.DB) *ExportTemplateRepository {
+	return &ExportTemplateRepository{db: db}
+}
+
+// List returns every export template ordered by report type.
+func (r *ExportTemplateRepository) List(ctx context.Context) ([]models.ExportTemplate, error) {
+	query := `SELECT ` + exportTemplateColumns + ` FROM export_templates ORDER BY report_type ASC`
+	var templates []models.ExportTemplate
+	if err := r.db.SelectContext(ctx, &templates, query); err != nil {
+		return nil, fmt.Errorf("list export templates: %w", err)
+	}
+	return templates, nil
+}
+
+// FindByID returns a template by its identifier.
+func (r *ExportTemplateRepository) FindByID(ctx context.Context, id string) (*models.ExportTemplate, error) {
+	query := `SELECT ` + exportTemplateColumns + ` FROM export_templates WHERE id = $1`
+	var template models.ExportTemplate
+	if err := r.db.GetContext(ctx, &template, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("find export template: %w", err)
+	}
+	return &template, nil
+}
+
+// FindByReportType returns the template applied to exports of a report type.
+func (r *ExportTemplateRepository) FindByReportType(ctx context.Context, reportType models.ReportType) (*models.ExportTemplate, error) {
+	query := `SELECT ` + exportTemplateColumns + ` FROM export_templates WHERE report_type = $1`
+	var template models.ExportTemplate
+	if err := r.db.GetContext(ctx, &template, query, reportType); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("find export template by report type: %w", err)
+	}
+	return &template, nil
+}
+
+// Create inserts a new template.
+func (r *ExportTemplateRepository) Create(ctx context.Context, template *models.ExportTemplate) error {
+	if template.ID == "" {
+		template.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	query := `INSERT INTO export_templates (` + exportTemplateColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	if _, err := r.db.ExecContext(ctx, query, template.ID, template.ReportType, template.Name, template.Columns,
+		template.GroupBy, template.UpdatedBy, template.CreatedAt, template.UpdatedAt); err != nil {
+		return fmt.Errorf("create export template: %w", err)
+	}
+	return nil
+}
+
+// Update replaces the layout of an existing template.
+func (r *ExportTemplateRepository) Update(ctx context.Context, template *models.ExportTemplate) error {
+	template.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE export_templates SET report_type = $2, name = $3, columns = $4, group_by = $5,
+    updated_by = $6, updated_at = $7 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, template.ID, template.ReportType, template.Name, template.Columns,
+		template.GroupBy, template.UpdatedBy, template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update export template: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check export template rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete removes a template so its report type falls back to the default layout.
+func (r *ExportTemplateRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM export_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete export template: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check export template rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
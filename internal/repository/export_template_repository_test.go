@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestExportTemplateRepositoryFindByReportTypeAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewExportTemplateRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "report_type", "name", "columns", "group_by", "updated_by", "created_at", "updated_at"}).
+		AddRow("tpl-1", "grades", "Nilai", `[{"key":"subjectId"}]`, "subjectId", "admin-1", now, now)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM export_templates WHERE report_type = $1")).
+		WithArgs(models.ReportTypeGrades).
+		WillReturnRows(rows)
+
+	template, err := repo.FindByReportType(context.Background(), models.ReportTypeGrades)
+	require.NoError(t, err)
+	assert.Equal(t, "tpl-1", template.ID)
+	assert.Equal(t, "subjectId", *template.GroupBy)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM export_templates WHERE id = $1")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fuzzyMinSimilarity is the minimum pg_trgm word similarity between a list search term and a name.
+// 0.3 still matches names with a typo or two, such as "Siti Nurhaliza" for "sitti nurhalisa".
+const fuzzyMinSimilarity = 0.3
+
+// queryWithFuzzySearch runs fn against the database, or when fuzzy is set, in a read-only transaction
+// whose word similarity threshold is fuzzyMinSimilarity so `term <% column` in fn's queries is served
+// by the trigram indexes.
+func queryWithFuzzySearch(ctx context.Context, db *sqlx.DB, fuzzy bool, fn func(q sqlx.QueryerContext) error) (err error) {
+	if !fuzzy {
+		return fn(db)
+	}
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("begin fuzzy search: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	threshold := strconv.FormatFloat(fuzzyMinSimilarity, 'f', -1, 64)
+	if _, err = tx.ExecContext(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, threshold); err != nil {
+		return fmt.Errorf("set fuzzy search threshold: %w", err)
+	}
+	if err = fn(tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit fuzzy search: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// gradeAppealStatus derives the status clients see: appeals forwarded to the super admins take the
+// outcome of their mutation.
+const gradeAppealStatus = `CASE WHEN a.status = 'TEACHER_REVIEW' THEN 'TEACHER_REVIEW'
+    WHEN m.status = 'APPROVED' THEN 'APPROVED'
+    WHEN m.status = 'REJECTED' THEN 'REJECTED'
+    ELSE 'ADMIN_REVIEW' END`
+
+const gradeAppealSelect = `SELECT a.id, a.grade_final_id, gf.enrollment_id, a.student_id, s.full_name AS student_name, e.class_id,
+    gf.subject_id, sub.name AS subject_name, e.term_id, a.teacher_id, t.full_name AS teacher_name, a.submitted_by, a.reason,
+    a.original_grade, a.requested_grade, gf.final_grade AS current_grade, ` + gradeAppealStatus + ` AS status,
+    a.teacher_due_at, a.recommendation, a.proposed_grade, a.teacher_note, a.forwarded_at, a.admin_due_at, a.mutation_id,
+    m.reviewed_at AS decided_at, m.note AS decision_note, a.created_at, a.updated_at
+FROM grade_appeals a
+JOIN grade_finals gf ON gf.id = a.grade_final_id
+JOIN enrollments e ON e.id = gf.enrollment_id
+JOIN students s ON s.id = a.student_id
+JOIN subjects sub ON sub.id = gf.subject_id
+LEFT JOIN teachers t ON t.id = a.teacher_id
+LEFT JOIN mutations m ON m.id = a.mutation_id`
+
+// ForwardGradeAppealParams records the teacher stage's outcome when an appeal moves to the super admins.
+type ForwardGradeAppealParams struct {
+	ID             string
+	Recommendation models.GradeAppealRecommendation
+	ProposedGrade  *float64
+	TeacherNote    *string
+	ForwardedAt    time.Time
+	AdminDueAt     time.Time
+}
+
+// GradeAppealRepository persists grade appeals.
+type GradeAppealRepository struct {
+	db *sqlx.DB
+}
+
+// NewGradeAppealRepository constructs the repository.
+func NewGradeAppealRepository(db *sqlx.DB) *GradeAppealRepository {
+	return &GradeAppealRepository{db: db}
+}
+
+// Target loads the student's final grade in a subject and term with the class's subject teacher.
+// When several teachers share the subject, the one assigned first is returned.
+func (r *GradeAppealRepository) Target(ctx context.Context, studentID models.StudentID, termID models.TermID, subjectID models.SubjectID) (*models.GradeAppealTarget, error) {
+	const query = `SELECT gf.id AS grade_final_id, gf.enrollment_id, e.class_id, gf.final_grade, gf.finalized,
+    (SELECT ta.teacher_id FROM teacher_assignments ta
+     WHERE ta.class_id = e.class_id AND ta.subject_id = gf.subject_id AND ta.term_id = e.term_id AND ta.role = 'SUBJECT_TEACHER'
+     ORDER BY ta.created_at, ta.id LIMIT 1) AS teacher_id
+FROM grade_finals gf
+JOIN enrollments e ON e.id = gf.enrollment_id
+WHERE e.student_id = $1 AND e.term_id = $2 AND gf.subject_id = $3
+ORDER BY e.joined_at DESC
+LIMIT 1`
+	var target models.GradeAppealTarget
+	if err := r.db.GetContext(ctx, &target, query, studentID, termID, subjectID); err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+// HasOpen reports whether the final grade has an appeal awaiting a decision.
+func (r *GradeAppealRepository) HasOpen(ctx context.Context, gradeFinalID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM grade_appeals a LEFT JOIN mutations m ON m.id = a.mutation_id
+WHERE a.grade_final_id = $1 AND ` + gradeAppealStatus + ` IN ('TEACHER_REVIEW', 'ADMIN_REVIEW'))`
+	var open bool
+	if err := r.db.GetContext(ctx, &open, query, gradeFinalID); err != nil {
+		return false, fmt.Errorf("check open grade appeal: %w", err)
+	}
+	return open, nil
+}
+
+// Create stores a new appeal awaiting its teacher.
+func (r *GradeAppealRepository) Create(ctx context.Context, appeal *models.GradeAppeal) error {
+	if appeal.ID == "" {
+		appeal.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	appeal.CreatedAt, appeal.UpdatedAt = now, now
+	const query = `INSERT INTO grade_appeals (id, grade_final_id, student_id, teacher_id, submitted_by, reason, original_grade, requested_grade,
+    status, teacher_due_at, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'TEACHER_REVIEW', $9, $10, $11)`
+	if _, err := r.db.ExecContext(ctx, query, appeal.ID, appeal.GradeFinalID, appeal.StudentID, appeal.TeacherID, appeal.SubmittedBy,
+		appeal.Reason, appeal.OriginalGrade, appeal.RequestedGrade, appeal.TeacherDueAt, appeal.CreatedAt, appeal.UpdatedAt); err != nil {
+		return fmt.Errorf("create grade appeal: %w", err)
+	}
+	return nil
+}
+
+// FindByID loads an appeal with its derived status.
+func (r *GradeAppealRepository) FindByID(ctx context.Context, id string) (*models.GradeAppeal, error) {
+	var appeal models.GradeAppeal
+	if err := r.db.GetContext(ctx, &appeal, gradeAppealSelect+` WHERE a.id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
+
+// List returns appeals matching the filter, newest first.
+func (r *GradeAppealRepository) List(ctx context.Context, filter models.GradeAppealFilter) ([]models.GradeAppeal, error) {
+	where := []string{"1=1"}
+	var args []interface{}
+	if filter.StudentID != "" {
+		args = append(args, filter.StudentID)
+		where = append(where, fmt.Sprintf("a.student_id = $%d", len(args)))
+	}
+	if filter.TeacherID != "" {
+		args = append(args, filter.TeacherID)
+		where = append(where, fmt.Sprintf("a.teacher_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("%s = $%d", gradeAppealStatus, len(args)))
+	}
+	query := gradeAppealSelect + ` WHERE ` + strings.Join(where, " AND ") + ` ORDER BY a.created_at DESC, a.id`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	appeals := []models.GradeAppeal{}
+	if err := r.db.SelectContext(ctx, &appeals, query, args...); err != nil {
+		return nil, fmt.Errorf("list grade appeals: %w", err)
+	}
+	return appeals, nil
+}
+
+// Forward closes the teacher stage. It returns sql.ErrNoRows when the appeal does not exist or was
+// forwarded in the meantime.
+func (r *GradeAppealRepository) Forward(ctx context.Context, params ForwardGradeAppealParams) error {
+	const query = `UPDATE grade_appeals SET status = 'FORWARDED', recommendation = $2, proposed_grade = $3, teacher_note = $4,
+    forwarded_at = $5, admin_due_at = $6, updated_at = $5
+WHERE id = $1 AND status = 'TEACHER_REVIEW'`
+	res, err := r.db.ExecContext(ctx, query, params.ID, params.Recommendation, params.ProposedGrade, params.TeacherNote, params.ForwardedAt, params.AdminDueAt)
+	if err != nil {
+		return fmt.Errorf("forward grade appeal: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check forwarded grade appeal rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetMutation links a forwarded appeal to the mutation filed for it.
+func (r *GradeAppealRepository) SetMutation(ctx context.Context, id, mutationID string) error {
+	const query = `UPDATE grade_appeals SET mutation_id = $2, updated_at = $3 WHERE id = $1 AND mutation_id IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, id, mutationID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("link grade appeal mutation: %w", err)
+	}
+	return nil
+}
+
+// ListTeacherOverdue returns appeals whose teacher did not answer by now.
+func (r *GradeAppealRepository) ListTeacherOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error) {
+	return r.listWhere(ctx, "list overdue teacher appeals", `a.status = 'TEACHER_REVIEW' AND a.teacher_due_at <= $1`, limit, now)
+}
+
+// ListUnfiled returns forwarded appeals whose mutation has not been filed yet.
+func (r *GradeAppealRepository) ListUnfiled(ctx context.Context, limit int) ([]models.GradeAppeal, error) {
+	return r.listWhere(ctx, "list unfiled grade appeals", `a.status = 'FORWARDED' AND a.mutation_id IS NULL`, limit)
+}
+
+// ListAdminOverdue returns appeals still awaiting the super admins past their deadline whose delay
+// has not been reported yet.
+func (r *GradeAppealRepository) ListAdminOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error) {
+	return r.listWhere(ctx, "list overdue admin appeals",
+		`a.status = 'FORWARDED' AND `+gradeAppealStatus+` = 'ADMIN_REVIEW' AND a.admin_due_at <= $1 AND a.overdue_notified_at IS NULL`, limit, now)
+}
+
+// ListUnannounced returns decided appeals whose appellant has not been told the outcome.
+func (r *GradeAppealRepository) ListUnannounced(ctx context.Context, limit int) ([]models.GradeAppeal, error) {
+	return r.listWhere(ctx, "list unannounced grade appeals",
+		`a.status = 'FORWARDED' AND m.status IN ('APPROVED', 'REJECTED') AND a.outcome_notified_at IS NULL`, limit)
+}
+
+// MarkOverdueNotified records that the super admins were told about the overdue appeal.
+func (r *GradeAppealRepository) MarkOverdueNotified(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE grade_appeals SET overdue_notified_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("mark grade appeal overdue notified: %w", err)
+	}
+	return nil
+}
+
+// MarkOutcomeNotified records that the appellant was told the outcome.
+func (r *GradeAppealRepository) MarkOutcomeNotified(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE grade_appeals SET outcome_notified_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("mark grade appeal outcome notified: %w", err)
+	}
+	return nil
+}
+
+func (r *GradeAppealRepository) listWhere(ctx context.Context, op, where string, limit int, args ...interface{}) ([]models.GradeAppeal, error) {
+	query := gradeAppealSelect + ` WHERE ` + where + ` ORDER BY a.created_at, a.id`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	appeals := []models.GradeAppeal{}
+	if err := r.db.SelectContext(ctx, &appeals, query, args...); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return appeals, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestGradeAppealRepositoryForward(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewGradeAppealRepository(sqlx.NewDb(db, "sqlmock"))
+
+	at := time.Date(2026, 6, 20, 8, 0, 0, 0, time.UTC)
+	proposed := 76.0
+	params := ForwardGradeAppealParams{ID: "appeal-1", Recommendation: models.GradeAppealSupport, ProposedGrade: &proposed, ForwardedAt: at, AdminDueAt: at.AddDate(0, 0, 7)}
+	mock.ExpectExec(`UPDATE grade_appeals SET status = 'FORWARDED'.*WHERE id = \$1 AND status = 'TEACHER_REVIEW'`).
+		WithArgs("appeal-1", models.GradeAppealSupport, &proposed, nil, at, at.AddDate(0, 0, 7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Forward(context.Background(), params))
+
+	// An appeal forwarded in the meantime no longer matches.
+	mock.ExpectExec("UPDATE grade_appeals SET status = 'FORWARDED'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Forward(context.Background(), params), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGradeAppealRepositoryListDerivesStatusFromMutation(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewGradeAppealRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHEN m.status = 'APPROVED' THEN 'APPROVED'")+`.*`+
+		regexp.QuoteMeta("WHERE 1=1 AND a.student_id = $1 AND CASE")).
+		WithArgs("student-1", models.GradeAppealApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "grade_final_id", "student_id", "status", "current_grade", "original_grade"}).
+			AddRow("appeal-1", "gf-1", "student-1", "APPROVED", 80.0, 68.0))
+
+	appeals, err := repo.List(context.Background(), models.GradeAppealFilter{StudentID: "student-1", Status: models.GradeAppealApproved})
+	require.NoError(t, err)
+	require.Len(t, appeals, 1)
+	assert.Equal(t, models.GradeAppealApproved, appeals[0].Status)
+	assert.False(t, appeals[0].Open())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -24,7 +24,7 @@ func NewGradeConfigRepository(db *sqlx.DB) *GradeConfigRepository {
 
 // List returns grade configs matching the provided filters.
 func (r *GradeConfigRepository) List(ctx context.Context, filter models.FinalGradeFilter) ([]models.GradeConfig, error) {
-	query := `SELECT id, class_id, subject_id, term_id, calculation_scheme, finalized, created_at, updated_at
+	query := `SELECT id, class_id, subject_id, term_id, calculation_scheme, drop_lowest, best_n, rounding_mode, rounding_places, round_to_integer, kkm, finalized, created_at, updated_at
         FROM grade_configs WHERE 1=1`
 	args := []interface{}{}
 	if filter.ClassID != "" {
@@ -57,7 +57,7 @@ func (r *GradeConfigRepository) List(ctx context.Context, filter models.FinalGra
 
 // FindByID returns a grade config by ID with components.
 func (r *GradeConfigRepository) FindByID(ctx context.Context, id string) (*models.GradeConfig, error) {
-	const query = `SELECT id, class_id, subject_id, term_id, calculation_scheme, finalized, created_at, updated_at FROM grade_configs WHERE id = $1`
+	const query = `SELECT id, class_id, subject_id, term_id, calculation_scheme, drop_lowest, best_n, rounding_mode, rounding_places, round_to_integer, kkm, finalized, created_at, updated_at FROM grade_configs WHERE id = $1`
 	var config models.GradeConfig
 	if err := r.db.GetContext(ctx, &config, query, id); err != nil {
 		return nil, err
@@ -71,8 +71,8 @@ func (r *GradeConfigRepository) FindByID(ctx context.Context, id string) (*model
 }
 
 // FindByScope retrieves a config using class+subject+term combination.
-func (r *GradeConfigRepository) FindByScope(ctx context.Context, classID, subjectID, termID string) (*models.GradeConfig, error) {
-	const query = `SELECT id, class_id, subject_id, term_id, calculation_scheme, finalized, created_at, updated_at FROM grade_configs WHERE class_id = $1 AND subject_id = $2 AND term_id = $3`
+func (r *GradeConfigRepository) FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error) {
+	const query = `SELECT id, class_id, subject_id, term_id, calculation_scheme, drop_lowest, best_n, rounding_mode, rounding_places, round_to_integer, kkm, finalized, created_at, updated_at FROM grade_configs WHERE class_id = $1 AND subject_id = $2 AND term_id = $3`
 	var config models.GradeConfig
 	if err := r.db.GetContext(ctx, &config, query, classID, subjectID, termID); err != nil {
 		return nil, err
@@ -86,7 +86,7 @@ func (r *GradeConfigRepository) FindByScope(ctx context.Context, classID, subjec
 }
 
 // Exists checks if config exists for scope, excluding optional ID.
-func (r *GradeConfigRepository) Exists(ctx context.Context, classID, subjectID, termID, excludeID string) (bool, error) {
+func (r *GradeConfigRepository) Exists(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID, excludeID string) (bool, error) {
 	query := "SELECT 1 FROM grade_configs WHERE class_id = $1 AND subject_id = $2 AND term_id = $3"
 	args := []interface{}{classID, subjectID, termID}
 	if excludeID != "" {
@@ -128,8 +128,8 @@ func (r *GradeConfigRepository) createTx(ctx context.Context, tx *sqlx.Tx, confi
 		config.CreatedAt = now
 	}
 	config.UpdatedAt = now
-	const insertConfig = `INSERT INTO grade_configs (id, class_id, subject_id, term_id, calculation_scheme, finalized, created_at, updated_at)
-        VALUES (:id, :class_id, :subject_id, :term_id, :calculation_scheme, :finalized, :created_at, :updated_at)`
+	const insertConfig = `INSERT INTO grade_configs (id, class_id, subject_id, term_id, calculation_scheme, drop_lowest, best_n, rounding_mode, rounding_places, round_to_integer, kkm, finalized, created_at, updated_at)
+        VALUES (:id, :class_id, :subject_id, :term_id, :calculation_scheme, :drop_lowest, :best_n, :rounding_mode, :rounding_places, :round_to_integer, :kkm, :finalized, :created_at, :updated_at)`
 	if _, err := tx.NamedExecContext(ctx, insertConfig, config); err != nil {
 		return fmt.Errorf("insert grade config: %w", err)
 	}
@@ -139,14 +139,16 @@ func (r *GradeConfigRepository) createTx(ctx context.Context, tx *sqlx.Tx, confi
 	return nil
 }
 
-// Update applies changes to config metadata and components.
+// Update applies changes to config metadata and components. The below_kkm flags of the scope's
+// final grades are recalculated in the same transaction, so a changed or cleared KKM applies to
+// finals computed before it.
 func (r *GradeConfigRepository) Update(ctx context.Context, config *models.GradeConfig) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	config.UpdatedAt = time.Now().UTC()
-	const updateQuery = `UPDATE grade_configs SET calculation_scheme = :calculation_scheme, finalized = :finalized, updated_at = :updated_at WHERE id = :id`
+	const updateQuery = `UPDATE grade_configs SET calculation_scheme = :calculation_scheme, drop_lowest = :drop_lowest, best_n = :best_n, rounding_mode = :rounding_mode, rounding_places = :rounding_places, round_to_integer = :round_to_integer, kkm = :kkm, finalized = :finalized, updated_at = :updated_at WHERE id = :id`
 	if _, err := tx.NamedExecContext(ctx, updateQuery, config); err != nil {
 		tx.Rollback() //nolint:errcheck
 		return fmt.Errorf("update grade config: %w", err)
@@ -155,6 +157,13 @@ func (r *GradeConfigRepository) Update(ctx context.Context, config *models.Grade
 		tx.Rollback() //nolint:errcheck
 		return err
 	}
+	const flagQuery = `UPDATE grade_finals gf SET below_kkm = COALESCE(gf.final_grade < $4, FALSE)
+        FROM enrollments e
+        WHERE e.id = gf.enrollment_id AND e.class_id = $1 AND e.term_id = $2 AND gf.subject_id = $3`
+	if _, err := tx.ExecContext(ctx, flagQuery, config.ClassID, config.TermID, config.SubjectID, config.KKM); err != nil {
+		tx.Rollback() //nolint:errcheck
+		return fmt.Errorf("flag finals below kkm: %w", err)
+	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit grade config: %w", err)
 	}
@@ -178,8 +187,8 @@ func (r *GradeConfigRepository) replaceComponentsTx(ctx context.Context, tx *sql
 	if len(components) == 0 {
 		return nil
 	}
-	const insertComponent = `INSERT INTO grade_config_components (id, grade_config_id, component_id, weight)
-        VALUES (:id, :grade_config_id, :component_id, :weight)`
+	const insertComponent = `INSERT INTO grade_config_components (id, grade_config_id, component_id, weight, min_grades)
+        VALUES (:id, :grade_config_id, :component_id, :weight, :min_grades)`
 	for i := range components {
 		if components[i].ID == "" {
 			components[i].ID = uuid.NewString()
@@ -193,7 +202,7 @@ func (r *GradeConfigRepository) replaceComponentsTx(ctx context.Context, tx *sql
 }
 
 func (r *GradeConfigRepository) loadComponents(ctx context.Context, configID string) ([]models.GradeConfigComponent, error) {
-	const query = `SELECT gcc.id, gcc.grade_config_id, gcc.component_id, gcc.weight, gc.code AS component_code, gc.name AS component_name, gcc.created_at
+	const query = `SELECT gcc.id, gcc.grade_config_id, gcc.component_id, gcc.weight, gc.code AS component_code, gc.name AS component_name, gcc.min_grades, gcc.created_at
         FROM grade_config_components gcc
         JOIN grade_components gc ON gc.id = gcc.component_id
         WHERE gcc.grade_config_id = $1 ORDER BY gc.code`
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestGradeConfigRepositoryUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewGradeConfigRepository(sqlx.NewDb(db, "sqlmock"))
+
+	kkm := 75.0
+	config := &models.GradeConfig{ID: "cfg-1", ClassID: "class-1", SubjectID: "sub-1", TermID: "term-1", CalculationScheme: models.GradeSchemeAverage, RoundingMode: models.GradeRoundingHalfUp, RoundingPlaces: 2, RoundToInteger: true, KKM: &kkm,
+		Components: []models.GradeConfigComponent{{ComponentID: "comp-1", Weight: 100, MinGrades: 2}}}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE grade_configs SET calculation_scheme = \?, drop_lowest = \?, best_n = \?, rounding_mode = \?, rounding_places = \?, round_to_integer = \?, kkm = \?, finalized = \?, updated_at = \? WHERE id = \?$`).
+		WithArgs(models.GradeSchemeAverage, 0, 0, models.GradeRoundingHalfUp, 2, true, kkm, false, sqlmock.AnyArg(), "cfg-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM grade_config_components WHERE grade_config_id = \$1`).WithArgs("cfg-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO grade_config_components \(id, grade_config_id, component_id, weight, min_grades\)`).
+		WithArgs(sqlmock.AnyArg(), "cfg-1", "comp-1", 100.0, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE grade_finals gf SET below_kkm = COALESCE\(gf.final_grade < \$4, FALSE\)`).
+		WithArgs("class-1", "term-1", "sub-1", kkm).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Update(context.Background(), config))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGradeConfigRepositoryUpdateClearsBelowKKM(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewGradeConfigRepository(sqlx.NewDb(db, "sqlmock"))
+
+	config := &models.GradeConfig{ID: "cfg-1", ClassID: "class-1", SubjectID: "sub-1", TermID: "term-1", CalculationScheme: models.GradeSchemeAverage}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE grade_configs SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM grade_config_components`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE grade_finals gf SET below_kkm`).WithArgs("class-1", "term-1", "sub-1", nil).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Update(context.Background(), config))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
@@ -22,19 +23,41 @@ func NewGradeFinalRepository(db *sqlx.DB) *GradeFinalRepository {
 	return &GradeFinalRepository{db: db}
 }
 
-// Upsert bulk upserts final grades.
-func (r *GradeFinalRepository) Upsert(ctx context.Context, finals []models.GradeFinal) error {
+func (r *GradeFinalRepository) exec(exec sqlx.ExtContext) sqlx.ExtContext {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+// Upsert bulk upserts final grades. Without exec the finals are written in their own transaction;
+// with exec the caller owns the transaction.
+func (r *GradeFinalRepository) Upsert(ctx context.Context, exec sqlx.ExtContext, finals []models.GradeFinal) error {
 	if len(finals) == 0 {
 		return nil
 	}
+	if exec != nil {
+		return r.upsert(ctx, exec, finals)
+	}
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	const query = `INSERT INTO grade_finals (id, enrollment_id, subject_id, final_grade, finalized, calculated_at, calculation_note)
-        VALUES (:id, :enrollment_id, :subject_id, :final_grade, :finalized, :calculated_at, :calculation_note)
+	if err := r.upsert(ctx, tx, finals); err != nil {
+		tx.Rollback() //nolint:errcheck
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit final grades: %w", err)
+	}
+	return nil
+}
+
+func (r *GradeFinalRepository) upsert(ctx context.Context, exec sqlx.ExtContext, finals []models.GradeFinal) error {
+	const query = `INSERT INTO grade_finals (id, enrollment_id, subject_id, final_grade, finalized, calculated_at, calculation_note, below_kkm)
+        VALUES (:id, :enrollment_id, :subject_id, :final_grade, :finalized, :calculated_at, :calculation_note, :below_kkm)
         ON CONFLICT (enrollment_id, subject_id)
-        DO UPDATE SET final_grade = EXCLUDED.final_grade, finalized = EXCLUDED.finalized, calculated_at = EXCLUDED.calculated_at, calculation_note = EXCLUDED.calculation_note`
+        DO UPDATE SET final_grade = EXCLUDED.final_grade, finalized = EXCLUDED.finalized, calculated_at = EXCLUDED.calculated_at, calculation_note = EXCLUDED.calculation_note, below_kkm = EXCLUDED.below_kkm`
 	now := time.Now().UTC()
 	for i := range finals {
 		if finals[i].ID == "" {
@@ -43,19 +66,15 @@ func (r *GradeFinalRepository) Upsert(ctx context.Context, finals []models.Grade
 		if finals[i].CalculatedAt.IsZero() {
 			finals[i].CalculatedAt = now
 		}
-		if _, err := tx.NamedExecContext(ctx, query, finals[i]); err != nil {
-			tx.Rollback() //nolint:errcheck
+		if _, err := sqlx.NamedExecContext(ctx, exec, query, finals[i]); err != nil {
 			return fmt.Errorf("upsert final grade: %w", err)
 		}
 	}
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit final grades: %w", err)
-	}
 	return nil
 }
 
 // SetFinalized toggles finalized flag for finals in scope.
-func (r *GradeFinalRepository) SetFinalized(ctx context.Context, enrollmentIDs []string, subjectID string, finalized bool) error {
+func (r *GradeFinalRepository) SetFinalized(ctx context.Context, exec sqlx.ExtContext, enrollmentIDs []string, subjectID string, finalized bool) error {
 	if len(enrollmentIDs) == 0 {
 		return nil
 	}
@@ -68,7 +87,7 @@ func (r *GradeFinalRepository) SetFinalized(ctx context.Context, enrollmentIDs [
 	}
 	args[len(args)-1] = subjectID
 	query := fmt.Sprintf("UPDATE grade_finals SET finalized = $1 WHERE enrollment_id IN (%s) AND subject_id = $%d", strings.Join(placeholders, ","), len(args))
-	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+	if _, err := r.exec(exec).ExecContext(ctx, query, args...); err != nil {
 		return fmt.Errorf("set finals finalized: %w", err)
 	}
 	return nil
@@ -87,7 +106,8 @@ func (r *GradeFinalRepository) FetchByEnrollments(ctx context.Context, enrollmen
 		args[i] = id
 	}
 	args[len(args)-1] = subjectID
-	query := fmt.Sprintf(`SELECT id, enrollment_id, subject_id, final_grade, finalized, calculated_at, calculation_note
+	query := fmt.Sprintf(`SELECT id, enrollment_id, subject_id, final_grade, finalized, calculated_at, calculation_note,
+        below_kkm, remedial_grade, remedial_at
         FROM grade_finals WHERE enrollment_id IN (%s) AND subject_id = $%d`, strings.Join(placeholders, ","), len(args))
 	rows, err := r.db.QueryxContext(ctx, query, args...)
 	if err != nil {
@@ -104,12 +124,49 @@ func (r *GradeFinalRepository) FetchByEnrollments(ctx context.Context, enrollmen
 	return result, nil
 }
 
-// ReportCard returns final grades per subject for a student term scope.
-func (r *GradeFinalRepository) ReportCard(ctx context.Context, studentID, termID string) ([]models.GradeReportSubject, error) {
-	const query = `SELECT gf.subject_id, s.name AS subject_name, gf.final_grade
+// RecordRemedial stores a remedial score next to the final grade without touching the final itself.
+func (r *GradeFinalRepository) RecordRemedial(ctx context.Context, id string, grade float64, at time.Time) error {
+	const query = `UPDATE grade_finals SET remedial_grade = $2, remedial_at = $3 WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, grade, at)
+	if err != nil {
+		return fmt.Errorf("record remedial grade: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check remedial grade rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Correct replaces a final grade, as when an appeal against it is approved, and records why. It
+// returns sql.ErrNoRows when the final grade does not exist.
+func (r *GradeFinalRepository) Correct(ctx context.Context, exec sqlx.ExtContext, id string, grade float64, belowKKM bool, note string, at time.Time) error {
+	const query = `UPDATE grade_finals SET final_grade = $2, below_kkm = $3, calculation_note = $4, calculated_at = $5 WHERE id = $1`
+	res, err := r.exec(exec).ExecContext(ctx, query, id, grade, belowKKM, note, at)
+	if err != nil {
+		return fmt.Errorf("correct final grade: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check corrected final grade rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReportCard returns final grades per subject for a student term scope, with the KKM and any
+// remedial score.
+func (r *GradeFinalRepository) ReportCard(ctx context.Context, studentID models.StudentID, termID models.TermID) ([]models.GradeReportSubject, error) {
+	const query = `SELECT gf.subject_id, s.name AS subject_name, gf.final_grade, gc.kkm, gf.below_kkm, gf.remedial_grade
         FROM grade_finals gf
         JOIN enrollments e ON e.id = gf.enrollment_id
         JOIN subjects s ON s.id = gf.subject_id
+        LEFT JOIN grade_configs gc ON gc.class_id = e.class_id AND gc.subject_id = gf.subject_id AND gc.term_id = e.term_id
         WHERE e.student_id = $1 AND e.term_id = $2
         ORDER BY s.name`
 	var subjects []models.GradeReportSubject
@@ -120,7 +177,7 @@ func (r *GradeFinalRepository) ReportCard(ctx context.Context, studentID, termID
 }
 
 // ClassReportRows returns per-student rows for class report.
-func (r *GradeFinalRepository) ClassReportRows(ctx context.Context, classID, subjectID, termID string) ([]models.GradeFinalReportRow, error) {
+func (r *GradeFinalRepository) ClassReportRows(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) ([]models.GradeFinalReportRow, error) {
 	const query = `SELECT st.id AS student_id, st.full_name AS student_name, gf.final_grade,
         CASE WHEN gf.final_grade IS NULL THEN NULL ELSE RANK() OVER (ORDER BY gf.final_grade DESC) END AS rank
         FROM grade_finals gf
@@ -136,7 +193,7 @@ func (r *GradeFinalRepository) ClassReportRows(ctx context.Context, classID, sub
 }
 
 // ClassDistribution aggregates metrics for a class.
-func (r *GradeFinalRepository) ClassDistribution(ctx context.Context, classID, subjectID, termID string) (*models.ClassGradeDistribution, error) {
+func (r *GradeFinalRepository) ClassDistribution(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.ClassGradeDistribution, error) {
 	const query = `SELECT gf.subject_id, e.term_id AS term_id,
         MIN(gf.final_grade) AS min, MAX(gf.final_grade) AS max, AVG(gf.final_grade) AS average
         FROM grade_finals gf
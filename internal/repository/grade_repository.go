@@ -24,7 +24,7 @@ func NewGradeRepository(db *sqlx.DB) *GradeRepository {
 
 // List returns grade entries matching the filter.
 func (r *GradeRepository) List(ctx context.Context, filter models.GradeFilter) ([]models.Grade, error) {
-	query := `SELECT g.id, g.enrollment_id, g.subject_id, g.component_id, g.grade_value, g.created_at, g.updated_at, gc.code AS component_code
+	query := `SELECT g.id, g.enrollment_id, g.subject_id, g.component_id, g.entry, g.grade_value, g.created_at, g.updated_at, gc.code AS component_code
         FROM grades g
         JOIN grade_components gc ON gc.id = g.component_id
         WHERE 1=1`
@@ -59,9 +59,9 @@ func (r *GradeRepository) Upsert(ctx context.Context, grade *models.Grade) error
 		grade.CreatedAt = now
 	}
 	grade.UpdatedAt = now
-	const query = `INSERT INTO grades (id, enrollment_id, subject_id, component_id, grade_value, created_at, updated_at)
-        VALUES (:id, :enrollment_id, :subject_id, :component_id, :grade_value, :created_at, :updated_at)
-        ON CONFLICT (enrollment_id, subject_id, component_id)
+	const query = `INSERT INTO grades (id, enrollment_id, subject_id, component_id, entry, grade_value, created_at, updated_at)
+        VALUES (:id, :enrollment_id, :subject_id, :component_id, :entry, :grade_value, :created_at, :updated_at)
+        ON CONFLICT (enrollment_id, subject_id, component_id, entry)
         DO UPDATE SET grade_value = EXCLUDED.grade_value, updated_at = EXCLUDED.updated_at`
 	if _, err := r.db.NamedExecContext(ctx, query, grade); err != nil {
 		return fmt.Errorf("upsert grade: %w", err)
@@ -84,9 +84,9 @@ func (r *GradeRepository) BulkUpsert(ctx context.Context, grades []models.Grade)
 			grades[i].CreatedAt = now
 		}
 		grades[i].UpdatedAt = now
-		const query = `INSERT INTO grades (id, enrollment_id, subject_id, component_id, grade_value, created_at, updated_at)
-                VALUES (:id, :enrollment_id, :subject_id, :component_id, :grade_value, :created_at, :updated_at)
-                ON CONFLICT (enrollment_id, subject_id, component_id)
+		const query = `INSERT INTO grades (id, enrollment_id, subject_id, component_id, entry, grade_value, created_at, updated_at)
+                VALUES (:id, :enrollment_id, :subject_id, :component_id, :entry, :grade_value, :created_at, :updated_at)
+                ON CONFLICT (enrollment_id, subject_id, component_id, entry)
                 DO UPDATE SET grade_value = EXCLUDED.grade_value, updated_at = EXCLUDED.updated_at`
 		if _, err := tx.NamedExecContext(ctx, query, grades[i]); err != nil {
 			tx.Rollback() //nolint:errcheck
@@ -111,7 +111,7 @@ func (r *GradeRepository) FetchByEnrollments(ctx context.Context, enrollmentIDs
 		args[i] = id
 	}
 	args[len(args)-1] = subjectID
-	query := fmt.Sprintf(`SELECT g.id, g.enrollment_id, g.subject_id, g.component_id, g.grade_value, g.created_at, g.updated_at, gc.code AS component_code
+	query := fmt.Sprintf(`SELECT g.id, g.enrollment_id, g.subject_id, g.component_id, g.entry, g.grade_value, g.created_at, g.updated_at, gc.code AS component_code
         FROM grades g
         JOIN grade_components gc ON gc.id = g.component_id
         WHERE g.enrollment_id IN (%s) AND g.subject_id = $%d`, strings.Join(placeholders, ","), len(args))
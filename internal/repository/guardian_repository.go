@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// GuardianRepository manages guardian to student links.
+type GuardianRepository struct {
+	db *sqlx.DB
+}
+
+// NewGuardianRepository constructs the repository.
+func NewGuardianRepository(db *sqlx.DB) *GuardianRepository {
+	return &GuardianRepository{db: db}
+}
+
+// ListStudents returns the students linked to a guardian ordered by name.
+func (r *GuardianRepository) ListStudents(ctx context.Context, guardianID string) ([]models.GuardianStudent, error) {
+	const query = `SELECT gs.guardian_id, gs.student_id, gs.relationship, s.full_name AS student_name, s.nis, gs.created_at
+FROM guardian_students gs
+JOIN students s ON s.id = gs.student_id
+WHERE gs.guardian_id = $1
+ORDER BY s.full_name ASC`
+	var links []models.GuardianStudent
+	if err := r.db.SelectContext(ctx, &links, query, guardianID); err != nil {
+		return nil, fmt.Errorf("list guardian students: %w", err)
+	}
+	return links, nil
+}
+
+// IsLinked reports whether the guardian may view the student.
+func (r *GuardianRepository) IsLinked(ctx context.Context, guardianID, studentID string) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM guardian_students WHERE guardian_id = $1 AND student_id = $2)`
+	var linked bool
+	if err := r.db.GetContext(ctx, &linked, query, guardianID, studentID); err != nil {
+		return false, fmt.Errorf("check guardian link: %w", err)
+	}
+	return linked, nil
+}
+
+// Link creates the link or updates its relationship label when it already exists.
+func (r *GuardianRepository) Link(ctx context.Context, guardianID, studentID string, relationship *string) error {
+	const query = `INSERT INTO guardian_students (guardian_id, student_id, relationship, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (guardian_id, student_id) DO UPDATE SET relationship = EXCLUDED.relationship`
+	if _, err := r.db.ExecContext(ctx, query, guardianID, studentID, relationship, time.Now().UTC()); err != nil {
+		return fmt.Errorf("link guardian student: %w", err)
+	}
+	return nil
+}
+
+// Unlink removes a link, returning sql.ErrNoRows when it did not exist.
+func (r *GuardianRepository) Unlink(ctx context.Context, guardianID, studentID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM guardian_students WHERE guardian_id = $1 AND student_id = $2`, guardianID, studentID)
+	if err != nil {
+		return fmt.Errorf("unlink guardian student: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check guardian unlink rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetContact returns the phone a guardian is messaged on.
+func (r *GuardianRepository) GetContact(ctx context.Context, guardianID string) (*models.GuardianContact, error) {
+	const query = `SELECT guardian_id, phone, channel, absence_alerts, updated_at FROM guardian_contacts WHERE guardian_id = $1`
+	var contact models.GuardianContact
+	if err := r.db.GetContext(ctx, &contact, query, guardianID); err != nil {
+		return nil, err
+	}
+	return &contact, nil
+}
+
+// UpsertContact creates or replaces a guardian's contact.
+func (r *GuardianRepository) UpsertContact(ctx context.Context, contact *models.GuardianContact) error {
+	contact.UpdatedAt = time.Now().UTC()
+	const query = `INSERT INTO guardian_contacts (guardian_id, phone, channel, absence_alerts, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (guardian_id) DO UPDATE SET phone = EXCLUDED.phone, channel = EXCLUDED.channel,
+    absence_alerts = EXCLUDED.absence_alerts, updated_at = EXCLUDED.updated_at`
+	if _, err := r.db.ExecContext(ctx, query, contact.GuardianID, contact.Phone, contact.Channel, contact.AbsenceAlerts, contact.UpdatedAt); err != nil {
+		return fmt.Errorf("upsert guardian contact: %w", err)
+	}
+	return nil
+}
+
+// DeleteContact removes a guardian's contact, returning sql.ErrNoRows when there was none.
+func (r *GuardianRepository) DeleteContact(ctx context.Context, guardianID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM guardian_contacts WHERE guardian_id = $1`, guardianID)
+	if err != nil {
+		return fmt.Errorf("delete guardian contact: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check guardian contact rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
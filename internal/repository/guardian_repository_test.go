@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardianRepositoryIsLinked(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewGuardianRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM guardian_students WHERE guardian_id = $1 AND student_id = $2)")).
+		WithArgs("guardian-1", "student-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	linked, err := repo.IsLinked(context.Background(), "guardian-1", "student-1")
+	require.NoError(t, err)
+	assert.True(t, linked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGuardianRepositoryUnlinkMissing(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewGuardianRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM guardian_students WHERE guardian_id = $1 AND student_id = $2")).
+		WithArgs("guardian-1", "student-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Unlink(context.Background(), "guardian-1", "student-9")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
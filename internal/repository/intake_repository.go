@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+)
+
+const intakeColumns = `id, term_id, full_name, nisn, gender, birth_date, address, phone, previous_school, requested_class_id,
+	status, waitlisted_at, class_id, student_id, enrollment_id, decision_note, decided_by, decided_at, created_by, created_at, updated_at`
+
+// IntakeRepository persists transfer-in applications and their checklist documents.
+type IntakeRepository struct {
+	db *sqlx.DB
+}
+
+// NewIntakeRepository constructs the repository.
+func NewIntakeRepository(db *sqlx.DB) *IntakeRepository {
+	return &IntakeRepository{db: db}
+}
+
+// Create inserts a new application.
+func (r *IntakeRepository) Create(ctx context.Context, application *models.IntakeApplication) error {
+	if application.ID == "" {
+		application.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if application.CreatedAt.IsZero() {
+		application.CreatedAt = now
+	}
+	application.UpdatedAt = now
+	if application.Status == "" {
+		application.Status = models.IntakeStatusPending
+	}
+	const query = `INSERT INTO intake_applications (id, term_id, full_name, nisn, gender, birth_date, address, phone, previous_school,
+	requested_class_id, status, created_by, created_at, updated_at)
+VALUES (:id, :term_id, :full_name, :nisn, :gender, :birth_date, :address, :phone, :previous_school,
+	:requested_class_id, :status, :created_by, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, application); err != nil {
+		return fmt.Errorf("create intake application: %w", err)
+	}
+	return nil
+}
+
+// Update stores the applicant's details of an application still awaiting a decision.
+func (r *IntakeRepository) Update(ctx context.Context, application *models.IntakeApplication) error {
+	application.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE intake_applications SET full_name = :full_name, nisn = :nisn, gender = :gender, birth_date = :birth_date,
+	address = :address, phone = :phone, previous_school = :previous_school, requested_class_id = :requested_class_id, updated_at = :updated_at
+WHERE id = :id AND status IN ('PENDING', 'WAITLISTED')`
+	res, err := r.db.NamedExecContext(ctx, query, application)
+	if err != nil {
+		return fmt.Errorf("update intake application: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FindByID loads an application with its documents.
+func (r *IntakeRepository) FindByID(ctx context.Context, id string) (*models.IntakeApplication, error) {
+	var application models.IntakeApplication
+	if err := r.db.GetContext(ctx, &application, `SELECT `+intakeColumns+` FROM intake_applications WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	applications := []models.IntakeApplication{application}
+	if err := r.loadDocuments(ctx, applications); err != nil {
+		return nil, err
+	}
+	return &applications[0], nil
+}
+
+// List returns applications with their documents, oldest first.
+func (r *IntakeRepository) List(ctx context.Context, filter models.IntakeFilter) ([]models.IntakeApplication, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+	if filter.TermID != "" {
+		args = append(args, filter.TermID)
+		conditions = append(conditions, fmt.Sprintf("term_id = $%d", len(args)))
+	}
+	if len(filter.Status) > 0 {
+		statuses := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			statuses[i] = string(status)
+		}
+		args = append(args, pq.Array(statuses))
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	query := `SELECT ` + intakeColumns + ` FROM intake_applications WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY created_at ASC, id ASC`
+	var applications []models.IntakeApplication
+	if err := r.db.SelectContext(ctx, &applications, query, args...); err != nil {
+		return nil, fmt.Errorf("list intake applications: %w", err)
+	}
+	if err := r.loadDocuments(ctx, applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// SetDocument links an archived document to an application, replacing an earlier document of the
+// same type.
+func (r *IntakeRepository) SetDocument(ctx context.Context, document *models.IntakeDocument) error {
+	if document.UploadedAt.IsZero() {
+		document.UploadedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO intake_documents (application_id, document_type, archive_id, uploaded_by, uploaded_at)
+VALUES (:application_id, :document_type, :archive_id, :uploaded_by, :uploaded_at)
+ON CONFLICT (application_id, document_type) DO UPDATE SET archive_id = EXCLUDED.archive_id, uploaded_by = EXCLUDED.uploaded_by, uploaded_at = EXCLUDED.uploaded_at`
+	if _, err := r.db.NamedExecContext(ctx, query, document); err != nil {
+		return fmt.Errorf("set intake document: %w", err)
+	}
+	return nil
+}
+
+// Decide records a waitlist or rejection decision on an open application.
+func (r *IntakeRepository) Decide(ctx context.Context, application *models.IntakeApplication) error {
+	application.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE intake_applications SET status = :status, waitlisted_at = :waitlisted_at, decision_note = :decision_note,
+	decided_by = :decided_by, decided_at = :decided_at, updated_at = :updated_at
+WHERE id = :id AND status IN ('PENDING', 'WAITLISTED')`
+	res, err := r.db.NamedExecContext(ctx, query, application)
+	if err != nil {
+		return fmt.Errorf("decide intake application: %w", err)
+	}
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Accept creates the student and their enrollment and marks the application accepted in one
+// transaction. It returns sql.ErrNoRows when the application was decided in the meantime.
+func (r *IntakeRepository) Accept(ctx context.Context, application *models.IntakeApplication, student *models.Student, enrollment *models.Enrollment) error {
+	now := time.Now().UTC()
+	if student.ID == "" {
+		student.ID = uuid.NewString()
+	}
+	student.CreatedAt, student.UpdatedAt = now, now
+	if enrollment.ID == "" {
+		enrollment.ID = uuid.NewString()
+	}
+	enrollment.StudentID = student.ID
+	application.StudentID = &student.ID
+	application.EnrollmentID = &enrollment.ID
+	application.UpdatedAt = now
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		const insertStudent = `INSERT INTO students (id, nis, nisn, full_name, gender, birth_date, address, phone, active, created_at, updated_at)
+VALUES (:id, :nis, :nisn, :full_name, :gender, :birth_date, :address, :phone, :active, :created_at, :updated_at)`
+		if _, err := tx.NamedExecContext(ctx, insertStudent, student); err != nil {
+			return fmt.Errorf("create intake student: %w", err)
+		}
+		const insertEnrollment = `INSERT INTO enrollments (id, student_id, class_id, term_id, joined_at, left_at, status)
+VALUES (:id, :student_id, :class_id, :term_id, :joined_at, :left_at, :status)`
+		if _, err := tx.NamedExecContext(ctx, insertEnrollment, enrollment); err != nil {
+			return fmt.Errorf("create intake enrollment: %w", err)
+		}
+		const accept = `UPDATE intake_applications SET status = :status, class_id = :class_id, student_id = :student_id,
+	enrollment_id = :enrollment_id, decision_note = :decision_note, decided_by = :decided_by, decided_at = :decided_at, updated_at = :updated_at
+WHERE id = :id AND status IN ('PENDING', 'WAITLISTED')`
+		res, err := tx.NamedExecContext(ctx, accept, application)
+		if err != nil {
+			return fmt.Errorf("accept intake application: %w", err)
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+}
+
+func (r *IntakeRepository) loadDocuments(ctx context.Context, applications []models.IntakeApplication) error {
+	if len(applications) == 0 {
+		return nil
+	}
+	ids := make([]string, len(applications))
+	index := make(map[string]int, len(applications))
+	for i := range applications {
+		ids[i] = applications[i].ID
+		index[applications[i].ID] = i
+		applications[i].Documents = make([]models.IntakeDocument, 0)
+	}
+	const query = `SELECT d.application_id, d.document_type, d.archive_id, a.title, a.mime_type, a.size_bytes, d.uploaded_by, d.uploaded_at
+FROM intake_documents d
+JOIN archives a ON a.id = d.archive_id
+WHERE d.application_id = ANY($1) AND a.deleted_at IS NULL
+ORDER BY d.document_type ASC`
+	var documents []models.IntakeDocument
+	if err := r.db.SelectContext(ctx, &documents, query, pq.Array(ids)); err != nil {
+		return fmt.Errorf("list intake documents: %w", err)
+	}
+	for _, document := range documents {
+		i := index[document.ApplicationID]
+		applications[i].Documents = append(applications[i].Documents, document)
+	}
+	return nil
+}
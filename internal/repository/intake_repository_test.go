@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestIntakeRepositoryAccept(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIntakeRepository(sqlx.NewDb(db, "sqlmock"))
+
+	classID := "class-a"
+	application := &models.IntakeApplication{ID: "intake-1", TermID: "term-1", Status: models.IntakeStatusAccepted, ClassID: &classID}
+	student := &models.Student{NIS: "2001", FullName: "Rina Putri", Active: true}
+	enrollment := &models.Enrollment{ClassID: classID, TermID: "term-1", JoinedAt: time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC), Status: models.EnrollmentStatusActive}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO students").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO enrollments").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE intake_applications SET status = .* WHERE id = .* AND status IN \('PENDING', 'WAITLISTED'\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Accept(context.Background(), application, student, enrollment))
+	assert.NotEmpty(t, student.ID)
+	assert.Equal(t, student.ID, enrollment.StudentID)
+	assert.Equal(t, enrollment.ID, *application.EnrollmentID)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO students").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO enrollments").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE intake_applications").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Accept(context.Background(), application, &models.Student{NIS: "2002"}, &models.Enrollment{ClassID: classID, TermID: "term-1"})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIntakeRepositoryFindByIDLoadsDocuments(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIntakeRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Date(2026, 8, 1, 8, 0, 0, 0, time.UTC)
+	columns := []string{"id", "term_id", "full_name", "nisn", "gender", "birth_date", "address", "phone", "previous_school", "requested_class_id",
+		"status", "waitlisted_at", "class_id", "student_id", "enrollment_id", "decision_note", "decided_by", "decided_at", "created_by", "created_at", "updated_at"}
+	mock.ExpectQuery(`FROM intake_applications WHERE id = \$1`).WithArgs("intake-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("intake-1", "term-1", "Rina Putri", nil, "F", now, "", "", "SMA 3 Bandung", nil,
+			"PENDING", nil, nil, nil, nil, nil, nil, nil, "admin-1", now, now))
+	mock.ExpectQuery(`FROM intake_documents d\s+JOIN archives a ON a.id = d.archive_id\s+WHERE d.application_id = ANY\(\$1\) AND a.deleted_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"application_id", "document_type", "archive_id", "title", "mime_type", "size_bytes", "uploaded_by", "uploaded_at"}).
+			AddRow("intake-1", "BIRTH_CERTIFICATE", "archive-1", "Rina Putri - BIRTH CERTIFICATE", "application/pdf", 2048, "admin-1", now))
+
+	application, err := repo.FindByID(context.Background(), "intake-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.IntakeStatusPending, application.Status)
+	require.Len(t, application.Documents, 1)
+	assert.Equal(t, models.IntakeDocumentBirthCertificate, application.Documents[0].DocumentType)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const legacySyncBatchColumns = `id, kind, idempotency_key, source, payload_hash, received, applied, failed, failures, created_at`
+
+// LegacySyncRepository stores the outcome of batches pushed by the legacy app.
+type LegacySyncRepository struct {
+	db *sqlx.DB
+}
+
+// NewLegacySyncRepository constructs the repository.
+func NewLegacySyncRepository(db *sqlx.DB) *LegacySyncRepository {
+	return &LegacySyncRepository{db: db}
+}
+
+// FindBatch returns the batch delivered with an idempotency key.
+func (r *LegacySyncRepository) FindBatch(ctx context.Context, kind models.LegacySyncKind, idempotencyKey string) (*models.LegacySyncBatch, error) {
+	query := `SELECT ` + legacySyncBatchColumns + ` FROM legacy_sync_batches WHERE kind = $1 AND idempotency_key = $2`
+	var batch models.LegacySyncBatch
+	if err := r.db.GetContext(ctx, &batch, query, kind, idempotencyKey); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("find legacy sync batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// CreateBatch records a processed batch. It reports false when a batch with the same kind and
+// idempotency key was stored first, e.g. by a concurrent delivery.
+func (r *LegacySyncRepository) CreateBatch(ctx context.Context, batch *models.LegacySyncBatch) (bool, error) {
+	if batch.ID == "" {
+		batch.ID = uuid.NewString()
+	}
+	if batch.CreatedAt.IsZero() {
+		batch.CreatedAt = time.Now().UTC()
+	}
+	query := `INSERT INTO legacy_sync_batches (` + legacySyncBatchColumns + `)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (kind, idempotency_key) DO NOTHING`
+	result, err := r.db.ExecContext(ctx, query, batch.ID, batch.Kind, batch.IdempotencyKey, batch.Source, batch.PayloadHash,
+		batch.Received, batch.Applied, batch.Failed, batch.Failures, batch.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("create legacy sync batch: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check legacy sync batch rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListBatches returns the batches received in [from, to), oldest first.
+func (r *LegacySyncRepository) ListBatches(ctx context.Context, from, to time.Time) ([]models.LegacySyncBatch, error) {
+	query := `SELECT ` + legacySyncBatchColumns + ` FROM legacy_sync_batches
+WHERE created_at >= $1 AND created_at < $2 ORDER BY created_at ASC, id ASC`
+	var batches []models.LegacySyncBatch
+	if err := r.db.SelectContext(ctx, &batches, query, from, to); err != nil {
+		return nil, fmt.Errorf("list legacy sync batches: %w", err)
+	}
+	return batches, nil
+}
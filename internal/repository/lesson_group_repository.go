@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+)
+
+const lessonGroupColumns = `id, term_id, subject_id, teacher_id, name, room, created_at, updated_at`
+
+// LessonGroupRepository persists lesson groups, their member classes and selected students.
+type LessonGroupRepository struct {
+	db *sqlx.DB
+}
+
+// NewLessonGroupRepository constructs the repository.
+func NewLessonGroupRepository(db *sqlx.DB) *LessonGroupRepository {
+	return &LessonGroupRepository{db: db}
+}
+
+// Create stores a group with its members.
+func (r *LessonGroupRepository) Create(ctx context.Context, group *models.LessonGroup) error {
+	if group.ID == "" {
+		group.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	group.CreatedAt, group.UpdatedAt = now, now
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		const query = `INSERT INTO lesson_groups (id, term_id, subject_id, teacher_id, name, room, created_at, updated_at)
+VALUES (:id, :term_id, :subject_id, :teacher_id, :name, :room, :created_at, :updated_at)`
+		if _, err := tx.NamedExecContext(ctx, query, group); err != nil {
+			return fmt.Errorf("create lesson group: %w", err)
+		}
+		return insertLessonGroupMembers(ctx, tx, group)
+	})
+}
+
+// Update rewrites the group's name, room and members. It returns sql.ErrNoRows when the group does
+// not exist.
+func (r *LessonGroupRepository) Update(ctx context.Context, group *models.LessonGroup) error {
+	group.UpdatedAt = time.Now().UTC()
+	return database.WithTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		res, err := tx.NamedExecContext(ctx, `UPDATE lesson_groups SET name = :name, room = :room, updated_at = :updated_at WHERE id = :id`, group)
+		if err != nil {
+			return fmt.Errorf("update lesson group: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("check lesson group update rows: %w", err)
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+		// Removing the classes removes their selected students too.
+		if _, err := tx.ExecContext(ctx, `DELETE FROM lesson_group_classes WHERE group_id = $1`, group.ID); err != nil {
+			return fmt.Errorf("clear lesson group members: %w", err)
+		}
+		return insertLessonGroupMembers(ctx, tx, group)
+	})
+}
+
+func insertLessonGroupMembers(ctx context.Context, tx *sqlx.Tx, group *models.LessonGroup) error {
+	for _, member := range group.Members {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO lesson_group_classes (group_id, class_id) VALUES ($1, $2)`, group.ID, member.ClassID); err != nil {
+			return fmt.Errorf("insert lesson group class: %w", err)
+		}
+		for _, enrollmentID := range member.EnrollmentIDs {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO lesson_group_students (group_id, class_id, enrollment_id) VALUES ($1, $2, $3)`, group.ID, member.ClassID, enrollmentID); err != nil {
+				return fmt.Errorf("insert lesson group student: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// FindByID loads a group with its members.
+func (r *LessonGroupRepository) FindByID(ctx context.Context, id string) (*models.LessonGroup, error) {
+	var group models.LessonGroup
+	if err := r.db.GetContext(ctx, &group, `SELECT `+lessonGroupColumns+` FROM lesson_groups WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	groups := []models.LessonGroup{group}
+	if err := r.loadMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// ListByTerm returns the term's groups with their members ordered by name.
+func (r *LessonGroupRepository) ListByTerm(ctx context.Context, termID string) ([]models.LessonGroup, error) {
+	var groups []models.LessonGroup
+	if err := r.db.SelectContext(ctx, &groups, `SELECT `+lessonGroupColumns+` FROM lesson_groups WHERE term_id = $1 ORDER BY name ASC`, termID); err != nil {
+		return nil, fmt.Errorf("list lesson groups: %w", err)
+	}
+	if err := r.loadMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *LessonGroupRepository) loadMembers(ctx context.Context, groups []models.LessonGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	ids := make([]string, len(groups))
+	index := make(map[string]int, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+		index[group.ID] = i
+		groups[i].Members = []models.LessonGroupMember{}
+	}
+
+	var classes []struct {
+		GroupID string `db:"group_id"`
+		ClassID string `db:"class_id"`
+	}
+	if err := r.db.SelectContext(ctx, &classes, `SELECT group_id, class_id FROM lesson_group_classes WHERE group_id = ANY($1) ORDER BY class_id ASC`, pq.Array(ids)); err != nil {
+		return fmt.Errorf("list lesson group classes: %w", err)
+	}
+	var students []models.LessonGroupStudent
+	if err := r.db.SelectContext(ctx, &students, `SELECT group_id, class_id, enrollment_id FROM lesson_group_students WHERE group_id = ANY($1) ORDER BY enrollment_id ASC`, pq.Array(ids)); err != nil {
+		return fmt.Errorf("list lesson group students: %w", err)
+	}
+	selected := make(map[string][]string)
+	for _, student := range students {
+		key := student.GroupID + "|" + student.ClassID
+		selected[key] = append(selected[key], student.EnrollmentID)
+	}
+	for _, class := range classes {
+		i := index[class.GroupID]
+		groups[i].Members = append(groups[i].Members, models.LessonGroupMember{
+			ClassID:       class.ClassID,
+			EnrollmentIDs: selected[class.GroupID+"|"+class.ClassID],
+		})
+	}
+	return nil
+}
+
+// Delete removes a group and its members. It returns sql.ErrNoRows when the group does not exist;
+// groups with sessions cannot be removed.
+func (r *LessonGroupRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM lesson_groups WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete lesson group: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson group delete rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListSessions returns the schedules of the group's sessions ordered by day, slot and class.
+func (r *LessonGroupRepository) ListSessions(ctx context.Context, groupID string) ([]models.Schedule, error) {
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at
+FROM schedules WHERE lesson_group_id = $1 ORDER BY day_of_week ASC, time_slot ASC, class_id ASC`
+	var schedules []models.Schedule
+	if err := r.db.SelectContext(ctx, &schedules, query, groupID); err != nil {
+		return nil, fmt.Errorf("list lesson group sessions: %w", err)
+	}
+	return schedules, nil
+}
+
+// DeleteSession removes the schedules of the group's session on dayOfWeek and timeSlot. It returns
+// sql.ErrNoRows when the group has no session there.
+func (r *LessonGroupRepository) DeleteSession(ctx context.Context, groupID, dayOfWeek, timeSlot string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM schedules WHERE lesson_group_id = $1 AND day_of_week = $2 AND time_slot = $3`, groupID, dayOfWeek, timeSlot)
+	if err != nil {
+		return fmt.Errorf("delete lesson group session: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson group session delete rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
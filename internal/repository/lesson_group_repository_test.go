@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestLessonGroupRepositoryCreate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewLessonGroupRepository(sqlx.NewDb(db, "sqlmock"))
+
+	group := &models.LessonGroup{
+		ID: "group-1", TermID: "term-1", SubjectID: "subject-1", TeacherID: "teacher-1", Name: "Catholic Religion",
+		Members: []models.LessonGroupMember{
+			{ClassID: "class-a", EnrollmentIDs: []string{"enr-1", "enr-2"}},
+			{ClassID: "class-b"},
+		},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO lesson_groups").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lesson_group_classes").WithArgs("group-1", "class-a").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lesson_group_students").WithArgs("group-1", "class-a", "enr-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lesson_group_students").WithArgs("group-1", "class-a", "enr-2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lesson_group_classes").WithArgs("group-1", "class-b").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Create(context.Background(), group))
+	assert.False(t, group.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLessonGroupRepositoryFindByIDLoadsMembers(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewLessonGroupRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Date(2026, 7, 13, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM lesson_groups WHERE id = \$1`).WithArgs("group-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "term_id", "subject_id", "teacher_id", "name", "room", "created_at", "updated_at"}).
+			AddRow("group-1", "term-1", "subject-1", "teacher-1", "Catholic Religion", nil, now, now))
+	mock.ExpectQuery(`FROM lesson_group_classes WHERE group_id = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id", "class_id"}).AddRow("group-1", "class-a").AddRow("group-1", "class-b"))
+	mock.ExpectQuery(`FROM lesson_group_students WHERE group_id = ANY\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id", "class_id", "enrollment_id"}).AddRow("group-1", "class-a", "enr-1"))
+
+	group, err := repo.FindByID(context.Background(), "group-1")
+	require.NoError(t, err)
+	require.Len(t, group.Members, 2)
+	assert.Equal(t, []string{"enr-1"}, group.Members[0].EnrollmentIDs)
+	assert.True(t, group.Members[1].WholeClass())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLessonGroupRepositoryDeleteSession(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewLessonGroupRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectExec(`DELETE FROM schedules WHERE lesson_group_id = \$1 AND day_of_week = \$2 AND time_slot = \$3`).
+		WithArgs("group-1", "MONDAY", "3").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	require.NoError(t, repo.DeleteSession(context.Background(), "group-1", "MONDAY", "3"))
+
+	mock.ExpectExec("DELETE FROM schedules").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteSession(context.Background(), "group-1", "MONDAY", "4"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
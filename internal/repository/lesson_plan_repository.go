@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const lessonPlanColumns = `id, teacher_id, class_id, subject_id, term_id, week_start, title, objectives, activities, assessment,
+       attachment_id, status, submitted_at, reviewed_by, reviewed_at, review_note, created_at, updated_at`
+
+// LessonPlanRepository persists weekly lesson plans and their review state.
+type LessonPlanRepository struct {
+	db *sqlx.DB
+}
+
+// NewLessonPlanRepository constructs the repository.
+func NewLessonPlanRepository(db *sqlx.DB) *LessonPlanRepository {
+	return &LessonPlanRepository{db: db}
+}
+
+// List returns lesson plans matching the filter, latest week first.
+func (r *LessonPlanRepository) List(ctx context.Context, filter models.LessonPlanFilter) ([]models.LessonPlan, error) {
+	args := make([]interface{}, 0, 6)
+	conditions := make([]string, 0, 6)
+	add := func(column string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if filter.TeacherID != "" {
+		add("teacher_id", filter.TeacherID)
+	}
+	if filter.ClassID != "" {
+		add("class_id", filter.ClassID)
+	}
+	if filter.SubjectID != "" {
+		add("subject_id", filter.SubjectID)
+	}
+	if filter.TermID != "" {
+		add("term_id", filter.TermID)
+	}
+	if filter.WeekStart != nil {
+		add("week_start", *filter.WeekStart)
+	}
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			args = append(args, status)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ",")))
+	}
+	query := `SELECT ` + lessonPlanColumns + ` FROM lesson_plans`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY week_start DESC, class_id ASC, subject_id ASC"
+	var plans []models.LessonPlan
+	if err := r.db.SelectContext(ctx, &plans, query, args...); err != nil {
+		return nil, fmt.Errorf("list lesson plans: %w", err)
+	}
+	return plans, nil
+}
+
+// FindByID fetches a lesson plan.
+func (r *LessonPlanRepository) FindByID(ctx context.Context, id string) (*models.LessonPlan, error) {
+	var plan models.LessonPlan
+	if err := r.db.GetContext(ctx, &plan, `SELECT `+lessonPlanColumns+` FROM lesson_plans WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Create inserts a lesson plan.
+func (r *LessonPlanRepository) Create(ctx context.Context, plan *models.LessonPlan) error {
+	if plan.ID == "" {
+		plan.ID = uuid.NewString()
+	}
+	if plan.Status == "" {
+		plan.Status = models.LessonPlanStatusDraft
+	}
+	now := time.Now().UTC()
+	if plan.CreatedAt.IsZero() {
+		plan.CreatedAt = now
+	}
+	plan.UpdatedAt = now
+	const query = `INSERT INTO lesson_plans (id, teacher_id, class_id, subject_id, term_id, week_start, title, objectives, activities,
+    assessment, attachment_id, status, created_at, updated_at)
+VALUES (:id, :teacher_id, :class_id, :subject_id, :term_id, :week_start, :title, :objectives, :activities,
+    :assessment, :attachment_id, :status, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, plan); err != nil {
+		return fmt.Errorf("create lesson plan: %w", err)
+	}
+	return nil
+}
+
+// Update saves the content and attachment of a plan that is still a draft or was rejected.
+func (r *LessonPlanRepository) Update(ctx context.Context, plan *models.LessonPlan) error {
+	plan.UpdatedAt = time.Now().UTC()
+	query := fmt.Sprintf(`UPDATE lesson_plans SET title = :title, objectives = :objectives, activities = :activities,
+    assessment = :assessment, attachment_id = :attachment_id, updated_at = :updated_at
+WHERE id = :id AND status IN ('%s', '%s')`, models.LessonPlanStatusDraft, models.LessonPlanStatusRejected)
+	result, err := r.db.NamedExecContext(ctx, query, plan)
+	if err != nil {
+		return fmt.Errorf("update lesson plan: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson plan rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Submit moves a draft or rejected plan into review, clearing the previous review outcome.
+func (r *LessonPlanRepository) Submit(ctx context.Context, id string, submittedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE lesson_plans SET status = $2, submitted_at = $3, reviewed_by = NULL, reviewed_at = NULL,
+    review_note = NULL, updated_at = $3
+WHERE id = $1 AND status IN ('%s', '%s')`, models.LessonPlanStatusDraft, models.LessonPlanStatusRejected)
+	result, err := r.db.ExecContext(ctx, query, id, models.LessonPlanStatusSubmitted, submittedAt)
+	if err != nil {
+		return fmt.Errorf("submit lesson plan: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson plan rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReviewLessonPlanParams groups the columns written by a review decision.
+type ReviewLessonPlanParams struct {
+	ID         string
+	Status     models.LessonPlanStatus
+	ReviewedBy string
+	ReviewedAt time.Time
+	Note       *string
+}
+
+// Review persists the review outcome of a submitted plan.
+func (r *LessonPlanRepository) Review(ctx context.Context, params ReviewLessonPlanParams) error {
+	query := fmt.Sprintf(`UPDATE lesson_plans SET status = $2, reviewed_by = $3, reviewed_at = $4, review_note = $5, updated_at = $4
+WHERE id = $1 AND status = '%s'`, models.LessonPlanStatusSubmitted)
+	result, err := r.db.ExecContext(ctx, query, params.ID, params.Status, params.ReviewedBy, params.ReviewedAt, params.Note)
+	if err != nil {
+		return fmt.Errorf("review lesson plan: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson plan rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete removes a lesson plan.
+func (r *LessonPlanRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM lesson_plans WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete lesson plan: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check lesson plan rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListGaps returns the subject teaching assignments of a term that have no submitted or approved
+// plan for the week starting on weekStart.
+func (r *LessonPlanRepository) ListGaps(ctx context.Context, termID string, weekStart time.Time) ([]models.LessonPlanGap, error) {
+	const query = `SELECT ta.teacher_id, ta.class_id, ta.subject_id
+FROM teacher_assignments ta
+WHERE ta.term_id = $1 AND ta.role = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM lesson_plans lp
+    WHERE lp.teacher_id = ta.teacher_id AND lp.class_id = ta.class_id AND lp.subject_id = ta.subject_id
+      AND lp.week_start = $3 AND lp.status IN ($4, $5)
+  )
+ORDER BY ta.teacher_id ASC, ta.class_id ASC, ta.subject_id ASC`
+	var gaps []models.LessonPlanGap
+	if err := r.db.SelectContext(ctx, &gaps, query, termID, models.TeacherAssignmentRoleSubject, weekStart,
+		models.LessonPlanStatusSubmitted, models.LessonPlanStatusApproved); err != nil {
+		return nil, fmt.Errorf("list lesson plan gaps: %w", err)
+	}
+	return gaps, nil
+}
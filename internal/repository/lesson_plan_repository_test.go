@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestLessonPlanRepositoryReviewOnlyTouchesSubmitted(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewLessonPlanRepository(db)
+
+	reviewedAt := time.Date(2026, 2, 6, 10, 0, 0, 0, time.UTC)
+	note := "add an assessment rubric"
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE lesson_plans SET status = $2, reviewed_by = $3, reviewed_at = $4, review_note = $5, updated_at = $4\nWHERE id = $1 AND status = 'SUBMITTED'")).
+		WithArgs("plan-1", models.LessonPlanStatusRejected, "admin-1", reviewedAt, &note).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Review(context.Background(), ReviewLessonPlanParams{
+		ID:         "plan-1",
+		Status:     models.LessonPlanStatusRejected,
+		ReviewedBy: "admin-1",
+		ReviewedAt: reviewedAt,
+		Note:       &note,
+	})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLessonPlanRepositoryListGaps(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewLessonPlanRepository(db)
+
+	week := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM teacher_assignments ta")).
+		WithArgs("term-1", models.TeacherAssignmentRoleSubject, week, models.LessonPlanStatusSubmitted, models.LessonPlanStatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "class_id", "subject_id"}).
+			AddRow("teacher-1", "class-1", "math").
+			AddRow("teacher-1", "class-2", "math"))
+
+	gaps, err := repo.ListGaps(context.Background(), "term-1", week)
+	require.NoError(t, err)
+	require.Len(t, gaps, 2)
+	assert.Equal(t, "class-2", gaps[1].ClassID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
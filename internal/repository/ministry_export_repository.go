@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// MinistryExportRepository reads the identified, per-student data of ministry exports.
+type MinistryExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewMinistryExportRepository constructs the repository.
+func NewMinistryExportRepository(db *sqlx.DB) *MinistryExportRepository {
+	return &MinistryExportRepository{db: db}
+}
+
+// AttendanceRecap counts the sick, excused and unexcused days of every active enrollment in a term,
+// optionally for one class, with the class's homeroom teacher.
+func (r *MinistryExportRepository) AttendanceRecap(ctx context.Context, termID, classID string) ([]models.MinistryAttendanceRow, error) {
+	query := `SELECT s.id AS student_id, s.nisn, s.nis, s.full_name AS student_name, s.gender, c.name AS class_name,
+    t.nip AS homeroom_nip, t.full_name AS homeroom_name,
+    COUNT(da.id) FILTER (WHERE da.status = 'S') AS sick,
+    COUNT(da.id) FILTER (WHERE da.status = 'I') AS excused,
+    COUNT(da.id) FILTER (WHERE da.status = 'A') AS absent
+FROM enrollments e
+JOIN students s ON s.id = e.student_id
+JOIN classes c ON c.id = e.class_id
+LEFT JOIN teacher_assignments ta ON ta.class_id = e.class_id AND ta.term_id = e.term_id AND ta.role = 'HOMEROOM'
+LEFT JOIN teachers t ON t.id = ta.teacher_id
+LEFT JOIN daily_attendance da ON da.enrollment_id = e.id AND ` + attendanceTermWindow("da.date", "$1") + `
+WHERE e.term_id = $1 AND e.status = 'ACTIVE'`
+	args := []interface{}{termID}
+	if classID != "" {
+		args = append(args, classID)
+		query += fmt.Sprintf(" AND e.class_id = $%d", len(args))
+	}
+	query += `
+GROUP BY s.id, s.nisn, s.nis, s.full_name, s.gender, c.name, t.nip, t.full_name
+ORDER BY c.name, s.full_name, s.id`
+	rows := []models.MinistryAttendanceRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("list ministry attendance recap: %w", err)
+	}
+	return rows, nil
+}
+
+// FinalGrades returns the finalized subject grades of a term's active enrollments, optionally for
+// one class. Each grade names the class's subject teacher; when several share the subject, the one
+// assigned first is reported.
+func (r *MinistryExportRepository) FinalGrades(ctx context.Context, termID, classID string) ([]models.MinistryGradeRow, error) {
+	query := `SELECT s.id AS student_id, s.nisn, s.nis, s.full_name AS student_name, c.name AS class_name,
+    sub.code AS subject_code, sub.name AS subject_name, st.nip AS teacher_nip, st.full_name AS teacher_name, gf.final_grade
+FROM grade_finals gf
+JOIN enrollments e ON e.id = gf.enrollment_id
+JOIN students s ON s.id = e.student_id
+JOIN classes c ON c.id = e.class_id
+JOIN subjects sub ON sub.id = gf.subject_id
+LEFT JOIN LATERAL (
+    SELECT t.nip, t.full_name
+    FROM teacher_assignments ta
+    JOIN teachers t ON t.id = ta.teacher_id
+    WHERE ta.class_id = e.class_id AND ta.subject_id = gf.subject_id AND ta.term_id = e.term_id AND ta.role = 'SUBJECT_TEACHER'
+    ORDER BY ta.created_at, ta.id
+    LIMIT 1
+) st ON TRUE
+WHERE e.term_id = $1 AND e.status = 'ACTIVE' AND gf.finalized = TRUE`
+	args := []interface{}{termID}
+	if classID != "" {
+		args = append(args, classID)
+		query += fmt.Sprintf(" AND e.class_id = $%d", len(args))
+	}
+	query += " ORDER BY c.name, s.full_name, s.id, sub.code"
+	rows := []models.MinistryGradeRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("list ministry final grades: %w", err)
+	}
+	return rows, nil
+}
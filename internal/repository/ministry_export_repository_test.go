@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinistryExportRepositoryFiltersByTermAndClass(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewMinistryExportRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM enrollments e")+`.*ta\.role = 'HOMEROOM'.*AND e\.class_id = \$2\s+GROUP BY`).
+		WithArgs("term-1", "class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "nisn", "nis", "student_name", "gender", "class_name", "homeroom_nip", "homeroom_name", "sick", "excused", "absent"}).
+			AddRow("stu-1", "0061234567", "1001", "Ani", "P", "X IPA 1", nil, nil, 2, 1, 0))
+	attendance, err := repo.AttendanceRecap(context.Background(), "term-1", "class-1")
+	require.NoError(t, err)
+	require.Len(t, attendance, 1)
+	assert.Equal(t, "0061234567", *attendance[0].NISN)
+	assert.Nil(t, attendance[0].HomeroomNIP)
+	assert.Equal(t, 2, attendance[0].Sick)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM grade_finals gf") + `.*gf\.finalized = TRUE ORDER BY`).
+		WithArgs("term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "nisn", "nis", "student_name", "class_name", "subject_code", "subject_name", "teacher_nip", "teacher_name", "final_grade"}))
+	grades, err := repo.FinalGrades(context.Background(), "term-1", "")
+	require.NoError(t, err)
+	assert.Empty(t, grades)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
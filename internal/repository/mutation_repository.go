@@ -23,6 +23,13 @@ func NewMutationRepository(db *sqlx.DB) *MutationRepository {
 	return &MutationRepository{db: db}
 }
 
+func (r *MutationRepository) exec(exec sqlx.ExtContext) sqlx.ExtContext {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
 // Create inserts a new mutation row.
 func (r *MutationRepository) Create(ctx context.Context, mutation *models.Mutation) error {
 	if mutation.ID == "" {
@@ -46,7 +53,7 @@ func (r *MutationRepository) Create(ctx context.Context, mutation *models.Mutati
 // GetByID fetches a mutation by identifier.
 func (r *MutationRepository) GetByID(ctx context.Context, id string) (*models.Mutation, error) {
 	const query = `SELECT id, type, entity, entity_id, current_snapshot, requested_changes, status, reason,
-       requested_by, reviewed_by, requested_at, reviewed_at, note
+       requested_by, reviewed_by, requested_at, reviewed_at, note, escalated_at
 	FROM mutations WHERE id = $1`
 	var mutation models.Mutation
 	if err := r.db.GetContext(ctx, &mutation, query, id); err != nil {
@@ -60,7 +67,7 @@ func (r *MutationRepository) List(ctx context.Context, filter models.MutationFil
 	builder := strings.Builder{}
 	args := make([]interface{}, 0, 6)
 	builder.WriteString(`SELECT id, type, entity, entity_id, current_snapshot, requested_changes, status, reason,
-       requested_by, reviewed_by, requested_at, reviewed_at, note FROM mutations`)
+       requested_by, reviewed_by, requested_at, reviewed_at, note, escalated_at FROM mutations`)
 
 	conditions := make([]string, 0, 4)
 	if len(filter.Status) > 0 {
@@ -91,6 +98,13 @@ func (r *MutationRepository) List(ctx context.Context, filter models.MutationFil
 		args = append(args, filter.ReviewerID)
 		conditions = append(conditions, fmt.Sprintf("reviewed_by = $%d", len(args)))
 	}
+	if !filter.RequestedBefore.IsZero() {
+		args = append(args, filter.RequestedBefore)
+		conditions = append(conditions, fmt.Sprintf("requested_at < $%d", len(args)))
+	}
+	if filter.Unescalated {
+		conditions = append(conditions, "escalated_at IS NULL")
+	}
 	if len(conditions) > 0 {
 		builder.WriteString(" WHERE ")
 		builder.WriteString(strings.Join(conditions, " AND "))
@@ -125,7 +139,7 @@ type UpdateMutationParams struct {
 }
 
 // UpdateStatusAndSnapshot persists review outcome.
-func (r *MutationRepository) UpdateStatusAndSnapshot(ctx context.Context, params UpdateMutationParams) error {
+func (r *MutationRepository) UpdateStatusAndSnapshot(ctx context.Context, exec sqlx.ExtContext, params UpdateMutationParams) error {
 	setParts := []string{
 		"status = :status",
 		"reviewed_by = :reviewed_by",
@@ -141,7 +155,7 @@ func (r *MutationRepository) UpdateStatusAndSnapshot(ctx context.Context, params
 		strings.Join(setParts, ", "),
 		models.MutationStatusPending,
 	)
-	result, err := r.db.NamedExecContext(ctx, query, map[string]interface{}{
+	result, err := sqlx.NamedExecContext(ctx, r.exec(exec), query, map[string]interface{}{
 		"id":               params.ID,
 		"status":           params.Status,
 		"reviewed_by":      params.ReviewedBy,
@@ -161,3 +175,82 @@ func (r *MutationRepository) UpdateStatusAndSnapshot(ctx context.Context, params
 	}
 	return nil
 }
+
+// Expire rejects a mutation still pending without a reviewer, recording note as the reason.
+// Mutations reviewed meanwhile are left untouched and reported as sql.ErrNoRows.
+func (r *MutationRepository) Expire(ctx context.Context, id, note string, at time.Time) error {
+	const query = `UPDATE mutations SET status = $2, reviewed_at = $3, note = $4 WHERE id = $1 AND status = $5`
+	res, err := r.db.ExecContext(ctx, query, id, models.MutationStatusRejected, at, note, models.MutationStatusPending)
+	if err != nil {
+		return fmt.Errorf("expire mutation: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check mutation expire rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Escalate marks a pending mutation as escalated. Mutations already escalated or reviewed are left
+// untouched and reported as sql.ErrNoRows.
+func (r *MutationRepository) Escalate(ctx context.Context, id string, at time.Time) error {
+	const query = `UPDATE mutations SET escalated_at = $2 WHERE id = $1 AND status = $3 AND escalated_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, at, models.MutationStatusPending)
+	if err != nil {
+		return fmt.Errorf("escalate mutation: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check mutation escalate rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PendingSummary counts the pending mutations, those requested before overdueBefore and those
+// escalated, along with the oldest request time.
+func (r *MutationRepository) PendingSummary(ctx context.Context, overdueBefore time.Time) (*models.MutationPendingSummary, error) {
+	const query = `SELECT COUNT(*) AS pending,
+       COUNT(*) FILTER (WHERE requested_at < $2) AS overdue,
+       COUNT(*) FILTER (WHERE escalated_at IS NOT NULL) AS escalated,
+       MIN(requested_at) AS oldest_requested_at
+	FROM mutations WHERE status = $1`
+	var summary models.MutationPendingSummary
+	if err := r.db.GetContext(ctx, &summary, query, models.MutationStatusPending, overdueBefore); err != nil {
+		return nil, fmt.Errorf("summarize pending mutations: %w", err)
+	}
+	return &summary, nil
+}
+
+// AddAttachment links an archived document to a mutation.
+func (r *MutationRepository) AddAttachment(ctx context.Context, attachment *models.MutationAttachment) error {
+	if attachment.AttachedAt.IsZero() {
+		attachment.AttachedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO mutation_attachments (mutation_id, archive_id, attached_by, attached_at)
+	VALUES (:mutation_id, :archive_id, :attached_by, :attached_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, attachment); err != nil {
+		return fmt.Errorf("add mutation attachment: %w", err)
+	}
+	return nil
+}
+
+// ListAttachments returns the documents attached to a mutation, oldest first. Attachments whose
+// archive is in the trash are left out.
+func (r *MutationRepository) ListAttachments(ctx context.Context, mutationID string) ([]models.MutationAttachment, error) {
+	const query = `SELECT ma.mutation_id, ma.archive_id, a.title, a.mime_type, a.size_bytes, ma.attached_by, ma.attached_at
+	FROM mutation_attachments ma
+	JOIN archives a ON a.id = ma.archive_id
+	WHERE ma.mutation_id = $1 AND a.deleted_at IS NULL
+	ORDER BY ma.attached_at ASC`
+	var attachments []models.MutationAttachment
+	if err := r.db.SelectContext(ctx, &attachments, query, mutationID); err != nil {
+		return nil, fmt.Errorf("list mutation attachments: %w", err)
+	}
+	return attachments, nil
+}
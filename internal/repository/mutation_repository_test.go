@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -79,7 +80,7 @@ func TestMutationRepositoryUpdateStatus(t *testing.T) {
 	now := time.Now()
 	note := "looks good"
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE mutations SET")).WillReturnResult(sqlmock.NewResult(0, 1))
-	err := repo.UpdateStatusAndSnapshot(context.Background(), UpdateMutationParams{
+	err := repo.UpdateStatusAndSnapshot(context.Background(), nil, UpdateMutationParams{
 		ID:         "mut-1",
 		Status:     models.MutationStatusApproved,
 		ReviewedBy: "admin-1",
@@ -90,7 +91,7 @@ func TestMutationRepositoryUpdateStatus(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE mutations SET")).WillReturnResult(sqlmock.NewResult(0, 0))
-	err = repo.UpdateStatusAndSnapshot(context.Background(), UpdateMutationParams{
+	err = repo.UpdateStatusAndSnapshot(context.Background(), nil, UpdateMutationParams{
 		ID:         "mut-1",
 		Status:     models.MutationStatusApproved,
 		ReviewedBy: "admin-1",
@@ -98,3 +99,67 @@ func TestMutationRepositoryUpdateStatus(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestMutationRepositoryAttachments(t *testing.T) {
+	db, mock, cleanup := newMutationRepoMock(t)
+	defer cleanup()
+
+	repo := NewMutationRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO mutation_attachments")).
+		WithArgs("mut-1", "arch-1", "teacher-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	attachment := &models.MutationAttachment{MutationID: "mut-1", ArchiveID: "arch-1", AttachedBy: "teacher-1"}
+	require.NoError(t, repo.AddAttachment(context.Background(), attachment))
+	require.False(t, attachment.AttachedAt.IsZero())
+
+	rows := sqlmock.NewRows([]string{"mutation_id", "archive_id", "title", "mime_type", "size_bytes", "attached_by", "attached_at"}).
+		AddRow("mut-1", "arch-1", "score sheet.pdf", "application/pdf", 2048, "teacher-1", time.Now())
+	mock.ExpectQuery(`JOIN archives a ON a.id = ma.archive_id\s+WHERE ma.mutation_id = \$1 AND a.deleted_at IS NULL`).
+		WithArgs("mut-1").
+		WillReturnRows(rows)
+	attachments, err := repo.ListAttachments(context.Background(), "mut-1")
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	require.Equal(t, "score sheet.pdf", attachments[0].Title)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMutationRepositoryExpiry(t *testing.T) {
+	db, mock, cleanup := newMutationRepoMock(t)
+	defer cleanup()
+
+	repo := NewMutationRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(`requested_at < \$2.*escalated_at IS NULL.*ORDER BY requested_at DESC`).
+		WithArgs("PENDING", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "requested_at"}).AddRow("mut-1", "PENDING", now.Add(-time.Hour)))
+	list, err := repo.List(context.Background(), models.MutationFilter{
+		Status:          []models.MutationStatus{models.MutationStatusPending},
+		RequestedBefore: now,
+		Unescalated:     true,
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE mutations SET status = $2, reviewed_at = $3, note = $4 WHERE id = $1 AND status = $5")).
+		WithArgs("mut-1", "REJECTED", now, "expired", "PENDING").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Expire(context.Background(), "mut-1", "expired", now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE mutations SET escalated_at = $2")).
+		WithArgs("mut-2", now, "PENDING").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.ErrorIs(t, repo.Escalate(context.Background(), "mut-2", now), sql.ErrNoRows)
+
+	oldest := now.Add(-72 * time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta("COUNT(*) FILTER (WHERE requested_at < $2) AS overdue")).
+		WithArgs("PENDING", now).
+		WillReturnRows(sqlmock.NewRows([]string{"pending", "overdue", "escalated", "oldest_requested_at"}).AddRow(4, 2, 1, oldest))
+	summary, err := repo.PendingSummary(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 4, summary.Pending)
+	require.Equal(t, 2, summary.Overdue)
+	require.Equal(t, 1, summary.Escalated)
+	require.NotNil(t, summary.OldestRequestedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
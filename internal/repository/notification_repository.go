@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// NotificationRepository persists in-app notifications.
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository constructs the repository.
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateOnce inserts a notification unless the user already has one with the same dedupe key. It
+// reports whether a row was written. New notifications are queued for push delivery.
+func (r *NotificationRepository) CreateOnce(ctx context.Context, notification *models.Notification) (bool, error) {
+	if notification.ID == "" {
+		notification.ID = uuid.NewString()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO notifications (id, user_id, type, title, body, ref_id, dedupe_key, created_at, push_next_at)
+VALUES (:id, :user_id, :type, :title, :body, :ref_id, :dedupe_key, :created_at, :created_at)
+ON CONFLICT (user_id, dedupe_key) DO NOTHING`
+	result, err := r.db.NamedExecContext(ctx, query, notification)
+	if err != nil {
+		return false, fmt.Errorf("create notification: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check notification rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListByUser returns a user's notifications, newest first.
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := `SELECT id, user_id, type, title, body, ref_id, dedupe_key, read_at, created_at FROM notifications WHERE user_id = $1`
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC LIMIT $2"
+	var items []models.Notification
+	if err := r.db.SelectContext(ctx, &items, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return items, nil
+}
+
+// MarkRead stamps a user's notification as read. Marking an already read notification is a no-op.
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID string, readAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, $3) WHERE id = $1 AND user_id = $2`, id, userID, readAt)
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check notification rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// notificationInsertChunk bounds the rows per INSERT when fanning a notification out; each row
+// binds 8 parameters and Postgres allows 65535 per statement.
+const notificationInsertChunk = 500
+
+// CreateForRoles copies notification to every active user holding one of roles, skipping users who
+// already have its dedupe key, and returns how many notifications were written. All copies are
+// written in one transaction and queued for push delivery.
+func (r *NotificationRepository) CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (written int, err error) {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin notification fan-out: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var userIDs []string
+	if err = tx.SelectContext(ctx, &userIDs, `SELECT id FROM users WHERE active = TRUE AND role = ANY($1) ORDER BY id`, pq.Array(names)); err != nil {
+		return 0, fmt.Errorf("list notification recipients: %w", err)
+	}
+	createdAt := notification.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now().UTC()
+	}
+	for start := 0; start < len(userIDs); start += notificationInsertChunk {
+		end := start + notificationInsertChunk
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+		var values strings.Builder
+		args := make([]interface{}, 0, (end-start)*8)
+		for i, userID := range userIDs[start:end] {
+			if i > 0 {
+				values.WriteString(", ")
+			}
+			n := len(args)
+			fmt.Fprintf(&values, "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+8)
+			args = append(args, uuid.NewString(), userID, notification.Type, notification.Title, notification.Body, notification.RefID, notification.DedupeKey, createdAt)
+		}
+		query := `INSERT INTO notifications (id, user_id, type, title, body, ref_id, dedupe_key, created_at, push_next_at)
+VALUES ` + values.String() + `
+ON CONFLICT (user_id, dedupe_key) DO NOTHING`
+		var result sql.Result
+		if result, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, fmt.Errorf("create notifications: %w", err)
+		}
+		var affected int64
+		if affected, err = result.RowsAffected(); err != nil {
+			return 0, fmt.Errorf("check notification rows: %w", err)
+		}
+		written += int(affected)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit notification fan-out: %w", err)
+	}
+	return written, nil
+}
+
+// ClaimPush leases up to limit notifications due for push delivery until leaseUntil and counts the
+// attempt. Leased rows are skipped by other replicas and become due again if this one stops.
+func (r *NotificationRepository) ClaimPush(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error) {
+	const query = `UPDATE notifications SET push_next_at = $2, push_attempts = push_attempts + 1
+WHERE id IN (
+    SELECT id FROM notifications WHERE push_next_at <= $1
+    ORDER BY push_next_at LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, type, title, body, ref_id, created_at, push_attempts`
+	var items []models.Notification
+	if err := r.db.SelectContext(ctx, &items, query, now, leaseUntil, limit); err != nil {
+		return nil, fmt.Errorf("claim push notifications: %w", err)
+	}
+	return items, nil
+}
+
+// MarkPushed records that a notification reached at least one device.
+func (r *NotificationRepository) MarkPushed(ctx context.Context, id string, pushedAt time.Time) error {
+	return r.finishPush(ctx, `UPDATE notifications SET pushed_at = $2, push_next_at = NULL, push_error = NULL WHERE id = $1`, id, pushedAt)
+}
+
+// MarkPushFailed records why a push failed. A nil retryAt gives up on the notification; otherwise it
+// becomes due again at retryAt.
+func (r *NotificationRepository) MarkPushFailed(ctx context.Context, id, reason string, retryAt *time.Time) error {
+	return r.finishPush(ctx, `UPDATE notifications SET push_error = $2, push_next_at = $3 WHERE id = $1`, id, reason, retryAt)
+}
+
+func (r *NotificationRepository) finishPush(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update push state: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check notification rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
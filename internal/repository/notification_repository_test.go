@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestNotificationRepositoryCreateForRoles(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewNotificationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	refID, dedupe := "ann-1", "announcement:ann-1"
+	createdAt := time.Date(2024, 7, 15, 7, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users WHERE active = TRUE AND role = ANY($1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("user-1").AddRow("user-2"))
+	mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8), ($9, $10, $11, $12, $13, $14, $15, $16, $16)")).
+		WithArgs(sqlmock.AnyArg(), "user-1", models.NotificationTypeAnnouncement, "Libur", "Sekolah libur", &refID, &dedupe, createdAt,
+			sqlmock.AnyArg(), "user-2", models.NotificationTypeAnnouncement, "Libur", "Sekolah libur", &refID, &dedupe, createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	written, err := repo.CreateForRoles(context.Background(), []models.UserRole{models.RoleStudent, models.RoleGuardian}, models.Notification{
+		Type:      models.NotificationTypeAnnouncement,
+		Title:     "Libur",
+		Body:      "Sekolah libur",
+		RefID:     &refID,
+		DedupeKey: &dedupe,
+		CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRepositoryPushQueue(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewNotificationRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now().UTC()
+	lease := now.Add(time.Minute)
+	mock.ExpectQuery(`UPDATE notifications SET push_next_at = \$2, push_attempts = push_attempts \+ 1\s+WHERE id IN \(.*FOR UPDATE SKIP LOCKED`).
+		WithArgs(now, lease, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "title", "body", "ref_id", "created_at", "push_attempts"}).
+			AddRow("n-1", "user-1", models.NotificationTypeAttendanceAlert, "Alert", "Body", nil, now, 2))
+	items, err := repo.ClaimPush(context.Background(), now, lease, 50)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, 2, items[0].PushAttempts)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notifications SET pushed_at = $2, push_next_at = NULL")).
+		WithArgs("n-1", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.MarkPushed(context.Background(), "n-1", now))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notifications SET push_error = $2, push_next_at = $3")).
+		WithArgs("n-2", "fcm returned 503", nil).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.MarkPushFailed(context.Background(), "n-2", "fcm returned 503", nil), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const outboxEventColumns = `id, event_type, schema_version, aggregate_id, payload, occurred_at, attempts, last_error, claimed_by, claimed_at, published_at`
+
+// OutboxRepository stores domain events until the relay has published them.
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewOutboxRepository constructs the repository.
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) exec(exec sqlx.ExtContext) sqlx.ExtContext {
+	if exec != nil {
+		return exec
+	}
+	return r.db
+}
+
+// Enqueue stores event. Pass the transaction that writes the change the event describes so both
+// commit or roll back together.
+func (r *OutboxRepository) Enqueue(ctx context.Context, exec sqlx.ExtContext, event *models.OutboxEvent) error {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO outbox_events (id, event_type, schema_version, aggregate_id, payload, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := r.exec(exec).ExecContext(ctx, query, event.ID, event.EventType, event.SchemaVersion, event.AggregateID, event.Payload, event.OccurredAt); err != nil {
+		return fmt.Errorf("enqueue outbox event: %w", err)
+	}
+	return nil
+}
+
+// ClaimPending takes ownership of up to limit unpublished events, oldest first, that no relay holds
+// a claim on newer than staleBefore. Rows locked by another relay are skipped, so concurrent relays
+// never publish the same event at once.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, relayID string, staleBefore time.Time, limit int) ([]models.OutboxEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	const query = `UPDATE outbox_events SET claimed_by = $1, claimed_at = $2
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE published_at IS NULL
+      AND (claimed_at IS NULL OR claimed_at < $3)
+    ORDER BY occurred_at ASC
+    LIMIT $4
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + outboxEventColumns
+	var events []models.OutboxEvent
+	if err := r.db.SelectContext(ctx, &events, query, relayID, time.Now().UTC(), staleBefore, limit); err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	return events, nil
+}
+
+// MarkPublished records that relayID delivered an event. It returns sql.ErrNoRows when the claim
+// was taken over by another relay.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id, relayID string) error {
+	const query = `UPDATE outbox_events SET published_at = $1, attempts = attempts + 1, last_error = NULL
+WHERE id = $2 AND claimed_by = $3 AND published_at IS NULL`
+	return r.finish(ctx, "mark outbox event published", query, time.Now().UTC(), id, relayID)
+}
+
+// MarkFailed records a failed delivery and releases the claim so the event is retried.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id, relayID, reason string) error {
+	const query = `UPDATE outbox_events SET attempts = attempts + 1, last_error = $1, claimed_by = NULL, claimed_at = NULL
+WHERE id = $2 AND claimed_by = $3 AND published_at IS NULL`
+	return r.finish(ctx, "mark outbox event failed", query, reason, id, relayID)
+}
+
+func (r *OutboxRepository) finish(ctx context.Context, op, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check %s rows: %w", op, err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgePublished deletes events published before cutoff and returns how many were removed.
+func (r *OutboxRepository) PurgePublished(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at IS NOT NULL AND published_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge outbox events: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check purged outbox events: %w", err)
+	}
+	return affected, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestOutboxRepositoryEnqueueInTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := NewOutboxRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO outbox_events")).
+		WithArgs(sqlmock.AnyArg(), models.DomainEventGradeFinalized, 1, "class-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	require.NoError(t, err)
+	event := &models.OutboxEvent{EventType: models.DomainEventGradeFinalized, SchemaVersion: 1, AggregateID: "class-1", Payload: types.JSONText(`{}`)}
+	require.NoError(t, repo.Enqueue(context.Background(), tx, event))
+	require.NoError(t, tx.Commit())
+	assert.NotEmpty(t, event.ID)
+	assert.False(t, event.OccurredAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepositoryClaimAndFinish(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewOutboxRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	staleBefore := now.Add(-time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "event_type", "schema_version", "aggregate_id", "payload", "occurred_at", "attempts", "last_error", "claimed_by", "claimed_at", "published_at"}).
+		AddRow("evt-2", "attendance.marked", 1, "att-2", `{}`, now, 0, nil, "relay-a", now, nil).
+		AddRow("evt-1", "attendance.marked", 1, "att-1", `{}`, now.Add(-time.Second), 1, "timeout", "relay-a", now, nil)
+	mock.ExpectQuery(`UPDATE outbox_events SET claimed_by = \$1, claimed_at = \$2\s+WHERE id IN \(.*FOR UPDATE SKIP LOCKED`).
+		WithArgs("relay-a", sqlmock.AnyArg(), staleBefore, 100).
+		WillReturnRows(rows)
+
+	events, err := repo.ClaimPending(context.Background(), "relay-a", staleBefore, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "evt-1", events[0].ID)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE outbox_events SET published_at = $1")).
+		WithArgs(sqlmock.AnyArg(), "evt-1", "relay-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.MarkPublished(context.Background(), "evt-1", "relay-a"))
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE outbox_events SET attempts = attempts + 1, last_error = $1, claimed_by = NULL")).
+		WithArgs("broker down", "evt-2", "relay-a").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.MarkFailed(context.Background(), "evt-2", "relay-a", "broker down"), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// partialUpdateQuery builds a named UPDATE that writes only columns (plus updated_at) of the row
+// identified by :id. Columns must appear in allowed so callers cannot reach unexpected fields.
+func partialUpdateQuery(table string, columns []string, allowed map[string]struct{}) (string, error) {
+	if len(columns) == 0 {
+		return "", fmt.Errorf("no columns to update")
+	}
+	sets := make([]string, 0, len(columns)+1)
+	seen := make(map[string]struct{}, len(columns))
+	for _, column := range columns {
+		if _, ok := allowed[column]; !ok {
+			return "", fmt.Errorf("column %q cannot be updated", column)
+		}
+		if _, dup := seen[column]; dup {
+			continue
+		}
+		seen[column] = struct{}{}
+		sets = append(sets, fmt.Sprintf("%s = :%s", column, column))
+	}
+	sets = append(sets, "updated_at = :updated_at")
+	return fmt.Sprintf("UPDATE %s SET %s WHERE id = :id", table, strings.Join(sets, ", ")), nil
+}
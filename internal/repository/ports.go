@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/noah-isme/sma-adp-api/internal/ports"
+
+// Compile-time checks that the repositories implement the shared service ports.
+var (
+	_ ports.TermResolver             = (*TermRepository)(nil)
+	_ ports.ClassReader              = (*ClassRepository)(nil)
+	_ ports.SubjectReader            = (*SubjectRepository)(nil)
+	_ ports.TeacherReader            = (*TeacherRepository)(nil)
+	_ ports.StudentReader            = (*StudentRepository)(nil)
+	_ ports.TeacherPreferenceReader  = (*TeacherPreferenceRepository)(nil)
+	_ ports.TeacherAssignmentLister  = (*TeacherAssignmentRepository)(nil)
+	_ ports.TeacherAssignmentChecker = (*TeacherAssignmentRepository)(nil)
+	_ ports.ClassAccessChecker       = (*TeacherAssignmentRepository)(nil)
+	_ ports.AnalyticsRepository      = (*AnalyticsRepository)(nil)
+	_ ports.AuditLogger              = (*UserRepository)(nil)
+)
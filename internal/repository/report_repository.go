@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
 )
@@ -30,8 +33,11 @@ func (r *ReportRepository) Create(ctx context.Context, job *models.ReportJob) er
 	if job.Status == "" {
 		job.Status = models.ReportStatusQueued
 	}
-	const query = `INSERT INTO report_jobs (id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message)
-VALUES (:id, :type, :params, :status, :progress, :result_url, :created_by, :created_at, :finished_at, :error_message)`
+	if job.Priority == "" {
+		job.Priority = models.ReportPriorityNormal
+	}
+	const query = `INSERT INTO report_jobs (id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint)
+VALUES (:id, :type, :params, :status, :progress, :result_url, :created_by, :created_at, :finished_at, :error_message, :priority, :claimed_by, :heartbeat_at, :fingerprint)`
 	if job.CreatedAt.IsZero() {
 		job.CreatedAt = time.Now().UTC()
 	}
@@ -43,7 +49,7 @@ VALUES (:id, :type, :params, :status, :progress, :result_url, :created_by, :crea
 
 // GetByID returns a job row by its identifier.
 func (r *ReportRepository) GetByID(ctx context.Context, id string) (*models.ReportJob, error) {
-	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message
+	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint
 FROM report_jobs WHERE id = $1`
 	var job models.ReportJob
 	if err := r.db.GetContext(ctx, &job, query, id); err != nil {
@@ -52,6 +58,24 @@ FROM report_jobs WHERE id = $1`
 	return &job, nil
 }
 
+// FindReusable returns the newest job with the given fingerprint that is still queued or running,
+// or that finished at or after finishedSince. A non-empty createdBy limits the search to that
+// requester's jobs. It returns sql.ErrNoRows when there is nothing to reuse.
+func (r *ReportRepository) FindReusable(ctx context.Context, fingerprint, createdBy string, finishedSince time.Time) (*models.ReportJob, error) {
+	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint
+FROM report_jobs
+WHERE fingerprint = $1
+  AND ($2 = '' OR created_by = $2)
+  AND (status IN ('QUEUED', 'PROCESSING') OR (status = 'FINISHED' AND finished_at >= $3))
+ORDER BY created_at DESC
+LIMIT 1`
+	var job models.ReportJob
+	if err := r.db.GetContext(ctx, &job, query, fingerprint, createdBy, finishedSince); err != nil {
+		return nil, fmt.Errorf("find reusable report job: %w", err)
+	}
+	return &job, nil
+}
+
 // UpdateReportJobParams defines the mutable fields.
 type UpdateReportJobParams struct {
 	Status       *models.ReportStatus
@@ -106,26 +130,90 @@ func (r *ReportRepository) Update(ctx context.Context, id string, params UpdateR
 	return nil
 }
 
-// ListQueued fetches queued jobs (used for cold start recovery).
-func (r *ReportRepository) ListQueued(ctx context.Context, limit int) ([]models.ReportJob, error) {
+// ClaimQueued takes ownership of up to limit jobs nobody is working on, highest priority first:
+// queued jobs without a live claim and processing jobs whose worker stopped sending heartbeats.
+// Claimed jobs are reset to QUEUED for workerID. Rows locked by another replica are skipped, so concurrent recoveries never
+// return the same job; calling it again pages through the remaining backlog.
+func (r *ReportRepository) ClaimQueued(ctx context.Context, workerID string, staleBefore time.Time, limit int) ([]models.ReportJob, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message
-FROM report_jobs WHERE status = 'QUEUED' ORDER BY created_at ASC LIMIT $1`
+	const query = `UPDATE report_jobs SET status = 'QUEUED', progress = 0, claimed_by = $1, heartbeat_at = $2
+WHERE id IN (
+    SELECT id FROM report_jobs
+    WHERE status IN ('QUEUED', 'PROCESSING')
+      AND (claimed_by IS NULL OR heartbeat_at IS NULL OR heartbeat_at < $3)
+    ORDER BY priority DESC, created_at ASC
+    LIMIT $4
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint`
 	var jobs []models.ReportJob
-	if err := r.db.SelectContext(ctx, &jobs, query, limit); err != nil {
-		return nil, fmt.Errorf("list queued report jobs: %w", err)
+	if err := r.db.SelectContext(ctx, &jobs, query, workerID, time.Now().UTC(), staleBefore, limit); err != nil {
+		return nil, fmt.Errorf("claim queued report jobs: %w", err)
 	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Priority.Rank() != jobs[j].Priority.Rank() {
+			return jobs[i].Priority.Rank() > jobs[j].Priority.Rank()
+		}
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
 	return jobs, nil
 }
 
+// QueuePosition returns the 1-based position of a queued job: the number of queued jobs that will
+// be picked up before it, plus one.
+func (r *ReportRepository) QueuePosition(ctx context.Context, job *models.ReportJob) (int, error) {
+	const query = `SELECT COUNT(*) + 1 FROM report_jobs
+WHERE status = 'QUEUED' AND id <> $1 AND (priority > $2 OR (priority = $2 AND created_at < $3))`
+	var position int
+	if err := r.db.GetContext(ctx, &position, query, job.ID, job.Priority, job.CreatedAt); err != nil {
+		return 0, fmt.Errorf("report job queue position: %w", err)
+	}
+	return position, nil
+}
+
+// Claim marks a queued job as processing by workerID. It returns sql.ErrNoRows when another worker
+// holds a live claim on the job or it is no longer queued.
+func (r *ReportRepository) Claim(ctx context.Context, id, workerID string, staleBefore time.Time) (*models.ReportJob, error) {
+	const query = `UPDATE report_jobs SET status = 'PROCESSING', claimed_by = $2, heartbeat_at = $3
+WHERE id = (
+    SELECT id FROM report_jobs
+    WHERE id = $1 AND status = 'QUEUED'
+      AND (claimed_by IS NULL OR claimed_by = $2 OR heartbeat_at IS NULL OR heartbeat_at < $4)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint`
+	var job models.ReportJob
+	if err := r.db.GetContext(ctx, &job, query, id, workerID, time.Now().UTC(), staleBefore); err != nil {
+		return nil, fmt.Errorf("claim report job: %w", err)
+	}
+	return &job, nil
+}
+
+// Heartbeat extends workerID's claim on a job. It returns sql.ErrNoRows when the claim was taken
+// over by another worker.
+func (r *ReportRepository) Heartbeat(ctx context.Context, id, workerID string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE report_jobs SET heartbeat_at = $1 WHERE id = $2 AND claimed_by = $3`, time.Now().UTC(), id, workerID)
+	if err != nil {
+		return fmt.Errorf("report job heartbeat: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check report job heartbeat rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 // ListFinishedBefore retrieves completed jobs prior to cutoff for cleanup.
 func (r *ReportRepository) ListFinishedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.ReportJob, error) {
 	if limit <= 0 {
 		limit = 50
 	}
-	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message
+	const query = `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint
 FROM report_jobs WHERE status = 'FINISHED' AND finished_at IS NOT NULL AND finished_at < $1 ORDER BY finished_at ASC LIMIT $2`
 	var jobs []models.ReportJob
 	if err := r.db.SelectContext(ctx, &jobs, query, cutoff, limit); err != nil {
@@ -133,3 +221,39 @@ FROM report_jobs WHERE status = 'FINISHED' AND finished_at IS NOT NULL AND finis
 	}
 	return jobs, nil
 }
+
+// ListFailed returns failed jobs created at or after since, optionally restricted to ids, oldest
+// first.
+func (r *ReportRepository) ListFailed(ctx context.Context, since time.Time, ids []string) ([]models.ReportJob, error) {
+	query := `SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint
+FROM report_jobs WHERE status = 'FAILED' AND created_at >= $1`
+	args := []interface{}{since}
+	if len(ids) > 0 {
+		query += ` AND id = ANY($2)`
+		args = append(args, pq.Array(ids))
+	}
+	query += ` ORDER BY created_at ASC`
+	var jobs []models.ReportJob
+	if err := r.db.SelectContext(ctx, &jobs, query, args...); err != nil {
+		return nil, fmt.Errorf("list failed report jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// RequeueFailed puts failed jobs created at or after since, optionally restricted to ids, back in
+// the queue without a claim, so the next recovery sweep picks them up. It returns the requeued IDs.
+func (r *ReportRepository) RequeueFailed(ctx context.Context, since time.Time, ids []string) ([]string, error) {
+	query := `UPDATE report_jobs SET status = 'QUEUED', progress = 0, error_message = NULL, finished_at = NULL, claimed_by = NULL, heartbeat_at = NULL
+WHERE status = 'FAILED' AND created_at >= $1`
+	args := []interface{}{since}
+	if len(ids) > 0 {
+		query += ` AND id = ANY($2)`
+		args = append(args, pq.Array(ids))
+	}
+	query += ` RETURNING id`
+	var requeued []string
+	if err := r.db.SelectContext(ctx, &requeued, query, args...); err != nil {
+		return nil, fmt.Errorf("requeue failed report jobs: %w", err)
+	}
+	return requeued, nil
+}
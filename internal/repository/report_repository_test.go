@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -25,7 +26,7 @@ func TestReportRepositoryCreateAndGet(t *testing.T) {
 
 	repo := NewReportRepository(db)
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO report_jobs")).
-		WithArgs(sqlmock.AnyArg(), "grades", sqlmock.AnyArg(), "QUEUED", 0, nil, "user-1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), "grades", sqlmock.AnyArg(), "QUEUED", 0, nil, "user-1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), models.ReportPriorityNormal, nil, nil, "").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	job := &models.ReportJob{
@@ -35,9 +36,9 @@ func TestReportRepositoryCreateAndGet(t *testing.T) {
 	}
 	require.NoError(t, repo.Create(context.Background(), job))
 
-	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message"}).
-		AddRow(job.ID, "grades", `{"termId":"term-1","format":"csv","extras":{}}`, "QUEUED", 0, nil, "user-1", time.Now(), nil, nil)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message FROM report_jobs WHERE id = $1")).
+	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message", "claimed_by", "heartbeat_at"}).
+		AddRow(job.ID, "grades", `{"termId":"term-1","format":"csv","extras":{}}`, "QUEUED", 0, nil, "user-1", time.Now(), nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint FROM report_jobs WHERE id = $1")).
 		WithArgs(job.ID).
 		WillReturnRows(rows)
 
@@ -70,20 +71,86 @@ func TestReportRepositoryUpdate(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestReportRepositoryListQueued(t *testing.T) {
+func TestReportRepositoryClaimQueued(t *testing.T) {
 	db, mock, cleanup := newReportRepoMock(t)
 	defer cleanup()
 	repo := NewReportRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message"}).
-		AddRow("job-1", "attendance", `{"termId":"term-1","format":"csv","extras":{}}`, "QUEUED", 0, nil, "user-1", time.Now(), nil, nil)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message FROM report_jobs WHERE status = 'QUEUED' ORDER BY created_at ASC LIMIT $1")).
-		WithArgs(20).
+	staleBefore := time.Now().Add(-2 * time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message", "claimed_by", "heartbeat_at"}).
+		AddRow("job-2", "attendance", `{"termId":"term-1","format":"csv","extras":{}}`, "QUEUED", 0, nil, "user-1", time.Now(), nil, nil, "worker-a", time.Now()).
+		AddRow("job-1", "attendance", `{"termId":"term-1","format":"csv","extras":{}}`, "QUEUED", 0, nil, "user-1", time.Now().Add(-time.Hour), nil, nil, "worker-a", time.Now())
+	mock.ExpectQuery(`UPDATE report_jobs SET status = 'QUEUED', progress = 0, claimed_by = \$1, heartbeat_at = \$2\s+WHERE id IN \(.*FOR UPDATE SKIP LOCKED`).
+		WithArgs("worker-a", sqlmock.AnyArg(), staleBefore, 20).
 		WillReturnRows(rows)
 
-	jobs, err := repo.ListQueued(context.Background(), 0)
+	jobs, err := repo.ClaimQueued(context.Background(), "worker-a", staleBefore, 0)
 	require.NoError(t, err)
-	require.Len(t, jobs, 1)
+	require.Len(t, jobs, 2)
+	require.Equal(t, "job-1", jobs[0].ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepositoryFindReusable(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewReportRepository(db)
+
+	since := time.Now().Add(-10 * time.Minute)
+	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message", "priority", "claimed_by", "heartbeat_at", "fingerprint"}).
+		AddRow("job-1", "grades", `{"termId":"term-1","format":"csv","extras":{}}`, "PROCESSING", 10, nil, "user-1", time.Now(), nil, nil, 1, "worker-a", time.Now(), "abc")
+	mock.ExpectQuery(`WHERE fingerprint = \$1\s+AND \(\$2 = '' OR created_by = \$2\)`).
+		WithArgs("abc", "", since).
+		WillReturnRows(rows)
+
+	job, err := repo.FindReusable(context.Background(), "abc", "", since)
+	require.NoError(t, err)
+	require.Equal(t, "job-1", job.ID)
+	require.Equal(t, models.ReportPriorityNormal, job.Priority)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepositoryQueuePosition(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewReportRepository(db)
+
+	createdAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) + 1 FROM report_jobs WHERE status = 'QUEUED' AND id <> $1 AND (priority > $2 OR (priority = $2 AND created_at < $3))")).
+		WithArgs("job-1", int64(2), createdAt).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(3))
+
+	position, err := repo.QueuePosition(context.Background(), &models.ReportJob{ID: "job-1", Priority: models.ReportPriorityHigh, CreatedAt: createdAt})
+	require.NoError(t, err)
+	require.Equal(t, 3, position)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepositoryClaimTakenElsewhere(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewReportRepository(db)
+
+	mock.ExpectQuery(`UPDATE report_jobs SET status = 'PROCESSING'.*FOR UPDATE SKIP LOCKED`).
+		WithArgs("job-1", "worker-b", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.Claim(context.Background(), "job-1", "worker-b", time.Now().Add(-2*time.Minute))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReportRepositoryHeartbeatLostClaim(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewReportRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE report_jobs SET heartbeat_at = $1 WHERE id = $2 AND claimed_by = $3")).
+		WithArgs(sqlmock.AnyArg(), "job-1", "worker-a").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Heartbeat(context.Background(), "job-1", "worker-a")
+	require.ErrorIs(t, err, sql.ErrNoRows)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -92,9 +159,9 @@ func TestReportRepositoryListFinishedBefore(t *testing.T) {
 	defer cleanup()
 	repo := NewReportRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message"}).
-		AddRow("job-1", "grades", `{"termId":"term-1","format":"csv","extras":{}}`, "FINISHED", 100, "/api/v1/export/token", "user-1", time.Now().Add(-48*time.Hour), time.Now().Add(-25*time.Hour), nil)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message FROM report_jobs WHERE status = 'FINISHED' AND finished_at IS NOT NULL AND finished_at < $1 ORDER BY finished_at ASC LIMIT $2")).
+	rows := sqlmock.NewRows([]string{"id", "type", "params", "status", "progress", "result_url", "created_by", "created_at", "finished_at", "error_message", "claimed_by", "heartbeat_at"}).
+		AddRow("job-1", "grades", `{"termId":"term-1","format":"csv","extras":{}}`, "FINISHED", 100, "/api/v1/export/token", "user-1", time.Now().Add(-48*time.Hour), time.Now().Add(-25*time.Hour), nil, "worker-a", time.Now().Add(-25*time.Hour))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, type, params, status, progress, result_url, created_by, created_at, finished_at, error_message, priority, claimed_by, heartbeat_at, fingerprint FROM report_jobs WHERE status = 'FINISHED' AND finished_at IS NOT NULL AND finished_at < $1 ORDER BY finished_at ASC LIMIT $2")).
 		WithArgs(sqlmock.AnyArg(), 50).
 		WillReturnRows(rows)
 
@@ -103,3 +170,27 @@ func TestReportRepositoryListFinishedBefore(t *testing.T) {
 	require.Len(t, jobs, 1)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestReportRepositoryRequeueFailed(t *testing.T) {
+	db, mock, cleanup := newReportRepoMock(t)
+	defer cleanup()
+	repo := NewReportRepository(db)
+
+	since := time.Now().Add(-24 * time.Hour)
+	mock.ExpectQuery(`UPDATE report_jobs SET status = 'QUEUED', progress = 0, error_message = NULL, finished_at = NULL, claimed_by = NULL, heartbeat_at = NULL\s+WHERE status = 'FAILED' AND created_at >= \$1 AND id = ANY\(\$2\) RETURNING id`).
+		WithArgs(since, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("job-1"))
+
+	ids, err := repo.RequeueFailed(context.Background(), since, []string{"job-1", "job-2"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"job-1"}, ids)
+
+	mock.ExpectQuery(`SELECT .* FROM report_jobs WHERE status = 'FAILED' AND created_at >= \$1 ORDER BY created_at ASC`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("job-2", "FAILED"))
+
+	jobs, err := repo.ListFailed(context.Background(), since, nil)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
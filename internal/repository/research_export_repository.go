@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// ResearchExportRepository reads the record-level data of research exports. It selects no names,
+// NIS or contact details; callers pseudonymize the IDs it returns.
+type ResearchExportRepository struct {
+	db *sqlx.DB
+}
+
+// NewResearchExportRepository constructs the repository.
+func NewResearchExportRepository(db *sqlx.DB) *ResearchExportRepository {
+	return &ResearchExportRepository{db: db}
+}
+
+// Attendance returns the daily attendance marks of a term's enrollments, optionally for one class.
+func (r *ResearchExportRepository) Attendance(ctx context.Context, termID, classID string) ([]models.ResearchAttendanceRow, error) {
+	query := `SELECT e.student_id, e.class_id, da.date, da.status
+FROM daily_attendance da
+JOIN enrollments e ON e.id = da.enrollment_id
+WHERE e.term_id = $1 AND ` + attendanceTermWindow("da.date", "$1")
+	args := []interface{}{termID}
+	if classID != "" {
+		args = append(args, classID)
+		query += fmt.Sprintf(" AND e.class_id = $%d", len(args))
+	}
+	query += " ORDER BY e.class_id, e.student_id, da.date"
+	rows := []models.ResearchAttendanceRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("list research attendance: %w", err)
+	}
+	return rows, nil
+}
+
+// Grades returns the grade component scores of a term's enrollments, optionally for one class.
+func (r *ResearchExportRepository) Grades(ctx context.Context, termID, classID string) ([]models.ResearchGradeRow, error) {
+	query := `SELECT e.student_id, e.class_id, s.code AS subject_code, gc.code AS component_code, g.grade_value
+FROM grades g
+JOIN enrollments e ON e.id = g.enrollment_id
+JOIN subjects s ON s.id = g.subject_id
+JOIN grade_components gc ON gc.id = g.component_id
+WHERE e.term_id = $1`
+	args := []interface{}{termID}
+	if classID != "" {
+		args = append(args, classID)
+		query += fmt.Sprintf(" AND e.class_id = $%d", len(args))
+	}
+	query += " ORDER BY e.class_id, e.student_id, s.code, gc.code"
+	rows := []models.ResearchGradeRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("list research grades: %w", err)
+	}
+	return rows, nil
+}
+
+// Behavior returns the behavior notes dated within a term for its enrollments, optionally for one
+// class. Descriptions are left out because they routinely name students.
+func (r *ResearchExportRepository) Behavior(ctx context.Context, termID, classID string) ([]models.ResearchBehaviorRow, error) {
+	query := `SELECT e.student_id, e.class_id, bn.date, bn.note_type, bn.points
+FROM behavior_notes bn
+JOIN enrollments e ON e.student_id = bn.student_id AND e.term_id = $1
+JOIN terms t ON t.id = e.term_id
+WHERE bn.date BETWEEN t.start_date AND t.end_date`
+	args := []interface{}{termID}
+	if classID != "" {
+		args = append(args, classID)
+		query += fmt.Sprintf(" AND e.class_id = $%d", len(args))
+	}
+	query += " ORDER BY e.class_id, e.student_id, bn.date"
+	rows := []models.ResearchBehaviorRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("list research behavior: %w", err)
+	}
+	return rows, nil
+}
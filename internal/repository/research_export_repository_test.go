@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResearchExportRepositoryFiltersByTermAndClass(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewResearchExportRepository(sqlx.NewDb(db, "sqlmock"))
+	date := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT e.student_id, e.class_id, da.date, da.status\nFROM daily_attendance da")+`.*AND e\.class_id = \$2 ORDER BY`).
+		WithArgs("term-1", "class-1").
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "class_id", "date", "status"}).AddRow("stu-1", "class-1", date, "H"))
+	attendance, err := repo.Attendance(context.Background(), "term-1", "class-1")
+	require.NoError(t, err)
+	require.Len(t, attendance, 1)
+	assert.Equal(t, "stu-1", attendance[0].StudentID)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT e.student_id, e.class_id, bn.date, bn.note_type, bn.points\nFROM behavior_notes bn") + `.*ORDER BY`).
+		WithArgs("term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"student_id", "class_id", "date", "note_type", "points"}))
+	behavior, err := repo.Behavior(context.Background(), "term-1", "")
+	require.NoError(t, err)
+	assert.Empty(t, behavior)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
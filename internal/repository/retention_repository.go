@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// retentionTarget describes where a data class lives and which of its rows are past a cutoff ($1).
+type retentionTarget struct {
+	table    string
+	eligible string
+}
+
+// Refresh tokens age from their revocation, or from expiry when they were never revoked; live
+// tokens are never eligible.
+var retentionTargets = map[models.RetentionDataClass]retentionTarget{
+	models.RetentionRefreshTokens: {
+		table:    "refresh_tokens",
+		eligible: `((t.revoked AND COALESCE(t.revoked_at, t.created_at) < $1) OR t.expires_at < $1)`,
+	},
+	models.RetentionAuditLogs: {
+		table:    "audit_logs",
+		eligible: `t.created_at < $1`,
+	},
+}
+
+// retentionHeld matches rows of the data class ($2) kept by an active legal hold on the class or
+// on the row's user.
+const retentionHeld = `EXISTS (SELECT 1 FROM legal_holds h WHERE h.data_class = $2 AND h.released_at IS NULL
+    AND (h.user_id IS NULL OR h.user_id = t.user_id))`
+
+const legalHoldColumns = `id, data_class, user_id, reason, created_by, created_at, released_by, released_at`
+
+// RetentionRepository purges refresh tokens and audit logs past their retention period and manages
+// the legal holds that exempt them.
+type RetentionRepository struct {
+	db *sqlx.DB
+}
+
+// NewRetentionRepository constructs the repository.
+func NewRetentionRepository(db *sqlx.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// Candidates counts the rows of class past cutoff and how many of them are held.
+func (r *RetentionRepository) Candidates(ctx context.Context, class models.RetentionDataClass, cutoff time.Time) (models.RetentionCandidates, error) {
+	var counts models.RetentionCandidates
+	target, ok := retentionTargets[class]
+	if !ok {
+		return counts, fmt.Errorf("unknown retention data class %q", class)
+	}
+	query := fmt.Sprintf(`SELECT COUNT(*) AS eligible, COUNT(*) FILTER (WHERE %s) AS held
+FROM %s t WHERE %s`, retentionHeld, target.table, target.eligible)
+	if err := r.db.GetContext(ctx, &counts, query, cutoff, class); err != nil {
+		return counts, fmt.Errorf("count retention candidates: %w", err)
+	}
+	return counts, nil
+}
+
+// Purge deletes up to limit unheld rows of class past cutoff and returns how many were removed.
+func (r *RetentionRepository) Purge(ctx context.Context, class models.RetentionDataClass, cutoff time.Time, limit int) (int64, error) {
+	target, ok := retentionTargets[class]
+	if !ok {
+		return 0, fmt.Errorf("unknown retention data class %q", class)
+	}
+	query := fmt.Sprintf(`DELETE FROM %[1]s WHERE id IN (
+    SELECT t.id FROM %[1]s t WHERE %[2]s AND NOT %[3]s LIMIT $3)`, target.table, target.eligible, retentionHeld)
+	res, err := r.db.ExecContext(ctx, query, cutoff, class, limit)
+	if err != nil {
+		return 0, fmt.Errorf("purge %s: %w", target.table, err)
+	}
+	purged, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check %s purge rows: %w", target.table, err)
+	}
+	return purged, nil
+}
+
+// CreateHold persists a legal hold.
+func (r *RetentionRepository) CreateHold(ctx context.Context, hold *models.LegalHold) error {
+	if hold.ID == "" {
+		hold.ID = uuid.NewString()
+	}
+	if hold.CreatedAt.IsZero() {
+		hold.CreatedAt = time.Now().UTC()
+	}
+	const query = `INSERT INTO legal_holds (id, data_class, user_id, reason, created_by, created_at)
+VALUES (:id, :data_class, :user_id, :reason, :created_by, :created_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, hold); err != nil {
+		return fmt.Errorf("create legal hold: %w", err)
+	}
+	return nil
+}
+
+// FindHold loads a legal hold by id.
+func (r *RetentionRepository) FindHold(ctx context.Context, id string) (*models.LegalHold, error) {
+	query := `SELECT ` + legalHoldColumns + ` FROM legal_holds WHERE id = $1`
+	var hold models.LegalHold
+	if err := r.db.GetContext(ctx, &hold, query, id); err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// ListHolds returns the legal holds, newest first; released ones only when includeReleased is set.
+func (r *RetentionRepository) ListHolds(ctx context.Context, includeReleased bool) ([]models.LegalHold, error) {
+	query := `SELECT ` + legalHoldColumns + ` FROM legal_holds`
+	if !includeReleased {
+		query += ` WHERE released_at IS NULL`
+	}
+	query += ` ORDER BY created_at DESC, id`
+	holds := make([]models.LegalHold, 0)
+	if err := r.db.SelectContext(ctx, &holds, query); err != nil {
+		return nil, fmt.Errorf("list legal holds: %w", err)
+	}
+	return holds, nil
+}
+
+// ReleaseHold ends an active legal hold. It returns sql.ErrNoRows when the hold does not exist or
+// was already released.
+func (r *RetentionRepository) ReleaseHold(ctx context.Context, id, releasedBy string, at time.Time) error {
+	const query = `UPDATE legal_holds SET released_by = $2, released_at = $3 WHERE id = $1 AND released_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, releasedBy, at)
+	if err != nil {
+		return fmt.Errorf("release legal hold: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check legal hold release rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestRetentionRepositoryPurgeExcludesHeldRows(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRetentionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	cutoff := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM legal_holds h WHERE h.data_class = $2")+`.*`+
+		regexp.QuoteMeta("FROM audit_logs t WHERE t.created_at < $1")).
+		WithArgs(cutoff, models.RetentionAuditLogs).
+		WillReturnRows(sqlmock.NewRows([]string{"eligible", "held"}).AddRow(12, 5))
+	counts, err := repo.Candidates(context.Background(), models.RetentionAuditLogs, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, models.RetentionCandidates{Eligible: 12, Held: 5}, counts)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM refresh_tokens WHERE id IN (")+`.*`+
+		regexp.QuoteMeta("OR t.expires_at < $1) AND NOT EXISTS (SELECT 1 FROM legal_holds h")+`.*`+
+		regexp.QuoteMeta("(h.user_id IS NULL OR h.user_id = t.user_id)) LIMIT $3)")).
+		WithArgs(cutoff, models.RetentionRefreshTokens, 1000).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	purged, err := repo.Purge(context.Background(), models.RetentionRefreshTokens, cutoff, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), purged)
+
+	_, err = repo.Purge(context.Background(), models.RetentionDataClass("SESSIONS"), cutoff, 1000)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRetentionRepositoryReleaseHold(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewRetentionRepository(sqlx.NewDb(db, "sqlmock"))
+
+	at := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE legal_holds SET released_by = $2, released_at = $3 WHERE id = $1 AND released_at IS NULL")).
+		WithArgs("hold-1", "admin-1", at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.ReleaseHold(context.Background(), "hold-1", "admin-1", at))
+
+	// A hold released in the meantime no longer matches.
+	mock.ExpectExec("UPDATE legal_holds SET released_by").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.ReleaseHold(context.Background(), "hold-1", "admin-1", at), sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -85,7 +85,7 @@ func (r *ScheduleRepository) List(ctx context.Context, filter models.ScheduleFil
 	}
 	offset := (page - 1) * size
 
-	query := fmt.Sprintf("SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at %s ORDER BY %s %s LIMIT %d OFFSET %d", base, sortBy, order, size, offset)
+	query := fmt.Sprintf("SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at %s ORDER BY %s %s LIMIT %d OFFSET %d", base, sortBy, order, size, offset)
 	var schedules []models.Schedule
 	if err := r.db.SelectContext(ctx, &schedules, query, args...); err != nil {
 		return nil, 0, fmt.Errorf("list schedules: %w", err)
@@ -102,7 +102,7 @@ func (r *ScheduleRepository) List(ctx context.Context, filter models.ScheduleFil
 
 // FindByID loads a schedule by id.
 func (r *ScheduleRepository) FindByID(ctx context.Context, id string) (*models.Schedule, error) {
-	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at FROM schedules WHERE id = $1`
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at FROM schedules WHERE id = $1`
 	var sched models.Schedule
 	if err := r.db.GetContext(ctx, &sched, query, id); err != nil {
 		return nil, err
@@ -112,7 +112,7 @@ func (r *ScheduleRepository) FindByID(ctx context.Context, id string) (*models.S
 
 // FindConflicts returns schedules that overlap on term/day/time slot for validation.
 func (r *ScheduleRepository) FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error) {
-	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at FROM schedules WHERE term_id = $1 AND day_of_week = $2 AND time_slot = $3`
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at FROM schedules WHERE term_id = $1 AND day_of_week = $2 AND time_slot = $3`
 	var schedules []models.Schedule
 	if err := r.db.SelectContext(ctx, &schedules, query, termID, dayOfWeek, timeSlot); err != nil {
 		return nil, fmt.Errorf("find schedule conflicts: %w", err)
@@ -120,9 +120,19 @@ func (r *ScheduleRepository) FindConflicts(ctx context.Context, termID, dayOfWee
 	return schedules, nil
 }
 
+// ListByTerm returns every schedule of a term so callers can match many slots in memory.
+func (r *ScheduleRepository) ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error) {
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at FROM schedules WHERE term_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
+	var schedules []models.Schedule
+	if err := r.db.SelectContext(ctx, &schedules, query, termID); err != nil {
+		return nil, fmt.Errorf("list schedules by term: %w", err)
+	}
+	return schedules, nil
+}
+
 // ListByClass returns schedules for a class ordered by day/time.
 func (r *ScheduleRepository) ListByClass(ctx context.Context, classID string) ([]models.Schedule, error) {
-	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at FROM schedules WHERE class_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at FROM schedules WHERE class_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
 	var schedules []models.Schedule
 	if err := r.db.SelectContext(ctx, &schedules, query, classID); err != nil {
 		return nil, fmt.Errorf("list schedules by class: %w", err)
@@ -132,7 +142,7 @@ func (r *ScheduleRepository) ListByClass(ctx context.Context, classID string) ([
 
 // ListByTeacher returns schedules taught by a teacher.
 func (r *ScheduleRepository) ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error) {
-	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at FROM schedules WHERE teacher_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
+	const query = `SELECT id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at FROM schedules WHERE teacher_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
 	var schedules []models.Schedule
 	if err := r.db.SelectContext(ctx, &schedules, query, teacherID); err != nil {
 		return nil, fmt.Errorf("list schedules by teacher: %w", err)
@@ -151,7 +161,7 @@ func (r *ScheduleRepository) Create(ctx context.Context, schedule *models.Schedu
 	}
 	schedule.UpdatedAt = now
 
-	const query = `INSERT INTO schedules (id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at) VALUES (:id, :term_id, :class_id, :subject_id, :teacher_id, :day_of_week, :time_slot, :room, :created_at, :updated_at)`
+	const query = `INSERT INTO schedules (id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at) VALUES (:id, :term_id, :class_id, :subject_id, :teacher_id, :day_of_week, :time_slot, :room, :lesson_group_id, :created_at, :updated_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, schedule); err != nil {
 		return fmt.Errorf("create schedule: %w", err)
 	}
@@ -200,7 +210,7 @@ func (r *ScheduleRepository) bulkInsertSchedules(ctx context.Context, exec sqlx.
 		}
 		payload.UpdatedAt = now
 
-		if _, err := sqlx.NamedExecContext(ctx, exec, `INSERT INTO schedules (id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, created_at, updated_at) VALUES (:id, :term_id, :class_id, :subject_id, :teacher_id, :day_of_week, :time_slot, :room, :created_at, :updated_at)`, &payload); err != nil {
+		if _, err := sqlx.NamedExecContext(ctx, exec, `INSERT INTO schedules (id, term_id, class_id, subject_id, teacher_id, day_of_week, time_slot, room, lesson_group_id, created_at, updated_at) VALUES (:id, :term_id, :class_id, :subject_id, :teacher_id, :day_of_week, :time_slot, :room, :lesson_group_id, :created_at, :updated_at)`, &payload); err != nil {
 			return fmt.Errorf("bulk insert schedule: %w", err)
 		}
 		schedules[i] = payload
@@ -218,6 +228,23 @@ func (r *ScheduleRepository) Update(ctx context.Context, schedule *models.Schedu
 	return nil
 }
 
+var schedulePatchColumns = map[string]struct{}{
+	"term_id": {}, "class_id": {}, "subject_id": {}, "teacher_id": {}, "day_of_week": {}, "time_slot": {}, "room": {},
+}
+
+// UpdateFields writes only the given columns of schedule so concurrent edits to other fields survive.
+func (r *ScheduleRepository) UpdateFields(ctx context.Context, schedule *models.Schedule, columns []string) error {
+	query, err := partialUpdateQuery("schedules", columns, schedulePatchColumns)
+	if err != nil {
+		return fmt.Errorf("patch schedule: %w", err)
+	}
+	schedule.UpdatedAt = time.Now().UTC()
+	if _, err := r.db.NamedExecContext(ctx, query, schedule); err != nil {
+		return fmt.Errorf("patch schedule: %w", err)
+	}
+	return nil
+}
+
 // Delete removes a schedule by id.
 func (r *ScheduleRepository) Delete(ctx context.Context, id string) error {
 	if _, err := r.db.ExecContext(ctx, `DELETE FROM schedules WHERE id = $1`, id); err != nil {
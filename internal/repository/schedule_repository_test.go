@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduleRepoMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	return sqlx.NewDb(db, "sqlmock"), mock, func() { db.Close() }
+}
+
+func TestScheduleRepositoryListByTerm(t *testing.T) {
+	db, mock, cleanup := newScheduleRepoMock(t)
+	defer cleanup()
+	repo := NewScheduleRepository(db)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "term_id", "class_id", "subject_id", "teacher_id", "day_of_week", "time_slot", "room", "created_at", "updated_at"}).
+		AddRow("s-1", "term-1", "class-1", "math", "teacher-1", "MONDAY", "1", "R1", now, now).
+		AddRow("s-2", "term-1", "class-2", "science", "teacher-2", "MONDAY", "2", "", now, now)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM schedules WHERE term_id = $1 ORDER BY day_of_week ASC, time_slot ASC")).
+		WithArgs("term-1").
+		WillReturnRows(rows)
+
+	schedules, err := repo.ListByTerm(context.Background(), "term-1")
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "teacher-2", schedules[1].TeacherID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
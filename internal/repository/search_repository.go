@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// SearchRepository runs the trigram-backed lookups behind the global search bar.
+type SearchRepository struct {
+	db *sqlx.DB
+}
+
+// NewSearchRepository constructs a SearchRepository.
+func NewSearchRepository(db *sqlx.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Teachers matches active teachers by name, email or NIP.
+func (r *SearchRepository) Teachers(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	const stmt = `SELECT id, full_name AS label, email AS detail FROM teachers
+WHERE active = TRUE AND (full_name ILIKE $1 ESCAPE '\' OR email ILIKE $1 ESCAPE '\' OR nip ILIKE $1 ESCAPE '\')
+ORDER BY similarity(full_name, $2) DESC, full_name ASC LIMIT $3`
+	return r.search(ctx, "teachers", stmt, likePattern(query), query, limit)
+}
+
+// Students matches active students by name or NIS. A non-nil classIDs restricts hits to students
+// actively enrolled in those classes.
+func (r *SearchRepository) Students(ctx context.Context, query string, classIDs []string, limit int) ([]models.SearchHit, error) {
+	if classIDs == nil {
+		const stmt = `SELECT s.id, s.full_name AS label, s.nis AS detail FROM students s
+WHERE s.active = TRUE AND (s.full_name ILIKE $1 ESCAPE '\' OR s.nis ILIKE $1 ESCAPE '\')
+ORDER BY similarity(s.full_name, $2) DESC, s.full_name ASC LIMIT $3`
+		return r.search(ctx, "students", stmt, likePattern(query), query, limit)
+	}
+	if len(classIDs) == 0 {
+		return []models.SearchHit{}, nil
+	}
+	const stmt = `SELECT s.id, s.full_name AS label, s.nis AS detail FROM students s
+WHERE s.active = TRUE AND (s.full_name ILIKE $1 ESCAPE '\' OR s.nis ILIKE $1 ESCAPE '\')
+  AND EXISTS (SELECT 1 FROM enrollments e WHERE e.student_id = s.id AND e.status = $4 AND e.class_id = ANY($5))
+ORDER BY similarity(s.full_name, $2) DESC, s.full_name ASC LIMIT $3`
+	return r.search(ctx, "students", stmt, likePattern(query), query, limit, models.EnrollmentStatusActive, pqStringArray(classIDs))
+}
+
+// Classes matches classes by name.
+func (r *SearchRepository) Classes(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	const stmt = `SELECT id, name AS label, grade || ' ' || track AS detail FROM classes
+WHERE name ILIKE $1 ESCAPE '\'
+ORDER BY similarity(name, $2) DESC, name ASC LIMIT $3`
+	return r.search(ctx, "classes", stmt, likePattern(query), query, limit)
+}
+
+// Subjects matches subjects by name or code.
+func (r *SearchRepository) Subjects(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	const stmt = `SELECT id, name AS label, code AS detail FROM subjects
+WHERE name ILIKE $1 ESCAPE '\' OR code ILIKE $1 ESCAPE '\'
+ORDER BY similarity(name, $2) DESC, name ASC LIMIT $3`
+	return r.search(ctx, "subjects", stmt, likePattern(query), query, limit)
+}
+
+func (r *SearchRepository) search(ctx context.Context, entity, stmt string, args ...interface{}) ([]models.SearchHit, error) {
+	hits := []models.SearchHit{}
+	if err := r.db.SelectContext(ctx, &hits, stmt, args...); err != nil {
+		return nil, fmt.Errorf("search %s: %w", entity, err)
+	}
+	return hits, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern wraps query for a substring ILIKE, escaping wildcards typed by the user.
+func likePattern(query string) string {
+	return "%" + likeEscaper.Replace(query) + "%"
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func newSearchRepoMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	return sqlx.NewDb(db, "sqlmock"), mock, func() { db.Close() }
+}
+
+func TestSearchRepositoryTeachersEscapesWildcards(t *testing.T) {
+	db, mock, cleanup := newSearchRepoMock(t)
+	defer cleanup()
+	repo := NewSearchRepository(db)
+
+	mock.ExpectQuery("FROM teachers").
+		WithArgs(`%50\%\_off%`, "50%_off", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "label", "detail"}).AddRow("t1", "Budi", "budi@example.com"))
+
+	hits, err := repo.Teachers(context.Background(), "50%_off", 5)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "budi@example.com", *hits[0].Detail)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchRepositoryStudentsScopedToClasses(t *testing.T) {
+	db, mock, cleanup := newSearchRepoMock(t)
+	defer cleanup()
+	repo := NewSearchRepository(db)
+
+	mock.ExpectQuery(`e\.class_id = ANY`).
+		WithArgs("%budi%", "budi", 5, models.EnrollmentStatusActive, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "label", "detail"}).AddRow("s1", "Budi", "1001"))
+
+	hits, err := repo.Students(context.Background(), "budi", []string{"class-1"}, 5)
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+
+	hits, err = repo.Students(context.Background(), "budi", []string{}, 5)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// SecurityRepository reads access denials back from the audit log.
+type SecurityRepository struct {
+	db *sqlx.DB
+}
+
+// NewSecurityRepository constructs the repository.
+func NewSecurityRepository(db *sqlx.DB) *SecurityRepository {
+	return &SecurityRepository{db: db}
+}
+
+// DenialCounts aggregates denials recorded since the provided instant.
+func (r *SecurityRepository) DenialCounts(ctx context.Context, since time.Time) ([]models.AccessDenialCount, error) {
+	const query = `SELECT user_id, resource AS route, COALESCE(new_values->>'reason', '') AS reason, COUNT(*) AS total
+FROM audit_logs WHERE action = $1 AND created_at >= $2
+GROUP BY user_id, resource, COALESCE(new_values->>'reason', '')
+ORDER BY total DESC`
+	var counts []models.AccessDenialCount
+	if err := r.db.SelectContext(ctx, &counts, query, models.AuditActionAccessDenied, since); err != nil {
+		return nil, fmt.Errorf("count access denials: %w", err)
+	}
+	return counts, nil
+}
+
+// RecentDenials returns the latest denials, newest first.
+func (r *SecurityRepository) RecentDenials(ctx context.Context, limit int) ([]models.AccessDenialRecord, error) {
+	const query = `SELECT id, user_id, resource AS route, resource_id, COALESCE(new_values->>'method', '') AS method,
+COALESCE(new_values->>'reason', '') AS reason, COALESCE(ip_address, '') AS ip_address, created_at
+FROM audit_logs WHERE action = $1 ORDER BY created_at DESC LIMIT $2`
+	var records []models.AccessDenialRecord
+	if err := r.db.SelectContext(ctx, &records, query, models.AuditActionAccessDenied, limit); err != nil {
+		return nil, fmt.Errorf("list recent access denials: %w", err)
+	}
+	return records, nil
+}
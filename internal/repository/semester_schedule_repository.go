@@ -70,7 +70,7 @@ VALUES (:id, :term_id, :class_id, :version, :status, :meta, :created_at, :update
 }
 
 // ListByTermClass returns all versions for the provided class-term tuple.
-func (r *SemesterScheduleRepository) ListByTermClass(ctx context.Context, termID, classID string) ([]models.SemesterSchedule, error) {
+func (r *SemesterScheduleRepository) ListByTermClass(ctx context.Context, termID models.TermID, classID models.ClassID) ([]models.SemesterSchedule, error) {
 	const query = `SELECT id, term_id, class_id, version, status, meta, created_at, updated_at
 FROM semester_schedules WHERE term_id = $1 AND class_id = $2 ORDER BY version DESC`
 	var schedules []models.SemesterSchedule
@@ -136,3 +136,66 @@ func (r *SemesterScheduleRepository) UpdateStatus(ctx context.Context, exec sqlx
 	}
 	return nil
 }
+
+// ListPublishedByTeacher returns the published schedules with at least one slot taught by teacherID.
+func (r *SemesterScheduleRepository) ListPublishedByTeacher(ctx context.Context, teacherID models.TeacherID) ([]models.SemesterSchedule, error) {
+	const query = `SELECT ss.id, ss.term_id, ss.class_id, ss.version, ss.status, ss.meta, ss.created_at, ss.updated_at
+FROM semester_schedules ss
+WHERE ss.status = $1
+  AND EXISTS (SELECT 1 FROM semester_schedule_slots s WHERE s.semester_schedule_id = ss.id AND s.teacher_id = $2)
+ORDER BY ss.term_id ASC, ss.class_id ASC`
+	var schedules []models.SemesterSchedule
+	if err := r.db.SelectContext(ctx, &schedules, query, models.SemesterScheduleStatusPublished, teacherID); err != nil {
+		return nil, fmt.Errorf("list published semester schedules by teacher: %w", err)
+	}
+	return schedules, nil
+}
+
+// ReplaceWarnings makes warnings the complete set of open warnings for a schedule.
+func (r *SemesterScheduleRepository) ReplaceWarnings(ctx context.Context, scheduleID string, warnings []models.ScheduleWarning) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin replace schedule warnings: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM semester_schedule_warnings WHERE semester_schedule_id = $1`, scheduleID); err != nil {
+		return fmt.Errorf("clear schedule warnings: %w", err)
+	}
+	const insert = `INSERT INTO semester_schedule_warnings (id, semester_schedule_id, type, teacher_id, subject_id, day_of_week, time_slot, message, detected_at)
+VALUES (:id, :semester_schedule_id, :type, :teacher_id, :subject_id, :day_of_week, :time_slot, :message, :detected_at)`
+	now := time.Now().UTC()
+	for i := range warnings {
+		warning := &warnings[i]
+		warning.SemesterScheduleID = scheduleID
+		if warning.ID == "" {
+			warning.ID = uuid.NewString()
+		}
+		if warning.DetectedAt.IsZero() {
+			warning.DetectedAt = now
+		}
+		if _, err = tx.NamedExecContext(ctx, insert, warning); err != nil {
+			return fmt.Errorf("insert schedule warning: %w", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit replace schedule warnings: %w", err)
+	}
+	return nil
+}
+
+// ListWarnings returns a schedule's open warnings ordered by position in the week.
+func (r *SemesterScheduleRepository) ListWarnings(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error) {
+	const query = `SELECT id, semester_schedule_id, type, teacher_id, subject_id, day_of_week, time_slot, message, detected_at
+FROM semester_schedule_warnings WHERE semester_schedule_id = $1
+ORDER BY day_of_week ASC NULLS FIRST, time_slot ASC NULLS FIRST, type ASC`
+	var warnings []models.ScheduleWarning
+	if err := r.db.SelectContext(ctx, &warnings, query, scheduleID); err != nil {
+		return nil, fmt.Errorf("list schedule warnings: %w", err)
+	}
+	return warnings, nil
+}
@@ -117,3 +117,23 @@ func TestSemesterScheduleRepositoryUpdateStatusNoMeta(t *testing.T) {
 	require.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSemesterScheduleRepositoryReplaceWarnings(t *testing.T) {
+	db, mock, cleanup := newSemesterScheduleRepoMock(t)
+	defer cleanup()
+	repo := NewSemesterScheduleRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM semester_schedule_warnings WHERE semester_schedule_id = $1")).
+		WithArgs("sch-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO semester_schedule_warnings")).
+		WithArgs(sqlmock.AnyArg(), "sch-1", string(models.ScheduleWarningWeeklyLoad), "teacher-1", nil, nil, nil, "too many lessons", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	warnings := []models.ScheduleWarning{{Type: models.ScheduleWarningWeeklyLoad, TeacherID: "teacher-1", Message: "too many lessons"}}
+	require.NoError(t, repo.ReplaceWarnings(context.Background(), "sch-1", warnings))
+	assert.NotEmpty(t, warnings[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -37,12 +37,13 @@ func (r *SemesterScheduleSlotRepository) UpsertBatch(ctx context.Context, exec s
 	now := time.Now().UTC()
 
 	const query = `
-INSERT INTO semester_schedule_slots (id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, created_at)
-VALUES (:id, :semester_schedule_id, :day_of_week, :time_slot, :subject_id, :teacher_id, :room, :created_at)
+INSERT INTO semester_schedule_slots (id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, block_size, created_at)
+VALUES (:id, :semester_schedule_id, :day_of_week, :time_slot, :subject_id, :teacher_id, :room, :block_size, :created_at)
 ON CONFLICT (semester_schedule_id, day_of_week, time_slot) DO UPDATE
 SET subject_id = EXCLUDED.subject_id,
     teacher_id = EXCLUDED.teacher_id,
-    room = EXCLUDED.room`
+    room = EXCLUDED.room,
+    block_size = EXCLUDED.block_size`
 
 	for i := range slots {
 		slot := &slots[i]
@@ -52,6 +53,9 @@ SET subject_id = EXCLUDED.subject_id,
 		if slot.CreatedAt.IsZero() {
 			slot.CreatedAt = now
 		}
+		if slot.BlockSize < 1 {
+			slot.BlockSize = 1
+		}
 		if _, err := sqlx.NamedExecContext(ctx, target, query, slot); err != nil {
 			return fmt.Errorf("upsert semester schedule slot: %w", err)
 		}
@@ -61,7 +65,7 @@ SET subject_id = EXCLUDED.subject_id,
 
 // ListBySchedule returns slots ordered by day/time for a schedule.
 func (r *SemesterScheduleSlotRepository) ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error) {
-	const query = `SELECT id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, created_at
+	const query = `SELECT id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, block_size, created_at
 FROM semester_schedule_slots WHERE semester_schedule_id = $1 ORDER BY day_of_week ASC, time_slot ASC`
 	var slots []models.SemesterScheduleSlot
 	if err := r.db.SelectContext(ctx, &slots, query, scheduleID); err != nil {
@@ -69,3 +73,18 @@ FROM semester_schedule_slots WHERE semester_schedule_id = $1 ORDER BY day_of_wee
 	}
 	return slots, nil
 }
+
+// ListTeacherTermSlots returns a teacher's slots for the term drawn from the given schedule and
+// the published schedules of every other class.
+func (r *SemesterScheduleSlotRepository) ListTeacherTermSlots(ctx context.Context, teacherID models.TeacherID, termID models.TermID, scheduleID string, classID models.ClassID) ([]models.SemesterScheduleClassSlot, error) {
+	const query = `SELECT s.id, s.semester_schedule_id, s.day_of_week, s.time_slot, s.subject_id, s.teacher_id, s.room, s.block_size, s.created_at, ss.class_id
+FROM semester_schedule_slots s
+JOIN semester_schedules ss ON ss.id = s.semester_schedule_id
+WHERE s.teacher_id = $1 AND ss.term_id = $2 AND (ss.id = $3 OR (ss.status = 'PUBLISHED' AND ss.class_id <> $4))
+ORDER BY s.day_of_week ASC, s.time_slot ASC`
+	var slots []models.SemesterScheduleClassSlot
+	if err := r.db.SelectContext(ctx, &slots, query, teacherID, termID, scheduleID, classID); err != nil {
+		return nil, fmt.Errorf("list teacher semester schedule slots: %w", err)
+	}
+	return slots, nil
+}
@@ -26,11 +26,11 @@ func TestSemesterScheduleSlotRepositoryUpsertBatch(t *testing.T) {
 	repo := NewSemesterScheduleSlotRepository(db)
 
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO semester_schedule_slots")).
-		WithArgs(sqlmock.AnyArg(), "sched-1", 1, 1, "sub-1", "teacher-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), "sched-1", 1, 1, "sub-1", "teacher-1", sqlmock.AnyArg(), 1, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO semester_schedule_slots")).
-		WithArgs(sqlmock.AnyArg(), "sched-1", 1, 2, "sub-2", "teacher-2", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), "sched-1", 1, 2, "sub-2", "teacher-2", sqlmock.AnyArg(), 1, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	slots := []models.SemesterScheduleSlot{
@@ -59,9 +59,9 @@ func TestSemesterScheduleSlotRepositoryListBySchedule(t *testing.T) {
 	defer cleanup()
 	repo := NewSemesterScheduleSlotRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "semester_schedule_id", "day_of_week", "time_slot", "subject_id", "teacher_id", "room", "created_at"}).
-		AddRow("slot-1", "sched-1", 1, 1, "sub-1", "teacher-1", nil, time.Now())
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, created_at FROM semester_schedule_slots WHERE semester_schedule_id = $1 ORDER BY day_of_week ASC, time_slot ASC")).
+	rows := sqlmock.NewRows([]string{"id", "semester_schedule_id", "day_of_week", "time_slot", "subject_id", "teacher_id", "room", "block_size", "created_at"}).
+		AddRow("slot-1", "sched-1", 1, 1, "sub-1", "teacher-1", nil, 1, time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, semester_schedule_id, day_of_week, time_slot, subject_id, teacher_id, room, block_size, created_at FROM semester_schedule_slots WHERE semester_schedule_id = $1 ORDER BY day_of_week ASC, time_slot ASC")).
 		WithArgs("sched-1").
 		WillReturnRows(rows)
 
@@ -70,3 +70,22 @@ func TestSemesterScheduleSlotRepositoryListBySchedule(t *testing.T) {
 	assert.Len(t, slots, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSemesterScheduleSlotRepositoryListTeacherTermSlots(t *testing.T) {
+	db, mock, cleanup := newSemesterScheduleSlotRepoMock(t)
+	defer cleanup()
+	repo := NewSemesterScheduleSlotRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "semester_schedule_id", "day_of_week", "time_slot", "subject_id", "teacher_id", "room", "block_size", "created_at", "class_id"}).
+		AddRow("slot-1", "sched-1", 1, 1, "sub-1", "teacher-1", nil, 1, time.Now(), "class-1").
+		AddRow("slot-2", "sched-2", 1, 2, "sub-1", "teacher-1", nil, 1, time.Now(), "class-2")
+	mock.ExpectQuery(regexp.QuoteMeta("FROM semester_schedule_slots s JOIN semester_schedules ss ON ss.id = s.semester_schedule_id WHERE s.teacher_id = $1 AND ss.term_id = $2 AND (ss.id = $3 OR (ss.status = 'PUBLISHED' AND ss.class_id <> $4))")).
+		WithArgs("teacher-1", "term-1", "sched-1", "class-1").
+		WillReturnRows(rows)
+
+	slots, err := repo.ListTeacherTermSlots(context.Background(), "teacher-1", "term-1", "sched-1", "class-1")
+	require.NoError(t, err)
+	require.Len(t, slots, 2)
+	assert.Equal(t, models.ClassID("class-2"), slots[1].ClassID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
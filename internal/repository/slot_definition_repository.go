@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// SlotDefinitionRepository persists per-term slot time mappings.
+type SlotDefinitionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSlotDefinitionRepository constructs the repository.
+func NewSlotDefinitionRepository(db *sqlx.DB) *SlotDefinitionRepository {
+	return &SlotDefinitionRepository{db: db}
+}
+
+// ListByTerm returns the slot definitions of a term ordered by slot index.
+func (r *SlotDefinitionRepository) ListByTerm(ctx context.Context, termID string) ([]models.SlotDefinition, error) {
+	const query = `SELECT id, term_id, slot_index, start_time, end_time, label, is_break, created_at, updated_at
+FROM slot_definitions WHERE term_id = $1 ORDER BY slot_index ASC`
+	var definitions []models.SlotDefinition
+	if err := r.db.SelectContext(ctx, &definitions, query, termID); err != nil {
+		return nil, fmt.Errorf("list slot definitions: %w", err)
+	}
+	return definitions, nil
+}
+
+// FindByID fetches a slot definition.
+func (r *SlotDefinitionRepository) FindByID(ctx context.Context, id string) (*models.SlotDefinition, error) {
+	const query = `SELECT id, term_id, slot_index, start_time, end_time, label, is_break, created_at, updated_at
+FROM slot_definitions WHERE id = $1`
+	var definition models.SlotDefinition
+	if err := r.db.GetContext(ctx, &definition, query, id); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// Create inserts a slot definition.
+func (r *SlotDefinitionRepository) Create(ctx context.Context, definition *models.SlotDefinition) error {
+	if definition.ID == "" {
+		definition.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if definition.CreatedAt.IsZero() {
+		definition.CreatedAt = now
+	}
+	definition.UpdatedAt = now
+	const query = `INSERT INTO slot_definitions (id, term_id, slot_index, start_time, end_time, label, is_break, created_at, updated_at)
+VALUES (:id, :term_id, :slot_index, :start_time, :end_time, :label, :is_break, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, definition); err != nil {
+		return fmt.Errorf("create slot definition: %w", err)
+	}
+	return nil
+}
+
+// Update modifies a slot definition.
+func (r *SlotDefinitionRepository) Update(ctx context.Context, definition *models.SlotDefinition) error {
+	definition.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE slot_definitions SET slot_index = :slot_index, start_time = :start_time, end_time = :end_time,
+label = :label, is_break = :is_break, updated_at = :updated_at WHERE id = :id`
+	if _, err := r.db.NamedExecContext(ctx, query, definition); err != nil {
+		return fmt.Errorf("update slot definition: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a slot definition.
+func (r *SlotDefinitionRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM slot_definitions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete slot definition: %w", err)
+	}
+	return nil
+}
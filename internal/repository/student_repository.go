@@ -23,7 +23,8 @@ func NewStudentRepository(db *sqlx.DB) *StudentRepository {
 	return &StudentRepository{db: db}
 }
 
-// List returns students matching the provided filters.
+// List returns students matching the provided filters. A search matches the name or NIS as a
+// substring or the name fuzzily; without an explicit sort the closest names come first.
 func (r *StudentRepository) List(ctx context.Context, filter models.StudentFilter) ([]models.StudentDetail, int, error) {
 	base := "FROM students s LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $1 LEFT JOIN classes c ON c.id = e.class_id"
 	args := []interface{}{models.EnrollmentStatusActive}
@@ -37,9 +38,12 @@ func (r *StudentRepository) List(ctx context.Context, filter models.StudentFilte
 		conditions = append(conditions, fmt.Sprintf("s.active = $%d", len(args)+1))
 		args = append(args, *filter.Active)
 	}
+	rank := ""
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf("(LOWER(s.full_name) LIKE $%d OR LOWER(s.nis) LIKE $%d)", len(args)+1, len(args)+1))
-		args = append(args, "%"+strings.ToLower(filter.Search)+"%")
+		pattern, term := len(args)+1, len(args)+2
+		conditions = append(conditions, fmt.Sprintf(`(s.full_name ILIKE $%[1]d ESCAPE '\' OR s.nis ILIKE $%[1]d ESCAPE '\' OR $%[2]d <%% s.full_name)`, pattern, term))
+		args = append(args, likePattern(filter.Search), filter.Search)
+		rank = fmt.Sprintf("word_similarity($%d, s.full_name) DESC, s.full_name ASC", term)
 	}
 
 	base = fmt.Sprintf("%s WHERE %s", base, strings.Join(conditions, " AND "))
@@ -70,27 +74,36 @@ func (r *StudentRepository) List(ctx context.Context, filter models.StudentFilte
 		size = 20
 	}
 	offset := (page - 1) * size
+	orderBy := column + " " + order
+	if rank != "" && filter.SortBy == "" {
+		orderBy = rank
+	}
 
-	query := fmt.Sprintf(`SELECT s.id, s.nis, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,
+	query := fmt.Sprintf(`SELECT s.id, s.nis, s.nisn, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,
         e.class_id AS current_class_id, c.name AS current_class_name, e.term_id AS current_term_id, e.joined_at
-        %s ORDER BY %s %s LIMIT %d OFFSET %d`, base, column, order, size, offset)
+        %s ORDER BY %s LIMIT %d OFFSET %d`, base, orderBy, size, offset)
 
 	var students []models.StudentDetail
-	if err := r.db.SelectContext(ctx, &students, query, args...); err != nil {
-		return nil, 0, fmt.Errorf("list students: %w", err)
-	}
-
-	countQuery := fmt.Sprintf("SELECT COUNT(DISTINCT s.id) %s", base)
 	var total int
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("count students: %w", err)
+	err := queryWithFuzzySearch(ctx, r.db, filter.Search != "", func(q sqlx.QueryerContext) error {
+		if err := sqlx.SelectContext(ctx, q, &students, query, args...); err != nil {
+			return fmt.Errorf("list students: %w", err)
+		}
+		countQuery := fmt.Sprintf("SELECT COUNT(DISTINCT s.id) %s", base)
+		if err := sqlx.GetContext(ctx, q, &total, countQuery, args...); err != nil {
+			return fmt.Errorf("count students: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 	return students, total, nil
 }
 
 // FindByID fetches a student detail by ID.
 func (r *StudentRepository) FindByID(ctx context.Context, id string) (*models.StudentDetail, error) {
-	query := `SELECT s.id, s.nis, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,
+	query := `SELECT s.id, s.nis, s.nisn, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,
         e.class_id AS current_class_id, c.name AS current_class_name, e.term_id AS current_term_id, e.joined_at
         FROM students s
         LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $2
@@ -103,6 +116,15 @@ func (r *StudentRepository) FindByID(ctx context.Context, id string) (*models.St
 	return &detail, nil
 }
 
+// FindIDByNIS resolves an active student's ID from their NIS.
+func (r *StudentRepository) FindIDByNIS(ctx context.Context, nis string) (string, error) {
+	var id string
+	if err := r.db.GetContext(ctx, &id, "SELECT id FROM students WHERE nis = $1 AND active = TRUE", nis); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
 // ExistsByNIS checks if a student with given NIS exists optionally excluding an ID.
 func (r *StudentRepository) ExistsByNIS(ctx context.Context, nis string, excludeID string) (bool, error) {
 	query := "SELECT 1 FROM students WHERE nis = $1"
@@ -131,8 +153,8 @@ func (r *StudentRepository) Create(ctx context.Context, student *models.Student)
 		student.CreatedAt = now
 	}
 	student.UpdatedAt = now
-	const query = `INSERT INTO students (id, nis, full_name, gender, birth_date, address, phone, active, created_at, updated_at)
-        VALUES (:id, :nis, :full_name, :gender, :birth_date, :address, :phone, :active, :created_at, :updated_at)`
+	const query = `INSERT INTO students (id, nis, nisn, full_name, gender, birth_date, address, phone, active, created_at, updated_at)
+        VALUES (:id, :nis, :nisn, :full_name, :gender, :birth_date, :address, :phone, :active, :created_at, :updated_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, student); err != nil {
 		return fmt.Errorf("create student: %w", err)
 	}
@@ -142,7 +164,7 @@ func (r *StudentRepository) Create(ctx context.Context, student *models.Student)
 // Update modifies an existing student.
 func (r *StudentRepository) Update(ctx context.Context, student *models.Student) error {
 	student.UpdatedAt = time.Now().UTC()
-	const query = `UPDATE students SET nis = :nis, full_name = :full_name, gender = :gender, birth_date = :birth_date, address = :address, phone = :phone, active = :active, updated_at = :updated_at WHERE id = :id`
+	const query = `UPDATE students SET nis = :nis, nisn = :nisn, full_name = :full_name, gender = :gender, birth_date = :birth_date, address = :address, phone = :phone, active = :active, updated_at = :updated_at WHERE id = :id`
 	if _, err := r.db.NamedExecContext(ctx, query, student); err != nil {
 		return fmt.Errorf("update student: %w", err)
 	}
@@ -157,3 +179,48 @@ func (r *StudentRepository) Deactivate(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// FindByUserID fetches the student detail linked to a login account.
+func (r *StudentRepository) FindByUserID(ctx context.Context, userID string) (*models.StudentDetail, error) {
+	query := `SELECT s.id, s.nis, s.nisn, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,
+        e.class_id AS current_class_id, c.name AS current_class_name, e.term_id AS current_term_id, e.joined_at
+        FROM students s
+        LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $2
+        LEFT JOIN classes c ON c.id = e.class_id
+        WHERE s.user_id = $1`
+	var detail models.StudentDetail
+	if err := r.db.GetContext(ctx, &detail, query, userID, models.EnrollmentStatusActive); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// FindUserID returns the login account linked to a student, nil when none is linked yet.
+func (r *StudentRepository) FindUserID(ctx context.Context, studentID string) (*string, error) {
+	var userID sql.NullString
+	if err := r.db.GetContext(ctx, &userID, "SELECT user_id FROM students WHERE id = $1", studentID); err != nil {
+		return nil, err
+	}
+	if !userID.Valid {
+		return nil, nil
+	}
+	return &userID.String, nil
+}
+
+// AttachUser links a login account to a student that has none, returning sql.ErrNoRows when the
+// student is missing or already linked.
+func (r *StudentRepository) AttachUser(ctx context.Context, studentID, userID string) error {
+	const query = `UPDATE students SET user_id = $2, updated_at = $3 WHERE id = $1 AND user_id IS NULL`
+	result, err := r.db.ExecContext(ctx, query, studentID, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("attach student user: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check student user rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
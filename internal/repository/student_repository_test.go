@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
 	"time"
@@ -25,9 +26,9 @@ func TestStudentRepositoryList(t *testing.T) {
 	defer cleanup()
 	repo := NewStudentRepository(db)
 
-	rows := sqlmock.NewRows([]string{"id", "nis", "full_name", "gender", "birth_date", "address", "phone", "active", "created_at", "updated_at", "current_class_id", "current_class_name", "current_term_id", "joined_at"}).
-		AddRow("1", "001", "Student", "M", time.Now(), "Street", "123", true, time.Now(), time.Now(), "class", "Class", "term", time.Now())
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT s.id, s.nis, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,\n        e.class_id AS current_class_id, c.name AS current_class_name, e.term_id AS current_term_id, e.joined_at\n        FROM students s LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $1 LEFT JOIN classes c ON c.id = e.class_id WHERE 1=1 ORDER BY s.created_at DESC LIMIT 20 OFFSET 0")).
+	rows := sqlmock.NewRows([]string{"id", "nis", "nisn", "full_name", "gender", "birth_date", "address", "phone", "active", "created_at", "updated_at", "current_class_id", "current_class_name", "current_term_id", "joined_at"}).
+		AddRow("1", "001", "0051234567", "Student", "M", time.Now(), "Street", "123", true, time.Now(), time.Now(), "class", "Class", "term", time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT s.id, s.nis, s.nisn, s.full_name, s.gender, s.birth_date, s.address, s.phone, s.active, s.created_at, s.updated_at,\n        e.class_id AS current_class_id, c.name AS current_class_name, e.term_id AS current_term_id, e.joined_at\n        FROM students s LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $1 LEFT JOIN classes c ON c.id = e.class_id WHERE 1=1 ORDER BY s.created_at DESC LIMIT 20 OFFSET 0")).
 		WithArgs(models.EnrollmentStatusActive).
 		WillReturnRows(rows)
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(DISTINCT s.id) FROM students s LEFT JOIN enrollments e ON e.student_id = s.id AND e.status = $1 LEFT JOIN classes c ON c.id = e.class_id WHERE 1=1")).
@@ -41,16 +42,55 @@ func TestStudentRepositoryList(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestStudentRepositoryListFuzzySearch(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewStudentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)")).
+		WithArgs("0.3").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE 1=1 AND (s.full_name ILIKE $2 ESCAPE '\' OR s.nis ILIKE $2 ESCAPE '\' OR $3 <% s.full_name) ORDER BY word_similarity($3, s.full_name) DESC, s.full_name ASC LIMIT 20 OFFSET 0`)).
+		WithArgs(models.EnrollmentStatusActive, "%ahmad%", "ahmad").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "nis", "full_name"}).AddRow("1", "001", "Achmad Fauzi"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(DISTINCT s.id)")).
+		WithArgs(models.EnrollmentStatusActive, "%ahmad%", "ahmad").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	students, total, err := repo.List(context.Background(), models.StudentFilter{Search: "ahmad"})
+	require.NoError(t, err)
+	require.Len(t, students, 1)
+	assert.Equal(t, "Achmad Fauzi", students[0].FullName)
+	assert.Equal(t, 1, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestStudentRepositoryCreate(t *testing.T) {
 	db, mock, cleanup := newStudentMock(t)
 	defer cleanup()
 	repo := NewStudentRepository(db)
 
 	mock.ExpectExec("INSERT INTO students").
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err := repo.Create(context.Background(), &models.Student{NIS: "123", FullName: "Student", Gender: "M", BirthDate: time.Now(), Address: "Street", Phone: "123", Active: true})
 	require.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestStudentRepositoryAttachUserOnlyOnce(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewStudentRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE students SET user_id = $2, updated_at = $3 WHERE id = $1 AND user_id IS NULL")).
+		WithArgs("student-1", "user-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.AttachUser(context.Background(), "student-1", "user-1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
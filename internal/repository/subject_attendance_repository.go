@@ -11,6 +11,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 )
 
 // SubjectAttendanceRepository persists subject session attendance.
@@ -109,7 +110,7 @@ RETURNING id, enrollment_id, schedule_id, date, status, notes, created_at, updat
 	return &stored, nil
 }
 
-// BulkInsert inserts multiple subject attendance entries.
+// BulkInsert inserts multiple subject attendance entries, rolling back for dry-run contexts.
 func (r *SubjectAttendanceRepository) BulkInsert(ctx context.Context, records []models.SubjectAttendance, atomic bool) ([]models.SubjectAttendance, error) {
 	if len(records) == 0 {
 		return nil, nil
@@ -150,7 +151,7 @@ ON CONFLICT (enrollment_id, schedule_id, date) DO NOTHING RETURNING id`
 			return nil, fmt.Errorf("bulk insert subject attendance: %w", err)
 		}
 	}
-	if err := tx.Commit(); err != nil {
+	if err := database.CommitOrRollback(ctx, tx); err != nil {
 		return nil, fmt.Errorf("commit bulk subject attendance: %w", err)
 	}
 	commit = true
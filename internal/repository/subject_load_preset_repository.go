@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const subjectLoadPresetColumns = `id, name, grade, track, loads, created_at, updated_at`
+
+// SubjectLoadPresetRepository persists the curriculum load presets used by the scheduler.
+type SubjectLoadPresetRepository struct {
+	db *sqlx.DB
+}
+
+// NewSubjectLoadPresetRepository constructs the repository.
+func NewSubjectLoadPresetRepository(db *sqlx.DB) *SubjectLoadPresetRepository {
+	return &SubjectLoadPresetRepository{db: db}
+}
+
+// List returns presets ordered by grade, track and name.
+func (r *SubjectLoadPresetRepository) List(ctx context.Context, filter models.SubjectLoadPresetFilter) ([]models.SubjectLoadPreset, error) {
+	var where []string
+	var args []interface{}
+	if filter.Grade != "" {
+		args = append(args, filter.Grade)
+		where = append(where, fmt.Sprintf("grade = $%d", len(args)))
+	}
+	if filter.Track != "" {
+		args = append(args, filter.Track)
+		where = append(where, fmt.Sprintf("track = $%d", len(args)))
+	}
+	query := `SELECT ` + subjectLoadPresetColumns + ` FROM subject_load_presets`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY grade ASC, track ASC NULLS FIRST, name ASC"
+	var presets []models.SubjectLoadPreset
+	if err := r.db.SelectContext(ctx, &presets, query, args...); err != nil {
+		return nil, fmt.Errorf("list subject load presets: %w", err)
+	}
+	return presets, nil
+}
+
+// FindByID returns a preset by its identifier.
+func (r *SubjectLoadPresetRepository) FindByID(ctx context.Context, id string) (*models.SubjectLoadPreset, error) {
+	query := `SELECT ` + subjectLoadPresetColumns + ` FROM subject_load_presets WHERE id = $1`
+	var preset models.SubjectLoadPreset
+	if err := r.db.GetContext(ctx, &preset, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("find subject load preset: %w", err)
+	}
+	return &preset, nil
+}
+
+// ExistsByName reports whether another preset already uses the name.
+func (r *SubjectLoadPresetRepository) ExistsByName(ctx context.Context, name, excludeID string) (bool, error) {
+	const query = `SELECT EXISTS(SELECT 1 FROM subject_load_presets WHERE LOWER(name) = LOWER($1) AND id <> $2)`
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, name, excludeID); err != nil {
+		return false, fmt.Errorf("check subject load preset name: %w", err)
+	}
+	return exists, nil
+}
+
+// Create inserts a new preset.
+func (r *SubjectLoadPresetRepository) Create(ctx context.Context, preset *models.SubjectLoadPreset) error {
+	if preset.ID == "" {
+		preset.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	preset.CreatedAt = now
+	preset.UpdatedAt = now
+	query := `INSERT INTO subject_load_presets (` + subjectLoadPresetColumns + `) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if _, err := r.db.ExecContext(ctx, query, preset.ID, preset.Name, preset.Grade, preset.Track, preset.Loads,
+		preset.CreatedAt, preset.UpdatedAt); err != nil {
+		return fmt.Errorf("create subject load preset: %w", err)
+	}
+	return nil
+}
+
+// Update replaces an existing preset.
+func (r *SubjectLoadPresetRepository) Update(ctx context.Context, preset *models.SubjectLoadPreset) error {
+	preset.UpdatedAt = time.Now().UTC()
+	const query = `UPDATE subject_load_presets SET name = $2, grade = $3, track = $4, loads = $5, updated_at = $6 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, preset.ID, preset.Name, preset.Grade, preset.Track, preset.Loads, preset.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update subject load preset: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check subject load preset rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete removes a preset.
+func (r *SubjectLoadPresetRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM subject_load_presets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete subject load preset: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check subject load preset rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
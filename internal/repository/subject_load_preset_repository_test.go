@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestSubjectLoadPresetRepositoryList(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewSubjectLoadPresetRepository(sqlx.NewDb(db, "sqlmock"))
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "grade", "track", "loads", "created_at", "updated_at"}).
+		AddRow("preset-1", "Kelas 10 IPA", "10", "IPA", `[{"subject_id":"math","weekly_count":4}]`, now, now)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM subject_load_presets WHERE grade = $1 AND track = $2 ORDER BY grade")).
+		WithArgs("10", "IPA").
+		WillReturnRows(rows)
+
+	presets, err := repo.List(context.Background(), models.SubjectLoadPresetFilter{Grade: "10", Track: "IPA"})
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	assert.Equal(t, "IPA", *presets[0].Track)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
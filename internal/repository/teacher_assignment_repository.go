@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,6 +42,99 @@ ORDER BY t.start_date DESC, c.name ASC`
 	return assignments, nil
 }
 
+// teacherAssignmentJoins maps expandable relations to their join and selected name column.
+var teacherAssignmentJoins = []struct {
+	relation string
+	join     string
+	column   string
+}{
+	{models.TeacherAssignmentExpandClass, "JOIN classes c ON c.id = ta.class_id", "c.name AS class_name"},
+	{models.TeacherAssignmentExpandSubject, "JOIN subjects s ON s.id = ta.subject_id", "s.name AS subject_name"},
+	{models.TeacherAssignmentExpandTerm, "JOIN terms t ON t.id = ta.term_id", "t.name AS term_name"},
+	{models.TeacherAssignmentExpandTeacher, "JOIN teachers tr ON tr.id = ta.teacher_id", "tr.full_name AS teacher_name"},
+}
+
+// ListByTeacherFiltered returns a filtered, sorted page of a teacher's assignments and the total
+// count. Only the expanded relations are joined, plus any relation required for sorting.
+func (r *TeacherAssignmentRepository) ListByTeacherFiltered(ctx context.Context, filter models.TeacherAssignmentFilter) ([]models.TeacherAssignmentDetail, int, error) {
+	conditions := []string{"ta.teacher_id = $1"}
+	args := []interface{}{filter.TeacherID}
+	if filter.TermID != "" {
+		args = append(args, filter.TermID)
+		conditions = append(conditions, fmt.Sprintf("ta.term_id = $%d", len(args)))
+	}
+	if filter.ClassID != "" {
+		args = append(args, filter.ClassID)
+		conditions = append(conditions, fmt.Sprintf("ta.class_id = $%d", len(args)))
+	}
+	if filter.SubjectID != "" {
+		args = append(args, filter.SubjectID)
+		conditions = append(conditions, fmt.Sprintf("ta.subject_id = $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	allowedSorts := map[string]struct {
+		column   string
+		relation string
+	}{
+		"term":       {"t.start_date", models.TeacherAssignmentExpandTerm},
+		"class":      {"c.name", models.TeacherAssignmentExpandClass},
+		"subject":    {"s.name", models.TeacherAssignmentExpandSubject},
+		"created_at": {"ta.created_at", ""},
+	}
+	sort, ok := allowedSorts[filter.SortBy]
+	if !ok {
+		sort = allowedSorts["term"]
+	}
+	order := strings.ToUpper(filter.SortOrder)
+	if order != "ASC" && order != "DESC" {
+		order = "DESC"
+	}
+
+	expanded := make(map[string]bool)
+	if filter.Expand == nil {
+		for _, join := range teacherAssignmentJoins {
+			expanded[join.relation] = true
+		}
+	}
+	for _, relation := range filter.Expand {
+		expanded[relation] = true
+	}
+	columns := []string{"ta.id", "ta.teacher_id", "ta.class_id", "ta.subject_id", "ta.term_id", "ta.role", "ta.created_at"}
+	var joins []string
+	for _, join := range teacherAssignmentJoins {
+		if expanded[join.relation] {
+			columns = append(columns, join.column)
+		}
+		if expanded[join.relation] || join.relation == sort.relation {
+			joins = append(joins, join.join)
+		}
+	}
+
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	size := filter.PageSize
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+	offset := (page - 1) * size
+
+	query := fmt.Sprintf("SELECT %s FROM teacher_assignments ta %s %s ORDER BY %s %s, ta.created_at ASC, ta.id ASC LIMIT %d OFFSET %d",
+		strings.Join(columns, ", "), strings.Join(joins, " "), where, sort.column, order, size, offset)
+	var assignments []models.TeacherAssignmentDetail
+	if err := r.db.SelectContext(ctx, &assignments, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("list teacher assignments: %w", err)
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM teacher_assignments ta "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("count teacher assignments: %w", err)
+	}
+	return assignments, total, nil
+}
+
 // ListByClassAndTerm returns assignments scoped to a class within a term.
 func (r *TeacherAssignmentRepository) ListByClassAndTerm(ctx context.Context, classID, termID string) ([]models.TeacherAssignment, error) {
 	const query = `SELECT id, teacher_id, class_id, subject_id, term_id, role, created_at
@@ -52,6 +146,17 @@ FROM teacher_assignments WHERE class_id = $1 AND term_id = $2`
 	return assignments, nil
 }
 
+// ListByTerm returns every assignment of a term so callers can match subjects and classes in memory.
+func (r *TeacherAssignmentRepository) ListByTerm(ctx context.Context, termID string) ([]models.TeacherAssignment, error) {
+	const query = `SELECT id, teacher_id, class_id, subject_id, term_id, role, created_at
+FROM teacher_assignments WHERE term_id = $1`
+	var assignments []models.TeacherAssignment
+	if err := r.db.SelectContext(ctx, &assignments, query, termID); err != nil {
+		return nil, fmt.Errorf("list term teacher assignments: %w", err)
+	}
+	return assignments, nil
+}
+
 // Exists checks if the teacher-class-subject-term tuple already exists.
 func (r *TeacherAssignmentRepository) Exists(ctx context.Context, teacherID, classID, subjectID, termID string) (bool, error) {
 	const query = `SELECT 1 FROM teacher_assignments WHERE teacher_id = $1 AND class_id = $2 AND subject_id = $3 AND term_id = $4 LIMIT 1`
@@ -65,6 +170,25 @@ func (r *TeacherAssignmentRepository) Exists(ctx context.Context, teacherID, cla
 	return true, nil
 }
 
+// FindByID loads an assignment by its identifier.
+func (r *TeacherAssignmentRepository) FindByID(ctx context.Context, id string) (*models.TeacherAssignment, error) {
+	const query = `SELECT id, teacher_id, class_id, subject_id, term_id, role, created_at FROM teacher_assignments WHERE id = $1`
+	var assignment models.TeacherAssignment
+	if err := r.db.GetContext(ctx, &assignment, query, id); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// TeacherOf returns the teacher an assignment belongs to.
+func (r *TeacherAssignmentRepository) TeacherOf(ctx context.Context, id string) (string, error) {
+	assignment, err := r.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return assignment.TeacherID, nil
+}
+
 // Create inserts a new assignment.
 func (r *TeacherAssignmentRepository) Create(ctx context.Context, assignment *models.TeacherAssignment) error {
 	if assignment.ID == "" {
@@ -46,6 +46,37 @@ ORDER BY t.start_date DESC, c.name ASC`)).
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTeacherAssignmentRepositoryListByTeacherFiltered(t *testing.T) {
+	db, mock, cleanup := newTeacherAssignmentMock(t)
+	defer cleanup()
+	repo := NewTeacherAssignmentRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "teacher_id", "class_id", "subject_id", "term_id", "role", "created_at", "subject_name"}).
+		AddRow("assign-1", "teacher-1", "class-1", "subject-1", "term-1", "SUBJECT_TEACHER", time.Now(), "Math")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ta.id, ta.teacher_id, ta.class_id, ta.subject_id, ta.term_id, ta.role, ta.created_at, s.name AS subject_name FROM teacher_assignments ta JOIN classes c ON c.id = ta.class_id JOIN subjects s ON s.id = ta.subject_id WHERE ta.teacher_id = $1 AND ta.term_id = $2 ORDER BY c.name ASC, ta.created_at ASC, ta.id ASC LIMIT 10 OFFSET 10")).
+		WithArgs("teacher-1", "term-1").
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM teacher_assignments ta WHERE ta.teacher_id = $1 AND ta.term_id = $2")).
+		WithArgs("teacher-1", "term-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	assignments, total, err := repo.ListByTeacherFiltered(context.Background(), models.TeacherAssignmentFilter{
+		TeacherID: "teacher-1",
+		TermID:    "term-1",
+		Expand:    []string{models.TeacherAssignmentExpandSubject},
+		Page:      2,
+		PageSize:  10,
+		SortBy:    "class",
+		SortOrder: "asc",
+	})
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.Equal(t, "Math", assignments[0].SubjectName)
+	assert.Empty(t, assignments[0].ClassName)
+	assert.Equal(t, 11, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTeacherAssignmentRepositoryCreateDelete(t *testing.T) {
 	db, mock, cleanup := newTeacherAssignmentMock(t)
 	defer cleanup()
@@ -110,3 +141,21 @@ func TestTeacherAssignmentRepositoryListByClassAndTerm(t *testing.T) {
 	assert.Len(t, assignments, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestTeacherAssignmentRepositoryListByTerm(t *testing.T) {
+	db, mock, cleanup := newTeacherAssignmentMock(t)
+	defer cleanup()
+	repo := NewTeacherAssignmentRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "teacher_id", "class_id", "subject_id", "term_id", "role", "created_at"}).
+		AddRow("assign-1", "teacher-1", "class-1", "subject-1", "term-1", "SUBJECT_TEACHER", time.Now()).
+		AddRow("assign-2", "teacher-2", "class-2", "subject-1", "term-1", "SUBJECT_TEACHER", time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, teacher_id, class_id, subject_id, term_id, role, created_at FROM teacher_assignments WHERE term_id = $1")).
+		WithArgs("term-1").
+		WillReturnRows(rows)
+
+	assignments, err := repo.ListByTerm(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Len(t, assignments, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const teacherAttendanceColumns = `id, teacher_id, date, check_in_at, check_out_at, late_minutes, check_in_ip, check_out_ip,
+check_in_latitude, check_in_longitude, check_out_latitude, check_out_longitude, created_at, updated_at`
+
+// TeacherAttendanceRepository persists teacher clock-ins and clock-outs.
+type TeacherAttendanceRepository struct {
+	db *sqlx.DB
+}
+
+// NewTeacherAttendanceRepository constructs the repository.
+func NewTeacherAttendanceRepository(db *sqlx.DB) *TeacherAttendanceRepository {
+	return &TeacherAttendanceRepository{db: db}
+}
+
+// InsertCheckIn stores a clock-in unless the teacher already has a row for that date, in which case
+// the existing row is returned with created=false.
+func (r *TeacherAttendanceRepository) InsertCheckIn(ctx context.Context, record *models.TeacherAttendance) (*models.TeacherAttendance, bool, error) {
+	now := time.Now().UTC()
+	if record.ID == "" {
+		record.ID = uuid.NewString()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+	insert := `INSERT INTO teacher_attendance (id, teacher_id, date, check_in_at, late_minutes, check_in_ip, check_in_latitude, check_in_longitude, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (teacher_id, date) DO NOTHING
+RETURNING ` + teacherAttendanceColumns
+	var stored models.TeacherAttendance
+	err := r.db.GetContext(ctx, &stored, insert, record.ID, record.TeacherID, record.Date, record.CheckInAt, record.LateMinutes,
+		record.CheckInIP, record.CheckInLatitude, record.CheckInLongitude, record.CreatedAt, record.UpdatedAt)
+	if err == nil {
+		return &stored, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("insert teacher check-in: %w", err)
+	}
+	existing, err := r.FindByTeacherAndDate(ctx, record.TeacherID, record.Date)
+	if err != nil {
+		return nil, false, fmt.Errorf("load existing teacher attendance: %w", err)
+	}
+	return existing, false, nil
+}
+
+// FindByTeacherAndDate returns the teacher's row for a date; sql.ErrNoRows is returned unwrapped.
+func (r *TeacherAttendanceRepository) FindByTeacherAndDate(ctx context.Context, teacherID string, date time.Time) (*models.TeacherAttendance, error) {
+	var record models.TeacherAttendance
+	query := `SELECT ` + teacherAttendanceColumns + ` FROM teacher_attendance WHERE teacher_id = $1 AND date = $2`
+	if err := r.db.GetContext(ctx, &record, query, teacherID, date); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// RecordCheckOut sets the clock-out on a row that has none yet. sql.ErrNoRows is returned unwrapped
+// when the row is missing or was already closed.
+func (r *TeacherAttendanceRepository) RecordCheckOut(ctx context.Context, id string, checkOut models.TeacherCheckOut) (*models.TeacherAttendance, error) {
+	query := `UPDATE teacher_attendance
+SET check_out_at = $2, check_out_ip = $3, check_out_latitude = $4, check_out_longitude = $5, updated_at = $6
+WHERE id = $1 AND check_out_at IS NULL
+RETURNING ` + teacherAttendanceColumns
+	var stored models.TeacherAttendance
+	if err := r.db.GetContext(ctx, &stored, query, id, checkOut.At, checkOut.IP, checkOut.Latitude, checkOut.Longitude, time.Now().UTC()); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("record teacher check-out: %w", err)
+	}
+	return &stored, nil
+}
+
+// ListByTeacher returns a teacher's rows between two dates inclusive, oldest first.
+func (r *TeacherAttendanceRepository) ListByTeacher(ctx context.Context, teacherID string, from, to time.Time) ([]models.TeacherAttendance, error) {
+	query := `SELECT ` + teacherAttendanceColumns + ` FROM teacher_attendance
+WHERE teacher_id = $1 AND date BETWEEN $2 AND $3
+ORDER BY date ASC`
+	var records []models.TeacherAttendance
+	if err := r.db.SelectContext(ctx, &records, query, teacherID, from, to); err != nil {
+		return nil, fmt.Errorf("list teacher attendance: %w", err)
+	}
+	return records, nil
+}
+
+// Recap aggregates presence per active teacher between two dates inclusive. Teachers without any
+// clock-in are included with zero counts.
+func (r *TeacherAttendanceRepository) Recap(ctx context.Context, from, to time.Time) ([]models.TeacherAttendanceRecap, error) {
+	query := `SELECT t.id AS teacher_id, t.full_name,
+COUNT(ta.id) AS days_present,
+COUNT(ta.id) FILTER (WHERE ta.late_minutes > 0) AS late_days,
+COALESCE(SUM(ta.late_minutes), 0) AS late_minutes,
+COUNT(ta.id) FILTER (WHERE ta.check_out_at IS NULL) AS missing_checkouts,
+COALESCE(SUM(EXTRACT(EPOCH FROM (ta.check_out_at - ta.check_in_at)) / 60), 0)::BIGINT AS worked_minutes
+FROM teachers t
+LEFT JOIN teacher_attendance ta ON ta.teacher_id = t.id AND ta.date BETWEEN $1 AND $2
+WHERE t.active = TRUE
+GROUP BY t.id, t.full_name
+ORDER BY t.full_name ASC`
+	var recap []models.TeacherAttendanceRecap
+	if err := r.db.SelectContext(ctx, &recap, query, from, to); err != nil {
+		return nil, fmt.Errorf("teacher attendance recap: %w", err)
+	}
+	return recap, nil
+}
+
+// CountsForDate summarises clock-ins for one date against the number of active teachers.
+func (r *TeacherAttendanceRepository) CountsForDate(ctx context.Context, date time.Time) (*models.TeacherPresenceCounts, error) {
+	query := `SELECT (SELECT COUNT(*) FROM teachers WHERE active = TRUE) AS active_teachers,
+COUNT(ta.id) AS checked_in,
+COUNT(ta.id) FILTER (WHERE ta.late_minutes > 0) AS late,
+COUNT(ta.check_out_at) AS checked_out
+FROM teacher_attendance ta
+WHERE ta.date = $1`
+	var counts models.TeacherPresenceCounts
+	if err := r.db.GetContext(ctx, &counts, query, date); err != nil {
+		return nil, fmt.Errorf("count teacher presence: %w", err)
+	}
+	return &counts, nil
+}
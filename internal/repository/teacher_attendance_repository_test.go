@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+var teacherAttendanceRowColumns = []string{"id", "teacher_id", "date", "check_in_at", "check_out_at", "late_minutes", "check_in_ip", "check_out_ip",
+	"check_in_latitude", "check_in_longitude", "check_out_latitude", "check_out_longitude", "created_at", "updated_at"}
+
+func TestTeacherAttendanceRepositoryInsertCheckInKeepsExistingRow(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewTeacherAttendanceRepository(db)
+
+	date := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	first := date.Add(-25 * time.Minute)
+	mock.ExpectQuery(regexp.QuoteMeta("ON CONFLICT (teacher_id, date) DO NOTHING")).
+		WillReturnRows(sqlmock.NewRows(teacherAttendanceRowColumns))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM teacher_attendance WHERE teacher_id = $1 AND date = $2")).
+		WithArgs("teacher-1", date).
+		WillReturnRows(sqlmock.NewRows(teacherAttendanceRowColumns).
+			AddRow("ta-1", "teacher-1", date, first, nil, 0, "10.0.0.5", nil, nil, nil, nil, nil, time.Now(), time.Now()))
+
+	stored, created, err := repo.InsertCheckIn(context.Background(), &models.TeacherAttendance{TeacherID: "teacher-1", Date: date, CheckInAt: date})
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "ta-1", stored.ID)
+	assert.Equal(t, first, stored.CheckInAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeacherAttendanceRepositoryRecordCheckOutOnlyOnce(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewTeacherAttendanceRepository(db)
+
+	at := time.Date(2024, 8, 5, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id = $1 AND check_out_at IS NULL")).
+		WithArgs("ta-1", at, nil, nil, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(teacherAttendanceRowColumns))
+
+	_, err := repo.RecordCheckOut(context.Background(), "ta-1", models.TeacherCheckOut{At: at})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeacherAttendanceRepositoryRecap(t *testing.T) {
+	db, mock, cleanup := newStudentMock(t)
+	defer cleanup()
+	repo := NewTeacherAttendanceRepository(db)
+
+	from := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("LEFT JOIN teacher_attendance ta ON ta.teacher_id = t.id AND ta.date BETWEEN $1 AND $2")).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "full_name", "days_present", "late_days", "late_minutes", "missing_checkouts", "worked_minutes"}).
+			AddRow("teacher-1", "Bu Sari", 20, 2, 17, 1, 9120).
+			AddRow("teacher-2", "Pak Budi", 0, 0, 0, 0, 0))
+
+	recap, err := repo.Recap(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Len(t, recap, 2)
+	assert.Equal(t, 9120, recap[0].WorkedMinutes)
+	assert.Zero(t, recap[1].DaysPresent)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
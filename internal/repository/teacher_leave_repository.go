@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const teacherLeaveColumns = `l.id, l.teacher_id, t.full_name AS teacher_name, l.type, l.start_date, l.end_date, l.days, l.reason, l.status,
+l.requested_by, l.reviewed_by, l.reviewed_at, l.review_note, l.created_at, l.updated_at`
+
+// TeacherLeaveRepository persists teacher leave requests.
+type TeacherLeaveRepository struct {
+	db *sqlx.DB
+}
+
+// NewTeacherLeaveRepository constructs the repository.
+func NewTeacherLeaveRepository(db *sqlx.DB) *TeacherLeaveRepository {
+	return &TeacherLeaveRepository{db: db}
+}
+
+// Create stores a new leave request.
+func (r *TeacherLeaveRepository) Create(ctx context.Context, leave *models.TeacherLeave) error {
+	if leave.ID == "" {
+		leave.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	leave.CreatedAt, leave.UpdatedAt = now, now
+	const query = `INSERT INTO teacher_leaves (id, teacher_id, type, start_date, end_date, days, reason, status, requested_by, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	if _, err := r.db.ExecContext(ctx, query, leave.ID, leave.TeacherID, leave.Type, leave.StartDate, leave.EndDate, leave.Days,
+		leave.Reason, leave.Status, leave.RequestedBy, leave.CreatedAt, leave.UpdatedAt); err != nil {
+		return fmt.Errorf("create teacher leave: %w", err)
+	}
+	return nil
+}
+
+// FindByID loads a leave with the teacher's name.
+func (r *TeacherLeaveRepository) FindByID(ctx context.Context, id string) (*models.TeacherLeave, error) {
+	query := `SELECT ` + teacherLeaveColumns + ` FROM teacher_leaves l JOIN teachers t ON t.id = l.teacher_id WHERE l.id = $1`
+	var leave models.TeacherLeave
+	if err := r.db.GetContext(ctx, &leave, query, id); err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// List returns leaves matching the filter, latest start first.
+func (r *TeacherLeaveRepository) List(ctx context.Context, filter models.TeacherLeaveFilter) ([]models.TeacherLeave, error) {
+	where := []string{"1=1"}
+	var args []interface{}
+	if filter.TeacherID != "" {
+		args = append(args, filter.TeacherID)
+		where = append(where, fmt.Sprintf("l.teacher_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("l.status = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where = append(where, fmt.Sprintf("l.end_date >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where = append(where, fmt.Sprintf("l.start_date <= $%d", len(args)))
+	}
+	query := `SELECT ` + teacherLeaveColumns + ` FROM teacher_leaves l JOIN teachers t ON t.id = l.teacher_id WHERE ` +
+		strings.Join(where, " AND ") + ` ORDER BY l.start_date DESC, l.created_at DESC`
+	var leaves []models.TeacherLeave
+	if err := r.db.SelectContext(ctx, &leaves, query, args...); err != nil {
+		return nil, fmt.Errorf("list teacher leaves: %w", err)
+	}
+	return leaves, nil
+}
+
+// HasOverlap reports whether the teacher has a pending or approved leave overlapping the range.
+func (r *TeacherLeaveRepository) HasOverlap(ctx context.Context, teacherID string, start, end time.Time) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM teacher_leaves WHERE teacher_id = $1 AND status IN ('PENDING', 'APPROVED') AND start_date <= $3 AND end_date >= $2)`
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, query, teacherID, start, end); err != nil {
+		return false, fmt.Errorf("check teacher leave overlap: %w", err)
+	}
+	return exists, nil
+}
+
+// SetStatus moves a leave in one of the from statuses to status, recording who decided and why. It
+// returns sql.ErrNoRows when the leave does not exist or has moved on in the meantime.
+func (r *TeacherLeaveRepository) SetStatus(ctx context.Context, id string, from []models.TeacherLeaveStatus, status models.TeacherLeaveStatus, actorID string, note *string, at time.Time) error {
+	expected := make([]string, len(from))
+	for i, s := range from {
+		expected[i] = string(s)
+	}
+	const query = `UPDATE teacher_leaves SET status = $3, reviewed_by = $4, reviewed_at = $5, review_note = $6, updated_at = $5
+WHERE id = $1 AND status = ANY($2)`
+	res, err := r.db.ExecContext(ctx, query, id, pq.Array(expected), status, actorID, at, note)
+	if err != nil {
+		return fmt.Errorf("update teacher leave status: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check teacher leave status rows: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TeachersOnLeave returns the teachers with approved leave covering the date.
+func (r *TeacherLeaveRepository) TeachersOnLeave(ctx context.Context, date time.Time) ([]string, error) {
+	const query = `SELECT DISTINCT teacher_id FROM teacher_leaves WHERE status = 'APPROVED' AND start_date <= $1 AND end_date >= $1`
+	var ids []string
+	if err := r.db.SelectContext(ctx, &ids, query, date); err != nil {
+		return nil, fmt.Errorf("list teachers on leave: %w", err)
+	}
+	return ids, nil
+}
+
+// Usage sums approved and pending leave days per active teacher and type for leaves starting in the
+// year. Teachers without leave that year get one row with a nil type; teacherID narrows the report to
+// one teacher.
+func (r *TeacherLeaveRepository) Usage(ctx context.Context, year int, teacherID string) ([]models.TeacherLeaveUsage, error) {
+	args := []interface{}{year}
+	where := "t.active = TRUE"
+	if teacherID != "" {
+		args = append(args, teacherID)
+		where = "t.id = $2"
+	}
+	query := `SELECT t.id AS teacher_id, t.full_name AS teacher_name, l.type,
+    COALESCE(SUM(CASE WHEN l.status = 'APPROVED' THEN l.days ELSE 0 END), 0) AS used,
+    COALESCE(SUM(CASE WHEN l.status = 'PENDING' THEN l.days ELSE 0 END), 0) AS pending
+FROM teachers t
+LEFT JOIN teacher_leaves l ON l.teacher_id = t.id AND l.status IN ('APPROVED', 'PENDING') AND EXTRACT(YEAR FROM l.start_date) = $1
+WHERE ` + where + `
+GROUP BY t.id, t.full_name, l.type
+ORDER BY t.full_name ASC, t.id ASC`
+	var usage []models.TeacherLeaveUsage
+	if err := r.db.SelectContext(ctx, &usage, query, args...); err != nil {
+		return nil, fmt.Errorf("sum teacher leave usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestTeacherLeaveRepositorySetStatus(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTeacherLeaveRepository(sqlx.NewDb(db, "sqlmock"))
+
+	at := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	note := "get well soon"
+	mock.ExpectExec(`UPDATE teacher_leaves SET status = \$3, reviewed_by = \$4, reviewed_at = \$5, review_note = \$6, updated_at = \$5\s+WHERE id = \$1 AND status = ANY\(\$2\)`).
+		WithArgs("leave-1", sqlmock.AnyArg(), models.TeacherLeaveApproved, "admin-1", at, &note).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SetStatus(context.Background(), "leave-1", []models.TeacherLeaveStatus{models.TeacherLeavePending}, models.TeacherLeaveApproved, "admin-1", &note, at))
+
+	// A leave reviewed in the meantime no longer matches.
+	mock.ExpectExec("UPDATE teacher_leaves SET status").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = repo.SetStatus(context.Background(), "leave-1", []models.TeacherLeaveStatus{models.TeacherLeavePending}, models.TeacherLeaveRejected, "admin-1", nil, at)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeacherLeaveRepositoryHasOverlap(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTeacherLeaveRepository(sqlx.NewDb(db, "sqlmock"))
+
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+	mock.ExpectQuery(regexp.QuoteMeta("status IN ('PENDING', 'APPROVED') AND start_date <= $3 AND end_date >= $2")).
+		WithArgs("teacher-1", start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	overlap, err := repo.HasOverlap(context.Background(), "teacher-1", start, end)
+	require.NoError(t, err)
+	assert.True(t, overlap)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeacherLeaveRepositoryUsage(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTeacherLeaveRepository(sqlx.NewDb(db, "sqlmock"))
+
+	mock.ExpectQuery(`LEFT JOIN teacher_leaves l ON .*EXTRACT\(YEAR FROM l.start_date\) = \$1\s+WHERE t.active = TRUE\s+GROUP BY t.id, t.full_name, l.type`).
+		WithArgs(2026).
+		WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "teacher_name", "type", "used", "pending"}).
+			AddRow("teacher-1", "Ani", "SICK", 3, 1).
+			AddRow("teacher-2", "Budi", nil, 0, 0))
+
+	usage, err := repo.Usage(context.Background(), 2026, "")
+	require.NoError(t, err)
+	require.Len(t, usage, 2)
+	require.NotNil(t, usage[0].Type)
+	assert.Equal(t, models.TeacherLeaveSick, *usage[0].Type)
+	assert.Equal(t, 3, usage[0].Used)
+	assert.Equal(t, 1, usage[0].Pending)
+	assert.Nil(t, usage[1].Type)
+
+	mock.ExpectQuery(`WHERE t.id = \$2`).
+		WithArgs(2026, "teacher-1").
+		WillReturnRows(sqlmock.NewRows([]string{"teacher_id", "teacher_name", "type", "used", "pending"}))
+	_, err = repo.Usage(context.Background(), 2026, "teacher-1")
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/models"
 )
 
+const upsertTeacherPreferenceQuery = `INSERT INTO teacher_preferences (id, teacher_id, max_load_per_day, max_load_per_week, unavailable, created_at, updated_at)
+		VALUES (:id, :teacher_id, :max_load_per_day, :max_load_per_week, :unavailable, :created_at, :updated_at)
+		ON CONFLICT (teacher_id) DO UPDATE
+		SET max_load_per_day = EXCLUDED.max_load_per_day,
+		    max_load_per_week = EXCLUDED.max_load_per_week,
+		    unavailable = EXCLUDED.unavailable,
+		    updated_at = EXCLUDED.updated_at`
+
 // TeacherPreferenceRepository persists teacher preferences.
 type TeacherPreferenceRepository struct {
 	db *sqlx.DB
@@ -31,6 +39,16 @@ func (r *TeacherPreferenceRepository) GetByTeacher(ctx context.Context, teacherI
 	return &pref, nil
 }
 
+// List returns the stored preferences of every teacher.
+func (r *TeacherPreferenceRepository) List(ctx context.Context) ([]models.TeacherPreference, error) {
+	const query = `SELECT id, teacher_id, max_load_per_day, max_load_per_week, unavailable, created_at, updated_at FROM teacher_preferences ORDER BY teacher_id`
+	var prefs []models.TeacherPreference
+	if err := r.db.SelectContext(ctx, &prefs, query); err != nil {
+		return nil, fmt.Errorf("list teacher preferences: %w", err)
+	}
+	return prefs, nil
+}
+
 // Upsert creates or updates teacher preferences.
 func (r *TeacherPreferenceRepository) Upsert(ctx context.Context, pref *models.TeacherPreference) error {
 	if pref.ID == "" {
@@ -45,15 +63,46 @@ func (r *TeacherPreferenceRepository) Upsert(ctx context.Context, pref *models.T
 		pref.Unavailable = []byte("[]")
 	}
 
-	const query = `INSERT INTO teacher_preferences (id, teacher_id, max_load_per_day, max_load_per_week, unavailable, created_at, updated_at)
-		VALUES (:id, :teacher_id, :max_load_per_day, :max_load_per_week, :unavailable, :created_at, :updated_at)
-		ON CONFLICT (teacher_id) DO UPDATE
-		SET max_load_per_day = EXCLUDED.max_load_per_day,
-		    max_load_per_week = EXCLUDED.max_load_per_week,
-		    unavailable = EXCLUDED.unavailable,
-		    updated_at = EXCLUDED.updated_at`
-	if _, err := r.db.NamedExecContext(ctx, query, pref); err != nil {
+	if _, err := r.db.NamedExecContext(ctx, upsertTeacherPreferenceQuery, pref); err != nil {
 		return fmt.Errorf("upsert teacher preference: %w", err)
 	}
 	return nil
 }
+
+// UpsertMany creates or updates the preferences of several teachers in one transaction.
+func (r *TeacherPreferenceRepository) UpsertMany(ctx context.Context, prefs []models.TeacherPreference) (err error) {
+	if len(prefs) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin upsert teacher preferences: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	now := time.Now().UTC()
+	for i := range prefs {
+		pref := &prefs[i]
+		if pref.ID == "" {
+			pref.ID = uuid.NewString()
+		}
+		if pref.CreatedAt.IsZero() {
+			pref.CreatedAt = now
+		}
+		pref.UpdatedAt = now
+		if len(pref.Unavailable) == 0 {
+			pref.Unavailable = []byte("[]")
+		}
+		if _, err = tx.NamedExecContext(ctx, upsertTeacherPreferenceQuery, pref); err != nil {
+			return fmt.Errorf("upsert teacher preference %s: %w", pref.TeacherID, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit teacher preferences: %w", err)
+	}
+	return nil
+}
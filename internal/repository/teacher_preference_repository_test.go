@@ -50,3 +50,25 @@ func TestTeacherPreferenceRepositoryGetAndUpsert(t *testing.T) {
 	assert.Equal(t, "pref-1", pref.ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestTeacherPreferenceRepositoryUpsertMany(t *testing.T) {
+	db, mock, cleanup := newTeacherPrefMock(t)
+	defer cleanup()
+	repo := NewTeacherPreferenceRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO teacher_preferences").
+		WithArgs(sqlmock.AnyArg(), "teacher-1", 6, 30, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO teacher_preferences").
+		WithArgs(sqlmock.AnyArg(), "teacher-2", 0, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err := repo.UpsertMany(context.Background(), []models.TeacherPreference{
+		{TeacherID: "teacher-1", MaxLoadPerDay: 6, MaxLoadPerWeek: 30},
+		{TeacherID: "teacher-2"},
+	})
+	require.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
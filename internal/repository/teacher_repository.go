@@ -23,8 +23,62 @@ func NewTeacherRepository(db *sqlx.DB) *TeacherRepository {
 	return &TeacherRepository{db: db}
 }
 
-// List returns teachers matching filters along with total count.
+// List returns teachers matching filters along with total count. A search matches the name, email
+// or NIP as a substring or the name fuzzily; without an explicit sort the closest names come first.
 func (r *TeacherRepository) List(ctx context.Context, filter models.TeacherFilter) ([]models.Teacher, int, error) {
+	base, args, orderLimit := teacherListClauses(filter)
+
+	var teachers []models.Teacher
+	var total int
+	err := queryWithFuzzySearch(ctx, r.db, filter.Search != "", func(q sqlx.QueryerContext) error {
+		query := fmt.Sprintf("SELECT id, nip, email, full_name, phone, expertise, active, created_at, updated_at %s %s", base, orderLimit)
+		if err := sqlx.SelectContext(ctx, q, &teachers, query, args...); err != nil {
+			return fmt.Errorf("list teachers: %w", err)
+		}
+		countQuery := fmt.Sprintf("SELECT COUNT(*) %s", base)
+		if err := sqlx.GetContext(ctx, q, &total, countQuery, args...); err != nil {
+			return fmt.Errorf("count teachers: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return teachers, total, nil
+}
+
+// ListWithTermLoad returns teachers matching filters together with their assignment count,
+// homeroom flag and weekly scheduled slots for filter.TermID, computed in a single query.
+func (r *TeacherRepository) ListWithTermLoad(ctx context.Context, filter models.TeacherFilter) ([]models.TeacherWithLoad, int, error) {
+	base, args, orderLimit := teacherListClauses(filter)
+	term := len(args) + 1
+
+	query := fmt.Sprintf(`SELECT id, nip, email, full_name, phone, expertise, active, created_at, updated_at,
+	(SELECT COUNT(*) FROM teacher_assignments ta WHERE ta.teacher_id = teachers.id AND ta.term_id = $%[1]d) AS assignment_count,
+	EXISTS (SELECT 1 FROM teacher_assignments ta WHERE ta.teacher_id = teachers.id AND ta.term_id = $%[1]d AND ta.role = 'HOMEROOM') AS is_homeroom,
+	(SELECT COUNT(*) FROM schedules s WHERE s.teacher_id = teachers.id AND s.term_id = $%[1]d) AS weekly_load
+	%[2]s %[3]s`, term, base, orderLimit)
+	var teachers []models.TeacherWithLoad
+	var total int
+	err := queryWithFuzzySearch(ctx, r.db, filter.Search != "", func(q sqlx.QueryerContext) error {
+		if err := sqlx.SelectContext(ctx, q, &teachers, query, append(args, filter.TermID)...); err != nil {
+			return fmt.Errorf("list teachers with load: %w", err)
+		}
+		countQuery := fmt.Sprintf("SELECT COUNT(*) %s", base)
+		if err := sqlx.GetContext(ctx, q, &total, countQuery, args...); err != nil {
+			return fmt.Errorf("count teachers: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return teachers, total, nil
+}
+
+// teacherListClauses builds the FROM/WHERE clause, its arguments and the ORDER/LIMIT suffix shared by
+// the teacher list queries.
+func teacherListClauses(filter models.TeacherFilter) (string, []interface{}, string) {
 	base := "FROM teachers WHERE 1=1"
 	var conditions []string
 	var args []interface{}
@@ -33,16 +87,31 @@ func (r *TeacherRepository) List(ctx context.Context, filter models.TeacherFilte
 		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)+1))
 		args = append(args, *filter.Active)
 	}
+	rank := ""
 	if filter.Search != "" {
-		search := "%" + strings.ToLower(filter.Search) + "%"
-		conditions = append(conditions, fmt.Sprintf("(LOWER(full_name) LIKE $%d OR LOWER(email) LIKE $%d OR LOWER(COALESCE(nip, '')) LIKE $%d)", len(args)+1, len(args)+1, len(args)+1))
-		args = append(args, search)
+		pattern, term := len(args)+1, len(args)+2
+		conditions = append(conditions, fmt.Sprintf(`(full_name ILIKE $%[1]d ESCAPE '\' OR email ILIKE $%[1]d ESCAPE '\' OR nip ILIKE $%[1]d ESCAPE '\' OR $%[2]d <%% full_name)`, pattern, term))
+		args = append(args, likePattern(filter.Search), filter.Search)
+		rank = fmt.Sprintf("word_similarity($%d, full_name) DESC, full_name ASC", term)
 	}
 
 	if len(conditions) > 0 {
 		base += " AND " + strings.Join(conditions, " AND ")
 	}
 
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	size := filter.PageSize
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+	limit := fmt.Sprintf("LIMIT %d OFFSET %d", size, (page-1)*size)
+	if rank != "" && filter.SortBy == "" {
+		return base, args, fmt.Sprintf("ORDER BY %s %s", rank, limit)
+	}
+
 	sortBy := filter.SortBy
 	if sortBy == "" {
 		sortBy = "created_at"
@@ -62,30 +131,7 @@ func (r *TeacherRepository) List(ctx context.Context, filter models.TeacherFilte
 	if order != "ASC" && order != "DESC" {
 		order = "DESC"
 	}
-
-	page := filter.Page
-	if page < 1 {
-		page = 1
-	}
-	size := filter.PageSize
-	if size <= 0 || size > 100 {
-		size = 20
-	}
-	offset := (page - 1) * size
-
-	query := fmt.Sprintf("SELECT id, nip, email, full_name, phone, expertise, active, created_at, updated_at %s ORDER BY %s %s LIMIT %d OFFSET %d", base, column, order, size, offset)
-	var teachers []models.Teacher
-	if err := r.db.SelectContext(ctx, &teachers, query, args...); err != nil {
-		return nil, 0, fmt.Errorf("list teachers: %w", err)
-	}
-
-	countQuery := fmt.Sprintf("SELECT COUNT(*) %s", base)
-	var total int
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("count teachers: %w", err)
-	}
-
-	return teachers, total, nil
+	return base, args, fmt.Sprintf("ORDER BY %s %s %s", column, order, limit)
 }
 
 // FindByID fetches a teacher by ID.
@@ -186,6 +232,23 @@ func (r *TeacherRepository) Update(ctx context.Context, teacher *models.Teacher)
 	return nil
 }
 
+var teacherPatchColumns = map[string]struct{}{
+	"nip": {}, "email": {}, "full_name": {}, "phone": {}, "expertise": {}, "active": {},
+}
+
+// UpdateFields writes only the given columns of teacher so concurrent edits to other fields survive.
+func (r *TeacherRepository) UpdateFields(ctx context.Context, teacher *models.Teacher, columns []string) error {
+	query, err := partialUpdateQuery("teachers", columns, teacherPatchColumns)
+	if err != nil {
+		return fmt.Errorf("patch teacher: %w", err)
+	}
+	teacher.UpdatedAt = time.Now().UTC()
+	if _, err := r.db.NamedExecContext(ctx, query, teacher); err != nil {
+		return fmt.Errorf("patch teacher: %w", err)
+	}
+	return nil
+}
+
 // Deactivate sets a teacher's active flag to false.
 func (r *TeacherRepository) Deactivate(ctx context.Context, id string) error {
 	const query = `UPDATE teachers SET active = FALSE, updated_at = $2 WHERE id = $1`
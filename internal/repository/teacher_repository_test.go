@@ -39,6 +39,70 @@ func TestTeacherRepositoryList(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTeacherRepositoryListFuzzySearch(t *testing.T) {
+	db, mock, cleanup := newTeacherRepoMock(t)
+	defer cleanup()
+	repo := NewTeacherRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "nip", "email", "full_name", "phone", "expertise", "active", "created_at", "updated_at"}).
+		AddRow("t1", nil, "siti@example.com", "Siti Nurhaliza", nil, nil, true, time.Now(), time.Now())
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)")).
+		WithArgs("0.3").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM teachers WHERE 1=1 AND (full_name ILIKE $1 ESCAPE '\' OR email ILIKE $1 ESCAPE '\' OR nip ILIKE $1 ESCAPE '\' OR $2 <% full_name) ORDER BY word_similarity($2, full_name) DESC, full_name ASC LIMIT 20 OFFSET 0`)).
+		WithArgs("%sitti\\_n%", "sitti_n").
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM teachers WHERE 1=1 AND (full_name ILIKE $1")).
+		WithArgs("%sitti\\_n%", "sitti_n").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	list, total, err := repo.List(context.Background(), models.TeacherFilter{Search: "sitti_n"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, 1, total)
+
+	// An explicit sort wins over the similarity ranking.
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT set_config").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`<% full_name\) ORDER BY full_name ASC LIMIT 20 OFFSET 0`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectCommit()
+
+	_, _, err = repo.List(context.Background(), models.TeacherFilter{Search: "budi", SortBy: "full_name", SortOrder: "asc"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTeacherRepositoryListWithTermLoad(t *testing.T) {
+	db, mock, cleanup := newTeacherRepoMock(t)
+	defer cleanup()
+	repo := NewTeacherRepository(db)
+
+	active := true
+	rows := sqlmock.NewRows([]string{"id", "nip", "email", "full_name", "phone", "expertise", "active", "created_at", "updated_at", "assignment_count", "is_homeroom", "weekly_load"}).
+		AddRow("t1", nil, "a@example.com", "Teacher A", nil, nil, true, time.Now(), time.Now(), 3, true, 18)
+	mock.ExpectQuery(`SELECT id, nip, .*AS assignment_count.*ta\.role = 'HOMEROOM'\) AS is_homeroom.*s\.term_id = \$2\) AS weekly_load\s+FROM teachers WHERE 1=1 AND active = \$1 ORDER BY full_name ASC LIMIT 20 OFFSET 0`).
+		WithArgs(true, "term-1").
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM teachers WHERE 1=1 AND active = $1")).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	list, total, err := repo.ListWithTermLoad(context.Background(), models.TeacherFilter{Active: &active, TermID: "term-1", SortBy: "full_name", SortOrder: "asc"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Teacher A", list[0].FullName)
+	assert.Equal(t, 3, list[0].AssignmentCount)
+	assert.True(t, list[0].IsHomeroom)
+	assert.Equal(t, 18, list[0].WeeklyLoad)
+	assert.Equal(t, 1, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTeacherRepositoryCreateAndDeactivate(t *testing.T) {
 	db, mock, cleanup := newTeacherRepoMock(t)
 	defer cleanup()
@@ -59,6 +123,21 @@ func TestTeacherRepositoryCreateAndDeactivate(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestTeacherRepositoryUpdateFields(t *testing.T) {
+	db, mock, cleanup := newTeacherRepoMock(t)
+	defer cleanup()
+	repo := NewTeacherRepository(db)
+
+	mock.ExpectExec(`UPDATE teachers SET active = \?, updated_at = \? WHERE id = \?`).
+		WithArgs(false, sqlmock.AnyArg(), "id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, repo.UpdateFields(context.Background(), &models.Teacher{ID: "id-1", Email: "a@example.com"}, []string{"active"}))
+	assert.Error(t, repo.UpdateFields(context.Background(), &models.Teacher{ID: "id-1"}, []string{"created_at"}))
+	assert.Error(t, repo.UpdateFields(context.Background(), &models.Teacher{ID: "id-1"}, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTeacherRepositoryExistsByEmail(t *testing.T) {
 	db, mock, cleanup := newTeacherRepoMock(t)
 	defer cleanup()
@@ -58,6 +58,16 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string, ts time
 	return nil
 }
 
+// ListRecentlyActive returns ids of active users with role who logged in since, most recent first.
+func (r *UserRepository) ListRecentlyActive(ctx context.Context, role models.UserRole, since time.Time, limit int) ([]string, error) {
+	const query = `SELECT id FROM users WHERE role = $1 AND active = TRUE AND last_login >= $2 ORDER BY last_login DESC LIMIT $3`
+	var ids []string
+	if err := r.db.SelectContext(ctx, &ids, query, role, since, limit); err != nil {
+		return nil, fmt.Errorf("list recently active users: %w", err)
+	}
+	return ids, nil
+}
+
 // UpdatePassword updates the stored password hash.
 func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string, updatedAt time.Time) error {
 	const query = `UPDATE users SET password_hash = $2, updated_at = $3 WHERE id = $1`
@@ -172,15 +182,26 @@ func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+const refreshTokenColumns = `id, user_id, token, expires_at, created_at, revoked, revoked_at, ip_address, user_agent, session_id, session_started_at, last_active_at`
+
 // CreateRefreshToken persists a refresh token entry.
 func (r *UserRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
 	if token.ID == "" {
 		token.ID = uuid.NewString()
 	}
-	const query = `INSERT INTO refresh_tokens (id, user_id, token, expires_at, created_at, revoked, revoked_at, ip_address, user_agent) VALUES (:id, :user_id, :token, :expires_at, :created_at, :revoked, :revoked_at, :ip_address, :user_agent)`
+	const query = `INSERT INTO refresh_tokens (id, user_id, token, expires_at, created_at, revoked, revoked_at, ip_address, user_agent, session_id, session_started_at, last_active_at) VALUES (:id, :user_id, :token, :expires_at, :created_at, :revoked, :revoked_at, :ip_address, :user_agent, :session_id, :session_started_at, :last_active_at)`
 	if token.CreatedAt.IsZero() {
 		token.CreatedAt = time.Now().UTC()
 	}
+	if token.SessionID == "" {
+		token.SessionID = token.ID
+	}
+	if token.SessionStartedAt.IsZero() {
+		token.SessionStartedAt = token.CreatedAt
+	}
+	if token.LastActiveAt.IsZero() {
+		token.LastActiveAt = token.CreatedAt
+	}
 	if _, err := r.db.NamedExecContext(ctx, query, token); err != nil {
 		return fmt.Errorf("create refresh token: %w", err)
 	}
@@ -189,7 +210,7 @@ func (r *UserRepository) CreateRefreshToken(ctx context.Context, token *models.R
 
 // FindRefreshToken returns a refresh token by token string.
 func (r *UserRepository) FindRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error) {
-	const query = `SELECT id, user_id, token, expires_at, created_at, revoked, revoked_at, ip_address, user_agent FROM refresh_tokens WHERE token = $1 LIMIT 1`
+	const query = `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token = $1 LIMIT 1`
 	var rt models.RefreshToken
 	if err := r.db.GetContext(ctx, &rt, query, token); err != nil {
 		if err == sql.ErrNoRows {
@@ -218,6 +239,65 @@ func (r *UserRepository) RevokeUserRefreshTokens(ctx context.Context, userID str
 	return nil
 }
 
+// ListSessions returns the live refresh tokens of a user, one per session, most recently active
+// first.
+func (r *UserRepository) ListSessions(ctx context.Context, userID string, now time.Time) ([]models.RefreshToken, error) {
+	const query = `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE user_id = $1 AND revoked = FALSE AND expires_at > $2 ORDER BY last_active_at DESC, session_started_at DESC`
+	var sessions []models.RefreshToken
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, now); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// TouchSession records activity on the live token of a session that has been active since
+// activeSince, and reports whether there was one.
+func (r *UserRepository) TouchSession(ctx context.Context, userID, sessionID string, activeSince, at time.Time) (bool, error) {
+	const query = `UPDATE refresh_tokens SET last_active_at = $4
+WHERE session_id = $2 AND user_id = $1 AND revoked = FALSE AND expires_at > $4 AND last_active_at >= $3`
+	result, err := r.db.ExecContext(ctx, query, userID, sessionID, activeSince, at)
+	if err != nil {
+		return false, fmt.Errorf("touch session: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("check session rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RevokeExcessSessions keeps the keep most recently active sessions of a user and revokes the rest,
+// returning how many were revoked.
+func (r *UserRepository) RevokeExcessSessions(ctx context.Context, userID string, keep int, at time.Time) (int64, error) {
+	const query = `UPDATE refresh_tokens SET revoked = TRUE, revoked_at = $3 WHERE id IN (
+SELECT id FROM refresh_tokens WHERE user_id = $1 AND revoked = FALSE AND expires_at > $3
+ORDER BY last_active_at DESC, session_started_at DESC OFFSET $2)`
+	result, err := r.db.ExecContext(ctx, query, userID, keep, at)
+	if err != nil {
+		return 0, fmt.Errorf("revoke excess sessions: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check session rows: %w", err)
+	}
+	return affected, nil
+}
+
+// RevokeIdleSessions revokes every live refresh token not active since idleSince and returns how
+// many were revoked.
+func (r *UserRepository) RevokeIdleSessions(ctx context.Context, idleSince, at time.Time) (int64, error) {
+	const query = `UPDATE refresh_tokens SET revoked = TRUE, revoked_at = $2 WHERE revoked = FALSE AND expires_at > $2 AND last_active_at < $1`
+	result, err := r.db.ExecContext(ctx, query, idleSince, at)
+	if err != nil {
+		return 0, fmt.Errorf("revoke idle sessions: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check session rows: %w", err)
+	}
+	return affected, nil
+}
+
 // CreateAuditLog stores an audit log entry.
 func (r *UserRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
 	if log.ID == "" {
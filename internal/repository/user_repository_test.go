@@ -53,6 +53,36 @@ func TestCreateRefreshToken(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestSessionLimitsAndActivity(t *testing.T) {
+	db, mock, cleanup := newMock(t)
+	defer cleanup()
+	repo := NewUserRepository(db)
+	now := time.Now().UTC()
+
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked = TRUE, revoked_at = \$3 WHERE id IN \(\s*SELECT id FROM refresh_tokens WHERE user_id = \$1 .* ORDER BY last_active_at DESC, session_started_at DESC OFFSET \$2\)`).
+		WithArgs("u1", 2, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	ended, err := repo.RevokeExcessSessions(context.Background(), "u1", 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), ended)
+
+	idleSince := now.Add(-14 * 24 * time.Hour)
+	mock.ExpectExec(`UPDATE refresh_tokens SET last_active_at = \$4\s+WHERE session_id = \$2 AND user_id = \$1 AND revoked = FALSE AND expires_at > \$4 AND last_active_at >= \$3`).
+		WithArgs("u1", "session-1", idleSince, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	touched, err := repo.TouchSession(context.Background(), "u1", "session-1", idleSince, now)
+	require.NoError(t, err)
+	assert.False(t, touched)
+
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked = TRUE, revoked_at = \$2 WHERE revoked = FALSE AND expires_at > \$2 AND last_active_at < \$1`).
+		WithArgs(idleSince, now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	ended, err = repo.RevokeIdleSessions(context.Background(), idleSince, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), ended)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestListUsers(t *testing.T) {
 	db, mock, cleanup := newMock(t)
 	defer cleanup()
@@ -0,0 +1,244 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/handler"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+)
+
+// teacherQuery makes the teacher named by the teacher_id query the subject of SELF.
+var teacherQuery = auth.Query("teacher_id", "teacherId")
+
+// RegisterTeachers mounts teacher records, assignments and scheduling preferences.
+func RegisterTeachers(rg *gin.RouterGroup, h *handler.TeacherHandler, owners Owners) {
+	teachers := rg.Group("/teachers")
+	teachers.GET("", admins(), h.List)
+	teachers.POST("", admins(), h.Create)
+	teachers.GET("/:id", selfOrAdmins(), h.Get)
+	teachers.PUT("/:id", admins(), h.Update)
+	teachers.PATCH("/:id", admins(), h.Patch)
+	teachers.DELETE("/:id", superAdmins(), h.Delete)
+	teachers.GET("/:id/assignments", selfOrAdmins(), h.ListAssignments)
+	teachers.POST("/:id/assignments", admins(), h.CreateAssignment)
+	teachers.GET("/:id/assignments/:aid", selfOrAdmins(auth.Param("id"), auth.Owned("aid", owners.TeacherAssignment)), h.GetAssignment)
+	teachers.DELETE("/:id/assignments/:aid", admins(), h.DeleteAssignment)
+	teachers.GET("/:id/preferences", selfOrAdmins(), h.GetPreferences)
+	teachers.PUT("/:id/preferences", selfOrAdmins(), h.UpsertPreferences)
+}
+
+// RegisterSchedulePreferences mounts the legacy /schedules/preferences aliases.
+func RegisterSchedulePreferences(rg *gin.RouterGroup, h *handler.SchedulePreferenceAliasHandler) {
+	schedules := rg.Group("/schedules")
+	schedules.GET("/preferences", selfOrAdmins(teacherQuery), h.Get)
+	schedules.POST("/preferences", selfOrAdmins(teacherQuery), h.Upsert)
+	schedules.GET("/preferences/export", admins(), h.Export)
+	schedules.POST("/preferences/import", admins(), h.Import)
+}
+
+// RegisterLessonGroups mounts lessons shared by several classes, their weekly sessions and session
+// attendance.
+func RegisterLessonGroups(rg *gin.RouterGroup, h *handler.LessonGroupHandler) {
+	groups := rg.Group("/lesson-groups")
+	groups.GET("", staff(), h.List)
+	groups.POST("", admins(), h.Create)
+	groups.GET("/:id", staff(), h.Get)
+	groups.PUT("/:id", admins(), h.Update)
+	groups.DELETE("/:id", admins(), h.Delete)
+	groups.GET("/:id/sessions", staff(), h.Sessions)
+	groups.POST("/:id/sessions", admins(), h.AddSession)
+	groups.DELETE("/:id/sessions", admins(), h.RemoveSession)
+	groups.GET("/:id/roster", staff(), h.Roster)
+	groups.POST("/:id/attendance", staff(), h.MarkAttendance)
+	groups.GET("/:id/attendance", staff(), h.AttendanceReport)
+}
+
+// RegisterElectives mounts elective offerings, students' ranked choices and the allocation of
+// students to sections.
+func RegisterElectives(rg *gin.RouterGroup, h *handler.ElectiveHandler) {
+	readers := roles(models.RoleStudent, models.RoleTeacher, models.RoleAdmin, models.RoleSuperAdmin)
+	choosers := roles(models.RoleStudent, models.RoleAdmin, models.RoleSuperAdmin)
+	electives := rg.Group("/electives")
+	electives.GET("", readers, h.List)
+	electives.POST("", admins(), h.Create)
+	electives.GET("/selections", choosers, h.ListSelections)
+	electives.PUT("/selections", choosers, h.SubmitSelections)
+	electives.POST("/allocate", admins(), h.Allocate)
+	electives.GET("/allocation", admins(), h.Allocation)
+	electives.GET("/:id", readers, h.Get)
+	electives.PUT("/:id", admins(), h.Update)
+	electives.DELETE("/:id", admins(), h.Delete)
+}
+
+// RegisterSubjectLoadPresets mounts the curriculum load presets used by schedule generation.
+func RegisterSubjectLoadPresets(rg *gin.RouterGroup, h *handler.SubjectLoadPresetHandler) {
+	presets := rg.Group("/schedule/presets")
+	presets.GET("", admins(), h.List)
+	presets.POST("", admins(), h.Create)
+	presets.GET("/:id", admins(), h.Get)
+	presets.PUT("/:id", admins(), h.Update)
+	presets.DELETE("/:id", admins(), h.Delete)
+}
+
+// RegisterTermSlots mounts the per-term lesson slot definitions.
+func RegisterTermSlots(rg *gin.RouterGroup, h *handler.SlotDefinitionHandler) {
+	slots := rg.Group("/terms/:id/slots")
+	slots.GET("", staff(), h.List)
+	slots.POST("", admins(), h.Create)
+	slots.PUT("/:slotId", admins(), h.Update)
+	slots.DELETE("/:slotId", admins(), h.Delete)
+}
+
+// RegisterExams mounts exam periods and timetables. exports may be nil when reports are disabled.
+func RegisterExams(rg *gin.RouterGroup, h *handler.ExamHandler, exports *handler.ExamExportHandler) {
+	periods := rg.Group("/exam-periods")
+	periods.GET("", staff(), h.ListPeriods)
+	periods.POST("", admins(), h.CreatePeriod)
+	periods.DELETE("/:id", admins(), h.DeletePeriod)
+	periods.GET("/:id/schedules", staff(), h.List)
+	periods.POST("/:id/schedules", admins(), h.Create)
+	periods.DELETE("/:id/schedules/:examId", admins(), h.Delete)
+	periods.POST("/:id/generate", admins(), h.Generate)
+	if exports != nil {
+		periods.GET("/:id/export", staff(), exports.Export)
+	}
+}
+
+// RegisterCurriculum mounts curriculum topics, progress marking and coverage.
+func RegisterCurriculum(rg *gin.RouterGroup, h *handler.CurriculumHandler) {
+	curriculum := rg.Group("/curriculum")
+	curriculum.GET("/topics", staff(), h.ListTopics)
+	curriculum.POST("/topics", admins(), h.CreateTopic)
+	curriculum.PUT("/topics/:id", admins(), h.UpdateTopic)
+	curriculum.DELETE("/topics/:id", admins(), h.DeleteTopic)
+	curriculum.POST("/topics/:id/progress", staff(), h.MarkTaught)
+	curriculum.DELETE("/topics/:id/progress/:classId", staff(), h.UnmarkTaught)
+	curriculum.GET("/progress", staff(), h.ClassProgress)
+	curriculum.GET("/coverage", admins(), h.Coverage)
+}
+
+// RegisterLessonPlans mounts weekly lesson plans and their review workflow.
+func RegisterLessonPlans(rg *gin.RouterGroup, h *handler.LessonPlanHandler) {
+	teachers := roles(models.RoleTeacher)
+	plans := rg.Group("/lesson-plans")
+	plans.GET("", staff(), h.List)
+	plans.GET("/:id", staff(), h.Get)
+	plans.POST("", teachers, h.Create)
+	plans.PUT("/:id", teachers, h.Update)
+	plans.DELETE("/:id", teachers, h.Delete)
+	plans.POST("/:id/attachment", teachers, h.Attach)
+	plans.POST("/:id/submit", teachers, h.Submit)
+	plans.POST("/:id/review", admins(), h.Review)
+}
+
+// RegisterGrades mounts grade entry, final grade calculation and finalization.
+func RegisterGrades(rg *gin.RouterGroup, h *handler.GradeHandler) {
+	grades := rg.Group("/grades")
+	grades.GET("", staff(), h.List)
+	grades.POST("", staff(), h.Upsert)
+	grades.POST("/bulk", staff(), h.Bulk)
+	grades.POST("/recalculate", staff(), h.Recalculate)
+	grades.POST("/remedial", staff(), h.Remedial)
+	grades.POST("/finalize", admins(), h.Finalize)
+	grades.POST("/finalize-class", admins(), h.FinalizeClass)
+	// Reopening only files a request; super admins approve it through /mutations/:id/review.
+	grades.POST("/unfinalize", admins(), h.Unfinalize)
+}
+
+// RegisterGradeAppeals mounts grade appeals. Students appeal their own grades and guardians those of
+// linked students; decisions are made by super admins through /mutations/:id/review.
+func RegisterGradeAppeals(rg *gin.RouterGroup, h *handler.GradeAppealHandler) {
+	rg.Group("/student", roles(models.RoleStudent)).POST("/grade-appeals", h.SubmitOwn)
+	rg.Group("/guardian", roles(models.RoleGuardian)).POST("/students/:studentId/grade-appeals", h.SubmitForStudent)
+
+	// The service narrows listings to what the caller's role may see.
+	appeals := rg.Group("/grade-appeals")
+	appeals.GET("", h.List)
+	appeals.GET("/:id", h.Get)
+	appeals.POST("/:id/respond", staff(), h.Respond)
+}
+
+// RegisterGradeConfigs mounts grade components and the per class/subject/term calculation configs.
+func RegisterGradeConfigs(rg *gin.RouterGroup, configs *handler.GradeConfigHandler, components *handler.GradeComponentHandler) {
+	rg.GET("/grade-components", staff(), components.List)
+	rg.POST("/grade-components", admins(), components.Create)
+	gradeConfigs := rg.Group("/grade-configs")
+	gradeConfigs.GET("", staff(), configs.List)
+	gradeConfigs.GET("/:id", staff(), configs.Get)
+	gradeConfigs.POST("", admins(), configs.Create)
+	gradeConfigs.PUT("/:id", admins(), configs.Update)
+	gradeConfigs.POST("/:id/finalize", admins(), configs.Finalize)
+}
+
+// RegisterCalendar mounts the teacher-facing calendar alias.
+func RegisterCalendar(rg *gin.RouterGroup, h *handler.CalendarAliasHandler) {
+	rg.GET("/calendar", staff(), h.List)
+}
+
+// RegisterCalendarEvents mounts event RSVPs and event-day check-ins. exports may be nil when reports
+// are disabled.
+func RegisterCalendarEvents(rg *gin.RouterGroup, h *handler.EventRSVPHandler, exports *handler.EventAttendanceExportHandler) {
+	events := rg.Group("/calendar/events/:id")
+	events.PUT("/rsvp-options", admins(), h.SetOptions)
+	events.PUT("/rsvp", roles(models.RoleTeacher, models.RoleGuardian), h.Respond)
+	events.GET("/rsvps", staff(), h.Report)
+	events.POST("/attendance", staff(), h.CheckIn)
+	if exports != nil {
+		events.GET("/attendance/export", staff(), exports.Export)
+	}
+}
+
+// RegisterHomerooms mounts homeroom assignment.
+func RegisterHomerooms(rg *gin.RouterGroup, h *handler.HomeroomHandler) {
+	homerooms := rg.Group("/homerooms")
+	homerooms.GET("", staff(), h.List)
+	homerooms.GET("/:classId", staff(), h.Get)
+	homerooms.POST("", admins(), h.Set)
+}
+
+// RegisterIntake mounts the transfer-in intake workflow.
+func RegisterIntake(rg *gin.RouterGroup, h *handler.IntakeHandler) {
+	intake := rg.Group("/intake", admins())
+	intake.GET("", h.List)
+	intake.POST("", h.Create)
+	intake.GET("/:id", h.Get)
+	intake.PUT("/:id", h.Update)
+	intake.POST("/:id/documents", h.AttachDocument)
+	intake.POST("/:id/decision", h.Decide)
+}
+
+// RegisterScheduleClashes mounts the daily schedule clash report. exports may be nil when reports are
+// disabled.
+func RegisterScheduleClashes(rg *gin.RouterGroup, h *handler.ScheduleClashHandler, exports *handler.ScheduleClashExportHandler) {
+	rg.GET("/schedules/clashes", admins(), h.Clashes)
+	if exports != nil {
+		rg.GET("/schedules/clashes/export", admins(), exports.Export)
+	}
+}
+
+// RegisterClassTimetable mounts the weekly class timetable.
+func RegisterClassTimetable(rg *gin.RouterGroup, h *handler.ClassTimetableHandler) {
+	rg.GET("/classes/:id/timetable", staff(), h.Week)
+}
+
+// RegisterScheduler mounts semester schedule generation. exports may be nil when reports are disabled.
+func RegisterScheduler(rg *gin.RouterGroup, h *handler.ScheduleGeneratorHandler, exports *handler.ScheduleExportHandler, warnings *handler.ScheduleWarningHandler) {
+	rg.POST("/schedule/generate", admins(), h.Generate)
+	rg.POST("/schedules/generator", admins(), h.GenerateAlias)
+	rg.POST("/schedule/save", admins(), h.Save)
+	rg.PATCH("/schedule/proposals/:id/slots", admins(), h.EditSlots)
+	rg.POST("/schedule/score", admins(), h.Score)
+	rg.GET("/schedule/proposals/:id/explain", admins(), h.Explain)
+	rg.GET("/schedule/constraints", admins(), h.Constraints)
+	rg.GET("/semester-schedule", staff(), h.List)
+	rg.GET("/semester-schedule/:id/slots", staff(), h.Slots)
+	rg.DELETE("/semester-schedule/:id", superAdmins(), h.Delete)
+	if exports != nil {
+		rg.GET("/semester-schedule/:id/export", staff(), exports.Export)
+	}
+	if warnings != nil {
+		rg.GET("/semester-schedule/:id/warnings", staff(), warnings.Warnings)
+		rg.POST("/semester-schedule/:id/revalidate", admins(), warnings.Revalidate)
+	}
+}
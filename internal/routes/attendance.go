@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/handler"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// RegisterAttendance mounts the attendance summary aliases and absence excuses.
+func RegisterAttendance(rg *gin.RouterGroup, h *handler.AttendanceAliasHandler) {
+	attendance := rg.Group("/attendance")
+	attendance.Use(staff())
+	attendance.GET("", h.Summary)
+	attendance.GET("/daily", h.Daily)
+	attendance.GET("/monthly", h.Monthly)
+	attendance.GET("/student/:id", h.Student)
+	attendance.PUT("/excuses", h.Excuse)
+}
+
+// RegisterAttendanceCheckin mounts device check-in on public, since devices authenticate with
+// X-Device-Key instead of a user token, and QR token issuance on secured.
+func RegisterAttendanceCheckin(public, secured *gin.RouterGroup, h *handler.AttendanceCheckinHandler) {
+	public.POST("/attendance/checkin", h.CheckIn)
+	secured.GET("/attendance/checkin/qr/:studentId", admins(), h.QRToken)
+}
+
+// RegisterAbsenceMessages mounts the absence message log and, on public, the messaging providers'
+// status callbacks, which authenticate with the provider's signature.
+func RegisterAbsenceMessages(public, secured *gin.RouterGroup, h *handler.AbsenceMessageHandler) {
+	public.GET("/messaging/callbacks/:provider", h.VerifyCallback)
+	public.POST("/messaging/callbacks/:provider", h.Callback)
+	secured.GET("/attendance/absence-messages", admins(), h.List)
+}
+
+// RegisterAttendanceImports mounts bulk attendance imports.
+func RegisterAttendanceImports(rg *gin.RouterGroup, h *handler.AttendanceImportHandler) {
+	imports := rg.Group("/attendance/imports")
+	imports.Use(admins())
+	imports.POST("", h.Create)
+	imports.GET("/:id", h.Status)
+}
+
+// RegisterAttendanceAlerts mounts attendance thresholds and the alerts raised against them.
+func RegisterAttendanceAlerts(rg *gin.RouterGroup, h *handler.AttendanceAlertHandler) {
+	attendance := rg.Group("/attendance")
+	attendance.GET("/thresholds", staff(), h.Thresholds)
+	attendance.PUT("/thresholds", admins(), h.SetThreshold)
+	attendance.DELETE("/thresholds/:classId", admins(), h.DeleteThreshold)
+	attendance.GET("/alerts", staff(), h.Alerts)
+	attendance.POST("/alerts/evaluate", admins(), h.Evaluate)
+}
+
+// RegisterAlertRules mounts alert rule management and the breaches found by their evaluation.
+func RegisterAlertRules(rg *gin.RouterGroup, h *handler.AlertRuleHandler) {
+	rules := rg.Group("/alert-rules")
+	rules.GET("", admins(), h.ListRules)
+	rules.POST("", admins(), h.CreateRule)
+	rules.GET("/breaches", staff(), h.Breaches)
+	rules.POST("/evaluate", admins(), h.Evaluate)
+	rules.PUT("/:id", admins(), h.UpdateRule)
+	rules.DELETE("/:id", admins(), h.DeleteRule)
+}
+
+// RegisterTeacherAttendance mounts teacher check-in/out and recaps.
+func RegisterTeacherAttendance(rg *gin.RouterGroup, h *handler.TeacherAttendanceHandler) {
+	teachers := roles(models.RoleTeacher)
+	attendance := rg.Group("/teacher-attendance")
+	attendance.POST("/checkin", teachers, h.CheckIn)
+	attendance.POST("/checkout", teachers, h.CheckOut)
+	attendance.GET("/recap", admins(), h.Recap)
+	attendance.GET("/teachers/:id", selfOrAdmins(), h.TeacherMonth)
+}
+
+// RegisterTeacherLeaves mounts teacher leave requests, their review and leave balances.
+func RegisterTeacherLeaves(rg *gin.RouterGroup, h *handler.TeacherLeaveHandler) {
+	leaves := rg.Group("/teacher-leaves")
+	leaves.POST("", staff(), h.Request)
+	leaves.GET("", staff(), h.List)
+	leaves.GET("/balance", staff(), h.Balance)
+	leaves.GET("/:id", staff(), h.Get)
+	leaves.POST("/:id/approve", admins(), h.Approve)
+	leaves.POST("/:id/reject", admins(), h.Reject)
+	leaves.POST("/:id/cancel", staff(), h.Cancel)
+	leaves.GET("/:id/substitutions", admins(), h.Substitutions)
+}
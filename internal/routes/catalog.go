@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/pkg/response"
+)
+
+// Route is one mounted method and path.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Inventory lists the features known to the application and the routes actually mounted.
+type Inventory struct {
+	Features []FeatureStatus `json:"features"`
+	Routes   []Route         `json:"routes"`
+}
+
+// Catalog serves the runtime route inventory.
+type Catalog struct {
+	routes   func() gin.RoutesInfo
+	features []FeatureStatus
+}
+
+// NewCatalog builds a Catalog over the routes reported by routes, typically (*gin.Engine).Routes.
+func NewCatalog(routes func() gin.RoutesInfo, features []FeatureStatus) *Catalog {
+	return &Catalog{routes: routes, features: features}
+}
+
+// Inventory returns the feature statuses and the mounted routes sorted by path and method.
+func (c *Catalog) Inventory() Inventory {
+	info := c.routes()
+	mounted := make([]Route, 0, len(info))
+	for _, route := range info {
+		mounted = append(mounted, Route{Method: route.Method, Path: route.Path})
+	}
+	sort.Slice(mounted, func(i, j int) bool {
+		if mounted[i].Path != mounted[j].Path {
+			return mounted[i].Path < mounted[j].Path
+		}
+		return mounted[i].Method < mounted[j].Method
+	})
+	return Inventory{Features: c.features, Routes: mounted}
+}
+
+// List godoc
+// @Summary List mounted routes
+// @Description Enumerate the features with their enabled state and every route mounted on the API router
+// @Tags Internal
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /internal/routes [get]
+func (c *Catalog) List(ctx *gin.Context) {
+	response.JSON(ctx, http.StatusOK, c.Inventory(), nil)
+}
@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/handler"
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// RegisterAuth mounts login, token and session management and the caller's permissions. authenticate guards the
+// routes that need a session.
+func RegisterAuth(rg *gin.RouterGroup, h *handler.AuthHandler, permissions *handler.PermissionHandler, authenticate gin.HandlerFunc) {
+	authRoutes := rg.Group("/auth")
+	authRoutes.POST("/login", h.Login)
+	authRoutes.POST("/refresh", h.Refresh)
+	authRoutes.POST("/forgot-password", h.ForgotPassword)
+	authRoutes.POST("/reset-password", h.ResetPassword)
+	protected := authRoutes.Group("", authenticate)
+	protected.POST("/logout", h.Logout)
+	protected.POST("/change-password", h.ChangePassword)
+	protected.GET("/sessions", h.Sessions)
+	protected.POST("/sessions/heartbeat", h.Heartbeat)
+	protected.GET("/me/permissions", permissions.Get)
+}
+
+// RegisterSearch mounts global search.
+func RegisterSearch(rg *gin.RouterGroup, h *handler.SearchHandler) {
+	rg.GET("/search", roles(models.RoleStudent, models.RoleTeacher, models.RoleAdmin, models.RoleSuperAdmin), h.Search)
+}
+
+// RegisterNotifications mounts the caller's in-app notifications and push device registration.
+func RegisterNotifications(rg *gin.RouterGroup, h *handler.NotificationHandler) {
+	rg.GET("/notifications", h.List)
+	rg.POST("/notifications/:id/read", h.MarkRead)
+	rg.POST("/notifications/devices", h.RegisterDevice)
+	rg.DELETE("/notifications/devices/:token", h.UnregisterDevice)
+}
+
+// RegisterAnnouncements mounts admin announcement broadcasts and read receipts. Any recipient may
+// acknowledge an announcement.
+func RegisterAnnouncements(rg *gin.RouterGroup, h *handler.AnnouncementHandler) {
+	rg.POST("/announcements/broadcast", admins(), h.Broadcast)
+	rg.POST("/announcements/:id/ack", h.Acknowledge)
+	rg.GET("/announcements/:id/acks", admins(), h.Acks)
+}
+
+// RegisterSecurity mounts the access denial dashboard.
+func RegisterSecurity(rg *gin.RouterGroup, h *handler.SecurityHandler) {
+	rg.GET("/analytics/security", admins(), h.Dashboard)
+}
+
+// RegisterAnalytics mounts the analytics datasets.
+func RegisterAnalytics(rg *gin.RouterGroup, h *handler.AnalyticsHandler) {
+	analytics := rg.Group("/analytics")
+	analytics.Use(middleware.WithResponseMeta())
+	analytics.GET("/attendance", h.Attendance)
+	analytics.GET("/grades", h.Grades)
+	analytics.GET("/behavior", h.Behavior)
+	analytics.GET("/system", h.System)
+}
+
+// RegisterConfiguration mounts runtime configuration management.
+func RegisterConfiguration(rg *gin.RouterGroup, h *handler.ConfigurationHandler) {
+	configuration := rg.Group("/configuration")
+	configuration.Use(admins())
+	configuration.GET("", h.List)
+	configuration.GET("/:key", h.Get)
+	configuration.PUT("/:key", h.Update)
+	configuration.PUT("/bulk", h.BulkUpdate)
+}
+
+// RegisterReports mounts report generation, export templates and signed downloads.
+func RegisterReports(rg *gin.RouterGroup, h *handler.ReportHandler, templates *handler.ExportTemplateHandler) {
+	reports := rg.Group("/reports")
+	reports.POST("/generate", staff(), h.GenerateReport)
+	reports.GET("/status/:id", staff(), h.ReportStatus)
+	if templates != nil {
+		reports.GET("/templates", staff(), templates.List)
+		reports.GET("/templates/columns", staff(), templates.Columns)
+		reports.GET("/templates/:id", staff(), templates.Get)
+		reports.POST("/templates", admins(), templates.Create)
+		reports.PUT("/templates/:id", admins(), templates.Update)
+		reports.DELETE("/templates/:id", admins(), templates.Delete)
+	}
+	rg.GET("/export/:token", h.DownloadReport)
+}
+
+// RegisterMutations mounts the student data change request workflow.
+func RegisterMutations(rg *gin.RouterGroup, h *handler.MutationHandler) {
+	mutations := rg.Group("/mutations")
+	mutations.POST("", admins(), h.Create)
+	mutations.GET("", staff(), h.List)
+	mutations.GET("/:id", staff(), h.Get)
+	mutations.POST("/:id/attachments", staff(), h.Attach)
+	mutations.POST("/:id/review", superAdmins(), h.Review)
+}
+
+// RegisterArchives mounts the document archive and, when retention is configured, its report.
+func RegisterArchives(rg *gin.RouterGroup, h *handler.ArchiveHandler, retention *handler.ArchiveRetentionHandler) {
+	archives := rg.Group("/archives")
+	archives.POST("", admins(), h.Upload)
+	archives.GET("", staff(), h.List)
+	archives.GET("/trash", superAdmins(), h.Trash)
+	archives.GET("/usage", admins(), h.Usage)
+	if retention != nil {
+		archives.GET("/retention", admins(), retention.Report)
+	}
+	archives.GET("/:id", staff(), h.Get)
+	archives.GET("/:id/download", staff(), h.Download)
+	archives.GET("/:id/preview", staff(), h.Preview)
+	archives.DELETE("/:id", superAdmins(), h.Delete)
+	archives.POST("/:id/restore", superAdmins(), h.Restore)
+}
+
+// RegisterRetention mounts the refresh token and audit log purge with its legal holds.
+func RegisterRetention(rg *gin.RouterGroup, h *handler.RetentionHandler) {
+	retention := rg.Group("/retention", superAdmins())
+	retention.POST("/purge", h.Purge)
+	retention.GET("/legal-holds", h.ListHolds)
+	retention.POST("/legal-holds", h.CreateHold)
+	retention.POST("/legal-holds/:id/release", h.ReleaseHold)
+}
+
+// RegisterDashboard mounts the admin and teacher dashboards.
+func RegisterDashboard(rg *gin.RouterGroup, h *handler.DashboardHandler) {
+	dashboard := rg.Group("")
+	dashboard.Use(middleware.WithResponseMeta())
+	dashboard.GET("/dashboard", admins(), h.Admin)
+	dashboard.GET("/dashboard/academics", staff(), h.Teacher)
+}
+
+// RegisterPprof mounts the runtime profiling endpoints.
+func RegisterPprof(r gin.IRouter) {
+	group := r.Group("/debug/pprof")
+	group.GET("/", gin.WrapF(pprof.Index))
+	group.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/profile", gin.WrapF(pprof.Profile))
+	group.POST("/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/trace", gin.WrapF(pprof.Trace))
+	group.GET("/allocs", gin.WrapH(pprof.Handler("allocs")))
+	group.GET("/block", gin.WrapH(pprof.Handler("block")))
+	group.GET("/goroutine", gin.WrapH(pprof.Handler("goroutine")))
+	group.GET("/heap", gin.WrapH(pprof.Handler("heap")))
+	group.GET("/mutex", gin.WrapH(pprof.Handler("mutex")))
+	group.GET("/threadcreate", gin.WrapH(pprof.Handler("threadcreate")))
+}
+
+// RegisterLogLevels mounts the runtime log level controls. r must be guarded like /metrics.
+func RegisterLogLevels(r gin.IRouter, h *handler.LogLevelHandler) {
+	group := r.Group("/internal/log-levels")
+	group.GET("", h.List)
+	group.PUT("", h.Update)
+	group.DELETE("/:module", h.Reset)
+}
+
+// RegisterQueues mounts the job queue report. r must be guarded like /metrics.
+func RegisterQueues(r gin.IRouter, h *handler.QueueHandler) {
+	r.GET("/internal/queues", h.List)
+}
+
+// RegisterBackups mounts the backup endpoints. rg must authenticate users.
+func RegisterBackups(rg *gin.RouterGroup, h *handler.BackupHandler) {
+	rg.POST("", superAdmins(), h.Trigger)
+	rg.GET("", superAdmins(), h.List)
+	rg.GET("/:id", superAdmins(), h.Get)
+}
+
+// RegisterLegacySync mounts the write-back endpoints of the legacy app. rg must authenticate the
+// legacy client, since these routes carry no user token.
+func RegisterLegacySync(rg *gin.RouterGroup, h *handler.LegacySyncHandler) {
+	rg.POST("/attendance", h.SyncAttendance)
+	rg.POST("/grades", h.SyncGrades)
+	rg.GET("/reconciliation", h.Reconciliation)
+}
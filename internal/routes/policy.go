@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+)
+
+// guardName and probeAuthName identify the RBAC guard and the stand-in authentication in a
+// route's handler chain.
+var (
+	guardName     = handlerName(auth.RequireRole())
+	probeAuthName = handlerName(probeAuthenticate)
+)
+
+// probeAuthenticate stands in for token authentication on the probe router.
+func probeAuthenticate(c *gin.Context) {
+	c.Next()
+}
+
+// Policies reads the RBAC guards off the route table. register mounts the same routes as the API
+// on a fresh router, with authenticate in place of the token check; each route then gets one probe
+// request that stops at its last guard, so no handler runs. Because the guards themselves answer
+// the probe, the result always matches what they enforce.
+func Policies(register func(r *gin.Engine, authenticate gin.HandlerFunc)) []models.RoutePolicy {
+	r := gin.New()
+	found := map[string]*models.RoutePolicy{}
+	r.Use(func(c *gin.Context) {
+		policy := &models.RoutePolicy{Method: c.Request.Method, Path: c.FullPath(), Guards: [][]string{}}
+		found[policy.Method+" "+policy.Path] = policy
+		probe := &auth.PolicyProbe{}
+		for _, name := range c.HandlerNames() {
+			switch name {
+			case guardName:
+				probe.Guards++
+			case probeAuthName:
+				policy.Authenticated = true
+			}
+		}
+		if probe.Guards == 0 {
+			c.Abort()
+			return
+		}
+		c.Set(auth.ContextPolicyProbeKey, probe)
+		c.Next()
+		policy.Guards = probe.Allowed
+	})
+	register(r, probeAuthenticate)
+
+	policies := make([]models.RoutePolicy, 0, len(r.Routes()))
+	for _, route := range r.Routes() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(route.Method, probePath(route.Path), nil))
+		if policy, ok := found[route.Method+" "+route.Path]; ok {
+			policies = append(policies, *policy)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Path != policies[j].Path {
+			return policies[i].Path < policies[j].Path
+		}
+		return policies[i].Method < policies[j].Method
+	})
+	return policies
+}
+
+// probePath fills the parameters of a route pattern so a request matches it.
+func probePath(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "probe"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// handlerName names a handler the way gin does in (*gin.Context).HandlerNames.
+func handlerName(h gin.HandlerFunc) string {
+	return runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/handler"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// RegisterGuardians mounts guardian-student link management and the guardian portal.
+func RegisterGuardians(rg *gin.RouterGroup, h *handler.GuardianHandler) {
+	links := rg.Group("/guardians/:id/students")
+	links.GET("", selfOrAdmins(), h.ListLinks)
+	links.POST("", admins(), h.Link)
+	links.DELETE("/:studentId", admins(), h.Unlink)
+
+	// Guardians keep their own phone up to date and may opt out of absence messages.
+	contact := rg.Group("/guardians/:id/contact")
+	contact.Use(selfOrAdmins())
+	contact.GET("", h.Contact)
+	contact.PUT("", h.SetContact)
+	contact.DELETE("", h.DeleteContact)
+
+	// Guardian portal routes resolve the guardian from the token; the service checks every studentId
+	// against the guardian's links.
+	portal := rg.Group("/guardian")
+	portal.Use(roles(models.RoleGuardian))
+	portal.GET("/students", h.MyStudents)
+	portal.GET("/students/:studentId/attendance", h.StudentAttendance)
+	portal.GET("/students/:studentId/report-card", h.ReportCard)
+	portal.GET("/announcements", h.Announcements)
+	portal.GET("/calendar", h.Calendar)
+}
+
+// RegisterStudentPortal mounts student account provisioning and the student portal.
+func RegisterStudentPortal(rg *gin.RouterGroup, h *handler.StudentPortalHandler) {
+	rg.POST("/students/:id/account", admins(), h.ProvisionAccount)
+
+	// Student portal routes resolve the student from the token, never from the request.
+	portal := rg.Group("/student")
+	portal.Use(roles(models.RoleStudent))
+	portal.GET("/schedule", h.Schedule)
+	portal.GET("/attendance", h.Attendance)
+	portal.GET("/report-card", h.ReportCard)
+}
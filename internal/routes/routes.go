@@ -0,0 +1,77 @@
+// Package routes holds the route table, one Register function per feature. The application decides
+// which features are enabled and mounts them with Mount, which also records what was registered for
+// the /internal/routes inventory.
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/noah-isme/sma-adp-api/internal/middleware"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
+)
+
+// Feature is a group of routes switched on or off together.
+type Feature struct {
+	Name    string
+	Enabled bool
+	// Register mounts the feature's routes. It is only called when Enabled is true.
+	Register func()
+}
+
+// FeatureStatus reports whether a feature's routes were mounted.
+type FeatureStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Mount registers every enabled feature in order and returns the status of each.
+func Mount(features ...Feature) []FeatureStatus {
+	statuses := make([]FeatureStatus, 0, len(features))
+	for _, feature := range features {
+		if feature.Enabled && feature.Register != nil {
+			feature.Register()
+		}
+		statuses = append(statuses, FeatureStatus{Name: feature.Name, Enabled: feature.Enabled})
+	}
+	return statuses
+}
+
+func roles(allowed ...models.UserRole) gin.HandlerFunc {
+	names := make([]string, len(allowed))
+	for i, role := range allowed {
+		names[i] = string(role)
+	}
+	return middleware.RBAC(names...)
+}
+
+// superAdmins admits super admins only.
+func superAdmins() gin.HandlerFunc {
+	return roles(models.RoleSuperAdmin)
+}
+
+// admins admits admins and super admins.
+func admins() gin.HandlerFunc {
+	return roles(models.RoleAdmin, models.RoleSuperAdmin)
+}
+
+// staff admits teachers and administrators.
+func staff() gin.HandlerFunc {
+	return roles(models.RoleTeacher, models.RoleAdmin, models.RoleSuperAdmin)
+}
+
+// selfOrAdmins admits administrators and the user the request is about: the user named by :id, or
+// the one every rule of chain resolves to.
+func selfOrAdmins(chain ...auth.OwnershipRule) gin.HandlerFunc {
+	if len(chain) == 0 {
+		return middleware.RBAC(auth.Self, string(models.RoleAdmin), string(models.RoleSuperAdmin))
+	}
+	return auth.RequireOwnership(chain, auth.Self, string(models.RoleAdmin), string(models.RoleSuperAdmin))
+}
+
+// Owners finds who owns the records nested routes address, so their SELF guards admit callers for
+// their own records only.
+type Owners struct {
+	// TeacherAssignment returns the teacher of an assignment.
+	TeacherAssignment auth.OwnerLookup
+}
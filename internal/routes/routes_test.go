@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestMountSkipsDisabledFeatures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rg := r.Group("/api")
+	noop := func(c *gin.Context) {}
+
+	statuses := Mount(
+		Feature{Name: "reports", Enabled: true, Register: func() { rg.GET("/reports", noop) }},
+		Feature{Name: "archives", Enabled: false, Register: func() { rg.GET("/archives", noop) }},
+	)
+
+	assert.Equal(t, []FeatureStatus{{Name: "reports", Enabled: true}, {Name: "archives", Enabled: false}}, statuses)
+	require.Len(t, r.Routes(), 1)
+	assert.Equal(t, "/api/reports", r.Routes()[0].Path)
+}
+
+func TestCatalogListsMountedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	noop := func(c *gin.Context) {}
+	r.POST("/b", noop)
+	r.GET("/b", noop)
+	r.GET("/a", noop)
+	r.GET("/internal/routes", NewCatalog(r.Routes, []FeatureStatus{{Name: "a", Enabled: true}}).List)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/routes", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data Inventory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []FeatureStatus{{Name: "a", Enabled: true}}, body.Data.Features)
+	assert.Equal(t, []Route{
+		{Method: http.MethodGet, Path: "/a"},
+		{Method: http.MethodGet, Path: "/b"},
+		{Method: http.MethodPost, Path: "/b"},
+		{Method: http.MethodGet, Path: "/internal/routes"},
+	}, body.Data.Routes)
+}
+
+func TestPoliciesReadGuardsWithoutRunningHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := func(c *gin.Context) { t.Errorf("handler of %s ran during the probe", c.FullPath()) }
+
+	policies := Policies(func(r *gin.Engine, authenticate gin.HandlerFunc) {
+		api := r.Group("/api")
+		api.POST("/auth/login", handler)
+		secured := api.Group("", authenticate)
+		secured.GET("/notifications", handler)
+		secured.GET("/reports/:id", staff(), handler)
+		contact := secured.Group("/guardians/:id/contact")
+		contact.Use(selfOrAdmins())
+		contact.PUT("", handler)
+		portal := secured.Group("/student")
+		portal.Use(staff())
+		portal.GET("/files/*path", admins(), handler)
+	})
+
+	assert.Equal(t, []models.RoutePolicy{
+		{Method: http.MethodPost, Path: "/api/auth/login", Guards: [][]string{}},
+		{Method: http.MethodPut, Path: "/api/guardians/:id/contact", Authenticated: true, Guards: [][]string{{"SELF", "ADMIN", "SUPERADMIN"}}},
+		{Method: http.MethodGet, Path: "/api/notifications", Authenticated: true, Guards: [][]string{}},
+		{Method: http.MethodGet, Path: "/api/reports/:id", Authenticated: true, Guards: [][]string{{"TEACHER", "ADMIN", "SUPERADMIN"}}},
+		{Method: http.MethodGet, Path: "/api/student/files/*path", Authenticated: true, Guards: [][]string{{"TEACHER", "ADMIN", "SUPERADMIN"}, {"ADMIN", "SUPERADMIN"}}},
+	}, policies)
+}
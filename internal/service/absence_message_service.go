@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/messaging"
+)
+
+// absenceMessageBody is the SMS sent to guardians; WhatsApp templates receive the same two values.
+const absenceMessageBody = "Yth. Bapak/Ibu, ananda %s tercatat tidak hadir tanpa keterangan (alpa) pada %s. Mohon hubungi wali kelas bila ada kekeliruan."
+
+type absenceMessageStore interface {
+	Recipients(ctx context.Context, enrollmentIDs []string) ([]models.AbsenceRecipient, error)
+	Enqueue(ctx context.Context, messages []models.AbsenceMessage) (int, error)
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.AbsenceMessage, error)
+	MarkSent(ctx context.Context, id, providerMessageID string, sentAt time.Time) error
+	MarkSkipped(ctx context.Context, id, reason string, at time.Time) error
+	MarkFailed(ctx context.Context, id, reason string, retryAt *time.Time, at time.Time) error
+	ApplyStatus(ctx context.Context, providerMessageID string, status models.AbsenceMessageStatus, reason *string, at time.Time) (bool, error)
+	List(ctx context.Context, filter models.AbsenceMessageFilter) ([]models.AbsenceMessage, int, error)
+}
+
+type absenceMessageSender interface {
+	Send(ctx context.Context, msg messaging.Message) (string, error)
+}
+
+// AbsenceMessageConfig holds the notification rules and delivery tuning.
+type AbsenceMessageConfig struct {
+	// QuietHoursStart and QuietHoursEnd ("HH:MM" in Location) hold messages that would go out in
+	// between until the quiet hours end. Equal or empty values disable quiet hours.
+	QuietHoursStart string
+	QuietHoursEnd   string
+	Location        *time.Location
+	PollInterval    time.Duration
+	BatchSize       int
+	SendTimeout     time.Duration
+	MaxAttempts     int
+	// RetryBackoff is the delay before the first retry; it doubles with every attempt.
+	RetryBackoff time.Duration
+}
+
+// AbsenceMessageServiceParams groups constructor dependencies.
+type AbsenceMessageServiceParams struct {
+	Store  absenceMessageStore
+	Sender absenceMessageSender
+	Logger *zap.Logger
+	Config AbsenceMessageConfig
+}
+
+// AbsenceMessageService messages guardians by SMS or WhatsApp when their child is marked absent.
+// Only the first absence of a student's day is messaged, only for today, and never during quiet
+// hours. Messages are queued when the absence is stored and sent by a background dispatcher, which
+// skips absences corrected in the meantime.
+type AbsenceMessageService struct {
+	store      absenceMessageStore
+	sender     absenceMessageSender
+	logger     *zap.Logger
+	cfg        AbsenceMessageConfig
+	quietStart time.Duration
+	quietEnd   time.Duration
+	lease      time.Duration
+	now        func() time.Time
+}
+
+// NewAbsenceMessageService validates cfg and constructs the service.
+func NewAbsenceMessageService(params AbsenceMessageServiceParams) (*AbsenceMessageService, error) {
+	cfg := params.Config
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Minute
+	}
+	svc := &AbsenceMessageService{store: params.Store, sender: params.Sender, logger: params.Logger, cfg: cfg, now: time.Now}
+	if svc.logger == nil {
+		svc.logger = zap.NewNop()
+	}
+	if cfg.QuietHoursStart != "" || cfg.QuietHoursEnd != "" {
+		var err error
+		if svc.quietStart, err = parseClock(cfg.QuietHoursStart); err != nil {
+			return nil, fmt.Errorf("quiet hours start must use HH:MM: %w", err)
+		}
+		if svc.quietEnd, err = parseClock(cfg.QuietHoursEnd); err != nil {
+			return nil, fmt.Errorf("quiet hours end must use HH:MM: %w", err)
+		}
+	}
+	// Messages are sent one after another, so a batch is leased for every send timing out plus one
+	// timeout of slack.
+	svc.lease = time.Duration(cfg.BatchSize+1) * cfg.SendTimeout
+	return svc, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// QueueAbsences queues messages to the guardians of every record marked absent today. It runs after
+// the attendance is stored and never undoes it: callers log failures.
+func (s *AbsenceMessageService) QueueAbsences(ctx context.Context, records ...models.DailyAttendance) error {
+	if database.IsDryRun(ctx) {
+		return nil
+	}
+	now := s.now()
+	today := now.In(s.cfg.Location).Format("2006-01-02")
+	absences := make(map[string]models.DailyAttendance)
+	enrollmentIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		// Backfilled and corrected past days are not news to guardians any more.
+		if record.Status != models.AttendanceStatusAbsent || record.Date.Format("2006-01-02") != today {
+			continue
+		}
+		if _, ok := absences[record.EnrollmentID]; !ok {
+			enrollmentIDs = append(enrollmentIDs, record.EnrollmentID)
+		}
+		absences[record.EnrollmentID] = record
+	}
+	if len(enrollmentIDs) == 0 {
+		return nil
+	}
+	recipients, err := s.store.Recipients(ctx, enrollmentIDs)
+	if err != nil {
+		return err
+	}
+	sendAfter := s.afterQuietHours(now).UTC()
+	messages := make([]models.AbsenceMessage, 0, len(recipients))
+	for _, recipient := range recipients {
+		record := absences[recipient.EnrollmentID]
+		messages = append(messages, models.AbsenceMessage{
+			AttendanceID: record.ID,
+			StudentID:    recipient.StudentID,
+			GuardianID:   recipient.GuardianID,
+			Date:         record.Date,
+			Channel:      recipient.Channel,
+			Phone:        recipient.Phone,
+			Body:         fmt.Sprintf(absenceMessageBody, recipient.StudentName, record.Date.Format("02-01-2006")),
+			SendAfter:    sendAfter,
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	_, err = s.store.Enqueue(ctx, messages)
+	return err
+}
+
+// afterQuietHours returns t, or the end of the quiet hours when t falls inside them.
+func (s *AbsenceMessageService) afterQuietHours(t time.Time) time.Time {
+	if s.quietStart == s.quietEnd {
+		return t
+	}
+	local := t.In(s.cfg.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.cfg.Location)
+	clock := local.Sub(midnight)
+	if s.quietStart < s.quietEnd {
+		if clock >= s.quietStart && clock < s.quietEnd {
+			return midnight.Add(s.quietEnd)
+		}
+		return t
+	}
+	// Quiet hours span midnight, e.g. 21:00-06:00.
+	switch {
+	case clock >= s.quietStart:
+		return midnight.AddDate(0, 0, 1).Add(s.quietEnd)
+	case clock < s.quietEnd:
+		return midnight.Add(s.quietEnd)
+	default:
+		return t
+	}
+}
+
+// Start sends due messages every poll interval until ctx is done.
+func (s *AbsenceMessageService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *AbsenceMessageService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				claimed, err := s.Dispatch(ctx)
+				if err != nil {
+					s.logger.Warn("absence message dispatch failed", zap.Error(err))
+					break
+				}
+				if claimed < s.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Dispatch claims one batch of due messages, sends them and returns how many were claimed.
+func (s *AbsenceMessageService) Dispatch(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	messages, err := s.store.ClaimDue(ctx, now, now.Add(s.lease), s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range messages {
+		if err := s.deliver(ctx, &messages[i], now); err != nil {
+			s.logger.Warn("failed to record absence message outcome", zap.String("message_id", messages[i].ID), zap.Error(err))
+		}
+	}
+	return len(messages), nil
+}
+
+// deliver sends one claimed message and records the outcome. A message whose outcome cannot be
+// recorded becomes due again when its lease ends.
+func (s *AbsenceMessageService) deliver(ctx context.Context, message *models.AbsenceMessage, now time.Time) error {
+	if message.AttendanceStatus == nil || *message.AttendanceStatus != models.AttendanceStatusAbsent {
+		return s.store.MarkSkipped(ctx, message.ID, "absence corrected before sending", now)
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, s.cfg.SendTimeout)
+	providerID, err := s.sender.Send(sendCtx, messaging.Message{
+		To:             message.Phone,
+		Channel:        messaging.Channel(message.Channel),
+		Body:           message.Body,
+		TemplateParams: []string{message.StudentName, message.Date.Format("02-01-2006")},
+	})
+	cancel()
+	switch {
+	case err == nil:
+		return s.store.MarkSent(ctx, message.ID, providerID, now)
+	case errors.Is(err, messaging.ErrInvalidRecipient), message.Attempts >= s.cfg.MaxAttempts:
+		return s.store.MarkFailed(ctx, message.ID, err.Error(), nil, now)
+	default:
+		backoff := s.cfg.RetryBackoff
+		for i := 1; i < message.Attempts; i++ {
+			backoff *= 2
+		}
+		retryAt := s.afterQuietHours(now.Add(backoff)).UTC()
+		return s.store.MarkFailed(ctx, message.ID, err.Error(), &retryAt, now)
+	}
+}
+
+// ApplyStatuses records the delivery statuses posted by a provider. Updates for unknown messages,
+// e.g. ones sent before a restore, are ignored.
+func (s *AbsenceMessageService) ApplyStatuses(ctx context.Context, updates []messaging.StatusUpdate) error {
+	now := s.now().UTC()
+	for _, update := range updates {
+		var status models.AbsenceMessageStatus
+		switch update.Status {
+		case messaging.StatusDelivered:
+			status = models.AbsenceMessageDelivered
+		case messaging.StatusFailed:
+			status = models.AbsenceMessageFailed
+		default:
+			continue
+		}
+		if _, err := s.store.ApplyStatus(ctx, update.MessageID, status, optionalString(update.Error), now); err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record message status")
+		}
+	}
+	return nil
+}
+
+// List returns the delivery log.
+func (s *AbsenceMessageService) List(ctx context.Context, filter models.AbsenceMessageFilter) ([]models.AbsenceMessage, *models.Pagination, error) {
+	if filter.Status != "" {
+		filter.Status = models.AbsenceMessageStatus(strings.ToUpper(string(filter.Status)))
+		switch filter.Status {
+		case models.AbsenceMessagePending, models.AbsenceMessageSent, models.AbsenceMessageDelivered, models.AbsenceMessageFailed, models.AbsenceMessageSkipped:
+		default:
+			return nil, nil, appErrors.Clone(appErrors.ErrValidation, "invalid message status")
+		}
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+	messages, total, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list absence messages")
+	}
+	if messages == nil {
+		messages = []models.AbsenceMessage{}
+	}
+	return messages, &models.Pagination{Page: filter.Page, PageSize: filter.PageSize, TotalCount: total}, nil
+}
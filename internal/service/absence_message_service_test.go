@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/messaging"
+)
+
+type absenceStoreStub struct {
+	recipients []models.AbsenceRecipient
+	lookedUp   []string
+	queued     []models.AbsenceMessage
+	due        []models.AbsenceMessage
+	sent       map[string]string
+	skipped    []string
+	failed     map[string]*time.Time
+	applied    map[string]models.AbsenceMessageStatus
+}
+
+func (s *absenceStoreStub) Recipients(ctx context.Context, enrollmentIDs []string) ([]models.AbsenceRecipient, error) {
+	s.lookedUp = enrollmentIDs
+	return s.recipients, nil
+}
+
+func (s *absenceStoreStub) Enqueue(ctx context.Context, messages []models.AbsenceMessage) (int, error) {
+	s.queued = append(s.queued, messages...)
+	return len(messages), nil
+}
+
+func (s *absenceStoreStub) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.AbsenceMessage, error) {
+	return s.due, nil
+}
+
+func (s *absenceStoreStub) MarkSent(ctx context.Context, id, providerMessageID string, sentAt time.Time) error {
+	if s.sent == nil {
+		s.sent = make(map[string]string)
+	}
+	s.sent[id] = providerMessageID
+	return nil
+}
+
+func (s *absenceStoreStub) MarkSkipped(ctx context.Context, id, reason string, at time.Time) error {
+	s.skipped = append(s.skipped, id)
+	return nil
+}
+
+func (s *absenceStoreStub) MarkFailed(ctx context.Context, id, reason string, retryAt *time.Time, at time.Time) error {
+	if s.failed == nil {
+		s.failed = make(map[string]*time.Time)
+	}
+	s.failed[id] = retryAt
+	return nil
+}
+
+func (s *absenceStoreStub) ApplyStatus(ctx context.Context, providerMessageID string, status models.AbsenceMessageStatus, reason *string, at time.Time) (bool, error) {
+	if s.applied == nil {
+		s.applied = make(map[string]models.AbsenceMessageStatus)
+	}
+	s.applied[providerMessageID] = status
+	return true, nil
+}
+
+func (s *absenceStoreStub) List(ctx context.Context, filter models.AbsenceMessageFilter) ([]models.AbsenceMessage, int, error) {
+	return nil, 0, nil
+}
+
+// absenceSenderStub rejects "+620*" numbers as invalid and "+621*" numbers as unavailable.
+type absenceSenderStub struct {
+	sent []messaging.Message
+}
+
+func (s *absenceSenderStub) Send(ctx context.Context, msg messaging.Message) (string, error) {
+	switch msg.To[:5] {
+	case "+6200":
+		return "", fmt.Errorf("%w: twilio error 21211", messaging.ErrInvalidRecipient)
+	case "+6211":
+		return "", errors.New("twilio returned 503")
+	}
+	s.sent = append(s.sent, msg)
+	return "SM" + msg.To[len(msg.To)-1:], nil
+}
+
+func newAbsenceMessageServiceForTest(t *testing.T, store *absenceStoreStub, sender *absenceSenderStub, now time.Time) *AbsenceMessageService {
+	t.Helper()
+	jakarta := time.FixedZone("WIB", 7*3600)
+	svc, err := NewAbsenceMessageService(AbsenceMessageServiceParams{
+		Store:  store,
+		Sender: sender,
+		Config: AbsenceMessageConfig{QuietHoursStart: "21:00", QuietHoursEnd: "06:00", Location: jakarta, MaxAttempts: 3, RetryBackoff: time.Minute},
+	})
+	require.NoError(t, err)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestAbsenceMessageServiceQueueAbsences(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	today := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	store := &absenceStoreStub{recipients: []models.AbsenceRecipient{
+		{EnrollmentID: "enr-1", StudentID: "student-1", StudentName: "Ani", GuardianID: "guardian-1", Phone: "+6281200000001", Channel: models.MessageChannelSMS},
+		{EnrollmentID: "enr-1", StudentID: "student-1", StudentName: "Ani", GuardianID: "guardian-2", Phone: "+6281200000002", Channel: models.MessageChannelWhatsApp},
+	}}
+	// 07:30 WIB is outside quiet hours: messages are due at once.
+	morning := time.Date(2024, 7, 15, 7, 30, 0, 0, jakarta)
+	svc := newAbsenceMessageServiceForTest(t, store, nil, morning)
+
+	require.NoError(t, svc.QueueAbsences(context.Background(),
+		models.DailyAttendance{ID: "att-1", EnrollmentID: "enr-1", Date: today, Status: models.AttendanceStatusAbsent},
+		models.DailyAttendance{ID: "att-2", EnrollmentID: "enr-2", Date: today, Status: models.AttendanceStatusSick},
+		models.DailyAttendance{ID: "att-3", EnrollmentID: "enr-3", Date: today.AddDate(0, 0, -3), Status: models.AttendanceStatusAbsent},
+	))
+	assert.Equal(t, []string{"enr-1"}, store.lookedUp)
+	require.Len(t, store.queued, 2)
+	assert.Equal(t, "att-1", store.queued[0].AttendanceID)
+	assert.Equal(t, "guardian-2", store.queued[1].GuardianID)
+	assert.Equal(t, models.MessageChannelWhatsApp, store.queued[1].Channel)
+	assert.Equal(t, morning.UTC(), store.queued[0].SendAfter)
+	assert.Contains(t, store.queued[0].Body, "ananda Ani tercatat tidak hadir")
+	assert.Contains(t, store.queued[0].Body, "15-07-2024")
+
+	// Marked at 22:00 WIB, the message waits for 06:00 WIB the next morning.
+	store.queued = nil
+	svc.now = func() time.Time { return time.Date(2024, 7, 15, 22, 0, 0, 0, jakarta) }
+	require.NoError(t, svc.QueueAbsences(context.Background(), models.DailyAttendance{ID: "att-1", EnrollmentID: "enr-1", Date: today, Status: models.AttendanceStatusAbsent}))
+	require.Len(t, store.queued, 2)
+	assert.Equal(t, time.Date(2024, 7, 16, 6, 0, 0, 0, jakarta).UTC(), store.queued[0].SendAfter)
+}
+
+func TestAbsenceMessageServiceDispatch(t *testing.T) {
+	now := time.Date(2024, 7, 15, 1, 0, 0, 0, time.UTC) // 08:00 WIB
+	absent, present := models.AttendanceStatusAbsent, models.AttendanceStatusPresent
+	date := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	store := &absenceStoreStub{due: []models.AbsenceMessage{
+		{ID: "m-ok", Phone: "+6281200000001", Channel: models.MessageChannelSMS, StudentName: "Ani", Date: date, Attempts: 1, AttendanceStatus: &absent},
+		{ID: "m-corrected", Phone: "+6281200000002", Channel: models.MessageChannelSMS, Date: date, Attempts: 1, AttendanceStatus: &present},
+		{ID: "m-invalid", Phone: "+6200", Channel: models.MessageChannelSMS, Date: date, Attempts: 1, AttendanceStatus: &absent},
+		{ID: "m-retry", Phone: "+6211", Channel: models.MessageChannelWhatsApp, Date: date, Attempts: 2, AttendanceStatus: &absent},
+		{ID: "m-giveup", Phone: "+6211", Channel: models.MessageChannelWhatsApp, Date: date, Attempts: 3, AttendanceStatus: &absent},
+	}}
+	sender := &absenceSenderStub{}
+	svc := newAbsenceMessageServiceForTest(t, store, sender, now)
+
+	claimed, err := svc.Dispatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, claimed)
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, []string{"Ani", "15-07-2024"}, sender.sent[0].TemplateParams)
+	assert.Equal(t, map[string]string{"m-ok": "SM1"}, store.sent)
+	assert.Equal(t, []string{"m-corrected"}, store.skipped)
+	assert.Nil(t, store.failed["m-invalid"])
+	require.NotNil(t, store.failed["m-retry"])
+	assert.Equal(t, now.Add(2*time.Minute), *store.failed["m-retry"])
+	assert.Contains(t, store.failed, "m-giveup")
+	assert.Nil(t, store.failed["m-giveup"])
+
+	require.NoError(t, svc.ApplyStatuses(context.Background(), []messaging.StatusUpdate{
+		{MessageID: "SM1", Status: messaging.StatusDelivered},
+		{MessageID: "SM2", Status: messaging.StatusSent},
+		{MessageID: "SM3", Status: messaging.StatusFailed, Error: "twilio error 30003"},
+	}))
+	assert.Equal(t, map[string]models.AbsenceMessageStatus{"SM1": models.AbsenceMessageDelivered, "SM3": models.AbsenceMessageFailed}, store.applied)
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// defaultAlertRules are what dashboards evaluate when alert rules are disabled. Migration 000054
+// seeds the same rules, so enabling the subsystem starts from the same alerts.
+var defaultAlertRules = []models.AlertRule{
+	{
+		ID: "default-class-attendance", Name: "Low class attendance", Metric: models.AlertMetricClassAttendanceRate,
+		Comparator: models.AlertComparatorLT, Threshold: 90, Audience: models.AlertAudienceTeachers,
+		Channel: models.AlertChannelDashboard, Enabled: true,
+	},
+	{
+		ID: "default-class-grade", Name: "Low class average grade", Metric: models.AlertMetricClassAverageGrade,
+		Comparator: models.AlertComparatorLT, Threshold: 70, Audience: models.AlertAudienceTeachers,
+		Channel: models.AlertChannelDashboard, Enabled: true,
+	},
+}
+
+// teacherAlertAudiences are the rule audiences shown to teachers; ADMINS rules stay on admin views.
+var teacherAlertAudiences = []models.AlertAudience{models.AlertAudienceHomeroom, models.AlertAudienceTeachers}
+
+var alertMetricLabels = map[models.AlertMetric]string{
+	models.AlertMetricClassAttendanceRate: "attendance rate",
+	models.AlertMetricClassAverageGrade:   "average grade",
+}
+
+var alertComparatorLabels = map[models.AlertComparator]string{
+	models.AlertComparatorLT:  "below",
+	models.AlertComparatorLTE: "at or below",
+	models.AlertComparatorGT:  "above",
+	models.AlertComparatorGTE: "at or above",
+}
+
+type alertRuleStore interface {
+	ListRules(ctx context.Context, includeDisabled bool) ([]models.AlertRule, error)
+	FindRule(ctx context.Context, id string) (*models.AlertRule, error)
+	CreateRule(ctx context.Context, rule *models.AlertRule) error
+	UpdateRule(ctx context.Context, rule *models.AlertRule) error
+	DeleteRule(ctx context.Context, id string) error
+	ReplaceBreaches(ctx context.Context, termID string, breaches []models.AlertRuleBreach, now time.Time) ([]models.AlertRuleBreach, error)
+	ListBreaches(ctx context.Context, filter models.AlertRuleBreachFilter) ([]models.AlertRuleBreach, error)
+}
+
+type alertRuleNotifier interface {
+	CreateOnce(ctx context.Context, notification *models.Notification) (bool, error)
+	CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error)
+}
+
+type alertAssignmentReader interface {
+	ListByTerm(ctx context.Context, termID string) ([]models.TeacherAssignment, error)
+}
+
+// AlertRuleServiceParams groups constructor dependencies.
+type AlertRuleServiceParams struct {
+	Store     alertRuleStore
+	Terms     ports.TermResolver
+	Analytics ports.AnalyticsRepository
+	Classes   ports.ClassReader
+	// Notifications, Homerooms and Assignments are optional; without them breaches are stored but
+	// the audience is not notified.
+	Notifications alertRuleNotifier
+	Homerooms     homeroomLister
+	Assignments   alertAssignmentReader
+	Validator     *validator.Validate
+	Logger        *zap.Logger
+	// Interval is how often the rules are evaluated; it defaults to one hour.
+	Interval time.Duration
+}
+
+// AlertRuleService manages alert rules and evaluates them against the active term's class
+// attendance and grades. Breaches are stored for dashboards, and rules on the NOTIFICATION channel
+// notify their audience when a class first breaches them.
+type AlertRuleService struct {
+	store         alertRuleStore
+	terms         ports.TermResolver
+	analytics     ports.AnalyticsRepository
+	classes       ports.ClassReader
+	notifications alertRuleNotifier
+	homerooms     homeroomLister
+	assignments   alertAssignmentReader
+	validator     *validator.Validate
+	logger        *zap.Logger
+	interval      time.Duration
+	now           func() time.Time
+}
+
+// NewAlertRuleService constructs the service.
+func NewAlertRuleService(params AlertRuleServiceParams) *AlertRuleService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	interval := params.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &AlertRuleService{
+		store:         params.Store,
+		terms:         params.Terms,
+		analytics:     params.Analytics,
+		classes:       params.Classes,
+		notifications: params.Notifications,
+		homerooms:     params.Homerooms,
+		assignments:   params.Assignments,
+		validator:     validate,
+		logger:        logger,
+		interval:      interval,
+		now:           time.Now,
+	}
+}
+
+// ListRules returns the enabled rules, and disabled ones when asked.
+func (s *AlertRuleService) ListRules(ctx context.Context, query dto.AlertRuleQuery) ([]models.AlertRule, error) {
+	rules, err := s.store.ListRules(ctx, query.IncludeDisabled)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list alert rules")
+	}
+	return rules, nil
+}
+
+// CreateRule adds a rule. It is evaluated from the next run on.
+func (s *AlertRuleService) CreateRule(ctx context.Context, req dto.AlertRuleRequest, actor *models.JWTClaims) (*models.AlertRule, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	rule, err := s.ruleFromRequest(req, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateRule(ctx, rule); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create alert rule")
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces a rule. Its open breaches keep their old threshold until the next run.
+func (s *AlertRuleService) UpdateRule(ctx context.Context, id string, req dto.AlertRuleRequest, actor *models.JWTClaims) (*models.AlertRule, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	rule, err := s.ruleFromRequest(req, actor)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	if err := s.store.UpdateRule(ctx, rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "alert rule not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update alert rule")
+	}
+	updated, err := s.store.FindRule(ctx, id)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load alert rule")
+	}
+	return updated, nil
+}
+
+// DeleteRule removes a rule and its open breaches.
+func (s *AlertRuleService) DeleteRule(ctx context.Context, id string) error {
+	if err := s.store.DeleteRule(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "alert rule not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete alert rule")
+	}
+	return nil
+}
+
+// Breaches lists the open breaches of enabled rules. Teachers only see classes they teach and rules
+// addressed to teachers.
+func (s *AlertRuleService) Breaches(ctx context.Context, query dto.AlertRuleBreachQuery, claims *models.JWTClaims) ([]models.AlertRuleBreach, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	termID, err := s.resolveTerm(ctx, query.TermID)
+	if err != nil {
+		return nil, err
+	}
+	filter := models.AlertRuleBreachFilter{TermID: termID}
+	if query.ClassID != "" {
+		filter.ClassIDs = []string{query.ClassID}
+	}
+	if claims.Role == models.RoleTeacher {
+		filter.TeacherID = claims.UserID
+		filter.Audiences = teacherAlertAudiences
+	}
+	breaches, err := s.store.ListBreaches(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list alert rule breaches")
+	}
+	if breaches == nil {
+		breaches = []models.AlertRuleBreach{}
+	}
+	return breaches, nil
+}
+
+// Start evaluates the rules now and then every interval until ctx is cancelled.
+func (s *AlertRuleService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Evaluate(ctx); err != nil {
+				s.logger.Warn("alert rule evaluation failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Evaluate compares every class of the active term with the enabled rules, stores the breaches and
+// notifies the audience of NOTIFICATION rules about classes that newly breached them.
+func (s *AlertRuleService) Evaluate(ctx context.Context) (*dto.AlertRuleRun, error) {
+	termID, err := s.resolveTerm(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.store.ListRules(ctx, false)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list alert rules")
+	}
+	attendance, err := s.analytics.AttendanceSummary(ctx, models.AnalyticsAttendanceFilter{TermID: termID})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class attendance")
+	}
+	grades, err := s.analytics.GradeSummary(ctx, models.AnalyticsGradeFilter{TermID: termID})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class grades")
+	}
+	values := classMetricValues(attendance, grades)
+	breaches := evaluateAlertRules(rules, values)
+
+	now := s.now().UTC()
+	created, err := s.store.ReplaceBreaches(ctx, termID, breaches, now)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store alert rule breaches")
+	}
+	classes := make(map[string]struct{})
+	for _, byClass := range values {
+		for classID := range byClass {
+			classes[classID] = struct{}{}
+		}
+	}
+	run := &dto.AlertRuleRun{TermID: termID, Rules: len(rules), Classes: len(classes), Open: len(breaches), New: len(created)}
+	run.Notified = s.notify(ctx, termID, rules, created, now)
+	s.logger.Info("alert rules evaluated",
+		zap.String("term_id", termID),
+		zap.Int("open", run.Open),
+		zap.Int("new", run.New),
+		zap.Int("notified", run.Notified))
+	return run, nil
+}
+
+// notify writes one notification per recipient of every new breach of a NOTIFICATION rule and
+// returns how many were written.
+func (s *AlertRuleService) notify(ctx context.Context, termID string, rules []models.AlertRule, created []models.AlertRuleBreach, now time.Time) int {
+	if len(created) == 0 || s.notifications == nil {
+		return 0
+	}
+	byID := make(map[string]models.AlertRule, len(rules))
+	for _, rule := range rules {
+		byID[rule.ID] = rule
+	}
+	recipients := &alertRecipients{svc: s, termID: termID}
+	names := newScheduleNameCache(nil, nil, s.classes)
+	day := now.Format("2006-01-02")
+	sent := 0
+	for _, breach := range created {
+		rule, ok := byID[breach.RuleID]
+		if !ok || rule.Channel != models.AlertChannelNotification {
+			continue
+		}
+		key := fmt.Sprintf("alert-rule:%s:%s:%s:%s", rule.ID, termID, breach.ClassID, day)
+		ref := breach.ClassID
+		notification := models.Notification{
+			Type:  models.NotificationTypeAlertRule,
+			Title: rule.Name,
+			Body: fmt.Sprintf("The %s of %s is %.2f, %s the threshold of %.2f.",
+				alertMetricLabels[rule.Metric], names.class(ctx, breach.ClassID), breach.Value,
+				alertComparatorLabels[rule.Comparator], breach.Threshold),
+			RefID:     &ref,
+			DedupeKey: &key,
+		}
+		if rule.Audience == models.AlertAudienceAdmins {
+			written, err := s.notifications.CreateForRoles(ctx, []models.UserRole{models.RoleAdmin, models.RoleSuperAdmin}, notification)
+			if err != nil {
+				s.logger.Warn("alert rule notification failed", zap.String("rule_id", rule.ID), zap.Error(err))
+				continue
+			}
+			sent += written
+			continue
+		}
+		for _, userID := range recipients.forClass(ctx, rule.Audience, breach.ClassID) {
+			item := notification
+			item.UserID = userID
+			written, err := s.notifications.CreateOnce(ctx, &item)
+			if err != nil {
+				s.logger.Warn("alert rule notification failed", zap.String("rule_id", rule.ID), zap.String("user_id", userID), zap.Error(err))
+				continue
+			}
+			if written {
+				sent++
+			}
+		}
+	}
+	return sent
+}
+
+// alertRecipients loads a term's homeroom and assigned teachers once, on first use.
+type alertRecipients struct {
+	svc       *AlertRuleService
+	termID    string
+	homerooms map[string]string
+	teachers  map[string][]string
+}
+
+func (r *alertRecipients) forClass(ctx context.Context, audience models.AlertAudience, classID string) []string {
+	switch audience {
+	case models.AlertAudienceHomeroom:
+		if r.homerooms == nil {
+			r.homerooms = make(map[string]string)
+			if r.svc.homerooms != nil {
+				items, err := r.svc.homerooms.List(ctx, dto.HomeroomFilter{TermID: r.termID})
+				if err != nil {
+					r.svc.logger.Warn("alert rules could not list homerooms", zap.Error(err))
+				}
+				for _, item := range items {
+					if item.HomeroomTeacherID != nil && *item.HomeroomTeacherID != "" {
+						r.homerooms[item.ClassID] = *item.HomeroomTeacherID
+					}
+				}
+			}
+		}
+		if teacherID, ok := r.homerooms[classID]; ok {
+			return []string{teacherID}
+		}
+	case models.AlertAudienceTeachers:
+		if r.teachers == nil {
+			r.teachers = make(map[string][]string)
+			if r.svc.assignments != nil {
+				assignments, err := r.svc.assignments.ListByTerm(ctx, r.termID)
+				if err != nil {
+					r.svc.logger.Warn("alert rules could not list teacher assignments", zap.Error(err))
+				}
+				for _, assignment := range assignments {
+					r.teachers[assignment.ClassID] = append(r.teachers[assignment.ClassID], assignment.TeacherID)
+				}
+			}
+		}
+		return uniqueStrings(r.teachers[classID])
+	}
+	return nil
+}
+
+func (s *AlertRuleService) ruleFromRequest(req dto.AlertRuleRequest, actor *models.JWTClaims) (*models.AlertRule, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid alert rule payload")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "name is required")
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &models.AlertRule{
+		Name:       name,
+		Metric:     models.AlertMetric(req.Metric),
+		Comparator: models.AlertComparator(req.Comparator),
+		Threshold:  req.Threshold,
+		Audience:   models.AlertAudience(req.Audience),
+		Channel:    models.AlertChannel(req.Channel),
+		Enabled:    enabled,
+		UpdatedBy:  &actor.UserID,
+	}, nil
+}
+
+func (s *AlertRuleService) resolveTerm(ctx context.Context, termID string) (string, error) {
+	if termID != "" {
+		if _, err := s.terms.FindByID(ctx, termID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", appErrors.Clone(appErrors.ErrNotFound, "term not found")
+			}
+			return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+		}
+		return termID, nil
+	}
+	term, err := s.terms.FindActive(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", appErrors.Clone(appErrors.ErrNotFound, "active term not found")
+		}
+		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load active term")
+	}
+	return term.ID, nil
+}
+
+// classMetricValues indexes each class's attendance rate and average grade, rounded as stored.
+// Classes without data are left out so they never breach a rule.
+func classMetricValues(attendance []models.AnalyticsAttendanceSummary, grades []models.AnalyticsGradeSummary) map[models.AlertMetric]map[string]float64 {
+	rates := make(map[string]float64, len(attendance))
+	for _, summary := range attendance {
+		rates[summary.ClassID] = math.Round(summary.Percentage*100) / 100
+	}
+	averages := averageGradeByClass(grades)
+	for classID, average := range averages {
+		averages[classID] = math.Round(average*100) / 100
+	}
+	return map[models.AlertMetric]map[string]float64{
+		models.AlertMetricClassAttendanceRate: rates,
+		models.AlertMetricClassAverageGrade:   averages,
+	}
+}
+
+// evaluateAlertRules returns a breach for every enabled rule and class whose value crosses the
+// rule's threshold, in rule order and then by class.
+func evaluateAlertRules(rules []models.AlertRule, values map[models.AlertMetric]map[string]float64) []models.AlertRuleBreach {
+	breaches := make([]models.AlertRuleBreach, 0)
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		byClass := values[rule.Metric]
+		classIDs := make([]string, 0, len(byClass))
+		for classID := range byClass {
+			classIDs = append(classIDs, classID)
+		}
+		sort.Strings(classIDs)
+		for _, classID := range classIDs {
+			value := byClass[classID]
+			if !rule.Breached(value) {
+				continue
+			}
+			breaches = append(breaches, models.AlertRuleBreach{
+				RuleID:     rule.ID,
+				RuleName:   rule.Name,
+				Metric:     rule.Metric,
+				Comparator: rule.Comparator,
+				Audience:   rule.Audience,
+				ClassID:    classID,
+				Value:      value,
+				Threshold:  rule.Threshold,
+			})
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type alertRuleStoreStub struct {
+	rules   []models.AlertRule
+	open    map[string]models.AlertRuleBreach
+	created *models.AlertRule
+	filter  models.AlertRuleBreachFilter
+}
+
+func (s *alertRuleStoreStub) ListRules(ctx context.Context, includeDisabled bool) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	for _, rule := range s.rules {
+		if rule.Enabled || includeDisabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (s *alertRuleStoreStub) FindRule(ctx context.Context, id string) (*models.AlertRule, error) {
+	for _, rule := range s.rules {
+		if rule.ID == id {
+			return &rule, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *alertRuleStoreStub) CreateRule(ctx context.Context, rule *models.AlertRule) error {
+	rule.ID = "rule-new"
+	s.created = rule
+	return nil
+}
+
+func (s *alertRuleStoreStub) UpdateRule(ctx context.Context, rule *models.AlertRule) error {
+	for i := range s.rules {
+		if s.rules[i].ID == rule.ID {
+			s.rules[i] = *rule
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (s *alertRuleStoreStub) DeleteRule(ctx context.Context, id string) error {
+	return sql.ErrNoRows
+}
+
+func (s *alertRuleStoreStub) ReplaceBreaches(ctx context.Context, termID string, breaches []models.AlertRuleBreach, now time.Time) ([]models.AlertRuleBreach, error) {
+	next := make(map[string]models.AlertRuleBreach, len(breaches))
+	var created []models.AlertRuleBreach
+	for _, breach := range breaches {
+		key := breach.RuleID + ":" + breach.ClassID
+		if _, ok := s.open[key]; !ok {
+			created = append(created, breach)
+		}
+		next[key] = breach
+	}
+	s.open = next
+	return created, nil
+}
+
+func (s *alertRuleStoreStub) ListBreaches(ctx context.Context, filter models.AlertRuleBreachFilter) ([]models.AlertRuleBreach, error) {
+	s.filter = filter
+	return nil, nil
+}
+
+type alertAssignmentStub []models.TeacherAssignment
+
+func (s alertAssignmentStub) ListByTerm(ctx context.Context, termID string) ([]models.TeacherAssignment, error) {
+	return s, nil
+}
+
+func newAlertRuleServiceForTest(store *alertRuleStoreStub, notifications *mutationNotifierStub) *AlertRuleService {
+	teacher := "teacher-1"
+	svc := NewAlertRuleService(AlertRuleServiceParams{
+		Store: store,
+		Terms: termReaderStub{active: &models.Term{ID: "term-1"}},
+		Analytics: &fakeAnalyticsRepo{
+			attendance: []models.AnalyticsAttendanceSummary{
+				{ClassID: "class-a", Percentage: 82.456},
+				{ClassID: "class-b", Percentage: 95},
+			},
+			grades: []models.AnalyticsGradeSummary{
+				{ClassID: "class-a", SubjectID: "math", AverageScore: 60},
+				{ClassID: "class-a", SubjectID: "bio", AverageScore: 70},
+				{ClassID: "class-b", SubjectID: "math", AverageScore: 90},
+			},
+		},
+		Classes: classLookupStub{},
+		Homerooms: homeroomListerStub{items: []dto.HomeroomItem{
+			{ClassID: "class-a", TermID: "term-1", HomeroomTeacherID: &teacher},
+		}},
+		Assignments: alertAssignmentStub{
+			{TeacherID: "teacher-1", ClassID: "class-a", TermID: "term-1"},
+			{TeacherID: "teacher-2", ClassID: "class-a", TermID: "term-1"},
+			{TeacherID: "teacher-2", ClassID: "class-a", TermID: "term-1"},
+		},
+		Notifications: notifications,
+	})
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestAlertRuleServiceEvaluate(t *testing.T) {
+	store := &alertRuleStoreStub{rules: []models.AlertRule{
+		{ID: "attendance", Name: "Low attendance", Metric: models.AlertMetricClassAttendanceRate, Comparator: models.AlertComparatorLT,
+			Threshold: 90, Audience: models.AlertAudienceTeachers, Channel: models.AlertChannelNotification, Enabled: true},
+		{ID: "grade", Name: "Low grades", Metric: models.AlertMetricClassAverageGrade, Comparator: models.AlertComparatorLTE,
+			Threshold: 65, Audience: models.AlertAudienceAdmins, Channel: models.AlertChannelNotification, Enabled: true},
+		{ID: "homeroom", Name: "Dashboard only", Metric: models.AlertMetricClassAttendanceRate, Comparator: models.AlertComparatorLT,
+			Threshold: 100, Audience: models.AlertAudienceHomeroom, Channel: models.AlertChannelDashboard, Enabled: true},
+		{ID: "disabled", Name: "Disabled", Metric: models.AlertMetricClassAttendanceRate, Comparator: models.AlertComparatorGTE,
+			Threshold: 0, Audience: models.AlertAudienceAdmins, Channel: models.AlertChannelNotification},
+	}}
+	notifications := &mutationNotifierStub{}
+	svc := newAlertRuleServiceForTest(store, notifications)
+
+	run, err := svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, dto.AlertRuleRun{TermID: "term-1", Rules: 3, Classes: 2, Open: 4, New: 4, Notified: 4}, *run)
+	require.Contains(t, store.open, "attendance:class-a")
+	assert.Equal(t, 82.46, store.open["attendance:class-a"].Value)
+	assert.Equal(t, 65.0, store.open["grade:class-a"].Value)
+	assert.Contains(t, store.open, "homeroom:class-b")
+
+	// Both teachers of class-a are notified once; the admins through a role fan-out.
+	require.Len(t, notifications.sent, 2)
+	assert.Equal(t, "teacher-1", notifications.sent[0].UserID)
+	assert.Equal(t, "teacher-2", notifications.sent[1].UserID)
+	assert.Equal(t, models.NotificationTypeAlertRule, notifications.sent[0].Type)
+	assert.Equal(t, "The attendance rate of X IPA 1 is 82.46, below the threshold of 90.00.", notifications.sent[0].Body)
+	require.Len(t, notifications.broadcasts, 1)
+	assert.Equal(t, "Low grades", notifications.broadcasts[0].Title)
+
+	// Open breaches are refreshed without notifying again.
+	run, err = svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.New)
+	assert.Len(t, notifications.sent, 2)
+}
+
+func TestAlertRuleServiceManagesRules(t *testing.T) {
+	store := &alertRuleStoreStub{rules: []models.AlertRule{{ID: "rule-1", Name: "Old", Enabled: true}}}
+	svc := newAlertRuleServiceForTest(store, &mutationNotifierStub{})
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	req := dto.AlertRuleRequest{Name: " Low attendance ", Metric: "CLASS_ATTENDANCE_RATE", Comparator: "LT", Threshold: 85, Audience: "HOMEROOM", Channel: "NOTIFICATION"}
+
+	rule, err := svc.CreateRule(context.Background(), req, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "rule-new", rule.ID)
+	assert.Equal(t, "Low attendance", rule.Name)
+	assert.True(t, rule.Enabled)
+	assert.Equal(t, "admin-1", *store.created.UpdatedBy)
+
+	invalid := req
+	invalid.Threshold = 120
+	_, err = svc.CreateRule(context.Background(), invalid, admin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	disabled := false
+	req.Enabled = &disabled
+	updated, err := svc.UpdateRule(context.Background(), "rule-1", req, admin)
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, models.AlertAudienceHomeroom, updated.Audience)
+
+	_, err = svc.UpdateRule(context.Background(), "missing", req, admin)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+	err = svc.DeleteRule(context.Background(), "missing")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestAlertRuleServiceBreachesScopesTeachers(t *testing.T) {
+	store := &alertRuleStoreStub{}
+	svc := newAlertRuleServiceForTest(store, &mutationNotifierStub{})
+
+	breaches, err := svc.Breaches(context.Background(), dto.AlertRuleBreachQuery{ClassID: "class-a"}, &models.JWTClaims{UserID: "teacher-9", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.NotNil(t, breaches)
+	assert.Equal(t, models.AlertRuleBreachFilter{
+		TermID: "term-1", ClassIDs: []string{"class-a"}, TeacherID: "teacher-9", Audiences: teacherAlertAudiences,
+	}, store.filter)
+}
@@ -9,14 +9,11 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 )
 
 // AnalyticsRepository describes the persistence layer required by AnalyticsService.
-type AnalyticsRepository interface {
-	AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error)
-	GradeSummary(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, error)
-	BehaviorSummary(ctx context.Context, filter models.AnalyticsBehaviorFilter) ([]models.AnalyticsBehaviorSummary, error)
-}
+type AnalyticsRepository = ports.AnalyticsRepository
 
 // AnalyticsService provides read-optimised access to analytics datasets with cache integration.
 type AnalyticsService struct {
@@ -33,7 +30,7 @@ func NewAnalyticsService(repo AnalyticsRepository, cache *CacheService, metrics
 
 // Attendance returns aggregated attendance analytics. The boolean indicates whether data originated from cache.
 func (s *AnalyticsService) Attendance(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, bool, error) {
-	cacheKey := makeAnalyticsCacheKey("attendance", filter.TermID, filter.ClassID, formatTime(filter.DateFrom), formatTime(filter.DateTo))
+	cacheKey := makeAnalyticsCacheKey("attendance", filter.TermID, filter.ClassID, classListKey(filter.ClassIDs), formatTime(filter.DateFrom), formatTime(filter.DateTo))
 	var cached []models.AnalyticsAttendanceSummary
 	if s.cache != nil {
 		if hit, err := s.cache.Get(ctx, cacheKey, &cached); err != nil {
@@ -61,7 +58,7 @@ func (s *AnalyticsService) Attendance(ctx context.Context, filter models.Analyti
 
 // Grades returns aggregated grade analytics.
 func (s *AnalyticsService) Grades(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, bool, error) {
-	cacheKey := makeAnalyticsCacheKey("grades", filter.TermID, filter.ClassID, filter.SubjectID)
+	cacheKey := makeAnalyticsCacheKey("grades", filter.TermID, filter.ClassID, classListKey(filter.ClassIDs), filter.SubjectID)
 	var cached []models.AnalyticsGradeSummary
 	if s.cache != nil {
 		if hit, err := s.cache.Get(ctx, cacheKey, &cached); err != nil {
@@ -137,6 +134,14 @@ func makeAnalyticsCacheKey(parts ...string) string {
 	return builder.String()
 }
 
+// classListKey renders a class list filter as a cache key part that cannot collide with a single ID.
+func classListKey(classIDs []string) string {
+	if len(classIDs) == 0 {
+		return ""
+	}
+	return "classes=" + strings.Join(classIDs, ",")
+}
+
 func formatTime(t *time.Time) string {
 	if t == nil {
 		return ""
@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
@@ -19,17 +22,38 @@ type announcementRepository interface {
 	Create(ctx context.Context, announcement *models.Announcement) error
 	Update(ctx context.Context, announcement *models.Announcement) error
 	Delete(ctx context.Context, id string) error
+	Acknowledge(ctx context.Context, announcementID, userID string, at time.Time) (time.Time, error)
+	ListRecipients(ctx context.Context, announcementID string, role models.UserRole) ([]models.AnnouncementRecipient, error)
+	ListUnacknowledged(ctx context.Context, limit int) ([]models.AnnouncementAckSummary, error)
+}
+
+// announcementAckReportLimit bounds each recipient list of an acknowledgement report.
+const announcementAckReportLimit = 500
+
+type announcementNotifier interface {
+	CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error)
 }
 
 // AnnouncementService handles announcement workflows.
 type AnnouncementService struct {
 	repo      announcementRepository
+	notifier  announcementNotifier
 	validator *validator.Validate
 	logger    *zap.Logger
 }
 
+// AnnouncementServiceOption configures the service.
+type AnnouncementServiceOption func(*AnnouncementService)
+
+// WithAnnouncementNotifications enables Broadcast, which notifies the targeted users through notifier.
+func WithAnnouncementNotifications(notifier announcementNotifier) AnnouncementServiceOption {
+	return func(s *AnnouncementService) {
+		s.notifier = notifier
+	}
+}
+
 // NewAnnouncementService constructs the service.
-func NewAnnouncementService(repo announcementRepository, validate *validator.Validate, logger *zap.Logger) *AnnouncementService {
+func NewAnnouncementService(repo announcementRepository, validate *validator.Validate, logger *zap.Logger, opts ...AnnouncementServiceOption) *AnnouncementService {
 	if validate == nil {
 		validate = validator.New()
 	}
@@ -37,6 +61,11 @@ func NewAnnouncementService(repo announcementRepository, validate *validator.Val
 		logger = zap.NewNop()
 	}
 	svc := &AnnouncementService{repo: repo, validator: validate, logger: logger}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(svc)
+		}
+	}
 	svc.validator.RegisterValidation("audience", func(fl validator.FieldLevel) bool {
 		switch models.AnnouncementAudience(strings.ToUpper(fl.Field().String())) {
 		case models.AnnouncementAudienceAll, models.AnnouncementAudienceGuru, models.AnnouncementAudienceSiswa, models.AnnouncementAudienceClass:
@@ -193,6 +222,192 @@ func (s *AnnouncementService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// Broadcast publishes an announcement to the portals and notifies, in-app and by push, every active
+// user holding one of the requested roles. The portal audience is derived from the roles: teachers
+// see GURU, students and guardians see SISWA and both see ALL; admins see every audience already.
+func (s *AnnouncementService) Broadcast(ctx context.Context, req dto.BroadcastAnnouncementRequest, claims *models.JWTClaims) (*dto.BroadcastAnnouncementResult, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if s.notifier == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "announcement broadcasts are not configured")
+	}
+	title, content := strings.TrimSpace(req.Title), strings.TrimSpace(req.Content)
+	if title == "" || len(title) > 200 || content == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "title (max 200 characters) and content are required")
+	}
+	roles, audience, err := broadcastAudience(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+	priority := models.AnnouncementPriority(strings.ToUpper(strings.TrimSpace(req.Priority)))
+	switch priority {
+	case "":
+		priority = models.AnnouncementPriorityNormal
+	case models.AnnouncementPriorityLow, models.AnnouncementPriorityNormal, models.AnnouncementPriorityHigh:
+	default:
+		return nil, appErrors.Clone(appErrors.ErrValidation, "priority must be LOW, NORMAL or HIGH")
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "expiresAt must be in the future")
+	}
+
+	announcement := &models.Announcement{
+		Title:       title,
+		Content:     content,
+		Audience:    audience,
+		Priority:    priority,
+		IsPinned:    req.IsPinned,
+		RequiresAck: req.RequiresAck,
+		PublishedAt: now,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   claims.UserID,
+	}
+	if err := s.repo.Create(ctx, announcement); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create announcement")
+	}
+	dedupeKey := models.AnnouncementDedupeKey(announcement.ID)
+	recipients, err := s.notifier.CreateForRoles(ctx, roles, models.Notification{
+		Type:      models.NotificationTypeAnnouncement,
+		Title:     title,
+		Body:      content,
+		RefID:     &announcement.ID,
+		DedupeKey: &dedupeKey,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to notify announcement recipients")
+	}
+	s.logger.Info("announcement broadcast",
+		zap.String("announcement_id", announcement.ID),
+		zap.Any("roles", roles),
+		zap.Int("recipients", recipients),
+	)
+	return &dto.BroadcastAnnouncementResult{Announcement: announcement, Recipients: recipients}, nil
+}
+
+// Acknowledge records that the caller read an announcement sent to them and marks its notification
+// read. Acknowledging again is harmless and returns the first acknowledgement.
+func (s *AnnouncementService) Acknowledge(ctx context.Context, id string, claims *models.JWTClaims) (*dto.AnnouncementAck, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	acknowledgedAt, err := s.repo.Acknowledge(ctx, id, claims.UserID, time.Now().UTC())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrForbidden, "announcement was not sent to you")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to acknowledge announcement")
+	}
+	return &dto.AnnouncementAck{AnnouncementID: id, AcknowledgedAt: acknowledgedAt}, nil
+}
+
+// Acknowledgements reports who acknowledged an announcement and who is still missing, optionally
+// restricted to recipients holding role.
+func (s *AnnouncementService) Acknowledgements(ctx context.Context, id, role string) (*dto.AnnouncementAckReport, error) {
+	announcement, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	userRole := models.UserRole(strings.ToUpper(strings.TrimSpace(role)))
+	switch userRole {
+	case "", models.RoleTeacher, models.RoleStudent, models.RoleGuardian, models.RoleAdmin, models.RoleSuperAdmin:
+	default:
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unknown role %q", role))
+	}
+	recipients, err := s.repo.ListRecipients(ctx, id, userRole)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list announcement recipients")
+	}
+	report := &dto.AnnouncementAckReport{
+		AnnouncementID: announcement.ID,
+		Title:          announcement.Title,
+		RequiresAck:    announcement.RequiresAck,
+		Recipients:     len(recipients),
+		Acknowledgers:  []dto.AnnouncementRecipientStatus{},
+		Missing:        []dto.AnnouncementRecipientStatus{},
+	}
+	for _, recipient := range recipients {
+		status := dto.AnnouncementRecipientStatus{
+			UserID:         recipient.UserID,
+			FullName:       recipient.FullName,
+			Role:           recipient.Role,
+			AcknowledgedAt: recipient.AcknowledgedAt,
+		}
+		list := &report.Missing
+		if recipient.AcknowledgedAt != nil {
+			report.Acknowledged++
+			list = &report.Acknowledgers
+		}
+		if len(*list) == announcementAckReportLimit {
+			report.Truncated = true
+			continue
+		}
+		*list = append(*list, status)
+	}
+	report.Pending = report.Recipients - report.Acknowledged
+	return report, nil
+}
+
+// UnacknowledgedAnnouncements lists up to limit active announcements requiring acknowledgement that
+// some recipients have not acknowledged yet, newest first.
+func (s *AnnouncementService) UnacknowledgedAnnouncements(ctx context.Context, limit int) ([]dto.UnacknowledgedAnnouncement, error) {
+	summaries, err := s.repo.ListUnacknowledged(ctx, limit)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list unacknowledged announcements")
+	}
+	result := make([]dto.UnacknowledgedAnnouncement, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, dto.UnacknowledgedAnnouncement{
+			ID:           summary.ID,
+			Title:        summary.Title,
+			Priority:     string(summary.Priority),
+			PublishedAt:  summary.PublishedAt,
+			Recipients:   summary.Recipients,
+			Acknowledged: summary.Acknowledged,
+			Pending:      summary.Recipients - summary.Acknowledged,
+		})
+	}
+	return result, nil
+}
+
+// broadcastAudience validates roles, drops duplicates and picks the portal audience they map to.
+func broadcastAudience(requested []models.UserRole) ([]models.UserRole, models.AnnouncementAudience, error) {
+	roles := make([]models.UserRole, 0, len(requested))
+	seen := make(map[models.UserRole]bool, len(requested))
+	var teachers, students bool
+	for _, role := range requested {
+		role = models.UserRole(strings.ToUpper(strings.TrimSpace(string(role))))
+		switch role {
+		case models.RoleTeacher:
+			teachers = true
+		case models.RoleStudent, models.RoleGuardian:
+			students = true
+		case models.RoleAdmin, models.RoleSuperAdmin:
+		default:
+			return nil, "", appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unknown role %q", role))
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	switch {
+	case teachers && students:
+		return roles, models.AnnouncementAudienceAll, nil
+	case teachers:
+		return roles, models.AnnouncementAudienceGuru, nil
+	case students:
+		return roles, models.AnnouncementAudienceSiswa, nil
+	default:
+		return nil, "", appErrors.Clone(appErrors.ErrValidation, "roles must include TEACHER, STUDENT or GUARDIAN")
+	}
+}
+
 func (s *AnnouncementService) ensureAudienceTarget(audience string, target *string) error {
 	if strings.ToUpper(audience) == string(models.AnnouncementAudienceClass) && (target == nil || *target == "") {
 		return appErrors.Clone(appErrors.ErrValidation, "target_class_id required for CLASS audience")
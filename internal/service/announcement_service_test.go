@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+func TestAnnouncementServiceAcknowledgements(t *testing.T) {
+	earlier := time.Date(2026, 5, 4, 7, 0, 0, 0, time.UTC)
+	repo := &announcementRepoStub{recipients: []models.AnnouncementRecipient{
+		{UserID: "teacher-1", FullName: "Ani", Role: models.RoleTeacher, AcknowledgedAt: &earlier},
+		{UserID: "teacher-2", FullName: "Budi", Role: models.RoleTeacher},
+		{UserID: "student-1", FullName: "Citra", Role: models.RoleStudent},
+	}}
+	svc := NewAnnouncementService(repo, nil, nil, WithAnnouncementNotifications(&announcementNotifierStub{}))
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	result, err := svc.Broadcast(context.Background(), dto.BroadcastAnnouncementRequest{
+		Title: "Rapat", Content: "Rapat guru", Roles: []models.UserRole{models.RoleTeacher, models.RoleStudent}, RequiresAck: true,
+	}, admin)
+	require.NoError(t, err)
+	assert.True(t, result.Announcement.RequiresAck)
+
+	ack, err := svc.Acknowledge(context.Background(), "ann-1", &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.Equal(t, "ann-1", ack.AnnouncementID)
+
+	// Repeated acknowledgements keep the first timestamp.
+	ack, err = svc.Acknowledge(context.Background(), "ann-1", &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.Equal(t, earlier, ack.AcknowledgedAt)
+
+	_, err = svc.Acknowledge(context.Background(), "ann-1", &models.JWTClaims{UserID: "guardian-1", Role: models.RoleGuardian})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = svc.Acknowledge(context.Background(), "missing", admin)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	report, err := svc.Acknowledgements(context.Background(), "ann-1", "")
+	require.NoError(t, err)
+	assert.True(t, report.RequiresAck)
+	assert.Equal(t, 3, report.Recipients)
+	assert.Equal(t, 2, report.Acknowledged)
+	assert.Equal(t, 1, report.Pending)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "student-1", report.Missing[0].UserID)
+	assert.False(t, report.Truncated)
+
+	report, err = svc.Acknowledgements(context.Background(), "ann-1", "teacher")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Recipients)
+	assert.Zero(t, report.Pending)
+	assert.Empty(t, report.Missing)
+
+	_, err = svc.Acknowledgements(context.Background(), "ann-1", "janitor")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestAnnouncementServiceUnacknowledged(t *testing.T) {
+	published := time.Date(2026, 5, 4, 7, 0, 0, 0, time.UTC)
+	repo := &announcementRepoStub{summaries: []models.AnnouncementAckSummary{
+		{ID: "ann-1", Title: "Rapat", Priority: models.AnnouncementPriorityHigh, PublishedAt: published, Recipients: 40, Acknowledged: 31},
+	}}
+	svc := NewAnnouncementService(repo, nil, nil)
+
+	pending, err := svc.UnacknowledgedAnnouncements(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 9, pending[0].Pending)
+	assert.Equal(t, "HIGH", pending[0].Priority)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	archiveRetentionBatch = 100
+	// archiveRetentionReportLimit bounds the archives listed per category in a retention report.
+	archiveRetentionReportLimit = 500
+	maxArchiveRetentionWindow   = 365
+)
+
+type archiveRetentionStore interface {
+	ListUploadedBefore(ctx context.Context, category string, before time.Time, limit int) ([]models.ArchiveItem, error)
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.ArchiveItem, error)
+	SoftDelete(ctx context.Context, id, reason string, deletedAt time.Time) error
+	Purge(ctx context.Context, id string) error
+}
+
+type archiveFileRemover interface {
+	Delete(filename string) error
+}
+
+// ArchiveRetentionConfig holds the per-category retention periods.
+type ArchiveRetentionConfig struct {
+	// Policies maps a category, matched case-insensitively, to how long its archives are kept
+	// after upload. Categories without a policy are kept indefinitely.
+	Policies map[string]time.Duration
+	// Grace is how long soft-deleted archives stay in the trash before their files and rows are purged.
+	Grace    time.Duration
+	Interval time.Duration
+}
+
+// ArchiveRetentionService expires archives once their category's retention period has passed and
+// empties the trash. Expired archives are soft-deleted like manual deletions, so they stay restorable
+// until every soft-deleted archive older than the grace period is purged. Both steps are audited.
+type ArchiveRetentionService struct {
+	store  archiveRetentionStore
+	files  archiveFileRemover
+	audit  ports.AuditLogger
+	logger *zap.Logger
+	cfg    ArchiveRetentionConfig
+	now    func() time.Time
+}
+
+// NewArchiveRetentionService constructs the service.
+func NewArchiveRetentionService(store archiveRetentionStore, files archiveFileRemover, audit ports.AuditLogger, logger *zap.Logger, cfg ArchiveRetentionConfig) *ArchiveRetentionService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.Grace < 0 {
+		cfg.Grace = 0
+	}
+	policies := make(map[string]time.Duration, len(cfg.Policies))
+	for category, period := range cfg.Policies {
+		if category = strings.ToLower(strings.TrimSpace(category)); category != "" && period > 0 {
+			policies[category] = period
+		}
+	}
+	cfg.Policies = policies
+	return &ArchiveRetentionService{store: store, files: files, audit: audit, logger: logger, cfg: cfg, now: time.Now}
+}
+
+// Start runs the retention job now and then every interval until ctx is cancelled.
+func (s *ArchiveRetentionService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Run(ctx); err != nil {
+				s.logger.Warn("archive retention run failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run soft-deletes the archives past their retention period and purges those deleted longer than
+// the grace period ago.
+func (s *ArchiveRetentionService) Run(ctx context.Context) (dto.ArchiveRetentionRun, error) {
+	var run dto.ArchiveRetentionRun
+	now := s.now().UTC()
+	for _, category := range s.categories() {
+		expired, err := s.expireCategory(ctx, category, now)
+		run.Expired += expired
+		if err != nil {
+			return run, err
+		}
+	}
+	purged, err := s.purgeTrash(ctx, now)
+	run.Purged = purged
+	if run.Expired > 0 || run.Purged > 0 {
+		s.logger.Info("archive retention applied", zap.Int("expired", run.Expired), zap.Int("purged", run.Purged))
+	}
+	return run, err
+}
+
+// Report lists the archives that will expire within the given number of days.
+func (s *ArchiveRetentionService) Report(ctx context.Context, withinDays int) (*dto.ArchiveRetentionReport, error) {
+	if withinDays < 0 || withinDays > maxArchiveRetentionWindow {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "days must be between 0 and 365")
+	}
+	now := s.now().UTC()
+	report := &dto.ArchiveRetentionReport{
+		GeneratedAt: now,
+		WithinDays:  withinDays,
+		GraceDays:   int(s.cfg.Grace / (24 * time.Hour)),
+		Policies:    []dto.ArchiveRetentionPolicy{},
+		Items:       []dto.ArchiveExpiringItem{},
+	}
+	horizon := now.AddDate(0, 0, withinDays)
+	for _, category := range s.categories() {
+		period := s.cfg.Policies[category]
+		report.Policies = append(report.Policies, dto.ArchiveRetentionPolicy{Category: category, RetentionDays: int(period / (24 * time.Hour))})
+		items, err := s.store.ListUploadedBefore(ctx, category, horizon.Add(-period), archiveRetentionReportLimit)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list expiring archives")
+		}
+		if len(items) == archiveRetentionReportLimit {
+			report.Truncated = true
+		}
+		for _, item := range items {
+			report.Items = append(report.Items, dto.ArchiveExpiringItem{
+				ID:         item.ID,
+				Title:      item.Title,
+				Category:   item.Category,
+				SizeBytes:  item.SizeBytes,
+				UploadedBy: item.UploadedBy,
+				UploadedAt: item.UploadedAt,
+				ExpiresAt:  item.RetainedSince().Add(period),
+			})
+			report.TotalBytes += item.SizeBytes
+		}
+	}
+	sort.SliceStable(report.Items, func(i, j int) bool { return report.Items[i].ExpiresAt.Before(report.Items[j].ExpiresAt) })
+	return report, nil
+}
+
+func (s *ArchiveRetentionService) categories() []string {
+	categories := make([]string, 0, len(s.cfg.Policies))
+	for category := range s.cfg.Policies {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// expireCategory soft-deletes the expired archives of category batch by batch. Failed rows stop the
+// category so they are not fetched again in a loop; the next run retries them.
+func (s *ArchiveRetentionService) expireCategory(ctx context.Context, category string, now time.Time) (int, error) {
+	period := s.cfg.Policies[category]
+	cutoff := now.Add(-period)
+	expired := 0
+	for {
+		items, err := s.store.ListUploadedBefore(ctx, category, cutoff, archiveRetentionBatch)
+		if err != nil {
+			return expired, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list expired archives")
+		}
+		failed := false
+		for _, item := range items {
+			if err := s.store.SoftDelete(ctx, item.ID, models.ArchiveDeletionRetention, now); err != nil {
+				s.logger.Warn("failed to expire archive", zap.String("archive_id", item.ID), zap.Error(err))
+				failed = true
+				continue
+			}
+			expired++
+			s.emitAudit(ctx, models.AuditActionArchiveExpire, item, map[string]interface{}{
+				"category":      item.Category,
+				"retainedSince": item.RetainedSince(),
+				"retentionDays": int(period / (24 * time.Hour)),
+			})
+		}
+		if failed || len(items) < archiveRetentionBatch {
+			return expired, nil
+		}
+	}
+}
+
+// purgeTrash removes the files and rows of archives deleted before the grace period.
+func (s *ArchiveRetentionService) purgeTrash(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-s.cfg.Grace)
+	purged := 0
+	for {
+		items, err := s.store.ListDeletedBefore(ctx, cutoff, archiveRetentionBatch)
+		if err != nil {
+			return purged, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list deleted archives")
+		}
+		failed := false
+		for _, item := range items {
+			// The row goes first so an archive restored meanwhile keeps its file; Purge only removes
+			// rows still in the trash.
+			if err := s.store.Purge(ctx, item.ID); err != nil {
+				if !errors.Is(err, sql.ErrNoRows) {
+					s.logger.Warn("failed to purge archive", zap.String("archive_id", item.ID), zap.Error(err))
+					failed = true
+				}
+				continue
+			}
+			if s.files != nil {
+				if err := s.files.Delete(item.FilePath); err != nil {
+					s.logger.Warn("failed to delete purged archive file", zap.String("archive_id", item.ID), zap.String("file_path", item.FilePath), zap.Error(err))
+				}
+				if item.ThumbnailPath != nil {
+					if err := s.files.Delete(*item.ThumbnailPath); err != nil {
+						s.logger.Warn("failed to delete purged archive thumbnail", zap.String("archive_id", item.ID), zap.Error(err))
+					}
+				}
+			}
+			purged++
+			s.emitAudit(ctx, models.AuditActionArchivePurge, item, map[string]interface{}{
+				"category":  item.Category,
+				"title":     item.Title,
+				"filePath":  item.FilePath,
+				"deletedAt": item.DeletedAt,
+				"reason":    item.DeletionReason,
+			})
+		}
+		if failed || len(items) < archiveRetentionBatch {
+			return purged, nil
+		}
+	}
+}
+
+func (s *ArchiveRetentionService) emitAudit(ctx context.Context, action string, item models.ArchiveItem, values map[string]interface{}) {
+	if s.audit == nil {
+		return
+	}
+	payload, _ := json.Marshal(values)
+	id := item.ID
+	log := &models.AuditLog{
+		Action:     action,
+		Resource:   "archive",
+		ResourceID: &id,
+		NewValues:  payload,
+		IPAddress:  "system",
+		UserAgent:  "archive-retention",
+	}
+	if err := s.audit.CreateAuditLog(ctx, log); err != nil {
+		s.logger.Warn("failed to create archive retention audit", zap.Error(err))
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func (r *archiveRepoStub) ListUploadedBefore(ctx context.Context, category string, before time.Time, limit int) ([]models.ArchiveItem, error) {
+	var result []models.ArchiveItem
+	for _, item := range r.items {
+		if item.DeletedAt == nil && strings.EqualFold(item.Category, category) && item.RetainedSince().Before(before) {
+			result = append(result, *item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].RetainedSince().Before(result[j].RetainedSince()) })
+	return result, nil
+}
+
+func (r *archiveRepoStub) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.ArchiveItem, error) {
+	var result []models.ArchiveItem
+	for _, item := range r.items {
+		if item.DeletedAt != nil && item.DeletedAt.Before(before) {
+			result = append(result, *item)
+		}
+	}
+	return result, nil
+}
+
+func (r *archiveRepoStub) Purge(ctx context.Context, id string) error {
+	delete(r.items, id)
+	return nil
+}
+
+func TestArchiveRetentionServiceExpiresAndPurges(t *testing.T) {
+	now := time.Date(2024, 7, 15, 2, 0, 0, 0, time.UTC)
+	year := 365 * 24 * time.Hour
+	repo := newArchiveRepoStub()
+	files := newStorageStub()
+	files.saved["old.pdf"] = []byte("old")
+	files.saved["old.thumb.png"] = []byte("png")
+	thumbnail := "old.thumb.png"
+	repo.items["old"] = &models.ArchiveItem{ID: "old", Category: "Class_Materials", FilePath: "old.pdf", ThumbnailPath: &thumbnail, UploadedAt: now.Add(-3 * year)}
+	repo.items["recent"] = &models.ArchiveItem{ID: "recent", Category: "class_materials", UploadedAt: now.Add(-year)}
+	repo.items["record"] = &models.ArchiveItem{ID: "record", Category: "student_records", UploadedAt: now.Add(-3 * year)}
+	manual := models.ArchiveDeletionManual
+	deletedAt := now.Add(-90 * 24 * time.Hour)
+	repo.items["trashed"] = &models.ArchiveItem{ID: "trashed", Category: "class_materials", UploadedAt: now, DeletedAt: &deletedAt, DeletionReason: &manual}
+	audit := &auditStub{}
+	svc := NewArchiveRetentionService(repo, files, audit, nil, ArchiveRetentionConfig{
+		Policies: map[string]time.Duration{"Class_Materials": 2 * year, "student_records": 10 * year},
+		Grace:    30 * 24 * time.Hour,
+	})
+	svc.now = func() time.Time { return now }
+
+	// The manual deletion has been in the trash past the grace period and is purged at once.
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Expired)
+	assert.Equal(t, 1, run.Purged)
+	assert.NotContains(t, repo.items, "trashed")
+	require.NotNil(t, repo.items["old"].DeletedAt)
+	assert.Equal(t, models.ArchiveDeletionRetention, *repo.items["old"].DeletionReason)
+	assert.Nil(t, repo.items["recent"].DeletedAt)
+	assert.Nil(t, repo.items["record"].DeletedAt)
+	require.Len(t, audit.logs, 2)
+	assert.Equal(t, models.AuditActionArchiveExpire, audit.logs[0].Action)
+	assert.Contains(t, string(audit.logs[0].NewValues), `"retentionDays":730`)
+	assert.Equal(t, models.AuditActionArchivePurge, audit.logs[1].Action)
+
+	// After the grace period the expired archive and its file are purged too.
+	svc.now = func() time.Time { return now.Add(31 * 24 * time.Hour) }
+	run, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Purged)
+	assert.NotContains(t, repo.items, "old")
+	assert.NotContains(t, files.saved, "old.pdf")
+	assert.NotContains(t, files.saved, "old.thumb.png")
+	require.Len(t, audit.logs, 3)
+}
+
+func TestArchiveRetentionServiceRestartsRetentionOnRestore(t *testing.T) {
+	now := time.Date(2024, 7, 15, 2, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	restoredAt := now.Add(-day)
+	repo := newArchiveRepoStub()
+	repo.items["restored"] = &models.ArchiveItem{ID: "restored", Category: "class_materials", UploadedAt: now.Add(-900 * day), RestoredAt: &restoredAt}
+	svc := NewArchiveRetentionService(repo, nil, nil, nil, ArchiveRetentionConfig{Policies: map[string]time.Duration{"class_materials": 730 * day}})
+	svc.now = func() time.Time { return now }
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, run.Expired)
+	assert.Nil(t, repo.items["restored"].DeletedAt)
+}
+
+func TestArchiveRetentionServiceReport(t *testing.T) {
+	now := time.Date(2024, 7, 15, 2, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := newArchiveRepoStub()
+	repo.items["soon"] = &models.ArchiveItem{ID: "soon", Category: "class_materials", SizeBytes: 10, UploadedAt: now.Add(-720 * day)}
+	repo.items["later"] = &models.ArchiveItem{ID: "later", Category: "class_materials", SizeBytes: 20, UploadedAt: now.Add(-600 * day)}
+	svc := NewArchiveRetentionService(repo, nil, nil, nil, ArchiveRetentionConfig{
+		Policies: map[string]time.Duration{"class_materials": 730 * day},
+		Grace:    30 * day,
+	})
+	svc.now = func() time.Time { return now }
+
+	report, err := svc.Report(context.Background(), 30)
+	require.NoError(t, err)
+	assert.Equal(t, 30, report.GraceDays)
+	assert.Equal(t, 730, report.Policies[0].RetentionDays)
+	require.Len(t, report.Items, 1)
+	assert.Equal(t, "soon", report.Items[0].ID)
+	assert.Equal(t, now.Add(10*day), report.Items[0].ExpiresAt)
+	assert.Equal(t, int64(10), report.TotalBytes)
+
+	_, err = svc.Report(context.Background(), 400)
+	require.Error(t, err)
+}
@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -25,11 +28,11 @@ type archiveStore interface {
 	Create(ctx context.Context, item *models.ArchiveItem) error
 	GetByID(ctx context.Context, id string) (*models.ArchiveItem, error)
 	List(ctx context.Context, filter models.ArchiveFilter) ([]models.ArchiveItem, error)
-	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
-}
-
-type archiveAssignmentLister interface {
-	ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error)
+	SoftDelete(ctx context.Context, id, reason string, deletedAt time.Time) error
+	Restore(ctx context.Context, id string, restoredAt time.Time) error
+	Usage(ctx context.Context, category, uploaderID string) (categoryBytes, uploaderBytes int64, err error)
+	UsageByCategory(ctx context.Context) ([]models.ArchiveUsage, error)
+	UsageByUploader(ctx context.Context, limit int) ([]models.ArchiveUsage, error)
 }
 
 type archiveEnrollmentResolver interface {
@@ -70,23 +73,35 @@ type ArchiveServiceConfig struct {
 	MaxFileSize  int64
 	AllowedMIMEs []string
 	APIPrefix    string
+	// TrashGrace is how long deleted archives stay restorable; it only sets PurgeAt in the trash
+	// listing, ArchiveRetentionService does the purging.
+	TrashGrace time.Duration
+	// CategoryQuotas caps the bytes of live archives per category, matched case-insensitively;
+	// other categories use DefaultCategoryQuota. UploaderQuota caps the bytes per uploader. Zero
+	// means unlimited. Deleted archives stop counting as soon as they are in the trash.
+	CategoryQuotas       map[string]int64
+	DefaultCategoryQuota int64
+	UploaderQuota        int64
 }
 
+// archiveUsageUploaderLimit bounds the uploaders listed in the usage report.
+const archiveUsageUploaderLimit = 50
+
 // ArchiveService manages archive metadata and storage IO.
 type ArchiveService struct {
 	repo        archiveStore
-	assignments archiveAssignmentLister
+	assignments ports.TeacherAssignmentLister
 	enrollments archiveEnrollmentResolver
 	storage     archiveFileStorage
 	signer      archiveSignedURLSigner
-	audit       auditLogger
+	audit       ports.AuditLogger
 	logger      *zap.Logger
 	cfg         ArchiveServiceConfig
 	mimeSet     map[string]struct{}
 }
 
 // NewArchiveService constructs the service with defaults.
-func NewArchiveService(repo archiveStore, assignments archiveAssignmentLister, enrollments archiveEnrollmentResolver, storage archiveFileStorage, signer archiveSignedURLSigner, audit auditLogger, logger *zap.Logger, cfg ArchiveServiceConfig) *ArchiveService {
+func NewArchiveService(repo archiveStore, assignments ports.TeacherAssignmentLister, enrollments archiveEnrollmentResolver, storage archiveFileStorage, signer archiveSignedURLSigner, audit ports.AuditLogger, logger *zap.Logger, cfg ArchiveServiceConfig) *ArchiveService {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -104,6 +119,13 @@ func NewArchiveService(repo archiveStore, assignments archiveAssignmentLister, e
 	if cfg.APIPrefix == "" {
 		cfg.APIPrefix = "/api/v1"
 	}
+	quotas := make(map[string]int64, len(cfg.CategoryQuotas))
+	for category, quota := range cfg.CategoryQuotas {
+		if category = strings.ToLower(strings.TrimSpace(category)); category != "" && quota > 0 {
+			quotas[category] = quota
+		}
+	}
+	cfg.CategoryQuotas = quotas
 	mimeSet := make(map[string]struct{}, len(cfg.AllowedMIMEs))
 	for _, mt := range cfg.AllowedMIMEs {
 		mimeSet[strings.ToLower(mt)] = struct{}{}
@@ -129,6 +151,18 @@ func (s *ArchiveService) Upload(ctx context.Context, meta dto.CreateArchiveReque
 	if actor.Role != models.RoleAdmin && actor.Role != models.RoleSuperAdmin {
 		return nil, appErrors.ErrForbidden
 	}
+	switch models.ArchiveScope(strings.ToUpper(string(meta.Scope))) {
+	case models.ArchiveScopeMutation:
+		return nil, appErrors.Clone(appErrors.ErrValidation, "MUTATION scope is reserved for mutation attachments")
+	case models.ArchiveScopeIntake:
+		return nil, appErrors.Clone(appErrors.ErrValidation, "INTAKE scope is reserved for intake documents")
+	}
+	return s.UploadAttachment(ctx, meta, upload, actor.UserID)
+}
+
+// UploadAttachment stores a file on behalf of another module (e.g. lesson plans) that attaches
+// documents to its own records. The caller is responsible for authorising uploaderID.
+func (s *ArchiveService) UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error) {
 	if err := s.validateUploadMeta(meta); err != nil {
 		return nil, err
 	}
@@ -145,6 +179,9 @@ func (s *ArchiveService) Upload(ctx context.Context, meta dto.CreateArchiveReque
 	if _, allowed := s.mimeSet[strings.ToLower(mimeType)]; !allowed {
 		return nil, appErrors.Clone(appErrors.ErrValidation, "mime type not allowed")
 	}
+	if err := s.checkQuota(ctx, meta.Category, uploaderID, upload.Size); err != nil {
+		return nil, err
+	}
 	filename := s.generateFilename(meta.Category, upload.Filename, mimeType)
 	if _, err := upload.Content.Seek(0, io.SeekStart); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to reset upload stream")
@@ -163,14 +200,14 @@ func (s *ArchiveService) Upload(ctx context.Context, meta dto.CreateArchiveReque
 		FilePath:     path,
 		MimeType:     mimeType,
 		SizeBytes:    upload.Size,
-		UploadedBy:   actor.UserID,
+		UploadedBy:   uploaderID,
 	}
 	if err := s.repo.Create(ctx, item); err != nil {
 		_ = s.storage.Delete(path)
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create archive metadata")
 	}
 	s.emitAudit(ctx, &models.AuditLog{
-		UserID:     &actor.UserID,
+		UserID:     &uploaderID,
 		Action:     models.AuditActionArchiveUpload,
 		Resource:   "archive",
 		ResourceID: &item.ID,
@@ -189,6 +226,7 @@ func (s *ArchiveService) List(ctx context.Context, filter dto.ArchiveFilter, act
 		Category: filter.Category,
 		TermID:   filter.TermID,
 		ClassID:  filter.ClassID,
+		Search:   filter.Search,
 	}
 	items, err := s.repo.List(ctx, repoFilter)
 	if err != nil {
@@ -280,6 +318,33 @@ func (s *ArchiveService) Download(ctx context.Context, id, token string, actor *
 	}, nil
 }
 
+// Preview opens the first-page thumbnail of an archive. It is not found until the thumbnail job
+// has rendered one, and never for documents other than PDFs.
+func (s *ArchiveService) Preview(ctx context.Context, id string, actor *models.JWTClaims) (*ArchiveDownload, error) {
+	item, err := s.Get(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if item.ThumbnailPath == nil {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "archive preview not available")
+	}
+	file, err := s.storage.Open(*item.ThumbnailPath)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to open archive preview")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close() //nolint:errcheck
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to read archive preview metadata")
+	}
+	return &ArchiveDownload{
+		File:      file,
+		Filename:  filepath.Base(*item.ThumbnailPath),
+		MimeType:  "image/png",
+		SizeBytes: info.Size(),
+	}, nil
+}
+
 // Delete marks an archive as deleted (soft delete).
 func (s *ArchiveService) Delete(ctx context.Context, id string, actor *models.JWTClaims) error {
 	if actor == nil {
@@ -288,7 +353,7 @@ func (s *ArchiveService) Delete(ctx context.Context, id string, actor *models.JW
 	if actor.Role != models.RoleSuperAdmin {
 		return appErrors.ErrForbidden
 	}
-	if err := s.repo.SoftDelete(ctx, id, time.Now().UTC()); err != nil {
+	if err := s.repo.SoftDelete(ctx, id, models.ArchiveDeletionManual, time.Now().UTC()); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return appErrors.ErrNotFound
 		}
@@ -303,6 +368,154 @@ func (s *ArchiveService) Delete(ctx context.Context, id string, actor *models.JW
 	return nil
 }
 
+// Trash lists soft-deleted archives, newest deletion first, with the time each will be purged.
+func (s *ArchiveService) Trash(ctx context.Context, filter dto.ArchiveFilter, actor *models.JWTClaims) ([]dto.ArchiveTrashItem, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if actor.Role != models.RoleSuperAdmin {
+		return nil, appErrors.ErrForbidden
+	}
+	items, err := s.repo.List(ctx, models.ArchiveFilter{
+		Scope:       filter.Scope,
+		Category:    filter.Category,
+		TermID:      filter.TermID,
+		ClassID:     filter.ClassID,
+		Search:      filter.Search,
+		OnlyDeleted: true,
+		Limit:       filter.Limit,
+		Offset:      filter.Offset,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list deleted archives")
+	}
+	trash := make([]dto.ArchiveTrashItem, 0, len(items))
+	for _, item := range items {
+		entry := dto.ArchiveTrashItem{ArchiveItem: item}
+		if item.DeletedAt != nil {
+			entry.PurgeAt = item.DeletedAt.Add(s.cfg.TrashGrace)
+		}
+		trash = append(trash, entry)
+	}
+	return trash, nil
+}
+
+// Restore brings a soft-deleted archive back from the trash. A restored archive starts a new
+// retention period so the retention job does not delete it again straight away.
+func (s *ArchiveService) Restore(ctx context.Context, id string, actor *models.JWTClaims) (*models.ArchiveItem, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if actor.Role != models.RoleSuperAdmin {
+		return nil, appErrors.ErrForbidden
+	}
+	if err := s.repo.Restore(ctx, id, time.Now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "archive not found in trash")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to restore archive")
+	}
+	s.emitAudit(ctx, &models.AuditLog{
+		UserID:     &actor.UserID,
+		Action:     models.AuditActionArchiveRestore,
+		Resource:   "archive",
+		ResourceID: &id,
+	})
+	return s.Get(ctx, id, actor)
+}
+
+// Usage reports the storage taken by live archives per category and for the largest uploaders,
+// alongside the configured quotas.
+func (s *ArchiveService) Usage(ctx context.Context, actor *models.JWTClaims) (*dto.ArchiveUsageReport, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if actor.Role != models.RoleAdmin && actor.Role != models.RoleSuperAdmin {
+		return nil, appErrors.ErrForbidden
+	}
+	byCategory, err := s.repo.UsageByCategory(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load archive usage")
+	}
+	byUploader, err := s.repo.UsageByUploader(ctx, archiveUsageUploaderLimit)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load archive usage")
+	}
+	report := &dto.ArchiveUsageReport{
+		GeneratedAt:               time.Now().UTC(),
+		MaxFileBytes:              s.cfg.MaxFileSize,
+		DefaultCategoryQuotaBytes: s.cfg.DefaultCategoryQuota,
+		UploaderQuotaBytes:        s.cfg.UploaderQuota,
+		Categories:                make([]dto.ArchiveQuotaUsage, 0, len(byCategory)),
+		Uploaders:                 make([]dto.ArchiveQuotaUsage, 0, len(byUploader)),
+	}
+	seen := make(map[string]bool, len(byCategory))
+	for _, usage := range byCategory {
+		seen[usage.Key] = true
+		report.TotalFiles += usage.Files
+		report.TotalBytes += usage.Bytes
+		report.Categories = append(report.Categories, newArchiveQuotaUsage(usage, s.categoryQuota(usage.Key)))
+	}
+	// Categories with a quota but nothing uploaded yet are listed too, so every limit is visible.
+	for category, quota := range s.cfg.CategoryQuotas {
+		if !seen[category] {
+			report.Categories = append(report.Categories, newArchiveQuotaUsage(models.ArchiveUsage{Key: category}, quota))
+		}
+	}
+	sort.SliceStable(report.Categories, func(i, j int) bool {
+		if report.Categories[i].Bytes != report.Categories[j].Bytes {
+			return report.Categories[i].Bytes > report.Categories[j].Bytes
+		}
+		return report.Categories[i].Key < report.Categories[j].Key
+	})
+	for _, usage := range byUploader {
+		report.Uploaders = append(report.Uploaders, newArchiveQuotaUsage(usage, s.cfg.UploaderQuota))
+	}
+	return report, nil
+}
+
+// checkQuota rejects an upload of size bytes that would take its category or uploader over quota.
+// Concurrent uploads are not serialised, so a quota can be overshot by at most one file each.
+func (s *ArchiveService) checkQuota(ctx context.Context, category, uploaderID string, size int64) error {
+	categoryQuota := s.categoryQuota(category)
+	if categoryQuota <= 0 && s.cfg.UploaderQuota <= 0 {
+		return nil
+	}
+	categoryBytes, uploaderBytes, err := s.repo.Usage(ctx, category, uploaderID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check archive quota")
+	}
+	if categoryQuota > 0 && categoryBytes+size > categoryQuota {
+		return appErrors.Clone(appErrors.ErrQuotaExceeded, fmt.Sprintf("category %s quota exceeded: %s used of %s, upload is %s",
+			category, formatMegabytes(categoryBytes), formatMegabytes(categoryQuota), formatMegabytes(size)))
+	}
+	if s.cfg.UploaderQuota > 0 && uploaderBytes+size > s.cfg.UploaderQuota {
+		return appErrors.Clone(appErrors.ErrQuotaExceeded, fmt.Sprintf("uploader quota exceeded: %s used of %s, upload is %s",
+			formatMegabytes(uploaderBytes), formatMegabytes(s.cfg.UploaderQuota), formatMegabytes(size)))
+	}
+	return nil
+}
+
+func (s *ArchiveService) categoryQuota(category string) int64 {
+	if quota, ok := s.cfg.CategoryQuotas[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return quota
+	}
+	return s.cfg.DefaultCategoryQuota
+}
+
+func newArchiveQuotaUsage(usage models.ArchiveUsage, quota int64) dto.ArchiveQuotaUsage {
+	result := dto.ArchiveQuotaUsage{Key: usage.Key, Files: usage.Files, Bytes: usage.Bytes}
+	if quota > 0 {
+		result.QuotaBytes = quota
+		result.UsedPercent = math.Round(float64(usage.Bytes)/float64(quota)*1000) / 10
+	}
+	return result
+}
+
+func formatMegabytes(bytes int64) string {
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+}
+
 func (s *ArchiveService) ensureAccess(ctx context.Context, item *models.ArchiveItem, actor *models.JWTClaims) error {
 	if actor == nil {
 		return appErrors.ErrUnauthorized
@@ -325,14 +538,16 @@ func (s *ArchiveService) ensureAccess(ctx context.Context, item *models.ArchiveI
 }
 
 type teacherScope struct {
-	ClassIDs map[string]struct{}
-	TermIDs  map[string]struct{}
+	TeacherID string
+	ClassIDs  map[string]struct{}
+	TermIDs   map[string]struct{}
 }
 
 func (s *ArchiveService) teacherScope(ctx context.Context, teacherID string) (*teacherScope, error) {
 	result := &teacherScope{
-		ClassIDs: map[string]struct{}{},
-		TermIDs:  map[string]struct{}{},
+		TeacherID: teacherID,
+		ClassIDs:  map[string]struct{}{},
+		TermIDs:   map[string]struct{}{},
 	}
 	if s.assignments == nil {
 		return result, nil
@@ -387,6 +602,8 @@ func (s *ArchiveService) canTeacherAccess(ctx context.Context, scope *teacherSco
 			}
 		}
 		return false
+	case models.ArchiveScopeMutation:
+		return item.UploadedBy == scope.TeacherID
 	default:
 		return false
 	}
@@ -414,6 +631,7 @@ func (s *ArchiveService) validateUploadMeta(meta dto.CreateArchiveRequest) error
 		if meta.RefStudentID == nil || *meta.RefStudentID == "" || meta.RefClassID == nil || *meta.RefClassID == "" {
 			return appErrors.Clone(appErrors.ErrValidation, "refStudentId and refClassId required for STUDENT scope")
 		}
+	case models.ArchiveScopeMutation, models.ArchiveScopeIntake:
 	default:
 		return appErrors.Clone(appErrors.ErrValidation, "invalid scope")
 	}
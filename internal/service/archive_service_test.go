@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/storage"
 )
 
@@ -55,14 +57,70 @@ func (r *archiveRepoStub) List(ctx context.Context, filter models.ArchiveFilter)
 	return result, nil
 }
 
-func (r *archiveRepoStub) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+func (r *archiveRepoStub) SoftDelete(ctx context.Context, id, reason string, deletedAt time.Time) error {
 	if item, ok := r.items[id]; ok {
 		item.DeletedAt = &deletedAt
+		item.DeletionReason = &reason
 		return nil
 	}
 	return fmt.Errorf("not found")
 }
 
+func (r *archiveRepoStub) Restore(ctx context.Context, id string, restoredAt time.Time) error {
+	item, ok := r.items[id]
+	if !ok || item.DeletedAt == nil {
+		return sql.ErrNoRows
+	}
+	item.DeletedAt = nil
+	item.DeletionReason = nil
+	item.RestoredAt = &restoredAt
+	return nil
+}
+
+func (r *archiveRepoStub) Usage(ctx context.Context, category, uploaderID string) (int64, int64, error) {
+	var categoryBytes, uploaderBytes int64
+	for _, item := range r.items {
+		if item.DeletedAt != nil {
+			continue
+		}
+		if strings.EqualFold(item.Category, category) {
+			categoryBytes += item.SizeBytes
+		}
+		if item.UploadedBy == uploaderID {
+			uploaderBytes += item.SizeBytes
+		}
+	}
+	return categoryBytes, uploaderBytes, nil
+}
+
+func (r *archiveRepoStub) UsageByCategory(ctx context.Context) ([]models.ArchiveUsage, error) {
+	return r.usageBy(func(item *models.ArchiveItem) string { return strings.ToLower(item.Category) }), nil
+}
+
+func (r *archiveRepoStub) UsageByUploader(ctx context.Context, limit int) ([]models.ArchiveUsage, error) {
+	return r.usageBy(func(item *models.ArchiveItem) string { return item.UploadedBy }), nil
+}
+
+func (r *archiveRepoStub) usageBy(key func(item *models.ArchiveItem) string) []models.ArchiveUsage {
+	index := make(map[string]int)
+	var usage []models.ArchiveUsage
+	for _, item := range r.items {
+		if item.DeletedAt != nil {
+			continue
+		}
+		k := key(item)
+		i, ok := index[k]
+		if !ok {
+			i = len(usage)
+			index[k] = i
+			usage = append(usage, models.ArchiveUsage{Key: k})
+		}
+		usage[i].Files++
+		usage[i].Bytes += item.SizeBytes
+	}
+	return usage
+}
+
 type storageStub struct {
 	saved map[string][]byte
 	files map[string]string
@@ -278,4 +336,128 @@ func TestArchiveServiceDownload(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "application/pdf", download.MimeType)
 	download.File.Close() //nolint:errcheck
+
+	_, err = svc.Preview(context.Background(), item.ID, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	require.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+	thumbnail := "archive/policy.thumb.png"
+	_, err = store.SaveStream(thumbnail, bytes.NewReader([]byte("png")))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Delete(thumbnail) })
+	item.ThumbnailPath = &thumbnail
+	preview, err := svc.Preview(context.Background(), item.ID, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", preview.MimeType)
+	require.EqualValues(t, 3, preview.SizeBytes)
+	preview.File.Close() //nolint:errcheck
+}
+
+func TestArchiveServiceTrashAndRestore(t *testing.T) {
+	repo := newArchiveRepoStub()
+	audit := &auditStub{}
+	svc := NewArchiveService(repo, nil, nil, newStorageStub(), nil, audit, nil, ArchiveServiceConfig{TrashGrace: 30 * 24 * time.Hour})
+	repo.items["arch-1"] = &models.ArchiveItem{ID: "arch-1", Title: "Policy", Category: "ADMIN", Scope: models.ArchiveScopeGlobal, UploadedAt: time.Now()}
+	super := &models.JWTClaims{UserID: "super-1", Role: models.RoleSuperAdmin}
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	require.NoError(t, svc.Delete(context.Background(), "arch-1", super))
+	trash, err := svc.Trash(context.Background(), dto.ArchiveFilter{}, super)
+	require.NoError(t, err)
+	require.True(t, repo.filter.OnlyDeleted)
+	require.Len(t, trash, 1)
+	require.Equal(t, repo.items["arch-1"].DeletedAt.Add(30*24*time.Hour), trash[0].PurgeAt)
+	_, err = svc.Trash(context.Background(), dto.ArchiveFilter{}, admin)
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Restore(context.Background(), "arch-1", admin)
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	restored, err := svc.Restore(context.Background(), "arch-1", super)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.NotNil(t, restored.RestoredAt)
+	require.Equal(t, models.AuditActionArchiveRestore, audit.logs[len(audit.logs)-1].Action)
+
+	_, err = svc.Restore(context.Background(), "arch-1", super)
+	require.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestArchiveServiceQuotas(t *testing.T) {
+	repo := newArchiveRepoStub()
+	repo.items["arch-old"] = &models.ArchiveItem{ID: "arch-old", Category: "ops", SizeBytes: 900, UploadedBy: "admin-1"}
+	repo.items["arch-other"] = &models.ArchiveItem{ID: "arch-other", Category: "Finance", SizeBytes: 300, UploadedBy: "admin-2"}
+	store := newStorageStub()
+	svc := NewArchiveService(repo, nil, nil, store, nil, nil, nil, ArchiveServiceConfig{
+		AllowedMIMEs:   []string{"application/pdf"},
+		CategoryQuotas: map[string]int64{"OPS": 1000, "exams": 5000},
+		UploaderQuota:  1200,
+	})
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	upload := func(category string, size int) error {
+		content := bytes.NewReader(append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("a"), size-9)...))
+		item, err := svc.Upload(context.Background(), dto.CreateArchiveRequest{Title: "Doc", Category: category, Scope: models.ArchiveScopeGlobal},
+			ArchiveUpload{Filename: "doc.pdf", Size: int64(content.Len()), Content: content}, admin)
+		if item != nil {
+			t.Cleanup(func() { _ = store.Delete(item.FilePath) })
+		}
+		return err
+	}
+
+	err := upload("Ops", 200)
+	require.Error(t, err)
+	require.Equal(t, appErrors.ErrQuotaExceeded.Code, appErrors.FromError(err).Code)
+	require.Contains(t, err.Error(), "category Ops quota exceeded")
+	require.Len(t, repo.items, 2)
+
+	err = upload("finance", 400)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "uploader quota exceeded")
+
+	require.NoError(t, upload("finance", 100))
+	deletedAt := time.Now()
+	repo.items["arch-old"].DeletedAt = &deletedAt
+	require.NoError(t, upload("ops", 200))
+
+	_, err = svc.Usage(context.Background(), &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	report, err := svc.Usage(context.Background(), admin)
+	require.NoError(t, err)
+	require.EqualValues(t, 600, report.TotalBytes)
+	require.Len(t, report.Categories, 3)
+	require.Equal(t, dto.ArchiveQuotaUsage{Key: "finance", Files: 2, Bytes: 400}, report.Categories[0])
+	require.Equal(t, dto.ArchiveQuotaUsage{Key: "ops", Files: 1, Bytes: 200, QuotaBytes: 1000, UsedPercent: 20}, report.Categories[1])
+	require.Equal(t, dto.ArchiveQuotaUsage{Key: "exams", QuotaBytes: 5000}, report.Categories[2])
+	require.EqualValues(t, 1200, report.Uploaders[0].QuotaBytes)
+}
+
+func TestArchiveServiceMutationScope(t *testing.T) {
+	repo := newArchiveRepoStub()
+	repo.items["evidence"] = &models.ArchiveItem{ID: "evidence", Scope: models.ArchiveScopeMutation, UploadedBy: "teacher-1"}
+	svc := NewArchiveService(repo, archiveAssignmentStub{}, nil, newStorageStub(), nil, nil, nil, ArchiveServiceConfig{})
+
+	_, err := svc.Get(context.Background(), "evidence", &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	_, err = svc.Get(context.Background(), "evidence", &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	content := bytes.NewReader([]byte("%PDF-1.4"))
+	_, err = svc.Upload(context.Background(), dto.CreateArchiveRequest{Title: "Evidence", Category: "ops", Scope: "mutation"},
+		ArchiveUpload{Filename: "evidence.pdf", Size: int64(content.Len()), Content: content},
+		&models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestArchiveServiceIntakeScope(t *testing.T) {
+	repo := newArchiveRepoStub()
+	repo.items["birth"] = &models.ArchiveItem{ID: "birth", Scope: models.ArchiveScopeIntake, UploadedBy: "admin-1"}
+	svc := NewArchiveService(repo, archiveAssignmentStub{}, nil, newStorageStub(), nil, nil, nil, ArchiveServiceConfig{})
+
+	_, err := svc.Get(context.Background(), "birth", &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	_, err = svc.Get(context.Background(), "birth", &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	content := bytes.NewReader([]byte("%PDF-1.4"))
+	_, err = svc.Upload(context.Background(), dto.CreateArchiveRequest{Title: "Birth certificate", Category: "intake", Scope: "intake"},
+		ArchiveUpload{Filename: "birth.pdf", Size: int64(content.Len()), Content: content},
+		&models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
 }
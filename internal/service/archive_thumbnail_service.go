@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	archiveThumbnailBatch = 20
+	archiveThumbnailMIME  = "application/pdf"
+	// maxArchiveThumbnailError bounds the renderer output stored with a failed attempt.
+	maxArchiveThumbnailError = 500
+)
+
+type archiveThumbnailStore interface {
+	ListPendingThumbnails(ctx context.Context, mimeType string, limit int) ([]models.ArchiveItem, error)
+	SetThumbnail(ctx context.Context, id string, path, failure *string, generatedAt time.Time) error
+}
+
+type archiveThumbnailFiles interface {
+	Path(filename string) string
+	Delete(filename string) error
+}
+
+type pdfThumbnailRenderer interface {
+	RenderFirstPage(ctx context.Context, src, dst string) error
+}
+
+// ArchiveThumbnailConfig controls the thumbnail job.
+type ArchiveThumbnailConfig struct {
+	Interval time.Duration
+	// Timeout bounds the rendering of a single document.
+	Timeout time.Duration
+}
+
+// ArchiveThumbnailService renders first-page previews of PDF archives in the background and stores
+// them next to the archive files, so clients can show document cards without downloading whole files.
+// Each archive is attempted once; failures are recorded and not retried.
+type ArchiveThumbnailService struct {
+	store    archiveThumbnailStore
+	files    archiveThumbnailFiles
+	renderer pdfThumbnailRenderer
+	logger   *zap.Logger
+	cfg      ArchiveThumbnailConfig
+	now      func() time.Time
+}
+
+// NewArchiveThumbnailService constructs the service.
+func NewArchiveThumbnailService(store archiveThumbnailStore, files archiveThumbnailFiles, renderer pdfThumbnailRenderer, logger *zap.Logger, cfg ArchiveThumbnailConfig) *ArchiveThumbnailService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &ArchiveThumbnailService{store: store, files: files, renderer: renderer, logger: logger, cfg: cfg, now: time.Now}
+}
+
+// Start generates pending thumbnails now and then every interval until ctx is cancelled.
+func (s *ArchiveThumbnailService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Run(ctx); err != nil {
+				s.logger.Warn("archive thumbnail run failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run renders the thumbnails of every pending PDF archive and returns how many were generated.
+func (s *ArchiveThumbnailService) Run(ctx context.Context) (int, error) {
+	generated := 0
+	for {
+		items, err := s.store.ListPendingThumbnails(ctx, archiveThumbnailMIME, archiveThumbnailBatch)
+		if err != nil {
+			return generated, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list archives pending thumbnails")
+		}
+		for _, item := range items {
+			if ctx.Err() != nil {
+				return generated, nil
+			}
+			ok, err := s.generate(ctx, item)
+			if err != nil {
+				// The attempt could not be recorded, so the archive would be listed again; stop here
+				// and let the next run retry.
+				return generated, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record archive thumbnail")
+			}
+			if ok {
+				generated++
+			}
+		}
+		if len(items) < archiveThumbnailBatch {
+			return generated, nil
+		}
+	}
+}
+
+// generate renders one thumbnail and records the attempt. It reports whether an image was written.
+func (s *ArchiveThumbnailService) generate(ctx context.Context, item models.ArchiveItem) (bool, error) {
+	thumbnail := archiveThumbnailName(item.FilePath)
+	renderCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	renderErr := s.renderer.RenderFirstPage(renderCtx, s.files.Path(item.FilePath), s.files.Path(thumbnail))
+	cancel()
+	if renderErr != nil && ctx.Err() != nil {
+		// Shutting down; leave the archive pending rather than recording a failure.
+		return false, nil
+	}
+	now := s.now().UTC()
+	if renderErr != nil {
+		s.logger.Warn("failed to render archive thumbnail", zap.String("archive_id", item.ID), zap.Error(renderErr))
+		failure := renderErr.Error()
+		if len(failure) > maxArchiveThumbnailError {
+			failure = failure[:maxArchiveThumbnailError]
+		}
+		return false, ignoreMissingArchive(s.store.SetThumbnail(ctx, item.ID, nil, &failure, now))
+	}
+	if err := s.store.SetThumbnail(ctx, item.ID, &thumbnail, nil, now); err != nil {
+		_ = s.files.Delete(thumbnail)
+		return false, ignoreMissingArchive(err)
+	}
+	return true, nil
+}
+
+// ignoreMissingArchive drops the error of an archive purged while its thumbnail was rendered.
+func ignoreMissingArchive(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// archiveThumbnailName places the thumbnail next to its archive file.
+func archiveThumbnailName(filePath string) string {
+	return strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".thumb.png"
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+)
+
+func (r *archiveRepoStub) ListPendingThumbnails(ctx context.Context, mimeType string, limit int) ([]models.ArchiveItem, error) {
+	var result []models.ArchiveItem
+	for _, item := range r.items {
+		if item.ThumbnailGeneratedAt == nil && item.DeletedAt == nil && item.MimeType == mimeType {
+			result = append(result, *item)
+		}
+	}
+	return result, nil
+}
+
+func (r *archiveRepoStub) SetThumbnail(ctx context.Context, id string, path, failure *string, generatedAt time.Time) error {
+	item, ok := r.items[id]
+	if !ok {
+		return nil
+	}
+	item.ThumbnailPath = path
+	item.ThumbnailError = failure
+	item.ThumbnailGeneratedAt = &generatedAt
+	return nil
+}
+
+type thumbnailRendererStub struct {
+	fail     map[string]bool
+	rendered []string
+}
+
+func (r *thumbnailRendererStub) RenderFirstPage(ctx context.Context, src, dst string) error {
+	r.rendered = append(r.rendered, src)
+	if r.fail[src] {
+		return errors.New("render thumbnail: Syntax Error")
+	}
+	return os.WriteFile(dst, []byte("png"), 0o600)
+}
+
+func TestArchiveThumbnailServiceRun(t *testing.T) {
+	files, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	repo := newArchiveRepoStub()
+	repo.items["pdf"] = &models.ArchiveItem{ID: "pdf", FilePath: "archive_ops_1.pdf", MimeType: "application/pdf"}
+	repo.items["broken"] = &models.ArchiveItem{ID: "broken", FilePath: "archive_ops_2.pdf", MimeType: "application/pdf"}
+	repo.items["zip"] = &models.ArchiveItem{ID: "zip", FilePath: "archive_ops_3.zip", MimeType: "application/zip"}
+	renderer := &thumbnailRendererStub{fail: map[string]bool{files.Path("archive_ops_2.pdf"): true}}
+	svc := NewArchiveThumbnailService(repo, files, renderer, nil, ArchiveThumbnailConfig{})
+
+	generated, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+	require.NotNil(t, repo.items["pdf"].ThumbnailPath)
+	assert.Equal(t, "archive_ops_1.thumb.png", *repo.items["pdf"].ThumbnailPath)
+	assert.FileExists(t, files.Path("archive_ops_1.thumb.png"))
+	assert.Nil(t, repo.items["broken"].ThumbnailPath)
+	require.NotNil(t, repo.items["broken"].ThumbnailError)
+	assert.Contains(t, *repo.items["broken"].ThumbnailError, "Syntax Error")
+	assert.Nil(t, repo.items["zip"].ThumbnailGeneratedAt)
+
+	// Failed attempts are recorded, so nothing is rendered again.
+	generated, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, generated)
+	assert.Len(t, renderer.rendered, 2)
+}
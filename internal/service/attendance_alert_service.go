@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type attendanceAlertStore interface {
+	ListThresholds(ctx context.Context, termID string) ([]models.AttendanceAlertThreshold, error)
+	UpsertThreshold(ctx context.Context, threshold *models.AttendanceAlertThreshold) error
+	DeleteThreshold(ctx context.Context, classID, termID string) error
+	StudentRates(ctx context.Context, termID string, formula models.AttendanceRateFormula) ([]models.StudentAttendanceRate, error)
+	ReplaceAlerts(ctx context.Context, termID string, alerts []models.AttendanceAlert, now time.Time) ([]models.AttendanceAlert, error)
+	ListAlerts(ctx context.Context, filter models.AttendanceAlertFilter) ([]models.AttendanceAlert, error)
+}
+
+type homeroomLister interface {
+	List(ctx context.Context, filter dto.HomeroomFilter) ([]dto.HomeroomItem, error)
+}
+
+// AttendanceAlertConfig tunes the nightly attendance threshold evaluation.
+type AttendanceAlertConfig struct {
+	// DefaultThreshold is the minimum attendance percentage for classes without an override.
+	DefaultThreshold float64
+	// MinDays is how many daily marks a student needs before they can breach a threshold, so the
+	// first absences of a term do not raise alerts.
+	MinDays int
+	// RunAt is the "HH:MM" local time of the nightly evaluation.
+	RunAt    string
+	Location *time.Location
+}
+
+// AttendanceAlertServiceParams groups constructor dependencies.
+type AttendanceAlertServiceParams struct {
+	Store   attendanceAlertStore
+	Terms   ports.TermResolver
+	Classes ports.ClassReader
+	// Homerooms and Notifications are optional; without them breaches are stored but nobody is notified.
+	Homerooms     homeroomLister
+	Notifications notificationWriter
+	// Rates decides which statuses count as present; without it only H does.
+	Rates     *AttendanceRates
+	Validator *validator.Validate
+	Logger    *zap.Logger
+	Config    AttendanceAlertConfig
+}
+
+// AttendanceAlertService manages per class attendance thresholds and evaluates them every night.
+// Breaches are stored so dashboards read them instead of recomputing attendance, and the homeroom
+// teacher of each class is notified when students newly fall below its threshold.
+type AttendanceAlertService struct {
+	store         attendanceAlertStore
+	terms         ports.TermResolver
+	classes       ports.ClassReader
+	homerooms     homeroomLister
+	notifications notificationWriter
+	rates         *AttendanceRates
+	validator     *validator.Validate
+	logger        *zap.Logger
+	cfg           AttendanceAlertConfig
+	runAt         time.Duration
+	now           func() time.Time
+}
+
+// NewAttendanceAlertService validates cfg and constructs the service.
+func NewAttendanceAlertService(params AttendanceAlertServiceParams) (*AttendanceAlertService, error) {
+	cfg := params.Config
+	if cfg.DefaultThreshold <= 0 {
+		cfg.DefaultThreshold = 85
+	}
+	if cfg.DefaultThreshold > 100 {
+		return nil, fmt.Errorf("default attendance threshold must be at most 100")
+	}
+	if cfg.MinDays <= 0 {
+		cfg.MinDays = 5
+	}
+	if cfg.RunAt == "" {
+		cfg.RunAt = "01:00"
+	}
+	clock, err := time.Parse("15:04", cfg.RunAt)
+	if err != nil {
+		return nil, fmt.Errorf("attendance alert run time must use HH:MM: %w", err)
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AttendanceAlertService{
+		store:         params.Store,
+		terms:         params.Terms,
+		classes:       params.Classes,
+		homerooms:     params.Homerooms,
+		rates:         params.Rates,
+		notifications: params.Notifications,
+		validator:     validate,
+		logger:        logger,
+		cfg:           cfg,
+		runAt:         time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute,
+		now:           time.Now,
+	}, nil
+}
+
+// Thresholds returns the class overrides of a term, defaulting to the active term.
+func (s *AttendanceAlertService) Thresholds(ctx context.Context, termID string) (*dto.AttendanceThresholdsResponse, error) {
+	termID, err := s.resolveTerm(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := s.store.ListThresholds(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list attendance thresholds")
+	}
+	if thresholds == nil {
+		thresholds = []models.AttendanceAlertThreshold{}
+	}
+	return &dto.AttendanceThresholdsResponse{TermID: termID, DefaultPercentage: s.cfg.DefaultThreshold, Classes: thresholds}, nil
+}
+
+// SetThreshold creates or replaces the threshold of a class for a term.
+func (s *AttendanceAlertService) SetThreshold(ctx context.Context, req dto.SetAttendanceThresholdRequest, actor *models.JWTClaims) (*models.AttendanceAlertThreshold, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid attendance threshold payload")
+	}
+	if _, err := s.classes.FindByID(ctx, req.ClassID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "class not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class")
+	}
+	if _, err := s.resolveTerm(ctx, req.TermID); err != nil {
+		return nil, err
+	}
+	threshold := &models.AttendanceAlertThreshold{
+		ClassID:       req.ClassID,
+		TermID:        req.TermID,
+		MinPercentage: req.MinPercentage,
+		UpdatedBy:     &actor.UserID,
+	}
+	if err := s.store.UpsertThreshold(ctx, threshold); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save attendance threshold")
+	}
+	return threshold, nil
+}
+
+// DeleteThreshold removes a class override so the default threshold applies again.
+func (s *AttendanceAlertService) DeleteThreshold(ctx context.Context, classID, termID string) error {
+	if termID == "" {
+		return appErrors.Clone(appErrors.ErrValidation, "termId is required")
+	}
+	if err := s.store.DeleteThreshold(ctx, classID, termID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "attendance threshold not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete attendance threshold")
+	}
+	return nil
+}
+
+// Alerts lists the breaches found by the last evaluation. Teachers only see classes they teach.
+func (s *AttendanceAlertService) Alerts(ctx context.Context, query dto.AttendanceAlertQuery, claims *models.JWTClaims) ([]models.AttendanceAlert, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	termID, err := s.resolveTerm(ctx, query.TermID)
+	if err != nil {
+		return nil, err
+	}
+	filter := models.AttendanceAlertFilter{TermID: termID}
+	if query.ClassID != "" {
+		filter.ClassIDs = []string{query.ClassID}
+	}
+	if claims.Role == models.RoleTeacher {
+		filter.TeacherID = claims.UserID
+	}
+	alerts, err := s.store.ListAlerts(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list attendance alerts")
+	}
+	if alerts == nil {
+		alerts = []models.AttendanceAlert{}
+	}
+	return alerts, nil
+}
+
+// Start evaluates thresholds every night at the configured time until ctx is done.
+func (s *AttendanceAlertService) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *AttendanceAlertService) run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(s.nextRun(s.now()).Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := s.Evaluate(ctx); err != nil {
+				s.logger.Warn("attendance alert evaluation failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// nextRun returns the first run time strictly after now.
+func (s *AttendanceAlertService) nextRun(now time.Time) time.Time {
+	local := now.In(s.cfg.Location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.cfg.Location).Add(s.runAt)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Evaluate compares every student's attendance in the active term with their class threshold,
+// stores the breaches and notifies homeroom teachers about students who newly fell below it.
+func (s *AttendanceAlertService) Evaluate(ctx context.Context) (*dto.AttendanceAlertRun, error) {
+	termID, err := s.resolveTerm(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := s.store.ListThresholds(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list attendance thresholds")
+	}
+	classThreshold := make(map[string]float64, len(thresholds))
+	for _, threshold := range thresholds {
+		classThreshold[threshold.ClassID] = threshold.MinPercentage
+	}
+	rates, err := s.store.StudentRates(ctx, termID, s.rates.Formula(ctx))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to compute student attendance")
+	}
+
+	alerts := make([]models.AttendanceAlert, 0)
+	for _, rate := range rates {
+		if rate.TotalDays < s.cfg.MinDays {
+			continue
+		}
+		threshold, ok := classThreshold[rate.ClassID]
+		if !ok {
+			threshold = s.cfg.DefaultThreshold
+		}
+		percentage := math.Round(rate.Percentage*100) / 100
+		if percentage >= threshold {
+			continue
+		}
+		alerts = append(alerts, models.AttendanceAlert{
+			EnrollmentID: rate.EnrollmentID,
+			StudentID:    rate.StudentID,
+			ClassID:      rate.ClassID,
+			TermID:       termID,
+			Percentage:   percentage,
+			Threshold:    threshold,
+			PresentDays:  rate.PresentDays,
+			TotalDays:    rate.TotalDays,
+		})
+	}
+
+	now := s.now().UTC()
+	created, err := s.store.ReplaceAlerts(ctx, termID, alerts, now)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store attendance alerts")
+	}
+	run := &dto.AttendanceAlertRun{TermID: termID, Evaluated: len(rates), Open: len(alerts), New: len(created)}
+	run.Notified = s.notifyHomerooms(ctx, termID, created, now)
+	s.logger.Info("attendance alerts evaluated",
+		zap.String("term_id", termID),
+		zap.Int("open", run.Open),
+		zap.Int("new", run.New),
+		zap.Int("notified", run.Notified))
+	return run, nil
+}
+
+// notifyHomerooms sends one notification per class with new breaches to its homeroom teacher and
+// returns how many were written.
+func (s *AttendanceAlertService) notifyHomerooms(ctx context.Context, termID string, created []models.AttendanceAlert, now time.Time) int {
+	if len(created) == 0 || s.homerooms == nil || s.notifications == nil {
+		return 0
+	}
+	homerooms, err := s.homerooms.List(ctx, dto.HomeroomFilter{TermID: termID})
+	if err != nil {
+		s.logger.Warn("attendance alerts could not list homerooms", zap.Error(err))
+		return 0
+	}
+	byClass := make(map[string]dto.HomeroomItem, len(homerooms))
+	for _, homeroom := range homerooms {
+		byClass[homeroom.ClassID] = homeroom
+	}
+	newByClass := make(map[string]int)
+	for _, alert := range created {
+		newByClass[alert.ClassID]++
+	}
+	classIDs := make([]string, 0, len(newByClass))
+	for classID := range newByClass {
+		classIDs = append(classIDs, classID)
+	}
+	sort.Strings(classIDs)
+
+	day := now.In(s.cfg.Location).Format("2006-01-02")
+	sent := 0
+	for _, classID := range classIDs {
+		homeroom, ok := byClass[classID]
+		if !ok || homeroom.HomeroomTeacherID == nil || *homeroom.HomeroomTeacherID == "" {
+			continue
+		}
+		className := strings.TrimSpace(homeroom.ClassName)
+		if className == "" {
+			className = classID
+		}
+		key := fmt.Sprintf("attendance-alert:%s:%s", classID, day)
+		ref := classID
+		written, err := s.notifications.CreateOnce(ctx, &models.Notification{
+			UserID:    *homeroom.HomeroomTeacherID,
+			Type:      models.NotificationTypeAttendanceAlert,
+			Title:     "Attendance below threshold",
+			Body:      fmt.Sprintf("%d student(s) in %s fell below the attendance threshold.", newByClass[classID], className),
+			RefID:     &ref,
+			DedupeKey: &key,
+		})
+		if err != nil {
+			s.logger.Warn("attendance alert notification failed", zap.String("class_id", classID), zap.Error(err))
+			continue
+		}
+		if written {
+			sent++
+		}
+	}
+	return sent
+}
+
+func (s *AttendanceAlertService) resolveTerm(ctx context.Context, termID string) (string, error) {
+	if termID != "" {
+		if _, err := s.terms.FindByID(ctx, termID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", appErrors.Clone(appErrors.ErrNotFound, "term not found")
+			}
+			return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+		}
+		return termID, nil
+	}
+	term, err := s.terms.FindActive(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", appErrors.Clone(appErrors.ErrNotFound, "active term not found")
+		}
+		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load active term")
+	}
+	return term.ID, nil
+}
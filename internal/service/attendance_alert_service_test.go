@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type attendanceAlertStoreStub struct {
+	thresholds []models.AttendanceAlertThreshold
+	rates      []models.StudentAttendanceRate
+	open       map[string]models.AttendanceAlert
+	saved      *models.AttendanceAlertThreshold
+	filter     models.AttendanceAlertFilter
+}
+
+func (s *attendanceAlertStoreStub) ListThresholds(ctx context.Context, termID string) ([]models.AttendanceAlertThreshold, error) {
+	return s.thresholds, nil
+}
+
+func (s *attendanceAlertStoreStub) UpsertThreshold(ctx context.Context, threshold *models.AttendanceAlertThreshold) error {
+	threshold.ID = "threshold-1"
+	s.saved = threshold
+	return nil
+}
+
+func (s *attendanceAlertStoreStub) DeleteThreshold(ctx context.Context, classID, termID string) error {
+	return sql.ErrNoRows
+}
+
+func (s *attendanceAlertStoreStub) StudentRates(ctx context.Context, termID string, formula models.AttendanceRateFormula) ([]models.StudentAttendanceRate, error) {
+	return s.rates, nil
+}
+
+func (s *attendanceAlertStoreStub) ReplaceAlerts(ctx context.Context, termID string, alerts []models.AttendanceAlert, now time.Time) ([]models.AttendanceAlert, error) {
+	next := make(map[string]models.AttendanceAlert, len(alerts))
+	var created []models.AttendanceAlert
+	for _, alert := range alerts {
+		if _, ok := s.open[alert.EnrollmentID]; !ok {
+			created = append(created, alert)
+		}
+		next[alert.EnrollmentID] = alert
+	}
+	s.open = next
+	return created, nil
+}
+
+func (s *attendanceAlertStoreStub) ListAlerts(ctx context.Context, filter models.AttendanceAlertFilter) ([]models.AttendanceAlert, error) {
+	s.filter = filter
+	return nil, nil
+}
+
+type homeroomListerStub struct{ items []dto.HomeroomItem }
+
+func (s homeroomListerStub) List(ctx context.Context, filter dto.HomeroomFilter) ([]dto.HomeroomItem, error) {
+	return s.items, nil
+}
+
+func newAttendanceAlertServiceForTest(t *testing.T, store *attendanceAlertStoreStub, notifications *notificationWriterStub) *AttendanceAlertService {
+	t.Helper()
+	teacher := "teacher-1"
+	svc, err := NewAttendanceAlertService(AttendanceAlertServiceParams{
+		Store:   store,
+		Terms:   termReaderStub{active: &models.Term{ID: "term-1"}},
+		Classes: classReaderStub{},
+		Homerooms: homeroomListerStub{items: []dto.HomeroomItem{
+			{ClassID: "class-a", ClassName: "X IPA 1", TermID: "term-1", HomeroomTeacherID: &teacher},
+			{ClassID: "class-b", ClassName: "X IPA 2", TermID: "term-1"},
+		}},
+		Notifications: notifications,
+		Config:        AttendanceAlertConfig{DefaultThreshold: 85, MinDays: 5},
+	})
+	require.NoError(t, err)
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestAttendanceAlertServiceEvaluate(t *testing.T) {
+	store := &attendanceAlertStoreStub{
+		thresholds: []models.AttendanceAlertThreshold{{ClassID: "class-b", MinPercentage: 70}},
+		rates: []models.StudentAttendanceRate{
+			{EnrollmentID: "enr-1", StudentID: "student-1", ClassID: "class-a", PresentDays: 16, TotalDays: 20, Percentage: 80},
+			{EnrollmentID: "enr-2", StudentID: "student-2", ClassID: "class-a", PresentDays: 18, TotalDays: 20, Percentage: 90},
+			// Too few marks to judge yet.
+			{EnrollmentID: "enr-3", StudentID: "student-3", ClassID: "class-a", PresentDays: 1, TotalDays: 3, Percentage: 33.3333},
+			// Below the default but above the class override.
+			{EnrollmentID: "enr-4", StudentID: "student-4", ClassID: "class-b", PresentDays: 15, TotalDays: 20, Percentage: 75},
+			{EnrollmentID: "enr-5", StudentID: "student-5", ClassID: "class-b", PresentDays: 13, TotalDays: 20, Percentage: 65},
+		},
+	}
+	notifications := &notificationWriterStub{}
+	svc := newAttendanceAlertServiceForTest(t, store, notifications)
+
+	run, err := svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, dto.AttendanceAlertRun{TermID: "term-1", Evaluated: 5, Open: 2, New: 2, Notified: 1}, *run)
+	require.Contains(t, store.open, "enr-1")
+	assert.Equal(t, 85.0, store.open["enr-1"].Threshold)
+	assert.Equal(t, 70.0, store.open["enr-5"].Threshold)
+
+	// class-b has no homeroom teacher, so only class-a's teacher hears about it.
+	require.Len(t, notifications.sent, 1)
+	sent := notifications.sent[0]
+	assert.Equal(t, "teacher-1", sent.UserID)
+	assert.Equal(t, models.NotificationTypeAttendanceAlert, sent.Type)
+	assert.Equal(t, "1 student(s) in X IPA 1 fell below the attendance threshold.", sent.Body)
+	assert.Equal(t, "class-a", *sent.RefID)
+
+	// Open alerts are refreshed without notifying again.
+	run, err = svc.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, run.New)
+	assert.Len(t, notifications.sent, 1)
+}
+
+func TestAttendanceAlertServiceThresholds(t *testing.T) {
+	store := &attendanceAlertStoreStub{}
+	svc := newAttendanceAlertServiceForTest(t, store, &notificationWriterStub{})
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	_, err := svc.SetThreshold(context.Background(), dto.SetAttendanceThresholdRequest{ClassID: "class-a", TermID: "term-1", MinPercentage: 120}, admin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	threshold, err := svc.SetThreshold(context.Background(), dto.SetAttendanceThresholdRequest{ClassID: "class-a", TermID: "term-1", MinPercentage: 80}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "threshold-1", threshold.ID)
+	assert.Equal(t, "admin-1", *store.saved.UpdatedBy)
+
+	list, err := svc.Thresholds(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "term-1", list.TermID)
+	assert.Equal(t, 85.0, list.DefaultPercentage)
+	assert.NotNil(t, list.Classes)
+
+	err = svc.DeleteThreshold(context.Background(), "class-a", "term-1")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestAttendanceAlertServiceAlertsScopesTeachers(t *testing.T) {
+	store := &attendanceAlertStoreStub{}
+	svc := newAttendanceAlertServiceForTest(t, store, &notificationWriterStub{})
+
+	alerts, err := svc.Alerts(context.Background(), dto.AttendanceAlertQuery{ClassID: "class-a"}, &models.JWTClaims{UserID: "teacher-9", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.NotNil(t, alerts)
+	assert.Equal(t, models.AttendanceAlertFilter{TermID: "term-1", ClassIDs: []string{"class-a"}, TeacherID: "teacher-9"}, store.filter)
+}
+
+func TestAttendanceAlertServiceNextRun(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	svc, err := NewAttendanceAlertService(AttendanceAlertServiceParams{Config: AttendanceAlertConfig{RunAt: "01:30", Location: jakarta}})
+	require.NoError(t, err)
+
+	// 20:00 WIB: tonight's run is still ahead.
+	next := svc.nextRun(time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 11, 1, 30, 0, 0, jakarta), next)
+	// 01:30 WIB exactly: the next run is tomorrow.
+	next = svc.nextRun(time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 12, 1, 30, 0, 0, jakarta), next)
+
+	_, err = NewAttendanceAlertService(AttendanceAlertServiceParams{Config: AttendanceAlertConfig{RunAt: "25:00"}})
+	assert.Error(t, err)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
@@ -19,9 +21,11 @@ type analyticsAttendanceProvider interface {
 
 type attendanceSummaryRepository interface {
 	Aggregate(ctx context.Context, filter repository.AttendanceAliasFilter) (*repository.AttendanceAliasAggregate, error)
+	Roster(ctx context.Context, filter repository.AttendanceAliasRosterFilter) ([]repository.AttendanceAliasDayRow, error)
 }
 
 type aliasEnrollmentReader interface {
+	FindByID(ctx context.Context, id string) (*models.Enrollment, error)
 	ListByClassAndTerm(ctx context.Context, classID, termID string) ([]models.Enrollment, error)
 	FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error)
 }
@@ -31,10 +35,6 @@ type teacherAssignmentAccessor interface {
 	HasClassAccess(ctx context.Context, teacherID, classID, termID string) (bool, error)
 }
 
-type termLookup interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-}
-
 // AttendanceAliasService exposes /attendance and /attendance/daily adapters.
 type AttendanceAliasService struct {
 	attendance  *AttendanceService
@@ -42,7 +42,7 @@ type AttendanceAliasService struct {
 	summaries   attendanceSummaryRepository
 	assignments teacherAssignmentAccessor
 	enrollments aliasEnrollmentReader
-	terms       termLookup
+	terms       ports.TermReader
 	logger      *zap.Logger
 }
 
@@ -53,7 +53,7 @@ func NewAttendanceAliasService(
 	summaries attendanceSummaryRepository,
 	assignments teacherAssignmentAccessor,
 	enrollments aliasEnrollmentReader,
-	terms termLookup,
+	terms ports.TermReader,
 	logger *zap.Logger,
 ) *AttendanceAliasService {
 	if logger == nil {
@@ -173,31 +173,35 @@ func (s *AttendanceAliasService) Summary(ctx context.Context, req dto.Attendance
 		response.Scope.StudentID = &req.StudentID
 	}
 
-	totalRecords := aggregate.Present + aggregate.Sick + aggregate.Excused + aggregate.Absent
-	var rate float64
-	if totalRecords > 0 {
-		rate = float64(aggregate.Present) / float64(totalRecords) * 100
-	}
+	formula := s.rateFormula(ctx)
 	response.Summary = dto.AttendanceSummaryStats{
-		TotalDays:      aggregate.TotalDays,
-		Present:        aggregate.Present,
-		Sick:           aggregate.Sick,
-		Excused:        aggregate.Excused,
-		Absent:         aggregate.Absent,
-		AttendanceRate: rate,
+		TotalDays:          aggregate.TotalDays,
+		Present:            aggregate.Present,
+		Sick:               aggregate.Sick,
+		Excused:            aggregate.Excused,
+		Absent:             aggregate.Absent,
+		SchoolDuty:         aggregate.SchoolDuty,
+		ExcusedAbsences:    aggregate.Sick + aggregate.Excused,
+		UnexcusedAbsences:  aggregate.Absent,
+		DocumentedAbsences: aggregate.Documented,
+		AttendanceRate:     formula.Rate(models.AttendanceStatusCounts{Present: aggregate.Present, Sick: aggregate.Sick, Excused: aggregate.Excused, Absent: aggregate.Absent}),
 	}
 
 	perStudent := make([]dto.AttendanceSummaryStudent, 0, len(aggregate.Students))
 	for _, row := range aggregate.Students {
 		perStudent = append(perStudent, dto.AttendanceSummaryStudent{
-			StudentID:      row.StudentID,
-			StudentName:    row.StudentName,
-			ClassID:        row.ClassID,
-			Present:        row.Present,
-			Sick:           row.Sick,
-			Excused:        row.Excused,
-			Absent:         row.Absent,
-			AttendanceRate: row.Rate,
+			StudentID:          row.StudentID,
+			StudentName:        row.StudentName,
+			ClassID:            row.ClassID,
+			Present:            row.Present,
+			Sick:               row.Sick,
+			Excused:            row.Excused,
+			Absent:             row.Absent,
+			SchoolDuty:         row.SchoolDuty,
+			ExcusedAbsences:    row.Sick + row.Excused,
+			UnexcusedAbsences:  row.Absent,
+			DocumentedAbsences: row.Documented,
+			AttendanceRate:     formula.Rate(models.AttendanceStatusCounts{Present: row.Present, Sick: row.Sick, Excused: row.Excused, Absent: row.Absent}),
 		})
 	}
 	response.PerStudent = perStudent
@@ -213,6 +217,216 @@ func (s *AttendanceAliasService) Summary(ctx context.Context, req dto.Attendance
 	return &response, cacheHit, nil
 }
 
+// Excuse records the reason and evidence for a student's absence. Teachers may only excuse students
+// of classes they teach in the enrollment's term.
+func (s *AttendanceAliasService) Excuse(ctx context.Context, req dto.AttendanceExcuseRequest, claims *models.JWTClaims) (*models.DailyAttendance, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if req.EnrollmentID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "enrollmentId is required")
+	}
+	enrollment, err := s.enrollments.FindByID(ctx, req.EnrollmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "enrollment not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+	}
+	if claims.Role == models.RoleTeacher {
+		if err := s.assertClassAccess(ctx, claims.UserID, enrollment.ClassID, enrollment.TermID); err != nil {
+			return nil, err
+		}
+	}
+	return s.attendance.ExcuseAbsence(ctx, enrollment, req, claims.UserID)
+}
+
+// Monthly pivots a class's daily attendance for one month into per-student, per-day statuses.
+func (s *AttendanceAliasService) Monthly(ctx context.Context, req dto.AttendanceMonthlyRequest, claims *models.JWTClaims) (*dto.AttendanceMonthlyResponse, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if req.ClassID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "classId is required")
+	}
+	start, err := time.Parse("2006-01", req.Month)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid month, expected YYYY-MM")
+	}
+	end := start.AddDate(0, 1, -1)
+	if req.TermID != "" {
+		if err := s.ensureTerm(ctx, req.TermID); err != nil {
+			return nil, err
+		}
+	}
+
+	if claims.Role == models.RoleTeacher {
+		if req.TermID != "" {
+			if err := s.assertClassAccess(ctx, claims.UserID, req.ClassID, req.TermID); err != nil {
+				return nil, err
+			}
+		} else if err := s.assertAnyClassAssignment(ctx, claims.UserID, req.ClassID); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.summaries.Roster(ctx, repository.AttendanceAliasRosterFilter{
+		TermID:   req.TermID,
+		ClassID:  req.ClassID,
+		DateFrom: &start,
+		DateTo:   &end,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load monthly attendance")
+	}
+
+	response := &dto.AttendanceMonthlyResponse{
+		ClassID:  req.ClassID,
+		Month:    start.Format("2006-01"),
+		Students: []dto.AttendanceMonthlyStudent{},
+	}
+	if req.TermID != "" {
+		response.TermID = &req.TermID
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		response.Dates = append(response.Dates, day.Format("2006-01-02"))
+	}
+
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.StudentID]
+		if !ok {
+			i = len(response.Students)
+			index[row.StudentID] = i
+			response.Students = append(response.Students, dto.AttendanceMonthlyStudent{
+				StudentID:   row.StudentID,
+				StudentName: row.StudentName,
+				NIS:         row.NIS,
+				Days:        map[string]string{},
+			})
+		}
+		if row.Date == nil || row.Status == nil {
+			continue
+		}
+		student := &response.Students[i]
+		student.Days[row.Date.Format("2006-01-02")] = *row.Status
+		tallyLegacySummary(&student.Summary, *row.Status)
+	}
+	formula := s.rateFormula(ctx)
+	for i := range response.Students {
+		finishLegacySummary(&response.Students[i].Summary, formula)
+	}
+	return response, nil
+}
+
+// StudentHistory returns a student's daily attendance in a term using the legacy response shape.
+func (s *AttendanceAliasService) StudentHistory(ctx context.Context, req dto.AttendanceStudentRequest, claims *models.JWTClaims) (*dto.AttendanceStudentResponse, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if req.StudentID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "student id is required")
+	}
+	if req.TermID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "termId is required")
+	}
+	if err := s.ensureTerm(ctx, req.TermID); err != nil {
+		return nil, err
+	}
+	if claims.Role == models.RoleTeacher {
+		if err := s.ensureTeacherCanSeeStudent(ctx, claims.UserID, req.StudentID, req.TermID); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.summaries.Roster(ctx, repository.AttendanceAliasRosterFilter{
+		TermID:    req.TermID,
+		StudentID: req.StudentID,
+		DateFrom:  req.StartDate,
+		DateTo:    req.EndDate,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student attendance")
+	}
+	if len(rows) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "student is not enrolled in term")
+	}
+	return studentHistoryFromRows(req.TermID, rows, s.rateFormula(ctx)), nil
+}
+
+// studentHistoryFromRows folds one student's roster rows into the legacy history payload. rows must
+// not be empty.
+func studentHistoryFromRows(termID string, rows []repository.AttendanceAliasDayRow, formula models.AttendanceRateFormula) *dto.AttendanceStudentResponse {
+	response := &dto.AttendanceStudentResponse{
+		StudentID:   rows[0].StudentID,
+		StudentName: rows[0].StudentName,
+		NIS:         rows[0].NIS,
+		TermID:      termID,
+		Records:     []dto.AttendanceStudentRecord{},
+	}
+	// Rows are ordered oldest first; the legacy payload lists the most recent day first.
+	for i := len(rows) - 1; i >= 0; i-- {
+		row := rows[i]
+		if row.Date == nil || row.Status == nil {
+			continue
+		}
+		response.Records = append(response.Records, dto.AttendanceStudentRecord{
+			Date:   row.Date.Format("2006-01-02"),
+			Status: *row.Status,
+			Reason: row.AbsenceReason,
+			Notes:  row.Notes,
+		})
+		tallyLegacySummary(&response.Summary, *row.Status)
+	}
+	finishLegacySummary(&response.Summary, formula)
+	return response
+}
+
+func tallyLegacySummary(summary *dto.AttendanceLegacySummary, status string) {
+	summary.TotalDays++
+	switch models.AttendanceStatus(status) {
+	case models.AttendanceStatusPresent:
+		summary.Present++
+	case models.AttendanceStatusSick:
+		summary.Sick++
+	case models.AttendanceStatusExcused:
+		summary.Permission++
+	case models.AttendanceStatusAbsent:
+		summary.Absent++
+	}
+}
+
+func finishLegacySummary(summary *dto.AttendanceLegacySummary, formula models.AttendanceRateFormula) {
+	summary.Percentage = formula.Rate(models.AttendanceStatusCounts{
+		Present: summary.Present,
+		Sick:    summary.Sick,
+		Excused: summary.Permission,
+		Absent:  summary.Absent,
+	})
+}
+
+// rateFormula is the formula of the wrapped AttendanceService, so the legacy payloads match the
+// native ones.
+func (s *AttendanceAliasService) rateFormula(ctx context.Context) models.AttendanceRateFormula {
+	if s.attendance == nil {
+		return models.DefaultAttendanceRateFormula
+	}
+	return s.attendance.RateFormula(ctx)
+}
+
+func (s *AttendanceAliasService) assertAnyClassAssignment(ctx context.Context, teacherID, classID string) error {
+	assignments, err := s.assignments.ListByTeacher(ctx, teacherID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to resolve assignments")
+	}
+	for _, assignment := range assignments {
+		if assignment.ClassID == classID {
+			return nil
+		}
+	}
+	return appErrors.ErrForbidden
+}
+
 func (s *AttendanceAliasService) ensureTerm(ctx context.Context, termID string) error {
 	if _, err := s.terms.FindByID(ctx, termID); err != nil {
 		if err == sql.ErrNoRows {
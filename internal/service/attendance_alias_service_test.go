@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,9 +17,14 @@ import (
 
 type attendanceSummaryRepoStub struct {
 	aggregate *repository.AttendanceAliasAggregate
+	roster    []repository.AttendanceAliasDayRow
 	err       error
 }
 
+func (s attendanceSummaryRepoStub) Roster(ctx context.Context, filter repository.AttendanceAliasRosterFilter) ([]repository.AttendanceAliasDayRow, error) {
+	return s.roster, s.err
+}
+
 func (s attendanceSummaryRepoStub) Aggregate(ctx context.Context, filter repository.AttendanceAliasFilter) (*repository.AttendanceAliasAggregate, error) {
 	if s.err != nil {
 		return nil, s.err
@@ -39,6 +46,13 @@ func (assignmentAccessStub) HasClassAccess(ctx context.Context, teacherID, class
 
 type enrollmentReaderStub struct{}
 
+func (enrollmentReaderStub) FindByID(ctx context.Context, id string) (*models.Enrollment, error) {
+	if id == "missing" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Enrollment{ID: id, StudentID: "stu-1", ClassID: "class-1", TermID: "term-1"}, nil
+}
+
 func (enrollmentReaderStub) ListByClassAndTerm(ctx context.Context, classID, termID string) ([]models.Enrollment, error) {
 	return nil, nil
 }
@@ -84,6 +98,9 @@ func TestAttendanceAliasServiceSummary(t *testing.T) {
 	require.Len(t, resp.PerStudent, 2)
 	assert.InDelta(t, 75, resp.Summary.AttendanceRate, 0.1)
 	assert.Equal(t, 3, resp.Summary.Absent)
+	assert.Equal(t, 3, resp.Summary.ExcusedAbsences)
+	assert.Equal(t, 3, resp.Summary.UnexcusedAbsences)
+	assert.Equal(t, 2, resp.PerStudent[1].ExcusedAbsences)
 }
 
 func TestAttendanceAliasServiceSummaryTeacherForbidden(t *testing.T) {
@@ -100,3 +117,87 @@ func TestAttendanceAliasServiceSummaryTeacherForbidden(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
 }
+
+func TestAttendanceAliasServiceMonthly(t *testing.T) {
+	day := func(d int) *time.Time {
+		value := time.Date(2024, time.February, d, 0, 0, 0, 0, time.UTC)
+		return &value
+	}
+	status := func(v string) *string { return &v }
+	roster := []repository.AttendanceAliasDayRow{
+		{StudentID: "stu-1", StudentName: "Alice", NIS: "001", Date: day(1), Status: status("H")},
+		{StudentID: "stu-1", StudentName: "Alice", NIS: "001", Date: day(2), Status: status("A")},
+		{StudentID: "stu-2", StudentName: "Bob", NIS: "002"},
+	}
+	service := NewAttendanceAliasService(&AttendanceService{}, nil, attendanceSummaryRepoStub{roster: roster},
+		assignmentAccessStub{list: []models.TeacherAssignmentDetail{{TeacherAssignment: models.TeacherAssignment{ClassID: "class-1"}}}},
+		enrollmentReaderStub{}, attendanceTermLookupStub{}, nil)
+
+	resp, err := service.Monthly(context.Background(), dto.AttendanceMonthlyRequest{ClassID: "class-1", Month: "2024-02"}, &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.Len(t, resp.Dates, 29)
+	require.Len(t, resp.Students, 2)
+	assert.Equal(t, map[string]string{"2024-02-01": "H", "2024-02-02": "A"}, resp.Students[0].Days)
+	assert.Equal(t, 2, resp.Students[0].Summary.TotalDays)
+	assert.InDelta(t, 50, resp.Students[0].Summary.Percentage, 0.01)
+	assert.Empty(t, resp.Students[1].Days)
+
+	_, err = service.Monthly(context.Background(), dto.AttendanceMonthlyRequest{ClassID: "class-2", Month: "2024-02"}, &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	_, err = service.Monthly(context.Background(), dto.AttendanceMonthlyRequest{ClassID: "class-1", Month: "02-2024"}, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestAttendanceAliasServiceStudentHistory(t *testing.T) {
+	first := time.Date(2024, time.October, 23, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 1)
+	sick, present, notes := "S", "H", "Flu"
+	roster := []repository.AttendanceAliasDayRow{
+		{StudentID: "stu-1", StudentName: "Ahmad", NIS: "2024001", Date: &first, Status: &sick, Notes: &notes},
+		{StudentID: "stu-1", StudentName: "Ahmad", NIS: "2024001", Date: &second, Status: &present},
+	}
+	service := NewAttendanceAliasService(&AttendanceService{}, nil, attendanceSummaryRepoStub{roster: roster},
+		assignmentAccessStub{}, enrollmentReaderStub{}, attendanceTermLookupStub{}, nil)
+
+	resp, err := service.StudentHistory(context.Background(), dto.AttendanceStudentRequest{StudentID: "stu-1", TermID: "term-1"}, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, "2024001", resp.NIS)
+	require.Len(t, resp.Records, 2)
+	assert.Equal(t, "2024-10-24", resp.Records[0].Date)
+	assert.Equal(t, dto.AttendanceLegacySummary{TotalDays: 2, Present: 1, Sick: 1, Percentage: 50}, resp.Summary)
+
+	empty := NewAttendanceAliasService(&AttendanceService{}, nil, attendanceSummaryRepoStub{}, assignmentAccessStub{}, enrollmentReaderStub{}, attendanceTermLookupStub{}, nil)
+	_, err = empty.StudentHistory(context.Background(), dto.AttendanceStudentRequest{StudentID: "stu-9", TermID: "term-1"}, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+type teacherAssignmentDenyStub struct{ assignmentAccessStub }
+
+func (teacherAssignmentDenyStub) HasClassAccess(ctx context.Context, teacherID, classID, termID string) (bool, error) {
+	return false, nil
+}
+
+func TestAttendanceAliasServiceExcuse(t *testing.T) {
+	repo := &dailyAttendanceRepoStub{}
+	attendanceSvc := NewAttendanceService(repo, nil, nil, nil)
+	req := dto.AttendanceExcuseRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Reason: "school_duty"}
+	admin := &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin}
+
+	service := NewAttendanceAliasService(attendanceSvc, nil, attendanceSummaryRepoStub{}, assignmentAccessStub{}, enrollmentReaderStub{}, attendanceTermLookupStub{}, nil)
+	stored, err := service.Excuse(context.Background(), req, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendanceStatusExcused, stored.Status)
+	require.NotNil(t, stored.AbsenceReason)
+	assert.Equal(t, models.AbsenceReasonSchoolDuty, *stored.AbsenceReason)
+
+	req.EnrollmentID = "missing"
+	_, err = service.Excuse(context.Background(), req, admin)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	denied := NewAttendanceAliasService(attendanceSvc, nil, attendanceSummaryRepoStub{}, teacherAssignmentDenyStub{}, enrollmentReaderStub{}, attendanceTermLookupStub{}, nil)
+	req.EnrollmentID = "enr-1"
+	_, err = denied.Excuse(context.Background(), req, &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	assert.Len(t, repo.upserted, 1)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// qrTokenPurpose is signed into student QR tokens so download tokens cannot be replayed at the gate.
+const qrTokenPurpose = "checkin"
+
+type checkinStudentLookup interface {
+	FindIDByNIS(ctx context.Context, nis string) (string, error)
+	FindByID(ctx context.Context, id string) (*models.StudentDetail, error)
+}
+
+type checkinEnrollmentReader interface {
+	FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error)
+}
+
+type checkinRecorder interface {
+	RecordCheckIn(ctx context.Context, record models.DailyAttendance) (*models.DailyAttendance, bool, error)
+}
+
+type checkinSettingReader interface {
+	Get(ctx context.Context, key string) (*models.Configuration, error)
+}
+
+type qrTokenSigner interface {
+	Generate(subject, purpose string) (string, time.Time, error)
+	Parse(token string, allowExpired bool) (subject, purpose string, expiresAt time.Time, err error)
+}
+
+// AttendanceCheckinConfig configures device check-ins.
+type AttendanceCheckinConfig struct {
+	// DeviceKeys maps device names to API keys.
+	DeviceKeys map[string]string
+	// LateAfter is the fallback "HH:MM" cut-off when the attendance_late_after setting is unset.
+	LateAfter string
+	// Location is the school's time zone used to derive the attendance date and lateness.
+	Location *time.Location
+	// MaxClockSkew bounds how far in the future a device timestamp may be.
+	MaxClockSkew time.Duration
+}
+
+// AttendanceCheckinService turns gate scans into daily attendance rows.
+type AttendanceCheckinService struct {
+	students    checkinStudentLookup
+	enrollments checkinEnrollmentReader
+	terms       ports.ActiveTermReader
+	recorder    checkinRecorder
+	settings    checkinSettingReader
+	qr          qrTokenSigner
+	cfg         AttendanceCheckinConfig
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewAttendanceCheckinService constructs the service. qr may be nil, in which case only NIS check-ins
+// are accepted.
+func NewAttendanceCheckinService(students checkinStudentLookup, enrollments checkinEnrollmentReader, terms ports.ActiveTermReader, recorder checkinRecorder, settings checkinSettingReader, qr qrTokenSigner, cfg AttendanceCheckinConfig, logger *zap.Logger) *AttendanceCheckinService {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.MaxClockSkew <= 0 {
+		cfg.MaxClockSkew = 5 * time.Minute
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AttendanceCheckinService{
+		students:    students,
+		enrollments: enrollments,
+		terms:       terms,
+		recorder:    recorder,
+		settings:    settings,
+		qr:          qr,
+		cfg:         cfg,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// CheckIn authenticates the device, resolves the student's active enrollment and records the day as
+// present, noting lateness against the configured cut-off.
+func (s *AttendanceCheckinService) CheckIn(ctx context.Context, deviceKey string, req dto.AttendanceCheckinRequest) (*dto.AttendanceCheckinResponse, error) {
+	device, ok := s.authenticate(deviceKey)
+	if !ok {
+		return nil, appErrors.Clone(appErrors.ErrUnauthorized, "invalid device key")
+	}
+
+	now := s.now()
+	scannedAt := now
+	if req.Timestamp != nil {
+		scannedAt = *req.Timestamp
+	}
+	if scannedAt.After(now.Add(s.cfg.MaxClockSkew)) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "timestamp is in the future")
+	}
+
+	studentID, err := s.resolveStudent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := s.activeEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	cutoff, err := s.lateAfter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	local := scannedAt.In(s.cfg.Location)
+	lateMinutes := minutesLate(local, cutoff)
+	notes := fmt.Sprintf("Check-in %s via %s", local.Format("15:04"), device)
+	if lateMinutes > 0 {
+		notes = fmt.Sprintf("Late %d min: %s", lateMinutes, notes)
+	}
+	checkedInAt := scannedAt.UTC()
+	record := models.DailyAttendance{
+		EnrollmentID: enrollment.ID,
+		Date:         time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC),
+		Status:       models.AttendanceStatusPresent,
+		Notes:        &notes,
+		Source:       models.AttendanceSourceDevice,
+		CheckedInAt:  &checkedInAt,
+	}
+	stored, created, err := s.recorder.RecordCheckIn(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AttendanceCheckinResponse{
+		AttendanceID: stored.ID,
+		StudentID:    studentID,
+		EnrollmentID: stored.EnrollmentID,
+		Date:         stored.Date.Format("2006-01-02"),
+		Status:       stored.Status,
+		Source:       stored.Source,
+		CheckedInAt:  stored.CheckedInAt,
+		Duplicate:    !created,
+		Device:       device,
+		Warnings:     stored.Warnings,
+	}
+	if resp.Source == "" {
+		resp.Source = models.AttendanceSourceManual
+	}
+	if stored.CheckedInAt != nil {
+		resp.LateMinutes = minutesLate(stored.CheckedInAt.In(s.cfg.Location), cutoff)
+		resp.Late = resp.LateMinutes > 0
+	}
+	return resp, nil
+}
+
+// IssueQRToken signs a check-in token for a student card.
+func (s *AttendanceCheckinService) IssueQRToken(ctx context.Context, studentID string) (*dto.AttendanceQRTokenResponse, error) {
+	if s.qr == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "QR check-in is not configured")
+	}
+	if _, err := s.students.FindByID(ctx, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "student not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+	}
+	token, expiresAt, err := s.qr.Generate(studentID, qrTokenPurpose)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to sign QR token")
+	}
+	return &dto.AttendanceQRTokenResponse{StudentID: studentID, Token: token, ExpiresAt: expiresAt.UTC()}, nil
+}
+
+// authenticate compares the key against every device in constant time and returns the device name.
+func (s *AttendanceCheckinService) authenticate(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	names := make([]string, 0, len(s.cfg.DeviceKeys))
+	for name := range s.cfg.DeviceKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	matched := ""
+	for _, name := range names {
+		if subtle.ConstantTimeCompare([]byte(s.cfg.DeviceKeys[name]), []byte(key)) == 1 && matched == "" {
+			matched = name
+		}
+	}
+	return matched, matched != ""
+}
+
+func (s *AttendanceCheckinService) resolveStudent(ctx context.Context, req dto.AttendanceCheckinRequest) (string, error) {
+	nis := strings.TrimSpace(req.NIS)
+	token := strings.TrimSpace(req.QRToken)
+	if (nis == "") == (token == "") {
+		return "", appErrors.Clone(appErrors.ErrValidation, "provide exactly one of nis or qrToken")
+	}
+	if token != "" {
+		if s.qr == nil {
+			return "", appErrors.Clone(appErrors.ErrValidation, "QR check-in is not configured")
+		}
+		studentID, purpose, _, err := s.qr.Parse(token, false)
+		if err != nil || purpose != qrTokenPurpose {
+			return "", appErrors.Clone(appErrors.ErrForbidden, "invalid or expired QR token")
+		}
+		return studentID, nil
+	}
+	studentID, err := s.students.FindIDByNIS(ctx, nis)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", appErrors.Clone(appErrors.ErrNotFound, "student not found")
+		}
+		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to resolve student")
+	}
+	return studentID, nil
+}
+
+func (s *AttendanceCheckinService) activeEnrollment(ctx context.Context, studentID string) (*models.Enrollment, error) {
+	term, err := s.terms.FindActive(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "no active term")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load active term")
+	}
+	enrollments, err := s.enrollments.FindActiveByStudentAndTerm(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+	}
+	if len(enrollments) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "student has no active enrollment in the active term")
+	}
+	return &enrollments[0], nil
+}
+
+// lateAfter returns the cut-off as minutes after midnight, preferring the runtime setting.
+func (s *AttendanceCheckinService) lateAfter(ctx context.Context) (int, error) {
+	raw := s.cfg.LateAfter
+	if s.settings != nil {
+		setting, err := s.settings.Get(ctx, AttendanceLateAfterKey)
+		switch {
+		case err == nil && strings.TrimSpace(setting.Value) != "":
+			raw = strings.TrimSpace(setting.Value)
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			s.logger.Warn("failed to read late threshold setting, using default", zap.Error(err))
+		}
+	}
+	cutoff, err := time.Parse("15:04", raw)
+	if err != nil {
+		return 0, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "invalid late threshold")
+	}
+	return cutoff.Hour()*60 + cutoff.Minute(), nil
+}
+
+// minutesLate rounds up so a scan seconds after the cut-off still counts as late.
+func minutesLate(local time.Time, cutoff int) int {
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	late := local.Sub(midnight.Add(time.Duration(cutoff) * time.Minute))
+	if late <= 0 {
+		return 0
+	}
+	return int(math.Ceil(late.Minutes()))
+}
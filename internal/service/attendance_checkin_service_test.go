@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+)
+
+type checkinStudentStub struct{}
+
+func (checkinStudentStub) FindIDByNIS(ctx context.Context, nis string) (string, error) {
+	if nis == "1001" {
+		return "student-1", nil
+	}
+	return "", sql.ErrNoRows
+}
+
+func (checkinStudentStub) FindByID(ctx context.Context, id string) (*models.StudentDetail, error) {
+	if id != "student-1" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.StudentDetail{}, nil
+}
+
+type checkinEnrollmentStub struct{}
+
+func (checkinEnrollmentStub) FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error) {
+	if studentID != "student-1" || termID != "term-1" {
+		return nil, nil
+	}
+	return []models.Enrollment{{ID: "enr-1", StudentID: studentID, TermID: termID}}, nil
+}
+
+type checkinRecorderStub struct {
+	records  []models.DailyAttendance
+	existing *models.DailyAttendance
+}
+
+func (s *checkinRecorderStub) RecordCheckIn(ctx context.Context, record models.DailyAttendance) (*models.DailyAttendance, bool, error) {
+	if s.existing != nil {
+		return s.existing, false, nil
+	}
+	s.records = append(s.records, record)
+	record.ID = "att-1"
+	return &record, true, nil
+}
+
+type checkinSettingStub struct{ value string }
+
+func (s checkinSettingStub) Get(ctx context.Context, key string) (*models.Configuration, error) {
+	if s.value == "" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Configuration{Key: key, Value: s.value}, nil
+}
+
+func newCheckinService(t *testing.T, settings checkinSettingReader, recorder *checkinRecorderStub, qr qrTokenSigner) *AttendanceCheckinService {
+	t.Helper()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	svc := NewAttendanceCheckinService(checkinStudentStub{}, checkinEnrollmentStub{}, warmerTermStub{term: &models.Term{ID: "term-1"}}, recorder, settings, qr, AttendanceCheckinConfig{
+		DeviceKeys: map[string]string{"gate-1": "key-1", "gate-2": "key-2"},
+		LateAfter:  "07:15",
+		Location:   jakarta,
+	}, zap.NewNop())
+	svc.now = func() time.Time { return time.Date(2024, 8, 5, 1, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func TestAttendanceCheckinMarksLateAgainstLocalCutoff(t *testing.T) {
+	recorder := &checkinRecorderStub{}
+	svc := newCheckinService(t, checkinSettingStub{}, recorder, nil)
+	ctx := context.Background()
+
+	onTime := time.Date(2024, 8, 5, 0, 10, 0, 0, time.UTC) // 07:10 WIB
+	resp, err := svc.CheckIn(ctx, "key-2", dto.AttendanceCheckinRequest{NIS: "1001", Timestamp: &onTime})
+	require.NoError(t, err)
+	assert.False(t, resp.Late)
+	assert.Equal(t, "gate-2", resp.Device)
+	assert.Equal(t, "2024-08-05", resp.Date)
+	assert.Equal(t, models.AttendanceSourceDevice, resp.Source)
+	assert.Equal(t, "Check-in 07:10 via gate-2", *recorder.records[0].Notes)
+
+	late := time.Date(2024, 8, 5, 0, 27, 30, 0, time.UTC) // 07:27:30 WIB
+	resp, err = svc.CheckIn(ctx, "key-1", dto.AttendanceCheckinRequest{NIS: "1001", Timestamp: &late})
+	require.NoError(t, err)
+	assert.True(t, resp.Late)
+	assert.Equal(t, 13, resp.LateMinutes)
+	assert.Equal(t, models.AttendanceStatusPresent, recorder.records[1].Status)
+	assert.Equal(t, "Late 13 min: Check-in 07:27 via gate-1", *recorder.records[1].Notes)
+
+	override := newCheckinService(t, checkinSettingStub{value: "07:30"}, &checkinRecorderStub{}, nil)
+	resp, err = override.CheckIn(ctx, "key-1", dto.AttendanceCheckinRequest{NIS: "1001", Timestamp: &late})
+	require.NoError(t, err)
+	assert.False(t, resp.Late, "the runtime setting replaces the default cut-off")
+}
+
+func TestAttendanceCheckinAcceptsSignedQRTokens(t *testing.T) {
+	signer := storage.NewSignedURLSigner("qr-secret", time.Hour)
+	recorder := &checkinRecorderStub{}
+	svc := newCheckinService(t, nil, recorder, signer)
+	ctx := context.Background()
+
+	issued, err := svc.IssueQRToken(ctx, "student-1")
+	require.NoError(t, err)
+
+	resp, err := svc.CheckIn(ctx, "key-1", dto.AttendanceCheckinRequest{QRToken: issued.Token})
+	require.NoError(t, err)
+	assert.Equal(t, "student-1", resp.StudentID)
+	assert.Equal(t, "enr-1", resp.EnrollmentID)
+
+	download, _, err := signer.Generate("student-1", "reports/file.csv")
+	require.NoError(t, err)
+	_, err = svc.CheckIn(ctx, "key-1", dto.AttendanceCheckinRequest{QRToken: download})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code, "tokens signed for other purposes are rejected")
+}
+
+func TestAttendanceCheckinReportsDuplicates(t *testing.T) {
+	checkedIn := time.Date(2024, 8, 5, 0, 5, 0, 0, time.UTC)
+	recorder := &checkinRecorderStub{existing: &models.DailyAttendance{ID: "att-0", EnrollmentID: "enr-1", Status: models.AttendanceStatusPresent, Source: models.AttendanceSourceDevice, CheckedInAt: &checkedIn}}
+	svc := newCheckinService(t, nil, recorder, nil)
+
+	resp, err := svc.CheckIn(context.Background(), "key-1", dto.AttendanceCheckinRequest{NIS: "1001"})
+	require.NoError(t, err)
+	assert.True(t, resp.Duplicate)
+	assert.Equal(t, "att-0", resp.AttendanceID)
+	assert.False(t, resp.Late, "lateness reflects the first scan")
+}
+
+func TestAttendanceCheckinRejectsBadRequests(t *testing.T) {
+	svc := newCheckinService(t, nil, &checkinRecorderStub{}, nil)
+	ctx := context.Background()
+	future := time.Date(2024, 8, 5, 2, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		key  string
+		req  dto.AttendanceCheckinRequest
+		code string
+	}{
+		"unknown device":   {key: "nope", req: dto.AttendanceCheckinRequest{NIS: "1001"}, code: appErrors.ErrUnauthorized.Code},
+		"no identifier":    {key: "key-1", code: appErrors.ErrValidation.Code},
+		"both identifiers": {key: "key-1", req: dto.AttendanceCheckinRequest{NIS: "1001", QRToken: "x"}, code: appErrors.ErrValidation.Code},
+		"future scan":      {key: "key-1", req: dto.AttendanceCheckinRequest{NIS: "1001", Timestamp: &future}, code: appErrors.ErrValidation.Code},
+		"unknown student":  {key: "key-1", req: dto.AttendanceCheckinRequest{NIS: "9999"}, code: appErrors.ErrNotFound.Code},
+		"qr not enabled":   {key: "key-1", req: dto.AttendanceCheckinRequest{QRToken: "x"}, code: appErrors.ErrValidation.Code},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.CheckIn(ctx, tc.key, tc.req)
+			require.Error(t, err)
+			assert.Equal(t, tc.code, appErrors.FromError(err).Code)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type attendanceEvidenceArchives interface {
+	GetByID(ctx context.Context, id string) (*models.ArchiveItem, error)
+}
+
+// WithAbsenceEvidence lets excuses link STUDENT-scope archives as evidence. Without it excuses can
+// still be recorded, but not with documents.
+func WithAbsenceEvidence(archives attendanceEvidenceArchives) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		s.evidence = archives
+	}
+}
+
+// resolveAbsenceReason checks reason against status and fills in the reason S and I imply when none
+// was given. Present and unexcused days carry no reason.
+func resolveAbsenceReason(status models.AttendanceStatus, reason *string) (*models.AbsenceReason, error) {
+	if reason == nil || strings.TrimSpace(*reason) == "" {
+		if implied := models.DefaultAbsenceReason(status); implied != "" {
+			return &implied, nil
+		}
+		return nil, nil
+	}
+	resolved := models.AbsenceReason(strings.ToUpper(strings.TrimSpace(*reason)))
+	if !resolved.Valid() {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unknown absence reason %q", *reason))
+	}
+	if resolved.Status() != status {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("absence reason %s requires status %s", resolved, resolved.Status()))
+	}
+	return &resolved, nil
+}
+
+// ExcuseAbsence records why the enrollment's student missed a day: the status follows from the
+// reason (S for SICK, I otherwise) and the evidence replaces any documents linked before. Evidence
+// must be STUDENT-scope archives of the same student. The caller checks access to the enrollment.
+func (s *AttendanceService) ExcuseAbsence(ctx context.Context, enrollment *models.Enrollment, req dto.AttendanceExcuseRequest, actorID string) (*models.DailyAttendance, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid excuse payload")
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
+	}
+	reason := models.AbsenceReason(strings.ToUpper(req.Reason))
+	now := time.Now().UTC()
+	evidence, err := s.excuseEvidence(ctx, enrollment, date, req.EvidenceIDs, actorID, now)
+	if err != nil {
+		return nil, err
+	}
+	notes, warning, err := s.newSchoolDayChecker().apply(ctx, enrollment.ID, date, req.Makeup, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+	record := &models.DailyAttendance{EnrollmentID: enrollment.ID, Date: date, Status: reason.Status(), AbsenceReason: &reason, Notes: notes}
+	var stored *models.DailyAttendance
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		var err error
+		if stored, err = s.dailyRepo.Upsert(ctx, exec, record); err != nil {
+			return nil, err
+		}
+		if err := s.dailyRepo.ReplaceEvidence(ctx, exec, enrollment.ID, date, evidence); err != nil {
+			return nil, err
+		}
+		return attendanceMarkedEvents(*stored)
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to excuse absence")
+	}
+	stored.Evidence = evidence
+	if warning != "" {
+		stored.Warnings = append(stored.Warnings, warning)
+	}
+	return stored, nil
+}
+
+// excuseEvidence resolves archive IDs into evidence links, skipping duplicates.
+func (s *AttendanceService) excuseEvidence(ctx context.Context, enrollment *models.Enrollment, date time.Time, archiveIDs []string, actorID string, now time.Time) ([]models.AttendanceEvidence, error) {
+	if len(archiveIDs) == 0 {
+		return []models.AttendanceEvidence{}, nil
+	}
+	if s.evidence == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "absence evidence requires archives to be enabled")
+	}
+	seen := make(map[string]bool, len(archiveIDs))
+	evidence := make([]models.AttendanceEvidence, 0, len(archiveIDs))
+	for _, id := range archiveIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		item, err := s.evidence.GetByID(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && item.DeletedAt != nil) {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("evidence archive %s not found", id))
+		}
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load evidence archive")
+		}
+		if item.Scope != models.ArchiveScopeStudent || item.RefStudentID == nil || *item.RefStudentID != enrollment.StudentID {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("evidence archive %s is not a STUDENT archive of this student", id))
+		}
+		evidence = append(evidence, models.AttendanceEvidence{EnrollmentID: enrollment.ID, Date: date, ArchiveID: id, LinkedBy: actorID, LinkedAt: now})
+	}
+	return evidence, nil
+}
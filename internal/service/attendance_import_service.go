@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+)
+
+// AttendanceImportJobType labels attendance import jobs on the queue.
+const AttendanceImportJobType = "attendance_daily_import"
+
+type attendanceImportStore interface {
+	Create(ctx context.Context, job *models.AttendanceImportJob) error
+	GetByID(ctx context.Context, id string) (*models.AttendanceImportJob, error)
+	Update(ctx context.Context, id string, params repository.UpdateAttendanceImportParams) error
+	ListQueued(ctx context.Context, limit int) ([]models.AttendanceImportJob, error)
+}
+
+type bulkDailyMarker interface {
+	ValidateBulkDaily(req BulkMarkDailyAttendanceRequest) (time.Time, error)
+	BulkMarkDaily(ctx context.Context, req BulkMarkDailyAttendanceRequest) (*BulkAttendanceResult, error)
+}
+
+// AttendanceImportConfig tunes asynchronous bulk attendance processing.
+type AttendanceImportConfig struct {
+	// ChunkSize is the number of rows written per batch in partialOnError mode. Atomic imports are
+	// always written in a single batch.
+	ChunkSize  int
+	MaxRetries int
+}
+
+func (c AttendanceImportConfig) withDefaults() AttendanceImportConfig {
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = 500
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	return c
+}
+
+// AttendanceImportService accepts large bulk attendance payloads and hands them to a queue worker.
+type AttendanceImportService struct {
+	repo   attendanceImportStore
+	marker bulkDailyMarker
+	queue  jobDispatcher
+	logger *zap.Logger
+}
+
+// NewAttendanceImportService constructs the import service.
+func NewAttendanceImportService(repo attendanceImportStore, marker bulkDailyMarker, queue jobDispatcher, logger *zap.Logger) *AttendanceImportService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AttendanceImportService{repo: repo, marker: marker, queue: queue, logger: logger}
+}
+
+// CreateJob validates the payload, stores it and enqueues processing.
+func (s *AttendanceImportService) CreateJob(ctx context.Context, req BulkMarkDailyAttendanceRequest, actorID string) (*dto.AttendanceImportJobResponse, error) {
+	if _, err := s.marker.ValidateBulkDaily(req); err != nil {
+		return nil, err
+	}
+	payload := models.AttendanceImportPayload{
+		Date:   req.Date,
+		Mode:   req.Mode,
+		Makeup: req.Makeup,
+		Items:  make([]models.AttendanceImportItem, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		payload.Items[i] = models.AttendanceImportItem{EnrollmentID: item.EnrollmentID, Status: item.Status, Notes: item.Notes}
+	}
+	job := &models.AttendanceImportJob{
+		Payload:   payload,
+		TotalRows: len(payload.Items),
+		Status:    models.ReportStatusQueued,
+		CreatedBy: actorID,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create attendance import job")
+	}
+	if err := s.queue.Enqueue(jobs.Job{ID: job.ID, Type: AttendanceImportJobType}); err != nil {
+		status := models.ReportStatusFailed
+		msg := "failed to enqueue job"
+		now := time.Now().UTC()
+		progress := 100
+		_ = s.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+			Status:       &status,
+			Progress:     &progress,
+			ErrorMessage: &msg,
+			FinishedAt:   &now,
+		})
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to enqueue attendance import job")
+	}
+	return &dto.AttendanceImportJobResponse{ID: job.ID, Status: job.Status, Progress: job.Progress, TotalRows: job.TotalRows}, nil
+}
+
+// GetStatus reports progress and the per-row conflicts collected so far.
+func (s *AttendanceImportService) GetStatus(ctx context.Context, id string) (*dto.AttendanceImportStatusResponse, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.ErrNotFound
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load attendance import job")
+	}
+	resp := &dto.AttendanceImportStatusResponse{
+		ID:         job.ID,
+		Status:     job.Status,
+		Progress:   job.Progress,
+		TotalRows:  job.TotalRows,
+		Processed:  job.Result.Processed,
+		Success:    job.Result.Success,
+		Conflicts:  job.Result.Conflicts,
+		Warnings:   job.Result.Warnings,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	if resp.Conflicts == nil {
+		resp.Conflicts = []models.AttendanceImportConflict{}
+	}
+	if job.ErrorMessage != nil && *job.ErrorMessage != "" {
+		resp.Error = job.ErrorMessage
+	}
+	return resp, nil
+}
+
+// RecoverPendingJobs replays queued imports (e.g. after process restart).
+func (s *AttendanceImportService) RecoverPendingJobs(ctx context.Context) {
+	pending, err := s.repo.ListQueued(ctx, 50)
+	if err != nil {
+		s.logger.Sugar().Warnw("failed to recover queued attendance imports", "error", err)
+		return
+	}
+	for _, job := range pending {
+		if err := s.queue.Enqueue(jobs.Job{ID: job.ID, Type: AttendanceImportJobType}); err != nil {
+			s.logger.Sugar().Warnw("failed to requeue attendance import", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// AttendanceImportWorker writes stored import payloads in chunks through AttendanceService.
+type AttendanceImportWorker struct {
+	repo   attendanceImportStore
+	marker bulkDailyMarker
+	cfg    AttendanceImportConfig
+	logger *zap.Logger
+}
+
+// NewAttendanceImportWorker constructs a worker.
+func NewAttendanceImportWorker(repo attendanceImportStore, marker bulkDailyMarker, cfg AttendanceImportConfig, logger *zap.Logger) *AttendanceImportWorker {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AttendanceImportWorker{repo: repo, marker: marker, cfg: cfg.withDefaults(), logger: logger}
+}
+
+// Handle processes a queue job. Progress is saved after every chunk so a retried job resumes after the
+// last written row instead of reporting its own rows as duplicates.
+func (w *AttendanceImportWorker) Handle(ctx context.Context, job jobs.Job) error {
+	record, err := w.repo.GetByID(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if record.Status == models.ReportStatusFinished || record.Status == models.ReportStatusFailed {
+		return nil
+	}
+	payload := record.Payload
+	total := len(payload.Items)
+	result := record.Result
+
+	processing := models.ReportStatusProcessing
+	progress := importProgress(result.Processed, total)
+	if err := w.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+		Status:   &processing,
+		Progress: &progress,
+	}); err != nil {
+		return err
+	}
+
+	rows := make(map[string]int, total)
+	for i, item := range payload.Items {
+		rows[item.EnrollmentID] = i
+	}
+	warnings := newWarningSet()
+	for _, warning := range result.Warnings {
+		warnings.add(warning)
+	}
+	chunkSize := w.cfg.ChunkSize
+	if strings.EqualFold(payload.Mode, string(models.BulkModeAtomic)) {
+		chunkSize = total
+	}
+
+	for offset := result.Processed; offset < total; offset += chunkSize {
+		end := offset + chunkSize
+		if end > total {
+			end = total
+		}
+		req := BulkMarkDailyAttendanceRequest{Date: payload.Date, Mode: payload.Mode, Makeup: payload.Makeup}
+		for _, item := range payload.Items[offset:end] {
+			req.Items = append(req.Items, BulkDailyAttendanceItem{EnrollmentID: item.EnrollmentID, Status: item.Status, Notes: item.Notes})
+		}
+		chunk, err := w.marker.BulkMarkDaily(ctx, req)
+		if err != nil {
+			return w.fail(ctx, job, result, err)
+		}
+		result.Processed = end
+		result.Success += chunk.Success
+		for _, conflict := range chunk.Conflicts {
+			result.Conflicts = append(result.Conflicts, models.AttendanceImportConflict{
+				Row:          rows[conflict.EnrollmentID],
+				EnrollmentID: conflict.EnrollmentID,
+				Reason:       conflict.Reason,
+			})
+		}
+		for _, warning := range chunk.Warnings {
+			warnings.add(warning)
+		}
+		result.Warnings = warnings.list()
+		progress = importProgress(result.Processed, total)
+		if err := w.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+			Progress: &progress,
+			Result:   &result,
+		}); err != nil {
+			return err
+		}
+	}
+
+	finished := models.ReportStatusFinished
+	progress = 100
+	now := time.Now().UTC()
+	clear := ""
+	if err := w.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+		Status:       &finished,
+		Progress:     &progress,
+		Result:       &result,
+		ErrorMessage: &clear,
+		FinishedAt:   &now,
+	}); err != nil {
+		w.logger.Sugar().Warnw("failed to mark attendance import finished", "job_id", job.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// fail records a chunk failure. Client errors (bad dates, atomic conflicts, non-school days) will not
+// succeed on retry, so they fail the job immediately; anything else is requeued until retries run out.
+func (w *AttendanceImportWorker) fail(ctx context.Context, job jobs.Job, result models.AttendanceImportResult, cause error) error {
+	msg := cause.Error()
+	permanent := appErrors.FromError(cause).Status < http.StatusInternalServerError
+	if permanent || job.Attempt >= w.cfg.MaxRetries {
+		failed := models.ReportStatusFailed
+		now := time.Now().UTC()
+		if err := w.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+			Status:       &failed,
+			Result:       &result,
+			ErrorMessage: &msg,
+			FinishedAt:   &now,
+		}); err != nil {
+			w.logger.Sugar().Warnw("failed to mark attendance import failed", "job_id", job.ID, "error", err)
+		}
+		if permanent {
+			return nil
+		}
+		return cause
+	}
+	queued := models.ReportStatusQueued
+	if err := w.repo.Update(ctx, job.ID, repository.UpdateAttendanceImportParams{
+		Status:       &queued,
+		Result:       &result,
+		ErrorMessage: &msg,
+	}); err != nil {
+		w.logger.Sugar().Warnw("failed to mark attendance import queued", "job_id", job.ID, "error", err)
+	}
+	return cause
+}
+
+// importProgress keeps running jobs below 100 so clients only see 100 once the job is finished.
+func importProgress(processed, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	progress := processed * 100 / total
+	if progress > 99 {
+		progress = 99
+	}
+	return progress
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+)
+
+type importStoreStub struct {
+	jobs map[string]*models.AttendanceImportJob
+}
+
+func newImportStoreStub() *importStoreStub {
+	return &importStoreStub{jobs: map[string]*models.AttendanceImportJob{}}
+}
+
+func (s *importStoreStub) Create(ctx context.Context, job *models.AttendanceImportJob) error {
+	job.ID = fmt.Sprintf("import-%d", len(s.jobs)+1)
+	stored := *job
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+func (s *importStoreStub) GetByID(ctx context.Context, id string) (*models.AttendanceImportJob, error) {
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	stored := *job
+	return &stored, nil
+}
+
+func (s *importStoreStub) Update(ctx context.Context, id string, params repository.UpdateAttendanceImportParams) error {
+	job := s.jobs[id]
+	if params.Status != nil {
+		job.Status = *params.Status
+	}
+	if params.Progress != nil {
+		job.Progress = *params.Progress
+	}
+	if params.Result != nil {
+		job.Result = *params.Result
+	}
+	if params.ErrorMessage != nil {
+		job.ErrorMessage = params.ErrorMessage
+	}
+	if params.FinishedAt != nil {
+		job.FinishedAt = params.FinishedAt
+	}
+	return nil
+}
+
+func (s *importStoreStub) ListQueued(ctx context.Context, limit int) ([]models.AttendanceImportJob, error) {
+	var queued []models.AttendanceImportJob
+	for _, job := range s.jobs {
+		if job.Status == models.ReportStatusQueued {
+			queued = append(queued, *job)
+		}
+	}
+	return queued, nil
+}
+
+type bulkMarkerStub struct {
+	chunks    [][]string
+	conflicts map[string]bool
+	failOn    int
+	failErr   error
+}
+
+func (m *bulkMarkerStub) ValidateBulkDaily(req BulkMarkDailyAttendanceRequest) (time.Time, error) {
+	if len(req.Items) == 0 {
+		return time.Time{}, appErrors.Clone(appErrors.ErrValidation, "invalid payload")
+	}
+	return time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC), nil
+}
+
+func (m *bulkMarkerStub) BulkMarkDaily(ctx context.Context, req BulkMarkDailyAttendanceRequest) (*BulkAttendanceResult, error) {
+	if m.failErr != nil && len(m.chunks) == m.failOn {
+		err := m.failErr
+		m.failErr = nil
+		return nil, err
+	}
+	ids := make([]string, len(req.Items))
+	result := &BulkAttendanceResult{Processed: len(req.Items), Warnings: []string{"makeup session"}}
+	for i, item := range req.Items {
+		ids[i] = item.EnrollmentID
+		if m.conflicts[item.EnrollmentID] {
+			result.Conflicts = append(result.Conflicts, models.AttendanceBulkConflict{EnrollmentID: item.EnrollmentID, Reason: "duplicate record"})
+		}
+	}
+	result.Success = len(req.Items) - len(result.Conflicts)
+	m.chunks = append(m.chunks, ids)
+	return result, nil
+}
+
+type importQueueStub struct {
+	enqueued []jobs.Job
+}
+
+func (q *importQueueStub) Enqueue(job jobs.Job) error {
+	q.enqueued = append(q.enqueued, job)
+	return nil
+}
+
+func importRequest(rows int, mode string) BulkMarkDailyAttendanceRequest {
+	req := BulkMarkDailyAttendanceRequest{Date: "2024-08-05", Mode: mode}
+	for i := 0; i < rows; i++ {
+		req.Items = append(req.Items, BulkDailyAttendanceItem{EnrollmentID: fmt.Sprintf("enr-%d", i), Status: "PRESENT"})
+	}
+	return req
+}
+
+func TestAttendanceImportProcessesInChunks(t *testing.T) {
+	store := newImportStoreStub()
+	marker := &bulkMarkerStub{conflicts: map[string]bool{"enr-3": true}}
+	queue := &importQueueStub{}
+	svc := NewAttendanceImportService(store, marker, queue, zap.NewNop())
+
+	accepted, err := svc.CreateJob(context.Background(), importRequest(5, "partialOnError"), "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 5, accepted.TotalRows)
+	require.Len(t, queue.enqueued, 1)
+	assert.Equal(t, AttendanceImportJobType, queue.enqueued[0].Type)
+
+	worker := NewAttendanceImportWorker(store, marker, AttendanceImportConfig{ChunkSize: 2}, zap.NewNop())
+	require.NoError(t, worker.Handle(context.Background(), queue.enqueued[0]))
+	assert.Equal(t, [][]string{{"enr-0", "enr-1"}, {"enr-2", "enr-3"}, {"enr-4"}}, marker.chunks)
+
+	status, err := svc.GetStatus(context.Background(), accepted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportStatusFinished, status.Status)
+	assert.Equal(t, 100, status.Progress)
+	assert.Equal(t, 5, status.Processed)
+	assert.Equal(t, 4, status.Success)
+	assert.Equal(t, []models.AttendanceImportConflict{{Row: 3, EnrollmentID: "enr-3", Reason: "duplicate record"}}, status.Conflicts)
+	assert.Equal(t, []string{"makeup session"}, status.Warnings)
+	assert.Nil(t, status.Error)
+}
+
+func TestAttendanceImportResumesAfterTransientFailure(t *testing.T) {
+	store := newImportStoreStub()
+	marker := &bulkMarkerStub{failOn: 1, failErr: errors.New("connection reset")}
+	queue := &importQueueStub{}
+	svc := NewAttendanceImportService(store, marker, queue, zap.NewNop())
+	accepted, err := svc.CreateJob(context.Background(), importRequest(4, "partialOnError"), "admin-1")
+	require.NoError(t, err)
+
+	worker := NewAttendanceImportWorker(store, marker, AttendanceImportConfig{ChunkSize: 2}, zap.NewNop())
+	require.Error(t, worker.Handle(context.Background(), queue.enqueued[0]))
+	assert.Equal(t, models.ReportStatusQueued, store.jobs[accepted.ID].Status)
+	assert.Equal(t, 2, store.jobs[accepted.ID].Result.Processed)
+
+	require.NoError(t, worker.Handle(context.Background(), jobs.Job{ID: accepted.ID, Attempt: 1}))
+	assert.Equal(t, [][]string{{"enr-0", "enr-1"}, {"enr-2", "enr-3"}}, marker.chunks, "written rows are not replayed")
+	assert.Equal(t, models.ReportStatusFinished, store.jobs[accepted.ID].Status)
+	assert.Equal(t, 4, store.jobs[accepted.ID].Result.Success)
+}
+
+func TestAttendanceImportFailsFastOnClientErrors(t *testing.T) {
+	store := newImportStoreStub()
+	marker := &bulkMarkerStub{failErr: appErrors.Clone(appErrors.ErrConflict, "duplicate record")}
+	queue := &importQueueStub{}
+	svc := NewAttendanceImportService(store, marker, queue, zap.NewNop())
+
+	_, err := svc.CreateJob(context.Background(), BulkMarkDailyAttendanceRequest{Date: "2024-08-05", Mode: "atomic"}, "admin-1")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	assert.Empty(t, queue.enqueued)
+
+	accepted, err := svc.CreateJob(context.Background(), importRequest(3, "atomic"), "admin-1")
+	require.NoError(t, err)
+	worker := NewAttendanceImportWorker(store, marker, AttendanceImportConfig{ChunkSize: 2}, zap.NewNop())
+	require.NoError(t, worker.Handle(context.Background(), queue.enqueued[0]), "client errors are not retried")
+
+	status, err := svc.GetStatus(context.Background(), accepted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportStatusFailed, status.Status)
+	require.NotNil(t, status.Error)
+	assert.Contains(t, *status.Error, "duplicate record")
+	assert.Empty(t, status.Conflicts)
+
+	_, err = svc.GetStatus(context.Background(), "missing")
+	assert.ErrorIs(t, err, appErrors.ErrNotFound)
+}
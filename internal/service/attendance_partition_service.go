@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type attendancePartitionStore interface {
+	List(ctx context.Context, table string) ([]models.AttendancePartition, error)
+	Create(ctx context.Context, table string, month time.Time) error
+	Archive(ctx context.Context, table, partition string) error
+}
+
+// AttendancePartitionConfig tunes the attendance partition maintenance job.
+type AttendancePartitionConfig struct {
+	// AheadMonths is how many months after the current one get their partition in advance.
+	AheadMonths int
+	// RetainMonths is how many months, the current one included, stay attached. Older months are
+	// detached into the archive schema; zero keeps every month attached.
+	RetainMonths int
+	Interval     time.Duration
+	// Location decides which month is current.
+	Location *time.Location
+}
+
+// AttendancePartitionService keeps the monthly attendance partitions in shape: upcoming months are
+// created before their first mark would fall into the default partition, and months past the
+// retention window are detached so everyday queries and vacuums no longer touch them.
+type AttendancePartitionService struct {
+	store  attendancePartitionStore
+	logger *zap.Logger
+	cfg    AttendancePartitionConfig
+	now    func() time.Time
+}
+
+// NewAttendancePartitionService constructs the job with defaults.
+func NewAttendancePartitionService(store attendancePartitionStore, logger *zap.Logger, cfg AttendancePartitionConfig) *AttendancePartitionService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.AheadMonths < 1 {
+		cfg.AheadMonths = 1
+	}
+	if cfg.RetainMonths < 0 {
+		cfg.RetainMonths = 0
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &AttendancePartitionService{store: store, logger: logger, cfg: cfg, now: time.Now}
+}
+
+// Start runs the maintenance now and then every interval until ctx is cancelled.
+func (s *AttendancePartitionService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Run(ctx); err != nil {
+				s.logger.Warn("attendance partition maintenance failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run creates the missing partitions from the current month to AheadMonths ahead and archives the
+// partitions older than the retention window, table by table.
+func (s *AttendancePartitionService) Run(ctx context.Context) (dto.AttendancePartitionRun, error) {
+	run := dto.AttendancePartitionRun{Created: []string{}, Archived: []string{}}
+	now := s.now().In(s.cfg.Location)
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, table := range models.AttendancePartitionedTables {
+		partitions, err := s.store.List(ctx, table)
+		if err != nil {
+			return run, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list attendance partitions")
+		}
+		existing := make(map[time.Time]bool, len(partitions))
+		for _, partition := range partitions {
+			existing[partition.Month] = true
+		}
+		for i := 0; i <= s.cfg.AheadMonths; i++ {
+			month := current.AddDate(0, i, 0)
+			if existing[month] {
+				continue
+			}
+			if err := s.store.Create(ctx, table, month); err != nil {
+				return run, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create attendance partition")
+			}
+			run.Created = append(run.Created, table+"_"+month.Format("2006_01"))
+		}
+		if s.cfg.RetainMonths == 0 {
+			continue
+		}
+		cutoff := current.AddDate(0, 1-s.cfg.RetainMonths, 0)
+		for _, partition := range partitions {
+			if !partition.Month.Before(cutoff) {
+				continue
+			}
+			// A failed detach is retried on the next run; the other old months still move.
+			if err := s.store.Archive(ctx, table, partition.Name); err != nil {
+				s.logger.Warn("failed to archive attendance partition", zap.String("partition", partition.Name), zap.Error(err))
+				continue
+			}
+			run.Archived = append(run.Archived, partition.Name)
+		}
+	}
+	if len(run.Created) > 0 || len(run.Archived) > 0 {
+		s.logger.Info("attendance partitions maintained", zap.Strings("created", run.Created), zap.Strings("archived", run.Archived))
+	}
+	return run, nil
+}
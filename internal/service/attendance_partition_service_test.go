@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type attendancePartitionStoreStub struct {
+	partitions map[string][]models.AttendancePartition
+	failing    map[string]bool
+	archived   []string
+}
+
+func (s *attendancePartitionStoreStub) List(ctx context.Context, table string) ([]models.AttendancePartition, error) {
+	return append([]models.AttendancePartition(nil), s.partitions[table]...), nil
+}
+
+func (s *attendancePartitionStoreStub) Create(ctx context.Context, table string, month time.Time) error {
+	s.partitions[table] = append(s.partitions[table], models.AttendancePartition{Table: table, Name: table + "_" + month.Format("2006_01"), Month: month})
+	return nil
+}
+
+func (s *attendancePartitionStoreStub) Archive(ctx context.Context, table, partition string) error {
+	if s.failing[partition] {
+		return errors.New("lock timeout")
+	}
+	kept := s.partitions[table][:0]
+	for _, p := range s.partitions[table] {
+		if p.Name != partition {
+			kept = append(kept, p)
+		}
+	}
+	s.partitions[table] = kept
+	s.archived = append(s.archived, partition)
+	return nil
+}
+
+func monthPartition(table string, year int, month time.Month) models.AttendancePartition {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return models.AttendancePartition{Table: table, Name: table + "_" + start.Format("2006_01"), Month: start}
+}
+
+func TestAttendancePartitionServiceRun(t *testing.T) {
+	store := &attendancePartitionStoreStub{
+		partitions: map[string][]models.AttendancePartition{
+			"daily_attendance": {
+				monthPartition("daily_attendance", 2025, time.August),
+				monthPartition("daily_attendance", 2025, time.September),
+				monthPartition("daily_attendance", 2026, time.February),
+				monthPartition("daily_attendance", 2026, time.March),
+			},
+			"subject_attendance": {
+				monthPartition("subject_attendance", 2025, time.August),
+			},
+		},
+		failing: map[string]bool{"subject_attendance_2025_08": true},
+	}
+	jakarta := time.FixedZone("WIB", 7*3600)
+	svc := NewAttendancePartitionService(store, nil, AttendancePartitionConfig{AheadMonths: 2, RetainMonths: 6, Location: jakarta})
+	// Already March 1st in Jakarta, so March is the current month.
+	svc.now = func() time.Time { return time.Date(2026, 2, 28, 18, 0, 0, 0, time.UTC) }
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"daily_attendance_2026_04", "daily_attendance_2026_05",
+		"subject_attendance_2026_03", "subject_attendance_2026_04", "subject_attendance_2026_05",
+	}, run.Created)
+	// Six months are kept, October through March; the failed detach does not stop the others.
+	assert.Equal(t, []string{"daily_attendance_2025_08", "daily_attendance_2025_09"}, run.Archived)
+	assert.Len(t, store.partitions["subject_attendance"], 4)
+
+	// A second run has nothing left to do apart from retrying the failed detach.
+	delete(store.failing, "subject_attendance_2025_08")
+	run, err = svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, run.Created)
+	assert.Equal(t, []string{"subject_attendance_2025_08"}, run.Archived)
+}
+
+func TestAttendancePartitionServiceKeepsEverythingWithoutRetention(t *testing.T) {
+	store := &attendancePartitionStoreStub{partitions: map[string][]models.AttendancePartition{
+		"daily_attendance": {monthPartition("daily_attendance", 2020, time.January)},
+	}}
+	svc := NewAttendancePartitionService(store, nil, AttendancePartitionConfig{})
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC) }
+
+	run, err := svc.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, run.Archived)
+	// One month ahead is always prepared.
+	assert.Contains(t, run.Created, "daily_attendance_2026_04")
+	assert.NotContains(t, run.Created, "daily_attendance_2026_05")
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+)
+
+type attendanceRateSettings interface {
+	Get(ctx context.Context, key string) (*models.Configuration, error)
+}
+
+// AttendanceRates resolves the attendance percentage formula from the attendance_present_statuses
+// and attendance_excused_statuses settings so every summary computes percentages the same way. A
+// nil *AttendanceRates uses models.DefaultAttendanceRateFormula.
+type AttendanceRates struct {
+	settings attendanceRateSettings
+	logger   *zap.Logger
+}
+
+// NewAttendanceRates constructs an AttendanceRates reading settings.
+func NewAttendanceRates(settings attendanceRateSettings, logger *zap.Logger) *AttendanceRates {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &AttendanceRates{settings: settings, logger: logger}
+}
+
+// Formula returns the configured formula. Unset settings keep the default, as do unreadable ones,
+// which are logged: a summary is better served with the default percentage than not at all.
+func (r *AttendanceRates) Formula(ctx context.Context) models.AttendanceRateFormula {
+	if r == nil || r.settings == nil {
+		return models.DefaultAttendanceRateFormula
+	}
+	return models.AttendanceRateFormula{
+		Present: r.statuses(ctx, AttendancePresentStatusesKey),
+		Excused: r.statuses(ctx, AttendanceExcusedStatusesKey),
+	}
+}
+
+func (r *AttendanceRates) statuses(ctx context.Context, key string) []models.AttendanceStatus {
+	setting, err := r.settings.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			r.logger.Warn("failed to read attendance rate setting, using default", zap.String("key", key), zap.Error(err))
+		}
+		return nil
+	}
+	statuses, err := models.ParseAttendanceStatuses(setting.Value)
+	if err != nil {
+		r.logger.Warn("invalid attendance rate setting, using default", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+	return statuses
+}
+
+// Analytics wraps repo so attendance summaries carry percentages computed with the configured
+// formula, ordered highest first as the repository orders them.
+func (r *AttendanceRates) Analytics(repo ports.AnalyticsRepository) ports.AnalyticsRepository {
+	return &rateAnalytics{AnalyticsRepository: repo, rates: r}
+}
+
+type rateAnalytics struct {
+	ports.AnalyticsRepository
+	rates *AttendanceRates
+}
+
+func (a *rateAnalytics) AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error) {
+	summaries, err := a.AnalyticsRepository.AttendanceSummary(ctx, filter)
+	if err != nil || len(summaries) == 0 {
+		return summaries, err
+	}
+	formula := a.rates.Formula(ctx)
+	for i := range summaries {
+		summaries[i].Percentage = formula.Rate(summaries[i].Counts())
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Percentage > summaries[j].Percentage
+	})
+	return summaries, nil
+}
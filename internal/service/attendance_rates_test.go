@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type rateAnalyticsStub struct {
+	analyticsStub
+	summaries []models.AnalyticsAttendanceSummary
+}
+
+func (s rateAnalyticsStub) AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error) {
+	return append([]models.AnalyticsAttendanceSummary(nil), s.summaries...), nil
+}
+
+func TestAttendanceRatesFormula(t *testing.T) {
+	var unset *AttendanceRates
+	assert.Equal(t, models.DefaultAttendanceRateFormula, unset.Formula(context.Background()))
+
+	settings := &configurationRepoStub{items: map[string]models.Configuration{
+		AttendancePresentStatusesKey: {Key: AttendancePresentStatusesKey, Value: "H"},
+		AttendanceExcusedStatusesKey: {Key: AttendanceExcusedStatusesKey, Value: "S,I"},
+	}}
+	formula := NewAttendanceRates(settings, nil).Formula(context.Background())
+	assert.Equal(t, []models.AttendanceStatus{models.AttendanceStatusPresent}, formula.Present)
+	assert.Equal(t, []models.AttendanceStatus{models.AttendanceStatusSick, models.AttendanceStatusExcused}, formula.Excused)
+
+	// Unreadable settings fall back to the default formula.
+	formula = NewAttendanceRates(&configurationRepoStub{err: errors.New("down")}, nil).Formula(context.Background())
+	assert.InDelta(t, 50, formula.Rate(models.AttendanceStatusCounts{Present: 1, Sick: 1}), 0.001)
+}
+
+func TestAttendanceRatesAnalyticsRecomputesPercentages(t *testing.T) {
+	settings := &configurationRepoStub{items: map[string]models.Configuration{
+		AttendanceExcusedStatusesKey: {Key: AttendanceExcusedStatusesKey, Value: "S,I"},
+	}}
+	repo := NewAttendanceRates(settings, nil).Analytics(rateAnalyticsStub{summaries: []models.AnalyticsAttendanceSummary{
+		{ClassID: "class-a", PresentCount: 18, AbsentCount: 2, Percentage: 90},
+		{ClassID: "class-b", PresentCount: 16, SickCount: 3, ExcusedCount: 1, Percentage: 80},
+	}})
+
+	summaries, err := repo.AttendanceSummary(context.Background(), models.AnalyticsAttendanceFilter{TermID: "term-1"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "class-b", summaries[0].ClassID)
+	assert.InDelta(t, 100, summaries[0].Percentage, 0.001)
+	assert.InDelta(t, 90, summaries[1].Percentage, 0.001)
+
+	grades, err := repo.GradeSummary(context.Background(), models.AnalyticsGradeFilter{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Len(t, grades, 1)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	// NonSchoolDayPolicyReject refuses attendance on non-school days unless flagged as a makeup session.
+	NonSchoolDayPolicyReject = "reject"
+	// NonSchoolDayPolicyWarn stores the mark but returns a warning.
+	NonSchoolDayPolicyWarn = "warn"
+
+	nonSchoolDayWeekend     = "WEEKEND"
+	nonSchoolDayHoliday     = "HOLIDAY"
+	nonSchoolDayOutsideTerm = "OUTSIDE_TERM"
+)
+
+type attendanceCalendar interface {
+	HolidayOn(ctx context.Context, date time.Time, classID string) (*models.CalendarEvent, error)
+}
+
+type attendanceEnrollmentReader interface {
+	FindByID(ctx context.Context, id string) (*models.Enrollment, error)
+}
+
+// AttendanceCalendarConfig tunes school day validation.
+type AttendanceCalendarConfig struct {
+	// Policy is NonSchoolDayPolicyReject (default) or NonSchoolDayPolicyWarn.
+	Policy string
+	// NonSchoolWeekdays defaults to Sunday.
+	NonSchoolWeekdays []time.Weekday
+}
+
+// AttendanceServiceOption customises AttendanceService.
+type AttendanceServiceOption func(*AttendanceService)
+
+// WithSchoolDayValidation checks every mark against weekends, calendar holidays and the enrollment term.
+func WithSchoolDayValidation(calendar attendanceCalendar, enrollments attendanceEnrollmentReader, terms ports.TermReader, cfg AttendanceCalendarConfig) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		if cfg.Policy != NonSchoolDayPolicyWarn {
+			cfg.Policy = NonSchoolDayPolicyReject
+		}
+		if len(cfg.NonSchoolWeekdays) == 0 {
+			cfg.NonSchoolWeekdays = []time.Weekday{time.Sunday}
+		}
+		s.calendar = calendar
+		s.enrollments = enrollments
+		s.terms = terms
+		s.calendarCfg = cfg
+	}
+}
+
+// nonSchoolDay describes why a date cannot hold regular lessons.
+type nonSchoolDay struct {
+	reason string
+	detail string
+}
+
+func (d *nonSchoolDay) String() string {
+	if d.detail == "" {
+		return d.reason
+	}
+	return fmt.Sprintf("%s: %s", d.reason, d.detail)
+}
+
+// schoolDayChecker memoises enrollment and term lookups across the items of one request.
+type schoolDayChecker struct {
+	svc         *AttendanceService
+	enrollments map[string]*models.Enrollment
+	terms       map[string]*models.Term
+}
+
+func (s *AttendanceService) newSchoolDayChecker() *schoolDayChecker {
+	return &schoolDayChecker{
+		svc:         s,
+		enrollments: make(map[string]*models.Enrollment),
+		terms:       make(map[string]*models.Term),
+	}
+}
+
+// apply validates date for the enrollment. Makeup marks bypass the check and are annotated in
+// notes; otherwise the configured policy either rejects the mark or returns a warning.
+func (c *schoolDayChecker) apply(ctx context.Context, enrollmentID string, date time.Time, makeup bool, notes *string) (*string, string, error) {
+	if c.svc.calendar == nil {
+		return notes, "", nil
+	}
+	blocked, err := c.check(ctx, enrollmentID, date)
+	if err != nil || blocked == nil {
+		return notes, "", err
+	}
+	day := date.Format("2006-01-02")
+	if makeup {
+		annotated := fmt.Sprintf("Makeup session (%s)", blocked)
+		if notes != nil && strings.TrimSpace(*notes) != "" {
+			annotated += "; " + *notes
+		}
+		return &annotated, "", nil
+	}
+	if c.svc.calendarCfg.Policy == NonSchoolDayPolicyReject {
+		return nil, "", appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("%s is not a school day (%s); set makeup to record a makeup session", day, blocked))
+	}
+	c.svc.logger.Warn("attendance marked on non-school day",
+		zap.String("enrollment_id", enrollmentID),
+		zap.String("date", day),
+		zap.String("reason", blocked.reason),
+	)
+	return notes, fmt.Sprintf("%s is not a school day (%s)", day, blocked), nil
+}
+
+func (c *schoolDayChecker) check(ctx context.Context, enrollmentID string, date time.Time) (*nonSchoolDay, error) {
+	for _, weekday := range c.svc.calendarCfg.NonSchoolWeekdays {
+		if date.Weekday() == weekday {
+			return &nonSchoolDay{reason: nonSchoolDayWeekend, detail: date.Weekday().String()}, nil
+		}
+	}
+
+	enrollment, err := c.enrollment(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if term, err := c.term(ctx, enrollment.TermID); err != nil {
+		return nil, err
+	} else if term != nil && (date.Before(dateOnly(term.StartDate)) || date.After(dateOnly(term.EndDate))) {
+		return &nonSchoolDay{
+			reason: nonSchoolDayOutsideTerm,
+			detail: fmt.Sprintf("%s runs %s to %s", term.Name, term.StartDate.Format("2006-01-02"), term.EndDate.Format("2006-01-02")),
+		}, nil
+	}
+
+	holiday, err := c.svc.calendar.HolidayOn(ctx, date, enrollment.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil {
+		return &nonSchoolDay{reason: nonSchoolDayHoliday, detail: holiday.Title}, nil
+	}
+	return nil, nil
+}
+
+func (c *schoolDayChecker) enrollment(ctx context.Context, id string) (*models.Enrollment, error) {
+	if enrollment, ok := c.enrollments[id]; ok {
+		return enrollment, nil
+	}
+	enrollment, err := c.svc.enrollments.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, fmt.Sprintf("enrollment %s not found", id))
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+	}
+	c.enrollments[id] = enrollment
+	return enrollment, nil
+}
+
+func (c *schoolDayChecker) term(ctx context.Context, id string) (*models.Term, error) {
+	if term, ok := c.terms[id]; ok {
+		return term, nil
+	}
+	term, err := c.svc.terms.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.terms[id] = nil
+			return nil, nil
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+	}
+	c.terms[id] = term
+	return term, nil
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// warningSet collects distinct warnings in insertion order.
+type warningSet struct {
+	seen  map[string]struct{}
+	items []string
+}
+
+func newWarningSet() *warningSet {
+	return &warningSet{seen: make(map[string]struct{})}
+}
+
+func (w *warningSet) add(warning string) {
+	if warning == "" {
+		return
+	}
+	if _, ok := w.seen[warning]; ok {
+		return
+	}
+	w.seen[warning] = struct{}{}
+	w.items = append(w.items, warning)
+}
+
+func (w *warningSet) list() []string {
+	return w.items
+}
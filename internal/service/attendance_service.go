@@ -8,19 +8,24 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type dailyAttendanceRepository interface {
 	List(ctx context.Context, filter models.DailyAttendanceFilter) ([]models.DailyAttendanceRecord, int, error)
-	Upsert(ctx context.Context, record *models.DailyAttendance) (*models.DailyAttendance, error)
-	BulkInsert(ctx context.Context, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error)
+	Upsert(ctx context.Context, exec sqlx.ExtContext, record *models.DailyAttendance) (*models.DailyAttendance, error)
+	BulkInsert(ctx context.Context, exec sqlx.ExtContext, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error)
+	InsertCheckIn(ctx context.Context, exec sqlx.ExtContext, record *models.DailyAttendance) (*models.DailyAttendance, bool, error)
 	ClassReport(ctx context.Context, classID string, date time.Time) ([]models.DailyAttendanceReportRow, error)
 	StudentHistory(ctx context.Context, studentID string, from, to *time.Time) ([]models.DailyAttendanceHistoryRow, error)
 	StudentSummary(ctx context.Context, studentID string, termID string) (*models.DailyAttendanceSummary, error)
+	ReplaceEvidence(ctx context.Context, exec sqlx.ExtContext, enrollmentID string, date time.Time, evidence []models.AttendanceEvidence) error
 }
 
 type subjectAttendanceRepository interface {
@@ -34,12 +39,21 @@ type subjectAttendanceRepository interface {
 type AttendanceService struct {
 	dailyRepo   dailyAttendanceRepository
 	subjectRepo subjectAttendanceRepository
+	calendar    attendanceCalendar
+	enrollments attendanceEnrollmentReader
+	terms       ports.TermReader
+	calendarCfg AttendanceCalendarConfig
+	onBulkWrite func()
+	events      *DomainEvents
+	absences    absenceQueue
+	evidence    attendanceEvidenceArchives
+	rates       *AttendanceRates
 	validator   *validator.Validate
 	logger      *zap.Logger
 }
 
 // NewAttendanceService constructs the attendance service.
-func NewAttendanceService(daily dailyAttendanceRepository, subject subjectAttendanceRepository, validate *validator.Validate, logger *zap.Logger) *AttendanceService {
+func NewAttendanceService(daily dailyAttendanceRepository, subject subjectAttendanceRepository, validate *validator.Validate, logger *zap.Logger, opts ...AttendanceServiceOption) *AttendanceService {
 	if validate == nil {
 		validate = validator.New()
 	}
@@ -47,17 +61,79 @@ func NewAttendanceService(daily dailyAttendanceRepository, subject subjectAttend
 		logger = zap.NewNop()
 	}
 	svc := &AttendanceService{dailyRepo: daily, subjectRepo: subject, validator: validate, logger: logger}
+	for _, opt := range opts {
+		opt(svc)
+	}
 	svc.validator.RegisterValidation("attendance_status", func(fl validator.FieldLevel) bool {
 		status := models.AttendanceStatus(strings.ToUpper(fl.Field().String()))
 		return status.Valid()
 	})
+	svc.validator.RegisterValidation("absence_reason", func(fl validator.FieldLevel) bool {
+		return models.AbsenceReason(strings.ToUpper(fl.Field().String())).Valid()
+	})
 	svc.validator.RegisterValidation("bulk_mode", func(fl validator.FieldLevel) bool {
-		mode := models.BulkOperationMode(strings.ToLower(fl.Field().String()))
-		return mode == models.BulkModeAtomic || mode == models.BulkModePartialOnError
+		mode := fl.Field().String()
+		return strings.EqualFold(mode, string(models.BulkModeAtomic)) || strings.EqualFold(mode, string(models.BulkModePartialOnError))
 	})
 	return svc
 }
 
+// WithBulkWriteHook calls hook after a bulk mark commits at least one record, e.g. to warm caches.
+func WithBulkWriteHook(hook func()) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		s.onBulkWrite = hook
+	}
+}
+
+// WithAttendanceEvents emits attendance.marked for every daily attendance row written.
+func WithAttendanceEvents(events *DomainEvents) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		s.events = events
+	}
+}
+
+// WithAttendanceRates computes attendance percentages with the configured formula instead of
+// models.DefaultAttendanceRateFormula.
+func WithAttendanceRates(rates *AttendanceRates) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		s.rates = rates
+	}
+}
+
+// RateFormula returns the formula attendance percentages are computed with.
+func (s *AttendanceService) RateFormula(ctx context.Context) models.AttendanceRateFormula {
+	return s.rates.Formula(ctx)
+}
+
+// absenceQueue is notified of daily attendance rows once they are stored.
+type absenceQueue interface {
+	QueueAbsences(ctx context.Context, records ...models.DailyAttendance) error
+}
+
+// WithAbsenceMessages queues messages to guardians for students marked absent.
+func WithAbsenceMessages(queue absenceQueue) AttendanceServiceOption {
+	return func(s *AttendanceService) {
+		s.absences = queue
+	}
+}
+
+// queueAbsences hands stored rows to the absence queue. Failures are logged rather than returned:
+// the attendance is already stored and must not be reported as failed.
+func (s *AttendanceService) queueAbsences(ctx context.Context, records ...models.DailyAttendance) {
+	if s.absences == nil || len(records) == 0 {
+		return
+	}
+	if err := s.absences.QueueAbsences(ctx, records...); err != nil {
+		s.logger.Warn("failed to queue absence messages", zap.Int("records", len(records)), zap.Error(err))
+	}
+}
+
+func (s *AttendanceService) notifyBulkWrite(result *BulkAttendanceResult) {
+	if s.onBulkWrite != nil && result.Success > 0 && !result.DryRun {
+		s.onBulkWrite()
+	}
+}
+
 // DailyAttendanceListRequest is used for listing daily attendance.
 type DailyAttendanceListRequest struct {
 	ClassID   string     `json:"class_id"`
@@ -72,26 +148,31 @@ type DailyAttendanceListRequest struct {
 	SortOrder string     `json:"sort_order"`
 }
 
-// MarkDailyAttendanceRequest describes payload for marking single daily attendance.
+// MarkDailyAttendanceRequest describes payload for marking single daily attendance. Reason refines
+// S and I marks and defaults to SICK and PERMISSION respectively.
 type MarkDailyAttendanceRequest struct {
 	EnrollmentID string  `json:"enrollment_id" validate:"required"`
 	Date         string  `json:"date" validate:"required"`
 	Status       string  `json:"status" validate:"required,attendance_status"`
+	Reason       *string `json:"reason" validate:"omitempty,absence_reason"`
 	Notes        *string `json:"notes"`
+	Makeup       bool    `json:"makeup"`
 }
 
 // BulkDailyAttendanceItem holds entries for bulk operations.
 type BulkDailyAttendanceItem struct {
 	EnrollmentID string  `json:"enrollment_id" validate:"required"`
 	Status       string  `json:"status" validate:"required,attendance_status"`
+	Reason       *string `json:"reason" validate:"omitempty,absence_reason"`
 	Notes        *string `json:"notes"`
 }
 
 // BulkMarkDailyAttendanceRequest describes the bulk mark payload.
 type BulkMarkDailyAttendanceRequest struct {
-	Date  string                    `json:"date" validate:"required"`
-	Items []BulkDailyAttendanceItem `json:"items" validate:"required,min=1,dive"`
-	Mode  string                    `json:"mode" validate:"required,bulk_mode"`
+	Date   string                    `json:"date" validate:"required"`
+	Items  []BulkDailyAttendanceItem `json:"items" validate:"required,min=1,dive"`
+	Mode   string                    `json:"mode" validate:"required,bulk_mode"`
+	Makeup bool                      `json:"makeup"`
 }
 
 // BulkAttendanceResult summarises bulk execution.
@@ -99,6 +180,8 @@ type BulkAttendanceResult struct {
 	Processed int                             `json:"processed"`
 	Success   int                             `json:"success"`
 	Conflicts []models.AttendanceBulkConflict `json:"conflicts,omitempty"`
+	Warnings  []string                        `json:"warnings,omitempty"`
+	DryRun    bool                            `json:"dry_run,omitempty"`
 }
 
 // SubjectAttendanceListRequest describes filters for subject attendance listing.
@@ -119,6 +202,7 @@ type MarkSubjectAttendanceRequest struct {
 	Date         string  `json:"date" validate:"required"`
 	Status       string  `json:"status" validate:"required,attendance_status"`
 	Notes        *string `json:"notes"`
+	Makeup       bool    `json:"makeup"`
 }
 
 // BulkSubjectAttendanceItem for bulk operations.
@@ -126,6 +210,9 @@ type BulkSubjectAttendanceItem struct {
 	EnrollmentID string  `json:"enrollment_id" validate:"required"`
 	Status       string  `json:"status" validate:"required,attendance_status"`
 	Notes        *string `json:"notes"`
+	// ScheduleID overrides the request's schedule for this item, as lesson group sessions record
+	// each student against their own class's schedule.
+	ScheduleID string `json:"schedule_id,omitempty"`
 }
 
 // BulkMarkSubjectAttendanceRequest describes a bulk subject attendance request.
@@ -134,6 +221,7 @@ type BulkMarkSubjectAttendanceRequest struct {
 	Date       string                      `json:"date" validate:"required"`
 	Mode       string                      `json:"mode" validate:"required,bulk_mode"`
 	Items      []BulkSubjectAttendanceItem `json:"items" validate:"required,min=1,dive"`
+	Makeup     bool                        `json:"makeup"`
 }
 
 // ListDaily returns paginated daily attendance.
@@ -184,43 +272,128 @@ func (s *AttendanceService) MarkDaily(ctx context.Context, req MarkDailyAttendan
 		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
 	}
 	status := models.AttendanceStatus(strings.ToUpper(req.Status))
-	record := &models.DailyAttendance{EnrollmentID: req.EnrollmentID, Date: date, Status: status, Notes: req.Notes}
-	stored, err := s.dailyRepo.Upsert(ctx, record)
+	reason, err := resolveAbsenceReason(status, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	notes, warning, err := s.newSchoolDayChecker().apply(ctx, req.EnrollmentID, date, req.Makeup, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+	record := &models.DailyAttendance{EnrollmentID: req.EnrollmentID, Date: date, Status: status, AbsenceReason: reason, Notes: notes}
+	var stored *models.DailyAttendance
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		var err error
+		if stored, err = s.dailyRepo.Upsert(ctx, exec, record); err != nil {
+			return nil, err
+		}
+		return attendanceMarkedEvents(*stored)
+	})
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to mark attendance")
 	}
+	s.queueAbsences(ctx, *stored)
+	if warning != "" {
+		stored.Warnings = append(stored.Warnings, warning)
+	}
 	return stored, nil
 }
 
-// BulkMarkDaily records daily attendance for multiple students.
-func (s *AttendanceService) BulkMarkDaily(ctx context.Context, req BulkMarkDailyAttendanceRequest) (*BulkAttendanceResult, error) {
+// RecordCheckIn stores a device check-in under the school day policy. The first record of the day
+// wins: created is false when the enrollment was already marked, manually or by an earlier scan.
+func (s *AttendanceService) RecordCheckIn(ctx context.Context, record models.DailyAttendance) (*models.DailyAttendance, bool, error) {
+	notes, warning, err := s.newSchoolDayChecker().apply(ctx, record.EnrollmentID, record.Date, false, record.Notes)
+	if err != nil {
+		return nil, false, err
+	}
+	record.Notes = notes
+	var stored *models.DailyAttendance
+	var created bool
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		var err error
+		if stored, created, err = s.dailyRepo.InsertCheckIn(ctx, exec, &record); err != nil || !created {
+			return nil, err
+		}
+		return attendanceMarkedEvents(*stored)
+	})
+	if err != nil {
+		return nil, false, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record check-in")
+	}
+	if warning != "" {
+		stored.Warnings = append(stored.Warnings, warning)
+	}
+	return stored, created, nil
+}
+
+// ValidateBulkDaily checks a bulk daily payload without writing it and returns the parsed date. Async
+// imports call it before accepting a job so malformed payloads fail fast.
+func (s *AttendanceService) ValidateBulkDaily(req BulkMarkDailyAttendanceRequest) (time.Time, error) {
 	if err := s.validator.Struct(req); err != nil {
-		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid payload")
+		return time.Time{}, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid payload")
 	}
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
+		return time.Time{}, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
+	}
+	seen := make(map[string]struct{}, len(req.Items))
+	for _, item := range req.Items {
+		if _, ok := seen[item.EnrollmentID]; ok {
+			return time.Time{}, appErrors.Clone(appErrors.ErrConflict, "duplicate enrollment in payload")
+		}
+		seen[item.EnrollmentID] = struct{}{}
+		if _, err := resolveAbsenceReason(models.AttendanceStatus(strings.ToUpper(item.Status)), item.Reason); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return date, nil
+}
+
+// BulkMarkDaily records daily attendance for multiple students.
+func (s *AttendanceService) BulkMarkDaily(ctx context.Context, req BulkMarkDailyAttendanceRequest) (*BulkAttendanceResult, error) {
+	date, err := s.ValidateBulkDaily(req)
+	if err != nil {
+		return nil, err
 	}
 	mode := models.BulkOperationMode(strings.ToLower(req.Mode))
-	seen := map[string]struct{}{}
+	checker := s.newSchoolDayChecker()
+	warnings := newWarningSet()
 	records := make([]models.DailyAttendance, len(req.Items))
 	for i, item := range req.Items {
-		key := fmt.Sprintf("%s|%s", item.EnrollmentID, date.Format("2006-01-02"))
-		if _, ok := seen[key]; ok {
-			return nil, appErrors.Clone(appErrors.ErrConflict, "duplicate enrollment in payload")
+		notes, warning, err := checker.apply(ctx, item.EnrollmentID, date, req.Makeup, item.Notes)
+		if err != nil {
+			return nil, err
 		}
-		seen[key] = struct{}{}
+		warnings.add(warning)
 		status := models.AttendanceStatus(strings.ToUpper(item.Status))
-		records[i] = models.DailyAttendance{EnrollmentID: item.EnrollmentID, Date: date, Status: status, Notes: item.Notes}
-	}
-	conflicts, err := s.dailyRepo.BulkInsert(ctx, records, mode == models.BulkModeAtomic)
+		reason, _ := resolveAbsenceReason(status, item.Reason)
+		records[i] = models.DailyAttendance{EnrollmentID: item.EnrollmentID, Date: date, Status: status, AbsenceReason: reason, Notes: notes}
+	}
+	var conflicts, written []models.DailyAttendance
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		var err error
+		if conflicts, err = s.dailyRepo.BulkInsert(ctx, exec, records, mode == models.BulkModeAtomic); err != nil {
+			return nil, err
+		}
+		skipped := make(map[string]bool, len(conflicts))
+		for _, conflict := range conflicts {
+			skipped[conflict.ID] = true
+		}
+		written = make([]models.DailyAttendance, 0, len(records))
+		for _, record := range records {
+			if !skipped[record.ID] {
+				written = append(written, record)
+			}
+		}
+		return attendanceMarkedEvents(written...)
+	})
 	if err != nil {
 		if mode == models.BulkModeAtomic {
 			return nil, appErrors.Wrap(err, appErrors.ErrConflict.Code, appErrors.ErrConflict.Status, err.Error())
 		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "bulk mark failed")
 	}
-	result := &BulkAttendanceResult{Processed: len(records), Success: len(records) - len(conflicts)}
+	s.queueAbsences(ctx, written...)
+	result := &BulkAttendanceResult{Processed: len(records), Success: len(records) - len(conflicts), Warnings: warnings.list(), DryRun: database.IsDryRun(ctx)}
 	if len(conflicts) > 0 {
 		result.Conflicts = make([]models.AttendanceBulkConflict, len(conflicts))
 		for i, conflict := range conflicts {
@@ -231,6 +404,7 @@ func (s *AttendanceService) BulkMarkDaily(ctx context.Context, req BulkMarkDaily
 			}
 		}
 	}
+	s.notifyBulkWrite(result)
 	return result, nil
 }
 
@@ -255,7 +429,7 @@ func (s *AttendanceService) StudentAttendanceReport(ctx context.Context, student
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch attendance history")
 	}
-	summary, err := s.dailyRepo.StudentSummary(ctx, studentID, termID)
+	summary, err := s.studentSummary(ctx, studentID, termID)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to summarise attendance")
 	}
@@ -264,13 +438,24 @@ func (s *AttendanceService) StudentAttendanceReport(ctx context.Context, student
 
 // AttendancePercentage returns attendance percentage for a student term.
 func (s *AttendanceService) AttendancePercentage(ctx context.Context, studentID, termID string) (*models.DailyAttendanceSummary, error) {
-	summary, err := s.dailyRepo.StudentSummary(ctx, studentID, termID)
+	summary, err := s.studentSummary(ctx, studentID, termID)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to calculate percentage")
 	}
 	return summary, nil
 }
 
+// studentSummary loads a student's status counts and applies the configured formula.
+func (s *AttendanceService) studentSummary(ctx context.Context, studentID, termID string) (*models.DailyAttendanceSummary, error) {
+	summary, err := s.dailyRepo.StudentSummary(ctx, studentID, termID)
+	if err != nil || summary == nil {
+		return summary, err
+	}
+	counts := models.AttendanceStatusCounts{Present: summary.Present, Sick: summary.Sick, Excused: summary.Excused, Absent: summary.Absent}
+	summary.Percent = s.RateFormula(ctx).Rate(counts)
+	return summary, nil
+}
+
 // ListSubject returns subject attendance list.
 func (s *AttendanceService) ListSubject(ctx context.Context, req SubjectAttendanceListRequest) ([]models.SubjectAttendanceRecord, *models.Pagination, error) {
 	if err := s.validator.Struct(req); err != nil {
@@ -315,17 +500,24 @@ func (s *AttendanceService) MarkSubject(ctx context.Context, req MarkSubjectAtte
 	if err != nil {
 		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
 	}
+	notes, warning, err := s.newSchoolDayChecker().apply(ctx, req.EnrollmentID, date, req.Makeup, req.Notes)
+	if err != nil {
+		return nil, err
+	}
 	record := &models.SubjectAttendance{
 		EnrollmentID: req.EnrollmentID,
 		ScheduleID:   req.ScheduleID,
 		Date:         date,
 		Status:       models.AttendanceStatus(strings.ToUpper(req.Status)),
-		Notes:        req.Notes,
+		Notes:        notes,
 	}
 	stored, err := s.subjectRepo.Upsert(ctx, record)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to mark subject attendance")
 	}
+	if warning != "" {
+		stored.Warnings = append(stored.Warnings, warning)
+	}
 	return stored, nil
 }
 
@@ -339,20 +531,31 @@ func (s *AttendanceService) BulkMarkSubject(ctx context.Context, req BulkMarkSub
 		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
 	}
 	mode := models.BulkOperationMode(strings.ToLower(req.Mode))
+	checker := s.newSchoolDayChecker()
+	warnings := newWarningSet()
 	seen := map[string]struct{}{}
 	records := make([]models.SubjectAttendance, len(req.Items))
 	for i, item := range req.Items {
-		key := fmt.Sprintf("%s|%s|%s", item.EnrollmentID, req.ScheduleID, date.Format("2006-01-02"))
+		scheduleID := req.ScheduleID
+		if item.ScheduleID != "" {
+			scheduleID = item.ScheduleID
+		}
+		key := fmt.Sprintf("%s|%s|%s", item.EnrollmentID, scheduleID, date.Format("2006-01-02"))
 		if _, ok := seen[key]; ok {
 			return nil, appErrors.Clone(appErrors.ErrConflict, "duplicate enrollment in payload")
 		}
 		seen[key] = struct{}{}
+		notes, warning, err := checker.apply(ctx, item.EnrollmentID, date, req.Makeup, item.Notes)
+		if err != nil {
+			return nil, err
+		}
+		warnings.add(warning)
 		records[i] = models.SubjectAttendance{
 			EnrollmentID: item.EnrollmentID,
-			ScheduleID:   req.ScheduleID,
+			ScheduleID:   scheduleID,
 			Date:         date,
 			Status:       models.AttendanceStatus(strings.ToUpper(item.Status)),
-			Notes:        item.Notes,
+			Notes:        notes,
 		}
 	}
 	conflicts, err := s.subjectRepo.BulkInsert(ctx, records, mode == models.BulkModeAtomic)
@@ -362,7 +565,7 @@ func (s *AttendanceService) BulkMarkSubject(ctx context.Context, req BulkMarkSub
 		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "bulk mark failed")
 	}
-	result := &BulkAttendanceResult{Processed: len(records), Success: len(records) - len(conflicts)}
+	result := &BulkAttendanceResult{Processed: len(records), Success: len(records) - len(conflicts), Warnings: warnings.list(), DryRun: database.IsDryRun(ctx)}
 	if len(conflicts) > 0 {
 		result.Conflicts = make([]models.AttendanceBulkConflict, len(conflicts))
 		for i, conflict := range conflicts {
@@ -375,6 +578,7 @@ func (s *AttendanceService) BulkMarkSubject(ctx context.Context, req BulkMarkSub
 			}
 		}
 	}
+	s.notifyBulkWrite(result)
 	return result, nil
 }
 
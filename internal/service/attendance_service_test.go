@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type dailyAttendanceRepoStub struct {
+	dailyAttendanceRepository
+	upserted []models.DailyAttendance
+	inserted []models.DailyAttendance
+	evidence []models.AttendanceEvidence
+}
+
+func (s *dailyAttendanceRepoStub) Upsert(ctx context.Context, exec sqlx.ExtContext, record *models.DailyAttendance) (*models.DailyAttendance, error) {
+	s.upserted = append(s.upserted, *record)
+	stored := *record
+	return &stored, nil
+}
+
+func (s *dailyAttendanceRepoStub) BulkInsert(ctx context.Context, exec sqlx.ExtContext, records []models.DailyAttendance, atomic bool) ([]models.DailyAttendance, error) {
+	s.inserted = append(s.inserted, records...)
+	return nil, nil
+}
+
+func (s *dailyAttendanceRepoStub) ReplaceEvidence(ctx context.Context, exec sqlx.ExtContext, enrollmentID string, date time.Time, evidence []models.AttendanceEvidence) error {
+	s.evidence = evidence
+	return nil
+}
+
+type attendanceCalendarStub struct {
+	holidays map[string]string
+}
+
+func (s attendanceCalendarStub) HolidayOn(ctx context.Context, date time.Time, classID string) (*models.CalendarEvent, error) {
+	if title, ok := s.holidays[date.Format("2006-01-02")]; ok {
+		return &models.CalendarEvent{Title: title, EventType: models.CalendarEventTypeHoliday}, nil
+	}
+	return nil, nil
+}
+
+type attendanceEnrollmentStub struct{}
+
+func (attendanceEnrollmentStub) FindByID(ctx context.Context, id string) (*models.Enrollment, error) {
+	if id == "missing" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Enrollment{ID: id, ClassID: "class-1", TermID: "term-1"}, nil
+}
+
+type attendanceTermStub struct{}
+
+func (attendanceTermStub) FindByID(ctx context.Context, id string) (*models.Term, error) {
+	return &models.Term{
+		ID:        id,
+		Name:      "Ganjil 2024",
+		StartDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func newCalendarAwareAttendanceService(policy string) (*AttendanceService, *dailyAttendanceRepoStub) {
+	repo := &dailyAttendanceRepoStub{}
+	calendar := attendanceCalendarStub{holidays: map[string]string{"2024-08-17": "Hari Kemerdekaan"}}
+	svc := NewAttendanceService(repo, nil, nil, nil,
+		WithSchoolDayValidation(calendar, attendanceEnrollmentStub{}, attendanceTermStub{}, AttendanceCalendarConfig{Policy: policy}),
+	)
+	return svc, repo
+}
+
+func TestAttendanceServiceMarkDailyRejectsNonSchoolDays(t *testing.T) {
+	svc, repo := newCalendarAwareAttendanceService(NonSchoolDayPolicyReject)
+	ctx := context.Background()
+
+	cases := map[string]string{
+		"sunday":       "2024-08-18",
+		"holiday":      "2024-08-17",
+		"outside term": "2025-01-06",
+	}
+	for name, date := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: date, Status: "H"})
+			require.Error(t, err)
+			assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+		})
+	}
+	assert.Empty(t, repo.upserted)
+
+	_, err := svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "missing", Date: "2024-08-19", Status: "H"})
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	stored, err := svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Status: "H"})
+	require.NoError(t, err)
+	assert.Empty(t, stored.Warnings)
+}
+
+func TestAttendanceServiceMarkDailyMakeupAnnotatesNotes(t *testing.T) {
+	svc, repo := newCalendarAwareAttendanceService(NonSchoolDayPolicyReject)
+	notes := "replacing Friday"
+
+	_, err := svc.MarkDaily(context.Background(), MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-17", Status: "H", Notes: &notes, Makeup: true})
+	require.NoError(t, err)
+	require.Len(t, repo.upserted, 1)
+	require.NotNil(t, repo.upserted[0].Notes)
+	assert.Equal(t, "Makeup session (HOLIDAY: Hari Kemerdekaan); replacing Friday", *repo.upserted[0].Notes)
+}
+
+func TestAttendanceServiceBulkMarkDailyWarnPolicy(t *testing.T) {
+	svc, repo := newCalendarAwareAttendanceService(NonSchoolDayPolicyWarn)
+
+	result, err := svc.BulkMarkDaily(context.Background(), BulkMarkDailyAttendanceRequest{
+		Date: "2024-08-18",
+		Mode: "atomic",
+		Items: []BulkDailyAttendanceItem{
+			{EnrollmentID: "enr-1", Status: "H"},
+			{EnrollmentID: "enr-2", Status: "A"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, repo.inserted, 2)
+	assert.Equal(t, []string{"2024-08-18 is not a school day (WEEKEND: Sunday)"}, result.Warnings)
+}
+
+func TestAttendanceServiceBulkMarkDailyDryRun(t *testing.T) {
+	svc, repo := newCalendarAwareAttendanceService(NonSchoolDayPolicyWarn)
+
+	result, err := svc.BulkMarkDaily(database.WithDryRun(context.Background()), BulkMarkDailyAttendanceRequest{
+		Date:  "2024-08-19",
+		Mode:  "atomic",
+		Items: []BulkDailyAttendanceItem{{EnrollmentID: "enr-1", Status: "H"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Success)
+	assert.Len(t, repo.inserted, 1)
+}
+
+type absenceQueueStub struct {
+	records []models.DailyAttendance
+}
+
+func (s *absenceQueueStub) QueueAbsences(ctx context.Context, records ...models.DailyAttendance) error {
+	s.records = append(s.records, records...)
+	return errors.New("messaging store unavailable")
+}
+
+func TestAttendanceServiceBulkMarkDailyQueuesAbsences(t *testing.T) {
+	queue := &absenceQueueStub{}
+	svc := NewAttendanceService(&dailyAttendanceRepoStub{}, nil, nil, nil, WithAbsenceMessages(queue))
+
+	result, err := svc.BulkMarkDaily(context.Background(), BulkMarkDailyAttendanceRequest{
+		Date: "2024-08-19",
+		Mode: "atomic",
+		Items: []BulkDailyAttendanceItem{
+			{EnrollmentID: "enr-1", Status: "H"},
+			{EnrollmentID: "enr-2", Status: "A"},
+		},
+	})
+	// A failure to queue messages does not fail the attendance that was already stored.
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Success)
+	require.Len(t, queue.records, 2)
+	assert.Equal(t, models.AttendanceStatusAbsent, queue.records[1].Status)
+}
+
+func TestAttendanceServiceValidateBulkDaily(t *testing.T) {
+	svc, _ := newCalendarAwareAttendanceService(NonSchoolDayPolicyWarn)
+	req := BulkMarkDailyAttendanceRequest{
+		Date:  "2024-08-19",
+		Mode:  "partialOnError",
+		Items: []BulkDailyAttendanceItem{{EnrollmentID: "enr-1", Status: "H"}, {EnrollmentID: "enr-2", Status: "A"}},
+	}
+
+	date, err := svc.ValidateBulkDaily(req)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-08-19", date.Format("2006-01-02"))
+
+	req.Items[1].EnrollmentID = "enr-1"
+	_, err = svc.ValidateBulkDaily(req)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	req.Mode = "bestEffort"
+	_, err = svc.ValidateBulkDaily(req)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestAttendanceServiceAbsenceReasons(t *testing.T) {
+	repo := &dailyAttendanceRepoStub{}
+	svc := NewAttendanceService(repo, nil, nil, nil)
+	ctx := context.Background()
+	duty, sick := "SCHOOL_DUTY", "SICK"
+
+	stored, err := svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Status: "s"})
+	require.NoError(t, err)
+	require.NotNil(t, stored.AbsenceReason)
+	assert.Equal(t, models.AbsenceReasonSick, *stored.AbsenceReason)
+
+	stored, err = svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Status: "I", Reason: &duty})
+	require.NoError(t, err)
+	assert.Equal(t, models.AbsenceReasonSchoolDuty, *stored.AbsenceReason)
+
+	stored, err = svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Status: "A"})
+	require.NoError(t, err)
+	assert.Nil(t, stored.AbsenceReason)
+
+	_, err = svc.MarkDaily(ctx, MarkDailyAttendanceRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Status: "I", Reason: &sick})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.ValidateBulkDaily(BulkMarkDailyAttendanceRequest{
+		Date:  "2024-08-19",
+		Mode:  "atomic",
+		Items: []BulkDailyAttendanceItem{{EnrollmentID: "enr-1", Status: "A", Reason: &sick}},
+	})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+type evidenceArchiveStub map[string]*models.ArchiveItem
+
+func (s evidenceArchiveStub) GetByID(ctx context.Context, id string) (*models.ArchiveItem, error) {
+	if item, ok := s[id]; ok {
+		return item, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func TestAttendanceServiceExcuseAbsenceLinksEvidence(t *testing.T) {
+	own, other := "stu-1", "stu-2"
+	deleted := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	archives := evidenceArchiveStub{
+		"note":    {ID: "note", Scope: models.ArchiveScopeStudent, RefStudentID: &own},
+		"other":   {ID: "other", Scope: models.ArchiveScopeStudent, RefStudentID: &other},
+		"class":   {ID: "class", Scope: models.ArchiveScopeClass},
+		"trashed": {ID: "trashed", Scope: models.ArchiveScopeStudent, RefStudentID: &own, DeletedAt: &deleted},
+	}
+	repo := &dailyAttendanceRepoStub{}
+	svc := NewAttendanceService(repo, nil, nil, nil, WithAbsenceEvidence(archives))
+	enrollment := &models.Enrollment{ID: "enr-1", StudentID: own}
+	ctx := context.Background()
+
+	stored, err := svc.ExcuseAbsence(ctx, enrollment, dto.AttendanceExcuseRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Reason: "SICK", EvidenceIDs: []string{"note", "note"}}, "teacher-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendanceStatusSick, stored.Status)
+	require.Len(t, repo.evidence, 1)
+	assert.Equal(t, "note", repo.evidence[0].ArchiveID)
+	assert.Equal(t, "teacher-1", repo.evidence[0].LinkedBy)
+	assert.Equal(t, "2024-08-19", repo.evidence[0].Date.Format("2006-01-02"))
+
+	for _, id := range []string{"other", "class", "trashed", "unknown"} {
+		_, err := svc.ExcuseAbsence(ctx, enrollment, dto.AttendanceExcuseRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Reason: "SICK", EvidenceIDs: []string{id}}, "teacher-1")
+		assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code, id)
+	}
+	_, err = svc.ExcuseAbsence(ctx, enrollment, dto.AttendanceExcuseRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Reason: "LATE"}, "teacher-1")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	withoutArchives := NewAttendanceService(repo, nil, nil, nil)
+	_, err = withoutArchives.ExcuseAbsence(ctx, enrollment, dto.AttendanceExcuseRequest{EnrollmentID: "enr-1", Date: "2024-08-19", Reason: "PERMISSION", EvidenceIDs: []string{"note"}}, "teacher-1")
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+}
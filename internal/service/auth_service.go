@@ -15,7 +15,9 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/auth"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -28,17 +30,27 @@ type authUserRepository interface {
 	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
 	FindRefreshToken(ctx context.Context, token string) (*models.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, id string, revokedAt time.Time) error
+	ListSessions(ctx context.Context, userID string, now time.Time) ([]models.RefreshToken, error)
+	TouchSession(ctx context.Context, userID, sessionID string, activeSince, at time.Time) (bool, error)
+	RevokeExcessSessions(ctx context.Context, userID string, keep int, at time.Time) (int64, error)
 	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
 }
 
 // AuthConfig defines configuration for authentication flows.
 type AuthConfig struct {
-	AccessTokenSecret  string
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
-	Issuer             string
-	Audience           []string
-	SingleSession      bool
+	AccessTokenSecret string
+	// SecondaryAccessTokenSecret is still accepted for verification while rotating secrets.
+	SecondaryAccessTokenSecret string
+	AccessTokenExpiry          time.Duration
+	RefreshTokenExpiry         time.Duration
+	Issuer                     string
+	Audience                   []string
+	SingleSession              bool
+	// MaxSessions caps the live sessions per role. A login beyond the cap ends the least recently
+	// active sessions; roles without an entry are unlimited.
+	MaxSessions map[models.UserRole]int
+	// IdleTimeout ends sessions without a refresh or heartbeat for that long. Zero disables it.
+	IdleTimeout time.Duration
 }
 
 // AuthService provides authentication use cases.
@@ -88,7 +100,8 @@ func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 		}
 	}
 
-	accessToken, _, err := s.generateAccessToken(user)
+	sessionID := uuid.NewString()
+	accessToken, _, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create access token")
 	}
@@ -98,21 +111,35 @@ func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create refresh token")
 	}
 
+	now := time.Now().UTC()
 	refreshToken := &models.RefreshToken{
-		ID:        uuid.NewString(),
-		UserID:    user.ID,
-		Token:     refreshTokenValue,
-		ExpiresAt: time.Now().UTC().Add(s.config.RefreshTokenExpiry),
-		CreatedAt: time.Now().UTC(),
-		Revoked:   false,
-		IPAddress: req.IP,
-		UserAgent: req.UserAgent,
+		ID:               uuid.NewString(),
+		UserID:           user.ID,
+		Token:            refreshTokenValue,
+		ExpiresAt:        now.Add(s.config.RefreshTokenExpiry),
+		CreatedAt:        now,
+		Revoked:          false,
+		IPAddress:        req.IP,
+		UserAgent:        req.UserAgent,
+		SessionID:        sessionID,
+		SessionStartedAt: now,
+		LastActiveAt:     now,
 	}
 
 	if err := s.repo.CreateRefreshToken(ctx, refreshToken); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to persist refresh token")
 	}
 
+	if limit := s.config.MaxSessions[user.Role]; limit > 0 && !s.config.SingleSession {
+		// The new session is the most recently active, so it is always among those kept.
+		ended, err := s.repo.RevokeExcessSessions(ctx, user.ID, limit, now)
+		if err != nil {
+			s.logger.Warn("failed to enforce session limit", zap.String("user_id", user.ID), zap.Error(err))
+		} else if ended > 0 {
+			s.logger.Info("sessions over the role limit ended", zap.String("user_id", user.ID), zap.Int("limit", limit), zap.Int64("ended", ended))
+		}
+	}
+
 	if err := s.repo.UpdateLastLogin(ctx, user.ID, time.Now().UTC()); err != nil {
 		s.logger.Warn("failed to update last login", zap.Error(err))
 	}
@@ -157,10 +184,18 @@ func (s *AuthService) RefreshToken(ctx context.Context, req models.RefreshTokenR
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch refresh token")
 	}
 
-	if storedToken.Revoked || time.Now().UTC().After(storedToken.ExpiresAt) {
+	now := time.Now().UTC()
+	if storedToken.Revoked || now.After(storedToken.ExpiresAt) {
 		return nil, appErrors.Clone(appErrors.ErrUnauthorized, "refresh token is expired or revoked")
 	}
 
+	if s.config.IdleTimeout > 0 && storedToken.LastActiveAt.Before(now.Add(-s.config.IdleTimeout)) {
+		if err := s.repo.RevokeRefreshToken(ctx, storedToken.ID, now); err != nil {
+			s.logger.Warn("failed to revoke idle refresh token", zap.Error(err))
+		}
+		return nil, appErrors.Clone(appErrors.ErrUnauthorized, "session expired after inactivity")
+	}
+
 	user, err := s.repo.FindByID(ctx, storedToken.UserID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -177,7 +212,16 @@ func (s *AuthService) RefreshToken(ctx context.Context, req models.RefreshTokenR
 		s.logger.Warn("failed to revoke used refresh token", zap.Error(err))
 	}
 
-	accessToken, _, err := s.generateAccessToken(user)
+	// Tokens issued before sessions were tracked start their own session here.
+	sessionID, startedAt := storedToken.SessionID, storedToken.SessionStartedAt
+	if sessionID == "" {
+		sessionID = storedToken.ID
+	}
+	if startedAt.IsZero() {
+		startedAt = storedToken.CreatedAt
+	}
+
+	accessToken, _, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to generate access token")
 	}
@@ -188,14 +232,17 @@ func (s *AuthService) RefreshToken(ctx context.Context, req models.RefreshTokenR
 	}
 
 	newRefresh := &models.RefreshToken{
-		ID:        uuid.NewString(),
-		UserID:    user.ID,
-		Token:     refreshTokenValue,
-		ExpiresAt: time.Now().UTC().Add(s.config.RefreshTokenExpiry),
-		CreatedAt: time.Now().UTC(),
-		Revoked:   false,
-		IPAddress: req.IP,
-		UserAgent: req.UserAgent,
+		ID:               uuid.NewString(),
+		UserID:           user.ID,
+		Token:            refreshTokenValue,
+		ExpiresAt:        now.Add(s.config.RefreshTokenExpiry),
+		CreatedAt:        now,
+		Revoked:          false,
+		IPAddress:        req.IP,
+		UserAgent:        req.UserAgent,
+		SessionID:        sessionID,
+		SessionStartedAt: startedAt,
+		LastActiveAt:     now,
 	}
 
 	if err := s.repo.CreateRefreshToken(ctx, newRefresh); err != nil {
@@ -255,6 +302,61 @@ func (s *AuthService) Logout(ctx context.Context, refreshToken string, userID st
 	return nil
 }
 
+// Sessions lists the live sessions of the caller, most recently active first.
+func (s *AuthService) Sessions(ctx context.Context, claims *models.JWTClaims) ([]dto.Session, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	tokens, err := s.repo.ListSessions(ctx, claims.UserID, time.Now().UTC())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list sessions")
+	}
+	sessions := make([]dto.Session, 0, len(tokens))
+	for _, token := range tokens {
+		session := dto.Session{
+			ID:           token.SessionID,
+			Current:      claims.SessionID != "" && token.SessionID == claims.SessionID,
+			StartedAt:    token.SessionStartedAt,
+			LastActiveAt: token.LastActiveAt,
+			ExpiresAt:    token.ExpiresAt,
+			IPAddress:    token.IPAddress,
+			UserAgent:    token.UserAgent,
+		}
+		if s.config.IdleTimeout > 0 {
+			idleAt := token.LastActiveAt.Add(s.config.IdleTimeout)
+			session.IdleExpiresAt = &idleAt
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// Heartbeat records activity on the caller's session so it does not run into the idle timeout
+// between refreshes. It fails once the session has ended, e.g. after a logout elsewhere or when a
+// newer login pushed it over the session limit.
+func (s *AuthService) Heartbeat(ctx context.Context, claims *models.JWTClaims) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	if claims.SessionID == "" {
+		// Tokens issued before sessions were tracked have nothing to record against.
+		return nil
+	}
+	now := time.Now().UTC()
+	var activeSince time.Time
+	if s.config.IdleTimeout > 0 {
+		activeSince = now.Add(-s.config.IdleTimeout)
+	}
+	touched, err := s.repo.TouchSession(ctx, claims.UserID, claims.SessionID, activeSince, now)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record session activity")
+	}
+	if !touched {
+		return appErrors.Clone(appErrors.ErrUnauthorized, "session has ended")
+	}
+	return nil
+}
+
 // ChangePassword changes the password for the given user ID.
 func (s *AuthService) ChangePassword(ctx context.Context, userID string, req models.ChangePasswordRequest) error {
 	if err := s.validator.Struct(req); err != nil {
@@ -299,14 +401,14 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID string, req mod
 	return nil
 }
 
+var _ auth.TokenVerifier = (*AuthService)(nil)
+
 // ValidateToken parses and validates an access token returning the claims.
 func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &models.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if token.Method != jwt.SigningMethodHS256 {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.config.AccessTokenSecret), nil
-	})
+	token, err := s.parseToken(tokenString, s.config.AccessTokenSecret)
+	if errors.Is(err, jwt.ErrTokenSignatureInvalid) && s.config.SecondaryAccessTokenSecret != "" {
+		token, err = s.parseToken(tokenString, s.config.SecondaryAccessTokenSecret)
+	}
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrUnauthorized.Code, appErrors.ErrUnauthorized.Status, "invalid token")
 	}
@@ -319,6 +421,15 @@ func (s *AuthService) ValidateToken(tokenString string) (*models.JWTClaims, erro
 	return claims, nil
 }
 
+func (s *AuthService) parseToken(tokenString, secret string) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, &models.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+}
+
 // ForgotPassword initiates the reset flow. Phase 1 stub.
 func (s *AuthService) ForgotPassword(ctx context.Context, req models.ResetPasswordRequest) error {
 	if err := s.validator.Struct(req); err != nil {
@@ -337,14 +448,15 @@ func (s *AuthService) ResetPassword(ctx context.Context, req models.ConfirmReset
 	return nil
 }
 
-func (s *AuthService) generateAccessToken(user *models.User) (string, time.Time, error) {
+func (s *AuthService) generateAccessToken(user *models.User, sessionID string) (string, time.Time, error) {
 	issuedAt := time.Now().UTC()
 	expiresAt := issuedAt.Add(s.config.AccessTokenExpiry)
 	claims := &models.JWTClaims{
-		UserID:   user.ID,
-		Role:     user.Role,
-		Email:    user.Email,
-		FullName: user.FullName,
+		UserID:    user.ID,
+		Role:      user.Role,
+		Email:     user.Email,
+		FullName:  user.FullName,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.config.Issuer,
 			Subject:   user.ID,
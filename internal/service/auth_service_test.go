@@ -29,6 +29,8 @@ type mockAuthRepo struct {
 	updatePasswordErr   error
 	auditLogs           []*models.AuditLog
 	lastLoginUpdated    bool
+	sessionsKept        []int
+	touched             bool
 }
 
 func (m *mockAuthRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -102,6 +104,32 @@ func (m *mockAuthRepo) RevokeRefreshToken(ctx context.Context, id string, revoke
 	return nil
 }
 
+func (m *mockAuthRepo) ListSessions(ctx context.Context, userID string, now time.Time) ([]models.RefreshToken, error) {
+	var sessions []models.RefreshToken
+	for _, token := range m.refreshTokens {
+		if token.UserID == userID && !token.Revoked && token.ExpiresAt.After(now) {
+			sessions = append(sessions, *token)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockAuthRepo) TouchSession(ctx context.Context, userID, sessionID string, activeSince, at time.Time) (bool, error) {
+	for _, token := range m.refreshTokens {
+		if token.UserID == userID && token.SessionID == sessionID && !token.Revoked && !token.LastActiveAt.Before(activeSince) {
+			token.LastActiveAt = at
+			m.touched = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockAuthRepo) RevokeExcessSessions(ctx context.Context, userID string, keep int, at time.Time) (int64, error) {
+	m.sessionsKept = append(m.sessionsKept, keep)
+	return 0, nil
+}
+
 func (m *mockAuthRepo) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
 	m.auditLogs = append(m.auditLogs, log)
 	return nil
@@ -152,6 +180,83 @@ func TestAuthServiceRefreshToken(t *testing.T) {
 	assert.True(t, repo.refreshTokens["token"].Revoked)
 }
 
+func TestAuthServiceLoginEnforcesRoleSessionLimit(t *testing.T) {
+	password, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	repo := &mockAuthRepo{userByEmail: &models.User{ID: "s1", Email: "student@example.com", PasswordHash: string(password), Active: true, Role: models.RoleStudent}}
+	svc := NewAuthService(repo, nil, nil, AuthConfig{
+		AccessTokenSecret:  "secret",
+		AccessTokenExpiry:  time.Hour,
+		RefreshTokenExpiry: time.Hour,
+		MaxSessions:        map[models.UserRole]int{models.RoleStudent: 2},
+	})
+
+	res, err := svc.Login(context.Background(), models.LoginRequest{Email: "student@example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, repo.sessionsKept)
+
+	claims, err := svc.ValidateToken(res.AccessToken)
+	require.NoError(t, err)
+	stored := repo.refreshTokens[res.RefreshToken]
+	require.NotNil(t, stored)
+	assert.Equal(t, stored.SessionID, claims.SessionID)
+	assert.Equal(t, stored.SessionStartedAt, stored.LastActiveAt)
+
+	repo.userByEmail.Role = models.RoleTeacher
+	_, err = svc.Login(context.Background(), models.LoginRequest{Email: "student@example.com", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, repo.sessionsKept, "roles without a limit keep every session")
+}
+
+func TestAuthServiceRefreshKeepsSessionAndEnforcesIdleTimeout(t *testing.T) {
+	user := &models.User{ID: "u1", Email: "user@example.com", Active: true, Role: models.RoleTeacher}
+	started := time.Now().UTC().Add(-48 * time.Hour)
+	repo := &mockAuthRepo{userByID: user, refreshTokens: map[string]*models.RefreshToken{
+		"active": {ID: "rt1", UserID: "u1", Token: "active", ExpiresAt: time.Now().Add(time.Hour), SessionID: "session-1", SessionStartedAt: started, LastActiveAt: time.Now().UTC().Add(-time.Hour)},
+		"idle":   {ID: "rt2", UserID: "u1", Token: "idle", ExpiresAt: time.Now().Add(time.Hour), SessionID: "session-2", SessionStartedAt: started, LastActiveAt: started},
+	}}
+	svc := NewAuthService(repo, nil, nil, AuthConfig{AccessTokenSecret: "secret", AccessTokenExpiry: time.Hour, RefreshTokenExpiry: time.Hour, IdleTimeout: 24 * time.Hour})
+
+	res, err := svc.RefreshToken(context.Background(), models.RefreshTokenRequest{RefreshToken: "active"})
+	require.NoError(t, err)
+	rotated := repo.refreshTokens[res.RefreshToken]
+	require.NotNil(t, rotated)
+	assert.Equal(t, "session-1", rotated.SessionID)
+	assert.Equal(t, started, rotated.SessionStartedAt)
+	assert.WithinDuration(t, time.Now(), rotated.LastActiveAt, time.Minute)
+
+	_, err = svc.RefreshToken(context.Background(), models.RefreshTokenRequest{RefreshToken: "idle"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrUnauthorized.Code, appErrors.FromError(err).Code)
+	assert.True(t, repo.refreshTokens["idle"].Revoked)
+
+	sessions, err := svc.Sessions(context.Background(), &models.JWTClaims{UserID: "u1", SessionID: "session-1"})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "session-1", sessions[0].ID)
+	assert.True(t, sessions[0].Current)
+	require.NotNil(t, sessions[0].IdleExpiresAt)
+	assert.Equal(t, rotated.LastActiveAt.Add(24*time.Hour), *sessions[0].IdleExpiresAt)
+}
+
+func TestAuthServiceHeartbeat(t *testing.T) {
+	repo := &mockAuthRepo{refreshTokens: map[string]*models.RefreshToken{
+		"live": {ID: "rt1", UserID: "u1", Token: "live", ExpiresAt: time.Now().Add(time.Hour), SessionID: "session-1", LastActiveAt: time.Now().UTC().Add(-time.Hour)},
+	}}
+	svc := NewAuthService(repo, nil, nil, AuthConfig{AccessTokenSecret: "secret", IdleTimeout: 24 * time.Hour})
+
+	require.NoError(t, svc.Heartbeat(context.Background(), &models.JWTClaims{UserID: "u1", SessionID: "session-1"}))
+	assert.True(t, repo.touched)
+	assert.WithinDuration(t, time.Now(), repo.refreshTokens["live"].LastActiveAt, time.Minute)
+
+	err := svc.Heartbeat(context.Background(), &models.JWTClaims{UserID: "u1", SessionID: "session-2"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrUnauthorized.Code, appErrors.FromError(err).Code)
+
+	repo.touched = false
+	require.NoError(t, svc.Heartbeat(context.Background(), &models.JWTClaims{UserID: "u1"}), "tokens from before session tracking are accepted")
+	assert.False(t, repo.touched)
+}
+
 func TestAuthServiceChangePassword(t *testing.T) {
 	oldHash, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.DefaultCost)
 	repo := &mockAuthRepo{userByEmail: &models.User{ID: "u1", PasswordHash: string(oldHash), Active: true}}
@@ -166,10 +271,27 @@ func TestValidateToken(t *testing.T) {
 	repo := &mockAuthRepo{}
 	svc := NewAuthService(repo, validator.New(), zap.NewNop(), AuthConfig{AccessTokenSecret: "secret", AccessTokenExpiry: time.Hour, RefreshTokenExpiry: time.Hour})
 	user := &models.User{ID: "u1", Email: "user@example.com", Role: models.RoleAdmin}
-	token, _, err := svc.generateAccessToken(user)
+	token, _, err := svc.generateAccessToken(user, "s1")
 	require.NoError(t, err)
 
 	claims, err := svc.ValidateToken(token)
 	require.NoError(t, err)
 	assert.Equal(t, user.ID, claims.UserID)
 }
+
+func TestValidateTokenAcceptsSecondarySecret(t *testing.T) {
+	repo := &mockAuthRepo{}
+	cfg := AuthConfig{AccessTokenSecret: "old-secret", AccessTokenExpiry: time.Hour, RefreshTokenExpiry: time.Hour}
+	user := &models.User{ID: "u1", Email: "user@example.com", Role: models.RoleAdmin}
+	token, _, err := NewAuthService(repo, nil, nil, cfg).generateAccessToken(user, "s1")
+	require.NoError(t, err)
+
+	cfg.AccessTokenSecret = "new-secret"
+	_, err = NewAuthService(repo, nil, nil, cfg).ValidateToken(token)
+	require.Error(t, err)
+
+	cfg.SecondaryAccessTokenSecret = "old-secret"
+	claims, err := NewAuthService(repo, nil, nil, cfg).ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/backup"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+)
+
+// BackupJobType labels backup jobs on the queue.
+const BackupJobType = "backup"
+
+// StorageBackups names the backup directory in /ready and StorageHealthService.Ensure.
+const StorageBackups = "backups"
+
+const backupListLimit = 50
+
+type backupStore interface {
+	Create(ctx context.Context, backup *models.Backup) error
+	FindByID(ctx context.Context, id string) (*models.Backup, error)
+	List(ctx context.Context, limit int) ([]models.Backup, error)
+	Active(ctx context.Context) (*models.Backup, error)
+	MarkProcessing(ctx context.Context, id string, at time.Time) error
+	Complete(ctx context.Context, id string, result models.BackupResult, at time.Time) error
+	Fail(ctx context.Context, id, message string, at time.Time) error
+	FailUnfinished(ctx context.Context, message string, at time.Time) (int, error)
+}
+
+type backupDumper interface {
+	Dump(ctx context.Context, w io.Writer) error
+}
+
+type backupFileStore interface {
+	SaveStream(filename string, r io.Reader) (string, error)
+	Delete(filename string) error
+}
+
+// BackupServiceParams wires the backup service. Storage is optional.
+type BackupServiceParams struct {
+	Store  backupStore
+	Dumper backupDumper
+	Files  backupFileStore
+	Queue  jobDispatcher
+	// Storage, when set, rejects new backups while the backup directory is full or not writable.
+	Storage storageGuard
+	Logger  *zap.Logger
+	Config  BackupConfig
+}
+
+// BackupConfig describes what goes into a backup and how it is sealed.
+type BackupConfig struct {
+	// Key is the 32-byte archive encryption key.
+	Key      []byte
+	Database string
+	Schemas  []string
+	// StorageDirs maps storage directory names to paths; their files are listed in the manifest.
+	StorageDirs map[string]string
+	// TempDir holds the dump while the archive is written; empty uses the system default.
+	TempDir string
+}
+
+// BackupService runs admin-triggered backups on a queue: a pg_dump of the configured schemas and a
+// manifest of the storage directories, packed into one encrypted archive in the backup directory.
+// Only one backup runs at a time.
+type BackupService struct {
+	store   backupStore
+	dumper  backupDumper
+	files   backupFileStore
+	queue   jobDispatcher
+	storage storageGuard
+	logger  *zap.Logger
+	cfg     BackupConfig
+	now     func() time.Time
+}
+
+// NewBackupService constructs the service.
+func NewBackupService(params BackupServiceParams) (*BackupService, error) {
+	if len(params.Config.Key) != backup.KeySize {
+		return nil, fmt.Errorf("backup encryption key must be %d bytes", backup.KeySize)
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &BackupService{
+		store:   params.Store,
+		dumper:  params.Dumper,
+		files:   params.Files,
+		queue:   params.Queue,
+		storage: params.Storage,
+		logger:  logger,
+		cfg:     params.Config,
+		now:     time.Now,
+	}, nil
+}
+
+// SetQueue attaches the queue backups are dispatched to. The queue's handler is the service's own
+// Handle, so it can only be created after the service.
+func (s *BackupService) SetQueue(queue jobDispatcher) {
+	s.queue = queue
+}
+
+// Trigger queues a backup unless one is already queued or running.
+func (s *BackupService) Trigger(ctx context.Context, actorID string) (*models.Backup, error) {
+	active, err := s.store.Active(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check running backups")
+	}
+	if active != nil {
+		return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("backup %s is already %s", active.ID, active.Status))
+	}
+	if s.storage != nil {
+		if err := s.storage.Ensure(StorageBackups); err != nil {
+			return nil, err
+		}
+	}
+	record := &models.Backup{Status: models.ReportStatusQueued, RequestedBy: actorID, CreatedAt: s.now().UTC()}
+	if err := s.store.Create(ctx, record); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create backup")
+	}
+	if err := s.queue.Enqueue(jobs.Job{ID: record.ID, Type: BackupJobType}); err != nil {
+		if failErr := s.store.Fail(ctx, record.ID, "failed to enqueue backup", s.now().UTC()); failErr != nil {
+			s.logger.Warn("failed to mark backup failed", zap.String("backup_id", record.ID), zap.Error(failErr))
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to enqueue backup")
+	}
+	return record, nil
+}
+
+// List returns the most recent backups, newest first.
+func (s *BackupService) List(ctx context.Context) ([]models.Backup, error) {
+	backups, err := s.store.List(ctx, backupListLimit)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list backups")
+	}
+	return backups, nil
+}
+
+// Get returns one backup.
+func (s *BackupService) Get(ctx context.Context, id string) (*models.Backup, error) {
+	record, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "backup not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load backup")
+	}
+	return record, nil
+}
+
+// FailInterrupted fails the backups a previous process left queued or running. The queue lives in
+// memory and a half-written archive cannot be resumed, so they would otherwise block new backups.
+func (s *BackupService) FailInterrupted(ctx context.Context) {
+	failed, err := s.store.FailUnfinished(ctx, "interrupted by a restart", s.now().UTC())
+	if err != nil {
+		s.logger.Warn("failed to clear interrupted backups", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		s.logger.Warn("interrupted backups marked failed", zap.Int("count", failed))
+	}
+}
+
+// Handle runs a queued backup. Failures are recorded on the backup rather than retried, since a
+// retry would dump a different point in time than the one requested.
+func (s *BackupService) Handle(ctx context.Context, job jobs.Job) error {
+	if err := s.store.MarkProcessing(ctx, job.ID, s.now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	result, err := s.produce(ctx, job.ID)
+	if err != nil {
+		s.logger.Error("backup failed", zap.String("backup_id", job.ID), zap.Error(err))
+		if failErr := s.store.Fail(ctx, job.ID, err.Error(), s.now().UTC()); failErr != nil {
+			s.logger.Warn("failed to mark backup failed", zap.String("backup_id", job.ID), zap.Error(failErr))
+		}
+		return nil
+	}
+	if err := s.store.Complete(ctx, job.ID, result, s.now().UTC()); err != nil {
+		return err
+	}
+	s.logger.Info("backup finished", zap.String("backup_id", job.ID), zap.String("file", result.FilePath), zap.Int64("bytes", result.SizeBytes))
+	return nil
+}
+
+// produce dumps the database to a temporary file and streams the encrypted archive into storage.
+func (s *BackupService) produce(ctx context.Context, id string) (models.BackupResult, error) {
+	var result models.BackupResult
+	dumpFile, err := os.CreateTemp(s.cfg.TempDir, "backup-*.dump")
+	if err != nil {
+		return result, fmt.Errorf("create dump file: %w", err)
+	}
+	defer os.Remove(dumpFile.Name()) //nolint:errcheck
+	dumpErr := s.dumper.Dump(ctx, dumpFile)
+	if closeErr := dumpFile.Close(); dumpErr == nil {
+		dumpErr = closeErr
+	}
+	if dumpErr != nil {
+		return result, dumpErr
+	}
+
+	storageFiles, err := backup.ScanStorage(s.cfg.StorageDirs)
+	if err != nil {
+		return result, err
+	}
+	now := s.now().UTC()
+	manifest := backup.Manifest{CreatedAt: now, Database: s.cfg.Database, Schemas: s.cfg.Schemas, Storage: storageFiles}
+
+	pr, pw := io.Pipe()
+	go func() {
+		enc, err := backup.NewEncrypter(pw, s.cfg.Key)
+		if err == nil {
+			err = backup.WriteArchive(enc, manifest, dumpFile.Name())
+			if closeErr := enc.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+	hash := sha256.New()
+	counter := &byteCounter{}
+	name := fmt.Sprintf("backup-%s-%s.sbk", now.Format("20060102-150405"), id)
+	if _, err := s.files.SaveStream(name, io.TeeReader(pr, io.MultiWriter(hash, counter))); err != nil {
+		pr.CloseWithError(err)
+		if delErr := s.files.Delete(name); delErr != nil {
+			s.logger.Warn("failed to remove partial backup", zap.String("file", name), zap.Error(delErr))
+		}
+		return result, err
+	}
+	return models.BackupResult{
+		FilePath:     name,
+		SizeBytes:    counter.n,
+		Checksum:     hex.EncodeToString(hash.Sum(nil)),
+		StorageFiles: len(storageFiles),
+	}, nil
+}
+
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
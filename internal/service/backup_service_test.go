@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/backup"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+)
+
+type backupStoreStub struct {
+	backups map[string]*models.Backup
+	results map[string]models.BackupResult
+}
+
+func newBackupStoreStub() *backupStoreStub {
+	return &backupStoreStub{backups: map[string]*models.Backup{}, results: map[string]models.BackupResult{}}
+}
+
+func (s *backupStoreStub) Create(ctx context.Context, b *models.Backup) error {
+	b.ID = "backup-" + string(rune('a'+len(s.backups)))
+	copied := *b
+	s.backups[b.ID] = &copied
+	return nil
+}
+
+func (s *backupStoreStub) FindByID(ctx context.Context, id string) (*models.Backup, error) {
+	if b, ok := s.backups[id]; ok {
+		return b, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *backupStoreStub) List(ctx context.Context, limit int) ([]models.Backup, error) {
+	var list []models.Backup
+	for _, b := range s.backups {
+		list = append(list, *b)
+	}
+	return list, nil
+}
+
+func (s *backupStoreStub) Active(ctx context.Context) (*models.Backup, error) {
+	for _, b := range s.backups {
+		if b.Status == models.ReportStatusQueued || b.Status == models.ReportStatusProcessing {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *backupStoreStub) MarkProcessing(ctx context.Context, id string, at time.Time) error {
+	b, ok := s.backups[id]
+	if !ok || b.Status != models.ReportStatusQueued {
+		return sql.ErrNoRows
+	}
+	b.Status = models.ReportStatusProcessing
+	b.StartedAt = &at
+	return nil
+}
+
+func (s *backupStoreStub) Complete(ctx context.Context, id string, result models.BackupResult, at time.Time) error {
+	b := s.backups[id]
+	b.Status = models.ReportStatusFinished
+	b.FilePath = &result.FilePath
+	b.FinishedAt = &at
+	s.results[id] = result
+	return nil
+}
+
+func (s *backupStoreStub) Fail(ctx context.Context, id, message string, at time.Time) error {
+	b := s.backups[id]
+	b.Status = models.ReportStatusFailed
+	b.ErrorMessage = &message
+	return nil
+}
+
+func (s *backupStoreStub) FailUnfinished(ctx context.Context, message string, at time.Time) (int, error) {
+	failed := 0
+	for id, b := range s.backups {
+		if b.Status == models.ReportStatusQueued || b.Status == models.ReportStatusProcessing {
+			_ = s.Fail(ctx, id, message, at)
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+type dumperStub struct {
+	data string
+	err  error
+}
+
+func (d dumperStub) Dump(ctx context.Context, w io.Writer) error {
+	if d.err != nil {
+		return d.err
+	}
+	_, err := io.WriteString(w, d.data)
+	return err
+}
+
+func newTestBackupService(t *testing.T, store *backupStoreStub, dumper backupDumper) (*BackupService, *queueStub, string, []byte) {
+	t.Helper()
+	root := t.TempDir()
+	files, err := storage.NewLocalStorage(filepath.Join(root, "backups"))
+	require.NoError(t, err)
+	archives := filepath.Join(root, "archives")
+	require.NoError(t, os.MkdirAll(archives, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(archives, "report-card.pdf"), []byte("pdf"), 0o644))
+
+	key := make([]byte, backup.KeySize)
+	_, err = rand.Read(key)
+	require.NoError(t, err)
+	queue := &queueStub{}
+	svc, err := NewBackupService(BackupServiceParams{
+		Store:  store,
+		Dumper: dumper,
+		Files:  files,
+		Queue:  queue,
+		Config: BackupConfig{Key: key, Database: "sma", Schemas: []string{"public"}, StorageDirs: map[string]string{StorageArchives: archives}, TempDir: root},
+	})
+	require.NoError(t, err)
+	svc.now = func() time.Time { return time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC) }
+	return svc, queue, filepath.Join(root, "backups"), key
+}
+
+func TestBackupServiceProducesEncryptedArchive(t *testing.T) {
+	store := newBackupStoreStub()
+	svc, queue, dir, key := newTestBackupService(t, store, dumperStub{data: "PGDMP"})
+
+	record, err := svc.Trigger(context.Background(), "admin-1")
+	require.NoError(t, err)
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, BackupJobType, queue.jobs[0].Type)
+
+	// Only one backup may be queued or running.
+	_, err = svc.Trigger(context.Background(), "admin-1")
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	require.NoError(t, svc.Handle(context.Background(), queue.jobs[0]))
+	stored := store.backups[record.ID]
+	require.Equal(t, models.ReportStatusFinished, stored.Status)
+	result := store.results[record.ID]
+	assert.Equal(t, 1, result.StorageFiles)
+
+	sealed, err := os.ReadFile(filepath.Join(dir, result.FilePath))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(sealed)), result.SizeBytes)
+	sum := sha256.Sum256(sealed)
+	assert.Equal(t, hex.EncodeToString(sum[:]), result.Checksum)
+	assert.NotContains(t, string(sealed), "PGDMP")
+
+	plain, err := backup.NewDecrypter(bytes.NewReader(sealed), key)
+	require.NoError(t, err)
+	var dump bytes.Buffer
+	manifest, err := backup.ReadArchive(plain, &dump)
+	require.NoError(t, err)
+	assert.Equal(t, "PGDMP", dump.String())
+	assert.Equal(t, "sma", manifest.Database)
+	require.Len(t, manifest.Storage, 1)
+	assert.Equal(t, "report-card.pdf", manifest.Storage[0].Path)
+
+	// The job is not run twice when the queue redelivers it.
+	require.NoError(t, svc.Handle(context.Background(), queue.jobs[0]))
+}
+
+func TestBackupServiceRecordsFailures(t *testing.T) {
+	store := newBackupStoreStub()
+	svc, queue, dir, _ := newTestBackupService(t, store, dumperStub{err: errors.New("pg_dump: connection refused")})
+
+	record, err := svc.Trigger(context.Background(), "admin-1")
+	require.NoError(t, err)
+	require.NoError(t, svc.Handle(context.Background(), queue.jobs[0]))
+	stored := store.backups[record.ID]
+	assert.Equal(t, models.ReportStatusFailed, stored.Status)
+	require.NotNil(t, stored.ErrorMessage)
+	assert.Contains(t, *stored.ErrorMessage, "connection refused")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A failed backup no longer blocks the next one.
+	_, err = svc.Trigger(context.Background(), "admin-1")
+	assert.NoError(t, err)
+}
+
+func TestBackupServiceFailsInterruptedBackups(t *testing.T) {
+	store := newBackupStoreStub()
+	svc, _, _, _ := newTestBackupService(t, store, dumperStub{})
+	store.backups["stale"] = &models.Backup{ID: "stale", Status: models.ReportStatusProcessing}
+
+	svc.FailInterrupted(context.Background())
+	assert.Equal(t, models.ReportStatusFailed, store.backups["stale"].Status)
+
+	_, err := NewBackupService(BackupServiceParams{Config: BackupConfig{Key: []byte("short")}})
+	assert.Error(t, err)
+}
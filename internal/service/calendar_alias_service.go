@@ -9,6 +9,7 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -16,30 +17,17 @@ type calendarEventProvider interface {
 	List(ctx context.Context, req CalendarListRequest) ([]models.CalendarEvent, *models.Pagination, error)
 }
 
-type aliasTermReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-	FindActive(ctx context.Context) (*models.Term, error)
-}
-
-type teacherAssignmentLister interface {
-	ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error)
-}
-
-type calendarAliasClassReader interface {
-	FindByID(ctx context.Context, id string) (*models.Class, error)
-}
-
 // CalendarAliasService exposes a thin adapter above CalendarService.
 type CalendarAliasService struct {
 	calendar    calendarEventProvider
-	terms       aliasTermReader
-	assignments teacherAssignmentLister
-	classes     calendarAliasClassReader
+	terms       ports.TermResolver
+	assignments ports.TeacherAssignmentLister
+	classes     ports.ClassReader
 	logger      *zap.Logger
 }
 
 // NewCalendarAliasService constructs the alias service.
-func NewCalendarAliasService(calendar calendarEventProvider, terms aliasTermReader, assignments teacherAssignmentLister, classes calendarAliasClassReader, logger *zap.Logger) *CalendarAliasService {
+func NewCalendarAliasService(calendar calendarEventProvider, terms ports.TermResolver, assignments ports.TeacherAssignmentLister, classes ports.ClassReader, logger *zap.Logger) *CalendarAliasService {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -192,6 +192,32 @@ func (s *CalendarService) Update(ctx context.Context, id string, req UpdateCalen
 	return event, nil
 }
 
+// HolidayOn returns the first holiday covering date for students of classID, or nil when there is none.
+func (s *CalendarService) HolidayOn(ctx context.Context, date time.Time, classID string) (*models.CalendarEvent, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	filter := models.CalendarFilter{
+		StartDate: &day,
+		EndDate:   &day,
+		Audience:  []models.AnnouncementAudience{models.AnnouncementAudienceAll, models.AnnouncementAudienceSiswa},
+		Page:      1,
+		PageSize:  200,
+	}
+	if classID != "" {
+		filter.Audience = append(filter.Audience, models.AnnouncementAudienceClass)
+		filter.ClassIDs = []string{classID}
+	}
+	events, _, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list calendar events")
+	}
+	for i := range events {
+		if strings.EqualFold(events[i].EventType, models.CalendarEventTypeHoliday) {
+			return &events[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // Delete removes a calendar event.
 func (s *CalendarService) Delete(ctx context.Context, id string) error {
 	if err := s.repo.Delete(ctx, id); err != nil {
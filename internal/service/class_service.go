@@ -20,6 +20,7 @@ type classRepository interface {
 	ExistsByName(ctx context.Context, name string, excludeID string) (bool, error)
 	Create(ctx context.Context, class *models.Class) error
 	Update(ctx context.Context, class *models.Class) error
+	UpdateFields(ctx context.Context, class *models.Class, columns []string) error
 	Delete(ctx context.Context, id string) error
 	CountClassSubjects(ctx context.Context, classID string) (int, error)
 	CountSchedules(ctx context.Context, classID string) (int, error)
@@ -46,6 +47,15 @@ type UpdateClassRequest struct {
 	HomeroomTeacherID *string `json:"homeroom_teacher_id"`
 }
 
+// PatchClassRequest updates only the fields present in the payload. An empty homeroom_teacher_id
+// clears the homeroom teacher.
+type PatchClassRequest struct {
+	Name              *string `json:"name" validate:"omitnil,required"`
+	Grade             *string `json:"grade" validate:"omitnil,required"`
+	Track             *string `json:"track" validate:"omitnil,required"`
+	HomeroomTeacherID *string `json:"homeroom_teacher_id"`
+}
+
 // AssignSubjectPayload describes class-subject assignment.
 type AssignSubjectPayload struct {
 	SubjectID string  `json:"subject_id" validate:"required"`
@@ -166,6 +176,54 @@ func (s *ClassService) Update(ctx context.Context, id string, req UpdateClassReq
 	return class, nil
 }
 
+// Patch applies the fields present in req and writes only those columns.
+func (s *ClassService) Patch(ctx context.Context, id string, req PatchClassRequest) (*models.Class, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid class payload")
+	}
+
+	class, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "class not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class")
+	}
+
+	var columns []string
+	if req.Name != nil {
+		exists, err := s.repo.ExistsByName(ctx, *req.Name, id)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check class name")
+		}
+		if exists {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "class name already exists")
+		}
+		class.Name = *req.Name
+		columns = append(columns, "name")
+	}
+	if req.Grade != nil {
+		class.Grade = *req.Grade
+		columns = append(columns, "grade")
+	}
+	if req.Track != nil {
+		class.Track = *req.Track
+		columns = append(columns, "track")
+	}
+	if req.HomeroomTeacherID != nil {
+		class.HomeroomTeacherID = normalizeOptional(req.HomeroomTeacherID)
+		columns = append(columns, "homeroom_teacher_id")
+	}
+	if len(columns) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "no fields to update")
+	}
+
+	if err := s.repo.UpdateFields(ctx, class, columns); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update class")
+	}
+	return class, nil
+}
+
 // Delete removes a class ensuring no schedules or subject mappings remain.
 func (s *ClassService) Delete(ctx context.Context, id string) error {
 	if _, err := s.repo.FindByID(ctx, id); err != nil {
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// Class timetable cell sources, from weakest to strongest.
+const (
+	ClassTimetableSemester = "SEMESTER"
+	ClassTimetableDaily    = "DAILY"
+	ClassTimetableExam     = "EXAM"
+)
+
+type timetableSemesterReader interface {
+	ListByTermClass(ctx context.Context, termID models.TermID, classID models.ClassID) ([]models.SemesterSchedule, error)
+}
+
+type timetableSlotReader interface {
+	ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error)
+}
+
+type timetableScheduleReader interface {
+	ListByClass(ctx context.Context, classID string) ([]models.Schedule, error)
+}
+
+type timetableExamReader interface {
+	List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error)
+}
+
+type timetableLeaveReader interface {
+	List(ctx context.Context, filter models.TeacherLeaveFilter) ([]models.TeacherLeave, error)
+}
+
+// ClassTimetableServiceParams groups the dependencies of ClassTimetableService.
+type ClassTimetableServiceParams struct {
+	Terms     ports.TermReader
+	Classes   ports.ClassReader
+	Subjects  ports.SubjectReader
+	Teachers  ports.TeacherReader
+	Semesters timetableSemesterReader
+	Slots     timetableSlotReader
+	Schedules timetableScheduleReader
+	// Exams, Leaves, Calendar and Labels are optional; their overrides are skipped when nil.
+	Exams     timetableExamReader
+	Leaves    timetableLeaveReader
+	Calendar  attendanceCalendar
+	Labels    SlotTimeLabeler
+	Validator *validator.Validate
+	Logger    *zap.Logger
+	// Location is the school's time zone; it decides the current week.
+	Location *time.Location
+}
+
+// ClassTimetableService resolves a class's effective timetable for a week: the published semester
+// schedule, replaced slot by slot by daily schedules, then by exam sittings, with holidays and dates
+// outside the term left blank and lessons of teachers on approved leave flagged.
+type ClassTimetableService struct {
+	terms     ports.TermReader
+	classes   ports.ClassReader
+	subjects  ports.SubjectReader
+	teachers  ports.TeacherReader
+	semesters timetableSemesterReader
+	slots     timetableSlotReader
+	schedules timetableScheduleReader
+	exams     timetableExamReader
+	leaves    timetableLeaveReader
+	calendar  attendanceCalendar
+	labels    SlotTimeLabeler
+	validator *validator.Validate
+	logger    *zap.Logger
+	location  *time.Location
+	now       func() time.Time
+}
+
+// NewClassTimetableService constructs the service.
+func NewClassTimetableService(params ClassTimetableServiceParams) *ClassTimetableService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	location := params.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return &ClassTimetableService{
+		terms:     params.Terms,
+		classes:   params.Classes,
+		subjects:  params.Subjects,
+		teachers:  params.Teachers,
+		semesters: params.Semesters,
+		slots:     params.Slots,
+		schedules: params.Schedules,
+		exams:     params.Exams,
+		leaves:    params.Leaves,
+		calendar:  params.Calendar,
+		labels:    params.Labels,
+		validator: validate,
+		logger:    logger,
+		location:  location,
+		now:       time.Now,
+	}
+}
+
+// timetableLesson is a weekly lesson before it is placed on a date.
+type timetableLesson struct {
+	source     string
+	subjectID  string
+	teacherID  string
+	room       string
+	scheduleID string
+}
+
+// Week returns the class's timetable for the week containing query.Week.
+func (s *ClassTimetableService) Week(ctx context.Context, classID string, query dto.ClassTimetableQuery) (*dto.ClassTimetable, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid timetable query")
+	}
+	week := s.now().In(s.location)
+	if query.Week != "" {
+		parsed, err := time.Parse("2006-01-02", query.Week)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "week must use YYYY-MM-DD format")
+		}
+		week = parsed
+	}
+	monday := weekMonday(week)
+
+	term, err := s.terms.FindByID(ctx, query.TermID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+	}
+	class, err := s.classes.FindByID(ctx, classID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "class not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class")
+	}
+
+	timetable := &dto.ClassTimetable{
+		ClassID:   class.ID,
+		ClassName: class.Name,
+		TermID:    term.ID,
+		WeekStart: monday.Format("2006-01-02"),
+		WeekEnd:   monday.AddDate(0, 0, 6).Format("2006-01-02"),
+		Slots:     []dto.ClassTimetableSlot{},
+		Days:      []dto.ClassTimetableDay{},
+	}
+	lessons, err := s.weeklyLessons(ctx, term.ID, class.ID, timetable)
+	if err != nil {
+		return nil, err
+	}
+	exams, err := s.weekExams(ctx, class.ID, monday)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := s.weekLeaves(ctx, monday)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newScheduleNameCache(s.subjects, s.teachers, nil)
+	slotSet := make(map[int]struct{})
+	for day := 1; day <= 7; day++ {
+		if day > 5 && len(lessons[day]) == 0 && len(exams[day]) == 0 {
+			continue
+		}
+		date := monday.AddDate(0, 0, day-1)
+		item := dto.ClassTimetableDay{
+			Date:      date.Format("2006-01-02"),
+			DayOfWeek: day,
+			DayName:   dayIndexToName(day),
+			Cells:     []dto.ClassTimetableCell{},
+		}
+		if outsideTerm(term, date) {
+			item.OutsideTerm = true
+			timetable.Days = append(timetable.Days, item)
+			continue
+		}
+		if s.calendar != nil {
+			holiday, err := s.calendar.HolidayOn(ctx, date, class.ID)
+			if err != nil {
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load holidays")
+			}
+			if holiday != nil {
+				item.Holiday = holiday.Title
+				timetable.Days = append(timetable.Days, item)
+				continue
+			}
+		}
+
+		cells := make(map[int]dto.ClassTimetableCell)
+		for slot, lesson := range lessons[day] {
+			cell := dto.ClassTimetableCell{
+				TimeSlot:    slot,
+				Source:      lesson.source,
+				SubjectID:   lesson.subjectID,
+				SubjectName: names.subject(ctx, lesson.subjectID),
+				TeacherID:   lesson.teacherID,
+				TeacherName: names.teacher(ctx, lesson.teacherID),
+				Room:        lesson.room,
+				ScheduleID:  lesson.scheduleID,
+			}
+			for _, leave := range leaves {
+				if leave.TeacherID == lesson.teacherID && leave.Covers(date) {
+					cell.TeacherOnLeave = true
+					cell.LeaveID = leave.ID
+					break
+				}
+			}
+			cells[slot] = cell
+		}
+		for _, exam := range exams[day] {
+			for slot := exam.StartSlot; slot <= exam.EndSlot; slot++ {
+				cells[slot] = dto.ClassTimetableCell{
+					TimeSlot:    slot,
+					Source:      ClassTimetableExam,
+					SubjectID:   exam.SubjectID,
+					SubjectName: names.subject(ctx, exam.SubjectID),
+					TeacherID:   exam.InvigilatorID,
+					TeacherName: names.teacher(ctx, exam.InvigilatorID),
+					Room:        exam.Room,
+					ExamID:      exam.ID,
+				}
+			}
+		}
+		for slot, cell := range cells {
+			slotSet[slot] = struct{}{}
+			item.Cells = append(item.Cells, cell)
+		}
+		sort.Slice(item.Cells, func(i, j int) bool { return item.Cells[i].TimeSlot < item.Cells[j].TimeSlot })
+		timetable.Days = append(timetable.Days, item)
+	}
+
+	labels := map[int]string{}
+	if s.labels != nil {
+		if labels, err = s.labels.SlotLabels(ctx, term.ID); err != nil {
+			s.logger.Warn("failed to load slot labels", zap.String("term_id", term.ID), zap.Error(err))
+		}
+	}
+	for slot := range slotSet {
+		timetable.Slots = append(timetable.Slots, dto.ClassTimetableSlot{TimeSlot: slot, Label: labels[slot]})
+	}
+	sort.Slice(timetable.Slots, func(i, j int) bool { return timetable.Slots[i].TimeSlot < timetable.Slots[j].TimeSlot })
+	return timetable, nil
+}
+
+// weeklyLessons indexes the lessons by day and slot: the latest published semester schedule first,
+// then the class's daily schedules for the term, which take its place where they overlap.
+func (s *ClassTimetableService) weeklyLessons(ctx context.Context, termID, classID string, timetable *dto.ClassTimetable) (map[int]map[int]timetableLesson, error) {
+	lessons := make(map[int]map[int]timetableLesson)
+	place := func(day, slot int, lesson timetableLesson) {
+		if day < 1 || day > 7 || slot <= 0 {
+			return
+		}
+		if lessons[day] == nil {
+			lessons[day] = make(map[int]timetableLesson)
+		}
+		lessons[day][slot] = lesson
+	}
+
+	versions, err := s.semesters.ListByTermClass(ctx, models.TermID(termID), models.ClassID(classID))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load semester schedules")
+	}
+	var published *models.SemesterSchedule
+	for i := range versions {
+		if versions[i].Status == models.SemesterScheduleStatusPublished && (published == nil || versions[i].Version > published.Version) {
+			published = &versions[i]
+		}
+	}
+	if published != nil {
+		timetable.ScheduleID = published.ID
+		timetable.ScheduleVersion = published.Version
+		slots, err := s.slots.ListBySchedule(ctx, published.ID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load semester schedule slots")
+		}
+		for _, slot := range slots {
+			room := ""
+			if slot.Room != nil {
+				room = *slot.Room
+			}
+			place(slot.DayOfWeek, slot.TimeSlot, timetableLesson{
+				source:    ClassTimetableSemester,
+				subjectID: slot.SubjectID.String(),
+				teacherID: slot.TeacherID.String(),
+				room:      room,
+			})
+		}
+	}
+
+	schedules, err := s.schedules.ListByClass(ctx, classID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load schedules")
+	}
+	for _, schedule := range schedules {
+		if schedule.TermID != termID {
+			continue
+		}
+		slot, err := strconv.Atoi(strings.TrimSpace(schedule.TimeSlot))
+		if err != nil {
+			s.logger.Warn("skipping schedule with non-numeric time slot", zap.String("schedule_id", schedule.ID), zap.String("time_slot", schedule.TimeSlot))
+			continue
+		}
+		place(dayStringToIndex(schedule.DayOfWeek), slot, timetableLesson{
+			source:     ClassTimetableDaily,
+			subjectID:  schedule.SubjectID,
+			teacherID:  schedule.TeacherID,
+			room:       schedule.Room,
+			scheduleID: schedule.ID,
+		})
+	}
+	return lessons, nil
+}
+
+// weekExams groups the class's exam sittings in the week by day.
+func (s *ClassTimetableService) weekExams(ctx context.Context, classID string, monday time.Time) (map[int][]models.ExamSchedule, error) {
+	byDay := make(map[int][]models.ExamSchedule)
+	if s.exams == nil {
+		return byDay, nil
+	}
+	exams, err := s.exams.List(ctx, models.ExamScheduleFilter{ClassID: classID})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load exam schedules")
+	}
+	start, end := monday.Format("2006-01-02"), monday.AddDate(0, 0, 6).Format("2006-01-02")
+	for _, exam := range exams {
+		date := exam.ExamDate.Format("2006-01-02")
+		if date < start || date > end {
+			continue
+		}
+		day := isoWeekday(dateOnly(exam.ExamDate))
+		byDay[day] = append(byDay[day], exam)
+	}
+	return byDay, nil
+}
+
+// weekLeaves returns the approved teacher leaves overlapping the week.
+func (s *ClassTimetableService) weekLeaves(ctx context.Context, monday time.Time) ([]models.TeacherLeave, error) {
+	if s.leaves == nil {
+		return nil, nil
+	}
+	sunday := monday.AddDate(0, 0, 6)
+	leaves, err := s.leaves.List(ctx, models.TeacherLeaveFilter{Status: models.TeacherLeaveApproved, From: &monday, To: &sunday})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher leaves")
+	}
+	return leaves, nil
+}
+
+// outsideTerm reports whether date falls outside the term's dates. Terms without dates cover every day.
+func outsideTerm(term *models.Term, date time.Time) bool {
+	day := date.Format("2006-01-02")
+	if !term.StartDate.IsZero() && day < term.StartDate.Format("2006-01-02") {
+		return true
+	}
+	return !term.EndDate.IsZero() && day > term.EndDate.Format("2006-01-02")
+}
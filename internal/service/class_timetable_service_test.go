@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type timetableScheduleStub []models.Schedule
+
+func (s timetableScheduleStub) ListByClass(ctx context.Context, classID string) ([]models.Schedule, error) {
+	return s, nil
+}
+
+type timetableExamStub []models.ExamSchedule
+
+func (s timetableExamStub) List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error) {
+	return s, nil
+}
+
+type timetableLeaveStub []models.TeacherLeave
+
+func (s timetableLeaveStub) List(ctx context.Context, filter models.TeacherLeaveFilter) ([]models.TeacherLeave, error) {
+	return s, nil
+}
+
+func newClassTimetableFixture() *ClassTimetableService {
+	room := "R101"
+	return NewClassTimetableService(ClassTimetableServiceParams{
+		Terms:    termLookupStub{},
+		Classes:  classLookupStub{},
+		Subjects: subjectLookupStub{},
+		Teachers: exportTeacherLookupStub{},
+		Semesters: &semesterScheduleRepoStub{items: []models.SemesterSchedule{
+			{ID: "v2", Version: 2, Status: models.SemesterScheduleStatusDraft},
+			{ID: "v1", Version: 1, Status: models.SemesterScheduleStatusPublished},
+		}},
+		Slots: &semesterScheduleSlotRepoStub{items: map[string][]models.SemesterScheduleSlot{
+			"v1": {
+				{DayOfWeek: 1, TimeSlot: 1, SubjectID: "math", TeacherID: "t1", Room: &room},
+				{DayOfWeek: 1, TimeSlot: 2, SubjectID: "bio", TeacherID: "t2"},
+				{DayOfWeek: 2, TimeSlot: 1, SubjectID: "math", TeacherID: "t1"},
+				{DayOfWeek: 3, TimeSlot: 1, SubjectID: "math", TeacherID: "t1"},
+			},
+			"v2": {{DayOfWeek: 1, TimeSlot: 1, SubjectID: "draft", TeacherID: "t9"}},
+		}},
+		Schedules: timetableScheduleStub{
+			{ID: "d1", TermID: "term-1", DayOfWeek: "MONDAY", TimeSlot: "2", SubjectID: "chem", TeacherID: "t3", Room: "Lab"},
+			{ID: "d2", TermID: "term-0", DayOfWeek: "MONDAY", TimeSlot: "1", SubjectID: "old", TeacherID: "t4"},
+		},
+		Exams: timetableExamStub{
+			{ID: "e1", SubjectID: "math", ExamDate: time.Date(2024, 7, 16, 0, 0, 0, 0, time.UTC), StartSlot: 1, EndSlot: 2, Room: "Hall", InvigilatorID: "t1"},
+			{ID: "e2", SubjectID: "bio", ExamDate: time.Date(2024, 7, 23, 0, 0, 0, 0, time.UTC), StartSlot: 1, EndSlot: 1},
+		},
+		Leaves: timetableLeaveStub{
+			{ID: "leave-1", TeacherID: "t1", StartDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)},
+		},
+		Calendar: attendanceCalendarStub{holidays: map[string]string{"2024-07-17": "Islamic New Year"}},
+		Labels:   StaticSlotLabels{1: "07:00-07:45", 2: "07:45-08:30"},
+	})
+}
+
+func TestClassTimetableServiceWeekResolvesOverrides(t *testing.T) {
+	svc := newClassTimetableFixture()
+
+	timetable, err := svc.Week(context.Background(), "class-1", dto.ClassTimetableQuery{TermID: "term-1", Week: "2024-07-18"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07-15", timetable.WeekStart)
+	assert.Equal(t, "2024-07-21", timetable.WeekEnd)
+	assert.Equal(t, "v1", timetable.ScheduleID)
+	assert.Equal(t, []dto.ClassTimetableSlot{{TimeSlot: 1, Label: "07:00-07:45"}, {TimeSlot: 2, Label: "07:45-08:30"}}, timetable.Slots)
+	require.Len(t, timetable.Days, 5)
+
+	monday := timetable.Days[0]
+	require.Len(t, monday.Cells, 2)
+	assert.Equal(t, ClassTimetableSemester, monday.Cells[0].Source)
+	assert.Equal(t, "Budi", monday.Cells[0].TeacherName)
+	assert.Equal(t, "R101", monday.Cells[0].Room)
+	assert.True(t, monday.Cells[0].TeacherOnLeave)
+	assert.Equal(t, "leave-1", monday.Cells[0].LeaveID)
+	assert.Equal(t, ClassTimetableDaily, monday.Cells[1].Source)
+	assert.Equal(t, "chem", monday.Cells[1].SubjectID)
+	assert.Equal(t, "d1", monday.Cells[1].ScheduleID)
+
+	tuesday := timetable.Days[1]
+	require.Len(t, tuesday.Cells, 2)
+	for _, cell := range tuesday.Cells {
+		assert.Equal(t, ClassTimetableExam, cell.Source)
+		assert.Equal(t, "e1", cell.ExamID)
+		assert.False(t, cell.TeacherOnLeave)
+	}
+
+	wednesday := timetable.Days[2]
+	assert.Equal(t, "Islamic New Year", wednesday.Holiday)
+	assert.Empty(t, wednesday.Cells)
+}
+
+func TestClassTimetableServiceWeekValidation(t *testing.T) {
+	svc := newClassTimetableFixture()
+
+	_, err := svc.Week(context.Background(), "class-1", dto.ClassTimetableQuery{})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Week(context.Background(), "class-1", dto.ClassTimetableQuery{TermID: "term-1", Week: "18/07/2024"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestClassTimetableServiceWeekOutsideTerm(t *testing.T) {
+	svc := newClassTimetableFixture()
+	svc.terms = termReaderFunc(func(id string) *models.Term {
+		return &models.Term{ID: id, StartDate: time.Date(2024, 7, 17, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)}
+	})
+	svc.now = func() time.Time { return time.Date(2024, 7, 16, 9, 0, 0, 0, time.UTC) }
+
+	timetable, err := svc.Week(context.Background(), "class-1", dto.ClassTimetableQuery{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07-15", timetable.WeekStart)
+	assert.True(t, timetable.Days[0].OutsideTerm)
+	assert.Empty(t, timetable.Days[0].Cells)
+	assert.True(t, timetable.Days[1].OutsideTerm)
+	assert.False(t, timetable.Days[3].OutsideTerm)
+}
+
+type termReaderFunc func(id string) *models.Term
+
+func (f termReaderFunc) FindByID(ctx context.Context, id string) (*models.Term, error) {
+	return f(id), nil
+}
@@ -5,13 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -22,21 +25,33 @@ type configurationRepository interface {
 	BulkUpsert(ctx context.Context, cfgs []models.Configuration) error
 }
 
-type configurationTermReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-}
-
-type configurationAuditLogger interface {
-	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
-}
-
 type allowedConfiguration struct {
 	Key          string
 	Type         models.ConfigurationType
 	Description  string
 	RequiresTerm bool
+	// TimeOfDay requires an "HH:MM" value.
+	TimeOfDay bool
+	// StatusList requires a comma separated list of attendance status codes.
+	StatusList bool
 }
 
+// AttendanceLateAfterKey holds the "HH:MM" time after which device check-ins are marked late.
+const AttendanceLateAfterKey = "attendance_late_after"
+
+// Attendance percentage formula: the statuses counted as present and those left out of the days
+// counted. Both hold comma separated status codes, e.g. "H" and "S,I".
+const (
+	AttendancePresentStatusesKey = "attendance_present_statuses"
+	AttendanceExcusedStatusesKey = "attendance_excused_statuses"
+)
+
+// Retention periods in days read by the retention purge; 0 keeps the data indefinitely.
+const (
+	RetentionRefreshTokensKey = "retention_refresh_tokens_days"
+	RetentionAuditLogsKey     = "retention_audit_logs_days"
+)
+
 var allowedConfigurationKeys = []string{
 	"active_term_id",
 	"default_dashboard_term_id",
@@ -44,6 +59,11 @@ var allowedConfigurationKeys = []string{
 	"enable_reports_ui",
 	"enable_archives_ui",
 	"school_display_name",
+	AttendanceLateAfterKey,
+	AttendancePresentStatusesKey,
+	AttendanceExcusedStatusesKey,
+	RetentionRefreshTokensKey,
+	RetentionAuditLogsKey,
 }
 
 var allowedConfigurations = map[string]allowedConfiguration{
@@ -80,6 +100,34 @@ var allowedConfigurations = map[string]allowedConfiguration{
 		Type:        models.ConfigurationTypeString,
 		Description: "Display name for the school shown in headers",
 	},
+	AttendanceLateAfterKey: {
+		Key:         AttendanceLateAfterKey,
+		Type:        models.ConfigurationTypeString,
+		Description: "Local time (HH:MM) after which device check-ins are marked late",
+		TimeOfDay:   true,
+	},
+	AttendancePresentStatusesKey: {
+		Key:         AttendancePresentStatusesKey,
+		Type:        models.ConfigurationTypeString,
+		Description: "Attendance statuses (comma separated codes) counted as present in attendance percentages; empty counts only H",
+		StatusList:  true,
+	},
+	AttendanceExcusedStatusesKey: {
+		Key:         AttendanceExcusedStatusesKey,
+		Type:        models.ConfigurationTypeString,
+		Description: "Attendance statuses (comma separated codes) left out of the days counted in attendance percentages",
+		StatusList:  true,
+	},
+	RetentionRefreshTokensKey: {
+		Key:         RetentionRefreshTokensKey,
+		Type:        models.ConfigurationTypeInteger,
+		Description: "Days expired or revoked refresh tokens are kept before they are purged; 0 keeps them",
+	},
+	RetentionAuditLogsKey: {
+		Key:         RetentionAuditLogsKey,
+		Type:        models.ConfigurationTypeInteger,
+		Description: "Days audit logs are kept before they are purged; 0 keeps them",
+	},
 }
 
 var builtinConfigurationDefaults = map[string]string{
@@ -95,15 +143,15 @@ type ConfigurationServiceConfig struct {
 // ConfigurationService orchestrates CRUD workflow for configuration entries.
 type ConfigurationService struct {
 	repo      configurationRepository
-	terms     configurationTermReader
-	audit     configurationAuditLogger
+	terms     ports.TermReader
+	audit     ports.AuditLogger
 	validator *validator.Validate
 	logger    *zap.Logger
 	defaults  map[string]string
 }
 
 // NewConfigurationService constructs a ConfigurationService.
-func NewConfigurationService(repo configurationRepository, terms configurationTermReader, audit configurationAuditLogger, validate *validator.Validate, logger *zap.Logger, cfg ConfigurationServiceConfig) *ConfigurationService {
+func NewConfigurationService(repo configurationRepository, terms ports.TermReader, audit ports.AuditLogger, validate *validator.Validate, logger *zap.Logger, cfg ConfigurationServiceConfig) *ConfigurationService {
 	if validate == nil {
 		validate = validator.New()
 	}
@@ -342,7 +390,25 @@ func (s *ConfigurationService) validateValue(ctx context.Context, meta allowedCo
 				return "", err
 			}
 		}
+		if meta.TimeOfDay {
+			if _, err := time.Parse("15:04", value); err != nil {
+				return "", appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("%s expects HH:MM value", meta.Key))
+			}
+		}
+		if meta.StatusList {
+			statuses, err := models.ParseAttendanceStatuses(value)
+			if err != nil {
+				return "", appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("%s expects comma separated attendance statuses: %v", meta.Key, err))
+			}
+			value = models.FormatAttendanceStatuses(statuses)
+		}
 		return value, nil
+	case models.ConfigurationTypeInteger:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || number < 0 {
+			return "", appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("%s expects a non-negative whole number", meta.Key))
+		}
+		return strconv.Itoa(number), nil
 	default:
 		return "", appErrors.Clone(appErrors.ErrValidation, "unsupported configuration type")
 	}
@@ -103,6 +103,39 @@ func TestConfigurationServiceUpdateInvalidKey(t *testing.T) {
 	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
 }
 
+func TestConfigurationServiceUpdateValidatesTimeOfDay(t *testing.T) {
+	service := NewConfigurationService(&configurationRepoStub{}, configurationTermRepoStub{}, &auditLoggerStub{}, validator.New(), nil, ConfigurationServiceConfig{})
+	_, err := service.Update(context.Background(), AttendanceLateAfterKey, "7.15", &models.JWTClaims{UserID: "admin"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	item, err := service.Update(context.Background(), AttendanceLateAfterKey, " 07:20 ", &models.JWTClaims{UserID: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "07:20", item.Value)
+}
+
+func TestConfigurationServiceUpdateValidatesStatusList(t *testing.T) {
+	service := NewConfigurationService(&configurationRepoStub{}, configurationTermRepoStub{}, &auditLoggerStub{}, validator.New(), nil, ConfigurationServiceConfig{})
+	_, err := service.Update(context.Background(), AttendancePresentStatusesKey, "H,LATE", &models.JWTClaims{UserID: "admin"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	item, err := service.Update(context.Background(), AttendanceExcusedStatusesKey, " s, i ,S ", &models.JWTClaims{UserID: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "S,I", item.Value)
+}
+
+func TestConfigurationServiceUpdateValidatesInteger(t *testing.T) {
+	service := NewConfigurationService(&configurationRepoStub{}, configurationTermRepoStub{}, &auditLoggerStub{}, validator.New(), nil, ConfigurationServiceConfig{})
+	for _, value := range []string{"-1", "30d", "1.5"} {
+		_, err := service.Update(context.Background(), RetentionAuditLogsKey, value, &models.JWTClaims{UserID: "admin"})
+		assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code, value)
+	}
+
+	item, err := service.Update(context.Background(), RetentionAuditLogsKey, " 0730 ", &models.JWTClaims{UserID: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, "730", item.Value)
+	assert.Equal(t, "INTEGER", item.Type)
+}
+
 func TestConfigurationServiceUpdateValidatesTerm(t *testing.T) {
 	termErr := sql.ErrNoRows
 	service := NewConfigurationService(&configurationRepoStub{}, configurationTermRepoStub{err: termErr}, &auditLoggerStub{}, validator.New(), nil, ConfigurationServiceConfig{})
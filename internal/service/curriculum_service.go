@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	// CurriculumStatusOnTrack means every topic planned up to the current week has been taught.
+	CurriculumStatusOnTrack = "ON_TRACK"
+	// CurriculumStatusBehind means at least one topic planned up to the current week is not taught yet.
+	CurriculumStatusBehind = "BEHIND"
+	// CurriculumStatusNoPlan means the subject has no topics defined for the term.
+	CurriculumStatusNoPlan = "NO_PLAN"
+)
+
+type curriculumStore interface {
+	ListTopics(ctx context.Context, subjectID, termID string) ([]models.CurriculumTopic, error)
+	FindTopic(ctx context.Context, id string) (*models.CurriculumTopic, error)
+	CreateTopic(ctx context.Context, topic *models.CurriculumTopic) error
+	UpdateTopic(ctx context.Context, topic *models.CurriculumTopic) error
+	DeleteTopic(ctx context.Context, id string) error
+	ListClassProgress(ctx context.Context, classID, subjectID, termID string) ([]models.CurriculumTopicProgress, error)
+	UpsertProgress(ctx context.Context, progress *models.CurriculumProgress) error
+	DeleteProgress(ctx context.Context, topicID, classID string) error
+	Coverage(ctx context.Context, filter models.CurriculumCoverageFilter) ([]models.CurriculumCoverageRow, error)
+}
+
+// CurriculumServiceParams groups constructor dependencies.
+type CurriculumServiceParams struct {
+	Store       curriculumStore
+	Terms       ports.TermReader
+	Subjects    ports.SubjectReader
+	Classes     ports.ClassReader
+	Assignments ports.TeacherAssignmentChecker
+	Validator   *validator.Validate
+	Logger      *zap.Logger
+}
+
+// CurriculumService manages syllabus topics, their per-class teaching progress and the coverage
+// report comparing what was planned up to the current week of the term with what was taught.
+type CurriculumService struct {
+	store       curriculumStore
+	terms       ports.TermReader
+	subjects    ports.SubjectReader
+	classes     ports.ClassReader
+	assignments ports.TeacherAssignmentChecker
+	validator   *validator.Validate
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewCurriculumService constructs the service.
+func NewCurriculumService(params CurriculumServiceParams) *CurriculumService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &CurriculumService{
+		store:       params.Store,
+		terms:       params.Terms,
+		subjects:    params.Subjects,
+		classes:     params.Classes,
+		assignments: params.Assignments,
+		validator:   validate,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// ListTopics returns the syllabus of a subject for a term.
+func (s *CurriculumService) ListTopics(ctx context.Context, subjectID, termID string) ([]models.CurriculumTopic, error) {
+	if subjectID == "" || termID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "subjectId and termId are required")
+	}
+	topics, err := s.store.ListTopics(ctx, subjectID, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list curriculum topics")
+	}
+	if topics == nil {
+		topics = []models.CurriculumTopic{}
+	}
+	return topics, nil
+}
+
+// CreateTopic adds a topic to the syllabus of a subject. The planned week must fall within the term.
+func (s *CurriculumService) CreateTopic(ctx context.Context, req dto.CurriculumTopicRequest) (*models.CurriculumTopic, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid curriculum topic payload")
+	}
+	term, err := s.term(ctx, req.TermID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.subjects.FindByID(ctx, req.SubjectID); err != nil {
+		return nil, referenceError(err, "subject")
+	}
+	if err := ensurePlannedWeek(term, req.PlannedWeek); err != nil {
+		return nil, err
+	}
+	topic := &models.CurriculumTopic{
+		SubjectID:   req.SubjectID,
+		TermID:      req.TermID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		PlannedWeek: req.PlannedWeek,
+	}
+	if err := s.store.CreateTopic(ctx, topic); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create curriculum topic")
+	}
+	return topic, nil
+}
+
+// UpdateTopic edits a topic's title, description or planned week.
+func (s *CurriculumService) UpdateTopic(ctx context.Context, id string, req dto.CurriculumTopicUpdateRequest) (*models.CurriculumTopic, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid curriculum topic payload")
+	}
+	topic, err := s.topic(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	term, err := s.term(ctx, topic.TermID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePlannedWeek(term, req.PlannedWeek); err != nil {
+		return nil, err
+	}
+	topic.Title = strings.TrimSpace(req.Title)
+	topic.Description = req.Description
+	topic.PlannedWeek = req.PlannedWeek
+	if err := s.store.UpdateTopic(ctx, topic); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "curriculum topic not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update curriculum topic")
+	}
+	return topic, nil
+}
+
+// DeleteTopic removes a topic together with its progress marks.
+func (s *CurriculumService) DeleteTopic(ctx context.Context, id string) error {
+	if err := s.store.DeleteTopic(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "curriculum topic not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete curriculum topic")
+	}
+	return nil
+}
+
+// ClassProgress lists the syllabus of a subject with the class's taught marks. Teachers only see
+// the classes and subjects they are assigned to.
+func (s *CurriculumService) ClassProgress(ctx context.Context, classID, subjectID, termID string, claims *models.JWTClaims) ([]models.CurriculumTopicProgress, error) {
+	if classID == "" || subjectID == "" || termID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "classId, subjectId and termId are required")
+	}
+	if err := s.ensureTeaches(ctx, claims, classID, subjectID, termID); err != nil {
+		return nil, err
+	}
+	items, err := s.store.ListClassProgress(ctx, classID, subjectID, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list curriculum progress")
+	}
+	if items == nil {
+		items = []models.CurriculumTopicProgress{}
+	}
+	return items, nil
+}
+
+// MarkTaught records that a topic was taught to a class. Marking again replaces the earlier date and notes.
+func (s *CurriculumService) MarkTaught(ctx context.Context, topicID string, req dto.CurriculumProgressRequest, claims *models.JWTClaims) (*models.CurriculumProgress, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid curriculum progress payload")
+	}
+	topic, err := s.topic(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.classes.FindByID(ctx, req.ClassID); err != nil {
+		return nil, referenceError(err, "class")
+	}
+	if err := s.ensureTeaches(ctx, claims, req.ClassID, topic.SubjectID, topic.TermID); err != nil {
+		return nil, err
+	}
+
+	today := dateOnly(s.now().UTC())
+	taughtOn := today
+	if req.TaughtOn != "" {
+		if taughtOn, err = time.Parse("2006-01-02", req.TaughtOn); err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "taughtOn must use YYYY-MM-DD format")
+		}
+		if taughtOn.After(today) {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "taughtOn cannot be in the future")
+		}
+	}
+	markedBy := claims.UserID
+	progress := &models.CurriculumProgress{
+		TopicID:  topic.ID,
+		ClassID:  req.ClassID,
+		TaughtOn: taughtOn,
+		Notes:    req.Notes,
+		MarkedBy: &markedBy,
+	}
+	if err := s.store.UpsertProgress(ctx, progress); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to mark curriculum topic")
+	}
+	return progress, nil
+}
+
+// UnmarkTaught clears a topic's taught mark for a class.
+func (s *CurriculumService) UnmarkTaught(ctx context.Context, topicID, classID string, claims *models.JWTClaims) error {
+	topic, err := s.topic(ctx, topicID)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureTeaches(ctx, claims, classID, topic.SubjectID, topic.TermID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteProgress(ctx, topic.ID, classID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "topic is not marked as taught for this class")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to unmark curriculum topic")
+	}
+	return nil
+}
+
+// Coverage reports planned vs. taught topics for every subject assignment of the term as of the
+// query date, which defaults to today.
+func (s *CurriculumService) Coverage(ctx context.Context, query dto.CurriculumCoverageQuery) (*dto.CurriculumCoverageResponse, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid curriculum coverage query")
+	}
+	date := dateOnly(s.now().UTC())
+	if query.Date != "" {
+		parsed, err := time.Parse("2006-01-02", query.Date)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "date must use YYYY-MM-DD format")
+		}
+		date = parsed
+	}
+	return s.coverage(ctx, models.CurriculumCoverageFilter{
+		TermID:    query.TermID,
+		ClassID:   query.ClassID,
+		SubjectID: query.SubjectID,
+		TeacherID: query.TeacherID,
+	}, date)
+}
+
+// TeacherCoverage returns the coverage of a teacher's own assignments, for the teacher dashboard.
+func (s *CurriculumService) TeacherCoverage(ctx context.Context, teacherID, termID string, date time.Time) ([]dto.CurriculumCoverage, error) {
+	result, err := s.coverage(ctx, models.CurriculumCoverageFilter{TermID: termID, TeacherID: teacherID}, dateOnly(date))
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+func (s *CurriculumService) coverage(ctx context.Context, filter models.CurriculumCoverageFilter, date time.Time) (*dto.CurriculumCoverageResponse, error) {
+	term, err := s.term(ctx, filter.TermID)
+	if err != nil {
+		return nil, err
+	}
+	filter.PlannedWeek = termWeek(term, date)
+	rows, err := s.store.Coverage(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to compute curriculum coverage")
+	}
+	items := make([]dto.CurriculumCoverage, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, buildCurriculumCoverage(row))
+	}
+	return &dto.CurriculumCoverageResponse{
+		TermID: term.ID,
+		Date:   date.Format("2006-01-02"),
+		Week:   filter.PlannedWeek,
+		Items:  items,
+	}, nil
+}
+
+func buildCurriculumCoverage(row models.CurriculumCoverageRow) dto.CurriculumCoverage {
+	item := dto.CurriculumCoverage{
+		ClassID:           row.ClassID,
+		SubjectID:         row.SubjectID,
+		TeacherID:         row.TeacherID,
+		TotalTopics:       row.TotalTopics,
+		PlannedToDate:     row.PlannedToDate,
+		Taught:            row.Taught,
+		Behind:            row.PlannedToDate - row.TaughtOnPlanned,
+		CoveragePercent:   100,
+		CompletionPercent: 0,
+		Status:            CurriculumStatusOnTrack,
+	}
+	if row.TotalTopics == 0 {
+		item.Status = CurriculumStatusNoPlan
+		return item
+	}
+	if row.PlannedToDate > 0 {
+		item.CoveragePercent = percentage(row.TaughtOnPlanned, row.PlannedToDate)
+	}
+	item.CompletionPercent = percentage(row.Taught, row.TotalTopics)
+	if item.Behind > 0 {
+		item.Status = CurriculumStatusBehind
+	}
+	return item
+}
+
+func (s *CurriculumService) ensureTeaches(ctx context.Context, claims *models.JWTClaims, classID, subjectID, termID string) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	switch claims.Role {
+	case models.RoleAdmin, models.RoleSuperAdmin:
+		return nil
+	case models.RoleTeacher:
+		ok, err := s.assignments.Exists(ctx, claims.UserID, classID, subjectID, termID)
+		if err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify teaching assignment")
+		}
+		if !ok {
+			return appErrors.Clone(appErrors.ErrForbidden, "you are not assigned to teach this subject in this class")
+		}
+		return nil
+	default:
+		return appErrors.ErrForbidden
+	}
+}
+
+func (s *CurriculumService) topic(ctx context.Context, id string) (*models.CurriculumTopic, error) {
+	topic, err := s.store.FindTopic(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "curriculum topic not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load curriculum topic")
+	}
+	return topic, nil
+}
+
+func (s *CurriculumService) term(ctx context.Context, id string) (*models.Term, error) {
+	term, err := s.terms.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+	}
+	return term, nil
+}
+
+// termWeek returns the 1-based week of the term containing date, or 0 before the term starts.
+func termWeek(term *models.Term, date time.Time) int {
+	start := dateOnly(term.StartDate)
+	date = dateOnly(date)
+	if date.Before(start) {
+		return 0
+	}
+	return int(date.Sub(start).Hours()/24)/7 + 1
+}
+
+func ensurePlannedWeek(term *models.Term, week int) error {
+	if term.EndDate.IsZero() {
+		return nil
+	}
+	if last := termWeek(term, term.EndDate); week > last {
+		return appErrors.Clone(appErrors.ErrValidation, "plannedWeek is beyond the last week of the term")
+	}
+	return nil
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
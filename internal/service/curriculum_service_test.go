@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type curriculumStoreStub struct {
+	topics   map[string]*models.CurriculumTopic
+	progress []models.CurriculumProgress
+	coverage []models.CurriculumCoverageRow
+	filter   models.CurriculumCoverageFilter
+}
+
+func (s *curriculumStoreStub) ListTopics(ctx context.Context, subjectID, termID string) ([]models.CurriculumTopic, error) {
+	return nil, nil
+}
+
+func (s *curriculumStoreStub) FindTopic(ctx context.Context, id string) (*models.CurriculumTopic, error) {
+	if topic, ok := s.topics[id]; ok {
+		return topic, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *curriculumStoreStub) CreateTopic(ctx context.Context, topic *models.CurriculumTopic) error {
+	topic.ID = "topic-new"
+	s.topics[topic.ID] = topic
+	return nil
+}
+
+func (s *curriculumStoreStub) UpdateTopic(ctx context.Context, topic *models.CurriculumTopic) error {
+	return nil
+}
+
+func (s *curriculumStoreStub) DeleteTopic(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *curriculumStoreStub) ListClassProgress(ctx context.Context, classID, subjectID, termID string) ([]models.CurriculumTopicProgress, error) {
+	return nil, nil
+}
+
+func (s *curriculumStoreStub) UpsertProgress(ctx context.Context, progress *models.CurriculumProgress) error {
+	s.progress = append(s.progress, *progress)
+	return nil
+}
+
+func (s *curriculumStoreStub) DeleteProgress(ctx context.Context, topicID, classID string) error {
+	return sql.ErrNoRows
+}
+
+func (s *curriculumStoreStub) Coverage(ctx context.Context, filter models.CurriculumCoverageFilter) ([]models.CurriculumCoverageRow, error) {
+	s.filter = filter
+	return s.coverage, nil
+}
+
+type curriculumAssignmentStub struct{ assigned map[string]bool }
+
+func (s curriculumAssignmentStub) Exists(ctx context.Context, teacherID, classID, subjectID, termID string) (bool, error) {
+	return s.assigned[teacherID+"|"+classID+"|"+subjectID+"|"+termID], nil
+}
+
+// newCurriculumFixture builds a service for a term starting Monday 5 January 2026, "today" being
+// Wednesday 28 January (week 4).
+func newCurriculumFixture() (*CurriculumService, *curriculumStoreStub) {
+	store := &curriculumStoreStub{topics: map[string]*models.CurriculumTopic{
+		"topic-1": {ID: "topic-1", SubjectID: "math", TermID: "term-1", Title: "Linear equations", PlannedWeek: 2},
+	}}
+	term := &models.Term{ID: "term-1",
+		StartDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 6, 26, 0, 0, 0, 0, time.UTC)}
+	svc := NewCurriculumService(CurriculumServiceParams{
+		Store:       store,
+		Terms:       examTermStub{term: term},
+		Subjects:    subjectLookupStub{subjects: map[string]struct{}{"math": {}}},
+		Classes:     classLookupStub{},
+		Assignments: curriculumAssignmentStub{assigned: map[string]bool{"teacher-1|class-1|math|term-1": true}},
+	})
+	svc.now = func() time.Time { return time.Date(2026, 1, 28, 9, 0, 0, 0, time.UTC) }
+	return svc, store
+}
+
+func TestCurriculumServiceMarkTaughtChecksAssignment(t *testing.T) {
+	svc, store := newCurriculumFixture()
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+
+	progress, err := svc.MarkTaught(ctx, "topic-1", dto.CurriculumProgressRequest{ClassID: "class-1"}, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-28", progress.TaughtOn.Format("2006-01-02"))
+	require.NotNil(t, progress.MarkedBy)
+	assert.Equal(t, "teacher-1", *progress.MarkedBy)
+
+	_, err = svc.MarkTaught(ctx, "topic-1", dto.CurriculumProgressRequest{ClassID: "class-2"}, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	_, err = svc.MarkTaught(ctx, "topic-1", dto.CurriculumProgressRequest{ClassID: "class-2", TaughtOn: "2026-01-20"}, admin)
+	require.NoError(t, err)
+	assert.Len(t, store.progress, 2)
+
+	_, err = svc.MarkTaught(ctx, "topic-1", dto.CurriculumProgressRequest{ClassID: "class-1", TaughtOn: "2026-02-02"}, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	err = svc.UnmarkTaught(ctx, "topic-1", "class-1", teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestCurriculumServiceCreateTopicRejectsWeekBeyondTerm(t *testing.T) {
+	svc, _ := newCurriculumFixture()
+
+	_, err := svc.CreateTopic(context.Background(), dto.CurriculumTopicRequest{SubjectID: "math", TermID: "term-1", Title: "Revision", PlannedWeek: 30})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	topic, err := svc.CreateTopic(context.Background(), dto.CurriculumTopicRequest{SubjectID: "math", TermID: "term-1", Title: " Revision ", PlannedWeek: 25})
+	require.NoError(t, err)
+	assert.Equal(t, "Revision", topic.Title)
+}
+
+func TestCurriculumServiceCoverage(t *testing.T) {
+	svc, store := newCurriculumFixture()
+	store.coverage = []models.CurriculumCoverageRow{
+		{ClassID: "class-1", SubjectID: "math", TeacherID: "teacher-1", TotalTopics: 10, PlannedToDate: 4, Taught: 3, TaughtOnPlanned: 2},
+		{ClassID: "class-2", SubjectID: "math", TeacherID: "teacher-2", TotalTopics: 10, PlannedToDate: 4, Taught: 5, TaughtOnPlanned: 4},
+		{ClassID: "class-3", SubjectID: "art", TeacherID: "teacher-3"},
+	}
+
+	result, err := svc.Coverage(context.Background(), dto.CurriculumCoverageQuery{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Week)
+	assert.Equal(t, 4, store.filter.PlannedWeek)
+	require.Len(t, result.Items, 3)
+
+	behind := result.Items[0]
+	assert.Equal(t, 2, behind.Behind)
+	assert.Equal(t, 50.0, behind.CoveragePercent)
+	assert.Equal(t, 30.0, behind.CompletionPercent)
+	assert.Equal(t, CurriculumStatusBehind, behind.Status)
+
+	assert.Equal(t, CurriculumStatusOnTrack, result.Items[1].Status)
+	assert.Equal(t, 100.0, result.Items[1].CoveragePercent)
+	assert.Equal(t, CurriculumStatusNoPlan, result.Items[2].Status)
+
+	items, err := svc.TeacherCoverage(context.Background(), "teacher-1", "term-1", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "teacher-1", store.filter.TeacherID)
+	assert.Equal(t, 0, store.filter.PlannedWeek)
+	assert.Len(t, items, 3)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState describes a cutover circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets calls through.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen short-circuits calls until the cooldown elapses.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen admits a single probe; its outcome closes or re-opens the circuit.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// circuitBreaker trips after consecutive failures and probes the target once per cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	onChange  func(CircuitState)
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, onChange func(CircuitState)) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now, onChange: onChange, state: CircuitClosed}
+}
+
+// State reports the current state, moving an expired open circuit to half-open.
+func (b *circuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	return b.state
+}
+
+// allow reports whether a call may proceed. In half-open only one probe is admitted at a time.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.transition(CircuitClosed)
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.transition(CircuitOpen)
+	}
+}
+
+func (b *circuitBreaker) expire() {
+	if b.state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.cooldown)) {
+		b.transition(CircuitHalfOpen)
+	}
+}
+
+func (b *circuitBreaker) transition(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
@@ -17,13 +17,21 @@ import (
 
 const (
 	segmentCookieName = "cutover_segment"
+
+	cutoverTargetLegacy = "legacy"
+	cutoverTargetGo     = "go"
 )
 
+// ErrCutoverCircuitOpen is returned when a target's circuit breaker rejects the call.
+var ErrCutoverCircuitOpen = errors.New("circuit open")
+
 // CutoverService coordinates feature flags and health probing for the legacy cutover.
 type CutoverService struct {
-	cfg     config.CutoverConfig
-	metrics *MetricsService
-	client  *http.Client
+	cfg      config.CutoverConfig
+	metrics  *MetricsService
+	client   *http.Client
+	breakers map[string]*circuitBreaker
+	timeouts map[string]time.Duration
 }
 
 // NewCutoverService constructs a CutoverService with sane defaults.
@@ -32,13 +40,39 @@ func NewCutoverService(cfg config.CutoverConfig, metrics *MetricsService) *Cutov
 	if timeout <= 0 {
 		timeout = 2 * time.Second
 	}
-	return &CutoverService{
+	svc := &CutoverService{
 		cfg:     cfg,
 		metrics: metrics,
 		client: &http.Client{
 			Timeout: timeout,
 		},
+		breakers: make(map[string]*circuitBreaker),
+		timeouts: map[string]time.Duration{
+			cutoverTargetLegacy: timeout,
+			cutoverTargetGo:     timeout,
+		},
+	}
+	if cfg.LegacyTimeout > 0 {
+		svc.timeouts[cutoverTargetLegacy] = cfg.LegacyTimeout
+	}
+	if cfg.GoTimeout > 0 {
+		svc.timeouts[cutoverTargetGo] = cfg.GoTimeout
+	}
+	for _, target := range []string{cutoverTargetLegacy, cutoverTargetGo} {
+		target := target
+		svc.breakers[target] = newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, func(state CircuitState) {
+			metrics.RecordCircuitState(target, state)
+		})
+	}
+	return svc
+}
+
+// LegacyHealthy reports whether the legacy circuit is not open.
+func (s *CutoverService) LegacyHealthy() bool {
+	if s == nil || s.breakers[cutoverTargetLegacy] == nil {
+		return true
 	}
+	return s.breakers[cutoverTargetLegacy].State() != CircuitOpen
 }
 
 // Stage determines the current rollout stage based on feature flags.
@@ -76,12 +110,18 @@ func (s *CutoverService) HeadersForRequest(r *http.Request) models.CutoverHeader
 
 	segment := s.segmentForRequest(r, segmentHeader)
 
-	return models.CutoverHeaders{
+	headers := models.CutoverHeaders{
 		StageHeader:   stageHeader,
 		Stage:         s.Stage(),
 		SegmentHeader: segmentHeader,
 		Segment:       segment,
 	}
+	// Serve from Go while legacy is failing its health checks.
+	if headers.Stage != models.CutoverStageFull && !s.LegacyHealthy() {
+		headers.Stage = models.CutoverStageFull
+		headers.Fallback = true
+	}
+	return headers
 }
 
 func (s *CutoverService) segmentForRequest(r *http.Request, headerName string) string {
@@ -134,12 +174,12 @@ func clientSource(r *http.Request) string {
 
 // PingLegacy probes the configured legacy health endpoint.
 func (s *CutoverService) PingLegacy(ctx context.Context) (models.CutoverPingResult, error) {
-	return s.ping(ctx, "legacy", s.cfg.LegacyHealthURL)
+	return s.ping(ctx, cutoverTargetLegacy, s.cfg.LegacyHealthURL)
 }
 
 // PingGo probes the Go API health endpoint.
 func (s *CutoverService) PingGo(ctx context.Context) (models.CutoverPingResult, error) {
-	return s.ping(ctx, "go", s.cfg.GoHealthURL)
+	return s.ping(ctx, cutoverTargetGo, s.cfg.GoHealthURL)
 }
 
 func (s *CutoverService) ping(ctx context.Context, target, url string) (models.CutoverPingResult, error) {
@@ -168,8 +208,26 @@ func (s *CutoverService) ping(ctx context.Context, target, url string) (models.C
 		client = &http.Client{Timeout: timeout}
 	}
 
+	breaker := s.breakers[target]
+	if breaker != nil {
+		if !breaker.allow() {
+			result.Circuit = string(CircuitOpen)
+			result.Error = ErrCutoverCircuitOpen.Error()
+			return result, fmt.Errorf("%s health check skipped: %w", target, ErrCutoverCircuitOpen)
+		}
+	}
+
+	if timeout := s.timeouts[target]; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
+		if breaker != nil {
+			breaker.record(false)
+		}
 		result.Error = err.Error()
 		return result, err
 	}
@@ -193,6 +251,11 @@ func (s *CutoverService) ping(ctx context.Context, target, url string) (models.C
 		result.Reachable = resp.StatusCode < http.StatusInternalServerError
 	}
 
+	if breaker != nil {
+		breaker.record(result.Reachable)
+		result.Circuit = string(breaker.State())
+	}
+
 	if s.metrics != nil {
 		s.metrics.ObserveHTTPRequest(http.MethodGet, fmt.Sprintf("cutover_%s_health", target), statusCode, duration)
 	}
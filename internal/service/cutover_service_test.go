@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/pkg/config"
 )
 
@@ -142,6 +144,76 @@ func TestPingMissingURL(t *testing.T) {
 	}
 }
 
+func TestPingLegacyCircuitBreaker(t *testing.T) {
+	calls := 0
+	healthy := false
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			status := http.StatusBadGateway
+			if healthy {
+				status = http.StatusOK
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header), Request: req}, nil
+		}),
+	}
+
+	svc := NewCutoverService(config.CutoverConfig{LegacyHealthURL: "http://legacy.test/health", BreakerThreshold: 2, BreakerCooldown: time.Minute}, NewMetricsService())
+	svc.client = client
+	now := time.Now()
+	svc.breakers[cutoverTargetLegacy].now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.PingLegacy(context.Background()); err == nil {
+			t.Fatalf("expected failure")
+		}
+	}
+	res, err := svc.PingLegacy(context.Background())
+	if !errors.Is(err, ErrCutoverCircuitOpen) || res.Circuit != string(CircuitOpen) {
+		t.Fatalf("expected open circuit, got %v (%s)", err, res.Circuit)
+	}
+	if calls != 2 {
+		t.Fatalf("open circuit should not call legacy, calls = %d", calls)
+	}
+
+	headers := svc.HeadersForRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	if headers.Stage != models.CutoverStageFull || !headers.Fallback {
+		t.Fatalf("expected fallback to go, got %s fallback=%v", headers.Stage, headers.Fallback)
+	}
+
+	now = now.Add(time.Minute)
+	healthy = true
+	res, err = svc.PingLegacy(context.Background())
+	if err != nil || res.Circuit != string(CircuitClosed) {
+		t.Fatalf("expected half-open probe to close circuit, got %v (%s)", err, res.Circuit)
+	}
+	if !svc.LegacyHealthy() {
+		t.Fatalf("expected legacy healthy after successful probe")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAdmitsSingleProbe(t *testing.T) {
+	b := newCircuitBreaker(1, time.Second, nil)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.record(false)
+	if b.allow() {
+		t.Fatalf("open circuit should reject calls")
+	}
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatalf("half-open circuit should admit a probe")
+	}
+	if b.allow() {
+		t.Fatalf("half-open circuit should admit only one probe")
+	}
+	b.record(false)
+	if b.State() != CircuitOpen {
+		t.Fatalf("failed probe should re-open the circuit, got %s", b.State())
+	}
+}
+
 type roundTripperFunc func(*http.Request) (*http.Response, error)
 
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -12,21 +12,19 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
+// unacknowledgedAnnouncementLimit bounds the announcements listed in the admin acknowledgement widget.
+const unacknowledgedAnnouncementLimit = 5
+
 type analyticsSummaryProvider interface {
 	Attendance(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, bool, error)
 	Grades(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, bool, error)
 	Behavior(ctx context.Context, filter models.AnalyticsBehaviorFilter) ([]models.AnalyticsBehaviorSummary, bool, error)
 }
 
-type analyticsSummaryRepository interface {
-	AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error)
-	GradeSummary(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, error)
-	BehaviorSummary(ctx context.Context, filter models.AnalyticsBehaviorFilter) ([]models.AnalyticsBehaviorSummary, error)
-}
-
 type calendarLister interface {
 	List(ctx context.Context, req CalendarListRequest) ([]models.CalendarEvent, *models.Pagination, error)
 }
@@ -39,15 +37,33 @@ type scheduleLister interface {
 	ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error)
 }
 
-type assignmentLister interface {
-	ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error)
+type teacherPresenceProvider interface {
+	TodayStats(ctx context.Context) (*dto.TeacherPresenceStats, error)
+}
+
+type pendingMutationProvider interface {
+	PendingStats(ctx context.Context) (*dto.PendingMutationStats, error)
+}
+
+type announcementAckProvider interface {
+	UnacknowledgedAnnouncements(ctx context.Context, limit int) ([]dto.UnacknowledgedAnnouncement, error)
+}
+
+type curriculumCoverageProvider interface {
+	TeacherCoverage(ctx context.Context, teacherID, termID string, date time.Time) ([]dto.CurriculumCoverage, error)
+}
+
+type attendanceAlertReader interface {
+	ListAlerts(ctx context.Context, filter models.AttendanceAlertFilter) ([]models.AttendanceAlert, error)
+}
+
+type alertRuleBreachReader interface {
+	ListBreaches(ctx context.Context, filter models.AlertRuleBreachFilter) ([]models.AlertRuleBreach, error)
 }
 
 // DashboardServiceConfig tunes dashboard behaviour.
 type DashboardServiceConfig struct {
 	CacheTTL               time.Duration
-	LowAttendanceThreshold float64
-	GradeOutlierThreshold  float64
 	UpcomingEventsLimit    int
 	BehaviorLeaderboardMax int
 }
@@ -55,11 +71,18 @@ type DashboardServiceConfig struct {
 // DashboardService orchestrates composition of dashboard payloads.
 type DashboardService struct {
 	analytics     analyticsSummaryProvider
-	analyticsRepo analyticsSummaryRepository
+	analyticsRepo ports.AnalyticsRepository
 	calendar      calendarLister
 	announcements announcementLister
 	schedules     scheduleLister
-	assignments   assignmentLister
+	assignments   ports.TeacherAssignmentLister
+	slotLabels    SlotTimeLabeler
+	presence      teacherPresenceProvider
+	mutations     pendingMutationProvider
+	acks          announcementAckProvider
+	curriculum    curriculumCoverageProvider
+	alerts        attendanceAlertReader
+	rules         alertRuleBreachReader
 	cache         *CacheService
 	logger        *zap.Logger
 	now           func() time.Time
@@ -69,14 +92,30 @@ type DashboardService struct {
 // DashboardServiceParams groups constructor dependencies.
 type DashboardServiceParams struct {
 	Analytics     analyticsSummaryProvider
-	AnalyticsRepo analyticsSummaryRepository
+	AnalyticsRepo ports.AnalyticsRepository
 	Calendar      calendarLister
 	Announcements announcementLister
 	Schedules     scheduleLister
-	Assignments   assignmentLister
-	Cache         *CacheService
-	Logger        *zap.Logger
-	Config        DashboardServiceConfig
+	Assignments   ports.TeacherAssignmentLister
+	SlotLabels    SlotTimeLabeler
+	// TeacherPresence is optional; leave nil when teacher clock-in is disabled.
+	TeacherPresence teacherPresenceProvider
+	// PendingMutations is optional; leave nil when the mutation workflow is disabled.
+	PendingMutations pendingMutationProvider
+	// AnnouncementAcks is optional; when set the admin dashboard lists announcements still awaiting
+	// acknowledgements.
+	AnnouncementAcks announcementAckProvider
+	// Curriculum is optional; when set the teacher dashboard includes syllabus coverage.
+	Curriculum curriculumCoverageProvider
+	// AttendanceAlerts is optional; when set low attendance classes come from the nightly threshold
+	// evaluation instead of the alert rules.
+	AttendanceAlerts attendanceAlertReader
+	// AlertRules is optional; when set rule alerts are the breaches stored by the rule evaluation,
+	// otherwise the default rules are evaluated against the class averages the dashboard loads.
+	AlertRules alertRuleBreachReader
+	Cache      *CacheService
+	Logger     *zap.Logger
+	Config     DashboardServiceConfig
 }
 
 // NewDashboardService constructs a DashboardService with sane defaults.
@@ -85,12 +124,6 @@ func NewDashboardService(params DashboardServiceParams) *DashboardService {
 	if cfg.CacheTTL <= 0 {
 		cfg.CacheTTL = 5 * time.Minute
 	}
-	if cfg.LowAttendanceThreshold <= 0 {
-		cfg.LowAttendanceThreshold = 90
-	}
-	if cfg.GradeOutlierThreshold <= 0 {
-		cfg.GradeOutlierThreshold = 70
-	}
 	if cfg.UpcomingEventsLimit <= 0 {
 		cfg.UpcomingEventsLimit = 3
 	}
@@ -108,6 +141,13 @@ func NewDashboardService(params DashboardServiceParams) *DashboardService {
 		announcements: params.Announcements,
 		schedules:     params.Schedules,
 		assignments:   params.Assignments,
+		slotLabels:    params.SlotLabels,
+		presence:      params.TeacherPresence,
+		mutations:     params.PendingMutations,
+		acks:          params.AnnouncementAcks,
+		curriculum:    params.Curriculum,
+		alerts:        params.AttendanceAlerts,
+		rules:         params.AlertRules,
 		cache:         params.Cache,
 		logger:        logger,
 		now:           time.Now,
@@ -159,6 +199,27 @@ func (s *DashboardService) Teacher(ctx context.Context, teacherID, termID string
 	return summary, false, nil
 }
 
+// RefreshAdmin recomputes the admin dashboard for termID and overwrites its cache entry.
+func (s *DashboardService) RefreshAdmin(ctx context.Context, termID string) error {
+	summary, err := s.composeAdminSummary(ctx, termID)
+	if err != nil {
+		return err
+	}
+	s.persistCache(ctx, fmt.Sprintf("dash:admin:%s", termID), summary)
+	return nil
+}
+
+// RefreshTeacher recomputes a teacher dashboard for termID and date and overwrites its cache entry.
+func (s *DashboardService) RefreshTeacher(ctx context.Context, teacherID, termID string, date time.Time) error {
+	date = date.UTC()
+	summary, err := s.composeTeacherSummary(ctx, teacherID, termID, date)
+	if err != nil {
+		return err
+	}
+	s.persistCache(ctx, fmt.Sprintf("dash:teacher:%s:%s:%s", teacherID, termID, date.Format("2006-01-02")), summary)
+	return nil
+}
+
 func (s *DashboardService) tryAdminCache(ctx context.Context, key string) (*dto.AdminDashboardResponse, bool, error) {
 	if s.cache == nil {
 		return nil, false, nil
@@ -212,12 +273,18 @@ func (s *DashboardService) composeAdminSummary(ctx context.Context, termID strin
 		return nil, err
 	}
 
+	breaches, err := s.ruleBreaches(ctx, models.AlertRuleBreachFilter{TermID: termID}, attendanceSummaries, gradeSummaries)
+	if err != nil {
+		return nil, err
+	}
+
 	summary := &dto.AdminDashboardResponse{
 		TermID:     termID,
 		Attendance: s.buildAdminAttendance(attendanceSummaries),
 		Grades:     s.buildAdminGrades(gradeSummaries),
 		Behavior:   s.buildAdminBehavior(behaviorSummaries),
 		Ops:        s.buildOpsHighlights(ctx),
+		Alerts:     dashboardRuleAlerts(breaches),
 	}
 	return summary, nil
 }
@@ -242,12 +309,61 @@ func (s *DashboardService) composeTeacherSummary(ctx context.Context, teacherID,
 	}
 	sort.Strings(classIDs)
 
-	attendanceSummaries, err := s.loadAttendance(ctx, models.AnalyticsAttendanceFilter{TermID: termID})
-	if err != nil {
-		return nil, err
+	// The reads below are independent, so they run concurrently; only the teacher's classes are
+	// loaded from analytics.
+	var (
+		attendanceSummaries []models.AnalyticsAttendanceSummary
+		gradeSummaries      []models.AnalyticsGradeSummary
+		stored              []models.AttendanceAlert
+		breaches            []models.AlertRuleBreach
+		schedules           []models.Schedule
+		labels              map[int]string
+		curriculum          []dto.CurriculumCoverage
+	)
+	group, gctx := newFetchGroup(ctx)
+	if len(classIDs) > 0 {
+		group.Go(func() (err error) {
+			attendanceSummaries, err = s.loadAttendance(gctx, models.AnalyticsAttendanceFilter{TermID: termID, ClassIDs: classIDs})
+			return err
+		})
+		group.Go(func() (err error) {
+			gradeSummaries, err = s.loadGrades(gctx, models.AnalyticsGradeFilter{TermID: termID, ClassIDs: classIDs})
+			return err
+		})
+		if s.alerts != nil {
+			group.Go(func() (err error) {
+				stored, err = s.alerts.ListAlerts(gctx, models.AttendanceAlertFilter{TermID: termID, ClassIDs: classIDs})
+				return err
+			})
+		}
+		if s.rules != nil {
+			group.Go(func() (err error) {
+				breaches, err = s.rules.ListBreaches(gctx, models.AlertRuleBreachFilter{TermID: termID, ClassIDs: classIDs, Audiences: teacherAlertAudiences})
+				return err
+			})
+		}
 	}
-	gradeSummaries, err := s.loadGrades(ctx, models.AnalyticsGradeFilter{TermID: termID})
-	if err != nil {
+	if s.schedules != nil {
+		group.Go(func() (err error) {
+			schedules, err = s.schedules.ListByTeacher(gctx, teacherID)
+			return err
+		})
+		group.Go(func() error {
+			labels = s.loadSlotLabels(gctx, termID)
+			return nil
+		})
+	}
+	if s.curriculum != nil {
+		group.Go(func() error {
+			var err error
+			if curriculum, err = s.curriculum.TeacherCoverage(gctx, teacherID, termID, date); err != nil {
+				s.logger.Warn("curriculum coverage fetch failed", zap.Error(err))
+				curriculum = nil
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
 		return nil, err
 	}
 
@@ -255,57 +371,140 @@ func (s *DashboardService) composeTeacherSummary(ctx context.Context, teacherID,
 	for _, summary := range attendanceSummaries {
 		classAttendance[summary.ClassID] = summary.Percentage
 	}
-	classGrades := s.averageGradeByClass(gradeSummaries)
+	classGrades := averageGradeByClass(gradeSummaries)
 
 	classSnapshots := make([]dto.TeacherClassSummary, 0, len(classIDs))
-	alerts := dto.TeacherAlerts{}
 	for _, classID := range classIDs {
-		attendanceRate := classAttendance[classID]
-		averageGrade := classGrades[classID]
 		classSnapshots = append(classSnapshots, dto.TeacherClassSummary{
 			ClassID:        classID,
-			AttendanceRate: attendanceRate,
-			AverageGrade:   averageGrade,
+			AttendanceRate: classAttendance[classID],
+			AverageGrade:   classGrades[classID],
 		})
-		if attendanceRate > 0 && attendanceRate < s.cfg.LowAttendanceThreshold {
+	}
+	if s.rules == nil {
+		breaches = evaluateAlertRules(defaultAlertRules, classMetricValues(attendanceSummaries, gradeSummaries))
+	}
+	alerts := dto.TeacherAlerts{Rules: dashboardRuleAlerts(breaches)}
+	lowAttendance, gradeOutliers := map[string]struct{}{}, map[string]struct{}{}
+	for _, breach := range breaches {
+		switch breach.Metric {
+		case models.AlertMetricClassAttendanceRate:
+			lowAttendance[breach.ClassID] = struct{}{}
+		case models.AlertMetricClassAverageGrade:
+			gradeOutliers[breach.ClassID] = struct{}{}
+		}
+	}
+	for _, classID := range classIDs {
+		if _, ok := lowAttendance[classID]; ok && s.alerts == nil {
 			alerts.LowAttendanceClasses = append(alerts.LowAttendanceClasses, classID)
 		}
-		if averageGrade > 0 && averageGrade < s.cfg.GradeOutlierThreshold {
+		if _, ok := gradeOutliers[classID]; ok {
 			alerts.GradeOutliers = append(alerts.GradeOutliers, classID)
 		}
 	}
+	if s.alerts != nil && len(classIDs) > 0 {
+		alerts.LowAttendanceClasses, alerts.LowAttendanceStudents = summariseAttendanceAlerts(stored)
+	}
 
 	today := dto.TeacherScheduleSummary{Date: date.Format("2006-01-02")}
-	if s.schedules != nil {
-		schedules, err := s.schedules.ListByTeacher(ctx, teacherID)
-		if err != nil {
-			return nil, err
-		}
-		day := strings.ToUpper(date.Weekday().String())
-		for _, sched := range schedules {
-			if sched.TermID != termID || strings.ToUpper(sched.DayOfWeek) != day {
-				continue
-			}
-			today.Schedules = append(today.Schedules, dto.TeacherScheduleSlot{
-				ClassID:   sched.ClassID,
-				SubjectID: sched.SubjectID,
-				TimeSlot:  parseTimeSlotInt(sched.TimeSlot),
-				Room:      normaliseRoom(sched.Room),
-			})
+	day := strings.ToUpper(date.Weekday().String())
+	for _, sched := range schedules {
+		if sched.TermID != termID || strings.ToUpper(sched.DayOfWeek) != day {
+			continue
 		}
-		sort.Slice(today.Schedules, func(i, j int) bool {
-			return today.Schedules[i].TimeSlot < today.Schedules[j].TimeSlot
+		slot := parseTimeSlotInt(sched.TimeSlot)
+		today.Schedules = append(today.Schedules, dto.TeacherScheduleSlot{
+			ClassID:   sched.ClassID,
+			SubjectID: sched.SubjectID,
+			TimeSlot:  slot,
+			TimeLabel: labels[slot],
+			Room:      normaliseRoom(sched.Room),
 		})
 	}
+	sort.Slice(today.Schedules, func(i, j int) bool {
+		return today.Schedules[i].TimeSlot < today.Schedules[j].TimeSlot
+	})
 
 	return &dto.TeacherDashboardResponse{
-		TeacherID: teacherID,
-		Today:     today,
-		Classes:   classSnapshots,
-		Alerts:    alerts,
+		TeacherID:  teacherID,
+		Today:      today,
+		Classes:    classSnapshots,
+		Alerts:     alerts,
+		Curriculum: curriculum,
 	}, nil
 }
 
+// ruleBreaches returns the stored breaches matching filter or, without alert rules, evaluates the
+// default rules against the loaded summaries.
+func (s *DashboardService) ruleBreaches(ctx context.Context, filter models.AlertRuleBreachFilter, attendance []models.AnalyticsAttendanceSummary, grades []models.AnalyticsGradeSummary) ([]models.AlertRuleBreach, error) {
+	if s.rules == nil {
+		return evaluateAlertRules(defaultAlertRules, classMetricValues(attendance, grades)), nil
+	}
+	breaches, err := s.rules.ListBreaches(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load alert rule breaches")
+	}
+	return breaches, nil
+}
+
+// dashboardRuleAlerts converts breaches to their dashboard entries. Breaches evaluated on the fly
+// have no detection date.
+func dashboardRuleAlerts(breaches []models.AlertRuleBreach) []dto.DashboardRuleAlert {
+	items := make([]dto.DashboardRuleAlert, 0, len(breaches))
+	for _, breach := range breaches {
+		item := dto.DashboardRuleAlert{
+			RuleID:     breach.RuleID,
+			RuleName:   breach.RuleName,
+			Metric:     string(breach.Metric),
+			Comparator: string(breach.Comparator),
+			ClassID:    breach.ClassID,
+			Value:      breach.Value,
+			Threshold:  breach.Threshold,
+		}
+		if !breach.DetectedAt.IsZero() {
+			item.Since = breach.DetectedAt.Format("2006-01-02")
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// summariseAttendanceAlerts lists the classes with open attendance alerts and the students behind them.
+func summariseAttendanceAlerts(stored []models.AttendanceAlert) ([]string, []dto.TeacherAttendanceAlert) {
+	seen := map[string]struct{}{}
+	var classIDs []string
+	students := make([]dto.TeacherAttendanceAlert, 0, len(stored))
+	for _, alert := range stored {
+		if _, ok := seen[alert.ClassID]; !ok {
+			seen[alert.ClassID] = struct{}{}
+			classIDs = append(classIDs, alert.ClassID)
+		}
+		students = append(students, dto.TeacherAttendanceAlert{
+			StudentID:   alert.StudentID,
+			StudentName: alert.StudentName,
+			ClassID:     alert.ClassID,
+			Percentage:  alert.Percentage,
+			Threshold:   alert.Threshold,
+			Since:       alert.DetectedAt.Format("2006-01-02"),
+		})
+	}
+	sort.Strings(classIDs)
+	return classIDs, students
+}
+
+// loadSlotLabels resolves slot time labels; failures only drop the labels from the payload.
+func (s *DashboardService) loadSlotLabels(ctx context.Context, termID string) map[int]string {
+	if s.slotLabels == nil {
+		return nil
+	}
+	labels, err := s.slotLabels.SlotLabels(ctx, termID)
+	if err != nil {
+		s.logger.Warn("failed to load slot labels", zap.String("term_id", termID), zap.Error(err))
+		return nil
+	}
+	return labels
+}
+
 func (s *DashboardService) loadAttendance(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error) {
 	if s.analytics != nil {
 		if summaries, _, err := s.analytics.Attendance(ctx, filter); err == nil {
@@ -492,10 +691,31 @@ func (s *DashboardService) buildOpsHighlights(ctx context.Context) dto.AdminOper
 			highlights.OpenAnnouncements = pagination.TotalCount
 		}
 	}
+	if s.presence != nil {
+		if stats, err := s.presence.TodayStats(ctx); err != nil {
+			s.logger.Warn("teacher presence highlight fetch failed", zap.Error(err))
+		} else {
+			highlights.TeacherPresence = stats
+		}
+	}
+	if s.mutations != nil {
+		if stats, err := s.mutations.PendingStats(ctx); err != nil {
+			s.logger.Warn("pending mutation highlight fetch failed", zap.Error(err))
+		} else {
+			highlights.PendingMutations = stats
+		}
+	}
+	if s.acks != nil {
+		if pending, err := s.acks.UnacknowledgedAnnouncements(ctx, unacknowledgedAnnouncementLimit); err != nil {
+			s.logger.Warn("announcement acknowledgement highlight fetch failed", zap.Error(err))
+		} else {
+			highlights.UnacknowledgedAnnouncements = pending
+		}
+	}
 	return highlights
 }
 
-func (s *DashboardService) averageGradeByClass(summaries []models.AnalyticsGradeSummary) map[string]float64 {
+func averageGradeByClass(summaries []models.AnalyticsGradeSummary) map[string]float64 {
 	result := make(map[string]float64)
 	if len(summaries) == 0 {
 		return result
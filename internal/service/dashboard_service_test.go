@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
@@ -23,13 +24,18 @@ type fakeAnalytics struct {
 	attendanceHit bool
 	gradesHit     bool
 	behaviorHit   bool
+
+	attendanceFilter models.AnalyticsAttendanceFilter
+	gradesFilter     models.AnalyticsGradeFilter
 }
 
-func (f *fakeAnalytics) Attendance(context.Context, models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, bool, error) {
+func (f *fakeAnalytics) Attendance(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, bool, error) {
+	f.attendanceFilter = filter
 	return f.attendance, f.attendanceHit, f.attendanceErr
 }
 
-func (f *fakeAnalytics) Grades(context.Context, models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, bool, error) {
+func (f *fakeAnalytics) Grades(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, bool, error) {
+	f.gradesFilter = filter
 	return f.grades, f.gradesHit, f.gradesErr
 }
 
@@ -103,6 +109,34 @@ func (f *fakeSchedules) ListByTeacher(context.Context, string) ([]models.Schedul
 	return f.schedules, nil
 }
 
+type fakePresence struct {
+	stats *dto.TeacherPresenceStats
+	err   error
+}
+
+func (f *fakePresence) TodayStats(context.Context) (*dto.TeacherPresenceStats, error) {
+	return f.stats, f.err
+}
+
+type fakePendingMutations struct {
+	stats *dto.PendingMutationStats
+	err   error
+}
+
+func (f *fakePendingMutations) PendingStats(context.Context) (*dto.PendingMutationStats, error) {
+	return f.stats, f.err
+}
+
+type fakeAnnouncementAcks struct {
+	pending []dto.UnacknowledgedAnnouncement
+	limit   int
+}
+
+func (f *fakeAnnouncementAcks) UnacknowledgedAnnouncements(ctx context.Context, limit int) ([]dto.UnacknowledgedAnnouncement, error) {
+	f.limit = limit
+	return f.pending, nil
+}
+
 func TestDashboardServiceAdmin_ComposesAndCaches(t *testing.T) {
 	cacheRepo := &stubCacheRepo{}
 	cacheSvc := NewCacheService(cacheRepo, nil, time.Minute, zap.NewNop(), true)
@@ -151,6 +185,56 @@ func TestDashboardServiceAdmin_ComposesAndCaches(t *testing.T) {
 	assert.Equal(t, result, resultCached)
 }
 
+func TestDashboardServiceAdmin_IncludesTeacherPresence(t *testing.T) {
+	stats := &dto.TeacherPresenceStats{Date: "2024-11-10", ActiveTeachers: 40, CheckedIn: 35, NotCheckedIn: 5, Late: 3}
+	svc := NewDashboardService(DashboardServiceParams{
+		Analytics:       &fakeAnalytics{},
+		TeacherPresence: &fakePresence{stats: stats},
+	})
+	result, _, err := svc.Admin(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, stats, result.Ops.TeacherPresence)
+
+	failing := NewDashboardService(DashboardServiceParams{
+		Analytics:       &fakeAnalytics{},
+		TeacherPresence: &fakePresence{err: assert.AnError},
+	})
+	result, _, err = failing.Admin(context.Background(), "term-1")
+	require.NoError(t, err, "presence failures degrade the ops section instead of the dashboard")
+	assert.Nil(t, result.Ops.TeacherPresence)
+}
+
+func TestDashboardServiceAdmin_IncludesPendingMutations(t *testing.T) {
+	stats := &dto.PendingMutationStats{Pending: 6, Overdue: 2, Escalated: 1, OldestAgeDays: 17}
+	svc := NewDashboardService(DashboardServiceParams{
+		Analytics:        &fakeAnalytics{},
+		PendingMutations: &fakePendingMutations{stats: stats},
+	})
+	result, _, err := svc.Admin(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, stats, result.Ops.PendingMutations)
+
+	failing := NewDashboardService(DashboardServiceParams{
+		Analytics:        &fakeAnalytics{},
+		PendingMutations: &fakePendingMutations{err: assert.AnError},
+	})
+	result, _, err = failing.Admin(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Nil(t, result.Ops.PendingMutations)
+}
+
+func TestDashboardServiceAdmin_IncludesUnacknowledgedAnnouncements(t *testing.T) {
+	acks := &fakeAnnouncementAcks{pending: []dto.UnacknowledgedAnnouncement{{ID: "ann-1", Title: "Rapat", Recipients: 40, Acknowledged: 31, Pending: 9}}}
+	svc := NewDashboardService(DashboardServiceParams{
+		Analytics:        &fakeAnalytics{},
+		AnnouncementAcks: acks,
+	})
+	result, _, err := svc.Admin(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, acks.pending, result.Ops.UnacknowledgedAnnouncements)
+	assert.Equal(t, unacknowledgedAnnouncementLimit, acks.limit)
+}
+
 func TestDashboardServiceTeacher_ComposesSummary(t *testing.T) {
 	cacheSvc := NewCacheService(nil, nil, time.Minute, zap.NewNop(), false)
 	assignments := &fakeAssignments{
@@ -172,7 +256,7 @@ func TestDashboardServiceTeacher_ComposesSummary(t *testing.T) {
 			{ClassID: "class-b", Percentage: 91},
 		},
 		grades: []models.AnalyticsGradeSummary{
-			{ClassID: "class-a", AverageScore: 75},
+			{ClassID: "class-a", AverageScore: 65},
 			{ClassID: "class-b", AverageScore: 88},
 		},
 	}
@@ -181,12 +265,9 @@ func TestDashboardServiceTeacher_ComposesSummary(t *testing.T) {
 		Analytics:   analytics,
 		Assignments: assignments,
 		Schedules:   schedules,
+		SlotLabels:  StaticSlotLabels{1: "07:00–07:45"},
 		Cache:       cacheSvc,
-		Config: DashboardServiceConfig{
-			LowAttendanceThreshold: 85,
-			GradeOutlierThreshold:  80,
-		},
-		Logger: zap.NewNop(),
+		Logger:      zap.NewNop(),
 	})
 
 	date := time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC) // Monday
@@ -194,16 +275,111 @@ func TestDashboardServiceTeacher_ComposesSummary(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, cacheHit)
 	assert.Equal(t, "teacher-1", result.TeacherID)
+	assert.Equal(t, models.AnalyticsAttendanceFilter{TermID: "term-1", ClassIDs: []string{"class-a", "class-b"}}, analytics.attendanceFilter)
+	assert.Equal(t, models.AnalyticsGradeFilter{TermID: "term-1", ClassIDs: []string{"class-a", "class-b"}}, analytics.gradesFilter)
 	require.Len(t, result.Classes, 2)
 	assert.Contains(t, result.Alerts.LowAttendanceClasses, "class-a")
 	assert.Contains(t, result.Alerts.GradeOutliers, "class-a")
+	require.Len(t, result.Alerts.Rules, 2)
+	assert.Equal(t, "default-class-attendance", result.Alerts.Rules[0].RuleID)
+	assert.Equal(t, 82.0, result.Alerts.Rules[0].Value)
+	assert.Empty(t, result.Alerts.Rules[0].Since)
 	require.Len(t, result.Today.Schedules, 1)
 	assert.Equal(t, "math", result.Today.Schedules[0].SubjectID)
 	assert.Equal(t, 1, result.Today.Schedules[0].TimeSlot)
+	assert.Equal(t, "07:00–07:45", result.Today.Schedules[0].TimeLabel)
 	assert.NotNil(t, result.Today.Schedules[0].Room)
 	assert.Equal(t, "Lab", *result.Today.Schedules[0].Room)
 }
 
+func TestDashboardServiceTeacher_PropagatesFetchErrors(t *testing.T) {
+	svc := NewDashboardService(DashboardServiceParams{
+		Analytics: &fakeAnalytics{},
+		Assignments: &fakeAssignments{assignments: []models.TeacherAssignmentDetail{
+			{TeacherAssignment: models.TeacherAssignment{ClassID: "class-a", TermID: "term-1"}},
+		}},
+		Schedules: &fakeSchedules{err: appErrors.Clone(appErrors.ErrInternal, "schedules unavailable")},
+		Logger:    zap.NewNop(),
+	})
+
+	_, _, err := svc.Teacher(context.Background(), "teacher-1", "term-1", time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC))
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrInternal.Code, appErrors.FromError(err).Code)
+}
+
+type fakeCurriculum struct {
+	items []dto.CurriculumCoverage
+	err   error
+}
+
+func (f *fakeCurriculum) TeacherCoverage(context.Context, string, string, time.Time) ([]dto.CurriculumCoverage, error) {
+	return f.items, f.err
+}
+
+func TestDashboardServiceTeacher_IncludesCurriculumCoverage(t *testing.T) {
+	items := []dto.CurriculumCoverage{{ClassID: "class-a", SubjectID: "math", PlannedToDate: 4, Taught: 3, Behind: 1, Status: CurriculumStatusBehind}}
+	assignments := &fakeAssignments{}
+	svc := NewDashboardService(DashboardServiceParams{
+		Analytics:   &fakeAnalytics{},
+		Assignments: assignments,
+		Curriculum:  &fakeCurriculum{items: items},
+		Logger:      zap.NewNop(),
+	})
+	date := time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC)
+	result, _, err := svc.Teacher(context.Background(), "teacher-1", "term-1", date)
+	require.NoError(t, err)
+	assert.Equal(t, items, result.Curriculum)
+
+	failing := NewDashboardService(DashboardServiceParams{
+		Analytics:   &fakeAnalytics{},
+		Assignments: assignments,
+		Curriculum:  &fakeCurriculum{err: assert.AnError},
+		Logger:      zap.NewNop(),
+	})
+	result, _, err = failing.Teacher(context.Background(), "teacher-1", "term-1", date)
+	require.NoError(t, err)
+	assert.Nil(t, result.Curriculum)
+}
+
+type fakeAttendanceAlerts struct {
+	alerts []models.AttendanceAlert
+	filter models.AttendanceAlertFilter
+}
+
+func (f *fakeAttendanceAlerts) ListAlerts(ctx context.Context, filter models.AttendanceAlertFilter) ([]models.AttendanceAlert, error) {
+	f.filter = filter
+	return f.alerts, nil
+}
+
+func TestDashboardServiceTeacher_UsesStoredAttendanceAlerts(t *testing.T) {
+	assignments := &fakeAssignments{
+		assignments: []models.TeacherAssignmentDetail{
+			{TeacherAssignment: models.TeacherAssignment{ClassID: "class-a", TermID: "term-1"}},
+			{TeacherAssignment: models.TeacherAssignment{ClassID: "class-b", TermID: "term-1"}},
+		},
+	}
+	detected := time.Date(2024, 11, 8, 1, 0, 0, 0, time.UTC)
+	stored := &fakeAttendanceAlerts{alerts: []models.AttendanceAlert{
+		{StudentID: "student-1", StudentName: "Budi", ClassID: "class-b", Percentage: 72.5, Threshold: 85, DetectedAt: detected},
+	}}
+	svc := NewDashboardService(DashboardServiceParams{
+		// class-a's average breaches the default attendance rule, but only stored alerts count.
+		Analytics:        &fakeAnalytics{attendance: []models.AnalyticsAttendanceSummary{{ClassID: "class-a", Percentage: 60}}},
+		Assignments:      assignments,
+		AttendanceAlerts: stored,
+		Logger:           zap.NewNop(),
+	})
+
+	result, _, err := svc.Teacher(context.Background(), "teacher-1", "term-1", time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, models.AttendanceAlertFilter{TermID: "term-1", ClassIDs: []string{"class-a", "class-b"}}, stored.filter)
+	assert.Equal(t, []string{"class-b"}, result.Alerts.LowAttendanceClasses)
+	require.Len(t, result.Alerts.LowAttendanceStudents, 1)
+	assert.Equal(t, dto.TeacherAttendanceAlert{
+		StudentID: "student-1", StudentName: "Budi", ClassID: "class-b", Percentage: 72.5, Threshold: 85, Since: "2024-11-08",
+	}, result.Alerts.LowAttendanceStudents[0])
+}
+
 func TestDashboardServiceAnalyticsFallback(t *testing.T) {
 	cacheSvc := NewCacheService(nil, nil, time.Minute, zap.NewNop(), false)
 	repo := &fakeAnalyticsRepo{
@@ -236,3 +412,50 @@ func TestDashboardServiceAdminValidation(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
 }
+
+type fakeAlertRuleBreaches struct {
+	breaches []models.AlertRuleBreach
+	filter   models.AlertRuleBreachFilter
+}
+
+func (f *fakeAlertRuleBreaches) ListBreaches(ctx context.Context, filter models.AlertRuleBreachFilter) ([]models.AlertRuleBreach, error) {
+	f.filter = filter
+	return f.breaches, nil
+}
+
+func TestDashboardService_UsesStoredAlertRuleBreaches(t *testing.T) {
+	detected := time.Date(2024, 11, 8, 1, 0, 0, 0, time.UTC)
+	stored := &fakeAlertRuleBreaches{breaches: []models.AlertRuleBreach{
+		{RuleID: "rule-grade", RuleName: "Low grades", Metric: models.AlertMetricClassAverageGrade, Comparator: models.AlertComparatorLT,
+			ClassID: "class-b", Value: 61.5, Threshold: 75, DetectedAt: detected},
+	}}
+	svc := NewDashboardService(DashboardServiceParams{
+		// class-a breaches the default rules, but once alert rules are stored only their breaches count.
+		Analytics: &fakeAnalytics{
+			attendance: []models.AnalyticsAttendanceSummary{{ClassID: "class-a", Percentage: 60}},
+			grades:     []models.AnalyticsGradeSummary{{ClassID: "class-a", AverageScore: 50}},
+		},
+		Assignments: &fakeAssignments{assignments: []models.TeacherAssignmentDetail{
+			{TeacherAssignment: models.TeacherAssignment{ClassID: "class-a", TermID: "term-1"}},
+			{TeacherAssignment: models.TeacherAssignment{ClassID: "class-b", TermID: "term-1"}},
+		}},
+		AlertRules: stored,
+		Logger:     zap.NewNop(),
+	})
+	want := dto.DashboardRuleAlert{
+		RuleID: "rule-grade", RuleName: "Low grades", Metric: "CLASS_AVERAGE_GRADE", Comparator: "LT",
+		ClassID: "class-b", Value: 61.5, Threshold: 75, Since: "2024-11-08",
+	}
+
+	result, _, err := svc.Teacher(context.Background(), "teacher-1", "term-1", time.Date(2024, 11, 11, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertRuleBreachFilter{TermID: "term-1", ClassIDs: []string{"class-a", "class-b"}, Audiences: teacherAlertAudiences}, stored.filter)
+	assert.Equal(t, []dto.DashboardRuleAlert{want}, result.Alerts.Rules)
+	assert.Equal(t, []string{"class-b"}, result.Alerts.GradeOutliers)
+	assert.Empty(t, result.Alerts.LowAttendanceClasses)
+
+	admin, _, err := svc.Admin(context.Background(), "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.AlertRuleBreachFilter{TermID: "term-1"}, stored.filter)
+	assert.Equal(t, []dto.DashboardRuleAlert{want}, admin.Alerts)
+}
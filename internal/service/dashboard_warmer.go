@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+)
+
+type dashboardRefresher interface {
+	RefreshAdmin(ctx context.Context, termID string) error
+	RefreshTeacher(ctx context.Context, teacherID, termID string, date time.Time) error
+}
+
+type recentUserLister interface {
+	ListRecentlyActive(ctx context.Context, role models.UserRole, since time.Time, limit int) ([]string, error)
+}
+
+// DashboardWarmerConfig tunes how often and how widely dashboards are precomputed.
+type DashboardWarmerConfig struct {
+	// Interval between scheduled warm-ups; zero only warms at start-up and after bulk writes.
+	Interval time.Duration
+	// Debounce collapses bursts of bulk writes into a single warm-up.
+	Debounce time.Duration
+	// ActiveWindow selects teachers who logged in within this window.
+	ActiveWindow time.Duration
+	// TeacherLimit caps the number of teacher dashboards warmed per run.
+	TeacherLimit int
+}
+
+// DashboardWarmer precomputes the admin dashboard and the dashboards of recently active teachers for
+// the active term so the first hit after cache expiry is served from cache.
+type DashboardWarmer struct {
+	dashboards dashboardRefresher
+	terms      ports.ActiveTermReader
+	users      recentUserLister
+	cfg        DashboardWarmerConfig
+	logger     *zap.Logger
+	now        func() time.Time
+	trigger    chan struct{}
+}
+
+// NewDashboardWarmer constructs a DashboardWarmer with defaults.
+func NewDashboardWarmer(dashboards dashboardRefresher, terms ports.ActiveTermReader, users recentUserLister, cfg DashboardWarmerConfig, logger *zap.Logger) *DashboardWarmer {
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = 30 * time.Second
+	}
+	if cfg.ActiveWindow <= 0 {
+		cfg.ActiveWindow = 24 * time.Hour
+	}
+	if cfg.TeacherLimit <= 0 {
+		cfg.TeacherLimit = 50
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &DashboardWarmer{
+		dashboards: dashboards,
+		terms:      terms,
+		users:      users,
+		cfg:        cfg,
+		logger:     logger,
+		now:        time.Now,
+		trigger:    make(chan struct{}, 1),
+	}
+}
+
+// Trigger schedules a warm-up after the debounce delay. It never blocks.
+func (w *DashboardWarmer) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Start warms once and then keeps warming on the interval and after triggers until ctx is done.
+func (w *DashboardWarmer) Start(ctx context.Context) {
+	go w.run(ctx)
+}
+
+func (w *DashboardWarmer) run(ctx context.Context) {
+	w.Warm(ctx)
+
+	var tick <-chan time.Time
+	if w.cfg.Interval > 0 {
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			w.Warm(ctx)
+		case <-w.trigger:
+			if debounce == nil {
+				debounce = time.After(w.cfg.Debounce)
+			}
+		case <-debounce:
+			debounce = nil
+			w.Warm(ctx)
+		}
+	}
+}
+
+// Warm refreshes the dashboards for the active term and returns how many were written. Failures are
+// logged and skipped so one broken dashboard does not block the rest.
+func (w *DashboardWarmer) Warm(ctx context.Context) int {
+	term, err := w.terms.FindActive(ctx)
+	if err != nil || term == nil {
+		w.logger.Warn("dashboard warm skipped: no active term", zap.Error(err))
+		return 0
+	}
+
+	warmed := 0
+	if err := w.dashboards.RefreshAdmin(ctx, term.ID); err != nil {
+		w.logger.Warn("admin dashboard warm failed", zap.String("term_id", term.ID), zap.Error(err))
+	} else {
+		warmed++
+	}
+
+	now := w.now().UTC()
+	teacherIDs, err := w.users.ListRecentlyActive(ctx, models.RoleTeacher, now.Add(-w.cfg.ActiveWindow), w.cfg.TeacherLimit)
+	if err != nil {
+		w.logger.Warn("dashboard warm could not list active teachers", zap.Error(err))
+		return warmed
+	}
+	for _, teacherID := range teacherIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := w.dashboards.RefreshTeacher(ctx, teacherID, term.ID, now); err != nil {
+			w.logger.Warn("teacher dashboard warm failed", zap.String("teacher_id", teacherID), zap.Error(err))
+			continue
+		}
+		warmed++
+	}
+	w.logger.Debug("dashboards warmed", zap.String("term_id", term.ID), zap.Int("count", warmed))
+	return warmed
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type refresherStub struct {
+	mu       sync.Mutex
+	admin    []string
+	teachers []string
+	failFor  string
+	refreshC chan struct{}
+}
+
+func (r *refresherStub) RefreshAdmin(ctx context.Context, termID string) error {
+	r.mu.Lock()
+	r.admin = append(r.admin, termID)
+	r.mu.Unlock()
+	if r.refreshC != nil {
+		r.refreshC <- struct{}{}
+	}
+	return nil
+}
+
+func (r *refresherStub) RefreshTeacher(ctx context.Context, teacherID, termID string, date time.Time) error {
+	if teacherID == r.failFor {
+		return errors.New("boom")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.teachers = append(r.teachers, teacherID)
+	return nil
+}
+
+type warmerTermStub struct{ term *models.Term }
+
+func (s warmerTermStub) FindActive(ctx context.Context) (*models.Term, error) {
+	if s.term == nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.term, nil
+}
+
+type recentUsersStub struct {
+	since time.Time
+	limit int
+}
+
+func (s *recentUsersStub) ListRecentlyActive(ctx context.Context, role models.UserRole, since time.Time, limit int) ([]string, error) {
+	s.since = since
+	s.limit = limit
+	return []string{"teacher-1", "teacher-2", "teacher-3"}, nil
+}
+
+func TestDashboardWarmerWarm(t *testing.T) {
+	refresher := &refresherStub{failFor: "teacher-2"}
+	users := &recentUsersStub{}
+	warmer := NewDashboardWarmer(refresher, warmerTermStub{term: &models.Term{ID: "term-1"}}, users, DashboardWarmerConfig{ActiveWindow: time.Hour, TeacherLimit: 10}, zap.NewNop())
+	now := time.Date(2024, 8, 5, 7, 0, 0, 0, time.UTC)
+	warmer.now = func() time.Time { return now }
+
+	assert.Equal(t, 3, warmer.Warm(context.Background()))
+	assert.Equal(t, []string{"term-1"}, refresher.admin)
+	assert.Equal(t, []string{"teacher-1", "teacher-3"}, refresher.teachers)
+	assert.Equal(t, now.Add(-time.Hour), users.since)
+	assert.Equal(t, 10, users.limit)
+
+	noTerm := NewDashboardWarmer(&refresherStub{}, warmerTermStub{}, users, DashboardWarmerConfig{}, zap.NewNop())
+	assert.Zero(t, noTerm.Warm(context.Background()))
+}
+
+func TestDashboardWarmerTriggerDebounces(t *testing.T) {
+	refresher := &refresherStub{refreshC: make(chan struct{}, 4)}
+	warmer := NewDashboardWarmer(refresher, warmerTermStub{term: &models.Term{ID: "term-1"}}, &recentUsersStub{}, DashboardWarmerConfig{Debounce: 20 * time.Millisecond}, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	warmer.Start(ctx)
+	waitForRefresh(t, refresher.refreshC)
+
+	warmer.Trigger()
+	warmer.Trigger()
+	warmer.Trigger()
+	waitForRefresh(t, refresher.refreshC)
+
+	select {
+	case <-refresher.refreshC:
+		t.Fatal("burst of triggers should warm once")
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func waitForRefresh(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		require.FailNow(t, "dashboard was not warmed")
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/broker"
+)
+
+type outboxStore interface {
+	ClaimPending(ctx context.Context, relayID string, staleBefore time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id, relayID string) error
+	MarkFailed(ctx context.Context, id, relayID, reason string) error
+	PurgePublished(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type eventPublisher interface {
+	Publish(ctx context.Context, msg broker.Message) error
+}
+
+// DomainEventRelayConfig tunes how pending outbox events are published.
+type DomainEventRelayConfig struct {
+	// RelayID identifies this replica's claims; it defaults to <hostname>-<pid>.
+	RelayID string
+	// SubjectPrefix is prepended to "<event type>.v<schema version>" to form the subject or topic.
+	SubjectPrefix  string
+	PollInterval   time.Duration
+	BatchSize      int
+	PublishTimeout time.Duration
+	// ClaimTimeout is how long another replica waits before taking over events claimed by a relay
+	// that stopped.
+	ClaimTimeout time.Duration
+	// Retention is how long published events are kept; zero keeps them forever.
+	Retention time.Duration
+}
+
+const domainEventPurgeInterval = time.Hour
+
+// DomainEventRelay publishes outbox events to the broker in the order they occurred and marks them
+// published. Delivery is at least once: an event whose acknowledgement is lost is sent again, so
+// consumers deduplicate on the envelope ID (sent as Nats-Msg-Id to NATS). A failed publish stops the
+// batch so later events are not delivered ahead of it.
+type DomainEventRelay struct {
+	store     outboxStore
+	publisher eventPublisher
+	cfg       DomainEventRelayConfig
+	logger    *zap.Logger
+	now       func() time.Time
+	lastPurge time.Time
+}
+
+// NewDomainEventRelay constructs a relay with defaults.
+func NewDomainEventRelay(store outboxStore, publisher eventPublisher, cfg DomainEventRelayConfig, logger *zap.Logger) *DomainEventRelay {
+	if cfg.RelayID == "" {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = "event-relay"
+		}
+		cfg.RelayID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PublishTimeout <= 0 {
+		cfg.PublishTimeout = 5 * time.Second
+	}
+	if cfg.ClaimTimeout <= cfg.PublishTimeout {
+		cfg.ClaimTimeout = 12 * cfg.PublishTimeout
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &DomainEventRelay{store: store, publisher: publisher, cfg: cfg, logger: logger, now: time.Now}
+}
+
+// Start relays pending events every poll interval until ctx is done.
+func (r *DomainEventRelay) Start(ctx context.Context) {
+	go r.run(ctx)
+}
+
+func (r *DomainEventRelay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.drain(ctx)
+			r.purge(ctx)
+		}
+	}
+}
+
+// drain relays full batches back to back so a backlog is not throttled by the poll interval.
+func (r *DomainEventRelay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.Relay(ctx)
+		if err != nil {
+			r.logger.Warn("domain event relay stopped", zap.Error(err))
+			return
+		}
+		if published < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// Relay claims one batch of pending events, publishes them and returns how many were published.
+func (r *DomainEventRelay) Relay(ctx context.Context) (int, error) {
+	staleBefore := r.now().UTC().Add(-r.cfg.ClaimTimeout)
+	events, err := r.store.ClaimPending(ctx, r.cfg.RelayID, staleBefore, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for i := range events {
+		event := &events[i]
+		if err := r.publish(ctx, event); err != nil {
+			if markErr := r.store.MarkFailed(ctx, event.ID, r.cfg.RelayID, err.Error()); markErr != nil {
+				r.logger.Warn("failed to record domain event failure", zap.String("event_id", event.ID), zap.Error(markErr))
+			}
+			// The rest of the batch stays claimed until ClaimTimeout and is retried after this event.
+			return published, fmt.Errorf("publish %s event %s: %w", event.EventType, event.ID, err)
+		}
+		if err := r.store.MarkPublished(ctx, event.ID, r.cfg.RelayID); err != nil {
+			// Another relay took the claim over and will publish the event again.
+			r.logger.Warn("failed to mark domain event published", zap.String("event_id", event.ID), zap.Error(err))
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+func (r *DomainEventRelay) publish(ctx context.Context, event *models.OutboxEvent) error {
+	data, err := json.Marshal(models.DomainEventEnvelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		SchemaVersion: event.SchemaVersion,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.OccurredAt,
+		Data:          json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return fmt.Errorf("encode envelope: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+	defer cancel()
+	return r.publisher.Publish(ctx, broker.Message{
+		Subject: r.Subject(event.EventType, event.SchemaVersion),
+		Key:     event.AggregateID,
+		Headers: map[string]string{"Nats-Msg-Id": event.ID},
+		Data:    data,
+	})
+}
+
+// Subject returns the subject or topic events of eventType at version are published to, e.g.
+// "sma.attendance.marked.v1". Versions get their own subject so consumers opt into a new schema.
+func (r *DomainEventRelay) Subject(eventType models.DomainEventType, version int) string {
+	subject := fmt.Sprintf("%s.v%d", eventType, version)
+	if prefix := strings.TrimSuffix(r.cfg.SubjectPrefix, "."); prefix != "" {
+		subject = prefix + "." + subject
+	}
+	return subject
+}
+
+func (r *DomainEventRelay) purge(ctx context.Context) {
+	now := r.now().UTC()
+	if r.cfg.Retention <= 0 || now.Sub(r.lastPurge) < domainEventPurgeInterval {
+		return
+	}
+	r.lastPurge = now
+	removed, err := r.store.PurgePublished(ctx, now.Add(-r.cfg.Retention))
+	if err != nil {
+		r.logger.Warn("failed to purge published domain events", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		r.logger.Info("purged published domain events", zap.Int64("removed", removed))
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/broker"
+)
+
+type outboxStoreStub struct {
+	pending   []models.OutboxEvent
+	published []string
+	failed    map[string]string
+}
+
+func (s *outboxStoreStub) ClaimPending(ctx context.Context, relayID string, staleBefore time.Time, limit int) ([]models.OutboxEvent, error) {
+	return s.pending, nil
+}
+
+func (s *outboxStoreStub) MarkPublished(ctx context.Context, id, relayID string) error {
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *outboxStoreStub) MarkFailed(ctx context.Context, id, relayID, reason string) error {
+	if s.failed == nil {
+		s.failed = make(map[string]string)
+	}
+	s.failed[id] = reason
+	return nil
+}
+
+func (s *outboxStoreStub) PurgePublished(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+type publisherStub struct {
+	messages []broker.Message
+	failOn   string
+}
+
+func (p *publisherStub) Publish(ctx context.Context, msg broker.Message) error {
+	if msg.Headers["Nats-Msg-Id"] == p.failOn {
+		return errors.New("broker unavailable")
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+func relayEvents() []models.OutboxEvent {
+	occurred := time.Date(2024, 7, 15, 7, 0, 0, 0, time.UTC)
+	return []models.OutboxEvent{
+		{ID: "evt-1", EventType: models.DomainEventAttendanceMarked, SchemaVersion: 1, AggregateID: "att-1", Payload: []byte(`{"attendanceId":"att-1"}`), OccurredAt: occurred},
+		{ID: "evt-2", EventType: models.DomainEventGradeFinalized, SchemaVersion: 2, AggregateID: "class-1", Payload: []byte(`{"classId":"class-1"}`), OccurredAt: occurred},
+		{ID: "evt-3", EventType: models.DomainEventAttendanceMarked, SchemaVersion: 1, AggregateID: "att-2", Payload: []byte(`{"attendanceId":"att-2"}`), OccurredAt: occurred},
+	}
+}
+
+func TestDomainEventRelayPublishesEnvelopes(t *testing.T) {
+	store := &outboxStoreStub{pending: relayEvents()}
+	publisher := &publisherStub{}
+	relay := NewDomainEventRelay(store, publisher, DomainEventRelayConfig{RelayID: "relay-1", SubjectPrefix: "sma."}, nil)
+
+	published, err := relay.Relay(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, []string{"evt-1", "evt-2", "evt-3"}, store.published)
+	require.Len(t, publisher.messages, 3)
+
+	msg := publisher.messages[1]
+	assert.Equal(t, "sma.grade.finalized.v2", msg.Subject)
+	assert.Equal(t, "class-1", msg.Key)
+	var envelope models.DomainEventEnvelope
+	require.NoError(t, json.Unmarshal(msg.Data, &envelope))
+	assert.Equal(t, "evt-2", envelope.ID)
+	assert.Equal(t, 2, envelope.SchemaVersion)
+	assert.JSONEq(t, `{"classId":"class-1"}`, string(envelope.Data))
+}
+
+func TestDomainEventRelayStopsAtFirstFailure(t *testing.T) {
+	store := &outboxStoreStub{pending: relayEvents()}
+	publisher := &publisherStub{failOn: "evt-2"}
+	relay := NewDomainEventRelay(store, publisher, DomainEventRelayConfig{RelayID: "relay-1"}, nil)
+
+	published, err := relay.Relay(context.Background())
+	require.ErrorContains(t, err, "broker unavailable")
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"evt-1"}, store.published)
+	assert.Equal(t, "broker unavailable", store.failed["evt-2"])
+	assert.NotContains(t, store.failed, "evt-3")
+	assert.Equal(t, "attendance.marked.v1", relay.Subject(models.DomainEventAttendanceMarked, 1))
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+)
+
+type domainEventOutbox interface {
+	Enqueue(ctx context.Context, exec sqlx.ExtContext, event *models.OutboxEvent) error
+}
+
+// DomainEvents stores domain events in the transactional outbox together with the change they
+// describe. A nil *DomainEvents is valid and drops every event, so services call it the same way
+// whether or not the event stream is enabled.
+type DomainEvents struct {
+	tx     txProvider
+	outbox domainEventOutbox
+}
+
+// NewDomainEvents constructs DomainEvents writing through tx.
+func NewDomainEvents(tx txProvider, outbox domainEventOutbox) *DomainEvents {
+	return &DomainEvents{tx: tx, outbox: outbox}
+}
+
+// Write runs write in a transaction and stores the events it returns in the same transaction, so
+// the events exist if and only if the change commits. Dry-run contexts are rolled back. On a nil
+// *DomainEvents write runs with a nil exec, i.e. outside any transaction, and its events are dropped.
+func (d *DomainEvents) Write(ctx context.Context, write func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error)) error {
+	if d == nil {
+		_, err := write(nil)
+		return err
+	}
+	tx, err := d.tx.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	events, err := write(tx)
+	if err == nil {
+		err = d.Enqueue(ctx, tx, events...)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := database.CommitOrRollback(ctx, tx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Enqueue stores events in a transaction owned by the caller. It is a no-op on a nil *DomainEvents.
+func (d *DomainEvents) Enqueue(ctx context.Context, exec sqlx.ExtContext, events ...*models.OutboxEvent) error {
+	if d == nil {
+		return nil
+	}
+	for _, event := range events {
+		if err := d.outbox.Enqueue(ctx, exec, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newDomainEvent encodes payload as an event of eventType at the type's current schema version.
+func newDomainEvent(eventType models.DomainEventType, aggregateID string, payload interface{}) (*models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+	return &models.OutboxEvent{
+		EventType:     eventType,
+		SchemaVersion: eventType.SchemaVersion(),
+		AggregateID:   aggregateID,
+		Payload:       types.JSONText(data),
+		OccurredAt:    time.Now().UTC(),
+	}, nil
+}
+
+// attendanceMarkedEvents describes the daily attendance rows just written.
+func attendanceMarkedEvents(records ...models.DailyAttendance) ([]*models.OutboxEvent, error) {
+	events := make([]*models.OutboxEvent, 0, len(records))
+	for _, record := range records {
+		source := record.Source
+		if source == "" {
+			source = models.AttendanceSourceManual
+		}
+		event, err := newDomainEvent(models.DomainEventAttendanceMarked, record.ID, models.AttendanceMarkedEvent{
+			AttendanceID: record.ID,
+			EnrollmentID: record.EnrollmentID,
+			Date:         record.Date.Format("2006-01-02"),
+			Status:       record.Status,
+			Source:       source,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
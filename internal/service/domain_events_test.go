@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type outboxStub struct {
+	events []*models.OutboxEvent
+	execs  []sqlx.ExtContext
+	err    error
+}
+
+func (s *outboxStub) Enqueue(ctx context.Context, exec sqlx.ExtContext, event *models.OutboxEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	s.execs = append(s.execs, exec)
+	return nil
+}
+
+func TestDomainEventsWriteCommitsEventsWithChange(t *testing.T) {
+	tx, mock := newTxProviderMock(t)
+	outbox := &outboxStub{}
+	events := NewDomainEvents(tx, outbox)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	var writeExec sqlx.ExtContext
+	err := events.Write(context.Background(), func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		writeExec = exec
+		event, err := newDomainEvent(models.DomainEventAttendanceMarked, "att-1", map[string]string{"attendanceId": "att-1"})
+		return []*models.OutboxEvent{event}, err
+	})
+	require.NoError(t, err)
+	require.NotNil(t, writeExec)
+	require.Len(t, outbox.events, 1)
+	assert.Same(t, writeExec, outbox.execs[0])
+	assert.Equal(t, 1, outbox.events[0].SchemaVersion)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDomainEventsWriteRollsBackOnFailure(t *testing.T) {
+	tx, mock := newTxProviderMock(t)
+	events := NewDomainEvents(tx, &outboxStub{err: errors.New("outbox down")})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err := events.Write(context.Background(), func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		return []*models.OutboxEvent{{EventType: models.DomainEventGradeFinalized}}, nil
+	})
+	require.EqualError(t, err, "outbox down")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDomainEventsNilWritesWithoutTransaction(t *testing.T) {
+	var events *DomainEvents
+	called := false
+	err := events.Write(context.Background(), func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		called = true
+		assert.Nil(t, exec)
+		return []*models.OutboxEvent{{EventType: models.DomainEventGradeFinalized}}, nil
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.NoError(t, events.Enqueue(context.Background(), nil, &models.OutboxEvent{}))
+}
+
+func TestMutationServiceReviewEmitsApprovedEvent(t *testing.T) {
+	tx, mock := newTxProviderMock(t)
+	outbox := &outboxStub{}
+	repo := newMutationRepoStub()
+	repo.mutations["mut-1"] = &models.Mutation{ID: "mut-1", Entity: "student", EntityID: "student-1", Status: models.MutationStatusPending, RequestedChanges: []byte(`{"name":"John"}`)}
+	repo.mutations["mut-2"] = &models.Mutation{ID: "mut-2", Entity: "student", EntityID: "student-2", Status: models.MutationStatusPending}
+	svc := NewMutationService(repo, &auditStub{}, nil,
+		WithMutationAppliers(map[string]MutationApplier{
+			"student": MutationApplierFunc(func(ctx context.Context, mut *models.Mutation) ([]byte, error) {
+				return []byte(`{"name":"John"}`), nil
+			}),
+		}),
+		WithMutationEvents(NewDomainEvents(tx, outbox)),
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err := svc.Review(context.Background(), "mut-1", dto.ReviewMutationRequest{Status: models.MutationStatusApproved}, "super-1")
+	require.NoError(t, err)
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, models.DomainEventMutationApproved, outbox.events[0].EventType)
+	assert.Equal(t, "mut-1", outbox.events[0].AggregateID)
+	var payload models.MutationApprovedEvent
+	require.NoError(t, json.Unmarshal(outbox.events[0].Payload, &payload))
+	assert.Equal(t, "student-1", payload.EntityID)
+	assert.Equal(t, "super-1", payload.ReviewedBy)
+	assert.JSONEq(t, `{"name":"John"}`, string(payload.Changes))
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	_, err = svc.Review(context.Background(), "mut-2", dto.ReviewMutationRequest{Status: models.MutationStatusRejected}, "super-1")
+	require.NoError(t, err)
+	assert.Len(t, outbox.events, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
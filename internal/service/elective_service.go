@@ -0,0 +1,701 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type electiveStore interface {
+	CreateOffering(ctx context.Context, offering *models.ElectiveOffering) error
+	UpdateOffering(ctx context.Context, offering *models.ElectiveOffering) error
+	FindOffering(ctx context.Context, id string) (*models.ElectiveOffering, error)
+	ListOfferings(ctx context.Context, termID, category string) ([]models.ElectiveOffering, error)
+	DeleteOffering(ctx context.Context, id string) error
+	ReplaceSelections(ctx context.Context, enrollmentID string, offeringIDs []string, selections []models.ElectiveSelection) error
+	ListSelections(ctx context.Context, filter models.ElectiveSelectionFilter) ([]models.ElectiveSelection, error)
+	SaveAllocation(ctx context.Context, groups []models.ElectiveSectionGroup) error
+}
+
+type electiveLessonGroups interface {
+	FindByID(ctx context.Context, id string) (*models.LessonGroup, error)
+	ListByTerm(ctx context.Context, termID string) ([]models.LessonGroup, error)
+	ListSessions(ctx context.Context, groupID string) ([]models.Schedule, error)
+}
+
+type electiveStudentResolver interface {
+	FindByUserID(ctx context.Context, userID string) (*models.StudentDetail, error)
+}
+
+type electiveEnrollmentReader interface {
+	FindByID(ctx context.Context, id string) (*models.Enrollment, error)
+	FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error)
+}
+
+// ElectiveServiceParams groups constructor dependencies.
+type ElectiveServiceParams struct {
+	Store        electiveStore
+	LessonGroups electiveLessonGroups
+	Students     electiveStudentResolver
+	Enrollments  electiveEnrollmentReader
+	Terms        ports.TermReader
+	Subjects     ports.SubjectReader
+	Teachers     ports.TeacherReader
+	Validator    *validator.Validate
+	Logger       *zap.Logger
+}
+
+// ElectiveService manages elective offerings, students' ranked choices and their allocation. Each
+// section of an offering becomes a lesson group holding the students allocated to it, so sessions are
+// scheduled and attendance is taken through the lesson group endpoints.
+type ElectiveService struct {
+	store        electiveStore
+	lessonGroups electiveLessonGroups
+	students     electiveStudentResolver
+	enrollments  electiveEnrollmentReader
+	terms        ports.TermReader
+	subjects     ports.SubjectReader
+	teachers     ports.TeacherReader
+	validator    *validator.Validate
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewElectiveService constructs the service.
+func NewElectiveService(params ElectiveServiceParams) *ElectiveService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ElectiveService{
+		store:        params.Store,
+		lessonGroups: params.LessonGroups,
+		students:     params.Students,
+		enrollments:  params.Enrollments,
+		terms:        params.Terms,
+		subjects:     params.Subjects,
+		teachers:     params.Teachers,
+		validator:    validate,
+		logger:       logger,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// ListOfferings returns the term's offerings, optionally of one category.
+func (s *ElectiveService) ListOfferings(ctx context.Context, query dto.ElectiveOfferingQuery) ([]models.ElectiveOffering, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective offering query")
+	}
+	offerings, err := s.store.ListOfferings(ctx, query.TermID, normalizeElectiveCategory(query.Category))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective offerings")
+	}
+	return offerings, nil
+}
+
+// GetOffering returns an offering with its sections.
+func (s *ElectiveService) GetOffering(ctx context.Context, id string) (*models.ElectiveOffering, error) {
+	offering, err := s.store.FindOffering(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "elective offering not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load elective offering")
+	}
+	return offering, nil
+}
+
+// CreateOffering validates and stores an offering with its sections.
+func (s *ElectiveService) CreateOffering(ctx context.Context, req dto.ElectiveOfferingRequest) (*models.ElectiveOffering, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective offering payload")
+	}
+	if s.terms != nil {
+		if _, err := s.terms.FindByID(ctx, req.TermID); err != nil {
+			return nil, lookupError(err, "term")
+		}
+	}
+	if s.subjects != nil {
+		if _, err := s.subjects.FindByID(ctx, req.SubjectID); err != nil {
+			return nil, lookupError(err, "subject")
+		}
+	}
+	offering := &models.ElectiveOffering{
+		TermID:      req.TermID,
+		SubjectID:   req.SubjectID,
+		Category:    normalizeElectiveCategory(req.Category),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	sections, err := s.buildSections(ctx, nil, req.Sections)
+	if err != nil {
+		return nil, err
+	}
+	offering.Sections = sections
+	if err := s.ensureUniqueOfferingName(ctx, offering.TermID, offering.Name, ""); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateOffering(ctx, offering); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create elective offering")
+	}
+	return offering, nil
+}
+
+// UpdateOffering renames an offering and replaces its sections. Sections that already hold allocated
+// students keep their teacher and cannot be removed; capacity changes apply to the next allocation.
+func (s *ElectiveService) UpdateOffering(ctx context.Context, id string, req dto.ElectiveOfferingRequest) (*models.ElectiveOffering, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective offering payload")
+	}
+	offering, err := s.GetOffering(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := s.buildSections(ctx, offering.Sections, req.Sections)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]struct{}, len(sections))
+	for _, section := range sections {
+		listed[section.ID] = struct{}{}
+	}
+	for _, section := range offering.Sections {
+		if _, ok := listed[section.ID]; !ok && section.LessonGroupID != nil {
+			return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("section %s has allocated students and cannot be removed", section.Name))
+		}
+	}
+	updated := *offering
+	updated.Name = strings.TrimSpace(req.Name)
+	updated.Description = req.Description
+	updated.Sections = sections
+	if err := s.ensureUniqueOfferingName(ctx, offering.TermID, updated.Name, offering.ID); err != nil {
+		return nil, err
+	}
+	if err := s.store.UpdateOffering(ctx, &updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "elective offering not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update elective offering")
+	}
+	return &updated, nil
+}
+
+// DeleteOffering removes an offering no student has been allocated to, along with its selections.
+func (s *ElectiveService) DeleteOffering(ctx context.Context, id string) error {
+	offering, err := s.GetOffering(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, section := range offering.Sections {
+		if section.LessonGroupID != nil {
+			return appErrors.Clone(appErrors.ErrConflict, "offering has allocated students; delete its sections' lesson groups first")
+		}
+	}
+	if err := s.store.DeleteOffering(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "elective offering not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete elective offering")
+	}
+	return nil
+}
+
+// SubmitSelections replaces an enrollment's ranked choices in a category. Students submit for their
+// own enrollment in the term; administrators name the enrollment.
+func (s *ElectiveService) SubmitSelections(ctx context.Context, req dto.ElectiveSelectionRequest, claims *models.JWTClaims) ([]models.ElectiveSelection, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective selection payload")
+	}
+	enrollment, err := s.resolveEnrollment(ctx, req.TermID, req.EnrollmentID, claims)
+	if err != nil {
+		return nil, err
+	}
+	category := normalizeElectiveCategory(req.Category)
+	offerings, err := s.store.ListOfferings(ctx, req.TermID, category)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective offerings")
+	}
+	inCategory := make(map[string]struct{}, len(offerings))
+	offeringIDs := make([]string, 0, len(offerings))
+	for _, offering := range offerings {
+		inCategory[offering.ID] = struct{}{}
+		offeringIDs = append(offeringIDs, offering.ID)
+	}
+
+	submittedAt := s.now()
+	selections := make([]models.ElectiveSelection, 0, len(req.OfferingIDs))
+	seen := make(map[string]struct{}, len(req.OfferingIDs))
+	for i, offeringID := range req.OfferingIDs {
+		if _, ok := inCategory[offeringID]; !ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("offering %s is not offered in %s this term", offeringID, category))
+		}
+		if _, ok := seen[offeringID]; ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("offering %s is ranked twice", offeringID))
+		}
+		seen[offeringID] = struct{}{}
+		selections = append(selections, models.ElectiveSelection{
+			EnrollmentID: enrollment.ID,
+			OfferingID:   offeringID,
+			Rank:         i + 1,
+			SubmittedAt:  submittedAt,
+			StudentID:    enrollment.StudentID,
+			ClassID:      enrollment.ClassID,
+		})
+	}
+	if err := s.store.ReplaceSelections(ctx, enrollment.ID, offeringIDs, selections); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save elective selections")
+	}
+	return selections, nil
+}
+
+// ListSelections returns ranked choices. Students only see their own.
+func (s *ElectiveService) ListSelections(ctx context.Context, query dto.ElectiveSelectionQuery, claims *models.JWTClaims) ([]models.ElectiveSelection, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective selection query")
+	}
+	filter := models.ElectiveSelectionFilter{TermID: query.TermID, Category: normalizeElectiveCategory(query.Category), EnrollmentID: query.EnrollmentID}
+	if claims.Role == models.RoleStudent {
+		enrollment, err := s.resolveEnrollment(ctx, query.TermID, "", claims)
+		if err != nil {
+			return nil, err
+		}
+		filter.EnrollmentID = enrollment.ID
+	}
+	selections, err := s.store.ListSelections(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective selections")
+	}
+	return selections, nil
+}
+
+// Allocate places the students who ranked the category's offerings and stores each section's
+// students in its lesson group; sections left empty get no group. Choices are honoured rank by rank:
+// every student's first choice is tried before anyone's second, and when an offering fills up earlier
+// submissions win. Students of an offering are spread over its sections in proportion to their
+// capacity, keeping classmates together. Re-allocating is refused once a section's lesson group has
+// sessions.
+func (s *ElectiveService) Allocate(ctx context.Context, req dto.ElectiveAllocationRequest) (*dto.ElectiveAllocationReport, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective allocation payload")
+	}
+	category := normalizeElectiveCategory(req.Category)
+	offerings, selections, err := s.loadCategory(ctx, req.TermID, category)
+	if err != nil {
+		return nil, err
+	}
+
+	report := allocateElectives(offerings, selections)
+	report.TermID, report.Category, report.DryRun = req.TermID, category, req.DryRun
+	if req.DryRun {
+		return report, nil
+	}
+
+	sections := make(map[string]models.ElectiveSection)
+	offeringOf := make(map[string]models.ElectiveOffering)
+	ownGroups := make(map[string]struct{})
+	for _, offering := range offerings {
+		for _, section := range offering.Sections {
+			sections[section.ID] = section
+			offeringOf[section.ID] = offering
+			if section.LessonGroupID == nil {
+				continue
+			}
+			ownGroups[*section.LessonGroupID] = struct{}{}
+			sessions, err := s.lessonGroups.ListSessions(ctx, *section.LessonGroupID)
+			if err != nil {
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson group sessions")
+			}
+			if len(sessions) > 0 {
+				return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("section %s %s already has sessions; remove them before re-allocating", offering.Name, section.Name))
+			}
+		}
+	}
+	existing, err := s.lessonGroups.ListByTerm(ctx, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson groups")
+	}
+	taken := make(map[string]struct{}, len(existing))
+	for _, group := range existing {
+		if _, ok := ownGroups[group.ID]; !ok {
+			taken[strings.ToLower(group.Name)] = struct{}{}
+		}
+	}
+
+	groups := make([]models.ElectiveSectionGroup, 0, len(report.Sections))
+	for _, placed := range report.Sections {
+		section, offering := sections[placed.SectionID], offeringOf[placed.SectionID]
+		if len(placed.Students) == 0 && section.LessonGroupID == nil {
+			continue
+		}
+		group := models.LessonGroup{
+			TermID:    offering.TermID,
+			SubjectID: offering.SubjectID,
+			TeacherID: section.TeacherID,
+			Name:      offering.Name + " " + section.Name,
+			Room:      section.Room,
+			Members:   electiveMembers(placed.Students),
+		}
+		if section.LessonGroupID != nil {
+			group.ID = *section.LessonGroupID
+		}
+		if _, ok := taken[strings.ToLower(group.Name)]; ok {
+			return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("a lesson group named %s already exists in the term", group.Name))
+		}
+		groups = append(groups, models.ElectiveSectionGroup{SectionID: section.ID, Group: group})
+	}
+	if err := s.store.SaveAllocation(ctx, groups); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save elective allocation")
+	}
+	groupIDs := make(map[string]string, len(groups))
+	for _, saved := range groups {
+		groupIDs[saved.SectionID] = saved.Group.ID
+	}
+	for i := range report.Sections {
+		report.Sections[i].LessonGroupID = groupIDs[report.Sections[i].SectionID]
+	}
+	return report, nil
+}
+
+// Allocation reports the stored allocation of a category from its sections' lesson groups.
+func (s *ElectiveService) Allocation(ctx context.Context, query dto.ElectiveAllocationQuery) (*dto.ElectiveAllocationReport, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid elective allocation query")
+	}
+	category := normalizeElectiveCategory(query.Category)
+	offerings, selections, err := s.loadCategory(ctx, query.TermID, category)
+	if err != nil {
+		return nil, err
+	}
+	students := electiveStudents(selections)
+	byEnrollment := make(map[string]*electiveStudent, len(students))
+	for i := range students {
+		byEnrollment[students[i].enrollmentID] = &students[i]
+	}
+
+	report := newElectiveReport(query.TermID, category, len(students))
+	placed := make(map[string]struct{})
+	for _, offering := range offerings {
+		for _, section := range offering.Sections {
+			allocation := electiveSectionAllocation(offering, section)
+			if section.LessonGroupID != nil {
+				allocation.LessonGroupID = *section.LessonGroupID
+				group, err := s.lessonGroups.FindByID(ctx, *section.LessonGroupID)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load lesson group")
+				}
+				if group != nil {
+					for _, member := range group.Members {
+						for _, enrollmentID := range member.EnrollmentIDs {
+							entry := dto.ElectiveAllocationStudent{EnrollmentID: enrollmentID, ClassID: member.ClassID}
+							if student, ok := byEnrollment[enrollmentID]; ok {
+								entry.StudentID = student.studentID
+								entry.Rank = student.rankOf(offering.ID)
+								if entry.Rank > 0 {
+									report.ChoiceCounts[entry.Rank]++
+								}
+							}
+							allocation.Students = append(allocation.Students, entry)
+							placed[enrollmentID] = struct{}{}
+						}
+					}
+				}
+			}
+			report.Allocated += len(allocation.Students)
+			report.Sections = append(report.Sections, allocation)
+		}
+	}
+	for _, student := range students {
+		if _, ok := placed[student.enrollmentID]; !ok {
+			report.Unallocated = append(report.Unallocated, student.ref(0))
+		}
+	}
+	return report, nil
+}
+
+func (s *ElectiveService) loadCategory(ctx context.Context, termID, category string) ([]models.ElectiveOffering, []models.ElectiveSelection, error) {
+	offerings, err := s.store.ListOfferings(ctx, termID, category)
+	if err != nil {
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective offerings")
+	}
+	if len(offerings) == 0 {
+		return nil, nil, appErrors.Clone(appErrors.ErrNotFound, "no elective offerings in this category")
+	}
+	selections, err := s.store.ListSelections(ctx, models.ElectiveSelectionFilter{TermID: termID, Category: category})
+	if err != nil {
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective selections")
+	}
+	return offerings, selections, nil
+}
+
+// buildSections validates requested sections against the offering's current ones. Section names
+// must be distinct, and allocated sections keep their teacher.
+func (s *ElectiveService) buildSections(ctx context.Context, current []models.ElectiveSection, reqs []dto.ElectiveSectionRequest) ([]models.ElectiveSection, error) {
+	existing := make(map[string]models.ElectiveSection, len(current))
+	for _, section := range current {
+		existing[section.ID] = section
+	}
+	names := make(map[string]struct{}, len(reqs))
+	sections := make([]models.ElectiveSection, 0, len(reqs))
+	for _, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		if _, ok := names[strings.ToLower(name)]; ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("section %s is listed twice", name))
+		}
+		names[strings.ToLower(name)] = struct{}{}
+		section := models.ElectiveSection{Name: name, TeacherID: req.TeacherID, Capacity: req.Capacity, Room: trimmedRoom(req.Room)}
+		if req.ID != "" {
+			previous, ok := existing[req.ID]
+			if !ok {
+				return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("section %s does not belong to the offering", req.ID))
+			}
+			if previous.LessonGroupID != nil && previous.TeacherID != req.TeacherID {
+				return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("section %s has allocated students; change the teacher of its lesson group instead", previous.Name))
+			}
+			section.ID = previous.ID
+			section.LessonGroupID = previous.LessonGroupID
+		}
+		if s.teachers != nil {
+			if _, err := s.teachers.FindByID(ctx, req.TeacherID); err != nil {
+				return nil, lookupError(err, "teacher")
+			}
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+func (s *ElectiveService) ensureUniqueOfferingName(ctx context.Context, termID, name, ignoreID string) error {
+	offerings, err := s.store.ListOfferings(ctx, termID, "")
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list elective offerings")
+	}
+	for _, offering := range offerings {
+		if offering.ID != ignoreID && strings.EqualFold(offering.Name, name) {
+			return appErrors.Clone(appErrors.ErrConflict, "an elective offering with this name already exists in the term")
+		}
+	}
+	return nil
+}
+
+func (s *ElectiveService) resolveEnrollment(ctx context.Context, termID, enrollmentID string, claims *models.JWTClaims) (*models.Enrollment, error) {
+	if claims.Role == models.RoleStudent {
+		student, err := s.students.FindByUserID(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, appErrors.Clone(appErrors.ErrForbidden, "account is not linked to a student")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+		}
+		enrollments, err := s.enrollments.FindActiveByStudentAndTerm(ctx, student.ID, termID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+		}
+		if len(enrollments) == 0 {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "student is not enrolled in term")
+		}
+		return &enrollments[0], nil
+	}
+	if enrollmentID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "enrollmentId is required")
+	}
+	enrollment, err := s.enrollments.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, lookupError(err, "enrollment")
+	}
+	if enrollment.TermID != termID || enrollment.Status != models.EnrollmentStatusActive {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "enrollment is not active in the term")
+	}
+	return enrollment, nil
+}
+
+func normalizeElectiveCategory(category string) string {
+	return strings.ToUpper(strings.TrimSpace(category))
+}
+
+// electiveStudent is one student's ranked offerings in a category.
+type electiveStudent struct {
+	enrollmentID string
+	studentID    string
+	classID      string
+	choices      []string
+}
+
+func (s electiveStudent) rankOf(offeringID string) int {
+	for i, choice := range s.choices {
+		if choice == offeringID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (s electiveStudent) ref(rank int) dto.ElectiveAllocationStudent {
+	return dto.ElectiveAllocationStudent{EnrollmentID: s.enrollmentID, StudentID: s.studentID, ClassID: s.classID, Rank: rank}
+}
+
+// electiveStudents folds selections, ordered by submission time, into students in that order with
+// their choices by rank.
+func electiveStudents(selections []models.ElectiveSelection) []electiveStudent {
+	index := make(map[string]int)
+	students := make([]electiveStudent, 0)
+	for _, selection := range selections {
+		i, ok := index[selection.EnrollmentID]
+		if !ok {
+			i = len(students)
+			index[selection.EnrollmentID] = i
+			students = append(students, electiveStudent{enrollmentID: selection.EnrollmentID, studentID: selection.StudentID, classID: selection.ClassID})
+		}
+		students[i].choices = append(students[i].choices, selection.OfferingID)
+	}
+	return students
+}
+
+func newElectiveReport(termID, category string, students int) *dto.ElectiveAllocationReport {
+	return &dto.ElectiveAllocationReport{
+		TermID:       termID,
+		Category:     category,
+		Students:     students,
+		ChoiceCounts: map[int]int{},
+		Sections:     make([]dto.ElectiveSectionAllocation, 0),
+		Unallocated:  make([]dto.ElectiveAllocationStudent, 0),
+	}
+}
+
+func electiveSectionAllocation(offering models.ElectiveOffering, section models.ElectiveSection) dto.ElectiveSectionAllocation {
+	return dto.ElectiveSectionAllocation{
+		OfferingID:   offering.ID,
+		OfferingName: offering.Name,
+		SectionID:    section.ID,
+		SectionName:  section.Name,
+		Capacity:     section.Capacity,
+		Students:     make([]dto.ElectiveAllocationStudent, 0),
+	}
+}
+
+// allocateElectives places students rank by rank and spreads each offering's students over its
+// sections.
+func allocateElectives(offerings []models.ElectiveOffering, selections []models.ElectiveSelection) *dto.ElectiveAllocationReport {
+	students := electiveStudents(selections)
+	report := newElectiveReport("", "", len(students))
+
+	remaining := make(map[string]int, len(offerings))
+	for _, offering := range offerings {
+		remaining[offering.ID] = offering.Capacity()
+	}
+	assigned := make([]string, len(students))
+	maxChoices := 0
+	for _, student := range students {
+		if len(student.choices) > maxChoices {
+			maxChoices = len(student.choices)
+		}
+	}
+	for rank := 0; rank < maxChoices; rank++ {
+		for i, student := range students {
+			if assigned[i] != "" || rank >= len(student.choices) {
+				continue
+			}
+			choice := student.choices[rank]
+			if remaining[choice] > 0 {
+				remaining[choice]--
+				assigned[i] = choice
+			}
+		}
+	}
+
+	byOffering := make(map[string][]dto.ElectiveAllocationStudent)
+	for i, student := range students {
+		if assigned[i] == "" {
+			report.Unallocated = append(report.Unallocated, student.ref(0))
+			continue
+		}
+		rank := student.rankOf(assigned[i])
+		report.ChoiceCounts[rank]++
+		report.Allocated++
+		byOffering[assigned[i]] = append(byOffering[assigned[i]], student.ref(rank))
+	}
+	for _, offering := range offerings {
+		placed := byOffering[offering.ID]
+		sort.SliceStable(placed, func(i, j int) bool {
+			if placed[i].ClassID != placed[j].ClassID {
+				return placed[i].ClassID < placed[j].ClassID
+			}
+			return placed[i].EnrollmentID < placed[j].EnrollmentID
+		})
+		targets := sectionTargets(offering.Sections, len(placed))
+		next := 0
+		for i, section := range offering.Sections {
+			allocation := electiveSectionAllocation(offering, section)
+			allocation.Students = append(allocation.Students, placed[next:next+targets[i]]...)
+			next += targets[i]
+			report.Sections = append(report.Sections, allocation)
+		}
+	}
+	return report
+}
+
+// sectionTargets splits n students over sections in proportion to their capacity using largest
+// remainders. n never exceeds the total capacity, so no section is filled beyond its own.
+func sectionTargets(sections []models.ElectiveSection, n int) []int {
+	targets := make([]int, len(sections))
+	total := 0
+	for _, section := range sections {
+		total += section.Capacity
+	}
+	if total == 0 || n == 0 {
+		return targets
+	}
+	remainders := make([]int, len(sections))
+	assigned := 0
+	for i, section := range sections {
+		targets[i] = n * section.Capacity / total
+		remainders[i] = n * section.Capacity % total
+		assigned += targets[i]
+	}
+	order := make([]int, len(sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order[:n-assigned] {
+		targets[i]++
+	}
+	return targets
+}
+
+// electiveMembers groups a section's students by class into lesson group members.
+func electiveMembers(students []dto.ElectiveAllocationStudent) []models.LessonGroupMember {
+	members := make([]models.LessonGroupMember, 0)
+	index := make(map[string]int)
+	for _, student := range students {
+		i, ok := index[student.ClassID]
+		if !ok {
+			i = len(members)
+			index[student.ClassID] = i
+			members = append(members, models.LessonGroupMember{ClassID: student.ClassID})
+		}
+		members[i].EnrollmentIDs = append(members[i].EnrollmentIDs, student.EnrollmentID)
+	}
+	return members
+}
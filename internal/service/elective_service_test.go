@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type electiveStoreStub struct {
+	offerings  []models.ElectiveOffering
+	selections []models.ElectiveSelection
+	replaced   []string
+	saved      []models.ElectiveSectionGroup
+}
+
+func (s *electiveStoreStub) CreateOffering(_ context.Context, offering *models.ElectiveOffering) error {
+	offering.ID = "offering-new"
+	s.offerings = append(s.offerings, *offering)
+	return nil
+}
+
+func (s *electiveStoreStub) UpdateOffering(_ context.Context, offering *models.ElectiveOffering) error {
+	for i := range s.offerings {
+		if s.offerings[i].ID == offering.ID {
+			s.offerings[i] = *offering
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (s *electiveStoreStub) FindOffering(_ context.Context, id string) (*models.ElectiveOffering, error) {
+	for _, offering := range s.offerings {
+		if offering.ID == id {
+			copied := offering
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *electiveStoreStub) ListOfferings(_ context.Context, termID, category string) ([]models.ElectiveOffering, error) {
+	var offerings []models.ElectiveOffering
+	for _, offering := range s.offerings {
+		if offering.TermID == termID && (category == "" || offering.Category == category) {
+			offerings = append(offerings, offering)
+		}
+	}
+	return offerings, nil
+}
+
+func (s *electiveStoreStub) DeleteOffering(context.Context, string) error {
+	return nil
+}
+
+func (s *electiveStoreStub) ReplaceSelections(_ context.Context, _ string, offeringIDs []string, selections []models.ElectiveSelection) error {
+	s.replaced = offeringIDs
+	s.selections = append(s.selections, selections...)
+	return nil
+}
+
+func (s *electiveStoreStub) ListSelections(_ context.Context, filter models.ElectiveSelectionFilter) ([]models.ElectiveSelection, error) {
+	var selections []models.ElectiveSelection
+	for _, selection := range s.selections {
+		if filter.EnrollmentID == "" || selection.EnrollmentID == filter.EnrollmentID {
+			selections = append(selections, selection)
+		}
+	}
+	return selections, nil
+}
+
+func (s *electiveStoreStub) SaveAllocation(_ context.Context, groups []models.ElectiveSectionGroup) error {
+	for i := range groups {
+		if groups[i].Group.ID == "" {
+			groups[i].Group.ID = "group-" + groups[i].SectionID
+		}
+	}
+	s.saved = groups
+	return nil
+}
+
+type electiveStudentStub map[string]*models.StudentDetail
+
+func (s electiveStudentStub) FindByUserID(_ context.Context, userID string) (*models.StudentDetail, error) {
+	student, ok := s[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return student, nil
+}
+
+type electiveEnrollmentStub []models.Enrollment
+
+func (s electiveEnrollmentStub) FindByID(_ context.Context, id string) (*models.Enrollment, error) {
+	for _, enrollment := range s {
+		if enrollment.ID == id {
+			copied := enrollment
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s electiveEnrollmentStub) FindActiveByStudentAndTerm(_ context.Context, studentID, termID string) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	for _, enrollment := range s {
+		if enrollment.StudentID == studentID && enrollment.TermID == termID && enrollment.Status == models.EnrollmentStatusActive {
+			enrollments = append(enrollments, enrollment)
+		}
+	}
+	return enrollments, nil
+}
+
+func electiveSelections(choices map[string][]string) []models.ElectiveSelection {
+	base := time.Date(2026, 7, 1, 8, 0, 0, 0, time.UTC)
+	order := []string{"enr-a1", "enr-a2", "enr-b1", "enr-b2", "enr-b3"}
+	var selections []models.ElectiveSelection
+	for i, enrollmentID := range order {
+		for rank, offeringID := range choices[enrollmentID] {
+			selections = append(selections, models.ElectiveSelection{
+				EnrollmentID: enrollmentID,
+				OfferingID:   offeringID,
+				Rank:         rank + 1,
+				SubmittedAt:  base.Add(time.Duration(i) * time.Minute),
+				StudentID:    "student-" + enrollmentID[4:],
+				ClassID:      "class-" + enrollmentID[4:5],
+			})
+		}
+	}
+	return selections
+}
+
+func electiveOfferings() []models.ElectiveOffering {
+	return []models.ElectiveOffering{
+		{ID: "off-econ", TermID: "term-1", SubjectID: "economics", Category: "IPS", Name: "Economics", Sections: []models.ElectiveSection{
+			{ID: "sec-econ-1", OfferingID: "off-econ", Name: "A", TeacherID: "teacher-1", Capacity: 2},
+			{ID: "sec-econ-2", OfferingID: "off-econ", Name: "B", TeacherID: "teacher-2", Capacity: 1},
+		}},
+		{ID: "off-geo", TermID: "term-1", SubjectID: "geography", Category: "IPS", Name: "Geography", Sections: []models.ElectiveSection{
+			{ID: "sec-geo-1", OfferingID: "off-geo", Name: "A", TeacherID: "teacher-3", Capacity: 1},
+		}},
+	}
+}
+
+func TestAllocateElectivesHonoursRanksAndBalancesSections(t *testing.T) {
+	selections := electiveSelections(map[string][]string{
+		"enr-a1": {"off-econ", "off-geo"},
+		"enr-a2": {"off-econ", "off-geo"},
+		"enr-b1": {"off-econ"},
+		"enr-b2": {"off-econ", "off-geo"},
+		"enr-b3": {"off-geo"},
+	})
+
+	report := allocateElectives(electiveOfferings(), selections)
+
+	assert.Equal(t, 5, report.Students)
+	assert.Equal(t, 4, report.Allocated)
+	assert.Equal(t, map[int]int{1: 4}, report.ChoiceCounts)
+	require.Len(t, report.Sections, 3)
+	assert.Equal(t, []string{"enr-a1", "enr-a2"}, allocatedEnrollments(report.Sections[0]))
+	assert.Equal(t, []string{"enr-b1"}, allocatedEnrollments(report.Sections[1]))
+	// enr-b2 loses economics to earlier submissions, and geography goes to enr-b3's first choice
+	// before second choices are tried.
+	assert.Equal(t, []string{"enr-b3"}, allocatedEnrollments(report.Sections[2]))
+	assert.Equal(t, 1, report.Sections[2].Students[0].Rank)
+	require.Len(t, report.Unallocated, 1)
+	assert.Equal(t, "enr-b2", report.Unallocated[0].EnrollmentID)
+}
+
+func TestSectionTargetsFollowCapacity(t *testing.T) {
+	sections := []models.ElectiveSection{{Capacity: 30}, {Capacity: 20}, {Capacity: 10}}
+	assert.Equal(t, []int{15, 10, 5}, sectionTargets(sections, 30))
+	assert.Equal(t, []int{4, 2, 1}, sectionTargets(sections, 7))
+	assert.Equal(t, []int{0, 0, 0}, sectionTargets(sections, 0))
+}
+
+func allocatedEnrollments(section dto.ElectiveSectionAllocation) []string {
+	ids := make([]string, 0, len(section.Students))
+	for _, student := range section.Students {
+		ids = append(ids, student.EnrollmentID)
+	}
+	return ids
+}
+
+func newElectiveFixture() (*ElectiveService, *electiveStoreStub, *lessonGroupStoreStub) {
+	store := &electiveStoreStub{offerings: electiveOfferings()}
+	groups := &lessonGroupStoreStub{groups: map[string]*models.LessonGroup{}}
+	svc := NewElectiveService(ElectiveServiceParams{
+		Store:        store,
+		LessonGroups: groups,
+		Students:     electiveStudentStub{"user-a1": {Student: models.Student{ID: "student-a1"}}},
+		Enrollments: electiveEnrollmentStub{
+			{ID: "enr-a1", StudentID: "student-a1", ClassID: "class-a", TermID: "term-1", Status: models.EnrollmentStatusActive},
+			{ID: "enr-old", StudentID: "student-a1", ClassID: "class-a", TermID: "term-0", Status: models.EnrollmentStatusActive},
+		},
+	})
+	return svc, store, groups
+}
+
+func TestElectiveServiceSubmitSelectionsForStudent(t *testing.T) {
+	svc, store, _ := newElectiveFixture()
+	claims := &models.JWTClaims{UserID: "user-a1", Role: models.RoleStudent}
+
+	selections, err := svc.SubmitSelections(context.Background(), dto.ElectiveSelectionRequest{
+		TermID: "term-1", Category: " ips ", EnrollmentID: "enr-old", OfferingIDs: []string{"off-geo", "off-econ"},
+	}, claims)
+	require.NoError(t, err)
+	require.Len(t, selections, 2)
+	assert.Equal(t, "enr-a1", selections[0].EnrollmentID)
+	assert.Equal(t, 1, selections[0].Rank)
+	assert.Equal(t, "off-econ", selections[1].OfferingID)
+	assert.ElementsMatch(t, []string{"off-econ", "off-geo"}, store.replaced)
+
+	_, err = svc.SubmitSelections(context.Background(), dto.ElectiveSelectionRequest{
+		TermID: "term-1", Category: "IPS", OfferingIDs: []string{"off-geo", "off-geo"},
+	}, claims)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.SubmitSelections(context.Background(), dto.ElectiveSelectionRequest{
+		TermID: "term-1", Category: "IPS", OfferingIDs: []string{"off-geo"},
+	}, &models.JWTClaims{UserID: "user-unknown", Role: models.RoleStudent})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+}
+
+func TestElectiveServiceAllocateStoresLessonGroups(t *testing.T) {
+	svc, store, groups := newElectiveFixture()
+	store.selections = electiveSelections(map[string][]string{
+		"enr-a1": {"off-econ"},
+		"enr-b1": {"off-econ"},
+		"enr-b2": {"off-geo"},
+	})
+	existing := "group-existing"
+	store.offerings[1].Sections[0].LessonGroupID = &existing
+
+	report, err := svc.Allocate(context.Background(), dto.ElectiveAllocationRequest{TermID: "term-1", Category: "ips"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Allocated)
+	require.Len(t, store.saved, 3)
+	assert.Equal(t, "Economics A", store.saved[0].Group.Name)
+	assert.Equal(t, "teacher-1", store.saved[0].Group.TeacherID)
+	assert.Equal(t, "economics", store.saved[0].Group.SubjectID)
+	assert.Equal(t, []models.LessonGroupMember{{ClassID: "class-a", EnrollmentIDs: []string{"enr-a1"}}}, store.saved[0].Group.Members)
+	assert.Equal(t, []models.LessonGroupMember{{ClassID: "class-b", EnrollmentIDs: []string{"enr-b1"}}}, store.saved[1].Group.Members)
+	assert.Equal(t, "group-existing", report.Sections[2].LessonGroupID)
+	assert.Equal(t, "group-sec-econ-1", report.Sections[0].LessonGroupID)
+
+	store.saved = nil
+	groups.schedules = []models.Schedule{{ID: "sched-1", LessonGroupID: &existing}}
+	_, err = svc.Allocate(context.Background(), dto.ElectiveAllocationRequest{TermID: "term-1", Category: "IPS"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+	assert.Nil(t, store.saved)
+
+	report, err = svc.Allocate(context.Background(), dto.ElectiveAllocationRequest{TermID: "term-1", Category: "IPS", DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Empty(t, report.Sections[0].LessonGroupID)
+}
+
+func TestElectiveServiceUpdateKeepsAllocatedSections(t *testing.T) {
+	svc, store, _ := newElectiveFixture()
+	group := "group-econ-a"
+	store.offerings[0].Sections[0].LessonGroupID = &group
+
+	_, err := svc.UpdateOffering(context.Background(), "off-econ", dto.ElectiveOfferingRequest{
+		TermID: "term-1", SubjectID: "economics", Category: "IPS", Name: "Economics",
+		Sections: []dto.ElectiveSectionRequest{{ID: "sec-econ-2", Name: "B", TeacherID: "teacher-2", Capacity: 3}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.UpdateOffering(context.Background(), "off-econ", dto.ElectiveOfferingRequest{
+		TermID: "term-1", SubjectID: "economics", Category: "IPS", Name: "Economics",
+		Sections: []dto.ElectiveSectionRequest{{ID: "sec-econ-1", Name: "A", TeacherID: "teacher-9", Capacity: 2}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	updated, err := svc.UpdateOffering(context.Background(), "off-econ", dto.ElectiveOfferingRequest{
+		TermID: "term-1", SubjectID: "economics", Category: "IPS", Name: "Economics",
+		Sections: []dto.ElectiveSectionRequest{{ID: "sec-econ-1", Name: "A", TeacherID: "teacher-1", Capacity: 4}, {Name: "C", TeacherID: "teacher-4", Capacity: 2}},
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.Sections, 2)
+	assert.Equal(t, &group, updated.Sections[0].LessonGroupID)
+	assert.Equal(t, 6, updated.Capacity())
+
+	err = svc.DeleteOffering(context.Background(), "off-econ")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
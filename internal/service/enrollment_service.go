@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -22,18 +23,6 @@ type enrollmentRepository interface {
 	UpdateStatus(ctx context.Context, id string, status models.EnrollmentStatus, leftAt *time.Time) error
 }
 
-type studentReader interface {
-	FindByID(ctx context.Context, id string) (*models.StudentDetail, error)
-}
-
-type enrollmentClassReader interface {
-	FindByID(ctx context.Context, id string) (*models.Class, error)
-}
-
-type enrollmentTermReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-}
-
 // EnrollStudentRequest describes enrollment creation request.
 type EnrollStudentRequest struct {
 	StudentID string `json:"student_id" validate:"required"`
@@ -49,15 +38,15 @@ type TransferEnrollmentRequest struct {
 // EnrollmentService orchestrates enrollment workflows.
 type EnrollmentService struct {
 	repo      enrollmentRepository
-	students  studentReader
-	classes   enrollmentClassReader
-	terms     enrollmentTermReader
+	students  ports.StudentReader
+	classes   ports.ClassReader
+	terms     ports.TermReader
 	validator *validator.Validate
 	logger    *zap.Logger
 }
 
 // NewEnrollmentService constructs EnrollmentService.
-func NewEnrollmentService(repo enrollmentRepository, students studentReader, classes enrollmentClassReader, terms enrollmentTermReader, validate *validator.Validate, logger *zap.Logger) *EnrollmentService {
+func NewEnrollmentService(repo enrollmentRepository, students ports.StudentReader, classes ports.ClassReader, terms ports.TermReader, validate *validator.Validate, logger *zap.Logger) *EnrollmentService {
 	if validate == nil {
 		validate = validator.New()
 	}
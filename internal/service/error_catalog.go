@@ -0,0 +1,41 @@
+package service
+
+import appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+
+// Every service module registers the error codes it can return so /internal/error-catalog stays
+// in step with the code. Update the list when a service starts returning a new code.
+func init() {
+	var (
+		notFound     = appErrors.ErrNotFound
+		validation   = appErrors.ErrValidation
+		conflict     = appErrors.ErrConflict
+		forbidden    = appErrors.ErrForbidden
+		unauthorized = appErrors.ErrUnauthorized
+		precondition = appErrors.ErrPreconditionFailed
+		internal     = appErrors.ErrInternal
+	)
+
+	appErrors.Register("analytics", internal)
+	appErrors.Register("announcements", notFound, validation, internal)
+	appErrors.Register("archives", forbidden, notFound, unauthorized, validation, appErrors.ErrQuotaExceeded, internal)
+	appErrors.Register("attendance", conflict, notFound, validation, forbidden, unauthorized, internal)
+	appErrors.Register("auth", appErrors.ErrInvalidCredentials, appErrors.ErrInactiveAccount, forbidden, notFound, unauthorized, validation, internal)
+	appErrors.Register("behavior", validation, internal)
+	appErrors.Register("calendar", forbidden, notFound, unauthorized, validation, internal)
+	appErrors.Register("classes", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("configuration", notFound, unauthorized, validation, internal)
+	appErrors.Register("dashboard", validation, internal)
+	appErrors.Register("enrollments", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("grades", conflict, appErrors.ErrFinalized, appErrors.ErrInvalidWeights, notFound, precondition, validation, internal)
+	appErrors.Register("homerooms", forbidden, notFound, precondition, unauthorized, validation, internal)
+	appErrors.Register("mutations", conflict, forbidden, notFound, precondition, unauthorized, validation, internal)
+	appErrors.Register("reports", forbidden, notFound, validation, appErrors.ErrInsufficientStorage, internal)
+	appErrors.Register("schedules", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("security", internal)
+	appErrors.Register("students", conflict, notFound, validation, internal)
+	appErrors.Register("subjects", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("teacher-assignments", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("teachers", conflict, notFound, validation, internal)
+	appErrors.Register("terms", conflict, notFound, precondition, validation, internal)
+	appErrors.Register("users", conflict, notFound, validation, internal)
+}
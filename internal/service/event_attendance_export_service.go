@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+type eventAttendanceLister interface {
+	List(ctx context.Context, eventID string) ([]models.EventRSVP, error)
+}
+
+// EventAttendanceExportService renders the RSVP and check-in list of a calendar event and publishes
+// it via a signed URL, like the exam timetable exports.
+type EventAttendanceExportService struct {
+	events    calendarEventReader
+	rsvps     eventAttendanceLister
+	store     scheduleExportStore
+	jobs      scheduleExportJobRecorder
+	pdf       timetableRenderer
+	xlsx      timetableRenderer
+	validator *validator.Validate
+	logger    *zap.Logger
+}
+
+// NewEventAttendanceExportService constructs an EventAttendanceExportService.
+func NewEventAttendanceExportService(events calendarEventReader, rsvps eventAttendanceLister, store scheduleExportStore, jobs scheduleExportJobRecorder, validate *validator.Validate, logger *zap.Logger) *EventAttendanceExportService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &EventAttendanceExportService{
+		events:    events,
+		rsvps:     rsvps,
+		store:     store,
+		jobs:      jobs,
+		pdf:       export.NewPDFExporter(),
+		xlsx:      export.NewXLSXExporter(),
+		validator: validate,
+		logger:    logger,
+	}
+}
+
+// Export renders the event's attendance list and returns a signed download URL.
+func (s *EventAttendanceExportService) Export(ctx context.Context, eventID string, req dto.EventAttendanceExportRequest, actorID string) (*dto.ScheduleExportResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid attendance export query")
+	}
+	if req.Format == "" {
+		req.Format = string(models.ReportFormatPDF)
+	}
+	event, err := s.events.GetByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "event not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to get event")
+	}
+	rsvps, err := s.rsvps.List(ctx, event.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list rsvps")
+	}
+
+	dataset := buildEventAttendanceDataset(rsvps)
+	title := fmt.Sprintf("Attendance %s - %s", event.Title, event.StartDate.Format("2006-01-02"))
+	format := models.ReportFormat(req.Format)
+	var payload []byte
+	switch format {
+	case models.ReportFormatXLSX:
+		payload, err = s.xlsx.Render(dataset, title)
+	default:
+		payload, err = s.pdf.Render(dataset, title)
+	}
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to render attendance export")
+	}
+
+	job := &models.ReportJob{
+		ID:   uuid.NewString(),
+		Type: models.ReportTypeEventAttendance,
+		Params: models.ReportJobParams{
+			Format: format,
+			Extras: map[string]string{"eventId": event.ID},
+		},
+		Status:    models.ReportStatusFinished,
+		Progress:  100,
+		CreatedBy: actorID,
+	}
+	filename := fmt.Sprintf("event_attendance_%s_%s.%s", sanitizeFilename(event.Title), time.Now().UTC().Format("20060102_150405"), format)
+	result, err := s.store.Store(job.ID, filename, format, payload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store attendance export")
+	}
+	now := time.Now().UTC()
+	job.ResultURL = &result.URL
+	job.FinishedAt = &now
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record attendance export")
+	}
+
+	return &dto.ScheduleExportResponse{
+		URL:       result.URL,
+		Format:    string(format),
+		View:      "attendance",
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
+
+// buildEventAttendanceDataset lists one row per RSVP or walk-in in name order.
+func buildEventAttendanceDataset(rsvps []models.EventRSVP) export.Dataset {
+	headers := []string{"Name", "Role", "RSVP", "Responded At (UTC)", "Checked In At (UTC)"}
+	rows := make([]map[string]string, 0, len(rsvps))
+	for _, rsvp := range rsvps {
+		checkedIn := ""
+		if rsvp.CheckedInAt != nil {
+			checkedIn = rsvp.CheckedInAt.UTC().Format("2006-01-02 15:04")
+		}
+		rows = append(rows, map[string]string{
+			"Name":                rsvp.FullName,
+			"Role":                string(rsvp.Role),
+			"RSVP":                string(rsvp.Response),
+			"Responded At (UTC)":  rsvp.RespondedAt.UTC().Format("2006-01-02 15:04"),
+			"Checked In At (UTC)": checkedIn,
+		})
+	}
+	return export.Dataset{Headers: headers, Rows: rows}
+}
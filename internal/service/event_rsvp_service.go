@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type calendarEventReader interface {
+	GetByID(ctx context.Context, id string) (*models.CalendarEvent, error)
+}
+
+type eventRSVPStore interface {
+	SetOptions(ctx context.Context, eventID string, enabled bool, capacity *int) error
+	Respond(ctx context.Context, rsvp *models.EventRSVP) (bool, error)
+	CheckIn(ctx context.Context, eventID, userID, checkedInBy string, at time.Time) (*models.EventRSVP, error)
+	List(ctx context.Context, eventID string) ([]models.EventRSVP, error)
+}
+
+type eventUserLookup interface {
+	FindByID(ctx context.Context, id string) (*models.User, error)
+}
+
+type guardianClassResolver interface {
+	ChildClassIDs(ctx context.Context, guardianID string) ([]string, error)
+}
+
+// EventRSVPConfig configures event RSVPs.
+type EventRSVPConfig struct {
+	// Location is the school's time zone; it decides which day is the event day.
+	Location *time.Location
+}
+
+// EventRSVPServiceParams groups constructor dependencies.
+type EventRSVPServiceParams struct {
+	Events    calendarEventReader
+	Store     eventRSVPStore
+	Users     eventUserLookup
+	Guardians guardianClassResolver
+	Validator *validator.Validate
+	Logger    *zap.Logger
+	Config    EventRSVPConfig
+}
+
+// EventRSVPService lets teachers and guardians RSVP to calendar events that accept them, within the
+// event's capacity, and lets staff check attendees in on the event day.
+type EventRSVPService struct {
+	events    calendarEventReader
+	store     eventRSVPStore
+	users     eventUserLookup
+	guardians guardianClassResolver
+	validator *validator.Validate
+	logger    *zap.Logger
+	cfg       EventRSVPConfig
+	now       func() time.Time
+}
+
+// NewEventRSVPService constructs the service.
+func NewEventRSVPService(params EventRSVPServiceParams) *EventRSVPService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	cfg := params.Config
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &EventRSVPService{
+		events:    params.Events,
+		store:     params.Store,
+		users:     params.Users,
+		guardians: params.Guardians,
+		validator: validate,
+		logger:    logger,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// SetOptions opens or closes RSVPs for an event and sets its capacity. Lowering the capacity keeps
+// the RSVPs already accepted.
+func (s *EventRSVPService) SetOptions(ctx context.Context, eventID string, req dto.EventRSVPOptionsRequest) (*models.CalendarEvent, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "capacity must be at least 1")
+	}
+	if err := s.store.SetOptions(ctx, eventID, req.Enabled, req.Capacity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "event not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update event rsvp options")
+	}
+	return s.event(ctx, eventID)
+}
+
+// Respond stores the caller's RSVP until the event is over. Teachers may answer events for teachers,
+// classes or everyone; guardians events for students or everyone and those of their children's
+// classes.
+func (s *EventRSVPService) Respond(ctx context.Context, eventID string, req dto.EventRSVPRequest, claims *models.JWTClaims) (*models.EventRSVP, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	req.Response = strings.ToUpper(strings.TrimSpace(req.Response))
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "response must be GOING or NOT_GOING")
+	}
+	event, err := s.event(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.RSVPEnabled {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "event does not accept RSVPs")
+	}
+	now := s.now()
+	if s.day(now) > event.EndDate.Format("2006-01-02") {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "event is over")
+	}
+	if err := s.ensureInvited(ctx, event, claims); err != nil {
+		return nil, err
+	}
+	rsvp := &models.EventRSVP{
+		EventID:     event.ID,
+		UserID:      claims.UserID,
+		Role:        claims.Role,
+		Response:    models.EventRSVPResponse(req.Response),
+		Note:        req.Note,
+		RespondedAt: now.UTC(),
+	}
+	accepted, err := s.store.Respond(ctx, rsvp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "event not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save rsvp")
+	}
+	if !accepted {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "event is full")
+	}
+	return rsvp, nil
+}
+
+// CheckIn records an attendee on the event day. Attendees without an RSVP are added as walk-ins and
+// do not count against the capacity.
+func (s *EventRSVPService) CheckIn(ctx context.Context, eventID string, req dto.EventCheckInRequest, claims *models.JWTClaims) (*models.EventRSVP, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "userId is required")
+	}
+	event, err := s.event(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	today := s.day(now)
+	if today < event.StartDate.Format("2006-01-02") || today > event.EndDate.Format("2006-01-02") {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "attendance can only be captured on the event day")
+	}
+	user, err := s.users.FindByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "user not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load user")
+	}
+	rsvp, err := s.store.CheckIn(ctx, event.ID, user.ID, claims.UserID, now.UTC())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check in attendee")
+	}
+	rsvp.FullName, rsvp.Role = user.FullName, user.Role
+	return rsvp, nil
+}
+
+// Report lists an event's RSVPs and check-ins with their counts.
+func (s *EventRSVPService) Report(ctx context.Context, eventID string) (*dto.EventRSVPReport, error) {
+	event, err := s.event(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	rsvps, err := s.store.List(ctx, event.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list rsvps")
+	}
+	report := &dto.EventRSVPReport{
+		EventID:     event.ID,
+		Title:       event.Title,
+		RSVPEnabled: event.RSVPEnabled,
+		Capacity:    event.RSVPCapacity,
+		RSVPs:       rsvps,
+	}
+	if report.RSVPs == nil {
+		report.RSVPs = []models.EventRSVP{}
+	}
+	for _, rsvp := range rsvps {
+		switch rsvp.Response {
+		case models.EventRSVPGoing:
+			report.Going++
+		case models.EventRSVPNotGoing:
+			report.NotGoing++
+		case models.EventRSVPWalkIn:
+			report.WalkIns++
+		}
+		if rsvp.CheckedInAt != nil {
+			report.CheckedIn++
+		}
+	}
+	if event.RSVPCapacity != nil {
+		left := *event.RSVPCapacity - report.Going
+		if left < 0 {
+			left = 0
+		}
+		report.SeatsLeft = &left
+	}
+	return report, nil
+}
+
+func (s *EventRSVPService) event(ctx context.Context, id string) (*models.CalendarEvent, error) {
+	event, err := s.events.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "event not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to get event")
+	}
+	return event, nil
+}
+
+// ensureInvited checks that the event's audience includes the caller.
+func (s *EventRSVPService) ensureInvited(ctx context.Context, event *models.CalendarEvent, claims *models.JWTClaims) error {
+	notInvited := appErrors.Clone(appErrors.ErrForbidden, "you are not invited to this event")
+	switch claims.Role {
+	case models.RoleTeacher:
+		if event.Audience == models.AnnouncementAudienceSiswa {
+			return notInvited
+		}
+		return nil
+	case models.RoleGuardian:
+		switch event.Audience {
+		case models.AnnouncementAudienceAll, models.AnnouncementAudienceSiswa:
+			return nil
+		case models.AnnouncementAudienceClass:
+			if s.guardians == nil || event.TargetClassID == nil {
+				return notInvited
+			}
+			classIDs, err := s.guardians.ChildClassIDs(ctx, claims.UserID)
+			if err != nil {
+				return err
+			}
+			for _, classID := range classIDs {
+				if classID == *event.TargetClassID {
+					return nil
+				}
+			}
+		}
+		return notInvited
+	default:
+		return appErrors.Clone(appErrors.ErrForbidden, "only teachers and guardians can RSVP")
+	}
+}
+
+// day returns the school-local date of t as YYYY-MM-DD, comparable with event dates.
+func (s *EventRSVPService) day(t time.Time) string {
+	return t.In(s.cfg.Location).Format("2006-01-02")
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type calendarEventStub struct {
+	events map[string]*models.CalendarEvent
+}
+
+func (s *calendarEventStub) GetByID(ctx context.Context, id string) (*models.CalendarEvent, error) {
+	if event, ok := s.events[id]; ok {
+		return event, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+type eventRSVPStoreStub struct {
+	events *calendarEventStub
+	rsvps  []models.EventRSVP
+}
+
+func (s *eventRSVPStoreStub) SetOptions(ctx context.Context, eventID string, enabled bool, capacity *int) error {
+	event, ok := s.events.events[eventID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	event.RSVPEnabled, event.RSVPCapacity = enabled, capacity
+	return nil
+}
+
+func (s *eventRSVPStoreStub) Respond(ctx context.Context, rsvp *models.EventRSVP) (bool, error) {
+	capacity := s.events.events[rsvp.EventID].RSVPCapacity
+	going := 0
+	for _, existing := range s.rsvps {
+		if existing.UserID != rsvp.UserID && existing.Response == models.EventRSVPGoing {
+			going++
+		}
+	}
+	if rsvp.Response == models.EventRSVPGoing && capacity != nil && going >= *capacity {
+		return false, nil
+	}
+	for i := range s.rsvps {
+		if s.rsvps[i].UserID == rsvp.UserID {
+			s.rsvps[i].Response = rsvp.Response
+			return true, nil
+		}
+	}
+	s.rsvps = append(s.rsvps, *rsvp)
+	return true, nil
+}
+
+func (s *eventRSVPStoreStub) CheckIn(ctx context.Context, eventID, userID, checkedInBy string, at time.Time) (*models.EventRSVP, error) {
+	for i := range s.rsvps {
+		if s.rsvps[i].UserID == userID {
+			s.rsvps[i].CheckedInAt = &at
+			rsvp := s.rsvps[i]
+			return &rsvp, nil
+		}
+	}
+	s.rsvps = append(s.rsvps, models.EventRSVP{EventID: eventID, UserID: userID, Response: models.EventRSVPWalkIn, RespondedAt: at, CheckedInAt: &at, CheckedInBy: &checkedInBy})
+	rsvp := s.rsvps[len(s.rsvps)-1]
+	return &rsvp, nil
+}
+
+func (s *eventRSVPStoreStub) List(ctx context.Context, eventID string) ([]models.EventRSVP, error) {
+	return s.rsvps, nil
+}
+
+type eventUserStub struct{}
+
+func (eventUserStub) FindByID(ctx context.Context, id string) (*models.User, error) {
+	if id == "missing" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.User{ID: id, FullName: "Walk In", Role: models.RoleGuardian}, nil
+}
+
+type guardianClassStub map[string][]string
+
+func (s guardianClassStub) ChildClassIDs(ctx context.Context, guardianID string) ([]string, error) {
+	return s[guardianID], nil
+}
+
+func newEventRSVPFixture(now time.Time) (*EventRSVPService, *eventRSVPStoreStub) {
+	day := time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC)
+	classID := "class-10a"
+	events := &calendarEventStub{events: map[string]*models.CalendarEvent{
+		"meeting": {ID: "meeting", Title: "Rapat Orang Tua", Audience: models.AnnouncementAudienceClass, TargetClassID: &classID, StartDate: day, EndDate: day},
+		"closed":  {ID: "closed", Title: "Upacara", Audience: models.AnnouncementAudienceAll, StartDate: day, EndDate: day},
+	}}
+	store := &eventRSVPStoreStub{events: events}
+	svc := NewEventRSVPService(EventRSVPServiceParams{
+		Events:    events,
+		Store:     store,
+		Users:     eventUserStub{},
+		Guardians: guardianClassStub{"guardian-1": {classID}, "guardian-2": {"class-10b"}, "guardian-3": {classID}},
+	})
+	svc.now = func() time.Time { return now }
+	return svc, store
+}
+
+func TestEventRSVPServiceRespond(t *testing.T) {
+	svc, _ := newEventRSVPFixture(time.Date(2026, 5, 10, 8, 0, 0, 0, time.UTC))
+	ctx := context.Background()
+	guardian := &models.JWTClaims{UserID: "guardian-1", Role: models.RoleGuardian}
+
+	_, err := svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "going"}, guardian)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	capacity := 1
+	_, err = svc.SetOptions(ctx, "meeting", dto.EventRSVPOptionsRequest{Enabled: true, Capacity: &capacity})
+	require.NoError(t, err)
+
+	rsvp, err := svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "going"}, guardian)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventRSVPGoing, rsvp.Response)
+
+	// Guardians of other classes are not invited and the only seat is taken.
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "GOING"}, &models.JWTClaims{UserID: "guardian-2", Role: models.RoleGuardian})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "GOING"}, &models.JWTClaims{UserID: "guardian-3", Role: models.RoleGuardian})
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "NOT_GOING"}, &models.JWTClaims{UserID: "guardian-3", Role: models.RoleGuardian})
+	require.NoError(t, err)
+
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "MAYBE"}, guardian)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "GOING"}, &models.JWTClaims{UserID: "student-1", Role: models.RoleStudent})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	report, err := svc.Report(ctx, "meeting")
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Going)
+	assert.Equal(t, 1, report.NotGoing)
+	require.NotNil(t, report.SeatsLeft)
+	assert.Zero(t, *report.SeatsLeft)
+}
+
+func TestEventRSVPServiceCheckIn(t *testing.T) {
+	ctx := context.Background()
+	staff := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+
+	early, _ := newEventRSVPFixture(time.Date(2026, 5, 19, 8, 0, 0, 0, time.UTC))
+	_, err := early.CheckIn(ctx, "meeting", dto.EventCheckInRequest{UserID: "guardian-1"}, staff)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	// 23:30 UTC on the 19th is already the event day in Jakarta.
+	svc, _ := newEventRSVPFixture(time.Date(2026, 5, 19, 23, 30, 0, 0, time.UTC))
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	svc.cfg.Location = jakarta
+	_, err = svc.SetOptions(ctx, "meeting", dto.EventRSVPOptionsRequest{Enabled: true})
+	require.NoError(t, err)
+	_, err = svc.Respond(ctx, "meeting", dto.EventRSVPRequest{Response: "GOING"}, &models.JWTClaims{UserID: "guardian-1", Role: models.RoleGuardian})
+	require.NoError(t, err)
+
+	rsvp, err := svc.CheckIn(ctx, "meeting", dto.EventCheckInRequest{UserID: "guardian-1"}, staff)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventRSVPGoing, rsvp.Response)
+	require.NotNil(t, rsvp.CheckedInAt)
+
+	rsvp, err = svc.CheckIn(ctx, "meeting", dto.EventCheckInRequest{UserID: "guardian-9"}, staff)
+	require.NoError(t, err)
+	assert.Equal(t, models.EventRSVPWalkIn, rsvp.Response)
+	assert.Equal(t, "Walk In", rsvp.FullName)
+
+	_, err = svc.CheckIn(ctx, "meeting", dto.EventCheckInRequest{UserID: "missing"}, staff)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	report, err := svc.Report(ctx, "meeting")
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Going)
+	assert.Equal(t, 1, report.WalkIns)
+	assert.Equal(t, 2, report.CheckedIn)
+	assert.Nil(t, report.SeatsLeft)
+
+	dataset := buildEventAttendanceDataset(report.RSVPs)
+	require.Len(t, dataset.Rows, 2)
+	assert.Equal(t, "WALK_IN", dataset.Rows[1]["RSVP"])
+	assert.NotEmpty(t, dataset.Rows[0]["Checked In At (UTC)"])
+}
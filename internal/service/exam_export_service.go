@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+type examExportReader interface {
+	FindPeriodByID(ctx context.Context, id string) (*models.ExamPeriod, error)
+	List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error)
+}
+
+// ExamExportService renders the exam timetable of one class or one room and publishes it via a
+// signed URL, like the semester schedule exports.
+type ExamExportService struct {
+	exams     examExportReader
+	subjects  ports.SubjectReader
+	teachers  ports.TeacherReader
+	classes   ports.ClassReader
+	labels    SlotTimeLabeler
+	store     scheduleExportStore
+	jobs      scheduleExportJobRecorder
+	pdf       timetableRenderer
+	xlsx      timetableRenderer
+	validator *validator.Validate
+	logger    *zap.Logger
+}
+
+// NewExamExportService constructs an ExamExportService.
+func NewExamExportService(
+	exams examExportReader,
+	subjects ports.SubjectReader,
+	teachers ports.TeacherReader,
+	classes ports.ClassReader,
+	labels SlotTimeLabeler,
+	store scheduleExportStore,
+	jobs scheduleExportJobRecorder,
+	validate *validator.Validate,
+	logger *zap.Logger,
+) *ExamExportService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if labels == nil {
+		labels = StaticSlotLabels{}
+	}
+	return &ExamExportService{
+		exams:     exams,
+		subjects:  subjects,
+		teachers:  teachers,
+		classes:   classes,
+		labels:    labels,
+		store:     store,
+		jobs:      jobs,
+		pdf:       export.NewPDFExporter(),
+		xlsx:      export.NewXLSXExporter(),
+		validator: validate,
+		logger:    logger,
+	}
+}
+
+// Export renders the period's sittings for a class or a room and returns a signed download URL.
+func (s *ExamExportService) Export(ctx context.Context, periodID string, req dto.ExamExportRequest, actorID string) (*dto.ScheduleExportResponse, error) {
+	if req.View == "" {
+		req.View = "class"
+		if req.ClassID == "" && req.Room != "" {
+			req.View = "room"
+		}
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid exam export query")
+	}
+	if req.Format == "" {
+		req.Format = string(models.ReportFormatPDF)
+	}
+
+	period, err := s.exams.FindPeriodByID(ctx, periodID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "exam period not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load exam period")
+	}
+	filter := models.ExamScheduleFilter{PeriodID: period.ID}
+	subject := req.Room
+	if req.View == "class" {
+		filter.ClassID = req.ClassID
+		subject = req.ClassID
+	} else {
+		filter.Room = req.Room
+	}
+	exams, err := s.exams.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam schedules")
+	}
+	labels, err := s.labels.SlotLabels(ctx, period.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load slot times")
+	}
+
+	names := newScheduleNameCache(s.subjects, s.teachers, s.classes)
+	dataset := buildExamDataset(ctx, exams, labels, names)
+	title := fmt.Sprintf("Exam Timetable %s - Room %s", period.Name, req.Room)
+	if req.View == "class" {
+		title = fmt.Sprintf("Exam Timetable %s - %s", period.Name, names.class(ctx, req.ClassID))
+	}
+
+	format := models.ReportFormat(req.Format)
+	var payload []byte
+	switch format {
+	case models.ReportFormatXLSX:
+		payload, err = s.xlsx.Render(dataset, title)
+	default:
+		payload, err = s.pdf.Render(dataset, title)
+	}
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to render exam export")
+	}
+
+	job := &models.ReportJob{
+		ID:   uuid.NewString(),
+		Type: models.ReportTypeExamSchedule,
+		Params: models.ReportJobParams{
+			TermID: period.TermID,
+			Format: format,
+			Extras: map[string]string{"periodId": period.ID, "view": req.View, "classId": req.ClassID, "room": req.Room},
+		},
+		Status:    models.ReportStatusFinished,
+		Progress:  100,
+		CreatedBy: actorID,
+	}
+	if req.ClassID != "" {
+		classID := req.ClassID
+		job.Params.ClassID = &classID
+	}
+	filename := fmt.Sprintf("exams_%s_%s_%s.%s", req.View, sanitizeFilename(subject), time.Now().UTC().Format("20060102_150405"), format)
+	result, err := s.store.Store(job.ID, filename, format, payload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store exam export")
+	}
+	now := time.Now().UTC()
+	job.ResultURL = &result.URL
+	job.FinishedAt = &now
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record exam export")
+	}
+
+	return &dto.ScheduleExportResponse{
+		URL:       result.URL,
+		Format:    string(format),
+		View:      req.View,
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
+
+// buildExamDataset lists one row per sitting in date and slot order.
+func buildExamDataset(ctx context.Context, exams []models.ExamSchedule, labels map[int]string, names *scheduleNameCache) export.Dataset {
+	headers := []string{"Date", "Day", "Slots", "Time", "Subject", "Class", "Room", "Invigilator"}
+	rows := make([]map[string]string, 0, len(exams))
+	for _, exam := range exams {
+		slots := fmt.Sprintf("%d", exam.StartSlot)
+		if exam.EndSlot != exam.StartSlot {
+			slots = fmt.Sprintf("%d-%d", exam.StartSlot, exam.EndSlot)
+		}
+		rows = append(rows, map[string]string{
+			"Date":        exam.ExamDate.Format("2006-01-02"),
+			"Day":         displayDayName(isoWeekday(exam.ExamDate)),
+			"Slots":       slots,
+			"Time":        examTimeLabel(labels, exam.StartSlot, exam.EndSlot),
+			"Subject":     names.subject(ctx, exam.SubjectID),
+			"Class":       names.class(ctx, exam.ClassID),
+			"Room":        exam.Room,
+			"Invigilator": names.teacher(ctx, exam.InvigilatorID),
+		})
+	}
+	return export.Dataset{Headers: headers, Rows: rows}
+}
+
+// examTimeLabel spans slot labels such as "07:00–07:45" and "07:45–08:30" into "07:00–08:30",
+// falling back to the raw labels when they are not time ranges.
+func examTimeLabel(labels map[int]string, startSlot, endSlot int) string {
+	first, last := labels[startSlot], labels[endSlot]
+	if startSlot == endSlot || first == "" || last == "" {
+		return first
+	}
+	from, _, okFirst := strings.Cut(first, "–")
+	_, to, okLast := strings.Cut(last, "–")
+	if !okFirst || !okLast {
+		return first + ", " + last
+	}
+	return from + "–" + to
+}
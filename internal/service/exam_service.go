@@ -0,0 +1,698 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	examConflictClass                  = "CLASS_EXAM"
+	examConflictRoom                   = "ROOM_EXAM"
+	examConflictInvigilator            = "INVIGILATOR_EXAM"
+	examConflictRoomRegular            = "ROOM_REGULAR"
+	examConflictInvigilatorRegular     = "INVIGILATOR_REGULAR"
+	examConflictInvigilatorUnavailable = "INVIGILATOR_UNAVAILABLE"
+	examConflictInvigilatorOnLeave     = "INVIGILATOR_ON_LEAVE"
+	examUnplacedNoSession              = "NO_FREE_SESSION"
+)
+
+var examConflictMessages = map[string]string{
+	examConflictClass:                  "class already sits another exam in this session",
+	examConflictRoom:                   "room already hosts another exam in this session",
+	examConflictInvigilator:            "invigilator already supervises another exam in this session",
+	examConflictRoomRegular:            "room is booked for a regular lesson in this session",
+	examConflictInvigilatorRegular:     "invigilator teaches a regular lesson in this session",
+	examConflictInvigilatorUnavailable: "invigilator is unavailable in this session",
+	examConflictInvigilatorOnLeave:     "invigilator is on approved leave that day",
+	examUnplacedNoSession:              "no free session left in the exam period",
+}
+
+type examStore interface {
+	ListPeriods(ctx context.Context, termID string) ([]models.ExamPeriod, error)
+	FindPeriodByID(ctx context.Context, id string) (*models.ExamPeriod, error)
+	CreatePeriod(ctx context.Context, period *models.ExamPeriod) error
+	DeletePeriod(ctx context.Context, id string) error
+	List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error)
+	FindByID(ctx context.Context, id string) (*models.ExamSchedule, error)
+	CreateBatch(ctx context.Context, exams []models.ExamSchedule) error
+	Delete(ctx context.Context, id string) error
+}
+
+type examLeaveReader interface {
+	TeachersOnLeave(ctx context.Context, date time.Time) ([]string, error)
+}
+
+type examRegularScheduleReader interface {
+	FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error)
+	ListByClass(ctx context.Context, classID string) ([]models.Schedule, error)
+}
+
+// ExamServiceParams groups constructor dependencies.
+type ExamServiceParams struct {
+	Store       examStore
+	Terms       ports.TermReader
+	Classes     ports.ClassReader
+	Subjects    ports.SubjectReader
+	Teachers    ports.TeacherReader
+	Regular     examRegularScheduleReader
+	Preferences ports.TeacherPreferenceReader
+	Leaves      examLeaveReader
+	Validator   *validator.Validate
+	Logger      *zap.Logger
+}
+
+// ExamService manages exam periods and the exam timetable. Sittings never share a class, room or
+// invigilator, and rooms or invigilators taken by the regular timetable are blocked for exams, as are
+// invigilators on approved leave when a leave reader is configured.
+type ExamService struct {
+	store       examStore
+	terms       ports.TermReader
+	classes     ports.ClassReader
+	subjects    ports.SubjectReader
+	teachers    ports.TeacherReader
+	regular     examRegularScheduleReader
+	preferences ports.TeacherPreferenceReader
+	leaves      examLeaveReader
+	validator   *validator.Validate
+	logger      *zap.Logger
+}
+
+// NewExamService constructs the service.
+func NewExamService(params ExamServiceParams) *ExamService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ExamService{
+		store:       params.Store,
+		terms:       params.Terms,
+		classes:     params.Classes,
+		subjects:    params.Subjects,
+		teachers:    params.Teachers,
+		regular:     params.Regular,
+		preferences: params.Preferences,
+		leaves:      params.Leaves,
+		validator:   validate,
+		logger:      logger,
+	}
+}
+
+// ListPeriods returns the exam periods of a term.
+func (s *ExamService) ListPeriods(ctx context.Context, termID string) ([]models.ExamPeriod, error) {
+	periods, err := s.store.ListPeriods(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam periods")
+	}
+	if periods == nil {
+		periods = []models.ExamPeriod{}
+	}
+	return periods, nil
+}
+
+// CreatePeriod adds an exam period. It must lie within the term and not overlap another period.
+func (s *ExamService) CreatePeriod(ctx context.Context, req dto.ExamPeriodRequest) (*models.ExamPeriod, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid exam period payload")
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "startDate must use YYYY-MM-DD format")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "endDate must use YYYY-MM-DD format")
+	}
+	if end.Before(start) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "endDate must not be before startDate")
+	}
+	term, err := s.terms.FindByID(ctx, req.TermID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+	}
+	if start.Before(dateOnly(term.StartDate)) || end.After(dateOnly(term.EndDate)) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "exam period must fall within the term")
+	}
+	existing, err := s.store.ListPeriods(ctx, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam periods")
+	}
+	for _, other := range existing {
+		if !start.After(dateOnly(other.EndDate)) && !dateOnly(other.StartDate).After(end) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("exam period overlaps %s", other.Name))
+		}
+	}
+
+	period := &models.ExamPeriod{TermID: req.TermID, Name: strings.TrimSpace(req.Name), StartDate: start, EndDate: end}
+	if err := s.store.CreatePeriod(ctx, period); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create exam period")
+	}
+	return period, nil
+}
+
+// DeletePeriod removes an exam period together with its sittings.
+func (s *ExamService) DeletePeriod(ctx context.Context, id string) error {
+	if err := s.store.DeletePeriod(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "exam period not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete exam period")
+	}
+	return nil
+}
+
+// List returns the sittings of an exam period.
+func (s *ExamService) List(ctx context.Context, periodID string, query dto.ExamScheduleQuery) ([]models.ExamSchedule, error) {
+	if _, err := s.period(ctx, periodID); err != nil {
+		return nil, err
+	}
+	filter := models.ExamScheduleFilter{PeriodID: periodID, ClassID: query.ClassID, Room: query.Room, InvigilatorID: query.InvigilatorID}
+	if query.Date != "" {
+		date, err := time.Parse("2006-01-02", query.Date)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "date must use YYYY-MM-DD format")
+		}
+		filter.Date = &date
+	}
+	exams, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam schedules")
+	}
+	if exams == nil {
+		exams = []models.ExamSchedule{}
+	}
+	return exams, nil
+}
+
+// Create places one sitting after checking it against other exams, the regular timetable and the
+// invigilator's availability.
+func (s *ExamService) Create(ctx context.Context, periodID string, req dto.ExamScheduleRequest) (*models.ExamSchedule, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid exam schedule payload")
+	}
+	period, err := s.period(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "date must use YYYY-MM-DD format")
+	}
+	if date.Before(dateOnly(period.StartDate)) || date.After(dateOnly(period.EndDate)) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "date must fall within the exam period")
+	}
+	if err := s.ensureReferences(ctx, []string{req.ClassID}, []string{req.SubjectID}, []string{req.InvigilatorID}); err != nil {
+		return nil, err
+	}
+
+	exam := models.ExamSchedule{
+		PeriodID:      period.ID,
+		ClassID:       req.ClassID,
+		SubjectID:     req.SubjectID,
+		ExamDate:      date,
+		StartSlot:     req.StartSlot,
+		EndSlot:       req.EndSlot,
+		Room:          strings.TrimSpace(req.Room),
+		InvigilatorID: req.InvigilatorID,
+	}
+	existing, err := s.store.List(ctx, models.ExamScheduleFilter{PeriodID: period.ID, ClassID: req.ClassID})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam schedules")
+	}
+	for _, other := range existing {
+		if other.SubjectID == req.SubjectID {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "class already has an exam for this subject in the period")
+		}
+	}
+
+	planner := newExamPlanner(s, period.TermID)
+	reason, err := planner.conflict(ctx, exam)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "exam conflict: "+examConflictMessages[reason])
+	}
+	batch := []models.ExamSchedule{exam}
+	if err := s.store.CreateBatch(ctx, batch); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create exam schedule")
+	}
+	return &batch[0], nil
+}
+
+// Delete removes a sitting from an exam period.
+func (s *ExamService) Delete(ctx context.Context, periodID, id string) error {
+	exam, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "exam schedule not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load exam schedule")
+	}
+	if exam.PeriodID != periodID {
+		return appErrors.Clone(appErrors.ErrNotFound, "exam schedule not found")
+	}
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "exam schedule not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete exam schedule")
+	}
+	return nil
+}
+
+// Generate lays out the pending exams of the requested classes over the period. It walks the
+// school days and sessions in order, giving every class at most one exam per session, and assigns
+// the first free room and the least loaded free invigilator. Exams already in the period are kept
+// and their class/subject pairs skipped; anything that does not fit is reported as unplaced.
+func (s *ExamService) Generate(ctx context.Context, periodID string, req dto.ExamGenerateRequest) (*dto.ExamGenerateResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid exam generation payload")
+	}
+	period, err := s.period(ctx, periodID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureReferences(ctx, req.ClassIDs, req.SubjectIDs, req.InvigilatorIDs); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.List(ctx, models.ExamScheduleFilter{PeriodID: period.ID})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list exam schedules")
+	}
+	scheduled := make(map[string]bool, len(existing))
+	load := make(map[string]int, len(req.InvigilatorIDs))
+	for _, exam := range existing {
+		scheduled[exam.ClassID+"|"+exam.SubjectID] = true
+		load[exam.InvigilatorID]++
+	}
+
+	classIDs := uniqueStrings(req.ClassIDs)
+	pending := make(map[string][]string, len(classIDs))
+	for _, classID := range classIDs {
+		subjects := uniqueStrings(req.SubjectIDs)
+		if len(subjects) == 0 {
+			if subjects, err = s.regularSubjects(ctx, classID, period.TermID); err != nil {
+				return nil, err
+			}
+		}
+		for _, subjectID := range subjects {
+			if !scheduled[classID+"|"+subjectID] {
+				pending[classID] = append(pending[classID], subjectID)
+			}
+		}
+	}
+
+	sessions := append([]dto.ExamSessionRequest(nil), req.Sessions...)
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartSlot < sessions[j].StartSlot })
+	days := normalizeDays(req.Days)
+	if len(days) == 0 {
+		days = []int{1, 2, 3, 4, 5}
+	}
+	allowed := make(map[int]bool, len(days))
+	for _, day := range days {
+		allowed[day] = true
+	}
+	rooms := uniqueStrings(req.Rooms)
+	invigilators := uniqueStrings(req.InvigilatorIDs)
+
+	planner := newExamPlanner(s, period.TermID)
+	lastReason := make(map[string]string, len(classIDs))
+	created := make([]models.ExamSchedule, 0)
+	for date := dateOnly(period.StartDate); !date.After(dateOnly(period.EndDate)); date = date.AddDate(0, 0, 1) {
+		if !allowed[isoWeekday(date)] {
+			continue
+		}
+		for _, session := range sessions {
+			for _, classID := range classIDs {
+				if len(pending[classID]) == 0 {
+					continue
+				}
+				candidate := models.ExamSchedule{
+					PeriodID:  period.ID,
+					ClassID:   classID,
+					SubjectID: pending[classID][0],
+					ExamDate:  date,
+					StartSlot: session.StartSlot,
+					EndSlot:   session.EndSlot,
+				}
+				placed, reason, err := planner.place(ctx, candidate, rooms, invigilators, load)
+				if err != nil {
+					return nil, err
+				}
+				if reason != "" {
+					lastReason[classID] = reason
+					continue
+				}
+				created = append(created, *placed)
+				load[placed.InvigilatorID]++
+				pending[classID] = pending[classID][1:]
+			}
+		}
+	}
+
+	unplaced := make([]dto.ExamUnplaced, 0)
+	for _, classID := range classIDs {
+		reason := lastReason[classID]
+		if reason == "" {
+			reason = examUnplacedNoSession
+		}
+		for _, subjectID := range pending[classID] {
+			unplaced = append(unplaced, dto.ExamUnplaced{ClassID: classID, SubjectID: subjectID, Reason: reason})
+		}
+	}
+	if len(created) > 0 {
+		if err := s.store.CreateBatch(ctx, created); err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save exam schedules")
+		}
+	}
+	s.logger.Info("exam timetable generated", zap.String("period_id", period.ID), zap.Int("created", len(created)), zap.Int("unplaced", len(unplaced)))
+	return &dto.ExamGenerateResponse{PeriodID: period.ID, Created: created, Unplaced: unplaced}, nil
+}
+
+func (s *ExamService) period(ctx context.Context, id string) (*models.ExamPeriod, error) {
+	period, err := s.store.FindPeriodByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "exam period not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load exam period")
+	}
+	return period, nil
+}
+
+func (s *ExamService) ensureReferences(ctx context.Context, classIDs, subjectIDs, teacherIDs []string) error {
+	for _, id := range classIDs {
+		if _, err := s.classes.FindByID(ctx, id); err != nil {
+			return referenceError(err, "class")
+		}
+	}
+	for _, id := range subjectIDs {
+		if _, err := s.subjects.FindByID(ctx, id); err != nil {
+			return referenceError(err, "subject")
+		}
+	}
+	for _, id := range teacherIDs {
+		if _, err := s.teachers.FindByID(ctx, id); err != nil {
+			return referenceError(err, "invigilator")
+		}
+	}
+	return nil
+}
+
+func referenceError(err error, entity string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return appErrors.Clone(appErrors.ErrNotFound, entity+" not found")
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load "+entity)
+}
+
+// regularSubjects returns the distinct subjects a class is taught during the term.
+func (s *ExamService) regularSubjects(ctx context.Context, classID, termID string) ([]string, error) {
+	schedules, err := s.regular.ListByClass(ctx, classID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class schedules")
+	}
+	subjects := make([]string, 0)
+	for _, schedule := range schedules {
+		if schedule.TermID == termID {
+			subjects = append(subjects, schedule.SubjectID)
+		}
+	}
+	subjects = uniqueStrings(subjects)
+	sort.Strings(subjects)
+	return subjects, nil
+}
+
+// examPlanner evaluates sittings against other exams, the regular timetable, invigilator
+// preferences and approved leave. Lookups are cached for the lifetime of one request.
+type examPlanner struct {
+	svc         *ExamService
+	termID      string
+	exams       map[string][]models.ExamSchedule
+	regular     map[slotKey][]models.Schedule
+	unavailable map[string]map[slotKey]bool
+	onLeave     map[string]map[string]bool
+}
+
+func newExamPlanner(svc *ExamService, termID string) *examPlanner {
+	return &examPlanner{
+		svc:         svc,
+		termID:      termID,
+		exams:       make(map[string][]models.ExamSchedule),
+		regular:     make(map[slotKey][]models.Schedule),
+		unavailable: make(map[string]map[slotKey]bool),
+		onLeave:     make(map[string]map[string]bool),
+	}
+}
+
+// place completes the candidate with the first conflict-free room and the least loaded
+// conflict-free invigilator. The returned reason explains the last blocker when nothing fits.
+func (p *examPlanner) place(ctx context.Context, candidate models.ExamSchedule, rooms, invigilators []string, load map[string]int) (*models.ExamSchedule, string, error) {
+	exams, err := p.examsOn(ctx, candidate.ExamDate)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, other := range exams {
+		if other.ClassID == candidate.ClassID && other.Overlaps(candidate.ExamDate, candidate.StartSlot, candidate.EndSlot) {
+			return nil, examConflictClass, nil
+		}
+	}
+
+	ordered := append([]string(nil), invigilators...)
+	sort.SliceStable(ordered, func(i, j int) bool { return load[ordered[i]] < load[ordered[j]] })
+
+	reason := ""
+	for _, room := range rooms {
+		if reason, err = p.roomConflict(ctx, candidate, room); err != nil {
+			return nil, "", err
+		}
+		if reason != "" {
+			continue
+		}
+		for _, invigilatorID := range ordered {
+			if reason, err = p.invigilatorConflict(ctx, candidate, invigilatorID); err != nil {
+				return nil, "", err
+			}
+			if reason != "" {
+				continue
+			}
+			placed := candidate
+			placed.Room = room
+			placed.InvigilatorID = invigilatorID
+			key := placed.ExamDate.Format("2006-01-02")
+			p.exams[key] = append(p.exams[key], placed)
+			return &placed, "", nil
+		}
+		// Every invigilator is busy in this session, so other rooms will not help.
+		return nil, reason, nil
+	}
+	return nil, reason, nil
+}
+
+// conflict returns the first reason the fully specified sitting cannot take place.
+func (p *examPlanner) conflict(ctx context.Context, exam models.ExamSchedule) (string, error) {
+	exams, err := p.examsOn(ctx, exam.ExamDate)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range exams {
+		if other.ClassID == exam.ClassID && other.Overlaps(exam.ExamDate, exam.StartSlot, exam.EndSlot) {
+			return examConflictClass, nil
+		}
+	}
+	if reason, err := p.roomConflict(ctx, exam, exam.Room); err != nil || reason != "" {
+		return reason, err
+	}
+	return p.invigilatorConflict(ctx, exam, exam.InvigilatorID)
+}
+
+func (p *examPlanner) roomConflict(ctx context.Context, exam models.ExamSchedule, room string) (string, error) {
+	exams, err := p.examsOn(ctx, exam.ExamDate)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range exams {
+		if strings.EqualFold(other.Room, room) && other.Overlaps(exam.ExamDate, exam.StartSlot, exam.EndSlot) {
+			return examConflictRoom, nil
+		}
+	}
+	for slot := exam.StartSlot; slot <= exam.EndSlot; slot++ {
+		lessons, err := p.regularAt(ctx, exam.ExamDate, slot)
+		if err != nil {
+			return "", err
+		}
+		for _, lesson := range lessons {
+			if lesson.ClassID != exam.ClassID && strings.EqualFold(lesson.Room, room) {
+				return examConflictRoomRegular, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (p *examPlanner) invigilatorConflict(ctx context.Context, exam models.ExamSchedule, teacherID string) (string, error) {
+	exams, err := p.examsOn(ctx, exam.ExamDate)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range exams {
+		if other.InvigilatorID == teacherID && other.Overlaps(exam.ExamDate, exam.StartSlot, exam.EndSlot) {
+			return examConflictInvigilator, nil
+		}
+	}
+	onLeave, err := p.teachersOnLeave(ctx, exam.ExamDate)
+	if err != nil {
+		return "", err
+	}
+	if onLeave[teacherID] {
+		return examConflictInvigilatorOnLeave, nil
+	}
+	unavailable, err := p.unavailableSlots(ctx, teacherID)
+	if err != nil {
+		return "", err
+	}
+	day := isoWeekday(exam.ExamDate)
+	for slot := exam.StartSlot; slot <= exam.EndSlot; slot++ {
+		if unavailable[slotKey{Day: day, Time: slot}] {
+			return examConflictInvigilatorUnavailable, nil
+		}
+		lessons, err := p.regularAt(ctx, exam.ExamDate, slot)
+		if err != nil {
+			return "", err
+		}
+		for _, lesson := range lessons {
+			// A teacher whose own class is sitting the exam is free from that lesson.
+			if lesson.TeacherID == teacherID && lesson.ClassID != exam.ClassID {
+				return examConflictInvigilatorRegular, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func (p *examPlanner) examsOn(ctx context.Context, date time.Time) ([]models.ExamSchedule, error) {
+	key := date.Format("2006-01-02")
+	if exams, ok := p.exams[key]; ok {
+		return exams, nil
+	}
+	day := dateOnly(date)
+	exams, err := p.svc.store.List(ctx, models.ExamScheduleFilter{Date: &day})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load exams for date")
+	}
+	if exams == nil {
+		exams = []models.ExamSchedule{}
+	}
+	p.exams[key] = exams
+	return exams, nil
+}
+
+// regularAt returns the regular lessons of the term held on the date's weekday at the slot.
+func (p *examPlanner) regularAt(ctx context.Context, date time.Time, slot int) ([]models.Schedule, error) {
+	key := slotKey{Day: isoWeekday(date), Time: slot}
+	if lessons, ok := p.regular[key]; ok {
+		return lessons, nil
+	}
+	lessons, err := p.svc.regular.FindConflicts(ctx, p.termID, dayIndexToName(key.Day), strconv.Itoa(slot))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load regular schedules")
+	}
+	p.regular[key] = lessons
+	return lessons, nil
+}
+
+func (p *examPlanner) unavailableSlots(ctx context.Context, teacherID string) (map[slotKey]bool, error) {
+	if blocked, ok := p.unavailable[teacherID]; ok {
+		return blocked, nil
+	}
+	blocked := make(map[slotKey]bool)
+	if p.svc.preferences != nil {
+		pref, err := p.svc.preferences.GetByTeacher(ctx, teacherID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher preferences")
+		}
+		if pref != nil && len(pref.Unavailable) > 0 {
+			var windows []models.TeacherUnavailableSlot
+			_ = json.Unmarshal(pref.Unavailable, &windows) // safe best-effort, as in the schedule generator
+			for _, window := range windows {
+				day := dayStringToIndex(window.DayOfWeek)
+				if day == 0 {
+					continue
+				}
+				for _, slot := range expandTimeRange(window.TimeRange) {
+					blocked[slotKey{Day: day, Time: slot}] = true
+				}
+			}
+		}
+	}
+	p.unavailable[teacherID] = blocked
+	return blocked, nil
+}
+
+func (p *examPlanner) teachersOnLeave(ctx context.Context, date time.Time) (map[string]bool, error) {
+	key := date.Format("2006-01-02")
+	if set, ok := p.onLeave[key]; ok {
+		return set, nil
+	}
+	set := make(map[string]bool)
+	if p.svc.leaves != nil {
+		ids, err := p.svc.leaves.TeachersOnLeave(ctx, dateOnly(date))
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teachers on leave")
+		}
+		for _, id := range ids {
+			set[id] = true
+		}
+	}
+	p.onLeave[key] = set
+	return set, nil
+}
+
+// isoWeekday maps a date to the 1 (Monday) .. 7 (Sunday) day index used by schedules.
+func isoWeekday(date time.Time) int {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(date.Weekday())
+}
+
+// uniqueStrings trims values and drops blanks and duplicates, keeping the first occurrence order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type examStoreStub struct {
+	periods map[string]*models.ExamPeriod
+	exams   []models.ExamSchedule
+}
+
+func (s *examStoreStub) ListPeriods(ctx context.Context, termID string) ([]models.ExamPeriod, error) {
+	var result []models.ExamPeriod
+	for _, period := range s.periods {
+		if period.TermID == termID {
+			result = append(result, *period)
+		}
+	}
+	return result, nil
+}
+
+func (s *examStoreStub) FindPeriodByID(ctx context.Context, id string) (*models.ExamPeriod, error) {
+	if period, ok := s.periods[id]; ok {
+		return period, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *examStoreStub) CreatePeriod(ctx context.Context, period *models.ExamPeriod) error {
+	period.ID = "period-" + strconv.Itoa(len(s.periods)+1)
+	s.periods[period.ID] = period
+	return nil
+}
+
+func (s *examStoreStub) DeletePeriod(ctx context.Context, id string) error {
+	delete(s.periods, id)
+	return nil
+}
+
+func (s *examStoreStub) List(ctx context.Context, filter models.ExamScheduleFilter) ([]models.ExamSchedule, error) {
+	var result []models.ExamSchedule
+	for _, exam := range s.exams {
+		if filter.PeriodID != "" && exam.PeriodID != filter.PeriodID {
+			continue
+		}
+		if filter.ClassID != "" && exam.ClassID != filter.ClassID {
+			continue
+		}
+		if filter.Date != nil && exam.ExamDate.Format("2006-01-02") != filter.Date.Format("2006-01-02") {
+			continue
+		}
+		result = append(result, exam)
+	}
+	return result, nil
+}
+
+func (s *examStoreStub) FindByID(ctx context.Context, id string) (*models.ExamSchedule, error) {
+	for i := range s.exams {
+		if s.exams[i].ID == id {
+			return &s.exams[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *examStoreStub) CreateBatch(ctx context.Context, exams []models.ExamSchedule) error {
+	for i := range exams {
+		exams[i].ID = "exam-" + strconv.Itoa(len(s.exams)+1)
+		s.exams = append(s.exams, exams[i])
+	}
+	return nil
+}
+
+func (s *examStoreStub) Delete(ctx context.Context, id string) error {
+	for i := range s.exams {
+		if s.exams[i].ID == id {
+			s.exams = append(s.exams[:i], s.exams[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+type examTeacherStub struct{}
+
+func (examTeacherStub) FindByID(ctx context.Context, id string) (*models.Teacher, error) {
+	return &models.Teacher{ID: id, FullName: "Teacher " + id}, nil
+}
+
+type examTermStub struct{ term *models.Term }
+
+func (s examTermStub) FindByID(ctx context.Context, id string) (*models.Term, error) {
+	return s.term, nil
+}
+
+type examRegularStub struct {
+	lessons []models.Schedule
+}
+
+func (s examRegularStub) FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error) {
+	var result []models.Schedule
+	for _, lesson := range s.lessons {
+		if lesson.TermID == termID && lesson.DayOfWeek == dayOfWeek && lesson.TimeSlot == timeSlot {
+			result = append(result, lesson)
+		}
+	}
+	return result, nil
+}
+
+func (s examRegularStub) ListByClass(ctx context.Context, classID string) ([]models.Schedule, error) {
+	var result []models.Schedule
+	for _, lesson := range s.lessons {
+		if lesson.ClassID == classID {
+			result = append(result, lesson)
+		}
+	}
+	return result, nil
+}
+
+type examPreferenceStub struct {
+	unavailable map[string]string
+}
+
+func (s examPreferenceStub) GetByTeacher(ctx context.Context, teacherID string) (*models.TeacherPreference, error) {
+	raw, ok := s.unavailable[teacherID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &models.TeacherPreference{TeacherID: teacherID, Unavailable: types.JSONText(raw)}, nil
+}
+
+// newExamFixture builds a service around an exam period spanning Monday 2 and Tuesday 3 March 2026.
+func newExamFixture(lessons []models.Schedule, unavailable map[string]string) (*ExamService, *examStoreStub) {
+	store := &examStoreStub{periods: map[string]*models.ExamPeriod{
+		"period-1": {
+			ID:        "period-1",
+			TermID:    "term-1",
+			Name:      "Midterm",
+			StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+	}}
+	svc := NewExamService(ExamServiceParams{
+		Store:       store,
+		Terms:       termLookupStub{},
+		Classes:     classLookupStub{},
+		Subjects:    subjectLookupStub{subjects: map[string]struct{}{"math": {}, "bio": {}}},
+		Teachers:    examTeacherStub{},
+		Regular:     examRegularStub{lessons: lessons},
+		Preferences: examPreferenceStub{unavailable: unavailable},
+	})
+	return svc, store
+}
+
+func TestExamServiceGenerateAvoidsRegularLessonsAndClashes(t *testing.T) {
+	lessons := []models.Schedule{
+		// Another class has a lesson with t1 in R1 on Monday morning.
+		{TermID: "term-1", ClassID: "c3", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R1"},
+	}
+	svc, store := newExamFixture(lessons, nil)
+
+	result, err := svc.Generate(context.Background(), "period-1", dto.ExamGenerateRequest{
+		ClassIDs:       []string{"c1", "c2"},
+		SubjectIDs:     []string{"math", "bio"},
+		Sessions:       []dto.ExamSessionRequest{{StartSlot: 1, EndSlot: 2}},
+		Rooms:          []string{"R1", "R2"},
+		InvigilatorIDs: []string{"t1", "t2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 3)
+	assert.Len(t, store.exams, 3)
+
+	monday := result.Created[0]
+	assert.Equal(t, "c1", monday.ClassID)
+	assert.Equal(t, "R2", monday.Room)
+	assert.Equal(t, "t2", monday.InvigilatorID)
+	for _, exam := range result.Created[1:] {
+		assert.Equal(t, "2026-03-03", exam.ExamDate.Format("2006-01-02"))
+	}
+	assert.NotEqual(t, result.Created[1].Room, result.Created[2].Room)
+	assert.NotEqual(t, result.Created[1].InvigilatorID, result.Created[2].InvigilatorID)
+
+	require.Len(t, result.Unplaced, 1)
+	assert.Equal(t, dto.ExamUnplaced{ClassID: "c2", SubjectID: "bio", Reason: examConflictRoom}, result.Unplaced[0])
+}
+
+func TestExamServiceGenerateDefaultsToRegularSubjectsAndSkipsScheduled(t *testing.T) {
+	lessons := []models.Schedule{
+		{TermID: "term-1", ClassID: "c1", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R1"},
+		{TermID: "term-1", ClassID: "c1", SubjectID: "bio", TeacherID: "t2", DayOfWeek: "TUESDAY", TimeSlot: "1", Room: "R1"},
+		{TermID: "term-0", ClassID: "c1", SubjectID: "chem", TeacherID: "t2", DayOfWeek: "TUESDAY", TimeSlot: "2", Room: "R1"},
+	}
+	svc, store := newExamFixture(lessons, nil)
+	store.exams = []models.ExamSchedule{{ID: "exam-0", PeriodID: "period-1", ClassID: "c1", SubjectID: "bio",
+		ExamDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), StartSlot: 3, EndSlot: 4, Room: "R9", InvigilatorID: "t9"}}
+
+	result, err := svc.Generate(context.Background(), "period-1", dto.ExamGenerateRequest{
+		ClassIDs:       []string{"c1"},
+		Sessions:       []dto.ExamSessionRequest{{StartSlot: 1, EndSlot: 2}},
+		Rooms:          []string{"R1"},
+		InvigilatorIDs: []string{"t1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 1)
+	// The class's own lesson in R1 with t1 does not block its exam.
+	assert.Equal(t, "math", result.Created[0].SubjectID)
+	assert.Equal(t, "2026-03-02", result.Created[0].ExamDate.Format("2006-01-02"))
+	assert.Empty(t, result.Unplaced)
+}
+
+func TestExamServiceCreateRejectsConflicts(t *testing.T) {
+	lessons := []models.Schedule{
+		{TermID: "term-1", ClassID: "c3", SubjectID: "math", TeacherID: "t2", DayOfWeek: "MONDAY", TimeSlot: "2", Room: "R5"},
+	}
+	svc, _ := newExamFixture(lessons, map[string]string{"t3": `[{"day_of_week":"MONDAY","time_range":"1-2"}]`})
+	ctx := context.Background()
+	base := dto.ExamScheduleRequest{ClassID: "c1", SubjectID: "math", Date: "2026-03-02", StartSlot: 1, EndSlot: 2, Room: "R1", InvigilatorID: "t1"}
+
+	created, err := svc.Create(ctx, "period-1", base)
+	require.NoError(t, err)
+	assert.Equal(t, "R1", created.Room)
+
+	cases := []struct {
+		name string
+		edit func(req *dto.ExamScheduleRequest)
+		code string
+	}{
+		{"duplicate subject", func(req *dto.ExamScheduleRequest) { req.Room = "R2"; req.InvigilatorID = "t4" }, appErrors.ErrConflict.Code},
+		{"room in use", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.InvigilatorID = "t4" }, appErrors.ErrConflict.Code},
+		{"invigilator in use", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.Room = "R2" }, appErrors.ErrConflict.Code},
+		{"regular lesson room", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.Room = "R5"; req.InvigilatorID = "t4" }, appErrors.ErrConflict.Code},
+		{"regular lesson invigilator", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.Room = "R2"; req.InvigilatorID = "t2" }, appErrors.ErrConflict.Code},
+		{"invigilator unavailable", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.Room = "R2"; req.InvigilatorID = "t3" }, appErrors.ErrConflict.Code},
+		{"outside period", func(req *dto.ExamScheduleRequest) { req.ClassID = "c2"; req.Date = "2026-03-09" }, appErrors.ErrValidation.Code},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := base
+			tc.edit(&req)
+			_, err := svc.Create(ctx, "period-1", req)
+			require.Error(t, err)
+			assert.Equal(t, tc.code, appErrors.FromError(err).Code)
+		})
+	}
+
+	other := base
+	other.ClassID, other.Room, other.InvigilatorID, other.StartSlot, other.EndSlot = "c2", "R2", "t3", 3, 4
+	_, err = svc.Create(ctx, "period-1", other)
+	require.NoError(t, err)
+}
+
+type examLeaveStub map[string][]string
+
+func (s examLeaveStub) TeachersOnLeave(ctx context.Context, date time.Time) ([]string, error) {
+	return s[date.Format("2006-01-02")], nil
+}
+
+func TestExamServiceGenerateSkipsInvigilatorsOnLeave(t *testing.T) {
+	svc, _ := newExamFixture(nil, nil)
+	svc.leaves = examLeaveStub{"2026-03-02": {"t1"}}
+
+	result, err := svc.Generate(context.Background(), "period-1", dto.ExamGenerateRequest{
+		ClassIDs:       []string{"c1"},
+		SubjectIDs:     []string{"math"},
+		Sessions:       []dto.ExamSessionRequest{{StartSlot: 1, EndSlot: 2}},
+		Rooms:          []string{"R1"},
+		InvigilatorIDs: []string{"t1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 1)
+	assert.Equal(t, "2026-03-03", result.Created[0].ExamDate.Format("2006-01-02"))
+
+	_, err = svc.Create(context.Background(), "period-1", dto.ExamScheduleRequest{
+		ClassID: "c2", SubjectID: "math", Date: "2026-03-02", StartSlot: 1, EndSlot: 2, Room: "R2", InvigilatorID: "t1",
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestExamServiceCreatePeriodRejectsOverlap(t *testing.T) {
+	svc, _ := newExamFixture(nil, nil)
+	svc.terms = examTermStub{term: &models.Term{ID: "term-1",
+		StartDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 6, 26, 0, 0, 0, 0, time.UTC)}}
+
+	_, err := svc.CreatePeriod(context.Background(), dto.ExamPeriodRequest{TermID: "term-1", Name: "Retake", StartDate: "2026-03-03", EndDate: "2026-03-05"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.CreatePeriod(context.Background(), dto.ExamPeriodRequest{TermID: "term-1", Name: "Late", StartDate: "2026-06-20", EndDate: "2026-07-03"})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	period, err := svc.CreatePeriod(context.Background(), dto.ExamPeriodRequest{TermID: "term-1", Name: "Final", StartDate: "2026-06-08", EndDate: "2026-06-12"})
+	require.NoError(t, err)
+	assert.Equal(t, "Final", period.Name)
+}
+
+func TestExamTimeLabelSpansSlots(t *testing.T) {
+	labels := map[int]string{1: "07:00–07:45", 2: "07:45–08:30", 3: "Slot 3"}
+	assert.Equal(t, "07:00–07:45", examTimeLabel(labels, 1, 1))
+	assert.Equal(t, "07:00–08:30", examTimeLabel(labels, 1, 2))
+	assert.Equal(t, "07:45–08:30, Slot 3", examTimeLabel(labels, 2, 3))
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+// reportColumn is one column a report dataset exposes. Dataset rows are keyed by Key; Label is the
+// header used when no export template renames the column.
+type reportColumn struct {
+	Key   string
+	Label string
+}
+
+// reportColumns lists, in default order, the columns of every report type that can be queued.
+var reportColumns = map[models.ReportType][]reportColumn{
+	models.ReportTypeAttendance: {
+		{Key: "termId", Label: "Term ID"},
+		{Key: "classId", Label: "Class ID"},
+		{Key: "present", Label: "Present"},
+		{Key: "absent", Label: "Absent"},
+		{Key: "percentage", Label: "Attendance (%)"},
+		{Key: "updatedAt", Label: "Updated At"},
+	},
+	models.ReportTypeGrades: {
+		{Key: "termId", Label: "Term ID"},
+		{Key: "classId", Label: "Class ID"},
+		{Key: "subjectId", Label: "Subject ID"},
+		{Key: "averageScore", Label: "Average Score"},
+		{Key: "medianScore", Label: "Median Score"},
+		{Key: "updatedAt", Label: "Updated At"},
+	},
+	models.ReportTypeBehavior: {
+		{Key: "termId", Label: "Term ID"},
+		{Key: "studentId", Label: "Student ID"},
+		{Key: "positivePoints", Label: "Positive Points"},
+		{Key: "negativePoints", Label: "Negative Points"},
+		{Key: "balance", Label: "Balance"},
+		{Key: "updatedAt", Label: "Updated At"},
+	},
+	models.ReportTypeSummary: {
+		{Key: "metric", Label: "Metric"},
+		{Key: "termId", Label: "Term ID"},
+		{Key: "value", Label: "Value"},
+		{Key: "notes", Label: "Notes"},
+	},
+}
+
+// exportLayout maps dataset rows onto the exported headers.
+type exportLayout struct {
+	columns []reportColumn
+	groupBy string
+}
+
+func defaultExportLayout(reportType models.ReportType) exportLayout {
+	return exportLayout{columns: reportColumns[reportType]}
+}
+
+func templateExportLayout(template *models.ExportTemplate) (exportLayout, error) {
+	var columns []models.ExportTemplateColumn
+	if err := json.Unmarshal(template.Columns, &columns); err != nil {
+		return exportLayout{}, fmt.Errorf("decode export template columns: %w", err)
+	}
+	groupBy := ""
+	if template.GroupBy != nil {
+		groupBy = *template.GroupBy
+	}
+	return resolveExportLayout(template.ReportType, columns, groupBy)
+}
+
+// resolveExportLayout checks template columns against the report type's catalogue and fills in
+// default labels. Keys and resulting headers must be unique because rows are keyed by header.
+func resolveExportLayout(reportType models.ReportType, columns []models.ExportTemplateColumn, groupBy string) (exportLayout, error) {
+	catalogue, ok := reportColumns[reportType]
+	if !ok {
+		return exportLayout{}, fmt.Errorf("report type %q has no exportable columns", reportType)
+	}
+	if len(columns) == 0 {
+		return exportLayout{}, fmt.Errorf("at least one column is required")
+	}
+	labels := make(map[string]string, len(catalogue))
+	for _, column := range catalogue {
+		labels[column.Key] = column.Label
+	}
+	layout := exportLayout{columns: make([]reportColumn, 0, len(columns))}
+	seenKeys := make(map[string]bool, len(columns))
+	seenLabels := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		defaultLabel, ok := labels[column.Key]
+		if !ok {
+			return exportLayout{}, fmt.Errorf("unknown column %q for %s reports", column.Key, reportType)
+		}
+		if seenKeys[column.Key] {
+			return exportLayout{}, fmt.Errorf("column %q is selected more than once", column.Key)
+		}
+		label := strings.TrimSpace(column.Label)
+		if label == "" {
+			label = defaultLabel
+		}
+		if seenLabels[label] {
+			return exportLayout{}, fmt.Errorf("label %q is used by more than one column", label)
+		}
+		seenKeys[column.Key] = true
+		seenLabels[label] = true
+		layout.columns = append(layout.columns, reportColumn{Key: column.Key, Label: label})
+	}
+	if groupBy != "" {
+		if _, ok := labels[groupBy]; !ok {
+			return exportLayout{}, fmt.Errorf("unknown group by column %q for %s reports", groupBy, reportType)
+		}
+		layout.groupBy = groupBy
+	}
+	return layout, nil
+}
+
+// apply builds the dataset handed to the renderers. Grouped rows are ordered by the group column so
+// every group is contiguous; rows keep their original order within a group.
+func (l exportLayout) apply(rows []map[string]string) export.Dataset {
+	if l.groupBy != "" {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i][l.groupBy] < rows[j][l.groupBy]
+		})
+	}
+	headers := make([]string, len(l.columns))
+	for i, column := range l.columns {
+		headers[i] = column.Label
+	}
+	dataRows := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		out := make(map[string]string, len(l.columns))
+		for _, column := range l.columns {
+			out[column.Label] = row[column.Key]
+		}
+		dataRows = append(dataRows, out)
+	}
+	return export.Dataset{Headers: headers, Rows: dataRows}
+}
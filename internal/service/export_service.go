@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -10,16 +12,11 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	"github.com/noah-isme/sma-adp-api/pkg/export"
 	"github.com/noah-isme/sma-adp-api/pkg/storage"
 )
 
-type analyticsRepository interface {
-	AttendanceSummary(ctx context.Context, filter models.AnalyticsAttendanceFilter) ([]models.AnalyticsAttendanceSummary, error)
-	GradeSummary(ctx context.Context, filter models.AnalyticsGradeFilter) ([]models.AnalyticsGradeSummary, error)
-	BehaviorSummary(ctx context.Context, filter models.AnalyticsBehaviorFilter) ([]models.AnalyticsBehaviorSummary, error)
-}
-
 type fileStorage interface {
 	Save(filename string, data []byte) (string, error)
 	Open(filename string) (*os.File, error)
@@ -44,13 +41,35 @@ type ExportResult struct {
 
 // ExportService builds report datasets and persists rendered files.
 type ExportService struct {
-	analytics analyticsRepository
+	analytics ports.AnalyticsRepository
 	storage   fileStorage
 	csv       csvRenderer
 	pdf       pdfRenderer
 	signer    *storage.SignedURLSigner
 	logger    *zap.Logger
 	cfg       ExportConfig
+	templates exportTemplateReader
+
+	research   researchExportSource
+	pseudonyms researchPseudonymizer
+
+	ministry ministryExportSource
+	xlsx     xlsxRenderer
+}
+
+type exportTemplateReader interface {
+	FindByReportType(ctx context.Context, reportType models.ReportType) (*models.ExportTemplate, error)
+}
+
+// ExportServiceOption configures optional ExportService dependencies.
+type ExportServiceOption func(*ExportService)
+
+// WithExportTemplates lays report datasets out according to the export template stored for their
+// report type.
+func WithExportTemplates(templates exportTemplateReader) ExportServiceOption {
+	return func(s *ExportService) {
+		s.templates = templates
+	}
 }
 
 type csvRenderer interface {
@@ -62,7 +81,7 @@ type pdfRenderer interface {
 }
 
 // NewExportService constructs an ExportService.
-func NewExportService(analytics analyticsRepository, storage fileStorage, signer *storage.SignedURLSigner, cfg ExportConfig, logger *zap.Logger, csv csvRenderer, pdf pdfRenderer) *ExportService {
+func NewExportService(analytics ports.AnalyticsRepository, storage fileStorage, signer *storage.SignedURLSigner, cfg ExportConfig, logger *zap.Logger, csv csvRenderer, pdf pdfRenderer, opts ...ExportServiceOption) *ExportService {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -75,7 +94,7 @@ func NewExportService(analytics analyticsRepository, storage fileStorage, signer
 	if pdf == nil {
 		pdf = export.NewPDFExporter()
 	}
-	return &ExportService{
+	svc := &ExportService{
 		analytics: analytics,
 		storage:   storage,
 		csv:       csv,
@@ -84,6 +103,10 @@ func NewExportService(analytics analyticsRepository, storage fileStorage, signer
 		logger:    logger,
 		cfg:       cfg,
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
 }
 
 // Generate builds dataset according to job definition and stores the rendered export.
@@ -91,6 +114,20 @@ func (s *ExportService) Generate(ctx context.Context, job *models.ReportJob) (*E
 	if job == nil {
 		return nil, fmt.Errorf("job nil")
 	}
+	if job.Type == models.ReportTypeResearch {
+		payload, err := s.buildResearchArchive(ctx, job.Params)
+		if err != nil {
+			return nil, err
+		}
+		return s.Store(job.ID, s.buildFilename(job), models.ReportFormatZIP, payload)
+	}
+	if isMinistryReportType(job.Type) {
+		payload, err := s.buildMinistryExport(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		return s.Store(job.ID, s.buildFilename(job), job.Params.Format, payload)
+	}
 	dataset, title, err := s.buildDataset(ctx, job)
 	if err != nil {
 		return nil, err
@@ -109,13 +146,17 @@ func (s *ExportService) Generate(ctx context.Context, job *models.ReportJob) (*E
 		return nil, err
 	}
 
-	filename := s.buildFilename(job)
+	return s.Store(job.ID, s.buildFilename(job), job.Params.Format, payload)
+}
+
+// Store persists an already rendered payload and signs a download URL bound to jobID.
+func (s *ExportService) Store(jobID, filename string, format models.ReportFormat, payload []byte) (*ExportResult, error) {
 	relPath, err := s.storage.Save(filename, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	token, expiresAt, err := s.signer.Generate(job.ID, relPath)
+	token, expiresAt, err := s.signer.Generate(jobID, relPath)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +170,7 @@ func (s *ExportService) Generate(ctx context.Context, job *models.ReportJob) (*E
 		RelativePath: relPath,
 		Token:        token,
 		URL:          signedURL,
-		Format:       job.Params.Format,
+		Format:       format,
 		ExpiresAt:    expiresAt,
 	}, nil
 }
@@ -177,77 +218,104 @@ func sanitizeFilename(raw string) string {
 }
 
 func (s *ExportService) buildDataset(ctx context.Context, job *models.ReportJob) (export.Dataset, string, error) {
+	var (
+		rows  []map[string]string
+		title string
+		err   error
+	)
 	switch job.Type {
 	case models.ReportTypeAttendance:
-		return s.buildAttendanceDataset(ctx, job.Params)
+		rows, title, err = s.buildAttendanceRows(ctx, job.Params)
 	case models.ReportTypeGrades:
-		return s.buildGradeDataset(ctx, job.Params)
+		rows, title, err = s.buildGradeRows(ctx, job.Params)
 	case models.ReportTypeBehavior:
-		return s.buildBehaviorDataset(ctx, job.Params)
+		rows, title, err = s.buildBehaviorRows(ctx, job.Params)
 	case models.ReportTypeSummary:
-		return s.buildSummaryDataset(ctx, job.Params)
+		rows, title, err = s.buildSummaryRows(ctx, job.Params)
 	default:
 		return export.Dataset{}, "", fmt.Errorf("unsupported report type %s", job.Type)
 	}
+	if err != nil {
+		return export.Dataset{}, "", err
+	}
+	layout, err := s.layout(ctx, job.Type)
+	if err != nil {
+		return export.Dataset{}, "", err
+	}
+	return layout.apply(rows), title, nil
 }
 
-func (s *ExportService) buildAttendanceDataset(ctx context.Context, params models.ReportJobParams) (export.Dataset, string, error) {
+// layout resolves the columns of a report type's exports: the stored export template when one
+// exists, otherwise every column of the type in catalogue order.
+func (s *ExportService) layout(ctx context.Context, reportType models.ReportType) (exportLayout, error) {
+	if s.templates == nil {
+		return defaultExportLayout(reportType), nil
+	}
+	template, err := s.templates.FindByReportType(ctx, reportType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return defaultExportLayout(reportType), nil
+		}
+		return exportLayout{}, fmt.Errorf("load export template: %w", err)
+	}
+	layout, err := templateExportLayout(template)
+	if err != nil {
+		// A template that no longer matches the catalogue must not block report generation.
+		s.logger.Warn("ignoring invalid export template", zap.String("report_type", string(reportType)), zap.Error(err))
+		return defaultExportLayout(reportType), nil
+	}
+	return layout, nil
+}
+
+func (s *ExportService) buildAttendanceRows(ctx context.Context, params models.ReportJobParams) ([]map[string]string, string, error) {
 	filter := models.AnalyticsAttendanceFilter{
 		TermID:  params.TermID,
 		ClassID: deref(params.ClassID),
 	}
 	rows, err := s.analytics.AttendanceSummary(ctx, filter)
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 	dataRows := make([]map[string]string, 0, len(rows))
 	for _, row := range rows {
 		dataRows = append(dataRows, map[string]string{
-			"Term ID":        row.TermID,
-			"Class ID":       row.ClassID,
-			"Present":        fmt.Sprintf("%d", row.PresentCount),
-			"Absent":         fmt.Sprintf("%d", row.AbsentCount),
-			"Attendance (%)": fmt.Sprintf("%.2f", row.Percentage),
-			"Updated At":     formatReportTime(row.UpdatedAt),
+			"termId":     row.TermID,
+			"classId":    row.ClassID,
+			"present":    fmt.Sprintf("%d", row.PresentCount),
+			"absent":     fmt.Sprintf("%d", row.AbsentCount),
+			"percentage": fmt.Sprintf("%.2f", row.Percentage),
+			"updatedAt":  formatReportTime(row.UpdatedAt),
 		})
 	}
-	dataset := export.Dataset{
-		Headers: []string{"Term ID", "Class ID", "Present", "Absent", "Attendance (%)", "Updated At"},
-		Rows:    dataRows,
-	}
 	title := fmt.Sprintf("Attendance Report %s", params.TermID)
-	return dataset, title, nil
+	return dataRows, title, nil
 }
 
-func (s *ExportService) buildGradeDataset(ctx context.Context, params models.ReportJobParams) (export.Dataset, string, error) {
+func (s *ExportService) buildGradeRows(ctx context.Context, params models.ReportJobParams) ([]map[string]string, string, error) {
 	filter := models.AnalyticsGradeFilter{
 		TermID:  params.TermID,
 		ClassID: deref(params.ClassID),
 	}
 	summaries, err := s.analytics.GradeSummary(ctx, filter)
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 	dataRows := make([]map[string]string, 0, len(summaries))
 	for _, row := range summaries {
 		dataRows = append(dataRows, map[string]string{
-			"Term ID":       row.TermID,
-			"Class ID":      row.ClassID,
-			"Subject ID":    row.SubjectID,
-			"Average Score": fmt.Sprintf("%.2f", row.AverageScore),
-			"Median Score":  fmt.Sprintf("%.2f", row.MedianScore),
-			"Updated At":    formatReportTime(row.UpdatedAt),
+			"termId":       row.TermID,
+			"classId":      row.ClassID,
+			"subjectId":    row.SubjectID,
+			"averageScore": fmt.Sprintf("%.2f", row.AverageScore),
+			"medianScore":  fmt.Sprintf("%.2f", row.MedianScore),
+			"updatedAt":    formatReportTime(row.UpdatedAt),
 		})
 	}
-	dataset := export.Dataset{
-		Headers: []string{"Term ID", "Class ID", "Subject ID", "Average Score", "Median Score", "Updated At"},
-		Rows:    dataRows,
-	}
 	title := fmt.Sprintf("Grade Report %s", params.TermID)
-	return dataset, title, nil
+	return dataRows, title, nil
 }
 
-func (s *ExportService) buildBehaviorDataset(ctx context.Context, params models.ReportJobParams) (export.Dataset, string, error) {
+func (s *ExportService) buildBehaviorRows(ctx context.Context, params models.ReportJobParams) ([]map[string]string, string, error) {
 	filter := models.AnalyticsBehaviorFilter{
 		TermID:   params.TermID,
 		ClassID:  deref(params.ClassID),
@@ -256,48 +324,44 @@ func (s *ExportService) buildBehaviorDataset(ctx context.Context, params models.
 	}
 	summaries, err := s.analytics.BehaviorSummary(ctx, filter)
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 	dataRows := make([]map[string]string, 0, len(summaries))
 	for _, row := range summaries {
 		dataRows = append(dataRows, map[string]string{
-			"Term ID":         row.TermID,
-			"Student ID":      row.StudentID,
-			"Positive Points": fmt.Sprintf("%d", row.TotalPositive),
-			"Negative Points": fmt.Sprintf("%d", row.TotalNegative),
-			"Balance":         fmt.Sprintf("%d", row.Balance),
-			"Updated At":      formatReportTime(row.UpdatedAt),
+			"termId":         row.TermID,
+			"studentId":      row.StudentID,
+			"positivePoints": fmt.Sprintf("%d", row.TotalPositive),
+			"negativePoints": fmt.Sprintf("%d", row.TotalNegative),
+			"balance":        fmt.Sprintf("%d", row.Balance),
+			"updatedAt":      formatReportTime(row.UpdatedAt),
 		})
 	}
-	dataset := export.Dataset{
-		Headers: []string{"Term ID", "Student ID", "Positive Points", "Negative Points", "Balance", "Updated At"},
-		Rows:    dataRows,
-	}
 	title := fmt.Sprintf("Behavior Report %s", params.TermID)
-	return dataset, title, nil
+	return dataRows, title, nil
 }
 
-func (s *ExportService) buildSummaryDataset(ctx context.Context, params models.ReportJobParams) (export.Dataset, string, error) {
+func (s *ExportService) buildSummaryRows(ctx context.Context, params models.ReportJobParams) ([]map[string]string, string, error) {
 	attendanceRows, err := s.analytics.AttendanceSummary(ctx, models.AnalyticsAttendanceFilter{
 		TermID:  params.TermID,
 		ClassID: deref(params.ClassID),
 	})
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 	gradeRows, err := s.analytics.GradeSummary(ctx, models.AnalyticsGradeFilter{
 		TermID:  params.TermID,
 		ClassID: deref(params.ClassID),
 	})
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 	behaviorRows, err := s.analytics.BehaviorSummary(ctx, models.AnalyticsBehaviorFilter{
 		TermID:  params.TermID,
 		ClassID: deref(params.ClassID),
 	})
 	if err != nil {
-		return export.Dataset{}, "", err
+		return nil, "", err
 	}
 
 	avgAttendance := averageAttendance(attendanceRows)
@@ -305,19 +369,14 @@ func (s *ExportService) buildSummaryDataset(ctx context.Context, params models.R
 	avgGrade := averageGrade(gradeRows)
 	behaviorBalance := aggregateBehaviorBalance(behaviorRows)
 
-	rows := []map[string]string{
-		{"Metric": "Average Attendance", "Term ID": params.TermID, "Value": fmt.Sprintf("%.2f", avgAttendance), "Notes": ""},
-		{"Metric": "Best Attendance Class", "Term ID": params.TermID, "Value": bestClass, "Notes": ""},
-		{"Metric": "Average Grade", "Term ID": params.TermID, "Value": fmt.Sprintf("%.2f", avgGrade), "Notes": ""},
-		{"Metric": "Behavior Balance", "Term ID": params.TermID, "Value": fmt.Sprintf("%d", behaviorBalance), "Notes": ""},
-	}
-
-	dataset := export.Dataset{
-		Headers: []string{"Metric", "Term ID", "Value", "Notes"},
-		Rows:    rows,
+	dataRows := []map[string]string{
+		{"metric": "Average Attendance", "termId": params.TermID, "value": fmt.Sprintf("%.2f", avgAttendance), "notes": ""},
+		{"metric": "Best Attendance Class", "termId": params.TermID, "value": bestClass, "notes": ""},
+		{"metric": "Average Grade", "termId": params.TermID, "value": fmt.Sprintf("%.2f", avgGrade), "notes": ""},
+		{"metric": "Behavior Balance", "termId": params.TermID, "value": fmt.Sprintf("%d", behaviorBalance), "notes": ""},
 	}
 	title := fmt.Sprintf("Summary Report %s", params.TermID)
-	return dataset, title, nil
+	return dataRows, title, nil
 }
 
 func deref(ptr *string) string {
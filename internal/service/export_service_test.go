@@ -1,12 +1,17 @@
 package service
 
 import (
+	"archive/zip"
 	"context"
+	"database/sql"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
@@ -35,6 +40,17 @@ func (analyticsStub) BehaviorSummary(ctx context.Context, filter models.Analytic
 	}, nil
 }
 
+type exportTemplateReaderStub struct {
+	template *models.ExportTemplate
+}
+
+func (s exportTemplateReaderStub) FindByReportType(ctx context.Context, reportType models.ReportType) (*models.ExportTemplate, error) {
+	if s.template == nil || s.template.ReportType != reportType {
+		return nil, sql.ErrNoRows
+	}
+	return s.template, nil
+}
+
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
@@ -86,3 +102,177 @@ func TestExportServiceGeneratePDF(t *testing.T) {
 	require.NoError(t, err)
 	require.Greater(t, info.Size(), int64(0))
 }
+
+func TestExportServiceGenerateAppliesTemplate(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	groupBy := "subjectId"
+	templates := exportTemplateReaderStub{template: &models.ExportTemplate{
+		ReportType: models.ReportTypeGrades,
+		Columns:    types.JSONText(`[{"key":"subjectId","label":"Mata Pelajaran"},{"key":"averageScore"}]`),
+		GroupBy:    &groupBy,
+	}}
+	svc := NewExportService(analyticsStub{}, store, storage.NewSignedURLSigner("secret", time.Hour), ExportConfig{}, zap.NewNop(), nil, nil, WithExportTemplates(templates))
+
+	result, err := svc.Generate(context.Background(), &models.ReportJob{
+		ID:     "job-3",
+		Type:   models.ReportTypeGrades,
+		Params: models.ReportJobParams{TermID: "term-1", Format: models.ReportFormatCSV},
+	})
+	require.NoError(t, err)
+	content, err := os.ReadFile(store.Path(result.RelativePath))
+	require.NoError(t, err)
+	assert.Equal(t, "Mata Pelajaran,Average Score\nmath,85.50\n", string(content))
+
+	// Types without a template keep the default layout.
+	dataset, _, err := svc.buildDataset(context.Background(), &models.ReportJob{Type: models.ReportTypeBehavior})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Term ID", "Student ID", "Positive Points", "Negative Points", "Balance", "Updated At"}, dataset.Headers)
+	assert.Equal(t, "4", dataset.Rows[0]["Balance"])
+}
+
+func TestExportLayoutGroupsRows(t *testing.T) {
+	layout, err := resolveExportLayout(models.ReportTypeAttendance, []models.ExportTemplateColumn{{Key: "classId"}, {Key: "percentage", Label: "Rate"}}, "classId")
+	require.NoError(t, err)
+	dataset := layout.apply([]map[string]string{
+		{"classId": "b", "percentage": "1"},
+		{"classId": "a", "percentage": "2"},
+		{"classId": "b", "percentage": "3"},
+	})
+	assert.Equal(t, []string{"Class ID", "Rate"}, dataset.Headers)
+	assert.Equal(t, []map[string]string{
+		{"Class ID": "a", "Rate": "2"},
+		{"Class ID": "b", "Rate": "1"},
+		{"Class ID": "b", "Rate": "3"},
+	}, dataset.Rows)
+
+	_, err = resolveExportLayout(models.ReportTypeAttendance, []models.ExportTemplateColumn{{Key: "classId", Label: "Present"}, {Key: "present"}}, "")
+	assert.Error(t, err)
+	_, err = resolveExportLayout(models.ReportTypeSemesterSchedule, []models.ExportTemplateColumn{{Key: "classId"}}, "")
+	assert.Error(t, err)
+}
+
+type researchSourceStub struct{}
+
+func (researchSourceStub) Attendance(ctx context.Context, termID, classID string) ([]models.ResearchAttendanceRow, error) {
+	date := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	return []models.ResearchAttendanceRow{
+		{StudentID: "student-1", ClassID: "class-1", Date: date, Status: models.AttendanceStatusPresent},
+		{StudentID: "student-2", ClassID: "class-1", Date: date, Status: models.AttendanceStatusSick},
+	}, nil
+}
+
+func (researchSourceStub) Grades(ctx context.Context, termID, classID string) ([]models.ResearchGradeRow, error) {
+	return []models.ResearchGradeRow{{StudentID: "student-1", ClassID: "class-1", SubjectCode: "MTK", ComponentCode: "UTS", Value: 87.5}}, nil
+}
+
+func (researchSourceStub) Behavior(ctx context.Context, termID, classID string) ([]models.ResearchBehaviorRow, error) {
+	return []models.ResearchBehaviorRow{{StudentID: "student-2", ClassID: "class-1", Date: time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), NoteType: models.BehaviorNoteNegative, Points: -5}}, nil
+}
+
+func TestExportServiceGenerateResearchArchive(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	signer := storage.NewSignedURLSigner("secret", time.Hour)
+	svc := NewExportService(analyticsStub{}, store, signer, ExportConfig{ResultTTL: time.Hour}, zap.NewNop(), nil, nil,
+		WithResearchExports(researchSourceStub{}, []byte("research-key")))
+	require.True(t, svc.ResearchEnabled())
+
+	job := &models.ReportJob{ID: "job-r", Type: models.ReportTypeResearch, Params: models.ReportJobParams{TermID: "term-1", Format: models.ReportFormatZIP}}
+	result, err := svc.Generate(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportFormatZIP, result.Format)
+
+	archive, err := zip.OpenReader(store.Path(result.RelativePath))
+	require.NoError(t, err)
+	defer archive.Close()
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[file.Name] = string(data)
+	}
+	require.Len(t, files, 3)
+
+	student1 := svc.pseudonyms.pseudonym("student", "student-1")
+	student2 := svc.pseudonyms.pseudonym("student", "student-2")
+	class1 := svc.pseudonyms.pseudonym("class", "class-1")
+	assert.Len(t, student1, researchPseudonymLength)
+	assert.NotEqual(t, student1, student2)
+	assert.Equal(t, "student,class,date,status\n"+student1+","+class1+",2026-02-03,H\n"+student2+","+class1+",2026-02-03,S\n", files["attendance.csv"])
+	assert.Equal(t, "student,class,subject,component,score\n"+student1+","+class1+",MTK,UTS,87.5\n", files["grades.csv"])
+	assert.Equal(t, "student,class,date,type,points\n"+student2+","+class1+",2026-02-04,-,-5\n", files["behavior.csv"])
+	for _, content := range files {
+		assert.NotContains(t, content, "student-1")
+		assert.NotContains(t, content, "class-1")
+	}
+
+	// Pseudonyms only depend on the key, so exports made with another key cannot be linked.
+	other := researchPseudonymizer{key: []byte("other-key")}
+	assert.Equal(t, student1, researchPseudonymizer{key: []byte("research-key")}.pseudonym("student", "student-1"))
+	assert.NotEqual(t, student1, other.pseudonym("student", "student-1"))
+}
+
+type ministrySourceStub struct {
+	attendance []models.MinistryAttendanceRow
+	grades     []models.MinistryGradeRow
+}
+
+func (s *ministrySourceStub) AttendanceRecap(ctx context.Context, termID, classID string) ([]models.MinistryAttendanceRow, error) {
+	return s.attendance, nil
+}
+
+func (s *ministrySourceStub) FinalGrades(ctx context.Context, termID, classID string) ([]models.MinistryGradeRow, error) {
+	return s.grades, nil
+}
+
+func TestExportServiceGenerateMinistryExports(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir)
+	require.NoError(t, err)
+	signer := storage.NewSignedURLSigner("secret", time.Hour)
+	nisn, nip := "0061234567", "198001012005011001"
+	homeroom, teacher := "Siti Aminah", "Budi Santoso"
+	source := &ministrySourceStub{
+		attendance: []models.MinistryAttendanceRow{{StudentID: "student-1", NISN: &nisn, NIS: "1001", StudentName: "Ani", Gender: "P", ClassName: "X IPA 1", HomeroomNIP: &nip, HomeroomName: &homeroom, Sick: 2, Excused: 1, Absent: 3}},
+		grades:     []models.MinistryGradeRow{{StudentID: "student-1", NISN: &nisn, NIS: "1001", StudentName: "Ani", ClassName: "X IPA 1", SubjectCode: "MTK", SubjectName: "Matematika", TeacherNIP: &nip, TeacherName: &teacher, FinalGrade: 87.5}},
+	}
+	svc := NewExportService(analyticsStub{}, store, signer, ExportConfig{ResultTTL: time.Hour}, zap.NewNop(), nil, nil, WithMinistryExports(source))
+	require.True(t, svc.MinistryEnabled())
+
+	job := &models.ReportJob{ID: "job-m", Type: models.ReportTypeMinistryAttendance, Params: models.ReportJobParams{TermID: "term-1", Format: models.ReportFormatCSV}}
+	result, err := svc.Generate(context.Background(), job)
+	require.NoError(t, err)
+	data, err := os.ReadFile(store.Path(result.RelativePath))
+	require.NoError(t, err)
+	assert.Equal(t, "No,NISN,NIS,Nama Peserta Didik,L/P,Kelas,Sakit,Izin,Tanpa Keterangan,NIP Wali Kelas,Nama Wali Kelas\n"+
+		"1,0061234567,1001,Ani,P,X IPA 1,2,1,3,198001012005011001,Siti Aminah\n", string(data))
+
+	job = &models.ReportJob{ID: "job-g", Type: models.ReportTypeMinistryGrades, Params: models.ReportJobParams{TermID: "term-1", Format: models.ReportFormatXLSX}}
+	result, err = svc.Generate(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportFormatXLSX, result.Format)
+	payload, err := os.ReadFile(store.Path(result.RelativePath))
+	require.NoError(t, err)
+	rows, err := export.ReadXLSX(payload)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, ministryGradeHeaders, rows[0])
+	assert.Equal(t, []string{"1", "0061234567", "1001", "Ani", "X IPA 1", "MTK", "Matematika", "198001012005011001", "Budi Santoso", "87.50"}, rows[1])
+
+	// Missing or malformed identifiers fail the export and name each offender once.
+	short := "12345"
+	source.grades = append(source.grades,
+		models.MinistryGradeRow{StudentID: "student-2", NIS: "1002", StudentName: "Bima", ClassName: "X IPA 1", SubjectCode: "MTK", TeacherNIP: &nip, TeacherName: &teacher},
+		models.MinistryGradeRow{StudentID: "student-2", NIS: "1002", StudentName: "Bima", ClassName: "X IPA 1", SubjectCode: "BIO", TeacherNIP: &short, TeacherName: &homeroom},
+		models.MinistryGradeRow{StudentID: "student-1", NISN: &nisn, NIS: "1001", StudentName: "Ani", ClassName: "X IPA 1", SubjectCode: "FIS"},
+	)
+	_, err = svc.Generate(context.Background(), job)
+	require.Error(t, err)
+	assert.Equal(t, "ministry export incomplete: 1 student(s) without a valid 10-digit NISN: Bima (NIS 1002); "+
+		"2 teacher(s) without a valid 18-digit NIP: Siti Aminah (BIO teacher of X IPA 1), FIS teacher of X IPA 1 (unassigned)", err.Error())
+}
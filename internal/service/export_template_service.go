@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type exportTemplateStore interface {
+	List(ctx context.Context) ([]models.ExportTemplate, error)
+	FindByID(ctx context.Context, id string) (*models.ExportTemplate, error)
+	FindByReportType(ctx context.Context, reportType models.ReportType) (*models.ExportTemplate, error)
+	Create(ctx context.Context, template *models.ExportTemplate) error
+	Update(ctx context.Context, template *models.ExportTemplate) error
+	Delete(ctx context.Context, id string) error
+}
+
+// ExportTemplateService manages the export layouts schools configure per report type. At most one
+// template exists per report type; ExportService applies it whenever that type is exported.
+type ExportTemplateService struct {
+	store     exportTemplateStore
+	validator *validator.Validate
+}
+
+// NewExportTemplateService constructs the service.
+func NewExportTemplateService(store exportTemplateStore, validate *validator.Validate) *ExportTemplateService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	return &ExportTemplateService{store: store, validator: validate}
+}
+
+// Columns lists the columns every report type offers to templates.
+func (s *ExportTemplateService) Columns() []dto.ExportReportColumns {
+	reportTypes := make([]string, 0, len(reportColumns))
+	for reportType := range reportColumns {
+		reportTypes = append(reportTypes, string(reportType))
+	}
+	sort.Strings(reportTypes)
+	result := make([]dto.ExportReportColumns, 0, len(reportTypes))
+	for _, reportType := range reportTypes {
+		catalogue := reportColumns[models.ReportType(reportType)]
+		columns := make([]dto.ExportTemplateColumn, len(catalogue))
+		for i, column := range catalogue {
+			columns[i] = dto.ExportTemplateColumn{Key: column.Key, Label: column.Label}
+		}
+		result = append(result, dto.ExportReportColumns{Type: models.ReportType(reportType), Columns: columns})
+	}
+	return result
+}
+
+// List returns every stored template.
+func (s *ExportTemplateService) List(ctx context.Context) ([]models.ExportTemplate, error) {
+	templates, err := s.store.List(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list export templates")
+	}
+	if templates == nil {
+		templates = []models.ExportTemplate{}
+	}
+	return templates, nil
+}
+
+// Get returns a template by ID.
+func (s *ExportTemplateService) Get(ctx context.Context, id string) (*models.ExportTemplate, error) {
+	template, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "export template not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load export template")
+	}
+	return template, nil
+}
+
+// Create stores the template of a report type that has none yet.
+func (s *ExportTemplateService) Create(ctx context.Context, req dto.ExportTemplateRequest, actor *models.JWTClaims) (*models.ExportTemplate, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	template, err := s.build(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureTypeFree(ctx, template.ReportType, ""); err != nil {
+		return nil, err
+	}
+	template.UpdatedBy = &actor.UserID
+	if err := s.store.Create(ctx, template); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create export template")
+	}
+	return template, nil
+}
+
+// Update replaces the layout of an existing template.
+func (s *ExportTemplateService) Update(ctx context.Context, id string, req dto.ExportTemplateRequest, actor *models.JWTClaims) (*models.ExportTemplate, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.build(req)
+	if err != nil {
+		return nil, err
+	}
+	if template.ReportType != existing.ReportType {
+		if err := s.ensureTypeFree(ctx, template.ReportType, id); err != nil {
+			return nil, err
+		}
+	}
+	template.ID = existing.ID
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedBy = &actor.UserID
+	if err := s.store.Update(ctx, template); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "export template not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update export template")
+	}
+	return template, nil
+}
+
+// Delete removes a template; its report type goes back to the default layout.
+func (s *ExportTemplateService) Delete(ctx context.Context, id string) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "export template not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete export template")
+	}
+	return nil
+}
+
+func (s *ExportTemplateService) build(req dto.ExportTemplateRequest) (*models.ExportTemplate, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid export template payload")
+	}
+	columns := make([]models.ExportTemplateColumn, len(req.Columns))
+	for i, column := range req.Columns {
+		columns[i] = models.ExportTemplateColumn{Key: column.Key, Label: strings.TrimSpace(column.Label)}
+	}
+	groupBy := ""
+	if req.GroupBy != nil {
+		groupBy = strings.TrimSpace(*req.GroupBy)
+	}
+	if _, err := resolveExportLayout(req.Type, columns, groupBy); err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, err.Error())
+	}
+	raw, err := json.Marshal(columns)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid export template columns")
+	}
+	template := &models.ExportTemplate{
+		ReportType: req.Type,
+		Name:       strings.TrimSpace(req.Name),
+		Columns:    types.JSONText(raw),
+	}
+	if groupBy != "" {
+		template.GroupBy = &groupBy
+	}
+	return template, nil
+}
+
+// ensureTypeFree rejects a second template for a report type. exceptID is the template being updated.
+func (s *ExportTemplateService) ensureTypeFree(ctx context.Context, reportType models.ReportType, exceptID string) error {
+	existing, err := s.store.FindByReportType(ctx, reportType)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load export template")
+	}
+	if existing.ID == exceptID {
+		return nil
+	}
+	return appErrors.Clone(appErrors.ErrConflict, "report type already has an export template")
+}
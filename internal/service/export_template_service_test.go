@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type exportTemplateStoreStub struct {
+	templates map[string]*models.ExportTemplate
+}
+
+func (s *exportTemplateStoreStub) List(ctx context.Context) ([]models.ExportTemplate, error) {
+	return nil, nil
+}
+
+func (s *exportTemplateStoreStub) FindByID(ctx context.Context, id string) (*models.ExportTemplate, error) {
+	if template, ok := s.templates[id]; ok {
+		return template, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *exportTemplateStoreStub) FindByReportType(ctx context.Context, reportType models.ReportType) (*models.ExportTemplate, error) {
+	for _, template := range s.templates {
+		if template.ReportType == reportType {
+			return template, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *exportTemplateStoreStub) Create(ctx context.Context, template *models.ExportTemplate) error {
+	template.ID = "tpl-" + string(template.ReportType)
+	s.templates[template.ID] = template
+	return nil
+}
+
+func (s *exportTemplateStoreStub) Update(ctx context.Context, template *models.ExportTemplate) error {
+	s.templates[template.ID] = template
+	return nil
+}
+
+func (s *exportTemplateStoreStub) Delete(ctx context.Context, id string) error {
+	if _, ok := s.templates[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(s.templates, id)
+	return nil
+}
+
+func TestExportTemplateServiceCreateAndUpdate(t *testing.T) {
+	store := &exportTemplateStoreStub{templates: map[string]*models.ExportTemplate{}}
+	svc := NewExportTemplateService(store, nil)
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	groupBy := " classId "
+	req := dto.ExportTemplateRequest{
+		Type:    models.ReportTypeAttendance,
+		Name:    "Rekap kehadiran",
+		Columns: []dto.ExportTemplateColumn{{Key: "classId", Label: " Kelas "}, {Key: "percentage"}},
+		GroupBy: &groupBy,
+	}
+
+	template, err := svc.Create(context.Background(), req, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "tpl-attendance", template.ID)
+	assert.JSONEq(t, `[{"key":"classId","label":"Kelas"},{"key":"percentage"}]`, string(template.Columns))
+	assert.Equal(t, "classId", *template.GroupBy)
+	assert.Equal(t, "admin-1", *template.UpdatedBy)
+
+	_, err = svc.Create(context.Background(), req, admin)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	req.GroupBy = nil
+	req.Columns = []dto.ExportTemplateColumn{{Key: "present"}}
+	updated, err := svc.Update(context.Background(), template.ID, req, admin)
+	require.NoError(t, err)
+	assert.Nil(t, updated.GroupBy)
+
+	_, err = svc.Update(context.Background(), "missing", req, admin)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	require.NoError(t, svc.Delete(context.Background(), template.ID))
+	err = svc.Delete(context.Background(), template.ID)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestExportTemplateServiceRejectsUnknownColumns(t *testing.T) {
+	svc := NewExportTemplateService(&exportTemplateStoreStub{templates: map[string]*models.ExportTemplate{}}, nil)
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	unknownGroup := "studentId"
+
+	cases := []dto.ExportTemplateRequest{
+		{Type: models.ReportTypeGrades, Name: "x", Columns: []dto.ExportTemplateColumn{{Key: "studentId"}}},
+		{Type: models.ReportTypeGrades, Name: "x", Columns: []dto.ExportTemplateColumn{{Key: "classId"}, {Key: "classId"}}},
+		{Type: models.ReportTypeGrades, Name: "x", Columns: []dto.ExportTemplateColumn{{Key: "classId"}}, GroupBy: &unknownGroup},
+		{Type: models.ReportTypeGrades, Name: "x"},
+		{Type: models.ReportTypeExamSchedule, Name: "x", Columns: []dto.ExportTemplateColumn{{Key: "classId"}}},
+	}
+	for _, req := range cases {
+		_, err := svc.Create(context.Background(), req, admin)
+		assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code, req)
+	}
+
+	columns := svc.Columns()
+	require.Len(t, columns, 4)
+	assert.Equal(t, models.ReportTypeAttendance, columns[0].Type)
+	assert.Equal(t, dto.ExportTemplateColumn{Key: "termId", Label: "Term ID"}, columns[0].Columns[0])
+}
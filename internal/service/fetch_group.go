@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// fetchGroup runs independent reads concurrently and keeps the first error, cancelling the
+// shared context so the remaining reads stop early. It mirrors errgroup.WithContext.
+type fetchGroup struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	cancel context.CancelFunc
+}
+
+func newFetchGroup(ctx context.Context) (*fetchGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &fetchGroup{cancel: cancel}, ctx
+}
+
+// Go runs fn in its own goroutine.
+func (g *fetchGroup) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until every function returned and reports the first error.
+func (g *fetchGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
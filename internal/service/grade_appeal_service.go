@@ -0,0 +1,537 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// GradeAppealEntity is the mutation entity of forwarded grade appeals; the entity ID is the appeal.
+const GradeAppealEntity = "grade_appeals"
+
+const gradeAppealSweepBatch = 100
+
+// gradeAppealChanges is the requested_changes payload of an appeal's mutation.
+type gradeAppealChanges struct {
+	FinalGrade     float64                          `json:"final_grade"`
+	Recommendation models.GradeAppealRecommendation `json:"recommendation"`
+	TeacherNote    *string                          `json:"teacher_note,omitempty"`
+}
+
+// gradeAppealChangesOf describes the appeal's teacher stage with the given grade.
+func gradeAppealChangesOf(appeal *models.GradeAppeal, grade float64) gradeAppealChanges {
+	changes := gradeAppealChanges{FinalGrade: grade, Recommendation: models.GradeAppealNone, TeacherNote: appeal.TeacherNote}
+	if appeal.Recommendation != nil {
+		changes.Recommendation = *appeal.Recommendation
+	}
+	return changes
+}
+
+type gradeAppealStore interface {
+	Target(ctx context.Context, studentID models.StudentID, termID models.TermID, subjectID models.SubjectID) (*models.GradeAppealTarget, error)
+	HasOpen(ctx context.Context, gradeFinalID string) (bool, error)
+	Create(ctx context.Context, appeal *models.GradeAppeal) error
+	FindByID(ctx context.Context, id string) (*models.GradeAppeal, error)
+	List(ctx context.Context, filter models.GradeAppealFilter) ([]models.GradeAppeal, error)
+	Forward(ctx context.Context, params repository.ForwardGradeAppealParams) error
+	SetMutation(ctx context.Context, id, mutationID string) error
+	ListTeacherOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error)
+	ListUnfiled(ctx context.Context, limit int) ([]models.GradeAppeal, error)
+	ListAdminOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error)
+	ListUnannounced(ctx context.Context, limit int) ([]models.GradeAppeal, error)
+	MarkOverdueNotified(ctx context.Context, id string, at time.Time) error
+	MarkOutcomeNotified(ctx context.Context, id string, at time.Time) error
+}
+
+type gradeAppealGuardians interface {
+	IsLinked(ctx context.Context, guardianID, studentID string) (bool, error)
+}
+
+type gradeAppealStudents interface {
+	FindByUserID(ctx context.Context, userID string) (*models.StudentDetail, error)
+}
+
+type gradeAppealNotifier interface {
+	CreateOnce(ctx context.Context, notification *models.Notification) (bool, error)
+	CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error)
+}
+
+// GradeAppealConfig sets the deadlines of the two review stages.
+type GradeAppealConfig struct {
+	// TeacherSLA is how long the subject teacher has to recommend; the appeal is then forwarded
+	// without a recommendation.
+	TeacherSLA time.Duration
+	// AdminSLA is how long super admins have to decide a forwarded appeal before they are told it is
+	// overdue.
+	AdminSLA time.Duration
+	// Interval between deadline sweeps.
+	Interval time.Duration
+}
+
+// GradeAppealServiceParams groups constructor dependencies.
+type GradeAppealServiceParams struct {
+	Store     gradeAppealStore
+	Mutations gradeMutationRequester
+	Guardians gradeAppealGuardians
+	Students  gradeAppealStudents
+	// Notifications is optional; without it nobody is told about appeals.
+	Notifications gradeAppealNotifier
+	Validator     *validator.Validate
+	Logger        *zap.Logger
+	Config        GradeAppealConfig
+}
+
+// GradeAppealService lets students and guardians appeal finalized grades. An appeal goes to the
+// subject teacher for a recommendation and then, as a GRADE_CORRECTION mutation, to the super admins,
+// whose approval corrects the grade through GradeAppealApplier.
+type GradeAppealService struct {
+	store         gradeAppealStore
+	mutations     gradeMutationRequester
+	guardians     gradeAppealGuardians
+	students      gradeAppealStudents
+	notifications gradeAppealNotifier
+	validator     *validator.Validate
+	logger        *zap.Logger
+	cfg           GradeAppealConfig
+	now           func() time.Time
+}
+
+// NewGradeAppealService constructs the service with defaults.
+func NewGradeAppealService(params GradeAppealServiceParams) *GradeAppealService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	cfg := params.Config
+	if cfg.TeacherSLA <= 0 {
+		cfg.TeacherSLA = 3 * 24 * time.Hour
+	}
+	if cfg.AdminSLA <= 0 {
+		cfg.AdminSLA = 7 * 24 * time.Hour
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	return &GradeAppealService{
+		store:         params.Store,
+		mutations:     params.Mutations,
+		guardians:     params.Guardians,
+		students:      params.Students,
+		notifications: params.Notifications,
+		validator:     validate,
+		logger:        logger,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// Submit files an appeal. Students appeal their own grades, whatever studentID says; guardians
+// appeal for a linked student.
+func (s *GradeAppealService) Submit(ctx context.Context, studentID string, req dto.GradeAppealRequest, claims *models.JWTClaims) (*models.GradeAppeal, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid grade appeal payload")
+	}
+	studentID, err := s.appellantStudent(ctx, studentID, claims)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.store.Target(ctx, models.StudentID(studentID), models.TermID(req.TermID), models.SubjectID(req.SubjectID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "final grade not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load final grade")
+	}
+	if !target.Finalized {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "only finalized grades can be appealed")
+	}
+	if req.RequestedGrade == target.FinalGrade {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "requested grade equals the final grade")
+	}
+	open, err := s.store.HasOpen(ctx, target.GradeFinalID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check open appeals")
+	}
+	if open {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "an appeal against this grade is already open")
+	}
+
+	now := s.now().UTC()
+	appeal := &models.GradeAppeal{
+		GradeFinalID:   target.GradeFinalID,
+		StudentID:      studentID,
+		TeacherID:      target.TeacherID,
+		SubmittedBy:    claims.UserID,
+		Reason:         strings.TrimSpace(req.Reason),
+		OriginalGrade:  target.FinalGrade,
+		RequestedGrade: req.RequestedGrade,
+		TeacherDueAt:   now.Add(s.cfg.TeacherSLA),
+	}
+	// Without a subject teacher there is nobody to wait for.
+	if appeal.TeacherID == nil {
+		appeal.TeacherDueAt = now
+	}
+	if err := s.store.Create(ctx, appeal); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create grade appeal")
+	}
+	stored, err := s.load(ctx, appeal.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.TeacherID == nil {
+		if err := s.forward(ctx, stored, models.GradeAppealNone, nil, nil, now); err != nil {
+			s.logger.Warn("failed to forward grade appeal without teacher", zap.String("appeal_id", stored.ID), zap.Error(err))
+		}
+		return s.load(ctx, stored.ID)
+	}
+	s.notifyTeacher(ctx, stored)
+	return stored, nil
+}
+
+// List returns the appeals the caller may see: students their own, guardians a linked student's,
+// teachers those routed to them and administrators any.
+func (s *GradeAppealService) List(ctx context.Context, query dto.GradeAppealQuery, claims *models.JWTClaims) ([]models.GradeAppeal, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid grade appeal query")
+	}
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	filter := models.GradeAppealFilter{Status: models.GradeAppealStatus(query.Status)}
+	switch claims.Role {
+	case models.RoleStudent, models.RoleGuardian:
+		studentID, err := s.appellantStudent(ctx, query.StudentID, claims)
+		if err != nil {
+			return nil, err
+		}
+		filter.StudentID = studentID
+	case models.RoleTeacher:
+		filter.TeacherID = claims.UserID
+	case models.RoleAdmin, models.RoleSuperAdmin:
+		filter.StudentID, filter.TeacherID = query.StudentID, query.TeacherID
+	default:
+		return nil, appErrors.ErrForbidden
+	}
+	appeals, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list grade appeals")
+	}
+	now := s.now()
+	for i := range appeals {
+		appeals[i].Overdue = appeals[i].IsOverdue(now)
+	}
+	return appeals, nil
+}
+
+// Get returns one appeal the caller may see.
+func (s *GradeAppealService) Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.GradeAppeal, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	appeal, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch claims.Role {
+	case models.RoleAdmin, models.RoleSuperAdmin:
+	case models.RoleTeacher:
+		if appeal.TeacherID == nil || *appeal.TeacherID != claims.UserID {
+			return nil, appErrors.ErrForbidden
+		}
+	case models.RoleStudent, models.RoleGuardian:
+		studentID, err := s.appellantStudent(ctx, appeal.StudentID, claims)
+		if err != nil {
+			return nil, err
+		}
+		if studentID != appeal.StudentID {
+			return nil, appErrors.ErrForbidden
+		}
+	default:
+		return nil, appErrors.ErrForbidden
+	}
+	return appeal, nil
+}
+
+// Respond records the subject teacher's recommendation and forwards the appeal to the super admins.
+// Administrators may respond in place of an absent teacher.
+func (s *GradeAppealService) Respond(ctx context.Context, id string, req dto.GradeAppealResponseRequest, claims *models.JWTClaims) (*models.GradeAppeal, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid grade appeal response")
+	}
+	appeal, err := s.Get(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role != models.RoleTeacher && claims.Role != models.RoleAdmin && claims.Role != models.RoleSuperAdmin {
+		return nil, appErrors.ErrForbidden
+	}
+	if appeal.Status != models.GradeAppealTeacherReview {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "appeal is no longer awaiting the teacher")
+	}
+	recommendation := models.GradeAppealRecommendation(req.Recommendation)
+	note := optionalString(deref(req.Note))
+	var proposed *float64
+	if recommendation == models.GradeAppealSupport {
+		proposed = &appeal.RequestedGrade
+		if req.ProposedGrade != nil {
+			proposed = req.ProposedGrade
+		}
+	} else if note == nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "note is required when opposing an appeal")
+	}
+	if err := s.forward(ctx, appeal, recommendation, proposed, note, s.now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.load(ctx, appeal.ID)
+}
+
+// Start sweeps the appeal deadlines now and then every interval until ctx is done.
+func (s *GradeAppealService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			s.Sweep(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sweep forwards appeals whose teacher let the deadline pass, files mutations for forwarded appeals
+// that lack one, tells super admins about appeals overdue with them and tells appellants the outcome.
+// Failures are logged and retried by the next sweep.
+func (s *GradeAppealService) Sweep(ctx context.Context) dto.GradeAppealSweep {
+	var run dto.GradeAppealSweep
+	now := s.now().UTC()
+	s.sweep(ctx, "forward overdue grade appeals", func() ([]models.GradeAppeal, error) {
+		return s.store.ListTeacherOverdue(ctx, now, gradeAppealSweepBatch)
+	}, func(appeal models.GradeAppeal) error {
+		if err := s.forward(ctx, &appeal, models.GradeAppealNone, nil, nil, now); err != nil {
+			return err
+		}
+		run.Forwarded++
+		return nil
+	})
+	s.sweep(ctx, "file grade appeal mutations", func() ([]models.GradeAppeal, error) {
+		return s.store.ListUnfiled(ctx, gradeAppealSweepBatch)
+	}, func(appeal models.GradeAppeal) error {
+		if err := s.file(ctx, &appeal); err != nil {
+			return err
+		}
+		run.Filed++
+		return nil
+	})
+	if s.notifications == nil {
+		return run
+	}
+	s.sweep(ctx, "escalate overdue grade appeals", func() ([]models.GradeAppeal, error) {
+		return s.store.ListAdminOverdue(ctx, now, gradeAppealSweepBatch)
+	}, func(appeal models.GradeAppeal) error {
+		if err := s.escalate(ctx, appeal, now); err != nil {
+			return err
+		}
+		run.Escalated++
+		return nil
+	})
+	s.sweep(ctx, "announce grade appeal outcomes", func() ([]models.GradeAppeal, error) {
+		return s.store.ListUnannounced(ctx, gradeAppealSweepBatch)
+	}, func(appeal models.GradeAppeal) error {
+		if err := s.announce(ctx, appeal, now); err != nil {
+			return err
+		}
+		run.Announced++
+		return nil
+	})
+	if run != (dto.GradeAppealSweep{}) {
+		s.logger.Info("grade appeals swept", zap.Int("forwarded", run.Forwarded), zap.Int("filed", run.Filed),
+			zap.Int("escalated", run.Escalated), zap.Int("announced", run.Announced))
+	}
+	return run
+}
+
+// sweep applies step to one batch; whatever is left is picked up by the next sweep.
+func (s *GradeAppealService) sweep(ctx context.Context, op string, list func() ([]models.GradeAppeal, error), step func(models.GradeAppeal) error) {
+	appeals, err := list()
+	if err != nil {
+		s.logger.Warn(op+" failed", zap.Error(err))
+		return
+	}
+	for _, appeal := range appeals {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := step(appeal); err != nil {
+			s.logger.Warn(op+" failed", zap.String("appeal_id", appeal.ID), zap.Error(err))
+		}
+	}
+}
+
+// forward closes the teacher stage and files the appeal's mutation. A failed filing leaves the
+// appeal forwarded without a mutation, which the sweep files later.
+func (s *GradeAppealService) forward(ctx context.Context, appeal *models.GradeAppeal, recommendation models.GradeAppealRecommendation, proposed *float64, note *string, now time.Time) error {
+	err := s.store.Forward(ctx, repository.ForwardGradeAppealParams{
+		ID:             appeal.ID,
+		Recommendation: recommendation,
+		ProposedGrade:  proposed,
+		TeacherNote:    note,
+		ForwardedAt:    now,
+		AdminDueAt:     now.Add(s.cfg.AdminSLA),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrConflict, "appeal is no longer awaiting the teacher")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to forward grade appeal")
+	}
+	appeal.Recommendation, appeal.ProposedGrade, appeal.TeacherNote = &recommendation, proposed, note
+	if err := s.file(ctx, appeal); err != nil {
+		s.logger.Warn("failed to file grade appeal mutation", zap.String("appeal_id", appeal.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// file requests the grade the teacher supports, or the appellant's when the teacher did not support
+// the appeal, as a mutation on behalf of the appellant.
+func (s *GradeAppealService) file(ctx context.Context, appeal *models.GradeAppeal) error {
+	grade := appeal.RequestedGrade
+	if appeal.ProposedGrade != nil {
+		grade = *appeal.ProposedGrade
+	}
+	changes := gradeAppealChangesOf(appeal, grade)
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("encode grade appeal changes: %w", err)
+	}
+	mutation, err := s.mutations.RequestChange(ctx, dto.CreateMutationRequest{
+		Type:             models.MutationTypeGradeCorrection,
+		Entity:           GradeAppealEntity,
+		EntityID:         appeal.ID,
+		Reason:           fmt.Sprintf("Grade appeal by %s in %s: %s", appeal.StudentName, appeal.SubjectName, appeal.Reason),
+		RequestedChanges: payload,
+	}, appeal.SubmittedBy)
+	if err != nil {
+		return err
+	}
+	return s.store.SetMutation(ctx, appeal.ID, mutation.ID)
+}
+
+func (s *GradeAppealService) notifyTeacher(ctx context.Context, appeal *models.GradeAppeal) {
+	if s.notifications == nil || appeal.TeacherID == nil {
+		return
+	}
+	id := appeal.ID
+	key := fmt.Sprintf("grade-appeal:%s", appeal.ID)
+	if _, err := s.notifications.CreateOnce(ctx, &models.Notification{
+		UserID:    *appeal.TeacherID,
+		Type:      models.NotificationTypeGradeAppeal,
+		Title:     "Grade appeal",
+		Body:      fmt.Sprintf("%s appealed their %s grade. Please respond by %s.", appeal.StudentName, appeal.SubjectName, appeal.TeacherDueAt.Format("2006-01-02 15:04")),
+		RefID:     &id,
+		DedupeKey: &key,
+	}); err != nil {
+		s.logger.Warn("failed to notify teacher of grade appeal", zap.String("appeal_id", appeal.ID), zap.Error(err))
+	}
+}
+
+func (s *GradeAppealService) escalate(ctx context.Context, appeal models.GradeAppeal, now time.Time) error {
+	id := appeal.ID
+	if appeal.MutationID != nil {
+		id = *appeal.MutationID
+	}
+	key := fmt.Sprintf("grade-appeal-overdue:%s", appeal.ID)
+	if _, err := s.notifications.CreateForRoles(ctx, []models.UserRole{models.RoleSuperAdmin}, models.Notification{
+		Type:      models.NotificationTypeGradeAppealOverdue,
+		Title:     "Grade appeal overdue",
+		Body:      fmt.Sprintf("The %s grade appeal of %s has been waiting for a decision since %s.", appeal.SubjectName, appeal.StudentName, appeal.ForwardedAt.UTC().Format("2006-01-02")),
+		RefID:     &id,
+		DedupeKey: &key,
+	}); err != nil {
+		return err
+	}
+	return s.store.MarkOverdueNotified(ctx, appeal.ID, now)
+}
+
+func (s *GradeAppealService) announce(ctx context.Context, appeal models.GradeAppeal, now time.Time) error {
+	id := appeal.ID
+	key := fmt.Sprintf("grade-appeal-decided:%s", appeal.ID)
+	body := fmt.Sprintf("Your %s grade appeal was rejected; the grade stays %.2f.", appeal.SubjectName, appeal.CurrentGrade)
+	if appeal.Status == models.GradeAppealApproved {
+		body = fmt.Sprintf("Your %s grade appeal was approved; the report card now shows %.2f.", appeal.SubjectName, appeal.CurrentGrade)
+	}
+	if _, err := s.notifications.CreateOnce(ctx, &models.Notification{
+		UserID:    appeal.SubmittedBy,
+		Type:      models.NotificationTypeGradeAppealDecided,
+		Title:     "Grade appeal decided",
+		Body:      body,
+		RefID:     &id,
+		DedupeKey: &key,
+	}); err != nil {
+		return err
+	}
+	return s.store.MarkOutcomeNotified(ctx, appeal.ID, now)
+}
+
+// appellantStudent resolves the student a student or guardian acts for: students always act for
+// themselves, guardians for a linked student.
+func (s *GradeAppealService) appellantStudent(ctx context.Context, studentID string, claims *models.JWTClaims) (string, error) {
+	if claims == nil {
+		return "", appErrors.ErrUnauthorized
+	}
+	switch claims.Role {
+	case models.RoleStudent:
+		student, err := s.students.FindByUserID(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", appErrors.Clone(appErrors.ErrForbidden, "account is not linked to a student")
+			}
+			return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+		}
+		return student.ID, nil
+	case models.RoleGuardian:
+		if strings.TrimSpace(studentID) == "" {
+			return "", appErrors.Clone(appErrors.ErrValidation, "student id is required")
+		}
+		linked, err := s.guardians.IsLinked(ctx, claims.UserID, studentID)
+		if err != nil {
+			return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify guardian access")
+		}
+		if !linked {
+			return "", appErrors.Clone(appErrors.ErrForbidden, "student is not linked to this guardian")
+		}
+		return studentID, nil
+	default:
+		return "", appErrors.Clone(appErrors.ErrForbidden, "only students and guardians can appeal grades")
+	}
+}
+
+func (s *GradeAppealService) load(ctx context.Context, id string) (*models.GradeAppeal, error) {
+	appeal, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "grade appeal not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade appeal")
+	}
+	appeal.Overdue = appeal.IsOverdue(s.now())
+	return appeal, nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type gradeAppealStoreStub struct {
+	targets map[string]*models.GradeAppealTarget
+	appeals map[string]*models.GradeAppeal
+	order   []string
+	// escalated and announced record the notified markers.
+	escalated map[string]bool
+	announced map[string]bool
+}
+
+func (s *gradeAppealStoreStub) Target(ctx context.Context, studentID models.StudentID, termID models.TermID, subjectID models.SubjectID) (*models.GradeAppealTarget, error) {
+	if target, ok := s.targets[studentID.String()+"|"+subjectID.String()]; ok {
+		return target, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *gradeAppealStoreStub) HasOpen(ctx context.Context, gradeFinalID string) (bool, error) {
+	for _, appeal := range s.appeals {
+		if appeal.GradeFinalID == gradeFinalID && appeal.Open() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *gradeAppealStoreStub) Create(ctx context.Context, appeal *models.GradeAppeal) error {
+	appeal.ID = "appeal-" + string(rune('1'+len(s.order)))
+	appeal.Status = models.GradeAppealTeacherReview
+	appeal.StudentName, appeal.SubjectName = "Budi", "Matematika"
+	appeal.CurrentGrade = appeal.OriginalGrade
+	stored := *appeal
+	s.appeals[appeal.ID] = &stored
+	s.order = append(s.order, appeal.ID)
+	return nil
+}
+
+func (s *gradeAppealStoreStub) FindByID(ctx context.Context, id string) (*models.GradeAppeal, error) {
+	if appeal, ok := s.appeals[id]; ok {
+		copied := *appeal
+		return &copied, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *gradeAppealStoreStub) List(ctx context.Context, filter models.GradeAppealFilter) ([]models.GradeAppeal, error) {
+	return s.where(func(a *models.GradeAppeal) bool {
+		return (filter.StudentID == "" || a.StudentID == filter.StudentID) &&
+			(filter.TeacherID == "" || (a.TeacherID != nil && *a.TeacherID == filter.TeacherID)) &&
+			(filter.Status == "" || a.Status == filter.Status)
+	}), nil
+}
+
+func (s *gradeAppealStoreStub) Forward(ctx context.Context, params repository.ForwardGradeAppealParams) error {
+	appeal, ok := s.appeals[params.ID]
+	if !ok || appeal.Status != models.GradeAppealTeacherReview {
+		return sql.ErrNoRows
+	}
+	recommendation := params.Recommendation
+	appeal.Status = models.GradeAppealAdminReview
+	appeal.Recommendation, appeal.ProposedGrade, appeal.TeacherNote = &recommendation, params.ProposedGrade, params.TeacherNote
+	appeal.ForwardedAt, appeal.AdminDueAt = &params.ForwardedAt, &params.AdminDueAt
+	return nil
+}
+
+func (s *gradeAppealStoreStub) SetMutation(ctx context.Context, id, mutationID string) error {
+	s.appeals[id].MutationID = &mutationID
+	return nil
+}
+
+func (s *gradeAppealStoreStub) ListTeacherOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error) {
+	return s.where(func(a *models.GradeAppeal) bool {
+		return a.Status == models.GradeAppealTeacherReview && !a.TeacherDueAt.After(now)
+	}), nil
+}
+
+func (s *gradeAppealStoreStub) ListUnfiled(ctx context.Context, limit int) ([]models.GradeAppeal, error) {
+	return s.where(func(a *models.GradeAppeal) bool { return a.ForwardedAt != nil && a.MutationID == nil }), nil
+}
+
+func (s *gradeAppealStoreStub) ListAdminOverdue(ctx context.Context, now time.Time, limit int) ([]models.GradeAppeal, error) {
+	return s.where(func(a *models.GradeAppeal) bool {
+		return a.Status == models.GradeAppealAdminReview && !a.AdminDueAt.After(now) && !s.escalated[a.ID]
+	}), nil
+}
+
+func (s *gradeAppealStoreStub) ListUnannounced(ctx context.Context, limit int) ([]models.GradeAppeal, error) {
+	return s.where(func(a *models.GradeAppeal) bool {
+		return (a.Status == models.GradeAppealApproved || a.Status == models.GradeAppealRejected) && !s.announced[a.ID]
+	}), nil
+}
+
+func (s *gradeAppealStoreStub) MarkOverdueNotified(ctx context.Context, id string, at time.Time) error {
+	s.escalated[id] = true
+	return nil
+}
+
+func (s *gradeAppealStoreStub) MarkOutcomeNotified(ctx context.Context, id string, at time.Time) error {
+	s.announced[id] = true
+	return nil
+}
+
+func (s *gradeAppealStoreStub) where(match func(*models.GradeAppeal) bool) []models.GradeAppeal {
+	result := []models.GradeAppeal{}
+	for _, id := range s.order {
+		if match(s.appeals[id]) {
+			result = append(result, *s.appeals[id])
+		}
+	}
+	return result
+}
+
+type gradeAppealFixture struct {
+	svc       *GradeAppealService
+	store     *gradeAppealStoreStub
+	mutations *mutationRequesterStub
+	notifier  *mutationNotifierStub
+	now       time.Time
+}
+
+var (
+	appealStudent  = &models.JWTClaims{UserID: "user-student-1", Role: models.RoleStudent}
+	appealGuardian = &models.JWTClaims{UserID: "guardian-1", Role: models.RoleGuardian}
+	appealTeacher  = &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+)
+
+func newGradeAppealFixture() *gradeAppealFixture {
+	teacherID := "teacher-1"
+	store := &gradeAppealStoreStub{
+		targets: map[string]*models.GradeAppealTarget{
+			"student-1|math":    {GradeFinalID: "gf-math", EnrollmentID: "enr-1", ClassID: "class-1", FinalGrade: 68, Finalized: true, TeacherID: &teacherID},
+			"student-1|art":     {GradeFinalID: "gf-art", EnrollmentID: "enr-1", ClassID: "class-1", FinalGrade: 80, Finalized: true},
+			"student-1|biology": {GradeFinalID: "gf-bio", EnrollmentID: "enr-1", ClassID: "class-1", FinalGrade: 70, TeacherID: &teacherID},
+		},
+		appeals:   map[string]*models.GradeAppeal{},
+		escalated: map[string]bool{},
+		announced: map[string]bool{},
+	}
+	fixture := &gradeAppealFixture{
+		store:     store,
+		mutations: &mutationRequesterStub{},
+		notifier:  &mutationNotifierStub{},
+		now:       time.Date(2026, 6, 20, 8, 0, 0, 0, time.UTC),
+	}
+	fixture.svc = NewGradeAppealService(GradeAppealServiceParams{
+		Store:     store,
+		Mutations: fixture.mutations,
+		Guardians: &guardianLinkStub{links: map[string][]string{"guardian-1": {"student-1"}}},
+		Students: &studentAccountStub{
+			students: map[string]*models.StudentDetail{"student-1": {Student: models.Student{ID: "student-1"}}},
+			accounts: map[string]string{"student-1": "user-student-1"},
+		},
+		Notifications: fixture.notifier,
+	})
+	fixture.svc.now = func() time.Time { return fixture.now }
+	return fixture
+}
+
+func appealRequest(subjectID string, grade float64) dto.GradeAppealRequest {
+	return dto.GradeAppealRequest{SubjectID: subjectID, TermID: "term-1", RequestedGrade: grade, Reason: "Remedial score was not counted"}
+}
+
+func TestGradeAppealSubmitRoutesToSubjectTeacher(t *testing.T) {
+	f := newGradeAppealFixture()
+
+	appeal, err := f.svc.Submit(context.Background(), "ignored", appealRequest("math", 75), appealStudent)
+	require.NoError(t, err)
+	assert.Equal(t, "student-1", appeal.StudentID)
+	assert.Equal(t, models.GradeAppealTeacherReview, appeal.Status)
+	assert.Equal(t, 68.0, appeal.OriginalGrade)
+	assert.Equal(t, f.now.Add(3*24*time.Hour), appeal.TeacherDueAt)
+	require.Len(t, f.notifier.sent, 1)
+	assert.Equal(t, "teacher-1", f.notifier.sent[0].UserID)
+	assert.Equal(t, models.NotificationTypeGradeAppeal, f.notifier.sent[0].Type)
+	assert.Empty(t, f.mutations.requests)
+
+	_, err = f.svc.Submit(context.Background(), "student-1", appealRequest("math", 80), appealGuardian)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestGradeAppealSubmitRejectsInvalidAppeals(t *testing.T) {
+	f := newGradeAppealFixture()
+	ctx := context.Background()
+
+	_, err := f.svc.Submit(ctx, "student-1", appealRequest("biology", 75), appealStudent)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	_, err = f.svc.Submit(ctx, "student-1", appealRequest("math", 68), appealStudent)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = f.svc.Submit(ctx, "student-1", appealRequest("physics", 75), appealStudent)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	_, err = f.svc.Submit(ctx, "student-2", appealRequest("math", 75), appealGuardian)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	_, err = f.svc.Submit(ctx, "student-1", appealRequest("math", 75), appealTeacher)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+}
+
+func TestGradeAppealWithoutTeacherGoesStraightToSuperAdmins(t *testing.T) {
+	f := newGradeAppealFixture()
+
+	appeal, err := f.svc.Submit(context.Background(), "student-1", appealRequest("art", 85), appealGuardian)
+	require.NoError(t, err)
+	assert.Equal(t, models.GradeAppealAdminReview, appeal.Status)
+	assert.Equal(t, models.GradeAppealNone, *appeal.Recommendation)
+	require.NotNil(t, appeal.MutationID)
+	require.Len(t, f.mutations.requests, 1)
+	assert.Equal(t, GradeAppealEntity, f.mutations.requests[0].Entity)
+	assert.Empty(t, f.notifier.sent)
+}
+
+func TestGradeAppealRespondForwardsRecommendation(t *testing.T) {
+	f := newGradeAppealFixture()
+	ctx := context.Background()
+	appeal, err := f.svc.Submit(ctx, "", appealRequest("math", 80), appealStudent)
+	require.NoError(t, err)
+
+	_, err = f.svc.Respond(ctx, appeal.ID, dto.GradeAppealResponseRequest{Recommendation: "OPPOSE"}, appealTeacher)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	_, err = f.svc.Respond(ctx, appeal.ID, dto.GradeAppealResponseRequest{Recommendation: "SUPPORT"}, &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	proposed := 76.0
+	forwarded, err := f.svc.Respond(ctx, appeal.ID, dto.GradeAppealResponseRequest{Recommendation: "SUPPORT", ProposedGrade: &proposed}, appealTeacher)
+	require.NoError(t, err)
+	assert.Equal(t, models.GradeAppealAdminReview, forwarded.Status)
+	assert.Equal(t, f.now.Add(7*24*time.Hour), *forwarded.AdminDueAt)
+	require.Len(t, f.mutations.requests, 1)
+	sent := f.mutations.requests[0]
+	assert.Equal(t, models.MutationTypeGradeCorrection, sent.Type)
+	assert.Equal(t, appeal.ID, sent.EntityID)
+	var changes gradeAppealChanges
+	require.NoError(t, json.Unmarshal(sent.RequestedChanges, &changes))
+	assert.Equal(t, 76.0, changes.FinalGrade)
+	assert.Equal(t, models.GradeAppealSupport, changes.Recommendation)
+
+	_, err = f.svc.Respond(ctx, appeal.ID, dto.GradeAppealResponseRequest{Recommendation: "SUPPORT"}, appealTeacher)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestGradeAppealListScopesByRole(t *testing.T) {
+	f := newGradeAppealFixture()
+	ctx := context.Background()
+	_, err := f.svc.Submit(ctx, "", appealRequest("math", 80), appealStudent)
+	require.NoError(t, err)
+	f.now = f.now.Add(4 * 24 * time.Hour)
+
+	appeals, err := f.svc.List(ctx, dto.GradeAppealQuery{StudentID: "student-2"}, appealStudent)
+	require.NoError(t, err)
+	require.Len(t, appeals, 1)
+	assert.True(t, appeals[0].Overdue)
+
+	_, err = f.svc.List(ctx, dto.GradeAppealQuery{}, appealGuardian)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	appeals, err = f.svc.List(ctx, dto.GradeAppealQuery{}, &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	assert.Empty(t, appeals)
+}
+
+func TestGradeAppealSweepEnforcesDeadlines(t *testing.T) {
+	f := newGradeAppealFixture()
+	ctx := context.Background()
+	appeal, err := f.svc.Submit(ctx, "", appealRequest("math", 80), appealStudent)
+	require.NoError(t, err)
+
+	f.now = f.now.Add(3*24*time.Hour + time.Minute)
+	run := f.svc.Sweep(ctx)
+	assert.Equal(t, dto.GradeAppealSweep{Forwarded: 1}, run)
+	stored := f.store.appeals[appeal.ID]
+	assert.Equal(t, models.GradeAppealNone, *stored.Recommendation)
+	require.NotNil(t, stored.MutationID)
+
+	f.now = f.now.Add(7*24*time.Hour + time.Minute)
+	assert.Equal(t, dto.GradeAppealSweep{Escalated: 1}, f.svc.Sweep(ctx))
+	require.Len(t, f.notifier.broadcasts, 1)
+	assert.Equal(t, models.NotificationTypeGradeAppealOverdue, f.notifier.broadcasts[0].Type)
+
+	stored.Status, stored.CurrentGrade = models.GradeAppealApproved, 80
+	assert.Equal(t, dto.GradeAppealSweep{Announced: 1}, f.svc.Sweep(ctx))
+	last := f.notifier.sent[len(f.notifier.sent)-1]
+	assert.Equal(t, "user-student-1", last.UserID)
+	assert.Equal(t, models.NotificationTypeGradeAppealDecided, last.Type)
+	assert.Contains(t, last.Body, "approved")
+
+	assert.Equal(t, dto.GradeAppealSweep{}, f.svc.Sweep(ctx))
+}
+
+type gradeCorrectorStub struct {
+	grade    float64
+	belowKKM bool
+	note     string
+}
+
+func (s *gradeCorrectorStub) Correct(ctx context.Context, exec sqlx.ExtContext, id string, grade float64, belowKKM bool, note string, at time.Time) error {
+	if id != "gf-math" {
+		return sql.ErrNoRows
+	}
+	s.grade, s.belowKKM, s.note = grade, belowKKM, note
+	return nil
+}
+
+type gradeConfigScopeStub struct{ kkm float64 }
+
+func (s gradeConfigScopeStub) FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error) {
+	return &models.GradeConfig{KKM: &s.kkm}, nil
+}
+
+func TestGradeAppealApplierCorrectsFinalGrade(t *testing.T) {
+	f := newGradeAppealFixture()
+	ctx := context.Background()
+	appeal, err := f.svc.Submit(ctx, "", appealRequest("math", 80), appealStudent)
+	require.NoError(t, err)
+	proposed := 72.0
+	_, err = f.svc.Respond(ctx, appeal.ID, dto.GradeAppealResponseRequest{Recommendation: "SUPPORT", ProposedGrade: &proposed}, appealTeacher)
+	require.NoError(t, err)
+
+	finals := &gradeCorrectorStub{}
+	applier := NewGradeAppealApplier(f.store, finals, gradeConfigScopeStub{kkm: 75}, nil, nil)
+	snapshot, err := applier.Snapshot(ctx, GradeAppealEntity, appeal.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"final_grade":68,"recommendation":"SUPPORT"}`, string(snapshot))
+
+	mutation := &models.Mutation{ID: "mut-1", EntityID: appeal.ID, RequestedChanges: f.mutations.requests[0].RequestedChanges}
+	_, err = applier.Apply(ctx, mutation)
+	require.NoError(t, err)
+	assert.Equal(t, 72.0, finals.grade)
+	assert.True(t, finals.belowKKM)
+	assert.Equal(t, "Adjusted by grade appeal "+appeal.ID, finals.note)
+
+	mutation.RequestedChanges = json.RawMessage(`{"final_grade":120}`)
+	_, err = applier.Apply(ctx, mutation)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
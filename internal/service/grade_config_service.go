@@ -15,8 +15,8 @@ import (
 type gradeConfigRepository interface {
 	List(ctx context.Context, filter models.FinalGradeFilter) ([]models.GradeConfig, error)
 	FindByID(ctx context.Context, id string) (*models.GradeConfig, error)
-	FindByScope(ctx context.Context, classID, subjectID, termID string) (*models.GradeConfig, error)
-	Exists(ctx context.Context, classID, subjectID, termID, excludeID string) (bool, error)
+	FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error)
+	Exists(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID, excludeID string) (bool, error)
 	Create(ctx context.Context, config *models.GradeConfig) error
 	Update(ctx context.Context, config *models.GradeConfig) error
 	Finalize(ctx context.Context, id string, finalized bool) error
@@ -26,10 +26,39 @@ type gradeComponentReader interface {
 	FindByID(ctx context.Context, id string) (*models.GradeComponent, error)
 }
 
-// GradeConfigComponentRequest captures payload for config components.
+// GradeConfigComponentRequest captures payload for config components. MinGrades is the number of
+// entries a student needs in the component before a final grade is calculated.
 type GradeConfigComponentRequest struct {
 	ComponentID string  `json:"component_id" validate:"required"`
 	Weight      float64 `json:"weight"`
+	MinGrades   int     `json:"min_grades" validate:"gte=0"`
+}
+
+// GradeSchemeOptions parameterises the calculation scheme. DropLowest is required by DROP_LOWEST and
+// BestN by BEST_N; both count entries within each component.
+type GradeSchemeOptions struct {
+	DropLowest int `json:"drop_lowest" validate:"gte=0"`
+	BestN      int `json:"best_n" validate:"gte=0"`
+}
+
+// GradeRoundingOptions selects how final grades are rounded. New configs default to HALF_EVEN at 2
+// decimal places; on update omitted fields keep their current value.
+type GradeRoundingOptions struct {
+	RoundingMode   models.GradeRoundingMode `json:"rounding_mode" validate:"omitempty,oneof=HALF_EVEN HALF_UP"`
+	RoundingPlaces *int                     `json:"rounding_places" validate:"omitempty,gte=0,lte=4"`
+	RoundToInteger *bool                    `json:"round_to_integer"`
+}
+
+func (o GradeRoundingOptions) apply(config *models.GradeConfig) {
+	if o.RoundingMode != "" {
+		config.RoundingMode = o.RoundingMode
+	}
+	if o.RoundingPlaces != nil {
+		config.RoundingPlaces = *o.RoundingPlaces
+	}
+	if o.RoundToInteger != nil {
+		config.RoundToInteger = *o.RoundToInteger
+	}
 }
 
 // CreateGradeConfigRequest handles creation payload.
@@ -38,13 +67,22 @@ type CreateGradeConfigRequest struct {
 	SubjectID         string                        `json:"subject_id" validate:"required"`
 	TermID            string                        `json:"term_id" validate:"required"`
 	CalculationScheme models.GradeCalculationScheme `json:"calculation_scheme" validate:"required"`
-	Components        []GradeConfigComponentRequest `json:"components" validate:"required,dive"`
+	GradeSchemeOptions
+	GradeRoundingOptions
+	// KKM is the minimum mastery score; finals below it are flagged for remedial work.
+	KKM        *float64                      `json:"kkm" validate:"omitempty,gte=0,lte=100"`
+	Components []GradeConfigComponentRequest `json:"components" validate:"required,dive"`
 }
 
-// UpdateGradeConfigRequest handles update payload.
+// UpdateGradeConfigRequest handles update payload. An omitted KKM keeps the current one; ClearKKM
+// removes it.
 type UpdateGradeConfigRequest struct {
 	CalculationScheme models.GradeCalculationScheme `json:"calculation_scheme" validate:"required"`
-	Components        []GradeConfigComponentRequest `json:"components" validate:"required,dive"`
+	GradeSchemeOptions
+	GradeRoundingOptions
+	KKM        *float64                      `json:"kkm" validate:"omitempty,gte=0,lte=100"`
+	ClearKKM   bool                          `json:"clear_kkm" validate:"excluded_with=KKM"`
+	Components []GradeConfigComponentRequest `json:"components" validate:"required,dive"`
 }
 
 // GradeConfigService manages grade configuration logic.
@@ -92,10 +130,10 @@ func (s *GradeConfigService) Create(ctx context.Context, req CreateGradeConfigRe
 	if err := s.validator.Struct(req); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid grade config payload")
 	}
-	if err := s.validateScheme(req.CalculationScheme, req.Components); err != nil {
+	if err := s.validateScheme(req.CalculationScheme, req.GradeSchemeOptions, req.Components); err != nil {
 		return nil, err
 	}
-	exists, err := s.repo.Exists(ctx, req.ClassID, req.SubjectID, req.TermID, "")
+	exists, err := s.repo.Exists(ctx, models.ClassID(req.ClassID), models.SubjectID(req.SubjectID), models.TermID(req.TermID), "")
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to validate grade config")
 	}
@@ -107,13 +145,21 @@ func (s *GradeConfigService) Create(ctx context.Context, req CreateGradeConfigRe
 		return nil, err
 	}
 	config := &models.GradeConfig{
-		ClassID:           req.ClassID,
-		SubjectID:         req.SubjectID,
-		TermID:            req.TermID,
+		ClassID:           models.ClassID(req.ClassID),
+		SubjectID:         models.SubjectID(req.SubjectID),
+		TermID:            models.TermID(req.TermID),
 		CalculationScheme: req.CalculationScheme,
+		DropLowest:        req.DropLowest,
+		BestN:             req.BestN,
+		RoundingMode:      models.GradeRoundingHalfEven,
+		RoundingPlaces:    2,
 		Finalized:         false,
 		Components:        comps,
 	}
+	req.GradeRoundingOptions.apply(config)
+	if req.KKM != nil {
+		config.KKM = req.KKM
+	}
 	if err := s.repo.Create(ctx, config); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create grade config")
 	}
@@ -139,7 +185,7 @@ func (s *GradeConfigService) Update(ctx context.Context, id string, req UpdateGr
 	if config.Finalized {
 		return nil, appErrors.Clone(appErrors.ErrFinalized, "grade config finalized")
 	}
-	if err := s.validateScheme(req.CalculationScheme, req.Components); err != nil {
+	if err := s.validateScheme(req.CalculationScheme, req.GradeSchemeOptions, req.Components); err != nil {
 		return nil, err
 	}
 	comps, err := s.resolveComponents(ctx, req.Components)
@@ -147,6 +193,15 @@ func (s *GradeConfigService) Update(ctx context.Context, id string, req UpdateGr
 		return nil, err
 	}
 	config.CalculationScheme = req.CalculationScheme
+	config.DropLowest = req.DropLowest
+	config.BestN = req.BestN
+	req.GradeRoundingOptions.apply(config)
+	switch {
+	case req.ClearKKM:
+		config.KKM = nil
+	case req.KKM != nil:
+		config.KKM = req.KKM
+	}
 	config.Components = comps
 	if err := s.repo.Update(ctx, config); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update grade config")
@@ -177,13 +232,29 @@ func (s *GradeConfigService) Finalize(ctx context.Context, id string) (*models.G
 	return config, nil
 }
 
-func (s *GradeConfigService) validateScheme(scheme models.GradeCalculationScheme, components []GradeConfigComponentRequest) error {
-	if scheme != models.GradeSchemeWeighted && scheme != models.GradeSchemeAverage {
+func (s *GradeConfigService) validateScheme(scheme models.GradeCalculationScheme, options GradeSchemeOptions, components []GradeConfigComponentRequest) error {
+	switch scheme {
+	case models.GradeSchemeWeighted, models.GradeSchemeAverage, models.GradeSchemeDropLowest, models.GradeSchemeBestN:
+	default:
 		return appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unsupported calculation scheme %s", scheme))
 	}
 	if len(components) == 0 {
 		return appErrors.Clone(appErrors.ErrValidation, "components required")
 	}
+	if scheme == models.GradeSchemeDropLowest {
+		if options.DropLowest < 1 {
+			return appErrors.Clone(appErrors.ErrValidation, "drop_lowest must be at least 1")
+		}
+	} else if options.DropLowest != 0 {
+		return appErrors.Clone(appErrors.ErrValidation, "drop_lowest only applies to the DROP_LOWEST scheme")
+	}
+	if scheme == models.GradeSchemeBestN {
+		if options.BestN < 1 {
+			return appErrors.Clone(appErrors.ErrValidation, "best_n must be at least 1")
+		}
+	} else if options.BestN != 0 {
+		return appErrors.Clone(appErrors.ErrValidation, "best_n only applies to the BEST_N scheme")
+	}
 	seen := make(map[string]struct{}, len(components))
 	total := 0.0
 	for _, comp := range components {
@@ -211,7 +282,7 @@ func (s *GradeConfigService) resolveComponents(ctx context.Context, payload []Gr
 			}
 			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade component")
 		}
-		components[i] = models.GradeConfigComponent{ComponentID: component.ID, Weight: p.Weight, ComponentCode: component.Code, ComponentName: component.Name}
+		components[i] = models.GradeConfigComponent{ComponentID: component.ID, Weight: p.Weight, ComponentCode: component.Code, ComponentName: component.Name, MinGrades: p.MinGrades}
 	}
 	return components, nil
 }
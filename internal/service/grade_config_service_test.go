@@ -31,7 +31,7 @@ func (m *mockGradeConfigRepo) FindByID(ctx context.Context, id string) (*models.
 	return nil, sql.ErrNoRows
 }
 
-func (m *mockGradeConfigRepo) FindByScope(ctx context.Context, classID, subjectID, termID string) (*models.GradeConfig, error) {
+func (m *mockGradeConfigRepo) FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error) {
 	for _, cfg := range m.configs {
 		if cfg.ClassID == classID && cfg.SubjectID == subjectID && cfg.TermID == termID {
 			return cfg, nil
@@ -40,7 +40,7 @@ func (m *mockGradeConfigRepo) FindByScope(ctx context.Context, classID, subjectI
 	return nil, sql.ErrNoRows
 }
 
-func (m *mockGradeConfigRepo) Exists(ctx context.Context, classID, subjectID, termID, excludeID string) (bool, error) {
+func (m *mockGradeConfigRepo) Exists(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID, excludeID string) (bool, error) {
 	return false, nil
 }
 
@@ -90,7 +90,7 @@ func TestGradeConfigServiceCreate(t *testing.T) {
 		Components: []GradeConfigComponentRequest{{ComponentID: "comp1", Weight: 100}},
 	})
 	require.NoError(t, err)
-	assert.Equal(t, "class", cfg.ClassID)
+	assert.Equal(t, models.ClassID("class"), cfg.ClassID)
 	assert.Len(t, cfg.Components, 1)
 }
 
@@ -106,6 +106,128 @@ func TestGradeConfigServiceCreateInvalidWeights(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGradeConfigServiceSchemeOptions(t *testing.T) {
+	components := &mockComponentReader{components: map[string]*models.GradeComponent{
+		"q1": {ID: "q1", Code: "Q1"}, "q2": {ID: "q2", Code: "Q2"}, "q3": {ID: "q3", Code: "Q3"},
+	}}
+	three := []GradeConfigComponentRequest{{ComponentID: "q1"}, {ComponentID: "q2"}, {ComponentID: "q3"}}
+
+	tests := []struct {
+		name    string
+		scheme  models.GradeCalculationScheme
+		options GradeSchemeOptions
+		wantErr bool
+	}{
+		{name: "drop lowest", scheme: models.GradeSchemeDropLowest, options: GradeSchemeOptions{DropLowest: 1}},
+		{name: "drop lowest missing count", scheme: models.GradeSchemeDropLowest, wantErr: true},
+		{name: "drop lowest counts entries", scheme: models.GradeSchemeDropLowest, options: GradeSchemeOptions{DropLowest: 3}},
+		{name: "best n", scheme: models.GradeSchemeBestN, options: GradeSchemeOptions{BestN: 2}},
+		{name: "best n missing count", scheme: models.GradeSchemeBestN, wantErr: true},
+		{name: "best n on average scheme", scheme: models.GradeSchemeAverage, options: GradeSchemeOptions{BestN: 2}, wantErr: true},
+		{name: "negative option", scheme: models.GradeSchemeDropLowest, options: GradeSchemeOptions{DropLowest: -1}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewGradeConfigService(&mockGradeConfigRepo{}, components, validator.New(), zap.NewNop())
+			cfg, err := svc.Create(context.Background(), CreateGradeConfigRequest{
+				ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: tc.scheme,
+				GradeSchemeOptions: tc.options,
+				Components:         three,
+			})
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.options.DropLowest, cfg.DropLowest)
+			assert.Equal(t, tc.options.BestN, cfg.BestN)
+		})
+	}
+}
+
+func TestGradeConfigServiceRoundingOptions(t *testing.T) {
+	repo := &mockGradeConfigRepo{}
+	components := &mockComponentReader{components: map[string]*models.GradeComponent{"comp1": {ID: "comp1", Code: "TST", Name: "Test"}}}
+	svc := NewGradeConfigService(repo, components, validator.New(), zap.NewNop())
+	req := CreateGradeConfigRequest{
+		ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeAverage,
+		Components: []GradeConfigComponentRequest{{ComponentID: "comp1"}},
+	}
+
+	cfg, err := svc.Create(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, models.GradeRoundingHalfEven, cfg.RoundingMode)
+	assert.Equal(t, 2, cfg.RoundingPlaces)
+	assert.False(t, cfg.RoundToInteger)
+
+	integer := true
+	cfg, err = svc.Update(context.Background(), "cfg1", UpdateGradeConfigRequest{
+		CalculationScheme:    models.GradeSchemeAverage,
+		GradeRoundingOptions: GradeRoundingOptions{RoundingMode: models.GradeRoundingHalfUp, RoundToInteger: &integer},
+		Components:           []GradeConfigComponentRequest{{ComponentID: "comp1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.GradeRoundingHalfUp, cfg.RoundingMode)
+	assert.Equal(t, 2, cfg.RoundingPlaces, "omitted options keep their value")
+	assert.True(t, cfg.RoundToInteger)
+
+	req.ClassID = "other"
+	req.GradeRoundingOptions = GradeRoundingOptions{RoundingMode: "CEILING"}
+	_, err = svc.Create(context.Background(), req)
+	require.Error(t, err)
+
+	places := 7
+	req.GradeRoundingOptions = GradeRoundingOptions{RoundingPlaces: &places}
+	_, err = svc.Create(context.Background(), req)
+	require.Error(t, err)
+}
+
+func TestGradeConfigServiceComponentMinGrades(t *testing.T) {
+	repo := &mockGradeConfigRepo{}
+	components := &mockComponentReader{components: map[string]*models.GradeComponent{
+		"quiz": {ID: "quiz", Code: "QUIZ", Name: "Quiz"},
+		"exam": {ID: "exam", Code: "UAS", Name: "Exam"},
+	}}
+	svc := NewGradeConfigService(repo, components, validator.New(), zap.NewNop())
+	req := CreateGradeConfigRequest{
+		ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeAverage,
+		Components: []GradeConfigComponentRequest{{ComponentID: "quiz", MinGrades: 3}, {ComponentID: "exam"}},
+	}
+
+	cfg, err := svc.Create(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, cfg.Components, 2)
+	assert.Equal(t, 3, cfg.Components[0].MinGrades, "the minimum belongs to this config's component")
+	assert.Equal(t, 0, cfg.Components[1].MinGrades)
+
+	req.ClassID = "other"
+	req.Components[0].MinGrades = -1
+	_, err = svc.Create(context.Background(), req)
+	require.Error(t, err)
+}
+
+func TestGradeConfigServiceClearKKM(t *testing.T) {
+	kkm := 75.0
+	repo := &mockGradeConfigRepo{configs: map[string]*models.GradeConfig{"cfg": {ID: "cfg", ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeAverage, KKM: &kkm}}}
+	components := &mockComponentReader{components: map[string]*models.GradeComponent{"comp1": {ID: "comp1", Code: "TST", Name: "Test"}}}
+	svc := NewGradeConfigService(repo, components, validator.New(), zap.NewNop())
+	req := UpdateGradeConfigRequest{CalculationScheme: models.GradeSchemeAverage, Components: []GradeConfigComponentRequest{{ComponentID: "comp1"}}}
+
+	cfg, err := svc.Update(context.Background(), "cfg", req)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.KKM, "an omitted kkm keeps the current one")
+	assert.Equal(t, 75.0, *cfg.KKM)
+
+	req.ClearKKM = true
+	cfg, err = svc.Update(context.Background(), "cfg", req)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.KKM)
+
+	req.KKM = &kkm
+	_, err = svc.Update(context.Background(), "cfg", req)
+	assert.Error(t, err, "kkm and clear_kkm are exclusive")
+}
+
 func TestGradeConfigServiceFinalize(t *testing.T) {
 	repo := &mockGradeConfigRepo{configs: map[string]*models.GradeConfig{"cfg": {ID: "cfg", ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeAverage}}}
 	components := &mockComponentReader{components: map[string]*models.GradeComponent{"comp1": {ID: "comp1", Code: "TST", Name: "Test"}}}
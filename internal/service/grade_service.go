@@ -3,15 +3,20 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -23,12 +28,13 @@ type gradeRepo interface {
 }
 
 type gradeFinalRepo interface {
-	Upsert(ctx context.Context, finals []models.GradeFinal) error
-	SetFinalized(ctx context.Context, enrollmentIDs []string, subjectID string, finalized bool) error
+	Upsert(ctx context.Context, exec sqlx.ExtContext, finals []models.GradeFinal) error
+	SetFinalized(ctx context.Context, exec sqlx.ExtContext, enrollmentIDs []string, subjectID string, finalized bool) error
+	RecordRemedial(ctx context.Context, id string, grade float64, at time.Time) error
 	FetchByEnrollments(ctx context.Context, enrollmentIDs []string, subjectID string) (map[string]models.GradeFinal, error)
-	ReportCard(ctx context.Context, studentID, termID string) ([]models.GradeReportSubject, error)
-	ClassReportRows(ctx context.Context, classID, subjectID, termID string) ([]models.GradeFinalReportRow, error)
-	ClassDistribution(ctx context.Context, classID, subjectID, termID string) (*models.ClassGradeDistribution, error)
+	ReportCard(ctx context.Context, studentID models.StudentID, termID models.TermID) ([]models.GradeReportSubject, error)
+	ClassReportRows(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) ([]models.GradeFinalReportRow, error)
+	ClassDistribution(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.ClassGradeDistribution, error)
 }
 
 type enrollmentReader interface {
@@ -37,7 +43,15 @@ type enrollmentReader interface {
 }
 
 type gradeConfigReader interface {
-	FindByScope(ctx context.Context, classID, subjectID, termID string) (*models.GradeConfig, error)
+	FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error)
+}
+
+type classSubjectLister interface {
+	ListByClass(ctx context.Context, classID string) ([]models.ClassSubjectAssignment, error)
+}
+
+type gradeMutationRequester interface {
+	RequestChange(ctx context.Context, req dto.CreateMutationRequest, userID string) (*models.Mutation, error)
 }
 
 type gradeComponentFetcher interface {
@@ -45,12 +59,14 @@ type gradeComponentFetcher interface {
 	FindByID(ctx context.Context, id string) (*models.GradeComponent, error)
 }
 
-// UpsertGradeRequest represents a single grade entry payload.
+// UpsertGradeRequest represents a single grade entry payload. Entry numbers the student's grades
+// within the component and defaults to 1.
 type UpsertGradeRequest struct {
 	EnrollmentID  string  `json:"enrollment_id" validate:"required"`
 	SubjectID     string  `json:"subject_id" validate:"required"`
 	ComponentID   string  `json:"component_id"`
 	ComponentCode string  `json:"component_code"`
+	Entry         int     `json:"entry" validate:"gte=0"`
 	GradeValue    float64 `json:"grade_value" validate:"required"`
 }
 
@@ -59,6 +75,7 @@ type BulkGradeItem struct {
 	EnrollmentID  string  `json:"enrollment_id" validate:"required"`
 	ComponentID   string  `json:"component_id"`
 	ComponentCode string  `json:"component_code"`
+	Entry         int     `json:"entry" validate:"gte=0"`
 	GradeValue    float64 `json:"grade_value" validate:"required"`
 }
 
@@ -91,27 +108,126 @@ type FinalizeGradesRequest struct {
 	TermID    string `json:"term_id" validate:"required"`
 }
 
+// FinalizeClassRequest finalizes every subject of a class for a term.
+type FinalizeClassRequest struct {
+	ClassID string `json:"class_id" validate:"required"`
+	TermID  string `json:"term_id" validate:"required"`
+}
+
+// Statuses reported per subject by FinalizeClass.
+const (
+	FinalizeSubjectFinalized = "finalized"
+	FinalizeSubjectSkipped   = "skipped"
+)
+
+// FinalizeSubjectResult reports the outcome for one subject of a class finalize. Finals counts the
+// final grades locked by this request, BelowKKM how many of them fell under the subject's KKM;
+// finals locked earlier are left as they are.
+type FinalizeSubjectResult struct {
+	SubjectID   string `json:"subject_id"`
+	SubjectName string `json:"subject_name"`
+	Status      string `json:"status"`
+	Finals      int    `json:"finals"`
+	BelowKKM    int    `json:"below_kkm"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// FinalizeClassResult summarises a class finalize.
+type FinalizeClassResult struct {
+	ClassID   string                  `json:"class_id"`
+	TermID    string                  `json:"term_id"`
+	Finalized int                     `json:"finalized"`
+	Skipped   int                     `json:"skipped"`
+	Subjects  []FinalizeSubjectResult `json:"subjects"`
+}
+
+// UnfinalizeGradesRequest asks to reopen finalized grades for a class/subject/term scope. When
+// EnrollmentIDs is empty every finalized student in the scope is reopened.
+type UnfinalizeGradesRequest struct {
+	ClassID       string   `json:"class_id" validate:"required"`
+	SubjectID     string   `json:"subject_id" validate:"required"`
+	TermID        string   `json:"term_id" validate:"required"`
+	EnrollmentIDs []string `json:"enrollment_ids"`
+	Reason        string   `json:"reason" validate:"required"`
+}
+
+// RemedialGradeRequest records a remedial score for a finalized grade below KKM.
+type RemedialGradeRequest struct {
+	EnrollmentID string  `json:"enrollment_id" validate:"required"`
+	SubjectID    string  `json:"subject_id" validate:"required"`
+	GradeValue   float64 `json:"grade_value" validate:"gte=0,lte=100"`
+}
+
+// GradeUnfinalizeEntity is the mutation entity of unfinalize requests. The mutation's entity ID is
+// the grade config of the scope.
+const GradeUnfinalizeEntity = "grade_finals"
+
+// gradeUnfinalizeChanges is the requested-changes payload of an unfinalize mutation.
+type gradeUnfinalizeChanges struct {
+	ClassID       string   `json:"class_id"`
+	SubjectID     string   `json:"subject_id"`
+	TermID        string   `json:"term_id"`
+	EnrollmentIDs []string `json:"enrollment_ids"`
+	Finalized     bool     `json:"finalized"`
+}
+
+// GradeServiceOption customises GradeService.
+type GradeServiceOption func(*GradeService)
+
+// WithClassSubjects lets FinalizeClass discover the subjects taught in a class.
+func WithClassSubjects(subjects classSubjectLister) GradeServiceOption {
+	return func(s *GradeService) {
+		s.classSubjects = subjects
+	}
+}
+
+// WithUnfinalizeApproval routes unfinalize requests through the mutation approval workflow.
+func WithUnfinalizeApproval(mutations gradeMutationRequester) GradeServiceOption {
+	return func(s *GradeService) {
+		s.mutations = mutations
+	}
+}
+
+// WithGradeEvents emits grade.finalized for every subject finalized by Finalize or FinalizeClass.
+func WithGradeEvents(events *DomainEvents) GradeServiceOption {
+	return func(s *GradeService) {
+		s.events = events
+	}
+}
+
+// WithTeacherAssignments restricts teachers recording remedial scores to the classes and subjects
+// they teach. Without it only administrators may record them.
+func WithTeacherAssignments(assignments ports.TeacherAssignmentChecker) GradeServiceOption {
+	return func(s *GradeService) {
+		s.assignments = assignments
+	}
+}
+
 // GradeService orchestrates grade entry and calculation flows.
 type GradeService struct {
-	grades       gradeRepo
-	finals       gradeFinalRepo
-	enrollments  enrollmentReader
-	configs      gradeConfigReader
-	components   gradeComponentFetcher
-	validator    *validator.Validate
-	logger       *zap.Logger
-	roundingMode func(float64) float64
+	grades        gradeRepo
+	finals        gradeFinalRepo
+	enrollments   enrollmentReader
+	configs       gradeConfigReader
+	components    gradeComponentFetcher
+	classSubjects classSubjectLister
+	mutations     gradeMutationRequester
+	events        *DomainEvents
+	assignments   ports.TeacherAssignmentChecker
+	validator     *validator.Validate
+	logger        *zap.Logger
+	roundingMode  func(float64) float64
 }
 
 // NewGradeService constructs GradeService.
-func NewGradeService(grades gradeRepo, finals gradeFinalRepo, enrollments enrollmentReader, configs gradeConfigReader, components gradeComponentFetcher, validate *validator.Validate, logger *zap.Logger) *GradeService {
+func NewGradeService(grades gradeRepo, finals gradeFinalRepo, enrollments enrollmentReader, configs gradeConfigReader, components gradeComponentFetcher, validate *validator.Validate, logger *zap.Logger, opts ...GradeServiceOption) *GradeService {
 	if validate == nil {
 		validate = validator.New()
 	}
 	if logger == nil {
 		logger = zap.NewNop()
 	}
-	return &GradeService{
+	svc := &GradeService{
 		grades:       grades,
 		finals:       finals,
 		enrollments:  enrollments,
@@ -121,6 +237,10 @@ func NewGradeService(grades gradeRepo, finals gradeFinalRepo, enrollments enroll
 		logger:       logger,
 		roundingMode: func(v float64) float64 { return math.RoundToEven(v*100) / 100 },
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
 }
 
 // List returns grade entries.
@@ -147,7 +267,7 @@ func (s *GradeService) Upsert(ctx context.Context, req UpsertGradeRequest) (*mod
 		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
 	}
-	config, err := s.configs.FindByScope(ctx, enrollment.ClassID, req.SubjectID, enrollment.TermID)
+	config, err := s.configs.FindByScope(ctx, models.ClassID(enrollment.ClassID), models.SubjectID(req.SubjectID), models.TermID(enrollment.TermID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "grade config missing")
@@ -168,7 +288,7 @@ func (s *GradeService) Upsert(ctx context.Context, req UpsertGradeRequest) (*mod
 	if final, ok := finals[req.EnrollmentID]; ok && final.Finalized {
 		return nil, appErrors.Clone(appErrors.ErrFinalized, "final grade already finalized")
 	}
-	grade := &models.Grade{EnrollmentID: req.EnrollmentID, SubjectID: req.SubjectID, ComponentID: componentID, GradeValue: req.GradeValue}
+	grade := &models.Grade{EnrollmentID: req.EnrollmentID, SubjectID: req.SubjectID, ComponentID: componentID, Entry: gradeEntry(req.Entry), GradeValue: req.GradeValue}
 	if err := s.grades.Upsert(ctx, grade); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to upsert grade")
 	}
@@ -179,10 +299,12 @@ func (s *GradeService) Upsert(ctx context.Context, req UpsertGradeRequest) (*mod
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade")
 	}
-	if len(grades) == 0 {
-		return grade, nil
+	for i := range grades {
+		if grades[i].Entry == grade.Entry {
+			return &grades[i], nil
+		}
 	}
-	return &grades[0], nil
+	return grade, nil
 }
 
 // BulkUpsert handles bulk grade submissions.
@@ -190,7 +312,7 @@ func (s *GradeService) BulkUpsert(ctx context.Context, req BulkGradesRequest) (*
 	if err := s.validator.Struct(req); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid bulk payload")
 	}
-	config, err := s.configs.FindByScope(ctx, req.ClassID, req.SubjectID, req.TermID)
+	config, err := s.configs.FindByScope(ctx, models.ClassID(req.ClassID), models.SubjectID(req.SubjectID), models.TermID(req.TermID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "grade config missing")
@@ -248,7 +370,7 @@ func (s *GradeService) BulkUpsert(ctx context.Context, req BulkGradesRequest) (*
 			result.Failures = append(result.Failures, BulkGradeFailure{EnrollmentID: item.EnrollmentID, Component: componentLabel(item), Reason: "enrollment missing"})
 			continue
 		}
-		grade := models.Grade{EnrollmentID: item.EnrollmentID, SubjectID: req.SubjectID, ComponentID: componentID, GradeValue: item.GradeValue}
+		grade := models.Grade{EnrollmentID: item.EnrollmentID, SubjectID: req.SubjectID, ComponentID: componentID, Entry: gradeEntry(item.Entry), GradeValue: item.GradeValue}
 		if atomic {
 			gradesToUpsert = append(gradesToUpsert, grade)
 		} else {
@@ -280,7 +402,7 @@ func (s *GradeService) Recalculate(ctx context.Context, filter models.FinalGrade
 	if filter.ClassID == "" || filter.SubjectID == "" || filter.TermID == "" {
 		return appErrors.Clone(appErrors.ErrValidation, "class, subject and term required")
 	}
-	config, err := s.configs.FindByScope(ctx, filter.ClassID, filter.SubjectID, filter.TermID)
+	config, err := s.configs.FindByScope(ctx, models.ClassID(filter.ClassID), models.SubjectID(filter.SubjectID), models.TermID(filter.TermID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return appErrors.Clone(appErrors.ErrPreconditionFailed, "grade config missing")
@@ -302,7 +424,7 @@ func (s *GradeService) Finalize(ctx context.Context, req FinalizeGradesRequest)
 	if err := s.validator.Struct(req); err != nil {
 		return appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid finalize payload")
 	}
-	config, err := s.configs.FindByScope(ctx, req.ClassID, req.SubjectID, req.TermID)
+	config, err := s.configs.FindByScope(ctx, models.ClassID(req.ClassID), models.SubjectID(req.SubjectID), models.TermID(req.TermID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return appErrors.Clone(appErrors.ErrPreconditionFailed, "grade config missing")
@@ -316,28 +438,256 @@ func (s *GradeService) Finalize(ctx context.Context, req FinalizeGradesRequest)
 	if err := s.recalculate(ctx, config, enrollments); err != nil {
 		return err
 	}
-	if err := s.finals.SetFinalized(ctx, extractIDs(enrollments), req.SubjectID, true); err != nil {
+	enrollmentIDs := extractIDs(enrollments)
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		if err := s.finals.SetFinalized(ctx, exec, enrollmentIDs, req.SubjectID, true); err != nil {
+			return nil, err
+		}
+		event, err := gradeFinalizedEvent(req.ClassID, req.SubjectID, req.TermID, enrollmentIDs)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.OutboxEvent{event}, nil
+	})
+	if err != nil {
 		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to finalize finals")
 	}
 	return nil
 }
 
+// FinalizeClass recalculates and locks final grades for every subject of a class in one transaction.
+// Subjects without a grade config are skipped and reported rather than failing the whole class.
+func (s *GradeService) FinalizeClass(ctx context.Context, req FinalizeClassRequest) (*FinalizeClassResult, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid finalize payload")
+	}
+	if s.classSubjects == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "class subjects not configured")
+	}
+	subjects, err := s.classSubjects.ListByClass(ctx, req.ClassID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list class subjects")
+	}
+	if len(subjects) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "class has no subjects")
+	}
+	enrollments, err := s.enrollments.ListByClassAndTerm(ctx, req.ClassID, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list enrollments")
+	}
+
+	result := &FinalizeClassResult{ClassID: req.ClassID, TermID: req.TermID, Subjects: make([]FinalizeSubjectResult, 0, len(subjects))}
+	var finals []models.GradeFinal
+	for _, subject := range subjects {
+		outcome := FinalizeSubjectResult{SubjectID: subject.SubjectID, SubjectName: subject.SubjectName}
+		config, err := s.configs.FindByScope(ctx, models.ClassID(req.ClassID), models.SubjectID(subject.SubjectID), models.TermID(req.TermID))
+		if err != nil {
+			if err != sql.ErrNoRows {
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade config")
+			}
+			outcome.Status = FinalizeSubjectSkipped
+			outcome.Reason = "grade config missing"
+			result.Skipped++
+			result.Subjects = append(result.Subjects, outcome)
+			continue
+		}
+		subjectFinals, err := s.computeFinals(ctx, config, enrollments)
+		if err != nil {
+			return nil, err
+		}
+		for i := range subjectFinals {
+			subjectFinals[i].Finalized = true
+		}
+		finals = append(finals, subjectFinals...)
+		outcome.Status = FinalizeSubjectFinalized
+		outcome.Finals = len(subjectFinals)
+		for _, final := range subjectFinals {
+			if final.BelowKKM {
+				outcome.BelowKKM++
+			}
+		}
+		result.Finalized++
+		result.Subjects = append(result.Subjects, outcome)
+	}
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		if err := s.finals.Upsert(ctx, exec, finals); err != nil {
+			return nil, err
+		}
+		bySubject := make(map[string][]string)
+		for _, final := range finals {
+			bySubject[final.SubjectID] = append(bySubject[final.SubjectID], final.EnrollmentID)
+		}
+		events := make([]*models.OutboxEvent, 0, result.Finalized)
+		for _, outcome := range result.Subjects {
+			if outcome.Status != FinalizeSubjectFinalized {
+				continue
+			}
+			event, err := gradeFinalizedEvent(req.ClassID, outcome.SubjectID, req.TermID, bySubject[outcome.SubjectID])
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+		return events, nil
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to finalize finals")
+	}
+	s.logger.Info("class grades finalized",
+		zap.String("class_id", req.ClassID),
+		zap.String("term_id", req.TermID),
+		zap.Int("subjects", result.Finalized),
+		zap.Int("skipped", result.Skipped),
+	)
+	return result, nil
+}
+
+// RequestUnfinalize files a grade correction mutation for the finalized grades in scope. Nothing is
+// reopened until a super admin approves the mutation.
+func (s *GradeService) RequestUnfinalize(ctx context.Context, req UnfinalizeGradesRequest, userID string) (*models.Mutation, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid unfinalize payload")
+	}
+	if s.mutations == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "unfinalize requires the mutation workflow")
+	}
+	config, err := s.configs.FindByScope(ctx, models.ClassID(req.ClassID), models.SubjectID(req.SubjectID), models.TermID(req.TermID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "grade config missing")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade config")
+	}
+	enrollments, err := s.enrollments.ListByClassAndTerm(ctx, req.ClassID, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list enrollments")
+	}
+	enrollmentIDs := extractIDs(enrollments)
+	if len(req.EnrollmentIDs) > 0 {
+		inScope := make(map[string]bool, len(enrollmentIDs))
+		for _, id := range enrollmentIDs {
+			inScope[id] = true
+		}
+		for _, id := range req.EnrollmentIDs {
+			if !inScope[id] {
+				return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("enrollment %s not in scope", id))
+			}
+		}
+		enrollmentIDs = req.EnrollmentIDs
+	}
+	finals, err := s.finals.FetchByEnrollments(ctx, enrollmentIDs, req.SubjectID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch finals")
+	}
+	changes := gradeUnfinalizeChanges{ClassID: req.ClassID, SubjectID: req.SubjectID, TermID: req.TermID, Finalized: false}
+	for _, id := range enrollmentIDs {
+		if final, ok := finals[id]; ok && final.Finalized {
+			changes.EnrollmentIDs = append(changes.EnrollmentIDs, id)
+		}
+	}
+	if len(changes.EnrollmentIDs) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "no finalized grades in scope")
+	}
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to encode unfinalize request")
+	}
+	return s.mutations.RequestChange(ctx, dto.CreateMutationRequest{
+		Type:             models.MutationTypeGradeCorrection,
+		Entity:           GradeUnfinalizeEntity,
+		EntityID:         config.ID,
+		Reason:           req.Reason,
+		RequestedChanges: payload,
+	}, userID)
+}
+
+// RecordRemedial stores a remedial score for a finalized final grade that fell below KKM. The final
+// grade itself is kept; the report card shows the remedial outcome next to it. Teachers may only
+// record scores for the subjects they teach in the enrollment's class.
+func (s *GradeService) RecordRemedial(ctx context.Context, req RemedialGradeRequest, claims *models.JWTClaims) (*models.GradeFinal, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid remedial payload")
+	}
+	enrollment, err := s.enrollments.FindByID(ctx, req.EnrollmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "enrollment not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+	}
+	if err := s.ensureTeaches(ctx, claims, enrollment.ClassID, req.SubjectID, enrollment.TermID); err != nil {
+		return nil, err
+	}
+	finals, err := s.finals.FetchByEnrollments(ctx, []string{req.EnrollmentID}, req.SubjectID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch finals")
+	}
+	final, ok := finals[req.EnrollmentID]
+	if !ok {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "final grade not found")
+	}
+	if !final.Finalized {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "final grade not finalized")
+	}
+	if !final.BelowKKM {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "final grade meets KKM")
+	}
+	now := time.Now().UTC()
+	if err := s.finals.RecordRemedial(ctx, final.ID, req.GradeValue, now); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "final grade not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record remedial grade")
+	}
+	final.RemedialGrade = &req.GradeValue
+	final.RemedialAt = &now
+	return &final, nil
+}
+
+func (s *GradeService) ensureTeaches(ctx context.Context, claims *models.JWTClaims, classID, subjectID, termID string) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	switch claims.Role {
+	case models.RoleAdmin, models.RoleSuperAdmin:
+		return nil
+	case models.RoleTeacher:
+		if s.assignments == nil {
+			return appErrors.ErrForbidden
+		}
+		ok, err := s.assignments.Exists(ctx, claims.UserID, classID, subjectID, termID)
+		if err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify teaching assignment")
+		}
+		if !ok {
+			return appErrors.Clone(appErrors.ErrForbidden, "you are not assigned to teach this subject in this class")
+		}
+		return nil
+	default:
+		return appErrors.ErrForbidden
+	}
+}
+
 // ReportCard returns student report card.
 func (s *GradeService) ReportCard(ctx context.Context, studentID, termID string) (*models.StudentReportCard, error) {
-	subjects, err := s.finals.ReportCard(ctx, studentID, termID)
+	subjects, err := s.finals.ReportCard(ctx, models.StudentID(studentID), models.TermID(termID))
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load report card")
 	}
+	for i := range subjects {
+		subjects[i].Remedial = remedialStatus(subjects[i])
+	}
 	return &models.StudentReportCard{StudentID: studentID, TermID: termID, Subjects: subjects}, nil
 }
 
 // ClassReport returns aggregated class grade report.
 func (s *GradeService) ClassReport(ctx context.Context, classID, subjectID, termID string) (*models.ClassGradeReport, error) {
-	rows, err := s.finals.ClassReportRows(ctx, classID, subjectID, termID)
+	class, subject, term := models.ClassID(classID), models.SubjectID(subjectID), models.TermID(termID)
+	rows, err := s.finals.ClassReportRows(ctx, class, subject, term)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class grades")
 	}
-	distribution, err := s.finals.ClassDistribution(ctx, classID, subjectID, termID)
+	distribution, err := s.finals.ClassDistribution(ctx, class, subject, term)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to aggregate class grades")
 	}
@@ -374,18 +724,47 @@ func (s *GradeService) resolveComponent(ctx context.Context, config *models.Grad
 	return "", appErrors.Clone(appErrors.ErrValidation, "component not part of configuration")
 }
 
+// gradeFinalizedEvent describes one finalized subject of a class. Events are keyed by class so a
+// class's finalizations stay in order on partitioned brokers.
+func gradeFinalizedEvent(classID, subjectID, termID string, enrollmentIDs []string) (*models.OutboxEvent, error) {
+	if enrollmentIDs == nil {
+		enrollmentIDs = []string{}
+	}
+	return newDomainEvent(models.DomainEventGradeFinalized, classID, models.GradeFinalizedEvent{
+		ClassID:       classID,
+		SubjectID:     subjectID,
+		TermID:        termID,
+		EnrollmentIDs: enrollmentIDs,
+	})
+}
+
 func (s *GradeService) recalculate(ctx context.Context, config *models.GradeConfig, enrollments []models.Enrollment) error {
-	if len(enrollments) == 0 {
+	finals, err := s.computeFinals(ctx, config, enrollments)
+	if err != nil {
+		return err
+	}
+	if len(finals) == 0 {
 		return nil
 	}
+	if err := s.finals.Upsert(ctx, nil, finals); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to upsert final grades")
+	}
+	return nil
+}
+
+// computeFinals calculates final grades for enrollments whose final is not yet locked.
+func (s *GradeService) computeFinals(ctx context.Context, config *models.GradeConfig, enrollments []models.Enrollment) ([]models.GradeFinal, error) {
+	if len(enrollments) == 0 {
+		return nil, nil
+	}
 	enrollmentIDs := extractIDs(enrollments)
-	grades, err := s.grades.FetchByEnrollments(ctx, enrollmentIDs, config.SubjectID)
+	grades, err := s.grades.FetchByEnrollments(ctx, enrollmentIDs, config.SubjectID.String())
 	if err != nil {
-		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch grades")
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch grades")
 	}
-	existingFinals, err := s.finals.FetchByEnrollments(ctx, enrollmentIDs, config.SubjectID)
+	existingFinals, err := s.finals.FetchByEnrollments(ctx, enrollmentIDs, config.SubjectID.String())
 	if err != nil {
-		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch finals")
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to fetch finals")
 	}
 	finals := make([]models.GradeFinal, 0, len(enrollments))
 	for _, enrollment := range enrollments {
@@ -397,58 +776,178 @@ func (s *GradeService) recalculate(ctx context.Context, config *models.GradeConf
 		finals = append(finals, models.GradeFinal{
 			ID:              final.ID,
 			EnrollmentID:    enrollment.ID,
-			SubjectID:       config.SubjectID,
+			SubjectID:       config.SubjectID.String(),
 			FinalGrade:      calculated,
 			Finalized:       false,
 			CalculatedAt:    time.Now().UTC(),
 			CalculationNote: note,
+			BelowKKM:        config.KKM != nil && calculated < *config.KKM,
 		})
 	}
-	if len(finals) == 0 {
-		return nil
-	}
-	if err := s.finals.Upsert(ctx, finals); err != nil {
-		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to upsert final grades")
-	}
-	return nil
+	return finals, nil
 }
 
 func (s *GradeService) calculateFinal(config *models.GradeConfig, grades []models.Grade) (float64, string) {
 	if len(grades) == 0 {
 		return 0, "no grades recorded"
 	}
+	entries := componentEntries(config, grades)
+	for _, comp := range config.Components {
+		if comp.MinGrades > 0 && len(entries[comp.ComponentID]) < comp.MinGrades {
+			return 0, fmt.Sprintf("needs at least %d grades in %s", comp.MinGrades, configComponentLabel(comp))
+		}
+	}
 	switch config.CalculationScheme {
 	case models.GradeSchemeWeighted:
-		weightMap := make(map[string]float64, len(config.Components))
-		for _, comp := range config.Components {
-			weightMap[comp.ComponentID] = comp.Weight
-		}
 		totalWeight := 0.0
 		sum := 0.0
-		for _, grade := range grades {
-			weight, ok := weightMap[grade.ComponentID]
-			if !ok {
+		for _, comp := range config.Components {
+			values := entries[comp.ComponentID]
+			if len(values) == 0 {
 				continue
 			}
-			totalWeight += weight
-			sum += grade.GradeValue * weight
+			totalWeight += comp.Weight
+			sum += mean(values) * comp.Weight
 		}
 		if totalWeight == 0 {
 			return 0, "weights missing"
 		}
-		return s.roundingMode(sum / 100), "weighted"
+		return s.round(config, sum/100), "weighted"
 	case models.GradeSchemeAverage:
-		sum := 0.0
-		for _, grade := range grades {
-			sum += grade.GradeValue
+		score, ok := combineComponents(config, entries, false, mean)
+		if !ok {
+			return 0, "no configured components graded"
 		}
-		avg := sum / float64(len(grades))
-		return s.roundingMode(avg), "average"
+		return s.round(config, score), "average"
+	case models.GradeSchemeDropLowest:
+		score, ok := combineComponents(config, entries, true, func(values []float64) float64 {
+			sort.Float64s(values)
+			drop := min(max(config.DropLowest, 0), len(values)-1)
+			return mean(values[drop:])
+		})
+		if !ok {
+			return 0, "no configured components graded"
+		}
+		return s.round(config, score), fmt.Sprintf("average after dropping lowest %d per component", config.DropLowest)
+	case models.GradeSchemeBestN:
+		score, ok := combineComponents(config, entries, true, func(values []float64) float64 {
+			sort.Sort(sort.Reverse(sort.Float64Slice(values)))
+			n := len(values)
+			if config.BestN > 0 && config.BestN < n {
+				n = config.BestN
+			}
+			return mean(values[:n])
+		})
+		if !ok {
+			return 0, "no configured components graded"
+		}
+		return s.round(config, score), fmt.Sprintf("best %d per component", config.BestN)
 	default:
 		return 0, "scheme unsupported"
 	}
 }
 
+// round applies the config's rounding policy. Configs without a policy keep the historical banker's
+// rounding to 2 decimals.
+func (s *GradeService) round(config *models.GradeConfig, value float64) float64 {
+	if config.RoundingMode == "" {
+		return s.roundingMode(value)
+	}
+	if config.RoundToInteger {
+		return roundGrade(value, config.RoundingMode, 0)
+	}
+	return roundGrade(value, config.RoundingMode, config.RoundingPlaces)
+}
+
+// roundGrade rounds value to places decimals. The scaled value is first trimmed to 6 decimals so
+// binary representation error (84.445 is stored as 84.44499...) does not decide the tie.
+func roundGrade(value float64, mode models.GradeRoundingMode, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	scaled := math.Round(value*scale*1e6) / 1e6
+	if mode == models.GradeRoundingHalfUp {
+		return math.Floor(scaled+0.5) / scale
+	}
+	return math.RoundToEven(scaled) / scale
+}
+
+// componentEntries groups the values of grades recorded for the config's components by component.
+func componentEntries(config *models.GradeConfig, grades []models.Grade) map[string][]float64 {
+	entries := make(map[string][]float64, len(config.Components))
+	for _, comp := range config.Components {
+		entries[comp.ComponentID] = nil
+	}
+	for _, grade := range grades {
+		if values, ok := entries[grade.ComponentID]; ok {
+			entries[grade.ComponentID] = append(values, grade.GradeValue)
+		}
+	}
+	return entries
+}
+
+// combineComponents scores every graded component with score and combines the results, by component
+// weight when byWeight is set and equally otherwise. Components are also weighted equally when none
+// of the graded ones carries a weight. It reports false when no configured component has grades.
+func combineComponents(config *models.GradeConfig, entries map[string][]float64, byWeight bool, score func([]float64) float64) (float64, bool) {
+	var scores, weights []float64
+	totalWeight := 0.0
+	for _, comp := range config.Components {
+		values := entries[comp.ComponentID]
+		if len(values) == 0 {
+			continue
+		}
+		scores = append(scores, score(values))
+		weights = append(weights, comp.Weight)
+		totalWeight += comp.Weight
+	}
+	if len(scores) == 0 {
+		return 0, false
+	}
+	if !byWeight || totalWeight == 0 {
+		return mean(scores), true
+	}
+	sum := 0.0
+	for i, value := range scores {
+		sum += value * weights[i]
+	}
+	return sum / totalWeight, true
+}
+
+func configComponentLabel(comp models.GradeConfigComponent) string {
+	if comp.ComponentCode != "" {
+		return comp.ComponentCode
+	}
+	return comp.ComponentID
+}
+
+func remedialStatus(subject models.GradeReportSubject) models.RemedialStatus {
+	switch {
+	case !subject.BelowKKM:
+		return ""
+	case subject.RemedialGrade == nil:
+		return models.RemedialPending
+	case subject.KKM != nil && *subject.RemedialGrade >= *subject.KKM:
+		return models.RemedialPassed
+	default:
+		return models.RemedialFailed
+	}
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// gradeEntry defaults an omitted entry number to the component's first entry.
+func gradeEntry(entry int) int {
+	if entry < 1 {
+		return 1
+	}
+	return entry
+}
+
 func componentLabel(item BulkGradeItem) string {
 	if item.ComponentCode != "" {
 		return item.ComponentCode
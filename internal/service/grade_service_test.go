@@ -3,14 +3,20 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type mockGradeRepo struct {
@@ -38,7 +44,7 @@ func (m *mockGradeRepo) Upsert(ctx context.Context, grade *models.Grade) error {
 	if m.storedGrades == nil {
 		m.storedGrades = make(map[string]models.Grade)
 	}
-	key := grade.EnrollmentID + grade.ComponentID
+	key := fmt.Sprintf("%s/%s/%d", grade.EnrollmentID, grade.ComponentID, grade.Entry)
 	m.storedGrades[key] = *grade
 	return nil
 }
@@ -64,19 +70,24 @@ func (m *mockGradeRepo) FetchByEnrollments(ctx context.Context, enrollmentIDs []
 type mockGradeFinalRepo struct {
 	finals      map[string]models.GradeFinal
 	finalizedID []string
+	upserts     [][]models.GradeFinal
 }
 
-func (m *mockGradeFinalRepo) Upsert(ctx context.Context, finals []models.GradeFinal) error {
+func (m *mockGradeFinalRepo) Upsert(ctx context.Context, exec sqlx.ExtContext, finals []models.GradeFinal) error {
+	m.upserts = append(m.upserts, finals)
 	if m.finals == nil {
 		m.finals = make(map[string]models.GradeFinal)
 	}
 	for _, final := range finals {
+		if final.ID == "" {
+			final.ID = "final-" + final.EnrollmentID
+		}
 		m.finals[final.EnrollmentID] = final
 	}
 	return nil
 }
 
-func (m *mockGradeFinalRepo) SetFinalized(ctx context.Context, enrollmentIDs []string, subjectID string, finalized bool) error {
+func (m *mockGradeFinalRepo) SetFinalized(ctx context.Context, exec sqlx.ExtContext, enrollmentIDs []string, subjectID string, finalized bool) error {
 	m.finalizedID = append(m.finalizedID, enrollmentIDs...)
 	for _, id := range enrollmentIDs {
 		if final, ok := m.finals[id]; ok {
@@ -87,6 +98,18 @@ func (m *mockGradeFinalRepo) SetFinalized(ctx context.Context, enrollmentIDs []s
 	return nil
 }
 
+func (m *mockGradeFinalRepo) RecordRemedial(ctx context.Context, id string, grade float64, at time.Time) error {
+	for enrollmentID, final := range m.finals {
+		if final.ID == id {
+			final.RemedialGrade = &grade
+			final.RemedialAt = &at
+			m.finals[enrollmentID] = final
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (m *mockGradeFinalRepo) FetchByEnrollments(ctx context.Context, enrollmentIDs []string, subjectID string) (map[string]models.GradeFinal, error) {
 	finals := make(map[string]models.GradeFinal)
 	for _, id := range enrollmentIDs {
@@ -97,16 +120,16 @@ func (m *mockGradeFinalRepo) FetchByEnrollments(ctx context.Context, enrollmentI
 	return finals, nil
 }
 
-func (m *mockGradeFinalRepo) ReportCard(ctx context.Context, studentID, termID string) ([]models.GradeReportSubject, error) {
+func (m *mockGradeFinalRepo) ReportCard(ctx context.Context, studentID models.StudentID, termID models.TermID) ([]models.GradeReportSubject, error) {
 	return []models.GradeReportSubject{{SubjectID: "sub", SubjectName: "Subject", FinalGrade: ptrFloat(80)}}, nil
 }
 
-func (m *mockGradeFinalRepo) ClassReportRows(ctx context.Context, classID, subjectID, termID string) ([]models.GradeFinalReportRow, error) {
+func (m *mockGradeFinalRepo) ClassReportRows(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) ([]models.GradeFinalReportRow, error) {
 	return []models.GradeFinalReportRow{{StudentID: "stu", StudentName: "Student", FinalGrade: ptrFloat(90)}}, nil
 }
 
-func (m *mockGradeFinalRepo) ClassDistribution(ctx context.Context, classID, subjectID, termID string) (*models.ClassGradeDistribution, error) {
-	return &models.ClassGradeDistribution{SubjectID: subjectID, TermID: termID, Min: ptrFloat(70), Max: ptrFloat(95), Average: ptrFloat(85)}, nil
+func (m *mockGradeFinalRepo) ClassDistribution(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.ClassGradeDistribution, error) {
+	return &models.ClassGradeDistribution{SubjectID: subjectID.String(), TermID: termID.String(), Min: ptrFloat(70), Max: ptrFloat(95), Average: ptrFloat(85)}, nil
 }
 
 type mockEnrollmentReader struct {
@@ -134,13 +157,37 @@ type mockConfigReader struct {
 	config *models.GradeConfig
 }
 
-func (m *mockConfigReader) FindByScope(ctx context.Context, classID, subjectID, termID string) (*models.GradeConfig, error) {
+func (m *mockConfigReader) FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error) {
 	if m.config != nil && m.config.ClassID == classID && m.config.SubjectID == subjectID && m.config.TermID == termID {
 		return m.config, nil
 	}
 	return nil, sql.ErrNoRows
 }
 
+type scopedConfigReader map[string]*models.GradeConfig
+
+func (m scopedConfigReader) FindByScope(ctx context.Context, classID models.ClassID, subjectID models.SubjectID, termID models.TermID) (*models.GradeConfig, error) {
+	if config, ok := m[classID.String()+"/"+subjectID.String()+"/"+termID.String()]; ok {
+		return config, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+type mockClassSubjects []models.ClassSubjectAssignment
+
+func (m mockClassSubjects) ListByClass(ctx context.Context, classID string) ([]models.ClassSubjectAssignment, error) {
+	return m, nil
+}
+
+type mutationRequesterStub struct {
+	requests []dto.CreateMutationRequest
+}
+
+func (m *mutationRequesterStub) RequestChange(ctx context.Context, req dto.CreateMutationRequest, userID string) (*models.Mutation, error) {
+	m.requests = append(m.requests, req)
+	return &models.Mutation{ID: "mut-1", Type: req.Type, Entity: req.Entity, EntityID: req.EntityID, RequestedChanges: req.RequestedChanges, Status: models.MutationStatusPending, RequestedBy: userID}, nil
+}
+
 type mockComponentFetcher struct {
 	components map[string]*models.GradeComponent
 }
@@ -213,6 +260,279 @@ func TestGradeServiceFinalize(t *testing.T) {
 	assert.Contains(t, finalRepo.finalizedID, "en1")
 }
 
+func TestGradeServiceFinalizeClass(t *testing.T) {
+	gradeRepo := &mockGradeRepo{}
+	finalRepo := &mockGradeFinalRepo{}
+	enrollments := &mockEnrollmentReader{enrollments: map[string]*models.Enrollment{
+		"en1": {ID: "en1", StudentID: "stu1", ClassID: "class", TermID: "term", Status: models.EnrollmentStatusActive},
+	}}
+	configs := scopedConfigReader{
+		"class/math/term": {ID: "cfg-math", ClassID: "class", SubjectID: "math", TermID: "term", CalculationScheme: models.GradeSchemeAverage, Components: []models.GradeConfigComponent{{ComponentID: "comp1"}}},
+		"class/art/term":  {ID: "cfg-art", ClassID: "class", SubjectID: "art", TermID: "term", CalculationScheme: models.GradeSchemeAverage},
+	}
+	subjects := mockClassSubjects{
+		{ClassSubject: models.ClassSubject{ClassID: "class", SubjectID: "art"}, SubjectName: "Art"},
+		{ClassSubject: models.ClassSubject{ClassID: "class", SubjectID: "bio"}, SubjectName: "Biology"},
+		{ClassSubject: models.ClassSubject{ClassID: "class", SubjectID: "math"}, SubjectName: "Math"},
+	}
+	svc := NewGradeService(gradeRepo, finalRepo, enrollments, configs, &mockComponentFetcher{}, validator.New(), zap.NewNop(), WithClassSubjects(subjects))
+
+	gradeRepo.Upsert(context.Background(), &models.Grade{EnrollmentID: "en1", SubjectID: "math", ComponentID: "comp1", GradeValue: 70})
+	result, err := svc.FinalizeClass(context.Background(), FinalizeClassRequest{ClassID: "class", TermID: "term"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Finalized)
+	assert.Equal(t, 1, result.Skipped)
+	require.Len(t, result.Subjects, 3)
+	assert.Equal(t, FinalizeSubjectResult{SubjectID: "bio", SubjectName: "Biology", Status: FinalizeSubjectSkipped, Reason: "grade config missing"}, result.Subjects[1])
+	assert.Equal(t, FinalizeSubjectFinalized, result.Subjects[2].Status)
+	assert.Equal(t, 1, result.Subjects[2].Finals)
+
+	require.Len(t, finalRepo.upserts, 1, "every subject is written in a single transaction")
+	require.Len(t, finalRepo.upserts[0], 2)
+	for _, final := range finalRepo.upserts[0] {
+		assert.True(t, final.Finalized)
+	}
+}
+
+func TestGradeServiceFinalizeClassRequiresSubjects(t *testing.T) {
+	svc := NewGradeService(&mockGradeRepo{}, &mockGradeFinalRepo{}, &mockEnrollmentReader{}, scopedConfigReader{}, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+	_, err := svc.FinalizeClass(context.Background(), FinalizeClassRequest{ClassID: "class", TermID: "term"})
+	assert.Equal(t, appErrors.ErrInternal.Code, appErrors.FromError(err).Code)
+
+	svc = NewGradeService(&mockGradeRepo{}, &mockGradeFinalRepo{}, &mockEnrollmentReader{}, scopedConfigReader{}, &mockComponentFetcher{}, validator.New(), zap.NewNop(), WithClassSubjects(mockClassSubjects{}))
+	_, err = svc.FinalizeClass(context.Background(), FinalizeClassRequest{ClassID: "class", TermID: "term"})
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.FinalizeClass(context.Background(), FinalizeClassRequest{ClassID: "class"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestGradeServiceRequestUnfinalize(t *testing.T) {
+	finalRepo := &mockGradeFinalRepo{finals: map[string]models.GradeFinal{
+		"en1": {EnrollmentID: "en1", SubjectID: "sub", Finalized: true},
+		"en2": {EnrollmentID: "en2", SubjectID: "sub", Finalized: false},
+	}}
+	enrollments := &mockEnrollmentReader{enrollments: map[string]*models.Enrollment{
+		"en1": {ID: "en1", ClassID: "class", TermID: "term"},
+		"en2": {ID: "en2", ClassID: "class", TermID: "term"},
+		"en3": {ID: "en3", ClassID: "other", TermID: "term"},
+	}}
+	configReader := &mockConfigReader{config: &models.GradeConfig{ID: "cfg", ClassID: "class", SubjectID: "sub", TermID: "term"}}
+	mutations := &mutationRequesterStub{}
+	svc := NewGradeService(&mockGradeRepo{}, finalRepo, enrollments, configReader, &mockComponentFetcher{}, validator.New(), zap.NewNop(), WithUnfinalizeApproval(mutations))
+	req := UnfinalizeGradesRequest{ClassID: "class", SubjectID: "sub", TermID: "term", Reason: "typo in exam score"}
+
+	mutation, err := svc.RequestUnfinalize(context.Background(), req, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.MutationStatusPending, mutation.Status)
+	require.Len(t, mutations.requests, 1)
+	sent := mutations.requests[0]
+	assert.Equal(t, models.MutationTypeGradeCorrection, sent.Type)
+	assert.Equal(t, GradeUnfinalizeEntity, sent.Entity)
+	assert.Equal(t, "cfg", sent.EntityID)
+	var changes gradeUnfinalizeChanges
+	require.NoError(t, json.Unmarshal(sent.RequestedChanges, &changes))
+	assert.Equal(t, []string{"en1"}, changes.EnrollmentIDs, "only finalized grades are reopened")
+	assert.True(t, finalRepo.finals["en1"].Finalized, "nothing changes before approval")
+
+	req.EnrollmentIDs = []string{"en2"}
+	_, err = svc.RequestUnfinalize(context.Background(), req, "admin-1")
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	req.EnrollmentIDs = []string{"en3"}
+	_, err = svc.RequestUnfinalize(context.Background(), req, "admin-1")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	svc = NewGradeService(&mockGradeRepo{}, finalRepo, enrollments, configReader, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+	req.EnrollmentIDs = nil
+	_, err = svc.RequestUnfinalize(context.Background(), req, "admin-1")
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+}
+
+func TestGradeServiceCalculateFinalSchemes(t *testing.T) {
+	svc := NewGradeService(&mockGradeRepo{}, &mockGradeFinalRepo{}, &mockEnrollmentReader{}, &mockConfigReader{}, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+	weighted := []models.GradeConfigComponent{{ComponentID: "quiz", ComponentCode: "QUIZ", Weight: 40}, {ComponentID: "exam", ComponentCode: "UAS", Weight: 60}}
+	unweighted := []models.GradeConfigComponent{{ComponentID: "quiz", ComponentCode: "QUIZ"}, {ComponentID: "exam", ComponentCode: "UAS"}}
+	minQuizzes := []models.GradeConfigComponent{{ComponentID: "quiz", ComponentCode: "QUIZ", Weight: 40, MinGrades: 3}, {ComponentID: "exam", ComponentCode: "UAS", Weight: 60}}
+	grades := []models.Grade{
+		{ComponentID: "quiz", Entry: 1, GradeValue: 60},
+		{ComponentID: "quiz", Entry: 2, GradeValue: 90},
+		{ComponentID: "quiz", Entry: 3, GradeValue: 70},
+		{ComponentID: "quiz", Entry: 4, GradeValue: 80},
+		{ComponentID: "exam", Entry: 1, GradeValue: 50},
+		{ComponentID: "exam", Entry: 2, GradeValue: 100},
+		{ComponentID: "other", Entry: 1, GradeValue: 10},
+	}
+	quizzes := grades[:4]
+	// Four quizzes averaging 75 and a single exam of 95: the component means average to 85, where
+	// pooling every row would give 79, or 67.5 with the unconfigured component.
+	oneExam := append(append([]models.Grade{}, quizzes...),
+		models.Grade{ComponentID: "exam", Entry: 1, GradeValue: 95},
+		models.Grade{ComponentID: "other", Entry: 1, GradeValue: 10},
+	)
+
+	tests := []struct {
+		name       string
+		config     models.GradeConfig
+		components []models.GradeConfigComponent
+		grades     []models.Grade
+		want       float64
+		note       string
+	}{
+		{name: "drop lowest one per component", config: models.GradeConfig{CalculationScheme: models.GradeSchemeDropLowest, DropLowest: 1}, components: weighted, grades: grades, want: 92, note: "average after dropping lowest 1 per component"},
+		{name: "drop keeps one entry per component", config: models.GradeConfig{CalculationScheme: models.GradeSchemeDropLowest, DropLowest: 2}, components: weighted, grades: grades, want: 94, note: "average after dropping lowest 2 per component"},
+		{name: "drop lowest without weights", config: models.GradeConfig{CalculationScheme: models.GradeSchemeDropLowest, DropLowest: 1}, components: unweighted, grades: grades, want: 90, note: "average after dropping lowest 1 per component"},
+		{name: "drop lowest with one component graded", config: models.GradeConfig{CalculationScheme: models.GradeSchemeDropLowest, DropLowest: 1}, components: weighted, grades: quizzes, want: 80, note: "average after dropping lowest 1 per component"},
+		{name: "best two per component", config: models.GradeConfig{CalculationScheme: models.GradeSchemeBestN, BestN: 2}, components: weighted, grades: grades, want: 79, note: "best 2 per component"},
+		{name: "best one per component", config: models.GradeConfig{CalculationScheme: models.GradeSchemeBestN, BestN: 1}, components: weighted, grades: grades, want: 96, note: "best 1 per component"},
+		{name: "average of component means", config: models.GradeConfig{CalculationScheme: models.GradeSchemeAverage}, components: weighted, grades: oneExam, want: 85, note: "average"},
+		{name: "average without weights", config: models.GradeConfig{CalculationScheme: models.GradeSchemeAverage}, components: unweighted, grades: oneExam, want: 85, note: "average"},
+		{name: "average with one component graded", config: models.GradeConfig{CalculationScheme: models.GradeSchemeAverage}, components: weighted, grades: quizzes, want: 75, note: "average"},
+		{name: "average of unconfigured grades", config: models.GradeConfig{CalculationScheme: models.GradeSchemeAverage}, components: weighted, grades: grades[6:], want: 0, note: "no configured components graded"},
+		{name: "weighted averages entries", config: models.GradeConfig{CalculationScheme: models.GradeSchemeWeighted}, components: weighted, grades: grades, want: 75, note: "weighted"},
+		{name: "component min grades unmet", config: models.GradeConfig{CalculationScheme: models.GradeSchemeBestN, BestN: 2}, components: minQuizzes, grades: append(append([]models.Grade{}, grades[:2]...), grades[4:6]...), want: 0, note: "needs at least 3 grades in QUIZ"},
+		{name: "component min grades met", config: models.GradeConfig{CalculationScheme: models.GradeSchemeBestN, BestN: 2}, components: minQuizzes, grades: grades, want: 79, note: "best 2 per component"},
+		{name: "only unconfigured grades", config: models.GradeConfig{CalculationScheme: models.GradeSchemeBestN, BestN: 2}, components: weighted, grades: grades[6:], want: 0, note: "no configured components graded"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.config
+			config.Components = tc.components
+			got, note := svc.calculateFinal(&config, append([]models.Grade{}, tc.grades...))
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.note, note)
+		})
+	}
+}
+
+func TestGradeServiceUpsertKeepsComponentEntries(t *testing.T) {
+	gradeRepo := &mockGradeRepo{}
+	finalRepo := &mockGradeFinalRepo{}
+	enrollments := &mockEnrollmentReader{enrollments: map[string]*models.Enrollment{"en1": {ID: "en1", ClassID: "class", TermID: "term"}}}
+	config := &models.GradeConfig{ID: "cfg", ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeDropLowest, DropLowest: 1,
+		Components: []models.GradeConfigComponent{{ComponentID: "quiz", ComponentCode: "QUIZ"}}}
+	svc := NewGradeService(gradeRepo, finalRepo, enrollments, &mockConfigReader{config: config}, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+
+	for entry, value := range []float64{60, 80, 90} {
+		grade, err := svc.Upsert(context.Background(), UpsertGradeRequest{EnrollmentID: "en1", SubjectID: "sub", ComponentID: "quiz", Entry: entry + 1, GradeValue: value})
+		require.NoError(t, err)
+		assert.Equal(t, entry+1, grade.Entry)
+	}
+	_, err := svc.Upsert(context.Background(), UpsertGradeRequest{EnrollmentID: "en1", SubjectID: "sub", ComponentID: "quiz", GradeValue: 70})
+	require.NoError(t, err)
+
+	assert.Len(t, gradeRepo.storedGrades, 3, "an omitted entry replaces entry 1")
+	assert.Equal(t, 85.0, finalRepo.finals["en1"].FinalGrade)
+}
+
+func TestRoundGrade(t *testing.T) {
+	tests := []struct {
+		value  float64
+		mode   models.GradeRoundingMode
+		places int
+		want   float64
+	}{
+		{value: 84.445, mode: models.GradeRoundingHalfUp, places: 2, want: 84.45},
+		{value: 84.445, mode: models.GradeRoundingHalfEven, places: 2, want: 84.44},
+		{value: 84.5, mode: models.GradeRoundingHalfUp, places: 0, want: 85},
+		{value: 84.5, mode: models.GradeRoundingHalfEven, places: 0, want: 84},
+		{value: 85.5, mode: models.GradeRoundingHalfEven, places: 0, want: 86},
+		{value: 77.04, mode: models.GradeRoundingHalfUp, places: 1, want: 77},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, roundGrade(tc.value, tc.mode, tc.places), "%v %s %d", tc.value, tc.mode, tc.places)
+	}
+}
+
+func TestGradeServiceCalculateFinalRounding(t *testing.T) {
+	svc := NewGradeService(&mockGradeRepo{}, &mockGradeFinalRepo{}, &mockEnrollmentReader{}, &mockConfigReader{}, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+	grades := []models.Grade{{ComponentID: "a", GradeValue: 84}, {ComponentID: "b", GradeValue: 85}}
+
+	config := &models.GradeConfig{CalculationScheme: models.GradeSchemeAverage, Components: []models.GradeConfigComponent{{ComponentID: "a"}, {ComponentID: "b"}}}
+	got, _ := svc.calculateFinal(config, grades)
+	assert.Equal(t, 84.5, got, "configs without a policy keep 2-decimal banker's rounding")
+
+	config.RoundingMode, config.RoundingPlaces, config.RoundToInteger = models.GradeRoundingHalfUp, 2, true
+	got, _ = svc.calculateFinal(config, grades)
+	assert.Equal(t, 85.0, got)
+
+	config.RoundingMode = models.GradeRoundingHalfEven
+	got, _ = svc.calculateFinal(config, grades)
+	assert.Equal(t, 84.0, got)
+}
+
+func TestGradeServiceRoundToIntegerRoundsOnce(t *testing.T) {
+	svc := NewGradeService(&mockGradeRepo{}, &mockGradeFinalRepo{}, &mockEnrollmentReader{}, &mockConfigReader{}, &mockComponentFetcher{}, validator.New(), zap.NewNop())
+	tests := []struct {
+		value float64
+		mode  models.GradeRoundingMode
+		want  float64
+	}{
+		{value: 84.495, mode: models.GradeRoundingHalfUp, want: 84},
+		{value: 74.4951, mode: models.GradeRoundingHalfUp, want: 74},
+		{value: 84.5, mode: models.GradeRoundingHalfUp, want: 85},
+		{value: 84.4951, mode: models.GradeRoundingHalfEven, want: 84},
+		{value: 85.5, mode: models.GradeRoundingHalfEven, want: 86},
+	}
+	for _, tc := range tests {
+		config := &models.GradeConfig{RoundingMode: tc.mode, RoundingPlaces: 2, RoundToInteger: true}
+		assert.Equal(t, tc.want, svc.round(config, tc.value), "%v %s", tc.value, tc.mode)
+	}
+}
+
+func TestGradeServiceFinalizeFlagsBelowKKM(t *testing.T) {
+	gradeRepo := &mockGradeRepo{}
+	finalRepo := &mockGradeFinalRepo{}
+	enrollments := &mockEnrollmentReader{enrollments: map[string]*models.Enrollment{
+		"en1": {ID: "en1", ClassID: "class", TermID: "term"},
+		"en2": {ID: "en2", ClassID: "class", TermID: "term"},
+	}}
+	kkm := 75.0
+	config := &models.GradeConfig{ID: "cfg", ClassID: "class", SubjectID: "sub", TermID: "term", CalculationScheme: models.GradeSchemeAverage, KKM: &kkm,
+		Components: []models.GradeConfigComponent{{ComponentID: "comp1"}}}
+	assignments := curriculumAssignmentStub{assigned: map[string]bool{"teacher|class|sub|term": true}}
+	svc := NewGradeService(gradeRepo, finalRepo, enrollments, &mockConfigReader{config: config}, &mockComponentFetcher{}, validator.New(), zap.NewNop(), WithTeacherAssignments(assignments))
+	admin := &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin}
+
+	gradeRepo.Upsert(context.Background(), &models.Grade{EnrollmentID: "en1", SubjectID: "sub", ComponentID: "comp1", GradeValue: 80})
+	gradeRepo.Upsert(context.Background(), &models.Grade{EnrollmentID: "en2", SubjectID: "sub", ComponentID: "comp1", GradeValue: 60})
+	require.NoError(t, svc.Finalize(context.Background(), FinalizeGradesRequest{ClassID: "class", SubjectID: "sub", TermID: "term"}))
+	assert.False(t, finalRepo.finals["en1"].BelowKKM)
+	assert.True(t, finalRepo.finals["en2"].BelowKKM)
+
+	_, err := svc.RecordRemedial(context.Background(), RemedialGradeRequest{EnrollmentID: "en1", SubjectID: "sub", GradeValue: 90}, admin)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code, "finals meeting KKM need no remedial")
+
+	final, err := svc.RecordRemedial(context.Background(), RemedialGradeRequest{EnrollmentID: "en2", SubjectID: "sub", GradeValue: 78}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 60.0, final.FinalGrade, "the final grade is kept")
+	require.NotNil(t, final.RemedialGrade)
+	assert.Equal(t, 78.0, *final.RemedialGrade)
+	assert.Equal(t, final.RemedialGrade, finalRepo.finals["en2"].RemedialGrade)
+
+	_, err = svc.RecordRemedial(context.Background(), RemedialGradeRequest{EnrollmentID: "missing", SubjectID: "sub", GradeValue: 78}, admin)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	other := &models.JWTClaims{UserID: "other-teacher", Role: models.RoleTeacher}
+	_, err = svc.RecordRemedial(context.Background(), RemedialGradeRequest{EnrollmentID: "en2", SubjectID: "sub", GradeValue: 95}, other)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code, "teachers of other classes or subjects are refused")
+	assert.Equal(t, 78.0, *finalRepo.finals["en2"].RemedialGrade)
+
+	teacher := &models.JWTClaims{UserID: "teacher", Role: models.RoleTeacher}
+	final, err = svc.RecordRemedial(context.Background(), RemedialGradeRequest{EnrollmentID: "en2", SubjectID: "sub", GradeValue: 80}, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, *final.RemedialGrade)
+}
+
+func TestRemedialStatus(t *testing.T) {
+	kkm, low, high := 75.0, 70.0, 80.0
+	assert.Equal(t, models.RemedialStatus(""), remedialStatus(models.GradeReportSubject{KKM: &kkm}))
+	assert.Equal(t, models.RemedialPending, remedialStatus(models.GradeReportSubject{KKM: &kkm, BelowKKM: true}))
+	assert.Equal(t, models.RemedialFailed, remedialStatus(models.GradeReportSubject{KKM: &kkm, BelowKKM: true, RemedialGrade: &low}))
+	assert.Equal(t, models.RemedialPassed, remedialStatus(models.GradeReportSubject{KKM: &kkm, BelowKKM: true, RemedialGrade: &high}))
+}
+
 func TestGradeServiceReport(t *testing.T) {
 	gradeRepo := &mockGradeRepo{}
 	finalRepo := &mockGradeFinalRepo{finals: make(map[string]models.GradeFinal)}
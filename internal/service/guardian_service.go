@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type guardianLinkStore interface {
+	ListStudents(ctx context.Context, guardianID string) ([]models.GuardianStudent, error)
+	IsLinked(ctx context.Context, guardianID, studentID string) (bool, error)
+	Link(ctx context.Context, guardianID, studentID string, relationship *string) error
+	Unlink(ctx context.Context, guardianID, studentID string) error
+}
+
+type guardianContactStore interface {
+	GetContact(ctx context.Context, guardianID string) (*models.GuardianContact, error)
+	UpsertContact(ctx context.Context, contact *models.GuardianContact) error
+	DeleteContact(ctx context.Context, guardianID string) error
+}
+
+type guardianUserLookup interface {
+	FindByID(ctx context.Context, id string) (*models.User, error)
+}
+
+type guardianEnrollmentReader interface {
+	ListActiveByStudent(ctx context.Context, studentID string) ([]models.Enrollment, error)
+}
+
+type attendanceRosterReader interface {
+	Roster(ctx context.Context, filter repository.AttendanceAliasRosterFilter) ([]repository.AttendanceAliasDayRow, error)
+}
+
+type reportCardProvider interface {
+	ReportCard(ctx context.Context, studentID, termID string) (*models.StudentReportCard, error)
+}
+
+// GuardianServiceParams groups constructor dependencies.
+type GuardianServiceParams struct {
+	Links         guardianLinkStore
+	Contacts      guardianContactStore
+	Users         guardianUserLookup
+	Students      ports.StudentReader
+	Enrollments   guardianEnrollmentReader
+	Terms         ports.ActiveTermReader
+	Attendance    attendanceRosterReader
+	Grades        reportCardProvider
+	Announcements announcementLister
+	Calendar      calendarLister
+	Logger        *zap.Logger
+	// Rates supplies the attendance percentage formula; nil uses the default.
+	Rates *AttendanceRates
+}
+
+// GuardianService serves the read-only guardian portal. Every student-scoped call verifies the
+// guardian is linked to the student before touching other services.
+type GuardianService struct {
+	links         guardianLinkStore
+	contacts      guardianContactStore
+	users         guardianUserLookup
+	students      ports.StudentReader
+	enrollments   guardianEnrollmentReader
+	terms         ports.ActiveTermReader
+	attendance    attendanceRosterReader
+	rates         *AttendanceRates
+	grades        reportCardProvider
+	announcements announcementLister
+	calendar      calendarLister
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewGuardianService constructs the service.
+func NewGuardianService(params GuardianServiceParams) *GuardianService {
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &GuardianService{
+		links:         params.Links,
+		contacts:      params.Contacts,
+		users:         params.Users,
+		students:      params.Students,
+		enrollments:   params.Enrollments,
+		terms:         params.Terms,
+		attendance:    params.Attendance,
+		rates:         params.Rates,
+		grades:        params.Grades,
+		announcements: params.Announcements,
+		calendar:      params.Calendar,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// LinkStudent lets an administrator attach a student to a GUARDIAN user.
+func (s *GuardianService) LinkStudent(ctx context.Context, guardianID string, req dto.LinkGuardianStudentRequest) ([]models.GuardianStudent, error) {
+	studentID := strings.TrimSpace(req.StudentID)
+	if studentID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "studentId is required")
+	}
+	relationship := optionalString(req.Relationship)
+	if relationship != nil && len(*relationship) > 30 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "relationship must be at most 30 characters")
+	}
+	user, err := s.users.FindByID(ctx, guardianID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "user not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load user")
+	}
+	if user.Role != models.RoleGuardian {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "user is not a guardian")
+	}
+	if _, err := s.students.FindByID(ctx, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "student not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+	}
+	if err := s.links.Link(ctx, guardianID, studentID, relationship); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to link student")
+	}
+	return s.ListStudents(ctx, guardianID)
+}
+
+// UnlinkStudent removes a guardian's access to a student.
+func (s *GuardianService) UnlinkStudent(ctx context.Context, guardianID, studentID string) error {
+	if err := s.links.Unlink(ctx, guardianID, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "guardian is not linked to student")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to unlink student")
+	}
+	return nil
+}
+
+// Contact returns the phone a guardian is messaged on.
+func (s *GuardianService) Contact(ctx context.Context, guardianID string) (*models.GuardianContact, error) {
+	contact, err := s.contacts.GetContact(ctx, guardianID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "guardian has no contact")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load guardian contact")
+	}
+	return contact, nil
+}
+
+// SetContact creates or replaces the phone a guardian is messaged on. Local Indonesian numbers are
+// stored in E.164 format.
+func (s *GuardianService) SetContact(ctx context.Context, guardianID string, req dto.GuardianContactRequest) (*models.GuardianContact, error) {
+	phone, ok := normalizePhone(req.Phone)
+	if !ok {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "phone must be a mobile number such as 081234567890 or +6281234567890")
+	}
+	channel := models.MessageChannel(strings.ToUpper(strings.TrimSpace(req.Channel)))
+	switch channel {
+	case "":
+		channel = models.MessageChannelSMS
+	case models.MessageChannelSMS, models.MessageChannelWhatsApp:
+	default:
+		return nil, appErrors.Clone(appErrors.ErrValidation, "channel must be SMS or WHATSAPP")
+	}
+	user, err := s.users.FindByID(ctx, guardianID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "user not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load user")
+	}
+	if user.Role != models.RoleGuardian {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "user is not a guardian")
+	}
+	contact := &models.GuardianContact{GuardianID: guardianID, Phone: phone, Channel: channel, AbsenceAlerts: true}
+	if req.AbsenceAlerts != nil {
+		contact.AbsenceAlerts = *req.AbsenceAlerts
+	}
+	if err := s.contacts.UpsertContact(ctx, contact); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to save guardian contact")
+	}
+	return contact, nil
+}
+
+// DeleteContact forgets a guardian's phone; no further messages are sent to them.
+func (s *GuardianService) DeleteContact(ctx context.Context, guardianID string) error {
+	if err := s.contacts.DeleteContact(ctx, guardianID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "guardian has no contact")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete guardian contact")
+	}
+	return nil
+}
+
+// normalizePhone converts a mobile number to E.164, reading numbers without a country code as
+// Indonesian (0812... becomes +62812...).
+func normalizePhone(raw string) (string, bool) {
+	phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(raw))
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(phone, "0"):
+		phone = "+62" + phone[1:]
+	case strings.HasPrefix(phone, "62"):
+		phone = "+" + phone
+	default:
+		return "", false
+	}
+	if len(phone) < 9 || len(phone) > 16 || phone[1] == '0' {
+		return "", false
+	}
+	for _, r := range phone[1:] {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return phone, true
+}
+
+// ListStudents returns the students a guardian may view.
+func (s *GuardianService) ListStudents(ctx context.Context, guardianID string) ([]models.GuardianStudent, error) {
+	links, err := s.links.ListStudents(ctx, guardianID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load linked students")
+	}
+	if links == nil {
+		links = []models.GuardianStudent{}
+	}
+	return links, nil
+}
+
+// StudentAttendance returns a linked student's attendance history for a term, defaulting to the
+// active term.
+func (s *GuardianService) StudentAttendance(ctx context.Context, guardianID string, req dto.AttendanceStudentRequest) (*dto.AttendanceStudentResponse, error) {
+	if err := s.ensureLinked(ctx, guardianID, req.StudentID); err != nil {
+		return nil, err
+	}
+	termID, err := s.resolveTerm(ctx, req.TermID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.attendance.Roster(ctx, repository.AttendanceAliasRosterFilter{
+		TermID:    termID,
+		StudentID: req.StudentID,
+		DateFrom:  req.StartDate,
+		DateTo:    req.EndDate,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student attendance")
+	}
+	if len(rows) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "student is not enrolled in term")
+	}
+	return studentHistoryFromRows(termID, rows, s.rates.Formula(ctx)), nil
+}
+
+// ReportCard returns a linked student's report card for a term, defaulting to the active term.
+func (s *GuardianService) ReportCard(ctx context.Context, guardianID, studentID, termID string) (*models.StudentReportCard, error) {
+	if err := s.ensureLinked(ctx, guardianID, studentID); err != nil {
+		return nil, err
+	}
+	termID, err := s.resolveTerm(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	return s.grades.ReportCard(ctx, studentID, termID)
+}
+
+// Announcements lists announcements addressed to students, including class announcements for the
+// classes the guardian's children are enrolled in.
+func (s *GuardianService) Announcements(ctx context.Context, guardianID string, page, pageSize int) ([]models.Announcement, *models.Pagination, error) {
+	classIDs, err := s.ChildClassIDs(ctx, guardianID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.announcements.List(ctx, AnnouncementListRequest{
+		AudienceRoles: []models.UserRole{models.RoleGuardian},
+		ClassIDs:      classIDs,
+		Page:          page,
+		PageSize:      pageSize,
+	})
+}
+
+// Calendar lists school-wide, student and child-class events. Without a range it covers the next 30
+// days.
+func (s *GuardianService) Calendar(ctx context.Context, guardianID string, start, end *time.Time) ([]models.CalendarEvent, *models.Pagination, error) {
+	classIDs, err := s.ChildClassIDs(ctx, guardianID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if start == nil {
+		today := s.now().UTC().Truncate(24 * time.Hour)
+		start = &today
+	}
+	if end == nil {
+		until := start.AddDate(0, 0, 30)
+		end = &until
+	}
+	// CLASS events are only requested alongside a class filter; otherwise every class's events leak.
+	audience := []string{string(models.AnnouncementAudienceAll), string(models.AnnouncementAudienceSiswa)}
+	if len(classIDs) > 0 {
+		audience = append(audience, string(models.AnnouncementAudienceClass))
+	}
+	return s.calendar.List(ctx, CalendarListRequest{StartDate: start, EndDate: end, Audience: audience, ClassIDs: classIDs})
+}
+
+// ensureLinked is the ownership check behind every student-scoped guardian endpoint.
+func (s *GuardianService) ensureLinked(ctx context.Context, guardianID, studentID string) error {
+	if strings.TrimSpace(studentID) == "" {
+		return appErrors.Clone(appErrors.ErrValidation, "student id is required")
+	}
+	linked, err := s.links.IsLinked(ctx, guardianID, studentID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify guardian access")
+	}
+	if !linked {
+		return appErrors.Clone(appErrors.ErrForbidden, "student is not linked to this guardian")
+	}
+	return nil
+}
+
+func (s *GuardianService) resolveTerm(ctx context.Context, termID string) (string, error) {
+	if termID != "" {
+		return termID, nil
+	}
+	term, err := s.terms.FindActive(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", appErrors.Clone(appErrors.ErrNotFound, "no active term")
+		}
+		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load active term")
+	}
+	return term.ID, nil
+}
+
+// ChildClassIDs returns the classes the guardian's linked students are actively enrolled in.
+func (s *GuardianService) ChildClassIDs(ctx context.Context, guardianID string) ([]string, error) {
+	links, err := s.links.ListStudents(ctx, guardianID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load linked students")
+	}
+	seen := make(map[string]struct{})
+	var classIDs []string
+	for _, link := range links {
+		enrollments, err := s.enrollments.ListActiveByStudent(ctx, link.StudentID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollments")
+		}
+		for _, enrollment := range enrollments {
+			if _, ok := seen[enrollment.ClassID]; ok {
+				continue
+			}
+			seen[enrollment.ClassID] = struct{}{}
+			classIDs = append(classIDs, enrollment.ClassID)
+		}
+	}
+	return classIDs, nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type guardianLinkStub struct {
+	links map[string][]string
+}
+
+func (s *guardianLinkStub) ListStudents(ctx context.Context, guardianID string) ([]models.GuardianStudent, error) {
+	var result []models.GuardianStudent
+	for _, studentID := range s.links[guardianID] {
+		result = append(result, models.GuardianStudent{GuardianID: guardianID, StudentID: studentID})
+	}
+	return result, nil
+}
+
+func (s *guardianLinkStub) IsLinked(ctx context.Context, guardianID, studentID string) (bool, error) {
+	for _, id := range s.links[guardianID] {
+		if id == studentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *guardianLinkStub) Link(ctx context.Context, guardianID, studentID string, relationship *string) error {
+	s.links[guardianID] = append(s.links[guardianID], studentID)
+	return nil
+}
+
+func (s *guardianLinkStub) Unlink(ctx context.Context, guardianID, studentID string) error {
+	return sql.ErrNoRows
+}
+
+type guardianUserStub struct{}
+
+func (guardianUserStub) FindByID(ctx context.Context, id string) (*models.User, error) {
+	switch id {
+	case "guardian-1", "guardian-2":
+		return &models.User{ID: id, Role: models.RoleGuardian}, nil
+	case "teacher-1":
+		return &models.User{ID: id, Role: models.RoleTeacher}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+type guardianEnrollmentStub struct{}
+
+func (guardianEnrollmentStub) ListActiveByStudent(ctx context.Context, studentID string) ([]models.Enrollment, error) {
+	classes := map[string]string{"student-1": "class-a", "student-2": "class-b"}
+	return []models.Enrollment{{StudentID: studentID, ClassID: classes[studentID]}}, nil
+}
+
+type guardianRosterStub struct {
+	filter repository.AttendanceAliasRosterFilter
+}
+
+func (s *guardianRosterStub) Roster(ctx context.Context, filter repository.AttendanceAliasRosterFilter) ([]repository.AttendanceAliasDayRow, error) {
+	s.filter = filter
+	day := time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)
+	present := string(models.AttendanceStatusPresent)
+	return []repository.AttendanceAliasDayRow{{StudentID: filter.StudentID, StudentName: "Ani", NIS: "1001", Date: &day, Status: &present}}, nil
+}
+
+type guardianGradesStub struct{ calls int }
+
+func (s *guardianGradesStub) ReportCard(ctx context.Context, studentID, termID string) (*models.StudentReportCard, error) {
+	s.calls++
+	return &models.StudentReportCard{StudentID: studentID, TermID: termID}, nil
+}
+
+type guardianAnnouncementStub struct{ req AnnouncementListRequest }
+
+func (s *guardianAnnouncementStub) List(ctx context.Context, req AnnouncementListRequest) ([]models.Announcement, *models.Pagination, error) {
+	s.req = req
+	return nil, &models.Pagination{}, nil
+}
+
+type guardianCalendarStub struct{ req CalendarListRequest }
+
+func (s *guardianCalendarStub) List(ctx context.Context, req CalendarListRequest) ([]models.CalendarEvent, *models.Pagination, error) {
+	s.req = req
+	return nil, &models.Pagination{}, nil
+}
+
+type guardianContactStub struct {
+	contacts map[string]models.GuardianContact
+}
+
+func (s *guardianContactStub) GetContact(ctx context.Context, guardianID string) (*models.GuardianContact, error) {
+	contact, ok := s.contacts[guardianID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &contact, nil
+}
+
+func (s *guardianContactStub) UpsertContact(ctx context.Context, contact *models.GuardianContact) error {
+	s.contacts[contact.GuardianID] = *contact
+	return nil
+}
+
+func (s *guardianContactStub) DeleteContact(ctx context.Context, guardianID string) error {
+	if _, ok := s.contacts[guardianID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(s.contacts, guardianID)
+	return nil
+}
+
+type guardianFixture struct {
+	svc           *GuardianService
+	links         *guardianLinkStub
+	contacts      *guardianContactStub
+	roster        *guardianRosterStub
+	grades        *guardianGradesStub
+	announcements *guardianAnnouncementStub
+	calendar      *guardianCalendarStub
+}
+
+func newGuardianFixture() guardianFixture {
+	f := guardianFixture{
+		links:         &guardianLinkStub{links: map[string][]string{"guardian-1": {"student-1", "student-2"}}},
+		contacts:      &guardianContactStub{contacts: map[string]models.GuardianContact{}},
+		roster:        &guardianRosterStub{},
+		grades:        &guardianGradesStub{},
+		announcements: &guardianAnnouncementStub{},
+		calendar:      &guardianCalendarStub{},
+	}
+	f.svc = NewGuardianService(GuardianServiceParams{
+		Links:         f.links,
+		Contacts:      f.contacts,
+		Users:         guardianUserStub{},
+		Students:      checkinStudentStub{},
+		Enrollments:   guardianEnrollmentStub{},
+		Terms:         warmerTermStub{term: &models.Term{ID: "term-1"}},
+		Attendance:    f.roster,
+		Grades:        f.grades,
+		Announcements: f.announcements,
+		Calendar:      f.calendar,
+		Logger:        zap.NewNop(),
+	})
+	return f
+}
+
+func TestGuardianServiceScopesStudentDataToLinks(t *testing.T) {
+	f := newGuardianFixture()
+	ctx := context.Background()
+
+	history, err := f.svc.StudentAttendance(ctx, "guardian-1", dto.AttendanceStudentRequest{StudentID: "student-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "term-1", f.roster.filter.TermID, "the active term is used by default")
+	assert.Equal(t, 1, history.Summary.Present)
+
+	report, err := f.svc.ReportCard(ctx, "guardian-1", "student-2", "term-0")
+	require.NoError(t, err)
+	assert.Equal(t, "term-0", report.TermID)
+
+	_, err = f.svc.StudentAttendance(ctx, "guardian-2", dto.AttendanceStudentRequest{StudentID: "student-1"})
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = f.svc.ReportCard(ctx, "guardian-2", "student-1", "")
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	assert.Equal(t, 1, f.grades.calls, "unlinked requests never reach the grade service")
+}
+
+func TestGuardianServiceFeedsFollowChildrensClasses(t *testing.T) {
+	f := newGuardianFixture()
+	ctx := context.Background()
+	f.svc.now = func() time.Time { return time.Date(2024, 8, 5, 9, 30, 0, 0, time.UTC) }
+
+	_, _, err := f.svc.Announcements(ctx, "guardian-1", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []models.UserRole{models.RoleGuardian}, f.announcements.req.AudienceRoles)
+	assert.ElementsMatch(t, []string{"class-a", "class-b"}, f.announcements.req.ClassIDs)
+
+	_, _, err = f.svc.Calendar(ctx, "guardian-1", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC), *f.calendar.req.StartDate)
+	assert.Equal(t, time.Date(2024, 9, 4, 0, 0, 0, 0, time.UTC), *f.calendar.req.EndDate)
+	assert.Contains(t, f.calendar.req.Audience, string(models.AnnouncementAudienceClass))
+
+	_, _, err = f.svc.Calendar(ctx, "guardian-2", nil, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, f.calendar.req.Audience, string(models.AnnouncementAudienceClass), "guardians without children never see class events")
+}
+
+func TestGuardianServiceLinkValidation(t *testing.T) {
+	f := newGuardianFixture()
+	ctx := context.Background()
+
+	students, err := f.svc.LinkStudent(ctx, "guardian-2", dto.LinkGuardianStudentRequest{StudentID: "student-1", Relationship: "Ibu"})
+	require.NoError(t, err)
+	require.Len(t, students, 1)
+
+	_, err = f.svc.LinkStudent(ctx, "teacher-1", dto.LinkGuardianStudentRequest{StudentID: "student-1"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	_, err = f.svc.LinkStudent(ctx, "guardian-2", dto.LinkGuardianStudentRequest{StudentID: "missing"})
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(f.svc.UnlinkStudent(ctx, "guardian-2", "student-9")).Code)
+}
+
+func TestGuardianServiceSetContact(t *testing.T) {
+	f := newGuardianFixture()
+	ctx := context.Background()
+
+	contact, err := f.svc.SetContact(ctx, "guardian-1", dto.GuardianContactRequest{Phone: "0812-3456-7890", Channel: "whatsapp"})
+	require.NoError(t, err)
+	assert.Equal(t, "+6281234567890", contact.Phone)
+	assert.Equal(t, models.MessageChannelWhatsApp, contact.Channel)
+	assert.True(t, contact.AbsenceAlerts, "absence messages are on by default")
+
+	optOut := false
+	contact, err = f.svc.SetContact(ctx, "guardian-1", dto.GuardianContactRequest{Phone: "+62 812 3456 7890", AbsenceAlerts: &optOut})
+	require.NoError(t, err)
+	assert.Equal(t, models.MessageChannelSMS, contact.Channel)
+	assert.False(t, f.contacts.contacts["guardian-1"].AbsenceAlerts)
+
+	for _, phone := range []string{"", "12345", "+62abc4567890", "0812"} {
+		_, err = f.svc.SetContact(ctx, "guardian-1", dto.GuardianContactRequest{Phone: phone})
+		assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code, phone)
+	}
+	_, err = f.svc.SetContact(ctx, "teacher-1", dto.GuardianContactRequest{Phone: "081234567890"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	require.NoError(t, f.svc.DeleteContact(ctx, "guardian-1"))
+	_, err = f.svc.Contact(ctx, "guardian-1")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
@@ -27,28 +28,19 @@ type homeroomStore interface {
 	Upsert(ctx context.Context, params repository.HomeroomAssignmentParams) (*string, error)
 }
 
-type homeroomTermReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-	FindActive(ctx context.Context) (*models.Term, error)
-}
-
 type homeroomSubjectFinder interface {
 	FindByCode(ctx context.Context, code string) (*models.Subject, error)
 }
 
-type homeroomClassAccessChecker interface {
-	HasClassAccess(ctx context.Context, teacherID, classID, termID string) (bool, error)
-}
-
 // HomeroomService orchestrates homeroom assignment workflows.
 type HomeroomService struct {
 	repo        homeroomStore
-	classes     classReader
-	terms       homeroomTermReader
+	classes     ports.ClassReader
+	terms       ports.TermResolver
 	teachers    teacherRepository
 	subjects    homeroomSubjectFinder
-	assignments homeroomClassAccessChecker
-	audit       auditLogger
+	assignments ports.ClassAccessChecker
+	audit       ports.AuditLogger
 	validator   *validator.Validate
 	logger      *zap.Logger
 }
@@ -56,12 +48,12 @@ type HomeroomService struct {
 // NewHomeroomService builds a HomeroomService with sane defaults.
 func NewHomeroomService(
 	repo homeroomStore,
-	classes classReader,
-	terms homeroomTermReader,
+	classes ports.ClassReader,
+	terms ports.TermResolver,
 	teachers teacherRepository,
 	subjects homeroomSubjectFinder,
-	assignments homeroomClassAccessChecker,
-	audit auditLogger,
+	assignments ports.ClassAccessChecker,
+	audit ports.AuditLogger,
 	validate *validator.Validate,
 	logger *zap.Logger,
 ) *HomeroomService {
@@ -0,0 +1,472 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// IntakeDocumentCategory is the archive category intake documents are filed under.
+const IntakeDocumentCategory = "intake"
+
+type intakeStore interface {
+	Create(ctx context.Context, application *models.IntakeApplication) error
+	Update(ctx context.Context, application *models.IntakeApplication) error
+	FindByID(ctx context.Context, id string) (*models.IntakeApplication, error)
+	List(ctx context.Context, filter models.IntakeFilter) ([]models.IntakeApplication, error)
+	SetDocument(ctx context.Context, document *models.IntakeDocument) error
+	Decide(ctx context.Context, application *models.IntakeApplication) error
+	Accept(ctx context.Context, application *models.IntakeApplication, student *models.Student, enrollment *models.Enrollment) error
+}
+
+type intakeNISChecker interface {
+	ExistsByNIS(ctx context.Context, nis string, excludeID string) (bool, error)
+}
+
+type intakeDocumentUploader interface {
+	UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error)
+}
+
+// IntakeServiceParams groups constructor dependencies. Documents is nil when archives are disabled;
+// Homerooms, Notifications and Audit are optional.
+type IntakeServiceParams struct {
+	Store         intakeStore
+	Students      intakeNISChecker
+	Terms         ports.TermReader
+	Classes       ports.ClassReader
+	Documents     intakeDocumentUploader
+	Homerooms     homeroomLister
+	Notifications notificationWriter
+	Audit         ports.AuditLogger
+	Validator     *validator.Validate
+	Logger        *zap.Logger
+}
+
+// IntakeService takes transfer-in applications from submission to placement. Admins collect the
+// checklist documents, then accept, waitlist or reject the application. Accepting creates the
+// student and their enrollment together and tells the class's homeroom teacher.
+type IntakeService struct {
+	store         intakeStore
+	students      intakeNISChecker
+	terms         ports.TermReader
+	classes       ports.ClassReader
+	documents     intakeDocumentUploader
+	homerooms     homeroomLister
+	notifications notificationWriter
+	audit         ports.AuditLogger
+	validator     *validator.Validate
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewIntakeService constructs the service.
+func NewIntakeService(params IntakeServiceParams) *IntakeService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &IntakeService{
+		store:         params.Store,
+		students:      params.Students,
+		terms:         params.Terms,
+		classes:       params.Classes,
+		documents:     params.Documents,
+		homerooms:     params.Homerooms,
+		notifications: params.Notifications,
+		audit:         params.Audit,
+		validator:     validate,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// List returns the term's applications, optionally of one status.
+func (s *IntakeService) List(ctx context.Context, query dto.IntakeQuery) ([]models.IntakeApplication, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid intake query")
+	}
+	filter := models.IntakeFilter{TermID: query.TermID}
+	if query.Status != "" {
+		status := models.IntakeStatus(strings.ToUpper(query.Status))
+		switch status {
+		case models.IntakeStatusPending, models.IntakeStatusWaitlisted, models.IntakeStatusAccepted, models.IntakeStatusRejected:
+		default:
+			return nil, appErrors.Clone(appErrors.ErrValidation, "status must be PENDING, WAITLISTED, ACCEPTED or REJECTED")
+		}
+		filter.Status = []models.IntakeStatus{status}
+	}
+	applications, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list intake applications")
+	}
+	if err := s.decorate(ctx, query.TermID, applications); err != nil {
+		return nil, err
+	}
+	if filter.Status != nil && filter.Status[0] == models.IntakeStatusWaitlisted {
+		sort.SliceStable(applications, func(i, j int) bool {
+			return applications[i].WaitlistPosition < applications[j].WaitlistPosition
+		})
+	}
+	return applications, nil
+}
+
+// Get returns an application with its documents and checklist.
+func (s *IntakeService) Get(ctx context.Context, id string) (*models.IntakeApplication, error) {
+	application, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "intake application not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load intake application")
+	}
+	applications := []models.IntakeApplication{*application}
+	if err := s.decorate(ctx, application.TermID, applications); err != nil {
+		return nil, err
+	}
+	return &applications[0], nil
+}
+
+// Create records a new pending application.
+func (s *IntakeService) Create(ctx context.Context, req dto.IntakeApplicationRequest, claims *models.JWTClaims) (*models.IntakeApplication, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid intake application payload")
+	}
+	if s.terms != nil {
+		if _, err := s.terms.FindByID(ctx, req.TermID); err != nil {
+			return nil, lookupError(err, "term")
+		}
+	}
+	application := &models.IntakeApplication{TermID: req.TermID, Status: models.IntakeStatusPending, CreatedBy: claims.UserID}
+	if err := s.applyRequest(ctx, application, req); err != nil {
+		return nil, err
+	}
+	if err := s.store.Create(ctx, application); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create intake application")
+	}
+	return s.Get(ctx, application.ID)
+}
+
+// Update corrects the applicant's details while the application awaits a decision.
+func (s *IntakeService) Update(ctx context.Context, id string, req dto.IntakeApplicationRequest) (*models.IntakeApplication, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid intake application payload")
+	}
+	application, err := s.openApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequest(ctx, application, req); err != nil {
+		return nil, err
+	}
+	if err := s.store.Update(ctx, application); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "intake application already decided")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update intake application")
+	}
+	return s.Get(ctx, id)
+}
+
+// AttachDocument stores a checklist document in the archive and links it to the application,
+// replacing an earlier document of the same type. The archive keeps it admin-only.
+func (s *IntakeService) AttachDocument(ctx context.Context, id, documentType string, upload ArchiveUpload, claims *models.JWTClaims) (*models.IntakeApplication, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if s.documents == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "intake documents require archives to be enabled")
+	}
+	kind := models.IntakeDocumentType(strings.ToUpper(strings.TrimSpace(documentType)))
+	if !intakeChecklistHas(kind) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "documentType must be one of "+intakeChecklistNames())
+	}
+	application, err := s.openApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	termID := application.TermID
+	item, err := s.documents.UploadAttachment(ctx, dto.CreateArchiveRequest{
+		Title:     fmt.Sprintf("%s - %s", application.FullName, strings.ReplaceAll(string(kind), "_", " ")),
+		Category:  IntakeDocumentCategory,
+		Scope:     models.ArchiveScopeIntake,
+		RefTermID: &termID,
+	}, upload, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	document := &models.IntakeDocument{ApplicationID: application.ID, DocumentType: kind, ArchiveID: item.ID, UploadedBy: claims.UserID}
+	if err := s.store.SetDocument(ctx, document); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to attach intake document")
+	}
+	return s.Get(ctx, id)
+}
+
+// Decide accepts, waitlists or rejects an open application. Accepting needs every checklist
+// document; it creates the student with the given NIS, enrolls them in the class for the
+// application's term and notifies the class's homeroom teacher. A waitlisted application keeps its
+// place when waitlisted again.
+func (s *IntakeService) Decide(ctx context.Context, id string, req dto.IntakeDecisionRequest, claims *models.JWTClaims) (*models.IntakeApplication, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	req.Decision = strings.ToUpper(strings.TrimSpace(req.Decision))
+	req.NIS = strings.TrimSpace(req.NIS)
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid intake decision payload")
+	}
+	application, err := s.openApplication(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous := application.Status
+	now := s.now().UTC()
+	application.DecisionNote = normalizeOptional(req.Note)
+	application.DecidedBy = &claims.UserID
+	application.DecidedAt = &now
+
+	switch req.Decision {
+	case dto.IntakeDecisionAccept:
+		if err := s.accept(ctx, application, req); err != nil {
+			return nil, err
+		}
+	default:
+		application.Status = models.IntakeStatusRejected
+		if req.Decision == dto.IntakeDecisionWaitlist {
+			application.Status = models.IntakeStatusWaitlisted
+			if application.WaitlistedAt == nil {
+				application.WaitlistedAt = &now
+			}
+		}
+		if err := s.store.Decide(ctx, application); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, appErrors.Clone(appErrors.ErrConflict, "intake application already decided")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record intake decision")
+		}
+	}
+
+	values, _ := json.Marshal(map[string]interface{}{"status": application.Status, "classId": application.ClassID, "studentId": application.StudentID})
+	oldValues, _ := json.Marshal(map[string]interface{}{"status": previous})
+	s.emitAudit(ctx, &models.AuditLog{
+		UserID:     &claims.UserID,
+		Action:     models.AuditActionIntakeDecision,
+		Resource:   "intake_application",
+		ResourceID: &application.ID,
+		OldValues:  oldValues,
+		NewValues:  values,
+	})
+	return s.Get(ctx, id)
+}
+
+func (s *IntakeService) accept(ctx context.Context, application *models.IntakeApplication, req dto.IntakeDecisionRequest) error {
+	applications := []models.IntakeApplication{*application}
+	setMissingDocuments(applications)
+	if missing := applications[0].MissingDocuments; len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, kind := range missing {
+			names[i] = string(kind)
+		}
+		return appErrors.Clone(appErrors.ErrPreconditionFailed, "missing intake documents: "+strings.Join(names, ", "))
+	}
+	class, err := s.classes.FindByID(ctx, req.ClassID)
+	if err != nil {
+		return lookupError(err, "class")
+	}
+	exists, err := s.students.ExistsByNIS(ctx, req.NIS, "")
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to validate nis")
+	}
+	if exists {
+		return appErrors.Clone(appErrors.ErrConflict, "nis already used")
+	}
+
+	student := &models.Student{
+		NIS:       req.NIS,
+		NISN:      application.NISN,
+		FullName:  application.FullName,
+		Gender:    application.Gender,
+		BirthDate: application.BirthDate,
+		Address:   application.Address,
+		Phone:     application.Phone,
+		Active:    true,
+	}
+	enrollment := &models.Enrollment{
+		ClassID:  class.ID,
+		TermID:   application.TermID,
+		JoinedAt: *application.DecidedAt,
+		Status:   models.EnrollmentStatusActive,
+	}
+	application.Status = models.IntakeStatusAccepted
+	application.ClassID = &class.ID
+	if err := s.store.Accept(ctx, application, student, enrollment); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrConflict, "intake application already decided")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to accept intake application")
+	}
+	s.notifyHomeroom(ctx, application, class)
+	return nil
+}
+
+// notifyHomeroom tells the class's homeroom teacher that a transfer student joins. Failures are
+// logged; the student is enrolled either way.
+func (s *IntakeService) notifyHomeroom(ctx context.Context, application *models.IntakeApplication, class *models.Class) {
+	if s.homerooms == nil || s.notifications == nil {
+		return
+	}
+	items, err := s.homerooms.List(ctx, dto.HomeroomFilter{TermID: application.TermID, ClassID: class.ID})
+	if err != nil {
+		s.logger.Warn("intake could not list homerooms", zap.String("application_id", application.ID), zap.Error(err))
+		return
+	}
+	for _, item := range items {
+		if item.ClassID != class.ID || item.HomeroomTeacherID == nil || *item.HomeroomTeacherID == "" {
+			continue
+		}
+		key := "intake:" + application.ID
+		notification := &models.Notification{
+			UserID:    *item.HomeroomTeacherID,
+			Type:      models.NotificationTypeIntakePlaced,
+			Title:     fmt.Sprintf("New student in %s", class.Name),
+			Body:      fmt.Sprintf("%s transfers in from %s and joins %s.", application.FullName, application.PreviousSchool, class.Name),
+			RefID:     application.StudentID,
+			DedupeKey: &key,
+		}
+		if _, err := s.notifications.CreateOnce(ctx, notification); err != nil {
+			s.logger.Warn("intake homeroom notification failed", zap.String("application_id", application.ID), zap.Error(err))
+		}
+		return
+	}
+	s.logger.Info("intake class has no homeroom teacher to notify", zap.String("class_id", class.ID), zap.String("term_id", application.TermID))
+}
+
+func (s *IntakeService) openApplication(ctx context.Context, id string) (*models.IntakeApplication, error) {
+	application, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "intake application not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load intake application")
+	}
+	if !application.Status.Open() {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "intake application already decided")
+	}
+	return application, nil
+}
+
+func (s *IntakeService) applyRequest(ctx context.Context, application *models.IntakeApplication, req dto.IntakeApplicationRequest) error {
+	requested := normalizeOptional(req.RequestedClassID)
+	if requested != nil && s.classes != nil {
+		if _, err := s.classes.FindByID(ctx, *requested); err != nil {
+			return lookupError(err, "requested class")
+		}
+	}
+	application.FullName = strings.TrimSpace(req.FullName)
+	application.NISN = normalizeOptional(req.NISN)
+	application.Gender = req.Gender
+	application.BirthDate = req.BirthDate
+	application.Address = req.Address
+	application.Phone = req.Phone
+	application.PreviousSchool = strings.TrimSpace(req.PreviousSchool)
+	application.RequestedClassID = requested
+	return nil
+}
+
+// decorate fills the missing checklist documents and, for waitlisted applications, their place in
+// the term's waiting list, ordered by when they were waitlisted.
+func (s *IntakeService) decorate(ctx context.Context, termID string, applications []models.IntakeApplication) error {
+	setMissingDocuments(applications)
+	waiting := false
+	for _, application := range applications {
+		if application.Status == models.IntakeStatusWaitlisted {
+			waiting = true
+			break
+		}
+	}
+	if !waiting {
+		return nil
+	}
+	waitlisted, err := s.store.List(ctx, models.IntakeFilter{TermID: termID, Status: []models.IntakeStatus{models.IntakeStatusWaitlisted}})
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list waitlisted intake applications")
+	}
+	sort.SliceStable(waitlisted, func(i, j int) bool {
+		a, b := waitlisted[i], waitlisted[j]
+		if a.WaitlistedAt != nil && b.WaitlistedAt != nil && !a.WaitlistedAt.Equal(*b.WaitlistedAt) {
+			return a.WaitlistedAt.Before(*b.WaitlistedAt)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	positions := make(map[string]int, len(waitlisted))
+	for i, application := range waitlisted {
+		positions[application.ID] = i + 1
+	}
+	for i := range applications {
+		applications[i].WaitlistPosition = positions[applications[i].ID]
+	}
+	return nil
+}
+
+func (s *IntakeService) emitAudit(ctx context.Context, log *models.AuditLog) {
+	if s.audit == nil || log == nil {
+		return
+	}
+	log.IPAddress = "system"
+	log.UserAgent = "intake-service"
+	if err := s.audit.CreateAuditLog(ctx, log); err != nil {
+		s.logger.Warn("failed to persist audit log", zap.Error(err))
+	}
+}
+
+func setMissingDocuments(applications []models.IntakeApplication) {
+	for i := range applications {
+		present := make(map[models.IntakeDocumentType]struct{}, len(applications[i].Documents))
+		for _, document := range applications[i].Documents {
+			present[document.DocumentType] = struct{}{}
+		}
+		missing := make([]models.IntakeDocumentType, 0)
+		for _, kind := range models.IntakeChecklist {
+			if _, ok := present[kind]; !ok {
+				missing = append(missing, kind)
+			}
+		}
+		applications[i].MissingDocuments = missing
+	}
+}
+
+func intakeChecklistHas(kind models.IntakeDocumentType) bool {
+	for _, item := range models.IntakeChecklist {
+		if item == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func intakeChecklistNames() string {
+	names := make([]string, len(models.IntakeChecklist))
+	for i, kind := range models.IntakeChecklist {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type intakeStoreStub struct {
+	applications map[string]*models.IntakeApplication
+	students     []models.Student
+	enrollments  []models.Enrollment
+}
+
+func (s *intakeStoreStub) Create(_ context.Context, application *models.IntakeApplication) error {
+	application.ID = "intake-new"
+	s.applications[application.ID] = application
+	return nil
+}
+
+func (s *intakeStoreStub) Update(_ context.Context, application *models.IntakeApplication) error {
+	s.applications[application.ID] = application
+	return nil
+}
+
+func (s *intakeStoreStub) FindByID(_ context.Context, id string) (*models.IntakeApplication, error) {
+	application, ok := s.applications[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *application
+	return &copied, nil
+}
+
+func (s *intakeStoreStub) List(_ context.Context, filter models.IntakeFilter) ([]models.IntakeApplication, error) {
+	var applications []models.IntakeApplication
+	for _, application := range s.applications {
+		if filter.TermID != "" && application.TermID != filter.TermID {
+			continue
+		}
+		if len(filter.Status) > 0 && application.Status != filter.Status[0] {
+			continue
+		}
+		applications = append(applications, *application)
+	}
+	return applications, nil
+}
+
+func (s *intakeStoreStub) SetDocument(_ context.Context, document *models.IntakeDocument) error {
+	application := s.applications[document.ApplicationID]
+	application.Documents = append(application.Documents, *document)
+	return nil
+}
+
+func (s *intakeStoreStub) Decide(_ context.Context, application *models.IntakeApplication) error {
+	s.applications[application.ID] = application
+	return nil
+}
+
+func (s *intakeStoreStub) Accept(_ context.Context, application *models.IntakeApplication, student *models.Student, enrollment *models.Enrollment) error {
+	student.ID = "student-new"
+	enrollment.ID = "enrollment-new"
+	enrollment.StudentID = student.ID
+	application.StudentID = &student.ID
+	application.EnrollmentID = &enrollment.ID
+	s.students = append(s.students, *student)
+	s.enrollments = append(s.enrollments, *enrollment)
+	s.applications[application.ID] = application
+	return nil
+}
+
+type intakeNISStub map[string]bool
+
+func (s intakeNISStub) ExistsByNIS(_ context.Context, nis string, _ string) (bool, error) {
+	return s[nis], nil
+}
+
+type intakeClassStub map[string]string
+
+func (s intakeClassStub) FindByID(_ context.Context, id string) (*models.Class, error) {
+	name, ok := s[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &models.Class{ID: id, Name: name}, nil
+}
+
+type intakeUploaderStub struct{ uploads []dto.CreateArchiveRequest }
+
+func (s *intakeUploaderStub) UploadAttachment(_ context.Context, meta dto.CreateArchiveRequest, _ ArchiveUpload, _ string) (*models.ArchiveItem, error) {
+	s.uploads = append(s.uploads, meta)
+	return &models.ArchiveItem{ID: "archive-" + meta.Title}, nil
+}
+
+func newIntakeFixture() (*IntakeService, *intakeStoreStub, *notificationWriterStub) {
+	first := time.Date(2026, 8, 1, 8, 0, 0, 0, time.UTC)
+	store := &intakeStoreStub{applications: map[string]*models.IntakeApplication{
+		"intake-1": {ID: "intake-1", TermID: "term-1", FullName: "Rina Putri", Gender: "F", PreviousSchool: "SMA 3 Bandung", Status: models.IntakeStatusPending, CreatedAt: first},
+		"intake-2": {ID: "intake-2", TermID: "term-1", FullName: "Budi", Gender: "M", PreviousSchool: "SMA 1 Bogor", Status: models.IntakeStatusWaitlisted, CreatedAt: first, WaitlistedAt: &first},
+	}}
+	teacher := "teacher-1"
+	notifications := &notificationWriterStub{}
+	svc := NewIntakeService(IntakeServiceParams{
+		Store:         store,
+		Students:      intakeNISStub{"1001": true},
+		Classes:       intakeClassStub{"class-a": "X IPA 1"},
+		Documents:     &intakeUploaderStub{},
+		Homerooms:     homeroomListerStub{items: []dto.HomeroomItem{{ClassID: "class-a", HomeroomTeacherID: &teacher}}},
+		Notifications: notifications,
+	})
+	svc.now = func() time.Time { return first.Add(48 * time.Hour) }
+	return svc, store, notifications
+}
+
+func TestIntakeServiceAcceptRequiresChecklist(t *testing.T) {
+	svc, store, _ := newIntakeFixture()
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	accept := dto.IntakeDecisionRequest{Decision: "accept", ClassID: "class-a", NIS: "2001"}
+
+	_, err := svc.Decide(context.Background(), "intake-1", accept, admin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+	assert.Empty(t, store.students)
+
+	for _, kind := range []string{"birth_certificate", "FAMILY_CARD", "REPORT_CARD"} {
+		_, err = svc.AttachDocument(context.Background(), "intake-1", kind, ArchiveUpload{}, admin)
+		require.NoError(t, err)
+	}
+	application, err := svc.Get(context.Background(), "intake-1")
+	require.NoError(t, err)
+	assert.Equal(t, []models.IntakeDocumentType{models.IntakeDocumentTransferLetter}, application.MissingDocuments)
+
+	_, err = svc.AttachDocument(context.Background(), "intake-1", "PASSPORT", ArchiveUpload{}, admin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestIntakeServiceAcceptCreatesStudentAndNotifiesHomeroom(t *testing.T) {
+	svc, store, notifications := newIntakeFixture()
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+	for _, kind := range models.IntakeChecklist {
+		_, err := svc.AttachDocument(context.Background(), "intake-1", string(kind), ArchiveUpload{}, admin)
+		require.NoError(t, err)
+	}
+
+	_, err := svc.Decide(context.Background(), "intake-1", dto.IntakeDecisionRequest{Decision: "ACCEPT", ClassID: "class-a", NIS: "1001"}, admin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	application, err := svc.Decide(context.Background(), "intake-1", dto.IntakeDecisionRequest{Decision: "ACCEPT", ClassID: "class-a", NIS: "2001"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.IntakeStatusAccepted, application.Status)
+	assert.Equal(t, "student-new", *application.StudentID)
+	require.Len(t, store.students, 1)
+	assert.Equal(t, "Rina Putri", store.students[0].FullName)
+	assert.True(t, store.students[0].Active)
+	require.Len(t, store.enrollments, 1)
+	assert.Equal(t, "class-a", store.enrollments[0].ClassID)
+	assert.Equal(t, "term-1", store.enrollments[0].TermID)
+
+	require.Len(t, notifications.sent, 1)
+	assert.Equal(t, "teacher-1", notifications.sent[0].UserID)
+	assert.Equal(t, models.NotificationTypeIntakePlaced, notifications.sent[0].Type)
+
+	_, err = svc.Decide(context.Background(), "intake-1", dto.IntakeDecisionRequest{Decision: "REJECT"}, admin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestIntakeServiceWaitlistKeepsPlace(t *testing.T) {
+	svc, _, _ := newIntakeFixture()
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	application, err := svc.Decide(context.Background(), "intake-1", dto.IntakeDecisionRequest{Decision: "WAITLIST"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.IntakeStatusWaitlisted, application.Status)
+	assert.Equal(t, 2, application.WaitlistPosition)
+
+	application, err = svc.Decide(context.Background(), "intake-2", dto.IntakeDecisionRequest{Decision: "WAITLIST"}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, 1, application.WaitlistPosition)
+
+	waitlisted, err := svc.List(context.Background(), dto.IntakeQuery{TermID: "term-1", Status: "waitlisted"})
+	require.NoError(t, err)
+	require.Len(t, waitlisted, 2)
+	assert.Equal(t, "intake-2", waitlisted[0].ID)
+	assert.Equal(t, "intake-1", waitlisted[1].ID)
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx/types"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	legacyIdempotencyKeyMaxLength = 128
+	legacyReconciliationMaxDays   = 31
+)
+
+type legacySyncStore interface {
+	FindBatch(ctx context.Context, kind models.LegacySyncKind, idempotencyKey string) (*models.LegacySyncBatch, error)
+	CreateBatch(ctx context.Context, batch *models.LegacySyncBatch) (bool, error)
+	ListBatches(ctx context.Context, from, to time.Time) ([]models.LegacySyncBatch, error)
+}
+
+type legacyEnrollmentReader interface {
+	FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error)
+}
+
+type legacyAttendanceWriter interface {
+	MarkDaily(ctx context.Context, req MarkDailyAttendanceRequest) (*models.DailyAttendance, error)
+}
+
+type legacyGradeWriter interface {
+	Upsert(ctx context.Context, req UpsertGradeRequest) (*models.Grade, error)
+}
+
+// legacyAttendanceStatuses maps the status spellings the legacy app sends to attendance codes.
+var legacyAttendanceStatuses = map[string]models.AttendanceStatus{
+	"h":          models.AttendanceStatusPresent,
+	"hadir":      models.AttendanceStatusPresent,
+	"present":    models.AttendanceStatusPresent,
+	"s":          models.AttendanceStatusSick,
+	"sakit":      models.AttendanceStatusSick,
+	"sick":       models.AttendanceStatusSick,
+	"i":          models.AttendanceStatusExcused,
+	"izin":       models.AttendanceStatusExcused,
+	"permission": models.AttendanceStatusExcused,
+	"a":          models.AttendanceStatusAbsent,
+	"alpa":       models.AttendanceStatusAbsent,
+	"absent":     models.AttendanceStatusAbsent,
+}
+
+// LegacySyncServiceParams groups constructor dependencies.
+type LegacySyncServiceParams struct {
+	Store       legacySyncStore
+	Enrollments legacyEnrollmentReader
+	Attendance  legacyAttendanceWriter
+	Grades      legacyGradeWriter
+	Validator   *validator.Validate
+	Logger      *zap.Logger
+}
+
+// LegacySyncService applies attendance and grades the legacy app still writes during the migration
+// window. Records go through the same services as the API so school day, finalization and grade
+// config rules hold; a record that breaks them is reported back instead of failing the batch.
+type LegacySyncService struct {
+	store       legacySyncStore
+	enrollments legacyEnrollmentReader
+	attendance  legacyAttendanceWriter
+	grades      legacyGradeWriter
+	validator   *validator.Validate
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewLegacySyncService constructs the service.
+func NewLegacySyncService(params LegacySyncServiceParams) *LegacySyncService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LegacySyncService{
+		store:       params.Store,
+		enrollments: params.Enrollments,
+		attendance:  params.Attendance,
+		grades:      params.Grades,
+		validator:   validate,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// SyncAttendance upserts a batch of legacy daily attendance marks.
+func (s *LegacySyncService) SyncAttendance(ctx context.Context, idempotencyKey, source string, req dto.LegacyAttendanceSyncRequest) (*dto.LegacySyncResult, error) {
+	return s.sync(ctx, models.LegacySyncAttendance, idempotencyKey, source, req, len(req.Records), func(i int) (string, error) {
+		record := req.Records[i]
+		status, ok := legacyAttendanceStatuses[strings.ToLower(strings.TrimSpace(record.Status))]
+		if !ok {
+			return record.StudentID, appErrors.Clone(appErrors.ErrValidation, "unknown attendance status "+record.Status)
+		}
+		enrollment, err := s.enrollment(ctx, record.StudentID, record.TermID)
+		if err != nil {
+			return record.StudentID, err
+		}
+		_, err = s.attendance.MarkDaily(ctx, MarkDailyAttendanceRequest{
+			EnrollmentID: enrollment.ID,
+			Date:         record.Date,
+			Status:       string(status),
+			Notes:        record.Notes,
+		})
+		return record.StudentID, err
+	})
+}
+
+// SyncGrades upserts a batch of legacy component scores.
+func (s *LegacySyncService) SyncGrades(ctx context.Context, idempotencyKey, source string, req dto.LegacyGradeSyncRequest) (*dto.LegacySyncResult, error) {
+	return s.sync(ctx, models.LegacySyncGrades, idempotencyKey, source, req, len(req.Records), func(i int) (string, error) {
+		record := req.Records[i]
+		enrollment, err := s.enrollment(ctx, record.StudentID, record.TermID)
+		if err != nil {
+			return record.StudentID, err
+		}
+		_, err = s.grades.Upsert(ctx, UpsertGradeRequest{
+			EnrollmentID:  enrollment.ID,
+			SubjectID:     record.SubjectID,
+			ComponentCode: record.Component,
+			GradeValue:    record.Score,
+		})
+		return record.StudentID, err
+	})
+}
+
+// Reconciliation totals the batches received between query.From and query.To and lists the records
+// that were rejected.
+func (s *LegacySyncService) Reconciliation(ctx context.Context, query dto.LegacyReconciliationQuery) (*dto.LegacyReconciliationReport, error) {
+	to := s.now().UTC().Truncate(24 * time.Hour)
+	if query.To != "" {
+		parsed, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -6)
+	if query.From != "" {
+		parsed, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "from must not be after to")
+	}
+	if to.Sub(from) >= legacyReconciliationMaxDays*24*time.Hour {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "reconciliation range is limited to 31 days")
+	}
+
+	batches, err := s.store.ListBatches(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list legacy sync batches")
+	}
+	report := &dto.LegacyReconciliationReport{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Totals:   make([]dto.LegacySyncTotals, 0, 2),
+		Failures: make([]dto.LegacyReconciliationFailure, 0),
+	}
+	totals := map[models.LegacySyncKind]*dto.LegacySyncTotals{}
+	for _, kind := range []models.LegacySyncKind{models.LegacySyncAttendance, models.LegacySyncGrades} {
+		report.Totals = append(report.Totals, dto.LegacySyncTotals{Kind: kind})
+		totals[kind] = &report.Totals[len(report.Totals)-1]
+	}
+	for _, batch := range batches {
+		if total, ok := totals[batch.Kind]; ok {
+			total.Batches++
+			total.Received += batch.Received
+			total.Applied += batch.Applied
+			total.Failed += batch.Failed
+		}
+		for _, failure := range decodeLegacyFailures(batch.Failures) {
+			report.Failures = append(report.Failures, dto.LegacyReconciliationFailure{
+				Kind:           batch.Kind,
+				IdempotencyKey: batch.IdempotencyKey,
+				ReceivedAt:     batch.CreatedAt,
+				Index:          failure.Index,
+				StudentID:      failure.StudentID,
+				Reason:         failure.Reason,
+			})
+		}
+	}
+	return report, nil
+}
+
+// sync applies a batch once per idempotency key. Rejected records are collected; an internal error
+// aborts the batch without recording it so the legacy app can retry with the same key.
+func (s *LegacySyncService) sync(ctx context.Context, kind models.LegacySyncKind, idempotencyKey, source string, payload interface{}, count int, apply func(i int) (string, error)) (*dto.LegacySyncResult, error) {
+	idempotencyKey = strings.TrimSpace(idempotencyKey)
+	if idempotencyKey == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "Idempotency-Key header is required")
+	}
+	if len(idempotencyKey) > legacyIdempotencyKeyMaxLength {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "Idempotency-Key must be at most 128 characters")
+	}
+	if err := s.validator.Struct(payload); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid legacy sync payload")
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid legacy sync payload")
+	}
+	sum := sha256.Sum256(raw)
+	hash := hex.EncodeToString(sum[:])
+
+	if replay, err := s.replay(ctx, kind, idempotencyKey, hash); replay != nil || err != nil {
+		return replay, err
+	}
+
+	failures := make([]models.LegacySyncFailure, 0)
+	for i := 0; i < count; i++ {
+		studentID, err := apply(i)
+		if err == nil {
+			continue
+		}
+		appErr := appErrors.FromError(err)
+		if appErr.Status >= http.StatusInternalServerError {
+			return nil, appErr
+		}
+		failures = append(failures, models.LegacySyncFailure{Index: i, StudentID: studentID, Reason: appErr.Message})
+	}
+	encoded, err := json.Marshal(failures)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to encode sync failures")
+	}
+	batch := &models.LegacySyncBatch{
+		Kind:           kind,
+		IdempotencyKey: idempotencyKey,
+		Source:         source,
+		PayloadHash:    hash,
+		Received:       count,
+		Applied:        count - len(failures),
+		Failed:         len(failures),
+		Failures:       types.JSONText(encoded),
+	}
+	created, err := s.store.CreateBatch(ctx, batch)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record legacy sync batch")
+	}
+	if !created {
+		// A concurrent delivery of the same key finished first; answer with its outcome.
+		if replay, err := s.replay(ctx, kind, idempotencyKey, hash); replay != nil || err != nil {
+			return replay, err
+		}
+	}
+	if batch.Failed > 0 {
+		s.logger.Warn("legacy sync batch had rejected records",
+			zap.String("kind", string(kind)),
+			zap.String("idempotency_key", idempotencyKey),
+			zap.Int("failed", batch.Failed))
+	}
+	return legacySyncResult(batch, failures, false), nil
+}
+
+// replay returns the stored outcome of an idempotency key, or nil when the key is new. Reusing a
+// key for a different payload is a conflict.
+func (s *LegacySyncService) replay(ctx context.Context, kind models.LegacySyncKind, idempotencyKey, hash string) (*dto.LegacySyncResult, error) {
+	existing, err := s.store.FindBatch(ctx, kind, idempotencyKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load legacy sync batch")
+	}
+	if existing.PayloadHash != hash {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "Idempotency-Key was already used for a different payload")
+	}
+	return legacySyncResult(existing, decodeLegacyFailures(existing.Failures), true), nil
+}
+
+func (s *LegacySyncService) enrollment(ctx context.Context, studentID, termID string) (*models.Enrollment, error) {
+	enrollments, err := s.enrollments.FindActiveByStudentAndTerm(ctx, studentID, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load enrollment")
+	}
+	if len(enrollments) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "student has no active enrollment in the term")
+	}
+	return &enrollments[0], nil
+}
+
+func legacySyncResult(batch *models.LegacySyncBatch, failures []models.LegacySyncFailure, replayed bool) *dto.LegacySyncResult {
+	return &dto.LegacySyncResult{
+		Kind:           batch.Kind,
+		IdempotencyKey: batch.IdempotencyKey,
+		Received:       batch.Received,
+		Applied:        batch.Applied,
+		Failed:         batch.Failed,
+		Failures:       failures,
+		Replayed:       replayed,
+	}
+}
+
+func decodeLegacyFailures(raw types.JSONText) []models.LegacySyncFailure {
+	failures := make([]models.LegacySyncFailure, 0)
+	if len(raw) == 0 {
+		return failures
+	}
+	if err := json.Unmarshal(raw, &failures); err != nil {
+		return make([]models.LegacySyncFailure, 0)
+	}
+	return failures
+}
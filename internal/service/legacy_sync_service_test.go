@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type legacySyncStoreStub struct {
+	batches []models.LegacySyncBatch
+	from    time.Time
+	to      time.Time
+}
+
+func (s *legacySyncStoreStub) FindBatch(ctx context.Context, kind models.LegacySyncKind, idempotencyKey string) (*models.LegacySyncBatch, error) {
+	for i := range s.batches {
+		if s.batches[i].Kind == kind && s.batches[i].IdempotencyKey == idempotencyKey {
+			return &s.batches[i], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *legacySyncStoreStub) CreateBatch(ctx context.Context, batch *models.LegacySyncBatch) (bool, error) {
+	s.batches = append(s.batches, *batch)
+	return true, nil
+}
+
+func (s *legacySyncStoreStub) ListBatches(ctx context.Context, from, to time.Time) ([]models.LegacySyncBatch, error) {
+	s.from, s.to = from, to
+	return s.batches, nil
+}
+
+type legacyEnrollmentStub struct{}
+
+func (legacyEnrollmentStub) FindActiveByStudentAndTerm(ctx context.Context, studentID, termID string) ([]models.Enrollment, error) {
+	if studentID == "student-unknown" {
+		return nil, nil
+	}
+	return []models.Enrollment{{ID: "enr-" + studentID, StudentID: studentID, TermID: termID}}, nil
+}
+
+type legacyAttendanceStub struct {
+	marked []MarkDailyAttendanceRequest
+	err    error
+}
+
+func (s *legacyAttendanceStub) MarkDaily(ctx context.Context, req MarkDailyAttendanceRequest) (*models.DailyAttendance, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.marked = append(s.marked, req)
+	return &models.DailyAttendance{EnrollmentID: req.EnrollmentID}, nil
+}
+
+type legacyGradeStub struct {
+	upserted []UpsertGradeRequest
+}
+
+func (s *legacyGradeStub) Upsert(ctx context.Context, req UpsertGradeRequest) (*models.Grade, error) {
+	if req.SubjectID == "finalized" {
+		return nil, appErrors.Clone(appErrors.ErrFinalized, "grade config finalized")
+	}
+	s.upserted = append(s.upserted, req)
+	return &models.Grade{EnrollmentID: req.EnrollmentID}, nil
+}
+
+func TestLegacySyncServiceSyncAttendance(t *testing.T) {
+	store := &legacySyncStoreStub{}
+	attendance := &legacyAttendanceStub{}
+	svc := NewLegacySyncService(LegacySyncServiceParams{Store: store, Enrollments: legacyEnrollmentStub{}, Attendance: attendance})
+	req := dto.LegacyAttendanceSyncRequest{Records: []dto.LegacyAttendanceRecord{
+		{StudentID: "student-1", TermID: "term-1", Date: "2026-03-10", Status: "izin"},
+		{StudentID: "student-2", TermID: "term-1", Date: "2026-03-10", Status: "late"},
+		{StudentID: "student-unknown", TermID: "term-1", Date: "2026-03-10", Status: "H"},
+	}}
+
+	result, err := svc.SyncAttendance(context.Background(), "batch-1", "legacy", req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Received)
+	assert.Equal(t, 1, result.Applied)
+	require.Len(t, result.Failures, 2)
+	assert.Equal(t, models.LegacySyncFailure{Index: 1, StudentID: "student-2", Reason: "unknown attendance status late"}, result.Failures[0])
+	assert.Equal(t, 2, result.Failures[1].Index)
+	require.Len(t, attendance.marked, 1)
+	assert.Equal(t, MarkDailyAttendanceRequest{EnrollmentID: "enr-student-1", Date: "2026-03-10", Status: "I"}, attendance.marked[0])
+	assert.Equal(t, "legacy", store.batches[0].Source)
+
+	// A retried delivery is answered from the stored batch.
+	replayed, err := svc.SyncAttendance(context.Background(), "batch-1", "legacy", req)
+	require.NoError(t, err)
+	assert.True(t, replayed.Replayed)
+	assert.Equal(t, result.Failures, replayed.Failures)
+	assert.Len(t, attendance.marked, 1)
+
+	req.Records = req.Records[:1]
+	_, err = svc.SyncAttendance(context.Background(), "batch-1", "legacy", req)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.SyncAttendance(context.Background(), " ", "legacy", req)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestLegacySyncServiceInternalErrorsAbortBatch(t *testing.T) {
+	store := &legacySyncStoreStub{}
+	svc := NewLegacySyncService(LegacySyncServiceParams{
+		Store:       store,
+		Enrollments: legacyEnrollmentStub{},
+		Attendance:  &legacyAttendanceStub{err: appErrors.Wrap(errors.New("connection reset"), appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to mark attendance")},
+	})
+
+	_, err := svc.SyncAttendance(context.Background(), "batch-2", "legacy", dto.LegacyAttendanceSyncRequest{Records: []dto.LegacyAttendanceRecord{
+		{StudentID: "student-1", TermID: "term-1", Date: "2026-03-10", Status: "H"},
+	}})
+	assert.Equal(t, appErrors.ErrInternal.Code, appErrors.FromError(err).Code)
+	assert.Empty(t, store.batches)
+}
+
+func TestLegacySyncServiceGradesAndReconciliation(t *testing.T) {
+	store := &legacySyncStoreStub{}
+	grades := &legacyGradeStub{}
+	svc := NewLegacySyncService(LegacySyncServiceParams{Store: store, Enrollments: legacyEnrollmentStub{}, Grades: grades})
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC) }
+
+	result, err := svc.SyncGrades(context.Background(), "grades-1", "legacy", dto.LegacyGradeSyncRequest{Records: []dto.LegacyGradeRecord{
+		{StudentID: "student-1", TermID: "term-1", SubjectID: "math", Component: "UTS", Score: 88},
+		{StudentID: "student-2", TermID: "term-1", SubjectID: "finalized", Component: "UTS", Score: 70},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Applied)
+	assert.Equal(t, UpsertGradeRequest{EnrollmentID: "enr-student-1", SubjectID: "math", ComponentCode: "UTS", GradeValue: 88}, grades.upserted[0])
+
+	_, err = svc.SyncGrades(context.Background(), "grades-2", "legacy", dto.LegacyGradeSyncRequest{Records: []dto.LegacyGradeRecord{
+		{StudentID: "student-1", TermID: "term-1", SubjectID: "math", Component: "UTS", Score: 120},
+	}})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	report, err := svc.Reconciliation(context.Background(), dto.LegacyReconciliationQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-04", report.From)
+	assert.Equal(t, "2026-03-10", report.To)
+	assert.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), store.to)
+	assert.Equal(t, []dto.LegacySyncTotals{
+		{Kind: models.LegacySyncAttendance},
+		{Kind: models.LegacySyncGrades, Batches: 1, Received: 2, Applied: 1, Failed: 1},
+	}, report.Totals)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, "grades-1", report.Failures[0].IdempotencyKey)
+	assert.Equal(t, "grade config finalized", report.Failures[0].Reason)
+
+	_, err = svc.Reconciliation(context.Background(), dto.LegacyReconciliationQuery{From: "2026-01-01", To: "2026-03-10"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
@@ -0,0 +1,689 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type lessonGroupStore interface {
+	Create(ctx context.Context, group *models.LessonGroup) error
+	Update(ctx context.Context, group *models.LessonGroup) error
+	FindByID(ctx context.Context, id string) (*models.LessonGroup, error)
+	ListByTerm(ctx context.Context, termID string) ([]models.LessonGroup, error)
+	Delete(ctx context.Context, id string) error
+	ListSessions(ctx context.Context, groupID string) ([]models.Schedule, error)
+	DeleteSession(ctx context.Context, groupID, dayOfWeek, timeSlot string) error
+}
+
+type lessonGroupScheduleStore interface {
+	FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error)
+	BulkCreate(ctx context.Context, schedules []models.Schedule) error
+}
+
+type lessonGroupEnrollmentLister interface {
+	ListByClassAndTerm(ctx context.Context, classID, termID string) ([]models.Enrollment, error)
+}
+
+type lessonGroupAttendance interface {
+	BulkMarkSubject(ctx context.Context, req BulkMarkSubjectAttendanceRequest) (*BulkAttendanceResult, error)
+	SubjectSessionReport(ctx context.Context, scheduleID string, date time.Time) ([]models.SubjectAttendanceReportRow, error)
+}
+
+// LessonGroupServiceParams groups constructor dependencies.
+type LessonGroupServiceParams struct {
+	Store       lessonGroupStore
+	Schedules   lessonGroupScheduleStore
+	Enrollments lessonGroupEnrollmentLister
+	Terms       ports.TermReader
+	Classes     ports.ClassReader
+	Subjects    ports.SubjectReader
+	Teachers    ports.TeacherReader
+	// Attendance is nil when attendance is disabled; marking and reports are then unavailable.
+	Attendance lessonGroupAttendance
+	Validator  *validator.Validate
+	Logger     *zap.Logger
+}
+
+// LessonGroupService manages lessons shared by several classes, such as religion or language
+// electives. A session of a group is booked as one schedule per member class so timetables keep
+// showing it, and is checked for clashes across every member class at once.
+type LessonGroupService struct {
+	store       lessonGroupStore
+	schedules   lessonGroupScheduleStore
+	enrollments lessonGroupEnrollmentLister
+	terms       ports.TermReader
+	classes     ports.ClassReader
+	subjects    ports.SubjectReader
+	teachers    ports.TeacherReader
+	attendance  lessonGroupAttendance
+	validator   *validator.Validate
+	logger      *zap.Logger
+}
+
+// NewLessonGroupService constructs the service.
+func NewLessonGroupService(params LessonGroupServiceParams) *LessonGroupService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LessonGroupService{
+		store:       params.Store,
+		schedules:   params.Schedules,
+		enrollments: params.Enrollments,
+		terms:       params.Terms,
+		classes:     params.Classes,
+		subjects:    params.Subjects,
+		teachers:    params.Teachers,
+		attendance:  params.Attendance,
+		validator:   validate,
+		logger:      logger,
+	}
+}
+
+// List returns the term's lesson groups.
+func (s *LessonGroupService) List(ctx context.Context, termID string) ([]models.LessonGroup, error) {
+	if strings.TrimSpace(termID) == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "termId is required")
+	}
+	groups, err := s.store.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson groups")
+	}
+	return groups, nil
+}
+
+// Get returns a lesson group with its members.
+func (s *LessonGroupService) Get(ctx context.Context, id string) (*models.LessonGroup, error) {
+	group, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "lesson group not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load lesson group")
+	}
+	return group, nil
+}
+
+// Create validates and stores a lesson group.
+func (s *LessonGroupService) Create(ctx context.Context, req dto.LessonGroupRequest) (*models.LessonGroup, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group payload")
+	}
+	if err := s.ensureReferences(ctx, req); err != nil {
+		return nil, err
+	}
+	group := &models.LessonGroup{
+		TermID:    req.TermID,
+		SubjectID: req.SubjectID,
+		TeacherID: req.TeacherID,
+		Name:      strings.TrimSpace(req.Name),
+		Room:      trimmedRoom(req.Room),
+	}
+	members, err := s.buildMembers(ctx, req.TermID, req.Members)
+	if err != nil {
+		return nil, err
+	}
+	group.Members = members
+	if err := s.ensureUniqueName(ctx, group.TermID, group.Name, ""); err != nil {
+		return nil, err
+	}
+	if err := s.store.Create(ctx, group); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create lesson group")
+	}
+	return group, nil
+}
+
+// Update renames a group, changes its room and replaces its members. The member classes cannot
+// change while the group has sessions; the selected students can, as long as every session still
+// leaves each class free of overlapping groups.
+func (s *LessonGroupService) Update(ctx context.Context, id string, req dto.LessonGroupRequest) (*models.LessonGroup, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group payload")
+	}
+	group, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.buildMembers(ctx, group.TermID, req.Members)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.listSessions(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *group
+	updated.Name = strings.TrimSpace(req.Name)
+	updated.Room = trimmedRoom(req.Room)
+	updated.Members = members
+	if err := s.ensureUniqueName(ctx, group.TermID, updated.Name, group.ID); err != nil {
+		return nil, err
+	}
+	if len(sessions) > 0 {
+		if !sameMemberClasses(group.Members, members) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "member classes cannot change while the group has sessions; remove its sessions first")
+		}
+		for _, session := range groupSessions(sessions) {
+			if err := s.ensureSessionFree(ctx, &updated, session.DayOfWeek, session.TimeSlot, session.Room); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := s.store.Update(ctx, &updated); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "lesson group not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update lesson group")
+	}
+	return &updated, nil
+}
+
+// Delete removes a lesson group without sessions.
+func (s *LessonGroupService) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	sessions, err := s.listSessions(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(sessions) > 0 {
+		return appErrors.Clone(appErrors.ErrConflict, "lesson group still has sessions; remove them first")
+	}
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "lesson group not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete lesson group")
+	}
+	return nil
+}
+
+// Sessions lists the group's weekly sessions.
+func (s *LessonGroupService) Sessions(ctx context.Context, id string) ([]dto.LessonGroupSession, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	schedules, err := s.listSessions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return groupSessions(schedules), nil
+}
+
+// AddSession books a weekly session for every member class. It fails when any member class, the
+// teacher or the room is already busy in the slot; a class may attend another group at the same time
+// only when the two groups take disjoint subsets of its students.
+func (s *LessonGroupService) AddSession(ctx context.Context, id string, req dto.LessonGroupSessionRequest) (*dto.LessonGroupSession, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group session payload")
+	}
+	day := strings.ToUpper(strings.TrimSpace(req.DayOfWeek))
+	if dayStringToIndex(day) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid dayOfWeek")
+	}
+	group, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.listSessions(ctx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if session.DayOfWeek == day && session.TimeSlot == req.TimeSlot {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "lesson group already meets in this slot")
+		}
+	}
+	room := strings.TrimSpace(req.Room)
+	if room == "" && group.Room != nil {
+		room = *group.Room
+	}
+	if room == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "room is required when the group has no default room")
+	}
+	if err := s.ensureSessionFree(ctx, group, day, req.TimeSlot, room); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]models.Schedule, len(group.Members))
+	for i, member := range group.Members {
+		schedules[i] = models.Schedule{
+			TermID:        group.TermID,
+			ClassID:       member.ClassID,
+			SubjectID:     group.SubjectID,
+			TeacherID:     group.TeacherID,
+			DayOfWeek:     day,
+			TimeSlot:      req.TimeSlot,
+			Room:          room,
+			LessonGroupID: &group.ID,
+		}
+	}
+	if err := s.schedules.BulkCreate(ctx, schedules); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create lesson group session")
+	}
+	session := groupSessions(schedules)[0]
+	return &session, nil
+}
+
+// RemoveSession deletes the group's session in a weekly slot from every member class.
+func (s *LessonGroupService) RemoveSession(ctx context.Context, id string, req dto.LessonGroupSessionRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group session payload")
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := s.store.DeleteSession(ctx, id, strings.ToUpper(strings.TrimSpace(req.DayOfWeek)), req.TimeSlot); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "lesson group session not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete lesson group session")
+	}
+	return nil
+}
+
+// Roster lists the students taking part in the group. Teachers may only view their own groups.
+func (s *LessonGroupService) Roster(ctx context.Context, id string, claims *models.JWTClaims) ([]dto.LessonGroupStudent, error) {
+	group, err := s.authorizedGroup(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.roster(ctx, group)
+}
+
+// MarkAttendance records the marks of one session. Each student is recorded against their own class's
+// schedule, so the marks show up in class attendance like any other lesson.
+func (s *LessonGroupService) MarkAttendance(ctx context.Context, id string, req dto.LessonGroupAttendanceRequest, claims *models.JWTClaims) (*BulkAttendanceResult, error) {
+	if s.attendance == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "attendance is disabled")
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group attendance payload")
+	}
+	group, err := s.authorizedGroup(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	scheduleByClass, err := s.sessionOn(ctx, group.ID, req.Date, req.TimeSlot)
+	if err != nil {
+		return nil, err
+	}
+	roster, err := s.roster(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	classOf := make(map[string]string, len(roster))
+	for _, student := range roster {
+		classOf[student.EnrollmentID] = student.ClassID
+	}
+
+	items := make([]BulkSubjectAttendanceItem, len(req.Items))
+	for i, item := range req.Items {
+		classID, ok := classOf[item.EnrollmentID]
+		if !ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("enrollment %s is not part of the lesson group", item.EnrollmentID))
+		}
+		items[i] = BulkSubjectAttendanceItem{
+			EnrollmentID: item.EnrollmentID,
+			Status:       item.Status,
+			Notes:        item.Notes,
+			ScheduleID:   scheduleByClass[classID],
+		}
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = string(models.BulkModeAtomic)
+	}
+	return s.attendance.BulkMarkSubject(ctx, BulkMarkSubjectAttendanceRequest{
+		ScheduleID: items[0].ScheduleID,
+		Date:       req.Date,
+		Mode:       mode,
+		Items:      items,
+		Makeup:     req.Makeup,
+	})
+}
+
+// AttendanceReport lists every student of a session with their mark, unmarked students included.
+func (s *LessonGroupService) AttendanceReport(ctx context.Context, id string, query dto.LessonGroupAttendanceQuery, claims *models.JWTClaims) (*dto.LessonGroupAttendanceReport, error) {
+	if s.attendance == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "attendance is disabled")
+	}
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson group attendance query")
+	}
+	group, err := s.authorizedGroup(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	scheduleByClass, err := s.sessionOn(ctx, group.ID, query.Date, query.TimeSlot)
+	if err != nil {
+		return nil, err
+	}
+	date, _ := time.Parse("2006-01-02", query.Date)
+	roster, err := s.roster(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	marks := make(map[string]models.SubjectAttendanceReportRow)
+	for _, member := range group.Members {
+		rows, err := s.attendance.SubjectSessionReport(ctx, scheduleByClass[member.ClassID], date)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			marks[row.EnrollmentID] = row
+		}
+	}
+	report := &dto.LessonGroupAttendanceReport{
+		GroupID:  group.ID,
+		Date:     query.Date,
+		TimeSlot: query.TimeSlot,
+		Rows:     make([]dto.LessonGroupAttendanceRow, 0, len(roster)),
+	}
+	for _, student := range roster {
+		row := dto.LessonGroupAttendanceRow{
+			EnrollmentID: student.EnrollmentID,
+			StudentID:    student.StudentID,
+			ClassID:      student.ClassID,
+			ScheduleID:   scheduleByClass[student.ClassID],
+		}
+		if mark, ok := marks[student.EnrollmentID]; ok {
+			row.StudentName = mark.StudentName
+			row.Status = string(mark.Status)
+			row.Notes = mark.Notes
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+func (s *LessonGroupService) ensureReferences(ctx context.Context, req dto.LessonGroupRequest) error {
+	if s.terms != nil {
+		if _, err := s.terms.FindByID(ctx, req.TermID); err != nil {
+			return lookupError(err, "term")
+		}
+	}
+	if s.subjects != nil {
+		if _, err := s.subjects.FindByID(ctx, req.SubjectID); err != nil {
+			return lookupError(err, "subject")
+		}
+	}
+	if s.teachers != nil {
+		if _, err := s.teachers.FindByID(ctx, req.TeacherID); err != nil {
+			return lookupError(err, "teacher")
+		}
+	}
+	return nil
+}
+
+func (s *LessonGroupService) ensureUniqueName(ctx context.Context, termID, name, ignoreID string) error {
+	groups, err := s.store.ListByTerm(ctx, termID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson groups")
+	}
+	for _, group := range groups {
+		if group.ID != ignoreID && strings.EqualFold(group.Name, name) {
+			return appErrors.Clone(appErrors.ErrConflict, "a lesson group with this name already exists in the term")
+		}
+	}
+	return nil
+}
+
+// lookupError reports a failed lookup of a referenced record as not found or internal.
+func lookupError(err error, what string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return appErrors.Clone(appErrors.ErrNotFound, what+" not found")
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load "+what)
+}
+
+// buildMembers checks that the classes are distinct and exist and that selected students are active
+// enrollments of their class in the term.
+func (s *LessonGroupService) buildMembers(ctx context.Context, termID string, reqs []dto.LessonGroupMemberRequest) ([]models.LessonGroupMember, error) {
+	members := make([]models.LessonGroupMember, 0, len(reqs))
+	seen := make(map[string]struct{}, len(reqs))
+	for _, req := range reqs {
+		if _, ok := seen[req.ClassID]; ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("class %s is listed twice", req.ClassID))
+		}
+		seen[req.ClassID] = struct{}{}
+		if s.classes != nil {
+			if _, err := s.classes.FindByID(ctx, req.ClassID); err != nil {
+				return nil, lookupError(err, "class")
+			}
+		}
+		member := models.LessonGroupMember{ClassID: req.ClassID}
+		if len(req.EnrollmentIDs) > 0 {
+			enrollments, err := s.enrollments.ListByClassAndTerm(ctx, req.ClassID, termID)
+			if err != nil {
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class enrollments")
+			}
+			active := make(map[string]struct{}, len(enrollments))
+			for _, enrollment := range enrollments {
+				active[enrollment.ID] = struct{}{}
+			}
+			selected := make(map[string]struct{}, len(req.EnrollmentIDs))
+			for _, enrollmentID := range req.EnrollmentIDs {
+				if _, ok := active[enrollmentID]; !ok {
+					return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("enrollment %s is not active in class %s", enrollmentID, req.ClassID))
+				}
+				if _, ok := selected[enrollmentID]; ok {
+					continue
+				}
+				selected[enrollmentID] = struct{}{}
+				member.EnrollmentIDs = append(member.EnrollmentIDs, enrollmentID)
+			}
+			sort.Strings(member.EnrollmentIDs)
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// ensureSessionFree checks a session of group in the slot against the schedules already booked
+// there, ignoring the group's own sessions.
+func (s *LessonGroupService) ensureSessionFree(ctx context.Context, group *models.LessonGroup, day, slot, room string) error {
+	existing, err := s.schedules.FindConflicts(ctx, group.TermID, day, slot)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check schedule conflicts")
+	}
+	others := make(map[string]*models.LessonGroup)
+	for _, item := range existing {
+		if item.LessonGroupID != nil && *item.LessonGroupID == group.ID {
+			continue
+		}
+		if member, ok := group.Member(item.ClassID); ok {
+			shared, err := s.sharesStudents(ctx, others, member, item)
+			if err != nil {
+				return err
+			}
+			if shared {
+				return wrapScheduleConflict("CLASS", "class already scheduled for this slot", item)
+			}
+		}
+		if item.TeacherID == group.TeacherID {
+			return wrapScheduleConflict("TEACHER", "teacher already scheduled for this slot", item)
+		}
+		if strings.EqualFold(strings.TrimSpace(item.Room), room) {
+			return wrapScheduleConflict("ROOM", "room already booked for this slot", item)
+		}
+	}
+	return nil
+}
+
+// sharesStudents reports whether member's students also attend the existing schedule of their
+// class. Only two lesson groups taking disjoint subsets of a class can meet at the same time.
+func (s *LessonGroupService) sharesStudents(ctx context.Context, cache map[string]*models.LessonGroup, member models.LessonGroupMember, existing models.Schedule) (bool, error) {
+	if existing.LessonGroupID == nil || member.WholeClass() {
+		return true, nil
+	}
+	other, ok := cache[*existing.LessonGroupID]
+	if !ok {
+		loaded, err := s.store.FindByID(ctx, *existing.LessonGroupID)
+		if err != nil {
+			return false, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load lesson group")
+		}
+		cache[loaded.ID] = loaded
+		other = loaded
+	}
+	otherMember, ok := other.Member(existing.ClassID)
+	if !ok || otherMember.WholeClass() {
+		return true, nil
+	}
+	selected := make(map[string]struct{}, len(member.EnrollmentIDs))
+	for _, enrollmentID := range member.EnrollmentIDs {
+		selected[enrollmentID] = struct{}{}
+	}
+	for _, enrollmentID := range otherMember.EnrollmentIDs {
+		if _, ok := selected[enrollmentID]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *LessonGroupService) listSessions(ctx context.Context, id string) ([]models.Schedule, error) {
+	schedules, err := s.store.ListSessions(ctx, id)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson group sessions")
+	}
+	return schedules, nil
+}
+
+// sessionOn maps each member class to its schedule for the session held on date in slot.
+func (s *LessonGroupService) sessionOn(ctx context.Context, groupID, date, slot string) (map[string]string, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid date format, expected YYYY-MM-DD")
+	}
+	schedules, err := s.listSessions(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	weekday := strings.ToUpper(day.Weekday().String())
+	scheduleByClass := make(map[string]string)
+	for _, schedule := range schedules {
+		if strings.EqualFold(schedule.DayOfWeek, weekday) && schedule.TimeSlot == slot {
+			scheduleByClass[schedule.ClassID] = schedule.ID
+		}
+	}
+	if len(scheduleByClass) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "lesson group has no session on that day and slot")
+	}
+	return scheduleByClass, nil
+}
+
+func (s *LessonGroupService) authorizedGroup(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonGroup, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	group, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role == models.RoleTeacher && group.TeacherID != claims.UserID {
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "you can only access your own lesson groups")
+	}
+	return group, nil
+}
+
+// roster expands the members into students, taking whole classes from their active enrollments.
+func (s *LessonGroupService) roster(ctx context.Context, group *models.LessonGroup) ([]dto.LessonGroupStudent, error) {
+	students := make([]dto.LessonGroupStudent, 0)
+	for _, member := range group.Members {
+		enrollments, err := s.enrollments.ListByClassAndTerm(ctx, member.ClassID, group.TermID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class enrollments")
+		}
+		selected := make(map[string]struct{}, len(member.EnrollmentIDs))
+		for _, enrollmentID := range member.EnrollmentIDs {
+			selected[enrollmentID] = struct{}{}
+		}
+		for _, enrollment := range enrollments {
+			if _, ok := selected[enrollment.ID]; !member.WholeClass() && !ok {
+				continue
+			}
+			students = append(students, dto.LessonGroupStudent{
+				EnrollmentID: enrollment.ID,
+				StudentID:    enrollment.StudentID,
+				ClassID:      member.ClassID,
+			})
+		}
+	}
+	return students, nil
+}
+
+// groupSessions folds the per-class schedules into sessions ordered by day and slot.
+func groupSessions(schedules []models.Schedule) []dto.LessonGroupSession {
+	index := make(map[string]int)
+	sessions := make([]dto.LessonGroupSession, 0)
+	for _, schedule := range schedules {
+		key := schedule.DayOfWeek + "|" + schedule.TimeSlot
+		i, ok := index[key]
+		if !ok {
+			i = len(sessions)
+			index[key] = i
+			sessions = append(sessions, dto.LessonGroupSession{
+				DayOfWeek:   schedule.DayOfWeek,
+				TimeSlot:    schedule.TimeSlot,
+				Room:        schedule.Room,
+				ScheduleIDs: map[string]string{},
+			})
+		}
+		sessions[i].ScheduleIDs[schedule.ClassID] = schedule.ID
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, b := dayStringToIndex(sessions[i].DayOfWeek), dayStringToIndex(sessions[j].DayOfWeek)
+		if a != b {
+			return a < b
+		}
+		return parseTimeSlot(sessions[i].TimeSlot) < parseTimeSlot(sessions[j].TimeSlot)
+	})
+	return sessions
+}
+
+func sameMemberClasses(a, b []models.LessonGroupMember) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	classes := make(map[string]struct{}, len(a))
+	for _, member := range a {
+		classes[member.ClassID] = struct{}{}
+	}
+	for _, member := range b {
+		if _, ok := classes[member.ClassID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func trimmedRoom(room *string) *string {
+	if room == nil {
+		return nil
+	}
+	value := strings.TrimSpace(*room)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
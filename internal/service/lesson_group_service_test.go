@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type lessonGroupStoreStub struct {
+	groups    map[string]*models.LessonGroup
+	schedules []models.Schedule
+}
+
+func (s *lessonGroupStoreStub) Create(_ context.Context, group *models.LessonGroup) error {
+	group.ID = "group-new"
+	s.groups[group.ID] = group
+	return nil
+}
+
+func (s *lessonGroupStoreStub) Update(_ context.Context, group *models.LessonGroup) error {
+	s.groups[group.ID] = group
+	return nil
+}
+
+func (s *lessonGroupStoreStub) FindByID(_ context.Context, id string) (*models.LessonGroup, error) {
+	group, ok := s.groups[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *group
+	return &copied, nil
+}
+
+func (s *lessonGroupStoreStub) ListByTerm(_ context.Context, termID string) ([]models.LessonGroup, error) {
+	var groups []models.LessonGroup
+	for _, group := range s.groups {
+		if group.TermID == termID {
+			groups = append(groups, *group)
+		}
+	}
+	return groups, nil
+}
+
+func (s *lessonGroupStoreStub) Delete(_ context.Context, id string) error {
+	delete(s.groups, id)
+	return nil
+}
+
+func (s *lessonGroupStoreStub) ListSessions(_ context.Context, groupID string) ([]models.Schedule, error) {
+	var sessions []models.Schedule
+	for _, schedule := range s.schedules {
+		if schedule.LessonGroupID != nil && *schedule.LessonGroupID == groupID {
+			sessions = append(sessions, schedule)
+		}
+	}
+	return sessions, nil
+}
+
+func (s *lessonGroupStoreStub) DeleteSession(context.Context, string, string, string) error {
+	return nil
+}
+
+func (s *lessonGroupStoreStub) FindConflicts(_ context.Context, termID, day, slot string) ([]models.Schedule, error) {
+	var matches []models.Schedule
+	for _, schedule := range s.schedules {
+		if schedule.TermID == termID && schedule.DayOfWeek == day && schedule.TimeSlot == slot {
+			matches = append(matches, schedule)
+		}
+	}
+	return matches, nil
+}
+
+func (s *lessonGroupStoreStub) BulkCreate(_ context.Context, schedules []models.Schedule) error {
+	for i := range schedules {
+		schedules[i].ID = "sched-" + schedules[i].ClassID
+		s.schedules = append(s.schedules, schedules[i])
+	}
+	return nil
+}
+
+type lessonGroupEnrollmentStub map[string][]models.Enrollment
+
+func (s lessonGroupEnrollmentStub) ListByClassAndTerm(_ context.Context, classID, _ string) ([]models.Enrollment, error) {
+	return s[classID], nil
+}
+
+type lessonGroupAttendanceStub struct {
+	marked  []BulkMarkSubjectAttendanceRequest
+	reports map[string][]models.SubjectAttendanceReportRow
+}
+
+func (s *lessonGroupAttendanceStub) BulkMarkSubject(_ context.Context, req BulkMarkSubjectAttendanceRequest) (*BulkAttendanceResult, error) {
+	s.marked = append(s.marked, req)
+	return &BulkAttendanceResult{Processed: len(req.Items), Success: len(req.Items)}, nil
+}
+
+func (s *lessonGroupAttendanceStub) SubjectSessionReport(_ context.Context, scheduleID string, _ time.Time) ([]models.SubjectAttendanceReportRow, error) {
+	return s.reports[scheduleID], nil
+}
+
+func newLessonGroupFixture() (*LessonGroupService, *lessonGroupStoreStub, *lessonGroupAttendanceStub) {
+	room := "Hall"
+	store := &lessonGroupStoreStub{groups: map[string]*models.LessonGroup{
+		"group-catholic": {
+			ID: "group-catholic", TermID: "term-1", SubjectID: "religion", TeacherID: "teacher-1", Name: "Catholic", Room: &room,
+			Members: []models.LessonGroupMember{
+				{ClassID: "class-a", EnrollmentIDs: []string{"enr-a1"}},
+				{ClassID: "class-b", EnrollmentIDs: []string{"enr-b1"}},
+			},
+		},
+		"group-islam": {
+			ID: "group-islam", TermID: "term-1", SubjectID: "religion", TeacherID: "teacher-2", Name: "Islam",
+			Members: []models.LessonGroupMember{
+				{ClassID: "class-a", EnrollmentIDs: []string{"enr-a2"}},
+			},
+		},
+	}}
+	enrollments := lessonGroupEnrollmentStub{
+		"class-a": {{ID: "enr-a1", StudentID: "stu-a1"}, {ID: "enr-a2", StudentID: "stu-a2"}},
+		"class-b": {{ID: "enr-b1", StudentID: "stu-b1"}, {ID: "enr-b2", StudentID: "stu-b2"}},
+	}
+	attendance := &lessonGroupAttendanceStub{}
+	svc := NewLessonGroupService(LessonGroupServiceParams{
+		Store:       store,
+		Schedules:   store,
+		Enrollments: enrollments,
+		Attendance:  attendance,
+	})
+	return svc, store, attendance
+}
+
+func TestLessonGroupServiceCreateValidatesMembers(t *testing.T) {
+	svc, _, _ := newLessonGroupFixture()
+	req := dto.LessonGroupRequest{
+		TermID: "term-1", SubjectID: "language", TeacherID: "teacher-3", Name: "German",
+		Members: []dto.LessonGroupMemberRequest{
+			{ClassID: "class-a", EnrollmentIDs: []string{"enr-a1"}},
+			{ClassID: "class-b"},
+		},
+	}
+	group, err := svc.Create(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "group-new", group.ID)
+	assert.True(t, group.Members[1].WholeClass())
+
+	req.Name = "catholic"
+	_, err = svc.Create(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	req.Name = "French"
+	req.Members[0].EnrollmentIDs = []string{"enr-b1"}
+	_, err = svc.Create(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestLessonGroupServiceAddSessionChecksMemberClasses(t *testing.T) {
+	svc, store, _ := newLessonGroupFixture()
+	islam := "group-islam"
+	store.schedules = []models.Schedule{
+		// The Islam group takes a different subset of class-a at the same time.
+		{ID: "s-islam", TermID: "term-1", ClassID: "class-a", TeacherID: "teacher-2", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R1", LessonGroupID: &islam},
+		{ID: "s-math", TermID: "term-1", ClassID: "class-b", TeacherID: "teacher-9", DayOfWeek: "MONDAY", TimeSlot: "2", Room: "R2"},
+	}
+
+	session, err := svc.AddSession(context.Background(), "group-catholic", dto.LessonGroupSessionRequest{DayOfWeek: "monday", TimeSlot: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hall", session.Room)
+	assert.Equal(t, map[string]string{"class-a": "sched-class-a", "class-b": "sched-class-b"}, session.ScheduleIDs)
+
+	// class-b has a regular lesson in slot 2.
+	_, err = svc.AddSession(context.Background(), "group-catholic", dto.LessonGroupSessionRequest{DayOfWeek: "MONDAY", TimeSlot: "2"})
+	require.Error(t, err)
+	var conflict *models.ScheduleConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "CLASS", conflict.Type)
+	assert.Equal(t, "s-math", conflict.Conflict.ScheduleID)
+
+	// Sharing a student with the Islam group is a clash too.
+	store.schedules = store.schedules[:2]
+	store.groups["group-catholic"].Members[0].EnrollmentIDs = []string{"enr-a1", "enr-a2"}
+	_, err = svc.AddSession(context.Background(), "group-catholic", dto.LessonGroupSessionRequest{DayOfWeek: "MONDAY", TimeSlot: "1", Room: "Chapel"})
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, "s-islam", conflict.Conflict.ScheduleID)
+}
+
+func TestLessonGroupServiceUpdateKeepsClassesWhileScheduled(t *testing.T) {
+	svc, store, _ := newLessonGroupFixture()
+	catholic := "group-catholic"
+	store.schedules = []models.Schedule{
+		{ID: "s1", TermID: "term-1", ClassID: "class-a", TeacherID: "teacher-1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "Hall", LessonGroupID: &catholic},
+		{ID: "s2", TermID: "term-1", ClassID: "class-b", TeacherID: "teacher-1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "Hall", LessonGroupID: &catholic},
+	}
+	req := dto.LessonGroupRequest{
+		TermID: "term-1", SubjectID: "religion", TeacherID: "teacher-1", Name: "Catholic",
+		Members: []dto.LessonGroupMemberRequest{{ClassID: "class-a", EnrollmentIDs: []string{"enr-a1"}}, {ClassID: "class-c"}},
+	}
+	_, err := svc.Update(context.Background(), "group-catholic", req)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	req.Members[1] = dto.LessonGroupMemberRequest{ClassID: "class-b"}
+	group, err := svc.Update(context.Background(), "group-catholic", req)
+	require.NoError(t, err)
+	assert.True(t, group.Members[1].WholeClass())
+
+	err = svc.Delete(context.Background(), "group-catholic")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestLessonGroupServiceMarkAttendanceUsesClassSchedules(t *testing.T) {
+	svc, store, attendance := newLessonGroupFixture()
+	catholic := "group-catholic"
+	store.schedules = []models.Schedule{
+		{ID: "s1", TermID: "term-1", ClassID: "class-a", TeacherID: "teacher-1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "Hall", LessonGroupID: &catholic},
+		{ID: "s2", TermID: "term-1", ClassID: "class-b", TeacherID: "teacher-1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "Hall", LessonGroupID: &catholic},
+	}
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+	// 2026-07-13 is a Monday.
+	req := dto.LessonGroupAttendanceRequest{
+		Date: "2026-07-13", TimeSlot: "1",
+		Items: []dto.LessonGroupAttendanceItem{{EnrollmentID: "enr-a1", Status: "H"}, {EnrollmentID: "enr-b1", Status: "S"}},
+	}
+	result, err := svc.MarkAttendance(context.Background(), "group-catholic", req, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Success)
+	require.Len(t, attendance.marked, 1)
+	assert.Equal(t, string(models.BulkModeAtomic), attendance.marked[0].Mode)
+	assert.Equal(t, "s1", attendance.marked[0].Items[0].ScheduleID)
+	assert.Equal(t, "s2", attendance.marked[0].Items[1].ScheduleID)
+
+	// enr-b2 is in class-b but not in the group.
+	req.Items = []dto.LessonGroupAttendanceItem{{EnrollmentID: "enr-b2", Status: "H"}}
+	_, err = svc.MarkAttendance(context.Background(), "group-catholic", req, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	req.Date = "2026-07-14"
+	req.Items = []dto.LessonGroupAttendanceItem{{EnrollmentID: "enr-a1", Status: "H"}}
+	_, err = svc.MarkAttendance(context.Background(), "group-catholic", req, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.MarkAttendance(context.Background(), "group-catholic", req, &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	attendance.reports = map[string][]models.SubjectAttendanceReportRow{
+		"s2": {{EnrollmentID: "enr-b1", StudentID: "stu-b1", StudentName: "Budi", Status: models.AttendanceStatusSick}},
+	}
+	report, err := svc.AttendanceReport(context.Background(), "group-catholic", dto.LessonGroupAttendanceQuery{Date: "2026-07-13", TimeSlot: "1"}, teacher)
+	require.NoError(t, err)
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, "enr-a1", report.Rows[0].EnrollmentID)
+	assert.Empty(t, report.Rows[0].Status)
+	assert.Equal(t, "S", report.Rows[1].Status)
+	assert.Equal(t, "s2", report.Rows[1].ScheduleID)
+}
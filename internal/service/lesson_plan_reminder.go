@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+)
+
+type lessonPlanGapLister interface {
+	ListGaps(ctx context.Context, termID string, weekStart time.Time) ([]models.LessonPlanGap, error)
+}
+
+// LessonPlanReminderConfig tunes when teachers are reminded about missing lesson plans.
+type LessonPlanReminderConfig struct {
+	// Interval between checks.
+	Interval time.Duration
+	// DeadlineDays is how many days before the Monday of a week its plans are due.
+	DeadlineDays int
+	// Lead is how long before the deadline reminders start going out.
+	Lead time.Duration
+}
+
+// LessonPlanReminder notifies subject teachers who have not submitted next week's lesson plans once
+// the deadline approaches. Each teacher is reminded at most once per week.
+type LessonPlanReminder struct {
+	gaps          lessonPlanGapLister
+	terms         ports.ActiveTermReader
+	notifications notificationWriter
+	cfg           LessonPlanReminderConfig
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewLessonPlanReminder constructs a LessonPlanReminder with defaults.
+func NewLessonPlanReminder(gaps lessonPlanGapLister, terms ports.ActiveTermReader, notifications notificationWriter, cfg LessonPlanReminderConfig, logger *zap.Logger) *LessonPlanReminder {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.DeadlineDays < 0 {
+		cfg.DeadlineDays = 0
+	}
+	if cfg.Lead <= 0 {
+		cfg.Lead = 48 * time.Hour
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LessonPlanReminder{
+		gaps:          gaps,
+		terms:         terms,
+		notifications: notifications,
+		cfg:           cfg,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Start checks once and then on every interval until ctx is done.
+func (r *LessonPlanReminder) Start(ctx context.Context) {
+	go r.run(ctx)
+}
+
+func (r *LessonPlanReminder) run(ctx context.Context) {
+	r.Remind(ctx)
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Remind(ctx)
+		}
+	}
+}
+
+// Remind notifies teachers with missing plans for the coming week when its reminder window is open
+// and returns how many notifications were written.
+func (r *LessonPlanReminder) Remind(ctx context.Context) int {
+	now := r.now().UTC()
+	weekStart := weekMonday(now).AddDate(0, 0, 7)
+	deadline := weekStart.AddDate(0, 0, -r.cfg.DeadlineDays)
+	if now.Before(deadline.Add(-r.cfg.Lead)) {
+		return 0
+	}
+	term, err := r.terms.FindActive(ctx)
+	if err != nil || term == nil {
+		r.logger.Warn("lesson plan reminder skipped: no active term", zap.Error(err))
+		return 0
+	}
+	if !term.EndDate.IsZero() && weekStart.After(dateOnly(term.EndDate)) {
+		return 0
+	}
+	gaps, err := r.gaps.ListGaps(ctx, term.ID, weekStart)
+	if err != nil {
+		r.logger.Warn("lesson plan reminder could not list missing plans", zap.Error(err))
+		return 0
+	}
+	missing := make(map[string]int)
+	order := make([]string, 0)
+	for _, gap := range gaps {
+		if _, ok := missing[gap.TeacherID]; !ok {
+			order = append(order, gap.TeacherID)
+		}
+		missing[gap.TeacherID]++
+	}
+
+	week := weekStart.Format("2006-01-02")
+	sent := 0
+	for _, teacherID := range order {
+		if ctx.Err() != nil {
+			break
+		}
+		key := fmt.Sprintf("lesson-plan-reminder:%s", week)
+		created, err := r.notifications.CreateOnce(ctx, &models.Notification{
+			UserID:    teacherID,
+			Type:      models.NotificationTypeLessonPlanReminder,
+			Title:     "Lesson plans due",
+			Body:      fmt.Sprintf("%d lesson plan(s) for the week of %s are due by %s.", missing[teacherID], week, deadline.Format("2006-01-02")),
+			DedupeKey: &key,
+		})
+		if err != nil {
+			r.logger.Warn("lesson plan reminder failed", zap.String("teacher_id", teacherID), zap.Error(err))
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+	r.logger.Debug("lesson plan reminders sent", zap.String("week", week), zap.Int("count", sent))
+	return sent
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// LessonPlanAttachmentCategory is the archive category lesson plan attachments are filed under.
+const LessonPlanAttachmentCategory = "lesson_plan"
+
+type lessonPlanStore interface {
+	List(ctx context.Context, filter models.LessonPlanFilter) ([]models.LessonPlan, error)
+	FindByID(ctx context.Context, id string) (*models.LessonPlan, error)
+	Create(ctx context.Context, plan *models.LessonPlan) error
+	Update(ctx context.Context, plan *models.LessonPlan) error
+	Submit(ctx context.Context, id string, submittedAt time.Time) error
+	Review(ctx context.Context, params repository.ReviewLessonPlanParams) error
+	Delete(ctx context.Context, id string) error
+}
+
+type lessonPlanAttachmentUploader interface {
+	UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error)
+}
+
+type notificationWriter interface {
+	CreateOnce(ctx context.Context, notification *models.Notification) (bool, error)
+}
+
+// LessonPlanServiceParams groups constructor dependencies. Attachments is nil when archives are
+// disabled; Notifications and Audit are optional.
+type LessonPlanServiceParams struct {
+	Store         lessonPlanStore
+	Terms         ports.TermReader
+	Subjects      ports.SubjectReader
+	Classes       ports.ClassReader
+	Assignments   ports.TeacherAssignmentChecker
+	Attachments   lessonPlanAttachmentUploader
+	Notifications notificationWriter
+	Audit         ports.AuditLogger
+	Validator     *validator.Validate
+	Logger        *zap.Logger
+}
+
+// LessonPlanService lets teachers draft weekly lesson plans for the classes they teach and submit
+// them for review. Reviewers approve or reject submitted plans; rejected plans can be edited and
+// resubmitted.
+type LessonPlanService struct {
+	store         lessonPlanStore
+	terms         ports.TermReader
+	subjects      ports.SubjectReader
+	classes       ports.ClassReader
+	assignments   ports.TeacherAssignmentChecker
+	attachments   lessonPlanAttachmentUploader
+	notifications notificationWriter
+	audit         ports.AuditLogger
+	validator     *validator.Validate
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewLessonPlanService constructs the service.
+func NewLessonPlanService(params LessonPlanServiceParams) *LessonPlanService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LessonPlanService{
+		store:         params.Store,
+		terms:         params.Terms,
+		subjects:      params.Subjects,
+		classes:       params.Classes,
+		assignments:   params.Assignments,
+		attachments:   params.Attachments,
+		notifications: params.Notifications,
+		audit:         params.Audit,
+		validator:     validate,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// List returns lesson plans matching the query. Teachers are restricted to their own plans.
+func (s *LessonPlanService) List(ctx context.Context, query dto.LessonPlanQuery, claims *models.JWTClaims) ([]models.LessonPlan, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	filter := models.LessonPlanFilter{
+		TeacherID: query.TeacherID,
+		ClassID:   query.ClassID,
+		SubjectID: query.SubjectID,
+		TermID:    query.TermID,
+	}
+	if claims.Role == models.RoleTeacher {
+		filter.TeacherID = claims.UserID
+	}
+	if query.Status != "" {
+		status := models.LessonPlanStatus(strings.ToUpper(query.Status))
+		if !validLessonPlanStatus(status) {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "status must be DRAFT, SUBMITTED, APPROVED or REJECTED")
+		}
+		filter.Status = []models.LessonPlanStatus{status}
+	}
+	if query.Week != "" {
+		week, err := time.Parse("2006-01-02", query.Week)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "week must use YYYY-MM-DD format")
+		}
+		monday := weekMonday(week)
+		filter.WeekStart = &monday
+	}
+	plans, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list lesson plans")
+	}
+	if plans == nil {
+		plans = []models.LessonPlan{}
+	}
+	return plans, nil
+}
+
+// Get returns a single plan. Teachers can only read their own plans.
+func (s *LessonPlanService) Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	plan, err := s.plan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensurePlanReader(plan, claims); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Create drafts a plan for one week of a class/subject the teacher is assigned to. A teacher has at
+// most one plan per class, subject and week.
+func (s *LessonPlanService) Create(ctx context.Context, req dto.LessonPlanRequest, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if claims.Role != models.RoleTeacher {
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "only teachers can write lesson plans")
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson plan payload")
+	}
+	week, err := time.Parse("2006-01-02", req.WeekStart)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "weekStart must use YYYY-MM-DD format")
+	}
+	weekStart := weekMonday(week)
+	term, err := s.terms.FindByID(ctx, req.TermID)
+	if err != nil {
+		return nil, referenceError(err, "term")
+	}
+	if weekStart.AddDate(0, 0, 6).Before(dateOnly(term.StartDate)) || (!term.EndDate.IsZero() && weekStart.After(dateOnly(term.EndDate))) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "weekStart is outside the term")
+	}
+	if _, err := s.classes.FindByID(ctx, req.ClassID); err != nil {
+		return nil, referenceError(err, "class")
+	}
+	if _, err := s.subjects.FindByID(ctx, req.SubjectID); err != nil {
+		return nil, referenceError(err, "subject")
+	}
+	assigned, err := s.assignments.Exists(ctx, claims.UserID, req.ClassID, req.SubjectID, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify teaching assignment")
+	}
+	if !assigned {
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "you are not assigned to teach this subject in this class")
+	}
+	existing, err := s.store.List(ctx, models.LessonPlanFilter{
+		TeacherID: claims.UserID,
+		ClassID:   req.ClassID,
+		SubjectID: req.SubjectID,
+		WeekStart: &weekStart,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check existing lesson plans")
+	}
+	if len(existing) > 0 {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "a lesson plan for this class, subject and week already exists")
+	}
+	plan := &models.LessonPlan{
+		TeacherID:  claims.UserID,
+		ClassID:    req.ClassID,
+		SubjectID:  req.SubjectID,
+		TermID:     req.TermID,
+		WeekStart:  weekStart,
+		Title:      strings.TrimSpace(req.Title),
+		Objectives: req.Objectives,
+		Activities: req.Activities,
+		Assessment: req.Assessment,
+		Status:     models.LessonPlanStatusDraft,
+	}
+	if err := s.store.Create(ctx, plan); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create lesson plan")
+	}
+	return plan, nil
+}
+
+// Update edits the content of the caller's draft or rejected plan.
+func (s *LessonPlanService) Update(ctx context.Context, id string, req dto.LessonPlanUpdateRequest, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid lesson plan payload")
+	}
+	plan, err := s.editablePlan(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	plan.Title = strings.TrimSpace(req.Title)
+	plan.Objectives = req.Objectives
+	plan.Activities = req.Activities
+	plan.Assessment = req.Assessment
+	if err := s.save(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Attach stores a document for the caller's draft or rejected plan in the archive, replacing the
+// previous attachment reference. The file is filed under the plan's class so teachers of that
+// class and administrators can download it through the archive endpoints.
+func (s *LessonPlanService) Attach(ctx context.Context, id string, upload ArchiveUpload, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	if s.attachments == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "lesson plan attachments require archives to be enabled")
+	}
+	plan, err := s.editablePlan(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	classID := plan.ClassID
+	termID := plan.TermID
+	item, err := s.attachments.UploadAttachment(ctx, dto.CreateArchiveRequest{
+		Title:      plan.Title,
+		Category:   LessonPlanAttachmentCategory,
+		Scope:      models.ArchiveScopeClass,
+		RefTermID:  &termID,
+		RefClassID: &classID,
+	}, upload, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	plan.AttachmentID = &item.ID
+	if err := s.save(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Delete removes the caller's draft or rejected plan.
+func (s *LessonPlanService) Delete(ctx context.Context, id string, claims *models.JWTClaims) error {
+	plan, err := s.editablePlan(ctx, id, claims)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, plan.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "lesson plan not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete lesson plan")
+	}
+	return nil
+}
+
+// Submit sends the caller's draft or rejected plan for review.
+func (s *LessonPlanService) Submit(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	plan, err := s.editablePlan(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	if err := s.store.Submit(ctx, plan.ID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "lesson plan already submitted")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to submit lesson plan")
+	}
+	plan.Status = models.LessonPlanStatusSubmitted
+	plan.SubmittedAt = &now
+	plan.ReviewedBy = nil
+	plan.ReviewedAt = nil
+	plan.ReviewNote = nil
+	s.emitAudit(ctx, &models.AuditLog{
+		UserID:     &claims.UserID,
+		Action:     models.AuditActionLessonPlanSubmit,
+		Resource:   "lesson_plan",
+		ResourceID: &plan.ID,
+	})
+	return plan, nil
+}
+
+// Review approves or rejects a submitted plan, records the audit trail and notifies the teacher.
+func (s *LessonPlanService) Review(ctx context.Context, id string, req dto.ReviewLessonPlanRequest, reviewerID string) (*models.LessonPlan, error) {
+	plan, err := s.plan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.LessonPlanStatusSubmitted {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "lesson plan is not awaiting review")
+	}
+	status := models.LessonPlanStatus(strings.ToUpper(string(req.Status)))
+	if status != models.LessonPlanStatusApproved && status != models.LessonPlanStatusRejected {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "status must be APPROVED or REJECTED")
+	}
+	note := optionalString(req.Note)
+	if status == models.LessonPlanStatusRejected && note == nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "a note is required when rejecting a lesson plan")
+	}
+	now := s.now().UTC()
+	if err := s.store.Review(ctx, repository.ReviewLessonPlanParams{
+		ID:         plan.ID,
+		Status:     status,
+		ReviewedBy: reviewerID,
+		ReviewedAt: now,
+		Note:       note,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "lesson plan already reviewed")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to review lesson plan")
+	}
+	plan.Status = status
+	plan.ReviewedBy = &reviewerID
+	plan.ReviewedAt = &now
+	plan.ReviewNote = note
+	s.emitAudit(ctx, &models.AuditLog{
+		UserID:     &reviewerID,
+		Action:     models.AuditActionLessonPlanReview,
+		Resource:   "lesson_plan",
+		ResourceID: &plan.ID,
+		NewValues:  []byte(fmt.Sprintf(`{"status":"%s"}`, status)),
+	})
+	s.notifyReviewed(ctx, plan)
+	return plan, nil
+}
+
+func (s *LessonPlanService) notifyReviewed(ctx context.Context, plan *models.LessonPlan) {
+	if s.notifications == nil {
+		return
+	}
+	title := "Lesson plan approved"
+	if plan.Status == models.LessonPlanStatusRejected {
+		title = "Lesson plan rejected"
+	}
+	body := fmt.Sprintf("%s (week of %s)", plan.Title, plan.WeekStart.Format("2006-01-02"))
+	if plan.ReviewNote != nil {
+		body += ": " + *plan.ReviewNote
+	}
+	// The review timestamp keeps the notifications of successive review rounds apart.
+	key := fmt.Sprintf("lesson-plan-review:%s:%d", plan.ID, plan.ReviewedAt.Unix())
+	if _, err := s.notifications.CreateOnce(ctx, &models.Notification{
+		UserID:    plan.TeacherID,
+		Type:      models.NotificationTypeLessonPlanReviewed,
+		Title:     title,
+		Body:      body,
+		RefID:     &plan.ID,
+		DedupeKey: &key,
+	}); err != nil {
+		s.logger.Warn("failed to notify lesson plan review", zap.Error(err), zap.String("lesson_plan_id", plan.ID))
+	}
+}
+
+func (s *LessonPlanService) save(ctx context.Context, plan *models.LessonPlan) error {
+	if err := s.store.Update(ctx, plan); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrConflict, "lesson plan can no longer be edited")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update lesson plan")
+	}
+	return nil
+}
+
+// editablePlan loads a plan owned by the calling teacher that is still a draft or was rejected.
+func (s *LessonPlanService) editablePlan(ctx context.Context, id string, claims *models.JWTClaims) (*models.LessonPlan, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	plan, err := s.plan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.TeacherID != claims.UserID {
+		return nil, appErrors.ErrForbidden
+	}
+	if !plan.Editable() {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "lesson plan can no longer be edited")
+	}
+	return plan, nil
+}
+
+func (s *LessonPlanService) plan(ctx context.Context, id string) (*models.LessonPlan, error) {
+	plan, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "lesson plan not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load lesson plan")
+	}
+	return plan, nil
+}
+
+func (s *LessonPlanService) emitAudit(ctx context.Context, log *models.AuditLog) {
+	if s.audit == nil || log == nil {
+		return
+	}
+	log.IPAddress = "system"
+	log.UserAgent = "lesson-plan-service"
+	if err := s.audit.CreateAuditLog(ctx, log); err != nil {
+		s.logger.Warn("failed to persist audit log", zap.Error(err))
+	}
+}
+
+func ensurePlanReader(plan *models.LessonPlan, claims *models.JWTClaims) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	switch claims.Role {
+	case models.RoleAdmin, models.RoleSuperAdmin:
+		return nil
+	case models.RoleTeacher:
+		if plan.TeacherID == claims.UserID {
+			return nil
+		}
+	}
+	return appErrors.ErrForbidden
+}
+
+func validLessonPlanStatus(status models.LessonPlanStatus) bool {
+	switch status {
+	case models.LessonPlanStatusDraft, models.LessonPlanStatusSubmitted, models.LessonPlanStatusApproved, models.LessonPlanStatusRejected:
+		return true
+	}
+	return false
+}
+
+// weekMonday returns the Monday of the week containing date.
+func weekMonday(date time.Time) time.Time {
+	date = dateOnly(date)
+	return date.AddDate(0, 0, 1-isoWeekday(date))
+}
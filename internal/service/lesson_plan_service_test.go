@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type lessonPlanStoreStub struct {
+	plans     map[string]*models.LessonPlan
+	reviewErr error
+	reviewed  []repository.ReviewLessonPlanParams
+	gaps      []models.LessonPlanGap
+	gapWeek   time.Time
+}
+
+func (s *lessonPlanStoreStub) List(ctx context.Context, filter models.LessonPlanFilter) ([]models.LessonPlan, error) {
+	var result []models.LessonPlan
+	for _, plan := range s.plans {
+		if plan.TeacherID == filter.TeacherID && plan.ClassID == filter.ClassID && plan.SubjectID == filter.SubjectID &&
+			filter.WeekStart != nil && plan.WeekStart.Equal(*filter.WeekStart) {
+			result = append(result, *plan)
+		}
+	}
+	return result, nil
+}
+
+func (s *lessonPlanStoreStub) FindByID(ctx context.Context, id string) (*models.LessonPlan, error) {
+	if plan, ok := s.plans[id]; ok {
+		copied := *plan
+		return &copied, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *lessonPlanStoreStub) Create(ctx context.Context, plan *models.LessonPlan) error {
+	plan.ID = "plan-new"
+	copied := *plan
+	s.plans[plan.ID] = &copied
+	return nil
+}
+
+func (s *lessonPlanStoreStub) Update(ctx context.Context, plan *models.LessonPlan) error {
+	copied := *plan
+	s.plans[plan.ID] = &copied
+	return nil
+}
+
+func (s *lessonPlanStoreStub) Submit(ctx context.Context, id string, submittedAt time.Time) error {
+	s.plans[id].Status = models.LessonPlanStatusSubmitted
+	return nil
+}
+
+func (s *lessonPlanStoreStub) Review(ctx context.Context, params repository.ReviewLessonPlanParams) error {
+	if s.reviewErr != nil {
+		return s.reviewErr
+	}
+	s.reviewed = append(s.reviewed, params)
+	s.plans[params.ID].Status = params.Status
+	return nil
+}
+
+func (s *lessonPlanStoreStub) Delete(ctx context.Context, id string) error {
+	delete(s.plans, id)
+	return nil
+}
+
+func (s *lessonPlanStoreStub) ListGaps(ctx context.Context, termID string, weekStart time.Time) ([]models.LessonPlanGap, error) {
+	s.gapWeek = weekStart
+	return s.gaps, nil
+}
+
+type notificationWriterStub struct {
+	sent []models.Notification
+	keys map[string]bool
+}
+
+func (s *notificationWriterStub) CreateOnce(ctx context.Context, notification *models.Notification) (bool, error) {
+	if s.keys == nil {
+		s.keys = map[string]bool{}
+	}
+	key := notification.UserID + "|" + *notification.DedupeKey
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	s.sent = append(s.sent, *notification)
+	return true, nil
+}
+
+type lessonPlanAttachmentStub struct{ meta dto.CreateArchiveRequest }
+
+func (s *lessonPlanAttachmentStub) UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error) {
+	s.meta = meta
+	return &models.ArchiveItem{ID: "archive-1", UploadedBy: uploaderID}, nil
+}
+
+func newLessonPlanFixture() (*LessonPlanService, *lessonPlanStoreStub, *notificationWriterStub) {
+	store := &lessonPlanStoreStub{plans: map[string]*models.LessonPlan{
+		"plan-1": {ID: "plan-1", TeacherID: "teacher-1", ClassID: "class-1", SubjectID: "math", TermID: "term-1",
+			WeekStart: time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Title: "Quadratics", Status: models.LessonPlanStatusDraft},
+	}}
+	notifications := &notificationWriterStub{}
+	term := &models.Term{ID: "term-1",
+		StartDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 6, 26, 0, 0, 0, 0, time.UTC)}
+	svc := NewLessonPlanService(LessonPlanServiceParams{
+		Store:         store,
+		Terms:         examTermStub{term: term},
+		Subjects:      subjectLookupStub{subjects: map[string]struct{}{"math": {}}},
+		Classes:       classLookupStub{},
+		Assignments:   curriculumAssignmentStub{assigned: map[string]bool{"teacher-1|class-1|math|term-1": true}},
+		Notifications: notifications,
+	})
+	svc.now = func() time.Time { return time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC) }
+	return svc, store, notifications
+}
+
+func TestLessonPlanServiceCreate(t *testing.T) {
+	svc, _, _ := newLessonPlanFixture()
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+	req := dto.LessonPlanRequest{ClassID: "class-1", SubjectID: "math", TermID: "term-1", WeekStart: "2026-02-18", Title: " Functions "}
+
+	plan, err := svc.Create(ctx, req, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-16", plan.WeekStart.Format("2006-01-02"))
+	assert.Equal(t, "Functions", plan.Title)
+	assert.Equal(t, models.LessonPlanStatusDraft, plan.Status)
+
+	req.WeekStart = "2026-02-10"
+	_, err = svc.Create(ctx, req, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	req.ClassID = "class-2"
+	_, err = svc.Create(ctx, req, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	req.ClassID = "class-1"
+	req.WeekStart = "2026-07-06"
+	_, err = svc.Create(ctx, req, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestLessonPlanServiceReviewWorkflow(t *testing.T) {
+	svc, store, notifications := newLessonPlanFixture()
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+
+	_, err := svc.Review(ctx, "plan-1", dto.ReviewLessonPlanRequest{Status: models.LessonPlanStatusApproved}, "admin-1")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Submit(ctx, "plan-1", &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	plan, err := svc.Submit(ctx, "plan-1", teacher)
+	require.NoError(t, err)
+	assert.Equal(t, models.LessonPlanStatusSubmitted, plan.Status)
+
+	_, err = svc.Update(ctx, "plan-1", dto.LessonPlanUpdateRequest{Title: "Changed"}, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Review(ctx, "plan-1", dto.ReviewLessonPlanRequest{Status: models.LessonPlanStatusRejected}, "admin-1")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	plan, err = svc.Review(ctx, "plan-1", dto.ReviewLessonPlanRequest{Status: "rejected", Note: "Add an exit ticket"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.LessonPlanStatusRejected, plan.Status)
+	require.Len(t, store.reviewed, 1)
+	require.Len(t, notifications.sent, 1)
+	assert.Equal(t, "teacher-1", notifications.sent[0].UserID)
+	assert.Equal(t, models.NotificationTypeLessonPlanReviewed, notifications.sent[0].Type)
+	assert.Contains(t, notifications.sent[0].Body, "Add an exit ticket")
+
+	_, err = svc.Update(ctx, "plan-1", dto.LessonPlanUpdateRequest{Title: "Quadratics, revised"}, teacher)
+	require.NoError(t, err)
+	_, err = svc.Submit(ctx, "plan-1", teacher)
+	require.NoError(t, err)
+
+	store.reviewErr = sql.ErrNoRows
+	_, err = svc.Review(ctx, "plan-1", dto.ReviewLessonPlanRequest{Status: models.LessonPlanStatusApproved}, "admin-2")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestLessonPlanServiceAttach(t *testing.T) {
+	svc, store, _ := newLessonPlanFixture()
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+
+	_, err := svc.Attach(ctx, "plan-1", ArchiveUpload{Filename: "plan.pdf"}, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	attachments := &lessonPlanAttachmentStub{}
+	svc.attachments = attachments
+	plan, err := svc.Attach(ctx, "plan-1", ArchiveUpload{Filename: "plan.pdf"}, teacher)
+	require.NoError(t, err)
+	require.NotNil(t, plan.AttachmentID)
+	assert.Equal(t, "archive-1", *store.plans["plan-1"].AttachmentID)
+	assert.Equal(t, models.ArchiveScopeClass, attachments.meta.Scope)
+	assert.Equal(t, LessonPlanAttachmentCategory, attachments.meta.Category)
+	assert.Equal(t, "class-1", *attachments.meta.RefClassID)
+}
+
+func TestLessonPlanReminderRemind(t *testing.T) {
+	store := &lessonPlanStoreStub{gaps: []models.LessonPlanGap{
+		{TeacherID: "teacher-1", ClassID: "class-1", SubjectID: "math"},
+		{TeacherID: "teacher-1", ClassID: "class-2", SubjectID: "math"},
+		{TeacherID: "teacher-2", ClassID: "class-1", SubjectID: "art"},
+	}}
+	notifications := &notificationWriterStub{}
+	term := &models.Term{ID: "term-1", EndDate: time.Date(2026, 6, 26, 0, 0, 0, 0, time.UTC)}
+	reminder := NewLessonPlanReminder(store, warmerTermStub{term: term}, notifications, LessonPlanReminderConfig{DeadlineDays: 3, Lead: 48 * time.Hour}, nil)
+
+	// Monday: next week's plans are due Friday, the reminder window opens Wednesday.
+	reminder.now = func() time.Time { return time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC) }
+	assert.Equal(t, 0, reminder.Remind(context.Background()))
+
+	reminder.now = func() time.Time { return time.Date(2026, 2, 4, 9, 0, 0, 0, time.UTC) }
+	assert.Equal(t, 2, reminder.Remind(context.Background()))
+	assert.Equal(t, "2026-02-09", store.gapWeek.Format("2006-01-02"))
+	require.Len(t, notifications.sent, 2)
+	assert.Contains(t, notifications.sent[0].Body, "2 lesson plan(s) for the week of 2026-02-09 are due by 2026-02-06")
+
+	assert.Equal(t, 0, reminder.Remind(context.Background()))
+}
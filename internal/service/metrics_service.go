@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,18 +14,50 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/models"
 )
 
+// Label values used in place of unbounded or missing request labels.
+const (
+	// RouteUnmatched labels requests that matched no registered route, instead of their raw path.
+	RouteUnmatched = "unmatched"
+	// RouteOverflow labels routes seen after maxRouteLabels distinct routes were recorded.
+	RouteOverflow = "other"
+	stageNone     = "none"
+	methodOther   = "OTHER"
+	// maxRouteLabels caps the distinct route label values; the router registers far fewer.
+	maxRouteLabels = 512
+)
+
+// requestLatencyBuckets cover fast cache hits up to slow report and export requests.
+var requestLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
 // MetricsService encapsulates Prometheus instrumentation and provides lightweight snapshots for API consumption.
 type MetricsService struct {
 	registry        *prometheus.Registry
 	handler         http.Handler
 	requestDuration *prometheus.HistogramVec
 	requestTotal    *prometheus.CounterVec
+	requestLatency  *prometheus.HistogramVec
+	requestSummary  *prometheus.SummaryVec
+	inFlight        *prometheus.GaugeVec
 	cacheLatency    prometheus.Observer
 	cacheWrite      prometheus.Observer
-	cacheHitRatio   prometheus.Gauge
 	cacheHits       prometheus.Counter
 	cacheMisses     prometheus.Counter
 	dbQueryDuration *prometheus.HistogramVec
+	circuitState    *prometheus.GaugeVec
+	circuitChanges  *prometheus.CounterVec
+	panics          *prometheus.CounterVec
+	storageFree     *prometheus.GaugeVec
+	storageTotal    *prometheus.GaugeVec
+	storageHealthy  *prometheus.GaugeVec
+	mutationBacklog *prometheus.GaugeVec
+	mutationAge     prometheus.Gauge
+	compression     *prometheus.HistogramVec
+	originalBytes   *prometheus.CounterVec
+	encodedBytes    *prometheus.CounterVec
+	retentionPurged *prometheus.CounterVec
+
+	routesMu sync.Mutex
+	routes   map[string]struct{}
 
 	cacheHitCount        uint64
 	cacheMissCount       uint64
@@ -61,10 +94,23 @@ func NewMetricsService() *MetricsService {
 		Buckets: prometheus.DefBuckets,
 	})
 
-	cacheHitRatio := prometheus.NewGauge(prometheus.GaugeOpts{
-		Name: "cache_hit_ratio",
-		Help: "Ratio of cache hits to total cache lookups",
-	})
+	requestLatency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_server_request_duration_seconds",
+		Help:    "Duration of HTTP requests per route and cutover stage",
+		Buckets: requestLatencyBuckets,
+	}, []string{"route", "method", "status", "stage"})
+
+	requestSummary := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "http_server_request_duration_quantiles_seconds",
+		Help:       "Request duration quantiles over the last ten minutes per route and cutover stage",
+		Objectives: map[float64]float64{0.5: 0.05, 0.95: 0.01, 0.99: 0.001},
+		MaxAge:     10 * time.Minute,
+	}, []string{"route", "method", "stage"})
+
+	inFlight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_server_requests_in_flight",
+		Help: "HTTP requests currently being served per route and cutover stage",
+	}, []string{"route", "stage"})
 
 	cacheHits := prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "cache_hits_total",
@@ -82,6 +128,67 @@ func NewMetricsService() *MetricsService {
 		Buckets: prometheus.DefBuckets,
 	}, []string{"query"})
 
+	circuitState := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cutover_circuit_state",
+		Help: "Cutover circuit breaker state per target (0 closed, 1 half-open, 2 open)",
+	}, []string{"target"})
+
+	circuitChanges := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cutover_circuit_transitions_total",
+		Help: "Cutover circuit breaker state transitions",
+	}, []string{"target", "state"})
+
+	panics := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_panics_total",
+		Help: "Panics recovered while serving HTTP requests",
+	}, []string{"method", "path"})
+
+	storageFree := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "storage_free_bytes",
+		Help: "Bytes available to the API on the filesystem of each storage directory",
+	}, []string{"dir"})
+
+	storageTotal := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "storage_total_bytes",
+		Help: "Size of the filesystem of each storage directory",
+	}, []string{"dir"})
+
+	storageHealthy := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "storage_healthy",
+		Help: "Whether each storage directory is writable and above its free-space threshold (1) or not (0)",
+	}, []string{"dir"})
+
+	mutationBacklog := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mutations_pending",
+		Help: "Mutation requests awaiting review, by state (pending, overdue, escalated)",
+	}, []string{"state"})
+
+	mutationAge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mutations_pending_oldest_age_seconds",
+		Help: "Age of the oldest mutation request awaiting review",
+	})
+
+	compression := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_response_compression_ratio",
+		Help:    "Compressed size of compressed responses as a fraction of their original size",
+		Buckets: []float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.8, 1},
+	}, []string{"route", "encoding"})
+
+	originalBytes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_response_uncompressed_bytes_total",
+		Help: "Bytes of compressed response bodies before encoding",
+	}, []string{"encoding"})
+
+	encodedBytes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_response_compressed_bytes_total",
+		Help: "Bytes of compressed response bodies sent",
+	}, []string{"encoding"})
+
+	retentionPurged := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "retention_purged_rows_total",
+		Help: "Rows deleted by the retention purge, by data class",
+	}, []string{"data_class"})
+
 	goroutines := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
 		Name: "goroutines_total",
 		Help: "Total number of goroutines",
@@ -89,22 +196,43 @@ func NewMetricsService() *MetricsService {
 		return float64(runtime.NumGoroutine())
 	})
 
-	registry.MustRegister(requestDuration, requestTotal, cacheLatency, cacheWrite, cacheHitRatio, cacheHits, cacheMisses, dbQueryDuration, goroutines)
-
-	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-
-	return &MetricsService{
+	m := &MetricsService{
 		registry:        registry,
-		handler:         handler,
 		requestDuration: requestDuration,
 		requestTotal:    requestTotal,
+		requestLatency:  requestLatency,
+		requestSummary:  requestSummary,
+		inFlight:        inFlight,
 		cacheLatency:    cacheLatency,
 		cacheWrite:      cacheWrite,
-		cacheHitRatio:   cacheHitRatio,
 		cacheHits:       cacheHits,
 		cacheMisses:     cacheMisses,
 		dbQueryDuration: dbQueryDuration,
+		circuitState:    circuitState,
+		circuitChanges:  circuitChanges,
+		panics:          panics,
+		storageFree:     storageFree,
+		storageTotal:    storageTotal,
+		storageHealthy:  storageHealthy,
+		mutationBacklog: mutationBacklog,
+		mutationAge:     mutationAge,
+		compression:     compression,
+		originalBytes:   originalBytes,
+		encodedBytes:    encodedBytes,
+		retentionPurged: retentionPurged,
+		routes:          make(map[string]struct{}),
 	}
+
+	// The ratio is derived from the atomic counters at scrape time, so concurrent lookups can never
+	// publish a stale value over a newer one.
+	cacheHitRatio := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "cache_hit_ratio",
+		Help: "Ratio of cache hits to total cache lookups",
+	}, m.cacheHitRatio)
+
+	registry.MustRegister(requestDuration, requestTotal, requestLatency, requestSummary, inFlight, cacheLatency, cacheWrite, cacheHitRatio, cacheHits, cacheMisses, dbQueryDuration, circuitState, circuitChanges, panics, storageFree, storageTotal, storageHealthy, mutationBacklog, mutationAge, compression, originalBytes, encodedBytes, retentionPurged, goroutines)
+	m.handler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return m
 }
 
 // Handler exposes the Prometheus HTTP handler.
@@ -117,19 +245,73 @@ func (m *MetricsService) Handler() http.Handler {
 	return m.handler
 }
 
-// ObserveHTTPRequest records request metrics and aggregates simple stats for snapshots.
+// ObserveHTTPRequest records request metrics outside any cutover stage.
 func (m *MetricsService) ObserveHTTPRequest(method, path string, status int, duration time.Duration) {
+	m.ObserveHTTPRequestInStage(method, path, "", status, duration)
+}
+
+// ObserveHTTPRequestInStage records request metrics for a route served in a cutover stage and
+// aggregates simple stats for snapshots. An empty route is recorded as RouteUnmatched.
+func (m *MetricsService) ObserveHTTPRequestInStage(method, route, stage string, status int, duration time.Duration) {
 	if m == nil {
 		return
 	}
+	method = metricMethod(method)
+	route = m.routeLabel(route)
+	stage = stageLabel(stage)
 	labelStatus := fmt.Sprintf("%d", status)
-	m.requestDuration.WithLabelValues(method, path, labelStatus).Observe(duration.Seconds())
-	m.requestTotal.WithLabelValues(method, path, labelStatus).Inc()
+	m.requestDuration.WithLabelValues(method, route, labelStatus).Observe(duration.Seconds())
+	m.requestTotal.WithLabelValues(method, route, labelStatus).Inc()
+	m.requestLatency.WithLabelValues(route, method, labelStatus, stage).Observe(duration.Seconds())
+	m.requestSummary.WithLabelValues(route, method, stage).Observe(duration.Seconds())
 	atomic.AddUint64(&m.requestCount, 1)
 	atomic.AddUint64(&m.requestDurationTotal, uint64(duration.Nanoseconds()))
 }
 
-// RecordCacheOperation records cache hit/miss metrics and updates hit ratio.
+// TrackInFlight counts a request as in flight until the returned function is called.
+func (m *MetricsService) TrackInFlight(route, stage string) func() {
+	if m == nil {
+		return func() {}
+	}
+	gauge := m.inFlight.WithLabelValues(m.routeLabel(route), stageLabel(stage))
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// routeLabel bounds the route label: unmatched requests share one value and routes beyond
+// maxRouteLabels are folded into RouteOverflow.
+func (m *MetricsService) routeLabel(route string) string {
+	if route == "" {
+		return RouteUnmatched
+	}
+	m.routesMu.Lock()
+	defer m.routesMu.Unlock()
+	if _, ok := m.routes[route]; ok {
+		return route
+	}
+	if len(m.routes) >= maxRouteLabels {
+		return RouteOverflow
+	}
+	m.routes[route] = struct{}{}
+	return route
+}
+
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return methodOther
+}
+
+func stageLabel(stage string) string {
+	if stage == "" {
+		return stageNone
+	}
+	return stage
+}
+
+// RecordCacheOperation records cache hit/miss metrics.
 func (m *MetricsService) RecordCacheOperation(hit bool, duration time.Duration) {
 	if m == nil {
 		return
@@ -144,12 +326,15 @@ func (m *MetricsService) RecordCacheOperation(hit bool, duration time.Duration)
 		m.cacheMisses.Inc()
 		atomic.AddUint64(&m.cacheMissCount, 1)
 	}
+}
+
+func (m *MetricsService) cacheHitRatio() float64 {
 	hits := atomic.LoadUint64(&m.cacheHitCount)
 	misses := atomic.LoadUint64(&m.cacheMissCount)
-	total := hits + misses
-	if total > 0 {
-		m.cacheHitRatio.Set(float64(hits) / float64(total))
+	if total := hits + misses; total > 0 {
+		return float64(hits) / float64(total)
 	}
+	return 0
 }
 
 // ObserveCacheWrite tracks the duration for cache write operations.
@@ -170,6 +355,78 @@ func (m *MetricsService) ObserveDBQuery(label string, duration time.Duration) {
 	atomic.AddUint64(&m.dbQueryDurationTotal, uint64(duration.Nanoseconds()))
 }
 
+// RecordCircuitState exports a cutover circuit breaker transition.
+func (m *MetricsService) RecordCircuitState(target string, state CircuitState) {
+	if m == nil {
+		return
+	}
+	value := 0.0
+	switch state {
+	case CircuitHalfOpen:
+		value = 1
+	case CircuitOpen:
+		value = 2
+	}
+	m.circuitState.WithLabelValues(target).Set(value)
+	m.circuitChanges.WithLabelValues(target, string(state)).Inc()
+}
+
+// RecordPanic counts a panic recovered while serving method and path.
+func (m *MetricsService) RecordPanic(method, path string) {
+	if m == nil {
+		return
+	}
+	m.panics.WithLabelValues(method, path).Inc()
+}
+
+// RecordCompression records a response compressed with encoding from originalBytes down to
+// compressedBytes.
+func (m *MetricsService) RecordCompression(route, encoding string, originalBytes, compressedBytes int64) {
+	if m == nil || originalBytes <= 0 {
+		return
+	}
+	m.compression.WithLabelValues(m.routeLabel(route), encoding).Observe(float64(compressedBytes) / float64(originalBytes))
+	m.originalBytes.WithLabelValues(encoding).Add(float64(originalBytes))
+	m.encodedBytes.WithLabelValues(encoding).Add(float64(compressedBytes))
+}
+
+// RecordStorageStatus exports the outcome of a storage directory check.
+func (m *MetricsService) RecordStorageStatus(status models.StorageStatus) {
+	if m == nil {
+		return
+	}
+	healthy := 0.0
+	if status.Healthy {
+		healthy = 1
+	}
+	m.storageHealthy.WithLabelValues(status.Name).Set(healthy)
+	m.storageFree.WithLabelValues(status.Name).Set(float64(status.FreeBytes))
+	m.storageTotal.WithLabelValues(status.Name).Set(float64(status.TotalBytes))
+}
+
+// RecordMutationBacklog exports the pending mutation summary taken at now.
+func (m *MetricsService) RecordMutationBacklog(summary models.MutationPendingSummary, now time.Time) {
+	if m == nil {
+		return
+	}
+	m.mutationBacklog.WithLabelValues("pending").Set(float64(summary.Pending))
+	m.mutationBacklog.WithLabelValues("overdue").Set(float64(summary.Overdue))
+	m.mutationBacklog.WithLabelValues("escalated").Set(float64(summary.Escalated))
+	age := 0.0
+	if summary.OldestRequestedAt != nil {
+		age = now.Sub(*summary.OldestRequestedAt).Seconds()
+	}
+	m.mutationAge.Set(age)
+}
+
+// RecordRetentionPurge counts rows of a data class deleted by the retention purge.
+func (m *MetricsService) RecordRetentionPurge(dataClass string, purged int64) {
+	if m == nil || purged <= 0 {
+		return
+	}
+	m.retentionPurged.WithLabelValues(dataClass).Add(float64(purged))
+}
+
 // Snapshot returns aggregated metrics suitable for analytics endpoints.
 func (m *MetricsService) Snapshot() models.AnalyticsSystemMetrics {
 	if m == nil {
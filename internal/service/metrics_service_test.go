@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsServiceRequestLabels(t *testing.T) {
+	m := NewMetricsService()
+
+	done := m.TrackInFlight("/api/v1/students", "canary")
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.inFlight.WithLabelValues("/api/v1/students", "canary")))
+	done()
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("/api/v1/students", "canary")))
+
+	m.ObserveHTTPRequestInStage(http.MethodGet, "/api/v1/students", "canary", http.StatusOK, 20*time.Millisecond)
+	m.ObserveHTTPRequestInStage("PROPFIND", "", "", http.StatusNotFound, time.Millisecond)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requestTotal.WithLabelValues("OTHER", RouteUnmatched, "404")))
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"method=GET route=/api/v1/students stage=canary status=200 count=1",
+		"method=OTHER route=unmatched stage=none status=404 count=1",
+	}, describeSeries(families, "http_server_request_duration_seconds"))
+	assert.Equal(t, []string{
+		"method=GET route=/api/v1/students stage=canary count=1",
+		"method=OTHER route=unmatched stage=none count=1",
+	}, describeSeries(families, "http_server_request_duration_quantiles_seconds"))
+
+	for i := 0; i < maxRouteLabels; i++ {
+		m.ObserveHTTPRequest(http.MethodGet, fmt.Sprintf("/generated/%d", i), http.StatusOK, time.Millisecond)
+	}
+	assert.Equal(t, RouteOverflow, m.routeLabel("/api/v1/late"))
+	assert.Equal(t, "/api/v1/students", m.routeLabel("/api/v1/students"))
+
+	families, err = m.registry.Gather()
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	for _, name := range []string{"http_server_request_duration_seconds", "http_server_request_duration_quantiles_seconds", "http_server_requests_in_flight", "cache_hit_ratio"} {
+		assert.True(t, names[name], name)
+	}
+}
+
+// describeSeries renders each series of a histogram or summary family as its labels, sorted by
+// name, and its sample count.
+func describeSeries(families []*dto.MetricFamily, name string) []string {
+	var series []string
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var parts []string
+			for _, label := range metric.GetLabel() {
+				parts = append(parts, label.GetName()+"="+label.GetValue())
+			}
+			count := metric.GetHistogram().GetSampleCount()
+			if metric.GetSummary() != nil {
+				count = metric.GetSummary().GetSampleCount()
+			}
+			series = append(series, fmt.Sprintf("%s count=%d", strings.Join(parts, " "), count))
+		}
+	}
+	sort.Strings(series)
+	return series
+}
+
+func TestMetricsServiceCacheHitRatio(t *testing.T) {
+	m := NewMetricsService()
+	m.RecordCacheOperation(true, time.Millisecond)
+	m.RecordCacheOperation(true, time.Millisecond)
+	m.RecordCacheOperation(false, time.Millisecond)
+	m.RecordCacheOperation(true, time.Millisecond)
+	assert.Equal(t, 0.75, m.cacheHitRatio())
+	assert.Equal(t, uint64(3), m.Snapshot().CacheHits)
+}
+
+func TestMetricsServiceRecordCompression(t *testing.T) {
+	m := NewMetricsService()
+	m.RecordCompression("/api/v1/classes/:id/roster", "gzip", 1000, 250)
+	m.RecordCompression("", "gzip", 0, 20)
+
+	assert.Equal(t, 1000.0, testutil.ToFloat64(m.originalBytes.WithLabelValues("gzip")))
+	assert.Equal(t, 250.0, testutil.ToFloat64(m.encodedBytes.WithLabelValues("gzip")))
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"encoding=gzip route=/api/v1/classes/:id/roster count=1"}, describeSeries(families, "http_response_compression_ratio"))
+}
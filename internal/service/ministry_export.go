@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+const (
+	// nisnLength and nipLength are the digit counts of the national student and civil servant numbers.
+	nisnLength = 10
+	nipLength  = 18
+	// ministryMissingListed caps how many offenders a failed ministry export names.
+	ministryMissingListed = 10
+)
+
+type ministryExportSource interface {
+	AttendanceRecap(ctx context.Context, termID, classID string) ([]models.MinistryAttendanceRow, error)
+	FinalGrades(ctx context.Context, termID, classID string) ([]models.MinistryGradeRow, error)
+}
+
+type xlsxRenderer interface {
+	Render(data export.Dataset, title string) ([]byte, error)
+}
+
+var (
+	ministryAttendanceHeaders = []string{"No", "NISN", "NIS", "Nama Peserta Didik", "L/P", "Kelas", "Sakit", "Izin", "Tanpa Keterangan", "NIP Wali Kelas", "Nama Wali Kelas"}
+	ministryGradeHeaders      = []string{"No", "NISN", "NIS", "Nama Peserta Didik", "Kelas", "Kode Mapel", "Mata Pelajaran", "NIP Guru", "Nama Guru", "Nilai Akhir"}
+)
+
+// WithMinistryExports enables the ministry_attendance and ministry_grades report types.
+func WithMinistryExports(source ministryExportSource) ExportServiceOption {
+	return func(s *ExportService) {
+		if source == nil {
+			return
+		}
+		s.ministry = source
+		if s.xlsx == nil {
+			s.xlsx = export.NewXLSXExporter()
+		}
+	}
+}
+
+// MinistryEnabled reports whether ministry report jobs can be generated.
+func (s *ExportService) MinistryEnabled() bool {
+	return s != nil && s.ministry != nil
+}
+
+func isMinistryReportType(t models.ReportType) bool {
+	return t == models.ReportTypeMinistryAttendance || t == models.ReportTypeMinistryGrades
+}
+
+// buildMinistryExport renders a term's attendance recap or final grades in the column layout the
+// ministry import expects. The import identifies students by NISN and teachers by NIP, so the export
+// fails, naming who is missing one, rather than produce a file the ministry would reject.
+func (s *ExportService) buildMinistryExport(ctx context.Context, job *models.ReportJob) ([]byte, error) {
+	if !s.MinistryEnabled() {
+		return nil, fmt.Errorf("ministry exports are not configured")
+	}
+	classID := deref(job.Params.ClassID)
+	var (
+		dataset export.Dataset
+		err     error
+	)
+	switch job.Type {
+	case models.ReportTypeMinistryAttendance:
+		dataset, err = s.ministryAttendanceDataset(ctx, job.Params.TermID, classID)
+	case models.ReportTypeMinistryGrades:
+		dataset, err = s.ministryGradeDataset(ctx, job.Params.TermID, classID)
+	default:
+		err = fmt.Errorf("unsupported report type %s", job.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch job.Params.Format {
+	case models.ReportFormatCSV:
+		return s.csv.Render(dataset)
+	case models.ReportFormatXLSX:
+		// No title row: the import reads the header from the first row.
+		return s.xlsx.Render(dataset, "")
+	default:
+		return nil, fmt.Errorf("unsupported format %s for ministry exports", job.Params.Format)
+	}
+}
+
+func (s *ExportService) ministryAttendanceDataset(ctx context.Context, termID, classID string) (export.Dataset, error) {
+	rows, err := s.ministry.AttendanceRecap(ctx, termID, classID)
+	if err != nil {
+		return export.Dataset{}, err
+	}
+	check := ministryFieldCheck{}
+	dataset := export.Dataset{Headers: ministryAttendanceHeaders, Rows: make([]map[string]string, 0, len(rows))}
+	for i, row := range rows {
+		check.student(row.NISN, row.StudentName, row.NIS)
+		check.teacher(row.HomeroomNIP, row.HomeroomName, "homeroom of "+row.ClassName)
+		dataset.Rows = append(dataset.Rows, map[string]string{
+			"No":                 strconv.Itoa(i + 1),
+			"NISN":               deref(row.NISN),
+			"NIS":                row.NIS,
+			"Nama Peserta Didik": row.StudentName,
+			"L/P":                row.Gender,
+			"Kelas":              row.ClassName,
+			"Sakit":              strconv.Itoa(row.Sick),
+			"Izin":               strconv.Itoa(row.Excused),
+			"Tanpa Keterangan":   strconv.Itoa(row.Absent),
+			"NIP Wali Kelas":     deref(row.HomeroomNIP),
+			"Nama Wali Kelas":    deref(row.HomeroomName),
+		})
+	}
+	return dataset, check.err()
+}
+
+func (s *ExportService) ministryGradeDataset(ctx context.Context, termID, classID string) (export.Dataset, error) {
+	rows, err := s.ministry.FinalGrades(ctx, termID, classID)
+	if err != nil {
+		return export.Dataset{}, err
+	}
+	check := ministryFieldCheck{}
+	dataset := export.Dataset{Headers: ministryGradeHeaders, Rows: make([]map[string]string, 0, len(rows))}
+	for i, row := range rows {
+		check.student(row.NISN, row.StudentName, row.NIS)
+		check.teacher(row.TeacherNIP, row.TeacherName, row.SubjectCode+" teacher of "+row.ClassName)
+		dataset.Rows = append(dataset.Rows, map[string]string{
+			"No":                 strconv.Itoa(i + 1),
+			"NISN":               deref(row.NISN),
+			"NIS":                row.NIS,
+			"Nama Peserta Didik": row.StudentName,
+			"Kelas":              row.ClassName,
+			"Kode Mapel":         row.SubjectCode,
+			"Mata Pelajaran":     row.SubjectName,
+			"NIP Guru":           deref(row.TeacherNIP),
+			"Nama Guru":          deref(row.TeacherName),
+			"Nilai Akhir":        strconv.FormatFloat(row.FinalGrade, 'f', 2, 64),
+		})
+	}
+	return dataset, check.err()
+}
+
+// ministryFieldCheck collects the students and teachers whose mandatory ministry identifiers are
+// missing or malformed, each named once.
+type ministryFieldCheck struct {
+	seen     map[string]bool
+	students []string
+	teachers []string
+}
+
+func (c *ministryFieldCheck) student(nisn *string, name, nis string) {
+	if validDigits(deref(nisn), nisnLength) {
+		return
+	}
+	c.students = c.add(c.students, fmt.Sprintf("%s (NIS %s)", name, nis))
+}
+
+func (c *ministryFieldCheck) teacher(nip, name *string, role string) {
+	if validDigits(deref(nip), nipLength) {
+		return
+	}
+	label := role + " (unassigned)"
+	if name != nil {
+		label = fmt.Sprintf("%s (%s)", *name, role)
+	}
+	c.teachers = c.add(c.teachers, label)
+}
+
+func (c *ministryFieldCheck) add(list []string, label string) []string {
+	if c.seen == nil {
+		c.seen = map[string]bool{}
+	}
+	if c.seen[label] {
+		return list
+	}
+	c.seen[label] = true
+	return append(list, label)
+}
+
+func (c *ministryFieldCheck) err() error {
+	var problems []string
+	if len(c.students) > 0 {
+		problems = append(problems, fmt.Sprintf("%d student(s) without a valid %d-digit NISN: %s", len(c.students), nisnLength, listSome(c.students)))
+	}
+	if len(c.teachers) > 0 {
+		problems = append(problems, fmt.Sprintf("%d teacher(s) without a valid %d-digit NIP: %s", len(c.teachers), nipLength, listSome(c.teachers)))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("ministry export incomplete: %s", strings.Join(problems, "; "))
+}
+
+func listSome(labels []string) string {
+	if len(labels) <= ministryMissingListed {
+		return strings.Join(labels, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(labels[:ministryMissingListed], ", "), len(labels)-ministryMissingListed)
+}
+
+func validDigits(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
@@ -32,6 +36,34 @@ func NewStudentMutationApplier(repo studentMutationRepository, logger *zap.Logge
 	return &StudentMutationApplier{repo: repo, logger: logger}
 }
 
+// MutationFields describes the student fields Apply accepts, keyed as in snapshots.
+func (a *StudentMutationApplier) MutationFields() []MutationField {
+	return []MutationField{
+		{Key: "nis", Label: "NIS"},
+		{Key: "full_name", Label: "Full name", Aliases: []string{"fullName"}},
+		{Key: "gender", Label: "Gender"},
+		{Key: "birth_date", Label: "Birth date", Aliases: []string{"birthDate"}, Kind: MutationFieldDate},
+		{Key: "address", Label: "Address"},
+		{Key: "phone", Label: "Phone"},
+		{Key: "active", Label: "Active"},
+	}
+}
+
+// Snapshot captures the student as it is before the requested changes.
+func (a *StudentMutationApplier) Snapshot(ctx context.Context, entity, entityID string) ([]byte, error) {
+	if a.repo == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "student repository not configured")
+	}
+	detail, err := a.repo.FindByID(ctx, entityID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "student not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+	}
+	return json.Marshal(detail.Student)
+}
+
 // Apply updates student fields and returns the refreshed snapshot.
 func (a *StudentMutationApplier) Apply(ctx context.Context, mutation *models.Mutation) ([]byte, error) {
 	if a.repo == nil {
@@ -118,6 +150,64 @@ func (a *StudentMutationApplier) Apply(ctx context.Context, mutation *models.Mut
 	return snapshot, nil
 }
 
+type gradeFinalizer interface {
+	SetFinalized(ctx context.Context, exec sqlx.ExtContext, enrollmentIDs []string, subjectID string, finalized bool) error
+}
+
+// GradeUnfinalizeApplier reopens the final grades named by an approved unfinalize request.
+type GradeUnfinalizeApplier struct {
+	finals gradeFinalizer
+	logger *zap.Logger
+}
+
+// NewGradeUnfinalizeApplier constructs an applier backed by the final grade repository.
+func NewGradeUnfinalizeApplier(finals gradeFinalizer, logger *zap.Logger) *GradeUnfinalizeApplier {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &GradeUnfinalizeApplier{finals: finals, logger: logger}
+}
+
+// MutationFields describes the unfinalize payload.
+func (a *GradeUnfinalizeApplier) MutationFields() []MutationField {
+	return []MutationField{
+		{Key: "class_id", Label: "Class"},
+		{Key: "subject_id", Label: "Subject"},
+		{Key: "term_id", Label: "Term"},
+		{Key: "enrollment_ids", Label: "Enrollments"},
+		{Key: "finalized", Label: "Finalized"},
+	}
+}
+
+// Apply clears the finalized flag for the requested enrollments and returns the reopened scope.
+func (a *GradeUnfinalizeApplier) Apply(ctx context.Context, mutation *models.Mutation) ([]byte, error) {
+	if a.finals == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "final grade repository not configured")
+	}
+	var changes gradeUnfinalizeChanges
+	if err := json.Unmarshal(mutation.RequestedChanges, &changes); err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid unfinalize payload")
+	}
+	if changes.SubjectID == "" || len(changes.EnrollmentIDs) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "unfinalize payload needs a subject and enrollments")
+	}
+	if err := a.finals.SetFinalized(ctx, nil, changes.EnrollmentIDs, changes.SubjectID, false); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to reopen final grades")
+	}
+	a.logger.Info("final grades reopened",
+		zap.String("mutation_id", mutation.ID),
+		zap.String("subject_id", changes.SubjectID),
+		zap.Int("enrollments", len(changes.EnrollmentIDs)),
+	)
+	changes.Finalized = false
+	snapshot, err := json.Marshal(changes)
+	if err != nil {
+		a.logger.Warn("failed to marshal unfinalize snapshot", zap.Error(err))
+		return []byte("{}"), nil
+	}
+	return snapshot, nil
+}
+
 func readString(payload map[string]json.RawMessage, keys ...string) (*string, bool, error) {
 	for _, key := range keys {
 		if raw, ok := payload[key]; ok {
@@ -144,3 +234,117 @@ func readBool(payload map[string]json.RawMessage, keys ...string) (bool, bool, e
 	}
 	return false, false, nil
 }
+
+type gradeAppealReader interface {
+	FindByID(ctx context.Context, id string) (*models.GradeAppeal, error)
+}
+
+type gradeCorrector interface {
+	Correct(ctx context.Context, exec sqlx.ExtContext, id string, grade float64, belowKKM bool, note string, at time.Time) error
+}
+
+// GradeAppealApplier corrects the final grade disputed by an approved grade appeal.
+type GradeAppealApplier struct {
+	appeals gradeAppealReader
+	finals  gradeCorrector
+	configs gradeConfigReader
+	events  *DomainEvents
+	logger  *zap.Logger
+}
+
+// NewGradeAppealApplier constructs the applier. events may be nil, in which case no grade.finalized
+// event announces the corrected grade.
+func NewGradeAppealApplier(appeals gradeAppealReader, finals gradeCorrector, configs gradeConfigReader, events *DomainEvents, logger *zap.Logger) *GradeAppealApplier {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &GradeAppealApplier{appeals: appeals, finals: finals, configs: configs, events: events, logger: logger}
+}
+
+// MutationFields describes the appeal payload.
+func (a *GradeAppealApplier) MutationFields() []MutationField {
+	return []MutationField{
+		{Key: "final_grade", Label: "Final grade"},
+		{Key: "recommendation", Label: "Teacher recommendation"},
+		{Key: "teacher_note", Label: "Teacher note"},
+	}
+}
+
+// Snapshot captures the disputed grade and the teacher's recommendation.
+func (a *GradeAppealApplier) Snapshot(ctx context.Context, entity, entityID string) ([]byte, error) {
+	appeal, err := a.load(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(gradeAppealChangesOf(appeal, appeal.CurrentGrade))
+}
+
+// Apply replaces the final grade with the approved one and emits grade.finalized for the enrollment,
+// so report cards and subscribers pick up the corrected grade.
+func (a *GradeAppealApplier) Apply(ctx context.Context, mutation *models.Mutation) ([]byte, error) {
+	if a.finals == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "final grade repository not configured")
+	}
+	var changes gradeAppealChanges
+	if err := json.Unmarshal(mutation.RequestedChanges, &changes); err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "invalid grade appeal payload")
+	}
+	if changes.FinalGrade < 0 || changes.FinalGrade > 100 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "final_grade must be between 0 and 100")
+	}
+	appeal, err := a.load(ctx, mutation.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	belowKKM := false
+	if a.configs != nil {
+		config, err := a.configs.FindByScope(ctx, models.ClassID(appeal.ClassID), models.SubjectID(appeal.SubjectID), models.TermID(appeal.TermID))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade config")
+		}
+		belowKKM = config != nil && config.KKM != nil && changes.FinalGrade < *config.KKM
+	}
+	note := fmt.Sprintf("Adjusted by grade appeal %s", appeal.ID)
+	err = a.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		if err := a.finals.Correct(ctx, exec, appeal.GradeFinalID, changes.FinalGrade, belowKKM, note, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		event, err := gradeFinalizedEvent(appeal.ClassID, appeal.SubjectID, appeal.TermID, []string{appeal.EnrollmentID})
+		if err != nil {
+			return nil, err
+		}
+		return []*models.OutboxEvent{event}, nil
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "final grade not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to correct final grade")
+	}
+	a.logger.Info("final grade corrected by appeal",
+		zap.String("mutation_id", mutation.ID),
+		zap.String("appeal_id", appeal.ID),
+		zap.Float64("from", appeal.CurrentGrade),
+		zap.Float64("to", changes.FinalGrade),
+	)
+	snapshot, err := json.Marshal(changes)
+	if err != nil {
+		a.logger.Warn("failed to marshal grade appeal snapshot", zap.Error(err))
+		return []byte("{}"), nil
+	}
+	return snapshot, nil
+}
+
+func (a *GradeAppealApplier) load(ctx context.Context, id string) (*models.GradeAppeal, error) {
+	if a.appeals == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "grade appeal repository not configured")
+	}
+	appeal, err := a.appeals.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "grade appeal not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load grade appeal")
+	}
+	return appeal, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
@@ -84,3 +85,31 @@ func TestStudentMutationApplierRejectsDuplicateNIS(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+type gradeFinalizerStub struct {
+	enrollmentIDs []string
+	subjectID     string
+	finalized     bool
+}
+
+func (s *gradeFinalizerStub) SetFinalized(ctx context.Context, exec sqlx.ExtContext, enrollmentIDs []string, subjectID string, finalized bool) error {
+	s.enrollmentIDs, s.subjectID, s.finalized = enrollmentIDs, subjectID, finalized
+	return nil
+}
+
+func TestGradeUnfinalizeApplierApply(t *testing.T) {
+	finals := &gradeFinalizerStub{finalized: true}
+	applier := NewGradeUnfinalizeApplier(finals, nil)
+	raw, err := json.Marshal(gradeUnfinalizeChanges{ClassID: "class", SubjectID: "sub", TermID: "term", EnrollmentIDs: []string{"en1", "en2"}})
+	require.NoError(t, err)
+
+	snapshot, err := applier.Apply(context.Background(), &models.Mutation{ID: "mut-1", EntityID: "cfg", RequestedChanges: raw})
+	require.NoError(t, err)
+	require.Equal(t, []string{"en1", "en2"}, finals.enrollmentIDs)
+	require.Equal(t, "sub", finals.subjectID)
+	require.False(t, finals.finalized)
+	require.JSONEq(t, string(raw), string(snapshot))
+
+	_, err = applier.Apply(context.Background(), &models.Mutation{RequestedChanges: []byte(`{"subject_id":"sub"}`)})
+	require.Error(t, err)
+}
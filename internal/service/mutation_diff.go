@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// MutationFieldDate marks fields compared and shown as YYYY-MM-DD, so a snapshot timestamp matches
+// the date a requester typed.
+const MutationFieldDate = "date"
+
+// MutationField describes one field of a mutable entity for review screens.
+type MutationField struct {
+	// Key is the field's key in entity snapshots.
+	Key   string
+	Label string
+	// Aliases are other keys the applier accepts for the field in requested changes.
+	Aliases []string
+	// Kind is MutationFieldDate or empty for values compared as they are.
+	Kind string
+}
+
+// MutationFieldDescriber is implemented by appliers that describe the fields of their entity. The
+// fields are listed in the order review screens show them.
+type MutationFieldDescriber interface {
+	MutationFields() []MutationField
+}
+
+// MutationDiffer compares the requested changes of a mutation with the entity snapshot taken when it
+// was requested, field by field.
+type MutationDiffer struct {
+	fields map[string][]MutationField
+}
+
+// NewMutationDiffer constructs a differ from the fields registered per entity.
+func NewMutationDiffer(fields map[string][]MutationField) *MutationDiffer {
+	normalized := make(map[string][]MutationField, len(fields))
+	for entity, list := range fields {
+		normalized[strings.ToLower(entity)] = list
+	}
+	return &MutationDiffer{fields: normalized}
+}
+
+// Diff returns an old→new pair for every field in the requested changes: described fields first in
+// their registered order, then unknown keys alphabetically, labelled with the key itself. Old is null
+// when the snapshot lacks the field. Both payloads must be JSON objects.
+func (d *MutationDiffer) Diff(entity string, snapshot, changes []byte) ([]models.MutationFieldDiff, error) {
+	if len(bytes.TrimSpace(changes)) == 0 {
+		return nil, nil
+	}
+	var requested map[string]json.RawMessage
+	if err := json.Unmarshal(changes, &requested); err != nil {
+		return nil, err
+	}
+	current := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(snapshot)) > 0 {
+		if err := json.Unmarshal(snapshot, &current); err != nil {
+			return nil, err
+		}
+	}
+
+	diffs := make([]models.MutationFieldDiff, 0, len(requested))
+	used := make(map[string]bool, len(requested))
+	for _, field := range d.fields[strings.ToLower(entity)] {
+		keys := append([]string{field.Key}, field.Aliases...)
+		for _, key := range keys {
+			if value, ok := requested[key]; ok {
+				diffs = append(diffs, fieldDiff(field, lookupField(current, keys), value))
+				break
+			}
+		}
+		// The applier reads the first key present, so the other aliases are ignored.
+		for _, key := range keys {
+			used[key] = true
+		}
+	}
+
+	unknown := make([]string, 0)
+	for key := range requested {
+		if !used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		diffs = append(diffs, fieldDiff(MutationField{Key: key, Label: key}, current[key], requested[key]))
+	}
+	return diffs, nil
+}
+
+func lookupField(snapshot map[string]json.RawMessage, keys []string) json.RawMessage {
+	for _, key := range keys {
+		if value, ok := snapshot[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+func fieldDiff(field MutationField, before, after json.RawMessage) models.MutationFieldDiff {
+	before, after = normalizeFieldValue(field.Kind, before), normalizeFieldValue(field.Kind, after)
+	label := field.Label
+	if label == "" {
+		label = field.Key
+	}
+	return models.MutationFieldDiff{
+		Field:   field.Key,
+		Label:   label,
+		Old:     before,
+		New:     after,
+		Changed: !jsonEqual(before, after),
+	}
+}
+
+func normalizeFieldValue(kind string, value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+	if kind == MutationFieldDate {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil && len(text) > 10 {
+			normalized, _ := json.Marshal(text[:10])
+			return normalized
+		}
+	}
+	return value
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return bytes.Equal(a, b)
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return strings.TrimSpace(l) == strings.TrimSpace(r)
+		}
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationDifferDiff(t *testing.T) {
+	differ := NewMutationDiffer(map[string][]MutationField{
+		"Student": NewStudentMutationApplier(nil, nil).MutationFields(),
+	})
+	snapshot := []byte(`{"id":"stu-1","nis":"123","full_name":"Adi","birth_date":"2005-01-02T00:00:00Z","address":"Jl lama","active":true}`)
+	changes := []byte(`{"zodiac":"leo","fullName":"Adi Baru","birthDate":"2005-01-02","active":false,"nickname":"Adi"}`)
+
+	diff, err := differ.Diff("student", snapshot, changes)
+	require.NoError(t, err)
+	require.Len(t, diff, 5)
+
+	assert.Equal(t, "full_name", diff[0].Field)
+	assert.Equal(t, "Full name", diff[0].Label)
+	assert.JSONEq(t, `"Adi"`, string(diff[0].Old))
+	assert.JSONEq(t, `"Adi Baru"`, string(diff[0].New))
+	assert.True(t, diff[0].Changed)
+
+	assert.Equal(t, "birth_date", diff[1].Field)
+	assert.JSONEq(t, `"2005-01-02"`, string(diff[1].Old))
+	assert.False(t, diff[1].Changed, "dates compare by day")
+
+	assert.Equal(t, "active", diff[2].Field)
+	assert.True(t, diff[2].Changed)
+
+	// Keys without a registered field follow alphabetically, labelled with the key.
+	assert.Equal(t, "nickname", diff[3].Label)
+	assert.JSONEq(t, `null`, string(diff[3].Old))
+	assert.True(t, diff[3].Changed)
+	assert.Equal(t, "zodiac", diff[4].Field)
+}
+
+func TestMutationDifferUnknownEntity(t *testing.T) {
+	differ := NewMutationDiffer(nil)
+	diff, err := differ.Diff("class", []byte(`{}`), []byte(`{"name":"XI IPA 1"}`))
+	require.NoError(t, err)
+	require.Len(t, diff, 1)
+	assert.Equal(t, "name", diff[0].Label)
+
+	_, err = differ.Diff("class", []byte(`{}`), []byte(`["not","an","object"]`))
+	assert.Error(t, err)
+}
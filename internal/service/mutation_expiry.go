@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// Actions applied to mutations pending longer than MutationExpiryConfig.ExpireAfter.
+const (
+	MutationExpiryReject   = "reject"
+	MutationExpiryEscalate = "escalate"
+)
+
+const mutationExpiryBatch = 100
+
+type mutationExpiryStore interface {
+	List(ctx context.Context, filter models.MutationFilter) ([]models.Mutation, error)
+	Expire(ctx context.Context, id, note string, at time.Time) error
+	Escalate(ctx context.Context, id string, at time.Time) error
+	PendingSummary(ctx context.Context, overdueBefore time.Time) (*models.MutationPendingSummary, error)
+}
+
+type mutationExpiryNotifier interface {
+	CreateOnce(ctx context.Context, notification *models.Notification) (bool, error)
+	CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error)
+}
+
+// MutationExpiryConfig tunes how long mutations may stay pending.
+type MutationExpiryConfig struct {
+	Interval time.Duration
+	// ReminderAfter is how long a mutation waits before reviewers get the daily reminder; zero
+	// disables reminders.
+	ReminderAfter time.Duration
+	// ExpireAfter is how long a mutation waits before Action is applied; zero disables expiry.
+	ExpireAfter time.Duration
+	// Action is MutationExpiryReject or MutationExpiryEscalate.
+	Action string
+}
+
+// MutationExpiry keeps the mutation review queue moving. Reviewers get one daily digest while
+// requests are overdue, and requests pending past ExpireAfter are either rejected on the requester's
+// behalf or escalated once to every super admin. Expiries and escalations are audited.
+type MutationExpiry struct {
+	store         mutationExpiryStore
+	notifications mutationExpiryNotifier
+	audit         ports.AuditLogger
+	metrics       *MetricsService
+	logger        *zap.Logger
+	cfg           MutationExpiryConfig
+	now           func() time.Time
+}
+
+// NewMutationExpiry constructs the job with defaults. notifications, audit and metrics are optional.
+func NewMutationExpiry(store mutationExpiryStore, notifications mutationExpiryNotifier, audit ports.AuditLogger, metrics *MetricsService, logger *zap.Logger, cfg MutationExpiryConfig) *MutationExpiry {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.ReminderAfter < 0 {
+		cfg.ReminderAfter = 0
+	}
+	if cfg.ExpireAfter < 0 {
+		cfg.ExpireAfter = 0
+	}
+	if cfg.Action != MutationExpiryReject {
+		cfg.Action = MutationExpiryEscalate
+	}
+	return &MutationExpiry{
+		store:         store,
+		notifications: notifications,
+		audit:         audit,
+		metrics:       metrics,
+		logger:        logger,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// Start sweeps now and then every interval until ctx is cancelled.
+func (e *MutationExpiry) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := e.Sweep(ctx); err != nil {
+				e.logger.Warn("mutation expiry sweep failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sweep applies the expiry action to overdue mutations, reminds reviewers about the remaining
+// backlog and records it as metrics.
+func (e *MutationExpiry) Sweep(ctx context.Context) (dto.MutationExpiryRun, error) {
+	var run dto.MutationExpiryRun
+	now := e.now().UTC()
+	if e.cfg.ExpireAfter > 0 {
+		expired, escalated, err := e.expire(ctx, now)
+		run.Expired, run.Escalated = expired, escalated
+		if err != nil {
+			return run, err
+		}
+	}
+	summary, err := e.summary(ctx, now)
+	if err != nil {
+		return run, err
+	}
+	e.metrics.RecordMutationBacklog(*summary, now)
+	if summary.Overdue > 0 {
+		run.Reminded = e.remind(ctx, summary, now)
+	}
+	if run.Expired > 0 || run.Escalated > 0 || run.Reminded > 0 {
+		e.logger.Info("mutation expiry applied", zap.Int("expired", run.Expired), zap.Int("escalated", run.Escalated), zap.Int("reminded", run.Reminded))
+	}
+	return run, nil
+}
+
+// PendingStats summarises the review backlog for the admin dashboard.
+func (e *MutationExpiry) PendingStats(ctx context.Context) (*dto.PendingMutationStats, error) {
+	now := e.now().UTC()
+	summary, err := e.summary(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	stats := &dto.PendingMutationStats{
+		Pending:           summary.Pending,
+		Overdue:           summary.Overdue,
+		Escalated:         summary.Escalated,
+		OldestRequestedAt: summary.OldestRequestedAt,
+	}
+	if summary.OldestRequestedAt != nil {
+		stats.OldestAgeDays = int(now.Sub(*summary.OldestRequestedAt) / (24 * time.Hour))
+	}
+	return stats, nil
+}
+
+// summary counts the pending mutations; they are overdue once ReminderAfter has passed, so none are
+// while reminders are disabled.
+func (e *MutationExpiry) summary(ctx context.Context, now time.Time) (*models.MutationPendingSummary, error) {
+	summary, err := e.store.PendingSummary(ctx, now.Add(-e.cfg.ReminderAfter))
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to summarize pending mutations")
+	}
+	if e.cfg.ReminderAfter == 0 {
+		summary.Overdue = 0
+	}
+	return summary, nil
+}
+
+// expire applies the configured action batch by batch. Failed rows stop the sweep so they are not
+// fetched again in a loop; the next sweep retries them.
+func (e *MutationExpiry) expire(ctx context.Context, now time.Time) (expired, escalated int, err error) {
+	filter := models.MutationFilter{
+		Status:          []models.MutationStatus{models.MutationStatusPending},
+		RequestedBefore: now.Add(-e.cfg.ExpireAfter),
+		Unescalated:     e.cfg.Action == MutationExpiryEscalate,
+		Limit:           mutationExpiryBatch,
+	}
+	days := int(e.cfg.ExpireAfter / (24 * time.Hour))
+	for {
+		mutations, err := e.store.List(ctx, filter)
+		if err != nil {
+			return expired, escalated, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list overdue mutations")
+		}
+		failed := false
+		for _, mutation := range mutations {
+			if ctx.Err() != nil {
+				return expired, escalated, nil
+			}
+			var ok bool
+			if e.cfg.Action == MutationExpiryReject {
+				ok, err = e.reject(ctx, mutation, days, now)
+			} else {
+				ok, err = e.escalate(ctx, mutation, days, now)
+			}
+			if err != nil {
+				e.logger.Warn("failed to expire mutation", zap.String("mutation_id", mutation.ID), zap.String("action", e.cfg.Action), zap.Error(err))
+				failed = true
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if e.cfg.Action == MutationExpiryReject {
+				expired++
+			} else {
+				escalated++
+			}
+		}
+		if failed || len(mutations) < mutationExpiryBatch {
+			return expired, escalated, nil
+		}
+	}
+}
+
+// reject closes a mutation without a reviewer and tells the requester. It reports false when the
+// mutation was reviewed meanwhile.
+func (e *MutationExpiry) reject(ctx context.Context, mutation models.Mutation, days int, now time.Time) (bool, error) {
+	note := fmt.Sprintf("Automatically rejected after %d day(s) without review.", days)
+	if err := e.store.Expire(ctx, mutation.ID, note, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	e.emitAudit(ctx, models.AuditActionMutationExpire, mutation, map[string]interface{}{
+		"status":      models.MutationStatusRejected,
+		"requestedAt": mutation.RequestedAt,
+		"expireDays":  days,
+	})
+	if e.notifications != nil {
+		id := mutation.ID
+		key := fmt.Sprintf("mutation-expired:%s", mutation.ID)
+		if _, err := e.notifications.CreateOnce(ctx, &models.Notification{
+			UserID:    mutation.RequestedBy,
+			Type:      models.NotificationTypeMutationExpired,
+			Title:     "Change request expired",
+			Body:      fmt.Sprintf("Your %s change request was rejected after %d day(s) without review. Submit it again if it is still needed.", mutation.Entity, days),
+			RefID:     &id,
+			DedupeKey: &key,
+		}); err != nil {
+			e.logger.Warn("failed to notify requester of expired mutation", zap.String("mutation_id", mutation.ID), zap.Error(err))
+		}
+	}
+	return true, nil
+}
+
+// escalate flags a mutation and notifies every super admin once. It reports false when the
+// mutation was reviewed or escalated meanwhile.
+func (e *MutationExpiry) escalate(ctx context.Context, mutation models.Mutation, days int, now time.Time) (bool, error) {
+	if err := e.store.Escalate(ctx, mutation.ID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	e.emitAudit(ctx, models.AuditActionMutationEscalate, mutation, map[string]interface{}{
+		"requestedAt": mutation.RequestedAt,
+		"expireDays":  days,
+	})
+	if e.notifications != nil {
+		id := mutation.ID
+		key := fmt.Sprintf("mutation-escalated:%s", mutation.ID)
+		if _, err := e.notifications.CreateForRoles(ctx, []models.UserRole{models.RoleSuperAdmin}, models.Notification{
+			Type:      models.NotificationTypeMutationEscalated,
+			Title:     "Change request escalated",
+			Body:      fmt.Sprintf("A %s change request has been waiting for review since %s.", mutation.Entity, mutation.RequestedAt.UTC().Format("2006-01-02")),
+			RefID:     &id,
+			DedupeKey: &key,
+		}); err != nil {
+			e.logger.Warn("failed to notify escalated mutation", zap.String("mutation_id", mutation.ID), zap.Error(err))
+		}
+	}
+	return true, nil
+}
+
+// remind sends reviewers at most one digest per day and returns how many notifications were written.
+func (e *MutationExpiry) remind(ctx context.Context, summary *models.MutationPendingSummary, now time.Time) int {
+	if e.notifications == nil {
+		return 0
+	}
+	day := now.Format("2006-01-02")
+	key := fmt.Sprintf("mutation-reminder:%s", day)
+	written, err := e.notifications.CreateForRoles(ctx, []models.UserRole{models.RoleSuperAdmin}, models.Notification{
+		Type:      models.NotificationTypeMutationReminder,
+		Title:     "Change requests awaiting review",
+		Body:      fmt.Sprintf("%d change request(s) have been pending for more than %d day(s).", summary.Overdue, int(e.cfg.ReminderAfter/(24*time.Hour))),
+		DedupeKey: &key,
+	})
+	if err != nil {
+		e.logger.Warn("mutation reminder failed", zap.String("day", day), zap.Error(err))
+		return 0
+	}
+	return written
+}
+
+func (e *MutationExpiry) emitAudit(ctx context.Context, action string, mutation models.Mutation, values map[string]interface{}) {
+	if e.audit == nil {
+		return
+	}
+	values["mutationId"] = mutation.ID
+	payload, _ := json.Marshal(values)
+	entityID := mutation.EntityID
+	log := &models.AuditLog{
+		Action:     action,
+		Resource:   mutation.Entity,
+		ResourceID: &entityID,
+		NewValues:  payload,
+		IPAddress:  "system",
+		UserAgent:  "mutation-expiry",
+	}
+	if err := e.audit.CreateAuditLog(ctx, log); err != nil {
+		e.logger.Warn("failed to create mutation expiry audit", zap.Error(err))
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type mutationExpiryStoreStub struct {
+	mutations map[string]*models.Mutation
+}
+
+func (s *mutationExpiryStoreStub) List(ctx context.Context, filter models.MutationFilter) ([]models.Mutation, error) {
+	var result []models.Mutation
+	for _, mutation := range s.mutations {
+		if mutation.Status != models.MutationStatusPending || !mutation.RequestedAt.Before(filter.RequestedBefore) {
+			continue
+		}
+		if filter.Unescalated && mutation.EscalatedAt != nil {
+			continue
+		}
+		result = append(result, *mutation)
+	}
+	return result, nil
+}
+
+func (s *mutationExpiryStoreStub) Expire(ctx context.Context, id, note string, at time.Time) error {
+	mutation, ok := s.mutations[id]
+	if !ok || mutation.Status != models.MutationStatusPending {
+		return sql.ErrNoRows
+	}
+	mutation.Status = models.MutationStatusRejected
+	mutation.ReviewedAt = &at
+	mutation.Note = &note
+	return nil
+}
+
+func (s *mutationExpiryStoreStub) Escalate(ctx context.Context, id string, at time.Time) error {
+	mutation, ok := s.mutations[id]
+	if !ok || mutation.Status != models.MutationStatusPending || mutation.EscalatedAt != nil {
+		return sql.ErrNoRows
+	}
+	mutation.EscalatedAt = &at
+	return nil
+}
+
+func (s *mutationExpiryStoreStub) PendingSummary(ctx context.Context, overdueBefore time.Time) (*models.MutationPendingSummary, error) {
+	summary := &models.MutationPendingSummary{}
+	for _, mutation := range s.mutations {
+		if mutation.Status != models.MutationStatusPending {
+			continue
+		}
+		summary.Pending++
+		if mutation.RequestedAt.Before(overdueBefore) {
+			summary.Overdue++
+		}
+		if mutation.EscalatedAt != nil {
+			summary.Escalated++
+		}
+		if summary.OldestRequestedAt == nil || mutation.RequestedAt.Before(*summary.OldestRequestedAt) {
+			requestedAt := mutation.RequestedAt
+			summary.OldestRequestedAt = &requestedAt
+		}
+	}
+	return summary, nil
+}
+
+type mutationNotifierStub struct {
+	notificationWriterStub
+	broadcasts []models.Notification
+	roleKeys   map[string]bool
+}
+
+func (s *mutationNotifierStub) CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error) {
+	if s.roleKeys == nil {
+		s.roleKeys = map[string]bool{}
+	}
+	if s.roleKeys[*notification.DedupeKey] {
+		return 0, nil
+	}
+	s.roleKeys[*notification.DedupeKey] = true
+	s.broadcasts = append(s.broadcasts, notification)
+	return 2, nil
+}
+
+func newMutationExpiryFixture(now time.Time) *mutationExpiryStoreStub {
+	day := 24 * time.Hour
+	return &mutationExpiryStoreStub{mutations: map[string]*models.Mutation{
+		"stale":    {ID: "stale", Entity: "student", EntityID: "student-1", RequestedBy: "admin-1", Status: models.MutationStatusPending, RequestedAt: now.Add(-20 * day)},
+		"overdue":  {ID: "overdue", Entity: "student", EntityID: "student-2", RequestedBy: "admin-1", Status: models.MutationStatusPending, RequestedAt: now.Add(-5 * day)},
+		"fresh":    {ID: "fresh", Entity: "student", EntityID: "student-3", RequestedBy: "admin-2", Status: models.MutationStatusPending, RequestedAt: now.Add(-day)},
+		"reviewed": {ID: "reviewed", Entity: "student", EntityID: "student-4", RequestedBy: "admin-2", Status: models.MutationStatusApproved, RequestedAt: now.Add(-30 * day)},
+	}}
+}
+
+func TestMutationExpiryEscalatesOnce(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	store := newMutationExpiryFixture(now)
+	notifier := &mutationNotifierStub{}
+	audit := &auditStub{}
+	job := NewMutationExpiry(store, notifier, audit, nil, nil, MutationExpiryConfig{
+		ReminderAfter: 3 * 24 * time.Hour,
+		ExpireAfter:   14 * 24 * time.Hour,
+		Action:        MutationExpiryEscalate,
+	})
+	job.now = func() time.Time { return now }
+
+	run, err := job.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Escalated)
+	assert.Zero(t, run.Expired)
+	assert.Equal(t, 2, run.Reminded)
+	assert.Equal(t, models.MutationStatusPending, store.mutations["stale"].Status)
+	require.NotNil(t, store.mutations["stale"].EscalatedAt)
+	require.Len(t, audit.logs, 1)
+	assert.Equal(t, models.AuditActionMutationEscalate, audit.logs[0].Action)
+	assert.Equal(t, "student-1", *audit.logs[0].ResourceID)
+	require.Len(t, notifier.broadcasts, 2)
+	assert.Equal(t, models.NotificationTypeMutationEscalated, notifier.broadcasts[0].Type)
+	assert.Equal(t, models.NotificationTypeMutationReminder, notifier.broadcasts[1].Type)
+	assert.Contains(t, notifier.broadcasts[1].Body, "2 change request(s)")
+
+	// Escalation and the daily digest are not repeated on the next sweep.
+	run, err = job.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, run.Escalated)
+	assert.Zero(t, run.Reminded)
+	assert.Len(t, audit.logs, 1)
+
+	stats, err := job.PendingStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Pending)
+	assert.Equal(t, 2, stats.Overdue)
+	assert.Equal(t, 1, stats.Escalated)
+	assert.Equal(t, 20, stats.OldestAgeDays)
+}
+
+func TestMutationExpiryRejects(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	store := newMutationExpiryFixture(now)
+	notifier := &mutationNotifierStub{}
+	audit := &auditStub{}
+	job := NewMutationExpiry(store, notifier, audit, nil, nil, MutationExpiryConfig{
+		ExpireAfter: 14 * 24 * time.Hour,
+		Action:      MutationExpiryReject,
+	})
+	job.now = func() time.Time { return now }
+
+	run, err := job.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Expired)
+	assert.Zero(t, run.Reminded)
+	stale := store.mutations["stale"]
+	assert.Equal(t, models.MutationStatusRejected, stale.Status)
+	assert.Nil(t, stale.ReviewedBy)
+	require.NotNil(t, stale.Note)
+	assert.Contains(t, *stale.Note, "14 day(s)")
+	require.Len(t, audit.logs, 1)
+	assert.Equal(t, models.AuditActionMutationExpire, audit.logs[0].Action)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "admin-1", notifier.sent[0].UserID)
+	assert.Equal(t, models.NotificationTypeMutationExpired, notifier.sent[0].Type)
+	assert.Empty(t, notifier.broadcasts)
+
+	stats, err := job.PendingStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Pending)
+	assert.Zero(t, stats.Overdue)
+	assert.Equal(t, 5, stats.OldestAgeDays)
+}
@@ -9,10 +9,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
@@ -21,14 +24,23 @@ type mutationStore interface {
 	Create(ctx context.Context, mutation *models.Mutation) error
 	GetByID(ctx context.Context, id string) (*models.Mutation, error)
 	List(ctx context.Context, filter models.MutationFilter) ([]models.Mutation, error)
-	UpdateStatusAndSnapshot(ctx context.Context, params repository.UpdateMutationParams) error
+	UpdateStatusAndSnapshot(ctx context.Context, exec sqlx.ExtContext, params repository.UpdateMutationParams) error
+	AddAttachment(ctx context.Context, attachment *models.MutationAttachment) error
+	ListAttachments(ctx context.Context, mutationID string) ([]models.MutationAttachment, error)
 }
 
-type auditLogger interface {
-	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+type mutationAttachmentUploader interface {
+	UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error)
 }
 
-// MutationSnapshotProvider resolves the latest entity snapshot for audit trails.
+const (
+	// MutationAttachmentCategory is the archive category mutation evidence is filed under.
+	MutationAttachmentCategory = "mutation_evidence"
+	maxMutationAttachments     = 10
+)
+
+// MutationSnapshotProvider resolves the latest entity snapshot for audit trails. Appliers that
+// implement it capture the snapshots of their entity unless WithMutationSnapshotProvider is used.
 type MutationSnapshotProvider interface {
 	Snapshot(ctx context.Context, entity, entityID string) ([]byte, error)
 }
@@ -48,12 +60,15 @@ func (f MutationApplierFunc) Apply(ctx context.Context, mutation *models.Mutatio
 
 // MutationService orchestrates mutation requests and reviews.
 type MutationService struct {
-	repo      mutationStore
-	audit     auditLogger
-	snapshot  MutationSnapshotProvider
-	appliers  map[string]MutationApplier
-	logger    *zap.Logger
-	validator mutationValidator
+	repo        mutationStore
+	audit       ports.AuditLogger
+	snapshot    MutationSnapshotProvider
+	appliers    map[string]MutationApplier
+	events      *DomainEvents
+	attachments mutationAttachmentUploader
+	differ      *MutationDiffer
+	logger      *zap.Logger
+	validator   mutationValidator
 }
 
 type mutationValidator interface {
@@ -84,19 +99,31 @@ func WithMutationSnapshotProvider(provider MutationSnapshotProvider) MutationSer
 	}
 }
 
+// WithMutationEvents emits mutation.approved when a review approves a mutation.
+func WithMutationEvents(events *DomainEvents) MutationServiceOption {
+	return func(s *MutationService) {
+		s.events = events
+	}
+}
+
+// WithMutationAttachments stores supporting evidence in the archive. Without it Attach fails, as
+// archives are disabled.
+func WithMutationAttachments(uploader mutationAttachmentUploader) MutationServiceOption {
+	return func(s *MutationService) {
+		s.attachments = uploader
+	}
+}
+
 // NewMutationService constructs the service with defaults.
-func NewMutationService(repo mutationStore, audit auditLogger, logger *zap.Logger, opts ...MutationServiceOption) *MutationService {
+func NewMutationService(repo mutationStore, audit ports.AuditLogger, logger *zap.Logger, opts ...MutationServiceOption) *MutationService {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	svc := &MutationService{
-		repo:     repo,
-		audit:    audit,
-		logger:   logger,
-		appliers: make(map[string]MutationApplier),
-		snapshot: MutationSnapshotProviderFunc(func(context.Context, string, string) ([]byte, error) {
-			return []byte("{}"), nil
-		}),
+		repo:      repo,
+		audit:     audit,
+		logger:    logger,
+		appliers:  make(map[string]MutationApplier),
 		validator: &defaultMutationValidator{},
 	}
 	for _, opt := range opts {
@@ -104,9 +131,27 @@ func NewMutationService(repo mutationStore, audit auditLogger, logger *zap.Logge
 			opt(svc)
 		}
 	}
+	if svc.snapshot == nil {
+		svc.snapshot = MutationSnapshotProviderFunc(svc.applierSnapshot)
+	}
+	fields := make(map[string][]MutationField)
+	for entity, applier := range svc.appliers {
+		if describer, ok := applier.(MutationFieldDescriber); ok {
+			fields[entity] = describer.MutationFields()
+		}
+	}
+	svc.differ = NewMutationDiffer(fields)
 	return svc
 }
 
+// applierSnapshot asks the entity's applier for a snapshot, falling back to an empty object.
+func (s *MutationService) applierSnapshot(ctx context.Context, entity, entityID string) ([]byte, error) {
+	if provider, ok := s.appliers[strings.ToLower(strings.TrimSpace(entity))].(MutationSnapshotProvider); ok {
+		return provider.Snapshot(ctx, entity, entityID)
+	}
+	return []byte("{}"), nil
+}
+
 // RequestChange stores a new mutation request after validating payloads.
 func (s *MutationService) RequestChange(ctx context.Context, req dto.CreateMutationRequest, userID string) (*models.Mutation, error) {
 	if err := s.validator.ValidateRequest(req); err != nil {
@@ -118,6 +163,10 @@ func (s *MutationService) RequestChange(ctx context.Context, req dto.CreateMutat
 	}
 	snapshot, err := s.snapshot.Snapshot(ctx, req.Entity, req.EntityID)
 	if err != nil {
+		var appErr *appErrors.Error
+		if errors.As(err, &appErr) {
+			return nil, err
+		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to capture current snapshot")
 	}
 	if len(snapshot) == 0 {
@@ -171,7 +220,8 @@ func (s *MutationService) List(ctx context.Context, query dto.MutationQuery, act
 	return mutations, nil
 }
 
-// Get returns a mutation enforcing scope constraints.
+// Get returns a mutation enforcing scope constraints, with its attachments and, until it is approved,
+// a field-level diff of the requested changes against the snapshot.
 func (s *MutationService) Get(ctx context.Context, id string, actor *models.JWTClaims) (*models.Mutation, error) {
 	if actor == nil {
 		return nil, appErrors.ErrUnauthorized
@@ -186,9 +236,73 @@ func (s *MutationService) Get(ctx context.Context, id string, actor *models.JWTC
 	if actor.Role == models.RoleTeacher && mutation.RequestedBy != actor.UserID {
 		return nil, appErrors.ErrForbidden
 	}
+	attachments, err := s.repo.ListAttachments(ctx, mutation.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load mutation attachments")
+	}
+	mutation.Attachments = attachments
+	// Approval replaces the snapshot with the applied state, so only unapplied mutations are diffed.
+	if mutation.Status != models.MutationStatusApproved {
+		diff, err := s.differ.Diff(mutation.Entity, mutation.CurrentSnapshot, mutation.RequestedChanges)
+		if err != nil {
+			s.logger.Warn("failed to diff mutation", zap.String("mutation_id", mutation.ID), zap.Error(err))
+		}
+		mutation.Diff = diff
+	}
 	return mutation, nil
 }
 
+// Attach stores a supporting document, such as a scanned score sheet, for a pending mutation. Teachers
+// can only attach to their own requests. The file is kept in the archive with MUTATION scope and is
+// downloadable through the archive endpoints by its uploader and administrators.
+func (s *MutationService) Attach(ctx context.Context, id string, upload ArchiveUpload, actor *models.JWTClaims) (*models.MutationAttachment, error) {
+	if s.attachments == nil {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "mutation attachments require archives to be enabled")
+	}
+	mutation, err := s.Get(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if mutation.Status != models.MutationStatusPending {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "mutation already reviewed")
+	}
+	if len(mutation.Attachments) >= maxMutationAttachments {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("a mutation can have at most %d attachments", maxMutationAttachments))
+	}
+	title := strings.TrimSpace(upload.Filename)
+	if title == "" {
+		title = fmt.Sprintf("Evidence for mutation %s", mutation.ID)
+	}
+	item, err := s.attachments.UploadAttachment(ctx, dto.CreateArchiveRequest{
+		Title:    title,
+		Category: MutationAttachmentCategory,
+		Scope:    models.ArchiveScopeMutation,
+	}, upload, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	attachment := &models.MutationAttachment{
+		MutationID: mutation.ID,
+		ArchiveID:  item.ID,
+		Title:      item.Title,
+		MimeType:   item.MimeType,
+		SizeBytes:  item.SizeBytes,
+		AttachedBy: actor.UserID,
+	}
+	if err := s.repo.AddAttachment(ctx, attachment); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to attach document to mutation")
+	}
+	values, _ := json.Marshal(map[string]interface{}{"archiveId": item.ID, "title": item.Title})
+	s.emitAudit(ctx, &models.AuditLog{
+		UserID:     &actor.UserID,
+		Action:     models.AuditActionMutationAttach,
+		Resource:   mutation.Entity,
+		ResourceID: &mutation.EntityID,
+		NewValues:  values,
+	})
+	return attachment, nil
+}
+
 // Review applies reviewer decision and records audit trail.
 func (s *MutationService) Review(ctx context.Context, id string, req dto.ReviewMutationRequest, reviewerID string) (*models.Mutation, error) {
 	mutation, err := s.repo.GetByID(ctx, id)
@@ -202,6 +316,11 @@ func (s *MutationService) Review(ctx context.Context, id string, req dto.ReviewM
 	if mutation.Status != models.MutationStatusPending {
 		return nil, appErrors.Clone(appErrors.ErrConflict, "mutation already reviewed")
 	}
+	attachments, err := s.repo.ListAttachments(ctx, mutation.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load mutation attachments")
+	}
+	mutation.Attachments = attachments
 	if req.Status != models.MutationStatusApproved && req.Status != models.MutationStatusRejected {
 		return nil, appErrors.Clone(appErrors.ErrValidation, "status must be APPROVED or REJECTED")
 	}
@@ -228,7 +347,20 @@ func (s *MutationService) Review(ctx context.Context, id string, req dto.ReviewM
 	if len(newSnapshot) > 0 {
 		params.CurrentSnapshot = newSnapshot
 	}
-	if err := s.repo.UpdateStatusAndSnapshot(ctx, params); err != nil {
+	err = s.events.Write(ctx, func(exec sqlx.ExtContext) ([]*models.OutboxEvent, error) {
+		if err := s.repo.UpdateStatusAndSnapshot(ctx, exec, params); err != nil {
+			return nil, err
+		}
+		if req.Status != models.MutationStatusApproved {
+			return nil, nil
+		}
+		event, err := mutationApprovedEvent(mutation, reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.OutboxEvent{event}, nil
+	})
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, appErrors.Clone(appErrors.ErrConflict, "mutation already processed")
 		}
@@ -248,12 +380,52 @@ func (s *MutationService) Review(ctx context.Context, id string, req dto.ReviewM
 		Action:     models.AuditActionMutationReview,
 		Resource:   mutation.Entity,
 		ResourceID: &mutation.EntityID,
-		NewValues:  mutation.RequestedChanges,
+		NewValues:  mutationReviewAuditValues(mutation),
 		OldValues:  oldSnapshot,
 	})
 	return mutation, nil
 }
 
+// mutationReviewAuditValues records the reviewed changes with references to the evidence the reviewer
+// had, so the audit trail still names it if the archives are later deleted.
+func mutationReviewAuditValues(mutation *models.Mutation) []byte {
+	type evidence struct {
+		ArchiveID string `json:"archiveId"`
+		Title     string `json:"title"`
+	}
+	refs := make([]evidence, 0, len(mutation.Attachments))
+	for _, attachment := range mutation.Attachments {
+		refs = append(refs, evidence{ArchiveID: attachment.ArchiveID, Title: attachment.Title})
+	}
+	changes := json.RawMessage(mutation.RequestedChanges)
+	if len(changes) == 0 {
+		changes = json.RawMessage("{}")
+	}
+	values, err := json.Marshal(struct {
+		Status           models.MutationStatus `json:"status"`
+		RequestedChanges json.RawMessage       `json:"requestedChanges"`
+		Attachments      []evidence            `json:"attachments"`
+	}{Status: mutation.Status, RequestedChanges: changes, Attachments: refs})
+	if err != nil {
+		return mutation.RequestedChanges
+	}
+	return values
+}
+
+func mutationApprovedEvent(mutation *models.Mutation, reviewerID string) (*models.OutboxEvent, error) {
+	changes := types.JSONText(mutation.RequestedChanges)
+	if len(changes) == 0 {
+		changes = types.JSONText("{}")
+	}
+	return newDomainEvent(models.DomainEventMutationApproved, mutation.ID, models.MutationApprovedEvent{
+		MutationID: mutation.ID,
+		Entity:     mutation.Entity,
+		EntityID:   mutation.EntityID,
+		ReviewedBy: reviewerID,
+		Changes:    changes,
+	})
+}
+
 func (s *MutationService) emitAudit(ctx context.Context, log *models.AuditLog) {
 	if s.audit == nil || log == nil {
 		return
@@ -1,20 +1,26 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type mutationRepoStub struct {
-	mutations map[string]*models.Mutation
-	filter    models.MutationFilter
+	mutations   map[string]*models.Mutation
+	attachments []models.MutationAttachment
+	filter      models.MutationFilter
 }
 
 func newMutationRepoStub() *mutationRepoStub {
@@ -43,7 +49,7 @@ func (m *mutationRepoStub) List(ctx context.Context, filter models.MutationFilte
 	return result, nil
 }
 
-func (m *mutationRepoStub) UpdateStatusAndSnapshot(ctx context.Context, params repository.UpdateMutationParams) error {
+func (m *mutationRepoStub) UpdateStatusAndSnapshot(ctx context.Context, exec sqlx.ExtContext, params repository.UpdateMutationParams) error {
 	mut, ok := m.mutations[params.ID]
 	if !ok {
 		return sql.ErrNoRows
@@ -60,6 +66,30 @@ func (m *mutationRepoStub) UpdateStatusAndSnapshot(ctx context.Context, params r
 	return nil
 }
 
+func (m *mutationRepoStub) AddAttachment(ctx context.Context, attachment *models.MutationAttachment) error {
+	m.attachments = append(m.attachments, *attachment)
+	return nil
+}
+
+func (m *mutationRepoStub) ListAttachments(ctx context.Context, mutationID string) ([]models.MutationAttachment, error) {
+	var result []models.MutationAttachment
+	for _, attachment := range m.attachments {
+		if attachment.MutationID == mutationID {
+			result = append(result, attachment)
+		}
+	}
+	return result, nil
+}
+
+type mutationUploaderStub struct {
+	meta []dto.CreateArchiveRequest
+}
+
+func (u *mutationUploaderStub) UploadAttachment(ctx context.Context, meta dto.CreateArchiveRequest, upload ArchiveUpload, uploaderID string) (*models.ArchiveItem, error) {
+	u.meta = append(u.meta, meta)
+	return &models.ArchiveItem{ID: fmt.Sprintf("arch-%d", len(u.meta)), Title: meta.Title, MimeType: "application/pdf", SizeBytes: upload.Size, UploadedBy: uploaderID}, nil
+}
+
 type auditStub struct {
 	logs []*models.AuditLog
 }
@@ -120,6 +150,44 @@ func TestMutationServiceReviewApprove(t *testing.T) {
 	require.Len(t, audit.logs, 1)
 }
 
+func TestMutationServiceDiffsStudentChanges(t *testing.T) {
+	repo := newMutationRepoStub()
+	students := &studentMutationRepoStub{detail: &models.StudentDetail{Student: models.Student{
+		ID:        "student-1",
+		NIS:       "123",
+		FullName:  "Jon",
+		BirthDate: time.Date(2005, 1, 2, 0, 0, 0, 0, time.UTC),
+	}}}
+	svc := NewMutationService(repo, nil, nil, WithMutationAppliers(map[string]MutationApplier{
+		"student": NewStudentMutationApplier(students, nil),
+	}))
+
+	created, err := svc.RequestChange(context.Background(), dto.CreateMutationRequest{
+		Type:             models.MutationTypeStudentData,
+		Entity:           "Student",
+		EntityID:         "student-1",
+		Reason:           "typo",
+		RequestedChanges: []byte(`{"fullName":"John","nis":"123"}`),
+	}, "admin-1")
+	require.NoError(t, err)
+	require.Contains(t, string(created.CurrentSnapshot), `"full_name":"Jon"`)
+
+	mutation, err := svc.Get(context.Background(), created.ID, &models.JWTClaims{UserID: "super-1", Role: models.RoleSuperAdmin})
+	require.NoError(t, err)
+	require.Len(t, mutation.Diff, 2)
+	require.Equal(t, "NIS", mutation.Diff[0].Label)
+	require.False(t, mutation.Diff[0].Changed)
+	require.Equal(t, "Full name", mutation.Diff[1].Label)
+	require.JSONEq(t, `"Jon"`, string(mutation.Diff[1].Old))
+	require.JSONEq(t, `"John"`, string(mutation.Diff[1].New))
+	require.True(t, mutation.Diff[1].Changed)
+
+	repo.mutations[created.ID].Status = models.MutationStatusApproved
+	mutation, err = svc.Get(context.Background(), created.ID, &models.JWTClaims{UserID: "super-1", Role: models.RoleSuperAdmin})
+	require.NoError(t, err)
+	require.Nil(t, mutation.Diff)
+}
+
 func TestMutationServiceListTeacherFilters(t *testing.T) {
 	repo := newMutationRepoStub()
 	audit := &auditStub{}
@@ -133,3 +201,52 @@ func TestMutationServiceListTeacherFilters(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "teacher-1", repo.filter.RequestedBy)
 }
+
+func TestMutationServiceAttachments(t *testing.T) {
+	repo := newMutationRepoStub()
+	audit := &auditStub{}
+	repo.mutations["mut-1"] = &models.Mutation{
+		ID:               "mut-1",
+		Type:             models.MutationTypeGradeCorrection,
+		Entity:           "grade",
+		EntityID:         "grade-1",
+		Status:           models.MutationStatusPending,
+		RequestedChanges: []byte(`{"score":85}`),
+		RequestedBy:      "teacher-1",
+	}
+	teacher := &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher}
+	upload := ArchiveUpload{Filename: "score sheet.pdf", Size: 3, Content: bytes.NewReader([]byte("pdf"))}
+
+	_, err := NewMutationService(repo, audit, nil).Attach(context.Background(), "mut-1", upload, teacher)
+	require.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	uploader := &mutationUploaderStub{}
+	svc := NewMutationService(repo, audit, nil, WithMutationAttachments(uploader), WithMutationAppliers(map[string]MutationApplier{
+		"grade": MutationApplierFunc(func(ctx context.Context, mut *models.Mutation) ([]byte, error) {
+			return []byte(`{"score":85}`), nil
+		}),
+	}))
+	_, err = svc.Attach(context.Background(), "mut-1", upload, &models.JWTClaims{UserID: "teacher-2", Role: models.RoleTeacher})
+	require.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	attachment, err := svc.Attach(context.Background(), "mut-1", upload, teacher)
+	require.NoError(t, err)
+	require.Equal(t, "arch-1", attachment.ArchiveID)
+	require.Equal(t, models.ArchiveScopeMutation, uploader.meta[0].Scope)
+	require.Equal(t, MutationAttachmentCategory, uploader.meta[0].Category)
+	require.Equal(t, models.AuditActionMutationAttach, audit.logs[0].Action)
+
+	detail, err := svc.Get(context.Background(), "mut-1", teacher)
+	require.NoError(t, err)
+	require.Len(t, detail.Attachments, 1)
+	require.Equal(t, "score sheet.pdf", detail.Attachments[0].Title)
+
+	_, err = svc.Review(context.Background(), "mut-1", dto.ReviewMutationRequest{Status: models.MutationStatusApproved}, "super-1")
+	require.NoError(t, err)
+	review := audit.logs[len(audit.logs)-1]
+	require.Equal(t, models.AuditActionMutationReview, review.Action)
+	require.JSONEq(t, `{"status":"APPROVED","requestedChanges":{"score":85},"attachments":[{"archiveId":"arch-1","title":"score sheet.pdf"}]}`, string(review.NewValues))
+
+	_, err = svc.Attach(context.Background(), "mut-1", upload, teacher)
+	require.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/push"
+)
+
+type pushQueue interface {
+	ClaimPush(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error)
+	MarkPushed(ctx context.Context, id string, pushedAt time.Time) error
+	MarkPushFailed(ctx context.Context, id, reason string, retryAt *time.Time) error
+}
+
+type pushDeviceStore interface {
+	ListByUsers(ctx context.Context, userIDs []string) ([]models.DeviceToken, error)
+	DeleteTokens(ctx context.Context, tokens []string) error
+}
+
+type pushSender interface {
+	Send(ctx context.Context, msg push.Message) error
+}
+
+// PushChannelConfig tunes push delivery.
+type PushChannelConfig struct {
+	PollInterval time.Duration
+	// BatchSize is how many notifications are claimed per round; their devices are loaded together.
+	BatchSize int
+	// Concurrency bounds how many notifications of a batch are sent at once.
+	Concurrency int
+	SendTimeout time.Duration
+	// MaxAttempts is how many times a notification is tried before it is given up.
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry; it doubles with every attempt.
+	RetryBackoff time.Duration
+	// MaxAge drops notifications that could not be pushed while still relevant.
+	MaxAge time.Duration
+}
+
+// pushOutcome is the result of pushing one notification to all of its user's devices.
+type pushOutcome struct {
+	delivered int
+	invalid   []string
+	err       error
+}
+
+// PushChannel delivers queued in-app notifications to the recipients' registered devices. A
+// notification is retried only when no device accepted it, so a device never gets it twice.
+type PushChannel struct {
+	queue   pushQueue
+	devices pushDeviceStore
+	sender  pushSender
+	cfg     PushChannelConfig
+	lease   time.Duration
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewPushChannel constructs the channel with defaults.
+func NewPushChannel(queue pushQueue, devices pushDeviceStore, sender pushSender, cfg PushChannelConfig, logger *zap.Logger) *PushChannel {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 10
+	}
+	if cfg.SendTimeout <= 0 {
+		cfg.SendTimeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 30 * time.Second
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 24 * time.Hour
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	// A claimed batch is leased for the worst case of every send timing out, plus one timeout of slack;
+	// if this replica stops, another takes the batch over once the lease ends.
+	rounds := (cfg.BatchSize + cfg.Concurrency - 1) / cfg.Concurrency
+	lease := time.Duration(rounds+1) * cfg.SendTimeout
+	return &PushChannel{queue: queue, devices: devices, sender: sender, cfg: cfg, lease: lease, logger: logger, now: time.Now}
+}
+
+// Start pushes queued notifications every poll interval until ctx is done.
+func (p *PushChannel) Start(ctx context.Context) {
+	go p.run(ctx)
+}
+
+func (p *PushChannel) run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				claimed, err := p.Dispatch(ctx)
+				if err != nil {
+					p.logger.Warn("push dispatch failed", zap.Error(err))
+					break
+				}
+				if claimed < p.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Dispatch claims one batch of due notifications, pushes them and returns how many were claimed.
+func (p *PushChannel) Dispatch(ctx context.Context) (int, error) {
+	now := p.now().UTC()
+	items, err := p.queue.ClaimPush(ctx, now, now.Add(p.lease), p.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	userIDs := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := seen[item.UserID]; !ok {
+			seen[item.UserID] = struct{}{}
+			userIDs = append(userIDs, item.UserID)
+		}
+	}
+	// The batch stays leased on error and is claimed again once the lease ends.
+	devices, err := p.devices.ListByUsers(ctx, userIDs)
+	if err != nil {
+		return len(items), fmt.Errorf("load push devices: %w", err)
+	}
+	tokens := make(map[string][]string, len(userIDs))
+	for _, device := range devices {
+		tokens[device.UserID] = append(tokens[device.UserID], device.Token)
+	}
+
+	outcomes := make([]pushOutcome, len(items))
+	sem := make(chan struct{}, p.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for i := range items {
+		if now.Sub(items[i].CreatedAt) > p.cfg.MaxAge {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			outcomes[i] = p.deliver(ctx, &items[i], tokens[items[i].UserID])
+		}(i)
+	}
+	wg.Wait()
+
+	var invalid []string
+	for i := range items {
+		invalid = append(invalid, outcomes[i].invalid...)
+		p.finish(ctx, &items[i], outcomes[i], now)
+	}
+	if err := p.devices.DeleteTokens(ctx, invalid); err != nil {
+		p.logger.Warn("failed to forget invalid push tokens", zap.Int("tokens", len(invalid)), zap.Error(err))
+	}
+	return len(items), nil
+}
+
+func (p *PushChannel) deliver(ctx context.Context, item *models.Notification, tokens []string) pushOutcome {
+	var outcome pushOutcome
+	data := map[string]string{"notificationId": item.ID, "type": item.Type}
+	if item.RefID != nil {
+		data["refId"] = *item.RefID
+	}
+	for _, token := range tokens {
+		sendCtx, cancel := context.WithTimeout(ctx, p.cfg.SendTimeout)
+		err := p.sender.Send(sendCtx, push.Message{Token: token, Title: item.Title, Body: item.Body, Data: data})
+		cancel()
+		switch {
+		case err == nil:
+			outcome.delivered++
+		case errors.Is(err, push.ErrInvalidToken):
+			outcome.invalid = append(outcome.invalid, token)
+		default:
+			outcome.err = err
+		}
+	}
+	return outcome
+}
+
+// finish records the outcome of one notification. Failures to record are logged; the notification
+// becomes due again when its lease ends.
+func (p *PushChannel) finish(ctx context.Context, item *models.Notification, outcome pushOutcome, now time.Time) {
+	var err error
+	switch {
+	case now.Sub(item.CreatedAt) > p.cfg.MaxAge:
+		err = p.queue.MarkPushFailed(ctx, item.ID, "expired before delivery", nil)
+	case outcome.delivered > 0:
+		if outcome.err != nil {
+			p.logger.Warn("push reached only some devices", zap.String("notification_id", item.ID), zap.Error(outcome.err))
+		}
+		err = p.queue.MarkPushed(ctx, item.ID, now)
+	case outcome.err == nil:
+		err = p.queue.MarkPushFailed(ctx, item.ID, "no registered devices", nil)
+	case item.PushAttempts >= p.cfg.MaxAttempts:
+		err = p.queue.MarkPushFailed(ctx, item.ID, outcome.err.Error(), nil)
+	default:
+		backoff := p.cfg.RetryBackoff
+		for i := 1; i < item.PushAttempts; i++ {
+			backoff *= 2
+		}
+		retryAt := now.Add(backoff)
+		err = p.queue.MarkPushFailed(ctx, item.ID, outcome.err.Error(), &retryAt)
+	}
+	if err != nil {
+		p.logger.Warn("failed to record push outcome", zap.String("notification_id", item.ID), zap.Error(err))
+	}
+}
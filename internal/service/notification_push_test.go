@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/push"
+)
+
+type pushQueueStub struct {
+	items   []models.Notification
+	pushed  []string
+	failed  map[string]string
+	retryAt map[string]*time.Time
+}
+
+func (s *pushQueueStub) ClaimPush(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.Notification, error) {
+	return s.items, nil
+}
+
+func (s *pushQueueStub) MarkPushed(ctx context.Context, id string, pushedAt time.Time) error {
+	s.pushed = append(s.pushed, id)
+	return nil
+}
+
+func (s *pushQueueStub) MarkPushFailed(ctx context.Context, id, reason string, retryAt *time.Time) error {
+	if s.failed == nil {
+		s.failed, s.retryAt = make(map[string]string), make(map[string]*time.Time)
+	}
+	s.failed[id], s.retryAt[id] = reason, retryAt
+	return nil
+}
+
+type pushDeviceStoreStub struct {
+	devices []models.DeviceToken
+	deleted []string
+}
+
+func (s *pushDeviceStoreStub) ListByUsers(ctx context.Context, userIDs []string) ([]models.DeviceToken, error) {
+	return s.devices, nil
+}
+
+func (s *pushDeviceStoreStub) DeleteTokens(ctx context.Context, tokens []string) error {
+	s.deleted = append(s.deleted, tokens...)
+	return nil
+}
+
+// pushSenderStub rejects "stale-*" tokens as invalid and "down-*" tokens as unavailable.
+type pushSenderStub struct {
+	mu   sync.Mutex
+	sent []push.Message
+}
+
+func (s *pushSenderStub) Send(ctx context.Context, msg push.Message) error {
+	switch {
+	case strings.HasPrefix(msg.Token, "stale-"):
+		return push.ErrInvalidToken
+	case strings.HasPrefix(msg.Token, "down-"):
+		return errors.New("fcm returned 503")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestPushChannelDispatch(t *testing.T) {
+	now := time.Date(2024, 7, 15, 7, 0, 0, 0, time.UTC)
+	refID := "ann-1"
+	queue := &pushQueueStub{items: []models.Notification{
+		{ID: "n-ok", UserID: "user-1", Type: models.NotificationTypeAnnouncement, Title: "Libur", RefID: &refID, CreatedAt: now, PushAttempts: 1},
+		{ID: "n-retry", UserID: "user-2", Title: "Alert", CreatedAt: now, PushAttempts: 2},
+		{ID: "n-giveup", UserID: "user-3", Title: "Alert", CreatedAt: now, PushAttempts: 3},
+		{ID: "n-none", UserID: "user-4", Title: "Alert", CreatedAt: now, PushAttempts: 1},
+		{ID: "n-old", UserID: "user-1", Title: "Reminder", CreatedAt: now.Add(-48 * time.Hour), PushAttempts: 1},
+	}}
+	devices := &pushDeviceStoreStub{devices: []models.DeviceToken{
+		{UserID: "user-1", Token: "phone-1"},
+		{UserID: "user-1", Token: "stale-1"},
+		{UserID: "user-2", Token: "down-2"},
+		{UserID: "user-3", Token: "down-3"},
+		{UserID: "user-4", Token: "stale-4"},
+	}}
+	sender := &pushSenderStub{}
+	channel := NewPushChannel(queue, devices, sender, PushChannelConfig{MaxAttempts: 3, RetryBackoff: time.Minute}, nil)
+	channel.now = func() time.Time { return now }
+
+	claimed, err := channel.Dispatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, claimed)
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "phone-1", sender.sent[0].Token)
+	assert.Equal(t, map[string]string{"notificationId": "n-ok", "type": models.NotificationTypeAnnouncement, "refId": "ann-1"}, sender.sent[0].Data)
+	assert.Equal(t, []string{"n-ok"}, queue.pushed)
+	assert.ElementsMatch(t, []string{"stale-1", "stale-4"}, devices.deleted)
+
+	assert.Equal(t, "fcm returned 503", queue.failed["n-retry"])
+	require.NotNil(t, queue.retryAt["n-retry"])
+	assert.Equal(t, now.Add(2*time.Minute), *queue.retryAt["n-retry"])
+	assert.Equal(t, "fcm returned 503", queue.failed["n-giveup"])
+	assert.Nil(t, queue.retryAt["n-giveup"])
+	assert.Equal(t, "no registered devices", queue.failed["n-none"])
+	assert.Equal(t, "expired before delivery", queue.failed["n-old"])
+}
+
+type announcementRepoStub struct {
+	created    []*models.Announcement
+	recipients []models.AnnouncementRecipient
+	summaries  []models.AnnouncementAckSummary
+}
+
+func (s *announcementRepoStub) List(ctx context.Context, filter models.AnnouncementFilter) ([]models.Announcement, int, error) {
+	return nil, 0, nil
+}
+
+func (s *announcementRepoStub) GetByID(ctx context.Context, id string) (*models.Announcement, error) {
+	for _, announcement := range s.created {
+		if announcement.ID == id {
+			return announcement, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *announcementRepoStub) Create(ctx context.Context, announcement *models.Announcement) error {
+	announcement.ID = "ann-1"
+	s.created = append(s.created, announcement)
+	return nil
+}
+
+func (s *announcementRepoStub) Update(ctx context.Context, announcement *models.Announcement) error {
+	return nil
+}
+
+func (s *announcementRepoStub) Delete(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *announcementRepoStub) Acknowledge(ctx context.Context, announcementID, userID string, at time.Time) (time.Time, error) {
+	for i := range s.recipients {
+		recipient := &s.recipients[i]
+		if recipient.UserID != userID {
+			continue
+		}
+		if recipient.AcknowledgedAt == nil {
+			recipient.AcknowledgedAt = &at
+		}
+		return *recipient.AcknowledgedAt, nil
+	}
+	return time.Time{}, sql.ErrNoRows
+}
+
+func (s *announcementRepoStub) ListRecipients(ctx context.Context, announcementID string, role models.UserRole) ([]models.AnnouncementRecipient, error) {
+	var result []models.AnnouncementRecipient
+	for _, recipient := range s.recipients {
+		if role == "" || recipient.Role == role {
+			result = append(result, recipient)
+		}
+	}
+	return result, nil
+}
+
+func (s *announcementRepoStub) ListUnacknowledged(ctx context.Context, limit int) ([]models.AnnouncementAckSummary, error) {
+	return s.summaries, nil
+}
+
+type announcementNotifierStub struct {
+	roles        []models.UserRole
+	notification models.Notification
+}
+
+func (s *announcementNotifierStub) CreateForRoles(ctx context.Context, roles []models.UserRole, notification models.Notification) (int, error) {
+	s.roles, s.notification = roles, notification
+	return 42, nil
+}
+
+func TestAnnouncementServiceBroadcast(t *testing.T) {
+	repo := &announcementRepoStub{}
+	notifier := &announcementNotifierStub{}
+	svc := NewAnnouncementService(repo, nil, nil, WithAnnouncementNotifications(notifier))
+	claims := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	result, err := svc.Broadcast(context.Background(), dto.BroadcastAnnouncementRequest{
+		Title:   "Libur semester",
+		Content: "Sekolah libur mulai 20 Desember.",
+		Roles:   []models.UserRole{"student", models.RoleGuardian, models.RoleStudent},
+	}, claims)
+	require.NoError(t, err)
+	assert.Equal(t, 42, result.Recipients)
+	assert.Equal(t, models.AnnouncementAudienceSiswa, result.Announcement.Audience)
+	assert.Equal(t, models.AnnouncementPriorityNormal, result.Announcement.Priority)
+	assert.Equal(t, "admin-1", result.Announcement.CreatedBy)
+	assert.Equal(t, []models.UserRole{models.RoleStudent, models.RoleGuardian}, notifier.roles)
+	assert.Equal(t, models.NotificationTypeAnnouncement, notifier.notification.Type)
+	require.NotNil(t, notifier.notification.DedupeKey)
+	assert.Equal(t, "announcement:ann-1", *notifier.notification.DedupeKey)
+
+	result, err = svc.Broadcast(context.Background(), dto.BroadcastAnnouncementRequest{
+		Title: "Rapat", Content: "Rapat guru", Roles: []models.UserRole{models.RoleTeacher, models.RoleAdmin},
+	}, claims)
+	require.NoError(t, err)
+	assert.Equal(t, models.AnnouncementAudienceGuru, result.Announcement.Audience)
+
+	_, err = svc.Broadcast(context.Background(), dto.BroadcastAnnouncementRequest{
+		Title: "Rapat", Content: "Rapat admin", Roles: []models.UserRole{models.RoleAdmin},
+	}, claims)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	assert.Len(t, repo.created, 2)
+}
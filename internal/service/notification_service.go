@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+	maxDeviceTokenLength     = 4096
+)
+
+type notificationStore interface {
+	ListByUser(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error)
+	MarkRead(ctx context.Context, id, userID string, readAt time.Time) error
+}
+
+type deviceTokenStore interface {
+	Register(ctx context.Context, token *models.DeviceToken) error
+	Delete(ctx context.Context, userID, token string) error
+}
+
+// NotificationService exposes the caller's in-app notifications and push devices.
+type NotificationService struct {
+	store   notificationStore
+	devices deviceTokenStore
+	now     func() time.Time
+}
+
+// NewNotificationService constructs the service.
+func NewNotificationService(store notificationStore, devices deviceTokenStore) *NotificationService {
+	return &NotificationService{store: store, devices: devices, now: time.Now}
+}
+
+// List returns the caller's notifications, newest first.
+func (s *NotificationService) List(ctx context.Context, query dto.NotificationQuery, claims *models.JWTClaims) ([]models.Notification, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+	items, err := s.store.ListByUser(ctx, claims.UserID, query.UnreadOnly, limit)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list notifications")
+	}
+	if items == nil {
+		items = []models.Notification{}
+	}
+	return items, nil
+}
+
+// MarkRead marks one of the caller's notifications as read.
+func (s *NotificationService) MarkRead(ctx context.Context, id string, claims *models.JWTClaims) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	if err := s.store.MarkRead(ctx, id, claims.UserID, s.now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "notification not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to mark notification read")
+	}
+	return nil
+}
+
+// RegisterDevice registers the caller's device token so their notifications are also pushed to it.
+// Registering a token again refreshes it.
+func (s *NotificationService) RegisterDevice(ctx context.Context, req dto.RegisterDeviceRequest, claims *models.JWTClaims) (*models.DeviceToken, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "token is required and must be at most 4096 characters")
+	}
+	platform := models.DevicePlatform(strings.ToUpper(strings.TrimSpace(req.Platform)))
+	switch platform {
+	case models.DevicePlatformAndroid, models.DevicePlatformIOS, models.DevicePlatformWeb:
+	default:
+		return nil, appErrors.Clone(appErrors.ErrValidation, "platform must be ANDROID, IOS or WEB")
+	}
+	device := &models.DeviceToken{UserID: claims.UserID, Token: token, Platform: platform}
+	if err := s.devices.Register(ctx, device); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to register device")
+	}
+	return device, nil
+}
+
+// UnregisterDevice stops pushing to one of the caller's devices, e.g. on sign-out.
+func (s *NotificationService) UnregisterDevice(ctx context.Context, token string, claims *models.JWTClaims) error {
+	if claims == nil {
+		return appErrors.ErrUnauthorized
+	}
+	if err := s.devices.Delete(ctx, claims.UserID, strings.TrimSpace(token)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "device not registered")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to unregister device")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// panicWebhookInflight bounds concurrent deliveries so a panic storm cannot pile up goroutines.
+const panicWebhookInflight = 8
+
+// PanicWebhook posts recovered panics as JSON to an alerting endpoint without delaying the failed
+// request. Alerts beyond the in-flight limit are dropped and logged.
+type PanicWebhook struct {
+	url      string
+	client   *http.Client
+	timeout  time.Duration
+	logger   *zap.Logger
+	inflight chan struct{}
+}
+
+// NewPanicWebhook constructs a PanicWebhook posting to url.
+func NewPanicWebhook(url string, timeout time.Duration, logger *zap.Logger) *PanicWebhook {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &PanicWebhook{
+		url:      url,
+		client:   &http.Client{Timeout: timeout},
+		timeout:  timeout,
+		logger:   logger,
+		inflight: make(chan struct{}, panicWebhookInflight),
+	}
+}
+
+// AlertPanic delivers event in the background.
+func (w *PanicWebhook) AlertPanic(_ context.Context, event models.PanicEvent) {
+	select {
+	case w.inflight <- struct{}{}:
+	default:
+		w.logger.Warn("panic alert dropped: too many deliveries in flight", zap.String("request_id", event.RequestID))
+		return
+	}
+	go func() {
+		defer func() { <-w.inflight }()
+		// The request context ends with the response, so deliveries get their own deadline.
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		defer cancel()
+		if err := w.Send(ctx, event); err != nil {
+			w.logger.Warn("panic alert delivery failed", zap.String("request_id", event.RequestID), zap.Error(err))
+		}
+	}()
+}
+
+// Send posts event synchronously.
+func (w *PanicWebhook) Send(ctx context.Context, event models.PanicEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode panic alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build panic alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post panic alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("post panic alert: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+func TestPanicWebhookDeliversEvent(t *testing.T) {
+	received := make(chan models.PanicEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.PanicEvent
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	hook := NewPanicWebhook(server.URL, time.Second, nil)
+	hook.AlertPanic(context.Background(), models.PanicEvent{RequestID: "req-1", Method: http.MethodGet, Path: "/boom", Value: "boom"})
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "req-1", event.RequestID)
+		assert.Equal(t, "/boom", event.Path)
+	case <-time.After(2 * time.Second):
+		t.Fatal("panic alert was not delivered")
+	}
+}
+
+func TestPanicWebhookReportsRejectedAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewPanicWebhook(server.URL, time.Second, nil).Send(context.Background(), models.PanicEvent{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 502")
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// PermissionService answers which routes a token may use, from the policies the RBAC guards
+// enforce.
+type PermissionService struct {
+	policies  []models.RoutePolicy
+	apiPrefix string
+}
+
+// NewPermissionService builds the service over the policies of the mounted routes. Only routes
+// under apiPrefix that take a user token are reported.
+func NewPermissionService(policies []models.RoutePolicy, apiPrefix string) *PermissionService {
+	return &PermissionService{policies: policies, apiPrefix: "/" + strings.Trim(apiPrefix, "/")}
+}
+
+// For lists the routes claims may use. Handlers may narrow access further, e.g. to a teacher's own
+// classes; SELF-scoped routes only admit the caller's own user ID as :id.
+func (s *PermissionService) For(claims *models.JWTClaims) (*dto.PermissionsResponse, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	resp := &dto.PermissionsResponse{UserID: claims.UserID, Role: claims.Role, Permissions: []dto.Permission{}}
+	for _, policy := range s.policies {
+		if !policy.Authenticated || !strings.HasPrefix(policy.Path, s.apiPrefix+"/") {
+			continue
+		}
+		scope, ok := policy.Access(claims.Role)
+		if !ok {
+			continue
+		}
+		resp.Permissions = append(resp.Permissions, dto.Permission{
+			Method: policy.Method,
+			Path:   strings.TrimPrefix(policy.Path, s.apiPrefix),
+			Scope:  scope,
+		})
+	}
+	return resp, nil
+}
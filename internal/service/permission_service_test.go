@@ -0,0 +1,42 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+func TestPermissionServiceFor(t *testing.T) {
+	admins := []string{"ADMIN", "SUPERADMIN"}
+	svc := NewPermissionService([]models.RoutePolicy{
+		{Method: http.MethodPost, Path: "/api/v1/auth/login", Guards: [][]string{}},
+		{Method: http.MethodGet, Path: "/api/v1/guardians/:id/contact", Authenticated: true, Guards: [][]string{{"SELF", "ADMIN", "SUPERADMIN"}}},
+		{Method: http.MethodGet, Path: "/api/v1/notifications", Authenticated: true, Guards: [][]string{}},
+		{Method: http.MethodGet, Path: "/api/v1/reports/:id", Authenticated: true, Guards: [][]string{{"TEACHER", "ADMIN", "SUPERADMIN"}, admins}},
+		{Method: http.MethodPost, Path: "/internal/backups", Authenticated: true, Guards: [][]string{{"SUPERADMIN"}}},
+	}, "api/v1/")
+
+	resp, err := svc.For(&models.JWTClaims{UserID: "guardian-1", Role: models.RoleGuardian})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.Permission{
+		{Method: http.MethodGet, Path: "/guardians/:id/contact", Scope: models.PermissionScopeSelf},
+		{Method: http.MethodGet, Path: "/notifications", Scope: models.PermissionScopeAll},
+	}, resp.Permissions)
+
+	resp, err = svc.For(&models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.Permission{
+		{Method: http.MethodGet, Path: "/guardians/:id/contact", Scope: models.PermissionScopeAll},
+		{Method: http.MethodGet, Path: "/notifications", Scope: models.PermissionScopeAll},
+		{Method: http.MethodGet, Path: "/reports/:id", Scope: models.PermissionScopeAll},
+	}, resp.Permissions)
+
+	_, err = svc.For(nil)
+	assert.Equal(t, appErrors.ErrUnauthorized.Code, appErrors.FromError(err).Code)
+}
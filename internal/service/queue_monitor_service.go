@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+)
+
+// QueuePool is a job queue whose worker pool can be inspected and resized.
+type QueuePool interface {
+	Stats() jobs.Stats
+	Resize(workers int)
+}
+
+// QueueScaling bounds the worker pool of a queue. A zero value keeps the pool at its current size.
+type QueueScaling struct {
+	MinWorkers int
+	MaxWorkers int
+}
+
+// QueueMonitorConfig controls automatic resizing of the registered queues.
+type QueueMonitorConfig struct {
+	// Autoscale resizes pools with a range between their bounds every Interval.
+	Autoscale bool
+	Interval  time.Duration
+	// JobsPerWorker is the backlog one extra worker is expected to absorb; it defaults to 4.
+	JobsPerWorker int
+}
+
+type monitoredQueue struct {
+	pool    QueuePool
+	scaling QueueScaling
+}
+
+// QueueMonitorService reports the backlog of the background job queues and, when enabled, grows
+// their worker pools while jobs pile up and shrinks them again once the backlog clears.
+type QueueMonitorService struct {
+	cfg    QueueMonitorConfig
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	queues []monitoredQueue
+}
+
+// NewQueueMonitorService constructs the service.
+func NewQueueMonitorService(cfg QueueMonitorConfig, logger *zap.Logger) *QueueMonitorService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.JobsPerWorker <= 0 {
+		cfg.JobsPerWorker = 4
+	}
+	return &QueueMonitorService{cfg: cfg, logger: logger}
+}
+
+// Register adds a queue. Bounds that are unset or inverted default to the pool's current size.
+func (s *QueueMonitorService) Register(pool QueuePool, scaling QueueScaling) {
+	workers := pool.Stats().Workers
+	if scaling.MinWorkers <= 0 {
+		scaling.MinWorkers = workers
+	}
+	if scaling.MaxWorkers < scaling.MinWorkers {
+		scaling.MaxWorkers = scaling.MinWorkers
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues = append(s.queues, monitoredQueue{pool: pool, scaling: scaling})
+}
+
+// List returns the status of every registered queue in registration order.
+func (s *QueueMonitorService) List() []dto.QueueStatus {
+	s.mu.Lock()
+	queues := append([]monitoredQueue(nil), s.queues...)
+	s.mu.Unlock()
+
+	statuses := make([]dto.QueueStatus, 0, len(queues))
+	for _, queue := range queues {
+		stats := queue.pool.Stats()
+		statuses = append(statuses, dto.QueueStatus{
+			Name:                stats.Name,
+			Workers:             stats.Workers,
+			MinWorkers:          queue.scaling.MinWorkers,
+			MaxWorkers:          queue.scaling.MaxWorkers,
+			Autoscaled:          s.autoscaled(queue),
+			RecommendedWorkers:  s.recommend(stats),
+			Depth:               stats.Depth,
+			Active:              stats.Active,
+			Processed:           stats.Processed,
+			Failed:              stats.Failed,
+			RatePerMinute:       math.Round(stats.Rate*100) / 100,
+			AvgDurationMs:       stats.AvgDuration.Milliseconds(),
+			OldestQueuedSeconds: math.Round(stats.OldestQueued.Seconds()*10) / 10,
+		})
+	}
+	return statuses
+}
+
+// Start resizes the pools every interval until ctx is cancelled. It does nothing unless autoscaling
+// is enabled.
+func (s *QueueMonitorService) Start(ctx context.Context) {
+	if !s.cfg.Autoscale {
+		return
+	}
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Scale()
+			}
+		}
+	}()
+}
+
+// Scale resizes every pool that has a range towards the recommended size. Pools grow to the
+// recommendation at once but shrink by one worker per call, so a short lull does not drop workers a
+// returning backlog needs.
+func (s *QueueMonitorService) Scale() {
+	s.mu.Lock()
+	queues := append([]monitoredQueue(nil), s.queues...)
+	s.mu.Unlock()
+
+	for _, queue := range queues {
+		if !s.autoscaled(queue) {
+			continue
+		}
+		stats := queue.pool.Stats()
+		target := s.recommend(stats)
+		if target < queue.scaling.MinWorkers {
+			target = queue.scaling.MinWorkers
+		}
+		if target > queue.scaling.MaxWorkers {
+			target = queue.scaling.MaxWorkers
+		}
+		if target < stats.Workers {
+			target = stats.Workers - 1
+		}
+		if target == stats.Workers {
+			continue
+		}
+		queue.pool.Resize(target)
+		s.logger.Info("queue resized",
+			zap.String("queue", stats.Name),
+			zap.Int("from", stats.Workers),
+			zap.Int("to", target),
+			zap.Int("depth", stats.Depth))
+	}
+}
+
+func (s *QueueMonitorService) autoscaled(queue monitoredQueue) bool {
+	return s.cfg.Autoscale && queue.scaling.MaxWorkers > queue.scaling.MinWorkers
+}
+
+// recommend keeps a worker for every running job and adds one per JobsPerWorker waiting jobs.
+func (s *QueueMonitorService) recommend(stats jobs.Stats) int {
+	workers := stats.Active + (stats.Depth+s.cfg.JobsPerWorker-1)/s.cfg.JobsPerWorker
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
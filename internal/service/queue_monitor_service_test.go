@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/jobs"
+)
+
+type queuePoolStub struct {
+	stats   jobs.Stats
+	resized []int
+}
+
+func (q *queuePoolStub) Stats() jobs.Stats { return q.stats }
+
+func (q *queuePoolStub) Resize(workers int) {
+	q.resized = append(q.resized, workers)
+	q.stats.Workers = workers
+}
+
+func TestQueueMonitorServiceList(t *testing.T) {
+	svc := NewQueueMonitorService(QueueMonitorConfig{Autoscale: true}, nil)
+	reports := &queuePoolStub{stats: jobs.Stats{
+		Name: "reports", Workers: 2, Depth: 9, Active: 2, Processed: 40, Failed: 1,
+		Rate: 1.666, AvgDuration: 1500 * time.Millisecond, OldestQueued: 92340 * time.Millisecond,
+	}}
+	backups := &queuePoolStub{stats: jobs.Stats{Name: "backups", Workers: 1}}
+	svc.Register(reports, QueueScaling{MinWorkers: 1, MaxWorkers: 4})
+	svc.Register(backups, QueueScaling{})
+
+	statuses := svc.List()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "reports", statuses[0].Name)
+	assert.True(t, statuses[0].Autoscaled)
+	assert.Equal(t, 5, statuses[0].RecommendedWorkers)
+	assert.Equal(t, 1.67, statuses[0].RatePerMinute)
+	assert.Equal(t, int64(1500), statuses[0].AvgDurationMs)
+	assert.Equal(t, 92.3, statuses[0].OldestQueuedSeconds)
+	assert.Equal(t, "backups", statuses[1].Name)
+	assert.False(t, statuses[1].Autoscaled)
+	assert.Equal(t, 1, statuses[1].MinWorkers)
+	assert.Equal(t, 1, statuses[1].MaxWorkers)
+	assert.Equal(t, 1, statuses[1].RecommendedWorkers)
+}
+
+func TestQueueMonitorServiceScale(t *testing.T) {
+	svc := NewQueueMonitorService(QueueMonitorConfig{Autoscale: true, JobsPerWorker: 4}, nil)
+	reports := &queuePoolStub{stats: jobs.Stats{Name: "reports", Workers: 2, Depth: 9, Active: 2}}
+	fixed := &queuePoolStub{stats: jobs.Stats{Name: "backups", Workers: 1, Depth: 20, Active: 1}}
+	svc.Register(reports, QueueScaling{MinWorkers: 1, MaxWorkers: 4})
+	svc.Register(fixed, QueueScaling{})
+
+	// The backlog calls for five workers; the pool grows to its maximum at once.
+	svc.Scale()
+	assert.Equal(t, []int{4}, reports.resized)
+	assert.Empty(t, fixed.resized)
+
+	// Once idle it shrinks one worker per run down to its minimum.
+	reports.stats.Depth, reports.stats.Active = 0, 0
+	svc.Scale()
+	svc.Scale()
+	svc.Scale()
+	svc.Scale()
+	assert.Equal(t, []int{4, 3, 2, 1}, reports.resized)
+}
+
+func TestQueueMonitorServiceScaleDisabled(t *testing.T) {
+	svc := NewQueueMonitorService(QueueMonitorConfig{}, nil)
+	reports := &queuePoolStub{stats: jobs.Stats{Name: "reports", Workers: 1, Depth: 12}}
+	svc.Register(reports, QueueScaling{MinWorkers: 1, MaxWorkers: 4})
+
+	svc.Scale()
+	assert.Empty(t, reports.resized)
+	assert.False(t, svc.List()[0].Autoscaled)
+	assert.Equal(t, 3, svc.List()[0].RecommendedWorkers)
+}
@@ -14,20 +14,21 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/jobs"
 )
 
-type classAccessChecker interface {
-	HasClassAccess(ctx context.Context, teacherID, classID, termID string) (bool, error)
-}
-
 type reportJobStore interface {
 	Create(ctx context.Context, job *models.ReportJob) error
 	GetByID(ctx context.Context, id string) (*models.ReportJob, error)
 	Update(ctx context.Context, id string, params repository.UpdateReportJobParams) error
-	ListQueued(ctx context.Context, limit int) ([]models.ReportJob, error)
+	ClaimQueued(ctx context.Context, workerID string, staleBefore time.Time, limit int) ([]models.ReportJob, error)
+	Claim(ctx context.Context, id, workerID string, staleBefore time.Time) (*models.ReportJob, error)
+	Heartbeat(ctx context.Context, id, workerID string) error
+	QueuePosition(ctx context.Context, job *models.ReportJob) (int, error)
+	FindReusable(ctx context.Context, fingerprint, createdBy string, finishedSince time.Time) (*models.ReportJob, error)
 	ListFinishedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.ReportJob, error)
 }
 
@@ -42,7 +43,7 @@ type exportGenerator interface {
 // ReportService orchestrates report job lifecycle management.
 type ReportService struct {
 	repo        reportJobStore
-	assignments classAccessChecker
+	assignments ports.ClassAccessChecker
 	queue       jobDispatcher
 	exporter    *ExportService
 	logger      *zap.Logger
@@ -54,6 +55,51 @@ type ReportServiceConfig struct {
 	ResultTTL       time.Duration
 	CleanupInterval time.Duration
 	MaxRetries      int
+	Claims          ReportClaimConfig
+	// DedupWindow is how long a finished job is handed out again for identical requests; zero
+	// disables deduplication. It never exceeds ResultTTL so reused links are still valid.
+	DedupWindow time.Duration
+	// Storage, when set, rejects new jobs while the report directory is full or not writable, instead
+	// of letting them fail once rendered.
+	Storage storageGuard
+}
+
+// storageGuard reports whether a storage directory can take new files.
+type storageGuard interface {
+	Ensure(name string) error
+}
+
+// ReportClaimConfig identifies this replica's report worker. Jobs are owned by one worker at a time;
+// the owner refreshes its claim every HeartbeatInterval and other replicas may take the job over
+// once the claim is older than Timeout.
+type ReportClaimConfig struct {
+	WorkerID          string
+	HeartbeatInterval time.Duration
+	Timeout           time.Duration
+}
+
+const reportRecoveryBatch = 50
+
+func (c ReportClaimConfig) withDefaults() ReportClaimConfig {
+	if c.WorkerID == "" {
+		c.WorkerID = defaultReportWorkerID()
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = 30 * time.Second
+	}
+	if c.Timeout <= c.HeartbeatInterval {
+		c.Timeout = 4 * c.HeartbeatInterval
+	}
+	return c
+}
+
+// defaultReportWorkerID is unique per process so replicas never share an identity by accident.
+func defaultReportWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "report-worker"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
 }
 
 // ReportDownload aggregates resolved download data.
@@ -65,7 +111,7 @@ type ReportDownload struct {
 }
 
 // NewReportService constructs the report service.
-func NewReportService(repo reportJobStore, assignments classAccessChecker, queue jobDispatcher, exporter *ExportService, logger *zap.Logger, cfg ReportServiceConfig) *ReportService {
+func NewReportService(repo reportJobStore, assignments ports.ClassAccessChecker, queue jobDispatcher, exporter *ExportService, logger *zap.Logger, cfg ReportServiceConfig) *ReportService {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
@@ -75,6 +121,10 @@ func NewReportService(repo reportJobStore, assignments classAccessChecker, queue
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = 3
 	}
+	cfg.Claims = cfg.Claims.withDefaults()
+	if cfg.DedupWindow > cfg.ResultTTL {
+		cfg.DedupWindow = cfg.ResultTTL
+	}
 	return &ReportService{
 		repo:        repo,
 		assignments: assignments,
@@ -90,17 +140,47 @@ func (s *ReportService) CreateJob(ctx context.Context, req dto.ReportRequest, ac
 	if err := s.validateRequest(ctx, req, actorID, role); err != nil {
 		return nil, err
 	}
+	params := models.ReportJobParams{TermID: req.TermID, ClassID: req.ClassID, Format: req.Format}
+	fingerprint := params.Fingerprint(req.Type)
+	if !req.Force {
+		if existing := s.findReusable(ctx, fingerprint, actorID, role); existing != nil {
+			return &dto.ReportJobResponse{
+				ID:           existing.ID,
+				Status:       existing.Status,
+				Progress:     existing.Progress,
+				Priority:     existing.Priority,
+				ResultURL:    existing.ResultURL,
+				Deduplicated: true,
+			}, nil
+		}
+	}
+	if s.cfg.Storage != nil {
+		if err := s.cfg.Storage.Ensure(StorageReports); err != nil {
+			return nil, err
+		}
+	}
+	// The job is claimed up front so recovery on other replicas leaves it to the local queue.
+	workerID := s.cfg.Claims.WorkerID
+	claimedAt := time.Now().UTC()
+	priority := req.Priority
+	if priority == "" {
+		priority = models.ReportPriorityNormal
+	}
 	job := &models.ReportJob{
-		Type:      req.Type,
-		Params:    models.ReportJobParams{TermID: req.TermID, ClassID: req.ClassID, Format: req.Format},
-		Status:    models.ReportStatusQueued,
-		Progress:  0,
-		CreatedBy: actorID,
+		Type:        req.Type,
+		Params:      params,
+		Status:      models.ReportStatusQueued,
+		Progress:    0,
+		CreatedBy:   actorID,
+		Priority:    priority,
+		ClaimedBy:   &workerID,
+		HeartbeatAt: &claimedAt,
+		Fingerprint: fingerprint,
 	}
 	if err := s.repo.Create(ctx, job); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create report job")
 	}
-	if err := s.queue.Enqueue(jobs.Job{ID: job.ID, Type: string(job.Type)}); err != nil {
+	if err := s.queue.Enqueue(reportQueueJob(job)); err != nil {
 		status := models.ReportStatusFailed
 		msg := "failed to enqueue job"
 		now := time.Now().UTC()
@@ -113,7 +193,28 @@ func (s *ReportService) CreateJob(ctx context.Context, req dto.ReportRequest, ac
 		})
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to enqueue report job")
 	}
-	return &dto.ReportJobResponse{ID: job.ID, Status: job.Status, Progress: job.Progress}, nil
+	return &dto.ReportJobResponse{ID: job.ID, Status: job.Status, Progress: job.Progress, Priority: job.Priority}, nil
+}
+
+// findReusable looks for an in-flight or recently finished job for the same request. Teachers only
+// reuse their own jobs because they cannot read anyone else's. Lookup failures fall back to
+// creating a new job.
+func (s *ReportService) findReusable(ctx context.Context, fingerprint, actorID string, role models.UserRole) *models.ReportJob {
+	if s.cfg.DedupWindow <= 0 {
+		return nil
+	}
+	createdBy := ""
+	if role == models.RoleTeacher {
+		createdBy = actorID
+	}
+	job, err := s.repo.FindReusable(ctx, fingerprint, createdBy, time.Now().UTC().Add(-s.cfg.DedupWindow))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Sugar().Warnw("failed to look up reusable report job", "error", err)
+		}
+		return nil
+	}
+	return job
 }
 
 // GetStatus exposes job metadata to clients, enforcing ownership for teachers.
@@ -128,10 +229,23 @@ func (s *ReportService) GetStatus(ctx context.Context, id string, actorID string
 	if role == models.RoleTeacher && job.CreatedBy != actorID {
 		return nil, appErrors.ErrForbidden
 	}
+	// The status carries the download link, so research jobs stay with super admins.
+	if job.Type == models.ReportTypeResearch && role != models.RoleSuperAdmin {
+		return nil, appErrors.ErrForbidden
+	}
 	resp := &dto.ReportStatusResponse{
 		ID:       job.ID,
 		Status:   job.Status,
 		Progress: job.Progress,
+		Priority: job.Priority,
+	}
+	if job.Status == models.ReportStatusQueued {
+		position, err := s.repo.QueuePosition(ctx, job)
+		if err != nil {
+			s.logger.Sugar().Warnw("failed to compute report queue position", "job_id", job.ID, "error", err)
+		} else {
+			resp.QueuePosition = &position
+		}
 	}
 	if job.ResultURL != nil {
 		resp.ResultURL = job.ResultURL
@@ -174,20 +288,46 @@ func (s *ReportService) ResolveDownload(ctx context.Context, token string) (*Rep
 	}, nil
 }
 
-// RecoverPendingJobs replays queued jobs (e.g. after process restart).
+// RecoverPendingJobs claims and replays jobs without a live owner (e.g. after a restart or when
+// another replica died mid-job). It pages through the whole backlog; jobs claimed by other
+// replicas are left alone.
 func (s *ReportService) RecoverPendingJobs(ctx context.Context) {
-	pending, err := s.repo.ListQueued(ctx, 50)
-	if err != nil {
-		s.logger.Sugar().Warnw("failed to recover queued report jobs", "error", err)
-		return
-	}
-	for _, job := range pending {
-		if err := s.queue.Enqueue(jobs.Job{ID: job.ID, Type: string(job.Type)}); err != nil {
-			s.logger.Sugar().Warnw("failed to requeue pending job", "job_id", job.ID, "error", err)
+	for {
+		staleBefore := time.Now().UTC().Add(-s.cfg.Claims.Timeout)
+		pending, err := s.repo.ClaimQueued(ctx, s.cfg.Claims.WorkerID, staleBefore, reportRecoveryBatch)
+		if err != nil {
+			s.logger.Sugar().Warnw("failed to recover queued report jobs", "error", err)
+			return
+		}
+		for _, job := range pending {
+			if err := s.queue.Enqueue(reportQueueJob(&job)); err != nil {
+				s.logger.Sugar().Warnw("failed to requeue pending job", "job_id", job.ID, "error", err)
+			}
+		}
+		if len(pending) < reportRecoveryBatch {
+			return
 		}
 	}
 }
 
+// StartRecovery recovers pending jobs in the background now and again every claim timeout, so
+// jobs abandoned by a crashed replica are picked up by the survivors.
+func (s *ReportService) StartRecovery(ctx context.Context) {
+	go func() {
+		s.RecoverPendingJobs(ctx)
+		ticker := time.NewTicker(s.cfg.Claims.Timeout)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RecoverPendingJobs(ctx)
+			}
+		}
+	}()
+}
+
 // StartCleanup boots a goroutine that purges expired exports periodically.
 func (s *ReportService) StartCleanup(ctx context.Context) {
 	if s.cfg.CleanupInterval <= 0 {
@@ -247,12 +387,24 @@ func (s *ReportService) validateRequest(ctx context.Context, req dto.ReportReque
 	if req.TermID == "" {
 		return appErrors.Clone(appErrors.ErrValidation, "termId is required")
 	}
+	if req.Type == models.ReportTypeResearch {
+		return s.validateResearchRequest(req, role)
+	}
+	if isMinistryReportType(req.Type) {
+		return s.validateMinistryRequest(req, role)
+	}
 	if !isValidReportType(req.Type) {
 		return appErrors.Clone(appErrors.ErrValidation, "unsupported report type")
 	}
 	if !isValidFormat(req.Format) {
 		return appErrors.Clone(appErrors.ErrValidation, "unsupported report format")
 	}
+	if req.Priority != "" && !req.Priority.Valid() {
+		return appErrors.Clone(appErrors.ErrValidation, "priority must be high, normal or low")
+	}
+	if req.Priority == models.ReportPriorityHigh && role != models.RoleAdmin && role != models.RoleSuperAdmin {
+		return appErrors.Clone(appErrors.ErrForbidden, "only administrators can request high priority reports")
+	}
 	if role == models.RoleTeacher {
 		if req.ClassID == nil || *req.ClassID == "" {
 			return appErrors.Clone(appErrors.ErrValidation, "classId is required for teacher reports")
@@ -271,6 +423,39 @@ func (s *ReportService) validateRequest(ctx context.Context, req dto.ReportReque
 	return nil
 }
 
+// validateResearchRequest gates research exports: they leave the school, so only super admins may
+// request them, and they are always a zip of CSV files.
+func (s *ReportService) validateResearchRequest(req dto.ReportRequest, role models.UserRole) error {
+	if role != models.RoleSuperAdmin {
+		return appErrors.Clone(appErrors.ErrForbidden, "only super admins can request research exports")
+	}
+	if req.Format != models.ReportFormatZIP {
+		return appErrors.Clone(appErrors.ErrValidation, "research exports must use the zip format")
+	}
+	if !s.exporter.ResearchEnabled() {
+		return appErrors.Clone(appErrors.ErrPreconditionFailed, "research exports are not configured")
+	}
+	return nil
+}
+
+// validateMinistryRequest gates ministry exports: they carry identified data of every student in the
+// term, so only administrators may request them, as CSV or XLSX.
+func (s *ReportService) validateMinistryRequest(req dto.ReportRequest, role models.UserRole) error {
+	if role != models.RoleAdmin && role != models.RoleSuperAdmin {
+		return appErrors.Clone(appErrors.ErrForbidden, "only administrators can request ministry exports")
+	}
+	if req.Format != models.ReportFormatCSV && req.Format != models.ReportFormatXLSX {
+		return appErrors.Clone(appErrors.ErrValidation, "ministry exports must use the csv or xlsx format")
+	}
+	if req.Priority != "" && !req.Priority.Valid() {
+		return appErrors.Clone(appErrors.ErrValidation, "priority must be high, normal or low")
+	}
+	if !s.exporter.MinistryEnabled() {
+		return appErrors.Clone(appErrors.ErrPreconditionFailed, "ministry exports are not configured")
+	}
+	return nil
+}
+
 func isValidReportType(t models.ReportType) bool {
 	switch t {
 	case models.ReportTypeAttendance, models.ReportTypeGrades, models.ReportTypeBehavior, models.ReportTypeSummary:
@@ -284,6 +469,11 @@ func isValidFormat(f models.ReportFormat) bool {
 	return f == models.ReportFormatCSV || f == models.ReportFormatPDF
 }
 
+// reportQueueJob builds the queue entry for a report job, carrying its priority rank.
+func reportQueueJob(job *models.ReportJob) jobs.Job {
+	return jobs.Job{ID: job.ID, Type: string(job.Type), Priority: job.Priority.Rank()}
+}
+
 func extractToken(url string) string {
 	if url == "" {
 		return ""
@@ -298,39 +488,70 @@ type ReportWorker struct {
 	exporter   exportGenerator
 	logger     *zap.Logger
 	maxRetries int
+	claims     ReportClaimConfig
+}
+
+// ReportWorkerOption customises ReportWorker.
+type ReportWorkerOption func(*ReportWorker)
+
+// WithReportClaims sets the identity and heartbeat timing the worker claims jobs with. It must match
+// the ReportService configuration of the same replica.
+func WithReportClaims(cfg ReportClaimConfig) ReportWorkerOption {
+	return func(w *ReportWorker) {
+		w.claims = cfg.withDefaults()
+	}
 }
 
 // NewReportWorker constructs a worker.
-func NewReportWorker(repo reportJobStore, exporter exportGenerator, maxRetries int, logger *zap.Logger) *ReportWorker {
+func NewReportWorker(repo reportJobStore, exporter exportGenerator, maxRetries int, logger *zap.Logger, opts ...ReportWorkerOption) *ReportWorker {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 	if maxRetries <= 0 {
 		maxRetries = 3
 	}
-	return &ReportWorker{
+	worker := &ReportWorker{
 		repo:       repo,
 		exporter:   exporter,
 		logger:     logger,
 		maxRetries: maxRetries,
+		claims:     ReportClaimConfig{}.withDefaults(),
+	}
+	for _, opt := range opts {
+		opt(worker)
 	}
+	return worker
 }
 
-// Handle processes a queue job.
+// Handle processes a queue job. The job is claimed first; when another replica owns it the job is
+// skipped rather than generated twice.
 func (w *ReportWorker) Handle(ctx context.Context, job jobs.Job) error {
-	record, err := w.repo.GetByID(ctx, job.ID)
+	staleBefore := time.Now().UTC().Add(-w.claims.Timeout)
+	record, err := w.repo.Claim(ctx, job.ID, w.claims.WorkerID, staleBefore)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			w.logger.Sugar().Debugw("report job claimed elsewhere; skipping", "job_id", job.ID)
+			return nil
+		}
 		return err
 	}
-	processing := models.ReportStatusProcessing
 	progress := 10
-	if err := w.repo.Update(ctx, job.ID, repository.UpdateReportJobParams{
-		Status:   &processing,
-		Progress: &progress,
-	}); err != nil {
+	if err := w.repo.Update(ctx, job.ID, repository.UpdateReportJobParams{Progress: &progress}); err != nil {
 		return err
 	}
-	result, err := w.exporter.Generate(ctx, record)
+
+	genCtx, cancel := context.WithCancel(ctx)
+	lost := make(chan struct{})
+	stopHeartbeat := w.keepClaim(genCtx, job.ID, cancel, lost)
+	result, err := w.exporter.Generate(genCtx, record)
+	stopHeartbeat()
+	cancel()
+	select {
+	case <-lost:
+		w.logger.Sugar().Warnw("report job claim lost during generation", "job_id", job.ID)
+		return nil
+	default:
+	}
 	if err != nil {
 		msg := err.Error()
 		if job.Attempt >= w.maxRetries {
@@ -375,3 +596,37 @@ func (w *ReportWorker) Handle(ctx context.Context, job jobs.Job) error {
 	}
 	return nil
 }
+
+// keepClaim refreshes the worker's claim until the returned stop function is called. When the claim
+// has been taken over it closes lost and cancels the generation.
+func (w *ReportWorker) keepClaim(ctx context.Context, jobID string, cancel context.CancelFunc, lost chan struct{}) func() {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(w.claims.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := w.repo.Heartbeat(ctx, jobID, w.claims.WorkerID)
+				if errors.Is(err, sql.ErrNoRows) {
+					close(lost)
+					cancel()
+					return
+				}
+				if err != nil {
+					w.logger.Sugar().Warnw("report job heartbeat failed", "job_id", jobID, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"path/filepath"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
 	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 	"github.com/noah-isme/sma-adp-api/pkg/jobs"
 	"github.com/google/uuid"
 )
@@ -65,14 +67,75 @@ func (r *reportRepoStub) Update(ctx context.Context, id string, params repositor
 	return nil
 }
 
-func (r *reportRepoStub) ListQueued(ctx context.Context, limit int) ([]models.ReportJob, error) {
-	var queued []models.ReportJob
+func (r *reportRepoStub) ClaimQueued(ctx context.Context, workerID string, staleBefore time.Time, limit int) ([]models.ReportJob, error) {
+	var claimed []models.ReportJob
 	for _, job := range r.jobs {
-		if job.Status == models.ReportStatusQueued {
-			queued = append(queued, *job)
+		if len(claimed) == limit {
+			break
 		}
+		if job.Status != models.ReportStatusQueued && job.Status != models.ReportStatusProcessing {
+			continue
+		}
+		if job.ClaimedBy != nil && job.HeartbeatAt != nil && !job.HeartbeatAt.Before(staleBefore) {
+			continue
+		}
+		now := time.Now().UTC()
+		job.Status = models.ReportStatusQueued
+		job.ClaimedBy = &workerID
+		job.HeartbeatAt = &now
+		claimed = append(claimed, *job)
 	}
-	return queued, nil
+	return claimed, nil
+}
+
+func (r *reportRepoStub) Claim(ctx context.Context, id, workerID string, staleBefore time.Time) (*models.ReportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok || job.Status != models.ReportStatusQueued {
+		return nil, sql.ErrNoRows
+	}
+	if job.ClaimedBy != nil && *job.ClaimedBy != workerID && job.HeartbeatAt != nil && !job.HeartbeatAt.Before(staleBefore) {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now().UTC()
+	job.Status = models.ReportStatusProcessing
+	job.ClaimedBy = &workerID
+	job.HeartbeatAt = &now
+	return job, nil
+}
+
+func (r *reportRepoStub) Heartbeat(ctx context.Context, id, workerID string) error {
+	return nil
+}
+
+func (r *reportRepoStub) FindReusable(ctx context.Context, fingerprint, createdBy string, finishedSince time.Time) (*models.ReportJob, error) {
+	var found *models.ReportJob
+	for _, job := range r.jobs {
+		if job.Fingerprint != fingerprint || (createdBy != "" && job.CreatedBy != createdBy) {
+			continue
+		}
+		live := job.Status == models.ReportStatusQueued || job.Status == models.ReportStatusProcessing
+		recent := job.Status == models.ReportStatusFinished && job.FinishedAt != nil && !job.FinishedAt.Before(finishedSince)
+		if (live || recent) && (found == nil || job.CreatedAt.After(found.CreatedAt)) {
+			found = job
+		}
+	}
+	if found == nil {
+		return nil, sql.ErrNoRows
+	}
+	return found, nil
+}
+
+func (r *reportRepoStub) QueuePosition(ctx context.Context, job *models.ReportJob) (int, error) {
+	position := 1
+	for _, other := range r.jobs {
+		if other.ID == job.ID || other.Status != models.ReportStatusQueued {
+			continue
+		}
+		if other.Priority.Rank() > job.Priority.Rank() || (other.Priority.Rank() == job.Priority.Rank() && other.CreatedAt.Before(job.CreatedAt)) {
+			position++
+		}
+	}
+	return position, nil
 }
 
 func (r *reportRepoStub) ListFinishedBefore(ctx context.Context, cutoff time.Time, limit int) ([]models.ReportJob, error) {
@@ -128,9 +191,118 @@ func TestReportServiceCreateJob(t *testing.T) {
 	require.NotEmpty(t, resp.ID)
 	require.Len(t, queue.jobs, 1)
 	assert.Equal(t, models.ReportStatusQueued, resp.Status)
+	assert.Equal(t, models.ReportPriorityNormal, resp.Priority)
 	assert.Contains(t, repo.jobs, resp.ID)
 }
 
+func TestReportServiceCreateJobDeduplicates(t *testing.T) {
+	svc, repo, queue, _ := newReportServiceForTest(t)
+	svc.cfg.DedupWindow = 10 * time.Minute
+	req := dto.ReportRequest{Type: models.ReportTypeGrades, TermID: "term-1", Format: models.ReportFormatCSV}
+
+	first, err := svc.CreateJob(context.Background(), req, "admin-1", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.False(t, first.Deduplicated)
+
+	// A second admin asking for the same report gets the in-flight job.
+	second, err := svc.CreateJob(context.Background(), req, "admin-2", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.True(t, second.Deduplicated)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Len(t, queue.jobs, 1)
+
+	// Recently finished jobs are reused together with their download link.
+	url := "/api/v1/export/token"
+	finishedAt := time.Now().UTC().Add(-time.Minute)
+	repo.jobs[first.ID].Status = models.ReportStatusFinished
+	repo.jobs[first.ID].ResultURL = &url
+	repo.jobs[first.ID].FinishedAt = &finishedAt
+	reused, err := svc.CreateJob(context.Background(), req, "admin-2", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, reused.ID)
+	assert.Equal(t, &url, reused.ResultURL)
+
+	// Stale results and forced requests queue a new job.
+	stale := time.Now().UTC().Add(-time.Hour)
+	repo.jobs[first.ID].FinishedAt = &stale
+	fresh, err := svc.CreateJob(context.Background(), req, "admin-2", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, fresh.ID)
+	req.Force = true
+	forced, err := svc.CreateJob(context.Background(), req, "admin-2", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.False(t, forced.Deduplicated)
+	assert.NotEqual(t, fresh.ID, forced.ID)
+	assert.Len(t, queue.jobs, 3)
+}
+
+func TestReportServiceCreateJobDeduplicatesOnlyOwnTeacherJobs(t *testing.T) {
+	svc, _, queue, _ := newReportServiceForTest(t)
+	svc.cfg.DedupWindow = 10 * time.Minute
+	classID := "class-1"
+	req := dto.ReportRequest{Type: models.ReportTypeAttendance, TermID: "term-1", ClassID: &classID, Format: models.ReportFormatPDF}
+
+	first, err := svc.CreateJob(context.Background(), req, "teacher-1", models.RoleTeacher)
+	require.NoError(t, err)
+	other, err := svc.CreateJob(context.Background(), req, "teacher-2", models.RoleTeacher)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+	again, err := svc.CreateJob(context.Background(), req, "teacher-1", models.RoleTeacher)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Len(t, queue.jobs, 2)
+}
+
+func TestReportServiceCreateJobPriority(t *testing.T) {
+	svc, _, queue, _ := newReportServiceForTest(t)
+	resp, err := svc.CreateJob(context.Background(), dto.ReportRequest{
+		Type:     models.ReportTypeGrades,
+		TermID:   "term-1",
+		Format:   models.ReportFormatCSV,
+		Priority: models.ReportPriorityHigh,
+	}, "root", models.RoleSuperAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportPriorityHigh, resp.Priority)
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, models.ReportPriorityHigh.Rank(), queue.jobs[0].Priority)
+
+	classID := "class-1"
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{
+		Type:     models.ReportTypeGrades,
+		TermID:   "term-1",
+		ClassID:  &classID,
+		Format:   models.ReportFormatCSV,
+		Priority: models.ReportPriorityHigh,
+	}, "teacher-1", models.RoleTeacher)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{
+		Type:     models.ReportTypeGrades,
+		TermID:   "term-1",
+		Format:   models.ReportFormatCSV,
+		Priority: "urgent",
+	}, "admin", models.RoleAdmin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestReportServiceGetStatusQueuePosition(t *testing.T) {
+	svc, repo, _, _ := newReportServiceForTest(t)
+	base := time.Now().UTC()
+	repo.jobs["early-low"] = &models.ReportJob{ID: "early-low", Status: models.ReportStatusQueued, Priority: models.ReportPriorityLow, CreatedAt: base.Add(-time.Hour)}
+	repo.jobs["early-normal"] = &models.ReportJob{ID: "early-normal", Status: models.ReportStatusQueued, Priority: models.ReportPriorityNormal, CreatedAt: base.Add(-time.Minute)}
+	repo.jobs["late-high"] = &models.ReportJob{ID: "late-high", Status: models.ReportStatusQueued, Priority: models.ReportPriorityHigh, CreatedAt: base, CreatedBy: "admin"}
+	repo.jobs["running"] = &models.ReportJob{ID: "running", Status: models.ReportStatusProcessing, Priority: models.ReportPriorityHigh, CreatedAt: base.Add(-2 * time.Hour), CreatedBy: "admin"}
+
+	resp, err := svc.GetStatus(context.Background(), "late-high", "admin", models.RoleAdmin)
+	require.NoError(t, err)
+	require.NotNil(t, resp.QueuePosition)
+	assert.Equal(t, 1, *resp.QueuePosition)
+
+	resp, err = svc.GetStatus(context.Background(), "running", "admin", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Nil(t, resp.QueuePosition)
+}
+
 func TestReportServiceCreateJobTeacherValidation(t *testing.T) {
 	svc, _, _, _ := newReportServiceForTest(t)
 	_, err := svc.CreateJob(context.Background(), dto.ReportRequest{
@@ -233,3 +405,107 @@ func TestReportWorkerHandleFailureRetries(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, models.ReportStatusFailed, repo.jobs["job-1"].Status)
 }
+
+func TestReportWorkerSkipsJobClaimedElsewhere(t *testing.T) {
+	owner := "worker-a"
+	heartbeat := time.Now().UTC()
+	repo := &reportRepoStub{
+		jobs: map[string]*models.ReportJob{
+			"job-1": {
+				ID:          "job-1",
+				Type:        models.ReportTypeGrades,
+				Params:      models.ReportJobParams{TermID: "term-1", Format: models.ReportFormatCSV},
+				Status:      models.ReportStatusQueued,
+				CreatedBy:   "admin",
+				ClaimedBy:   &owner,
+				HeartbeatAt: &heartbeat,
+			},
+		},
+	}
+	exporter := exportStub{err: errors.New("must not run")}
+	worker := NewReportWorker(repo, exporter, 3, zap.NewNop(), WithReportClaims(ReportClaimConfig{WorkerID: "worker-b"}))
+
+	err := worker.Handle(context.Background(), jobs.Job{ID: "job-1"})
+	require.NoError(t, err)
+	require.Equal(t, models.ReportStatusQueued, repo.jobs["job-1"].Status)
+	require.Equal(t, "worker-a", *repo.jobs["job-1"].ClaimedBy)
+}
+
+func TestReportServiceRecoverPendingJobsPagesAndSkipsLiveClaims(t *testing.T) {
+	svc, repo, queue, _ := newReportServiceForTest(t)
+	stale := time.Now().UTC().Add(-time.Hour)
+	fresh := time.Now().UTC()
+	other := "worker-other"
+	for i := 0; i < reportRecoveryBatch+5; i++ {
+		id := uuid.NewString()
+		repo.jobs[id] = &models.ReportJob{ID: id, Type: models.ReportTypeGrades, Status: models.ReportStatusProcessing, ClaimedBy: &other, HeartbeatAt: &stale}
+	}
+	repo.jobs["live"] = &models.ReportJob{ID: "live", Type: models.ReportTypeGrades, Status: models.ReportStatusQueued, ClaimedBy: &other, HeartbeatAt: &fresh}
+
+	svc.RecoverPendingJobs(context.Background())
+
+	require.Len(t, queue.jobs, reportRecoveryBatch+5)
+	for _, job := range queue.jobs {
+		require.NotEqual(t, "live", job.ID)
+		require.Equal(t, models.ReportStatusQueued, repo.jobs[job.ID].Status)
+	}
+}
+
+func TestReportServiceCreateJobRejectsWhenStorageIsLow(t *testing.T) {
+	svc, repo, queue, _ := newReportServiceForTest(t)
+	svc.cfg.Storage = newStorageHealthForTest(10<<20, nil)
+
+	_, err := svc.CreateJob(context.Background(), dto.ReportRequest{Type: models.ReportTypeGrades, TermID: "term-1", Format: models.ReportFormatCSV}, "admin", models.RoleAdmin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrInsufficientStorage.Code, appErrors.FromError(err).Code)
+	assert.Empty(t, repo.jobs)
+	assert.Empty(t, queue.jobs)
+}
+
+func TestReportServiceResearchJobsAreSuperAdminOnly(t *testing.T) {
+	svc, repo, queue, _ := newReportServiceForTest(t)
+	req := dto.ReportRequest{Type: models.ReportTypeResearch, TermID: "term-1", Format: models.ReportFormatZIP}
+
+	// Research exports stay off until a pseudonym key is configured.
+	_, err := svc.CreateJob(context.Background(), req, "root", models.RoleSuperAdmin)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	WithResearchExports(researchSourceStub{}, []byte("research-key"))(svc.exporter)
+	_, err = svc.CreateJob(context.Background(), req, "admin", models.RoleAdmin)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{Type: models.ReportTypeResearch, TermID: "term-1", Format: models.ReportFormatCSV}, "root", models.RoleSuperAdmin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	resp, err := svc.CreateJob(context.Background(), req, "root", models.RoleSuperAdmin)
+	require.NoError(t, err)
+	require.Len(t, queue.jobs, 1)
+	assert.Equal(t, models.ReportTypeResearch, repo.jobs[resp.ID].Type)
+
+	_, err = svc.GetStatus(context.Background(), resp.ID, "admin", models.RoleAdmin)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = svc.GetStatus(context.Background(), resp.ID, "root", models.RoleSuperAdmin)
+	assert.NoError(t, err)
+
+	// Research exports are zips, which no other report type may use.
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{Type: models.ReportTypeGrades, TermID: "term-1", Format: models.ReportFormatZIP}, "admin", models.RoleAdmin)
+	assert.Error(t, err)
+}
+
+func TestReportServiceMinistryJobsAreAdminOnly(t *testing.T) {
+	svc, repo, _, _ := newReportServiceForTest(t)
+	req := dto.ReportRequest{Type: models.ReportTypeMinistryGrades, TermID: "term-1", Format: models.ReportFormatXLSX}
+
+	_, err := svc.CreateJob(context.Background(), req, "admin", models.RoleAdmin)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	WithMinistryExports(&ministrySourceStub{})(svc.exporter)
+	classID := "class-1"
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{Type: models.ReportTypeMinistryGrades, TermID: "term-1", ClassID: &classID, Format: models.ReportFormatXLSX}, "teacher-1", models.RoleTeacher)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+	_, err = svc.CreateJob(context.Background(), dto.ReportRequest{Type: models.ReportTypeMinistryAttendance, TermID: "term-1", Format: models.ReportFormatPDF}, "admin", models.RoleAdmin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	resp, err := svc.CreateJob(context.Background(), req, "admin", models.RoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportTypeMinistryGrades, repo.jobs[resp.ID].Type)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+// researchPseudonymLength is the number of hex characters kept from a pseudonym's HMAC.
+const researchPseudonymLength = 16
+
+type researchExportSource interface {
+	Attendance(ctx context.Context, termID, classID string) ([]models.ResearchAttendanceRow, error)
+	Grades(ctx context.Context, termID, classID string) ([]models.ResearchGradeRow, error)
+	Behavior(ctx context.Context, termID, classID string) ([]models.ResearchBehaviorRow, error)
+}
+
+// WithResearchExports enables research report jobs. IDs in them are replaced by an HMAC under key,
+// so the same student or class gets the same pseudonym in every export made with that key and
+// datasets of different terms can be joined without revealing who is who.
+func WithResearchExports(source researchExportSource, key []byte) ExportServiceOption {
+	return func(s *ExportService) {
+		if source == nil || len(key) == 0 {
+			return
+		}
+		s.research = source
+		s.pseudonyms = researchPseudonymizer{key: key}
+	}
+}
+
+// ResearchEnabled reports whether research report jobs can be generated.
+func (s *ExportService) ResearchEnabled() bool {
+	return s != nil && s.research != nil
+}
+
+// researchPseudonymizer derives stable pseudonyms for IDs. The kind is part of the MAC so a student
+// and a class never share a pseudonym.
+type researchPseudonymizer struct {
+	key []byte
+}
+
+func (p researchPseudonymizer) pseudonym(kind, id string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(kind + ":" + id)) //nolint:errcheck
+	return hex.EncodeToString(mac.Sum(nil))[:researchPseudonymLength]
+}
+
+// buildResearchArchive renders the attendance, grades and behavior of a term as three CSV files in
+// one zip. Nothing but pseudonyms, codes, dates and values is written.
+func (s *ExportService) buildResearchArchive(ctx context.Context, params models.ReportJobParams) ([]byte, error) {
+	if !s.ResearchEnabled() {
+		return nil, fmt.Errorf("research exports are not configured")
+	}
+	classID := deref(params.ClassID)
+	attendance, err := s.research.Attendance(ctx, params.TermID, classID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := s.research.Grades(ctx, params.TermID, classID)
+	if err != nil {
+		return nil, err
+	}
+	behavior, err := s.research.Behavior(ctx, params.TermID, classID)
+	if err != nil {
+		return nil, err
+	}
+
+	student := func(id string) string { return s.pseudonyms.pseudonym("student", id) }
+	class := func(id string) string { return s.pseudonyms.pseudonym("class", id) }
+	files := []struct {
+		name    string
+		dataset export.Dataset
+	}{
+		{name: "attendance.csv", dataset: export.Dataset{Headers: []string{"student", "class", "date", "status"}}},
+		{name: "grades.csv", dataset: export.Dataset{Headers: []string{"student", "class", "subject", "component", "score"}}},
+		{name: "behavior.csv", dataset: export.Dataset{Headers: []string{"student", "class", "date", "type", "points"}}},
+	}
+	for _, row := range attendance {
+		files[0].dataset.Rows = append(files[0].dataset.Rows, map[string]string{
+			"student": student(row.StudentID),
+			"class":   class(row.ClassID),
+			"date":    row.Date.Format("2006-01-02"),
+			"status":  string(row.Status),
+		})
+	}
+	for _, row := range grades {
+		files[1].dataset.Rows = append(files[1].dataset.Rows, map[string]string{
+			"student":   student(row.StudentID),
+			"class":     class(row.ClassID),
+			"subject":   row.SubjectCode,
+			"component": row.ComponentCode,
+			"score":     strconv.FormatFloat(row.Value, 'f', -1, 64),
+		})
+	}
+	for _, row := range behavior {
+		files[2].dataset.Rows = append(files[2].dataset.Rows, map[string]string{
+			"student": student(row.StudentID),
+			"class":   class(row.ClassID),
+			"date":    row.Date.Format("2006-01-02"),
+			"type":    string(row.NoteType),
+			"points":  strconv.Itoa(row.Points),
+		})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	modified := time.Now().UTC()
+	for _, file := range files {
+		payload, err := s.csv.Render(file.dataset)
+		if err != nil {
+			return nil, err
+		}
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, fmt.Errorf("add %s: %w", file.name, err)
+		}
+		if _, err := w.Write(payload); err != nil {
+			return nil, fmt.Errorf("write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close research archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// retentionPurgeBatch bounds the rows removed per statement so a first purge of a large table does
+// not hold one long lock.
+const retentionPurgeBatch = 1000
+
+// retentionSettingKeys maps each data class to its configuration setting.
+var retentionSettingKeys = map[models.RetentionDataClass]string{
+	models.RetentionRefreshTokens: RetentionRefreshTokensKey,
+	models.RetentionAuditLogs:     RetentionAuditLogsKey,
+}
+
+type retentionStore interface {
+	Candidates(ctx context.Context, class models.RetentionDataClass, cutoff time.Time) (models.RetentionCandidates, error)
+	Purge(ctx context.Context, class models.RetentionDataClass, cutoff time.Time, limit int) (int64, error)
+	CreateHold(ctx context.Context, hold *models.LegalHold) error
+	FindHold(ctx context.Context, id string) (*models.LegalHold, error)
+	ListHolds(ctx context.Context, includeReleased bool) ([]models.LegalHold, error)
+	ReleaseHold(ctx context.Context, id, releasedBy string, at time.Time) error
+}
+
+type retentionSettingReader interface {
+	Get(ctx context.Context, key string) (*models.Configuration, error)
+}
+
+type legalHoldUserReader interface {
+	FindByID(ctx context.Context, id string) (*models.User, error)
+}
+
+// RetentionConfig holds the default retention periods and the purge interval.
+type RetentionConfig struct {
+	// Periods are used for data classes whose configuration setting is unset. Missing or zero
+	// periods keep the class indefinitely.
+	Periods  map[models.RetentionDataClass]time.Duration
+	Interval time.Duration
+}
+
+// RetentionServiceParams groups the dependencies of RetentionService.
+type RetentionServiceParams struct {
+	Store     retentionStore
+	Settings  retentionSettingReader
+	Users     legalHoldUserReader
+	Audit     ports.AuditLogger
+	Metrics   *MetricsService
+	Validator *validator.Validate
+	Logger    *zap.Logger
+	Config    RetentionConfig
+}
+
+// RetentionService purges refresh tokens and audit logs older than their retention period, except
+// rows under an active legal hold. Periods come from the configuration API, falling back to the
+// environment defaults.
+type RetentionService struct {
+	store     retentionStore
+	settings  retentionSettingReader
+	users     legalHoldUserReader
+	audit     ports.AuditLogger
+	metrics   *MetricsService
+	validator *validator.Validate
+	logger    *zap.Logger
+	cfg       RetentionConfig
+	now       func() time.Time
+}
+
+// NewRetentionService constructs the service.
+func NewRetentionService(params RetentionServiceParams) *RetentionService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	cfg := params.Config
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	return &RetentionService{
+		store:     params.Store,
+		settings:  params.Settings,
+		users:     params.Users,
+		audit:     params.Audit,
+		metrics:   params.Metrics,
+		validator: validate,
+		logger:    logger,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Start purges now and then every interval until ctx is cancelled.
+func (s *RetentionService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			if _, err := s.Purge(ctx, ""); err != nil {
+				s.logger.Warn("retention purge failed", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Purge deletes the unheld rows of every data class past its retention period. A dry-run context
+// only counts them. actorID is empty for scheduled runs.
+func (s *RetentionService) Purge(ctx context.Context, actorID string) (*dto.RetentionPurgeReport, error) {
+	now := s.now().UTC()
+	report := &dto.RetentionPurgeReport{
+		GeneratedAt: now,
+		DryRun:      database.IsDryRun(ctx),
+		Classes:     make([]dto.RetentionClassReport, 0, len(models.RetentionDataClasses)),
+	}
+	for _, class := range models.RetentionDataClasses {
+		period, err := s.period(ctx, class)
+		if err != nil {
+			return nil, err
+		}
+		item := dto.RetentionClassReport{DataClass: string(class), RetentionDays: int(period / (24 * time.Hour))}
+		if period <= 0 {
+			report.Classes = append(report.Classes, item)
+			continue
+		}
+		cutoff := now.Add(-period)
+		item.Cutoff = &cutoff
+		counts, err := s.store.Candidates(ctx, class, cutoff)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to count retention candidates")
+		}
+		item.Eligible, item.Held = counts.Eligible, counts.Held
+		if !report.DryRun && counts.Eligible > counts.Held {
+			purged, err := s.purgeClass(ctx, class, cutoff)
+			item.Purged = purged
+			report.Purged += purged
+			s.metrics.RecordRetentionPurge(string(class), purged)
+			if err != nil {
+				report.Classes = append(report.Classes, item)
+				s.emitPurgeAudit(ctx, actorID, report)
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to purge "+strings.ToLower(string(class)))
+			}
+		}
+		report.Classes = append(report.Classes, item)
+	}
+	if report.Purged > 0 {
+		s.logger.Info("retention purge applied", zap.Int64("purged", report.Purged))
+		s.emitPurgeAudit(ctx, actorID, report)
+	}
+	return report, nil
+}
+
+// ListHolds returns the active legal holds, and released ones when asked.
+func (s *RetentionService) ListHolds(ctx context.Context, query dto.LegalHoldQuery) ([]models.LegalHold, error) {
+	holds, err := s.store.ListHolds(ctx, query.IncludeReleased)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list legal holds")
+	}
+	return holds, nil
+}
+
+// CreateHold places a legal hold on a data class or on one user's rows of it.
+func (s *RetentionService) CreateHold(ctx context.Context, req dto.CreateLegalHoldRequest, actorID string) (*models.LegalHold, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid legal hold payload")
+	}
+	hold := &models.LegalHold{
+		DataClass: models.RetentionDataClass(req.DataClass),
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: actorID,
+		CreatedAt: s.now().UTC(),
+	}
+	if hold.Reason == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "reason is required")
+	}
+	if req.UserID != nil {
+		userID := strings.TrimSpace(*req.UserID)
+		if s.users != nil {
+			if _, err := s.users.FindByID(ctx, userID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, appErrors.Clone(appErrors.ErrNotFound, "user not found")
+				}
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to verify user")
+			}
+		}
+		hold.UserID = &userID
+	}
+	if err := s.store.CreateHold(ctx, hold); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create legal hold")
+	}
+	s.emitHoldAudit(ctx, models.AuditActionLegalHoldPlace, actorID, hold)
+	return hold, nil
+}
+
+// ReleaseHold ends an active legal hold; its rows are purged by the next run once past retention.
+func (s *RetentionService) ReleaseHold(ctx context.Context, id, actorID string) (*models.LegalHold, error) {
+	if err := s.store.ReleaseHold(ctx, id, actorID, s.now().UTC()); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to release legal hold")
+		}
+		if _, findErr := s.store.FindHold(ctx, id); errors.Is(findErr, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "legal hold not found")
+		}
+		return nil, appErrors.Clone(appErrors.ErrConflict, "legal hold already released")
+	}
+	hold, err := s.store.FindHold(ctx, id)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load legal hold")
+	}
+	s.emitHoldAudit(ctx, models.AuditActionLegalHoldRelease, actorID, hold)
+	return hold, nil
+}
+
+// period resolves the retention period of class. A setting that cannot be read fails the purge
+// rather than deleting with a period the admins may have changed.
+func (s *RetentionService) period(ctx context.Context, class models.RetentionDataClass) (time.Duration, error) {
+	fallback := s.cfg.Periods[class]
+	if s.settings == nil {
+		return fallback, nil
+	}
+	setting, err := s.settings.Get(ctx, retentionSettingKeys[class])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fallback, nil
+		}
+		return 0, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to read retention settings")
+	}
+	days, err := strconv.Atoi(strings.TrimSpace(setting.Value))
+	if err != nil || days < 0 {
+		s.logger.Warn("invalid retention setting, using default", zap.String("key", setting.Key), zap.String("value", setting.Value))
+		return fallback, nil
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// purgeClass deletes batch by batch until a batch comes back short.
+func (s *RetentionService) purgeClass(ctx context.Context, class models.RetentionDataClass, cutoff time.Time) (int64, error) {
+	var purged int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+		removed, err := s.store.Purge(ctx, class, cutoff, retentionPurgeBatch)
+		purged += removed
+		if err != nil {
+			return purged, err
+		}
+		if removed < retentionPurgeBatch {
+			return purged, nil
+		}
+	}
+}
+
+func (s *RetentionService) emitPurgeAudit(ctx context.Context, actorID string, report *dto.RetentionPurgeReport) {
+	payload, _ := json.Marshal(report)
+	s.emitAudit(ctx, &models.AuditLog{
+		Action:    models.AuditActionRetentionPurge,
+		Resource:  "retention",
+		NewValues: payload,
+	}, actorID)
+}
+
+func (s *RetentionService) emitHoldAudit(ctx context.Context, action, actorID string, hold *models.LegalHold) {
+	payload, _ := json.Marshal(hold)
+	id := hold.ID
+	s.emitAudit(ctx, &models.AuditLog{
+		Action:     action,
+		Resource:   "legal_hold",
+		ResourceID: &id,
+		NewValues:  payload,
+	}, actorID)
+}
+
+func (s *RetentionService) emitAudit(ctx context.Context, log *models.AuditLog, actorID string) {
+	if s.audit == nil {
+		return
+	}
+	log.IPAddress = "system"
+	log.UserAgent = "retention"
+	if actorID != "" {
+		log.UserID = &actorID
+	}
+	if err := s.audit.CreateAuditLog(ctx, log); err != nil {
+		s.logger.Warn("failed to create retention audit", zap.Error(err))
+	}
+}
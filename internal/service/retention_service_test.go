@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type retentionStoreStub struct {
+	candidates map[models.RetentionDataClass]models.RetentionCandidates
+	// remaining is how many unheld rows each class still has to purge.
+	remaining map[models.RetentionDataClass]int64
+	cutoffs   map[models.RetentionDataClass]time.Time
+	purgeErr  error
+	holds     map[string]*models.LegalHold
+}
+
+func (s *retentionStoreStub) Candidates(ctx context.Context, class models.RetentionDataClass, cutoff time.Time) (models.RetentionCandidates, error) {
+	if s.cutoffs == nil {
+		s.cutoffs = make(map[models.RetentionDataClass]time.Time)
+	}
+	s.cutoffs[class] = cutoff
+	return s.candidates[class], nil
+}
+
+func (s *retentionStoreStub) Purge(ctx context.Context, class models.RetentionDataClass, cutoff time.Time, limit int) (int64, error) {
+	if s.purgeErr != nil {
+		return 0, s.purgeErr
+	}
+	removed := s.remaining[class]
+	if removed > int64(limit) {
+		removed = int64(limit)
+	}
+	s.remaining[class] -= removed
+	return removed, nil
+}
+
+func (s *retentionStoreStub) CreateHold(ctx context.Context, hold *models.LegalHold) error {
+	hold.ID = "hold-1"
+	s.holds = map[string]*models.LegalHold{hold.ID: hold}
+	return nil
+}
+
+func (s *retentionStoreStub) FindHold(ctx context.Context, id string) (*models.LegalHold, error) {
+	if hold, ok := s.holds[id]; ok {
+		return hold, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *retentionStoreStub) ListHolds(ctx context.Context, includeReleased bool) ([]models.LegalHold, error) {
+	return nil, nil
+}
+
+func (s *retentionStoreStub) ReleaseHold(ctx context.Context, id, releasedBy string, at time.Time) error {
+	hold, ok := s.holds[id]
+	if !ok || !hold.Active() {
+		return sql.ErrNoRows
+	}
+	hold.ReleasedBy, hold.ReleasedAt = &releasedBy, &at
+	return nil
+}
+
+type retentionUserStub struct{}
+
+func (retentionUserStub) FindByID(ctx context.Context, id string) (*models.User, error) {
+	if id != "user-1" {
+		return nil, sql.ErrNoRows
+	}
+	return &models.User{ID: id}, nil
+}
+
+func newRetentionFixture(store *retentionStoreStub, settings map[string]models.Configuration) (*RetentionService, *auditLoggerStub) {
+	audit := &auditLoggerStub{}
+	svc := NewRetentionService(RetentionServiceParams{
+		Store:    store,
+		Settings: &configurationRepoStub{items: settings},
+		Users:    retentionUserStub{},
+		Audit:    audit,
+		Config: RetentionConfig{Periods: map[models.RetentionDataClass]time.Duration{
+			models.RetentionRefreshTokens: 30 * 24 * time.Hour,
+		}},
+	})
+	svc.now = func() time.Time { return time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC) }
+	return svc, audit
+}
+
+func TestRetentionServicePurgeSkipsHeldRows(t *testing.T) {
+	store := &retentionStoreStub{
+		candidates: map[models.RetentionDataClass]models.RetentionCandidates{
+			models.RetentionRefreshTokens: {Eligible: 2500, Held: 300},
+			models.RetentionAuditLogs:     {Eligible: 40, Held: 0},
+		},
+		remaining: map[models.RetentionDataClass]int64{models.RetentionRefreshTokens: 2200, models.RetentionAuditLogs: 40},
+	}
+	// The configured audit log period overrides the default, which keeps audit logs.
+	svc, audit := newRetentionFixture(store, map[string]models.Configuration{
+		RetentionAuditLogsKey: {Key: RetentionAuditLogsKey, Value: "365"},
+	})
+
+	report, err := svc.Purge(context.Background(), "admin-1")
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, int64(2240), report.Purged)
+	require.Len(t, report.Classes, 2)
+	assert.Equal(t, dto.RetentionClassReport{
+		DataClass: "REFRESH_TOKENS", RetentionDays: 30, Cutoff: report.Classes[0].Cutoff, Eligible: 2500, Held: 300, Purged: 2200,
+	}, report.Classes[0])
+	assert.Equal(t, time.Date(2026, 9, 1, 2, 0, 0, 0, time.UTC), *report.Classes[0].Cutoff)
+	assert.Equal(t, 365, report.Classes[1].RetentionDays)
+	assert.Equal(t, time.Date(2025, 10, 1, 2, 0, 0, 0, time.UTC), store.cutoffs[models.RetentionAuditLogs])
+
+	require.Len(t, audit.logs, 1)
+	assert.Equal(t, models.AuditActionRetentionPurge, audit.logs[0].Action)
+	assert.Equal(t, "admin-1", *audit.logs[0].UserID)
+}
+
+func TestRetentionServicePurgeDryRunOnlyCounts(t *testing.T) {
+	store := &retentionStoreStub{
+		candidates: map[models.RetentionDataClass]models.RetentionCandidates{models.RetentionRefreshTokens: {Eligible: 10, Held: 4}},
+		remaining:  map[models.RetentionDataClass]int64{models.RetentionRefreshTokens: 6},
+		purgeErr:   errors.New("must not purge"),
+	}
+	svc, audit := newRetentionFixture(store, nil)
+
+	report, err := svc.Purge(database.WithDryRun(context.Background()), "")
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Zero(t, report.Purged)
+	assert.Equal(t, int64(10), report.Classes[0].Eligible)
+	assert.Equal(t, int64(4), report.Classes[0].Held)
+	// Audit logs have no default period, so they are kept and not counted.
+	assert.Nil(t, report.Classes[1].Cutoff)
+	assert.NotContains(t, store.cutoffs, models.RetentionAuditLogs)
+	assert.Empty(t, audit.logs)
+}
+
+func TestRetentionServiceLegalHolds(t *testing.T) {
+	store := &retentionStoreStub{}
+	svc, audit := newRetentionFixture(store, nil)
+
+	_, err := svc.CreateHold(context.Background(), dto.CreateLegalHoldRequest{DataClass: "SESSIONS", Reason: "case 12"}, "admin-1")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+	missing := "user-9"
+	_, err = svc.CreateHold(context.Background(), dto.CreateLegalHoldRequest{DataClass: "AUDIT_LOGS", UserID: &missing, Reason: "case 12"}, "admin-1")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	userID := " user-1 "
+	hold, err := svc.CreateHold(context.Background(), dto.CreateLegalHoldRequest{DataClass: "AUDIT_LOGS", UserID: &userID, Reason: " case 12 "}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", *hold.UserID)
+	assert.Equal(t, "case 12", hold.Reason)
+	assert.True(t, hold.Active())
+
+	released, err := svc.ReleaseHold(context.Background(), hold.ID, "admin-2")
+	require.NoError(t, err)
+	assert.False(t, released.Active())
+	assert.Equal(t, "admin-2", *released.ReleasedBy)
+
+	_, err = svc.ReleaseHold(context.Background(), hold.ID, "admin-2")
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+	_, err = svc.ReleaseHold(context.Background(), "hold-x", "admin-2")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	require.Len(t, audit.logs, 2)
+	assert.Equal(t, models.AuditActionLegalHoldPlace, audit.logs[0].Action)
+	assert.Equal(t, models.AuditActionLegalHoldRelease, audit.logs[1].Action)
+}
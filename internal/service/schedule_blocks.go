@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// blockPeriod is one period of a timetable as seen by findBlocks. Lesson identifies what is taught,
+// e.g. the subject and teacher, and Size is the block size it was scheduled with.
+type blockPeriod struct {
+	Day    int
+	Time   int
+	Size   int
+	Lesson string
+}
+
+// lessonBlock is a block lesson: Length consecutive periods of one lesson on a day starting at
+// Start. A block shorter than its Size has been broken up, e.g. by a manual edit.
+type lessonBlock struct {
+	Day    int
+	Start  int
+	Length int
+	Size   int
+}
+
+// Broken reports whether the block is missing periods.
+func (b lessonBlock) Broken() bool {
+	return b.Length != b.Size
+}
+
+// Keys returns the block's periods in order.
+func (b lessonBlock) Keys() []slotKey {
+	keys := make([]slotKey, b.Length)
+	for i := range keys {
+		keys[i] = slotKey{Day: b.Day, Time: b.Start + i}
+	}
+	return keys
+}
+
+// Label renders the periods as "3-4".
+func (b lessonBlock) Label() string {
+	if b.Length <= 1 {
+		return fmt.Sprintf("%d", b.Start)
+	}
+	return fmt.Sprintf("%d-%d", b.Start, b.Start+b.Length-1)
+}
+
+// findBlocks maps every period of a block lesson to its block. Consecutive periods of one lesson
+// on a day are cut into blocks of its size from the first period on, so two double lessons taught
+// back to back stay two blocks. Periods with a size of 1 or less are not block lessons.
+func findBlocks(periods []blockPeriod) map[slotKey]lessonBlock {
+	type runKey struct {
+		day    int
+		lesson string
+	}
+	runs := make(map[runKey][]blockPeriod)
+	for _, period := range periods {
+		if period.Size <= 1 {
+			continue
+		}
+		key := runKey{day: period.Day, lesson: period.Lesson}
+		runs[key] = append(runs[key], period)
+	}
+
+	blocks := make(map[slotKey]lessonBlock)
+	for key, run := range runs {
+		sort.Slice(run, func(i, j int) bool { return run[i].Time < run[j].Time })
+		var current lessonBlock
+		for _, period := range run {
+			if current.Length > 0 && period.Time == current.Start+current.Length && current.Length < current.Size {
+				current.Length++
+			} else {
+				current = lessonBlock{Day: key.day, Start: period.Time, Length: 1, Size: period.Size}
+			}
+			for _, member := range current.Keys() {
+				blocks[member] = current
+			}
+		}
+	}
+	return blocks
+}
+
+// proposalBlocks finds the block lessons of generated slots.
+func proposalBlocks(slots []dto.ScheduleSlotProposal) map[slotKey]lessonBlock {
+	periods := make([]blockPeriod, len(slots))
+	for i, slot := range slots {
+		periods[i] = blockPeriod{Day: slot.DayOfWeek, Time: slot.TimeSlot, Size: slot.BlockSize, Lesson: subjectLoadKey(slot.SubjectID, slot.TeacherID)}
+	}
+	return findBlocks(periods)
+}
+
+// semesterSlotBlocks finds the block lessons of stored semester schedule slots.
+func semesterSlotBlocks(slots []models.SemesterScheduleSlot) map[slotKey]lessonBlock {
+	periods := make([]blockPeriod, len(slots))
+	for i, slot := range slots {
+		periods[i] = blockPeriod{Day: slot.DayOfWeek, Time: slot.TimeSlot, Size: slot.BlockSize, Lesson: subjectLoadKey(slot.SubjectID.String(), slot.TeacherID.String())}
+	}
+	return findBlocks(periods)
+}
+
+// loadBlockSize returns how many consecutive periods one lesson of load takes.
+func loadBlockSize(load dto.SubjectLoadRequest) int {
+	if load.BlockSize < 1 {
+		return 1
+	}
+	return load.BlockSize
+}
+
+// slotBlockSize is the BlockSize recorded on the periods of a lesson of size periods.
+func slotBlockSize(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	return size
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBlocks(t *testing.T) {
+	periods := []blockPeriod{
+		{Day: 1, Time: 4, Size: 2, Lesson: "chem"},
+		{Day: 1, Time: 1, Size: 2, Lesson: "chem"},
+		{Day: 1, Time: 2, Size: 2, Lesson: "chem"},
+		{Day: 1, Time: 3, Size: 2, Lesson: "chem"},
+		{Day: 2, Time: 5, Size: 3, Lesson: "pe"},
+		{Day: 2, Time: 1, Size: 0, Lesson: "math"},
+	}
+
+	blocks := findBlocks(periods)
+
+	require.Len(t, blocks, 5)
+	// Back-to-back double lessons stay two blocks.
+	assert.Equal(t, lessonBlock{Day: 1, Start: 1, Length: 2, Size: 2}, blocks[slotKey{Day: 1, Time: 2}])
+	assert.Equal(t, "3-4", blocks[slotKey{Day: 1, Time: 3}].Label())
+	assert.False(t, blocks[slotKey{Day: 1, Time: 4}].Broken())
+
+	broken := blocks[slotKey{Day: 2, Time: 5}]
+	assert.True(t, broken.Broken())
+	assert.Equal(t, []slotKey{{Day: 2, Time: 5}}, broken.Keys())
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+// Schedule clash dimensions, matching the ones reported by conflict checks.
+const (
+	ScheduleClashTeacher = "TEACHER"
+	ScheduleClashClass   = "CLASS"
+	ScheduleClashRoom    = "ROOM"
+)
+
+type scheduleTermLister interface {
+	ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error)
+}
+
+// ScheduleClashServiceParams groups the dependencies of ScheduleClashService.
+type ScheduleClashServiceParams struct {
+	Schedules scheduleTermLister
+	Terms     ports.TermReader
+	Subjects  ports.SubjectReader
+	Teachers  ports.TeacherReader
+	Classes   ports.ClassReader
+	// Store and Jobs publish exports; both are nil when reports are disabled.
+	Store     scheduleExportStore
+	Jobs      scheduleExportJobRecorder
+	Validator *validator.Validate
+	Logger    *zap.Logger
+}
+
+// ScheduleClashService scans a term's daily schedules for teachers, classes and rooms booked twice in
+// the same slot. Conflict checks keep new schedules from clashing, but rows imported from the legacy
+// system never went through them.
+type ScheduleClashService struct {
+	schedules scheduleTermLister
+	terms     ports.TermReader
+	subjects  ports.SubjectReader
+	teachers  ports.TeacherReader
+	classes   ports.ClassReader
+	store     scheduleExportStore
+	jobs      scheduleExportJobRecorder
+	pdf       timetableRenderer
+	xlsx      timetableRenderer
+	validator *validator.Validate
+	logger    *zap.Logger
+}
+
+// NewScheduleClashService constructs the service.
+func NewScheduleClashService(params ScheduleClashServiceParams) *ScheduleClashService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ScheduleClashService{
+		schedules: params.Schedules,
+		terms:     params.Terms,
+		subjects:  params.Subjects,
+		teachers:  params.Teachers,
+		classes:   params.Classes,
+		store:     params.Store,
+		jobs:      params.Jobs,
+		pdf:       export.NewPDFExporter(),
+		xlsx:      export.NewXLSXExporter(),
+		validator: validate,
+		logger:    logger,
+	}
+}
+
+// Report lists the term's clashes ordered by dimension, day and slot.
+func (s *ScheduleClashService) Report(ctx context.Context, query dto.ScheduleClashQuery) (*dto.ScheduleClashReport, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule clash query")
+	}
+	if s.terms != nil {
+		if _, err := s.terms.FindByID(ctx, query.TermID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+		}
+	}
+	schedules, err := s.schedules.ListByTerm(ctx, query.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list schedules")
+	}
+
+	names := newScheduleNameCache(s.subjects, s.teachers, s.classes)
+	clashes := findScheduleClashes(schedules)
+	report := &dto.ScheduleClashReport{
+		TermID:  query.TermID,
+		Total:   len(clashes),
+		Counts:  map[string]int{ScheduleClashTeacher: 0, ScheduleClashClass: 0, ScheduleClashRoom: 0},
+		Clashes: make([]dto.ScheduleClash, 0, len(clashes)),
+	}
+	for _, clash := range clashes {
+		report.Counts[clash.dimension]++
+		item := dto.ScheduleClash{
+			Dimension:  clash.dimension,
+			DayOfWeek:  clash.schedules[0].DayOfWeek,
+			TimeSlot:   clash.schedules[0].TimeSlot,
+			ResourceID: clash.resource,
+			Schedules:  make([]dto.ScheduleClashEntry, 0, len(clash.schedules)),
+		}
+		switch clash.dimension {
+		case ScheduleClashTeacher:
+			item.ResourceName = names.teacher(ctx, clash.resource)
+		case ScheduleClashClass:
+			item.ResourceName = names.class(ctx, clash.resource)
+		default:
+			item.ResourceName = clash.resource
+		}
+		for _, sched := range clash.schedules {
+			item.Schedules = append(item.Schedules, dto.ScheduleClashEntry{
+				ScheduleID:  sched.ID,
+				ClassID:     sched.ClassID,
+				ClassName:   names.class(ctx, sched.ClassID),
+				SubjectID:   sched.SubjectID,
+				SubjectName: names.subject(ctx, sched.SubjectID),
+				TeacherID:   sched.TeacherID,
+				TeacherName: names.teacher(ctx, sched.TeacherID),
+				Room:        sched.Room,
+			})
+		}
+		report.Clashes = append(report.Clashes, item)
+	}
+	return report, nil
+}
+
+// Export renders the clash report with one row per clashing schedule and returns a signed download URL.
+func (s *ScheduleClashService) Export(ctx context.Context, req dto.ScheduleClashExportRequest, actorID string) (*dto.ScheduleExportResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule clash export query")
+	}
+	if s.store == nil || s.jobs == nil {
+		return nil, appErrors.Clone(appErrors.ErrInternal, "schedule clash exports unavailable")
+	}
+	if req.Format == "" {
+		req.Format = string(models.ReportFormatPDF)
+	}
+	report, err := s.Report(ctx, dto.ScheduleClashQuery{TermID: req.TermID})
+	if err != nil {
+		return nil, err
+	}
+
+	dataset := buildScheduleClashDataset(report.Clashes)
+	title := fmt.Sprintf("Schedule Clashes %s", req.TermID)
+	format := models.ReportFormat(req.Format)
+	var payload []byte
+	switch format {
+	case models.ReportFormatXLSX:
+		payload, err = s.xlsx.Render(dataset, title)
+	default:
+		payload, err = s.pdf.Render(dataset, title)
+	}
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to render schedule clash export")
+	}
+
+	job := &models.ReportJob{
+		ID:   uuid.NewString(),
+		Type: models.ReportTypeScheduleClashes,
+		Params: models.ReportJobParams{
+			TermID: req.TermID,
+			Format: format,
+		},
+		Status:    models.ReportStatusFinished,
+		Progress:  100,
+		CreatedBy: actorID,
+	}
+	filename := fmt.Sprintf("schedule_clashes_%s_%s.%s", sanitizeFilename(req.TermID), time.Now().UTC().Format("20060102_150405"), format)
+	result, err := s.store.Store(job.ID, filename, format, payload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store schedule clash export")
+	}
+	now := time.Now().UTC()
+	job.ResultURL = &result.URL
+	job.FinishedAt = &now
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record schedule clash export")
+	}
+
+	return &dto.ScheduleExportResponse{
+		URL:       result.URL,
+		Format:    string(format),
+		View:      "clashes",
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
+
+type scheduleClashGroup struct {
+	dimension string
+	resource  string
+	day       int
+	slot      int
+	schedules []models.Schedule
+}
+
+// clashes reports whether the group's schedules belong to more than one session. The per-class
+// schedules of a lesson group session share a teacher and room, and a class may attend several
+// groups at once when each takes a disjoint subset of its students, which is checked when the
+// session is created.
+func (g scheduleClashGroup) clashes() bool {
+	sessions := make(map[string]struct{}, len(g.schedules))
+	grouped := true
+	for _, sched := range g.schedules {
+		key := sched.ID
+		if sched.LessonGroupID != nil {
+			key = "group:" + *sched.LessonGroupID
+		} else {
+			grouped = false
+		}
+		sessions[key] = struct{}{}
+	}
+	if g.dimension == ScheduleClashClass && grouped {
+		return false
+	}
+	return len(sessions) > 1
+}
+
+// findScheduleClashes groups schedules sharing a day and slot by teacher, class and room. Rooms are
+// compared case-insensitively and schedules without a room never clash on it.
+func findScheduleClashes(schedules []models.Schedule) []scheduleClashGroup {
+	groups := make(map[string]*scheduleClashGroup)
+	add := func(dimension, resource string, sched models.Schedule) {
+		day := dayStringToIndex(sched.DayOfWeek)
+		slot := parseTimeSlot(sched.TimeSlot)
+		key := strings.Join([]string{dimension, strings.ToUpper(strings.TrimSpace(sched.DayOfWeek)), strings.TrimSpace(sched.TimeSlot), strings.ToLower(resource)}, "|")
+		group, ok := groups[key]
+		if !ok {
+			group = &scheduleClashGroup{dimension: dimension, resource: resource, day: day, slot: slot}
+			groups[key] = group
+		}
+		group.schedules = append(group.schedules, sched)
+	}
+	for _, sched := range schedules {
+		add(ScheduleClashTeacher, sched.TeacherID, sched)
+		add(ScheduleClashClass, sched.ClassID, sched)
+		if room := strings.TrimSpace(sched.Room); room != "" {
+			add(ScheduleClashRoom, room, sched)
+		}
+	}
+
+	order := map[string]int{ScheduleClashTeacher: 0, ScheduleClashClass: 1, ScheduleClashRoom: 2}
+	clashes := make([]scheduleClashGroup, 0)
+	for _, group := range groups {
+		if len(group.schedules) < 2 || !group.clashes() {
+			continue
+		}
+		sort.Slice(group.schedules, func(i, j int) bool { return group.schedules[i].ID < group.schedules[j].ID })
+		clashes = append(clashes, *group)
+	}
+	sort.Slice(clashes, func(i, j int) bool {
+		a, b := clashes[i], clashes[j]
+		if a.dimension != b.dimension {
+			return order[a.dimension] < order[b.dimension]
+		}
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		if a.slot != b.slot {
+			return a.slot < b.slot
+		}
+		return a.resource < b.resource
+	})
+	return clashes
+}
+
+// buildScheduleClashDataset lists one row per clashing schedule, numbering the clashes so rows of the
+// same clash can be told apart.
+func buildScheduleClashDataset(clashes []dto.ScheduleClash) export.Dataset {
+	headers := []string{"Clash", "Type", "Day", "Slot", "Resource", "Class", "Subject", "Teacher", "Room"}
+	rows := make([]map[string]string, 0)
+	for i, clash := range clashes {
+		for _, entry := range clash.Schedules {
+			rows = append(rows, map[string]string{
+				"Clash":    strconv.Itoa(i + 1),
+				"Type":     clash.Dimension,
+				"Day":      clash.DayOfWeek,
+				"Slot":     clash.TimeSlot,
+				"Resource": clash.ResourceName,
+				"Class":    entry.ClassName,
+				"Subject":  entry.SubjectName,
+				"Teacher":  entry.TeacherName,
+				"Room":     entry.Room,
+			})
+		}
+	}
+	return export.Dataset{Headers: headers, Rows: rows}
+}
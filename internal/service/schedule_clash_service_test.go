@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+func newScheduleClashFixture(schedules []models.Schedule) (*ScheduleClashService, *exportStoreStub, *exportJobRecorderStub) {
+	store := &exportStoreStub{}
+	jobs := &exportJobRecorderStub{}
+	svc := NewScheduleClashService(ScheduleClashServiceParams{
+		Schedules: scheduleFeederStub{termSchedules: schedules},
+		Terms:     termLookupStub{},
+		Subjects:  subjectLookupStub{subjects: map[string]struct{}{"math": {}, "bio": {}}},
+		Teachers:  exportTeacherLookupStub{},
+		Classes:   classLookupStub{},
+		Store:     store,
+		Jobs:      jobs,
+	})
+	return svc, store, jobs
+}
+
+func TestScheduleClashServiceReportGroupsDoubleBookings(t *testing.T) {
+	svc, _, _ := newScheduleClashFixture([]models.Schedule{
+		{ID: "s1", TermID: "term-1", ClassID: "class-1", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R101"},
+		{ID: "s2", TermID: "term-1", ClassID: "class-2", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "r101 "},
+		{ID: "s3", TermID: "term-1", ClassID: "class-1", SubjectID: "bio", TeacherID: "t2", DayOfWeek: "TUESDAY", TimeSlot: "2"},
+		{ID: "s4", TermID: "term-1", ClassID: "class-1", SubjectID: "math", TeacherID: "t3", DayOfWeek: "TUESDAY", TimeSlot: "2"},
+		{ID: "s5", TermID: "term-1", ClassID: "class-3", SubjectID: "bio", TeacherID: "t2", DayOfWeek: "TUESDAY", TimeSlot: "3"},
+	})
+
+	report, err := svc.Report(context.Background(), dto.ScheduleClashQuery{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, map[string]int{ScheduleClashTeacher: 1, ScheduleClashClass: 1, ScheduleClashRoom: 1}, report.Counts)
+
+	require.Len(t, report.Clashes, 3)
+	teacher := report.Clashes[0]
+	assert.Equal(t, ScheduleClashTeacher, teacher.Dimension)
+	assert.Equal(t, "Budi", teacher.ResourceName)
+	assert.Equal(t, "MONDAY", teacher.DayOfWeek)
+	require.Len(t, teacher.Schedules, 2)
+	assert.Equal(t, "s1", teacher.Schedules[0].ScheduleID)
+	assert.Equal(t, "X IPA 1", teacher.Schedules[0].ClassName)
+
+	class := report.Clashes[1]
+	assert.Equal(t, ScheduleClashClass, class.Dimension)
+	assert.Equal(t, "class-1", class.ResourceID)
+	assert.Equal(t, "TUESDAY", class.DayOfWeek)
+
+	assert.Equal(t, ScheduleClashRoom, report.Clashes[2].Dimension)
+	assert.Equal(t, "R101", report.Clashes[2].ResourceName)
+}
+
+func TestScheduleClashServiceReportSkipsLessonGroupSessions(t *testing.T) {
+	religion, language := "group-religion", "group-language"
+	svc, _, _ := newScheduleClashFixture([]models.Schedule{
+		{ID: "s1", TermID: "term-1", ClassID: "class-1", SubjectID: "bio", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R101", LessonGroupID: &religion},
+		{ID: "s2", TermID: "term-1", ClassID: "class-2", SubjectID: "bio", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R101", LessonGroupID: &religion},
+		{ID: "s3", TermID: "term-1", ClassID: "class-1", SubjectID: "math", TeacherID: "t2", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R102", LessonGroupID: &language},
+		// A regular lesson still clashes with the group session sharing its teacher.
+		{ID: "s4", TermID: "term-1", ClassID: "class-3", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1", Room: "R103"},
+	})
+
+	report, err := svc.Report(context.Background(), dto.ScheduleClashQuery{TermID: "term-1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Total)
+	assert.Equal(t, ScheduleClashTeacher, report.Clashes[0].Dimension)
+	require.Len(t, report.Clashes[0].Schedules, 3)
+}
+
+func TestScheduleClashServiceReportEmptyTerm(t *testing.T) {
+	svc, _, _ := newScheduleClashFixture(nil)
+
+	report, err := svc.Report(context.Background(), dto.ScheduleClashQuery{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Zero(t, report.Total)
+	assert.NotNil(t, report.Clashes)
+
+	_, err = svc.Report(context.Background(), dto.ScheduleClashQuery{})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleClashServiceExportXLSX(t *testing.T) {
+	svc, store, jobs := newScheduleClashFixture([]models.Schedule{
+		{ID: "s1", ClassID: "class-1", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1"},
+		{ID: "s2", ClassID: "class-2", SubjectID: "bio", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1"},
+	})
+
+	result, err := svc.Export(context.Background(), dto.ScheduleClashExportRequest{TermID: "term-1", Format: "xlsx"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "xlsx", result.Format)
+	assert.Contains(t, store.filename, "schedule_clashes_term-1_")
+	assert.NotEmpty(t, store.payload)
+	require.Len(t, jobs.jobs, 1)
+	assert.Equal(t, models.ReportTypeScheduleClashes, jobs.jobs[0].Type)
+	assert.Equal(t, "admin-1", jobs.jobs[0].CreatedBy)
+
+	dataset := buildScheduleClashDataset([]dto.ScheduleClash{{
+		Dimension: ScheduleClashTeacher, DayOfWeek: "MONDAY", TimeSlot: "1", ResourceName: "Budi",
+		Schedules: []dto.ScheduleClashEntry{{ClassName: "Class class-1"}, {ClassName: "Class class-2"}},
+	}})
+	require.Len(t, dataset.Rows, 2)
+	assert.Equal(t, "1", dataset.Rows[1]["Clash"])
+	assert.Equal(t, "Class class-2", dataset.Rows[1]["Class"])
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// ScheduleConstraintKind distinguishes blocking rules from score penalties.
+type ScheduleConstraintKind string
+
+const (
+	// ScheduleConstraintHard prevents placements that would break the rule.
+	ScheduleConstraintHard ScheduleConstraintKind = "HARD"
+	// ScheduleConstraintSoft lowers the proposal score by weight per violation.
+	ScheduleConstraintSoft ScheduleConstraintKind = "SOFT"
+)
+
+// ScheduleConstraint is a pluggable timetable rule evaluated by the generator.
+type ScheduleConstraint interface {
+	// Name is the identifier clients pass in hardConstraints/softConstraints.
+	Name() string
+	// Description explains the rule for API consumers.
+	Description() string
+	// Weight is the score penalty applied per violation when used as a soft constraint.
+	Weight() float64
+	// Violations counts how many times the timetable breaks the rule.
+	Violations(tt ScheduleTimetable) int
+}
+
+// ScheduleTimetable is the read-only view of a class timetable handed to constraints.
+type ScheduleTimetable struct {
+	Days            []int
+	TimeSlotsPerDay int
+	Slots           []dto.ScheduleSlotProposal
+	Loads           map[string]dto.SubjectLoadRequest
+}
+
+// Load returns the subject load that produced the slot.
+func (t ScheduleTimetable) Load(slot dto.ScheduleSlotProposal) dto.SubjectLoadRequest {
+	return t.Loads[subjectLoadKey(slot.SubjectID, slot.TeacherID)]
+}
+
+// DefaultScheduleConstraints returns the built-in constraint catalogue.
+func DefaultScheduleConstraints() []ScheduleConstraint {
+	return []ScheduleConstraint{
+		NewHeavySubjectCutoffConstraint(6, 7, 2),
+		NewMorningOnlyConstraint("PE", 4, 3),
+		NewMaxSubjectPerDayConstraint(2, 5),
+	}
+}
+
+// NewHeavySubjectCutoffConstraint forbids heavy subjects (difficulty >= minDifficulty or tagged HEAVY) after cutoffSlot.
+func NewHeavySubjectCutoffConstraint(cutoffSlot, minDifficulty int, weight float64) ScheduleConstraint {
+	return heavySubjectCutoffConstraint{cutoffSlot: cutoffSlot, minDifficulty: minDifficulty, weight: weight}
+}
+
+// NewMorningOnlyConstraint keeps subjects carrying tag within the first lastSlot periods.
+func NewMorningOnlyConstraint(tag string, lastSlot int, weight float64) ScheduleConstraint {
+	return morningOnlyConstraint{tag: strings.ToUpper(strings.TrimSpace(tag)), lastSlot: lastSlot, weight: weight}
+}
+
+// NewMaxSubjectPerDayConstraint caps how often the same subject appears on one day.
+func NewMaxSubjectPerDayConstraint(max int, weight float64) ScheduleConstraint {
+	return maxSubjectPerDayConstraint{max: max, weight: weight}
+}
+
+type heavySubjectCutoffConstraint struct {
+	cutoffSlot    int
+	minDifficulty int
+	weight        float64
+}
+
+func (c heavySubjectCutoffConstraint) Name() string { return "NO_HEAVY_AFTER_SLOT" }
+
+func (c heavySubjectCutoffConstraint) Description() string {
+	return fmt.Sprintf("subjects with difficulty >= %d (or tagged HEAVY) must not be placed after slot %d", c.minDifficulty, c.cutoffSlot)
+}
+
+func (c heavySubjectCutoffConstraint) Weight() float64 { return c.weight }
+
+func (c heavySubjectCutoffConstraint) Violations(tt ScheduleTimetable) int {
+	count := 0
+	for _, slot := range tt.Slots {
+		if slot.TimeSlot <= c.cutoffSlot {
+			continue
+		}
+		load := tt.Load(slot)
+		if load.Difficulty >= c.minDifficulty || hasTag(load.Tags, "HEAVY") {
+			count++
+		}
+	}
+	return count
+}
+
+type morningOnlyConstraint struct {
+	tag      string
+	lastSlot int
+	weight   float64
+}
+
+func (c morningOnlyConstraint) Name() string { return "MORNING_ONLY_" + c.tag }
+
+func (c morningOnlyConstraint) Description() string {
+	return fmt.Sprintf("subjects tagged %s must be placed within the first %d slots", c.tag, c.lastSlot)
+}
+
+func (c morningOnlyConstraint) Weight() float64 { return c.weight }
+
+func (c morningOnlyConstraint) Violations(tt ScheduleTimetable) int {
+	count := 0
+	for _, slot := range tt.Slots {
+		if slot.TimeSlot > c.lastSlot && hasTag(tt.Load(slot).Tags, c.tag) {
+			count++
+		}
+	}
+	return count
+}
+
+type maxSubjectPerDayConstraint struct {
+	max    int
+	weight float64
+}
+
+func (c maxSubjectPerDayConstraint) Name() string { return "MAX_SUBJECT_PER_DAY" }
+
+func (c maxSubjectPerDayConstraint) Description() string {
+	return fmt.Sprintf("the same subject may appear at most %d times per day; a block lesson counts once", c.max)
+}
+
+func (c maxSubjectPerDayConstraint) Weight() float64 { return c.weight }
+
+func (c maxSubjectPerDayConstraint) Violations(tt ScheduleTimetable) int {
+	counts := make(map[string]int)
+	blocks := proposalBlocks(tt.Slots)
+	for _, slot := range tt.Slots {
+		if block, ok := blocks[slotKey{Day: slot.DayOfWeek, Time: slot.TimeSlot}]; ok && block.Start != slot.TimeSlot {
+			continue
+		}
+		counts[fmt.Sprintf("%d|%s", slot.DayOfWeek, slot.SubjectID)]++
+	}
+	violations := 0
+	for _, count := range counts {
+		if count > c.max {
+			violations += count - c.max
+		}
+	}
+	return violations
+}
+
+// scheduleConstraintSet holds the constraints selected for one generation run.
+type scheduleConstraintSet struct {
+	hard []ScheduleConstraint
+	soft []ScheduleConstraint
+}
+
+func (s scheduleConstraintSet) names() map[string][]string {
+	result := map[string][]string{"hard": {}, "soft": {}}
+	for _, c := range s.hard {
+		result["hard"] = append(result["hard"], c.Name())
+	}
+	for _, c := range s.soft {
+		result["soft"] = append(result["soft"], c.Name())
+	}
+	return result
+}
+
+// softPenalty sums weighted soft violations for the timetable.
+func (s scheduleConstraintSet) softPenalty(tt ScheduleTimetable) float64 {
+	var penalty float64
+	for _, c := range s.soft {
+		penalty += float64(c.Violations(tt)) * c.Weight()
+	}
+	return penalty
+}
+
+func newConstraintRegistry(constraints []ScheduleConstraint) map[string]ScheduleConstraint {
+	registry := make(map[string]ScheduleConstraint, len(constraints))
+	for _, c := range constraints {
+		if c == nil {
+			continue
+		}
+		registry[strings.ToUpper(c.Name())] = c
+	}
+	return registry
+}
+
+func resolveScheduleConstraints(registry map[string]ScheduleConstraint, hard, soft []string) (scheduleConstraintSet, error) {
+	var set scheduleConstraintSet
+	seen := make(map[string]ScheduleConstraintKind)
+	resolve := func(names []string, kind ScheduleConstraintKind) error {
+		for _, raw := range names {
+			name := strings.ToUpper(strings.TrimSpace(raw))
+			if name == "" {
+				continue
+			}
+			constraint, ok := registry[name]
+			if !ok {
+				return appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unknown schedule constraint %s", raw))
+			}
+			if existing, dup := seen[name]; dup {
+				if existing != kind {
+					return appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("constraint %s cannot be both hard and soft", raw))
+				}
+				continue
+			}
+			seen[name] = kind
+			if kind == ScheduleConstraintHard {
+				set.hard = append(set.hard, constraint)
+			} else {
+				set.soft = append(set.soft, constraint)
+			}
+		}
+		return nil
+	}
+	if err := resolve(hard, ScheduleConstraintHard); err != nil {
+		return scheduleConstraintSet{}, err
+	}
+	if err := resolve(soft, ScheduleConstraintSoft); err != nil {
+		return scheduleConstraintSet{}, err
+	}
+	return set, nil
+}
+
+func describeConstraints(registry map[string]ScheduleConstraint) []dto.ScheduleConstraintInfo {
+	result := make([]dto.ScheduleConstraintInfo, 0, len(registry))
+	for _, c := range registry {
+		result = append(result, dto.ScheduleConstraintInfo{
+			Name:        c.Name(),
+			Description: c.Description(),
+			Weight:      c.Weight(),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func subjectLoadKey(subjectID, teacherID string) string {
+	return subjectID + "|" + teacherID
+}
+
+func indexSubjectLoads(loads []dto.SubjectLoadRequest) map[string]dto.SubjectLoadRequest {
+	result := make(map[string]dto.SubjectLoadRequest, len(loads))
+	for _, load := range loads {
+		result[subjectLoadKey(load.SubjectID, load.TeacherID)] = load
+	}
+	return result
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, candidate := range tags {
+		if strings.EqualFold(strings.TrimSpace(candidate), tag) {
+			return true
+		}
+	}
+	return false
+}
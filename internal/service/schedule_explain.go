@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	blockReasonClassSlotOccupied = "CLASS_SLOT_OCCUPIED"
+	blockReasonTeacherUnknown    = "TEACHER_NOT_LOADED"
+	blockReasonTeacherDailyCap   = "TEACHER_DAILY_CAP"
+	blockReasonTeacherWeeklyCap  = "TEACHER_WEEKLY_CAP"
+	blockReasonHardConstraint    = "HARD_CONSTRAINT"
+	blockReasonCrossesDayEnd     = "BLOCK_CROSSES_DAY_END"
+)
+
+// ExplainProposal returns the proposal conflicts together with the blocking checks recorded for each unplaced load.
+func (s *ScheduleGeneratorService) ExplainProposal(ctx context.Context, proposalID string) (*dto.ProposalExplanationResponse, error) {
+	proposal, ok := s.store.Get(proposalID)
+	if !ok {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "proposal not found or expired")
+	}
+	conflicts := proposal.Conflicts
+	if conflicts == nil {
+		conflicts = []dto.ProposalConflict{}
+	}
+	return &dto.ProposalExplanationResponse{
+		ProposalID: proposal.ProposalID,
+		Score:      proposal.Score,
+		Conflicts:  conflicts,
+	}, nil
+}
+
+// explainUnplaced evaluates every day/slot for load and records which checks rejected it. For a
+// block lesson each slot is evaluated as the start of the block, so every period it covers counts.
+func (s *schedulerState) explainUnplaced(load dto.SubjectLoadRequest) *dto.ConflictExplanation {
+	explanation := &dto.ConflictExplanation{
+		SubjectID: load.SubjectID,
+		TeacherID: load.TeacherID,
+		Blockers:  make(map[string]int),
+		Slots:     make([]dto.SlotBlockerDetail, 0),
+	}
+	teacher := s.teacherLoads[load.TeacherID]
+	size := loadBlockSize(load)
+	for _, day := range s.days {
+		for slot := 1; slot <= s.timeSlots; slot++ {
+			explanation.EvaluatedSlots++
+			var reasons, details []string
+
+			last := slot + size - 1
+			if last > s.timeSlots {
+				reasons = append(reasons, blockReasonCrossesDayEnd)
+				details = append(details, fmt.Sprintf("a %d-period block would end after slot %d", size, s.timeSlots))
+				last = s.timeSlots
+			}
+			for period := slot; period <= last; period++ {
+				if occupant, occupied := s.classSlots[slotKey{Day: day, Time: period}]; occupied {
+					reasons = appendReason(reasons, blockReasonClassSlotOccupied)
+					details = append(details, fmt.Sprintf("class already has %s with %s", occupant.SubjectID, occupant.TeacherID))
+				}
+			}
+			if teacher == nil {
+				reasons = append(reasons, blockReasonTeacherUnknown)
+			} else {
+				for period := slot; period <= last; period++ {
+					if reason := teacher.blockedReason(day, period); reason != "" {
+						reasons = appendReason(reasons, reason)
+					}
+				}
+				if teacher.MaxLoadPerDay > 0 && teacher.perDay[day]+size > teacher.MaxLoadPerDay {
+					reasons = append(reasons, blockReasonTeacherDailyCap)
+					details = append(details, fmt.Sprintf("teacher already has %d/%d lessons on this day", teacher.perDay[day], teacher.MaxLoadPerDay))
+				}
+				if teacher.MaxLoadPerWeek > 0 && teacher.weekly+size > teacher.MaxLoadPerWeek {
+					reasons = append(reasons, blockReasonTeacherWeeklyCap)
+					details = append(details, fmt.Sprintf("teacher already has %d/%d lessons this week", teacher.weekly, teacher.MaxLoadPerWeek))
+				}
+			}
+			for _, rule := range s.constraints.hard {
+				if !s.constraintsAllow([]ScheduleConstraint{rule}, load, day, slot) {
+					reasons = append(reasons, blockReasonHardConstraint+":"+rule.Name())
+				}
+			}
+
+			if len(reasons) == 0 {
+				explanation.AvailableSlots++
+				continue
+			}
+			for _, reason := range reasons {
+				explanation.Blockers[reason]++
+			}
+			explanation.Slots = append(explanation.Slots, dto.SlotBlockerDetail{
+				DayOfWeek: day,
+				TimeSlot:  slot,
+				Reasons:   reasons,
+				Detail:    strings.Join(details, "; "),
+			})
+		}
+	}
+	return explanation
+}
+
+func appendReason(reasons []string, reason string) []string {
+	for _, existing := range reasons {
+		if existing == reason {
+			return reasons
+		}
+	}
+	return append(reasons, reason)
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+type scheduleExportSemesterReader interface {
+	FindByID(ctx context.Context, id string) (*models.SemesterSchedule, error)
+}
+
+type scheduleExportSlotReader interface {
+	ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error)
+	ListTeacherTermSlots(ctx context.Context, teacherID models.TeacherID, termID models.TermID, scheduleID string, classID models.ClassID) ([]models.SemesterScheduleClassSlot, error)
+}
+
+type scheduleExportStore interface {
+	Store(jobID, filename string, format models.ReportFormat, payload []byte) (*ExportResult, error)
+}
+
+type scheduleExportJobRecorder interface {
+	Create(ctx context.Context, job *models.ReportJob) error
+}
+
+type timetableRenderer interface {
+	Render(data export.Dataset, title string) ([]byte, error)
+}
+
+// SlotTimeLabeler resolves display labels (e.g. "07:00-07:45") for slot numbers within a term.
+type SlotTimeLabeler interface {
+	SlotLabels(ctx context.Context, termID string) (map[int]string, error)
+}
+
+// StaticSlotLabels is a SlotTimeLabeler backed by a fixed mapping shared by every term.
+type StaticSlotLabels map[int]string
+
+// SlotLabels implements SlotTimeLabeler.
+func (m StaticSlotLabels) SlotLabels(context.Context, string) (map[int]string, error) {
+	return m, nil
+}
+
+// ScheduleExportService renders semester schedules as timetable grids and publishes them via signed URLs.
+type ScheduleExportService struct {
+	semesters scheduleExportSemesterReader
+	slots     scheduleExportSlotReader
+	subjects  ports.SubjectReader
+	teachers  ports.TeacherReader
+	classes   ports.ClassReader
+	labels    SlotTimeLabeler
+	store     scheduleExportStore
+	jobs      scheduleExportJobRecorder
+	pdf       timetableRenderer
+	xlsx      timetableRenderer
+	validator *validator.Validate
+	logger    *zap.Logger
+}
+
+// NewScheduleExportService constructs a ScheduleExportService.
+func NewScheduleExportService(
+	semesters scheduleExportSemesterReader,
+	slots scheduleExportSlotReader,
+	subjects ports.SubjectReader,
+	teachers ports.TeacherReader,
+	classes ports.ClassReader,
+	labels SlotTimeLabeler,
+	store scheduleExportStore,
+	jobs scheduleExportJobRecorder,
+	validate *validator.Validate,
+	logger *zap.Logger,
+) *ScheduleExportService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if labels == nil {
+		labels = StaticSlotLabels{}
+	}
+	return &ScheduleExportService{
+		semesters: semesters,
+		slots:     slots,
+		subjects:  subjects,
+		teachers:  teachers,
+		classes:   classes,
+		labels:    labels,
+		store:     store,
+		jobs:      jobs,
+		pdf:       export.NewPDFExporter(),
+		xlsx:      export.NewXLSXExporter(),
+		validator: validate,
+		logger:    logger,
+	}
+}
+
+// scheduleExportCell is one occupied timetable cell prior to rendering.
+type scheduleExportCell struct {
+	day     int
+	slot    int
+	primary string
+	other   string
+	room    *string
+	// block is the block lesson the cell belongs to, if any.
+	block *lessonBlock
+}
+
+// Export renders the schedule grid in the requested format/view and returns a signed download URL.
+func (s *ScheduleExportService) Export(ctx context.Context, scheduleID string, req dto.ScheduleExportRequest, actorID string) (*dto.ScheduleExportResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule export query")
+	}
+	if req.Format == "" {
+		req.Format = string(models.ReportFormatPDF)
+	}
+	if req.View == "" {
+		req.View = "class"
+	}
+
+	schedule, err := s.semesters.FindByID(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "semester schedule not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load semester schedule")
+	}
+
+	cells, title, err := s.collectCells(ctx, schedule, req)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.labels.SlotLabels(ctx, schedule.TermID.String())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load slot times")
+	}
+	dataset := buildTimetableDataset(cells, labels)
+
+	format := models.ReportFormat(req.Format)
+	var payload []byte
+	switch format {
+	case models.ReportFormatXLSX:
+		payload, err = s.xlsx.Render(dataset, title)
+	default:
+		payload, err = s.pdf.Render(dataset, title)
+	}
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to render schedule export")
+	}
+
+	classID := schedule.ClassID.String()
+	job := &models.ReportJob{
+		ID:   uuid.NewString(),
+		Type: models.ReportTypeSemesterSchedule,
+		Params: models.ReportJobParams{
+			TermID:  schedule.TermID.String(),
+			ClassID: &classID,
+			Format:  format,
+			Extras:  map[string]string{"scheduleId": schedule.ID, "view": req.View, "teacherId": req.TeacherID},
+		},
+		Status:    models.ReportStatusFinished,
+		Progress:  100,
+		CreatedBy: actorID,
+	}
+	filename := fmt.Sprintf("schedule_%s_%s_v%d_%s.%s", req.View, sanitizeFilename(scheduleSubject(schedule, req)), schedule.Version, time.Now().UTC().Format("20060102_150405"), format)
+	result, err := s.store.Store(job.ID, filename, format, payload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store schedule export")
+	}
+	now := time.Now().UTC()
+	job.ResultURL = &result.URL
+	job.FinishedAt = &now
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record schedule export")
+	}
+
+	return &dto.ScheduleExportResponse{
+		URL:       result.URL,
+		Format:    string(format),
+		View:      req.View,
+		ExpiresAt: result.ExpiresAt,
+	}, nil
+}
+
+func (s *ScheduleExportService) collectCells(ctx context.Context, schedule *models.SemesterSchedule, req dto.ScheduleExportRequest) ([]scheduleExportCell, string, error) {
+	names := newScheduleNameCache(s.subjects, s.teachers, s.classes)
+	if req.View == "teacher" {
+		slots, err := s.slots.ListTeacherTermSlots(ctx, models.TeacherID(req.TeacherID), schedule.TermID, schedule.ID, schedule.ClassID)
+		if err != nil {
+			return nil, "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list teacher schedule slots")
+		}
+		// The teacher's blocks are told apart by class, as one subject may be taught to several.
+		periods := make([]blockPeriod, len(slots))
+		for i, slot := range slots {
+			periods[i] = blockPeriod{Day: slot.DayOfWeek, Time: slot.TimeSlot, Size: slot.BlockSize, Lesson: slot.ClassID.String() + "|" + slot.SubjectID.String()}
+		}
+		blocks := findBlocks(periods)
+		cells := make([]scheduleExportCell, 0, len(slots))
+		for _, slot := range slots {
+			cells = append(cells, scheduleExportCell{
+				day:     slot.DayOfWeek,
+				slot:    slot.TimeSlot,
+				primary: names.subject(ctx, slot.SubjectID.String()),
+				other:   names.class(ctx, slot.ClassID.String()),
+				room:    slot.Room,
+				block:   cellBlock(blocks, slot.DayOfWeek, slot.TimeSlot),
+			})
+		}
+		return cells, fmt.Sprintf("Teaching Schedule %s", names.teacher(ctx, req.TeacherID)), nil
+	}
+
+	slots, err := s.slots.ListBySchedule(ctx, schedule.ID)
+	if err != nil {
+		return nil, "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list semester schedule slots")
+	}
+	blocks := semesterSlotBlocks(slots)
+	cells := make([]scheduleExportCell, 0, len(slots))
+	for _, slot := range slots {
+		cells = append(cells, scheduleExportCell{
+			day:     slot.DayOfWeek,
+			slot:    slot.TimeSlot,
+			primary: names.subject(ctx, slot.SubjectID.String()),
+			other:   names.teacher(ctx, slot.TeacherID.String()),
+			room:    slot.Room,
+			block:   cellBlock(blocks, slot.DayOfWeek, slot.TimeSlot),
+		})
+	}
+	return cells, fmt.Sprintf("Class Schedule %s v%d", names.class(ctx, schedule.ClassID.String()), schedule.Version), nil
+}
+
+func cellBlock(blocks map[slotKey]lessonBlock, day, slot int) *lessonBlock {
+	block, ok := blocks[slotKey{Day: day, Time: slot}]
+	if !ok || block.Length <= 1 {
+		return nil
+	}
+	return &block
+}
+
+// buildTimetableDataset lays cells out as one row per slot and one column per school day. The first
+// period of a block lesson names the periods it spans and the rest are marked as continuations.
+func buildTimetableDataset(cells []scheduleExportCell, labels map[int]string) export.Dataset {
+	daySet := map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true}
+	maxSlot := 0
+	grid := make(map[slotKey]string, len(cells))
+	for _, cell := range cells {
+		daySet[cell.day] = true
+		if cell.slot > maxSlot {
+			maxSlot = cell.slot
+		}
+		text := cell.primary
+		if cell.block != nil && cell.slot != cell.block.Start {
+			text += " (cont.)"
+		} else {
+			if cell.other != "" {
+				text += " - " + cell.other
+			}
+			if cell.room != nil && *cell.room != "" {
+				text += " (" + *cell.room + ")"
+			}
+			if cell.block != nil {
+				text += " [slots " + cell.block.Label() + "]"
+			}
+		}
+		key := slotKey{Day: cell.day, Time: cell.slot}
+		if existing, ok := grid[key]; ok {
+			text = existing + " / " + text
+		}
+		grid[key] = text
+	}
+	for slot := range labels {
+		if slot > maxSlot {
+			maxSlot = slot
+		}
+	}
+	days := make([]int, 0, len(daySet))
+	for day := range daySet {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+
+	headers := []string{"Slot", "Time"}
+	for _, day := range days {
+		headers = append(headers, displayDayName(day))
+	}
+	rows := make([]map[string]string, 0, maxSlot)
+	for slot := 1; slot <= maxSlot; slot++ {
+		row := map[string]string{
+			"Slot": fmt.Sprintf("%d", slot),
+			"Time": labels[slot],
+		}
+		for _, day := range days {
+			row[displayDayName(day)] = grid[slotKey{Day: day, Time: slot}]
+		}
+		rows = append(rows, row)
+	}
+	return export.Dataset{Headers: headers, Rows: rows}
+}
+
+func displayDayName(day int) string {
+	name := strings.ToLower(dayIndexToName(day))
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func scheduleSubject(schedule *models.SemesterSchedule, req dto.ScheduleExportRequest) string {
+	if req.View == "teacher" {
+		return req.TeacherID
+	}
+	return schedule.ClassID.String()
+}
+
+// scheduleNameCache resolves display names once per export, falling back to raw IDs.
+type scheduleNameCache struct {
+	subjects ports.SubjectReader
+	teachers ports.TeacherReader
+	classes  ports.ClassReader
+	cache    map[string]string
+}
+
+func newScheduleNameCache(subjects ports.SubjectReader, teachers ports.TeacherReader, classes ports.ClassReader) *scheduleNameCache {
+	return &scheduleNameCache{subjects: subjects, teachers: teachers, classes: classes, cache: make(map[string]string)}
+}
+
+func (c *scheduleNameCache) subject(ctx context.Context, id string) string {
+	return c.lookup("subject:"+id, id, func() (string, error) {
+		if c.subjects == nil {
+			return "", nil
+		}
+		subject, err := c.subjects.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return subject.Name, nil
+	})
+}
+
+func (c *scheduleNameCache) teacher(ctx context.Context, id string) string {
+	return c.lookup("teacher:"+id, id, func() (string, error) {
+		if c.teachers == nil {
+			return "", nil
+		}
+		teacher, err := c.teachers.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return teacher.FullName, nil
+	})
+}
+
+func (c *scheduleNameCache) class(ctx context.Context, id string) string {
+	return c.lookup("class:"+id, id, func() (string, error) {
+		if c.classes == nil {
+			return "", nil
+		}
+		class, err := c.classes.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return class.Name, nil
+	})
+}
+
+func (c *scheduleNameCache) lookup(key, fallback string, load func() (string, error)) string {
+	if name, ok := c.cache[key]; ok {
+		return name
+	}
+	name, err := load()
+	if err != nil || name == "" {
+		name = fallback
+	}
+	c.cache[key] = name
+	return name
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type exportSlotReaderStub struct {
+	classSlots   []models.SemesterScheduleSlot
+	teacherSlots []models.SemesterScheduleClassSlot
+}
+
+func (s *exportSlotReaderStub) ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error) {
+	return s.classSlots, nil
+}
+
+func (s *exportSlotReaderStub) ListTeacherTermSlots(ctx context.Context, teacherID models.TeacherID, termID models.TermID, scheduleID string, classID models.ClassID) ([]models.SemesterScheduleClassSlot, error) {
+	return s.teacherSlots, nil
+}
+
+type exportTeacherLookupStub struct{}
+
+func (exportTeacherLookupStub) FindByID(ctx context.Context, id string) (*models.Teacher, error) {
+	if id == "t1" {
+		return &models.Teacher{ID: id, FullName: "Budi"}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+type exportStoreStub struct {
+	filename string
+	payload  []byte
+}
+
+func (s *exportStoreStub) Store(jobID, filename string, format models.ReportFormat, payload []byte) (*ExportResult, error) {
+	s.filename = filename
+	s.payload = payload
+	return &ExportResult{URL: "https://files.local/export/" + jobID, Format: format, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+type exportJobRecorderStub struct {
+	jobs []models.ReportJob
+}
+
+func (s *exportJobRecorderStub) Create(ctx context.Context, job *models.ReportJob) error {
+	s.jobs = append(s.jobs, *job)
+	return nil
+}
+
+func newScheduleExportFixture() (*ScheduleExportService, *exportStoreStub, *exportJobRecorderStub) {
+	semesters := &semesterScheduleRepoStub{items: []models.SemesterSchedule{{ID: "sched-1", TermID: "term-1", ClassID: "class-1", Version: 2}}}
+	room := "R101"
+	slots := &exportSlotReaderStub{
+		classSlots: []models.SemesterScheduleSlot{
+			{SemesterScheduleID: "sched-1", DayOfWeek: 1, TimeSlot: 1, SubjectID: "math", TeacherID: "t1", Room: &room},
+			{SemesterScheduleID: "sched-1", DayOfWeek: 3, TimeSlot: 2, SubjectID: "bio", TeacherID: "t2"},
+		},
+		teacherSlots: []models.SemesterScheduleClassSlot{
+			{SemesterScheduleSlot: models.SemesterScheduleSlot{DayOfWeek: 2, TimeSlot: 1, SubjectID: "math", TeacherID: "t1"}, ClassID: "class-2"},
+		},
+	}
+	store := &exportStoreStub{}
+	jobs := &exportJobRecorderStub{}
+	labels := StaticSlotLabels{1: "07:00-07:45", 2: "07:45-08:30", 3: "08:30-09:15"}
+	svc := NewScheduleExportService(semesters, slots, subjectLookupStub{subjects: map[string]struct{}{"math": {}, "bio": {}}}, exportTeacherLookupStub{}, classLookupStub{}, labels, store, jobs, nil, nil)
+	return svc, store, jobs
+}
+
+func TestBuildTimetableDatasetClassView(t *testing.T) {
+	room := "Lab"
+	cells := []scheduleExportCell{
+		{day: 1, slot: 1, primary: "Math", other: "Budi"},
+		{day: 6, slot: 2, primary: "Biology", other: "Sari", room: &room},
+	}
+
+	dataset := buildTimetableDataset(cells, map[int]string{1: "07:00-07:45", 3: "08:30-09:15"})
+
+	assert.Equal(t, []string{"Slot", "Time", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}, dataset.Headers)
+	require.Len(t, dataset.Rows, 3)
+	assert.Equal(t, "07:00-07:45", dataset.Rows[0]["Time"])
+	assert.Equal(t, "Math - Budi", dataset.Rows[0]["Monday"])
+	assert.Equal(t, "Biology - Sari (Lab)", dataset.Rows[1]["Saturday"])
+	assert.Equal(t, "", dataset.Rows[1]["Time"])
+	assert.Equal(t, "08:30-09:15", dataset.Rows[2]["Time"])
+}
+
+func TestBuildTimetableDatasetBlockLessons(t *testing.T) {
+	block := &lessonBlock{Day: 2, Start: 3, Length: 2, Size: 2}
+	cells := []scheduleExportCell{
+		{day: 2, slot: 3, primary: "Chemistry", other: "Rina", block: block},
+		{day: 2, slot: 4, primary: "Chemistry", other: "Rina", block: block},
+	}
+
+	dataset := buildTimetableDataset(cells, nil)
+
+	require.Len(t, dataset.Rows, 4)
+	assert.Equal(t, "Chemistry - Rina [slots 3-4]", dataset.Rows[2]["Tuesday"])
+	assert.Equal(t, "Chemistry (cont.)", dataset.Rows[3]["Tuesday"])
+}
+
+func TestScheduleExportServiceExportClassXLSX(t *testing.T) {
+	svc, store, jobs := newScheduleExportFixture()
+
+	resp, err := svc.Export(context.Background(), "sched-1", dto.ScheduleExportRequest{Format: "xlsx"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "xlsx", resp.Format)
+	assert.Equal(t, "class", resp.View)
+	assert.Contains(t, store.filename, "schedule_class_class-1_v2_")
+
+	archive, err := zip.NewReader(bytes.NewReader(store.payload), int64(len(store.payload)))
+	require.NoError(t, err)
+	var sheet []byte
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := file.Open()
+			require.NoError(t, err)
+			sheet, err = io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+		}
+	}
+	assert.Contains(t, string(sheet), "math - Budi (R101)")
+	assert.Contains(t, string(sheet), "07:45-08:30")
+
+	require.Len(t, jobs.jobs, 1)
+	job := jobs.jobs[0]
+	assert.Equal(t, models.ReportTypeSemesterSchedule, job.Type)
+	assert.Equal(t, models.ReportStatusFinished, job.Status)
+	require.NotNil(t, job.ResultURL)
+	assert.Equal(t, resp.URL, *job.ResultURL)
+}
+
+func TestScheduleExportServiceExportTeacherView(t *testing.T) {
+	svc, store, _ := newScheduleExportFixture()
+
+	_, err := svc.Export(context.Background(), "sched-1", dto.ScheduleExportRequest{View: "teacher"}, "admin-1")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	resp, err := svc.Export(context.Background(), "sched-1", dto.ScheduleExportRequest{View: "teacher", TeacherID: "t1"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pdf", resp.Format)
+	assert.True(t, bytes.HasPrefix(store.payload, []byte("%PDF")))
+}
+
+func TestScheduleExportServiceExportNotFound(t *testing.T) {
+	svc, _, _ := newScheduleExportFixture()
+
+	_, err := svc.Export(context.Background(), "missing", dto.ScheduleExportRequest{}, "admin-1")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
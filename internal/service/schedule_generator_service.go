@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,12 +22,14 @@ import (
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type semesterScheduleRepository interface {
 	CreateVersioned(ctx context.Context, exec sqlx.ExtContext, schedule *models.SemesterSchedule) error
-	ListByTermClass(ctx context.Context, termID, classID string) ([]models.SemesterSchedule, error)
+	ListByTermClass(ctx context.Context, termID models.TermID, classID models.ClassID) ([]models.SemesterSchedule, error)
 	FindByID(ctx context.Context, id string) (*models.SemesterSchedule, error)
 	Delete(ctx context.Context, id string) error
 	UpdateStatus(ctx context.Context, exec sqlx.ExtContext, id string, status models.SemesterScheduleStatus, meta types.JSONText) error
@@ -41,44 +44,32 @@ type teacherAssignmentFetcher interface {
 	ListByClassAndTerm(ctx context.Context, classID, termID string) ([]models.TeacherAssignment, error)
 }
 
-type teacherPreferenceFetcher interface {
-	GetByTeacher(ctx context.Context, teacherID string) (*models.TeacherPreference, error)
-}
-
 type scheduleFeeder interface {
 	ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error)
 	ListByClass(ctx context.Context, classID string) ([]models.Schedule, error)
-	FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error)
+	ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error)
 	BulkCreateWithTx(ctx context.Context, tx *sqlx.Tx, schedules []models.Schedule) error
 }
 
-type schedulerClassReader interface {
-	FindByID(ctx context.Context, id string) (*models.Class, error)
-}
-
-type schedulerTermReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-}
-
-type schedulerSubjectReader interface {
-	FindByID(ctx context.Context, id string) (*models.Subject, error)
-}
-
 type txProvider interface {
 	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
 }
 
+type subjectLoadPresetReader interface {
+	FindByID(ctx context.Context, id string) (*models.SubjectLoadPreset, error)
+}
+
 type scheduleConflictChecker interface {
 	Check(ctx context.Context, termID, classID string, slots []dto.ScheduleSlotProposal) ([]models.ScheduleConflict, error)
 }
 
 // ScheduleGeneratorService builds timetable proposals and persists semester schedules.
 type ScheduleGeneratorService struct {
-	terms       schedulerTermReader
-	classes     schedulerClassReader
-	subjects    schedulerSubjectReader
+	terms       ports.TermReader
+	classes     ports.ClassReader
+	subjects    ports.SubjectReader
 	assignments teacherAssignmentFetcher
-	prefs       teacherPreferenceFetcher
+	prefs       ports.TeacherPreferenceReader
 	schedules   scheduleFeeder
 	semesters   semesterScheduleRepository
 	slots       semesterScheduleSlotRepository
@@ -87,20 +78,34 @@ type ScheduleGeneratorService struct {
 	validator   *validator.Validate
 	logger      *zap.Logger
 	store       *proposalStore
+	constraints map[string]ScheduleConstraint
+	events      *DomainEvents
+	presets     subjectLoadPresetReader
+
+	maxOptimizationBudget time.Duration
 }
 
 // ScheduleGeneratorConfig governs generator behaviour.
 type ScheduleGeneratorConfig struct {
 	ProposalTTL time.Duration
+	// Constraints registers the rules selectable via hardConstraints/softConstraints.
+	// Defaults to DefaultScheduleConstraints when nil.
+	Constraints []ScheduleConstraint
+	// MaxOptimizationBudget caps the total annealing time a single request may ask for.
+	MaxOptimizationBudget time.Duration
+	// Events receives schedule.published when a save commits to daily schedules; nil disables it.
+	Events *DomainEvents
+	// Presets resolves presetId in generation payloads; nil rejects payloads naming a preset.
+	Presets subjectLoadPresetReader
 }
 
 // NewScheduleGeneratorService wires scheduler dependencies.
 func NewScheduleGeneratorService(
-	terms schedulerTermReader,
-	classes schedulerClassReader,
-	subjects schedulerSubjectReader,
+	terms ports.TermReader,
+	classes ports.ClassReader,
+	subjects ports.SubjectReader,
 	assignments teacherAssignmentFetcher,
-	prefs teacherPreferenceFetcher,
+	prefs ports.TeacherPreferenceReader,
 	schedules scheduleFeeder,
 	semesters semesterScheduleRepository,
 	slots semesterScheduleSlotRepository,
@@ -119,6 +124,12 @@ func NewScheduleGeneratorService(
 	if cfg.ProposalTTL <= 0 {
 		cfg.ProposalTTL = 30 * time.Minute
 	}
+	if cfg.MaxOptimizationBudget <= 0 {
+		cfg.MaxOptimizationBudget = 10 * time.Second
+	}
+	if cfg.Constraints == nil {
+		cfg.Constraints = DefaultScheduleConstraints()
+	}
 	if conflictChecker == nil && schedules != nil {
 		conflictChecker = &defaultScheduleConflictChecker{repo: schedules}
 	}
@@ -136,15 +147,30 @@ func NewScheduleGeneratorService(
 		validator:   validate,
 		logger:      logger,
 		store:       newProposalStore(cfg.ProposalTTL),
+		constraints: newConstraintRegistry(cfg.Constraints),
+		events:      cfg.Events,
+		presets:     cfg.Presets,
+
+		maxOptimizationBudget: cfg.MaxOptimizationBudget,
 	}
 }
 
+// Constraints lists the constraints that can be selected in generation payloads.
+func (s *ScheduleGeneratorService) Constraints() []dto.ScheduleConstraintInfo {
+	return describeConstraints(s.constraints)
+}
+
 // Generate orchestrates the constraint-based scheduling pipeline.
 func (s *ScheduleGeneratorService) Generate(ctx context.Context, req dto.GenerateScheduleRequest) (*dto.GenerateScheduleResponse, error) {
 	if err := s.validator.Struct(req); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule generation payload")
 	}
-	if err := s.ensureTermAndClass(ctx, req.TermID, req.ClassID); err != nil {
+	class, err := s.ensureTermAndClass(ctx, req.TermID, req.ClassID)
+	if err != nil {
+		return nil, err
+	}
+	constraintSet, err := resolveScheduleConstraints(s.constraints, req.HardConstraints, req.SoftConstraints)
+	if err != nil {
 		return nil, err
 	}
 
@@ -152,14 +178,6 @@ func (s *ScheduleGeneratorService) Generate(ctx context.Context, req dto.Generat
 	if len(days) == 0 {
 		return nil, appErrors.Clone(appErrors.ErrValidation, "days must contain at least one entry between 1-6")
 	}
-	expectedLoad := req.TimeSlotsPerDay * len(days)
-	totalLoad := 0
-	for _, item := range req.SubjectLoads {
-		totalLoad += item.WeeklyCount
-	}
-	if totalLoad != expectedLoad {
-		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subjectLoads weeklyCount (%d) must equal total weekly slots (%d)", totalLoad, expectedLoad))
-	}
 
 	assignments, err := s.assignments.ListByClassAndTerm(ctx, req.ClassID, req.TermID)
 	if err != nil {
@@ -168,6 +186,20 @@ func (s *ScheduleGeneratorService) Generate(ctx context.Context, req dto.Generat
 	if len(assignments) == 0 {
 		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "no teacher assignments defined for this class and term")
 	}
+	if req.PresetID != "" {
+		if req.SubjectLoads, err = s.presetSubjectLoads(ctx, req.PresetID, class, assignments); err != nil {
+			return nil, err
+		}
+	}
+
+	expectedLoad := req.TimeSlotsPerDay * len(days)
+	totalLoad := 0
+	for _, item := range req.SubjectLoads {
+		totalLoad += item.WeeklyCount
+	}
+	if totalLoad != expectedLoad {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subjectLoads weeklyCount (%d) must equal total weekly slots (%d)", totalLoad, expectedLoad))
+	}
 
 	if err := s.ensureSubjectsExist(ctx, req.SubjectLoads); err != nil {
 		return nil, err
@@ -184,166 +216,247 @@ func (s *ScheduleGeneratorService) Generate(ctx context.Context, req dto.Generat
 	}
 
 	state := newSchedulerState(days, req.TimeSlotsPerDay, teacherAvailabilities)
+	state.useConstraints(constraintSet, req.SubjectLoads)
 	conflicts := s.seedSlots(state, req.SubjectLoads)
 	improvements := state.repairGaps(12)
 
-	slots := state.exportSlots()
-	gapPenalty := calculateGapPenalty(days, req.TimeSlotsPerDay, slots)
-	loadPenalty := calculateLoadPenalty(teacherAvailabilities)
-	conflictPenalty := float64(len(conflicts))
-	score := math.Max(0, 100-(conflictPenalty*100+gapPenalty*2+loadPenalty*5))
+	plan := resolveOptimizationPlan(req.Optimization, s.maxOptimizationBudget)
+	requestedAt := time.Now().UTC()
+	proposals := make([]scheduleProposal, 0, plan.candidates)
+	if !plan.enabled {
+		proposals = append(proposals, buildScheduleProposal(req, days, state, conflicts, constraintSet, dto.ScheduleImprovementStats{
+			Iterations: improvements,
+			Algorithm:  ScheduleAlgorithmHeuristic,
+		}, requestedAt))
+	} else {
+		perCandidate := plan.budget / time.Duration(plan.candidates)
+		for i := 0; i < plan.candidates; i++ {
+			rng := rand.New(rand.NewSource(plan.seed + int64(i)))
+			best, steps := state.clone().anneal(ctx, perCandidate, rng)
+			proposals = append(proposals, buildScheduleProposal(req, days, best, conflicts, constraintSet, dto.ScheduleImprovementStats{
+				Iterations:        improvements,
+				OptimizationSteps: steps,
+				Algorithm:         ScheduleAlgorithmAnnealing,
+			}, requestedAt))
+		}
+		sort.SliceStable(proposals, func(i, j int) bool { return proposals[i].Score > proposals[j].Score })
+	}
+
+	var candidates []dto.ScheduleCandidate
+	for i, proposal := range proposals {
+		s.store.Save(proposal)
+		if plan.enabled {
+			candidates = append(candidates, dto.ScheduleCandidate{
+				Rank:       i + 1,
+				ProposalID: proposal.ProposalID,
+				Score:      proposal.Score,
+				Conflicts:  len(proposal.Conflicts),
+				Stats:      proposal.Stats,
+			})
+		}
+	}
+
+	best := proposals[0]
+	resp := &dto.GenerateScheduleResponse{
+		ProposalID: best.ProposalID,
+		Score:      best.Score,
+		Slots:      best.Slots,
+		Conflicts:  best.Conflicts,
+		Stats:      best.Stats,
+		Candidates: candidates,
+	}
+	return resp, nil
+}
 
-	proposal := scheduleProposal{
+// buildScheduleProposal scores the state and packages it as a cacheable proposal.
+func buildScheduleProposal(
+	req dto.GenerateScheduleRequest,
+	days []int,
+	state *schedulerState,
+	conflicts []dto.ProposalConflict,
+	constraintSet scheduleConstraintSet,
+	stats dto.ScheduleImprovementStats,
+	requestedAt time.Time,
+) scheduleProposal {
+	score, stats := scoreSchedule(state, len(conflicts), stats)
+	return scheduleProposal{
 		ProposalID:      uuid.NewString(),
 		TermID:          req.TermID,
 		ClassID:         req.ClassID,
 		Score:           score,
-		Slots:           slots,
+		Slots:           state.exportSlots(),
 		Conflicts:       conflicts,
-		Stats:           dto.ScheduleImprovementStats{Iterations: improvements, GapPenalty: gapPenalty, LoadPenalty: loadPenalty},
+		Stats:           stats,
 		TimeSlotsPerDay: req.TimeSlotsPerDay,
 		Days:            days,
 		SubjectLoads:    req.SubjectLoads,
-		RequestedAt:     time.Now().UTC(),
+		RequestedAt:     requestedAt,
+		Constraints:     constraintSet,
 		Meta: map[string]any{
-			"hardConstraints": req.HardConstraints,
-			"softConstraints": req.SoftConstraints,
+			"constraints": constraintSet.names(),
 		},
 	}
-	s.store.Save(proposal)
+}
 
-	resp := &dto.GenerateScheduleResponse{
-		ProposalID: proposal.ProposalID,
-		Score:      score,
-		Slots:      slots,
-		Conflicts:  conflicts,
-		Stats:      proposal.Stats,
-	}
-	return resp, nil
+// scoreSchedule fills the penalty stats for the state and returns the 0-100 proposal score.
+func scoreSchedule(state *schedulerState, conflictCount int, stats dto.ScheduleImprovementStats) (float64, dto.ScheduleImprovementStats) {
+	tt := state.timetable()
+	stats.GapPenalty = calculateGapPenalty(state.days, state.timeSlots, tt.Slots)
+	stats.LoadPenalty = calculateLoadPenalty(state.teacherLoads)
+	stats.ConstraintPenalty = state.constraints.softPenalty(tt)
+	conflictPenalty := float64(conflictCount)
+	score := math.Max(0, 100-(conflictPenalty*100+stats.GapPenalty*2+stats.LoadPenalty*5+stats.ConstraintPenalty))
+	return score, stats
 }
 
-// Save persists a validated proposal as a semester schedule and optionally daily schedules.
-func (s *ScheduleGeneratorService) Save(ctx context.Context, req dto.SaveScheduleRequest) (string, error) {
+// Save persists a validated proposal as a semester schedule and optionally daily schedules. For
+// dry-run contexts every write and conflict check runs, the transaction is rolled back and the
+// proposal stays cached. Saves that lose a deadlock or serialization race are retried.
+func (s *ScheduleGeneratorService) Save(ctx context.Context, req dto.SaveScheduleRequest) (*dto.SaveScheduleResult, error) {
 	if err := s.validator.Struct(req); err != nil {
-		return "", appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid save schedule payload")
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid save schedule payload")
 	}
 	proposal, ok := s.store.Get(req.ProposalID)
 	if !ok {
-		return "", appErrors.Clone(appErrors.ErrNotFound, "proposal not found or expired")
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "proposal not found or expired")
 	}
 	if len(proposal.Conflicts) > 0 {
-		return "", appErrors.Clone(appErrors.ErrConflict, "proposal contains unresolved conflicts")
+		return nil, appErrors.Clone(appErrors.ErrConflict, "proposal contains unresolved conflicts")
 	}
 	if s.tx == nil {
-		return "", appErrors.Clone(appErrors.ErrInternal, "transaction provider missing")
+		return nil, appErrors.Clone(appErrors.ErrInternal, "transaction provider missing")
 	}
 
-	tx, err := s.tx.BeginTxx(ctx, nil)
-	if err != nil {
-		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to begin transaction")
-	}
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback()
-		}
-	}()
-
 	metaPayload := map[string]any{
 		"score":      proposal.Score,
 		"stats":      proposal.Stats,
 		"generated":  proposal.RequestedAt,
 		"days":       proposal.Days,
 		"timeSlots":  proposal.TimeSlotsPerDay,
-		"algorithm":  "heuristic_v1",
+		"algorithm":  proposalAlgorithm(proposal),
 		"subjectMap": proposal.SubjectLoads,
+		"meta":       proposal.Meta,
 	}
-	metaBytes, marshalErr := json.Marshal(metaPayload)
-	if marshalErr != nil {
-		err = appErrors.Wrap(marshalErr, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to encode schedule metadata")
-		return "", err
-	}
-
-	record := &models.SemesterSchedule{
-		TermID:  proposal.TermID,
-		ClassID: proposal.ClassID,
-		Status:  models.SemesterScheduleStatusDraft,
-		Meta:    types.JSONText(metaBytes),
-	}
-
-	if err = s.semesters.CreateVersioned(ctx, tx, record); err != nil {
-		err = appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create semester schedule")
-		return "", err
-	}
-
-	slotModels := make([]models.SemesterScheduleSlot, 0, len(proposal.Slots))
-	for _, slot := range proposal.Slots {
-		slotModels = append(slotModels, models.SemesterScheduleSlot{
-			SemesterScheduleID: record.ID,
-			DayOfWeek:          slot.DayOfWeek,
-			TimeSlot:           slot.TimeSlot,
-			SubjectID:          slot.SubjectID,
-			TeacherID:          slot.TeacherID,
-			Room:               slot.Room,
-		})
-	}
-
-	if err = s.slots.UpsertBatch(ctx, tx, slotModels); err != nil {
-		err = appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to persist semester schedule slots")
-		return "", err
-	}
-
-	if req.CommitToDaily {
-		if s.conflicts == nil {
-			err = appErrors.Clone(appErrors.ErrInternal, "schedule conflict checker unavailable")
-			return "", err
-		}
-		conflicts, conflictErr := s.conflicts.Check(ctx, proposal.TermID, proposal.ClassID, proposal.Slots)
-		if conflictErr != nil {
-			err = conflictErr
-			return "", err
+	metaBytes, err := json.Marshal(metaPayload)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to encode schedule metadata")
+	}
+
+	var record *models.SemesterSchedule
+	var slotModels []models.SemesterScheduleSlot
+	err = database.WithTx(ctx, s.tx, func(tx *sqlx.Tx) error {
+		// A retried attempt starts over, so the version is assigned afresh.
+		record = &models.SemesterSchedule{
+			TermID:  models.TermID(proposal.TermID),
+			ClassID: models.ClassID(proposal.ClassID),
+			Status:  models.SemesterScheduleStatusDraft,
+			Meta:    types.JSONText(metaBytes),
 		}
-		if len(conflicts) > 0 {
-			err = appErrors.Wrap(&models.ScheduleConflictError{Type: "CONFLICT", Message: "detected conflicts when committing to daily schedules", Errors: conflicts}, appErrors.ErrConflict.Code, appErrors.ErrConflict.Status, "conflict detected")
-			return "", err
+		if err := s.semesters.CreateVersioned(ctx, tx, record); err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create semester schedule")
 		}
 
-		daily := make([]models.Schedule, 0, len(proposal.Slots))
+		slotModels = make([]models.SemesterScheduleSlot, 0, len(proposal.Slots))
 		for _, slot := range proposal.Slots {
-			daily = append(daily, models.Schedule{
-				TermID:    proposal.TermID,
-				ClassID:   proposal.ClassID,
-				SubjectID: slot.SubjectID,
-				TeacherID: slot.TeacherID,
-				DayOfWeek: dayIndexToName(slot.DayOfWeek),
-				TimeSlot:  strconv.Itoa(slot.TimeSlot),
-				Room:      slotRoomValue(slot),
+			slotModels = append(slotModels, models.SemesterScheduleSlot{
+				SemesterScheduleID: record.ID,
+				DayOfWeek:          slot.DayOfWeek,
+				TimeSlot:           slot.TimeSlot,
+				SubjectID:          models.SubjectID(slot.SubjectID),
+				TeacherID:          models.TeacherID(slot.TeacherID),
+				Room:               slot.Room,
+				BlockSize:          slot.BlockSize,
 			})
 		}
-		if err = s.schedules.BulkCreateWithTx(ctx, tx, daily); err != nil {
-			err = appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to commit daily schedules")
-			return "", err
+		if err := s.slots.UpsertBatch(ctx, tx, slotModels); err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to persist semester schedule slots")
 		}
-		if err = s.semesters.UpdateStatus(ctx, tx, record.ID, models.SemesterScheduleStatusPublished, nil); err != nil {
-			err = appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update schedule status")
-			return "", err
+
+		if req.CommitToDaily {
+			return s.commitToDaily(ctx, tx, proposal, record, len(slotModels))
 		}
+		return nil
+	})
+	if err != nil {
+		var appErr *appErrors.Error
+		if errors.As(err, &appErr) {
+			return nil, appErr
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to commit schedule transaction")
 	}
 
-	if err = tx.Commit(); err != nil {
-		err = appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to commit schedule transaction")
-		return "", err
+	result := &dto.SaveScheduleResult{
+		ScheduleID: record.ID,
+		Version:    record.Version,
+		Status:     string(record.Status),
+		Slots:      len(slotModels),
+		DryRun:     database.IsDryRun(ctx),
+	}
+	if req.CommitToDaily {
+		result.Status = string(models.SemesterScheduleStatusPublished)
+		result.DailySchedules = len(proposal.Slots)
+	}
+	if !result.DryRun {
+		s.store.Delete(req.ProposalID)
+	}
+	return result, nil
+}
+
+// commitToDaily copies a saved proposal into the daily schedules and publishes it, failing when the
+// slots clash with the term's other schedules.
+func (s *ScheduleGeneratorService) commitToDaily(ctx context.Context, tx *sqlx.Tx, proposal scheduleProposal, record *models.SemesterSchedule, slots int) error {
+	if s.conflicts == nil {
+		return appErrors.Clone(appErrors.ErrInternal, "schedule conflict checker unavailable")
+	}
+	conflicts, err := s.conflicts.Check(ctx, proposal.TermID, proposal.ClassID, proposal.Slots)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return appErrors.Wrap(&models.ScheduleConflictError{Type: "CONFLICT", Message: "detected conflicts when committing to daily schedules", Errors: conflicts}, appErrors.ErrConflict.Code, appErrors.ErrConflict.Status, "conflict detected")
 	}
 
-	s.store.Delete(req.ProposalID)
-	return record.ID, nil
+	daily := make([]models.Schedule, 0, len(proposal.Slots))
+	for _, slot := range proposal.Slots {
+		daily = append(daily, models.Schedule{
+			TermID:    proposal.TermID,
+			ClassID:   proposal.ClassID,
+			SubjectID: slot.SubjectID,
+			TeacherID: slot.TeacherID,
+			DayOfWeek: dayIndexToName(slot.DayOfWeek),
+			TimeSlot:  strconv.Itoa(slot.TimeSlot),
+			Room:      slotRoomValue(slot),
+		})
+	}
+	if err := s.schedules.BulkCreateWithTx(ctx, tx, daily); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to commit daily schedules")
+	}
+	if err := s.semesters.UpdateStatus(ctx, tx, record.ID, models.SemesterScheduleStatusPublished, nil); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update schedule status")
+	}
+	event, err := newDomainEvent(models.DomainEventSchedulePublished, record.ID, models.SchedulePublishedEvent{
+		ScheduleID: record.ID,
+		TermID:     record.TermID.String(),
+		ClassID:    record.ClassID.String(),
+		Version:    record.Version,
+		Slots:      slots,
+	})
+	if err == nil {
+		err = s.events.Enqueue(ctx, tx, event)
+	}
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record schedule event")
+	}
+	return nil
 }
 
 // List returns semester schedules for a class-term tuple.
 func (s *ScheduleGeneratorService) List(ctx context.Context, query dto.SemesterScheduleQuery) ([]models.SemesterSchedule, error) {
-	if query.TermID == "" || query.ClassID == "" {
+	termID, classID := models.TermID(query.TermID), models.ClassID(query.ClassID)
+	if termID.Validate() != nil || classID.Validate() != nil {
 		return nil, appErrors.Clone(appErrors.ErrValidation, "termId and classId are required")
 	}
-	list, err := s.semesters.ListByTermClass(ctx, query.TermID, query.ClassID)
+	list, err := s.semesters.ListByTermClass(ctx, termID, classID)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list semester schedules")
 	}
@@ -389,24 +502,76 @@ func (s *ScheduleGeneratorService) Delete(ctx context.Context, scheduleID string
 	return nil
 }
 
-func (s *ScheduleGeneratorService) ensureTermAndClass(ctx context.Context, termID, classID string) error {
+// ensureTermAndClass checks both exist and returns the class; the class is nil when no class reader
+// is configured.
+func (s *ScheduleGeneratorService) ensureTermAndClass(ctx context.Context, termID, classID string) (*models.Class, error) {
 	if s.terms != nil {
 		if _, err := s.terms.FindByID(ctx, termID); err != nil {
 			if err == sql.ErrNoRows {
-				return appErrors.Clone(appErrors.ErrNotFound, "term not found")
+				return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
 			}
-			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
 		}
 	}
-	if s.classes != nil {
-		if _, err := s.classes.FindByID(ctx, classID); err != nil {
-			if err == sql.ErrNoRows {
-				return appErrors.Clone(appErrors.ErrNotFound, "class not found")
-			}
-			return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class")
+	if s.classes == nil {
+		return nil, nil
+	}
+	class, err := s.classes.FindByID(ctx, classID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "class not found")
 		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load class")
 	}
-	return nil
+	return class, nil
+}
+
+// presetSubjectLoads expands a stored preset into subject loads for the class. The preset must
+// target the class's grade (and track, when set) and every subject needs exactly one teacher
+// assigned in the class and term.
+func (s *ScheduleGeneratorService) presetSubjectLoads(ctx context.Context, presetID string, class *models.Class, assignments []models.TeacherAssignment) ([]dto.SubjectLoadRequest, error) {
+	if s.presets == nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "subject load presets are not available")
+	}
+	preset, err := s.presets.FindByID(ctx, presetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "subject load preset not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load subject load preset")
+	}
+	if class != nil {
+		if !strings.EqualFold(class.Grade, preset.Grade) || (preset.Track != nil && !strings.EqualFold(class.Track, *preset.Track)) {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("preset %s does not apply to class %s", preset.Name, class.Name))
+		}
+	}
+	var items []models.SubjectLoadPresetItem
+	if err := json.Unmarshal(preset.Loads, &items); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to read subject load preset")
+	}
+
+	teachers := make(map[string][]string)
+	for _, assignment := range assignments {
+		teachers[assignment.SubjectID] = append(teachers[assignment.SubjectID], assignment.TeacherID)
+	}
+	loads := make([]dto.SubjectLoadRequest, 0, len(items))
+	for _, item := range items {
+		switch assigned := teachers[item.SubjectID]; len(assigned) {
+		case 0:
+			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, fmt.Sprintf("no teacher assigned to subject %s in this class and term", item.SubjectID))
+		case 1:
+			loads = append(loads, dto.SubjectLoadRequest{
+				SubjectID:   item.SubjectID,
+				TeacherID:   assigned[0],
+				WeeklyCount: item.WeeklyCount,
+				BlockSize:   item.BlockSize,
+				Difficulty:  item.Difficulty,
+			})
+		default:
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s has several teachers in this class; send subjectLoads instead of presetId", item.SubjectID))
+		}
+	}
+	return loads, nil
 }
 
 func (s *ScheduleGeneratorService) ensureSubjectsExist(ctx context.Context, loads []dto.SubjectLoadRequest) error {
@@ -468,7 +633,7 @@ func (s *ScheduleGeneratorService) buildTeacherAvailability(
 						continue
 					}
 					for _, slot := range expandTimeRange(window.TimeRange) {
-						availability.Block(day, slot)
+						availability.Block(day, slot, blockReasonUnavailableWindow)
 					}
 				}
 			}
@@ -488,7 +653,7 @@ func (s *ScheduleGeneratorService) buildTeacherAvailability(
 				if day == 0 || slot == 0 {
 					continue
 				}
-				availability.Block(day, slot)
+				availability.Block(day, slot, blockReasonExistingSchedule)
 			}
 		}
 		result[teacherID] = availability
@@ -500,7 +665,21 @@ func (s *ScheduleGeneratorService) seedSlots(state *schedulerState, loads []dto.
 	conflicts := make([]dto.ProposalConflict, 0)
 	sorted := make([]dto.SubjectLoadRequest, len(loads))
 	copy(sorted, loads)
-	sort.Slice(sorted, func(i, j int) bool {
+	// Loads restricted by hard constraints are seeded first so they are not starved of slots.
+	feasible := make(map[string]int, len(sorted))
+	for _, load := range sorted {
+		feasible[subjectLoadKey(load.SubjectID, load.TeacherID)] = state.feasiblePlacements(load)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		fi := feasible[subjectLoadKey(sorted[i].SubjectID, sorted[i].TeacherID)]
+		fj := feasible[subjectLoadKey(sorted[j].SubjectID, sorted[j].TeacherID)]
+		if fi != fj {
+			return fi < fj
+		}
+		// Long blocks need free runs of periods, which fill up quickly.
+		if bi, bj := loadBlockSize(sorted[i]), loadBlockSize(sorted[j]); bi != bj {
+			return bi > bj
+		}
 		if sorted[i].Difficulty == sorted[j].Difficulty {
 			return sorted[i].WeeklyCount > sorted[j].WeeklyCount
 		}
@@ -508,23 +687,45 @@ func (s *ScheduleGeneratorService) seedSlots(state *schedulerState, loads []dto.
 	})
 
 	for _, load := range sorted {
-		for i := 0; i < load.WeeklyCount; i++ {
+		var explanation *dto.ConflictExplanation
+		for i := 0; i < load.WeeklyCount/loadBlockSize(load); i++ {
 			if state.Assign(load) {
 				continue
 			}
-			conflicts = append(conflicts, dto.ProposalConflict{
-				Type:    "UNFULFILLED_LOAD",
-				Message: fmt.Sprintf("unable to schedule subject %s for teacher %s", load.SubjectID, load.TeacherID),
-				Meta: map[string]any{
-					"subjectId": load.SubjectID,
-					"teacherId": load.TeacherID,
-				},
-			})
+			if explanation == nil {
+				explanation = state.explainUnplaced(load)
+			}
+			conflicts = append(conflicts, unfulfilledLoadConflict(load, explanation))
 		}
 	}
 	return conflicts
 }
 
+// unfulfilledLoadConflict reports one lesson of load, i.e. one block of periods, that could not be
+// placed.
+func unfulfilledLoadConflict(load dto.SubjectLoadRequest, explanation *dto.ConflictExplanation) dto.ProposalConflict {
+	meta := map[string]any{
+		"subjectId": load.SubjectID,
+		"teacherId": load.TeacherID,
+	}
+	if size := loadBlockSize(load); size > 1 {
+		meta["blockSize"] = size
+	}
+	return dto.ProposalConflict{
+		Type:        "UNFULFILLED_LOAD",
+		Message:     fmt.Sprintf("unable to schedule subject %s for teacher %s", load.SubjectID, load.TeacherID),
+		Meta:        meta,
+		Explanation: explanation,
+	}
+}
+
+func proposalAlgorithm(proposal scheduleProposal) string {
+	if proposal.Stats.Algorithm == "" {
+		return ScheduleAlgorithmHeuristic
+	}
+	return proposal.Stats.Algorithm
+}
+
 func mapAssignments(items []models.TeacherAssignment) map[string]map[string]bool {
 	result := make(map[string]map[string]bool)
 	for _, item := range items {
@@ -541,6 +742,9 @@ func validateSubjectLoads(loads []dto.SubjectLoadRequest, assignments map[string
 		if load.WeeklyCount <= 0 {
 			return appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s weeklyCount must be > 0", load.SubjectID))
 		}
+		if load.WeeklyCount%loadBlockSize(load) != 0 {
+			return appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s weeklyCount must be a multiple of blockSize %d", load.SubjectID, load.BlockSize))
+		}
 		if load.SubjectID == "" || load.TeacherID == "" {
 			return appErrors.Clone(appErrors.ErrValidation, "subjectId and teacherId are required for subjectLoads")
 		}
@@ -567,6 +771,7 @@ type scheduleProposal struct {
 	Days            []int
 	SubjectLoads    []dto.SubjectLoadRequest
 	RequestedAt     time.Time
+	Constraints     scheduleConstraintSet
 	Meta            map[string]any
 }
 
@@ -623,6 +828,8 @@ type schedulerState struct {
 	dayLoad        map[int]int
 	teacherLoads   map[string]*teacherAvailability
 	preferredCache map[string][]int
+	constraints    scheduleConstraintSet
+	loadIndex      map[string]dto.SubjectLoadRequest
 }
 
 func newSchedulerState(days []int, timeSlots int, loads map[string]*teacherAvailability) *schedulerState {
@@ -636,6 +843,11 @@ func newSchedulerState(days []int, timeSlots int, loads map[string]*teacherAvail
 	}
 }
 
+func (s *schedulerState) useConstraints(set scheduleConstraintSet, loads []dto.SubjectLoadRequest) {
+	s.constraints = set
+	s.loadIndex = indexSubjectLoads(loads)
+}
+
 func (s *schedulerState) Assign(load dto.SubjectLoadRequest) bool {
 	dayOrder := make([]int, len(s.days))
 	copy(dayOrder, s.days)
@@ -643,15 +855,82 @@ func (s *schedulerState) Assign(load dto.SubjectLoadRequest) bool {
 		return s.dayLoad[dayOrder[i]] < s.dayLoad[dayOrder[j]]
 	})
 
+	// Try to honour soft constraints first, then fall back to hard constraints only.
+	passes := [][]ScheduleConstraint{s.constraints.hard}
+	if len(s.constraints.soft) > 0 {
+		all := append(append([]ScheduleConstraint{}, s.constraints.hard...), s.constraints.soft...)
+		passes = [][]ScheduleConstraint{all, s.constraints.hard}
+	}
+
+	// A block starts at a candidate time; its later periods follow it on the same day.
+	size := loadBlockSize(load)
 	candidateTimes := s.candidateTimes(load)
-	for _, day := range dayOrder {
-		for _, slot := range candidateTimes {
-			if s.canPlace(load.TeacherID, day, slot) {
-				s.place(load, day, slot)
-				return true
+	for _, rules := range passes {
+		for _, day := range dayOrder {
+			for _, slot := range candidateTimes {
+				if s.canPlaceRun(load.TeacherID, day, slot, size) && s.constraintsAllow(rules, load, day, slot) {
+					s.place(load, day, slot)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// feasiblePlacements counts the empty-timetable positions permitted by hard constraints.
+func (s *schedulerState) feasiblePlacements(load dto.SubjectLoadRequest) int {
+	if len(s.constraints.hard) == 0 {
+		return 0
+	}
+	count := 0
+	for _, day := range s.days {
+		for slot := 1; slot+loadBlockSize(load)-1 <= s.timeSlots; slot++ {
+			if s.constraintsAllow(s.constraints.hard, load, day, slot) {
+				count++
 			}
 		}
 	}
+	return count
+}
+
+// constraintsAllow reports whether placing a lesson of load starting at day/slot keeps every
+// rule's violation count unchanged.
+func (s *schedulerState) constraintsAllow(rules []ScheduleConstraint, load dto.SubjectLoadRequest, day, slot int) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	before := s.timetable()
+	after := before
+	after.Slots = append([]dto.ScheduleSlotProposal{}, before.Slots...)
+	size := loadBlockSize(load)
+	for i := 0; i < size; i++ {
+		after.Slots = append(after.Slots, dto.ScheduleSlotProposal{
+			DayOfWeek: day,
+			TimeSlot:  slot + i,
+			SubjectID: load.SubjectID,
+			TeacherID: load.TeacherID,
+			BlockSize: slotBlockSize(size),
+		})
+	}
+	return !violationsIncrease(rules, before, after)
+}
+
+func (s *schedulerState) timetable() ScheduleTimetable {
+	return ScheduleTimetable{
+		Days:            s.days,
+		TimeSlotsPerDay: s.timeSlots,
+		Slots:           s.exportSlots(),
+		Loads:           s.loadIndex,
+	}
+}
+
+func violationsIncrease(rules []ScheduleConstraint, before, after ScheduleTimetable) bool {
+	for _, rule := range rules {
+		if rule.Violations(after) > rule.Violations(before) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -675,30 +954,107 @@ func (s *schedulerState) candidateTimes(load dto.SubjectLoadRequest) []int {
 }
 
 func (s *schedulerState) canPlace(teacherID string, day, slot int) bool {
-	if day < 1 || slot < 1 || slot > s.timeSlots {
+	return s.canPlaceRun(teacherID, day, slot, 1)
+}
+
+// canPlaceRun reports whether size consecutive periods from slot are free for the class and the
+// teacher. A run never crosses the end of the day.
+func (s *schedulerState) canPlaceRun(teacherID string, day, slot, size int) bool {
+	if day < 1 || slot < 1 || slot+size-1 > s.timeSlots {
 		return false
 	}
-	key := slotKey{Day: day, Time: slot}
-	if _, exists := s.classSlots[key]; exists {
-		return false
+	for i := 0; i < size; i++ {
+		if _, exists := s.classSlots[slotKey{Day: day, Time: slot + i}]; exists {
+			return false
+		}
 	}
 	teacher := s.teacherLoads[teacherID]
 	if teacher == nil {
 		return false
 	}
-	return teacher.CanTeach(day, slot)
+	return teacher.CanTeachRun(day, slot, size)
 }
 
+// place puts one lesson of load, all of its block, from day/slot on.
 func (s *schedulerState) place(load dto.SubjectLoadRequest, day, slot int) {
-	key := slotKey{Day: day, Time: slot}
-	s.classSlots[key] = dto.ScheduleSlotProposal{
-		DayOfWeek: day,
-		TimeSlot:  slot,
-		SubjectID: load.SubjectID,
-		TeacherID: load.TeacherID,
+	size := loadBlockSize(load)
+	for i := 0; i < size; i++ {
+		s.put(dto.ScheduleSlotProposal{
+			SubjectID: load.SubjectID,
+			TeacherID: load.TeacherID,
+			BlockSize: slotBlockSize(size),
+		}, slotKey{Day: day, Time: slot + i})
+	}
+}
+
+// lessonAt returns the periods of the lesson at key: the whole block for block lessons, key alone
+// for single periods and nothing for an empty period.
+func (s *schedulerState) lessonAt(key slotKey) []slotKey {
+	slot, ok := s.classSlots[key]
+	if !ok {
+		return nil
+	}
+	if slot.BlockSize <= 1 {
+		return []slotKey{key}
 	}
-	s.teacherLoads[load.TeacherID].Reserve(day, slot)
-	s.dayLoad[day]++
+	if block, ok := proposalBlocks(s.exportSlots())[key]; ok {
+		return block.Keys()
+	}
+	return []slotKey{key}
+}
+
+// relocate moves the consecutive periods of lesson so they start at day/slot. Targets must be free
+// unless they belong to lesson itself. When check is set the move is rejected if it breaks teacher
+// availability or increases hard constraint violations; a rejected move leaves the state unchanged.
+func (s *schedulerState) relocate(lesson []slotKey, day, slot int, check bool) bool {
+	if len(lesson) == 0 || slot < 1 || slot+len(lesson)-1 > s.timeSlots {
+		return false
+	}
+	own := make(map[slotKey]bool, len(lesson))
+	for _, key := range lesson {
+		own[key] = true
+	}
+	targets := make([]slotKey, len(lesson))
+	for i := range lesson {
+		targets[i] = slotKey{Day: day, Time: slot + i}
+		if _, taken := s.classSlots[targets[i]]; taken && !own[targets[i]] {
+			return false
+		}
+	}
+	var before ScheduleTimetable
+	checkHard := check && len(s.constraints.hard) > 0
+	if checkHard {
+		before = s.timetable()
+	}
+
+	periods := make([]dto.ScheduleSlotProposal, len(lesson))
+	for i, key := range lesson {
+		periods[i] = s.classSlots[key]
+		s.lift(key)
+	}
+	ok := true
+	placed := 0
+	for i, target := range targets {
+		if check && !s.canPlace(periods[i].TeacherID, target.Day, target.Time) {
+			ok = false
+			break
+		}
+		s.put(periods[i], target)
+		placed++
+	}
+	if ok && checkHard && violationsIncrease(s.constraints.hard, before, s.timetable()) {
+		ok = false
+	}
+	if ok {
+		return true
+	}
+	for _, target := range targets[:placed] {
+		s.lift(target)
+	}
+	for i, key := range lesson {
+		s.put(periods[i], key)
+	}
+	return false
 }
 
 func (s *schedulerState) repairGaps(maxIterations int) int {
@@ -716,10 +1072,8 @@ func (s *schedulerState) repairGaps(maxIterations int) int {
 				if next-current <= 1 {
 					continue
 				}
-				target := current + 1
-				slot := s.classSlots[slotKey{Day: day, Time: next}]
-				if s.canPlace(slot.TeacherID, day, target) {
-					s.moveSlot(day, next, target)
+				// next starts its lesson, so a block lesson moves up as a whole.
+				if s.relocate(s.lessonAt(slotKey{Day: day, Time: next}), day, current+1, true) {
 					moved = true
 					break
 				}
@@ -747,17 +1101,6 @@ func (s *schedulerState) timesForDay(day int) []int {
 	return times
 }
 
-func (s *schedulerState) moveSlot(day, fromSlot, toSlot int) {
-	key := slotKey{Day: day, Time: fromSlot}
-	slot := s.classSlots[key]
-	delete(s.classSlots, key)
-	s.teacherLoads[slot.TeacherID].Release(day, fromSlot)
-
-	slot.TimeSlot = toSlot
-	s.classSlots[slotKey{Day: day, Time: toSlot}] = slot
-	s.teacherLoads[slot.TeacherID].Reserve(day, toSlot)
-}
-
 func (s *schedulerState) exportSlots() []dto.ScheduleSlotProposal {
 	slots := make([]dto.ScheduleSlotProposal, 0, len(s.classSlots))
 	for _, slot := range s.classSlots {
@@ -779,41 +1122,61 @@ type teacherAvailability struct {
 	MaxLoadPerWeek int
 	perDay         map[int]int
 	weekly         int
-	blocked        map[int]map[int]bool
+	blocked        map[int]map[int]string
 	assigned       map[int]map[int]bool
 }
 
+const (
+	blockReasonUnavailableWindow = "TEACHER_UNAVAILABLE_WINDOW"
+	blockReasonExistingSchedule  = "TEACHER_EXISTING_SCHEDULE"
+)
+
 func newTeacherAvailability() *teacherAvailability {
 	return &teacherAvailability{
 		perDay:   make(map[int]int),
-		blocked:  make(map[int]map[int]bool),
+		blocked:  make(map[int]map[int]string),
 		assigned: make(map[int]map[int]bool),
 	}
 }
 
-func (t *teacherAvailability) Block(day, slot int) {
+// Block marks a slot as unusable; the first recorded reason is kept for explanations.
+func (t *teacherAvailability) Block(day, slot int, reason string) {
 	if t.blocked[day] == nil {
-		t.blocked[day] = make(map[int]bool)
+		t.blocked[day] = make(map[int]string)
+	}
+	if t.blocked[day][slot] == "" {
+		t.blocked[day][slot] = reason
 	}
-	t.blocked[day][slot] = true
 }
 
 func (t *teacherAvailability) CanTeach(day, slot int) bool {
-	if t.blocked[day] != nil && t.blocked[day][slot] {
-		return false
-	}
-	if t.assigned[day] != nil && t.assigned[day][slot] {
-		return false
+	return t.CanTeachRun(day, slot, 1)
+}
+
+// CanTeachRun reports whether the teacher is free for size consecutive periods from slot without
+// exceeding the daily or weekly load.
+func (t *teacherAvailability) CanTeachRun(day, slot, size int) bool {
+	for i := 0; i < size; i++ {
+		if t.blockedReason(day, slot+i) != "" {
+			return false
+		}
+		if t.assigned[day] != nil && t.assigned[day][slot+i] {
+			return false
+		}
 	}
-	if t.MaxLoadPerDay > 0 && t.perDay[day] >= t.MaxLoadPerDay {
+	if t.MaxLoadPerDay > 0 && t.perDay[day]+size > t.MaxLoadPerDay {
 		return false
 	}
-	if t.MaxLoadPerWeek > 0 && t.weekly >= t.MaxLoadPerWeek {
+	if t.MaxLoadPerWeek > 0 && t.weekly+size > t.MaxLoadPerWeek {
 		return false
 	}
 	return true
 }
 
+func (t *teacherAvailability) blockedReason(day, slot int) string {
+	return t.blocked[day][slot]
+}
+
 func (t *teacherAvailability) Reserve(day, slot int) {
 	if t.assigned[day] == nil {
 		t.assigned[day] = make(map[int]bool)
@@ -970,54 +1333,53 @@ type defaultScheduleConflictChecker struct {
 	repo scheduleFeeder
 }
 
+// Check loads the term's timetable once and matches every proposed slot against it in memory. A
+// conflict on any period of a block lesson names the whole block, since the block moves as one.
 func (d *defaultScheduleConflictChecker) Check(ctx context.Context, termID, classID string, slots []dto.ScheduleSlotProposal) ([]models.ScheduleConflict, error) {
+	existing, err := d.repo.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check conflicts")
+	}
+	bySlot := make(map[string][]models.Schedule, len(existing))
+	for _, sched := range existing {
+		key := sched.DayOfWeek + "|" + sched.TimeSlot
+		bySlot[key] = append(bySlot[key], sched)
+	}
+
+	blocks := proposalBlocks(slots)
 	var conflicts []models.ScheduleConflict
 	for _, slot := range slots {
-		existing, err := d.repo.FindConflicts(ctx, termID, dayIndexToName(slot.DayOfWeek), strconv.Itoa(slot.TimeSlot))
-		if err != nil {
-			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check conflicts")
-		}
-		for _, sched := range existing {
+		start := len(conflicts)
+		for _, sched := range bySlot[dayIndexToName(slot.DayOfWeek)+"|"+strconv.Itoa(slot.TimeSlot)] {
 			if sched.ClassID == classID {
-				conflicts = append(conflicts, models.ScheduleConflict{
-					ScheduleID: sched.ID,
-					TermID:     sched.TermID,
-					ClassID:    sched.ClassID,
-					SubjectID:  sched.SubjectID,
-					TeacherID:  sched.TeacherID,
-					DayOfWeek:  sched.DayOfWeek,
-					TimeSlot:   sched.TimeSlot,
-					Room:       sched.Room,
-					Dimension:  "CLASS",
-				})
+				conflicts = append(conflicts, scheduleConflictFor(sched, "CLASS"))
 			}
 			if sched.TeacherID == slot.TeacherID {
-				conflicts = append(conflicts, models.ScheduleConflict{
-					ScheduleID: sched.ID,
-					TermID:     sched.TermID,
-					ClassID:    sched.ClassID,
-					SubjectID:  sched.SubjectID,
-					TeacherID:  sched.TeacherID,
-					DayOfWeek:  sched.DayOfWeek,
-					TimeSlot:   sched.TimeSlot,
-					Room:       sched.Room,
-					Dimension:  "TEACHER",
-				})
+				conflicts = append(conflicts, scheduleConflictFor(sched, "TEACHER"))
 			}
 			if sched.Room != "" && slot.Room != nil && *slot.Room != "" && sched.Room == *slot.Room {
-				conflicts = append(conflicts, models.ScheduleConflict{
-					ScheduleID: sched.ID,
-					TermID:     sched.TermID,
-					ClassID:    sched.ClassID,
-					SubjectID:  sched.SubjectID,
-					TeacherID:  sched.TeacherID,
-					DayOfWeek:  sched.DayOfWeek,
-					TimeSlot:   sched.TimeSlot,
-					Room:       sched.Room,
-					Dimension:  "ROOM",
-				})
+				conflicts = append(conflicts, scheduleConflictFor(sched, "ROOM"))
+			}
+		}
+		if block, ok := blocks[slotKey{Day: slot.DayOfWeek, Time: slot.TimeSlot}]; ok {
+			for i := start; i < len(conflicts); i++ {
+				conflicts[i].BlockSlots = block.Label()
 			}
 		}
 	}
 	return conflicts, nil
 }
+
+func scheduleConflictFor(sched models.Schedule, dimension string) models.ScheduleConflict {
+	return models.ScheduleConflict{
+		ScheduleID: sched.ID,
+		TermID:     sched.TermID,
+		ClassID:    sched.ClassID,
+		SubjectID:  sched.SubjectID,
+		TeacherID:  sched.TeacherID,
+		DayOfWeek:  sched.DayOfWeek,
+		TimeSlot:   sched.TimeSlot,
+		Room:       sched.Room,
+		Dimension:  dimension,
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/dto"
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/pkg/database"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
@@ -84,12 +86,64 @@ func TestScheduleGeneratorServiceSaveDraft(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectCommit()
 
-	id, err := service.Save(context.Background(), dto.SaveScheduleRequest{ProposalID: resp.ProposalID})
+	result, err := service.Save(context.Background(), dto.SaveScheduleRequest{ProposalID: resp.ProposalID})
 	require.NoError(t, err)
-	assert.NotEmpty(t, id)
+	assert.NotEmpty(t, result.ScheduleID)
+	assert.Equal(t, 4, result.Slots)
+	assert.False(t, result.DryRun)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestScheduleGeneratorServiceSaveRetriesDeadlocks(t *testing.T) {
+	txProvider, mock := newTxProviderMock(t)
+	semesters := &semesterScheduleRepoStub{createErrs: []error{fmt.Errorf("insert semester schedule: %w", &pq.Error{Code: "40P01"})}}
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{tx: txProvider, semesters: semesters})
+
+	resp, err := service.Generate(context.Background(), defaultGenerateRequest())
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	result, err := service.Save(context.Background(), dto.SaveScheduleRequest{ProposalID: resp.ProposalID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Version)
+	assert.Len(t, semesters.items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleGeneratorServiceSaveDryRun(t *testing.T) {
+	txProvider, mock := newTxProviderMock(t)
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{tx: txProvider})
+
+	resp, err := service.Generate(context.Background(), dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 2,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 2},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	result, err := service.Save(database.WithDryRun(context.Background()), dto.SaveScheduleRequest{ProposalID: resp.ProposalID, CommitToDaily: true})
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, string(models.SemesterScheduleStatusPublished), result.Status)
+	assert.Equal(t, 4, result.DailySchedules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, ok := service.store.Get(resp.ProposalID)
+	assert.True(t, ok, "dry run must keep the proposal cached")
+}
+
 func TestScheduleGeneratorServiceSaveConflict(t *testing.T) {
 	txProvider, mock := newTxProviderMock(t)
 	conflictErr := &models.ScheduleConflictError{Type: "CLASS", Message: "conflict"}
@@ -124,12 +178,464 @@ func TestScheduleGeneratorServiceSaveConflict(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDefaultScheduleConflictCheckerLoadsTermOnce(t *testing.T) {
+	calls := 0
+	room := "Lab"
+	checker := &defaultScheduleConflictChecker{repo: scheduleFeederStub{termCalls: &calls, termSchedules: []models.Schedule{
+		{ID: "s-class", ClassID: "class-1", TeacherID: "teacher-9", DayOfWeek: "MONDAY", TimeSlot: "1"},
+		{ID: "s-teacher", ClassID: "class-2", TeacherID: "teacher-1", DayOfWeek: "MONDAY", TimeSlot: "2", Room: "Lab"},
+		{ID: "s-free", ClassID: "class-3", TeacherID: "teacher-3", DayOfWeek: "TUESDAY", TimeSlot: "1", Room: "Lab"},
+	}}}
+
+	conflicts, err := checker.Check(context.Background(), "term-1", "class-1", []dto.ScheduleSlotProposal{
+		{DayOfWeek: 1, TimeSlot: 1, TeacherID: "teacher-1"},
+		{DayOfWeek: 1, TimeSlot: 2, TeacherID: "teacher-1", Room: &room},
+		{DayOfWeek: 2, TimeSlot: 2, TeacherID: "teacher-3", Room: &room},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	var found []string
+	for _, conflict := range conflicts {
+		found = append(found, conflict.ScheduleID+"/"+conflict.Dimension)
+	}
+	assert.Equal(t, []string{"s-class/CLASS", "s-teacher/TEACHER", "s-teacher/ROOM"}, found)
+}
+
+func TestScheduleGeneratorServiceGenerateHardConstraint(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		constraints: []ScheduleConstraint{NewMorningOnlyConstraint("PE", 1, 1)},
+	})
+
+	resp, err := service.Generate(context.Background(), dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 3,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 4, Difficulty: 8},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2, Tags: []string{"pe"}},
+		},
+		HardConstraints: []string{"morning_only_pe"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Conflicts)
+	for _, slot := range resp.Slots {
+		if slot.SubjectID == "science" {
+			assert.Equal(t, 1, slot.TimeSlot, "PE-tagged subject must stay in the first slot")
+		}
+	}
+}
+
+func TestScheduleGeneratorServiceGenerateSoftConstraintPenalty(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		constraints: []ScheduleConstraint{NewMaxSubjectPerDayConstraint(1, 4)},
+	})
+
+	resp, err := service.Generate(context.Background(), dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 2,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 3},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 1},
+		},
+		SoftConstraints: []string{"MAX_SUBJECT_PER_DAY"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Slots, 4)
+	assert.Equal(t, 4.0, resp.Stats.ConstraintPenalty)
+}
+
+func TestScheduleGeneratorServiceGenerateAnnealingCandidates(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		constraints: []ScheduleConstraint{NewMorningOnlyConstraint("PE", 1, 1), NewMaxSubjectPerDayConstraint(2, 3)},
+	})
+	req := dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 3,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 4, Difficulty: 8},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2, Tags: []string{"pe"}},
+		},
+		HardConstraints: []string{"MORNING_ONLY_PE"},
+		SoftConstraints: []string{"MAX_SUBJECT_PER_DAY"},
+	}
+
+	baseline, err := service.Generate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, baseline.Candidates)
+	assert.Equal(t, ScheduleAlgorithmHeuristic, baseline.Stats.Algorithm)
+
+	req.Optimization = &dto.ScheduleOptimization{Mode: "annealing", TimeBudgetMs: 30, Candidates: 3, Seed: 42}
+	resp, err := service.Generate(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Candidates, 3)
+	assert.Equal(t, resp.Candidates[0].ProposalID, resp.ProposalID)
+	assert.Equal(t, ScheduleAlgorithmAnnealing, resp.Stats.Algorithm)
+	assert.GreaterOrEqual(t, resp.Score, baseline.Score)
+	for i := 1; i < len(resp.Candidates); i++ {
+		assert.GreaterOrEqual(t, resp.Candidates[i-1].Score, resp.Candidates[i].Score)
+		assert.Equal(t, i+1, resp.Candidates[i].Rank)
+	}
+	for _, slot := range resp.Slots {
+		if slot.SubjectID == "science" {
+			assert.Equal(t, 1, slot.TimeSlot, "annealing must not break hard constraints")
+		}
+	}
+	for _, candidate := range resp.Candidates {
+		_, ok := service.store.Get(candidate.ProposalID)
+		assert.True(t, ok, "every candidate should be saveable")
+	}
+}
+
+func TestScheduleGeneratorServiceGenerateBlockLessons(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	req := dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 3,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 4, BlockSize: 2},
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 2},
+		},
+		Optimization: &dto.ScheduleOptimization{Mode: "annealing", TimeBudgetMs: 20, Candidates: 1, Seed: 7},
+	}
+
+	resp, err := service.Generate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Conflicts)
+	science := map[int][]int{}
+	for _, slot := range resp.Slots {
+		if slot.SubjectID == "science" {
+			assert.Equal(t, 2, slot.BlockSize)
+			science[slot.DayOfWeek] = append(science[slot.DayOfWeek], slot.TimeSlot)
+		}
+	}
+	require.Len(t, science, 2, "each double lesson should sit on its own day")
+	for day, times := range science {
+		require.Len(t, times, 2, "day %d", day)
+		assert.Equal(t, times[0]+1, times[1], "day %d periods should be consecutive", day)
+	}
+
+	// A block lesson moves as a whole and cannot be swapped.
+	var start dto.ScheduleSlotProposal
+	var single dto.ScheduleSlotProposal
+	for _, slot := range resp.Slots {
+		if slot.SubjectID == "science" && slot.DayOfWeek == 1 && (start.SubjectID == "" || slot.TimeSlot < start.TimeSlot) {
+			start = slot
+		}
+		if slot.SubjectID == "math" {
+			single = slot
+		}
+	}
+	_, err = service.EditProposalSlots(context.Background(), resp.ProposalID, dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{
+			Type: "swap",
+			From: dto.ScheduleSlotRef{DayOfWeek: start.DayOfWeek, TimeSlot: start.TimeSlot},
+			To:   &dto.ScheduleSlotRef{DayOfWeek: single.DayOfWeek, TimeSlot: single.TimeSlot},
+		}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	req.SubjectLoads[0].WeeklyCount = 3
+	_, err = service.Generate(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleGeneratorServiceEditProposalSlotsReportsBrokenBlock(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	generated, err := service.Generate(context.Background(), dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 3,
+		Days:            []int{1},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2, BlockSize: 2},
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, generated.Conflicts)
+	var member dto.ScheduleSlotProposal
+	for _, slot := range generated.Slots {
+		if slot.SubjectID == "science" {
+			member = slot
+		}
+	}
+
+	resp, err := service.EditProposalSlots(context.Background(), generated.ProposalID, dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{
+			Type:      "replace",
+			From:      dto.ScheduleSlotRef{DayOfWeek: member.DayOfWeek, TimeSlot: member.TimeSlot},
+			SubjectID: "math",
+			TeacherID: "teacher-1",
+		}},
+	})
+	require.NoError(t, err)
+	types := map[string]int{}
+	for _, conflict := range resp.Conflicts {
+		types[conflict.Type]++
+	}
+	assert.Equal(t, 1, types["BROKEN_BLOCK"])
+	assert.Equal(t, 1, types["UNFULFILLED_LOAD"])
+	assert.Equal(t, 1, types["EXCESS_LOAD"])
+}
+
+func TestScheduleGeneratorServiceEditProposalSlotsSwap(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	generated, err := service.Generate(context.Background(), defaultGenerateRequest())
+	require.NoError(t, err)
+	first, second := generated.Slots[0], generated.Slots[1]
+
+	resp, err := service.EditProposalSlots(context.Background(), generated.ProposalID, dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{
+			Type: "swap",
+			From: dto.ScheduleSlotRef{DayOfWeek: first.DayOfWeek, TimeSlot: first.TimeSlot},
+			To:   &dto.ScheduleSlotRef{DayOfWeek: second.DayOfWeek, TimeSlot: second.TimeSlot},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, generated.ProposalID, resp.ProposalID)
+	assert.Empty(t, resp.Conflicts)
+	assert.Equal(t, second.SubjectID, resp.Slots[0].SubjectID)
+	assert.Equal(t, first.SubjectID, resp.Slots[1].SubjectID)
+}
+
+func TestScheduleGeneratorServiceEditProposalSlotsRevalidates(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		preferences: map[string]*models.TeacherPreference{
+			"teacher-1": mockPreference("MONDAY", "1"),
+		},
+	})
+	generated, err := service.Generate(context.Background(), defaultGenerateRequest())
+	require.NoError(t, err)
+	require.Empty(t, generated.Conflicts)
+
+	resp, err := service.EditProposalSlots(context.Background(), generated.ProposalID, dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{
+			Type:      "replace",
+			From:      dto.ScheduleSlotRef{DayOfWeek: 1, TimeSlot: 1},
+			SubjectID: "math",
+			TeacherID: "teacher-1",
+		}},
+	})
+	require.NoError(t, err)
+	types := map[string]int{}
+	for _, conflict := range resp.Conflicts {
+		types[conflict.Type]++
+	}
+	assert.Equal(t, 1, types["TEACHER_UNAVAILABLE"])
+	assert.Equal(t, 1, types["UNFULFILLED_LOAD"])
+	assert.Equal(t, 1, types["EXCESS_LOAD"])
+	assert.Less(t, resp.Score, generated.Score)
+
+	_, err = service.Save(context.Background(), dto.SaveScheduleRequest{ProposalID: generated.ProposalID})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleGeneratorServiceEditProposalSlotsRejectsInvalidMove(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	generated, err := service.Generate(context.Background(), defaultGenerateRequest())
+	require.NoError(t, err)
+
+	_, err = service.EditProposalSlots(context.Background(), generated.ProposalID, dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{
+			Type: "move",
+			From: dto.ScheduleSlotRef{DayOfWeek: 1, TimeSlot: 1},
+			To:   &dto.ScheduleSlotRef{DayOfWeek: 1, TimeSlot: 2},
+		}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = service.EditProposalSlots(context.Background(), "missing", dto.EditProposalSlotsRequest{
+		Operations: []dto.ScheduleSlotOperation{{Type: "replace", From: dto.ScheduleSlotRef{DayOfWeek: 1, TimeSlot: 1}, SubjectID: "math", TeacherID: "teacher-1"}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleGeneratorServiceScoreMatchesGeneratedProposal(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	generated, err := service.Generate(context.Background(), defaultGenerateRequest())
+	require.NoError(t, err)
+
+	slots := make([]dto.ScheduleSlotInput, 0, len(generated.Slots))
+	for _, slot := range generated.Slots {
+		slots = append(slots, dto.ScheduleSlotInput{DayOfWeek: slot.DayOfWeek, TimeSlot: slot.TimeSlot, SubjectID: slot.SubjectID, TeacherID: slot.TeacherID})
+	}
+	resp, err := service.Score(context.Background(), dto.ScoreScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 2,
+		Days:            []int{1, 2},
+		Slots:           slots,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, generated.Score, resp.Score)
+	assert.Equal(t, generated.Stats.GapPenalty, resp.Stats.GapPenalty)
+	assert.Empty(t, resp.Conflicts)
+	assert.Zero(t, resp.ConflictPenalty)
+}
+
+func TestScheduleGeneratorServiceScoreReportsPenalties(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		preferences: map[string]*models.TeacherPreference{
+			"teacher-1": mockPreference("MONDAY", "1"),
+		},
+	})
+	req := dto.ScoreScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 3,
+		Days:            []int{1, 2},
+		SubjectLoads:    defaultGenerateRequest().SubjectLoads,
+		Slots: []dto.ScheduleSlotInput{
+			{DayOfWeek: 1, TimeSlot: 1, SubjectID: "math", TeacherID: "teacher-1"},
+			{DayOfWeek: 1, TimeSlot: 3, SubjectID: "science", TeacherID: "teacher-2"},
+			{DayOfWeek: 2, TimeSlot: 1, SubjectID: "science", TeacherID: "teacher-2"},
+		},
+	}
+
+	resp, err := service.Score(context.Background(), req)
+	require.NoError(t, err)
+	types := map[string]int{}
+	for _, conflict := range resp.Conflicts {
+		types[conflict.Type]++
+	}
+	assert.Equal(t, 1, types["TEACHER_UNAVAILABLE"])
+	assert.Equal(t, 1, types["UNFULFILLED_LOAD"])
+	assert.Equal(t, 2.0, resp.ConflictPenalty)
+	// Monday has one hole and one unused slot; days with a single lesson are not penalised.
+	assert.Equal(t, 2.0, resp.Stats.GapPenalty)
+	assert.Zero(t, resp.Score)
+}
+
+func TestScheduleGeneratorServiceScoreRejectsInvalidSlots(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+	base := dto.ScoreScheduleRequest{TermID: "term-1", ClassID: "class-1", TimeSlotsPerDay: 2, Days: []int{1, 2}}
+
+	cases := map[string][]dto.ScheduleSlotInput{
+		"outside day": {{DayOfWeek: 3, TimeSlot: 1, SubjectID: "math", TeacherID: "teacher-1"}},
+		"duplicate cell": {
+			{DayOfWeek: 1, TimeSlot: 1, SubjectID: "math", TeacherID: "teacher-1"},
+			{DayOfWeek: 1, TimeSlot: 1, SubjectID: "science", TeacherID: "teacher-2"},
+		},
+		"unassigned teacher": {{DayOfWeek: 1, TimeSlot: 1, SubjectID: "math", TeacherID: "teacher-2"}},
+	}
+	for name, slots := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := base
+			req.Slots = slots
+			_, err := service.Score(context.Background(), req)
+			require.Error(t, err)
+			assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+		})
+	}
+}
+
+func TestScheduleGeneratorServiceExplainUnfulfilledLoad(t *testing.T) {
+	pref := mockPreference("MONDAY", "1")
+	pref.MaxLoadPerDay = 1
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{
+		preferences: map[string]*models.TeacherPreference{"teacher-1": pref},
+	})
+	req := defaultGenerateRequest()
+	req.SubjectLoads = []dto.SubjectLoadRequest{
+		{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 3},
+		{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 1},
+	}
+	generated, err := service.Generate(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, generated.Conflicts, 1)
+
+	resp, err := service.ExplainProposal(context.Background(), generated.ProposalID)
+	require.NoError(t, err)
+	require.Len(t, resp.Conflicts, 1)
+	explanation := resp.Conflicts[0].Explanation
+	require.NotNil(t, explanation)
+	assert.Equal(t, "math", explanation.SubjectID)
+	assert.Equal(t, 4, explanation.EvaluatedSlots)
+	assert.Equal(t, 0, explanation.AvailableSlots)
+	assert.Equal(t, 1, explanation.Blockers[blockReasonUnavailableWindow])
+	assert.Equal(t, 4, explanation.Blockers[blockReasonTeacherDailyCap])
+	assert.Equal(t, 2, explanation.Blockers[blockReasonClassSlotOccupied])
+
+	_, err = service.ExplainProposal(context.Background(), "missing")
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleGeneratorServiceGenerateUnknownConstraint(t *testing.T) {
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{})
+
+	_, err := service.Generate(context.Background(), dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 2,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 2},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2},
+		},
+		HardConstraints: []string{"NO_FRIDAY_AFTERNOON"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
+func TestScheduleGeneratorServiceGenerateFromPreset(t *testing.T) {
+	ips := "IPS"
+	service := newSchedulerServiceFixture(t, schedulerFixtureConfig{presets: presetLookupStub{
+		"preset-10":  {ID: "preset-10", Name: "Kelas 10", Grade: "10", Loads: types.JSONText(`[{"subject_id":"math","weekly_count":2,"difficulty":5},{"subject_id":"science","weekly_count":2}]`)},
+		"preset-ips": {ID: "preset-ips", Name: "Kelas 10 IPS", Grade: "10", Track: &ips, Loads: types.JSONText(`[{"subject_id":"math","weekly_count":4}]`)},
+		"preset-art": {ID: "preset-art", Name: "Seni", Grade: "10", Loads: types.JSONText(`[{"subject_id":"art","weekly_count":4}]`)},
+	}})
+	req := dto.GenerateScheduleRequest{TermID: "term-1", ClassID: "class-1", TimeSlotsPerDay: 2, Days: []int{1, 2}, PresetID: "preset-10"}
+
+	resp, err := service.Generate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Slots, 4)
+	teachers := map[string]string{}
+	for _, slot := range resp.Slots {
+		teachers[slot.SubjectID] = slot.TeacherID
+	}
+	assert.Equal(t, map[string]string{"math": "teacher-1", "science": "teacher-2"}, teachers)
+
+	for presetID, code := range map[string]string{
+		"preset-ips": appErrors.ErrValidation.Code,
+		"preset-art": appErrors.ErrPreconditionFailed.Code,
+		"missing":    appErrors.ErrNotFound.Code,
+	} {
+		req.PresetID = presetID
+		_, err := service.Generate(context.Background(), req)
+		require.Error(t, err, presetID)
+		assert.Equal(t, code, appErrors.FromError(err).Code, presetID)
+	}
+
+	req.PresetID = "preset-10"
+	req.SubjectLoads = []dto.SubjectLoadRequest{{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 4}}
+	_, err = service.Generate(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
+
 // --- Fixtures ---
 
 type schedulerFixtureConfig struct {
 	preferences map[string]*models.TeacherPreference
 	tx          txProvider
 	conflicts   scheduleConflictChecker
+	constraints []ScheduleConstraint
+	presets     subjectLoadPresetReader
+	semesters   *semesterScheduleRepoStub
 }
 
 func newSchedulerServiceFixture(t *testing.T, cfg schedulerFixtureConfig) *ScheduleGeneratorService {
@@ -140,7 +646,10 @@ func newSchedulerServiceFixture(t *testing.T, cfg schedulerFixtureConfig) *Sched
 		},
 	}
 	prefs := preferenceRepoSchedulerStub{items: cfg.preferences}
-	semesters := &semesterScheduleRepoStub{}
+	semesters := cfg.semesters
+	if semesters == nil {
+		semesters = &semesterScheduleRepoStub{}
+	}
 	slots := &semesterScheduleSlotRepoStub{}
 	subjects := subjectLookupStub{subjects: map[string]struct{}{"math": {}, "science": {}}}
 	terms := termLookupStub{}
@@ -168,7 +677,7 @@ func newSchedulerServiceFixture(t *testing.T, cfg schedulerFixtureConfig) *Sched
 		tx,
 		validator.New(),
 		zap.NewNop(),
-		ScheduleGeneratorConfig{ProposalTTL: time.Hour},
+		ScheduleGeneratorConfig{ProposalTTL: time.Hour, Constraints: cfg.constraints, Presets: cfg.presets},
 	)
 }
 
@@ -196,16 +705,23 @@ func (s preferenceRepoSchedulerStub) GetByTeacher(ctx context.Context, teacherID
 
 type semesterScheduleRepoStub struct {
 	items []models.SemesterSchedule
+	// createErrs fail the next CreateVersioned calls in order.
+	createErrs []error
 }
 
 func (s *semesterScheduleRepoStub) CreateVersioned(ctx context.Context, exec sqlx.ExtContext, schedule *models.SemesterSchedule) error {
+	if len(s.createErrs) > 0 {
+		err := s.createErrs[0]
+		s.createErrs = s.createErrs[1:]
+		return err
+	}
 	schedule.ID = uuidString(len(s.items) + 1)
 	schedule.Version = len(s.items) + 1
 	s.items = append(s.items, *schedule)
 	return nil
 }
 
-func (s *semesterScheduleRepoStub) ListByTermClass(ctx context.Context, termID, classID string) ([]models.SemesterSchedule, error) {
+func (s *semesterScheduleRepoStub) ListByTermClass(ctx context.Context, termID models.TermID, classID models.ClassID) ([]models.SemesterSchedule, error) {
 	return s.items, nil
 }
 
@@ -276,11 +792,22 @@ func (termLookupStub) FindByID(ctx context.Context, id string) (*models.Term, er
 type classLookupStub struct{}
 
 func (classLookupStub) FindByID(ctx context.Context, id string) (*models.Class, error) {
-	return &models.Class{ID: id}, nil
+	return &models.Class{ID: id, Name: "X IPA 1", Grade: "10", Track: "IPA"}, nil
+}
+
+type presetLookupStub map[string]*models.SubjectLoadPreset
+
+func (s presetLookupStub) FindByID(ctx context.Context, id string) (*models.SubjectLoadPreset, error) {
+	if preset, ok := s[id]; ok {
+		return preset, nil
+	}
+	return nil, sql.ErrNoRows
 }
 
 type scheduleFeederStub struct {
 	teacherSchedules map[string][]models.Schedule
+	termSchedules    []models.Schedule
+	termCalls        *int
 }
 
 func (s scheduleFeederStub) ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error) {
@@ -291,8 +818,11 @@ func (scheduleFeederStub) ListByClass(ctx context.Context, classID string) ([]mo
 	return nil, nil
 }
 
-func (scheduleFeederStub) FindConflicts(ctx context.Context, termID, dayOfWeek, timeSlot string) ([]models.Schedule, error) {
-	return nil, nil
+func (s scheduleFeederStub) ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error) {
+	if s.termCalls != nil {
+		*s.termCalls++
+	}
+	return s.termSchedules, nil
 }
 
 func (scheduleFeederStub) BulkCreateWithTx(ctx context.Context, tx *sqlx.Tx, schedules []models.Schedule) error {
@@ -335,6 +865,19 @@ func uuidString(v int) string {
 	return fmt.Sprintf("sched-%d", v)
 }
 
+func defaultGenerateRequest() dto.GenerateScheduleRequest {
+	return dto.GenerateScheduleRequest{
+		TermID:          "term-1",
+		ClassID:         "class-1",
+		TimeSlotsPerDay: 2,
+		Days:            []int{1, 2},
+		SubjectLoads: []dto.SubjectLoadRequest{
+			{SubjectID: "math", TeacherID: "teacher-1", WeeklyCount: 2},
+			{SubjectID: "science", TeacherID: "teacher-2", WeeklyCount: 2},
+		},
+	}
+}
+
 func mockPreference(day, slot string) *models.TeacherPreference {
 	payload, _ := json.Marshal([]models.TeacherUnavailableSlot{{DayOfWeek: day, TimeRange: slot}})
 	return &models.TeacherPreference{
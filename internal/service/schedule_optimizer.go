@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+)
+
+const (
+	// ScheduleAlgorithmHeuristic marks proposals produced by seeding and gap repair only.
+	ScheduleAlgorithmHeuristic = "heuristic_v1"
+	// ScheduleAlgorithmAnnealing marks proposals refined by simulated annealing.
+	ScheduleAlgorithmAnnealing = "annealing_v1"
+
+	defaultOptimizationBudget     = 2 * time.Second
+	defaultOptimizationCandidates = 3
+	annealingStartTemperature     = 5.0
+	annealingEndTemperature       = 0.05
+	annealingClockInterval        = 64
+)
+
+// scheduleOptimizationPlan is the normalised optimisation request.
+type scheduleOptimizationPlan struct {
+	enabled    bool
+	budget     time.Duration
+	candidates int
+	seed       int64
+}
+
+func resolveOptimizationPlan(opt *dto.ScheduleOptimization, maxBudget time.Duration) scheduleOptimizationPlan {
+	if opt == nil || opt.Mode != "annealing" {
+		return scheduleOptimizationPlan{candidates: 1}
+	}
+	plan := scheduleOptimizationPlan{
+		enabled:    true,
+		budget:     time.Duration(opt.TimeBudgetMs) * time.Millisecond,
+		candidates: opt.Candidates,
+		seed:       opt.Seed,
+	}
+	if plan.budget <= 0 {
+		plan.budget = defaultOptimizationBudget
+	}
+	if maxBudget > 0 && plan.budget > maxBudget {
+		plan.budget = maxBudget
+	}
+	if plan.candidates <= 0 {
+		plan.candidates = defaultOptimizationCandidates
+	}
+	if plan.seed == 0 {
+		plan.seed = time.Now().UnixNano()
+	}
+	return plan
+}
+
+// penalty mirrors the scoring weights used for proposals, excluding unfulfilled loads.
+func (s *schedulerState) penalty() float64 {
+	tt := s.timetable()
+	gap := calculateGapPenalty(s.days, s.timeSlots, tt.Slots)
+	load := calculateLoadPenalty(s.teacherLoads)
+	return gap*2 + load*5 + s.constraints.softPenalty(tt)
+}
+
+// anneal refines the timetable by random slot swaps and block moves until the budget elapses and returns the best state seen.
+func (s *schedulerState) anneal(ctx context.Context, budget time.Duration, rng *rand.Rand) (*schedulerState, int) {
+	positions := make([]slotKey, 0, len(s.days)*s.timeSlots)
+	for _, day := range s.days {
+		for slot := 1; slot <= s.timeSlots; slot++ {
+			positions = append(positions, slotKey{Day: day, Time: slot})
+		}
+	}
+	best := s.clone()
+	if len(positions) < 2 || len(s.classSlots) == 0 || budget <= 0 {
+		return best, 0
+	}
+
+	started := time.Now()
+	currentCost := s.penalty()
+	bestCost := currentCost
+	temperature := annealingStartTemperature
+	steps := 0
+	for bestCost > 0 {
+		if steps%annealingClockInterval == 0 {
+			elapsed := time.Since(started)
+			if elapsed >= budget || ctx.Err() != nil {
+				break
+			}
+			progress := float64(elapsed) / float64(budget)
+			temperature = annealingStartTemperature * math.Pow(annealingEndTemperature/annealingStartTemperature, progress)
+		}
+		steps++
+
+		a := positions[rng.Intn(len(positions))]
+		b := positions[rng.Intn(len(positions))]
+		undo, ok := s.perturb(a, b)
+		if !ok {
+			continue
+		}
+		cost := s.penalty()
+		delta := cost - currentCost
+		if delta <= 0 || rng.Float64() < math.Exp(-delta/temperature) {
+			currentCost = cost
+			if cost < bestCost {
+				bestCost = cost
+				best = s.clone()
+			}
+			continue
+		}
+		undo()
+	}
+	return best, steps
+}
+
+// perturb makes one random move between positions a and b and returns how to take it back. Single
+// periods are swapped; a block lesson moves as a whole into free periods starting at the other
+// position. Moves that would split a block are rejected.
+func (s *schedulerState) perturb(a, b slotKey) (func(), bool) {
+	lessonA := s.lessonAt(a)
+	lessonB := s.lessonAt(b)
+	if len(lessonA) <= 1 && len(lessonB) <= 1 {
+		if !s.swap(a, b, true) {
+			return nil, false
+		}
+		return func() { s.swap(a, b, false) }, true
+	}
+
+	var lesson []slotKey
+	var target slotKey
+	switch {
+	case len(lessonA) > 1 && len(lessonB) == 0:
+		lesson, target = lessonA, b
+	case len(lessonB) > 1 && len(lessonA) == 0:
+		lesson, target = lessonB, a
+	default:
+		return nil, false
+	}
+	origin := lesson[0]
+	if !s.relocate(lesson, target.Day, target.Time, true) {
+		return nil, false
+	}
+	moved := lessonBlock{Day: target.Day, Start: target.Time, Length: len(lesson)}.Keys()
+	return func() { s.relocate(moved, origin.Day, origin.Time, false) }, true
+}
+
+// swap exchanges the contents of two positions (either may be empty). When check is set the
+// swap is rejected if it breaks teacher availability or increases hard constraint violations.
+func (s *schedulerState) swap(a, b slotKey, check bool) bool {
+	if a == b {
+		return false
+	}
+	slotA, okA := s.classSlots[a]
+	slotB, okB := s.classSlots[b]
+	if !okA && !okB {
+		return false
+	}
+	var before ScheduleTimetable
+	checkHard := check && len(s.constraints.hard) > 0
+	if checkHard {
+		before = s.timetable()
+	}
+
+	s.lift(a)
+	s.lift(b)
+	ok := true
+	if okA {
+		if check && !s.canPlace(slotA.TeacherID, b.Day, b.Time) {
+			ok = false
+		} else {
+			s.put(slotA, b)
+		}
+	}
+	if ok && okB {
+		if check && !s.canPlace(slotB.TeacherID, a.Day, a.Time) {
+			ok = false
+		} else {
+			s.put(slotB, a)
+		}
+	}
+	if ok && checkHard && violationsIncrease(s.constraints.hard, before, s.timetable()) {
+		ok = false
+	}
+	if ok {
+		return true
+	}
+
+	s.lift(a)
+	s.lift(b)
+	if okA {
+		s.put(slotA, a)
+	}
+	if okB {
+		s.put(slotB, b)
+	}
+	return false
+}
+
+func (s *schedulerState) lift(key slotKey) {
+	slot, ok := s.classSlots[key]
+	if !ok {
+		return
+	}
+	delete(s.classSlots, key)
+	if teacher := s.teacherLoads[slot.TeacherID]; teacher != nil {
+		teacher.Release(key.Day, key.Time)
+	}
+	if s.dayLoad[key.Day] > 0 {
+		s.dayLoad[key.Day]--
+	}
+}
+
+func (s *schedulerState) put(slot dto.ScheduleSlotProposal, key slotKey) {
+	slot.DayOfWeek = key.Day
+	slot.TimeSlot = key.Time
+	s.classSlots[key] = slot
+	if teacher := s.teacherLoads[slot.TeacherID]; teacher != nil {
+		teacher.Reserve(key.Day, key.Time)
+	}
+	s.dayLoad[key.Day]++
+}
+
+func (s *schedulerState) clone() *schedulerState {
+	cloned := &schedulerState{
+		days:           s.days,
+		timeSlots:      s.timeSlots,
+		classSlots:     make(map[slotKey]dto.ScheduleSlotProposal, len(s.classSlots)),
+		dayLoad:        make(map[int]int, len(s.dayLoad)),
+		teacherLoads:   make(map[string]*teacherAvailability, len(s.teacherLoads)),
+		preferredCache: s.preferredCache,
+		constraints:    s.constraints,
+		loadIndex:      s.loadIndex,
+	}
+	for key, slot := range s.classSlots {
+		cloned.classSlots[key] = slot
+	}
+	for day, count := range s.dayLoad {
+		cloned.dayLoad[day] = count
+	}
+	for teacherID, availability := range s.teacherLoads {
+		cloned.teacherLoads[teacherID] = availability.clone()
+	}
+	return cloned
+}
+
+func (t *teacherAvailability) clone() *teacherAvailability {
+	cloned := &teacherAvailability{
+		MaxLoadPerDay:  t.MaxLoadPerDay,
+		MaxLoadPerWeek: t.MaxLoadPerWeek,
+		perDay:         make(map[int]int, len(t.perDay)),
+		weekly:         t.weekly,
+		blocked:        make(map[int]map[int]string, len(t.blocked)),
+		assigned:       make(map[int]map[int]bool, len(t.assigned)),
+	}
+	for day, count := range t.perDay {
+		cloned.perDay[day] = count
+	}
+	for day, slots := range t.blocked {
+		cloned.blocked[day] = make(map[int]string, len(slots))
+		for slot, reason := range slots {
+			cloned.blocked[day][slot] = reason
+		}
+	}
+	for day, slots := range t.assigned {
+		cloned.assigned[day] = make(map[int]bool, len(slots))
+		for slot, value := range slots {
+			cloned.assigned[day][slot] = value
+		}
+	}
+	return cloned
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// EditProposalSlots applies manual move/swap/replace edits to a cached proposal, then revalidates
+// conflicts and rescores it. Edits that introduce conflicts are kept so admins can keep adjusting,
+// but Save will refuse the proposal until the conflicts are resolved.
+func (s *ScheduleGeneratorService) EditProposalSlots(ctx context.Context, proposalID string, req dto.EditProposalSlotsRequest) (*dto.GenerateScheduleResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid slot edit payload")
+	}
+	proposal, ok := s.store.Get(proposalID)
+	if !ok {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "proposal not found or expired")
+	}
+
+	availability, err := s.buildTeacherAvailability(ctx, proposal.TermID, map[string]map[string]bool{}, proposal.SubjectLoads)
+	if err != nil {
+		return nil, err
+	}
+	state := newSchedulerState(proposal.Days, proposal.TimeSlotsPerDay, availability)
+	state.useConstraints(proposal.Constraints, proposal.SubjectLoads)
+	for _, slot := range proposal.Slots {
+		state.put(slot, slotKey{Day: slot.DayOfWeek, Time: slot.TimeSlot})
+	}
+
+	for i, op := range req.Operations {
+		if err := state.applyEdit(op); err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("operation %d: %s", i+1, err.Error()))
+		}
+	}
+
+	conflicts := state.revalidate(proposal.SubjectLoads)
+	score, stats := scoreSchedule(state, len(conflicts), proposal.Stats)
+	proposal.Slots = state.exportSlots()
+	proposal.Conflicts = conflicts
+	proposal.Score = score
+	proposal.Stats = stats
+	proposal.Meta = copyProposalMeta(proposal.Meta)
+	edits, _ := proposal.Meta["manualEdits"].(int)
+	proposal.Meta["manualEdits"] = edits + len(req.Operations)
+	s.store.Save(proposal)
+
+	return &dto.GenerateScheduleResponse{
+		ProposalID: proposal.ProposalID,
+		Score:      proposal.Score,
+		Slots:      proposal.Slots,
+		Conflicts:  proposal.Conflicts,
+		Stats:      proposal.Stats,
+	}, nil
+}
+
+func (s *schedulerState) applyEdit(op dto.ScheduleSlotOperation) error {
+	from := slotKey{Day: op.From.DayOfWeek, Time: op.From.TimeSlot}
+	if err := s.checkCell(from); err != nil {
+		return err
+	}
+	current, occupied := s.classSlots[from]
+
+	switch op.Type {
+	case "move", "swap":
+		if op.To == nil {
+			return fmt.Errorf("%s requires a target slot", op.Type)
+		}
+		to := slotKey{Day: op.To.DayOfWeek, Time: op.To.TimeSlot}
+		if err := s.checkCell(to); err != nil {
+			return err
+		}
+		_, targetOccupied := s.classSlots[to]
+		if !occupied {
+			return fmt.Errorf("no lesson at day %d slot %d", from.Day, from.Time)
+		}
+		if lesson := s.lessonAt(from); op.Type == "move" && len(lesson) > 1 {
+			if !s.relocate(lesson, to.Day, to.Time, false) {
+				return fmt.Errorf("block lesson needs %d free periods from day %d slot %d", len(lesson), to.Day, to.Time)
+			}
+			return nil
+		}
+		if op.Type == "move" && targetOccupied {
+			return fmt.Errorf("target day %d slot %d is occupied; use swap instead", to.Day, to.Time)
+		}
+		if op.Type == "swap" && !targetOccupied {
+			return fmt.Errorf("target day %d slot %d is empty; use move instead", to.Day, to.Time)
+		}
+		if op.Type == "swap" && (len(s.lessonAt(from)) > 1 || len(s.lessonAt(to)) > 1) {
+			return fmt.Errorf("block lessons cannot be swapped; move them instead")
+		}
+		s.swap(from, to, false)
+	case "replace":
+		load, known := s.loadIndex[subjectLoadKey(op.SubjectID, op.TeacherID)]
+		if !known {
+			return fmt.Errorf("subject %s with teacher %s is not part of this proposal's subject loads", op.SubjectID, op.TeacherID)
+		}
+		s.lift(from)
+		replacement := dto.ScheduleSlotProposal{SubjectID: op.SubjectID, TeacherID: op.TeacherID, BlockSize: slotBlockSize(loadBlockSize(load))}
+		if occupied {
+			replacement.Room = current.Room
+		}
+		s.put(replacement, from)
+	default:
+		return fmt.Errorf("unsupported operation %s", op.Type)
+	}
+	return nil
+}
+
+func (s *schedulerState) checkCell(key slotKey) error {
+	for _, day := range s.days {
+		if day == key.Day {
+			if key.Time < 1 || key.Time > s.timeSlots {
+				return fmt.Errorf("time slot %d is outside 1-%d", key.Time, s.timeSlots)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("day %d is not part of this proposal", key.Day)
+}
+
+// revalidate recomputes conflicts for a manually edited timetable.
+func (s *schedulerState) revalidate(loads []dto.SubjectLoadRequest) []dto.ProposalConflict {
+	conflicts := make([]dto.ProposalConflict, 0)
+	for _, slot := range s.exportSlots() {
+		teacher := s.teacherLoads[slot.TeacherID]
+		if teacher == nil || teacher.blockedReason(slot.DayOfWeek, slot.TimeSlot) == "" {
+			continue
+		}
+		slotCopy := slot
+		conflicts = append(conflicts, dto.ProposalConflict{
+			Type:    "TEACHER_UNAVAILABLE",
+			Message: fmt.Sprintf("teacher %s is unavailable on day %d slot %d", slot.TeacherID, slot.DayOfWeek, slot.TimeSlot),
+			Slot:    &slotCopy,
+			Meta:    map[string]any{"teacherId": slot.TeacherID},
+		})
+	}
+
+	conflicts = append(conflicts, s.brokenBlocks()...)
+
+	teacherIDs := make([]string, 0, len(s.teacherLoads))
+	for teacherID := range s.teacherLoads {
+		teacherIDs = append(teacherIDs, teacherID)
+	}
+	sort.Strings(teacherIDs)
+	for _, teacherID := range teacherIDs {
+		teacher := s.teacherLoads[teacherID]
+		for _, day := range s.days {
+			if teacher.MaxLoadPerDay > 0 && teacher.perDay[day] > teacher.MaxLoadPerDay {
+				conflicts = append(conflicts, dto.ProposalConflict{
+					Type:    "TEACHER_OVERLOAD",
+					Message: fmt.Sprintf("teacher %s exceeds max load of %d on day %d", teacherID, teacher.MaxLoadPerDay, day),
+					Meta:    map[string]any{"teacherId": teacherID, "dayOfWeek": day, "load": teacher.perDay[day]},
+				})
+			}
+		}
+		if teacher.MaxLoadPerWeek > 0 && teacher.weekly > teacher.MaxLoadPerWeek {
+			conflicts = append(conflicts, dto.ProposalConflict{
+				Type:    "TEACHER_OVERLOAD",
+				Message: fmt.Sprintf("teacher %s exceeds max weekly load of %d", teacherID, teacher.MaxLoadPerWeek),
+				Meta:    map[string]any{"teacherId": teacherID, "load": teacher.weekly},
+			})
+		}
+	}
+
+	tt := s.timetable()
+	for _, rule := range s.constraints.hard {
+		if violations := rule.Violations(tt); violations > 0 {
+			conflicts = append(conflicts, dto.ProposalConflict{
+				Type:    "HARD_CONSTRAINT_VIOLATION",
+				Message: fmt.Sprintf("%s violated %d time(s)", rule.Name(), violations),
+				Meta:    map[string]any{"constraint": rule.Name(), "violations": violations},
+			})
+		}
+	}
+
+	placed := make(map[string]int, len(loads))
+	for _, slot := range tt.Slots {
+		placed[subjectLoadKey(slot.SubjectID, slot.TeacherID)]++
+	}
+	for _, load := range loads {
+		count := placed[subjectLoadKey(load.SubjectID, load.TeacherID)]
+		var explanation *dto.ConflictExplanation
+		// One conflict per missing lesson, so a block lesson missing one period is reported once.
+		size := loadBlockSize(load)
+		for i := count; i < load.WeeklyCount; i += size {
+			if explanation == nil {
+				explanation = s.explainUnplaced(load)
+			}
+			conflicts = append(conflicts, unfulfilledLoadConflict(load, explanation))
+		}
+		if count > load.WeeklyCount {
+			conflicts = append(conflicts, dto.ProposalConflict{
+				Type:    "EXCESS_LOAD",
+				Message: fmt.Sprintf("subject %s for teacher %s is scheduled %d times but weeklyCount is %d", load.SubjectID, load.TeacherID, count, load.WeeklyCount),
+				Meta: map[string]any{
+					"subjectId": load.SubjectID,
+					"teacherId": load.TeacherID,
+				},
+			})
+		}
+	}
+	return conflicts
+}
+
+// brokenBlocks reports block lessons that no longer have all their periods, one conflict per block.
+func (s *schedulerState) brokenBlocks() []dto.ProposalConflict {
+	slots := s.exportSlots()
+	blocks := proposalBlocks(slots)
+	var conflicts []dto.ProposalConflict
+	for _, slot := range slots {
+		block, ok := blocks[slotKey{Day: slot.DayOfWeek, Time: slot.TimeSlot}]
+		if !ok || !block.Broken() || block.Start != slot.TimeSlot {
+			continue
+		}
+		slotCopy := slot
+		conflicts = append(conflicts, dto.ProposalConflict{
+			Type:    "BROKEN_BLOCK",
+			Message: fmt.Sprintf("block lesson of subject %s on day %d slot %s has %d of %d periods", slot.SubjectID, block.Day, block.Label(), block.Length, block.Size),
+			Slot:    &slotCopy,
+			Meta:    map[string]any{"subjectId": slot.SubjectID, "teacherId": slot.TeacherID, "blockSize": block.Size},
+		})
+	}
+	return conflicts
+}
+
+func copyProposalMeta(meta map[string]any) map[string]any {
+	result := make(map[string]any, len(meta)+1)
+	for key, value := range meta {
+		result[key] = value
+	}
+	return result
+}
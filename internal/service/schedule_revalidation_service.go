@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type scheduleWarningStore interface {
+	FindByID(ctx context.Context, id string) (*models.SemesterSchedule, error)
+	ListPublishedByTeacher(ctx context.Context, teacherID models.TeacherID) ([]models.SemesterSchedule, error)
+	ReplaceWarnings(ctx context.Context, scheduleID string, warnings []models.ScheduleWarning) error
+	ListWarnings(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error)
+}
+
+type scheduleWarningSlots interface {
+	ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error)
+	ListTeacherTermSlots(ctx context.Context, teacherID models.TeacherID, termID models.TermID, scheduleID string, classID models.ClassID) ([]models.SemesterScheduleClassSlot, error)
+}
+
+// scheduleRevalidator is notified after a teacher's assignments or preferences change.
+type scheduleRevalidator interface {
+	RevalidateTeacher(ctx context.Context, teacherID string) error
+}
+
+// ScheduleRevalidationService re-checks published semester schedules against the current teacher
+// assignments and preferences. Published schedules are not regenerated when those change, so the
+// inconsistencies are recorded as warnings for an administrator to act on.
+type ScheduleRevalidationService struct {
+	schedules   scheduleWarningStore
+	slots       scheduleWarningSlots
+	assignments teacherAssignmentFetcher
+	prefs       ports.TeacherPreferenceReader
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewScheduleRevalidationService constructs the service.
+func NewScheduleRevalidationService(
+	schedules scheduleWarningStore,
+	slots scheduleWarningSlots,
+	assignments teacherAssignmentFetcher,
+	prefs ports.TeacherPreferenceReader,
+	logger *zap.Logger,
+) *ScheduleRevalidationService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ScheduleRevalidationService{
+		schedules:   schedules,
+		slots:       slots,
+		assignments: assignments,
+		prefs:       prefs,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Warnings returns the open warnings of a schedule.
+func (s *ScheduleRevalidationService) Warnings(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error) {
+	if _, err := s.load(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	warnings, err := s.schedules.ListWarnings(ctx, scheduleID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list schedule warnings")
+	}
+	if warnings == nil {
+		warnings = []models.ScheduleWarning{}
+	}
+	return warnings, nil
+}
+
+// Revalidate re-checks one published schedule and replaces its warnings.
+func (s *ScheduleRevalidationService) Revalidate(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error) {
+	schedule, err := s.load(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != models.SemesterScheduleStatusPublished {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "only published schedules are revalidated")
+	}
+	return s.revalidate(ctx, schedule)
+}
+
+// RevalidateTeacher re-checks every published schedule the teacher appears in. It is called after
+// the teacher's assignments or preferences change.
+func (s *ScheduleRevalidationService) RevalidateTeacher(ctx context.Context, teacherID string) error {
+	schedules, err := s.schedules.ListPublishedByTeacher(ctx, models.TeacherID(teacherID))
+	if err != nil {
+		return fmt.Errorf("list published schedules: %w", err)
+	}
+	for i := range schedules {
+		warnings, err := s.revalidate(ctx, &schedules[i])
+		if err != nil {
+			return err
+		}
+		if len(warnings) > 0 {
+			s.logger.Info("published schedule has new warnings",
+				zap.String("schedule_id", schedules[i].ID),
+				zap.String("teacher_id", teacherID),
+				zap.Int("warnings", len(warnings)))
+		}
+	}
+	return nil
+}
+
+func (s *ScheduleRevalidationService) load(ctx context.Context, scheduleID string) (*models.SemesterSchedule, error) {
+	schedule, err := s.schedules.FindByID(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "semester schedule not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load semester schedule")
+	}
+	return schedule, nil
+}
+
+func (s *ScheduleRevalidationService) revalidate(ctx context.Context, schedule *models.SemesterSchedule) ([]models.ScheduleWarning, error) {
+	warnings, err := s.check(ctx, schedule)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to revalidate semester schedule")
+	}
+	if err := s.schedules.ReplaceWarnings(ctx, schedule.ID, warnings); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store schedule warnings")
+	}
+	return warnings, nil
+}
+
+// check compares a schedule's slots with the class's teacher assignments and with each teacher's
+// preferences and published slots in other classes of the term.
+func (s *ScheduleRevalidationService) check(ctx context.Context, schedule *models.SemesterSchedule) ([]models.ScheduleWarning, error) {
+	slots, err := s.slots.ListBySchedule(ctx, schedule.ID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignments.ListByClassAndTerm(ctx, schedule.ClassID.String(), schedule.TermID.String())
+	if err != nil {
+		return nil, err
+	}
+	assigned := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		assigned[subjectLoadKey(assignment.SubjectID, assignment.TeacherID)] = true
+	}
+
+	byTeacher := make(map[string][]models.SemesterScheduleSlot)
+	teacherIDs := make([]string, 0)
+	for _, slot := range slots {
+		teacherID := slot.TeacherID.String()
+		if _, ok := byTeacher[teacherID]; !ok {
+			teacherIDs = append(teacherIDs, teacherID)
+		}
+		byTeacher[teacherID] = append(byTeacher[teacherID], slot)
+	}
+	sort.Strings(teacherIDs)
+
+	now := s.now().UTC()
+	warnings := make([]models.ScheduleWarning, 0)
+	add := func(warning models.ScheduleWarning) {
+		warning.SemesterScheduleID = schedule.ID
+		warning.DetectedAt = now
+		warnings = append(warnings, warning)
+	}
+	for _, teacherID := range teacherIDs {
+		pref, err := s.preference(ctx, teacherID)
+		if err != nil {
+			return nil, err
+		}
+		termSlots, err := s.slots.ListTeacherTermSlots(ctx, models.TeacherID(teacherID), schedule.TermID, schedule.ID, schedule.ClassID)
+		if err != nil {
+			return nil, err
+		}
+		elsewhere := make(map[int][]string)
+		perDay := make(map[int]int)
+		for _, slot := range termSlots {
+			perDay[slot.DayOfWeek]++
+			if slot.ClassID != schedule.ClassID {
+				key := slot.DayOfWeek*100 + slot.TimeSlot
+				elsewhere[key] = append(elsewhere[key], slot.ClassID.String())
+			}
+		}
+		blocked := unavailableSlots(pref)
+
+		days := make([]int, 0)
+		seenDay := make(map[int]bool)
+		for _, slot := range byTeacher[teacherID] {
+			day, timeSlot, subjectID := slot.DayOfWeek, slot.TimeSlot, slot.SubjectID.String()
+			position := models.ScheduleWarning{TeacherID: teacherID, SubjectID: &subjectID, DayOfWeek: &day, TimeSlot: &timeSlot}
+			if !assigned[subjectLoadKey(subjectID, teacherID)] {
+				warning := position
+				warning.Type = models.ScheduleWarningTeacherUnassigned
+				warning.Message = fmt.Sprintf("Teacher %s is no longer assigned to subject %s in this class; reassign the slot or restore the assignment.", teacherID, slot.SubjectID)
+				add(warning)
+			}
+			if blocked[day*100+timeSlot] {
+				warning := position
+				warning.Type = models.ScheduleWarningTeacherUnavailable
+				warning.Message = fmt.Sprintf("Teacher %s is unavailable on %s slot %d; move the lesson or update the teacher's preferences.", teacherID, dayIndexToName(day), timeSlot)
+				add(warning)
+			}
+			if classes := elsewhere[day*100+timeSlot]; len(classes) > 0 {
+				warning := position
+				warning.Type = models.ScheduleWarningTeacherDoubleBooked
+				warning.Message = fmt.Sprintf("Teacher %s also teaches class %s on %s slot %d; move one of the lessons.", teacherID, strings.Join(classes, ", "), dayIndexToName(day), timeSlot)
+				add(warning)
+			}
+			if !seenDay[day] {
+				seenDay[day] = true
+				days = append(days, day)
+			}
+		}
+		if pref == nil {
+			continue
+		}
+		if pref.MaxLoadPerDay > 0 {
+			for _, day := range days {
+				if perDay[day] <= pref.MaxLoadPerDay {
+					continue
+				}
+				day := day
+				add(models.ScheduleWarning{
+					Type:      models.ScheduleWarningDailyLoad,
+					TeacherID: teacherID,
+					DayOfWeek: &day,
+					Message:   fmt.Sprintf("Teacher %s teaches %d lessons on %s, above their maximum of %d per day.", teacherID, perDay[day], dayIndexToName(day), pref.MaxLoadPerDay),
+				})
+			}
+		}
+		if pref.MaxLoadPerWeek > 0 && len(termSlots) > pref.MaxLoadPerWeek {
+			add(models.ScheduleWarning{
+				Type:      models.ScheduleWarningWeeklyLoad,
+				TeacherID: teacherID,
+				Message:   fmt.Sprintf("Teacher %s teaches %d lessons a week, above their maximum of %d.", teacherID, len(termSlots), pref.MaxLoadPerWeek),
+			})
+		}
+	}
+	return warnings, nil
+}
+
+func (s *ScheduleRevalidationService) preference(ctx context.Context, teacherID string) (*models.TeacherPreference, error) {
+	if s.prefs == nil {
+		return nil, nil
+	}
+	pref, err := s.prefs.GetByTeacher(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pref, nil
+}
+
+// unavailableSlots indexes a preference's unavailable windows by day*100+slot.
+func unavailableSlots(pref *models.TeacherPreference) map[int]bool {
+	blocked := make(map[int]bool)
+	if pref == nil || len(pref.Unavailable) == 0 {
+		return blocked
+	}
+	var windows []models.TeacherUnavailableSlot
+	if err := json.Unmarshal(pref.Unavailable, &windows); err != nil {
+		return blocked
+	}
+	for _, window := range windows {
+		day := dayStringToIndex(window.DayOfWeek)
+		if day == 0 {
+			continue
+		}
+		for _, slot := range expandTimeRange(window.TimeRange) {
+			blocked[day*100+slot] = true
+		}
+	}
+	return blocked
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type scheduleWarningStoreStub struct {
+	schedules map[string]*models.SemesterSchedule
+	warnings  map[string][]models.ScheduleWarning
+}
+
+func (s *scheduleWarningStoreStub) FindByID(ctx context.Context, id string) (*models.SemesterSchedule, error) {
+	if schedule, ok := s.schedules[id]; ok {
+		return schedule, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *scheduleWarningStoreStub) ListPublishedByTeacher(ctx context.Context, teacherID models.TeacherID) ([]models.SemesterSchedule, error) {
+	var result []models.SemesterSchedule
+	for _, schedule := range s.schedules {
+		if schedule.Status == models.SemesterScheduleStatusPublished {
+			result = append(result, *schedule)
+		}
+	}
+	return result, nil
+}
+
+func (s *scheduleWarningStoreStub) ReplaceWarnings(ctx context.Context, scheduleID string, warnings []models.ScheduleWarning) error {
+	if s.warnings == nil {
+		s.warnings = make(map[string][]models.ScheduleWarning)
+	}
+	s.warnings[scheduleID] = warnings
+	return nil
+}
+
+func (s *scheduleWarningStoreStub) ListWarnings(ctx context.Context, scheduleID string) ([]models.ScheduleWarning, error) {
+	return s.warnings[scheduleID], nil
+}
+
+type scheduleWarningSlotsStub struct {
+	slots     []models.SemesterScheduleSlot
+	termSlots []models.SemesterScheduleClassSlot
+}
+
+func (s scheduleWarningSlotsStub) ListBySchedule(ctx context.Context, scheduleID string) ([]models.SemesterScheduleSlot, error) {
+	return s.slots, nil
+}
+
+func (s scheduleWarningSlotsStub) ListTeacherTermSlots(ctx context.Context, teacherID models.TeacherID, termID models.TermID, scheduleID string, classID models.ClassID) ([]models.SemesterScheduleClassSlot, error) {
+	return s.termSlots, nil
+}
+
+func TestScheduleRevalidationServiceRevalidateTeacher(t *testing.T) {
+	slot := func(day, timeSlot int, subjectID models.SubjectID) models.SemesterScheduleSlot {
+		return models.SemesterScheduleSlot{SemesterScheduleID: "sch-1", DayOfWeek: day, TimeSlot: timeSlot, SubjectID: subjectID, TeacherID: "teacher-1"}
+	}
+	slots := []models.SemesterScheduleSlot{slot(1, 1, "math"), slot(1, 2, "physics"), slot(2, 1, "math")}
+	termSlots := make([]models.SemesterScheduleClassSlot, 0, len(slots)+1)
+	for _, s := range slots {
+		termSlots = append(termSlots, models.SemesterScheduleClassSlot{SemesterScheduleSlot: s, ClassID: "class-a"})
+	}
+	termSlots = append(termSlots, models.SemesterScheduleClassSlot{SemesterScheduleSlot: slot(1, 1, "math"), ClassID: "class-b"})
+
+	store := &scheduleWarningStoreStub{schedules: map[string]*models.SemesterSchedule{
+		"sch-1": {ID: "sch-1", TermID: "term-1", ClassID: "class-a", Status: models.SemesterScheduleStatusPublished},
+	}}
+	svc := NewScheduleRevalidationService(
+		store,
+		scheduleWarningSlotsStub{slots: slots, termSlots: termSlots},
+		assignmentRepoSchedulerStub{items: []models.TeacherAssignment{{TeacherID: "teacher-1", ClassID: "class-a", SubjectID: "math", TermID: "term-1"}}},
+		preferenceRepoSchedulerStub{items: map[string]*models.TeacherPreference{
+			"teacher-1": {
+				TeacherID:      "teacher-1",
+				MaxLoadPerDay:  2,
+				MaxLoadPerWeek: 3,
+				Unavailable:    types.JSONText(`[{"day_of_week":"TUESDAY","time_range":"1"}]`),
+			},
+		}},
+		nil,
+	)
+	detectedAt := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return detectedAt }
+
+	require.NoError(t, svc.RevalidateTeacher(context.Background(), "teacher-1"))
+
+	warnings := store.warnings["sch-1"]
+	kinds := make([]models.ScheduleWarningType, len(warnings))
+	for i, warning := range warnings {
+		kinds[i] = warning.Type
+		assert.Equal(t, detectedAt, warning.DetectedAt)
+	}
+	assert.Equal(t, []models.ScheduleWarningType{
+		models.ScheduleWarningTeacherDoubleBooked,
+		models.ScheduleWarningTeacherUnassigned,
+		models.ScheduleWarningTeacherUnavailable,
+		models.ScheduleWarningDailyLoad,
+		models.ScheduleWarningWeeklyLoad,
+	}, kinds)
+	assert.Contains(t, warnings[0].Message, "class-b")
+	assert.Equal(t, "physics", *warnings[1].SubjectID)
+	assert.Equal(t, 2, *warnings[2].DayOfWeek)
+	assert.Equal(t, 1, *warnings[3].DayOfWeek)
+	assert.Nil(t, warnings[4].TimeSlot)
+
+	listed, err := svc.Warnings(context.Background(), "sch-1")
+	require.NoError(t, err)
+	assert.Len(t, listed, 5)
+}
+
+func TestScheduleRevalidationServiceRevalidateRequiresPublished(t *testing.T) {
+	store := &scheduleWarningStoreStub{schedules: map[string]*models.SemesterSchedule{
+		"sch-draft": {ID: "sch-draft", Status: models.SemesterScheduleStatusDraft},
+	}}
+	svc := NewScheduleRevalidationService(store, scheduleWarningSlotsStub{}, assignmentRepoSchedulerStub{}, nil, nil)
+
+	_, err := svc.Revalidate(context.Background(), "sch-draft")
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Warnings(context.Background(), "missing")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+
+	warnings, err := svc.Warnings(context.Background(), "sch-draft")
+	require.NoError(t, err)
+	assert.NotNil(t, warnings)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// Score rates a hand-built timetable with the generator's penalties and conflict checks. Nothing is
+// cached or persisted, so the result cannot be saved; teachers' preferences and their daily
+// schedules elsewhere in the term are taken into account as they are during generation.
+func (s *ScheduleGeneratorService) Score(ctx context.Context, req dto.ScoreScheduleRequest) (*dto.ScoreScheduleResponse, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule score payload")
+	}
+	if _, err := s.ensureTermAndClass(ctx, req.TermID, req.ClassID); err != nil {
+		return nil, err
+	}
+	constraintSet, err := resolveScheduleConstraints(s.constraints, req.HardConstraints, req.SoftConstraints)
+	if err != nil {
+		return nil, err
+	}
+	days := normalizeDays(req.Days)
+	if len(days) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "days must contain at least one entry between 1-6")
+	}
+
+	loads := req.SubjectLoads
+	if len(loads) == 0 {
+		loads = subjectLoadsFromSlots(req.Slots)
+	}
+	known := indexSubjectLoads(loads)
+	for i, slot := range req.Slots {
+		if _, ok := known[subjectLoadKey(slot.SubjectID, slot.TeacherID)]; !ok {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("slot %d: subject %s with teacher %s is not part of subjectLoads", i+1, slot.SubjectID, slot.TeacherID))
+		}
+	}
+	if err := s.ensureSubjectsExist(ctx, loads); err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignments.ListByClassAndTerm(ctx, req.ClassID, req.TermID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher assignments")
+	}
+	assignmentMap := mapAssignments(assignments)
+	if err := validateSubjectLoads(loads, assignmentMap); err != nil {
+		return nil, err
+	}
+
+	availability, err := s.buildTeacherAvailability(ctx, req.TermID, assignmentMap, loads)
+	if err != nil {
+		return nil, err
+	}
+	state := newSchedulerState(days, req.TimeSlotsPerDay, availability)
+	state.useConstraints(constraintSet, loads)
+	for i, slot := range req.Slots {
+		key := slotKey{Day: slot.DayOfWeek, Time: slot.TimeSlot}
+		if err := state.checkCell(key); err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("slot %d: %s", i+1, err.Error()))
+		}
+		if _, taken := state.classSlots[key]; taken {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("slot %d: day %d slot %d is already taken", i+1, key.Day, key.Time))
+		}
+		load := known[subjectLoadKey(slot.SubjectID, slot.TeacherID)]
+		state.put(dto.ScheduleSlotProposal{SubjectID: slot.SubjectID, TeacherID: slot.TeacherID, Room: slot.Room, BlockSize: slotBlockSize(loadBlockSize(load))}, key)
+	}
+
+	conflicts := state.revalidate(loads)
+	score, stats := scoreSchedule(state, len(conflicts), dto.ScheduleImprovementStats{})
+	return &dto.ScoreScheduleResponse{
+		Score:           score,
+		ConflictPenalty: float64(len(conflicts)),
+		Slots:           state.exportSlots(),
+		Conflicts:       conflicts,
+		Stats:           stats,
+	}, nil
+}
+
+// subjectLoadsFromSlots derives each subject-teacher pair's weekly count from the timetable, in
+// order of first appearance.
+func subjectLoadsFromSlots(slots []dto.ScheduleSlotInput) []dto.SubjectLoadRequest {
+	var loads []dto.SubjectLoadRequest
+	index := make(map[string]int, len(slots))
+	for _, slot := range slots {
+		key := subjectLoadKey(slot.SubjectID, slot.TeacherID)
+		if i, ok := index[key]; ok {
+			loads[i].WeeklyCount++
+			continue
+		}
+		index[key] = len(loads)
+		loads = append(loads, dto.SubjectLoadRequest{SubjectID: slot.SubjectID, TeacherID: slot.TeacherID, WeeklyCount: 1})
+	}
+	return loads
+}
@@ -23,6 +23,7 @@ type scheduleRepository interface {
 	Create(ctx context.Context, schedule *models.Schedule) error
 	BulkCreate(ctx context.Context, schedules []models.Schedule) error
 	Update(ctx context.Context, schedule *models.Schedule) error
+	UpdateFields(ctx context.Context, schedule *models.Schedule, columns []string) error
 	Delete(ctx context.Context, id string) error
 }
 
@@ -48,6 +49,17 @@ type UpdateScheduleRequest struct {
 	Room      string `json:"room" validate:"required"`
 }
 
+// PatchScheduleRequest updates only the fields present in the payload.
+type PatchScheduleRequest struct {
+	TermID    *string `json:"term_id" validate:"omitnil,required"`
+	ClassID   *string `json:"class_id" validate:"omitnil,required"`
+	SubjectID *string `json:"subject_id" validate:"omitnil,required"`
+	TeacherID *string `json:"teacher_id" validate:"omitnil,required"`
+	DayOfWeek *string `json:"day_of_week" validate:"omitnil,required"`
+	TimeSlot  *string `json:"time_slot" validate:"omitnil,required"`
+	Room      *string `json:"room" validate:"omitnil,required"`
+}
+
 // BulkCreateSchedulesRequest holds multiple schedules for creation.
 type BulkCreateSchedulesRequest struct {
 	Items          []CreateScheduleRequest `json:"items" validate:"required,min=1,dive"`
@@ -153,6 +165,9 @@ func (s *ScheduleService) Update(ctx context.Context, id string, req UpdateSched
 		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load schedule")
 	}
+	if err := ensureNotGroupSession(existing); err != nil {
+		return nil, err
+	}
 
 	updated := models.Schedule{
 		ID:        existing.ID,
@@ -175,14 +190,65 @@ func (s *ScheduleService) Update(ctx context.Context, id string, req UpdateSched
 	return &updated, nil
 }
 
+// Patch applies the fields present in req, re-checks conflicts against the merged schedule and
+// writes only the changed columns.
+func (s *ScheduleService) Patch(ctx context.Context, id string, req PatchScheduleRequest) (*models.Schedule, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid schedule payload")
+	}
+
+	schedule, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "schedule not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load schedule")
+	}
+	if err := ensureNotGroupSession(schedule); err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	patch := func(value *string, field *string, column string) {
+		if value != nil {
+			*field = *value
+			columns = append(columns, column)
+		}
+	}
+	patch(req.TermID, &schedule.TermID, "term_id")
+	patch(req.ClassID, &schedule.ClassID, "class_id")
+	patch(req.SubjectID, &schedule.SubjectID, "subject_id")
+	patch(req.TeacherID, &schedule.TeacherID, "teacher_id")
+	patch(req.DayOfWeek, &schedule.DayOfWeek, "day_of_week")
+	patch(req.TimeSlot, &schedule.TimeSlot, "time_slot")
+	patch(req.Room, &schedule.Room, "room")
+	if len(columns) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "no fields to update")
+	}
+	schedule.DayOfWeek = strings.ToUpper(schedule.DayOfWeek)
+
+	if err := s.ensureNoConflict(ctx, *schedule, schedule.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateFields(ctx, schedule, columns); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update schedule")
+	}
+	return schedule, nil
+}
+
 // Delete removes a schedule entry.
 func (s *ScheduleService) Delete(ctx context.Context, id string) error {
-	if _, err := s.repo.FindByID(ctx, id); err != nil {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return appErrors.Clone(appErrors.ErrNotFound, "schedule not found")
 		}
 		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load schedule")
 	}
+	if err := ensureNotGroupSession(existing); err != nil {
+		return err
+	}
 
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete schedule")
@@ -241,6 +307,15 @@ func (s *ScheduleService) BulkCreate(ctx context.Context, req BulkCreateSchedule
 	return result, nil
 }
 
+// ensureNotGroupSession refuses changes to one class's copy of a lesson group session, which would
+// leave the group's member classes out of step.
+func ensureNotGroupSession(schedule *models.Schedule) error {
+	if schedule.LessonGroupID != nil {
+		return appErrors.Clone(appErrors.ErrConflict, "schedule belongs to a lesson group; change it through the lesson group's sessions")
+	}
+	return nil
+}
+
 func (s *ScheduleService) ensureNoConflict(ctx context.Context, schedule models.Schedule, ignoreID string) error {
 	existing, err := s.repo.FindConflicts(ctx, schedule.TermID, schedule.DayOfWeek, schedule.TimeSlot)
 	if err != nil {
@@ -252,19 +327,20 @@ func (s *ScheduleService) ensureNoConflict(ctx context.Context, schedule models.
 			continue
 		}
 		if item.ClassID == schedule.ClassID {
-			return s.wrapConflict("CLASS", "class already scheduled for this slot", item)
+			return wrapScheduleConflict("CLASS", "class already scheduled for this slot", item)
 		}
 		if item.TeacherID == schedule.TeacherID {
-			return s.wrapConflict("TEACHER", "teacher already scheduled for this slot", item)
+			return wrapScheduleConflict("TEACHER", "teacher already scheduled for this slot", item)
 		}
 		if strings.EqualFold(item.Room, schedule.Room) {
-			return s.wrapConflict("ROOM", "room already booked for this slot", item)
+			return wrapScheduleConflict("ROOM", "room already booked for this slot", item)
 		}
 	}
 	return nil
 }
 
-func (s *ScheduleService) wrapConflict(conflictType, message string, existing models.Schedule) error {
+// wrapScheduleConflict reports a clash with an existing schedule as a conflict error.
+func wrapScheduleConflict(conflictType, message string, existing models.Schedule) error {
 	conflict := models.ScheduleConflict{
 		ScheduleID: existing.ID,
 		TermID:     existing.TermID,
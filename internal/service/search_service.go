@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	minSearchQueryLength = 2
+	defaultSearchLimit   = 5
+	maxSearchLimit       = 20
+)
+
+type searchRepository interface {
+	Teachers(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+	Students(ctx context.Context, query string, classIDs []string, limit int) ([]models.SearchHit, error)
+	Classes(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+	Subjects(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+}
+
+type searchArchiveLister interface {
+	List(ctx context.Context, filter dto.ArchiveFilter, actor *models.JWTClaims) ([]models.ArchiveItem, error)
+}
+
+// searchRoleTypes lists the groups each role may search. Teachers only see students enrolled in
+// classes they teach, and archives are filtered by the archive scope rules.
+var searchRoleTypes = map[models.UserRole][]models.SearchType{
+	models.RoleSuperAdmin: models.SearchTypes,
+	models.RoleAdmin:      models.SearchTypes,
+	models.RoleTeacher:    models.SearchTypes,
+	models.RoleStudent:    {models.SearchTypeClass, models.SearchTypeSubject},
+}
+
+// SearchService powers the global search bar across core entities.
+type SearchService struct {
+	repo        searchRepository
+	archives    searchArchiveLister
+	assignments ports.TeacherAssignmentLister
+	logger      *zap.Logger
+}
+
+// NewSearchService constructs a SearchService. archives may be nil when the archive feature is disabled.
+func NewSearchService(repo searchRepository, archives searchArchiveLister, assignments ports.TeacherAssignmentLister, logger *zap.Logger) *SearchService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &SearchService{repo: repo, archives: archives, assignments: assignments, logger: logger}
+}
+
+// Search returns one group per type the actor may see, in models.SearchTypes order.
+func (s *SearchService) Search(ctx context.Context, req dto.SearchRequest, actor *models.JWTClaims) (*dto.SearchResponse, error) {
+	if actor == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	query := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(query) < minSearchQueryLength {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "q must be at least 2 characters")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	requested := make(map[models.SearchType]bool, len(req.Types))
+	for _, t := range req.Types {
+		if !isSearchType(t) {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "unknown search type "+string(t))
+		}
+		requested[t] = true
+	}
+	allowed := searchRoleTypes[actor.Role]
+	if len(allowed) == 0 {
+		return nil, appErrors.ErrForbidden
+	}
+
+	resp := &dto.SearchResponse{Query: query, Groups: []dto.SearchGroup{}}
+	for _, searchType := range allowed {
+		if len(requested) > 0 && !requested[searchType] {
+			continue
+		}
+		if searchType == models.SearchTypeArchive && s.archives == nil {
+			continue
+		}
+		hits, err := s.searchType(ctx, searchType, query, limit, actor)
+		if err != nil {
+			return nil, err
+		}
+		resp.Groups = append(resp.Groups, dto.SearchGroup{Type: searchType, Items: hits})
+	}
+	return resp, nil
+}
+
+func (s *SearchService) searchType(ctx context.Context, searchType models.SearchType, query string, limit int, actor *models.JWTClaims) ([]models.SearchHit, error) {
+	var (
+		hits []models.SearchHit
+		err  error
+	)
+	switch searchType {
+	case models.SearchTypeTeacher:
+		hits, err = s.repo.Teachers(ctx, query, limit)
+	case models.SearchTypeStudent:
+		var classIDs []string
+		if actor.Role == models.RoleTeacher {
+			if classIDs, err = s.teacherClassIDs(ctx, actor.UserID); err != nil {
+				return nil, err
+			}
+		}
+		hits, err = s.repo.Students(ctx, query, classIDs, limit)
+	case models.SearchTypeClass:
+		hits, err = s.repo.Classes(ctx, query, limit)
+	case models.SearchTypeSubject:
+		hits, err = s.repo.Subjects(ctx, query, limit)
+	case models.SearchTypeArchive:
+		return s.searchArchives(ctx, query, limit, actor)
+	}
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to search "+string(searchType)+"s")
+	}
+	return hits, nil
+}
+
+func (s *SearchService) searchArchives(ctx context.Context, query string, limit int, actor *models.JWTClaims) ([]models.SearchHit, error) {
+	items, err := s.archives.List(ctx, dto.ArchiveFilter{Search: query}, actor)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]models.SearchHit, 0, limit)
+	for _, item := range items {
+		if len(hits) == limit {
+			break
+		}
+		category := item.Category
+		hits = append(hits, models.SearchHit{ID: item.ID, Label: item.Title, Detail: &category})
+	}
+	return hits, nil
+}
+
+// teacherClassIDs returns the classes a teacher is assigned to; the result is never nil so the
+// repository always applies the restriction.
+func (s *SearchService) teacherClassIDs(ctx context.Context, teacherID string) ([]string, error) {
+	classIDs := []string{}
+	if s.assignments == nil {
+		return classIDs, nil
+	}
+	assignments, err := s.assignments.ListByTeacher(ctx, teacherID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher assignments")
+	}
+	seen := make(map[string]struct{}, len(assignments))
+	for _, assignment := range assignments {
+		if _, ok := seen[assignment.ClassID]; ok {
+			continue
+		}
+		seen[assignment.ClassID] = struct{}{}
+		classIDs = append(classIDs, assignment.ClassID)
+	}
+	return classIDs, nil
+}
+
+func isSearchType(value models.SearchType) bool {
+	for _, t := range models.SearchTypes {
+		if t == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type searchRepoStub struct {
+	studentClassIDs []string
+	calls           []models.SearchType
+}
+
+func (s *searchRepoStub) Teachers(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	s.calls = append(s.calls, models.SearchTypeTeacher)
+	return []models.SearchHit{{ID: "t1", Label: "Budi"}}, nil
+}
+
+func (s *searchRepoStub) Students(ctx context.Context, query string, classIDs []string, limit int) ([]models.SearchHit, error) {
+	s.calls = append(s.calls, models.SearchTypeStudent)
+	s.studentClassIDs = classIDs
+	return []models.SearchHit{{ID: "s1", Label: "Budi Santoso"}}, nil
+}
+
+func (s *searchRepoStub) Classes(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	s.calls = append(s.calls, models.SearchTypeClass)
+	return []models.SearchHit{}, nil
+}
+
+func (s *searchRepoStub) Subjects(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	s.calls = append(s.calls, models.SearchTypeSubject)
+	return []models.SearchHit{}, nil
+}
+
+type searchArchiveStub struct {
+	filter dto.ArchiveFilter
+	items  []models.ArchiveItem
+}
+
+func (s *searchArchiveStub) List(ctx context.Context, filter dto.ArchiveFilter, actor *models.JWTClaims) ([]models.ArchiveItem, error) {
+	s.filter = filter
+	return s.items, nil
+}
+
+type searchAssignmentStub struct{}
+
+func (searchAssignmentStub) ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error) {
+	return []models.TeacherAssignmentDetail{
+		{TeacherAssignment: models.TeacherAssignment{ClassID: "class-1"}},
+		{TeacherAssignment: models.TeacherAssignment{ClassID: "class-1"}},
+		{TeacherAssignment: models.TeacherAssignment{ClassID: "class-2"}},
+	}, nil
+}
+
+func TestSearchServiceGroupsByType(t *testing.T) {
+	repo := &searchRepoStub{}
+	archives := &searchArchiveStub{items: []models.ArchiveItem{{ID: "a1", Title: "Rapor Budi", Category: "REPORT"}, {ID: "a2", Title: "Budi 2"}}}
+	svc := NewSearchService(repo, archives, searchAssignmentStub{}, zap.NewNop())
+
+	resp, err := svc.Search(context.Background(), dto.SearchRequest{Query: " budi ", Limit: 1}, &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, "budi", resp.Query)
+	require.Len(t, resp.Groups, 5)
+	assert.Equal(t, models.SearchTypeTeacher, resp.Groups[0].Type)
+	assert.Nil(t, repo.studentClassIDs, "admins search every student")
+	assert.Equal(t, "budi", archives.filter.Search)
+	archiveGroup := resp.Groups[4]
+	assert.Equal(t, models.SearchTypeArchive, archiveGroup.Type)
+	require.Len(t, archiveGroup.Items, 1)
+	assert.Equal(t, "REPORT", *archiveGroup.Items[0].Detail)
+}
+
+func TestSearchServiceAppliesRoleScopes(t *testing.T) {
+	repo := &searchRepoStub{}
+	svc := NewSearchService(repo, nil, searchAssignmentStub{}, zap.NewNop())
+
+	resp, err := svc.Search(context.Background(), dto.SearchRequest{Query: "budi", Types: []models.SearchType{models.SearchTypeStudent, models.SearchTypeArchive}}, &models.JWTClaims{UserID: "teacher-1", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	require.Len(t, resp.Groups, 1, "archives are skipped when the feature is disabled")
+	assert.Equal(t, []string{"class-1", "class-2"}, repo.studentClassIDs)
+
+	repo.calls = nil
+	resp, err = svc.Search(context.Background(), dto.SearchRequest{Query: "budi"}, &models.JWTClaims{UserID: "student-1", Role: models.RoleStudent})
+	require.NoError(t, err)
+	assert.Equal(t, []models.SearchType{models.SearchTypeClass, models.SearchTypeSubject}, repo.calls)
+	assert.Len(t, resp.Groups, 2)
+}
+
+func TestSearchServiceValidation(t *testing.T) {
+	svc := NewSearchService(&searchRepoStub{}, nil, nil, zap.NewNop())
+	admin := &models.JWTClaims{UserID: "admin", Role: models.RoleAdmin}
+
+	_, err := svc.Search(context.Background(), dto.SearchRequest{Query: "b"}, admin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Search(context.Background(), dto.SearchRequest{Query: "budi", Types: []models.SearchType{"grade"}}, admin)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Search(context.Background(), dto.SearchRequest{Query: "budi"}, nil)
+	assert.Equal(t, appErrors.ErrUnauthorized.Code, appErrors.FromError(err).Code)
+}
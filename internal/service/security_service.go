@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const anonymousActor = "anonymous"
+
+type securityDenialReader interface {
+	DenialCounts(ctx context.Context, since time.Time) ([]models.AccessDenialCount, error)
+	RecentDenials(ctx context.Context, limit int) ([]models.AccessDenialRecord, error)
+}
+
+// SecurityServiceConfig tunes denial alerting.
+type SecurityServiceConfig struct {
+	AlertThreshold int
+	AlertWindow    time.Duration
+	RecentLimit    int
+}
+
+// SecurityService records access denials and summarises them for administrators.
+type SecurityService struct {
+	audit  ports.AuditLogger
+	repo   securityDenialReader
+	logger *zap.Logger
+	cfg    SecurityServiceConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	recent map[string][]time.Time
+	swept  time.Time
+}
+
+// NewSecurityService constructs a SecurityService.
+func NewSecurityService(audit ports.AuditLogger, repo securityDenialReader, logger *zap.Logger, cfg SecurityServiceConfig) *SecurityService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.AlertThreshold <= 0 {
+		cfg.AlertThreshold = 20
+	}
+	if cfg.AlertWindow <= 0 {
+		cfg.AlertWindow = time.Hour
+	}
+	if cfg.RecentLimit <= 0 {
+		cfg.RecentLimit = 20
+	}
+	return &SecurityService{
+		audit:  audit,
+		repo:   repo,
+		logger: logger,
+		cfg:    cfg,
+		now:    time.Now,
+		recent: make(map[string][]time.Time),
+	}
+}
+
+// RecordDenial stores a denial in the audit log and warns once an actor crosses the alert threshold.
+func (s *SecurityService) RecordDenial(ctx context.Context, denial models.AccessDenial) error {
+	details, _ := json.Marshal(map[string]interface{}{
+		"method": denial.Method,
+		"path":   denial.Path,
+		"reason": denial.Reason,
+		"role":   denial.Role,
+	})
+	err := s.audit.CreateAuditLog(ctx, &models.AuditLog{
+		UserID:     denial.UserID,
+		Action:     models.AuditActionAccessDenied,
+		Resource:   denial.Route,
+		ResourceID: denial.ResourceID,
+		NewValues:  details,
+		IPAddress:  denial.IPAddress,
+		UserAgent:  denial.UserAgent,
+	})
+	if err != nil {
+		s.logger.Warn("failed to record access denial", zap.String("route", denial.Route), zap.Error(err))
+	}
+
+	actor := anonymousActor
+	if denial.UserID != nil {
+		actor = *denial.UserID
+	}
+	if count := s.track(actor); count == s.cfg.AlertThreshold {
+		s.logger.Warn("access denial threshold reached",
+			zap.String("actor", actor),
+			zap.Int("denials", count),
+			zap.Duration("window", s.cfg.AlertWindow),
+			zap.String("route", denial.Route),
+		)
+	}
+	return err
+}
+
+// track records a denial for actor and returns how many fall inside the alert window. Once per
+// window it also drops the actors with no denial inside it, so the map does not grow without bound.
+func (s *SecurityService) track(actor string) int {
+	now := s.now()
+	cutoff := now.Add(-s.cfg.AlertWindow)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.swept.After(cutoff) {
+		for key, times := range s.recent {
+			if !times[len(times)-1].After(cutoff) {
+				delete(s.recent, key)
+			}
+		}
+		s.swept = now
+	}
+	kept := s.recent[actor][:0]
+	for _, at := range s.recent[actor] {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	kept = append(kept, now)
+	s.recent[actor] = kept
+	return len(kept)
+}
+
+// Dashboard returns denial counts for the alert window along with the most recent denials.
+func (s *SecurityService) Dashboard(ctx context.Context) (*dto.SecurityDashboardResponse, error) {
+	since := s.now().Add(-s.cfg.AlertWindow).UTC()
+	counts, err := s.repo.DenialCounts(ctx, since)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to count access denials")
+	}
+	recent, err := s.repo.RecentDenials(ctx, s.cfg.RecentLimit)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list access denials")
+	}
+	if recent == nil {
+		recent = []models.AccessDenialRecord{}
+	}
+
+	resp := &dto.SecurityDashboardResponse{
+		Window:   s.cfg.AlertWindow.String(),
+		Since:    since,
+		ByReason: make(map[string]int),
+		Recent:   recent,
+		Alert:    dto.SecurityAlertStatus{Threshold: s.cfg.AlertThreshold},
+	}
+	routes := make(map[string]int)
+	actors := make(map[string]int)
+	for _, count := range counts {
+		resp.TotalDenials += count.Total
+		resp.ByReason[count.Reason] += count.Total
+		routes[count.Route] += count.Total
+		actor := anonymousActor
+		if count.UserID != nil {
+			actor = *count.UserID
+		}
+		actors[actor] += count.Total
+	}
+	resp.TopRoutes = rankSecurityCounts(routes)
+	resp.TopActors = rankSecurityCounts(actors)
+	for _, actor := range resp.TopActors {
+		if actor.Total >= s.cfg.AlertThreshold {
+			resp.Alert.Actors = append(resp.Alert.Actors, actor.Key)
+		}
+	}
+	resp.Alert.Triggered = len(resp.Alert.Actors) > 0
+	return resp, nil
+}
+
+func rankSecurityCounts(totals map[string]int) []dto.SecurityCount {
+	result := make([]dto.SecurityCount, 0, len(totals))
+	for key, total := range totals {
+		result = append(result, dto.SecurityCount{Key: key, Total: total})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total == result[j].Total {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Total > result[j].Total
+	})
+	return result
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type securityAuditStub struct {
+	logs []models.AuditLog
+}
+
+func (s *securityAuditStub) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	s.logs = append(s.logs, *log)
+	return nil
+}
+
+type securityDenialReaderStub struct {
+	since  time.Time
+	counts []models.AccessDenialCount
+}
+
+func (s *securityDenialReaderStub) DenialCounts(ctx context.Context, since time.Time) ([]models.AccessDenialCount, error) {
+	s.since = since
+	return s.counts, nil
+}
+
+func (s *securityDenialReaderStub) RecentDenials(ctx context.Context, limit int) ([]models.AccessDenialRecord, error) {
+	return nil, nil
+}
+
+func TestSecurityServiceRecordDenial(t *testing.T) {
+	audit := &securityAuditStub{}
+	svc := NewSecurityService(audit, &securityDenialReaderStub{}, nil, SecurityServiceConfig{AlertThreshold: 2})
+	userID := "teacher-1"
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, svc.RecordDenial(context.Background(), models.AccessDenial{
+			UserID: &userID,
+			Method: "DELETE",
+			Route:  "/teachers/:id",
+			Reason: models.AccessDenialRBAC,
+		}))
+	}
+
+	require.Len(t, audit.logs, 3)
+	assert.Equal(t, models.AuditActionAccessDenied, audit.logs[0].Action)
+	assert.Equal(t, "/teachers/:id", audit.logs[0].Resource)
+	assert.JSONEq(t, `{"method":"DELETE","path":"","reason":"RBAC","role":""}`, string(audit.logs[0].NewValues))
+	assert.Len(t, svc.recent["teacher-1"], 3)
+}
+
+func TestSecurityServiceForgetsIdleActors(t *testing.T) {
+	svc := NewSecurityService(&securityAuditStub{}, &securityDenialReaderStub{}, nil, SecurityServiceConfig{AlertWindow: time.Hour})
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	svc.track("teacher-1")
+	svc.track("student-1")
+	now = now.Add(30 * time.Minute)
+	svc.track("student-1")
+	now = now.Add(45 * time.Minute)
+	assert.Equal(t, 2, svc.track("student-1"))
+
+	assert.NotContains(t, svc.recent, "teacher-1", "actors with no denial in the window are dropped")
+	assert.Len(t, svc.recent, 1)
+}
+
+func TestSecurityServiceDashboard(t *testing.T) {
+	teacher := "teacher-1"
+	student := "student-1"
+	reader := &securityDenialReaderStub{counts: []models.AccessDenialCount{
+		{UserID: &teacher, Route: "/teachers/:id", Reason: "RBAC", Total: 4},
+		{UserID: &teacher, Route: "/reports/status/:id", Reason: "OWNERSHIP", Total: 2},
+		{UserID: &student, Route: "/teachers/:id", Reason: "RBAC", Total: 1},
+	}}
+	svc := NewSecurityService(&securityAuditStub{}, reader, nil, SecurityServiceConfig{AlertThreshold: 5, AlertWindow: 30 * time.Minute})
+	now := time.Date(2024, 11, 11, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	resp, err := svc.Dashboard(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-30*time.Minute), reader.since)
+	assert.Equal(t, 7, resp.TotalDenials)
+	assert.Equal(t, map[string]int{"RBAC": 5, "OWNERSHIP": 2}, resp.ByReason)
+	require.Len(t, resp.TopRoutes, 2)
+	assert.Equal(t, "/teachers/:id", resp.TopRoutes[0].Key)
+	assert.Equal(t, 5, resp.TopRoutes[0].Total)
+	assert.True(t, resp.Alert.Triggered)
+	assert.Equal(t, []string{"teacher-1"}, resp.Alert.Actors)
+	assert.NotNil(t, resp.Recent)
+}
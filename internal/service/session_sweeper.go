@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type idleSessionRevoker interface {
+	RevokeIdleSessions(ctx context.Context, idleSince, at time.Time) (int64, error)
+}
+
+// SessionSweeperConfig tunes how idle sessions are ended.
+type SessionSweeperConfig struct {
+	// Interval between sweeps.
+	Interval time.Duration
+	// IdleTimeout is how long a session may go without a refresh or heartbeat.
+	IdleTimeout time.Duration
+}
+
+// SessionSweeper revokes the refresh tokens of idle sessions. Refresh and heartbeat already refuse
+// idle sessions; the sweep makes the revocation visible in the sessions list and the table.
+type SessionSweeper struct {
+	repo   idleSessionRevoker
+	cfg    SessionSweeperConfig
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewSessionSweeper constructs a SessionSweeper with defaults.
+func NewSessionSweeper(repo idleSessionRevoker, cfg SessionSweeperConfig, logger *zap.Logger) *SessionSweeper {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &SessionSweeper{repo: repo, cfg: cfg, logger: logger, now: time.Now}
+}
+
+// Start sweeps once and then on every interval until ctx is done.
+func (s *SessionSweeper) Start(ctx context.Context) {
+	go s.run(ctx)
+}
+
+func (s *SessionSweeper) run(ctx context.Context) {
+	s.Sweep(ctx)
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep revokes sessions idle for longer than the timeout and returns how many were ended.
+func (s *SessionSweeper) Sweep(ctx context.Context) int64 {
+	if s.cfg.IdleTimeout <= 0 {
+		return 0
+	}
+	now := s.now().UTC()
+	ended, err := s.repo.RevokeIdleSessions(ctx, now.Add(-s.cfg.IdleTimeout), now)
+	if err != nil {
+		s.logger.Warn("idle session sweep failed", zap.Error(err))
+		return 0
+	}
+	if ended > 0 {
+		s.logger.Info("idle sessions ended", zap.Int64("count", ended), zap.Duration("idle_timeout", s.cfg.IdleTimeout))
+	}
+	return ended
+}
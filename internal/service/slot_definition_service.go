@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const slotClockLayout = "15:04"
+
+type slotDefinitionRepository interface {
+	ListByTerm(ctx context.Context, termID string) ([]models.SlotDefinition, error)
+	FindByID(ctx context.Context, id string) (*models.SlotDefinition, error)
+	Create(ctx context.Context, definition *models.SlotDefinition) error
+	Update(ctx context.Context, definition *models.SlotDefinition) error
+	Delete(ctx context.Context, id string) error
+}
+
+// SlotDefinitionService manages the per-term mapping between slot numbers and real times.
+// It also implements SlotTimeLabeler so dashboards and exports render "slot 3" as "08:30–09:15".
+type SlotDefinitionService struct {
+	repo      slotDefinitionRepository
+	terms     ports.TermReader
+	fallback  StaticSlotLabels
+	validator *validator.Validate
+	logger    *zap.Logger
+}
+
+// NewSlotDefinitionService constructs a SlotDefinitionService. Fallback labels are used for terms
+// without any slot definitions.
+func NewSlotDefinitionService(repo slotDefinitionRepository, terms ports.TermReader, fallback map[int]string, validate *validator.Validate, logger *zap.Logger) *SlotDefinitionService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &SlotDefinitionService{
+		repo:      repo,
+		terms:     terms,
+		fallback:  StaticSlotLabels(fallback),
+		validator: validate,
+		logger:    logger,
+	}
+}
+
+// List returns the slot definitions of a term.
+func (s *SlotDefinitionService) List(ctx context.Context, termID string) ([]models.SlotDefinition, error) {
+	if err := s.ensureTerm(ctx, termID); err != nil {
+		return nil, err
+	}
+	definitions, err := s.repo.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list slot definitions")
+	}
+	if definitions == nil {
+		definitions = []models.SlotDefinition{}
+	}
+	return definitions, nil
+}
+
+// Create adds a slot definition to a term.
+func (s *SlotDefinitionService) Create(ctx context.Context, termID string, req dto.SlotDefinitionRequest) (*models.SlotDefinition, error) {
+	if err := s.ensureTerm(ctx, termID); err != nil {
+		return nil, err
+	}
+	definition := &models.SlotDefinition{TermID: termID}
+	if err := s.apply(ctx, definition, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, definition); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create slot definition")
+	}
+	return definition, nil
+}
+
+// Update replaces the times, label and break flag of a slot definition.
+func (s *SlotDefinitionService) Update(ctx context.Context, termID, id string, req dto.SlotDefinitionRequest) (*models.SlotDefinition, error) {
+	definition, err := s.find(ctx, termID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, definition, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, definition); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update slot definition")
+	}
+	return definition, nil
+}
+
+// Delete removes a slot definition.
+func (s *SlotDefinitionService) Delete(ctx context.Context, termID, id string) error {
+	if _, err := s.find(ctx, termID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete slot definition")
+	}
+	return nil
+}
+
+// SlotLabels implements SlotTimeLabeler.
+func (s *SlotDefinitionService) SlotLabels(ctx context.Context, termID string) (map[int]string, error) {
+	definitions, err := s.repo.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions) == 0 {
+		return s.fallback, nil
+	}
+	labels := make(map[int]string, len(definitions))
+	for _, definition := range definitions {
+		labels[definition.SlotIndex] = definition.DisplayLabel()
+	}
+	return labels, nil
+}
+
+func (s *SlotDefinitionService) apply(ctx context.Context, definition *models.SlotDefinition, req dto.SlotDefinitionRequest) error {
+	if err := s.validator.Struct(req); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid slot definition payload")
+	}
+	start, err := time.Parse(slotClockLayout, req.StartTime)
+	if err != nil {
+		return appErrors.Clone(appErrors.ErrValidation, "startTime must use HH:MM format")
+	}
+	end, err := time.Parse(slotClockLayout, req.EndTime)
+	if err != nil {
+		return appErrors.Clone(appErrors.ErrValidation, "endTime must use HH:MM format")
+	}
+	if !end.After(start) {
+		return appErrors.Clone(appErrors.ErrValidation, "endTime must be after startTime")
+	}
+
+	existing, err := s.repo.ListByTerm(ctx, definition.TermID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load slot definitions")
+	}
+	for _, other := range existing {
+		if other.ID == definition.ID {
+			continue
+		}
+		if other.SlotIndex == req.SlotIndex {
+			return appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("slot %d is already defined for this term", req.SlotIndex))
+		}
+		otherStart, startErr := time.Parse(slotClockLayout, other.StartTime)
+		otherEnd, endErr := time.Parse(slotClockLayout, other.EndTime)
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		if start.Before(otherEnd) && otherStart.Before(end) {
+			return appErrors.Clone(appErrors.ErrConflict, fmt.Sprintf("slot overlaps slot %d (%s)", other.SlotIndex, other.DisplayLabel()))
+		}
+	}
+
+	definition.SlotIndex = req.SlotIndex
+	definition.StartTime = start.Format(slotClockLayout)
+	definition.EndTime = end.Format(slotClockLayout)
+	definition.Label = req.Label
+	definition.IsBreak = req.IsBreak
+	return nil
+}
+
+func (s *SlotDefinitionService) find(ctx context.Context, termID, id string) (*models.SlotDefinition, error) {
+	definition, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "slot definition not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load slot definition")
+	}
+	if definition.TermID != termID {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "slot definition not found")
+	}
+	return definition, nil
+}
+
+func (s *SlotDefinitionService) ensureTerm(ctx context.Context, termID string) error {
+	if _, err := s.terms.FindByID(ctx, termID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "term not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+	}
+	return nil
+}
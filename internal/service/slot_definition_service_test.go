@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type slotDefinitionRepoStub struct {
+	items map[string]models.SlotDefinition
+}
+
+func newSlotDefinitionRepoStub() *slotDefinitionRepoStub {
+	return &slotDefinitionRepoStub{items: make(map[string]models.SlotDefinition)}
+}
+
+func (s *slotDefinitionRepoStub) ListByTerm(ctx context.Context, termID string) ([]models.SlotDefinition, error) {
+	var result []models.SlotDefinition
+	for _, item := range s.items {
+		if item.TermID == termID {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func (s *slotDefinitionRepoStub) FindByID(ctx context.Context, id string) (*models.SlotDefinition, error) {
+	item, ok := s.items[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &item, nil
+}
+
+func (s *slotDefinitionRepoStub) Create(ctx context.Context, definition *models.SlotDefinition) error {
+	definition.ID = uuid.NewString()
+	s.items[definition.ID] = *definition
+	return nil
+}
+
+func (s *slotDefinitionRepoStub) Update(ctx context.Context, definition *models.SlotDefinition) error {
+	s.items[definition.ID] = *definition
+	return nil
+}
+
+func (s *slotDefinitionRepoStub) Delete(ctx context.Context, id string) error {
+	delete(s.items, id)
+	return nil
+}
+
+func TestSlotDefinitionServiceCreateAndLabels(t *testing.T) {
+	repo := newSlotDefinitionRepoStub()
+	svc := NewSlotDefinitionService(repo, termLookupStub{}, map[int]string{1: "fallback"}, nil, nil)
+	ctx := context.Background()
+
+	labels, err := svc.SlotLabels(ctx, "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", labels[1])
+
+	_, err = svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 3, StartTime: "8:30", EndTime: "09:15"})
+	require.NoError(t, err)
+	breakLabel := "Istirahat"
+	_, err = svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 4, StartTime: "09:15", EndTime: "09:30", Label: &breakLabel, IsBreak: true})
+	require.NoError(t, err)
+
+	labels, err = svc.SlotLabels(ctx, "term-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{3: "08:30–09:15", 4: "Istirahat"}, labels)
+}
+
+func TestSlotDefinitionServiceRejectsInvalidSlots(t *testing.T) {
+	repo := newSlotDefinitionRepoStub()
+	svc := NewSlotDefinitionService(repo, termLookupStub{}, nil, nil, nil)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 1, StartTime: "07:00", EndTime: "07:45"})
+	require.NoError(t, err)
+
+	_, err = svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 2, StartTime: "08:00", EndTime: "07:50"})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 1, StartTime: "08:00", EndTime: "08:45"})
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Create(ctx, "term-1", dto.SlotDefinitionRequest{SlotIndex: 2, StartTime: "07:30", EndTime: "08:15"})
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	updated, err := svc.Update(ctx, "term-1", created.ID, dto.SlotDefinitionRequest{SlotIndex: 1, StartTime: "07:15", EndTime: "08:00"})
+	require.NoError(t, err)
+	assert.Equal(t, "07:15", updated.StartTime)
+
+	_, err = svc.Update(ctx, "term-2", created.ID, dto.SlotDefinitionRequest{SlotIndex: 1, StartTime: "07:15", EndTime: "08:00"})
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+)
+
+// Storage directory names used in /ready, metrics and StorageHealthService.Ensure.
+const (
+	StorageReports  = "reports"
+	StorageArchives = "archives"
+)
+
+// StorageDir is a directory the API writes files to.
+type StorageDir struct {
+	Name string
+	Path string
+}
+
+// StorageHealthConfig sets when a storage directory counts as low on space. Either threshold marks
+// a directory unhealthy; zero disables it.
+type StorageHealthConfig struct {
+	MinFreeBytes   uint64
+	MinFreePercent float64
+	// Interval is how often Start re-checks the directories and how long a result is reused.
+	Interval time.Duration
+}
+
+// StorageHealthService checks that storage directories are writable and have enough free space, so
+// running out of disk shows up in /ready and metrics before exports and uploads start failing.
+type StorageHealthService struct {
+	dirs    []StorageDir
+	cfg     StorageHealthConfig
+	metrics *MetricsService
+	logger  *zap.Logger
+
+	usage func(dir string) (storage.DiskUsage, error)
+	probe func(dir string) error
+	now   func() time.Time
+
+	mu        sync.Mutex
+	statuses  []models.StorageStatus
+	checkedAt time.Time
+}
+
+// NewStorageHealthService constructs the service for dirs.
+func NewStorageHealthService(dirs []StorageDir, cfg StorageHealthConfig, metrics *MetricsService, logger *zap.Logger) *StorageHealthService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &StorageHealthService{
+		dirs:    dirs,
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger,
+		usage:   storage.DiskUsageOf,
+		probe:   storage.ProbeWritable,
+		now:     time.Now,
+	}
+}
+
+// Start re-checks the directories every interval until ctx is cancelled.
+func (s *StorageHealthService) Start(ctx context.Context) {
+	if s == nil || len(s.dirs) == 0 {
+		return
+	}
+	s.Check()
+	ticker := time.NewTicker(s.cfg.Interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Check()
+			}
+		}
+	}()
+}
+
+// Check probes every directory now, records the results as metrics and keeps them for Statuses.
+func (s *StorageHealthService) Check() []models.StorageStatus {
+	if s == nil {
+		return nil
+	}
+	statuses := make([]models.StorageStatus, 0, len(s.dirs))
+	for _, dir := range s.dirs {
+		statuses = append(statuses, s.checkDir(dir))
+	}
+
+	s.mu.Lock()
+	previous := s.statuses
+	s.statuses = statuses
+	s.checkedAt = s.now()
+	s.mu.Unlock()
+
+	for i, status := range statuses {
+		s.metrics.RecordStorageStatus(status)
+		wasHealthy := i >= len(previous) || previous[i].Healthy
+		switch {
+		case !status.Healthy && wasHealthy:
+			s.logger.Warn("storage directory unhealthy",
+				zap.String("dir", status.Name),
+				zap.String("path", status.Path),
+				zap.Uint64("free_bytes", status.FreeBytes),
+				zap.String("error", status.Error))
+		case status.Healthy && !wasHealthy:
+			s.logger.Info("storage directory recovered", zap.String("dir", status.Name), zap.Uint64("free_bytes", status.FreeBytes))
+		}
+	}
+	return statuses
+}
+
+// Statuses returns the latest results, checking again when they are older than the interval.
+func (s *StorageHealthService) Statuses() []models.StorageStatus {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	statuses, checkedAt := s.statuses, s.checkedAt
+	s.mu.Unlock()
+	if checkedAt.IsZero() || s.now().Sub(checkedAt) >= s.cfg.Interval {
+		return s.Check()
+	}
+	return statuses
+}
+
+// Healthy reports whether every directory passed its latest check.
+func (s *StorageHealthService) Healthy() bool {
+	for _, status := range s.Statuses() {
+		if !status.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Ensure returns ErrInsufficientStorage when the named directory failed its latest check. Unknown
+// names pass, so callers need not know which directories are configured.
+func (s *StorageHealthService) Ensure(name string) error {
+	for _, status := range s.Statuses() {
+		if status.Name != name || status.Healthy {
+			continue
+		}
+		if !status.Writable {
+			return appErrors.Clone(appErrors.ErrInsufficientStorage, fmt.Sprintf("%s storage is not writable", name))
+		}
+		return appErrors.Clone(appErrors.ErrInsufficientStorage, fmt.Sprintf("%s storage is low on space (%d MB free)", name, status.FreeBytes>>20))
+	}
+	return nil
+}
+
+func (s *StorageHealthService) checkDir(dir StorageDir) models.StorageStatus {
+	status := models.StorageStatus{Name: dir.Name, Path: dir.Path, CheckedAt: s.now().UTC()}
+	if err := s.probe(dir.Path); err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Writable = true
+
+	usage, err := s.usage(dir.Path)
+	if errors.Is(err, storage.ErrDiskUsageUnsupported) {
+		status.Healthy = true
+		return status
+	}
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.FreeBytes = usage.Free
+	status.TotalBytes = usage.Total
+	status.FreePercent = usage.FreePercent()
+	switch {
+	case s.cfg.MinFreeBytes > 0 && usage.Free < s.cfg.MinFreeBytes:
+		status.Error = fmt.Sprintf("free space below %d MB", s.cfg.MinFreeBytes>>20)
+	case s.cfg.MinFreePercent > 0 && usage.Total > 0 && status.FreePercent < s.cfg.MinFreePercent:
+		status.Error = fmt.Sprintf("free space below %g%%", s.cfg.MinFreePercent)
+	default:
+		status.Healthy = true
+	}
+	return status
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/storage"
+)
+
+// newStorageHealthForTest reports free bytes out of 1000 MB for every directory; a nil probe error
+// marks the directory writable.
+func newStorageHealthForTest(free uint64, probeErr error) *StorageHealthService {
+	svc := NewStorageHealthService(
+		[]StorageDir{{Name: StorageReports, Path: "/data/exports"}, {Name: StorageArchives, Path: "/data/archives"}},
+		StorageHealthConfig{MinFreeBytes: 100 << 20, MinFreePercent: 5, Interval: time.Minute},
+		NewMetricsService(),
+		zap.NewNop(),
+	)
+	svc.usage = func(string) (storage.DiskUsage, error) {
+		return storage.DiskUsage{Free: free, Total: 1000 << 20}, nil
+	}
+	svc.probe = func(string) error { return probeErr }
+	return svc
+}
+
+func TestStorageHealthServiceThresholds(t *testing.T) {
+	svc := newStorageHealthForTest(500<<20, nil)
+	statuses := svc.Check()
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Healthy)
+	assert.Equal(t, float64(50), statuses[0].FreePercent)
+	assert.NoError(t, svc.Ensure(StorageReports))
+
+	// 60 MB is above 5% of 1000 MB but below the 100 MB floor.
+	svc = newStorageHealthForTest(60<<20, nil)
+	assert.False(t, svc.Healthy())
+	assert.Equal(t, "free space below 100 MB", svc.Statuses()[0].Error)
+	err := svc.Ensure(StorageReports)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInsufficientStorage, appErrors.FromError(err).Status)
+	assert.Contains(t, err.Error(), "60 MB free")
+
+	svc.cfg.MinFreeBytes = 0
+	svc.cfg.MinFreePercent = 10
+	assert.Equal(t, "free space below 10%", svc.Check()[1].Error)
+	assert.NoError(t, svc.Ensure("unknown"))
+}
+
+func TestStorageHealthServiceUnwritable(t *testing.T) {
+	svc := newStorageHealthForTest(500<<20, errors.New("read-only file system"))
+	status := svc.Check()[0]
+	assert.False(t, status.Writable)
+	assert.False(t, status.Healthy)
+	assert.Zero(t, status.FreeBytes)
+	assert.EqualError(t, svc.Ensure(StorageArchives), "archives storage is not writable")
+}
+
+func TestStorageHealthServiceReusesRecentResults(t *testing.T) {
+	svc := newStorageHealthForTest(500<<20, nil)
+	now := time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	probes := 0
+	svc.probe = func(string) error {
+		probes++
+		return nil
+	}
+
+	svc.Statuses()
+	svc.Statuses()
+	assert.Equal(t, 2, probes)
+	now = now.Add(time.Minute)
+	svc.Statuses()
+	assert.Equal(t, 4, probes)
+}
+
+func TestStorageHealthServiceProbesDisk(t *testing.T) {
+	svc := NewStorageHealthService([]StorageDir{{Name: StorageReports, Path: t.TempDir()}}, StorageHealthConfig{}, nil, nil)
+	status := svc.Check()[0]
+	assert.True(t, status.Writable)
+	assert.True(t, status.Healthy, status.Error)
+
+	svc = NewStorageHealthService([]StorageDir{{Name: StorageReports, Path: t.TempDir() + "/missing"}}, StorageHealthConfig{}, nil, nil)
+	assert.False(t, svc.Check()[0].Healthy)
+}
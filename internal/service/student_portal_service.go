@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/repository"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type studentAccountStore interface {
+	FindByID(ctx context.Context, id string) (*models.StudentDetail, error)
+	FindByUserID(ctx context.Context, userID string) (*models.StudentDetail, error)
+	FindUserID(ctx context.Context, studentID string) (*string, error)
+	AttachUser(ctx context.Context, studentID, userID string) error
+}
+
+type studentAccountCreator interface {
+	Create(ctx context.Context, req CreateUserRequest, actorID string, meta models.LoginRequest) (*models.User, error)
+	Delete(ctx context.Context, id string, actorID string, meta models.LoginRequest) error
+}
+
+type classScheduleLister interface {
+	ListByClass(ctx context.Context, classID string) ([]models.Schedule, error)
+}
+
+// StudentPortalServiceParams groups constructor dependencies.
+type StudentPortalServiceParams struct {
+	Students   studentAccountStore
+	Users      studentAccountCreator
+	Schedules  classScheduleLister
+	Attendance attendanceRosterReader
+	Grades     reportCardProvider
+	Logger     *zap.Logger
+	// Rates picks the formula behind the attendance percentage; nil keeps the default.
+	Rates *AttendanceRates
+}
+
+// StudentPortalService serves the STUDENT self-service endpoints. The student is always resolved from
+// the caller's own account, so one student can never address another student's records.
+type StudentPortalService struct {
+	students   studentAccountStore
+	users      studentAccountCreator
+	schedules  classScheduleLister
+	attendance attendanceRosterReader
+	rates      *AttendanceRates
+	grades     reportCardProvider
+	logger     *zap.Logger
+}
+
+// NewStudentPortalService constructs the service.
+func NewStudentPortalService(params StudentPortalServiceParams) *StudentPortalService {
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &StudentPortalService{
+		students:   params.Students,
+		users:      params.Users,
+		schedules:  params.Schedules,
+		attendance: params.Attendance,
+		rates:      params.Rates,
+		grades:     params.Grades,
+		logger:     logger,
+	}
+}
+
+// ProvisionAccount creates an active STUDENT login named after the student and links it to the
+// student record. A student can hold at most one account.
+func (s *StudentPortalService) ProvisionAccount(ctx context.Context, studentID string, req dto.ProvisionStudentAccountRequest, actorID string, meta models.LoginRequest) (*models.User, error) {
+	student, err := s.students.FindByID(ctx, studentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "student not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+	}
+	if !student.Active {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "student is inactive")
+	}
+	existing, err := s.students.FindUserID(ctx, studentID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check student account")
+	}
+	if existing != nil {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "student already has an account")
+	}
+
+	user, err := s.users.Create(ctx, CreateUserRequest{
+		Email:    strings.TrimSpace(req.Email),
+		FullName: student.FullName,
+		Role:     models.RoleStudent,
+		Active:   true,
+		Password: req.Password,
+	}, actorID, meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.students.AttachUser(ctx, studentID, user.ID); err != nil {
+		// Lost a race with a concurrent provisioning; disable the orphaned login instead of leaving it usable.
+		if delErr := s.users.Delete(ctx, user.ID, actorID, meta); delErr != nil {
+			s.logger.Warn("failed to deactivate orphaned student account", zap.String("user_id", user.ID), zap.Error(delErr))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "student already has an account")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to link student account")
+	}
+	return user, nil
+}
+
+// Schedule returns the timetable of the class the student is actively enrolled in.
+func (s *StudentPortalService) Schedule(ctx context.Context, userID string) (*dto.StudentScheduleResponse, error) {
+	student, err := s.resolveStudent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	classID, termID, err := currentEnrollment(student)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.schedules.ListByClass(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]models.Schedule, 0, len(all))
+	for _, schedule := range all {
+		if schedule.TermID == termID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	resp := &dto.StudentScheduleResponse{StudentID: student.ID, ClassID: classID, TermID: termID, Schedules: schedules}
+	if student.CurrentClassName != nil {
+		resp.ClassName = *student.CurrentClassName
+	}
+	return resp, nil
+}
+
+// Attendance returns the student's own attendance history, defaulting to the term of the active
+// enrollment. Any StudentID on the request is ignored.
+func (s *StudentPortalService) Attendance(ctx context.Context, userID string, req dto.AttendanceStudentRequest) (*dto.AttendanceStudentResponse, error) {
+	student, err := s.resolveStudent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	termID := req.TermID
+	if termID == "" {
+		if _, termID, err = currentEnrollment(student); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := s.attendance.Roster(ctx, repository.AttendanceAliasRosterFilter{
+		TermID:    termID,
+		StudentID: student.ID,
+		DateFrom:  req.StartDate,
+		DateTo:    req.EndDate,
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student attendance")
+	}
+	if len(rows) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "student is not enrolled in term")
+	}
+	return studentHistoryFromRows(termID, rows, s.rates.Formula(ctx)), nil
+}
+
+// ReportCard returns the student's own report card, defaulting to the term of the active enrollment.
+func (s *StudentPortalService) ReportCard(ctx context.Context, userID, termID string) (*models.StudentReportCard, error) {
+	student, err := s.resolveStudent(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if termID == "" {
+		if _, termID, err = currentEnrollment(student); err != nil {
+			return nil, err
+		}
+	}
+	return s.grades.ReportCard(ctx, student.ID, termID)
+}
+
+func (s *StudentPortalService) resolveStudent(ctx context.Context, userID string) (*models.StudentDetail, error) {
+	student, err := s.students.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrForbidden, "account is not linked to a student")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load student")
+	}
+	if !student.Active {
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "student is inactive")
+	}
+	return student, nil
+}
+
+func currentEnrollment(student *models.StudentDetail) (string, string, error) {
+	if student.CurrentClassID == nil || student.CurrentTermID == nil {
+		return "", "", appErrors.Clone(appErrors.ErrNotFound, "student has no active enrollment")
+	}
+	return *student.CurrentClassID, *student.CurrentTermID, nil
+}
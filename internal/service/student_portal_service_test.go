@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type studentAccountStub struct {
+	students map[string]*models.StudentDetail
+	accounts map[string]string
+	attachFn func(studentID, userID string) error
+}
+
+func (s *studentAccountStub) FindByID(ctx context.Context, id string) (*models.StudentDetail, error) {
+	if student, ok := s.students[id]; ok {
+		return student, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *studentAccountStub) FindByUserID(ctx context.Context, userID string) (*models.StudentDetail, error) {
+	for studentID, accountID := range s.accounts {
+		if accountID == userID {
+			return s.students[studentID], nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *studentAccountStub) FindUserID(ctx context.Context, studentID string) (*string, error) {
+	if userID, ok := s.accounts[studentID]; ok {
+		return &userID, nil
+	}
+	return nil, nil
+}
+
+func (s *studentAccountStub) AttachUser(ctx context.Context, studentID, userID string) error {
+	if s.attachFn != nil {
+		return s.attachFn(studentID, userID)
+	}
+	s.accounts[studentID] = userID
+	return nil
+}
+
+type studentUserCreatorStub struct {
+	created []CreateUserRequest
+	deleted []string
+}
+
+func (s *studentUserCreatorStub) Create(ctx context.Context, req CreateUserRequest, actorID string, meta models.LoginRequest) (*models.User, error) {
+	s.created = append(s.created, req)
+	return &models.User{ID: "user-new", Email: req.Email, FullName: req.FullName, Role: req.Role, Active: req.Active}, nil
+}
+
+func (s *studentUserCreatorStub) Delete(ctx context.Context, id string, actorID string, meta models.LoginRequest) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+type classScheduleStub struct{}
+
+func (classScheduleStub) ListByClass(ctx context.Context, classID string) ([]models.Schedule, error) {
+	return []models.Schedule{
+		{ID: "sch-old", TermID: "term-0", ClassID: classID},
+		{ID: "sch-1", TermID: "term-1", ClassID: classID},
+	}, nil
+}
+
+func newStudentPortalFixture() (*StudentPortalService, *studentAccountStub, *studentUserCreatorStub, *guardianRosterStub, *guardianGradesStub) {
+	classID, className, termID := "class-a", "X IPA 1", "term-1"
+	students := &studentAccountStub{
+		students: map[string]*models.StudentDetail{
+			"student-1": {Student: models.Student{ID: "student-1", FullName: "Ani", Active: true}, CurrentClassID: &classID, CurrentClassName: &className, CurrentTermID: &termID},
+			"student-2": {Student: models.Student{ID: "student-2", FullName: "Budi", Active: true}},
+		},
+		accounts: map[string]string{"student-1": "user-1", "student-2": "user-2"},
+	}
+	users := &studentUserCreatorStub{}
+	roster := &guardianRosterStub{}
+	grades := &guardianGradesStub{}
+	svc := NewStudentPortalService(StudentPortalServiceParams{
+		Students:   students,
+		Users:      users,
+		Schedules:  classScheduleStub{},
+		Attendance: roster,
+		Grades:     grades,
+		Logger:     zap.NewNop(),
+	})
+	return svc, students, users, roster, grades
+}
+
+func TestStudentPortalResolvesOwnActiveEnrollment(t *testing.T) {
+	svc, _, _, roster, _ := newStudentPortalFixture()
+	ctx := context.Background()
+
+	schedule, err := svc.Schedule(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "X IPA 1", schedule.ClassName)
+	require.Len(t, schedule.Schedules, 1, "only the active term's timetable is returned")
+	assert.Equal(t, "sch-1", schedule.Schedules[0].ID)
+
+	_, err = svc.Attendance(ctx, "user-1", dto.AttendanceStudentRequest{StudentID: "student-2"})
+	require.NoError(t, err)
+	assert.Equal(t, "student-1", roster.filter.StudentID, "a requested student id never overrides the caller")
+	assert.Equal(t, "term-1", roster.filter.TermID)
+
+	report, err := svc.ReportCard(ctx, "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "student-1", report.StudentID)
+	assert.Equal(t, "term-1", report.TermID)
+}
+
+func TestStudentPortalRejectsUnlinkedOrUnenrolledAccounts(t *testing.T) {
+	svc, _, _, _, grades := newStudentPortalFixture()
+	ctx := context.Background()
+
+	_, err := svc.Schedule(ctx, "user-9")
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.ReportCard(ctx, "user-2", "")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+	assert.Zero(t, grades.calls)
+
+	_, err = svc.ReportCard(ctx, "user-2", "term-0")
+	require.NoError(t, err, "past terms stay readable without an active enrollment")
+}
+
+func TestStudentPortalProvisionAccount(t *testing.T) {
+	svc, students, users, _, _ := newStudentPortalFixture()
+	ctx := context.Background()
+	students.students["student-3"] = &models.StudentDetail{Student: models.Student{ID: "student-3", FullName: "Citra", Active: true}}
+
+	user, err := svc.ProvisionAccount(ctx, "student-3", dto.ProvisionStudentAccountRequest{Email: " citra@example.com ", Password: "secret123"}, "admin-1", models.LoginRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleStudent, user.Role)
+	assert.Equal(t, "Citra", users.created[0].FullName)
+	assert.Equal(t, "citra@example.com", users.created[0].Email)
+	assert.Equal(t, "user-new", students.accounts["student-3"])
+
+	_, err = svc.ProvisionAccount(ctx, "student-1", dto.ProvisionStudentAccountRequest{Email: "ani@example.com", Password: "secret123"}, "admin-1", models.LoginRequest{})
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+	assert.Len(t, users.created, 1, "no login is created for an already provisioned student")
+
+	students.students["student-4"] = &models.StudentDetail{Student: models.Student{ID: "student-4", Active: true}}
+	students.attachFn = func(studentID, userID string) error { return sql.ErrNoRows }
+	_, err = svc.ProvisionAccount(ctx, "student-4", dto.ProvisionStudentAccountRequest{Email: "d@example.com", Password: "secret123"}, "admin-1", models.LoginRequest{})
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+	assert.Equal(t, []string{"user-new"}, users.deleted, "a login that lost the link race is deactivated")
+}
@@ -24,6 +24,7 @@ type studentRepository interface {
 // CreateStudentRequest holds payload for creating students.
 type CreateStudentRequest struct {
 	NIS       string    `json:"nis" validate:"required"`
+	NISN      *string   `json:"nisn" validate:"omitempty,len=10,numeric"`
 	FullName  string    `json:"full_name" validate:"required"`
 	Gender    string    `json:"gender" validate:"required"`
 	BirthDate time.Time `json:"birth_date" validate:"required"`
@@ -34,6 +35,7 @@ type CreateStudentRequest struct {
 // UpdateStudentRequest holds payload for updating students.
 type UpdateStudentRequest struct {
 	NIS       string    `json:"nis" validate:"required"`
+	NISN      *string   `json:"nisn" validate:"omitempty,len=10,numeric"`
 	FullName  string    `json:"full_name" validate:"required"`
 	Gender    string    `json:"gender" validate:"required"`
 	BirthDate time.Time `json:"birth_date" validate:"required"`
@@ -104,6 +106,7 @@ func (s *StudentService) Create(ctx context.Context, req CreateStudentRequest) (
 	}
 	student := &models.Student{
 		NIS:       req.NIS,
+		NISN:      normalizeOptional(req.NISN),
 		FullName:  req.FullName,
 		Gender:    req.Gender,
 		BirthDate: req.BirthDate,
@@ -117,6 +120,7 @@ func (s *StudentService) Create(ctx context.Context, req CreateStudentRequest) (
 	return &models.Student{
 		ID:        student.ID,
 		NIS:       student.NIS,
+		NISN:      student.NISN,
 		FullName:  student.FullName,
 		Gender:    student.Gender,
 		BirthDate: student.BirthDate,
@@ -149,6 +153,7 @@ func (s *StudentService) Update(ctx context.Context, id string, req UpdateStuden
 	}
 	student := detail.Student
 	student.NIS = req.NIS
+	student.NISN = normalizeOptional(req.NISN)
 	student.FullName = req.FullName
 	student.Gender = req.Gender
 	student.BirthDate = req.BirthDate
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx/types"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type subjectLoadPresetStore interface {
+	List(ctx context.Context, filter models.SubjectLoadPresetFilter) ([]models.SubjectLoadPreset, error)
+	FindByID(ctx context.Context, id string) (*models.SubjectLoadPreset, error)
+	ExistsByName(ctx context.Context, name, excludeID string) (bool, error)
+	Create(ctx context.Context, preset *models.SubjectLoadPreset) error
+	Update(ctx context.Context, preset *models.SubjectLoadPreset) error
+	Delete(ctx context.Context, id string) error
+}
+
+// SubjectLoadPresetService manages the per grade/track curriculum loads admins reuse every term
+// instead of retyping subjectLoads in generation payloads.
+type SubjectLoadPresetService struct {
+	store     subjectLoadPresetStore
+	subjects  ports.SubjectReader
+	validator *validator.Validate
+}
+
+// NewSubjectLoadPresetService constructs the service.
+func NewSubjectLoadPresetService(store subjectLoadPresetStore, subjects ports.SubjectReader, validate *validator.Validate) *SubjectLoadPresetService {
+	if validate == nil {
+		validate = validator.New()
+	}
+	return &SubjectLoadPresetService{store: store, subjects: subjects, validator: validate}
+}
+
+// List returns the presets matching the filter.
+func (s *SubjectLoadPresetService) List(ctx context.Context, filter models.SubjectLoadPresetFilter) ([]models.SubjectLoadPreset, error) {
+	filter.Grade = strings.TrimSpace(filter.Grade)
+	filter.Track = strings.TrimSpace(filter.Track)
+	presets, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list subject load presets")
+	}
+	if presets == nil {
+		presets = []models.SubjectLoadPreset{}
+	}
+	return presets, nil
+}
+
+// Get returns a preset by ID.
+func (s *SubjectLoadPresetService) Get(ctx context.Context, id string) (*models.SubjectLoadPreset, error) {
+	preset, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "subject load preset not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load subject load preset")
+	}
+	return preset, nil
+}
+
+// Create stores a new preset.
+func (s *SubjectLoadPresetService) Create(ctx context.Context, req dto.SubjectLoadPresetRequest) (*models.SubjectLoadPreset, error) {
+	preset, err := s.build(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Create(ctx, preset); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create subject load preset")
+	}
+	return preset, nil
+}
+
+// Update replaces an existing preset.
+func (s *SubjectLoadPresetService) Update(ctx context.Context, id string, req dto.SubjectLoadPresetRequest) (*models.SubjectLoadPreset, error) {
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	preset, err := s.build(ctx, req, id)
+	if err != nil {
+		return nil, err
+	}
+	preset.ID = existing.ID
+	preset.CreatedAt = existing.CreatedAt
+	if err := s.store.Update(ctx, preset); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "subject load preset not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update subject load preset")
+	}
+	return preset, nil
+}
+
+// Delete removes a preset. Proposals already generated from it are unaffected.
+func (s *SubjectLoadPresetService) Delete(ctx context.Context, id string) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "subject load preset not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete subject load preset")
+	}
+	return nil
+}
+
+// build validates a request into a preset. exceptID is the preset being updated.
+func (s *SubjectLoadPresetService) build(ctx context.Context, req dto.SubjectLoadPresetRequest, exceptID string) (*models.SubjectLoadPreset, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid subject load preset payload")
+	}
+	name := strings.TrimSpace(req.Name)
+	exists, err := s.store.ExistsByName(ctx, name, exceptID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check subject load preset name")
+	}
+	if exists {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "subject load preset name already exists")
+	}
+
+	items := make([]models.SubjectLoadPresetItem, 0, len(req.Loads))
+	seen := make(map[string]bool, len(req.Loads))
+	for _, load := range req.Loads {
+		subjectID := strings.TrimSpace(load.SubjectID)
+		if seen[subjectID] {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s is listed more than once", subjectID))
+		}
+		seen[subjectID] = true
+		if s.subjects != nil {
+			if _, err := s.subjects.FindByID(ctx, subjectID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s not found", subjectID))
+				}
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load subject")
+			}
+		}
+		if load.BlockSize > 1 && load.WeeklyCount%load.BlockSize != 0 {
+			return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("subject %s weeklyCount must be a multiple of blockSize %d", subjectID, load.BlockSize))
+		}
+		items = append(items, models.SubjectLoadPresetItem{SubjectID: subjectID, WeeklyCount: load.WeeklyCount, BlockSize: load.BlockSize, Difficulty: load.Difficulty})
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid subject load preset loads")
+	}
+
+	preset := &models.SubjectLoadPreset{
+		Name:  name,
+		Grade: strings.TrimSpace(req.Grade),
+		Loads: types.JSONText(raw),
+	}
+	if req.Track != nil {
+		if track := strings.TrimSpace(*req.Track); track != "" {
+			preset.Track = &track
+		}
+	}
+	return preset, nil
+}
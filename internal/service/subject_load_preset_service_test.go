@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type subjectLoadPresetStoreStub struct {
+	items map[string]*models.SubjectLoadPreset
+}
+
+func (s *subjectLoadPresetStoreStub) List(ctx context.Context, filter models.SubjectLoadPresetFilter) ([]models.SubjectLoadPreset, error) {
+	var presets []models.SubjectLoadPreset
+	for _, preset := range s.items {
+		if filter.Grade == "" || preset.Grade == filter.Grade {
+			presets = append(presets, *preset)
+		}
+	}
+	return presets, nil
+}
+
+func (s *subjectLoadPresetStoreStub) FindByID(ctx context.Context, id string) (*models.SubjectLoadPreset, error) {
+	if preset, ok := s.items[id]; ok {
+		cp := *preset
+		return &cp, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *subjectLoadPresetStoreStub) ExistsByName(ctx context.Context, name, excludeID string) (bool, error) {
+	for id, preset := range s.items {
+		if id != excludeID && strings.EqualFold(preset.Name, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *subjectLoadPresetStoreStub) Create(ctx context.Context, preset *models.SubjectLoadPreset) error {
+	preset.ID = "preset-new"
+	cp := *preset
+	s.items[preset.ID] = &cp
+	return nil
+}
+
+func (s *subjectLoadPresetStoreStub) Update(ctx context.Context, preset *models.SubjectLoadPreset) error {
+	if _, ok := s.items[preset.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	cp := *preset
+	s.items[preset.ID] = &cp
+	return nil
+}
+
+func (s *subjectLoadPresetStoreStub) Delete(ctx context.Context, id string) error {
+	if _, ok := s.items[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(s.items, id)
+	return nil
+}
+
+func TestSubjectLoadPresetServiceCreateAndUpdate(t *testing.T) {
+	store := &subjectLoadPresetStoreStub{items: map[string]*models.SubjectLoadPreset{
+		"preset-1": {ID: "preset-1", Name: "Kelas 10", Grade: "10"},
+	}}
+	subjects := subjectLookupStub{subjects: map[string]struct{}{"math": {}, "science": {}}}
+	svc := NewSubjectLoadPresetService(store, subjects, validator.New())
+
+	track := " IPA "
+	preset, err := svc.Create(context.Background(), dto.SubjectLoadPresetRequest{
+		Name:  "Kelas 10 IPA",
+		Grade: "10",
+		Track: &track,
+		Loads: []dto.SubjectLoadPresetItemRequest{{SubjectID: "math", WeeklyCount: 4, Difficulty: 7}, {SubjectID: "science", WeeklyCount: 3}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "IPA", *preset.Track)
+	assert.JSONEq(t, `[{"subject_id":"math","weekly_count":4,"difficulty":7},{"subject_id":"science","weekly_count":3}]`, string(preset.Loads))
+
+	cases := map[string]struct {
+		req  dto.SubjectLoadPresetRequest
+		code string
+	}{
+		"duplicate name":   {dto.SubjectLoadPresetRequest{Name: "kelas 10", Grade: "10", Loads: []dto.SubjectLoadPresetItemRequest{{SubjectID: "math", WeeklyCount: 1}}}, appErrors.ErrConflict.Code},
+		"unknown subject":  {dto.SubjectLoadPresetRequest{Name: "Lain", Grade: "10", Loads: []dto.SubjectLoadPresetItemRequest{{SubjectID: "art", WeeklyCount: 1}}}, appErrors.ErrValidation.Code},
+		"repeated subject": {dto.SubjectLoadPresetRequest{Name: "Lain", Grade: "10", Loads: []dto.SubjectLoadPresetItemRequest{{SubjectID: "math", WeeklyCount: 1}, {SubjectID: "math", WeeklyCount: 2}}}, appErrors.ErrValidation.Code},
+		"no loads":         {dto.SubjectLoadPresetRequest{Name: "Lain", Grade: "10"}, appErrors.ErrValidation.Code},
+	}
+	for name, tc := range cases {
+		_, err := svc.Create(context.Background(), tc.req)
+		require.Error(t, err, name)
+		assert.Equal(t, tc.code, appErrors.FromError(err).Code, name)
+	}
+
+	// Keeping its own name is not a conflict.
+	updated, err := svc.Update(context.Background(), "preset-1", dto.SubjectLoadPresetRequest{
+		Name: "Kelas 10", Grade: "10", Loads: []dto.SubjectLoadPresetItemRequest{{SubjectID: "math", WeeklyCount: 5}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "preset-1", updated.ID)
+	assert.Nil(t, updated.Track)
+
+	err = svc.Delete(context.Background(), "missing")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
@@ -10,38 +10,25 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type teacherAssignmentRepo interface {
 	ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error)
+	ListByTeacherFiltered(ctx context.Context, filter models.TeacherAssignmentFilter) ([]models.TeacherAssignmentDetail, int, error)
 	Exists(ctx context.Context, teacherID, classID, subjectID, termID string) (bool, error)
+	FindByID(ctx context.Context, id string) (*models.TeacherAssignment, error)
 	Create(ctx context.Context, assignment *models.TeacherAssignment) error
 	Delete(ctx context.Context, teacherID, assignmentID string) error
 	CountByTeacherAndTerm(ctx context.Context, teacherID, termID string) (int, error)
 }
 
-type classReader interface {
-	FindByID(ctx context.Context, id string) (*models.Class, error)
-}
-
-type subjectReader interface {
-	FindByID(ctx context.Context, id string) (*models.Subject, error)
-}
-
-type termReader interface {
-	FindByID(ctx context.Context, id string) (*models.Term, error)
-}
-
 type scheduleReader interface {
 	ListByClass(ctx context.Context, classID string) ([]models.Schedule, error)
 	ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error)
 }
 
-type teacherPreferenceReader interface {
-	GetByTeacher(ctx context.Context, teacherID string) (*models.TeacherPreference, error)
-}
-
 // CreateTeacherAssignmentRequest describes assignment payload.
 type CreateTeacherAssignmentRequest struct {
 	ClassID   string `json:"class_id" validate:"required"`
@@ -49,30 +36,42 @@ type CreateTeacherAssignmentRequest struct {
 	TermID    string `json:"term_id" validate:"required"`
 }
 
+// TeacherAssignmentServiceOption customises TeacherAssignmentService.
+type TeacherAssignmentServiceOption func(*TeacherAssignmentService)
+
+// WithAssignmentRevalidation re-checks the teacher's published schedules after assignments change.
+func WithAssignmentRevalidation(revalidator scheduleRevalidator) TeacherAssignmentServiceOption {
+	return func(s *TeacherAssignmentService) {
+		s.revalidator = revalidator
+	}
+}
+
 // TeacherAssignmentService handles roster assignments.
 type TeacherAssignmentService struct {
 	teachers    teacherRepository
-	classes     classReader
-	subjects    subjectReader
-	terms       termReader
+	classes     ports.ClassReader
+	subjects    ports.SubjectReader
+	terms       ports.TermReader
 	assignments teacherAssignmentRepo
 	schedules   scheduleReader
-	prefs       teacherPreferenceReader
+	prefs       ports.TeacherPreferenceReader
 	validator   *validator.Validate
 	logger      *zap.Logger
+	revalidator scheduleRevalidator
 }
 
 // NewTeacherAssignmentService creates a service instance.
 func NewTeacherAssignmentService(
 	teachers teacherRepository,
-	classes classReader,
-	subjects subjectReader,
-	terms termReader,
+	classes ports.ClassReader,
+	subjects ports.SubjectReader,
+	terms ports.TermReader,
 	assignments teacherAssignmentRepo,
 	schedules scheduleReader,
-	prefs teacherPreferenceReader,
+	prefs ports.TeacherPreferenceReader,
 	validate *validator.Validate,
 	logger *zap.Logger,
+	opts ...TeacherAssignmentServiceOption,
 ) *TeacherAssignmentService {
 	if validate == nil {
 		validate = validator.New()
@@ -80,7 +79,7 @@ func NewTeacherAssignmentService(
 	if logger == nil {
 		logger = zap.NewNop()
 	}
-	return &TeacherAssignmentService{
+	svc := &TeacherAssignmentService{
 		teachers:    teachers,
 		classes:     classes,
 		subjects:    subjects,
@@ -91,6 +90,10 @@ func NewTeacherAssignmentService(
 		validator:   validate,
 		logger:      logger,
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
 }
 
 // ListByTeacher returns assignments for the teacher.
@@ -108,6 +111,39 @@ func (s *TeacherAssignmentService) ListByTeacher(ctx context.Context, teacherID
 	return assignments, nil
 }
 
+// ListAssignments returns a filtered, paginated page of the teacher's assignments.
+func (s *TeacherAssignmentService) ListAssignments(ctx context.Context, filter models.TeacherAssignmentFilter) ([]models.TeacherAssignmentDetail, *models.Pagination, error) {
+	for _, relation := range filter.Expand {
+		switch relation {
+		case models.TeacherAssignmentExpandClass, models.TeacherAssignmentExpandSubject, models.TeacherAssignmentExpandTerm, models.TeacherAssignmentExpandTeacher:
+		default:
+			return nil, nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("unknown expand %q; use class, subject, term or teacher", relation))
+		}
+	}
+	if _, err := s.teachers.FindByID(ctx, filter.TeacherID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, appErrors.Clone(appErrors.ErrNotFound, "teacher not found")
+		}
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+	assignments, total, err := s.assignments.ListByTeacherFiltered(ctx, filter)
+	if err != nil {
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list assignments")
+	}
+	if assignments == nil {
+		assignments = []models.TeacherAssignmentDetail{}
+	}
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	size := filter.PageSize
+	if size <= 0 || size > 100 {
+		size = 20
+	}
+	return assignments, &models.Pagination{Page: page, PageSize: size, TotalCount: total}, nil
+}
+
 // Assign creates a new mapping between teacher-class-subject-term.
 func (s *TeacherAssignmentService) Assign(ctx context.Context, teacherID string, req CreateTeacherAssignmentRequest) (*models.TeacherAssignment, error) {
 	if err := s.validator.Struct(req); err != nil {
@@ -154,6 +190,22 @@ func (s *TeacherAssignmentService) Assign(ctx context.Context, teacherID string,
 	if err := s.assignments.Create(ctx, assignment); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create assignment")
 	}
+	s.revalidateSchedules(ctx, teacherID)
+	return assignment, nil
+}
+
+// Get returns one of the teacher's assignments.
+func (s *TeacherAssignmentService) Get(ctx context.Context, teacherID, assignmentID string) (*models.TeacherAssignment, error) {
+	assignment, err := s.assignments.FindByID(ctx, assignmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "assignment not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load assignment")
+	}
+	if assignment.TeacherID != teacherID {
+		return nil, appErrors.Clone(appErrors.ErrNotFound, "assignment not found")
+	}
 	return assignment, nil
 }
 
@@ -171,9 +223,21 @@ func (s *TeacherAssignmentService) Remove(ctx context.Context, teacherID, assign
 		}
 		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to delete assignment")
 	}
+	s.revalidateSchedules(ctx, teacherID)
 	return nil
 }
 
+// revalidateSchedules records conflicts the change introduced in published schedules. The
+// assignment is already stored, so a failure is logged rather than returned.
+func (s *TeacherAssignmentService) revalidateSchedules(ctx context.Context, teacherID string) {
+	if s.revalidator == nil {
+		return
+	}
+	if err := s.revalidator.RevalidateTeacher(ctx, teacherID); err != nil {
+		s.logger.Warn("failed to revalidate published schedules", zap.String("teacher_id", teacherID), zap.Error(err))
+	}
+}
+
 func (s *TeacherAssignmentService) ensureClassSubjectTerm(ctx context.Context, classID, subjectID, termID string) error {
 	if _, err := s.classes.FindByID(ctx, classID); err != nil {
 		if err == sql.ErrNoRows {
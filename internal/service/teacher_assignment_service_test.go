@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type teacherRepoStub struct {
@@ -21,6 +22,10 @@ func (s *teacherRepoStub) List(ctx context.Context, filter models.TeacherFilter)
 	return nil, 0, nil
 }
 
+func (s *teacherRepoStub) ListWithTermLoad(ctx context.Context, filter models.TeacherFilter) ([]models.TeacherWithLoad, int, error) {
+	return nil, 0, nil
+}
+
 func (s *teacherRepoStub) FindByID(ctx context.Context, id string) (*models.Teacher, error) {
 	if teacher, ok := s.items[id]; ok {
 		cp := *teacher
@@ -39,7 +44,10 @@ func (s *teacherRepoStub) ExistsByNIP(ctx context.Context, nip, excludeID string
 
 func (s *teacherRepoStub) Create(ctx context.Context, teacher *models.Teacher) error { return nil }
 func (s *teacherRepoStub) Update(ctx context.Context, teacher *models.Teacher) error { return nil }
-func (s *teacherRepoStub) Deactivate(ctx context.Context, id string) error           { return nil }
+func (s *teacherRepoStub) UpdateFields(ctx context.Context, teacher *models.Teacher, columns []string) error {
+	return nil
+}
+func (s *teacherRepoStub) Deactivate(ctx context.Context, id string) error { return nil }
 
 type stubClassRepo struct{}
 
@@ -65,16 +73,30 @@ type assignmentRepoStub struct {
 	deleteErr  error
 	count      int
 	deleteArgs []string
+	filters    []models.TeacherAssignmentFilter
+	stored     map[string]*models.TeacherAssignment
 }
 
 func (s *assignmentRepoStub) ListByTeacher(ctx context.Context, teacherID string) ([]models.TeacherAssignmentDetail, error) {
 	return nil, nil
 }
 
+func (s *assignmentRepoStub) ListByTeacherFiltered(ctx context.Context, filter models.TeacherAssignmentFilter) ([]models.TeacherAssignmentDetail, int, error) {
+	s.filters = append(s.filters, filter)
+	return []models.TeacherAssignmentDetail{{TeacherAssignment: models.TeacherAssignment{ID: "assign-1", TeacherID: filter.TeacherID}}}, 21, nil
+}
+
 func (s *assignmentRepoStub) Exists(ctx context.Context, teacherID, classID, subjectID, termID string) (bool, error) {
 	return s.exists, nil
 }
 
+func (s *assignmentRepoStub) FindByID(ctx context.Context, id string) (*models.TeacherAssignment, error) {
+	if assignment, ok := s.stored[id]; ok {
+		return assignment, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
 func (s *assignmentRepoStub) Create(ctx context.Context, assignment *models.TeacherAssignment) error {
 	s.created = append(s.created, assignment)
 	return nil
@@ -185,9 +207,66 @@ func TestTeacherAssignmentServiceRemove(t *testing.T) {
 		items: map[string]*models.Teacher{"teacher-1": {ID: "teacher-1", Active: true}},
 	}
 	assignRepo := &assignmentRepoStub{}
-	service := NewTeacherAssignmentService(teacherRepo, stubClassRepo{}, stubSubjectRepo{}, stubTermRepo{}, assignRepo, &scheduleReaderStub{}, &preferenceRepoStub{}, validator.New(), zap.NewNop())
+	revalidator := &scheduleRevalidatorStub{}
+	service := NewTeacherAssignmentService(teacherRepo, stubClassRepo{}, stubSubjectRepo{}, stubTermRepo{}, assignRepo, &scheduleReaderStub{}, &preferenceRepoStub{}, validator.New(), zap.NewNop(), WithAssignmentRevalidation(revalidator))
 
 	err := service.Remove(context.Background(), "teacher-1", "assignment-1")
 	require.NoError(t, err)
 	assert.Equal(t, []string{"teacher-1:assignment-1"}, assignRepo.deleteArgs)
+	assert.Equal(t, []string{"teacher-1"}, revalidator.teachers)
+}
+
+func TestTeacherAssignmentServiceGet(t *testing.T) {
+	assignRepo := &assignmentRepoStub{stored: map[string]*models.TeacherAssignment{
+		"assignment-1": {ID: "assignment-1", TeacherID: "teacher-1"},
+	}}
+	service := NewTeacherAssignmentService(&teacherRepoStub{}, stubClassRepo{}, stubSubjectRepo{}, stubTermRepo{}, assignRepo, &scheduleReaderStub{}, &preferenceRepoStub{}, validator.New(), zap.NewNop())
+
+	assignment, err := service.Get(context.Background(), "teacher-1", "assignment-1")
+	require.NoError(t, err)
+	assert.Equal(t, "assignment-1", assignment.ID)
+
+	// Another teacher's assignment is reported as missing rather than leaked.
+	for _, teacherID := range []string{"teacher-2", ""} {
+		_, err = service.Get(context.Background(), teacherID, "assignment-1")
+		assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+	}
+	_, err = service.Get(context.Background(), "teacher-1", "missing")
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
+}
+
+type scheduleRevalidatorStub struct {
+	teachers []string
+}
+
+func (s *scheduleRevalidatorStub) RevalidateTeacher(ctx context.Context, teacherID string) error {
+	s.teachers = append(s.teachers, teacherID)
+	return nil
+}
+
+func TestTeacherAssignmentServiceListAssignments(t *testing.T) {
+	teacherRepo := &teacherRepoStub{
+		items: map[string]*models.Teacher{"teacher-1": {ID: "teacher-1", Active: true}},
+	}
+	assignRepo := &assignmentRepoStub{}
+	service := NewTeacherAssignmentService(teacherRepo, stubClassRepo{}, stubSubjectRepo{}, stubTermRepo{}, assignRepo, &scheduleReaderStub{}, &preferenceRepoStub{}, validator.New(), zap.NewNop())
+
+	items, pagination, err := service.ListAssignments(context.Background(), models.TeacherAssignmentFilter{
+		TeacherID: "teacher-1",
+		ClassID:   "class-1",
+		Expand:    []string{models.TeacherAssignmentExpandClass},
+		Page:      2,
+		PageSize:  10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, &models.Pagination{Page: 2, PageSize: 10, TotalCount: 21}, pagination)
+	require.Len(t, assignRepo.filters, 1)
+	assert.Equal(t, "class-1", assignRepo.filters[0].ClassID)
+
+	_, _, err = service.ListAssignments(context.Background(), models.TeacherAssignmentFilter{TeacherID: "teacher-1", Expand: []string{"grades"}})
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+
+	_, _, err = service.ListAssignments(context.Background(), models.TeacherAssignmentFilter{TeacherID: "missing"})
+	assert.Equal(t, appErrors.ErrNotFound.Code, appErrors.FromError(err).Code)
 }
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// earthRadiusMeters is the mean Earth radius used for geofence distances.
+const earthRadiusMeters = 6371000.0
+
+type teacherAttendanceStore interface {
+	InsertCheckIn(ctx context.Context, record *models.TeacherAttendance) (*models.TeacherAttendance, bool, error)
+	FindByTeacherAndDate(ctx context.Context, teacherID string, date time.Time) (*models.TeacherAttendance, error)
+	RecordCheckOut(ctx context.Context, id string, checkOut models.TeacherCheckOut) (*models.TeacherAttendance, error)
+	ListByTeacher(ctx context.Context, teacherID string, from, to time.Time) ([]models.TeacherAttendance, error)
+	Recap(ctx context.Context, from, to time.Time) ([]models.TeacherAttendanceRecap, error)
+	CountsForDate(ctx context.Context, date time.Time) (*models.TeacherPresenceCounts, error)
+}
+
+// TeacherAttendanceConfig configures teacher clock-in validation.
+type TeacherAttendanceConfig struct {
+	// LateAfter is the "HH:MM" clock-in cut-off in Location.
+	LateAfter string
+	// Location is the school's time zone used to derive the attendance date and lateness.
+	Location *time.Location
+	// AllowedNetworks restricts clock-ins to these addresses or CIDR ranges; empty allows any.
+	AllowedNetworks []string
+	// GeofenceLatitude and GeofenceLongitude centre the geofence; a zero radius disables it.
+	GeofenceLatitude     float64
+	GeofenceLongitude    float64
+	GeofenceRadiusMeters float64
+}
+
+// TeacherAttendanceService records teacher clock-ins/outs and summarises them.
+type TeacherAttendanceService struct {
+	store    teacherAttendanceStore
+	teachers ports.TeacherReader
+	cfg      TeacherAttendanceConfig
+	cutoff   int
+	networks []*net.IPNet
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewTeacherAttendanceService constructs the service, rejecting malformed cut-offs or network entries.
+func NewTeacherAttendanceService(store teacherAttendanceStore, teachers ports.TeacherReader, cfg TeacherAttendanceConfig, logger *zap.Logger) (*TeacherAttendanceService, error) {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	cutoff, err := time.Parse("15:04", cfg.LateAfter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid teacher late threshold %q: %w", cfg.LateAfter, err)
+	}
+	networks := make([]*net.IPNet, 0, len(cfg.AllowedNetworks))
+	for _, raw := range cfg.AllowedNetworks {
+		entry := strings.TrimSpace(raw)
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid teacher attendance network %q: %w", raw, err)
+		}
+		networks = append(networks, network)
+	}
+	return &TeacherAttendanceService{
+		store:    store,
+		teachers: teachers,
+		cfg:      cfg,
+		cutoff:   cutoff.Hour()*60 + cutoff.Minute(),
+		networks: networks,
+		logger:   logger,
+		now:      time.Now,
+	}, nil
+}
+
+// CheckIn clocks the teacher in for today. A second clock-in returns the stored row flagged as a
+// duplicate instead of moving the original time.
+func (s *TeacherAttendanceService) CheckIn(ctx context.Context, teacherID string, req dto.TeacherPresenceRequest, clientIP string) (*dto.TeacherAttendanceResponse, error) {
+	if err := s.authorize(ctx, teacherID, req, clientIP); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	local := now.In(s.cfg.Location)
+	record := &models.TeacherAttendance{
+		TeacherID:        teacherID,
+		Date:             s.localDate(now),
+		CheckInAt:        now.UTC(),
+		LateMinutes:      minutesLate(local, s.cutoff),
+		CheckInIP:        optionalString(clientIP),
+		CheckInLatitude:  req.Latitude,
+		CheckInLongitude: req.Longitude,
+	}
+	stored, created, err := s.store.InsertCheckIn(ctx, record)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record check-in")
+	}
+	resp := toTeacherAttendanceResponse(*stored)
+	resp.Duplicate = !created
+	return &resp, nil
+}
+
+// CheckOut closes today's row. Teachers must clock in first and can only clock out once.
+func (s *TeacherAttendanceService) CheckOut(ctx context.Context, teacherID string, req dto.TeacherPresenceRequest, clientIP string) (*dto.TeacherAttendanceResponse, error) {
+	if err := s.authorize(ctx, teacherID, req, clientIP); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	record, err := s.store.FindByTeacherAndDate(ctx, teacherID, s.localDate(now))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "check in before checking out")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load check-in")
+	}
+	if record.CheckOutAt != nil {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "already checked out today")
+	}
+	stored, err := s.store.RecordCheckOut(ctx, record.ID, models.TeacherCheckOut{
+		At:        now.UTC(),
+		IP:        optionalString(clientIP),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrConflict, "already checked out today")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to record check-out")
+	}
+	resp := toTeacherAttendanceResponse(*stored)
+	return &resp, nil
+}
+
+// Recap summarises every active teacher for a "YYYY-MM" month, defaulting to the current one.
+func (s *TeacherAttendanceService) Recap(ctx context.Context, month string) (*dto.TeacherAttendanceRecapResponse, error) {
+	from, to, label, err := s.monthRange(month)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.store.Recap(ctx, from, to)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher attendance recap")
+	}
+	resp := &dto.TeacherAttendanceRecapResponse{Month: label, Teachers: make([]dto.TeacherAttendanceSummary, 0, len(rows))}
+	for _, row := range rows {
+		resp.Teachers = append(resp.Teachers, dto.TeacherAttendanceSummary{
+			TeacherID:        row.TeacherID,
+			FullName:         row.FullName,
+			DaysPresent:      row.DaysPresent,
+			LateDays:         row.LateDays,
+			LateMinutes:      row.LateMinutes,
+			MissingCheckouts: row.MissingCheckouts,
+			WorkedMinutes:    row.WorkedMinutes,
+		})
+	}
+	return resp, nil
+}
+
+// TeacherMonth returns one teacher's daily log and summary for a "YYYY-MM" month.
+func (s *TeacherAttendanceService) TeacherMonth(ctx context.Context, teacherID, month string) (*dto.TeacherAttendanceMonthResponse, error) {
+	from, to, label, err := s.monthRange(month)
+	if err != nil {
+		return nil, err
+	}
+	teacher, err := s.teachers.FindByID(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "teacher not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+	records, err := s.store.ListByTeacher(ctx, teacherID, from, to)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher attendance")
+	}
+	resp := &dto.TeacherAttendanceMonthResponse{
+		Month:   label,
+		Summary: dto.TeacherAttendanceSummary{TeacherID: teacher.ID, FullName: teacher.FullName},
+		Days:    make([]dto.TeacherAttendanceResponse, 0, len(records)),
+	}
+	for _, record := range records {
+		day := toTeacherAttendanceResponse(record)
+		resp.Days = append(resp.Days, day)
+		resp.Summary.DaysPresent++
+		resp.Summary.LateMinutes += record.LateMinutes
+		resp.Summary.WorkedMinutes += day.WorkedMinutes
+		if record.LateMinutes > 0 {
+			resp.Summary.LateDays++
+		}
+		if record.CheckOutAt == nil {
+			resp.Summary.MissingCheckouts++
+		}
+	}
+	return resp, nil
+}
+
+// TodayStats counts today's clock-ins for the admin dashboard.
+func (s *TeacherAttendanceService) TodayStats(ctx context.Context) (*dto.TeacherPresenceStats, error) {
+	date := s.localDate(s.now())
+	counts, err := s.store.CountsForDate(ctx, date)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to count teacher presence")
+	}
+	notCheckedIn := counts.ActiveTeachers - counts.CheckedIn
+	if notCheckedIn < 0 {
+		notCheckedIn = 0
+	}
+	return &dto.TeacherPresenceStats{
+		Date:           date.Format("2006-01-02"),
+		ActiveTeachers: counts.ActiveTeachers,
+		CheckedIn:      counts.CheckedIn,
+		NotCheckedIn:   notCheckedIn,
+		Late:           counts.Late,
+		CheckedOut:     counts.CheckedOut,
+	}, nil
+}
+
+// authorize confirms the caller has an active teacher profile and is on an allowed network and
+// inside the geofence when those checks are configured.
+func (s *TeacherAttendanceService) authorize(ctx context.Context, teacherID string, req dto.TeacherPresenceRequest, clientIP string) error {
+	teacher, err := s.teachers.FindByID(ctx, teacherID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrNotFound, "teacher profile not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+	if !teacher.Active {
+		return appErrors.Clone(appErrors.ErrForbidden, "teacher is inactive")
+	}
+	if len(s.networks) > 0 && !ipAllowed(s.networks, clientIP) {
+		return appErrors.Clone(appErrors.ErrForbidden, "check-in is only allowed from the school network")
+	}
+	if s.cfg.GeofenceRadiusMeters <= 0 {
+		return nil
+	}
+	if req.Latitude == nil || req.Longitude == nil {
+		return appErrors.Clone(appErrors.ErrValidation, "latitude and longitude are required")
+	}
+	if math.Abs(*req.Latitude) > 90 || math.Abs(*req.Longitude) > 180 {
+		return appErrors.Clone(appErrors.ErrValidation, "latitude or longitude is out of range")
+	}
+	distance := haversineMeters(s.cfg.GeofenceLatitude, s.cfg.GeofenceLongitude, *req.Latitude, *req.Longitude)
+	if distance > s.cfg.GeofenceRadiusMeters {
+		return appErrors.Clone(appErrors.ErrForbidden, fmt.Sprintf("outside the school area (%.0f m away)", distance))
+	}
+	return nil
+}
+
+func (s *TeacherAttendanceService) localDate(at time.Time) time.Time {
+	local := at.In(s.cfg.Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// monthRange resolves "YYYY-MM" into its first and last dates.
+func (s *TeacherAttendanceService) monthRange(month string) (time.Time, time.Time, string, error) {
+	month = strings.TrimSpace(month)
+	var start time.Time
+	if month == "" {
+		local := s.now().In(s.cfg.Location)
+		start = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		parsed, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", appErrors.Clone(appErrors.ErrValidation, "month must use YYYY-MM format")
+		}
+		start = parsed
+	}
+	return start, start.AddDate(0, 1, -1), start.Format("2006-01"), nil
+}
+
+func toTeacherAttendanceResponse(record models.TeacherAttendance) dto.TeacherAttendanceResponse {
+	resp := dto.TeacherAttendanceResponse{
+		ID:          record.ID,
+		TeacherID:   record.TeacherID,
+		Date:        record.Date.Format("2006-01-02"),
+		CheckInAt:   record.CheckInAt,
+		CheckOutAt:  record.CheckOutAt,
+		Late:        record.LateMinutes > 0,
+		LateMinutes: record.LateMinutes,
+	}
+	if record.CheckOutAt != nil {
+		resp.WorkedMinutes = int(record.CheckOutAt.Sub(record.CheckInAt).Minutes())
+	}
+	return resp
+}
+
+func ipAllowed(networks []*net.IPNet, raw string) bool {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
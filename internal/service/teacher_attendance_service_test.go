@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type teacherAttendanceStoreStub struct {
+	rows map[string]*models.TeacherAttendance
+}
+
+func newTeacherAttendanceStoreStub() *teacherAttendanceStoreStub {
+	return &teacherAttendanceStoreStub{rows: map[string]*models.TeacherAttendance{}}
+}
+
+func (s *teacherAttendanceStoreStub) key(teacherID string, date time.Time) string {
+	return teacherID + "|" + date.Format("2006-01-02")
+}
+
+func (s *teacherAttendanceStoreStub) InsertCheckIn(ctx context.Context, record *models.TeacherAttendance) (*models.TeacherAttendance, bool, error) {
+	key := s.key(record.TeacherID, record.Date)
+	if existing, ok := s.rows[key]; ok {
+		stored := *existing
+		return &stored, false, nil
+	}
+	stored := *record
+	stored.ID = "ta-" + key
+	s.rows[key] = &stored
+	result := stored
+	return &result, true, nil
+}
+
+func (s *teacherAttendanceStoreStub) FindByTeacherAndDate(ctx context.Context, teacherID string, date time.Time) (*models.TeacherAttendance, error) {
+	row, ok := s.rows[s.key(teacherID, date)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	stored := *row
+	return &stored, nil
+}
+
+func (s *teacherAttendanceStoreStub) RecordCheckOut(ctx context.Context, id string, checkOut models.TeacherCheckOut) (*models.TeacherAttendance, error) {
+	for _, row := range s.rows {
+		if row.ID == id && row.CheckOutAt == nil {
+			at := checkOut.At
+			row.CheckOutAt = &at
+			row.CheckOutIP = checkOut.IP
+			stored := *row
+			return &stored, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *teacherAttendanceStoreStub) ListByTeacher(ctx context.Context, teacherID string, from, to time.Time) ([]models.TeacherAttendance, error) {
+	var rows []models.TeacherAttendance
+	for _, row := range s.rows {
+		if row.TeacherID == teacherID && !row.Date.Before(from) && !row.Date.After(to) {
+			rows = append(rows, *row)
+		}
+	}
+	return rows, nil
+}
+
+func (s *teacherAttendanceStoreStub) Recap(ctx context.Context, from, to time.Time) ([]models.TeacherAttendanceRecap, error) {
+	return []models.TeacherAttendanceRecap{{TeacherID: "teacher-1", FullName: "Bu Sari", DaysPresent: 2}}, nil
+}
+
+func (s *teacherAttendanceStoreStub) CountsForDate(ctx context.Context, date time.Time) (*models.TeacherPresenceCounts, error) {
+	counts := &models.TeacherPresenceCounts{ActiveTeachers: 3}
+	for _, row := range s.rows {
+		if !row.Date.Equal(date) {
+			continue
+		}
+		counts.CheckedIn++
+		if row.LateMinutes > 0 {
+			counts.Late++
+		}
+		if row.CheckOutAt != nil {
+			counts.CheckedOut++
+		}
+	}
+	return counts, nil
+}
+
+type presenceTeacherStub struct{}
+
+func (presenceTeacherStub) FindByID(ctx context.Context, id string) (*models.Teacher, error) {
+	switch id {
+	case "teacher-1":
+		return &models.Teacher{ID: id, FullName: "Bu Sari", Active: true}, nil
+	case "teacher-2":
+		return &models.Teacher{ID: id, FullName: "Pak Budi"}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func newTeacherAttendanceTestService(t *testing.T, store *teacherAttendanceStoreStub, cfg TeacherAttendanceConfig) *TeacherAttendanceService {
+	t.Helper()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	cfg.Location = jakarta
+	if cfg.LateAfter == "" {
+		cfg.LateAfter = "07:00"
+	}
+	svc, err := NewTeacherAttendanceService(store, presenceTeacherStub{}, cfg, zap.NewNop())
+	require.NoError(t, err)
+	return svc
+}
+
+func TestTeacherAttendanceCheckInAndOut(t *testing.T) {
+	store := newTeacherAttendanceStoreStub()
+	svc := newTeacherAttendanceTestService(t, store, TeacherAttendanceConfig{})
+	ctx := context.Background()
+
+	svc.now = func() time.Time { return time.Date(2024, 8, 5, 0, 12, 30, 0, time.UTC) } // 07:12:30 WIB
+	_, err := svc.CheckOut(ctx, "teacher-1", dto.TeacherPresenceRequest{}, "10.0.0.5")
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code, "clock-out needs a clock-in first")
+
+	resp, err := svc.CheckIn(ctx, "teacher-1", dto.TeacherPresenceRequest{}, "10.0.0.5")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-08-05", resp.Date)
+	assert.True(t, resp.Late)
+	assert.Equal(t, 13, resp.LateMinutes)
+	assert.False(t, resp.Duplicate)
+
+	svc.now = func() time.Time { return time.Date(2024, 8, 5, 0, 30, 0, 0, time.UTC) }
+	again, err := svc.CheckIn(ctx, "teacher-1", dto.TeacherPresenceRequest{}, "10.0.0.5")
+	require.NoError(t, err)
+	assert.True(t, again.Duplicate)
+	assert.Equal(t, resp.CheckInAt, again.CheckInAt, "a second clock-in keeps the first time")
+
+	svc.now = func() time.Time { return time.Date(2024, 8, 5, 8, 12, 30, 0, time.UTC) }
+	out, err := svc.CheckOut(ctx, "teacher-1", dto.TeacherPresenceRequest{}, "10.0.0.5")
+	require.NoError(t, err)
+	require.NotNil(t, out.CheckOutAt)
+	assert.Equal(t, 480, out.WorkedMinutes)
+
+	_, err = svc.CheckOut(ctx, "teacher-1", dto.TeacherPresenceRequest{}, "10.0.0.5")
+	assert.Equal(t, appErrors.ErrConflict.Code, appErrors.FromError(err).Code)
+
+	stats, err := svc.TodayStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &dto.TeacherPresenceStats{Date: "2024-08-05", ActiveTeachers: 3, CheckedIn: 1, NotCheckedIn: 2, Late: 1, CheckedOut: 1}, stats)
+
+	month, err := svc.TeacherMonth(ctx, "teacher-1", "2024-08")
+	require.NoError(t, err)
+	assert.Equal(t, 1, month.Summary.DaysPresent)
+	assert.Equal(t, 1, month.Summary.LateDays)
+	assert.Equal(t, 13, month.Summary.LateMinutes)
+	assert.Equal(t, 480, month.Summary.WorkedMinutes)
+	assert.Zero(t, month.Summary.MissingCheckouts)
+}
+
+func TestTeacherAttendanceEnforcesNetworkAndGeofence(t *testing.T) {
+	svc := newTeacherAttendanceTestService(t, newTeacherAttendanceStoreStub(), TeacherAttendanceConfig{
+		AllowedNetworks:      []string{"10.10.0.0/16", "203.0.113.7"},
+		GeofenceLatitude:     -6.2000,
+		GeofenceLongitude:    106.8166,
+		GeofenceRadiusMeters: 200,
+	})
+	svc.now = func() time.Time { return time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+	lat, lng := -6.2005, 106.8170
+	farLat := -6.2100
+
+	cases := map[string]struct {
+		teacherID string
+		req       dto.TeacherPresenceRequest
+		ip        string
+		code      string
+	}{
+		"unknown teacher":    {teacherID: "nobody", req: dto.TeacherPresenceRequest{Latitude: &lat, Longitude: &lng}, ip: "10.10.1.1", code: appErrors.ErrNotFound.Code},
+		"inactive teacher":   {teacherID: "teacher-2", req: dto.TeacherPresenceRequest{Latitude: &lat, Longitude: &lng}, ip: "10.10.1.1", code: appErrors.ErrForbidden.Code},
+		"outside network":    {teacherID: "teacher-1", req: dto.TeacherPresenceRequest{Latitude: &lat, Longitude: &lng}, ip: "192.168.1.1", code: appErrors.ErrForbidden.Code},
+		"missing location":   {teacherID: "teacher-1", ip: "203.0.113.7", code: appErrors.ErrValidation.Code},
+		"outside geofence":   {teacherID: "teacher-1", req: dto.TeacherPresenceRequest{Latitude: &farLat, Longitude: &lng}, ip: "10.10.1.1", code: appErrors.ErrForbidden.Code},
+		"invalid coordinate": {teacherID: "teacher-1", req: dto.TeacherPresenceRequest{Latitude: &lng, Longitude: &lng}, ip: "10.10.1.1", code: appErrors.ErrValidation.Code},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.CheckIn(ctx, tc.teacherID, tc.req, tc.ip)
+			require.Error(t, err)
+			assert.Equal(t, tc.code, appErrors.FromError(err).Code)
+		})
+	}
+
+	resp, err := svc.CheckIn(ctx, "teacher-1", dto.TeacherPresenceRequest{Latitude: &lat, Longitude: &lng}, "203.0.113.7")
+	require.NoError(t, err)
+	assert.False(t, resp.Late)
+
+	_, err = NewTeacherAttendanceService(newTeacherAttendanceStoreStub(), presenceTeacherStub{}, TeacherAttendanceConfig{LateAfter: "07:00", AllowedNetworks: []string{"school"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestTeacherAttendanceRecapValidatesMonth(t *testing.T) {
+	svc := newTeacherAttendanceTestService(t, newTeacherAttendanceStoreStub(), TeacherAttendanceConfig{})
+	svc.now = func() time.Time { return time.Date(2024, 8, 31, 18, 0, 0, 0, time.UTC) } // 1 Sep WIB
+
+	recap, err := svc.Recap(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-09", recap.Month, "the default month follows the school time zone")
+	require.Len(t, recap.Teachers, 1)
+	assert.Equal(t, 2, recap.Teachers[0].DaysPresent)
+
+	_, err = svc.Recap(context.Background(), "08-2024")
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
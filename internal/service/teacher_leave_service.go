@@ -0,0 +1,668 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+const (
+	// maxTeacherLeaveDays caps the calendar days one leave request may span.
+	maxTeacherLeaveDays = 366
+	// maxSubstitutionDays caps the school days substitution suggestions are computed for.
+	maxSubstitutionDays = 31
+	// substituteCandidateLimit caps the candidates suggested per lesson.
+	substituteCandidateLimit = 5
+)
+
+type teacherLeaveStore interface {
+	Create(ctx context.Context, leave *models.TeacherLeave) error
+	FindByID(ctx context.Context, id string) (*models.TeacherLeave, error)
+	List(ctx context.Context, filter models.TeacherLeaveFilter) ([]models.TeacherLeave, error)
+	HasOverlap(ctx context.Context, teacherID string, start, end time.Time) (bool, error)
+	SetStatus(ctx context.Context, id string, from []models.TeacherLeaveStatus, status models.TeacherLeaveStatus, actorID string, note *string, at time.Time) error
+	TeachersOnLeave(ctx context.Context, date time.Time) ([]string, error)
+	Usage(ctx context.Context, year int, teacherID string) ([]models.TeacherLeaveUsage, error)
+}
+
+type leaveScheduleReader interface {
+	ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error)
+	ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error)
+}
+
+type leaveAssignmentReader interface {
+	ListByTerm(ctx context.Context, termID string) ([]models.TeacherAssignment, error)
+}
+
+// TeacherLeaveConfig configures teacher leave.
+type TeacherLeaveConfig struct {
+	// Location is the school's time zone; it decides today's date and the default report year.
+	Location *time.Location
+	// Allowances are the annual school days per leave type; types without one are unlimited.
+	Allowances map[models.TeacherLeaveType]int
+	// NonSchoolWeekdays are not counted as leave days; defaults to Sunday.
+	NonSchoolWeekdays []time.Weekday
+}
+
+// TeacherLeaveServiceParams groups constructor dependencies.
+type TeacherLeaveServiceParams struct {
+	Store       teacherLeaveStore
+	Teachers    ports.TeacherReader
+	Terms       ports.TermReader
+	Schedules   leaveScheduleReader
+	Assignments leaveAssignmentReader
+	// Calendar is optional; school-wide holidays are not counted as leave days when set.
+	Calendar  attendanceCalendar
+	Validator *validator.Validate
+	Logger    *zap.Logger
+	Config    TeacherLeaveConfig
+}
+
+// TeacherLeaveService manages teacher leave requests. Administrators approve or reject them; approved
+// leave blocks the teacher as an exam invigilator, and the lessons it affects come with substitution
+// suggestions.
+type TeacherLeaveService struct {
+	store       teacherLeaveStore
+	teachers    ports.TeacherReader
+	terms       ports.TermReader
+	schedules   leaveScheduleReader
+	assignments leaveAssignmentReader
+	calendar    attendanceCalendar
+	validator   *validator.Validate
+	logger      *zap.Logger
+	cfg         TeacherLeaveConfig
+	now         func() time.Time
+}
+
+// NewTeacherLeaveService constructs the service.
+func NewTeacherLeaveService(params TeacherLeaveServiceParams) *TeacherLeaveService {
+	validate := params.Validator
+	if validate == nil {
+		validate = validator.New()
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	cfg := params.Config
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if len(cfg.NonSchoolWeekdays) == 0 {
+		cfg.NonSchoolWeekdays = []time.Weekday{time.Sunday}
+	}
+	return &TeacherLeaveService{
+		store:       params.Store,
+		teachers:    params.Teachers,
+		terms:       params.Terms,
+		schedules:   params.Schedules,
+		assignments: params.Assignments,
+		calendar:    params.Calendar,
+		validator:   validate,
+		logger:      logger,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// Request files a pending leave. Teachers file for themselves and administrators for any teacher.
+// The range must cover at least one school day and not overlap another pending or approved leave.
+func (s *TeacherLeaveService) Request(ctx context.Context, req dto.TeacherLeaveRequest, claims *models.JWTClaims) (*models.TeacherLeave, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	req.Type = strings.ToUpper(strings.TrimSpace(req.Type))
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid leave payload")
+	}
+	teacherID := strings.TrimSpace(req.TeacherID)
+	switch claims.Role {
+	case models.RoleTeacher:
+		if teacherID != "" && teacherID != claims.UserID {
+			return nil, appErrors.Clone(appErrors.ErrForbidden, "teachers can only request leave for themselves")
+		}
+		teacherID = claims.UserID
+	case models.RoleAdmin, models.RoleSuperAdmin:
+		if teacherID == "" {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "teacherId is required")
+		}
+	default:
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "only teachers and administrators can request leave")
+	}
+
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "startDate must use YYYY-MM-DD format")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "endDate must use YYYY-MM-DD format")
+	}
+	if end.Before(start) {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "endDate must not be before startDate")
+	}
+	if end.Sub(start) >= maxTeacherLeaveDays*24*time.Hour {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("a leave may span at most %d days", maxTeacherLeaveDays))
+	}
+
+	if _, err := s.teachers.FindByID(ctx, teacherID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "teacher not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+	days, err := s.schoolDays(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(days) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "leave does not cover any school day")
+	}
+	overlap, err := s.store.HasOverlap(ctx, teacherID, start, end)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to check overlapping leaves")
+	}
+	if overlap {
+		return nil, appErrors.Clone(appErrors.ErrConflict, "leave overlaps another pending or approved leave")
+	}
+
+	leave := &models.TeacherLeave{
+		ID:          uuid.NewString(),
+		TeacherID:   teacherID,
+		Type:        models.TeacherLeaveType(req.Type),
+		StartDate:   start,
+		EndDate:     end,
+		Days:        len(days),
+		Reason:      req.Reason,
+		Status:      models.TeacherLeavePending,
+		RequestedBy: claims.UserID,
+	}
+	if err := s.store.Create(ctx, leave); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to create leave")
+	}
+	return s.get(ctx, leave.ID)
+}
+
+// List returns leaves matching the query. Teachers only see their own.
+func (s *TeacherLeaveService) List(ctx context.Context, query dto.TeacherLeaveQuery, claims *models.JWTClaims) ([]models.TeacherLeave, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	query.Status = strings.ToUpper(strings.TrimSpace(query.Status))
+	if err := s.validator.Struct(query); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid leave query")
+	}
+	filter := models.TeacherLeaveFilter{TeacherID: query.TeacherID, Status: models.TeacherLeaveStatus(query.Status)}
+	if claims.Role == models.RoleTeacher {
+		filter.TeacherID = claims.UserID
+	}
+	if query.From != "" {
+		from, err := time.Parse("2006-01-02", query.From)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "from must use YYYY-MM-DD format")
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return nil, appErrors.Clone(appErrors.ErrValidation, "to must use YYYY-MM-DD format")
+		}
+		filter.To = &to
+	}
+	leaves, err := s.store.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list leaves")
+	}
+	if leaves == nil {
+		leaves = []models.TeacherLeave{}
+	}
+	return leaves, nil
+}
+
+// Get returns a leave. Teachers may only read their own.
+func (s *TeacherLeaveService) Get(ctx context.Context, id string, claims *models.JWTClaims) (*models.TeacherLeave, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	leave, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Role == models.RoleTeacher && leave.TeacherID != claims.UserID {
+		return nil, appErrors.Clone(appErrors.ErrForbidden, "you can only view your own leaves")
+	}
+	return leave, nil
+}
+
+// Approve approves a pending leave.
+func (s *TeacherLeaveService) Approve(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error) {
+	return s.review(ctx, id, req, claims, models.TeacherLeaveApproved)
+}
+
+// Reject rejects a pending leave.
+func (s *TeacherLeaveService) Reject(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error) {
+	return s.review(ctx, id, req, claims, models.TeacherLeaveRejected)
+}
+
+func (s *TeacherLeaveService) review(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims, status models.TeacherLeaveStatus) (*models.TeacherLeave, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid review payload")
+	}
+	leave, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if leave.Status != models.TeacherLeavePending {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, fmt.Sprintf("leave is already %s", strings.ToLower(string(leave.Status))))
+	}
+	if err := s.transition(ctx, leave.ID, []models.TeacherLeaveStatus{models.TeacherLeavePending}, status, claims.UserID, req.Note); err != nil {
+		return nil, err
+	}
+	s.logger.Info("teacher leave reviewed",
+		zap.String("leave_id", leave.ID),
+		zap.String("teacher_id", leave.TeacherID),
+		zap.String("status", string(status)),
+		zap.String("reviewer_id", claims.UserID),
+	)
+	return s.get(ctx, leave.ID)
+}
+
+// Cancel withdraws a leave. Pending leaves can be cancelled any time, approved ones only before they
+// start. Teachers may only cancel their own.
+func (s *TeacherLeaveService) Cancel(ctx context.Context, id string, req dto.TeacherLeaveReviewRequest, claims *models.JWTClaims) (*models.TeacherLeave, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid cancellation payload")
+	}
+	leave, err := s.Get(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	switch leave.Status {
+	case models.TeacherLeavePending:
+	case models.TeacherLeaveApproved:
+		if s.today() >= leave.StartDate.Format("2006-01-02") {
+			return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, "leave has already started")
+		}
+	default:
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, fmt.Sprintf("leave is already %s", strings.ToLower(string(leave.Status))))
+	}
+	if err := s.transition(ctx, leave.ID, []models.TeacherLeaveStatus{leave.Status}, models.TeacherLeaveCancelled, claims.UserID, req.Note); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, leave.ID)
+}
+
+func (s *TeacherLeaveService) transition(ctx context.Context, id string, from []models.TeacherLeaveStatus, status models.TeacherLeaveStatus, actorID string, note *string) error {
+	if err := s.store.SetStatus(ctx, id, from, status, actorID, note, s.now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.Clone(appErrors.ErrConflict, "leave was changed in the meantime")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update leave")
+	}
+	return nil
+}
+
+// Substitutions lists the lessons a pending or approved leave takes the teacher away from, date by
+// date, with free teachers who could cover each one. Candidates are teachers of the same subject or
+// class in the term who have no lesson in that slot and are not on approved leave themselves.
+func (s *TeacherLeaveService) Substitutions(ctx context.Context, id string) (*dto.TeacherLeaveSubstitutions, error) {
+	leave, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if leave.Status != models.TeacherLeavePending && leave.Status != models.TeacherLeaveApproved {
+		return nil, appErrors.Clone(appErrors.ErrPreconditionFailed, fmt.Sprintf("leave is %s", strings.ToLower(string(leave.Status))))
+	}
+	days, err := s.schoolDays(ctx, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.TeacherLeaveSubstitutions{LeaveID: leave.ID, TeacherID: leave.TeacherID, Slots: []dto.LeaveAffectedSlot{}}
+	if len(days) > maxSubstitutionDays {
+		days = days[:maxSubstitutionDays]
+		result.Truncated = true
+	}
+	lessons, err := s.schedules.ListByTeacher(ctx, leave.TeacherID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher schedule")
+	}
+	sortLessonsBySlot(lessons)
+
+	planner := newSubstitutePlanner(s)
+	for _, day := range days {
+		for _, lesson := range lessons {
+			if dayStringToIndex(lesson.DayOfWeek) != isoWeekday(day) {
+				continue
+			}
+			inTerm, err := planner.termCovers(ctx, lesson.TermID, day)
+			if err != nil {
+				return nil, err
+			}
+			if !inTerm {
+				continue
+			}
+			candidates, err := planner.candidates(ctx, leave.TeacherID, lesson, day)
+			if err != nil {
+				return nil, err
+			}
+			result.Slots = append(result.Slots, dto.LeaveAffectedSlot{
+				Date:       day.Format("2006-01-02"),
+				DayOfWeek:  lesson.DayOfWeek,
+				TimeSlot:   lesson.TimeSlot,
+				ScheduleID: lesson.ID,
+				TermID:     lesson.TermID,
+				ClassID:    lesson.ClassID,
+				SubjectID:  lesson.SubjectID,
+				Room:       lesson.Room,
+				Candidates: candidates,
+			})
+		}
+	}
+	return result, nil
+}
+
+// Balance reports each active teacher's leave allowance, approved and pending days per type for
+// leaves starting in the year; year 0 means the current year. Teachers only get their own balance.
+func (s *TeacherLeaveService) Balance(ctx context.Context, year int, teacherID string, claims *models.JWTClaims) (*dto.TeacherLeaveBalanceReport, error) {
+	if claims == nil {
+		return nil, appErrors.ErrUnauthorized
+	}
+	if year == 0 {
+		year = s.now().In(s.cfg.Location).Year()
+	}
+	if year < 2000 || year > 2100 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "year is out of range")
+	}
+	if claims.Role == models.RoleTeacher {
+		teacherID = claims.UserID
+	}
+	usage, err := s.store.Usage(ctx, year, teacherID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load leave usage")
+	}
+
+	report := &dto.TeacherLeaveBalanceReport{Year: year, Teachers: []dto.TeacherLeaveBalance{}}
+	index := make(map[string]int)
+	totals := make(map[string]map[models.TeacherLeaveType]models.TeacherLeaveUsage)
+	for _, row := range usage {
+		if _, ok := index[row.TeacherID]; !ok {
+			index[row.TeacherID] = len(report.Teachers)
+			report.Teachers = append(report.Teachers, dto.TeacherLeaveBalance{TeacherID: row.TeacherID, FullName: row.TeacherName})
+			totals[row.TeacherID] = make(map[models.TeacherLeaveType]models.TeacherLeaveUsage)
+		}
+		if row.Type != nil {
+			totals[row.TeacherID][*row.Type] = row
+		}
+	}
+	for i := range report.Teachers {
+		balance := &report.Teachers[i]
+		for _, leaveType := range models.TeacherLeaveTypes {
+			row := totals[balance.TeacherID][leaveType]
+			entry := dto.TeacherLeaveTypeBalance{Type: string(leaveType), Used: row.Used, Pending: row.Pending}
+			if allowance, ok := s.cfg.Allowances[leaveType]; ok && allowance > 0 {
+				remaining := allowance - row.Used
+				entry.Allowance = &allowance
+				entry.Overdrawn = remaining < 0
+				if remaining < 0 {
+					remaining = 0
+				}
+				entry.Remaining = &remaining
+			}
+			balance.Types = append(balance.Types, entry)
+		}
+	}
+	return report, nil
+}
+
+func (s *TeacherLeaveService) get(ctx context.Context, id string) (*models.TeacherLeave, error) {
+	leave, err := s.store.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "leave not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to get leave")
+	}
+	return leave, nil
+}
+
+// schoolDays lists the dates from start to end that are neither non-school weekdays nor school-wide
+// holidays.
+func (s *TeacherLeaveService) schoolDays(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	var days []time.Time
+	for day := dateOnly(start); !day.After(dateOnly(end)); day = day.AddDate(0, 0, 1) {
+		if s.nonSchoolWeekday(day) {
+			continue
+		}
+		if s.calendar != nil {
+			holiday, err := s.calendar.HolidayOn(ctx, day, "")
+			if err != nil {
+				return nil, err
+			}
+			if holiday != nil {
+				continue
+			}
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (s *TeacherLeaveService) nonSchoolWeekday(day time.Time) bool {
+	for _, weekday := range s.cfg.NonSchoolWeekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *TeacherLeaveService) today() string {
+	return s.now().In(s.cfg.Location).Format("2006-01-02")
+}
+
+// sortLessonsBySlot orders lessons by numeric time slot, keeping the repository order otherwise.
+func sortLessonsBySlot(lessons []models.Schedule) {
+	sort.SliceStable(lessons, func(i, j int) bool {
+		a, errA := strconv.Atoi(lessons[i].TimeSlot)
+		b, errB := strconv.Atoi(lessons[j].TimeSlot)
+		if errA != nil || errB != nil {
+			return lessons[i].TimeSlot < lessons[j].TimeSlot
+		}
+		return a < b
+	})
+}
+
+// substitutePlanner caches the lookups of one substitution request.
+type substitutePlanner struct {
+	svc         *TeacherLeaveService
+	terms       map[string]*models.Term
+	lessons     map[string][]models.Schedule
+	assignments map[string][]models.TeacherAssignment
+	onLeave     map[string]map[string]bool
+	names       map[string]string
+}
+
+func newSubstitutePlanner(svc *TeacherLeaveService) *substitutePlanner {
+	return &substitutePlanner{
+		svc:         svc,
+		terms:       make(map[string]*models.Term),
+		lessons:     make(map[string][]models.Schedule),
+		assignments: make(map[string][]models.TeacherAssignment),
+		onLeave:     make(map[string]map[string]bool),
+		names:       make(map[string]string),
+	}
+}
+
+// termCovers reports whether the lesson's term runs on the date. Lessons of unknown terms are skipped.
+func (p *substitutePlanner) termCovers(ctx context.Context, termID string, day time.Time) (bool, error) {
+	term, ok := p.terms[termID]
+	if !ok {
+		var err error
+		term, err = p.svc.terms.FindByID(ctx, termID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return false, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+		}
+		p.terms[termID] = term
+	}
+	if term == nil {
+		return false, nil
+	}
+	return !day.Before(dateOnly(term.StartDate)) && !day.After(dateOnly(term.EndDate)), nil
+}
+
+func (p *substitutePlanner) candidates(ctx context.Context, absentID string, lesson models.Schedule, day time.Time) ([]dto.SubstituteCandidate, error) {
+	termLessons, err := p.termLessons(ctx, lesson.TermID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := p.termAssignments(ctx, lesson.TermID)
+	if err != nil {
+		return nil, err
+	}
+	onLeave, err := p.teachersOnLeave(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	weekday := isoWeekday(day)
+	busy := make(map[string]bool)
+	load := make(map[string]int)
+	for _, other := range termLessons {
+		if dayStringToIndex(other.DayOfWeek) != weekday {
+			continue
+		}
+		load[other.TeacherID]++
+		if other.TimeSlot == lesson.TimeSlot {
+			busy[other.TeacherID] = true
+		}
+	}
+
+	byTeacher := make(map[string]*dto.SubstituteCandidate)
+	var order []string
+	for _, assignment := range assignments {
+		teacherID := assignment.TeacherID
+		if teacherID == absentID || busy[teacherID] || onLeave[teacherID] {
+			continue
+		}
+		teachesSubject := assignment.SubjectID == lesson.SubjectID
+		teachesClass := assignment.ClassID == lesson.ClassID
+		if !teachesSubject && !teachesClass {
+			continue
+		}
+		candidate, ok := byTeacher[teacherID]
+		if !ok {
+			candidate = &dto.SubstituteCandidate{TeacherID: teacherID, LessonsThatDay: load[teacherID]}
+			byTeacher[teacherID] = candidate
+			order = append(order, teacherID)
+		}
+		candidate.TeachesSubject = candidate.TeachesSubject || teachesSubject
+		candidate.TeachesClass = candidate.TeachesClass || teachesClass
+	}
+
+	candidates := make([]dto.SubstituteCandidate, 0, len(order))
+	for _, teacherID := range order {
+		candidates = append(candidates, *byTeacher[teacherID])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.TeachesSubject != b.TeachesSubject {
+			return a.TeachesSubject
+		}
+		if a.TeachesClass != b.TeachesClass {
+			return a.TeachesClass
+		}
+		if a.LessonsThatDay != b.LessonsThatDay {
+			return a.LessonsThatDay < b.LessonsThatDay
+		}
+		return a.TeacherID < b.TeacherID
+	})
+	if len(candidates) > substituteCandidateLimit {
+		candidates = candidates[:substituteCandidateLimit]
+	}
+	for i := range candidates {
+		name, err := p.teacherName(ctx, candidates[i].TeacherID)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i].FullName = name
+	}
+	return candidates, nil
+}
+
+func (p *substitutePlanner) termLessons(ctx context.Context, termID string) ([]models.Schedule, error) {
+	if lessons, ok := p.lessons[termID]; ok {
+		return lessons, nil
+	}
+	lessons, err := p.svc.schedules.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term schedules")
+	}
+	p.lessons[termID] = lessons
+	return lessons, nil
+}
+
+func (p *substitutePlanner) termAssignments(ctx context.Context, termID string) ([]models.TeacherAssignment, error) {
+	if assignments, ok := p.assignments[termID]; ok {
+		return assignments, nil
+	}
+	assignments, err := p.svc.assignments.ListByTerm(ctx, termID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher assignments")
+	}
+	p.assignments[termID] = assignments
+	return assignments, nil
+}
+
+func (p *substitutePlanner) teachersOnLeave(ctx context.Context, day time.Time) (map[string]bool, error) {
+	key := day.Format("2006-01-02")
+	if set, ok := p.onLeave[key]; ok {
+		return set, nil
+	}
+	ids, err := p.svc.store.TeachersOnLeave(ctx, day)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teachers on leave")
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	p.onLeave[key] = set
+	return set, nil
+}
+
+func (p *substitutePlanner) teacherName(ctx context.Context, teacherID string) (string, error) {
+	if name, ok := p.names[teacherID]; ok {
+		return name, nil
+	}
+	teacher, err := p.svc.teachers.FindByID(ctx, teacherID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+	name := ""
+	if teacher != nil {
+		name = teacher.FullName
+	}
+	p.names[teacherID] = name
+	return name, nil
+}
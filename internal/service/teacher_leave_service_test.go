@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type teacherLeaveStoreStub struct {
+	leaves []*models.TeacherLeave
+	usage  []models.TeacherLeaveUsage
+}
+
+func (s *teacherLeaveStoreStub) Create(ctx context.Context, leave *models.TeacherLeave) error {
+	copied := *leave
+	s.leaves = append(s.leaves, &copied)
+	return nil
+}
+
+func (s *teacherLeaveStoreStub) FindByID(ctx context.Context, id string) (*models.TeacherLeave, error) {
+	for _, leave := range s.leaves {
+		if leave.ID == id {
+			copied := *leave
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *teacherLeaveStoreStub) List(ctx context.Context, filter models.TeacherLeaveFilter) ([]models.TeacherLeave, error) {
+	var result []models.TeacherLeave
+	for _, leave := range s.leaves {
+		if filter.TeacherID == "" || leave.TeacherID == filter.TeacherID {
+			result = append(result, *leave)
+		}
+	}
+	return result, nil
+}
+
+func (s *teacherLeaveStoreStub) HasOverlap(ctx context.Context, teacherID string, start, end time.Time) (bool, error) {
+	for _, leave := range s.leaves {
+		active := leave.Status == models.TeacherLeavePending || leave.Status == models.TeacherLeaveApproved
+		if active && leave.TeacherID == teacherID && !leave.StartDate.After(end) && !leave.EndDate.Before(start) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *teacherLeaveStoreStub) SetStatus(ctx context.Context, id string, from []models.TeacherLeaveStatus, status models.TeacherLeaveStatus, actorID string, note *string, at time.Time) error {
+	for _, leave := range s.leaves {
+		if leave.ID != id {
+			continue
+		}
+		for _, expected := range from {
+			if leave.Status == expected {
+				leave.Status, leave.ReviewedBy, leave.ReviewedAt, leave.ReviewNote = status, &actorID, &at, note
+				return nil
+			}
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (s *teacherLeaveStoreStub) TeachersOnLeave(ctx context.Context, date time.Time) ([]string, error) {
+	var ids []string
+	for _, leave := range s.leaves {
+		if leave.Status == models.TeacherLeaveApproved && leave.Covers(date) {
+			ids = append(ids, leave.TeacherID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *teacherLeaveStoreStub) Usage(ctx context.Context, year int, teacherID string) ([]models.TeacherLeaveUsage, error) {
+	var result []models.TeacherLeaveUsage
+	for _, row := range s.usage {
+		if teacherID == "" || row.TeacherID == teacherID {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+type leaveScheduleStub struct {
+	lessons []models.Schedule
+}
+
+func (s leaveScheduleStub) ListByTeacher(ctx context.Context, teacherID string) ([]models.Schedule, error) {
+	var result []models.Schedule
+	for _, lesson := range s.lessons {
+		if lesson.TeacherID == teacherID {
+			result = append(result, lesson)
+		}
+	}
+	return result, nil
+}
+
+func (s leaveScheduleStub) ListByTerm(ctx context.Context, termID string) ([]models.Schedule, error) {
+	var result []models.Schedule
+	for _, lesson := range s.lessons {
+		if lesson.TermID == termID {
+			result = append(result, lesson)
+		}
+	}
+	return result, nil
+}
+
+type leaveAssignmentStub []models.TeacherAssignment
+
+func (s leaveAssignmentStub) ListByTerm(ctx context.Context, termID string) ([]models.TeacherAssignment, error) {
+	return s, nil
+}
+
+func newTeacherLeaveFixture(lessons []models.Schedule, assignments []models.TeacherAssignment) (*TeacherLeaveService, *teacherLeaveStoreStub) {
+	store := &teacherLeaveStoreStub{}
+	svc := NewTeacherLeaveService(TeacherLeaveServiceParams{
+		Store:    store,
+		Teachers: examTeacherStub{},
+		Terms: examTermStub{term: &models.Term{
+			ID:        "term-1",
+			StartDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+		}},
+		Schedules:   leaveScheduleStub{lessons: lessons},
+		Assignments: leaveAssignmentStub(assignments),
+		Config:      TeacherLeaveConfig{Allowances: map[models.TeacherLeaveType]int{models.TeacherLeaveSick: 12, models.TeacherLeavePersonal: 3}},
+	})
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+	return svc, store
+}
+
+func TestTeacherLeaveServiceRequest(t *testing.T) {
+	svc, _ := newTeacherLeaveFixture(nil, nil)
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "t1", Role: models.RoleTeacher}
+
+	// Friday to Monday skips the Sunday.
+	leave, err := svc.Request(ctx, dto.TeacherLeaveRequest{Type: "sick", StartDate: "2026-03-06", EndDate: "2026-03-09"}, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, "t1", leave.TeacherID)
+	assert.Equal(t, models.TeacherLeaveSick, leave.Type)
+	assert.Equal(t, models.TeacherLeavePending, leave.Status)
+	assert.Equal(t, 3, leave.Days)
+
+	cases := []struct {
+		name   string
+		req    dto.TeacherLeaveRequest
+		claims *models.JWTClaims
+		code   string
+	}{
+		{"overlap", dto.TeacherLeaveRequest{Type: "PERSONAL", StartDate: "2026-03-09", EndDate: "2026-03-10"}, teacher, appErrors.ErrConflict.Code},
+		{"sunday only", dto.TeacherLeaveRequest{Type: "PERSONAL", StartDate: "2026-03-15", EndDate: "2026-03-15"}, teacher, appErrors.ErrValidation.Code},
+		{"reversed range", dto.TeacherLeaveRequest{Type: "PERSONAL", StartDate: "2026-03-20", EndDate: "2026-03-19"}, teacher, appErrors.ErrValidation.Code},
+		{"unknown type", dto.TeacherLeaveRequest{Type: "VACATION", StartDate: "2026-03-20", EndDate: "2026-03-20"}, teacher, appErrors.ErrValidation.Code},
+		{"other teacher", dto.TeacherLeaveRequest{TeacherID: "t2", Type: "SICK", StartDate: "2026-03-20", EndDate: "2026-03-20"}, teacher, appErrors.ErrForbidden.Code},
+		{"admin without teacher", dto.TeacherLeaveRequest{Type: "SICK", StartDate: "2026-03-20", EndDate: "2026-03-20"}, &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}, appErrors.ErrValidation.Code},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.Request(ctx, tc.req, tc.claims)
+			require.Error(t, err)
+			assert.Equal(t, tc.code, appErrors.FromError(err).Code)
+		})
+	}
+
+	onBehalf, err := svc.Request(ctx, dto.TeacherLeaveRequest{TeacherID: "t2", Type: "TRAINING", StartDate: "2026-03-09", EndDate: "2026-03-09"},
+		&models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, "t2", onBehalf.TeacherID)
+	assert.Equal(t, "admin-1", onBehalf.RequestedBy)
+}
+
+func TestTeacherLeaveServiceReviewAndCancel(t *testing.T) {
+	svc, _ := newTeacherLeaveFixture(nil, nil)
+	ctx := context.Background()
+	teacher := &models.JWTClaims{UserID: "t1", Role: models.RoleTeacher}
+	admin := &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin}
+
+	current, err := svc.Request(ctx, dto.TeacherLeaveRequest{Type: "SICK", StartDate: "2026-02-26", EndDate: "2026-03-03"}, teacher)
+	require.NoError(t, err)
+	future, err := svc.Request(ctx, dto.TeacherLeaveRequest{Type: "PERSONAL", StartDate: "2026-03-20", EndDate: "2026-03-20"}, teacher)
+	require.NoError(t, err)
+
+	note := "get well soon"
+	approved, err := svc.Approve(ctx, current.ID, dto.TeacherLeaveReviewRequest{Note: &note}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, models.TeacherLeaveApproved, approved.Status)
+	require.NotNil(t, approved.ReviewedBy)
+	assert.Equal(t, "admin-1", *approved.ReviewedBy)
+
+	_, err = svc.Reject(ctx, current.ID, dto.TeacherLeaveReviewRequest{}, admin)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	// The approved leave has started, so it can no longer be withdrawn.
+	_, err = svc.Cancel(ctx, current.ID, dto.TeacherLeaveReviewRequest{}, teacher)
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrPreconditionFailed.Code, appErrors.FromError(err).Code)
+
+	_, err = svc.Cancel(ctx, future.ID, dto.TeacherLeaveReviewRequest{}, &models.JWTClaims{UserID: "t2", Role: models.RoleTeacher})
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrForbidden.Code, appErrors.FromError(err).Code)
+
+	cancelled, err := svc.Cancel(ctx, future.ID, dto.TeacherLeaveReviewRequest{}, teacher)
+	require.NoError(t, err)
+	assert.Equal(t, models.TeacherLeaveCancelled, cancelled.Status)
+}
+
+func TestTeacherLeaveServiceSubstitutions(t *testing.T) {
+	lessons := []models.Schedule{
+		{ID: "s1", TermID: "term-1", ClassID: "c1", SubjectID: "math", TeacherID: "t1", DayOfWeek: "MONDAY", TimeSlot: "1"},
+		{ID: "s2", TermID: "term-1", ClassID: "c1", SubjectID: "math", TeacherID: "t1", DayOfWeek: "WEDNESDAY", TimeSlot: "3"},
+		{ID: "s3", TermID: "term-1", ClassID: "c2", SubjectID: "math", TeacherID: "t2", DayOfWeek: "MONDAY", TimeSlot: "2"},
+		{ID: "s4", TermID: "term-1", ClassID: "c3", SubjectID: "math", TeacherID: "t4", DayOfWeek: "MONDAY", TimeSlot: "1"},
+		{ID: "s5", TermID: "term-1", ClassID: "c1", SubjectID: "bio", TeacherID: "t3", DayOfWeek: "TUESDAY", TimeSlot: "1"},
+	}
+	assignments := []models.TeacherAssignment{
+		{TeacherID: "t1", ClassID: "c1", SubjectID: "math"},
+		{TeacherID: "t2", ClassID: "c2", SubjectID: "math"},
+		{TeacherID: "t3", ClassID: "c1", SubjectID: "bio"},
+		{TeacherID: "t4", ClassID: "c3", SubjectID: "math"},
+		{TeacherID: "t5", ClassID: "c4", SubjectID: "math"},
+		{TeacherID: "t6", ClassID: "c5", SubjectID: "art"},
+	}
+	svc, store := newTeacherLeaveFixture(lessons, assignments)
+	store.leaves = []*models.TeacherLeave{
+		{ID: "leave-1", TeacherID: "t1", Status: models.TeacherLeaveApproved,
+			StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "leave-2", TeacherID: "t5", Status: models.TeacherLeaveApproved,
+			StartDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	result, err := svc.Substitutions(context.Background(), "leave-1")
+	require.NoError(t, err)
+	require.Len(t, result.Slots, 1)
+	slot := result.Slots[0]
+	assert.Equal(t, "2026-03-02", slot.Date)
+	assert.Equal(t, "s1", slot.ScheduleID)
+	// t4 teaches in the same slot and t5 is on leave; t6 neither teaches math nor the class.
+	require.Len(t, slot.Candidates, 2)
+	assert.Equal(t, dto.SubstituteCandidate{TeacherID: "t2", FullName: "Teacher t2", TeachesSubject: true, LessonsThatDay: 1}, slot.Candidates[0])
+	assert.Equal(t, dto.SubstituteCandidate{TeacherID: "t3", FullName: "Teacher t3", TeachesClass: true}, slot.Candidates[1])
+}
+
+func TestTeacherLeaveServiceBalance(t *testing.T) {
+	svc, store := newTeacherLeaveFixture(nil, nil)
+	sick, personal := models.TeacherLeaveSick, models.TeacherLeavePersonal
+	store.usage = []models.TeacherLeaveUsage{
+		{TeacherID: "t1", TeacherName: "Ani", Type: &sick, Used: 4, Pending: 2},
+		{TeacherID: "t1", TeacherName: "Ani", Type: &personal, Used: 5},
+		{TeacherID: "t2", TeacherName: "Budi"},
+	}
+
+	report, err := svc.Balance(context.Background(), 0, "", &models.JWTClaims{UserID: "admin-1", Role: models.RoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, 2026, report.Year)
+	require.Len(t, report.Teachers, 2)
+
+	types := report.Teachers[0].Types
+	require.Len(t, types, 3)
+	assert.Equal(t, 4, types[0].Used)
+	assert.Equal(t, 2, types[0].Pending)
+	assert.Equal(t, 8, *types[0].Remaining)
+	assert.Equal(t, 0, *types[1].Remaining)
+	assert.True(t, types[1].Overdrawn)
+	assert.Nil(t, types[2].Allowance)
+	assert.Equal(t, 12, *report.Teachers[1].Types[0].Remaining)
+
+	own, err := svc.Balance(context.Background(), 2026, "t1", &models.JWTClaims{UserID: "t2", Role: models.RoleTeacher})
+	require.NoError(t, err)
+	require.Len(t, own.Teachers, 1)
+	assert.Equal(t, "t2", own.Teachers[0].TeacherID)
+}
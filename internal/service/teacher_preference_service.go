@@ -10,12 +10,15 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
 	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
 )
 
 type teacherPreferenceRepo interface {
 	GetByTeacher(ctx context.Context, teacherID string) (*models.TeacherPreference, error)
 	Upsert(ctx context.Context, pref *models.TeacherPreference) error
+	List(ctx context.Context) ([]models.TeacherPreference, error)
+	UpsertMany(ctx context.Context, prefs []models.TeacherPreference) error
 }
 
 // UpsertTeacherPreferenceRequest captures payload to store preferences.
@@ -27,26 +30,43 @@ type UpsertTeacherPreferenceRequest struct {
 
 // TeacherPreferenceService handles preference logic.
 type TeacherPreferenceService struct {
-	teachers  teacherRepository
-	repo      teacherPreferenceRepo
-	validator *validator.Validate
-	logger    *zap.Logger
+	teachers    teacherRepository
+	repo        teacherPreferenceRepo
+	validator   *validator.Validate
+	logger      *zap.Logger
+	revalidator scheduleRevalidator
+	terms       ports.TermReader
+	slots       SlotTimeLabeler
+}
+
+// TeacherPreferenceServiceOption customises TeacherPreferenceService.
+type TeacherPreferenceServiceOption func(*TeacherPreferenceService)
+
+// WithPreferenceRevalidation re-checks the teacher's published schedules after preferences change.
+func WithPreferenceRevalidation(revalidator scheduleRevalidator) TeacherPreferenceServiceOption {
+	return func(s *TeacherPreferenceService) {
+		s.revalidator = revalidator
+	}
 }
 
 // NewTeacherPreferenceService builds the service.
-func NewTeacherPreferenceService(teachers teacherRepository, repo teacherPreferenceRepo, validate *validator.Validate, logger *zap.Logger) *TeacherPreferenceService {
+func NewTeacherPreferenceService(teachers teacherRepository, repo teacherPreferenceRepo, validate *validator.Validate, logger *zap.Logger, opts ...TeacherPreferenceServiceOption) *TeacherPreferenceService {
 	if validate == nil {
 		validate = validator.New()
 	}
 	if logger == nil {
 		logger = zap.NewNop()
 	}
-	return &TeacherPreferenceService{
+	svc := &TeacherPreferenceService{
 		teachers:  teachers,
 		repo:      repo,
 		validator: validate,
 		logger:    logger,
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
 }
 
 // Get returns stored preferences or defaults.
@@ -113,5 +133,10 @@ func (s *TeacherPreferenceService) Upsert(ctx context.Context, teacherID string,
 	if err := s.repo.Upsert(ctx, payload); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to upsert teacher preferences")
 	}
+	if s.revalidator != nil {
+		if err := s.revalidator.RevalidateTeacher(ctx, teacherID); err != nil {
+			s.logger.Warn("failed to revalidate published schedules", zap.String("teacher_id", teacherID), zap.Error(err))
+		}
+	}
 	return payload, nil
 }
@@ -12,10 +12,14 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
 )
 
 type prefRepoMock struct {
 	stored *models.TeacherPreference
+	listed []models.TeacherPreference
+	many   []models.TeacherPreference
 	err    error
 }
 
@@ -36,6 +40,15 @@ func (m *prefRepoMock) Upsert(ctx context.Context, pref *models.TeacherPreferenc
 	return nil
 }
 
+func (m *prefRepoMock) List(ctx context.Context) ([]models.TeacherPreference, error) {
+	return m.listed, m.err
+}
+
+func (m *prefRepoMock) UpsertMany(ctx context.Context, prefs []models.TeacherPreference) error {
+	m.many = append(m.many, prefs...)
+	return m.err
+}
+
 func TestTeacherPreferenceServiceGetDefault(t *testing.T) {
 	teacherRepo := &teacherRepoStub{
 		items: map[string]*models.Teacher{"teacher-1": {ID: "teacher-1", Active: true}},
@@ -67,3 +80,81 @@ func TestTeacherPreferenceServiceUpsert(t *testing.T) {
 	assert.Equal(t, 4, result.MaxLoadPerDay)
 	assert.NotNil(t, repo.stored)
 }
+
+// teacherListStub serves List from a fixed roster, one page at a time.
+type teacherListStub struct {
+	teacherRepoStub
+	roster []models.Teacher
+}
+
+func (s *teacherListStub) List(ctx context.Context, filter models.TeacherFilter) ([]models.Teacher, int, error) {
+	start := (filter.Page - 1) * filter.PageSize
+	if start >= len(s.roster) {
+		return nil, len(s.roster), nil
+	}
+	end := start + filter.PageSize
+	if end > len(s.roster) {
+		end = len(s.roster)
+	}
+	return s.roster[start:end], len(s.roster), nil
+}
+
+func TestTeacherPreferenceServiceSheetRoundTrip(t *testing.T) {
+	nip := "19800101"
+	teachers := &teacherListStub{roster: []models.Teacher{
+		{ID: "teacher-1", FullName: "Ani", NIP: &nip, Active: true},
+		{ID: "teacher-2", FullName: "Budi", Active: true},
+	}}
+	repo := &prefRepoMock{listed: []models.TeacherPreference{{
+		TeacherID: "teacher-1", MaxLoadPerDay: 6, MaxLoadPerWeek: 24,
+		Unavailable: types.JSONText(`[{"day_of_week":"MONDAY","time_range":"1-2"},{"day_of_week":"MONDAY","time_range":"7"}]`),
+	}}}
+	slots := StaticSlotLabels{1: "07:00", 2: "07:45", 3: "08:30", 7: "11:30"}
+	service := NewTeacherPreferenceService(teachers, repo, validator.New(), zap.NewNop(), WithPreferenceSheets(stubTermRepo{}, slots))
+
+	sheet, err := service.ExportSheet(context.Background())
+	require.NoError(t, err)
+	rows, err := export.ReadXLSX(sheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 5) // title, blank, header and one row per teacher
+	assert.Equal(t, []string{"teacher-1", "19800101", "Ani", "6", "24", "1-2, 7"}, rows[3])
+	assert.Equal(t, []string{"teacher-2", "", "Budi"}, rows[4])
+
+	// Importing the untouched template stores the same preferences.
+	result, err := service.ImportSheet(context.Background(), "term-1", sheet)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Applied)
+	require.Len(t, repo.many, 2)
+	assert.JSONEq(t, `[{"day_of_week":"MONDAY","time_range":"1-2"},{"day_of_week":"MONDAY","time_range":"7"}]`, string(repo.many[0].Unavailable))
+	assert.JSONEq(t, `[]`, string(repo.many[1].Unavailable))
+
+	// Invalid rows are reported while valid ones are still stored.
+	repo.many = nil
+	filled, err := export.NewXLSXExporter().Render(export.Dataset{
+		Headers: []string{"Teacher ID", "Max Load Per Day", "Monday", "Tuesday"},
+		Rows: []map[string]string{
+			{"Teacher ID": "teacher-1", "Max Load Per Day": "5", "Tuesday": "3"},
+			{"Teacher ID": "teacher-2", "Monday": "2-5"},
+			{"Teacher ID": "teacher-9", "Max Load Per Day": "many"},
+			{"Teacher ID": "teacher-1"},
+		},
+	}, "")
+	require.NoError(t, err)
+	result, err = service.ImportSheet(context.Background(), "term-1", filled)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Received)
+	assert.Equal(t, 1, result.Applied)
+	assert.Equal(t, 3, result.Failed)
+	assert.True(t, result.Rows[0].Applied)
+	assert.Equal(t, []string{`Monday "2-5": slots 4, 5 are not defined for the term`}, result.Rows[1].Errors)
+	assert.Equal(t, []string{"teacher not found or inactive", "max load per day must be a whole number of at least 0"}, result.Rows[2].Errors)
+	assert.Equal(t, 5, result.Rows[3].Row)
+	assert.Equal(t, []string{"teacher already listed on row 2"}, result.Rows[3].Errors)
+	require.Len(t, repo.many, 1)
+	assert.Equal(t, 5, repo.many[0].MaxLoadPerDay)
+	assert.JSONEq(t, `[{"day_of_week":"TUESDAY","time_range":"3"}]`, string(repo.many[0].Unavailable))
+
+	_, err = service.ImportSheet(context.Background(), "term-1", []byte("not a workbook"))
+	require.Error(t, err)
+	assert.Equal(t, appErrors.ErrValidation.Code, appErrors.FromError(err).Code)
+}
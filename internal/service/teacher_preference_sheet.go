@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	"github.com/noah-isme/sma-adp-api/internal/ports"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+	"github.com/noah-isme/sma-adp-api/pkg/export"
+)
+
+const (
+	// maxPreferenceImportRows bounds the teacher rows of an imported preference sheet.
+	maxPreferenceImportRows   = 2000
+	preferenceTeacherPageSize = 100
+	preferenceSheetTitle      = "Teacher preferences"
+	// preferenceSheetDays is the number of day columns, Monday through Sunday.
+	preferenceSheetDays = 7
+)
+
+// Preference sheet columns. Day columns, named after the day, hold the unavailable slots of that day
+// as comma separated slot numbers or ranges, e.g. "1-3, 7".
+const (
+	preferenceColumnTeacherID  = "Teacher ID"
+	preferenceColumnNIP        = "NIP"
+	preferenceColumnName       = "Name"
+	preferenceColumnMaxPerDay  = "Max Load Per Day"
+	preferenceColumnMaxPerWeek = "Max Load Per Week"
+)
+
+// WithPreferenceSheets enables spreadsheet import, validating unavailable windows against the slots
+// of the import's term.
+func WithPreferenceSheets(terms ports.TermReader, slots SlotTimeLabeler) TeacherPreferenceServiceOption {
+	return func(s *TeacherPreferenceService) {
+		s.terms = terms
+		s.slots = slots
+	}
+}
+
+// ExportSheet renders an xlsx template listing every active teacher with their stored preferences.
+func (s *TeacherPreferenceService) ExportSheet(ctx context.Context) ([]byte, error) {
+	teachers, err := s.activeTeachers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher preferences")
+	}
+	byTeacher := make(map[string]models.TeacherPreference, len(prefs))
+	for _, pref := range prefs {
+		byTeacher[pref.TeacherID] = pref
+	}
+
+	headers := preferenceSheetHeaders()
+	rows := make([]map[string]string, 0, len(teachers))
+	for _, teacher := range teachers {
+		row := map[string]string{
+			preferenceColumnTeacherID: teacher.ID,
+			preferenceColumnName:      teacher.FullName,
+		}
+		if teacher.NIP != nil {
+			row[preferenceColumnNIP] = *teacher.NIP
+		}
+		if pref, ok := byTeacher[teacher.ID]; ok {
+			row[preferenceColumnMaxPerDay] = strconv.Itoa(pref.MaxLoadPerDay)
+			row[preferenceColumnMaxPerWeek] = strconv.Itoa(pref.MaxLoadPerWeek)
+			var windows []models.TeacherUnavailableSlot
+			_ = json.Unmarshal(pref.Unavailable, &windows) // stored windows were validated on write
+			ranges := make(map[int][]string)
+			for _, window := range windows {
+				if day := dayStringToIndex(window.DayOfWeek); day != 0 {
+					ranges[day] = append(ranges[day], strings.TrimSpace(window.TimeRange))
+				}
+			}
+			for day, values := range ranges {
+				row[displayDayName(day)] = strings.Join(values, ", ")
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	payload, err := export.NewXLSXExporter().Render(export.Dataset{Headers: headers, Rows: rows}, preferenceSheetTitle)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to render preference sheet")
+	}
+	return payload, nil
+}
+
+// ImportSheet parses a filled preference sheet and stores the preferences of every valid row. Rows
+// with an unknown teacher, invalid loads or windows outside the term's slots are reported and skipped.
+func (s *TeacherPreferenceService) ImportSheet(ctx context.Context, termID string, data []byte) (*dto.TeacherPreferenceImportResult, error) {
+	termID = strings.TrimSpace(termID)
+	if termID == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "termId is required")
+	}
+	if s.terms != nil {
+		if _, err := s.terms.FindByID(ctx, termID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, appErrors.Clone(appErrors.ErrNotFound, "term not found")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term")
+		}
+	}
+	sheet, err := export.ReadXLSX(data)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "file is not a valid xlsx workbook")
+	}
+	headerRow, columns, err := locatePreferenceHeader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheet)-headerRow-1 > maxPreferenceImportRows {
+		return nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("preference sheet is limited to %d rows", maxPreferenceImportRows))
+	}
+
+	var slots map[int]string
+	if s.slots != nil {
+		if slots, err = s.slots.SlotLabels(ctx, termID); err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load term slots")
+		}
+	}
+	teachers, err := s.activeTeachers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(teachers))
+	for _, teacher := range teachers {
+		known[teacher.ID] = struct{}{}
+	}
+
+	result := &dto.TeacherPreferenceImportResult{TermID: termID, Rows: []dto.TeacherPreferenceImportRow{}}
+	seen := make(map[string]int)
+	var prefs []models.TeacherPreference
+	var applied []int
+	for i := headerRow + 1; i < len(sheet); i++ {
+		cell := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(sheet[i]) {
+				return ""
+			}
+			return strings.TrimSpace(sheet[i][index])
+		}
+		if isBlankRow(sheet[i]) {
+			continue
+		}
+		row := dto.TeacherPreferenceImportRow{Row: i + 1, TeacherID: cell(preferenceColumnTeacherID)}
+		pref, errs := parsePreferenceRow(cell, slots)
+		switch _, ok := known[row.TeacherID]; {
+		case row.TeacherID == "":
+			errs = append([]string{"teacher id is required"}, errs...)
+		case !ok:
+			errs = append([]string{"teacher not found or inactive"}, errs...)
+		}
+		if first, ok := seen[row.TeacherID]; ok && row.TeacherID != "" {
+			errs = append(errs, fmt.Sprintf("teacher already listed on row %d", first))
+		} else {
+			seen[row.TeacherID] = row.Row
+		}
+		if len(errs) > 0 {
+			row.Errors = errs
+			result.Failed++
+		} else {
+			pref.TeacherID = row.TeacherID
+			prefs = append(prefs, pref)
+			applied = append(applied, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	result.Received = len(result.Rows)
+	if len(prefs) == 0 {
+		return result, nil
+	}
+
+	if err := s.repo.UpsertMany(ctx, prefs); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to store teacher preferences")
+	}
+	for _, index := range applied {
+		result.Rows[index].Applied = true
+	}
+	result.Applied = len(prefs)
+	if s.revalidator != nil {
+		for _, pref := range prefs {
+			if err := s.revalidator.RevalidateTeacher(ctx, pref.TeacherID); err != nil {
+				s.logger.Warn("failed to revalidate published schedules", zap.String("teacher_id", pref.TeacherID), zap.Error(err))
+			}
+		}
+	}
+	return result, nil
+}
+
+// activeTeachers pages through every active teacher, ordered by name.
+func (s *TeacherPreferenceService) activeTeachers(ctx context.Context) ([]models.Teacher, error) {
+	active := true
+	var teachers []models.Teacher
+	for page := 1; ; page++ {
+		batch, total, err := s.teachers.List(ctx, models.TeacherFilter{Active: &active, Page: page, PageSize: preferenceTeacherPageSize, SortBy: "full_name", SortOrder: "asc"})
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list teachers")
+		}
+		teachers = append(teachers, batch...)
+		if len(batch) == 0 || len(teachers) >= total {
+			return teachers, nil
+		}
+	}
+}
+
+func preferenceSheetHeaders() []string {
+	headers := []string{preferenceColumnTeacherID, preferenceColumnNIP, preferenceColumnName, preferenceColumnMaxPerDay, preferenceColumnMaxPerWeek}
+	for day := 1; day <= preferenceSheetDays; day++ {
+		headers = append(headers, displayDayName(day))
+	}
+	return headers
+}
+
+// locatePreferenceHeader finds the header row, which follows the title in exported sheets, and maps
+// the known column names to their index. Header matching ignores case and surrounding spaces.
+func locatePreferenceHeader(sheet [][]string) (int, map[string]int, error) {
+	wanted := make(map[string]string)
+	for _, header := range preferenceSheetHeaders() {
+		wanted[strings.ToLower(header)] = header
+	}
+	for i, row := range sheet {
+		columns := make(map[string]int)
+		for index, value := range row {
+			if header, ok := wanted[strings.ToLower(strings.TrimSpace(value))]; ok {
+				columns[header] = index
+			}
+		}
+		if _, ok := columns[preferenceColumnTeacherID]; ok {
+			return i, columns, nil
+		}
+	}
+	return 0, nil, appErrors.Clone(appErrors.ErrValidation, fmt.Sprintf("preference sheet has no %q column", preferenceColumnTeacherID))
+}
+
+// parsePreferenceRow reads the loads and unavailable windows of a row. Slots are checked against
+// the term's slots when any are known.
+func parsePreferenceRow(cell func(column string) string, slots map[int]string) (models.TeacherPreference, []string) {
+	var errs []string
+	parseLoad := func(column string) int {
+		raw := cell(column)
+		if raw == "" {
+			return 0
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			errs = append(errs, fmt.Sprintf("%s must be a whole number of at least 0", strings.ToLower(column)))
+			return 0
+		}
+		return value
+	}
+	pref := models.TeacherPreference{
+		MaxLoadPerDay:  parseLoad(preferenceColumnMaxPerDay),
+		MaxLoadPerWeek: parseLoad(preferenceColumnMaxPerWeek),
+	}
+
+	windows := []models.TeacherUnavailableSlot{}
+	for day := 1; day <= preferenceSheetDays; day++ {
+		column := displayDayName(day)
+		for _, token := range strings.Split(cell(column), ",") {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				continue
+			}
+			timeRange, err := normalizeSlotRange(token, slots)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s %q: %s", column, token, err))
+				continue
+			}
+			windows = append(windows, models.TeacherUnavailableSlot{DayOfWeek: dayIndexToName(day), TimeRange: timeRange})
+		}
+	}
+	raw, _ := json.Marshal(windows)
+	pref.Unavailable = raw
+	return pref, errs
+}
+
+// normalizeSlotRange validates a "3" or "1-3" window and returns it without spaces.
+func normalizeSlotRange(token string, slots map[int]string) (string, error) {
+	parts := strings.SplitN(token, "-", 2)
+	bounds := make([]int, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 1 {
+			return "", errors.New("use slot numbers or ranges such as 1-3")
+		}
+		bounds[i] = value
+	}
+	if len(bounds) == 2 && bounds[1] < bounds[0] {
+		return "", errors.New("range end is before its start")
+	}
+	if len(slots) > 0 {
+		var missing []string
+		for slot := bounds[0]; slot <= bounds[len(bounds)-1]; slot++ {
+			if _, ok := slots[slot]; !ok {
+				missing = append(missing, strconv.Itoa(slot))
+			}
+		}
+		switch len(missing) {
+		case 0:
+		case 1:
+			return "", fmt.Errorf("slot %s is not defined for the term", missing[0])
+		default:
+			return "", fmt.Errorf("slots %s are not defined for the term", strings.Join(missing, ", "))
+		}
+	}
+	if len(bounds) == 2 {
+		return fmt.Sprintf("%d-%d", bounds[0], bounds[1]), nil
+	}
+	return strconv.Itoa(bounds[0]), nil
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -14,11 +14,13 @@ import (
 
 type teacherRepository interface {
 	List(ctx context.Context, filter models.TeacherFilter) ([]models.Teacher, int, error)
+	ListWithTermLoad(ctx context.Context, filter models.TeacherFilter) ([]models.TeacherWithLoad, int, error)
 	FindByID(ctx context.Context, id string) (*models.Teacher, error)
 	ExistsByEmail(ctx context.Context, email, excludeID string) (bool, error)
 	ExistsByNIP(ctx context.Context, nip, excludeID string) (bool, error)
 	Create(ctx context.Context, teacher *models.Teacher) error
 	Update(ctx context.Context, teacher *models.Teacher) error
+	UpdateFields(ctx context.Context, teacher *models.Teacher, columns []string) error
 	Deactivate(ctx context.Context, id string) error
 }
 
@@ -41,6 +43,17 @@ type UpdateTeacherRequest struct {
 	Active    *bool   `json:"active"`
 }
 
+// PatchTeacherRequest updates only the fields present in the payload. An empty nip, phone or
+// expertise clears the value.
+type PatchTeacherRequest struct {
+	Email     *string `json:"email" validate:"omitnil,email"`
+	FullName  *string `json:"full_name" validate:"omitnil,required"`
+	NIP       *string `json:"nip" validate:"omitnil,max=50"`
+	Phone     *string `json:"phone" validate:"omitnil,max=50"`
+	Expertise *string `json:"expertise" validate:"omitnil,max=500"`
+	Active    *bool   `json:"active"`
+}
+
 // TeacherService orchestrates teacher operations.
 type TeacherService struct {
 	repo      teacherRepository
@@ -65,6 +78,22 @@ func (s *TeacherService) List(ctx context.Context, filter models.TeacherFilter)
 	if err != nil {
 		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list teachers")
 	}
+	return teachers, teacherPagination(filter, total), nil
+}
+
+// ListWithTermLoad returns teachers with assignment counts, homeroom flag and weekly load for filter.TermID.
+func (s *TeacherService) ListWithTermLoad(ctx context.Context, filter models.TeacherFilter) ([]models.TeacherWithLoad, *models.Pagination, error) {
+	if strings.TrimSpace(filter.TermID) == "" {
+		return nil, nil, appErrors.Clone(appErrors.ErrValidation, "termId is required")
+	}
+	teachers, total, err := s.repo.ListWithTermLoad(ctx, filter)
+	if err != nil {
+		return nil, nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to list teachers")
+	}
+	return teachers, teacherPagination(filter, total), nil
+}
+
+func teacherPagination(filter models.TeacherFilter, total int) *models.Pagination {
 	page := filter.Page
 	if page < 1 {
 		page = 1
@@ -73,8 +102,7 @@ func (s *TeacherService) List(ctx context.Context, filter models.TeacherFilter)
 	if size <= 0 {
 		size = 20
 	}
-	pagination := &models.Pagination{Page: page, PageSize: size, TotalCount: total}
-	return teachers, pagination, nil
+	return &models.Pagination{Page: page, PageSize: size, TotalCount: total}
 }
 
 // Get returns a teacher by id.
@@ -146,6 +174,64 @@ func (s *TeacherService) Update(ctx context.Context, id string, req UpdateTeache
 	return teacher, nil
 }
 
+// Patch applies the fields present in req and writes only those columns.
+func (s *TeacherService) Patch(ctx context.Context, id string, req PatchTeacherRequest) (*models.Teacher, error) {
+	if err := s.validator.Struct(req); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation.Code, appErrors.ErrValidation.Status, "invalid teacher payload")
+	}
+
+	teacher, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, appErrors.Clone(appErrors.ErrNotFound, "teacher not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to load teacher")
+	}
+
+	var columns []string
+	if req.Email != nil {
+		teacher.Email = strings.TrimSpace(*req.Email)
+		columns = append(columns, "email")
+	}
+	if req.FullName != nil {
+		teacher.FullName = strings.TrimSpace(*req.FullName)
+		columns = append(columns, "full_name")
+	}
+	if req.NIP != nil {
+		teacher.NIP = normalizeOptional(req.NIP)
+		columns = append(columns, "nip")
+	}
+	if req.Phone != nil {
+		teacher.Phone = normalizeOptional(req.Phone)
+		columns = append(columns, "phone")
+	}
+	if req.Expertise != nil {
+		teacher.Expertise = normalizeOptional(req.Expertise)
+		columns = append(columns, "expertise")
+	}
+	if req.Active != nil {
+		teacher.Active = *req.Active
+		columns = append(columns, "active")
+	}
+	if len(columns) == 0 {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "no fields to update")
+	}
+	if teacher.FullName == "" {
+		return nil, appErrors.Clone(appErrors.ErrValidation, "full_name must not be blank")
+	}
+
+	if req.Email != nil || req.NIP != nil {
+		if err := s.ensureUniqueFields(ctx, teacher.Email, teacher.NIP, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateFields(ctx, teacher, columns); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal.Code, appErrors.ErrInternal.Status, "failed to update teacher")
+	}
+	return teacher, nil
+}
+
 // Deactivate marks a teacher inactive.
 func (s *TeacherService) Deactivate(ctx context.Context, id string) error {
 	if _, err := s.repo.FindByID(ctx, id); err != nil {
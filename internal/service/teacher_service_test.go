@@ -21,7 +21,10 @@ type mockTeacherRepo struct {
 	listResult  []models.Teacher
 	listTotal   int
 	listErr     error
+	loadResult  []models.TeacherWithLoad
+	loadFilter  models.TeacherFilter
 	deactivated []string
+	patched     []string
 }
 
 func (m *mockTeacherRepo) List(ctx context.Context, filter models.TeacherFilter) ([]models.Teacher, int, error) {
@@ -31,6 +34,11 @@ func (m *mockTeacherRepo) List(ctx context.Context, filter models.TeacherFilter)
 	return m.listResult, m.listTotal, nil
 }
 
+func (m *mockTeacherRepo) ListWithTermLoad(ctx context.Context, filter models.TeacherFilter) ([]models.TeacherWithLoad, int, error) {
+	m.loadFilter = filter
+	return m.loadResult, len(m.loadResult), nil
+}
+
 func (m *mockTeacherRepo) FindByID(ctx context.Context, id string) (*models.Teacher, error) {
 	if teacher, ok := m.items[id]; ok {
 		cp := *teacher
@@ -81,6 +89,11 @@ func (m *mockTeacherRepo) Update(ctx context.Context, teacher *models.Teacher) e
 	return nil
 }
 
+func (m *mockTeacherRepo) UpdateFields(ctx context.Context, teacher *models.Teacher, columns []string) error {
+	m.patched = columns
+	return m.Update(ctx, teacher)
+}
+
 func (m *mockTeacherRepo) Deactivate(ctx context.Context, id string) error {
 	m.deactivated = append(m.deactivated, id)
 	if t, ok := m.items[id]; ok {
@@ -133,6 +146,34 @@ func TestTeacherServiceUpdate(t *testing.T) {
 	assert.Equal(t, "Teacher Updated", updated.FullName)
 }
 
+func TestTeacherServicePatch(t *testing.T) {
+	nip := "198001"
+	repo := &mockTeacherRepo{
+		items: map[string]*models.Teacher{
+			"t1": {ID: "t1", Email: "teach@example.com", FullName: "Teacher One", NIP: &nip, Active: true},
+		},
+	}
+	service := NewTeacherService(repo, validator.New(), zap.NewNop())
+
+	inactive := false
+	empty := ""
+	patched, err := service.Patch(context.Background(), "t1", PatchTeacherRequest{Active: &inactive, NIP: &empty})
+	require.NoError(t, err)
+	assert.False(t, patched.Active)
+	assert.Nil(t, patched.NIP)
+	assert.Equal(t, "teach@example.com", patched.Email)
+	assert.Equal(t, []string{"nip", "active"}, repo.patched)
+
+	_, err = service.Patch(context.Background(), "t1", PatchTeacherRequest{})
+	require.Error(t, err)
+	blank := "  "
+	_, err = service.Patch(context.Background(), "t1", PatchTeacherRequest{FullName: &blank})
+	require.Error(t, err)
+	invalid := "not-an-email"
+	_, err = service.Patch(context.Background(), "t1", PatchTeacherRequest{Email: &invalid})
+	require.Error(t, err)
+}
+
 func TestTeacherServiceDeactivate(t *testing.T) {
 	repo := &mockTeacherRepo{
 		items: map[string]*models.Teacher{
@@ -145,3 +186,17 @@ func TestTeacherServiceDeactivate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []string{"t1"}, repo.deactivated)
 }
+
+func TestTeacherServiceListWithTermLoad(t *testing.T) {
+	repo := &mockTeacherRepo{loadResult: []models.TeacherWithLoad{{Teacher: models.Teacher{ID: "t1"}, AssignmentCount: 2, WeeklyLoad: 12}}}
+	service := NewTeacherService(repo, validator.New(), zap.NewNop())
+
+	teachers, pagination, err := service.ListWithTermLoad(context.Background(), models.TeacherFilter{TermID: "term-1"})
+	require.NoError(t, err)
+	assert.Len(t, teachers, 1)
+	assert.Equal(t, "term-1", repo.loadFilter.TermID)
+	assert.Equal(t, &models.Pagination{Page: 1, PageSize: 20, TotalCount: 1}, pagination)
+
+	_, _, err = service.ListWithTermLoad(context.Background(), models.TeacherFilter{TermID: " "})
+	require.Error(t, err)
+}
@@ -30,7 +30,7 @@ type userRepository interface {
 type CreateUserRequest struct {
 	Email    string          `json:"email" validate:"required,email"`
 	FullName string          `json:"full_name" validate:"required"`
-	Role     models.UserRole `json:"role" validate:"required,oneof=SUPERADMIN ADMIN TEACHER STUDENT"`
+	Role     models.UserRole `json:"role" validate:"required,oneof=SUPERADMIN ADMIN TEACHER STUDENT GUARDIAN"`
 	Active   bool            `json:"active"`
 	Password string          `json:"password" validate:"required,min=6"`
 }
@@ -38,7 +38,7 @@ type CreateUserRequest struct {
 // UpdateUserRequest payload for updating users.
 type UpdateUserRequest struct {
 	FullName string          `json:"full_name" validate:"required"`
-	Role     models.UserRole `json:"role" validate:"required,oneof=SUPERADMIN ADMIN TEACHER STUDENT"`
+	Role     models.UserRole `json:"role" validate:"required,oneof=SUPERADMIN ADMIN TEACHER STUDENT GUARDIAN"`
 	Active   *bool           `json:"active"`
 }
 
@@ -128,8 +128,13 @@ func (s *UserService) Create(ctx context.Context, req CreateUserRequest, actorID
 	}
 
 	newPayload, _ := json.Marshal(map[string]interface{}{"id": user.ID, "email": user.Email, "role": user.Role})
+	// Accounts bootstrapped from the command line have no acting user.
+	var actor *string
+	if actorID != "" {
+		actor = &actorID
+	}
 	if err := s.repo.CreateAuditLog(ctx, &models.AuditLog{
-		UserID:     &actorID,
+		UserID:     actor,
 		Action:     models.AuditActionUserCreate,
 		Resource:   "users",
 		ResourceID: &user.ID,
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS slot_definitions (
+    id VARCHAR(36) PRIMARY KEY,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    slot_index SMALLINT NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    label VARCHAR(50),
+    is_break BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(term_id, slot_index)
+);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_archives_title_trgm;
+DROP INDEX IF EXISTS idx_archives_title_tsv;
+DROP INDEX IF EXISTS idx_subjects_code_trgm;
+DROP INDEX IF EXISTS idx_subjects_name_trgm;
+DROP INDEX IF EXISTS idx_classes_name_trgm;
+DROP INDEX IF EXISTS idx_students_nis_trgm;
+DROP INDEX IF EXISTS idx_students_full_name_trgm;
+DROP INDEX IF EXISTS idx_teachers_email_trgm;
+DROP INDEX IF EXISTS idx_teachers_full_name_trgm;
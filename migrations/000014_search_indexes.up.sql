@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_teachers_full_name_trgm ON teachers USING GIN (full_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_teachers_email_trgm ON teachers USING GIN (email gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_students_full_name_trgm ON students USING GIN (full_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_students_nis_trgm ON students USING GIN (nis gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_classes_name_trgm ON classes USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_subjects_name_trgm ON subjects USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_subjects_code_trgm ON subjects USING GIN (code gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_archives_title_tsv ON archives USING GIN (to_tsvector('simple', title)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_archives_title_trgm ON archives USING GIN (title gin_trgm_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS attendance_import_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    total_rows INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) DEFAULT 'QUEUED',
+    progress INT DEFAULT 0,
+    result JSONB DEFAULT '{}'::jsonb,
+    created_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP,
+    error_message TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_import_jobs_status ON attendance_import_jobs(status);
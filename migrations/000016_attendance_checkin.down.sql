@@ -0,0 +1,2 @@
+ALTER TABLE daily_attendance DROP COLUMN IF EXISTS checked_in_at;
+ALTER TABLE daily_attendance DROP COLUMN IF EXISTS source;
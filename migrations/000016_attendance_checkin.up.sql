@@ -0,0 +1,2 @@
+ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'MANUAL';
+ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS teacher_attendance (
+    id VARCHAR(36) PRIMARY KEY,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    check_in_at TIMESTAMPTZ NOT NULL,
+    check_out_at TIMESTAMPTZ,
+    late_minutes INT NOT NULL DEFAULT 0,
+    check_in_ip VARCHAR(45),
+    check_out_ip VARCHAR(45),
+    check_in_latitude DOUBLE PRECISION,
+    check_in_longitude DOUBLE PRECISION,
+    check_out_latitude DOUBLE PRECISION,
+    check_out_longitude DOUBLE PRECISION,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(teacher_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_teacher_attendance_date ON teacher_attendance(date);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS guardian_students (
+    guardian_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    student_id VARCHAR(255) NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    relationship VARCHAR(30),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (guardian_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_guardian_students_student ON guardian_students(student_id);
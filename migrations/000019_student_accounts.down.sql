@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_students_user_id;
+ALTER TABLE students DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS exam_schedules;
+DROP TABLE IF EXISTS exam_periods;
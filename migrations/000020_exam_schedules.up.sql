@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS exam_periods (
+    id VARCHAR(36) PRIMARY KEY,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_periods_term ON exam_periods(term_id);
+
+CREATE TABLE IF NOT EXISTS exam_schedules (
+    id VARCHAR(36) PRIMARY KEY,
+    period_id VARCHAR(36) NOT NULL REFERENCES exam_periods(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    subject_id VARCHAR(36) NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+    exam_date DATE NOT NULL,
+    start_slot INT NOT NULL,
+    end_slot INT NOT NULL,
+    room VARCHAR(50) NOT NULL,
+    invigilator_id VARCHAR(36) NOT NULL REFERENCES teachers(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_slot >= 1 AND end_slot >= start_slot),
+    UNIQUE(period_id, class_id, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exam_schedules_date ON exam_schedules(exam_date);
+CREATE INDEX IF NOT EXISTS idx_exam_schedules_invigilator ON exam_schedules(invigilator_id, exam_date);
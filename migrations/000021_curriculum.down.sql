@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS curriculum_progress;
+DROP TABLE IF EXISTS curriculum_topics;
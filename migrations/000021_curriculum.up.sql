@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS curriculum_topics (
+    id VARCHAR(36) PRIMARY KEY,
+    subject_id VARCHAR(36) NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    planned_week INT NOT NULL CHECK (planned_week >= 1),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_curriculum_topics_subject_term ON curriculum_topics(subject_id, term_id, planned_week);
+
+CREATE TABLE IF NOT EXISTS curriculum_progress (
+    id VARCHAR(36) PRIMARY KEY,
+    topic_id VARCHAR(36) NOT NULL REFERENCES curriculum_topics(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    taught_on DATE NOT NULL,
+    notes TEXT,
+    marked_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(topic_id, class_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_curriculum_progress_class ON curriculum_progress(class_id);
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS lesson_plans (
+    id VARCHAR(36) PRIMARY KEY,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    subject_id VARCHAR(36) NOT NULL REFERENCES subjects(id) ON DELETE CASCADE,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    week_start DATE NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    objectives TEXT,
+    activities TEXT,
+    assessment TEXT,
+    attachment_id VARCHAR(36) REFERENCES archives(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT',
+    submitted_at TIMESTAMP,
+    reviewed_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(teacher_id, class_id, subject_id, week_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lesson_plans_term_week ON lesson_plans(term_id, week_start);
+CREATE INDEX IF NOT EXISTS idx_lesson_plans_status ON lesson_plans(status);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    ref_id VARCHAR(36),
+    dedupe_key VARCHAR(200),
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
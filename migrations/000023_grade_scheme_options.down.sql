@@ -0,0 +1,3 @@
+ALTER TABLE grade_config_components DROP COLUMN IF EXISTS min_grades;
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS best_n;
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS drop_lowest;
@@ -0,0 +1,3 @@
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS drop_lowest INT NOT NULL DEFAULT 0;
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS best_n INT NOT NULL DEFAULT 0;
+ALTER TABLE grade_config_components ADD COLUMN IF NOT EXISTS min_grades INT NOT NULL DEFAULT 0 CHECK (min_grades >= 0);
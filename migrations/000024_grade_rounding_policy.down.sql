@@ -0,0 +1,3 @@
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS round_to_integer;
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS rounding_places;
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS rounding_mode;
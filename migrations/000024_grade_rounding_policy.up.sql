@@ -0,0 +1,3 @@
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS rounding_mode VARCHAR(20) NOT NULL DEFAULT 'HALF_EVEN';
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS rounding_places INT NOT NULL DEFAULT 2;
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS round_to_integer BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_grade_finals_below_kkm;
+
+ALTER TABLE grade_finals DROP COLUMN IF EXISTS remedial_at;
+ALTER TABLE grade_finals DROP COLUMN IF EXISTS remedial_grade;
+ALTER TABLE grade_finals DROP COLUMN IF EXISTS below_kkm;
+
+ALTER TABLE grade_configs DROP COLUMN IF EXISTS kkm;
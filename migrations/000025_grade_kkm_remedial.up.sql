@@ -0,0 +1,7 @@
+ALTER TABLE grade_configs ADD COLUMN IF NOT EXISTS kkm NUMERIC(5,2);
+
+ALTER TABLE grade_finals ADD COLUMN IF NOT EXISTS below_kkm BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE grade_finals ADD COLUMN IF NOT EXISTS remedial_grade NUMERIC(5,2);
+ALTER TABLE grade_finals ADD COLUMN IF NOT EXISTS remedial_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_grade_finals_below_kkm ON grade_finals(subject_id) WHERE below_kkm;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS attendance_alert_thresholds (
+    id VARCHAR(36) PRIMARY KEY,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    min_percentage NUMERIC(5,2) NOT NULL CHECK (min_percentage > 0 AND min_percentage <= 100),
+    updated_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(class_id, term_id)
+);
+
+CREATE TABLE IF NOT EXISTS attendance_alerts (
+    id VARCHAR(36) PRIMARY KEY,
+    enrollment_id VARCHAR(36) NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+    student_id VARCHAR(255) NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    percentage NUMERIC(5,2) NOT NULL,
+    threshold NUMERIC(5,2) NOT NULL,
+    present_days INT NOT NULL DEFAULT 0,
+    total_days INT NOT NULL DEFAULT 0,
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(enrollment_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_alerts_term_class ON attendance_alerts(term_id, class_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS semester_schedule_warnings;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS semester_schedule_warnings (
+    id VARCHAR(36) PRIMARY KEY,
+    semester_schedule_id VARCHAR(36) NOT NULL REFERENCES semester_schedules(id) ON DELETE CASCADE,
+    type VARCHAR(40) NOT NULL,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    subject_id VARCHAR(36) REFERENCES subjects(id) ON DELETE CASCADE,
+    day_of_week SMALLINT,
+    time_slot SMALLINT,
+    message TEXT NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sem_sched_warnings_schedule ON semester_schedule_warnings(semester_schedule_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_report_jobs_status_created;
+
+ALTER TABLE report_jobs
+    DROP COLUMN IF EXISTS heartbeat_at,
+    DROP COLUMN IF EXISTS claimed_by;
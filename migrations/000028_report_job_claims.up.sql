@@ -0,0 +1,5 @@
+ALTER TABLE report_jobs
+    ADD COLUMN IF NOT EXISTS claimed_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_report_jobs_status_created ON report_jobs(status, created_at);
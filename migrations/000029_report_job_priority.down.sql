@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_report_jobs_queue_order;
+CREATE INDEX IF NOT EXISTS idx_report_jobs_status_created ON report_jobs(status, created_at);
+
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS priority;
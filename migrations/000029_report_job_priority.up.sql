@@ -0,0 +1,4 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 1;
+
+DROP INDEX IF EXISTS idx_report_jobs_status_created;
+CREATE INDEX IF NOT EXISTS idx_report_jobs_queue_order ON report_jobs(status, priority DESC, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_report_jobs_fingerprint;
+
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS fingerprint;
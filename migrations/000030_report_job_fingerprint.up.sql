@@ -0,0 +1,3 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_report_jobs_fingerprint ON report_jobs(fingerprint, created_at DESC);
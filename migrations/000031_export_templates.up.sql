@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS export_templates (
+    id VARCHAR(36) PRIMARY KEY,
+    report_type VARCHAR(32) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    columns JSONB NOT NULL DEFAULT '[]'::jsonb,
+    group_by VARCHAR(64),
+    updated_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS legacy_sync_batches (
+    id VARCHAR(36) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    idempotency_key VARCHAR(128) NOT NULL,
+    source VARCHAR(100) NOT NULL DEFAULT '',
+    payload_hash VARCHAR(64) NOT NULL,
+    received INT NOT NULL DEFAULT 0,
+    applied INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    failures JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(kind, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_legacy_sync_batches_created_at ON legacy_sync_batches(created_at);
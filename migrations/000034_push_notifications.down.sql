@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_push_next_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS pushed_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS push_error;
+ALTER TABLE notifications DROP COLUMN IF EXISTS push_attempts;
+ALTER TABLE notifications DROP COLUMN IF EXISTS push_next_at;
+DROP TABLE IF EXISTS device_tokens;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(4096) NOT NULL UNIQUE,
+    platform VARCHAR(20) NOT NULL CHECK (platform IN ('ANDROID', 'IOS', 'WEB')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_tokens_user ON device_tokens(user_id);
+
+-- push_next_at is set while a notification waits to be pushed; existing notifications are not queued.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS push_next_at TIMESTAMP;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS push_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS push_error TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS pushed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_push_next_at ON notifications(push_next_at) WHERE push_next_at IS NOT NULL;
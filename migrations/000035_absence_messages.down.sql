@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS absence_messages;
+DROP TABLE IF EXISTS guardian_contacts;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS guardian_contacts (
+    guardian_id VARCHAR(255) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    channel VARCHAR(10) NOT NULL DEFAULT 'SMS' CHECK (channel IN ('SMS', 'WHATSAPP')),
+    absence_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per guardian, student and day is both the queue and the delivery log; the unique key keeps
+-- a student marked absent twice on the same day from messaging guardians twice.
+CREATE TABLE IF NOT EXISTS absence_messages (
+    id VARCHAR(36) PRIMARY KEY,
+    attendance_id VARCHAR(255) NOT NULL REFERENCES daily_attendance(id) ON DELETE CASCADE,
+    student_id VARCHAR(255) NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    guardian_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('SMS', 'WHATSAPP')),
+    phone VARCHAR(20) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'DELIVERED', 'FAILED', 'SKIPPED')),
+    provider_message_id VARCHAR(100),
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    send_after TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (student_id, guardian_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_absence_messages_due ON absence_messages(send_after) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_absence_messages_date ON absence_messages(date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_absence_messages_provider ON absence_messages(provider_message_id) WHERE provider_message_id IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS subject_load_presets (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    grade VARCHAR(10) NOT NULL,
+    track VARCHAR(50),
+    loads JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_subject_load_presets_grade_track ON subject_load_presets (grade, track);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_schedules_term_day_slot;
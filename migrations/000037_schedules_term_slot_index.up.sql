@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_schedules_term_day_slot ON schedules(term_id, day_of_week, time_slot);
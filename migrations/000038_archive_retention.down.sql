@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_archives_deleted_at;
+DROP INDEX IF EXISTS idx_archives_category_uploaded;
+ALTER TABLE archives DROP COLUMN IF EXISTS deletion_reason;
@@ -0,0 +1,5 @@
+-- deletion_reason records why an archive was soft-deleted: MANUAL or RETENTION.
+ALTER TABLE archives ADD COLUMN IF NOT EXISTS deletion_reason VARCHAR(20);
+UPDATE archives SET deletion_reason = 'MANUAL' WHERE deleted_at IS NOT NULL AND deletion_reason IS NULL;
+CREATE INDEX IF NOT EXISTS idx_archives_category_uploaded ON archives(LOWER(category), uploaded_at) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_archives_deleted_at ON archives(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_archives_category_retained;
+CREATE INDEX IF NOT EXISTS idx_archives_category_uploaded ON archives(LOWER(category), uploaded_at) WHERE deleted_at IS NULL;
+ALTER TABLE archives DROP COLUMN IF EXISTS restored_at;
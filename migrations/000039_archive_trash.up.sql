@@ -0,0 +1,4 @@
+-- Restoring an archive restarts its retention period from restored_at.
+ALTER TABLE archives ADD COLUMN IF NOT EXISTS restored_at TIMESTAMP;
+DROP INDEX IF EXISTS idx_archives_category_uploaded;
+CREATE INDEX IF NOT EXISTS idx_archives_category_retained ON archives(LOWER(category), (COALESCE(restored_at, uploaded_at))) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_archives_thumbnail_pending;
+ALTER TABLE archives DROP COLUMN IF EXISTS thumbnail_generated_at;
+ALTER TABLE archives DROP COLUMN IF EXISTS thumbnail_error;
+ALTER TABLE archives DROP COLUMN IF EXISTS thumbnail_path;
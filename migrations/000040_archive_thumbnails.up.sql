@@ -0,0 +1,6 @@
+-- First-page previews of PDF archives. thumbnail_generated_at is set once an attempt has finished,
+-- whether or not it produced an image, so broken files are not retried forever.
+ALTER TABLE archives ADD COLUMN IF NOT EXISTS thumbnail_path TEXT;
+ALTER TABLE archives ADD COLUMN IF NOT EXISTS thumbnail_error TEXT;
+ALTER TABLE archives ADD COLUMN IF NOT EXISTS thumbnail_generated_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_archives_thumbnail_pending ON archives(uploaded_at) WHERE thumbnail_generated_at IS NULL AND deleted_at IS NULL;
@@ -0,0 +1,8 @@
+-- Supporting evidence for mutation requests, stored as MUTATION-scope archives.
+CREATE TABLE IF NOT EXISTS mutation_attachments (
+    mutation_id VARCHAR(36) NOT NULL REFERENCES mutations(id) ON DELETE CASCADE,
+    archive_id VARCHAR(36) NOT NULL REFERENCES archives(id) ON DELETE CASCADE,
+    attached_by VARCHAR(36) NOT NULL,
+    attached_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (mutation_id, archive_id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_mutations_pending_requested;
+ALTER TABLE mutations DROP COLUMN IF EXISTS escalated_at;
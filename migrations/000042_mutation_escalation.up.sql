@@ -0,0 +1,3 @@
+-- Pending mutations older than MUTATION_EXPIRE_AFTER are escalated to super admins once.
+ALTER TABLE mutations ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_mutations_pending_requested ON mutations(requested_at) WHERE status = 'PENDING';
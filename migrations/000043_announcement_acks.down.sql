@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notifications_dedupe_key;
+DROP TABLE IF EXISTS announcement_acks;
+ALTER TABLE announcements DROP COLUMN IF EXISTS requires_ack;
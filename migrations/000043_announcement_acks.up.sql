@@ -0,0 +1,12 @@
+-- Read receipts for announcements that ask recipients to acknowledge them. The recipients of a
+-- broadcast are the users holding its notification (dedupe key "announcement:<id>").
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS requires_ack BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS announcement_acks (
+    announcement_id VARCHAR(36) NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    acknowledged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (announcement_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_dedupe_key ON notifications(dedupe_key);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS calendar_event_rsvps;
+ALTER TABLE calendar_events DROP COLUMN IF EXISTS rsvp_capacity;
+ALTER TABLE calendar_events DROP COLUMN IF EXISTS rsvp_enabled;
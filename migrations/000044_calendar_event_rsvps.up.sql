@@ -0,0 +1,15 @@
+-- RSVPs and door check-ins for calendar events such as parent meetings. Walk-ins checked in without
+-- an RSVP are stored with response WALK_IN; a NULL capacity means unlimited seats.
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS rsvp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE calendar_events ADD COLUMN IF NOT EXISTS rsvp_capacity INT CHECK (rsvp_capacity > 0);
+
+CREATE TABLE IF NOT EXISTS calendar_event_rsvps (
+    event_id VARCHAR(36) NOT NULL REFERENCES calendar_events(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    response VARCHAR(16) NOT NULL CHECK (response IN ('GOING', 'NOT_GOING', 'WALK_IN')),
+    note TEXT,
+    responded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checked_in_at TIMESTAMP,
+    checked_in_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    PRIMARY KEY (event_id, user_id)
+);
@@ -0,0 +1,22 @@
+-- Teacher leave requests. days holds the school days the range covers when it was requested, so
+-- balances do not change when the school-day rules do.
+CREATE TABLE IF NOT EXISTS teacher_leaves (
+    id VARCHAR(36) PRIMARY KEY,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('SICK', 'PERSONAL', 'TRAINING')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    days INT NOT NULL CHECK (days > 0),
+    reason TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED', 'CANCELLED')),
+    requested_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    review_note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_teacher_leaves_teacher_dates ON teacher_leaves(teacher_id, start_date, end_date);
+CREATE INDEX IF NOT EXISTS idx_teacher_leaves_status_dates ON teacher_leaves(status, start_date, end_date);
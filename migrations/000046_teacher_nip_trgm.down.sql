@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_teachers_nip_trgm;
@@ -0,0 +1,3 @@
+-- Teacher list searches match the NIP by substring next to the name and email, which 000014 already
+-- indexes; without this index the OR falls back to a sequential scan.
+CREATE INDEX IF NOT EXISTS idx_teachers_nip_trgm ON teachers USING GIN (nip gin_trgm_ops);
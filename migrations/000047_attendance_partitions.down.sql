@@ -0,0 +1,35 @@
+-- Only attached partitions are folded back; reattach archived months from attendance_archive first
+-- to keep their rows. The schema is left in place so archived months are never dropped here.
+ALTER TABLE daily_attendance RENAME TO daily_attendance_partitioned;
+CREATE TABLE daily_attendance (LIKE daily_attendance_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO daily_attendance SELECT * FROM daily_attendance_partitioned;
+DROP TABLE daily_attendance_partitioned;
+
+ALTER TABLE subject_attendance RENAME TO subject_attendance_partitioned;
+CREATE TABLE subject_attendance (LIKE subject_attendance_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+INSERT INTO subject_attendance SELECT * FROM subject_attendance_partitioned;
+DROP TABLE subject_attendance_partitioned;
+
+ALTER TABLE daily_attendance ADD PRIMARY KEY (id);
+ALTER TABLE daily_attendance ADD CONSTRAINT daily_attendance_enrollment_id_date_key UNIQUE (enrollment_id, date);
+ALTER TABLE daily_attendance ADD CONSTRAINT daily_attendance_enrollment_id_fkey
+    FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_daily_attendance_date ON daily_attendance(date);
+CREATE INDEX IF NOT EXISTS idx_daily_attendance_status ON daily_attendance(status);
+CREATE INDEX IF NOT EXISTS idx_daily_attendance_class_date ON daily_attendance(enrollment_id, date);
+
+ALTER TABLE subject_attendance ADD PRIMARY KEY (id);
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_enrollment_id_schedule_id_date_key
+    UNIQUE (enrollment_id, schedule_id, date);
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_enrollment_id_fkey
+    FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE;
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_schedule_id_fkey
+    FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_subject_attendance_schedule_date ON subject_attendance(schedule_id, date);
+CREATE INDEX IF NOT EXISTS idx_subject_attendance_date ON subject_attendance(date);
+
+-- Messages whose attendance row was archived no longer have a target, hence NOT VALID.
+ALTER TABLE absence_messages ADD CONSTRAINT absence_messages_attendance_id_fkey
+    FOREIGN KEY (attendance_id) REFERENCES daily_attendance(id) ON DELETE CASCADE NOT VALID;
+
+DROP FUNCTION IF EXISTS ensure_attendance_partition(TEXT, DATE);
@@ -0,0 +1,85 @@
+-- Daily and per-subject attendance are range-partitioned by month on date. Months older than
+-- ATTENDANCE_PARTITION_RETAIN_MONTHS are detached into the attendance_archive schema by the
+-- partition maintenance job, which also creates the coming months ahead of time. Rows outside every
+-- monthly partition land in the default partition so writes never fail.
+CREATE SCHEMA IF NOT EXISTS attendance_archive;
+
+-- ensure_attendance_partition creates the partition of parent holding for_month unless it already
+-- exists and returns its name, e.g. daily_attendance_2026_03.
+CREATE OR REPLACE FUNCTION ensure_attendance_partition(parent TEXT, for_month DATE) RETURNS TEXT AS $$
+DECLARE
+    lower_bound DATE := date_trunc('month', for_month)::DATE;
+    partition_name TEXT := parent || '_' || to_char(for_month, 'YYYY_MM');
+BEGIN
+    IF to_regclass(partition_name) IS NULL THEN
+        EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+            partition_name, parent, lower_bound, (lower_bound + INTERVAL '1 month')::DATE);
+    END IF;
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Unique constraints on a partitioned table must include the partition key, so attendance ids are
+-- no longer unique on their own and absence messages keep attendance_id without a foreign key.
+ALTER TABLE absence_messages DROP CONSTRAINT IF EXISTS absence_messages_attendance_id_fkey;
+
+ALTER TABLE daily_attendance RENAME TO daily_attendance_unpartitioned;
+CREATE TABLE daily_attendance (LIKE daily_attendance_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY RANGE (date);
+CREATE TABLE daily_attendance_default PARTITION OF daily_attendance DEFAULT;
+
+ALTER TABLE subject_attendance RENAME TO subject_attendance_unpartitioned;
+CREATE TABLE subject_attendance (LIKE subject_attendance_unpartitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS)
+    PARTITION BY RANGE (date);
+CREATE TABLE subject_attendance_default PARTITION OF subject_attendance DEFAULT;
+
+-- Every month holding existing rows gets its partition, as do the next three months.
+DO $$
+DECLARE
+    m DATE;
+BEGIN
+    FOR m IN
+        SELECT generate_series(date_trunc('month', lo), date_trunc('month', hi) + INTERVAL '3 months', INTERVAL '1 month')::DATE
+        FROM (
+            SELECT LEAST(COALESCE(MIN(date), CURRENT_DATE), CURRENT_DATE) AS lo,
+                   GREATEST(COALESCE(MAX(date), CURRENT_DATE), CURRENT_DATE) AS hi
+            FROM daily_attendance_unpartitioned
+        ) bounds
+    LOOP
+        PERFORM ensure_attendance_partition('daily_attendance', m);
+    END LOOP;
+
+    FOR m IN
+        SELECT generate_series(date_trunc('month', lo), date_trunc('month', hi) + INTERVAL '3 months', INTERVAL '1 month')::DATE
+        FROM (
+            SELECT LEAST(COALESCE(MIN(date), CURRENT_DATE), CURRENT_DATE) AS lo,
+                   GREATEST(COALESCE(MAX(date), CURRENT_DATE), CURRENT_DATE) AS hi
+            FROM subject_attendance_unpartitioned
+        ) bounds
+    LOOP
+        PERFORM ensure_attendance_partition('subject_attendance', m);
+    END LOOP;
+END $$;
+
+INSERT INTO daily_attendance SELECT * FROM daily_attendance_unpartitioned;
+DROP TABLE daily_attendance_unpartitioned;
+
+INSERT INTO subject_attendance SELECT * FROM subject_attendance_unpartitioned;
+DROP TABLE subject_attendance_unpartitioned;
+
+ALTER TABLE daily_attendance ADD PRIMARY KEY (id, date);
+ALTER TABLE daily_attendance ADD CONSTRAINT daily_attendance_enrollment_id_date_key UNIQUE (enrollment_id, date);
+ALTER TABLE daily_attendance ADD CONSTRAINT daily_attendance_enrollment_id_fkey
+    FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_daily_attendance_date ON daily_attendance(date);
+CREATE INDEX IF NOT EXISTS idx_daily_attendance_status ON daily_attendance(status);
+
+ALTER TABLE subject_attendance ADD PRIMARY KEY (id, date);
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_enrollment_id_schedule_id_date_key
+    UNIQUE (enrollment_id, schedule_id, date);
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_enrollment_id_fkey
+    FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE;
+ALTER TABLE subject_attendance ADD CONSTRAINT subject_attendance_schedule_id_fkey
+    FOREIGN KEY (schedule_id) REFERENCES schedules(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_subject_attendance_schedule_date ON subject_attendance(schedule_id, date);
+CREATE INDEX IF NOT EXISTS idx_subject_attendance_date ON subject_attendance(date);
@@ -0,0 +1,17 @@
+-- Admin-triggered backups (POST /internal/backups). file_path is relative to BACKUPS_STORAGE_DIR and
+-- checksum is the SHA-256 of the encrypted archive, so copies taken off-site can be checked.
+CREATE TABLE IF NOT EXISTS backups (
+    id VARCHAR(36) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'QUEUED' CHECK (status IN ('QUEUED', 'PROCESSING', 'FINISHED', 'FAILED')),
+    file_path TEXT,
+    size_bytes BIGINT,
+    checksum VARCHAR(64),
+    storage_files INT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    requested_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_backups_created_at ON backups(created_at DESC);
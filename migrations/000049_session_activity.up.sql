@@ -0,0 +1,18 @@
+-- A session is the chain of refresh tokens issued by one login. Rotation copies session_id and
+-- session_started_at to the new token; last_active_at moves on every refresh and heartbeat and is
+-- what the idle timeout and the per-role session limit look at.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id VARCHAR(255);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS last_active_at TIMESTAMP;
+
+UPDATE refresh_tokens
+SET session_id = COALESCE(session_id, id),
+    session_started_at = COALESCE(session_started_at, created_at, CURRENT_TIMESTAMP),
+    last_active_at = COALESCE(last_active_at, created_at, CURRENT_TIMESTAMP);
+
+ALTER TABLE refresh_tokens ALTER COLUMN session_id SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN last_active_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_live_sessions ON refresh_tokens(user_id, last_active_at DESC) WHERE revoked = FALSE;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
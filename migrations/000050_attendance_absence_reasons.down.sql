@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS attendance_evidence;
+ALTER TABLE daily_attendance DROP COLUMN IF EXISTS absence_reason;
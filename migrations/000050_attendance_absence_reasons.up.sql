@@ -0,0 +1,17 @@
+-- Structured reasons for excused absences, in the ministry's categories: SICK refines status S,
+-- PERMISSION and SCHOOL_DUTY refine status I. Rows written before reasons existed keep NULL and
+-- count as SICK or PERMISSION by their status.
+ALTER TABLE daily_attendance ADD COLUMN IF NOT EXISTS absence_reason VARCHAR(16)
+    CHECK (absence_reason IN ('SICK', 'PERMISSION', 'SCHOOL_DUTY'));
+
+-- Evidence for an excused absence (doctor's notes, permission letters, duty assignments), stored
+-- as STUDENT-scope archives. daily_attendance is partitioned by date, so rows are referenced by
+-- their natural key rather than by a foreign key.
+CREATE TABLE IF NOT EXISTS attendance_evidence (
+    enrollment_id VARCHAR(36) NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    archive_id VARCHAR(36) NOT NULL REFERENCES archives(id) ON DELETE CASCADE,
+    linked_by VARCHAR(36) NOT NULL,
+    linked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (enrollment_id, date, archive_id)
+);
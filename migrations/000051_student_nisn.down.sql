@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_students_nisn;
+ALTER TABLE students DROP COLUMN IF EXISTS nisn;
@@ -0,0 +1,5 @@
+-- NISN is the national student number the ministry identifies students by in its reports. It is
+-- optional here so existing students can be completed over time; ministry exports refuse to run
+-- while any student in scope lacks one.
+ALTER TABLE students ADD COLUMN IF NOT EXISTS nisn VARCHAR(10);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_students_nisn ON students(nisn) WHERE nisn IS NOT NULL;
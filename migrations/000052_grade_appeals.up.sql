@@ -0,0 +1,32 @@
+-- Appeals against finalized grades, filed by a student or a linked guardian. The subject teacher
+-- gives a recommendation until teacher_due_at; the appeal is then forwarded as a mutation that a
+-- super admin decides. Once forwarded, the outcome is the mutation's status.
+CREATE TABLE IF NOT EXISTS grade_appeals (
+    id VARCHAR(36) PRIMARY KEY,
+    grade_final_id VARCHAR(36) NOT NULL REFERENCES grade_finals(id) ON DELETE CASCADE,
+    student_id VARCHAR(36) NOT NULL REFERENCES students(id) ON DELETE CASCADE,
+    teacher_id VARCHAR(36) REFERENCES teachers(id) ON DELETE SET NULL,
+    submitted_by VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    original_grade NUMERIC(5,2) NOT NULL,
+    requested_grade NUMERIC(5,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'TEACHER_REVIEW' CHECK (status IN ('TEACHER_REVIEW', 'FORWARDED')),
+    teacher_due_at TIMESTAMP NOT NULL,
+    recommendation VARCHAR(10) CHECK (recommendation IN ('SUPPORT', 'OPPOSE', 'NONE')),
+    proposed_grade NUMERIC(5,2),
+    teacher_note TEXT,
+    forwarded_at TIMESTAMP,
+    admin_due_at TIMESTAMP,
+    mutation_id VARCHAR(36) REFERENCES mutations(id) ON DELETE SET NULL,
+    overdue_notified_at TIMESTAMP,
+    outcome_notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One appeal per grade may wait for its teacher; the service also refuses appeals while one is
+-- with the super admins.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_grade_appeals_teacher_review ON grade_appeals(grade_final_id) WHERE status = 'TEACHER_REVIEW';
+CREATE INDEX IF NOT EXISTS idx_grade_appeals_student ON grade_appeals(student_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_grade_appeals_teacher ON grade_appeals(teacher_id, status);
+CREATE INDEX IF NOT EXISTS idx_grade_appeals_mutation ON grade_appeals(mutation_id);
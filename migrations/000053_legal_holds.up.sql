@@ -0,0 +1,15 @@
+-- Legal holds keep refresh tokens or audit logs from being purged by the retention job. A hold
+-- without a user covers the whole data class. user_id has no foreign key so a hold outlives the
+-- account it protects; released holds are kept as a record.
+CREATE TABLE IF NOT EXISTS legal_holds (
+    id VARCHAR(36) PRIMARY KEY,
+    data_class VARCHAR(20) NOT NULL CHECK (data_class IN ('REFRESH_TOKENS', 'AUDIT_LOGS')),
+    user_id VARCHAR(255),
+    reason TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_by VARCHAR(255),
+    released_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_legal_holds_active ON legal_holds(data_class, user_id) WHERE released_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alert_rule_breaches;
+DROP TABLE IF EXISTS alert_rules;
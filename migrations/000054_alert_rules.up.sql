@@ -0,0 +1,36 @@
+-- Alert rules compare a per class metric with a threshold; the evaluation job stores the classes
+-- breaching each rule in alert_rule_breaches. The seeded rules match the thresholds dashboards used
+-- before rules existed.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(120) NOT NULL,
+    metric VARCHAR(40) NOT NULL CHECK (metric IN ('CLASS_ATTENDANCE_RATE', 'CLASS_AVERAGE_GRADE')),
+    comparator VARCHAR(3) NOT NULL CHECK (comparator IN ('LT', 'LTE', 'GT', 'GTE')),
+    threshold NUMERIC(5,2) NOT NULL CHECK (threshold >= 0 AND threshold <= 100),
+    audience VARCHAR(20) NOT NULL CHECK (audience IN ('HOMEROOM', 'TEACHERS', 'ADMINS')),
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('DASHBOARD', 'NOTIFICATION')),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS alert_rule_breaches (
+    id VARCHAR(36) PRIMARY KEY,
+    rule_id VARCHAR(36) NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    value NUMERIC(5,2) NOT NULL,
+    threshold NUMERIC(5,2) NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(rule_id, term_id, class_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rule_breaches_term_class ON alert_rule_breaches(term_id, class_id);
+
+INSERT INTO alert_rules (id, name, metric, comparator, threshold, audience, channel)
+VALUES
+    ('default-class-attendance', 'Low class attendance', 'CLASS_ATTENDANCE_RATE', 'LT', 90, 'TEACHERS', 'DASHBOARD'),
+    ('default-class-grade', 'Low class average grade', 'CLASS_AVERAGE_GRADE', 'LT', 70, 'TEACHERS', 'DASHBOARD')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,15 @@
+DROP MATERIALIZED VIEW IF EXISTS attendance_summary_mv;
+
+CREATE MATERIALIZED VIEW attendance_summary_mv AS
+SELECT
+    e.term_id,
+    e.class_id,
+    SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END) AS present_count,
+    SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END) AS absent_count,
+    CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END)::DECIMAL / COUNT(*)) * 100 END AS percentage,
+    MAX(da.updated_at) AS updated_at
+FROM daily_attendance da
+JOIN enrollments e ON e.id = da.enrollment_id
+GROUP BY e.term_id, e.class_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS attendance_summary_mv_idx ON attendance_summary_mv(term_id, class_id);
@@ -0,0 +1,20 @@
+-- attendance_summary_mv gains sick and excused counts so percentages can be recomputed with the
+-- configured attendance_present_statuses / attendance_excused_statuses formula. percentage keeps the
+-- default formula (H over all recorded days) for readers that do not apply the setting.
+DROP MATERIALIZED VIEW IF EXISTS attendance_summary_mv;
+
+CREATE MATERIALIZED VIEW attendance_summary_mv AS
+SELECT
+    e.term_id,
+    e.class_id,
+    SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END) AS present_count,
+    SUM(CASE WHEN da.status = 'S' THEN 1 ELSE 0 END) AS sick_count,
+    SUM(CASE WHEN da.status = 'I' THEN 1 ELSE 0 END) AS excused_count,
+    SUM(CASE WHEN da.status = 'A' THEN 1 ELSE 0 END) AS absent_count,
+    CASE WHEN COUNT(*) = 0 THEN 0 ELSE (SUM(CASE WHEN da.status = 'H' THEN 1 ELSE 0 END)::DECIMAL / COUNT(*)) * 100 END AS percentage,
+    MAX(da.updated_at) AS updated_at
+FROM daily_attendance da
+JOIN enrollments e ON e.id = da.enrollment_id
+GROUP BY e.term_id, e.class_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS attendance_summary_mv_idx ON attendance_summary_mv(term_id, class_id);
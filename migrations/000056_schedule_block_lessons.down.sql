@@ -0,0 +1,5 @@
+ALTER TABLE semester_schedule_slots
+    DROP CONSTRAINT IF EXISTS chk_sem_sched_slots_block_size;
+
+ALTER TABLE semester_schedule_slots
+    DROP COLUMN IF EXISTS block_size;
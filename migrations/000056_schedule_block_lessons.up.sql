@@ -0,0 +1,5 @@
+ALTER TABLE semester_schedule_slots
+    ADD COLUMN IF NOT EXISTS block_size SMALLINT NOT NULL DEFAULT 1;
+
+ALTER TABLE semester_schedule_slots
+    ADD CONSTRAINT chk_sem_sched_slots_block_size CHECK (block_size BETWEEN 1 AND 4);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_schedules_lesson_group;
+
+ALTER TABLE schedules
+    DROP COLUMN IF EXISTS lesson_group_id;
+
+DROP TABLE IF EXISTS lesson_group_students;
+DROP TABLE IF EXISTS lesson_group_classes;
+DROP TABLE IF EXISTS lesson_groups;
@@ -0,0 +1,33 @@
+-- A lesson group merges classes, or subsets of their students, into one lesson. Each session is a
+-- daily schedule per member class carrying the group's id.
+CREATE TABLE IF NOT EXISTS lesson_groups (
+    id VARCHAR(36) PRIMARY KEY,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    subject_id VARCHAR(36) NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+    name VARCHAR(120) NOT NULL,
+    room VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(term_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS lesson_group_classes (
+    group_id VARCHAR(36) NOT NULL REFERENCES lesson_groups(id) ON DELETE CASCADE,
+    class_id VARCHAR(36) NOT NULL REFERENCES classes(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, class_id)
+);
+
+-- Classes without rows here take part with all their students.
+CREATE TABLE IF NOT EXISTS lesson_group_students (
+    group_id VARCHAR(36) NOT NULL,
+    class_id VARCHAR(36) NOT NULL,
+    enrollment_id VARCHAR(36) NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, enrollment_id),
+    FOREIGN KEY (group_id, class_id) REFERENCES lesson_group_classes(group_id, class_id) ON DELETE CASCADE
+);
+
+ALTER TABLE schedules
+    ADD COLUMN IF NOT EXISTS lesson_group_id VARCHAR(36) REFERENCES lesson_groups(id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_schedules_lesson_group ON schedules(lesson_group_id) WHERE lesson_group_id IS NOT NULL;
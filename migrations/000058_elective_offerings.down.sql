@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS elective_selections;
+DROP TABLE IF EXISTS elective_sections;
+DROP INDEX IF EXISTS idx_elective_offerings_term_category;
+DROP TABLE IF EXISTS elective_offerings;
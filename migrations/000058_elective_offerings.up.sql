@@ -0,0 +1,36 @@
+-- Elective offerings let students rank the subjects of a category (e.g. LANGUAGE). Allocation fills
+-- each section's lesson group with the students placed in it.
+CREATE TABLE IF NOT EXISTS elective_offerings (
+    id VARCHAR(36) PRIMARY KEY,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    subject_id VARCHAR(36) NOT NULL REFERENCES subjects(id) ON DELETE RESTRICT,
+    category VARCHAR(40) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(term_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_elective_offerings_term_category ON elective_offerings(term_id, category);
+
+CREATE TABLE IF NOT EXISTS elective_sections (
+    id VARCHAR(36) PRIMARY KEY,
+    offering_id VARCHAR(36) NOT NULL REFERENCES elective_offerings(id) ON DELETE CASCADE,
+    name VARCHAR(60) NOT NULL,
+    teacher_id VARCHAR(36) NOT NULL REFERENCES teachers(id) ON DELETE RESTRICT,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    room VARCHAR(64),
+    lesson_group_id VARCHAR(36) REFERENCES lesson_groups(id) ON DELETE SET NULL,
+    UNIQUE(offering_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS elective_selections (
+    enrollment_id VARCHAR(36) NOT NULL REFERENCES enrollments(id) ON DELETE CASCADE,
+    offering_id VARCHAR(36) NOT NULL REFERENCES elective_offerings(id) ON DELETE CASCADE,
+    rank SMALLINT NOT NULL CHECK (rank >= 1),
+    submitted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (enrollment_id, offering_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_elective_selections_offering ON elective_selections(offering_id);
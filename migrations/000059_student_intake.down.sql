@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS intake_documents;
+DROP INDEX IF EXISTS idx_intake_applications_term_status;
+DROP TABLE IF EXISTS intake_applications;
@@ -0,0 +1,37 @@
+-- Transfer-in applications. Accepting one creates the student and their enrollment in the placed
+-- class, recorded here.
+CREATE TABLE IF NOT EXISTS intake_applications (
+    id VARCHAR(36) PRIMARY KEY,
+    term_id VARCHAR(36) NOT NULL REFERENCES terms(id) ON DELETE CASCADE,
+    full_name VARCHAR(255) NOT NULL,
+    nisn VARCHAR(10),
+    gender VARCHAR(10) NOT NULL,
+    birth_date DATE NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    phone VARCHAR(32) NOT NULL DEFAULT '',
+    previous_school VARCHAR(255) NOT NULL,
+    requested_class_id VARCHAR(36) REFERENCES classes(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'WAITLISTED', 'ACCEPTED', 'REJECTED')),
+    waitlisted_at TIMESTAMP,
+    class_id VARCHAR(36) REFERENCES classes(id) ON DELETE SET NULL,
+    student_id VARCHAR(36) REFERENCES students(id) ON DELETE SET NULL,
+    enrollment_id VARCHAR(36) REFERENCES enrollments(id) ON DELETE SET NULL,
+    decision_note TEXT,
+    decided_by VARCHAR(36),
+    decided_at TIMESTAMP,
+    created_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_intake_applications_term_status ON intake_applications(term_id, status);
+
+-- Checklist documents, stored as INTAKE-scope archives. Uploading a type again replaces it.
+CREATE TABLE IF NOT EXISTS intake_documents (
+    application_id VARCHAR(36) NOT NULL REFERENCES intake_applications(id) ON DELETE CASCADE,
+    document_type VARCHAR(40) NOT NULL,
+    archive_id VARCHAR(36) NOT NULL REFERENCES archives(id) ON DELETE CASCADE,
+    uploaded_by VARCHAR(36) NOT NULL,
+    uploaded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (application_id, document_type)
+);
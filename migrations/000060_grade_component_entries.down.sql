@@ -0,0 +1,14 @@
+-- Going back to one grade per component would have to discard teacher-entered grades, so refuse
+-- while any component holds more than one entry.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM grades WHERE entry > 1) THEN
+        RAISE EXCEPTION 'grades has components with more than one entry; merge or remove them before reverting';
+    END IF;
+END;
+$$;
+
+DROP INDEX IF EXISTS idx_grades_enrollment_subject_component_entry;
+ALTER TABLE grades DROP COLUMN IF EXISTS entry;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grades_enrollment_subject_component
+    ON grades (enrollment_id, subject_id, component_id);
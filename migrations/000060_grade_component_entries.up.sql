@@ -0,0 +1,31 @@
+-- A component can hold several numbered entries per student (quiz 1, quiz 2, ...), so minimum grade
+-- counts and the DROP_LOWEST and BEST_N schemes apply within each component.
+ALTER TABLE grades ADD COLUMN IF NOT EXISTS entry INT NOT NULL DEFAULT 1 CHECK (entry >= 1);
+
+-- The grades table predates these migrations, so its one-grade-per-component key is found by its
+-- columns rather than its name.
+DO $$
+DECLARE
+    key_name TEXT;
+BEGIN
+    FOR key_name IN
+        SELECT c.conname FROM pg_constraint c
+        WHERE c.conrelid = 'grades'::regclass AND c.contype = 'u'
+          AND (SELECT array_agg(a.attname::TEXT ORDER BY a.attname) FROM pg_attribute a
+               WHERE a.attrelid = c.conrelid AND a.attnum = ANY (c.conkey)) = ARRAY['component_id', 'enrollment_id', 'subject_id']
+    LOOP
+        EXECUTE format('ALTER TABLE grades DROP CONSTRAINT %I', key_name);
+    END LOOP;
+    FOR key_name IN
+        SELECT i.indexrelid::regclass::TEXT FROM pg_index i
+        WHERE i.indrelid = 'grades'::regclass AND i.indisunique AND NOT i.indisprimary
+          AND (SELECT array_agg(a.attname::TEXT ORDER BY a.attname) FROM pg_attribute a
+               WHERE a.attrelid = i.indrelid AND a.attnum = ANY (i.indkey)) = ARRAY['component_id', 'enrollment_id', 'subject_id']
+    LOOP
+        EXECUTE format('DROP INDEX %s', key_name);
+    END LOOP;
+END;
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grades_enrollment_subject_component_entry
+    ON grades (enrollment_id, subject_id, component_id, entry);
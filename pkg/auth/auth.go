@@ -0,0 +1,87 @@
+// Package auth validates access tokens and checks roles consistently across transports. HTTP
+// handlers use the gin middleware in this package; other servers (e.g. gRPC) call Authenticate with
+// the raw authorization value and Authorize with the resolved subject.
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+// Self is the pseudo-role granting access when the caller is the subject of the request.
+const Self = "SELF"
+
+// TokenVerifier validates an access token and returns its claims. AuthService implements it.
+type TokenVerifier interface {
+	ValidateToken(token string) (*models.JWTClaims, error)
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" value.
+func BearerToken(authorization string) (string, error) {
+	if authorization == "" {
+		return "", appErrors.ErrUnauthorized
+	}
+	parts := strings.SplitN(authorization, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || strings.TrimSpace(parts[1]) == "" {
+		return "", appErrors.Clone(appErrors.ErrUnauthorized, "invalid authorization header")
+	}
+	return strings.TrimSpace(parts[1]), nil
+}
+
+// Authenticate verifies the bearer token carried by an authorization value.
+func Authenticate(verifier TokenVerifier, authorization string) (*models.JWTClaims, error) {
+	token, err := BearerToken(authorization)
+	if err != nil {
+		return nil, err
+	}
+	return verifier.ValidateToken(token)
+}
+
+// Authorize reports whether claims hold one of the allowed roles. The Self pseudo-role also admits
+// the caller when subjectID, the user the request is about, is the caller's own ID.
+func Authorize(claims *models.JWTClaims, subjectID string, allowed ...string) bool {
+	if claims == nil {
+		return false
+	}
+	for _, role := range allowed {
+		if role == Self {
+			if subjectID != "" && subjectID == claims.UserID {
+				return true
+			}
+			continue
+		}
+		if models.UserRole(role) == claims.Role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether claims hold any of roles.
+func HasRole(claims *models.JWTClaims, roles ...models.UserRole) bool {
+	if claims == nil {
+		return false
+	}
+	for _, role := range roles {
+		if claims.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *models.JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFrom returns the claims stored in ctx by WithClaims, or nil.
+func ClaimsFrom(ctx context.Context) *models.JWTClaims {
+	claims, _ := ctx.Value(claimsKey{}).(*models.JWTClaims)
+	return claims
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+	appErrors "github.com/noah-isme/sma-adp-api/pkg/errors"
+)
+
+type verifierStub map[string]*models.JWTClaims
+
+func (v verifierStub) ValidateToken(token string) (*models.JWTClaims, error) {
+	if claims, ok := v[token]; ok {
+		return claims, nil
+	}
+	return nil, appErrors.Clone(appErrors.ErrUnauthorized, "invalid token")
+}
+
+func TestBearerToken(t *testing.T) {
+	token, err := BearerToken("bearer abc.def")
+	require.NoError(t, err)
+	assert.Equal(t, "abc.def", token)
+
+	for _, header := range []string{"", "abc.def", "Basic abc", "Bearer  "} {
+		_, err := BearerToken(header)
+		require.Error(t, err, header)
+		assert.Equal(t, appErrors.ErrUnauthorized.Code, appErrors.FromError(err).Code)
+	}
+}
+
+func TestAuthorizeResolvesSelf(t *testing.T) {
+	teacher := &models.JWTClaims{UserID: "user-1", Role: models.RoleTeacher}
+
+	assert.True(t, Authorize(teacher, "", string(models.RoleTeacher)))
+	assert.False(t, Authorize(teacher, "user-1", string(models.RoleAdmin)))
+	assert.True(t, Authorize(teacher, "user-1", string(models.RoleAdmin), Self))
+	assert.False(t, Authorize(teacher, "user-2", string(models.RoleAdmin), Self))
+	assert.False(t, Authorize(nil, "user-1", Self))
+}
+
+func TestMiddlewareAndRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := verifierStub{
+		"teacher": {UserID: "user-1", Role: models.RoleTeacher},
+		"admin":   {UserID: "admin-1", Role: models.RoleAdmin},
+	}
+	engine := gin.New()
+	var fromRequest *models.JWTClaims
+	engine.GET("/users/:id", Middleware(verifier), RequireRole(string(models.RoleAdmin), Self), func(c *gin.Context) {
+		fromRequest = ClaimsFrom(c.Request.Context())
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve("/users/user-1", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve("/users/user-1", "forged"))
+	assert.Equal(t, http.StatusForbidden, serve("/users/user-2", "teacher"))
+	assert.Equal(t, http.StatusNoContent, serve("/users/user-1", "teacher"))
+	require.NotNil(t, fromRequest)
+	assert.Equal(t, "user-1", fromRequest.UserID)
+	assert.Equal(t, http.StatusNoContent, serve("/users/user-2", "admin"))
+}
+
+func TestClaimsFromEmptyContext(t *testing.T) {
+	assert.Nil(t, ClaimsFrom(context.Background()))
+}
+
+func TestRequireOwnershipResolvesNestedRecords(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := verifierStub{
+		"teacher": {UserID: "teacher-1", Role: models.RoleTeacher},
+		"admin":   {UserID: "admin-1", Role: models.RoleAdmin},
+	}
+	owners := map[string]string{"a-1": "teacher-1", "a-2": "teacher-2"}
+	lookups := 0
+	lookup := func(ctx context.Context, id string) (string, error) {
+		lookups++
+		if id == "broken" {
+			return "", errors.New("database down")
+		}
+		if owner, ok := owners[id]; ok {
+			return owner, nil
+		}
+		return "", sql.ErrNoRows
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	engine := gin.New()
+	engine.GET("/teachers/:id/assignments/:aid", Middleware(verifier),
+		RequireOwnership(Ownership{Param("id"), Owned("aid", lookup)}, string(models.RoleAdmin), Self), ok)
+	engine.GET("/preferences", Middleware(verifier),
+		RequireOwnership(Ownership{Query("teacher_id", "teacherId")}, string(models.RoleAdmin), Self), ok)
+
+	serve := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve("/teachers/teacher-1/assignments/a-1", "teacher"))
+	// A peer's assignment is refused whether it is reached through the peer's ID or the caller's.
+	assert.Equal(t, http.StatusForbidden, serve("/teachers/teacher-2/assignments/a-2", "teacher"))
+	assert.Equal(t, http.StatusForbidden, serve("/teachers/teacher-1/assignments/a-2", "teacher"))
+	assert.Equal(t, http.StatusForbidden, serve("/teachers/teacher-1/assignments/missing", "teacher"))
+	assert.Equal(t, http.StatusInternalServerError, serve("/teachers/teacher-1/assignments/broken", "teacher"))
+	calls := lookups
+	assert.Equal(t, http.StatusNoContent, serve("/teachers/teacher-2/assignments/a-2", "admin"))
+	assert.Equal(t, calls, lookups, "roles are checked before owners are looked up")
+
+	assert.Equal(t, http.StatusNoContent, serve("/preferences?teacherId=teacher-1", "teacher"))
+	assert.Equal(t, http.StatusForbidden, serve("/preferences?teacher_id=teacher-2", "teacher"))
+	assert.Equal(t, http.StatusForbidden, serve("/preferences", "teacher"))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OwnerLookup returns the user owning the record with the given ID. A missing record is reported as
+// sql.ErrNoRows or an empty owner.
+type OwnerLookup func(ctx context.Context, id string) (string, error)
+
+// OwnershipRule is one link of an Ownership chain: where the request carries an ID and, for IDs of
+// records rather than users, how to find the record's owner.
+type OwnershipRule struct {
+	// Param and Query name the route parameter or query values holding the ID; the first non-empty
+	// one is used.
+	Param string
+	Query []string
+	// Lookup maps the ID to its owner. Without it the ID is taken to be a user ID.
+	Lookup OwnerLookup
+}
+
+// Ownership declares who a request is about for the Self pseudo-role. Every rule must resolve to
+// the same user, so on /teachers/:id/assignments/:aid the chain {Param("id"), Owned("aid", ...)}
+// admits a teacher only for their own assignments.
+type Ownership []OwnershipRule
+
+// DefaultOwnership makes the :id route parameter the subject, which is what RequireRole uses.
+var DefaultOwnership = Ownership{Param("id")}
+
+// Param takes the subject's user ID from a route parameter.
+func Param(name string) OwnershipRule {
+	return OwnershipRule{Param: name}
+}
+
+// Query takes the subject's user ID from the first of the named query values that is present.
+func Query(names ...string) OwnershipRule {
+	return OwnershipRule{Query: names}
+}
+
+// Owned resolves the record named by a route parameter to its owner through lookup.
+func Owned(param string, lookup OwnerLookup) OwnershipRule {
+	return OwnershipRule{Param: param, Lookup: lookup}
+}
+
+// Subject returns the user every rule resolves to, or "" when an ID is missing, a record is not
+// found or the rules disagree. Lookup failures other than a missing record are returned.
+func (o Ownership) Subject(c *gin.Context) (string, error) {
+	subject := ""
+	for _, rule := range o {
+		owner, err := rule.owner(c)
+		if err != nil {
+			return "", err
+		}
+		if owner == "" || (subject != "" && owner != subject) {
+			return "", nil
+		}
+		subject = owner
+	}
+	return subject, nil
+}
+
+func (r OwnershipRule) owner(c *gin.Context) (string, error) {
+	id := ""
+	if r.Param != "" {
+		id = c.Param(r.Param)
+	}
+	for _, name := range r.Query {
+		if id != "" {
+			break
+		}
+		id = strings.TrimSpace(c.Query(name))
+	}
+	if id == "" || r.Lookup == nil {
+		return id, nil
+	}
+	owner, err := r.Lookup(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return owner, err
+}
+
+// RequireOwnership is RequireRole with the Self pseudo-role resolved through owner instead of the
+// :id parameter.
+func RequireOwnership(owner Ownership, allowed ...string) gin.HandlerFunc {
+	return roleGuard{allowed: allowed, owner: owner}.handle
+}
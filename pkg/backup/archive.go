@@ -0,0 +1,207 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Entry names inside the archive. The manifest comes first so it can be read without the dump.
+const (
+	ManifestEntry = "manifest.json"
+	DumpEntry     = "database.dump"
+)
+
+// Manifest describes what an archive holds. Storage files are listed, not copied; restores use the
+// list to check the storage directories restored from their own backups.
+type Manifest struct {
+	CreatedAt time.Time     `json:"createdAt"`
+	Database  string        `json:"database"`
+	Schemas   []string      `json:"schemas"`
+	Storage   []StorageFile `json:"storage"`
+}
+
+// StorageFile is one file in a storage directory at backup time.
+type StorageFile struct {
+	// Dir is the storage directory's name, e.g. "archives"; Path is relative to it.
+	Dir    string `json:"dir"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// StorageMismatch is a manifest file that is missing or differs on disk.
+type StorageMismatch struct {
+	File   StorageFile
+	Reason string
+}
+
+// ScanStorage lists the files under each named directory, hashing their contents. Missing
+// directories are skipped.
+func ScanStorage(dirs map[string]string) ([]StorageFile, error) {
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	files := []StorageFile{}
+	for _, name := range names {
+		root := dirs[name]
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && path == root {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			size, sum, err := hashFile(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, StorageFile{Dir: name, Path: filepath.ToSlash(rel), Size: size, SHA256: sum})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scan %s storage: %w", name, err)
+		}
+	}
+	return files, nil
+}
+
+// VerifyStorage compares the manifest's files with the named directories. Files added since the
+// backup are not reported.
+func VerifyStorage(files []StorageFile, dirs map[string]string) ([]StorageMismatch, error) {
+	var mismatches []StorageMismatch
+	for _, file := range files {
+		root, ok := dirs[file.Dir]
+		if !ok {
+			mismatches = append(mismatches, StorageMismatch{File: file, Reason: "storage directory not configured"})
+			continue
+		}
+		size, sum, err := hashFile(filepath.Join(root, filepath.FromSlash(file.Path)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			mismatches = append(mismatches, StorageMismatch{File: file, Reason: "missing"})
+		case err != nil:
+			return nil, err
+		case size != file.Size || sum != file.SHA256:
+			mismatches = append(mismatches, StorageMismatch{File: file, Reason: "content differs"})
+		}
+	}
+	return mismatches, nil
+}
+
+func hashFile(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close() //nolint:errcheck
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("hash %s: %w", path, err)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteArchive writes a gzipped tar holding the manifest and the dump file at dumpPath to w.
+func WriteArchive(w io.Writer, manifest Manifest, dumpPath string) error {
+	dump, err := os.Open(dumpPath)
+	if err != nil {
+		return fmt.Errorf("open database dump: %w", err)
+	}
+	defer dump.Close() //nolint:errcheck
+	info, err := dump.Stat()
+	if err != nil {
+		return fmt.Errorf("stat database dump: %w", err)
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode backup manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	modTime := manifest.CreatedAt
+	if err := tw.WriteHeader(&tar.Header{Name: ManifestEntry, Mode: 0o600, Size: int64(len(encoded)), ModTime: modTime}); err != nil {
+		return fmt.Errorf("write manifest header: %w", err)
+	}
+	if _, err := tw.Write(encoded); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: DumpEntry, Mode: 0o600, Size: info.Size(), ModTime: modTime}); err != nil {
+		return fmt.Errorf("write dump header: %w", err)
+	}
+	if _, err := io.Copy(tw, dump); err != nil {
+		return fmt.Errorf("write database dump: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("close backup tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("close backup gzip: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive reads an archive written by WriteArchive, copying the dump to dump when it is not nil.
+func ReadArchive(r io.Reader, dump io.Writer) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open backup gzip: %w", err)
+	}
+	defer gz.Close() //nolint:errcheck
+	tr := tar.NewReader(gz)
+	var manifest *Manifest
+	dumped := false
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read backup tar: %w", err)
+		}
+		switch header.Name {
+		case ManifestEntry:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("decode backup manifest: %w", err)
+			}
+		case DumpEntry:
+			if dump == nil {
+				// The manifest precedes the dump, so listing stops here.
+				return manifest, nil
+			}
+			if _, err := io.Copy(dump, tr); err != nil {
+				return nil, fmt.Errorf("extract database dump: %w", err)
+			}
+			dumped = true
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("backup archive has no manifest")
+	}
+	if dump != nil && !dumped {
+		return nil, errors.New("backup archive has no database dump")
+	}
+	return manifest, nil
+}
@@ -0,0 +1,144 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func encrypt(t *testing.T, key, plain []byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	enc, err := NewEncrypter(&out, key)
+	require.NoError(t, err)
+	_, err = enc.Write(plain)
+	require.NoError(t, err)
+	require.NoError(t, enc.Close())
+	return out.Bytes()
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	key := testKey(t)
+	for _, size := range []int{0, 10, chunkSize, 2*chunkSize + 17} {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		require.NoError(t, err)
+
+		sealed := encrypt(t, key, plain)
+		dec, err := NewDecrypter(bytes.NewReader(sealed), key)
+		require.NoError(t, err)
+		got, err := io.ReadAll(dec)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plain, got, "size %d", size)
+	}
+}
+
+func TestEncryptionRejectsTamperingTruncationAndWrongKeys(t *testing.T) {
+	key := testKey(t)
+	plain := bytes.Repeat([]byte("attendance"), chunkSize/5)
+	sealed := encrypt(t, key, plain)
+
+	readAll := func(data, key []byte) error {
+		dec, err := NewDecrypter(bytes.NewReader(data), key)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadAll(dec)
+		return err
+	}
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(magic)+saltSize+10] ^= 1
+	assert.ErrorIs(t, readAll(tampered, key), ErrCorrupt)
+
+	// Dropping the final chunk leaves only full chunks, which must not pass as complete.
+	full := len(magic) + saltSize + chunkSize + 16
+	assert.ErrorIs(t, readAll(sealed[:full], key), ErrCorrupt)
+	assert.ErrorIs(t, readAll(sealed[:len(sealed)-1], key), ErrCorrupt)
+
+	assert.ErrorIs(t, readAll(sealed, testKey(t)), ErrCorrupt)
+	assert.ErrorIs(t, readAll([]byte("not a backup at all, just text"), key), ErrCorrupt)
+}
+
+func TestParseKey(t *testing.T) {
+	key := testKey(t)
+	parsed, err := ParseKey(base64.StdEncoding.EncodeToString(key) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString(key[:16]))
+	assert.Error(t, err)
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+}
+
+func TestArchiveRoundTripAndStorageVerification(t *testing.T) {
+	dir := t.TempDir()
+	archives := filepath.Join(dir, "archives")
+	require.NoError(t, os.MkdirAll(filepath.Join(archives, "2026"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(archives, "2026", "a.pdf"), []byte("pdf"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(archives, "b.pdf"), []byte("other"), 0o644))
+	dirs := map[string]string{"archives": archives, "reports": filepath.Join(dir, "missing")}
+
+	files, err := ScanStorage(dirs)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "2026/a.pdf", files[0].Path)
+	assert.Equal(t, int64(3), files[0].Size)
+
+	dumpPath := filepath.Join(dir, "db.dump")
+	require.NoError(t, os.WriteFile(dumpPath, []byte("PGDMP"), 0o600))
+	manifest := Manifest{CreatedAt: time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC), Database: "sma", Schemas: []string{"public"}, Storage: files}
+	var archive bytes.Buffer
+	require.NoError(t, WriteArchive(&archive, manifest, dumpPath))
+
+	listed, err := ReadArchive(bytes.NewReader(archive.Bytes()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, *listed)
+
+	var dump bytes.Buffer
+	_, err = ReadArchive(bytes.NewReader(archive.Bytes()), &dump)
+	require.NoError(t, err)
+	assert.Equal(t, "PGDMP", dump.String())
+
+	require.NoError(t, os.WriteFile(filepath.Join(archives, "b.pdf"), []byte("changed"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(archives, "2026", "a.pdf")))
+	mismatches, err := VerifyStorage(files, dirs)
+	require.NoError(t, err)
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "missing", mismatches[0].Reason)
+	assert.Equal(t, "content differs", mismatches[1].Reason)
+}
+
+func TestPostgresArgs(t *testing.T) {
+	pg := Postgres{
+		Database: config.DatabaseConfig{Host: "db", Port: 5432, User: "app", Password: "secret", Name: "sma"},
+		Schemas:  []string{"public", " attendance_archive "},
+	}
+	args := pg.DumpArgs()
+	assert.Contains(t, args, "--schema=public")
+	assert.Contains(t, args, "--schema=attendance_archive")
+	assert.Contains(t, args, "--format=custom")
+	for _, arg := range append(args, pg.RestoreArgs("/tmp/db.dump")...) {
+		assert.NotContains(t, arg, "secret")
+	}
+	assert.Equal(t, "/tmp/db.dump", pg.RestoreArgs("/tmp/db.dump")[len(pg.RestoreArgs("/tmp/db.dump"))-1])
+}
@@ -0,0 +1,194 @@
+// Package backup builds and reads encrypted backup archives: a pg_dump of the database plus a
+// manifest of the files in the storage directories.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// KeySize is the length of the encryption key in bytes.
+const KeySize = 32
+
+const (
+	magic     = "SMABAK01"
+	saltSize  = 32
+	chunkSize = 64 * 1024
+)
+
+// ErrCorrupt is returned when an archive was truncated, tampered with or encrypted with another key.
+var ErrCorrupt = errors.New("backup archive is corrupt or the key is wrong")
+
+// ParseKey decodes a base64 encoded 32-byte key, as produced by `openssl rand -base64 32`.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decode backup key: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("backup key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// NewEncrypter returns a writer encrypting everything written to it into w. Each archive derives its
+// own key from key and a random salt, and is sealed in AES-256-GCM chunks whose nonces carry the
+// chunk number and a final-chunk flag, so reordered, dropped or truncated chunks fail to decrypt.
+// Close must be called to write the final chunk; it does not close w.
+func NewEncrypter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generate backup salt: %w", err)
+	}
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append([]byte(magic), salt...)); err != nil {
+		return nil, fmt.Errorf("write backup header: %w", err)
+	}
+	return &encrypter{w: w, aead: aead, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+// NewDecrypter returns a reader yielding the plaintext of an archive written by NewEncrypter. Reads
+// fail with ErrCorrupt as soon as a chunk does not authenticate.
+func NewDecrypter(r io.Reader, key []byte) (io.Reader, error) {
+	header := make([]byte, len(magic)+saltSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrCorrupt
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a backup archive: %w", ErrCorrupt)
+	}
+	aead, err := newAEAD(key, header[len(magic):])
+	if err != nil {
+		return nil, err
+	}
+	return &decrypter{r: bufio.NewReaderSize(r, chunkSize+aead.Overhead()), aead: aead}, nil
+}
+
+func newAEAD(key, salt []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("backup key must be %d bytes, got %d", KeySize, len(key))
+	}
+	derived := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, []byte("sma-adp backup")), derived); err != nil {
+		return nil, fmt.Errorf("derive backup key: %w", err)
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, fmt.Errorf("init backup cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce encodes the chunk number in the first 11 bytes and the final flag in the last.
+func chunkNonce(counter uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+type encrypter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+	closed  bool
+}
+
+func (e *encrypter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed backup encrypter")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+		// A full chunk is never the last one; Close always seals a shorter, possibly empty, chunk.
+		if len(e.buf) == chunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encrypter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *encrypter) seal(final bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.counter, final), e.buf, nil)
+	e.counter++
+	e.buf = e.buf[:0]
+	if _, err := e.w.Write(sealed); err != nil {
+		return fmt.Errorf("write backup chunk: %w", err)
+	}
+	return nil
+}
+
+type decrypter struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	plain   bytes.Buffer
+	counter uint64
+	done    bool
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	for d.plain.Len() == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	return d.plain.Read(p)
+}
+
+func (d *decrypter) next() error {
+	sealed := make([]byte, chunkSize+d.aead.Overhead())
+	n, err := io.ReadFull(d.r, sealed)
+	final := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		final = true
+	case errors.Is(err, io.EOF):
+		// The final chunk is always present, so running out of input here means truncation.
+		return ErrCorrupt
+	case err != nil:
+		return fmt.Errorf("read backup chunk: %w", err)
+	}
+	plain, err := d.aead.Open(nil, chunkNonce(d.counter, final), sealed[:n], nil)
+	if err != nil {
+		return ErrCorrupt
+	}
+	d.counter++
+	d.done = final
+	d.plain.Write(plain)
+	return nil
+}
@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// Default client commands, resolved on PATH.
+const (
+	DefaultDumpCommand    = "pg_dump"
+	DefaultRestoreCommand = "pg_restore"
+)
+
+// Postgres runs pg_dump and pg_restore against a database. The password is passed through the
+// environment so it never shows up in process listings.
+type Postgres struct {
+	Database config.DatabaseConfig
+	// Schemas limits dumps to these schemas; empty dumps the whole database.
+	Schemas        []string
+	DumpCommand    string
+	RestoreCommand string
+}
+
+// Available reports whether the dump command can be found on PATH.
+func (p Postgres) Available() error {
+	if _, err := exec.LookPath(p.dumpCommand()); err != nil {
+		return fmt.Errorf("backup dump command %s: %w", p.dumpCommand(), err)
+	}
+	return nil
+}
+
+// Dump writes a custom-format dump of the configured schemas to w.
+func (p Postgres) Dump(ctx context.Context, w io.Writer) error {
+	cmd := exec.CommandContext(ctx, p.dumpCommand(), p.DumpArgs()...)
+	cmd.Env = p.env()
+	cmd.Stdout = w
+	return run(cmd, "pg_dump")
+}
+
+// Restore loads the custom-format dump at path, replacing the objects it contains.
+func (p Postgres) Restore(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, p.restoreCommand(), p.RestoreArgs(path)...)
+	cmd.Env = p.env()
+	cmd.Stdout = os.Stdout
+	return run(cmd, "pg_restore")
+}
+
+// DumpArgs returns the pg_dump arguments.
+func (p Postgres) DumpArgs() []string {
+	args := append(p.connArgs(), "--format=custom", "--no-owner", "--no-privileges")
+	for _, schema := range p.Schemas {
+		if schema = strings.TrimSpace(schema); schema != "" {
+			args = append(args, "--schema="+schema)
+		}
+	}
+	return args
+}
+
+// RestoreArgs returns the pg_restore arguments for the dump at path. The restore runs in a single
+// transaction, so a failure leaves the database as it was.
+func (p Postgres) RestoreArgs(path string) []string {
+	return append(p.connArgs(), "--clean", "--if-exists", "--no-owner", "--no-privileges", "--single-transaction", "--exit-on-error", path)
+}
+
+func (p Postgres) connArgs() []string {
+	return []string{
+		"--host=" + p.Database.Host,
+		"--port=" + strconv.Itoa(p.Database.Port),
+		"--username=" + p.Database.User,
+		"--dbname=" + p.Database.Name,
+		"--no-password",
+	}
+}
+
+func (p Postgres) env() []string {
+	env := append(os.Environ(), "PGPASSWORD="+p.Database.Password)
+	if p.Database.SSLMode != "" {
+		env = append(env, "PGSSLMODE="+p.Database.SSLMode)
+	}
+	return env
+}
+
+func (p Postgres) dumpCommand() string {
+	if strings.TrimSpace(p.DumpCommand) == "" {
+		return DefaultDumpCommand
+	}
+	return p.DumpCommand
+}
+
+func (p Postgres) restoreCommand() string {
+	if strings.TrimSpace(p.RestoreCommand) == "" {
+		return DefaultRestoreCommand
+	}
+	return p.RestoreCommand
+}
+
+func run(cmd *exec.Cmd, name string) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package broker publishes messages to the message broker that feeds downstream consumers. The
+// publisher is chosen by configuration so callers depend only on Publisher.
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// Supported brokers.
+const (
+	BrokerLog       = "log"
+	BrokerNATS      = "nats"
+	BrokerKafkaREST = "kafka-rest"
+)
+
+// Message is one record to publish. Subject is the NATS subject or Kafka topic and Key partitions
+// Kafka records. Headers are sent as NATS message headers; the Kafka REST proxy has no headers.
+type Message struct {
+	Subject string
+	Key     string
+	Headers map[string]string
+	Data    []byte
+}
+
+// Publisher delivers messages to a broker. Publish returns only once the broker has accepted the
+// message, so a nil error means the message will not be lost by this process.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// New returns the publisher selected by cfg.Broker.
+func New(cfg config.EventsConfig, logger *zap.Logger) (Publisher, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	timeout := cfg.PublishTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	switch cfg.Broker {
+	case "", BrokerLog:
+		return NewLogPublisher(logger), nil
+	case BrokerNATS:
+		return NewNATSPublisher(cfg.BrokerURL, timeout)
+	case BrokerKafkaREST:
+		return NewKafkaRESTPublisher(cfg.BrokerURL, timeout)
+	default:
+		return nil, fmt.Errorf("unsupported events broker %q", cfg.Broker)
+	}
+}
+
+// LogPublisher writes messages to the log instead of a broker. It lets the outbox run in
+// development without broker infrastructure.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher constructs a LogPublisher.
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs msg.
+func (p *LogPublisher) Publish(_ context.Context, msg Message) error {
+	p.logger.Info("domain event", zap.String("subject", msg.Subject), zap.String("key", msg.Key), zap.ByteString("data", msg.Data))
+	return nil
+}
+
+// Close is a no-op.
+func (p *LogPublisher) Close() error {
+	return nil
+}
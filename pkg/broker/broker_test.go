@@ -0,0 +1,146 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATS accepts one connection, records the CONNECT line and every published frame, and answers
+// PINGs. It acknowledges publishes like a JetStream stream capturing "sma.>" and answers any other
+// subject with a 503 no-responders status, as the server does when no stream captures it.
+func fakeNATS(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	frames := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(`INFO {"headers":true,"max_payload":1048576,"proto":1}` + "\r\n"))
+		reader := bufio.NewReader(conn)
+		subs := map[string]string{}
+		seq := 0
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSpace(line)
+			fields := strings.Fields(line)
+			switch {
+			case line == "PING":
+				conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "CONNECT "):
+				frames <- line
+			case len(fields) == 3 && fields[0] == "SUB":
+				subs[strings.TrimSuffix(fields[1], "*")] = fields[2]
+			case len(fields) > 0 && (fields[0] == "PUB" || fields[0] == "HPUB"):
+				size, _ := strconv.Atoi(fields[len(fields)-1])
+				body := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, body); err != nil {
+					return
+				}
+				frames <- line + "\n" + string(body[:size])
+				reply := fields[2]
+				sid := ""
+				for prefix, id := range subs {
+					if strings.HasPrefix(reply, prefix) {
+						sid = id
+					}
+				}
+				if !strings.HasPrefix(fields[1], "sma.") {
+					status := "NATS/1.0 503\r\n\r\n"
+					fmt.Fprintf(conn, "HMSG %s %s %d %d\r\n%s\r\n", reply, sid, len(status), len(status), status)
+					continue
+				}
+				seq++
+				ack := fmt.Sprintf(`{"stream":"SMA_EVENTS","seq":%d}`, seq)
+				fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+			}
+		}
+	}()
+	return "nats://svc:secret@" + ln.Addr().String(), frames
+}
+
+func TestNATSPublisherPublishesWithHeaders(t *testing.T) {
+	url, frames := fakeNATS(t)
+	publisher, err := NewNATSPublisher(url, time.Second)
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	err = publisher.Publish(context.Background(), Message{
+		Subject: "sma.attendance.marked.v1",
+		Headers: map[string]string{"Nats-Msg-Id": "evt-1"},
+		Data:    []byte(`{"id":"evt-1"}`),
+	})
+	require.NoError(t, err)
+
+	connect := <-frames
+	assert.Contains(t, connect, `"user":"svc"`)
+	assert.Contains(t, connect, `"headers":true`)
+	frame := <-frames
+	assert.True(t, strings.HasPrefix(frame, "HPUB sma.attendance.marked.v1 _INBOX."), frame)
+	assert.Contains(t, frame, "Nats-Msg-Id: evt-1\r\n")
+	assert.True(t, strings.HasSuffix(frame, `{"id":"evt-1"}`), frame)
+
+	err = publisher.Publish(context.Background(), Message{Subject: "other.subject", Data: []byte(`{}`)})
+	assert.ErrorIs(t, err, jetstream.ErrNoStreamResponse)
+	assert.Error(t, publisher.Publish(context.Background(), Message{Subject: "bad subject", Data: []byte(`{}`)}))
+}
+
+func TestNewNATSPublisherRejectsInvalidURL(t *testing.T) {
+	for _, raw := range []string{"http://localhost:4222", "nats://", "localhost:4222"} {
+		_, err := NewNATSPublisher(raw, time.Second)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestKafkaRESTPublisher(t *testing.T) {
+	var received struct {
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	var path, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if strings.HasSuffix(r.URL.Path, "rejected") {
+			w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":40403,"error":"topic not authorized"}]}`))
+			return
+		}
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":42,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	publisher, err := NewKafkaRESTPublisher(server.URL+"/", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(context.Background(), Message{Subject: "sma.grade.finalized.v1", Key: "class-1", Data: []byte(`{"id":"evt-1"}`)}))
+	assert.Equal(t, "/topics/sma.grade.finalized.v1", path)
+	assert.Equal(t, kafkaRESTContentType, contentType)
+	require.Len(t, received.Records, 1)
+	assert.Equal(t, "class-1", received.Records[0].Key)
+	assert.JSONEq(t, `{"id":"evt-1"}`, string(received.Records[0].Value))
+
+	err = publisher.Publish(context.Background(), Message{Subject: "rejected", Data: []byte(`{}`)})
+	assert.ErrorContains(t, err, "topic not authorized")
+}
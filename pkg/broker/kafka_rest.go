@@ -0,0 +1,87 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const kafkaRESTContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaRESTPublisher produces to Kafka through a REST proxy speaking the v2 API, e.g. the Confluent
+// REST Proxy, so the service needs no native Kafka client. Message data must be JSON.
+type KafkaRESTPublisher struct {
+	baseURL string
+	client  *http.Client
+}
+
+type kafkaRESTRecord struct {
+	Key   string          `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaRESTResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// NewKafkaRESTPublisher constructs a publisher posting to the proxy at baseURL.
+func NewKafkaRESTPublisher(baseURL string, timeout time.Duration) (*KafkaRESTPublisher, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid Kafka REST url %q", baseURL)
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &KafkaRESTPublisher{baseURL: strings.TrimRight(baseURL, "/"), client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Publish produces msg to the topic named by msg.Subject and checks the per-record result.
+func (p *KafkaRESTPublisher) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string][]kafkaRESTRecord{
+		"records": {{Key: msg.Key, Value: json.RawMessage(msg.Data)}},
+	})
+	if err != nil {
+		return fmt.Errorf("encode kafka record: %w", err)
+	}
+	endpoint := p.baseURL + "/topics/" + url.PathEscape(msg.Subject)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build kafka produce request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaRESTContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post kafka record: %w", err)
+	}
+	defer resp.Body.Close()
+	payload, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka rest proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(payload)))
+	}
+	var result kafkaRESTResponse
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return fmt.Errorf("decode kafka produce response: %w", err)
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka rejected record: %s", offset.Error)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op; the HTTP client holds no dedicated connection.
+func (p *KafkaRESTPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSPublisher publishes to a JetStream stream. Every publish waits for the stream's PubAck, so a
+// nil error means the message is stored; a subject no stream captures fails with
+// jetstream.ErrNoStreamResponse instead of being dropped. The Nats-Msg-Id header lets the stream
+// discard redeliveries inside its duplicate window.
+//
+// The connection is opened on the first publish and reconnects on its own afterwards.
+type NATSPublisher struct {
+	url     string
+	timeout time.Duration
+	mu      sync.Mutex
+	conn    *nats.Conn
+	js      jetstream.JetStream
+}
+
+// NewNATSPublisher validates rawURL (nats://[user:pass@]host[:port], or tls:// to require TLS). It
+// does not connect until the first publish.
+func NewNATSPublisher(rawURL string, timeout time.Duration) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "nats" && u.Scheme != "tls") {
+		return nil, fmt.Errorf("invalid NATS url %q", rawURL)
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &NATSPublisher{url: rawURL, timeout: timeout}, nil
+}
+
+// Publish sends msg to JetStream and waits for the stream to acknowledge it.
+func (p *NATSPublisher) Publish(ctx context.Context, msg Message) error {
+	if msg.Subject == "" || strings.ContainsAny(msg.Subject, " \t\r\n") {
+		return fmt.Errorf("invalid NATS subject %q", msg.Subject)
+	}
+	js, err := p.jetStream()
+	if err != nil {
+		return err
+	}
+	out := nats.NewMsg(msg.Subject)
+	out.Data = msg.Data
+	for name, value := range msg.Headers {
+		out.Header.Set(name, value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if _, err := js.PublishMsg(ctx, out); err != nil {
+		if errors.Is(err, jetstream.ErrNoStreamResponse) {
+			return fmt.Errorf("publish %s: no JetStream stream captures the subject: %w", msg.Subject, err)
+		}
+		return fmt.Errorf("publish %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.js = nil, nil
+	}
+	return nil
+}
+
+func (p *NATSPublisher) jetStream() (jetstream.JetStream, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.js != nil {
+		return p.js, nil
+	}
+	conn, err := nats.Connect(p.url,
+		nats.Name("sma-adp-api"),
+		nats.Timeout(p.timeout),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect nats: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("open jetstream: %w", err)
+	}
+	p.conn, p.js = conn, js
+	return js, nil
+}
@@ -0,0 +1,69 @@
+// Package buildinfo identifies the running build. Release builds inject the values with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/noah-isme/sma-adp-api/pkg/buildinfo.Version=v1.4.0 \
+//	  -X github.com/noah-isme/sma-adp-api/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/noah-isme/sma-adp-api/pkg/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them, the commit and date fall back to the VCS stamp the Go toolchain embeds.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Values injected at link time.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information, falling back to the embedded VCS stamp for values not injected.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+	if info.Commit != "" && info.BuildDate != "" {
+		return info
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// String renders the version and short commit, e.g. "v1.4.0+3c02e56".
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if commit == "" || commit == "unknown" {
+		return i.Version
+	}
+	return i.Version + "+" + commit
+}
@@ -0,0 +1,21 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUsesInjectedValues(t *testing.T) {
+	defer func(version, commit, date string) { Version, Commit, BuildDate = version, commit, date }(Version, Commit, BuildDate)
+	Version, Commit, BuildDate = "v1.4.0", "3c02e56d0b1f2a", "2026-10-16T08:00:00Z"
+
+	info := Get()
+	assert.Equal(t, Info{Version: "v1.4.0", Commit: "3c02e56d0b1f2a", BuildDate: "2026-10-16T08:00:00Z", GoVersion: runtime.Version()}, info)
+	assert.Equal(t, "v1.4.0+3c02e56", info.String())
+}
+
+func TestStringWithoutCommit(t *testing.T) {
+	assert.Equal(t, "dev", Info{Version: "dev", Commit: "unknown"}.String())
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen is returned without contacting Redis while the breaker is open.
+var ErrCircuitOpen = errors.New("redis circuit open")
+
+// breaker is a go-redis hook that opens after consecutive connection failures and short-circuits
+// commands until the cooldown elapses. After the cooldown a single command probes the server; the
+// others are still short-circuited until its outcome closes or re-opens the circuit.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	open      bool
+	probing   bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration, logger *zap.Logger) *breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown, logger: logger, now: time.Now}
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if !isConnectionError(err) {
+		if b.open {
+			b.logger.Info("redis circuit closed")
+		}
+		b.failures = 0
+		b.open = false
+		return
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	if !b.open {
+		b.logger.Warn("redis circuit opened", zap.Int("failures", b.failures), zap.Duration("cooldown", b.cooldown), zap.Error(err))
+	}
+	b.open = true
+	b.openUntil = b.now().Add(b.cooldown)
+}
+
+// isConnectionError reports whether err indicates Redis is unreachable rather than a miss or a
+// command-level error reply.
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var reply redis.Error
+	if errors.As(err, &reply) {
+		return false
+	}
+	return true
+}
+
+func (b *breaker) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (b *breaker) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !b.allow() {
+			cmd.SetErr(ErrCircuitOpen)
+			return ErrCircuitOpen
+		}
+		err := next(ctx, cmd)
+		b.record(err)
+		return err
+	}
+}
+
+func (b *breaker) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !b.allow() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrCircuitOpen)
+			}
+			return ErrCircuitOpen
+		}
+		err := next(ctx, cmds)
+		b.record(err)
+		return err
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func TestBreakerShortCircuitsUnreachableRedis(t *testing.T) {
+	raw := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer raw.Close()
+
+	b := newBreaker(2, time.Minute, zap.NewNop())
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	raw.AddHook(b)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		err := raw.Get(ctx, "key").Err()
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrCircuitOpen))
+	}
+	assert.ErrorIs(t, raw.Get(ctx, "key").Err(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+	err := raw.Get(ctx, "key").Err()
+	assert.False(t, errors.Is(err, ErrCircuitOpen), "probe should reach the server after the cooldown")
+	assert.ErrorIs(t, raw.Get(ctx, "key").Err(), ErrCircuitOpen)
+}
+
+func TestBreakerIgnoresMisses(t *testing.T) {
+	b := newBreaker(1, time.Minute, zap.NewNop())
+	b.record(redis.Nil)
+	assert.True(t, b.allow())
+
+	b.record(errors.New("dial tcp: connection refused"))
+	assert.False(t, b.allow())
+
+	b.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	assert.True(t, b.allow())
+	b.record(redis.Nil)
+	assert.False(t, b.open)
+}
+
+func TestBreakerAdmitsOneProbe(t *testing.T) {
+	b := newBreaker(1, time.Minute, zap.NewNop())
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	b.record(errors.New("dial tcp: connection refused"))
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow(), "the first command after the cooldown probes")
+	assert.False(t, b.allow(), "concurrent commands wait for the probe")
+
+	b.record(errors.New("dial tcp: connection refused"))
+	assert.False(t, b.allow(), "a failed probe re-opens the circuit")
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+	b.record(nil)
+	assert.True(t, b.allow())
+	assert.True(t, b.allow(), "a successful probe closes the circuit")
+}
+
+func TestNewRedisRejectsIncompleteModes(t *testing.T) {
+	_, err := NewRedis(config.RedisConfig{Mode: ModeSentinel}, nil)
+	assert.Error(t, err)
+	_, err = NewRedis(config.RedisConfig{Mode: ModeCluster}, nil)
+	assert.Error(t, err)
+	_, err = NewRedis(config.RedisConfig{Mode: "replica"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,363 @@
+// Package client is a Go client for the SMA ADP API. generated.go has one method per operation in
+// the OpenAPI document; typed.go wraps the most used ones with request and response types. The
+// client logs in once and refreshes its access token before it expires or when a request comes back
+// 401.
+package client
+
+//go:generate go run ./gen -out generated.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+const (
+	// DefaultAPIPrefix is where the API is mounted unless API_PREFIX is changed.
+	DefaultAPIPrefix = "/api/v1"
+	defaultTimeout   = 30 * time.Second
+	// tokenSkew renews the access token this long before it expires.
+	tokenSkew = 30 * time.Second
+)
+
+// Options configures a Client. Zero values use the defaults.
+type Options struct {
+	// APIPrefix is prepended to API operations; health, version and /internal routes are served
+	// from the root.
+	APIPrefix  string
+	HTTPClient *http.Client
+	UserAgent  string
+	// ServerCasing leaves response key casing to the server's default instead of asking for the
+	// keys as tagged in the DTOs, which the typed methods decode into.
+	ServerCasing bool
+	// Now is used for token expiry and only needs overriding in tests.
+	Now func() time.Time
+}
+
+// Client calls the API on one base URL. It is safe for concurrent use.
+type Client struct {
+	baseURL      string
+	apiPrefix    string
+	http         *http.Client
+	userAgent    string
+	serverCasing bool
+	now          func() time.Time
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+// New creates a client for baseURL, e.g. "https://api.sma.example.sch.id".
+func New(baseURL string, opts Options) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("client needs an absolute base URL, got %q", baseURL)
+	}
+	prefix := opts.APIPrefix
+	if prefix == "" {
+		prefix = DefaultAPIPrefix
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = "sma-adp-api-client"
+	}
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		apiPrefix:    "/" + strings.Trim(prefix, "/"),
+		http:         httpClient,
+		userAgent:    userAgent,
+		serverCasing: opts.ServerCasing,
+		now:          now,
+	}, nil
+}
+
+// SetTokens installs tokens obtained elsewhere. expiresIn is the access token lifetime; zero means
+// it is only renewed after a 401.
+func (c *Client) SetTokens(accessToken, refreshToken string, expiresIn time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setTokensLocked(accessToken, refreshToken, expiresIn)
+}
+
+func (c *Client) setTokensLocked(accessToken, refreshToken string, expiresIn time.Duration) {
+	c.accessToken = accessToken
+	if refreshToken != "" {
+		c.refreshToken = refreshToken
+	}
+	c.expiresAt = time.Time{}
+	if expiresIn > 0 {
+		c.expiresAt = c.now().Add(expiresIn)
+	}
+}
+
+// Response is a decoded API response. Data holds the raw "data" member of the envelope and Body the
+// whole body, which is all there is for downloads.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Data       json.RawMessage
+	Pagination *models.Pagination
+	Meta       map[string]interface{}
+}
+
+// Decode unmarshals the envelope data into v.
+func (r *Response) Decode(v interface{}) error {
+	if len(r.Data) == 0 {
+		return errors.New("response has no data")
+	}
+	return json.Unmarshal(r.Data, v)
+}
+
+// APIError is returned for responses with a status of 400 or above, along with the Response.
+type APIError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// IsStatus reports whether err is an *APIError with the given HTTP status.
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Status == status
+}
+
+// Form is a multipart upload: Fields are sent as form values and File, when set, as the "file" part.
+type Form struct {
+	Fields   map[string]string
+	FileName string
+	File     io.Reader
+}
+
+// RequestOption adjusts a single request.
+type RequestOption func(*http.Request)
+
+// WithHeader sets a request header, e.g. X-API-Key for the /internal/sync routes.
+func WithHeader(key, value string) RequestOption {
+	return func(r *http.Request) { r.Header.Set(key, value) }
+}
+
+// request describes one call before it is bound to a URL.
+type request struct {
+	method string
+	path   string
+	// root marks routes served outside the API prefix.
+	root  bool
+	query url.Values
+	body  interface{}
+	form  *Form
+}
+
+// Do sends method to path, which is relative to the base URL and not prefixed, with body encoded as
+// JSON. It is meant for tools that replay arbitrary paths; prefer the generated methods.
+func (c *Client) Do(ctx context.Context, method, path string, body interface{}, opts ...RequestOption) (*Response, error) {
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse path: %w", err)
+	}
+	return c.do(ctx, request{method: method, path: parsed.Path, root: true, query: parsed.Query(), body: body}, opts...)
+}
+
+func (c *Client) do(ctx context.Context, req request, opts ...RequestOption) (*Response, error) {
+	payload, contentType, err := encodeBody(req)
+	if err != nil {
+		return nil, err
+	}
+	authenticated := !isAuthPath(req.path)
+	if authenticated {
+		if err := c.refreshIfExpiring(ctx); err != nil {
+			return nil, err
+		}
+	}
+	token := c.currentToken()
+	resp, err := c.send(ctx, req, payload, contentType, token, opts)
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized || !authenticated || !c.canRefresh() {
+		return resp, err
+	}
+	if err := c.refreshAfter(ctx, token); err != nil {
+		return resp, err
+	}
+	return c.send(ctx, req, payload, contentType, c.currentToken(), opts)
+}
+
+func (c *Client) send(ctx context.Context, req request, payload []byte, contentType, token string, opts []RequestOption) (*Response, error) {
+	target := c.baseURL + req.path
+	if !req.root {
+		target = c.baseURL + c.apiPrefix + req.path
+	}
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if !c.serverCasing || isAuthPath(req.path) {
+		// Tokens are always decoded as tagged.
+		httpReq.Header.Set("Accept-Profile", "tagged")
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	for _, opt := range opts {
+		opt(httpReq)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.method, req.path, err)
+	}
+	defer httpResp.Body.Close()
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s %s: %w", req.method, req.path, err)
+	}
+	return decodeResponse(httpResp, raw)
+}
+
+func encodeBody(req request) ([]byte, string, error) {
+	switch {
+	case req.form != nil:
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for name, value := range req.form.Fields {
+			if err := writer.WriteField(name, value); err != nil {
+				return nil, "", fmt.Errorf("encode form: %w", err)
+			}
+		}
+		if req.form.File != nil {
+			part, err := writer.CreateFormFile("file", req.form.FileName)
+			if err != nil {
+				return nil, "", fmt.Errorf("encode form: %w", err)
+			}
+			if _, err := io.Copy(part, req.form.File); err != nil {
+				return nil, "", fmt.Errorf("encode form: %w", err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", fmt.Errorf("encode form: %w", err)
+		}
+		return buf.Bytes(), writer.FormDataContentType(), nil
+	case req.body != nil:
+		payload, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, "", fmt.Errorf("encode body: %w", err)
+		}
+		return payload, "application/json", nil
+	default:
+		return nil, "", nil
+	}
+}
+
+func decodeResponse(httpResp *http.Response, raw []byte) (*Response, error) {
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: raw}
+	var envelope struct {
+		Data       json.RawMessage        `json:"data"`
+		Error      *APIError              `json:"error"`
+		Pagination *models.Pagination     `json:"pagination"`
+		Meta       map[string]interface{} `json:"meta"`
+	}
+	if strings.HasPrefix(httpResp.Header.Get("Content-Type"), "application/json") && len(raw) > 0 {
+		if err := json.Unmarshal(raw, &envelope); err == nil {
+			resp.Data = envelope.Data
+			resp.Pagination = envelope.Pagination
+			resp.Meta = envelope.Meta
+		}
+	}
+	if httpResp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+	apiErr := envelope.Error
+	if apiErr == nil {
+		apiErr = &APIError{Message: http.StatusText(httpResp.StatusCode)}
+	}
+	apiErr.Status = httpResp.StatusCode
+	return resp, apiErr
+}
+
+// isAuthPath reports whether path is a token endpoint, which never carries or refreshes a token.
+func isAuthPath(path string) bool {
+	return strings.HasSuffix(path, "/auth/login") || strings.HasSuffix(path, "/auth/refresh")
+}
+
+func (c *Client) currentToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accessToken
+}
+
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshToken != ""
+}
+
+// refreshIfExpiring renews the access token shortly before it expires.
+func (c *Client) refreshIfExpiring(ctx context.Context) error {
+	c.mu.Lock()
+	expiring := c.refreshToken != "" && !c.expiresAt.IsZero() && c.now().Add(tokenSkew).After(c.expiresAt)
+	token := c.accessToken
+	c.mu.Unlock()
+	if !expiring {
+		return nil
+	}
+	return c.refreshAfter(ctx, token)
+}
+
+// refreshAfter renews the access token unless another request already replaced stale.
+func (c *Client) refreshAfter(ctx context.Context, stale string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.accessToken != stale {
+		return nil
+	}
+	req := request{method: http.MethodPost, path: "/auth/refresh", body: models.RefreshTokenRequest{RefreshToken: c.refreshToken}}
+	payload, contentType, err := encodeBody(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(ctx, req, payload, contentType, "", nil)
+	if err != nil {
+		return fmt.Errorf("refresh access token: %w", err)
+	}
+	var tokens models.RefreshTokenResponse
+	if err := resp.Decode(&tokens); err != nil {
+		return fmt.Errorf("refresh access token: %w", err)
+	}
+	c.setTokensLocked(tokens.AccessToken, tokens.RefreshToken, time.Duration(tokens.ExpiresIn)*time.Second)
+	return nil
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI issues numbered access tokens and accepts only the latest one.
+type fakeAPI struct {
+	mu        sync.Mutex
+	issued    int
+	refreshes int
+	requests  []string
+}
+
+func (f *fakeAPI) token() string {
+	return "access-" + string(rune('0'+f.issued))
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch r.URL.Path {
+	case "/api/v1/auth/login", "/api/v1/auth/refresh":
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			f.refreshes++
+		}
+		f.issued++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"access_token": f.token(), "refresh_token": "refresh", "expires_in": 900,
+		}})
+		return
+	case "/health":
+		_, _ = io.WriteString(w, `{"status":"ok"}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+f.token() {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"error":{"code":"UNAUTHORIZED","message":"unauthorized","status":401}}`)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/v1/teachers":
+		_, _ = io.WriteString(w, `{"data":[{"id":"t-1","nip":"1987"}],"pagination":{"page":1,"page_size":20,"total_count":1}}`)
+	case "/api/v1/archives":
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(file)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{
+			"title": r.FormValue("title"), "file": header.Filename, "content": string(body),
+		}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":{"code":"NOT_FOUND","message":"resource not found","status":404}}`)
+	}
+}
+
+func newTestClient(t *testing.T, api *fakeAPI, now func() time.Time) *Client {
+	t.Helper()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	c, err := New(srv.URL, Options{Now: now})
+	require.NoError(t, err)
+	return c
+}
+
+func TestClientLogsInAndDecodesEnvelopes(t *testing.T) {
+	api := &fakeAPI{}
+	c := newTestClient(t, api, nil)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, "admin@sma.sch.id", "secret")
+	require.NoError(t, err)
+
+	teachers, pagination, err := c.ListTeachers(ctx, url.Values{"search": {"budi"}})
+	require.NoError(t, err)
+	require.Len(t, teachers, 1)
+	assert.Equal(t, "t-1", teachers[0].ID)
+	assert.Equal(t, 1, pagination.TotalCount)
+
+	resp, err := c.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"ok"}`, string(resp.Body))
+
+	_, err = c.GetArchivesByID(ctx, "a/1")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "NOT_FOUND", apiErr.Code)
+	assert.True(t, IsStatus(err, http.StatusNotFound))
+
+	assert.Equal(t, []string{
+		"POST /api/v1/auth/login",
+		"GET /api/v1/teachers?search=budi",
+		"GET /health",
+		"GET /api/v1/archives/a%2F1",
+	}, api.requests)
+}
+
+func TestClientRefreshesTokens(t *testing.T) {
+	api := &fakeAPI{}
+	clock := time.Date(2026, 10, 1, 7, 0, 0, 0, time.UTC)
+	c := newTestClient(t, api, func() time.Time { return clock })
+	ctx := context.Background()
+	_, err := c.Login(ctx, "admin@sma.sch.id", "secret")
+	require.NoError(t, err)
+
+	// The server forgot the token, so the 401 is answered with a refresh and a retry.
+	api.issued++
+	_, _, err = c.ListTeachers(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, api.refreshes)
+
+	// Close to expiry the token is renewed before the request goes out.
+	clock = clock.Add(890 * time.Second)
+	_, _, err = c.ListTeachers(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, api.refreshes)
+	assert.Equal(t, "POST /api/v1/auth/refresh", api.requests[len(api.requests)-2])
+}
+
+func TestClientUploadsForms(t *testing.T) {
+	api := &fakeAPI{}
+	c := newTestClient(t, api, nil)
+	_, err := c.Login(context.Background(), "admin@sma.sch.id", "secret")
+	require.NoError(t, err)
+
+	resp, err := c.PostArchives(context.Background(), Form{
+		Fields:   map[string]string{"title": "Rapor"},
+		FileName: "rapor.pdf",
+		File:     strings.NewReader("%PDF"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var out map[string]string
+	require.NoError(t, resp.Decode(&out))
+	assert.Equal(t, map[string]string{"title": "Rapor", "file": "rapor.pdf", "content": "%PDF"}, out)
+}
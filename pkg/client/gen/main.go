@@ -0,0 +1,202 @@
+// Command gen writes pkg/client/generated.go from the OpenAPI document in api/swagger, one method
+// per operation. Run it through go generate after changing the document.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/swaggo/swag"
+
+	_ "github.com/noah-isme/sma-adp-api/api/swagger"
+)
+
+// rootPrefixes are served outside the API prefix.
+var rootPrefixes = []string{"/health", "/ready", "/version", "/internal/"}
+
+var methodOrder = []string{"get", "post", "put", "patch", "delete"}
+
+type spec struct {
+	Paths map[string]map[string]struct {
+		Summary    string `json:"summary"`
+		Parameters []struct {
+			In   string `json:"in"`
+			Name string `json:"name"`
+		} `json:"parameters"`
+	} `json:"paths"`
+}
+
+type operation struct {
+	Name    string
+	Method  string
+	Path    string
+	Summary string
+	Args    []string
+	PathArg string
+	Root    bool
+	Query   bool
+	Body    bool
+	Form    bool
+	Headers []string
+}
+
+func main() {
+	out := flag.String("out", "generated.go", "file to write")
+	flag.Parse()
+
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		log.Fatalf("read openapi document: %v", err)
+	}
+	src, err := generate([]byte(doc))
+	if err != nil {
+		log.Fatalf("generate client: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
+
+func generate(doc []byte) ([]byte, error) {
+	var s spec
+	if err := json.Unmarshal(doc, &s); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	paths := make([]string, 0, len(s.Paths))
+	for path := range s.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	names := map[string]string{}
+	for _, path := range paths {
+		for _, method := range methodOrder {
+			item, ok := s.Paths[path][method]
+			if !ok {
+				continue
+			}
+			op := operation{
+				Method:  strings.ToUpper(method),
+				Path:    path,
+				Summary: strings.TrimSuffix(strings.TrimSpace(item.Summary), "."),
+				Root:    isRoot(path),
+			}
+			op.Name, op.Args, op.PathArg = describePath(method, path)
+			for _, param := range item.Parameters {
+				switch param.In {
+				case "query":
+					op.Query = true
+				case "body":
+					op.Body = true
+				case "formData":
+					op.Form = true
+				case "header":
+					op.Headers = append(op.Headers, param.Name)
+				}
+			}
+			if previous, taken := names[op.Name]; taken {
+				return nil, fmt.Errorf("%s %s and %s both map to %s", op.Method, path, previous, op.Name)
+			}
+			names[op.Name] = op.Method + " " + path
+			ops = append(ops, op)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, ops); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func isRoot(path string) bool {
+	for _, prefix := range rootPrefixes {
+		if path == strings.TrimSuffix(prefix, "/") || strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// describePath names the method after the verb and the static path segments, adding "By<Param>"
+// when the path ends in a parameter, and builds the Go expression for the path.
+func describePath(method, path string) (name string, args []string, expr string) {
+	name = exported(method)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var parts []string
+	literal := ""
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			param := exported(strings.Trim(segment, "{}"))
+			arg := unexported(param)
+			args = append(args, arg)
+			parts = append(parts, fmt.Sprintf("%q", literal+"/"), "url.PathEscape("+arg+")")
+			literal = ""
+			if i == len(segments)-1 {
+				name += "By" + param
+			}
+			continue
+		}
+		literal += "/" + segment
+		name += exported(segment)
+	}
+	if literal != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal))
+	}
+	return name, args, strings.Join(parts, " + ")
+}
+
+// exported turns "report-card", "studentId" or "id" into ReportCard, StudentID and ID.
+func exported(raw string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(raw, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	out := b.String()
+	if out == "Id" || strings.HasSuffix(out, "Id") {
+		out = strings.TrimSuffix(out, "Id") + "ID"
+	}
+	return out
+}
+
+func unexported(name string) string {
+	if name == "ID" {
+		return "id"
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+var fileTemplate = template.Must(template.New("client").Funcs(template.FuncMap{
+	"join":        strings.Join,
+	"methodConst": func(method string) string { return exported(strings.ToLower(method)) },
+}).Parse(`// Code generated by go run ./gen; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+{{range .}}
+// {{.Name}} calls {{.Method}} {{.Path}}{{if .Summary}}: {{.Summary}}{{end}}.{{if .Headers}}
+// Send {{join .Headers ", "}} with WithHeader.{{end}}
+func (c *Client) {{.Name}}(ctx context.Context{{range .Args}}, {{.}} string{{end}}{{if .Query}}, query url.Values{{end}}{{if .Body}}, body interface{}{{end}}{{if .Form}}, form Form{{end}}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.Method{{.Method | methodConst}}, path: {{.PathArg}}{{if .Root}}, root: true{{end}}{{if .Query}}, query: query{{end}}{{if .Body}}, body: body{{end}}{{if .Form}}, form: &form{{end}}}
+	return c.do(ctx, req, opts...)
+}
+{{end}}`))
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
+)
+
+func TestGeneratedClientIsUpToDate(t *testing.T) {
+	doc, err := swag.ReadDoc()
+	require.NoError(t, err)
+	want, err := generate([]byte(doc))
+	require.NoError(t, err)
+	got, err := os.ReadFile("../generated.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "pkg/client/generated.go is stale; run go generate ./pkg/client")
+}
+
+func TestDescribePath(t *testing.T) {
+	name, args, expr := describePath("delete", "/teachers/{id}/assignments/{aid}")
+	assert.Equal(t, "DeleteTeachersAssignmentsByAid", name)
+	assert.Equal(t, []string{"id", "aid"}, args)
+	assert.Equal(t, `"/teachers/" + url.PathEscape(id) + "/assignments/" + url.PathEscape(aid)`, expr)
+
+	name, args, expr = describePath("get", "/guardian/students/{studentId}/report-card")
+	assert.Equal(t, "GetGuardianStudentsReportCard", name)
+	assert.Equal(t, []string{"studentID"}, args)
+	assert.Equal(t, `"/guardian/students/" + url.PathEscape(studentID) + "/report-card"`, expr)
+}
@@ -0,0 +1,1129 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GetAlertRules calls GET /alert-rules: List alert rules.
+func (c *Client) GetAlertRules(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/alert-rules", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAlertRules calls POST /alert-rules: Create an alert rule, evaluated from the next run on.
+func (c *Client) PostAlertRules(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/alert-rules", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAlertRulesBreaches calls GET /alert-rules/breaches: List open alert rule breaches; teachers only see their classes and rules addressed to teachers.
+func (c *Client) GetAlertRulesBreaches(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/alert-rules/breaches", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAlertRulesEvaluate calls POST /alert-rules/evaluate: Evaluate the alert rules now instead of waiting for the scheduled run.
+func (c *Client) PostAlertRulesEvaluate(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/alert-rules/evaluate"}
+	return c.do(ctx, req, opts...)
+}
+
+// PutAlertRulesByID calls PUT /alert-rules/{id}: Replace an alert rule.
+func (c *Client) PutAlertRulesByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/alert-rules/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteAlertRulesByID calls DELETE /alert-rules/{id}: Delete an alert rule and its open breaches.
+func (c *Client) DeleteAlertRulesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/alert-rules/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAnnouncementsBroadcast calls POST /announcements/broadcast: Broadcast an announcement to users by role.
+func (c *Client) PostAnnouncementsBroadcast(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/announcements/broadcast", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAnnouncementsAck calls POST /announcements/{id}/ack: Acknowledge an announcement sent to the caller.
+func (c *Client) PostAnnouncementsAck(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/announcements/" + url.PathEscape(id) + "/ack"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAnnouncementsAcks calls GET /announcements/{id}/acks: List who acknowledged an announcement and who has not yet.
+func (c *Client) GetAnnouncementsAcks(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/announcements/" + url.PathEscape(id) + "/acks", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchives calls GET /archives: List archives.
+func (c *Client) GetArchives(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostArchives calls POST /archives: Upload archive document.
+func (c *Client) PostArchives(ctx context.Context, form Form, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/archives", form: &form}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesRetention calls GET /archives/retention: List archives due for retention deletion.
+func (c *Client) GetArchivesRetention(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/retention", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesTrash calls GET /archives/trash: List soft-deleted archives.
+func (c *Client) GetArchivesTrash(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/trash", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesUsage calls GET /archives/usage: Archive storage usage and quotas.
+func (c *Client) GetArchivesUsage(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/usage"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesByID calls GET /archives/{id}: Get archive metadata.
+func (c *Client) GetArchivesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteArchivesByID calls DELETE /archives/{id}: Soft delete archive.
+func (c *Client) DeleteArchivesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/archives/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesDownload calls GET /archives/{id}/download: Download archive file.
+func (c *Client) GetArchivesDownload(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/" + url.PathEscape(id) + "/download", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetArchivesPreview calls GET /archives/{id}/preview: Archive first-page thumbnail.
+func (c *Client) GetArchivesPreview(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/archives/" + url.PathEscape(id) + "/preview"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostArchivesRestore calls POST /archives/{id}/restore: Restore a soft-deleted archive.
+func (c *Client) PostArchivesRestore(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/archives/" + url.PathEscape(id) + "/restore"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAttendanceAbsenceMessages calls GET /attendance/absence-messages: Absence messages sent to guardians.
+func (c *Client) GetAttendanceAbsenceMessages(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/attendance/absence-messages", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAttendanceAlerts calls GET /attendance/alerts: List students below their class attendance threshold at the last evaluation.
+func (c *Client) GetAttendanceAlerts(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/attendance/alerts", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAttendanceAlertsEvaluate calls POST /attendance/alerts/evaluate: Evaluate attendance thresholds now instead of waiting for the nightly run.
+func (c *Client) PostAttendanceAlertsEvaluate(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/attendance/alerts/evaluate"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAttendanceCheckin calls POST /attendance/checkin: Record a gate check-in from a device.
+// Send X-Device-Key with WithHeader.
+func (c *Client) PostAttendanceCheckin(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/attendance/checkin", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAttendanceCheckinQrByStudentID calls GET /attendance/checkin/qr/{studentId}: Issue a signed check-in QR token for a student card.
+func (c *Client) GetAttendanceCheckinQrByStudentID(ctx context.Context, studentID string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/attendance/checkin/qr/" + url.PathEscape(studentID)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutAttendanceExcuses calls PUT /attendance/excuses: Record the reason and evidence for an absence.
+func (c *Client) PutAttendanceExcuses(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/attendance/excuses", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAttendanceImports calls POST /attendance/imports: Queue a bulk daily attendance import.
+func (c *Client) PostAttendanceImports(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/attendance/imports", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAttendanceImportsByID calls GET /attendance/imports/{id}: Get attendance import status and per-row conflicts.
+func (c *Client) GetAttendanceImportsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/attendance/imports/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAttendanceThresholds calls GET /attendance/thresholds: List attendance alert thresholds of a term.
+func (c *Client) GetAttendanceThresholds(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/attendance/thresholds", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PutAttendanceThresholds calls PUT /attendance/thresholds: Set the attendance alert threshold of a class.
+func (c *Client) PutAttendanceThresholds(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/attendance/thresholds", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteAttendanceThresholdsByClassID calls DELETE /attendance/thresholds/{classId}: Remove a class threshold so the default applies.
+func (c *Client) DeleteAttendanceThresholdsByClassID(ctx context.Context, classID string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/attendance/thresholds/" + url.PathEscape(classID), query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAuthMePermissions calls GET /auth/me/permissions: Routes the caller's token may use.
+func (c *Client) GetAuthMePermissions(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/auth/me/permissions"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetAuthSessions calls GET /auth/sessions: List the caller's live sessions.
+func (c *Client) GetAuthSessions(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/auth/sessions"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostAuthSessionsHeartbeat calls POST /auth/sessions/heartbeat: Record activity on the caller's session.
+func (c *Client) PostAuthSessionsHeartbeat(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/auth/sessions/heartbeat"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostCalendarEventsAttendance calls POST /calendar/events/{id}/attendance: Check an attendee in on the event day.
+func (c *Client) PostCalendarEventsAttendance(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/calendar/events/" + url.PathEscape(id) + "/attendance", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetCalendarEventsAttendanceExport calls GET /calendar/events/{id}/attendance/export: Export the attendance list of a calendar event.
+func (c *Client) GetCalendarEventsAttendanceExport(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/calendar/events/" + url.PathEscape(id) + "/attendance/export", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PutCalendarEventsRsvp calls PUT /calendar/events/{id}/rsvp: RSVP to a calendar event.
+func (c *Client) PutCalendarEventsRsvp(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/calendar/events/" + url.PathEscape(id) + "/rsvp", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PutCalendarEventsRsvpOptions calls PUT /calendar/events/{id}/rsvp-options: Open or close RSVPs for a calendar event.
+func (c *Client) PutCalendarEventsRsvpOptions(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/calendar/events/" + url.PathEscape(id) + "/rsvp-options", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetCalendarEventsRsvps calls GET /calendar/events/{id}/rsvps: List the RSVPs and check-ins of a calendar event.
+func (c *Client) GetCalendarEventsRsvps(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/calendar/events/" + url.PathEscape(id) + "/rsvps"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetClassesTimetable calls GET /classes/{id}/timetable: Get a class's effective timetable for one week.
+func (c *Client) GetClassesTimetable(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/classes/" + url.PathEscape(id) + "/timetable", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetCurriculumCoverage calls GET /curriculum/coverage: Syllabus coverage (planned vs. taught) per class and subject.
+func (c *Client) GetCurriculumCoverage(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/curriculum/coverage", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetCurriculumProgress calls GET /curriculum/progress: List a subject's topics with a class's taught marks.
+func (c *Client) GetCurriculumProgress(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/curriculum/progress", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetCurriculumTopics calls GET /curriculum/topics: List the syllabus topics of a subject in a term.
+func (c *Client) GetCurriculumTopics(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/curriculum/topics", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostCurriculumTopics calls POST /curriculum/topics: Add a syllabus topic planned for a week of the term.
+func (c *Client) PostCurriculumTopics(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/curriculum/topics", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PutCurriculumTopicsByID calls PUT /curriculum/topics/{id}: Update a syllabus topic.
+func (c *Client) PutCurriculumTopicsByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/curriculum/topics/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteCurriculumTopicsByID calls DELETE /curriculum/topics/{id}: Delete a syllabus topic and its progress marks.
+func (c *Client) DeleteCurriculumTopicsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/curriculum/topics/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostCurriculumTopicsProgress calls POST /curriculum/topics/{id}/progress: Mark a topic as taught to a class.
+func (c *Client) PostCurriculumTopicsProgress(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/curriculum/topics/" + url.PathEscape(id) + "/progress", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteCurriculumTopicsProgressByClassID calls DELETE /curriculum/topics/{id}/progress/{classId}: Clear a topic's taught mark for a class.
+func (c *Client) DeleteCurriculumTopicsProgressByClassID(ctx context.Context, id string, classID string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/curriculum/topics/" + url.PathEscape(id) + "/progress/" + url.PathEscape(classID)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetDashboard calls GET /dashboard: Admin dashboard summary.
+func (c *Client) GetDashboard(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/dashboard", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetDashboardAcademics calls GET /dashboard/academics: Teacher academics dashboard.
+func (c *Client) GetDashboardAcademics(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/dashboard/academics", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetElectives calls GET /electives: List a term's elective offerings.
+func (c *Client) GetElectives(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/electives", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostElectives calls POST /electives: Create an elective offering with its sections.
+func (c *Client) PostElectives(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/electives", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostElectivesAllocate calls POST /electives/allocate: Allocate students to elective sections.
+func (c *Client) PostElectivesAllocate(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/electives/allocate", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetElectivesAllocation calls GET /electives/allocation: Report the stored elective allocation of a category.
+func (c *Client) GetElectivesAllocation(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/electives/allocation", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetElectivesSelections calls GET /electives/selections: List ranked elective choices.
+func (c *Client) GetElectivesSelections(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/electives/selections", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PutElectivesSelections calls PUT /electives/selections: Rank elective offerings of a category.
+func (c *Client) PutElectivesSelections(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/electives/selections", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetElectivesByID calls GET /electives/{id}: Get an elective offering.
+func (c *Client) GetElectivesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/electives/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutElectivesByID calls PUT /electives/{id}: Update an elective offering and its sections.
+func (c *Client) PutElectivesByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/electives/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteElectivesByID calls DELETE /electives/{id}: Delete an elective offering without allocated students.
+func (c *Client) DeleteElectivesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/electives/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetExamPeriods calls GET /exam-periods: List exam periods of a term.
+func (c *Client) GetExamPeriods(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/exam-periods", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostExamPeriods calls POST /exam-periods: Create an exam period within a term.
+func (c *Client) PostExamPeriods(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/exam-periods", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteExamPeriodsByID calls DELETE /exam-periods/{id}: Delete an exam period and its sittings.
+func (c *Client) DeleteExamPeriodsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/exam-periods/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetExamPeriodsExport calls GET /exam-periods/{id}/export: Export the exam timetable of a class or room.
+func (c *Client) GetExamPeriodsExport(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/exam-periods/" + url.PathEscape(id) + "/export", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostExamPeriodsGenerate calls POST /exam-periods/{id}/generate: Generate the exam timetable, reporting exams that could not be placed.
+func (c *Client) PostExamPeriodsGenerate(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/exam-periods/" + url.PathEscape(id) + "/generate", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetExamPeriodsSchedules calls GET /exam-periods/{id}/schedules: List the sittings of an exam period.
+func (c *Client) GetExamPeriodsSchedules(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/exam-periods/" + url.PathEscape(id) + "/schedules", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostExamPeriodsSchedules calls POST /exam-periods/{id}/schedules: Place a sitting, rejecting clashes with exams, regular lessons and invigilator availability.
+func (c *Client) PostExamPeriodsSchedules(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/exam-periods/" + url.PathEscape(id) + "/schedules", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteExamPeriodsSchedulesByExamID calls DELETE /exam-periods/{id}/schedules/{examId}: Remove a sitting from an exam period.
+func (c *Client) DeleteExamPeriodsSchedulesByExamID(ctx context.Context, id string, examID string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/exam-periods/" + url.PathEscape(id) + "/schedules/" + url.PathEscape(examID)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetExportByToken calls GET /export/{token}: Download report using signed token.
+func (c *Client) GetExportByToken(ctx context.Context, token string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/export/" + url.PathEscape(token)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGradeAppeals calls GET /grade-appeals: List grade appeals visible to the caller.
+func (c *Client) GetGradeAppeals(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/grade-appeals", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGradeAppealsByID calls GET /grade-appeals/{id}: Get a grade appeal.
+func (c *Client) GetGradeAppealsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/grade-appeals/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradeAppealsRespond calls POST /grade-appeals/{id}/respond: Record the subject teacher's recommendation.
+func (c *Client) PostGradeAppealsRespond(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grade-appeals/" + url.PathEscape(id) + "/respond", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradeComponents calls POST /grade-components: Create a grade component.
+func (c *Client) PostGradeComponents(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grade-components", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradeConfigs calls POST /grade-configs: Create a grade calculation config for a class, subject and term.
+func (c *Client) PostGradeConfigs(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grade-configs", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradesFinalizeClass calls POST /grades/finalize-class: Finalize final grades for every subject of a class.
+func (c *Client) PostGradesFinalizeClass(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grades/finalize-class", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradesRemedial calls POST /grades/remedial: Record a remedial score.
+func (c *Client) PostGradesRemedial(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grades/remedial", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGradesUnfinalize calls POST /grades/unfinalize: Request reopening of finalized grades.
+func (c *Client) PostGradesUnfinalize(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/grades/unfinalize", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardianAnnouncements calls GET /guardian/announcements: Announcements for students and the children's classes.
+func (c *Client) GetGuardianAnnouncements(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardian/announcements", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardianCalendar calls GET /guardian/calendar: School calendar for the guardian's children.
+func (c *Client) GetGuardianCalendar(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardian/calendar", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardianStudents calls GET /guardian/students: List the authenticated guardian's children.
+func (c *Client) GetGuardianStudents(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardian/students"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardianStudentsAttendance calls GET /guardian/students/{studentId}/attendance: Attendance history for one of the guardian's children.
+func (c *Client) GetGuardianStudentsAttendance(ctx context.Context, studentID string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardian/students/" + url.PathEscape(studentID) + "/attendance", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGuardianStudentsGradeAppeals calls POST /guardian/students/{studentId}/grade-appeals: Appeal a final grade of a linked student.
+func (c *Client) PostGuardianStudentsGradeAppeals(ctx context.Context, studentID string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/guardian/students/" + url.PathEscape(studentID) + "/grade-appeals", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardianStudentsReportCard calls GET /guardian/students/{studentId}/report-card: Report card for one of the guardian's children.
+func (c *Client) GetGuardianStudentsReportCard(ctx context.Context, studentID string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardian/students/" + url.PathEscape(studentID) + "/report-card", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardiansContact calls GET /guardians/{id}/contact: Phone a guardian is messaged on.
+func (c *Client) GetGuardiansContact(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardians/" + url.PathEscape(id) + "/contact"}
+	return c.do(ctx, req, opts...)
+}
+
+// PutGuardiansContact calls PUT /guardians/{id}/contact: Set the phone a guardian is messaged on.
+func (c *Client) PutGuardiansContact(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/guardians/" + url.PathEscape(id) + "/contact", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteGuardiansContact calls DELETE /guardians/{id}/contact: Forget a guardian's phone.
+func (c *Client) DeleteGuardiansContact(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/guardians/" + url.PathEscape(id) + "/contact"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetGuardiansStudents calls GET /guardians/{id}/students: List students linked to a guardian.
+func (c *Client) GetGuardiansStudents(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/guardians/" + url.PathEscape(id) + "/students"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostGuardiansStudents calls POST /guardians/{id}/students: Link a student to a guardian.
+func (c *Client) PostGuardiansStudents(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/guardians/" + url.PathEscape(id) + "/students", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteGuardiansStudentsByStudentID calls DELETE /guardians/{id}/students/{studentId}: Remove a guardian's access to a student.
+func (c *Client) DeleteGuardiansStudentsByStudentID(ctx context.Context, id string, studentID string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/guardians/" + url.PathEscape(id) + "/students/" + url.PathEscape(studentID)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetHealth calls GET /health: Health check.
+func (c *Client) GetHealth(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/health", root: true}
+	return c.do(ctx, req, opts...)
+}
+
+// GetIntake calls GET /intake: List a term's intake applications.
+func (c *Client) GetIntake(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/intake", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostIntake calls POST /intake: Record a transfer-in application.
+func (c *Client) PostIntake(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/intake", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetIntakeByID calls GET /intake/{id}: Get an intake application with its document checklist.
+func (c *Client) GetIntakeByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/intake/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutIntakeByID calls PUT /intake/{id}: Correct an undecided intake application.
+func (c *Client) PutIntakeByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/intake/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostIntakeDecision calls POST /intake/{id}/decision: Accept, waitlist or reject an intake application.
+func (c *Client) PostIntakeDecision(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/intake/" + url.PathEscape(id) + "/decision", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostIntakeDocuments calls POST /intake/{id}/documents: Upload a checklist document for an intake application.
+func (c *Client) PostIntakeDocuments(ctx context.Context, id string, form Form, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/intake/" + url.PathEscape(id) + "/documents", form: &form}
+	return c.do(ctx, req, opts...)
+}
+
+// GetInternalBackups calls GET /internal/backups: List the most recent backups with their status.
+func (c *Client) GetInternalBackups(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/internal/backups", root: true}
+	return c.do(ctx, req, opts...)
+}
+
+// PostInternalBackups calls POST /internal/backups: Queue an encrypted database backup.
+func (c *Client) PostInternalBackups(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/internal/backups", root: true}
+	return c.do(ctx, req, opts...)
+}
+
+// GetInternalBackupsByID calls GET /internal/backups/{id}: Get a backup.
+func (c *Client) GetInternalBackupsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/internal/backups/" + url.PathEscape(id), root: true}
+	return c.do(ctx, req, opts...)
+}
+
+// PostInternalSyncAttendance calls POST /internal/sync/attendance: Upsert daily attendance written by the legacy app.
+// Send X-API-Key, Idempotency-Key with WithHeader.
+func (c *Client) PostInternalSyncAttendance(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/internal/sync/attendance", root: true, body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostInternalSyncGrades calls POST /internal/sync/grades: Upsert grades written by the legacy app.
+// Send X-API-Key, Idempotency-Key with WithHeader.
+func (c *Client) PostInternalSyncGrades(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/internal/sync/grades", root: true, body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetInternalSyncReconciliation calls GET /internal/sync/reconciliation: Summarise legacy sync batches and the records they rejected.
+// Send X-API-Key with WithHeader.
+func (c *Client) GetInternalSyncReconciliation(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/internal/sync/reconciliation", root: true, query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonGroups calls GET /lesson-groups: List a term's lesson groups.
+func (c *Client) GetLessonGroups(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-groups", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonGroups calls POST /lesson-groups: Create a lesson group.
+func (c *Client) PostLessonGroups(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-groups", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonGroupsByID calls GET /lesson-groups/{id}: Get a lesson group.
+func (c *Client) GetLessonGroupsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-groups/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutLessonGroupsByID calls PUT /lesson-groups/{id}: Update a lesson group's name, room and members.
+func (c *Client) PutLessonGroupsByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/lesson-groups/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteLessonGroupsByID calls DELETE /lesson-groups/{id}: Delete a lesson group without sessions.
+func (c *Client) DeleteLessonGroupsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/lesson-groups/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonGroupsAttendance calls GET /lesson-groups/{id}/attendance: Report attendance of a lesson group session.
+func (c *Client) GetLessonGroupsAttendance(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-groups/" + url.PathEscape(id) + "/attendance", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonGroupsAttendance calls POST /lesson-groups/{id}/attendance: Mark attendance for a lesson group session.
+func (c *Client) PostLessonGroupsAttendance(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-groups/" + url.PathEscape(id) + "/attendance", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonGroupsRoster calls GET /lesson-groups/{id}/roster: List the students taking part in a lesson group.
+func (c *Client) GetLessonGroupsRoster(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-groups/" + url.PathEscape(id) + "/roster"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonGroupsSessions calls GET /lesson-groups/{id}/sessions: List a lesson group's weekly sessions.
+func (c *Client) GetLessonGroupsSessions(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-groups/" + url.PathEscape(id) + "/sessions"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonGroupsSessions calls POST /lesson-groups/{id}/sessions: Schedule a weekly session for every member class.
+func (c *Client) PostLessonGroupsSessions(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-groups/" + url.PathEscape(id) + "/sessions", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteLessonGroupsSessions calls DELETE /lesson-groups/{id}/sessions: Remove a weekly session from every member class.
+func (c *Client) DeleteLessonGroupsSessions(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/lesson-groups/" + url.PathEscape(id) + "/sessions", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonPlans calls GET /lesson-plans: List lesson plans (teachers only see their own).
+func (c *Client) GetLessonPlans(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-plans", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonPlans calls POST /lesson-plans: Draft a weekly lesson plan.
+func (c *Client) PostLessonPlans(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-plans", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetLessonPlansByID calls GET /lesson-plans/{id}: Get a lesson plan.
+func (c *Client) GetLessonPlansByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/lesson-plans/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutLessonPlansByID calls PUT /lesson-plans/{id}: Edit a draft or rejected lesson plan.
+func (c *Client) PutLessonPlansByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/lesson-plans/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteLessonPlansByID calls DELETE /lesson-plans/{id}: Delete a draft or rejected lesson plan.
+func (c *Client) DeleteLessonPlansByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/lesson-plans/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonPlansAttachment calls POST /lesson-plans/{id}/attachment: Attach a document to a draft or rejected lesson plan.
+func (c *Client) PostLessonPlansAttachment(ctx context.Context, id string, form Form, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-plans/" + url.PathEscape(id) + "/attachment", form: &form}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonPlansReview calls POST /lesson-plans/{id}/review: Approve or reject a submitted lesson plan.
+func (c *Client) PostLessonPlansReview(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-plans/" + url.PathEscape(id) + "/review", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostLessonPlansSubmit calls POST /lesson-plans/{id}/submit: Submit a lesson plan for review.
+func (c *Client) PostLessonPlansSubmit(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/lesson-plans/" + url.PathEscape(id) + "/submit"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetMessagingCallbacksByProvider calls GET /messaging/callbacks/{provider}: Callback URL handshake of a messaging provider.
+func (c *Client) GetMessagingCallbacksByProvider(ctx context.Context, provider string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/messaging/callbacks/" + url.PathEscape(provider), query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostMessagingCallbacksByProvider calls POST /messaging/callbacks/{provider}: Delivery status callback of a messaging provider.
+func (c *Client) PostMessagingCallbacksByProvider(ctx context.Context, provider string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/messaging/callbacks/" + url.PathEscape(provider)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetMutations calls GET /mutations: List mutation requests.
+func (c *Client) GetMutations(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/mutations", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostMutations calls POST /mutations: Submit mutation request.
+func (c *Client) PostMutations(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/mutations", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetMutationsByID calls GET /mutations/{id}: Get mutation detail.
+func (c *Client) GetMutationsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/mutations/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostMutationsAttachments calls POST /mutations/{id}/attachments: Attach supporting evidence to a pending mutation.
+func (c *Client) PostMutationsAttachments(ctx context.Context, id string, form Form, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/mutations/" + url.PathEscape(id) + "/attachments", form: &form}
+	return c.do(ctx, req, opts...)
+}
+
+// PostMutationsReview calls POST /mutations/{id}/review: Review mutation request.
+func (c *Client) PostMutationsReview(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/mutations/" + url.PathEscape(id) + "/review", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetNotifications calls GET /notifications: List the caller's notifications.
+func (c *Client) GetNotifications(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/notifications", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostNotificationsDevices calls POST /notifications/devices: Register a device for push notifications.
+func (c *Client) PostNotificationsDevices(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/notifications/devices", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteNotificationsDevicesByToken calls DELETE /notifications/devices/{token}: Stop push notifications to a device.
+func (c *Client) DeleteNotificationsDevicesByToken(ctx context.Context, token string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/notifications/devices/" + url.PathEscape(token)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostNotificationsRead calls POST /notifications/{id}/read: Mark a notification as read.
+func (c *Client) PostNotificationsRead(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/notifications/" + url.PathEscape(id) + "/read"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetReady calls GET /ready: Readiness check.
+func (c *Client) GetReady(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/ready", root: true}
+	return c.do(ctx, req, opts...)
+}
+
+// PostReportsGenerate calls POST /reports/generate: Queue a new report job.
+func (c *Client) PostReportsGenerate(ctx context.Context, query url.Values, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/reports/generate", query: query, body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetReportsStatusByID calls GET /reports/status/{id}: Get report job status.
+func (c *Client) GetReportsStatusByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/reports/status/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetReportsTemplates calls GET /reports/templates: List export templates.
+func (c *Client) GetReportsTemplates(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/reports/templates"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostReportsTemplates calls POST /reports/templates: Create the export template of a report type.
+func (c *Client) PostReportsTemplates(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/reports/templates", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetReportsTemplatesColumns calls GET /reports/templates/columns: List the columns each report type offers to export templates.
+func (c *Client) GetReportsTemplatesColumns(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/reports/templates/columns"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetReportsTemplatesByID calls GET /reports/templates/{id}: Get an export template.
+func (c *Client) GetReportsTemplatesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/reports/templates/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutReportsTemplatesByID calls PUT /reports/templates/{id}: Replace an export template.
+func (c *Client) PutReportsTemplatesByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/reports/templates/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteReportsTemplatesByID calls DELETE /reports/templates/{id}: Delete an export template so the default layout applies.
+func (c *Client) DeleteReportsTemplatesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/reports/templates/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetRetentionLegalHolds calls GET /retention/legal-holds: List legal holds.
+func (c *Client) GetRetentionLegalHolds(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/retention/legal-holds", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostRetentionLegalHolds calls POST /retention/legal-holds: Place a legal hold on refresh tokens or audit logs.
+func (c *Client) PostRetentionLegalHolds(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/retention/legal-holds", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostRetentionLegalHoldsRelease calls POST /retention/legal-holds/{id}/release: Release a legal hold.
+func (c *Client) PostRetentionLegalHoldsRelease(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/retention/legal-holds/" + url.PathEscape(id) + "/release"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostRetentionPurge calls POST /retention/purge: Purge refresh tokens and audit logs past their retention period.
+func (c *Client) PostRetentionPurge(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/retention/purge", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSchedulePresets calls GET /schedule/presets: List subject load presets.
+func (c *Client) GetSchedulePresets(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/schedule/presets", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostSchedulePresets calls POST /schedule/presets: Create a subject load preset.
+func (c *Client) PostSchedulePresets(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/schedule/presets", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSchedulePresetsByID calls GET /schedule/presets/{id}: Get a subject load preset.
+func (c *Client) GetSchedulePresetsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/schedule/presets/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutSchedulePresetsByID calls PUT /schedule/presets/{id}: Replace a subject load preset.
+func (c *Client) PutSchedulePresetsByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/schedule/presets/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteSchedulePresetsByID calls DELETE /schedule/presets/{id}: Delete a subject load preset.
+func (c *Client) DeleteSchedulePresetsByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/schedule/presets/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostScheduleScore calls POST /schedule/score: Score a hand-built timetable.
+func (c *Client) PostScheduleScore(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/schedule/score", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSchedulesClashes calls GET /schedules/clashes: List double-booked daily schedules of a term.
+func (c *Client) GetSchedulesClashes(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/schedules/clashes", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSchedulesClashesExport calls GET /schedules/clashes/export: Export the daily schedule clash report.
+func (c *Client) GetSchedulesClashesExport(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/schedules/clashes/export", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSchedulesPreferencesExport calls GET /schedules/preferences/export: Download the preference sheet.
+func (c *Client) GetSchedulesPreferencesExport(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/schedules/preferences/export"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostSchedulesPreferencesImport calls POST /schedules/preferences/import: Import a filled preference sheet.
+func (c *Client) PostSchedulesPreferencesImport(ctx context.Context, query url.Values, form Form, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/schedules/preferences/import", query: query, form: &form}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSearch calls GET /search: Search teachers, students, classes, subjects and archives.
+func (c *Client) GetSearch(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/search", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostSemesterScheduleRevalidate calls POST /semester-schedule/{id}/revalidate: Re-check a published semester schedule now.
+func (c *Client) PostSemesterScheduleRevalidate(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/semester-schedule/" + url.PathEscape(id) + "/revalidate"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetSemesterScheduleWarnings calls GET /semester-schedule/{id}/warnings: List conflicts found in a published semester schedule.
+func (c *Client) GetSemesterScheduleWarnings(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/semester-schedule/" + url.PathEscape(id) + "/warnings"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetStudentAttendance calls GET /student/attendance: Attendance history of the authenticated student.
+func (c *Client) GetStudentAttendance(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/student/attendance", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostStudentGradeAppeals calls POST /student/grade-appeals: Appeal one of the authenticated student's final grades.
+func (c *Client) PostStudentGradeAppeals(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/student/grade-appeals", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetStudentReportCard calls GET /student/report-card: Report card of the authenticated student.
+func (c *Client) GetStudentReportCard(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/student/report-card", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetStudentSchedule calls GET /student/schedule: Timetable of the authenticated student's active class.
+func (c *Client) GetStudentSchedule(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/student/schedule"}
+	return c.do(ctx, req, opts...)
+}
+
+// PostStudentsAccount calls POST /students/{id}/account: Create the STUDENT login for a student.
+func (c *Client) PostStudentsAccount(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/students/" + url.PathEscape(id) + "/account", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherAttendanceCheckin calls POST /teacher-attendance/checkin: Clock in the authenticated teacher.
+func (c *Client) PostTeacherAttendanceCheckin(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-attendance/checkin", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherAttendanceCheckout calls POST /teacher-attendance/checkout: Clock out the authenticated teacher.
+func (c *Client) PostTeacherAttendanceCheckout(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-attendance/checkout", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherAttendanceRecap calls GET /teacher-attendance/recap: Monthly presence recap for all active teachers.
+func (c *Client) GetTeacherAttendanceRecap(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-attendance/recap", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherAttendanceTeachersByID calls GET /teacher-attendance/teachers/{id}: Monthly presence log for one teacher.
+func (c *Client) GetTeacherAttendanceTeachersByID(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-attendance/teachers/" + url.PathEscape(id), query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherLeaves calls GET /teacher-leaves: List teacher leaves.
+func (c *Client) GetTeacherLeaves(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-leaves", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherLeaves calls POST /teacher-leaves: Request leave for a teacher.
+func (c *Client) PostTeacherLeaves(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-leaves", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherLeavesBalance calls GET /teacher-leaves/balance: Leave balance per teacher for a year.
+func (c *Client) GetTeacherLeavesBalance(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-leaves/balance", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherLeavesByID calls GET /teacher-leaves/{id}: Get a teacher leave.
+func (c *Client) GetTeacherLeavesByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-leaves/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherLeavesApprove calls POST /teacher-leaves/{id}/approve: Approve a pending teacher leave.
+func (c *Client) PostTeacherLeavesApprove(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-leaves/" + url.PathEscape(id) + "/approve", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherLeavesCancel calls POST /teacher-leaves/{id}/cancel: Cancel a teacher leave.
+func (c *Client) PostTeacherLeavesCancel(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-leaves/" + url.PathEscape(id) + "/cancel", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeacherLeavesReject calls POST /teacher-leaves/{id}/reject: Reject a pending teacher leave.
+func (c *Client) PostTeacherLeavesReject(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teacher-leaves/" + url.PathEscape(id) + "/reject", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeacherLeavesSubstitutions calls GET /teacher-leaves/{id}/substitutions: List the lessons a leave affects with substitution suggestions.
+func (c *Client) GetTeacherLeavesSubstitutions(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teacher-leaves/" + url.PathEscape(id) + "/substitutions"}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeachers calls GET /teachers: List teachers.
+func (c *Client) GetTeachers(ctx context.Context, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teachers", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeachers calls POST /teachers: Create teacher.
+func (c *Client) PostTeachers(ctx context.Context, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teachers", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeachersByID calls GET /teachers/{id}: Get teacher.
+func (c *Client) GetTeachersByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teachers/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// PutTeachersByID calls PUT /teachers/{id}: Update teacher.
+func (c *Client) PutTeachersByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/teachers/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// PatchTeachersByID calls PATCH /teachers/{id}: Partially update teacher.
+func (c *Client) PatchTeachersByID(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPatch, path: "/teachers/" + url.PathEscape(id), body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteTeachersByID calls DELETE /teachers/{id}: Deactivate teacher.
+func (c *Client) DeleteTeachersByID(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/teachers/" + url.PathEscape(id)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeachersAssignments calls GET /teachers/{id}/assignments: List assignments.
+func (c *Client) GetTeachersAssignments(ctx context.Context, id string, query url.Values, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teachers/" + url.PathEscape(id) + "/assignments", query: query}
+	return c.do(ctx, req, opts...)
+}
+
+// PostTeachersAssignments calls POST /teachers/{id}/assignments: Create assignment.
+func (c *Client) PostTeachersAssignments(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPost, path: "/teachers/" + url.PathEscape(id) + "/assignments", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeachersAssignmentsByAid calls GET /teachers/{id}/assignments/{aid}: Get assignment.
+func (c *Client) GetTeachersAssignmentsByAid(ctx context.Context, id string, aid string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teachers/" + url.PathEscape(id) + "/assignments/" + url.PathEscape(aid)}
+	return c.do(ctx, req, opts...)
+}
+
+// DeleteTeachersAssignmentsByAid calls DELETE /teachers/{id}/assignments/{aid}: Delete assignment.
+func (c *Client) DeleteTeachersAssignmentsByAid(ctx context.Context, id string, aid string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodDelete, path: "/teachers/" + url.PathEscape(id) + "/assignments/" + url.PathEscape(aid)}
+	return c.do(ctx, req, opts...)
+}
+
+// GetTeachersPreferences calls GET /teachers/{id}/preferences: Get preferences.
+func (c *Client) GetTeachersPreferences(ctx context.Context, id string, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/teachers/" + url.PathEscape(id) + "/preferences"}
+	return c.do(ctx, req, opts...)
+}
+
+// PutTeachersPreferences calls PUT /teachers/{id}/preferences: Upsert preferences.
+func (c *Client) PutTeachersPreferences(ctx context.Context, id string, body interface{}, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodPut, path: "/teachers/" + url.PathEscape(id) + "/preferences", body: body}
+	return c.do(ctx, req, opts...)
+}
+
+// GetVersion calls GET /version: Build information.
+func (c *Client) GetVersion(ctx context.Context, opts ...RequestOption) (*Response, error) {
+	req := request{method: http.MethodGet, path: "/version", root: true}
+	return c.do(ctx, req, opts...)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/internal/dto"
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+// Login signs in and keeps the tokens for the following requests.
+func (c *Client) Login(ctx context.Context, email, password string) (*models.LoginResponse, error) {
+	var out models.LoginResponse
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/auth/login", body: models.LoginRequest{Email: email, Password: password}})
+	if err := decode(resp, err, &out); err != nil {
+		return nil, fmt.Errorf("login: %w", err)
+	}
+	c.SetTokens(out.AccessToken, out.RefreshToken, time.Duration(out.ExpiresIn)*time.Second)
+	return &out, nil
+}
+
+// Logout revokes the refresh token and forgets both tokens.
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	refresh := c.refreshToken
+	c.mu.Unlock()
+	if refresh == "" {
+		return nil
+	}
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/auth/logout", body: models.RefreshTokenRequest{RefreshToken: refresh}}); err != nil {
+		return fmt.Errorf("logout: %w", err)
+	}
+	c.mu.Lock()
+	c.accessToken, c.refreshToken, c.expiresAt = "", "", time.Time{}
+	c.mu.Unlock()
+	return nil
+}
+
+// GenerateReport queues a report job, or returns the identical job already queued.
+func (c *Client) GenerateReport(ctx context.Context, req dto.ReportRequest) (*dto.ReportJobResponse, error) {
+	var out dto.ReportJobResponse
+	resp, err := c.PostReportsGenerate(ctx, nil, req)
+	if err := decode(resp, err, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReportStatus reports the progress of a report job and, once finished, its download URL.
+func (c *Client) ReportStatus(ctx context.Context, id string) (*dto.ReportStatusResponse, error) {
+	var out dto.ReportStatusResponse
+	resp, err := c.GetReportsStatusByID(ctx, id)
+	if err := decode(resp, err, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTeachers lists teachers matching query (search, active, termId, page, limit, sort, order).
+func (c *Client) ListTeachers(ctx context.Context, query url.Values) ([]models.Teacher, *models.Pagination, error) {
+	var out []models.Teacher
+	resp, err := c.GetTeachers(ctx, query)
+	if err := decode(resp, err, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, resp.Pagination, nil
+}
+
+// decode unmarshals the data of a successful response into v.
+func decode(resp *Response, err error, v interface{}) error {
+	if err != nil {
+		return err
+	}
+	return resp.Decode(v)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeepsEmptyEnvValues(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("ENV=development\n"), 0o600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("SECURITY_FRAME_OPTIONS", "")
+	t.Setenv("SECURITY_DOCS_CSP", "")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Security.Headers.FrameOptions, "an empty variable disables the header")
+	assert.Empty(t, cfg.Security.Headers.DocsCSP)
+	assert.Equal(t, "strict-origin-when-cross-origin", cfg.Security.Headers.ReferrerPolicy, "unset variables keep their default")
+}
+
+func TestLoadBuildsRouteTimeoutsFromAPIPrefix(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("ENV=development\n"), 0o600))
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("API_PREFIX", "/api/v2")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Server.RouteTimeouts["POST /api/v2/reports/generate"])
+	assert.Contains(t, cfg.Server.RouteTimeouts, "/api/v2/export")
+	assert.NotContains(t, cfg.Server.RouteTimeouts, "/api/v1/archives")
+
+	t.Setenv("REQUEST_TIMEOUT_ROUTES", "")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Server.RouteTimeouts, "an empty variable drops the overrides")
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJWTSecret      = "dev_secret"
+	defaultReportsSecret  = "dev_reports_secret"
+	defaultArchivesSecret = "dev_archives_secret"
+	defaultDBPassword     = "postgres"
+
+	minProductionSecretLength = 32
+)
+
+// knownRoles are the user roles settings may name.
+var knownRoles = map[string]bool{"SUPERADMIN": true, "ADMIN": true, "TEACHER": true, "STUDENT": true, "GUARDIAN": true}
+
+// ValidationError lists every configuration problem found by Validate.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) port(name string, port int) {
+	v.check(port >= 1 && port <= 65535, "%s must be between 1 and 65535, got %d", name, port)
+}
+
+func (v *validator) positive(name string, d time.Duration) {
+	v.check(d > 0, "%s must be a positive duration", name)
+}
+
+// secondary checks an optional rotation secret that verifies tokens alongside primary.
+func (v *validator) secondary(name, value, primary string, production bool) {
+	if value == "" {
+		return
+	}
+	v.check(value != primary, "%s must differ from the primary secret", name)
+	if production {
+		v.check(len(value) >= minProductionSecretLength, "%s must be at least %d characters in production", name, minProductionSecretLength)
+	}
+}
+
+func (v *validator) secret(name, value, devDefault string, production bool) {
+	v.check(value != "", "%s is required", name)
+	if !production || value == "" {
+		return
+	}
+	v.check(value != devDefault, "%s must not use the development default in production", name)
+	v.check(len(value) >= minProductionSecretLength, "%s must be at least %d characters in production", name, minProductionSecretLength)
+}
+
+// Validate checks the loaded configuration and returns a *ValidationError listing every problem.
+// Production adds stricter rules for secrets and credentials.
+func (c *Config) Validate() error {
+	v := &validator{}
+	production := c.Env == EnvProduction
+
+	v.port("PORT", c.Port)
+	v.port("DB_PORT", c.Database.Port)
+	switch c.Redis.Mode {
+	case "", "standalone":
+		v.port("REDIS_PORT", c.Redis.Port)
+	case "sentinel":
+		v.check(c.Redis.MasterName != "", "REDIS_SENTINEL_MASTER is required when REDIS_MODE is sentinel")
+		v.check(len(c.Redis.Addrs) > 0, "REDIS_ADDRS is required when REDIS_MODE is sentinel")
+	case "cluster":
+		v.check(len(c.Redis.Addrs) > 0, "REDIS_ADDRS is required when REDIS_MODE is cluster")
+	default:
+		v.check(false, "REDIS_MODE must be standalone, sentinel or cluster, got %q", c.Redis.Mode)
+	}
+	for name, d := range map[string]time.Duration{
+		"SERVER_READ_TIMEOUT":        c.Server.ReadTimeout,
+		"SERVER_READ_HEADER_TIMEOUT": c.Server.ReadHeaderTimeout,
+		"SERVER_WRITE_TIMEOUT":       c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        c.Server.IdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":    c.Server.ShutdownTimeout,
+		"REQUEST_TIMEOUT":            c.Server.RequestTimeout,
+	} {
+		v.check(d >= 0, "%s must not be negative", name)
+	}
+	for route, budget := range c.Server.RouteTimeouts {
+		v.check(budget >= 0, "REQUEST_TIMEOUT_ROUTES entry %q needs a route and a duration, e.g. \"POST /api/v1/reports/generate=30s\"", route)
+		if budget > 0 && c.Server.WriteTimeout > 0 {
+			v.check(budget <= c.Server.WriteTimeout, "REQUEST_TIMEOUT_ROUTES budget for %q exceeds SERVER_WRITE_TIMEOUT", route)
+		}
+	}
+	v.check(c.Server.WriteTimeout == 0 || c.Server.RequestTimeout <= c.Server.WriteTimeout, "REQUEST_TIMEOUT must not exceed SERVER_WRITE_TIMEOUT")
+	if tls := c.Server.TLS; tls.CertFile != "" || tls.KeyFile != "" || len(tls.ACMEDomains) > 0 {
+		v.check((tls.CertFile == "") == (tls.KeyFile == ""), "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+		v.check(tls.CertFile == "" || len(tls.ACMEDomains) == 0, "TLS_CERT_FILE and TLS_ACME_DOMAINS are mutually exclusive")
+		if len(tls.ACMEDomains) > 0 {
+			v.check(tls.ACMECacheDir != "", "TLS_ACME_CACHE_DIR is required with TLS_ACME_DOMAINS")
+		}
+		if tls.HTTPPort != 0 {
+			v.port("TLS_HTTP_PORT", tls.HTTPPort)
+			v.check(tls.HTTPPort != c.Port, "TLS_HTTP_PORT must differ from PORT")
+		}
+	}
+	if c.Metrics.Port != 0 {
+		v.port("METRICS_PORT", c.Metrics.Port)
+		v.check(c.Metrics.Port != c.Port, "METRICS_PORT must differ from PORT")
+	}
+	v.check((c.Metrics.BasicAuthUser == "") == (c.Metrics.BasicAuthPassword == ""), "METRICS_BASIC_AUTH_USER and METRICS_BASIC_AUTH_PASSWORD must be set together")
+	if production {
+		locked := c.Metrics.Port != 0 || c.Metrics.BasicAuthPassword != "" || len(c.Metrics.AllowedIPs) > 0
+		v.check(locked, "metrics must be protected in production: set METRICS_PORT, METRICS_BASIC_AUTH_* or METRICS_ALLOWED_IPS")
+	}
+	if c.Alerts.PanicWebhookURL != "" {
+		u, err := url.Parse(c.Alerts.PanicWebhookURL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "PANIC_ALERT_WEBHOOK_URL must be an http(s) URL")
+		v.positive("PANIC_ALERT_TIMEOUT", c.Alerts.PanicTimeout)
+	}
+	v.check(c.Database.Host != "", "DB_HOST is required")
+	v.check(c.Database.Name != "", "DB_NAME is required")
+	if production {
+		v.check(c.Database.Password != defaultDBPassword, "DB_PASSWORD must not use the development default in production")
+	}
+
+	v.secret("JWT_SECRET", c.JWT.Secret, defaultJWTSecret, production)
+	v.secondary("JWT_SECRET_SECONDARY", c.JWT.SecondarySecret, c.JWT.Secret, production)
+	v.positive("JWT_EXPIRATION", c.JWT.Expiration)
+	v.positive("REFRESH_TOKEN_EXPIRATION", c.JWT.RefreshExpiration)
+	for role, limit := range c.Sessions.MaxPerRole {
+		v.check(limit > 0, "SESSION_MAX_PER_ROLE entry %q needs a role and a positive limit, e.g. \"STUDENT=2\"", role)
+		v.check(limit < 0 || knownRoles[role], "SESSION_MAX_PER_ROLE names unknown role %q", role)
+	}
+	v.check(c.Sessions.IdleTimeout >= 0, "SESSION_IDLE_TIMEOUT must not be negative")
+	if c.Sessions.IdleTimeout > 0 {
+		v.positive("SESSION_SWEEP_INTERVAL", c.Sessions.SweepInterval)
+	}
+	if c.Retention.Enabled {
+		v.check(c.Retention.RefreshTokens >= 0, "RETENTION_REFRESH_TOKENS must not be negative")
+		v.check(c.Retention.AuditLogs >= 0, "RETENTION_AUDIT_LOGS must not be negative")
+		v.positive("RETENTION_INTERVAL", c.Retention.Interval)
+	}
+
+	if c.Reports.Enabled {
+		v.check(c.Reports.StorageDir != "", "REPORTS_STORAGE_DIR is required when ENABLE_REPORTS is set")
+		v.secret("REPORTS_SIGNED_URL_SECRET", c.Reports.SignedURLSecret, defaultReportsSecret, production)
+		v.secondary("REPORTS_SIGNED_URL_SECRET_SECONDARY", c.Reports.SignedURLSecondarySecret, c.Reports.SignedURLSecret, production)
+		v.positive("REPORTS_SIGNED_URL_TTL", c.Reports.SignedURLTTL)
+		if r := c.Reports; r.WorkerMax > 0 {
+			v.check(r.WorkerMin <= r.WorkerConcurrency && r.WorkerConcurrency <= r.WorkerMax,
+				"REPORTS_WORKER_CONCURRENCY must be between REPORTS_WORKER_MIN and REPORTS_WORKER_MAX, got %d", r.WorkerConcurrency)
+		}
+	}
+	if q := c.Queues; q.Autoscale {
+		v.positive("QUEUE_AUTOSCALE_INTERVAL", q.AutoscaleInterval)
+		v.check(q.JobsPerWorker > 0, "QUEUE_JOBS_PER_WORKER must be positive, got %d", q.JobsPerWorker)
+	}
+	if ev := c.Events; ev.Enabled {
+		switch ev.Broker {
+		case "log":
+		case "nats":
+			u, err := url.Parse(ev.BrokerURL)
+			v.check(err == nil && (u.Scheme == "nats" || u.Scheme == "tls") && u.Host != "", "EVENTS_BROKER_URL must be a nats:// or tls:// URL when EVENTS_BROKER is nats")
+		case "kafka-rest":
+			u, err := url.Parse(ev.BrokerURL)
+			v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "EVENTS_BROKER_URL must be an http(s) URL when EVENTS_BROKER is kafka-rest")
+		default:
+			v.check(false, "EVENTS_BROKER must be log, nats or kafka-rest, got %q", ev.Broker)
+		}
+		v.check(ev.SubjectPrefix == "" || !strings.ContainsAny(ev.SubjectPrefix, " \t*>"), "EVENTS_SUBJECT_PREFIX must not contain spaces or wildcards")
+		v.positive("EVENTS_PUBLISH_TIMEOUT", ev.PublishTimeout)
+		v.positive("EVENTS_POLL_INTERVAL", ev.PollInterval)
+		v.check(ev.ClaimTimeout > ev.PublishTimeout, "EVENTS_CLAIM_TIMEOUT must be longer than EVENTS_PUBLISH_TIMEOUT")
+		if production {
+			v.check(ev.Broker != "log", "EVENTS_BROKER must not be log in production")
+		}
+	}
+	if push := c.Push; push.Enabled {
+		switch push.Provider {
+		case "log":
+		case "fcm":
+			v.check(push.FCMCredentialsFile != "", "PUSH_FCM_CREDENTIALS_FILE is required when PUSH_PROVIDER is fcm")
+		default:
+			v.check(false, "PUSH_PROVIDER must be log or fcm, got %q", push.Provider)
+		}
+		v.positive("PUSH_TIMEOUT", push.Timeout)
+		v.positive("PUSH_POLL_INTERVAL", push.PollInterval)
+		v.positive("PUSH_RETRY_BACKOFF", push.RetryBackoff)
+		v.positive("PUSH_MAX_AGE", push.MaxAge)
+		v.check(push.MaxAttempts > 0, "PUSH_MAX_ATTEMPTS must be positive")
+		if production {
+			v.check(push.Provider != "log", "PUSH_PROVIDER must not be log in production")
+		}
+	}
+	if msg := c.Messaging; msg.Enabled {
+		usesTwilio := msg.SMSProvider == "twilio" || msg.WhatsAppProvider == "twilio"
+		v.check(msg.SMSProvider == "log" || msg.SMSProvider == "twilio", "MESSAGING_SMS_PROVIDER must be log or twilio, got %q", msg.SMSProvider)
+		v.check(msg.WhatsAppProvider == "log" || msg.WhatsAppProvider == "twilio" || msg.WhatsAppProvider == "meta",
+			"MESSAGING_WHATSAPP_PROVIDER must be log, twilio or meta, got %q", msg.WhatsAppProvider)
+		if usesTwilio {
+			v.check(msg.TwilioAccountSID != "" && msg.TwilioAuthToken != "", "TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN are required when a messaging provider is twilio")
+		}
+		if msg.SMSProvider == "twilio" {
+			v.check(msg.TwilioSMSFrom != "", "TWILIO_SMS_FROM is required when MESSAGING_SMS_PROVIDER is twilio")
+		}
+		if msg.WhatsAppProvider == "twilio" {
+			v.check(msg.TwilioWhatsAppFrom != "", "TWILIO_WHATSAPP_FROM is required when MESSAGING_WHATSAPP_PROVIDER is twilio")
+		}
+		if msg.WhatsAppProvider == "meta" {
+			v.check(msg.MetaPhoneNumberID != "" && msg.MetaAccessToken != "", "WHATSAPP_PHONE_NUMBER_ID and WHATSAPP_ACCESS_TOKEN are required when MESSAGING_WHATSAPP_PROVIDER is meta")
+			v.check(msg.MetaTemplate != "", "WHATSAPP_ABSENCE_TEMPLATE is required when MESSAGING_WHATSAPP_PROVIDER is meta")
+			v.check(msg.CallbackURL == "" || msg.MetaAppSecret != "", "WHATSAPP_APP_SECRET is required to verify callbacks when MESSAGING_CALLBACK_URL is set")
+		}
+		if msg.CallbackURL != "" {
+			u, err := url.Parse(msg.CallbackURL)
+			v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "MESSAGING_CALLBACK_URL must be an http(s) URL")
+		}
+		v.check((msg.QuietHoursStart == "") == (msg.QuietHoursEnd == ""), "MESSAGING_QUIET_HOURS_START and MESSAGING_QUIET_HOURS_END must be set together")
+		for _, clock := range [][2]string{{"MESSAGING_QUIET_HOURS_START", msg.QuietHoursStart}, {"MESSAGING_QUIET_HOURS_END", msg.QuietHoursEnd}} {
+			if clock[1] != "" {
+				_, err := time.Parse("15:04", clock[1])
+				v.check(err == nil, "%s must be HH:MM, got %q", clock[0], clock[1])
+			}
+		}
+		v.positive("MESSAGING_TIMEOUT", msg.Timeout)
+		v.positive("MESSAGING_POLL_INTERVAL", msg.PollInterval)
+		v.positive("MESSAGING_RETRY_BACKOFF", msg.RetryBackoff)
+		v.check(msg.MaxAttempts > 0, "MESSAGING_MAX_ATTEMPTS must be positive")
+		if production {
+			v.check(msg.SMSProvider != "log" && msg.WhatsAppProvider != "log", "messaging providers must not be log in production")
+		}
+	}
+	if c.Archives.Enabled {
+		v.check(c.Archives.StorageDir != "", "ARCHIVES_STORAGE_DIR is required when ENABLE_ARCHIVES is set")
+		v.secret("ARCHIVES_SIGNED_URL_SECRET", c.Archives.SignedURLSecret, defaultArchivesSecret, production)
+		v.secondary("ARCHIVES_SIGNED_URL_SECRET_SECONDARY", c.Archives.SignedURLSecondarySecret, c.Archives.SignedURLSecret, production)
+		v.positive("ARCHIVES_SIGNED_URL_TTL", c.Archives.SignedURLTTL)
+	}
+
+	casing := c.Cutover.ResponseCasing
+	v.check(casing == "" || casing == "camel" || casing == "snake", "CUTOVER_RESPONSE_CASING must be camel, snake or empty, got %q", casing)
+	if production {
+		for name, key := range c.Cutover.SyncAPIKeys {
+			v.check(len(key) >= minProductionSecretLength, "CUTOVER_SYNC_API_KEYS key for %s must be at least %d characters in production", name, minProductionSecretLength)
+		}
+	}
+
+	policy := c.Attendance.NonSchoolDayPolicy
+	v.check(policy == "reject" || policy == "warn", "ATTENDANCE_NON_SCHOOL_DAY_POLICY must be reject or warn, got %q", policy)
+	_, err := time.Parse("15:04", c.Attendance.LateAfter)
+	v.check(err == nil, "ATTENDANCE_LATE_AFTER must be HH:MM, got %q", c.Attendance.LateAfter)
+	_, err = time.LoadLocation(c.Attendance.Timezone)
+	v.check(err == nil, "ATTENDANCE_TIMEZONE must be an IANA time zone, got %q", c.Attendance.Timezone)
+	if production {
+		for name, key := range c.Attendance.DeviceKeys {
+			v.check(len(key) >= minProductionSecretLength, "ATTENDANCE_DEVICE_KEYS key for %s must be at least %d characters in production", name, minProductionSecretLength)
+		}
+		if c.Attendance.QRSecret != "" {
+			v.check(len(c.Attendance.QRSecret) >= minProductionSecretLength, "ATTENDANCE_QR_SECRET must be at least %d characters in production", minProductionSecretLength)
+		}
+	}
+
+	if ta := c.TeacherAttendance; ta.Enabled {
+		_, err = time.Parse("15:04", ta.LateAfter)
+		v.check(err == nil, "TEACHER_ATTENDANCE_LATE_AFTER must be HH:MM, got %q", ta.LateAfter)
+		for _, entry := range ta.AllowedNetworks {
+			v.check(validNetwork(entry), "TEACHER_ATTENDANCE_ALLOWED_IPS entry %q is not an IP address or CIDR range", entry)
+		}
+		v.check(ta.GeofenceRadiusMeters >= 0, "TEACHER_ATTENDANCE_GEOFENCE_RADIUS_METERS must not be negative")
+		if ta.GeofenceRadiusMeters > 0 {
+			inRange := math.Abs(ta.GeofenceLatitude) <= 90 && math.Abs(ta.GeofenceLongitude) <= 180
+			v.check(inRange, "TEACHER_ATTENDANCE_GEOFENCE_LAT/LNG must be valid coordinates")
+		}
+	}
+
+	if m := c.Mutations; m.Enabled {
+		v.check(m.ReminderAfter >= 0, "MUTATION_REMINDER_AFTER must not be negative")
+		v.check(m.ExpireAfter >= 0, "MUTATION_EXPIRE_AFTER must not be negative")
+		v.check(m.ExpiryAction == "reject" || m.ExpiryAction == "escalate", "MUTATION_EXPIRY_ACTION must be reject or escalate, got %q", m.ExpiryAction)
+		v.positive("MUTATION_SWEEP_INTERVAL", m.SweepInterval)
+		v.positive("GRADE_APPEAL_TEACHER_SLA", c.GradeAppeals.TeacherSLA)
+		v.positive("GRADE_APPEAL_ADMIN_SLA", c.GradeAppeals.AdminSLA)
+	}
+
+	if c.AlertRules.Enabled {
+		v.positive("ALERT_RULES_INTERVAL", c.AlertRules.Interval)
+	}
+
+	if lp := c.LessonPlans; lp.RemindersEnabled {
+		v.check(lp.DeadlineDays >= 0 && lp.DeadlineDays <= 6, "LESSON_PLAN_DEADLINE_DAYS must be between 0 and 6, got %d", lp.DeadlineDays)
+		v.positive("LESSON_PLAN_REMINDER_LEAD", lp.ReminderLead)
+		v.positive("LESSON_PLAN_REMINDER_INTERVAL", lp.ReminderInterval)
+	}
+
+	if comp := c.Compression; comp.Enabled {
+		v.check(comp.Level >= 0 && comp.Level <= 9, "COMPRESSION_LEVEL must be between 0 and 9, got %d", comp.Level)
+		v.check(comp.MinSize >= 0, "COMPRESSION_MIN_SIZE must not be negative")
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validNetwork accepts a single IP address or a CIDR range.
+func validNetwork(entry string) bool {
+	if net.ParseIP(entry) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(entry)
+	return err == nil
+}
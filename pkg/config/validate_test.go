@@ -0,0 +1,261 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Env:        EnvDevelopment,
+		Port:       8080,
+		Database:   DatabaseConfig{Host: "localhost", Port: 5432, Name: "sma", Password: defaultDBPassword},
+		Redis:      RedisConfig{Port: 6379},
+		JWT:        JWTConfig{Secret: defaultJWTSecret, Expiration: time.Hour, RefreshExpiration: 24 * time.Hour},
+		Attendance: AttendanceConfig{NonSchoolDayPolicy: "reject", LateAfter: "07:15", Timezone: "Asia/Jakarta"},
+	}
+}
+
+func TestValidateAcceptsDevelopmentDefaults(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+}
+
+func TestValidateCollectsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.Port = 70000
+	cfg.Reports = ReportsConfig{Enabled: true, SignedURLSecret: "s", SignedURLTTL: time.Hour}
+	cfg.Attendance.NonSchoolDayPolicy = "ignore"
+	cfg.Cutover.ResponseCasing = "kebab"
+	cfg.Attendance.LateAfter = "7.15"
+
+	err := cfg.Validate()
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Len(t, validationErr.Problems, 5)
+	assert.Contains(t, err.Error(), "PORT must be between 1 and 65535")
+	assert.Contains(t, err.Error(), "REPORTS_STORAGE_DIR is required")
+	assert.Contains(t, err.Error(), "CUTOVER_RESPONSE_CASING must be camel, snake or empty")
+	assert.Contains(t, err.Error(), "ATTENDANCE_LATE_AFTER must be HH:MM")
+}
+
+func TestValidateProductionRequiresStrongSecrets(t *testing.T) {
+	cfg := validConfig()
+	cfg.Env = EnvProduction
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "JWT_SECRET must not use the development default")
+	assert.Contains(t, err.Error(), "DB_PASSWORD must not use the development default")
+
+	assert.Contains(t, err.Error(), "metrics must be protected in production")
+
+	cfg.JWT.Secret = strings.Repeat("x", minProductionSecretLength)
+	cfg.Database.Password = "s3cret"
+	cfg.Metrics.AllowedIPs = []string{"10.0.0.0/8"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Attendance.DeviceKeys = map[string]string{"gate-1": "short"}
+	assert.ErrorContains(t, cfg.Validate(), "ATTENDANCE_DEVICE_KEYS key for gate-1")
+
+	cfg.Attendance.DeviceKeys = nil
+	cfg.Cutover.SyncAPIKeys = map[string]string{"legacy": "short"}
+	assert.ErrorContains(t, cfg.Validate(), "CUTOVER_SYNC_API_KEYS key for legacy")
+}
+
+func TestValidateTeacherAttendance(t *testing.T) {
+	cfg := validConfig()
+	cfg.TeacherAttendance = TeacherAttendanceConfig{Enabled: true, LateAfter: "07:00", AllowedNetworks: []string{"10.0.0.0/8", "203.0.113.7"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TeacherAttendance.AllowedNetworks = append(cfg.TeacherAttendance.AllowedNetworks, "school-lan")
+	cfg.TeacherAttendance.GeofenceRadiusMeters = 150
+	cfg.TeacherAttendance.GeofenceLatitude = 120
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `TEACHER_ATTENDANCE_ALLOWED_IPS entry "school-lan"`)
+	assert.Contains(t, err.Error(), "TEACHER_ATTENDANCE_GEOFENCE_LAT/LNG must be valid coordinates")
+}
+
+func TestValidateEvents(t *testing.T) {
+	cfg := validConfig()
+	cfg.Events = EventsConfig{Enabled: true, Broker: "nats", BrokerURL: "nats://localhost:4222", SubjectPrefix: "sma", PublishTimeout: 5 * time.Second, PollInterval: time.Second, ClaimTimeout: time.Minute}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Events.BrokerURL = "http://localhost:8082"
+	assert.ErrorContains(t, cfg.Validate(), "EVENTS_BROKER_URL must be a nats:// or tls:// URL")
+
+	cfg.Events.Broker = "kafka"
+	cfg.Events.ClaimTimeout = time.Second
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `EVENTS_BROKER must be log, nats or kafka-rest, got "kafka"`)
+	assert.Contains(t, err.Error(), "EVENTS_CLAIM_TIMEOUT must be longer than EVENTS_PUBLISH_TIMEOUT")
+}
+
+func TestValidatePush(t *testing.T) {
+	cfg := validConfig()
+	cfg.Push = PushConfig{Enabled: true, Provider: "fcm", FCMCredentialsFile: "/etc/sma/fcm.json", Timeout: 10 * time.Second, PollInterval: 2 * time.Second, MaxAttempts: 5, RetryBackoff: 30 * time.Second, MaxAge: 24 * time.Hour}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Push.FCMCredentialsFile = ""
+	cfg.Push.MaxAttempts = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PUSH_FCM_CREDENTIALS_FILE is required when PUSH_PROVIDER is fcm")
+	assert.Contains(t, err.Error(), "PUSH_MAX_ATTEMPTS must be positive")
+
+	cfg.Push.Provider = "apns"
+	assert.ErrorContains(t, cfg.Validate(), `PUSH_PROVIDER must be log or fcm, got "apns"`)
+}
+
+func TestValidateMessaging(t *testing.T) {
+	cfg := validConfig()
+	cfg.Messaging = MessagingConfig{
+		Enabled:            true,
+		SMSProvider:        "twilio",
+		WhatsAppProvider:   "twilio",
+		TwilioAccountSID:   "AC123",
+		TwilioAuthToken:    "secret",
+		TwilioSMSFrom:      "+15005550006",
+		TwilioWhatsAppFrom: "+14155238886",
+		CallbackURL:        "https://api.example.sch.id/api/v1/messaging/callbacks",
+		QuietHoursStart:    "21:00",
+		QuietHoursEnd:      "06:00",
+		Timeout:            10 * time.Second,
+		PollInterval:       5 * time.Second,
+		MaxAttempts:        5,
+		RetryBackoff:       time.Minute,
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Messaging.WhatsAppProvider = "meta"
+	cfg.Messaging.QuietHoursEnd = "6am"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WHATSAPP_PHONE_NUMBER_ID and WHATSAPP_ACCESS_TOKEN are required when MESSAGING_WHATSAPP_PROVIDER is meta")
+	assert.Contains(t, err.Error(), "WHATSAPP_APP_SECRET is required to verify callbacks")
+	assert.Contains(t, err.Error(), `MESSAGING_QUIET_HOURS_END must be HH:MM, got "6am"`)
+
+	cfg.Messaging.SMSProvider = "meta"
+	assert.ErrorContains(t, cfg.Validate(), `MESSAGING_SMS_PROVIDER must be log or twilio, got "meta"`)
+}
+
+func TestValidateMutations(t *testing.T) {
+	cfg := validConfig()
+	cfg.Mutations = MutationsConfig{Enabled: true, ReminderAfter: 72 * time.Hour, ExpireAfter: 14 * 24 * time.Hour, ExpiryAction: "escalate", SweepInterval: time.Hour}
+	cfg.GradeAppeals = GradeAppealsConfig{TeacherSLA: 72 * time.Hour, AdminSLA: 7 * 24 * time.Hour}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Mutations.ExpiryAction = "archive"
+	cfg.Mutations.SweepInterval = 0
+	cfg.GradeAppeals.AdminSLA = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `MUTATION_EXPIRY_ACTION must be reject or escalate, got "archive"`)
+	assert.Contains(t, err.Error(), "MUTATION_SWEEP_INTERVAL must be a positive duration")
+	assert.Contains(t, err.Error(), "GRADE_APPEAL_ADMIN_SLA must be a positive duration")
+}
+
+func TestValidateCompression(t *testing.T) {
+	cfg := validConfig()
+	cfg.Compression = CompressionConfig{Enabled: true, MinSize: 1024}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Compression.Level = 11
+	cfg.Compression.MinSize = -1
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "COMPRESSION_LEVEL must be between 0 and 9, got 11")
+	assert.Contains(t, err.Error(), "COMPRESSION_MIN_SIZE must not be negative")
+}
+
+func TestValidateServerTLS(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.TLS = TLSConfig{ACMEDomains: []string{"api.sma.example.sch.id"}, ACMECacheDir: "./certs", HTTPPort: 80}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Server.TLS = TLSConfig{CertFile: "cert.pem", ACMEDomains: []string{"api.sma.example.sch.id"}, HTTPPort: cfg.Port}
+	cfg.Server.WriteTimeout = -time.Second
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	assert.Contains(t, err.Error(), "TLS_CERT_FILE and TLS_ACME_DOMAINS are mutually exclusive")
+	assert.Contains(t, err.Error(), "TLS_ACME_CACHE_DIR is required with TLS_ACME_DOMAINS")
+	assert.Contains(t, err.Error(), "TLS_HTTP_PORT must differ from PORT")
+	assert.Contains(t, err.Error(), "SERVER_WRITE_TIMEOUT must not be negative")
+}
+
+func TestValidateRequestTimeouts(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.WriteTimeout = 2 * time.Minute
+	cfg.Server.RequestTimeout = 5 * time.Second
+	cfg.Server.RouteTimeouts = parseRouteTimeouts("POST /api/v1/reports/generate=30s, /api/v1/export=0")
+	assert.Equal(t, map[string]time.Duration{"POST /api/v1/reports/generate": 30 * time.Second, "/api/v1/export": 0}, cfg.Server.RouteTimeouts)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Server.RequestTimeout = 5 * time.Minute
+	cfg.Server.RouteTimeouts = parseRouteTimeouts("/api/v1/archives=10m,/api/v1/grades")
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "REQUEST_TIMEOUT must not exceed SERVER_WRITE_TIMEOUT")
+	assert.Contains(t, err.Error(), `REQUEST_TIMEOUT_ROUTES budget for "/api/v1/archives" exceeds SERVER_WRITE_TIMEOUT`)
+	assert.Contains(t, err.Error(), `REQUEST_TIMEOUT_ROUTES entry "/api/v1/grades" needs a route and a duration`)
+}
+
+func TestValidateSessions(t *testing.T) {
+	cfg := validConfig()
+	cfg.Sessions = SessionsConfig{MaxPerRole: parseRoleLimits("student=2, GUARDIAN=3"), IdleTimeout: parseLongDuration("14d", 0), SweepInterval: time.Hour}
+	assert.Equal(t, map[string]int{"STUDENT": 2, "GUARDIAN": 3}, cfg.Sessions.MaxPerRole)
+	assert.Equal(t, 14*24*time.Hour, cfg.Sessions.IdleTimeout)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Sessions.MaxPerRole = parseRoleLimits("PARENT=2,TEACHER=0,ADMIN")
+	cfg.Sessions.SweepInterval = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `SESSION_MAX_PER_ROLE names unknown role "PARENT"`)
+	assert.Contains(t, err.Error(), `SESSION_MAX_PER_ROLE entry "TEACHER" needs a role and a positive limit`)
+	assert.Contains(t, err.Error(), `SESSION_MAX_PER_ROLE entry "ADMIN" needs a role and a positive limit`)
+	assert.Contains(t, err.Error(), "SESSION_SWEEP_INTERVAL must be a positive duration")
+}
+
+func TestValidateRetention(t *testing.T) {
+	cfg := validConfig()
+	cfg.Retention = RetentionConfig{Enabled: true, RefreshTokens: parseLongDuration("30d", 0), Interval: 24 * time.Hour}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Retention.AuditLogs = -time.Hour
+	cfg.Retention.Interval = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RETENTION_AUDIT_LOGS must not be negative")
+	assert.Contains(t, err.Error(), "RETENTION_INTERVAL must be a positive duration")
+}
+
+func TestValidateAlertRules(t *testing.T) {
+	cfg := validConfig()
+	cfg.AlertRules = AlertRulesConfig{Enabled: true, Interval: time.Hour}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AlertRules.Interval = 0
+	assert.ErrorContains(t, cfg.Validate(), "ALERT_RULES_INTERVAL must be a positive duration")
+}
+
+func TestValidateQueues(t *testing.T) {
+	cfg := validConfig()
+	cfg.Reports = ReportsConfig{Enabled: true, StorageDir: "./exports", SignedURLSecret: "s", SignedURLTTL: time.Hour, WorkerConcurrency: 2, WorkerMax: 6}
+	cfg.Queues = QueuesConfig{Autoscale: true, AutoscaleInterval: 30 * time.Second, JobsPerWorker: 4}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Reports.WorkerMin = 3
+	cfg.Queues.JobsPerWorker = 0
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "REPORTS_WORKER_CONCURRENCY must be between REPORTS_WORKER_MIN and REPORTS_WORKER_MAX, got 2")
+	assert.Contains(t, err.Error(), "QUEUE_JOBS_PER_WORKER must be positive, got 0")
+}
@@ -0,0 +1,31 @@
+package database
+
+import "context"
+
+type dryRunKey struct{}
+
+// Tx is the subset of *sqlx.Tx and *sql.Tx needed to finish a transaction.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// WithDryRun marks ctx so transactions finished via CommitOrRollback are rolled back. Every statement
+// still executes, so constraint violations and conflicts surface exactly as in a real run.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx was marked with WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// CommitOrRollback commits tx, or rolls it back when ctx requests a dry run.
+func CommitOrRollback(ctx context.Context, tx Tx) error {
+	if IsDryRun(ctx) {
+		return tx.Rollback()
+	}
+	return tx.Commit()
+}
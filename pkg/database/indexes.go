@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// IndexRecommendation describes an index a hot query path relies on.
+type IndexRecommendation struct {
+	Name    string
+	Table   string
+	Columns []string
+	Reason  string
+}
+
+// Statement returns the DDL that creates the recommended index.
+func (r IndexRecommendation) Statement() string {
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(%s);", r.Name, r.Table, strings.Join(r.Columns, ", "))
+}
+
+// RecommendedIndexes lists the indexes checked at startup. Tables created before the migrations
+// were introduced may lack them even though the application works without.
+var RecommendedIndexes = []IndexRecommendation{
+	{
+		Name:    "idx_schedules_term_day_slot",
+		Table:   "schedules",
+		Columns: []string{"term_id", "day_of_week", "time_slot"},
+		Reason:  "schedule conflict checks load a term's timetable and match slots by day and time",
+	},
+}
+
+// indexColumnsQuery returns the key columns of every index on a table, in index order.
+const indexColumnsQuery = `SELECT array_to_string(ARRAY(
+	SELECT a.attname FROM unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+	ORDER BY k.ord), ',') AS columns
+FROM pg_index i WHERE i.indrelid = to_regclass($1)`
+
+// MissingIndexes returns the recommendations not covered by an existing index. An index covers a
+// recommendation when its leading columns match the recommended ones in order; recommendations
+// for tables that do not exist are skipped.
+func MissingIndexes(ctx context.Context, db *sqlx.DB, recommendations []IndexRecommendation) ([]IndexRecommendation, error) {
+	var missing []IndexRecommendation
+	for _, rec := range recommendations {
+		var exists bool
+		if err := db.GetContext(ctx, &exists, `SELECT to_regclass($1) IS NOT NULL`, rec.Table); err != nil {
+			return nil, fmt.Errorf("look up table %s: %w", rec.Table, err)
+		}
+		if !exists {
+			continue
+		}
+		var indexes []string
+		if err := db.SelectContext(ctx, &indexes, indexColumnsQuery, rec.Table); err != nil {
+			return nil, fmt.Errorf("list indexes on %s: %w", rec.Table, err)
+		}
+		want := strings.Join(rec.Columns, ",")
+		covered := false
+		for _, columns := range indexes {
+			if columns == want || strings.HasPrefix(columns, want+",") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, rec)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingIndexes(t *testing.T) {
+	raw, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer raw.Close()
+	db := sqlx.NewDb(raw, "sqlmock")
+
+	recs := []IndexRecommendation{
+		{Name: "idx_schedules_term_day_slot", Table: "schedules", Columns: []string{"term_id", "day_of_week", "time_slot"}},
+		{Name: "idx_exams_term", Table: "exams", Columns: []string{"term_id"}},
+		{Name: "idx_legacy_term", Table: "legacy", Columns: []string{"term_id"}},
+	}
+	tableExists := regexp.QuoteMeta("SELECT to_regclass($1) IS NOT NULL")
+	indexColumns := regexp.QuoteMeta("FROM pg_index i WHERE i.indrelid = to_regclass($1)")
+
+	mock.ExpectQuery(tableExists).WithArgs("schedules").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(indexColumns).WithArgs("schedules").
+		WillReturnRows(sqlmock.NewRows([]string{"columns"}).AddRow("id").AddRow("term_id,day_of_week"))
+	mock.ExpectQuery(tableExists).WithArgs("exams").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(indexColumns).WithArgs("exams").
+		WillReturnRows(sqlmock.NewRows([]string{"columns"}).AddRow("term_id,class_id"))
+	mock.ExpectQuery(tableExists).WithArgs("legacy").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	missing, err := MissingIndexes(context.Background(), db, recs)
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "idx_schedules_term_day_slot", missing[0].Name)
+	assert.Equal(t, "CREATE INDEX IF NOT EXISTS idx_schedules_term_day_slot ON schedules(term_id, day_of_week, time_slot);", missing[0].Statement())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
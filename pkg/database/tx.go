@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Postgres error codes of transactions aborted by a concurrent one. Running the transaction again
+// usually succeeds.
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// TxBeginner starts transactions; *sqlx.DB satisfies it.
+type TxBeginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// TxOption customises WithTx.
+type TxOption func(*txConfig)
+
+type txConfig struct {
+	options  sql.TxOptions
+	attempts int
+	backoff  time.Duration
+}
+
+// TxIsolation runs the transaction at level instead of the database default.
+func TxIsolation(level sql.IsolationLevel) TxOption {
+	return func(cfg *txConfig) {
+		cfg.options.Isolation = level
+	}
+}
+
+// TxRetries sets how many times a transaction is attempted in total and the delay before the first
+// retry, which doubles for each further retry. The defaults are 3 attempts and 50ms.
+func TxRetries(attempts int, backoff time.Duration) TxOption {
+	return func(cfg *txConfig) {
+		cfg.attempts = attempts
+		cfg.backoff = backoff
+	}
+}
+
+// WithTx runs fn in a transaction. The transaction is committed when fn returns nil, rolled back when
+// ctx requests a dry run, and rolled back when fn fails or panics; a panic is re-raised once the
+// transaction is closed.
+//
+// When fn or the commit fails with a serialization failure or a deadlock, fn runs again in a new
+// transaction after a jittered backoff. fn must therefore only touch the database through tx and
+// rebuild any state it hands back on every call.
+func WithTx(ctx context.Context, db TxBeginner, fn func(tx *sqlx.Tx) error, opts ...TxOption) error {
+	cfg := txConfig{attempts: 3, backoff: 50 * time.Millisecond}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.attempts < 1 {
+		cfg.attempts = 1
+	}
+
+	delay := cfg.backoff
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, &cfg.options, fn)
+		if err == nil || attempt >= cfg.attempts || !IsRetryable(err) {
+			return err
+		}
+		wait := delay
+		if delay > 0 {
+			wait += time.Duration(rand.Int63n(int64(delay)))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func runTx(ctx context.Context, db TxBeginner, options *sql.TxOptions, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, options)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback()
+			panic(recovered)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err = CommitOrRollback(ctx, tx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IsRetryable reports whether err aborted a transaction because of a serialization failure or a
+// deadlock.
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == codeSerializationFailure || pqErr.Code == codeDeadlockDetected
+}
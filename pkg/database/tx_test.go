@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTxMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	raw, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { raw.Close() })
+	return sqlx.NewDb(raw, "sqlmock"), mock
+}
+
+func TestWithTxCommitsAndRollsBack(t *testing.T) {
+	db, mock := newTxMock(t)
+	failed := errors.New("boom")
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	require.NoError(t, WithTx(context.Background(), db, func(tx *sqlx.Tx) error { return nil }))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	assert.ErrorIs(t, WithTx(context.Background(), db, func(tx *sqlx.Tx) error { return failed }), failed)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	require.NoError(t, WithTx(WithDryRun(context.Background()), db, func(tx *sqlx.Tx) error { return nil }))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	db, mock := newTxMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = WithTx(context.Background(), db, func(tx *sqlx.Tx) error { panic("boom") })
+	})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxRetriesSerializationFailures(t *testing.T) {
+	db, mock := newTxMock(t)
+	deadlock := &pq.Error{Code: codeDeadlockDetected}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&pq.Error{Code: codeSerializationFailure})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	calls := 0
+	err := WithTx(context.Background(), db, func(tx *sqlx.Tx) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("update slots: %w", deadlock)
+		}
+		return nil
+	}, TxIsolation(sql.LevelSerializable), TxRetries(3, time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxGivesUpAfterLastAttempt(t *testing.T) {
+	db, mock := newTxMock(t)
+	deadlock := &pq.Error{Code: codeDeadlockDetected}
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+	calls := 0
+	err := WithTx(context.Background(), db, func(tx *sqlx.Tx) error {
+		calls++
+		return deadlock
+	}, TxRetries(2, time.Millisecond))
+	assert.ErrorIs(t, err, deadlock)
+	assert.Equal(t, 2, calls)
+
+	// Other errors are not retried.
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	err = WithTx(context.Background(), db, func(tx *sqlx.Tx) error {
+		calls++
+		return &pq.Error{Code: "23505"}
+	}, TxRetries(2, time.Millisecond))
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, 3, calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+)
+
+// CatalogEntry documents an error code, its HTTP status and the modules that may return it.
+type CatalogEntry struct {
+	Code        string   `json:"code"`
+	Status      int      `json:"status"`
+	Description string   `json:"description"`
+	Modules     []string `json:"modules"`
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = make(map[string]*CatalogEntry)
+)
+
+func init() {
+	Describe(ErrInvalidCredentials, "Login failed because the email or password is wrong.")
+	Describe(ErrInactiveAccount, "The account exists but has been deactivated.")
+	Describe(ErrNotFound, "The requested resource does not exist or is not visible to the caller.")
+	Describe(ErrForbidden, "The caller is authenticated but not allowed to perform the action.")
+	Describe(ErrUnauthorized, "Authentication is missing, invalid or expired.")
+	Describe(ErrConflict, "The request conflicts with existing data, e.g. a duplicate or overlapping record.")
+	Describe(ErrPreconditionFailed, "A prerequisite is not met, e.g. a dependent record is missing or still in use.")
+	Describe(ErrValidation, "The payload or query parameters failed validation; the message names the field.")
+	Describe(ErrInternal, "An unexpected server error; retrying may help.")
+	Describe(ErrFinalized, "The resource is finalized and can no longer be modified.")
+	Describe(ErrInvalidWeights, "Grade component weights do not add up to a valid total.")
+	Describe(ErrCacheMiss, "Internal cache lookup miss; not returned to clients.")
+	Describe(ErrStaleData, "Cached data is stale and could not be refreshed.")
+	Describe(ErrQuotaExceeded, "The upload would take a category or uploader over its archive storage quota.")
+	Describe(ErrInsufficientStorage, "The server's storage is full or not writable; retry once space is freed.")
+	Describe(ErrTimeout, "The request exceeded its time budget and was cancelled; retry later or narrow the request.")
+}
+
+// Describe adds err to the catalog with a client-facing description.
+func Describe(err *Error, description string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	entry := entryFor(err)
+	entry.Description = description
+}
+
+// Register records that module may return each of errs.
+func Register(module string, errs ...*Error) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	for _, err := range errs {
+		entry := entryFor(err)
+		if !containsString(entry.Modules, module) {
+			entry.Modules = append(entry.Modules, module)
+			sort.Strings(entry.Modules)
+		}
+	}
+}
+
+// Catalog returns every registered error ordered by code.
+func Catalog() []CatalogEntry {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, entry := range catalog {
+		copied := *entry
+		copied.Modules = append([]string{}, entry.Modules...)
+		entries = append(entries, copied)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+func entryFor(err *Error) *CatalogEntry {
+	entry, ok := catalog[err.Code]
+	if !ok {
+		entry = &CatalogEntry{Code: err.Code, Status: err.Status, Description: err.Message, Modules: []string{}}
+		catalog[err.Code] = entry
+	}
+	return entry
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
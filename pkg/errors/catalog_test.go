@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogListsRegisteredModules(t *testing.T) {
+	custom := New("TEST_ONLY", http.StatusTeapot, "test only")
+	Register("students", ErrNotFound, custom)
+	Register("attendance", ErrNotFound)
+	Register("students", ErrNotFound)
+
+	entries := make(map[string]CatalogEntry)
+	for _, entry := range Catalog() {
+		entries[entry.Code] = entry
+	}
+
+	notFound, ok := entries[ErrNotFound.Code]
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, notFound.Status)
+	assert.NotEqual(t, ErrNotFound.Message, notFound.Description)
+	assert.Equal(t, []string{"attendance", "students"}, notFound.Modules)
+
+	assert.Equal(t, CatalogEntry{Code: "TEST_ONLY", Status: http.StatusTeapot, Description: "test only", Modules: []string{"students"}}, entries["TEST_ONLY"])
+	assert.Contains(t, entries, ErrStaleData.Code)
+}
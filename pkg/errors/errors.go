@@ -45,19 +45,22 @@ func Wrap(err error, code string, status int, message string) *Error {
 
 // Predefined errors for common scenarios.
 var (
-	ErrInvalidCredentials = New("INVALID_CREDENTIALS", http.StatusUnauthorized, "invalid email or password")
-	ErrInactiveAccount    = New("ACCOUNT_INACTIVE", http.StatusForbidden, "account is inactive")
-	ErrNotFound           = New("NOT_FOUND", http.StatusNotFound, "resource not found")
-	ErrForbidden          = New("FORBIDDEN", http.StatusForbidden, "forbidden")
-	ErrUnauthorized       = New("UNAUTHORIZED", http.StatusUnauthorized, "unauthorized")
-	ErrConflict           = New("CONFLICT", http.StatusConflict, "conflict")
-	ErrPreconditionFailed = New("PRECONDITION_FAILED", http.StatusPreconditionFailed, "precondition failed")
-	ErrValidation         = New("VALIDATION_ERROR", http.StatusBadRequest, "validation failed")
-	ErrInternal           = New("INTERNAL_ERROR", http.StatusInternalServerError, "internal server error")
-	ErrFinalized          = New("FINALIZED", http.StatusConflict, "resource finalized")
-	ErrInvalidWeights     = New("INVALID_WEIGHTS", http.StatusBadRequest, "invalid component weights")
-	ErrCacheMiss          = New("CACHE_MISS", http.StatusNotFound, "cache entry not found")
-	ErrStaleData          = New("STALE_DATA", http.StatusServiceUnavailable, "stale cached data detected")
+	ErrInvalidCredentials  = New("INVALID_CREDENTIALS", http.StatusUnauthorized, "invalid email or password")
+	ErrInactiveAccount     = New("ACCOUNT_INACTIVE", http.StatusForbidden, "account is inactive")
+	ErrNotFound            = New("NOT_FOUND", http.StatusNotFound, "resource not found")
+	ErrForbidden           = New("FORBIDDEN", http.StatusForbidden, "forbidden")
+	ErrUnauthorized        = New("UNAUTHORIZED", http.StatusUnauthorized, "unauthorized")
+	ErrConflict            = New("CONFLICT", http.StatusConflict, "conflict")
+	ErrPreconditionFailed  = New("PRECONDITION_FAILED", http.StatusPreconditionFailed, "precondition failed")
+	ErrValidation          = New("VALIDATION_ERROR", http.StatusBadRequest, "validation failed")
+	ErrInternal            = New("INTERNAL_ERROR", http.StatusInternalServerError, "internal server error")
+	ErrFinalized           = New("FINALIZED", http.StatusConflict, "resource finalized")
+	ErrInvalidWeights      = New("INVALID_WEIGHTS", http.StatusBadRequest, "invalid component weights")
+	ErrCacheMiss           = New("CACHE_MISS", http.StatusNotFound, "cache entry not found")
+	ErrStaleData           = New("STALE_DATA", http.StatusServiceUnavailable, "stale cached data detected")
+	ErrInsufficientStorage = New("INSUFFICIENT_STORAGE", http.StatusInsufficientStorage, "insufficient storage")
+	ErrQuotaExceeded       = New("QUOTA_EXCEEDED", http.StatusRequestEntityTooLarge, "storage quota exceeded")
+	ErrTimeout             = New("REQUEST_TIMEOUT", http.StatusGatewayTimeout, "request took too long")
 )
 
 // FromError normalises any error into an *Error.
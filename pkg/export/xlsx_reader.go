@@ -0,0 +1,203 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize bounds the decompressed size of a workbook part to guard against zip bombs.
+const maxXLSXPartSize = 32 << 20
+
+// ReadXLSX returns the cell values of the first worksheet of a workbook, one slice per row. Empty
+// rows are kept so row numbers match the sheet; cells are placed by their column reference.
+func ReadXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx archive: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(file); err != nil {
+			return nil, err
+		}
+	}
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("xlsx worksheet %s not found", sheetPath)
+	}
+	return readSheet(file, shared)
+}
+
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("xlsx workbook not found")
+	}
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if len(workbook.Sheets) == 0 || !ok {
+		return fallback, nil
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+func readSharedStrings(file *zip.File) ([]string, error) {
+	var table struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodePart(file, &table); err != nil {
+		return nil, err
+	}
+	values := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		if len(item.Runs) == 0 {
+			values[i] = item.Text
+			continue
+		}
+		var sb strings.Builder
+		for _, run := range item.Runs {
+			sb.WriteString(run.Text)
+		}
+		values[i] = sb.String()
+	}
+	return values, nil
+}
+
+func readSheet(file *zip.File, shared []string) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			Number int `xml:"r,attr"`
+			Cells  []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:"t"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		number := row.Number
+		if number <= 0 {
+			number = len(rows) + 1
+		}
+		for len(rows) < number {
+			rows = append(rows, nil)
+		}
+		var record []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				index, ok := columnIndex(cell.Ref)
+				if !ok {
+					return nil, fmt.Errorf("invalid xlsx cell reference %q", cell.Ref)
+				}
+				column = index
+			}
+			var value string
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(shared) {
+					return nil, fmt.Errorf("invalid xlsx shared string in %s", cell.Ref)
+				}
+				value = shared[index]
+			case "inlineStr":
+				value = cell.Inline.Text
+			default:
+				value = cell.Value
+			}
+			for len(record) <= column {
+				record = append(record, "")
+			}
+			record[column] = value
+		}
+		rows[number-1] = record
+	}
+	return rows, nil
+}
+
+// columnIndex converts the letters of a cell reference into a zero-based column index (B7 -> 1).
+func columnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, false
+	}
+	return index - 1, true
+}
+
+func decodePart(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("open xlsx part %s: %w", file.Name, err)
+	}
+	defer reader.Close()
+	limited := &io.LimitedReader{R: reader, N: maxXLSXPartSize + 1}
+	if err := xml.NewDecoder(limited).Decode(target); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("xlsx part %s is too large", file.Name)
+		}
+		return fmt.Errorf("decode xlsx part %s: %w", file.Name, err)
+	}
+	return nil
+}
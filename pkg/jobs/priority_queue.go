@@ -0,0 +1,243 @@
+package jobs
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PriorityQueue is an in-memory job dispatcher that hands workers the highest priority job first and
+// keeps FIFO order between jobs of equal priority. It accepts the same configuration as Queue.
+type PriorityQueue struct {
+	name    string
+	handler Handler
+
+	workers    int
+	maxRetries int
+	retryDelay time.Duration
+	logger     *zap.Logger
+
+	pending jobHeap
+	seq     uint64
+	meter   *meter
+	stops   []chan struct{}
+	// slots bounds the number of pending jobs; ready holds one token per pending job.
+	slots   chan struct{}
+	ready   chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	started bool
+}
+
+// NewPriorityQueue builds a new priority queue with the provided handler.
+func NewPriorityQueue(name string, handler Handler, cfg QueueConfig) *PriorityQueue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = cfg.Workers * 4
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+
+	return &PriorityQueue{
+		name:       name,
+		handler:    handler,
+		workers:    cfg.Workers,
+		maxRetries: cfg.MaxRetries,
+		retryDelay: cfg.RetryDelay,
+		logger:     cfg.Logger,
+		slots:      make(chan struct{}, cfg.BufferSize),
+		ready:      make(chan struct{}, cfg.BufferSize),
+		meter:      newMeter(cfg.RateWindow),
+	}
+}
+
+// Start begins worker consumption. Safe to call once.
+func (q *PriorityQueue) Start(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		return
+	}
+	q.ctx, q.cancel = context.WithCancel(ctx)
+	q.started = true
+	q.scale(q.workers)
+	q.logger.Sugar().Infow("priority queue started", "queue", q.name, "workers", q.workers)
+}
+
+// Resize changes the number of workers, at least one. Workers that are let go finish their current
+// job first.
+func (q *PriorityQueue) Resize(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started && q.ctx.Err() == nil {
+		q.scale(workers)
+	}
+	q.workers = workers
+}
+
+// scale starts or stops workers until workers run. Callers hold q.mu.
+func (q *PriorityQueue) scale(workers int) {
+	for len(q.stops) < workers {
+		stop := make(chan struct{})
+		q.stops = append(q.stops, stop)
+		q.wg.Add(1)
+		go q.worker(stop)
+	}
+	for len(q.stops) > workers {
+		last := len(q.stops) - 1
+		close(q.stops[last])
+		q.stops = q.stops[:last]
+	}
+}
+
+// Stats returns the queue's current backlog and recent throughput.
+func (q *PriorityQueue) Stats() Stats {
+	q.mu.Lock()
+	workers := q.workers
+	q.mu.Unlock()
+	return q.meter.stats(q.name, workers)
+}
+
+// Stop cancels workers and waits for them to exit.
+func (q *PriorityQueue) Stop() {
+	q.mu.Lock()
+	if !q.started {
+		q.mu.Unlock()
+		return
+	}
+	q.cancel()
+	q.mu.Unlock()
+	q.wg.Wait()
+	q.logger.Sugar().Infow("priority queue stopped", "queue", q.name)
+}
+
+// Enqueue adds a job, blocking while the queue is full.
+func (q *PriorityQueue) Enqueue(job Job) error {
+	q.mu.Lock()
+	ctx := q.ctx
+	started := q.started
+	q.mu.Unlock()
+
+	if !started {
+		return fmt.Errorf("queue %s not started", q.name)
+	}
+	if job.Enqueued.IsZero() {
+		job.Enqueued = time.Now().UTC()
+	}
+
+	ticket := q.meter.queued()
+	select {
+	case <-ctx.Done():
+		q.meter.dropped(ticket)
+		return fmt.Errorf("queue %s stopped: %w", q.name, ctx.Err())
+	case q.slots <- struct{}{}:
+	}
+	q.mu.Lock()
+	q.seq++
+	heap.Push(&q.pending, queuedJob{job: job, seq: q.seq, ticket: ticket})
+	q.mu.Unlock()
+	q.ready <- struct{}{}
+	return nil
+}
+
+// Len returns the number of jobs waiting for a worker.
+func (q *PriorityQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending.Len()
+}
+
+func (q *PriorityQueue) worker(stop <-chan struct{}) {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-q.ready:
+			q.mu.Lock()
+			next := heap.Pop(&q.pending).(queuedJob)
+			q.mu.Unlock()
+			<-q.slots
+			start := q.meter.started(next.ticket)
+			err := q.handler(q.ctx, next.job)
+			q.meter.done(start, err)
+			if err != nil {
+				q.handleFailure(next.job, err)
+			}
+		}
+	}
+}
+
+func (q *PriorityQueue) handleFailure(job Job, err error) {
+	job.Attempt++
+	if job.Attempt > q.maxRetries {
+		q.logger.Sugar().Errorw("job exceeded retries", "queue", q.name, "job_id", job.ID, "type", job.Type, "error", err)
+		return
+	}
+	q.logger.Sugar().Warnw("job failed, retrying", "queue", q.name, "job_id", job.ID, "type", job.Type, "attempt", job.Attempt, "error", err)
+
+	go func(j Job) {
+		timer := time.NewTimer(q.retryDelay)
+		defer timer.Stop()
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-timer.C:
+			if err := q.Enqueue(j); err != nil {
+				q.logger.Sugar().Errorw("failed to requeue job", "queue", q.name, "job_id", j.ID, "error", err)
+			}
+		}
+	}(job)
+}
+
+// queuedJob is a job waiting for a worker. seq orders jobs of equal priority in a PriorityQueue and
+// ticket identifies the job to the queue's meter.
+type queuedJob struct {
+	job    Job
+	seq    uint64
+	ticket uint64
+}
+
+// jobHeap implements heap.Interface ordered by priority, then by arrival.
+type jobHeap []queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority > h[j].job.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x interface{}) { *h = append(*h, x.(queuedJob)) }
+
+func (h *jobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
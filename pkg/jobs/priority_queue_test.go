@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityQueueRunsHighestPriorityFirst(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	handler := func(ctx context.Context, job Job) error {
+		if job.ID == "blocker" {
+			<-release
+			return nil
+		}
+		mu.Lock()
+		order = append(order, job.ID)
+		if len(order) == 4 {
+			close(done)
+		}
+		mu.Unlock()
+		return nil
+	}
+	queue := NewPriorityQueue("test", handler, QueueConfig{Workers: 1, BufferSize: 8})
+	queue.Start(context.Background())
+	defer queue.Stop()
+
+	// Occupy the only worker so the remaining jobs queue up behind it.
+	require.NoError(t, queue.Enqueue(Job{ID: "blocker"}))
+	require.Eventually(t, func() bool { return queue.Len() == 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, queue.Enqueue(Job{ID: "low", Priority: 0}))
+	require.NoError(t, queue.Enqueue(Job{ID: "normal-1", Priority: 1}))
+	require.NoError(t, queue.Enqueue(Job{ID: "high", Priority: 2}))
+	require.NoError(t, queue.Enqueue(Job{ID: "normal-2", Priority: 1}))
+	assert.Equal(t, 4, queue.Len())
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("jobs were not processed")
+	}
+	assert.Equal(t, []string{"high", "normal-1", "normal-2", "low"}, order)
+}
+
+func TestPriorityQueueRejectsBeforeStart(t *testing.T) {
+	queue := NewPriorityQueue("test", func(context.Context, Job) error { return nil }, QueueConfig{})
+	assert.Error(t, queue.Enqueue(Job{ID: "job-1"}))
+}
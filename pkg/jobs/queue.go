@@ -16,6 +16,8 @@ type Job struct {
 	Payload  interface{}
 	Attempt  int
 	Enqueued time.Time
+	// Priority orders jobs in a PriorityQueue; higher values run first. Queue ignores it.
+	Priority int
 }
 
 // Handler processes a job.
@@ -27,6 +29,8 @@ type QueueConfig struct {
 	BufferSize int
 	MaxRetries int
 	RetryDelay time.Duration
+	// RateWindow is how far back Stats looks for finished jobs; it defaults to five minutes.
+	RateWindow time.Duration
 	Logger     *zap.Logger
 }
 
@@ -41,7 +45,9 @@ type Queue struct {
 	retryDelay time.Duration
 	logger     *zap.Logger
 
-	jobs    chan Job
+	jobs    chan queuedJob
+	meter   *meter
+	stops   []chan struct{}
 	ctx     context.Context
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
@@ -75,7 +81,8 @@ func NewQueue(name string, handler Handler, cfg QueueConfig) *Queue {
 		maxRetries: cfg.MaxRetries,
 		retryDelay: cfg.RetryDelay,
 		logger:     cfg.Logger,
-		jobs:       make(chan Job, cfg.BufferSize),
+		jobs:       make(chan queuedJob, cfg.BufferSize),
+		meter:      newMeter(cfg.RateWindow),
 	}
 }
 
@@ -87,14 +94,48 @@ func (q *Queue) Start(ctx context.Context) {
 		return
 	}
 	q.ctx, q.cancel = context.WithCancel(ctx)
-	for i := 0; i < q.workers; i++ {
-		q.wg.Add(1)
-		go q.worker(i + 1)
-	}
 	q.started = true
+	q.scale(q.workers)
 	q.logger.Sugar().Infow("queue started", "queue", q.name, "workers", q.workers)
 }
 
+// Resize changes the number of workers, at least one. Workers that are let go finish their current
+// job first.
+func (q *Queue) Resize(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started && q.ctx.Err() == nil {
+		q.scale(workers)
+	}
+	q.workers = workers
+}
+
+// scale starts or stops workers until workers run. Callers hold q.mu.
+func (q *Queue) scale(workers int) {
+	for len(q.stops) < workers {
+		stop := make(chan struct{})
+		q.stops = append(q.stops, stop)
+		q.wg.Add(1)
+		go q.worker(stop)
+	}
+	for len(q.stops) > workers {
+		last := len(q.stops) - 1
+		close(q.stops[last])
+		q.stops = q.stops[:last]
+	}
+}
+
+// Stats returns the queue's current backlog and recent throughput.
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	workers := q.workers
+	q.mu.Unlock()
+	return q.meter.stats(q.name, workers)
+}
+
 // Stop cancels workers and waits for them to exit.
 func (q *Queue) Stop() {
 	q.mu.Lock()
@@ -122,23 +163,30 @@ func (q *Queue) Enqueue(job Job) error {
 		job.Enqueued = time.Now().UTC()
 	}
 
+	ticket := q.meter.queued()
 	select {
 	case <-ctx.Done():
+		q.meter.dropped(ticket)
 		return fmt.Errorf("queue %s stopped: %w", q.name, ctx.Err())
-	case q.jobs <- job:
+	case q.jobs <- queuedJob{job: job, ticket: ticket}:
 		return nil
 	}
 }
 
-func (q *Queue) worker(workerID int) {
+func (q *Queue) worker(stop <-chan struct{}) {
 	defer q.wg.Done()
 	for {
 		select {
 		case <-q.ctx.Done():
 			return
-		case job := <-q.jobs:
-			if err := q.handler(q.ctx, job); err != nil {
-				q.handleFailure(job, err)
+		case <-stop:
+			return
+		case next := <-q.jobs:
+			start := q.meter.started(next.ticket)
+			err := q.handler(q.ctx, next.job)
+			q.meter.done(start, err)
+			if err != nil {
+				q.handleFailure(next.job, err)
 			}
 		}
 	}
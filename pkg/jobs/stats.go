@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultRateWindow is how far back Stats looks for finished jobs when no RateWindow is configured.
+const defaultRateWindow = 5 * time.Minute
+
+// Stats is a snapshot of a queue's backlog and throughput.
+type Stats struct {
+	Name    string
+	Workers int
+	// Depth counts jobs waiting for a worker and Active those being handled. Jobs waiting to be
+	// retried are in neither.
+	Depth  int
+	Active int
+	// Processed and Failed count handler runs since the queue was built.
+	Processed uint64
+	Failed    uint64
+	// Rate is the number of jobs finished per minute and AvgDuration their mean handling time, both
+	// over the rate window.
+	Rate        float64
+	AvgDuration time.Duration
+	// OldestQueued is how long the longest waiting job has been queued.
+	OldestQueued time.Duration
+}
+
+type finishedJob struct {
+	at   time.Time
+	took time.Duration
+}
+
+// meter tracks the jobs of one queue for Stats. Every queued job holds a ticket until a worker picks
+// it up, so the oldest waiting job can be found without looking into the queue itself.
+type meter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	since     time.Time
+	tickets   uint64
+	waiting   map[uint64]time.Time
+	active    int
+	processed uint64
+	failed    uint64
+	finished  []finishedJob
+	now       func() time.Time
+}
+
+func newMeter(window time.Duration) *meter {
+	if window <= 0 {
+		window = defaultRateWindow
+	}
+	return &meter{window: window, since: time.Now(), waiting: make(map[uint64]time.Time), now: time.Now}
+}
+
+// queued records a job entering the queue and returns its ticket.
+func (m *meter) queued() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tickets++
+	m.waiting[m.tickets] = m.now()
+	return m.tickets
+}
+
+// dropped forgets a ticket whose job never made it into the queue.
+func (m *meter) dropped(ticket uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.waiting, ticket)
+}
+
+// started moves a job from waiting to active and returns when it started.
+func (m *meter) started(ticket uint64) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.waiting, ticket)
+	m.active++
+	return m.now()
+}
+
+// done records a handler run that began at start.
+func (m *meter) done(start time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.active--
+	m.processed++
+	if err != nil {
+		m.failed++
+	}
+	m.finished = append(m.finished, finishedJob{at: now, took: now.Sub(start)})
+	m.trim(now)
+}
+
+// trim drops finished jobs that fell out of the rate window.
+func (m *meter) trim(now time.Time) {
+	cutoff := now.Add(-m.window)
+	keep := 0
+	for keep < len(m.finished) && m.finished[keep].at.Before(cutoff) {
+		keep++
+	}
+	m.finished = m.finished[keep:]
+}
+
+func (m *meter) stats(name string, workers int) Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.trim(now)
+	stats := Stats{
+		Name:      name,
+		Workers:   workers,
+		Depth:     len(m.waiting),
+		Active:    m.active,
+		Processed: m.processed,
+		Failed:    m.failed,
+	}
+	for _, queuedAt := range m.waiting {
+		if age := now.Sub(queuedAt); age > stats.OldestQueued {
+			stats.OldestQueued = age
+		}
+	}
+	if len(m.finished) > 0 {
+		var total time.Duration
+		for _, job := range m.finished {
+			total += job.took
+		}
+		stats.AvgDuration = total / time.Duration(len(m.finished))
+	}
+	// A queue younger than the window is measured over its lifetime so far.
+	span := m.window
+	if elapsed := now.Sub(m.since); elapsed < span {
+		span = elapsed
+	}
+	if span > 0 {
+		stats.Rate = float64(len(m.finished)) / span.Minutes()
+	}
+	return stats
+}
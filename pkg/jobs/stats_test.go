@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueStatsTrackBacklogAndThroughput(t *testing.T) {
+	release := make(chan struct{})
+	handler := func(ctx context.Context, job Job) error {
+		<-release
+		if job.ID == "bad" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+	queue := NewQueue("test", handler, QueueConfig{Workers: 1, BufferSize: 8, MaxRetries: 1, RetryDelay: time.Hour})
+	queue.Start(context.Background())
+	defer queue.Stop()
+
+	require.NoError(t, queue.Enqueue(Job{ID: "first"}))
+	require.Eventually(t, func() bool { return queue.Stats().Active == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, queue.Enqueue(Job{ID: "bad"}))
+	require.NoError(t, queue.Enqueue(Job{ID: "third"}))
+	time.Sleep(5 * time.Millisecond)
+
+	stats := queue.Stats()
+	assert.Equal(t, "test", stats.Name)
+	assert.Equal(t, 1, stats.Workers)
+	assert.Equal(t, 2, stats.Depth)
+	assert.GreaterOrEqual(t, stats.OldestQueued, 5*time.Millisecond)
+
+	close(release)
+	require.Eventually(t, func() bool { return queue.Stats().Processed == 3 }, time.Second, time.Millisecond)
+	stats = queue.Stats()
+	assert.Zero(t, stats.Depth)
+	assert.Zero(t, stats.Active)
+	assert.Zero(t, stats.OldestQueued)
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Positive(t, stats.Rate)
+	assert.Positive(t, stats.AvgDuration)
+}
+
+func TestPriorityQueueResize(t *testing.T) {
+	release := make(chan struct{})
+	handler := func(ctx context.Context, job Job) error {
+		<-release
+		return nil
+	}
+	queue := NewPriorityQueue("test", handler, QueueConfig{Workers: 1, BufferSize: 8})
+	queue.Start(context.Background())
+	defer queue.Stop()
+
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, queue.Enqueue(Job{ID: id}))
+	}
+	require.Eventually(t, func() bool { return queue.Stats().Active == 1 }, time.Second, time.Millisecond)
+
+	queue.Resize(3)
+	require.Eventually(t, func() bool { return queue.Stats().Active == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, queue.Stats().Workers)
+
+	queue.Resize(0)
+	assert.Equal(t, 1, queue.Stats().Workers)
+	close(release)
+	require.Eventually(t, func() bool { return queue.Stats().Processed == 3 }, time.Second, time.Millisecond)
+}
+
+func TestMeterRateWindow(t *testing.T) {
+	m := newMeter(time.Minute)
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	m.since = now.Add(-time.Hour)
+	m.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		m.done(m.started(m.queued()).Add(-2*time.Second), nil)
+	}
+	now = now.Add(30 * time.Second)
+	m.done(m.started(m.queued()).Add(-4*time.Second), nil)
+	stats := m.stats("test", 1)
+	assert.Equal(t, 4.0, stats.Rate)
+	assert.Equal(t, 2500*time.Millisecond, stats.AvgDuration)
+
+	// The first three jobs fall out of the one-minute window.
+	now = now.Add(45 * time.Second)
+	stats = m.stats("test", 1)
+	assert.Equal(t, 1.0, stats.Rate)
+	assert.Equal(t, 4*time.Second, stats.AvgDuration)
+	assert.Equal(t, uint64(4), stats.Processed)
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels holds the default log level and per-module overrides. A module is the first segment of a
+// logger name, so logr.Named("scheduler").Named("generator") follows the "scheduler" override.
+// Levels can be changed while the application runs.
+type Levels struct {
+	mu      sync.RWMutex
+	base    zapcore.Level
+	modules map[string]zapcore.Level
+}
+
+// NewLevels returns levels with the given default and overrides.
+func NewLevels(base zapcore.Level, modules map[string]zapcore.Level) *Levels {
+	l := &Levels{base: base, modules: make(map[string]zapcore.Level, len(modules))}
+	for module, level := range modules {
+		l.modules[strings.ToLower(module)] = level
+	}
+	return l
+}
+
+// ParseModuleLevels parses overrides such as "scheduler=debug,reports=warn".
+func ParseModuleLevels(raw []string) (map[string]zapcore.Level, error) {
+	modules := make(map[string]zapcore.Level, len(raw))
+	for _, entry := range raw {
+		module, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module log level %q, want module=level", entry)
+		}
+		level, err := zapcore.ParseLevel(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level for module %s: %w", module, err)
+		}
+		modules[strings.ToLower(module)] = level
+	}
+	return modules, nil
+}
+
+// Enabled reports whether a logger named name logs at level.
+func (l *Levels) Enabled(name string, level zapcore.Level) bool {
+	module, _, _ := strings.Cut(name, ".")
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if override, ok := l.modules[strings.ToLower(module)]; ok {
+		return level >= override
+	}
+	return level >= l.base
+}
+
+// minimum is the lowest level any logger may log at.
+func (l *Levels) minimum() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	lowest := l.base
+	for _, level := range l.modules {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	return lowest
+}
+
+// SetDefault changes the level of loggers without an override.
+func (l *Levels) SetDefault(level zapcore.Level) {
+	l.mu.Lock()
+	l.base = level
+	l.mu.Unlock()
+}
+
+// SetModule overrides the level of one module.
+func (l *Levels) SetModule(module string, level zapcore.Level) {
+	l.mu.Lock()
+	l.modules[strings.ToLower(module)] = level
+	l.mu.Unlock()
+}
+
+// ResetModule removes a module override so it follows the default again.
+func (l *Levels) ResetModule(module string) {
+	l.mu.Lock()
+	delete(l.modules, strings.ToLower(module))
+	l.mu.Unlock()
+}
+
+// Snapshot returns the default level and the overrides sorted by module.
+func (l *Levels) Snapshot() (string, []ModuleLevel) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	modules := make([]ModuleLevel, 0, len(l.modules))
+	for module, level := range l.modules {
+		modules = append(modules, ModuleLevel{Module: module, Level: level.String()})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Module < modules[j].Module })
+	return l.base.String(), modules
+}
+
+// ModuleLevel is one per-module override.
+type ModuleLevel struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+
+// levelCore filters entries by the level of the module that wrote them. The wrapped cores accept
+// every level.
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.minimum() && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.Enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -1,17 +1,31 @@
 package logger
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/noah-isme/sma-adp-api/pkg/buildinfo"
 	"github.com/noah-isme/sma-adp-api/pkg/config"
+	"github.com/noah-isme/sma-adp-api/pkg/middleware/clientip"
 	"github.com/noah-isme/sma-adp-api/pkg/middleware/requestid"
 )
 
-func New(cfg *config.Config) (*zap.Logger, error) {
+// Logger is the application logger with its runtime-adjustable levels.
+type Logger struct {
+	*zap.Logger
+	// Levels changes the default and per-module levels while the application runs.
+	Levels *Levels
+	files  []*RotatingFile
+}
+
+// New builds the logger described by cfg.Log. Entries go to stdout and, when configured, to a
+// rotated file and an error-only rotated file. Loggers created with Named follow the level of
+// their module.
+func New(cfg *config.Config) (*Logger, error) {
 	var zapCfg zap.Config
 	if cfg.Env == config.EnvProduction {
 		zapCfg = zap.NewProductionConfig()
@@ -31,11 +45,67 @@ func New(cfg *config.Config) (*zap.Logger, error) {
 			zapCfg.Level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 		}
 	}
+	modules, err := ParseModuleLevels(cfg.Log.ModuleLevels)
+	if err != nil {
+		return nil, err
+	}
+	levels := NewLevels(zapCfg.Level.Level(), modules)
+	// Levels does the filtering, so the sinks themselves accept everything.
+	zapCfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
 
 	zapCfg.EncoderConfig.TimeKey = "timestamp"
 	zapCfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoder := zapcore.NewJSONEncoder(zapCfg.EncoderConfig)
+	if zapCfg.Encoding == "console" {
+		encoder = zapcore.NewConsoleEncoder(zapCfg.EncoderConfig)
+	}
+
+	l := &Logger{Levels: levels}
+	rotate := RotateConfig{MaxSizeMB: cfg.Log.MaxSizeMB, MaxBackups: cfg.Log.MaxBackups, MaxAge: cfg.Log.MaxAge}
+	var extra []zapcore.Core
+	for _, sink := range []struct {
+		path  string
+		level zapcore.Level
+	}{{cfg.Log.FilePath, zapcore.DebugLevel}, {cfg.Log.ErrorFilePath, zapcore.ErrorLevel}} {
+		if sink.path == "" {
+			continue
+		}
+		file, err := OpenRotatingFile(sink.path, rotate)
+		if err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+		l.files = append(l.files, file)
+		extra = append(extra, zapcore.NewCore(encoder, file, sink.level))
+	}
+
+	logr, err := zapCfg.Build(
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &levelCore{Core: zapcore.NewTee(append([]zapcore.Core{core}, extra...)...), levels: levels}
+		}),
+		// Added after WrapCore so the file sinks carry the version too.
+		zap.Fields(zap.String("version", buildinfo.Get().String())),
+	)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	l.Logger = logr
+	return l, nil
+}
 
-	return zapCfg.Build()
+// Close flushes the logger and closes the file sinks.
+func (l *Logger) Close() error {
+	if l.Logger != nil {
+		_ = l.Logger.Sync()
+	}
+	var errs []error
+	for _, file := range l.files {
+		if err := file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func GinMiddleware(l *zap.Logger) gin.HandlerFunc {
@@ -51,7 +121,7 @@ func GinMiddleware(l *zap.Logger) gin.HandlerFunc {
 			zap.String("path", c.Request.URL.Path),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
-			zap.String("ip", c.ClientIP()),
+			zap.String("ip", clientip.Resolve(c)),
 		}
 		if reqID != "" {
 			fields = append(fields, zap.String("request_id", reqID))
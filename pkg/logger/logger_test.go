@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.log")
+	file, err := OpenRotatingFile(path, RotateConfig{MaxSizeMB: 1, MaxBackups: 2})
+	require.NoError(t, err)
+	defer file.Close()
+	clock := time.Date(2024, 7, 15, 8, 0, 0, 0, time.UTC)
+	file.now = func() time.Time { return clock }
+
+	chunk := []byte(strings.Repeat("x", 600<<10) + "\n")
+	for i := 0; i < 5; i++ {
+		clock = clock.Add(time.Second)
+		_, err := file.Write(chunk)
+		require.NoError(t, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	// Every write past 1 MiB rotates; only the two newest backups survive.
+	assert.Equal(t, []string{"api-2024-07-15T08-00-04.000.log", "api-2024-07-15T08-00-05.000.log", "api.log"}, names)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(chunk)), info.Size())
+}
+
+func TestNewFiltersByModuleLevel(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{Env: config.EnvProduction, Log: config.LogConfig{
+		Level:         "info",
+		ModuleLevels:  []string{"scheduler=debug", "reports=error"},
+		FilePath:      filepath.Join(dir, "api.log"),
+		ErrorFilePath: filepath.Join(dir, "error.log"),
+	}}
+	logr, err := New(cfg)
+	require.NoError(t, err)
+
+	logr.Debug("root debug")
+	logr.Named("scheduler").Named("generator").Debug("scheduler debug")
+	logr.Named("reports").Warn("reports warn")
+	logr.Named("reports").Error("reports error")
+
+	logr.Levels.SetModule("scheduler", zapcore.WarnLevel)
+	logr.Named("scheduler").Info("scheduler info after change")
+	logr.Levels.ResetModule("reports")
+	logr.Named("reports").Info("reports info after reset")
+	require.NoError(t, logr.Close())
+
+	all, err := os.ReadFile(filepath.Join(dir, "api.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(all), "root debug")
+	assert.Contains(t, string(all), `"logger":"scheduler.generator"`)
+	assert.NotContains(t, string(all), "reports warn")
+	assert.Contains(t, string(all), "reports error")
+	assert.NotContains(t, string(all), "scheduler info after change")
+	assert.Contains(t, string(all), "reports info after reset")
+	assert.Contains(t, string(all), `"version"`)
+
+	errorsOnly, err := os.ReadFile(filepath.Join(dir, "error.log"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(errorsOnly), "\n"))
+	assert.Contains(t, string(errorsOnly), "reports error")
+
+	_, err = New(&config.Config{Log: config.LogConfig{ModuleLevels: []string{"scheduler"}}})
+	assert.EqualError(t, err, `invalid module log level "scheduler", want module=level`)
+}
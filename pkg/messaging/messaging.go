@@ -0,0 +1,221 @@
+// Package messaging sends SMS and WhatsApp messages through third-party gateways and parses the
+// delivery status callbacks they post back. Each channel is served by the provider chosen in
+// configuration, so callers depend only on Gateway.
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// Supported providers.
+const (
+	ProviderLog    = "log"
+	ProviderTwilio = "twilio"
+	// ProviderMeta is the WhatsApp Business Cloud API.
+	ProviderMeta = "meta"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Channel is how a message reaches the recipient's phone.
+type Channel string
+
+// Supported channels.
+const (
+	ChannelSMS      Channel = "SMS"
+	ChannelWhatsApp Channel = "WHATSAPP"
+)
+
+// Valid reports whether c is a supported channel.
+func (c Channel) Valid() bool {
+	return c == ChannelSMS || c == ChannelWhatsApp
+}
+
+// DeliveryStatus is a provider status normalised across gateways.
+type DeliveryStatus string
+
+// Delivery statuses reported by callbacks.
+const (
+	StatusSent      DeliveryStatus = "SENT"
+	StatusDelivered DeliveryStatus = "DELIVERED"
+	StatusFailed    DeliveryStatus = "FAILED"
+)
+
+var (
+	// ErrInvalidRecipient reports a phone number the provider will never deliver to, e.g. a landline
+	// or a number without WhatsApp. The message should be given up rather than retried.
+	ErrInvalidRecipient = errors.New("recipient cannot receive messages")
+	// ErrInvalidSignature reports a status callback that was not signed by the provider.
+	ErrInvalidSignature = errors.New("invalid callback signature")
+	// ErrUnknownProvider reports a callback for a provider that is not configured.
+	ErrUnknownProvider = errors.New("messaging provider is not configured")
+)
+
+// Message is one text addressed to one phone number in E.164 format.
+type Message struct {
+	To      string
+	Channel Channel
+	// Body is the full text, used by gateways that send free-form messages.
+	Body string
+	// TemplateParams fill the approved template of gateways that only start conversations from
+	// templates, such as the WhatsApp Business Cloud API.
+	TemplateParams []string
+}
+
+// StatusUpdate is one delivery status reported by a provider callback.
+type StatusUpdate struct {
+	MessageID string
+	Status    DeliveryStatus
+	Error     string
+}
+
+// Sender delivers messages and returns the provider's message id.
+type Sender interface {
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+// CallbackParser authenticates a provider's status callback and extracts its updates. Statuses
+// without an equivalent DeliveryStatus, such as "queued", are left out.
+type CallbackParser interface {
+	ParseCallback(r *http.Request, body []byte) ([]StatusUpdate, error)
+}
+
+// subscriptionVerifier answers the handshake of providers that confirm a callback URL before
+// posting to it.
+type subscriptionVerifier interface {
+	VerifySubscription(query url.Values) (string, error)
+}
+
+// Gateway routes messages to the sender configured for their channel and callbacks to the parser of
+// the provider that posted them.
+type Gateway struct {
+	senders   map[Channel]Sender
+	callbacks map[string]CallbackParser
+}
+
+// New builds the gateway described by cfg.
+func New(cfg config.MessagingConfig, logger *zap.Logger) (*Gateway, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	gateway := &Gateway{senders: make(map[Channel]Sender), callbacks: make(map[string]CallbackParser)}
+	var twilio *TwilioSender
+	var meta *MetaSender
+	for channel, provider := range map[Channel]string{ChannelSMS: cfg.SMSProvider, ChannelWhatsApp: cfg.WhatsAppProvider} {
+		switch provider {
+		case "", ProviderLog:
+			gateway.senders[channel] = NewLogSender(logger)
+		case ProviderTwilio:
+			if twilio == nil {
+				twilio = NewTwilioSender(TwilioConfig{
+					AccountSID:     cfg.TwilioAccountSID,
+					AuthToken:      cfg.TwilioAuthToken,
+					SMSFrom:        cfg.TwilioSMSFrom,
+					WhatsAppFrom:   cfg.TwilioWhatsAppFrom,
+					StatusCallback: callbackURL(cfg.CallbackURL, ProviderTwilio),
+					Timeout:        cfg.Timeout,
+				})
+				gateway.callbacks[ProviderTwilio] = twilio
+			}
+			gateway.senders[channel] = twilio
+		case ProviderMeta:
+			if channel != ChannelWhatsApp {
+				return nil, fmt.Errorf("messaging provider %q only sends WhatsApp messages", provider)
+			}
+			meta = NewMetaSender(MetaConfig{
+				PhoneNumberID:    cfg.MetaPhoneNumberID,
+				AccessToken:      cfg.MetaAccessToken,
+				AppSecret:        cfg.MetaAppSecret,
+				VerifyToken:      cfg.MetaVerifyToken,
+				Template:         cfg.MetaTemplate,
+				TemplateLanguage: cfg.MetaTemplateLanguage,
+				Timeout:          cfg.Timeout,
+			})
+			gateway.senders[channel] = meta
+			gateway.callbacks[ProviderMeta] = meta
+		default:
+			return nil, fmt.Errorf("unsupported messaging provider %q", provider)
+		}
+	}
+	return gateway, nil
+}
+
+// Send delivers msg through the sender of its channel.
+func (g *Gateway) Send(ctx context.Context, msg Message) (string, error) {
+	sender, ok := g.senders[msg.Channel]
+	if !ok {
+		return "", fmt.Errorf("unsupported messaging channel %q", msg.Channel)
+	}
+	return sender.Send(ctx, msg)
+}
+
+// ParseCallback authenticates and parses a status callback posted by provider.
+func (g *Gateway) ParseCallback(provider string, r *http.Request, body []byte) ([]StatusUpdate, error) {
+	parser, ok := g.callbacks[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return parser.ParseCallback(r, body)
+}
+
+// VerifySubscription answers a provider's callback URL handshake with the challenge to echo.
+func (g *Gateway) VerifySubscription(provider string, query url.Values) (string, error) {
+	verifier, ok := g.callbacks[provider].(subscriptionVerifier)
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+	return verifier.VerifySubscription(query)
+}
+
+// callbackURL is where provider posts statuses; empty when callbacks are not configured.
+func callbackURL(base, provider string) string {
+	if base == "" {
+		return ""
+	}
+	return strings.TrimRight(base, "/") + "/" + provider
+}
+
+// LogSender writes messages to the log instead of a gateway, for development.
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender constructs a LogSender.
+func NewLogSender(logger *zap.Logger) *LogSender {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LogSender{logger: logger}
+}
+
+// Send logs msg with a masked phone number.
+func (s *LogSender) Send(_ context.Context, msg Message) (string, error) {
+	s.logger.Info("outbound message", zap.String("channel", string(msg.Channel)), zap.String("to", maskPhone(msg.To)), zap.String("body", msg.Body))
+	return "log-" + uuid.NewString(), nil
+}
+
+// maskPhone keeps guardians' phone numbers out of full log lines.
+func maskPhone(phone string) string {
+	if len(phone) <= 4 {
+		return phone
+	}
+	return strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
+
+func httpClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
@@ -0,0 +1,129 @@
+package messaging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func TestTwilioSender(t *testing.T) {
+	var forms []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "secret", pass)
+		require.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+		if r.PostForm.Get("To") == "+620000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211,"message":"The 'To' number +620000 is not a valid phone number."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	callback := "https://api.example.sch.id/api/v1/messaging/callbacks/twilio"
+	sender := NewTwilioSender(TwilioConfig{AccountSID: "AC123", AuthToken: "secret", SMSFrom: "+15005550006", WhatsAppFrom: "+14155238886", StatusCallback: callback})
+	sender.apiURL = server.URL
+
+	id, err := sender.Send(context.Background(), Message{To: "+6281234567890", Channel: ChannelWhatsApp, Body: "Ani absen"})
+	require.NoError(t, err)
+	assert.Equal(t, "SM1", id)
+	assert.Equal(t, "whatsapp:+6281234567890", forms[0].Get("To"))
+	assert.Equal(t, "whatsapp:+14155238886", forms[0].Get("From"))
+	assert.Equal(t, callback, forms[0].Get("StatusCallback"))
+
+	_, err = sender.Send(context.Background(), Message{To: "+620000", Channel: ChannelSMS, Body: "Ani absen"})
+	assert.True(t, errors.Is(err, ErrInvalidRecipient))
+	assert.Equal(t, "+15005550006", forms[1].Get("From"))
+
+	form := url.Values{"MessageSid": {"SM1"}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/messaging/callbacks/twilio", nil)
+	req.Header.Set("X-Twilio-Signature", twilioSignature("secret", callback, form))
+	updates, err := sender.ParseCallback(req, []byte(form.Encode()))
+	require.NoError(t, err)
+	assert.Equal(t, []StatusUpdate{{MessageID: "SM1", Status: StatusFailed, Error: "twilio error 30003"}}, updates)
+
+	req.Header.Set("X-Twilio-Signature", twilioSignature("other", callback, form))
+	_, err = sender.ParseCallback(req, []byte(form.Encode()))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestMetaSender(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.Write([]byte(`{"messaging_product":"whatsapp","messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	sender := NewMetaSender(MetaConfig{PhoneNumberID: "123", AccessToken: "token-1", AppSecret: "app-secret", VerifyToken: "verify-1", Template: "absensi_siswa"})
+	sender.apiURL = server.URL
+
+	id, err := sender.Send(context.Background(), Message{To: "+6281234567890", Channel: ChannelWhatsApp, TemplateParams: []string{"Ani", "15-07-2024"}})
+	require.NoError(t, err)
+	assert.Equal(t, "wamid.1", id)
+	assert.Equal(t, "6281234567890", sent["to"])
+	template := sent["template"].(map[string]any)
+	assert.Equal(t, "absensi_siswa", template["name"])
+	assert.Equal(t, map[string]any{"code": "id"}, template["language"])
+	params := template["components"].([]any)[0].(map[string]any)["parameters"].([]any)
+	assert.Equal(t, map[string]any{"type": "text", "text": "Ani"}, params[0])
+
+	body := []byte(`{"entry":[{"changes":[{"value":{"statuses":[{"id":"wamid.1","status":"delivered"},{"id":"wamid.2","status":"read"},{"id":"wamid.3","status":"failed","errors":[{"code":131026,"title":"Message undeliverable"}]},{"id":"wamid.4","status":"accepted"}]}}]}]}`)
+	mac := hmac.New(sha256.New, []byte("app-secret"))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/messaging/callbacks/meta", nil)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	updates, err := sender.ParseCallback(req, body)
+	require.NoError(t, err)
+	assert.Equal(t, []StatusUpdate{
+		{MessageID: "wamid.1", Status: StatusDelivered},
+		{MessageID: "wamid.2", Status: StatusDelivered},
+		{MessageID: "wamid.3", Status: StatusFailed, Error: "whatsapp error 131026: Message undeliverable"},
+	}, updates)
+
+	req.Header.Set("X-Hub-Signature-256", "sha256=00")
+	_, err = sender.ParseCallback(req, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	challenge, err := sender.VerifySubscription(url.Values{"hub.mode": {"subscribe"}, "hub.verify_token": {"verify-1"}, "hub.challenge": {"42"}})
+	require.NoError(t, err)
+	assert.Equal(t, "42", challenge)
+	_, err = sender.VerifySubscription(url.Values{"hub.mode": {"subscribe"}, "hub.verify_token": {"guess"}, "hub.challenge": {"42"}})
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestGatewayRoutesByChannel(t *testing.T) {
+	gateway, err := New(config.MessagingConfig{SMSProvider: ProviderLog, WhatsAppProvider: ProviderMeta, MetaAppSecret: "app-secret"}, nil)
+	require.NoError(t, err)
+
+	id, err := gateway.Send(context.Background(), Message{To: "+6281234567890", Channel: ChannelSMS, Body: "Ani absen"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(id, "log-"))
+
+	_, err = gateway.ParseCallback(ProviderTwilio, httptest.NewRequest(http.MethodPost, "/", nil), nil)
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+	_, err = gateway.ParseCallback(ProviderMeta, httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{}`))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = New(config.MessagingConfig{SMSProvider: ProviderMeta, WhatsAppProvider: ProviderLog}, nil)
+	assert.EqualError(t, err, `messaging provider "meta" only sends WhatsApp messages`)
+}
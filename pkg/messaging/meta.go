@@ -0,0 +1,174 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const metaAPIURL = "https://graph.facebook.com/v19.0/%s/messages"
+
+// metaInvalidRecipient lists Cloud API error codes for numbers that will never receive messages.
+var metaInvalidRecipient = map[int]bool{
+	131026: true, // message undeliverable, e.g. the number is not on WhatsApp
+	131030: true, // recipient not in the allowed list of a test number
+}
+
+// MetaConfig configures a MetaSender.
+type MetaConfig struct {
+	PhoneNumberID string
+	AccessToken   string
+	// AppSecret signs status callbacks; VerifyToken answers the callback URL handshake.
+	AppSecret   string
+	VerifyToken string
+	// Template is the approved message template; its body parameters are Message.TemplateParams.
+	Template         string
+	TemplateLanguage string
+	Timeout          time.Duration
+}
+
+// MetaSender sends WhatsApp template messages through the WhatsApp Business Cloud API. Business
+// initiated conversations must start from an approved template, so Message.Body is not sent.
+type MetaSender struct {
+	cfg    MetaConfig
+	apiURL string
+	client *http.Client
+}
+
+type metaResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type metaCallback struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Statuses []struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+					Errors []struct {
+						Code  int    `json:"code"`
+						Title string `json:"title"`
+					} `json:"errors"`
+				} `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// NewMetaSender constructs a MetaSender.
+func NewMetaSender(cfg MetaConfig) *MetaSender {
+	if cfg.TemplateLanguage == "" {
+		cfg.TemplateLanguage = "id"
+	}
+	return &MetaSender{cfg: cfg, apiURL: fmt.Sprintf(metaAPIURL, cfg.PhoneNumberID), client: httpClient(cfg.Timeout)}
+}
+
+// Send sends msg as the configured template.
+func (s *MetaSender) Send(ctx context.Context, msg Message) (string, error) {
+	parameters := make([]map[string]string, len(msg.TemplateParams))
+	for i, param := range msg.TemplateParams {
+		parameters[i] = map[string]string{"type": "text", "text": param}
+	}
+	body, err := json.Marshal(map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                strings.TrimPrefix(msg.To, "+"),
+		"type":              "template",
+		"template": map[string]any{
+			"name":       s.cfg.Template,
+			"language":   map[string]string{"code": s.cfg.TemplateLanguage},
+			"components": []map[string]any{{"type": "body", "parameters": parameters}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode whatsapp message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("build whatsapp request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send whatsapp message: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var result metaResponse
+	_ = json.Unmarshal(raw, &result)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && len(result.Messages) > 0 {
+		return result.Messages[0].ID, nil
+	}
+	if metaInvalidRecipient[result.Error.Code] {
+		return "", fmt.Errorf("%w: whatsapp error %d", ErrInvalidRecipient, result.Error.Code)
+	}
+	message := result.Error.Message
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+	return "", fmt.Errorf("whatsapp returned %d: %s", resp.StatusCode, message)
+}
+
+// ParseCallback verifies X-Hub-Signature-256 with the app secret and maps every reported status.
+func (s *MetaSender) ParseCallback(r *http.Request, body []byte) ([]StatusUpdate, error) {
+	mac := hmac.New(sha256.New, []byte(s.cfg.AppSecret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Hub-Signature-256"))) {
+		return nil, ErrInvalidSignature
+	}
+	var payload metaCallback
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("parse whatsapp callback: %w", err)
+	}
+	var updates []StatusUpdate
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			for _, status := range change.Value.Statuses {
+				update := StatusUpdate{MessageID: status.ID}
+				switch status.Status {
+				case "sent":
+					update.Status = StatusSent
+				case "delivered", "read":
+					update.Status = StatusDelivered
+				case "failed":
+					update.Status = StatusFailed
+					update.Error = "whatsapp delivery failed"
+					if len(status.Errors) > 0 {
+						update.Error = fmt.Sprintf("whatsapp error %d: %s", status.Errors[0].Code, status.Errors[0].Title)
+					}
+				default:
+					continue
+				}
+				updates = append(updates, update)
+			}
+		}
+	}
+	return updates, nil
+}
+
+// VerifySubscription answers the webhook handshake: the challenge is echoed when the verify token
+// matches.
+func (s *MetaSender) VerifySubscription(query url.Values) (string, error) {
+	if query.Get("hub.mode") != "subscribe" || s.cfg.VerifyToken == "" ||
+		!hmac.Equal([]byte(query.Get("hub.verify_token")), []byte(s.cfg.VerifyToken)) {
+		return "", ErrInvalidSignature
+	}
+	return query.Get("hub.challenge"), nil
+}
@@ -0,0 +1,140 @@
+package messaging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const twilioAPIURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+// twilioInvalidRecipient lists Twilio error codes for numbers that will never receive messages.
+var twilioInvalidRecipient = map[int]bool{
+	21211: true, // invalid "To" number
+	21408: true, // region not enabled
+	21610: true, // recipient replied STOP
+	21614: true, // not a mobile number
+	63003: true, // number not on WhatsApp
+}
+
+// TwilioConfig configures a TwilioSender.
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// SMSFrom and WhatsAppFrom are the sender numbers of each channel in E.164 format.
+	SMSFrom      string
+	WhatsAppFrom string
+	// StatusCallback is the public URL Twilio posts statuses to; callbacks are signed against it.
+	StatusCallback string
+	Timeout        time.Duration
+}
+
+// TwilioSender sends SMS and WhatsApp messages through the Twilio Programmable Messaging API.
+type TwilioSender struct {
+	cfg    TwilioConfig
+	apiURL string
+	client *http.Client
+}
+
+type twilioMessage struct {
+	SID     string `json:"sid"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewTwilioSender constructs a TwilioSender.
+func NewTwilioSender(cfg TwilioConfig) *TwilioSender {
+	return &TwilioSender{cfg: cfg, apiURL: fmt.Sprintf(twilioAPIURL, cfg.AccountSID), client: httpClient(cfg.Timeout)}
+}
+
+// Send creates a message. WhatsApp addresses carry Twilio's "whatsapp:" prefix.
+func (s *TwilioSender) Send(ctx context.Context, msg Message) (string, error) {
+	to, from := msg.To, s.cfg.SMSFrom
+	if msg.Channel == ChannelWhatsApp {
+		to, from = "whatsapp:"+msg.To, "whatsapp:"+s.cfg.WhatsAppFrom
+	}
+	form := url.Values{"To": {to}, "From": {from}, "Body": {msg.Body}}
+	if s.cfg.StatusCallback != "" {
+		form.Set("StatusCallback", s.cfg.StatusCallback)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build twilio request: %w", err)
+	}
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send twilio message: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var result twilioMessage
+	_ = json.Unmarshal(raw, &result)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && result.SID != "" {
+		return result.SID, nil
+	}
+	if twilioInvalidRecipient[result.Code] {
+		return "", fmt.Errorf("%w: twilio error %d", ErrInvalidRecipient, result.Code)
+	}
+	message := result.Message
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+	return "", fmt.Errorf("twilio returned %d: %s", resp.StatusCode, message)
+}
+
+// ParseCallback verifies X-Twilio-Signature against the configured status callback URL and maps
+// the reported MessageStatus.
+func (s *TwilioSender) ParseCallback(r *http.Request, body []byte) ([]StatusUpdate, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse twilio callback: %w", err)
+	}
+	expected := twilioSignature(s.cfg.AuthToken, s.cfg.StatusCallback, form)
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Twilio-Signature"))) {
+		return nil, ErrInvalidSignature
+	}
+	update := StatusUpdate{MessageID: form.Get("MessageSid")}
+	switch form.Get("MessageStatus") {
+	case "sent":
+		update.Status = StatusSent
+	case "delivered", "read":
+		update.Status = StatusDelivered
+	case "undelivered", "failed":
+		update.Status = StatusFailed
+		update.Error = "twilio error " + form.Get("ErrorCode")
+	default:
+		return nil, nil
+	}
+	return []StatusUpdate{update}, nil
+}
+
+// twilioSignature is base64(HMAC-SHA1(authToken, url + every parameter name and value sorted by name)).
+func twilioSignature(authToken, callbackURL string, form url.Values) string {
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(callbackURL)
+	for _, key := range keys {
+		for _, value := range form[key] {
+			b.WriteString(key)
+			b.WriteString(value)
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(b.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
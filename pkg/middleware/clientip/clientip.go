@@ -0,0 +1,61 @@
+package clientip
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Options describes the proxies in front of the API.
+type Options struct {
+	// TrustedProxies lists addresses or CIDRs whose forwarding headers are honoured. Empty trusts
+	// none, so the direct peer address is used.
+	TrustedProxies []string
+	// Platform names a CDN whose client IP header is preferred ("cloudflare", "google", "flyio") or a
+	// custom header name. It is only honoured from trusted proxies.
+	Platform string
+}
+
+var platformHeaders = map[string]string{
+	"cloudflare": gin.PlatformCloudflare,
+	"google":     gin.PlatformGoogleAppEngine,
+	"flyio":      gin.PlatformFlyIO,
+}
+
+// Configure applies opts to engine. Unlike gin's TrustedPlatform, which trusts the platform header
+// from any peer, the header is added to RemoteIPHeaders so it is ignored unless the request came
+// through a trusted proxy.
+func Configure(engine *gin.Engine, opts Options) error {
+	proxies := opts.TrustedProxies
+	if len(proxies) == 0 {
+		proxies = nil
+	}
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	headers := []string{"X-Forwarded-For", "X-Real-IP"}
+	if platform := strings.TrimSpace(opts.Platform); platform != "" {
+		header, ok := platformHeaders[strings.ToLower(platform)]
+		if !ok {
+			header = http.CanonicalHeaderKey(platform)
+		}
+		if len(proxies) == 0 {
+			return fmt.Errorf("platform %q requires TRUSTED_PROXIES listing the platform's edge addresses", platform)
+		}
+		headers = append([]string{header}, headers...)
+	}
+	engine.RemoteIPHeaders = headers
+	engine.TrustedPlatform = ""
+	return nil
+}
+
+// Resolve returns the client address recorded in audit logs, refresh tokens and login attempts.
+func Resolve(c *gin.Context) string {
+	if ip := c.ClientIP(); ip != "" {
+		return ip
+	}
+	return c.RemoteIP()
+}
@@ -0,0 +1,49 @@
+package clientip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resolve(t *testing.T, opts Options, remoteAddr string, headers map[string]string) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	require.NoError(t, Configure(engine, opts))
+
+	var got string
+	engine.GET("/", func(c *gin.Context) { got = Resolve(c) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+func TestResolveHonoursOnlyTrustedProxies(t *testing.T) {
+	forwarded := map[string]string{"X-Forwarded-For": "203.0.113.7"}
+
+	assert.Equal(t, "10.0.0.5", resolve(t, Options{}, "10.0.0.5:4000", forwarded))
+	assert.Equal(t, "203.0.113.7", resolve(t, Options{TrustedProxies: []string{"10.0.0.0/8"}}, "10.0.0.5:4000", forwarded))
+	assert.Equal(t, "198.51.100.9", resolve(t, Options{TrustedProxies: []string{"10.0.0.0/8"}}, "198.51.100.9:4000", forwarded))
+}
+
+func TestResolvePlatformHeader(t *testing.T) {
+	opts := Options{TrustedProxies: []string{"173.245.48.0/20"}, Platform: "cloudflare"}
+	headers := map[string]string{"CF-Connecting-IP": "203.0.113.7", "X-Forwarded-For": "192.0.2.1"}
+
+	assert.Equal(t, "203.0.113.7", resolve(t, opts, "173.245.48.10:443", headers))
+	assert.Equal(t, "198.51.100.9", resolve(t, opts, "198.51.100.9:443", headers), "platform header from an untrusted peer must be ignored")
+}
+
+func TestConfigureRejectsInvalidOptions(t *testing.T) {
+	assert.Error(t, Configure(gin.New(), Options{TrustedProxies: []string{"not-an-ip"}}))
+	assert.Error(t, Configure(gin.New(), Options{Platform: "cloudflare"}))
+}
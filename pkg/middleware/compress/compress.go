@@ -0,0 +1,269 @@
+package compress
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultContentTypes are compressed when Options.ContentTypes is empty. Already compressed
+// downloads (PDF, zip, xlsx, images) are left out on purpose.
+var DefaultContentTypes = []string{"application/json", "application/problem+json", "text/", "application/javascript", "image/svg+xml"}
+
+// DefaultMinSize is used when Options.MinSize is not positive; smaller bodies gain nothing once the
+// encoding overhead is paid.
+const DefaultMinSize = 1024
+
+// Observer receives the size of every compressed response before and after encoding. Route is the
+// matched route pattern and is empty for unmatched requests.
+type Observer func(route, encoding string, originalBytes, compressedBytes int64)
+
+// Options tunes which responses are compressed.
+type Options struct {
+	// MinSize is the smallest body, in bytes, that is compressed.
+	MinSize int
+	// ContentTypes lists media types, or prefixes ending in "/", that may be compressed.
+	ContentTypes []string
+	// Level is the gzip level; 0 uses gzip.DefaultCompression.
+	Level    int
+	Observer Observer
+}
+
+// encoder is a content coding the middleware can produce.
+type encoder struct {
+	name string
+	pool *sync.Pool
+}
+
+// Middleware compresses responses for clients that accept it. Bodies are buffered until MinSize
+// bytes are written, so small responses go out untouched. Responses that already carry a
+// Content-Encoding, have no body by definition or are not of an allowed content type are never
+// compressed.
+func Middleware(opts Options) gin.HandlerFunc {
+	if opts.MinSize <= 0 {
+		opts.MinSize = DefaultMinSize
+	}
+	if len(opts.ContentTypes) == 0 {
+		opts.ContentTypes = DefaultContentTypes
+	}
+	level := opts.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		level = gzip.DefaultCompression
+	}
+	encoders := []encoder{
+		{name: "gzip", pool: &sync.Pool{New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}}},
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		enc, ok := negotiate(c.GetHeader("Accept-Encoding"), encoders)
+		if !ok {
+			c.Next()
+			return
+		}
+		// The response differs by Accept-Encoding whether or not this one ends up compressed.
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		w := &compressWriter{ResponseWriter: c.Writer, opts: &opts, enc: enc}
+		c.Writer = w
+		// Restored even on panic, so the recovery middleware writes its error response directly.
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Next()
+		w.route = c.FullPath()
+		w.finish()
+	}
+}
+
+// negotiate picks the first encoder the client accepts with a non-zero quality.
+func negotiate(header string, encoders []encoder) (encoder, bool) {
+	if header == "" {
+		return encoder{}, false
+	}
+	accepted := map[string]bool{}
+	wildcard := false
+	for _, part := range strings.Split(header, ",") {
+		name, q := parseCoding(part)
+		if name == "*" {
+			wildcard = q > 0
+			continue
+		}
+		accepted[name] = q > 0
+	}
+	for _, enc := range encoders {
+		if ok, listed := accepted[enc.name]; listed {
+			if ok {
+				return enc, true
+			}
+			continue
+		}
+		if wildcard {
+			return enc, true
+		}
+	}
+	return encoder{}, false
+}
+
+func parseCoding(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	name := strings.ToLower(strings.TrimSpace(fields[0]))
+	q := 1.0
+	for _, param := range fields[1:] {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(strings.TrimSpace(key), "q") {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = parsed
+			}
+		}
+	}
+	return name, q
+}
+
+// compressWriter buffers the start of a body to decide whether to compress it.
+type compressWriter struct {
+	gin.ResponseWriter
+	opts  *Options
+	enc   encoder
+	route string
+
+	buf      []byte
+	decided  bool
+	gz       *gzip.Writer
+	counter  *countingWriter
+	original int64
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.decided {
+		if w.gz == nil {
+			return w.ResponseWriter.Write(p)
+		}
+		w.original += int64(len(p))
+		return w.gz.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.opts.MinSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what has been buffered so far; streamed responses are compressed only when enough of
+// them arrived before the first flush.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(len(w.buf) >= w.opts.MinSize)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.Hijack()
+}
+
+// decide settles the encoding and writes the buffered bytes. large reports whether the body reached
+// MinSize.
+func (w *compressWriter) decide(large bool) error {
+	w.decided = true
+	buffered := w.buf
+	w.buf = nil
+	if large && w.compressible(buffered) {
+		header := w.ResponseWriter.Header()
+		header.Set("Content-Encoding", w.enc.name)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			// The encoded body is a different representation, so a strong validator no longer holds.
+			header.Set("ETag", "W/"+etag)
+		}
+		w.counter = &countingWriter{w: w.ResponseWriter}
+		w.gz = w.enc.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.counter)
+		w.original = int64(len(buffered))
+		_, err := w.gz.Write(buffered)
+		return err
+	}
+	if len(buffered) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+func (w *compressWriter) compressible(body []byte) bool {
+	status := w.ResponseWriter.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	header := w.ResponseWriter.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range w.opts.ContentTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// finish writes out a body that stayed below MinSize or completes the compressed stream.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+		return
+	}
+	if w.gz == nil {
+		return
+	}
+	_ = w.gz.Close()
+	w.gz.Reset(io.Discard)
+	w.enc.pool.Put(w.gz)
+	w.gz = nil
+	if w.opts.Observer != nil {
+		w.opts.Observer(w.route, w.enc.name, w.original, w.counter.n)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,110 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	route      string
+	encoding   string
+	original   int64
+	compressed int64
+}
+
+func serve(t *testing.T, opts Options, acceptEncoding, path string) (*httptest.ResponseRecorder, []observation) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	var observed []observation
+	opts.Observer = func(route, encoding string, original, compressed int64) {
+		observed = append(observed, observation{route, encoding, original, compressed})
+	}
+	large := strings.Repeat(`{"studentId":"stu-1","status":"H"},`, 200)
+	r := gin.New()
+	r.Use(Middleware(opts))
+	r.GET("/roster", func(c *gin.Context) { c.String(http.StatusOK, large) })
+	r.GET("/json", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"rows": large}) })
+	r.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	r.GET("/report.pdf", func(c *gin.Context) { c.Data(http.StatusOK, "application/pdf", []byte(large)) })
+	r.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, observed
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	r, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestMiddlewareCompressesLargeJSON(t *testing.T) {
+	w, observed := serve(t, Options{}, "br;q=1.0, gzip;q=0.8", "/json")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	compressedSize := int64(w.Body.Len())
+	body := gunzip(t, w.Body)
+	assert.True(t, strings.HasPrefix(body, `{"rows":`))
+
+	require.Len(t, observed, 1)
+	assert.Equal(t, observation{route: "/json", encoding: "gzip", original: int64(len(body)), compressed: compressedSize}, observed[0])
+	assert.Less(t, observed[0].compressed, observed[0].original)
+}
+
+func TestMiddlewareSkipsSmallUnacceptedAndCompressedResponses(t *testing.T) {
+	w, observed := serve(t, Options{}, "gzip", "/small")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	assert.Empty(t, observed)
+
+	w, _ = serve(t, Options{}, "", "/json")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Header().Get("Vary"))
+
+	w, _ = serve(t, Options{}, "gzip;q=0, identity", "/json")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w, _ = serve(t, Options{}, "gzip", "/report.pdf")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+
+	// A body the handler encoded itself is passed through untouched.
+	w, observed = serve(t, Options{}, "gzip", "/encoded")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `{"studentId"`))
+	assert.Empty(t, observed)
+}
+
+func TestMiddlewareHonoursOptions(t *testing.T) {
+	// text/plain is not listed, so the roster string goes out as is.
+	w, _ := serve(t, Options{ContentTypes: []string{"application/json"}}, "*", "/roster")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w, _ = serve(t, Options{ContentTypes: []string{"text/"}}, "*", "/roster")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	w, _ = serve(t, Options{MinSize: 1 << 20}, "gzip", "/json")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `{"rows":`))
+}
@@ -1,51 +1,199 @@
 package cors
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// New returns a simple CORS middleware that honors a list of allowed origins.
+var (
+	defaultMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+	defaultHeaders = []string{"Authorization", "Content-Type", "X-Requested-With", "X-Request-ID"}
+)
+
+// Options configures the CORS middleware. Empty method/header lists fall back to defaults and an
+// empty origin list allows every origin.
+type Options struct {
+	// AllowedOrigins accepts exact origins ("https://app.school.sch.id"), "*" and subdomain
+	// wildcards with or without scheme ("https://*.school.sch.id", "*.school.sch.id").
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           time.Duration
+	AllowCredentials bool
+	// RequireOrigins rejects an empty origin list, which would otherwise allow every origin.
+	RequireOrigins bool
+}
+
+// Validate reports every misconfiguration at once.
+func (o Options) Validate() error {
+	var errs []error
+	if o.RequireOrigins && len(o.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("no allowed origins configured; set ALLOWED_ORIGINS"))
+	}
+	for _, origin := range o.AllowedOrigins {
+		if origin == "*" {
+			if o.AllowCredentials {
+				errs = append(errs, errors.New(`origin "*" cannot be combined with credentials; list explicit origins or disable CORS_ALLOW_CREDENTIALS`))
+			}
+			continue
+		}
+		if _, err := parseOrigin(origin); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, method := range o.AllowedMethods {
+		if method == "" || strings.ToUpper(method) != method || strings.ContainsAny(method, " ,") {
+			errs = append(errs, fmt.Errorf("invalid method %q: use upper-case HTTP method names", method))
+		}
+	}
+	if o.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("max age must not be negative, got %s", o.MaxAge))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid cors configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// New returns a CORS middleware honoring a list of allowed origins with default settings.
 func New(allowedOrigins []string) gin.HandlerFunc {
-	allowAll := len(allowedOrigins) == 0
-	originSet := make(map[string]struct{}, len(allowedOrigins))
-	for _, origin := range allowedOrigins {
-		originSet[strings.TrimRight(origin, "/")] = struct{}{}
+	handler, err := NewWithOptions(Options{AllowedOrigins: allowedOrigins, MaxAge: 10 * time.Minute, AllowCredentials: true})
+	if err != nil {
+		panic(err)
 	}
+	return handler
+}
 
-	return func(c *gin.Context) {
-		origin := c.GetHeader("Origin")
-		if origin != "" {
-			if allowAll || hasOrigin(originSet, origin) {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+// NewWithOptions validates opts and returns the middleware.
+func NewWithOptions(opts Options) (gin.HandlerFunc, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = defaultMethods
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = defaultHeaders
+	}
+
+	allowAll := len(opts.AllowedOrigins) == 0
+	exact := make(map[string]struct{}, len(opts.AllowedOrigins))
+	var wildcards []originPattern
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+			continue
+		}
+		pattern, _ := parseOrigin(origin)
+		if pattern.wildcard {
+			wildcards = append(wildcards, pattern)
+			continue
+		}
+		exact[pattern.String()] = struct{}{}
+	}
+	matches := func(origin string) bool {
+		if allowAll {
+			return true
+		}
+		origin = strings.ToLower(strings.TrimRight(origin, "/"))
+		if _, ok := exact[origin]; ok {
+			return true
+		}
+		for _, pattern := range wildcards {
+			if pattern.matches(origin) {
+				return true
 			}
-		} else if allowAll {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		}
+		return false
+	}
+
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge / time.Second))
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
 
-		c.Writer.Header().Set("Vary", "Origin")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Requested-With, X-Request-ID")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Max-Age", "600")
+		origin := c.GetHeader("Origin")
+		allowed := false
+		switch {
+		case origin != "" && matches(origin):
+			allowed = true
+			header.Set("Access-Control-Allow-Origin", origin)
+		case origin == "" && allowAll && !opts.AllowCredentials:
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+		if allowed && opts.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
 
 		if c.Request.Method == http.MethodOptions {
+			if allowed {
+				header.Set("Access-Control-Allow-Methods", methods)
+				header.Set("Access-Control-Allow-Headers", headers)
+				if opts.MaxAge > 0 {
+					header.Set("Access-Control-Max-Age", maxAge)
+				}
+			}
 			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
+		if allowed && exposed != "" {
+			header.Set("Access-Control-Expose-Headers", exposed)
+		}
 
 		c.Next()
-	}
+	}, nil
+}
+
+// originPattern is a parsed allowed origin. An empty scheme matches any scheme.
+type originPattern struct {
+	scheme   string
+	host     string
+	wildcard bool
 }
 
-func hasOrigin(originSet map[string]struct{}, origin string) bool {
-	if len(originSet) == 0 {
-		return true
+func parseOrigin(raw string) (originPattern, error) {
+	value := strings.ToLower(strings.TrimRight(strings.TrimSpace(raw), "/"))
+	var pattern originPattern
+	if scheme, rest, ok := strings.Cut(value, "://"); ok {
+		if scheme != "http" && scheme != "https" {
+			return pattern, fmt.Errorf("origin %q: scheme must be http or https", raw)
+		}
+		pattern.scheme = scheme
+		value = rest
+	}
+	if strings.HasPrefix(value, "*.") {
+		pattern.wildcard = true
+		value = strings.TrimPrefix(value, "*")
+	}
+	if value == "" || value == "." || strings.ContainsAny(value, "*/ ") {
+		return pattern, fmt.Errorf("origin %q: only a leading \"*.\" wildcard is supported", raw)
 	}
+	if pattern.scheme == "" && !pattern.wildcard {
+		return pattern, fmt.Errorf("origin %q: exact origins need a scheme, e.g. https://%s", raw, value)
+	}
+	pattern.host = value
+	return pattern, nil
+}
 
-	origin = strings.TrimRight(origin, "/")
-	_, ok := originSet[origin]
-	return ok
+func (p originPattern) String() string {
+	return p.scheme + "://" + p.host
+}
+
+func (p originPattern) matches(origin string) bool {
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || (p.scheme != "" && p.scheme != scheme) {
+		return false
+	}
+	return strings.HasSuffix(host, p.host) && len(host) > len(p.host)
 }
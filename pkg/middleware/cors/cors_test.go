@@ -0,0 +1,90 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, opts Options, method, origin string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler, err := NewWithOptions(opts)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(handler)
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/ping", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPreflightHonoursConfiguration(t *testing.T) {
+	opts := Options{
+		AllowedOrigins:   []string{"https://*.school.sch.id"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Authorization"},
+		MaxAge:           time.Hour,
+		AllowCredentials: true,
+	}
+
+	w := serve(t, opts, http.MethodOptions, "https://portal.school.sch.id")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://portal.school.sch.id", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+	for _, origin := range []string{"http://portal.school.sch.id", "https://school.sch.id", "https://evilschool.sch.id"} {
+		w = serve(t, opts, http.MethodOptions, origin)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		assert.Empty(t, w.Header().Get("Access-Control-Max-Age"), origin)
+	}
+}
+
+func TestSimpleRequestWithoutCredentials(t *testing.T) {
+	opts := Options{
+		AllowedOrigins: []string{"http://localhost:5173"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	}
+
+	w := serve(t, opts, http.MethodGet, "http://localhost:5173")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "http://localhost:5173", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestValidateRejectsMisconfiguration(t *testing.T) {
+	cases := map[string]Options{
+		"wildcard with credentials": {AllowedOrigins: []string{"*"}, AllowCredentials: true},
+		"missing scheme":            {AllowedOrigins: []string{"app.school.sch.id"}},
+		"inner wildcard":            {AllowedOrigins: []string{"https://app.*.sch.id"}},
+		"unsupported scheme":        {AllowedOrigins: []string{"ftp://app.school.sch.id"}},
+		"lower-case method":         {AllowedMethods: []string{"get"}},
+		"negative max age":          {MaxAge: -time.Second},
+		"origins required":          {RequireOrigins: true},
+	}
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewWithOptions(opts)
+			assert.Error(t, err)
+		})
+	}
+
+	assert.NoError(t, Options{AllowedOrigins: []string{"*"}}.Validate())
+	assert.NoError(t, Options{AllowedOrigins: []string{"*.school.sch.id", "https://school.sch.id/"}, AllowCredentials: true}.Validate())
+}
@@ -0,0 +1,53 @@
+package secureheaders
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Options selects the headers to emit. Zero values disable the corresponding header.
+type Options struct {
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	ContentTypeNosniff    bool
+	FrameOptions          string
+	ReferrerPolicy        string
+	// DocsPolicy is the Content-Security-Policy sent for requests under DocsPrefix.
+	DocsPolicy string
+	DocsPrefix string
+}
+
+// Middleware sets the configured security headers on every response.
+func Middleware(opts Options) gin.HandlerFunc {
+	static := make(map[string]string)
+	if opts.HSTSMaxAge > 0 {
+		hsts := "max-age=" + strconv.FormatInt(int64(opts.HSTSMaxAge/time.Second), 10)
+		if opts.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		static["Strict-Transport-Security"] = hsts
+	}
+	if opts.ContentTypeNosniff {
+		static["X-Content-Type-Options"] = "nosniff"
+	}
+	if opts.FrameOptions != "" {
+		static["X-Frame-Options"] = opts.FrameOptions
+	}
+	if opts.ReferrerPolicy != "" {
+		static["Referrer-Policy"] = opts.ReferrerPolicy
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		for key, value := range static {
+			header.Set(key, value)
+		}
+		if opts.DocsPolicy != "" && opts.DocsPrefix != "" && strings.HasPrefix(c.Request.URL.Path, opts.DocsPrefix) {
+			header.Set("Content-Security-Policy", opts.DocsPolicy)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package secureheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(opts Options, path string) http.Header {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(opts))
+	r.GET("/*any", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Header()
+}
+
+func TestMiddlewareSetsConfiguredHeaders(t *testing.T) {
+	opts := Options{
+		HSTSMaxAge:            24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeNosniff:    true,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		DocsPolicy:            "default-src 'self'",
+		DocsPrefix:            "/docs",
+	}
+
+	header := serve(opts, "/api/v1/students")
+	assert.Equal(t, "max-age=86400; includeSubDomains", header.Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+	assert.Empty(t, header.Get("Content-Security-Policy"))
+
+	header = serve(opts, "/docs/index.html")
+	assert.Equal(t, "default-src 'self'", header.Get("Content-Security-Policy"))
+}
+
+func TestMiddlewareSkipsDisabledHeaders(t *testing.T) {
+	header := serve(Options{ContentTypeNosniff: true}, "/docs/index.html")
+	assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+	for _, key := range []string{"Strict-Transport-Security", "X-Frame-Options", "Referrer-Policy", "Content-Security-Policy"} {
+		assert.Empty(t, header.Get(key), key)
+	}
+}
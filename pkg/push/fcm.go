@@ -0,0 +1,191 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope       = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL     = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	googleTokenURL = "https://oauth2.googleapis.com/token"
+	// fcmTokenSkew renews the access token this long before it expires.
+	fcmTokenSkew   = time.Minute
+	defaultTimeout = 10 * time.Second
+)
+
+// FCMSender sends through the Firebase Cloud Messaging HTTP v1 API, authenticating with a service
+// account. Access tokens are cached until shortly before they expire.
+type FCMSender struct {
+	projectID   string
+	clientEmail string
+	key         *rsa.PrivateKey
+	tokenURL    string
+	sendURL     string
+	client      *http.Client
+	now         func() time.Time
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type fcmServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+type fcmError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// NewFCMSender constructs a sender from a service account JSON key.
+func NewFCMSender(credentials []byte, timeout time.Duration) (*FCMSender, error) {
+	var account fcmServiceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("decode fcm credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("fcm credentials must include project_id, client_email and private_key")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse fcm private key: %w", err)
+	}
+	tokenURL := account.TokenURI
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &FCMSender{
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		key:         key,
+		tokenURL:    tokenURL,
+		sendURL:     fmt.Sprintf(fcmSendURL, url.PathEscape(account.ProjectID)),
+		client:      &http.Client{Timeout: timeout},
+		now:         time.Now,
+	}, nil
+}
+
+// Send delivers msg. It returns ErrInvalidToken when FCM reports the token as unregistered.
+func (s *FCMSender) Send(ctx context.Context, msg Message) error {
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"token": msg.Token, "notification": map[string]string{"title": msg.Title, "body": msg.Body}}
+	if len(msg.Data) > 0 {
+		payload["data"] = msg.Data
+	}
+	body, err := json.Marshal(map[string]any{"message": payload})
+	if err != nil {
+		return fmt.Errorf("encode fcm message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build fcm request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send fcm message: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var result fcmError
+	_ = json.Unmarshal(raw, &result)
+	for _, detail := range result.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" || detail.ErrorCode == "SENDER_ID_MISMATCH" {
+			return ErrInvalidToken
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.resetToken()
+	}
+	message := result.Error.Message
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+	return fmt.Errorf("fcm returned %d: %s", resp.StatusCode, message)
+}
+
+// token returns a cached access token or exchanges a signed assertion for a new one.
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-fcmTokenSkew)) {
+		return s.accessToken, nil
+	}
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.clientEmail,
+		"scope": fcmScope,
+		"aud":   s.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("sign fcm assertion: %w", err)
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build fcm token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fcm token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("decode fcm access token: %s", strings.TrimSpace(string(raw)))
+	}
+	s.accessToken = result.AccessToken
+	s.expiresAt = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
+
+func (s *FCMSender) resetToken() {
+	s.mu.Lock()
+	s.accessToken = ""
+	s.mu.Unlock()
+}
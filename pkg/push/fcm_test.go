@@ -0,0 +1,83 @@
+package push
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFCMSenderSend(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tokenRequests := 0
+	var sent []map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+		require.NoError(t, err)
+		assert.Equal(t, "push@sma.iam.gserviceaccount.com", claims["iss"])
+		assert.Equal(t, fcmScope, claims["scope"])
+		w.Write([]byte(`{"access_token":"access-1","expires_in":3600}`))
+	})
+	mux.HandleFunc("/send", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		var body struct {
+			Message map[string]any `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Message["token"] == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		if body.Message["token"] == "busy" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":503,"message":"The service is currently unavailable.","status":"UNAVAILABLE"}}`))
+			return
+		}
+		sent = append(sent, body.Message)
+		w.Write([]byte(`{"name":"projects/sma/messages/1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"project_id":   "sma",
+		"client_email": "push@sma.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL + "/token",
+	})
+	require.NoError(t, err)
+	sender, err := NewFCMSender(credentials, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "https://fcm.googleapis.com/v1/projects/sma/messages:send", sender.sendURL)
+	sender.sendURL = server.URL + "/send"
+
+	ctx := context.Background()
+	require.NoError(t, sender.Send(ctx, Message{Token: "device-1", Title: "Pengumuman", Body: "Libur", Data: map[string]string{"type": "ANNOUNCEMENT"}}))
+	require.NoError(t, sender.Send(ctx, Message{Token: "device-2", Title: "Pengumuman", Body: "Libur"}))
+	assert.Equal(t, 1, tokenRequests)
+	require.Len(t, sent, 2)
+	assert.Equal(t, map[string]any{"title": "Pengumuman", "body": "Libur"}, sent[0]["notification"])
+	assert.Equal(t, map[string]any{"type": "ANNOUNCEMENT"}, sent[0]["data"])
+
+	assert.ErrorIs(t, sender.Send(ctx, Message{Token: "stale"}), ErrInvalidToken)
+	err = sender.Send(ctx, Message{Token: "busy"})
+	assert.EqualError(t, err, "fcm returned 503: The service is currently unavailable.")
+}
@@ -0,0 +1,83 @@
+// Package push delivers notifications to users' devices. The sender is chosen by configuration so
+// callers depend only on Sender.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// Supported providers.
+const (
+	ProviderLog = "log"
+	ProviderFCM = "fcm"
+)
+
+// ErrInvalidToken reports a device token the provider no longer accepts, e.g. because the app was
+// uninstalled. The token should be forgotten rather than retried.
+var ErrInvalidToken = errors.New("push token is no longer valid")
+
+// Message is one notification addressed to one device token.
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers messages to a push provider.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the sender selected by cfg.Provider.
+func New(cfg config.PushConfig, logger *zap.Logger) (Sender, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	switch cfg.Provider {
+	case "", ProviderLog:
+		return NewLogSender(logger), nil
+	case ProviderFCM:
+		credentials, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("read fcm credentials: %w", err)
+		}
+		return NewFCMSender(credentials, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported push provider %q", cfg.Provider)
+	}
+}
+
+// LogSender writes messages to the log instead of a provider, for development.
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender constructs a LogSender.
+func NewLogSender(logger *zap.Logger) *LogSender {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &LogSender{logger: logger}
+}
+
+// Send logs msg.
+func (s *LogSender) Send(_ context.Context, msg Message) error {
+	s.logger.Info("push notification", zap.String("token", truncateToken(msg.Token)), zap.String("title", msg.Title))
+	return nil
+}
+
+// truncateToken keeps device tokens, which identify a device, out of full log lines.
+func truncateToken(token string) string {
+	if len(token) <= 8 {
+		return token
+	}
+	return token[:8] + "…"
+}
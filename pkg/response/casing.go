@@ -0,0 +1,194 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Casing selects the naming convention used for JSON object keys in responses.
+type Casing string
+
+const (
+	// CasingAsTagged renders keys exactly as declared in the DTO json tags.
+	CasingAsTagged Casing = ""
+	// CasingCamel renders snake_case keys as camelCase, matching the legacy API.
+	CasingCamel Casing = "camel"
+	// CasingSnake renders camelCase keys as snake_case.
+	CasingSnake Casing = "snake"
+)
+
+const (
+	// AcceptProfileHeader lets a client pick the casing for a single request, overriding the route
+	// group default.
+	AcceptProfileHeader = "Accept-Profile"
+	// ContentProfileHeader echoes the casing applied to the response body.
+	ContentProfileHeader = "Content-Profile"
+
+	casingContextKey = "response_casing"
+)
+
+// ParseCasing accepts camel/camelCase, snake/snake_case or tagged (case-insensitive).
+func ParseCasing(raw string) (Casing, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "tagged":
+		return CasingAsTagged, nil
+	case "camel", "camelcase":
+		return CasingCamel, nil
+	case "snake", "snake_case":
+		return CasingSnake, nil
+	default:
+		return CasingAsTagged, fmt.Errorf("unknown response casing %q", raw)
+	}
+}
+
+// WithCasing sets the default casing for every response rendered below the route group it is
+// attached to.
+func WithCasing(casing Casing) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(casingContextKey, casing)
+		c.Next()
+	}
+}
+
+func casingFor(c *gin.Context) Casing {
+	if raw := c.GetHeader(AcceptProfileHeader); raw != "" {
+		if casing, err := ParseCasing(raw); err == nil {
+			return casing
+		}
+	}
+	if value, ok := c.Get(casingContextKey); ok {
+		if casing, ok := value.(Casing); ok {
+			return casing
+		}
+	}
+	return CasingAsTagged
+}
+
+// render writes envelope using the casing selected for the request.
+func render(c *gin.Context, status int, envelope Envelope) {
+	casing := casingFor(c)
+	if casing == CasingAsTagged {
+		c.JSON(status, envelope)
+		return
+	}
+	payload, err := recase(envelope, casing)
+	if err != nil {
+		c.JSON(status, envelope)
+		return
+	}
+	c.Header(ContentProfileHeader, string(casing))
+	c.JSON(status, payload)
+}
+
+// recase round-trips v through JSON and rewrites every object key. Numbers are kept as json.Number
+// so large integers and decimals are emitted unchanged.
+func recase(v interface{}, casing Casing) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	convert := toCamel
+	if casing == CasingSnake {
+		convert = toSnake
+	}
+	return rekey(decoded, convert), nil
+}
+
+func rekey(v interface{}, convert func(string) string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			target := convert(key)
+			if target != key {
+				// A key already in the target convention wins over a converted duplicate.
+				if _, exists := value[target]; exists {
+					continue
+				}
+			}
+			out[target] = rekey(item, convert)
+		}
+		return out
+	case []interface{}:
+		for i, item := range value {
+			value[i] = rekey(item, convert)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// toCamel converts lower snake_case identifiers ("class_id" -> "classId"). Keys that are not such
+// identifiers, such as dates, IDs or upper-case codes used as map keys, are returned unchanged.
+func toCamel(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	for _, part := range parts {
+		if part == "" {
+			return key
+		}
+		for _, r := range part {
+			if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') {
+				return key
+			}
+		}
+	}
+	if parts[0][0] < 'a' || parts[0][0] > 'z' {
+		return key
+	}
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// toSnake converts camelCase identifiers ("studentID" -> "student_id", "dateFrom" -> "date_from").
+// Keys that do not start with a lower-case letter or contain non-alphanumerics are returned unchanged.
+func toSnake(key string) string {
+	if key == "" || key[0] < 'a' || key[0] > 'z' {
+		return key
+	}
+	hasUpper := false
+	for _, r := range key {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return key
+		}
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		}
+	}
+	if !hasUpper {
+		return key
+	}
+	runes := []rune(key)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
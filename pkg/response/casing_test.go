@@ -0,0 +1,105 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/internal/models"
+)
+
+type casingFixture struct {
+	ClassID   string            `json:"class_id"`
+	FullName  string            `json:"full_name"`
+	DateFrom  string            `json:"dateFrom"`
+	Score     float64           `json:"score"`
+	StudentID int64             `json:"studentID"`
+	Days      map[string]string `json:"days"`
+}
+
+func serveCasing(t *testing.T, groupCasing Casing, profile string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	group := engine.Group("", WithCasing(groupCasing))
+	group.GET("/", func(c *gin.Context) {
+		JSON(c, http.StatusOK, []casingFixture{{
+			ClassID:   "10A",
+			FullName:  "Siti",
+			DateFrom:  "2024-02-01",
+			Score:     87.5,
+			StudentID: 9007199254740993,
+			Days:      map[string]string{"2024-02-01": "H", "ABS_CODE": "A"},
+		}}, &models.Pagination{Page: 1, PageSize: 20, TotalCount: 1})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if profile != "" {
+		req.Header.Set(AcceptProfileHeader, profile)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	return w
+}
+
+func TestJSONRendersGroupCasing(t *testing.T) {
+	w := serveCasing(t, CasingCamel, "")
+	body := w.Body.String()
+
+	assert.Equal(t, "camel", w.Header().Get(ContentProfileHeader))
+	assert.Contains(t, body, `"classId":"10A"`)
+	assert.Contains(t, body, `"fullName":"Siti"`)
+	assert.Contains(t, body, `"dateFrom":"2024-02-01"`)
+	assert.Contains(t, body, `"totalCount":1`)
+	assert.Contains(t, body, `"studentID":9007199254740993`)
+	assert.Contains(t, body, `"score":87.5`)
+	assert.Contains(t, body, `"2024-02-01":"H"`)
+	assert.Contains(t, body, `"ABS_CODE":"A"`)
+}
+
+func TestJSONAcceptProfileOverridesGroup(t *testing.T) {
+	w := serveCasing(t, CasingCamel, "snake_case")
+	body := w.Body.String()
+
+	assert.Equal(t, "snake", w.Header().Get(ContentProfileHeader))
+	assert.Contains(t, body, `"class_id":"10A"`)
+	assert.Contains(t, body, `"date_from":"2024-02-01"`)
+	assert.Contains(t, body, `"student_id":9007199254740993`)
+
+	tagged := serveCasing(t, CasingCamel, "tagged")
+	assert.Empty(t, tagged.Header().Get(ContentProfileHeader))
+	assert.Contains(t, tagged.Body.String(), `"class_id":"10A"`)
+	assert.Contains(t, tagged.Body.String(), `"dateFrom":"2024-02-01"`)
+}
+
+func TestErrorRendersRequestedCasing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) { Error(c, assert.AnError) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(AcceptProfileHeader, "camelCase")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "camel", w.Header().Get(ContentProfileHeader))
+	assert.Contains(t, w.Body.String(), `"code":`)
+}
+
+func TestCaseConversion(t *testing.T) {
+	camel := map[string]string{"class_id": "classId", "is_homeroom": "isHomeroom", "page": "page", "ABS_CODE": "ABS_CODE", "a__b": "a__b", "2024_1": "2024_1"}
+	for in, want := range camel {
+		assert.Equal(t, want, toCamel(in), in)
+	}
+	snake := map[string]string{"classId": "class_id", "studentID": "student_id", "totalCount": "total_count", "page": "page", "ID": "ID", "2024-02-01": "2024-02-01"}
+	for in, want := range snake {
+		assert.Equal(t, want, toSnake(in), in)
+	}
+
+	_, err := ParseCasing("kebab")
+	assert.Error(t, err)
+}
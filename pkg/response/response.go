@@ -1,6 +1,8 @@
 package response
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -25,7 +27,7 @@ func JSON(c *gin.Context, status int, data interface{}, pagination *models.Pagin
 	if len(meta) > 0 && meta[0] != nil {
 		envelope.Meta = meta[0]
 	}
-	c.JSON(status, envelope)
+	render(c, status, envelope)
 }
 
 // Created responds with HTTP 201 Created.
@@ -33,12 +35,21 @@ func Created(c *gin.Context, data interface{}) {
 	JSON(c, http.StatusCreated, data, nil)
 }
 
-// Error sends an error response converting the error to the common structure.
-func Error(c *gin.Context, err error) {
+// Error sends an error response converting the error to the common structure, with optional metadata.
+func Error(c *gin.Context, err error, meta ...map[string]interface{}) {
 	appErr := appErrors.FromError(err)
+	if appErr.Status >= http.StatusInternalServerError && c.Request != nil && errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) {
+		// The database driver reports a cancelled query in its own words, so a failure after the
+		// request budget ran out is reported as the timeout it is.
+		appErr = appErrors.Wrap(err, appErrors.ErrTimeout.Code, appErrors.ErrTimeout.Status, appErrors.ErrTimeout.Message)
+	}
 	c.Header("Cache-Control", "no-store")
 	c.Header("Pragma", "no-cache")
-	c.JSON(appErr.Status, Envelope{Error: appErr})
+	envelope := Envelope{Error: appErr}
+	if len(meta) > 0 && meta[0] != nil {
+		envelope.Meta = meta[0]
+	}
+	render(c, appErr.Status, envelope)
 }
 
 // NoContent sends a 204 response.
@@ -0,0 +1,158 @@
+// Package server runs the API's http.Server: plain HTTP, TLS from a certificate file or TLS with
+// certificates obtained from an ACME CA such as Let's Encrypt.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+// Mode describes how the server accepts connections.
+type Mode string
+
+const (
+	ModeHTTP    Mode = "http"
+	ModeTLSFile Mode = "tls"
+	ModeACME    Mode = "acme"
+)
+
+// Server wraps the main http.Server and, with ACME, the plain HTTP listener that answers HTTP-01
+// challenges and redirects everything else to HTTPS.
+type Server struct {
+	http     *http.Server
+	redirect *http.Server
+	mode     Mode
+	cfg      config.ServerConfig
+}
+
+// New configures a server for handler on addr.
+func New(addr string, handler http.Handler, cfg config.ServerConfig) (*Server, error) {
+	srv := &Server{http: newHTTPServer(addr, handler, cfg), mode: ModeHTTP, cfg: cfg}
+	switch {
+	case cfg.TLS.CertFile != "" || cfg.TLS.KeyFile != "":
+		if cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == "" {
+			return nil, errors.New("TLS needs both a certificate and a key file")
+		}
+		srv.mode = ModeTLSFile
+		srv.http.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	case len(cfg.TLS.ACMEDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.ACMEDomains...),
+			Cache:      autocert.DirCache(cfg.TLS.ACMECacheDir),
+			Email:      cfg.TLS.ACMEEmail,
+		}
+		if cfg.TLS.ACMEDirectoryURL != "" {
+			manager.Client = &acme.Client{DirectoryURL: cfg.TLS.ACMEDirectoryURL}
+		}
+		srv.mode = ModeACME
+		srv.http.TLSConfig = manager.TLSConfig()
+		srv.http.TLSConfig.MinVersion = tls.VersionTLS12
+		if cfg.TLS.HTTPPort != 0 {
+			srv.redirect = newHTTPServer(fmt.Sprintf(":%d", cfg.TLS.HTTPPort), manager.HTTPHandler(nil), cfg)
+		}
+	}
+	if srv.http.TLSConfig != nil && !cfg.HTTP2 {
+		// A non-nil, empty map stops net/http from negotiating HTTP/2.
+		srv.http.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		srv.http.TLSConfig.NextProtos = withoutProto(srv.http.TLSConfig.NextProtos, "h2")
+	}
+	return srv, nil
+}
+
+// NewPlain configures a plain HTTP server with the timeouts of cfg, for listeners that are never
+// exposed directly such as the metrics port.
+func NewPlain(addr string, handler http.Handler, cfg config.ServerConfig) *Server {
+	return &Server{http: newHTTPServer(addr, handler, cfg), mode: ModeHTTP, cfg: cfg}
+}
+
+func newHTTPServer(addr string, handler http.Handler, cfg config.ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Mode reports how the server accepts connections.
+func (s *Server) Mode() Mode {
+	return s.mode
+}
+
+// Addr is the address of the main listener.
+func (s *Server) Addr() string {
+	return s.http.Addr
+}
+
+// ListenAndServe serves until Shutdown. It returns nil after a clean shutdown.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves on ln until Shutdown. It returns nil after a clean shutdown.
+func (s *Server) Serve(ln net.Listener) error {
+	errs := make(chan error, 1)
+	if s.redirect != nil {
+		go func() {
+			if err := s.redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errs <- fmt.Errorf("acme http listener: %w", err)
+				_ = s.http.Close()
+			}
+		}()
+	}
+	var err error
+	switch s.mode {
+	case ModeTLSFile:
+		err = s.http.ServeTLS(ln, s.cfg.TLS.CertFile, s.cfg.TLS.KeyFile)
+	case ModeACME:
+		// Certificates come from TLSConfig.GetCertificate.
+		err = s.http.ServeTLS(ln, "", "")
+	default:
+		err = s.http.Serve(ln)
+	}
+	select {
+	case redirectErr := <-errs:
+		return redirectErr
+	default:
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests until ctx ends.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var redirectErr error
+	if s.redirect != nil {
+		redirectErr = s.redirect.Shutdown(ctx)
+	}
+	return errors.Join(s.http.Shutdown(ctx), redirectErr)
+}
+
+func withoutProto(protos []string, drop string) []string {
+	kept := make([]string, 0, len(protos))
+	for _, proto := range protos {
+		if !strings.EqualFold(proto, drop) {
+			kept = append(kept, proto)
+		}
+	}
+	return kept
+}
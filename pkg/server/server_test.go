@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/noah-isme/sma-adp-api/pkg/config"
+)
+
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// start serves srv on a random local port and returns its address; the server is shut down when the
+// test ends.
+func start(t *testing.T, srv *Server) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.NoError(t, srv.Shutdown(ctx))
+		assert.NoError(t, <-done)
+	})
+	return ln.Addr().String()
+}
+
+var protoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, r.Proto)
+})
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func tlsClient() *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed test certificate
+		ForceAttemptHTTP2: true,
+	}}
+}
+
+func TestServerServesPlainHTTP(t *testing.T) {
+	cfg := config.ServerConfig{ReadHeaderTimeout: time.Second, IdleTimeout: time.Second}
+	srv, err := New("", protoHandler, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ModeHTTP, srv.Mode())
+	assert.Equal(t, time.Second, srv.http.ReadHeaderTimeout)
+
+	addr := start(t, srv)
+	assert.Equal(t, "HTTP/1.1", get(t, http.DefaultClient, "http://"+addr))
+}
+
+func TestServerTerminatesTLSWithHTTP2(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	srv, err := New("", protoHandler, config.ServerConfig{HTTP2: true, TLS: config.TLSConfig{CertFile: certFile, KeyFile: keyFile}})
+	require.NoError(t, err)
+	assert.Equal(t, ModeTLSFile, srv.Mode())
+
+	addr := start(t, srv)
+	assert.Equal(t, "HTTP/2.0", get(t, tlsClient(), "https://"+addr))
+}
+
+func TestServerCanDisableHTTP2(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	srv, err := New("", protoHandler, config.ServerConfig{TLS: config.TLSConfig{CertFile: certFile, KeyFile: keyFile}})
+	require.NoError(t, err)
+
+	addr := start(t, srv)
+	assert.Equal(t, "HTTP/1.1", get(t, tlsClient(), "https://"+addr))
+}
+
+func TestServerConfiguresACME(t *testing.T) {
+	srv, err := New(":443", protoHandler, config.ServerConfig{HTTP2: true, TLS: config.TLSConfig{
+		ACMEDomains:  []string{"api.sma.example.sch.id"},
+		ACMECacheDir: t.TempDir(),
+		HTTPPort:     80,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, ModeACME, srv.Mode())
+	require.NotNil(t, srv.redirect)
+	assert.Equal(t, ":80", srv.redirect.Addr)
+	assert.Contains(t, srv.http.TLSConfig.NextProtos, "h2")
+	assert.Contains(t, srv.http.TLSConfig.NextProtos, "acme-tls/1")
+
+	_, err = New("", protoHandler, config.ServerConfig{TLS: config.TLSConfig{CertFile: "cert.pem"}})
+	assert.Error(t, err)
+}
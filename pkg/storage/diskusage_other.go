@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package storage
+
+// DiskUsageOf is unavailable on this platform; storage checks fall back to the write probe.
+func DiskUsageOf(dir string) (DiskUsage, error) {
+	return DiskUsage{}, ErrDiskUsageUnsupported
+}
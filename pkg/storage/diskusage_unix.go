@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package storage
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// DiskUsageOf reports the free and total space of the filesystem holding dir.
+func DiskUsageOf(dir string) (DiskUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return DiskUsage{}, fmt.Errorf("statfs %s: %w", dir, err)
+	}
+	blockSize := uint64(stat.Bsize)
+	return DiskUsage{Free: uint64(stat.Bavail) * blockSize, Total: uint64(stat.Blocks) * blockSize}, nil
+}
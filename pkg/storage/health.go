@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrDiskUsageUnsupported is returned by DiskUsageOf on platforms without a free-space query.
+var ErrDiskUsageUnsupported = errors.New("disk usage is not supported on this platform")
+
+// DiskUsage describes the filesystem holding a directory. Free counts the bytes available to
+// unprivileged processes, which is what the API can actually write.
+type DiskUsage struct {
+	Free  uint64
+	Total uint64
+}
+
+// FreePercent returns Free as a percentage of Total.
+func (u DiskUsage) FreePercent() float64 {
+	if u.Total == 0 {
+		return 0
+	}
+	return float64(u.Free) / float64(u.Total) * 100
+}
+
+// ProbeWritable creates, writes and removes a temporary file in dir.
+func ProbeWritable(dir string) error {
+	file, err := os.CreateTemp(dir, ".probe-*")
+	if err != nil {
+		return fmt.Errorf("create probe file: %w", err)
+	}
+	name := file.Name()
+	defer os.Remove(name) //nolint:errcheck
+	if _, err := file.Write([]byte("ok")); err != nil {
+		file.Close() //nolint:errcheck
+		return fmt.Errorf("write probe file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close probe file: %w", err)
+	}
+	return nil
+}
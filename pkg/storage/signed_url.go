@@ -12,18 +12,26 @@ import (
 
 // SignedURLSigner creates and validates signed download tokens.
 type SignedURLSigner struct {
-	secret []byte
-	ttl    time.Duration
+	secret    []byte
+	secondary []byte
+	ttl       time.Duration
 }
 
 // NewSignedURLSigner constructs a signer with the provided secret and TTL.
 func NewSignedURLSigner(secret string, ttl time.Duration) *SignedURLSigner {
+	return NewRotatingSignedURLSigner(secret, "", ttl)
+}
+
+// NewRotatingSignedURLSigner signs with primary and also accepts tokens signed with secondary,
+// so the secret can be rotated without invalidating outstanding links.
+func NewRotatingSignedURLSigner(primary, secondary string, ttl time.Duration) *SignedURLSigner {
 	if ttl <= 0 {
 		ttl = 24 * time.Hour
 	}
 	return &SignedURLSigner{
-		secret: []byte(secret),
-		ttl:    ttl,
+		secret:    []byte(primary),
+		secondary: []byte(secondary),
+		ttl:       ttl,
 	}
 }
 
@@ -38,9 +46,7 @@ func (s *SignedURLSigner) Generate(jobID, relPath string) (string, time.Time, er
 	expiresAt := time.Now().Add(s.ttl)
 	encodedPath := base64.RawURLEncoding.EncodeToString([]byte(relPath))
 	payload := fmt.Sprintf("%s|%d|%s", jobID, expiresAt.Unix(), encodedPath)
-	mac := hmac.New(sha256.New, s.secret)
-	_, _ = mac.Write([]byte(payload))
-	signature := hex.EncodeToString(mac.Sum(nil))
+	signature := sign(s.secret, payload)
 	token := strings.Join([]string{jobID, fmt.Sprintf("%d", expiresAt.Unix()), encodedPath, signature}, ".")
 	return token, expiresAt, nil
 }
@@ -69,10 +75,7 @@ func (s *SignedURLSigner) Parse(token string, allowExpired bool) (jobID, relPath
 	expiresAt = time.Unix(expUnix, 0)
 
 	payload := fmt.Sprintf("%s|%s|%s", jobID, ts, encodedPath)
-	mac := hmac.New(sha256.New, s.secret)
-	_, _ = mac.Write([]byte(payload))
-	expected := hex.EncodeToString(mac.Sum(nil))
-	if !hmac.Equal([]byte(expected), []byte(signature)) {
+	if !s.verify(payload, signature) {
 		return "", "", time.Time{}, fmt.Errorf("invalid token signature")
 	}
 	if !allowExpired && time.Now().After(expiresAt) {
@@ -81,6 +84,20 @@ func (s *SignedURLSigner) Parse(token string, allowExpired bool) (jobID, relPath
 	return jobID, string(rawPath), expiresAt, nil
 }
 
+// verify accepts signatures from the primary secret and, during rotation, the secondary one.
+func (s *SignedURLSigner) verify(payload, signature string) bool {
+	if hmac.Equal([]byte(sign(s.secret, payload)), []byte(signature)) {
+		return true
+	}
+	return len(s.secondary) > 0 && hmac.Equal([]byte(sign(s.secondary, payload)), []byte(signature))
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func parseUnix(raw string) (int64, error) {
 	var ts int64
 	_, err := fmt.Sscanf(raw, "%d", &ts)
@@ -35,3 +35,22 @@ func TestSignedURLSignerExpired(t *testing.T) {
 	require.Equal(t, "job-1", jobID)
 	require.Equal(t, "reports/file.csv", path)
 }
+
+func TestSignedURLSignerRotation(t *testing.T) {
+	old := NewSignedURLSigner("old-secret", time.Hour)
+	token, _, err := old.Generate("job-1", "reports/file.csv")
+	require.NoError(t, err)
+
+	rotated := NewRotatingSignedURLSigner("new-secret", "old-secret", time.Hour)
+	jobID, _, _, err := rotated.Parse(token, false)
+	require.NoError(t, err)
+	require.Equal(t, "job-1", jobID)
+
+	fresh, _, err := rotated.Generate("job-2", "reports/file.csv")
+	require.NoError(t, err)
+	_, _, _, err = NewSignedURLSigner("new-secret", time.Hour).Parse(fresh, false)
+	require.NoError(t, err)
+
+	_, _, _, err = NewSignedURLSigner("new-secret", time.Hour).Parse(token, false)
+	require.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Package thumbnail renders preview images of documents with an external rasteriser.
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultCommand is the poppler-utils rasteriser used when no command is configured.
+const DefaultCommand = "pdftoppm"
+
+// PDFRenderer renders the first page of a PDF as a PNG by running pdftoppm, or a command accepting
+// the same arguments.
+type PDFRenderer struct {
+	command string
+	width   int
+}
+
+// NewPDFRenderer constructs a renderer producing images width pixels wide.
+func NewPDFRenderer(command string, width int) *PDFRenderer {
+	if strings.TrimSpace(command) == "" {
+		command = DefaultCommand
+	}
+	if width <= 0 {
+		width = 320
+	}
+	return &PDFRenderer{command: command, width: width}
+}
+
+// Available reports whether the command can be found on PATH.
+func (r *PDFRenderer) Available() error {
+	if _, err := exec.LookPath(r.command); err != nil {
+		return fmt.Errorf("thumbnail renderer %s: %w", r.command, err)
+	}
+	return nil
+}
+
+// RenderFirstPage writes the first page of the PDF at src to the PNG file dst.
+func (r *PDFRenderer) RenderFirstPage(ctx context.Context, src, dst string) error {
+	// pdftoppm appends the extension itself when writing a single file.
+	prefix := strings.TrimSuffix(dst, ".png")
+	cmd := exec.CommandContext(ctx, r.command,
+		"-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to-x", strconv.Itoa(r.width), "-scale-to-y", "-1",
+		src, prefix)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.Remove(prefix + ".png")
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("render thumbnail: %w: %s", err, msg)
+		}
+		return fmt.Errorf("render thumbnail: %w", err)
+	}
+	if _, err := os.Stat(prefix + ".png"); err != nil {
+		return fmt.Errorf("render thumbnail: no image written: %w", err)
+	}
+	return nil
+}
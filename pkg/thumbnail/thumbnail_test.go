@@ -0,0 +1,46 @@
+package thumbnail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRenderer writes a script that mimics pdftoppm by copying its input to <prefix>.png.
+func fakeRenderer(t *testing.T, body string) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "fake-pdftoppm")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"+body+"\n"), 0o755))
+	return script
+}
+
+func TestPDFRendererRenderFirstPage(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "doc.pdf")
+	require.NoError(t, os.WriteFile(src, []byte("%PDF-1.4"), 0o600))
+	// The last two arguments are the source and the output prefix.
+	script := fakeRenderer(t, `for last; do :; done; eval src=\${$(($#-1))}; cp "$src" "$last.png"`)
+
+	renderer := NewPDFRenderer(script, 0)
+	require.NoError(t, renderer.Available())
+	dst := filepath.Join(dir, "doc.thumb.png")
+	require.NoError(t, renderer.RenderFirstPage(context.Background(), src, dst))
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "%PDF-1.4", string(data))
+}
+
+func TestPDFRendererFailure(t *testing.T) {
+	dir := t.TempDir()
+	renderer := NewPDFRenderer(fakeRenderer(t, `echo "Syntax Error: broken file" >&2; exit 1`), 160)
+	err := renderer.RenderFirstPage(context.Background(), filepath.Join(dir, "doc.pdf"), filepath.Join(dir, "doc.png"))
+	require.ErrorContains(t, err, "broken file")
+
+	err = NewPDFRenderer(fakeRenderer(t, `exit 0`), 160).RenderFirstPage(context.Background(), filepath.Join(dir, "doc.pdf"), filepath.Join(dir, "doc.png"))
+	require.ErrorContains(t, err, "no image written")
+
+	require.Error(t, NewPDFRenderer(filepath.Join(dir, "missing"), 160).Available())
+}
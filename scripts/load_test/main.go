@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/noah-isme/sma-adp-api/pkg/client"
+)
+
+// scenario is one read the load test repeats.
+type scenario struct {
+	Name string
+	Call func(ctx context.Context, c *client.Client) (*client.Response, error)
+}
+
+type result struct {
+	latencies []time.Duration
+	errors    int
+}
+
+func scenarios(termID string) []scenario {
+	list := []scenario{
+		{Name: "teachers", Call: func(ctx context.Context, c *client.Client) (*client.Response, error) {
+			return c.GetTeachers(ctx, url.Values{"limit": {"20"}})
+		}},
+		{Name: "search", Call: func(ctx context.Context, c *client.Client) (*client.Response, error) {
+			return c.GetSearch(ctx, url.Values{"q": {"an"}, "limit": {"10"}})
+		}},
+		{Name: "notifications", Call: func(ctx context.Context, c *client.Client) (*client.Response, error) {
+			return c.GetNotifications(ctx, url.Values{"limit": {"20"}})
+		}},
+		{Name: "health", Call: func(ctx context.Context, c *client.Client) (*client.Response, error) {
+			return c.GetHealth(ctx)
+		}},
+	}
+	if termID != "" {
+		list = append(list, scenario{Name: "dashboard", Call: func(ctx context.Context, c *client.Client) (*client.Response, error) {
+			return c.GetDashboard(ctx, url.Values{"termId": {termID}})
+		}})
+	}
+	return list
+}
+
+func main() {
+	var (
+		base         string
+		email        string
+		password     string
+		termID       string
+		duration     time.Duration
+		concurrency  int
+		maxErrorRate float64
+	)
+	flag.StringVar(&base, "base", "http://localhost:8080", "API base URL")
+	flag.StringVar(&email, "email", os.Getenv("LOADTEST_EMAIL"), "Account to log in with")
+	flag.StringVar(&password, "password", os.Getenv("LOADTEST_PASSWORD"), "Password for -email")
+	flag.StringVar(&termID, "term", "", "Term ID for the dashboard scenario (skipped when empty)")
+	flag.DurationVar(&duration, "duration", 30*time.Second, "How long to send requests")
+	flag.IntVar(&concurrency, "concurrency", 10, "Concurrent workers")
+	flag.Float64Var(&maxErrorRate, "max-error-rate", 0.01, "Exit non-zero above this share of failed requests")
+	flag.Parse()
+
+	c, err := client.New(base, client.Options{HTTPClient: &http.Client{Timeout: 10 * time.Second}, UserAgent: "load-test"})
+	if err != nil {
+		log.Fatalf("create client: %v", err)
+	}
+	if email == "" {
+		log.Fatal("-email is required")
+	}
+	if _, err := c.Login(context.Background(), email, password); err != nil {
+		log.Fatalf("login: %v", err)
+	}
+
+	list := scenarios(termID)
+	results := make([]result, len(list))
+	var mu sync.Mutex
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+			for i := offset; ctx.Err() == nil; i++ {
+				idx := i % len(list)
+				start := time.Now()
+				_, err := list[idx].Call(ctx, c)
+				elapsed := time.Since(start)
+				if ctx.Err() != nil {
+					// Requests cut off by the end of the run are not counted.
+					return
+				}
+				mu.Lock()
+				results[idx].latencies = append(results[idx].latencies, elapsed)
+				if err != nil {
+					results[idx].errors++
+				}
+				mu.Unlock()
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if !report(list, results, duration, maxErrorRate) {
+		os.Exit(1)
+	}
+}
+
+// report prints throughput and latency percentiles per scenario and reports whether the error rate
+// stayed within maxErrorRate.
+func report(list []scenario, results []result, duration time.Duration, maxErrorRate float64) bool {
+	fmt.Println("Load Test Report")
+	fmt.Println("================")
+	fmt.Printf("%-14s %8s %8s %8s %10s %10s %10s\n", "scenario", "requests", "errors", "rps", "p50", "p95", "p99")
+	var total, failed int
+	for i, res := range results {
+		sort.Slice(res.latencies, func(a, b int) bool { return res.latencies[a] < res.latencies[b] })
+		count := len(res.latencies)
+		total += count
+		failed += res.errors
+		fmt.Printf("%-14s %8d %8d %8.1f %10s %10s %10s\n", list[i].Name, count, res.errors,
+			float64(count)/duration.Seconds(), percentile(res.latencies, 0.50), percentile(res.latencies, 0.95), percentile(res.latencies, 0.99))
+	}
+	rate := 0.0
+	if total > 0 {
+		rate = float64(failed) / float64(total)
+	}
+	fmt.Printf("Total: %d requests, %d errors (%.2f%%)\n", total, failed, rate*100)
+	return rate <= maxErrorRate
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx].Round(time.Millisecond)
+}
@@ -2,11 +2,10 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -14,6 +13,8 @@ import (
 	"reflect"
 	"strings"
 	"time"
+
+	"github.com/noah-isme/sma-adp-api/pkg/client"
 )
 
 type target struct {
@@ -43,12 +44,16 @@ func main() {
 		legacyBase  string
 		targetsPath string
 		timeout     time.Duration
+		email       string
+		password    string
 	)
 
 	flag.StringVar(&goBase, "go-base", "http://localhost:8080", "Go API base URL")
 	flag.StringVar(&legacyBase, "legacy-base", "http://localhost:3000", "Legacy API base URL")
 	flag.StringVar(&targetsPath, "targets", filepath.Join("scripts", "shadow_compare", "targets.json"), "Path to JSON targets file")
 	flag.DurationVar(&timeout, "timeout", 5*time.Second, "HTTP client timeout")
+	flag.StringVar(&email, "email", os.Getenv("SHADOW_EMAIL"), "Account to log in with on both APIs (optional)")
+	flag.StringVar(&password, "password", os.Getenv("SHADOW_PASSWORD"), "Password for -email")
 	flag.Parse()
 
 	targets, err := loadTargets(targetsPath)
@@ -56,7 +61,15 @@ func main() {
 		log.Fatalf("failed to load targets: %v", err)
 	}
 
-	client := &http.Client{Timeout: timeout}
+	httpClient := &http.Client{Timeout: timeout}
+	goClient, err := newClient(goBase, httpClient, email, password)
+	if err != nil {
+		log.Fatalf("go api: %v", err)
+	}
+	legacyClient, err := newClient(legacyBase, httpClient, email, password)
+	if err != nil {
+		log.Fatalf("legacy api: %v", err)
+	}
 	var (
 		comparisons  []comparison
 		breaking     int
@@ -64,7 +77,7 @@ func main() {
 	)
 
 	for _, t := range targets {
-		comp := compareTarget(client, goBase, legacyBase, t)
+		comp := compareTarget(goClient, legacyClient, t)
 		if comp.Error != nil {
 			if t.Critical {
 				breaking++
@@ -104,10 +117,26 @@ func loadTargets(path string) ([]target, error) {
 	return cfg.Targets, nil
 }
 
-func compareTarget(client *http.Client, goBase, legacyBase string, tgt target) comparison {
+// newClient builds an API client for base and logs in when credentials are given.
+func newClient(base string, httpClient *http.Client, email, password string) (*client.Client, error) {
+	c, err := client.New(base, client.Options{HTTPClient: httpClient, UserAgent: "shadow-compare", ServerCasing: true})
+	if err != nil {
+		return nil, err
+	}
+	if email != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), httpClient.Timeout)
+		defer cancel()
+		if _, err := c.Login(ctx, email, password); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func compareTarget(goClient, legacyClient *client.Client, tgt target) comparison {
 	comp := comparison{Target: tgt}
-	goResp, goDur, goErr := performRequest(client, goBase, tgt)
-	legacyResp, legacyDur, legacyErr := performRequest(client, legacyBase, tgt)
+	goResp, goDur, goErr := performRequest(goClient, tgt)
+	legacyResp, legacyDur, legacyErr := performRequest(legacyClient, tgt)
 	comp.DurationGo = goDur
 	comp.DurationLegacy = legacyDur
 
@@ -123,30 +152,14 @@ func compareTarget(client *http.Client, goBase, legacyBase string, tgt target) c
 	comp.GoStatus = goResp.StatusCode
 	comp.LegacyStatus = legacyResp.StatusCode
 	comp.StatusMatch = comp.GoStatus == comp.LegacyStatus
-
-	defer goResp.Body.Close()
-	defer legacyResp.Body.Close()
-
-	goBody, err := io.ReadAll(goResp.Body)
-	if err != nil {
-		comp.Error = fmt.Errorf("read go body: %w", err)
-		return comp
-	}
-	legacyBody, err := io.ReadAll(legacyResp.Body)
-	if err != nil {
-		comp.Error = fmt.Errorf("read legacy body: %w", err)
-		return comp
-	}
-
-	comp.BodyMatch = bodiesEqual(goBody, legacyBody)
+	comp.BodyMatch = bodiesEqual(goResp.Body, legacyResp.Body)
 
 	return comp
 }
 
-func performRequest(client *http.Client, base string, tgt target) (*http.Response, time.Duration, error) {
-	if client == nil {
-		return nil, 0, errors.New("nil client")
-	}
+// performRequest replays tgt; error statuses are part of the comparison, so only transport failures
+// count as errors.
+func performRequest(c *client.Client, tgt target) (*client.Response, time.Duration, error) {
 	method := strings.ToUpper(strings.TrimSpace(tgt.Method))
 	if method == "" {
 		method = http.MethodGet
@@ -155,15 +168,9 @@ func performRequest(client *http.Client, base string, tgt target) (*http.Respons
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	url := strings.TrimRight(base, "/") + path
-
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return nil, 0, err
-	}
 	start := time.Now()
-	resp, err := client.Do(req)
-	if err != nil {
+	resp, err := c.Do(context.Background(), method, path, nil)
+	if resp == nil {
 		return nil, 0, err
 	}
 	return resp, time.Since(start), nil